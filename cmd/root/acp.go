@@ -48,5 +48,10 @@ func (f *acpFlags) runACPCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return acp.Run(ctx, agentFilename, cmd.InOrStdin(), cmd.OutOrStdout(), &f.runConfig, sessionDB)
+	storeOpts, err := sessionStoreOpts()
+	if err != nil {
+		return err
+	}
+
+	return acp.Run(ctx, agentFilename, cmd.InOrStdin(), cmd.OutOrStdout(), &f.runConfig, sessionDB, storeOpts...)
 }
@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/docker-agent/pkg/cli"
 	"github.com/docker/docker-agent/pkg/config"
@@ -18,10 +20,13 @@ import (
 
 type apiFlags struct {
 	listenAddr       string
+	grpcListenAddr   string
 	sessionDB        string
 	pullIntervalMins int
 	fakeResponses    string
 	recordPath       string
+	scheduler        server.SchedulerConfig
+	shutdownTimeout  time.Duration
 	runConfig        config.RuntimeConfig
 }
 
@@ -36,10 +41,16 @@ func newAPICmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVarP(&flags.listenAddr, "listen", "l", "127.0.0.1:8080", "Address to listen on")
+	cmd.PersistentFlags().StringVar(&flags.grpcListenAddr, "grpc-listen", "", "Address to also serve the gRPC API on (disabled by default)")
 	cmd.PersistentFlags().StringVarP(&flags.sessionDB, "session-db", "s", "session.db", "Path to the session database")
 	cmd.PersistentFlags().IntVar(&flags.pullIntervalMins, "pull-interval", 0, "Auto-pull OCI reference every N minutes (0 = disabled)")
 	cmd.PersistentFlags().StringVar(&flags.fakeResponses, "fake", "", "Replay AI responses from cassette file (for testing)")
 	cmd.PersistentFlags().StringVar(&flags.recordPath, "record", "", "Record AI API interactions to cassette file")
+	cmd.PersistentFlags().IntVar(&flags.scheduler.MaxConcurrentRuns, "max-concurrent-runs", 0, "Maximum number of runs executed at the same time, others are queued (0 = unlimited)")
+	cmd.PersistentFlags().IntVar(&flags.scheduler.MaxRunsPerUser, "max-runs-per-user", 0, "Maximum number of runs of a user executed at the same time (0 = unlimited)")
+	cmd.PersistentFlags().Int64Var(&flags.scheduler.DailyTokensPerUser, "daily-tokens-per-user", 0, "Maximum number of tokens a user can use per day (0 = unlimited)")
+	cmd.PersistentFlags().StringVar(&flags.scheduler.UserHeader, "user-header", "X-User-ID", "Header identifying the user of a request, for the per-user limits and the members of shared sessions")
+	cmd.PersistentFlags().DurationVar(&flags.shutdownTimeout, "shutdown-timeout", server.DefaultShutdownTimeout, "How long the runs going on have to finish on shutdown before being interrupted")
 	cmd.MarkFlagsMutuallyExclusive("fake", "record")
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
 
@@ -83,14 +94,27 @@ func (f *apiFlags) runAPICommand(cmd *cobra.Command, args []string) error {
 		return errors.New("--pull-interval flag can only be used with OCI references, not local files")
 	}
 
-	ln, lnCleanup, err := newListener(ctx, f.listenAddr)
+	// The listeners stay open when ctx is done: the servers shut down
+	// gracefully, answering requests while the runs going on finish.
+	ln, err := server.Listen(ctx, f.listenAddr)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to listen on %s: %w", f.listenAddr, err)
 	}
-	defer lnCleanup()
+	defer ln.Close()
 
 	out.Println("Listening on", ln.Addr().String())
 
+	var grpcLn net.Listener
+	if f.grpcListenAddr != "" {
+		grpcLn, err = server.Listen(ctx, f.grpcListenAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", f.grpcListenAddr, err)
+		}
+		defer grpcLn.Close()
+
+		out.Println("Serving gRPC on", grpcLn.Addr().String())
+	}
+
 	slog.Debug("Starting server", "agents", agentsPath, "addr", ln.Addr().String())
 
 	// Expand tilde in session database path
@@ -99,7 +123,11 @@ func (f *apiFlags) runAPICommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	sessionStore, err := session.NewSQLiteSessionStore(sessionDB)
+	storeOpts, err := sessionStoreOpts()
+	if err != nil {
+		return err
+	}
+	sessionStore, err := session.NewSQLiteSessionStore(sessionDB, storeOpts...)
 	if err != nil {
 		return fmt.Errorf("creating session store: %w", err)
 	}
@@ -114,10 +142,21 @@ func (f *apiFlags) runAPICommand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("resolving agent sources: %w", err)
 	}
 
-	s, err := server.New(ctx, sessionStore, &f.runConfig, time.Duration(f.pullIntervalMins)*time.Minute, sources)
+	s, err := server.New(ctx, sessionStore, &f.runConfig, time.Duration(f.pullIntervalMins)*time.Minute, sources,
+		server.WithScheduler(f.scheduler),
+		server.WithShutdownTimeout(f.shutdownTimeout),
+	)
 	if err != nil {
 		return fmt.Errorf("creating server: %w", err)
 	}
 
-	return s.Serve(ctx, ln)
+	if grpcLn == nil {
+		return s.Serve(ctx, ln)
+	}
+
+	// Shut down both servers when one of them fails.
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error { return s.Serve(gctx, ln) })
+	g.Go(func() error { return s.ServeGRPC(gctx, grpcLn) })
+	return g.Wait()
 }
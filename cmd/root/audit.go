@@ -0,0 +1,60 @@
+package root
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Tools to check the audit logs of agent runs",
+		Example: `  # Record the tool calls and approvals of a run
+  docker-agent run --exec agent.yaml --audit-log audit.jsonl "Fix the build"
+
+  # Check that the audit log wasn't tampered with
+  docker-agent audit verify audit.jsonl`,
+		GroupID: "advanced",
+	}
+
+	cmd.AddCommand(newAuditVerifyCmd())
+
+	return cmd
+}
+
+func newAuditVerifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <audit-log>",
+		Short: "Check that an audit log wasn't tampered with",
+		Long: `Check that the entries of an audit log written with ` + "`--audit-log`" + ` form an
+unbroken chain: an entry that was changed, removed or reordered is reported.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAuditVerifyCommand,
+	}
+}
+
+func runAuditVerifyCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("audit", []string{"verify"})
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	count, err := audit.Verify(f)
+	if err != nil {
+		return fmt.Errorf("%s doesn't verify: %w", args[0], err)
+	}
+
+	out.Printf("%s is intact: %d entries.\n", args[0], count)
+	return nil
+}
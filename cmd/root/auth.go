@@ -0,0 +1,279 @@
+package root
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/desktop"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/telemetry"
+	"github.com/docker/docker-agent/pkg/tools/mcp"
+)
+
+type authLogoutFlags struct {
+	account string
+	all     bool
+}
+
+func newAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage logins to model providers and MCP servers",
+		Example: `  # List the logins
+  docker-agent auth list
+
+  # Store the OpenAI API key in the system's credential store
+  docker-agent auth set openai
+
+  # Log out of a remote MCP server
+  docker-agent auth logout https://mcp.example.com/mcp`,
+		GroupID: "advanced",
+	}
+
+	cmd.AddCommand(newAuthListCmd())
+	cmd.AddCommand(newAuthSetCmd())
+	cmd.AddCommand(newAuthUnsetCmd())
+	cmd.AddCommand(newAuthLogoutCmd())
+
+	return cmd
+}
+
+func newAuthListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the Docker login, the stored API keys and the OAuth authorizations of remote MCP servers",
+		Args:  cobra.NoArgs,
+		RunE:  runAuthListCommand,
+	}
+}
+
+func runAuthListCommand(cmd *cobra.Command, _ []string) error {
+	telemetry.TrackCommand("auth", []string{"list"})
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	out.Println("Docker (model providers through Docker Desktop):")
+	if desktop.GetToken(ctx) == "" {
+		out.Println("  not logged in")
+	} else {
+		out.Printf("  logged in as %s\n", cmp.Or(desktop.GetUserInfo(ctx).Username, "unknown user"))
+	}
+	out.Println()
+
+	out.Println("Stored API keys:")
+	if store, err := environment.NewCredentialStore(); err != nil {
+		out.Printf("  %s\n", err)
+	} else {
+		var stored int
+		for _, name := range provider.AllProviders() {
+			envVar := provider.APIKeyEnvVar(name)
+			if envVar == "" {
+				continue
+			}
+			if _, found, _ := store.Get(ctx, envVar); found {
+				out.Printf("  %s (%s)\n", name, envVar)
+				stored++
+			}
+		}
+		if stored == 0 {
+			out.Println("  none")
+		}
+	}
+	out.Println()
+
+	tokens, err := mcp.DefaultTokenStore().Tokens()
+	if err != nil {
+		return err
+	}
+
+	out.Println("MCP servers:")
+	if len(tokens) == 0 {
+		out.Println("  no OAuth authorizations")
+		return nil
+	}
+	for _, key := range slices.Sorted(maps.Keys(tokens)) {
+		serverURL, account := mcp.SplitTokenKey(key)
+		out.Printf("  %s (account: %s): %s\n", serverURL, cmp.Or(account, "default"), describeToken(tokens[key]))
+	}
+	return nil
+}
+
+func describeToken(token *mcp.OAuthToken) string {
+	switch {
+	case token.ExpiresAt.IsZero():
+		return "authorized"
+	case !token.IsExpired():
+		return "authorized until " + token.ExpiresAt.Local().Format(time.DateTime)
+	case token.RefreshToken != "":
+		return "expired, refreshed on next use"
+	default:
+		return "expired, authorized again on next use"
+	}
+}
+
+func newAuthSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <provider>|<env-var>",
+		Short: "Store an API key in the system's credential store",
+		Long: `Store the API key of a model provider, or any other secret given by the name of
+its environment variable, in the macOS Keychain, the Windows Credential Manager
+or the Secret Service on Linux. The key is read from the standard input,
+without being echoed in a terminal.
+
+Agents read stored keys when the environment variable isn't set.`,
+		Example: `  docker-agent auth set anthropic
+  echo "$KEY" | docker-agent auth set GITHUB_PERSONAL_ACCESS_TOKEN`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAuthSetCommand,
+	}
+}
+
+func runAuthSetCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("auth", []string{"set", args[0]})
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	envVar, err := credentialName(args[0])
+	if err != nil {
+		return err
+	}
+	store, err := environment.NewCredentialStore()
+	if err != nil {
+		return err
+	}
+
+	var value []byte
+	if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		out.Printf("%s: ", envVar)
+		value, err = term.ReadPassword(int(f.Fd()))
+		out.Println()
+	} else {
+		value, err = io.ReadAll(cmd.InOrStdin())
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", envVar, err)
+	}
+	key := strings.TrimSpace(string(value))
+	if key == "" {
+		return fmt.Errorf("%s is empty", envVar)
+	}
+
+	if err := store.Set(ctx, envVar, key); err != nil {
+		return err
+	}
+	out.Printf("Stored %s\n", envVar)
+	return nil
+}
+
+func newAuthUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <provider>|<env-var>",
+		Short: "Remove an API key from the system's credential store",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAuthUnsetCommand,
+	}
+}
+
+func runAuthUnsetCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("auth", []string{"unset", args[0]})
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	envVar, err := credentialName(args[0])
+	if err != nil {
+		return err
+	}
+	store, err := environment.NewCredentialStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(cmd.Context(), envVar); err != nil {
+		return err
+	}
+	out.Printf("Removed %s\n", envVar)
+	return nil
+}
+
+// credentialName returns the environment variable of the API key of a
+// provider. Names of environment variables are used as is.
+func credentialName(arg string) (string, error) {
+	if envVar := provider.APIKeyEnvVar(arg); envVar != "" {
+		return envVar, nil
+	}
+	if arg == strings.ToUpper(arg) && !strings.ContainsAny(arg, " =") {
+		return arg, nil
+	}
+	return "", fmt.Errorf("unknown provider %q: give a provider using an API key, like openai, or the name of an environment variable", arg)
+}
+
+func newAuthLogoutCmd() *cobra.Command {
+	var flags authLogoutFlags
+
+	cmd := &cobra.Command{
+		Use:   "logout [<server-url>]",
+		Short: "Remove the OAuth authorizations of remote MCP servers",
+		Long: `Remove the stored OAuth tokens of a remote MCP server, for all its accounts
+or only for the one selected with --account. The next time an agent uses the
+server, it asks for an authorization again.
+
+To log out of Docker, use Docker Desktop.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: flags.runAuthLogoutCommand,
+	}
+
+	cmd.Flags().StringVar(&flags.account, "account", "", "Only remove the token of this account")
+	cmd.Flags().BoolVar(&flags.all, "all", false, "Remove the tokens of all the MCP servers")
+
+	return cmd
+}
+
+func (f *authLogoutFlags) runAuthLogoutCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("auth", append([]string{"logout"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	if f.all == (len(args) == 1) {
+		return errors.New("either give a server URL or use --all")
+	}
+
+	store := mcp.DefaultTokenStore()
+	tokens, err := store.Tokens()
+	if err != nil {
+		return err
+	}
+
+	var removed int
+	for key := range tokens {
+		serverURL, account := mcp.SplitTokenKey(key)
+		if !f.all && serverURL != args[0] {
+			continue
+		}
+		if cmd.Flags().Changed("account") && account != f.account {
+			continue
+		}
+		if err := store.RemoveToken(key); err != nil {
+			return err
+		}
+		removed++
+	}
+
+	if removed == 0 {
+		return errors.New("no OAuth authorization to remove")
+	}
+	out.Printf("Removed %d OAuth authorization(s)\n", removed)
+	return nil
+}
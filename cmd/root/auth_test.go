@@ -0,0 +1,28 @@
+package root
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialName(t *testing.T) {
+	t.Parallel()
+
+	for arg, want := range map[string]string{
+		"openai":                       "OPENAI_API_KEY",
+		"Anthropic":                    "ANTHROPIC_API_KEY",
+		"mistral":                      "MISTRAL_API_KEY",
+		"GITHUB_PERSONAL_ACCESS_TOKEN": "GITHUB_PERSONAL_ACCESS_TOKEN",
+	} {
+		got, err := credentialName(arg)
+		require.NoError(t, err, arg)
+		assert.Equal(t, want, got)
+	}
+
+	for _, arg := range []string{"dmr", "unknown", "A=B"} {
+		_, err := credentialName(arg)
+		assert.Error(t, err, arg)
+	}
+}
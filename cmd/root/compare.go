@@ -0,0 +1,183 @@
+package root
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/teamloader"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+type compareFlags struct {
+	agentName   string
+	models      []string
+	autoApprove bool
+	format      string
+	runConfig   config.RuntimeConfig
+}
+
+func newCompareCmd() *cobra.Command {
+	var flags compareFlags
+
+	cmd := &cobra.Command{
+		Use:   "compare <agent-file>|<registry-ref> [<agent-file>|<registry-ref>] <message>...",
+		Short: "Compare two agents or two models on the same messages",
+		Long: `Run the same messages with two agents, or with one agent and two models given
+with --model, at the same time. The answers are printed side by side, with the
+status, duration, token usage, cost and tool calls of both runs, and the deltas
+of the second run compared to the first.
+
+Tool calls are rejected unless --yolo is set.`,
+		Example: `  # Compare two agents
+  docker-agent compare ./agent-v1.yaml ./agent-v2.yaml "Summarize the README"
+
+  # Compare two models with the same agent
+  docker-agent compare ./agent.yaml --model openai/gpt-5-mini --model anthropic/claude-haiku-4-5 "Summarize the README"
+
+  # Write a markdown report
+  docker-agent compare ./agent.yaml --model openai/gpt-5 --model openai/gpt-5-mini --format markdown "Review main.go" > report.md`,
+		GroupID: "advanced",
+		Args:    cobra.MinimumNArgs(2),
+		RunE:    flags.runCompareCommand,
+	}
+
+	cmd.PersistentFlags().StringVarP(&flags.agentName, "agent", "a", "root", "Name of the agent to run")
+	cmd.PersistentFlags().StringArrayVar(&flags.models, "model", nil, "Model to compare: [agent=]provider/model (twice)")
+	cmd.PersistentFlags().BoolVar(&flags.autoApprove, "yolo", false, "Automatically approve all tool calls")
+	cmd.PersistentFlags().StringVar(&flags.format, "format", "text", "Output format: text (side by side), markdown or json")
+	_ = cmd.RegisterFlagCompletionFunc("agent", completeAgentName)
+	_ = cmd.RegisterFlagCompletionFunc("model", completeModel)
+	addRuntimeConfigFlags(cmd, &flags.runConfig)
+
+	return cmd
+}
+
+// contender is an agent, and its model overrides, to compare.
+type contender struct {
+	label     string
+	agentRef  string
+	overrides []string
+}
+
+func (f *compareFlags) runCompareCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("compare", args[:1])
+
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+
+	if f.format != "text" && f.format != "markdown" && f.format != "json" {
+		return fmt.Errorf("invalid format %q: must be one of text, markdown, json", f.format)
+	}
+
+	var a, b contender
+	var messages []string
+	switch len(f.models) {
+	case 0:
+		if len(args) < 3 {
+			return errors.New("give two agents and at least one message, or one agent and two models with --model")
+		}
+		a = contender{label: args[0], agentRef: args[0]}
+		b = contender{label: args[1], agentRef: args[1]}
+		messages = args[2:]
+	case 2:
+		a = contender{label: f.models[0], agentRef: args[0], overrides: f.models[:1]}
+		b = contender{label: f.models[1], agentRef: args[0], overrides: f.models[1:]}
+		messages = args[1:]
+	default:
+		return errors.New("--model must be given twice, to compare two models")
+	}
+
+	// Both runs can't read stdin.
+	if len(messages) == 1 && messages[0] == "-" {
+		buf, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read from stdin: %w", err)
+		}
+		messages = []string{string(buf)}
+	}
+
+	var comparison cli.Comparison
+	var errA, errB error
+	var wg sync.WaitGroup
+	wg.Go(func() { comparison.A, errA = f.run(ctx, a, messages) })
+	wg.Go(func() { comparison.B, errB = f.run(ctx, b, messages) })
+	wg.Wait()
+	if err := errors.Join(errA, errB); err != nil {
+		return err
+	}
+
+	switch f.format {
+	case "json":
+		buf, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(buf))
+	case "markdown":
+		comparison.PrintMarkdown(out)
+	default:
+		width := 100
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+			width = w
+		}
+		comparison.PrintColumns(out, width)
+	}
+	return nil
+}
+
+// run runs the messages with an agent, without saving the session.
+func (f *compareFlags) run(ctx context.Context, c contender, messages []string) (cli.Contender, error) {
+	agentSource, err := config.Resolve(c.agentRef, f.runConfig.EnvProvider())
+	if err != nil {
+		return cli.Contender{}, err
+	}
+
+	t, err := teamloader.Load(ctx, agentSource, &f.runConfig, teamloader.WithModelOverrides(c.overrides))
+	if err != nil {
+		return cli.Contender{}, fmt.Errorf("loading %s: %w", c.label, err)
+	}
+	defer stopToolSets(t)
+
+	agent, err := t.Agent(f.agentName)
+	if err != nil {
+		return cli.Contender{}, err
+	}
+
+	rt, err := runtime.New(t,
+		runtime.WithCurrentAgent(f.agentName),
+		runtime.WithSessionStore(session.NewInMemorySessionStore()),
+	)
+	if err != nil {
+		return cli.Contender{}, fmt.Errorf("creating runtime: %w", err)
+	}
+
+	wd, _ := os.Getwd()
+	sess := session.New(
+		session.WithMaxIterations(agent.MaxIterations()),
+		session.WithToolsApproved(f.autoApprove),
+		session.WithThinking(agent.ThinkingConfigured()),
+		session.WithWorkingDirs(append([]string{cmp.Or(f.runConfig.WorkingDir, wd)}, f.runConfig.AdditionalDirs...)...),
+	)
+
+	start := time.Now()
+	result := cli.Collect(ctx, cli.Config{AppName: AppName, AutoApprove: f.autoApprove}, rt, sess, messages)
+	return cli.Contender{
+		Label:    c.label,
+		Duration: time.Since(start),
+		Result:   result,
+	}, nil
+}
@@ -1,17 +1,29 @@
 package root
 
 import (
+	"cmp"
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/content"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/modelsdev"
+	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/userconfig"
 )
 
+// completionTimeout bounds the time spent looking up completions, so that a
+// slow network never blocks the shell.
+const completionTimeout = 2 * time.Second
+
 func completeRunExec(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	switch len(args) {
 	case 0:
@@ -47,6 +59,16 @@ func completeAlias(toComplete string) ([]string, cobra.ShellCompDirective) {
 		}
 	}
 
+	// Add matching agents pulled from a registry
+	if store, err := content.NewStore(); err == nil {
+		artifacts, _ := store.ListArtifacts()
+		for _, artifact := range artifacts {
+			if artifact.Reference != "" && strings.HasPrefix(artifact.Reference, toComplete) {
+				candidates = append(candidates, artifact.Reference+"\tpulled agent")
+			}
+		}
+	}
+
 	// Also add matching YAML files from the current directory
 	fileCandidates, _ := completeAgentFilename(toComplete)
 	candidates = append(candidates, fileCandidates...)
@@ -59,13 +81,8 @@ func completeMessage(cmd *cobra.Command, args []string, toComplete string) ([]st
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	agentSource, err := config.Resolve(args[0], nil)
-	if err != nil {
-		return nil, cobra.ShellCompDirectiveNoFileComp
-	}
-
-	cfg, err := config.Load(context.Background(), agentSource)
-	if err != nil {
+	cfg, ok := loadAgentConfig(context.Background(), args)
+	if !ok {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
@@ -123,3 +140,134 @@ func completeAgentFilename(toComplete string) ([]string, cobra.ShellCompDirectiv
 
 	return out, cobra.ShellCompDirectiveNoFileComp
 }
+
+// loadAgentConfig loads the configuration of the agent given as first
+// argument, for completions that depend on it.
+func loadAgentConfig(ctx context.Context, args []string) (*latest.Config, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	agentSource, err := config.Resolve(args[0], nil)
+	if err != nil {
+		return nil, false
+	}
+	cfg, err := config.Load(ctx, agentSource)
+	if err != nil {
+		return nil, false
+	}
+	return cfg, true
+}
+
+// completeAgentName completes the --agent flag with the agents of the
+// configuration given as first argument.
+func completeAgentName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, ok := loadAgentConfig(context.Background(), args)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var candidates []string
+	for _, agent := range cfg.Agents {
+		if strings.HasPrefix(agent.Name, toComplete) {
+			candidates = append(candidates, agent.Name+"\t"+agent.Description)
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSessionID completes the --session flag with the sessions of the
+// session database, most recent first.
+func completeSessionID(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	sessionDB, _ := cmd.Flags().GetString("session-db")
+	sessionDB, err := expandTilde(sessionDB)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// Don't create a database just to complete a flag.
+	if _, err := os.Stat(sessionDB); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	storeOpts, err := sessionStoreOpts()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	store, err := session.NewSQLiteSessionStore(sessionDB, storeOpts...)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if closer, ok := store.(interface{ Close() error }); ok {
+		defer closer.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	summaries, err := store.GetSessionSummaries(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	slices.SortFunc(summaries, func(a, b session.Summary) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	var candidates []string
+	for _, s := range summaries {
+		if strings.HasPrefix(s.ID, toComplete) {
+			candidates = append(candidates, s.ID+"\t"+cmp.Or(s.Title, "Untitled"))
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeModel completes a provider/model flag value with the models of
+// the models.dev catalog. Values of the run command's --model flag can be
+// prefixed with an agent name: agent=provider/model.
+func completeModel(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var agentPrefix string
+	if before, after, ok := strings.Cut(toComplete, "="); ok {
+		agentPrefix, toComplete = before+"=", after
+	}
+
+	store, err := modelsdev.NewStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	db, err := store.GetDatabase(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return modelCandidates(db, agentPrefix, toComplete)
+}
+
+func modelCandidates(db *modelsdev.Database, prefix, toComplete string) ([]string, cobra.ShellCompDirective) {
+	providerID, _, hasModel := strings.Cut(toComplete, "/")
+
+	// Complete the provider first, then its models.
+	if !hasModel {
+		var candidates []string
+		for _, id := range provider.CatalogProviders() {
+			if _, found := db.Providers[id]; found && strings.HasPrefix(id, toComplete) {
+				candidates = append(candidates, prefix+id+"/")
+			}
+		}
+		slices.Sort(candidates)
+		return candidates, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+
+	if !provider.IsCatalogProvider(providerID) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var candidates []string
+	for id, model := range db.Providers[providerID].Models {
+		ref := providerID + "/" + id
+		if strings.HasPrefix(ref, toComplete) {
+			candidates = append(candidates, prefix+ref+"\t"+model.Name)
+		}
+	}
+	slices.Sort(candidates)
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
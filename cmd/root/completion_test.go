@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/modelsdev"
 )
 
 func TestCompleteAgentFilename(t *testing.T) {
@@ -325,3 +327,65 @@ func writeFile(t *testing.T, dir, name string) {
 	t.Helper()
 	require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
 }
+
+func TestModelCandidates(t *testing.T) {
+	t.Parallel()
+
+	db := &modelsdev.Database{Providers: map[string]modelsdev.Provider{
+		"openai": {ID: "openai", Models: map[string]modelsdev.Model{
+			"gpt-5":      {ID: "gpt-5", Name: "GPT-5"},
+			"gpt-5-mini": {ID: "gpt-5-mini", Name: "GPT-5 mini"},
+			"o3":         {ID: "o3", Name: "o3"},
+		}},
+		"anthropic": {ID: "anthropic", Models: map[string]modelsdev.Model{
+			"claude-sonnet-4-5": {ID: "claude-sonnet-4-5", Name: "Claude Sonnet 4.5"},
+		}},
+	}}
+
+	candidates, directive := modelCandidates(db, "", "op")
+	assert.Equal(t, []string{"openai/"}, candidates)
+	assert.NotEqual(t, cobra.ShellCompDirective(0), directive&cobra.ShellCompDirectiveNoSpace)
+
+	candidates, _ = modelCandidates(db, "", "openai/gpt")
+	assert.Equal(t, []string{"openai/gpt-5\tGPT-5", "openai/gpt-5-mini\tGPT-5 mini"}, candidates)
+
+	candidates, _ = modelCandidates(db, "reviewer=", "anthropic/")
+	assert.Equal(t, []string{"reviewer=anthropic/claude-sonnet-4-5\tClaude Sonnet 4.5"}, candidates)
+
+	candidates, _ = modelCandidates(db, "", "unknown/")
+	assert.Empty(t, candidates)
+}
+
+func TestCompleteSessionIDWithoutDatabase(t *testing.T) {
+	t.Parallel()
+
+	sessionDB := filepath.Join(t.TempDir(), "session.db")
+	cmd := &cobra.Command{}
+	cmd.Flags().String("session-db", sessionDB, "")
+
+	candidates, _ := completeSessionID(cmd, nil, "")
+	assert.Empty(t, candidates)
+
+	// Completing must not create the database.
+	assert.NoFileExists(t, sessionDB)
+}
+
+func TestCompleteAgentName(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	agentFile := filepath.Join(dir, "agent.yaml")
+	require.NoError(t, os.WriteFile(agentFile, []byte(`agents:
+  root:
+    model: openai/gpt-5-mini
+    instruction: Delegate.
+    sub_agents: [reviewer]
+  reviewer:
+    model: openai/gpt-5-mini
+    description: Reviews code
+    instruction: Review.
+`), 0o644))
+
+	candidates, _ := completeAgentName(&cobra.Command{}, []string{agentFile}, "re")
+	assert.Equal(t, []string{"reviewer\tReviews code"}, candidates)
+}
@@ -0,0 +1,57 @@
+package root
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+// AgentSchema is the JSON Schema of the latest config version, embedded in
+// the binary by main.
+var AgentSchema []byte
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Tools to write and track agent configurations",
+		Example: `  # Save the JSON Schema of the agent configurations
+  docker-agent config schema > agent-schema.json
+
+  # List the versions of an agent that were run
+  docker-agent config history agent.yaml`,
+		GroupID: "advanced",
+	}
+
+	cmd.AddCommand(newConfigSchemaCmd())
+	cmd.AddCommand(newConfigHistoryCmd())
+	cmd.AddCommand(newConfigDiffCmd())
+	cmd.AddCommand(newConfigRollbackCmd())
+
+	return cmd
+}
+
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the agent configurations",
+		Long: `Print the JSON Schema of the latest version of the agent configurations,
+including the options of each toolset type.
+
+Editors using the YAML language server, like VS Code with the YAML extension,
+complete and validate agent files with it.`,
+		Args: cobra.NoArgs,
+		RunE: runConfigSchemaCommand,
+	}
+}
+
+func runConfigSchemaCommand(cmd *cobra.Command, _ []string) error {
+	telemetry.TrackCommand("config", []string{"schema"})
+
+	if len(AgentSchema) == 0 {
+		return errors.New("this build doesn't include the JSON Schema")
+	}
+	_, err := cmd.OutOrStdout().Write(AgentSchema)
+	return err
+}
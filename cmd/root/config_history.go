@@ -0,0 +1,173 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/confighistory"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+func newConfigHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history [agent-file]",
+		Short: "List the versions of agent configurations that were run",
+		Long: `List the versions of an agent configuration that were run, newest first.
+Without an agent, list the versions of every agent.
+
+Each time an agent is run, the version of its configuration is recorded and
+the sessions remember which version they were started with.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runConfigHistoryCommand,
+	}
+}
+
+func newConfigDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <from> <to>",
+		Short: "Show the changes between two versions of an agent configuration",
+		Long: `Show the changes between two versions of an agent configuration.
+Each version is either the hash of a version listed by "config history", or
+the path of an agent file.`,
+		Example: `  # Compare a previous version with the current agent file
+  docker-agent config diff 3f2a9c1e agent.yaml`,
+		Args: cobra.ExactArgs(2),
+		RunE: runConfigDiffCommand,
+	}
+}
+
+func newConfigRollbackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback <version> <agent-file>",
+		Short: "Restore a previous version of an agent configuration",
+		Long: `Restore a version listed by "config history" in an agent file. The
+current content of the file is recorded in the history first, so a rollback
+can be undone.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runConfigRollbackCommand,
+	}
+}
+
+func runConfigHistoryCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("config", append([]string{"history"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	store := confighistory.Default()
+
+	var source, current string
+	if len(args) == 1 {
+		agentSource, err := config.Resolve(args[0], nil)
+		if err != nil {
+			return err
+		}
+		source = confighistory.SourceKey(agentSource)
+		if data, err := agentSource.Read(cmd.Context()); err == nil {
+			current = confighistory.Hash(data)
+		}
+	}
+
+	versions, err := store.Versions(source)
+	if err != nil {
+		return fmt.Errorf("reading the configuration history: %w", err)
+	}
+	if len(versions) == 0 {
+		out.Println("No configuration versions recorded yet. They're recorded each time an agent is run.")
+		return nil
+	}
+
+	for _, v := range slices.Backward(versions) {
+		line := fmt.Sprintf("%s  %s", confighistory.Short(v.Hash), v.Time.Local().Format("2006-01-02 15:04:05"))
+		if source == "" {
+			line += "  " + v.Source
+		}
+		if v.Hash == current {
+			line += "  (current)"
+		}
+		out.Println(line)
+	}
+	return nil
+}
+
+func runConfigDiffCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("config", []string{"diff"})
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	store := confighistory.Default()
+
+	fromLabel, from, err := readConfigVersion(store, args[0])
+	if err != nil {
+		return err
+	}
+	toLabel, to, err := readConfigVersion(store, args[1])
+	if err != nil {
+		return err
+	}
+
+	diff := confighistory.Diff(fromLabel, from, toLabel, to)
+	if diff == "" {
+		out.Println("The versions are identical.")
+		return nil
+	}
+	out.Print(diff)
+	return nil
+}
+
+func runConfigRollbackCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("config", []string{"rollback"})
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	store := confighistory.Default()
+	path := args[1]
+
+	hash, data, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if confighistory.Hash(current) == hash {
+		out.Printf("%s is already at version %s.\n", path, confighistory.Short(hash))
+		return nil
+	}
+
+	source := confighistory.FileKey(path)
+	if _, err := store.Record(source, current); err != nil {
+		return fmt.Errorf("recording the current version: %w", err)
+	}
+	if err := os.WriteFile(path, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	out.Printf("Restored version %s in %s. The previous version was %s.\n", confighistory.Short(hash), path, confighistory.Short(confighistory.Hash(current)))
+	return nil
+}
+
+// readConfigVersion reads a version of a configuration referenced by the path
+// of an agent file or by the hash of a recorded version.
+func readConfigVersion(store *confighistory.Store, ref string) (string, []byte, error) {
+	if data, err := os.ReadFile(ref); err == nil {
+		return ref, data, nil
+	}
+
+	hash, data, err := store.Get(ref)
+	if errors.Is(err, confighistory.ErrNotFound) {
+		return "", nil, fmt.Errorf("%q is neither an agent file nor a recorded version", ref)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return confighistory.Short(hash), data, nil
+}
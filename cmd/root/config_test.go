@@ -0,0 +1,77 @@
+package root
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/confighistory"
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestConfigSchemaCommand(t *testing.T) {
+	schema := AgentSchema
+	t.Cleanup(func() { AgentSchema = schema })
+	AgentSchema = []byte(`{"title":"Docker Agent Configuration"}`)
+
+	var out bytes.Buffer
+	cmd := newConfigSchemaCmd()
+	cmd.SetArgs([]string{})
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.JSONEq(t, `{"title":"Docker Agent Configuration"}`, out.String())
+
+	AgentSchema = nil
+	require.Error(t, cmd.Execute())
+}
+
+func TestConfigDiffAndRollbackCommands(t *testing.T) {
+	paths.SetDataDir(t.TempDir())
+	t.Cleanup(func() { paths.SetDataDir("") })
+
+	const v1 = "agents:\n  root:\n    model: openai/gpt-4o\n    instruction: Be helpful.\n"
+	const v2 = "agents:\n  root:\n    model: openai/gpt-4o\n    instruction: Be concise.\n"
+
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(v2), 0o600))
+	hash, err := confighistory.Default().Record(confighistory.FileKey(path), []byte(v1))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	cmd := newConfigDiffCmd()
+	cmd.SetArgs([]string{confighistory.Short(hash), path})
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "-    instruction: Be helpful.")
+	assert.Contains(t, out.String(), "+    instruction: Be concise.")
+
+	cmd = newConfigRollbackCmd()
+	cmd.SetArgs([]string{confighistory.Short(hash), path})
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, v1, string(buf))
+
+	// The replaced version was recorded, so the rollback can be undone.
+	versions, err := confighistory.Default().Versions(confighistory.FileKey(path))
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, confighistory.Hash([]byte(v2)), versions[1].Hash)
+}
+
+func TestConfigChangeWarning(t *testing.T) {
+	sess := session.New()
+	assert.Empty(t, configChangeWarning(sess, "abc"))
+
+	sess.ConfigHash = "abc"
+	assert.Empty(t, configChangeWarning(sess, "abc"))
+	assert.Empty(t, configChangeWarning(sess, ""))
+	assert.Contains(t, configChangeWarning(sess, "def"), "config diff abc def")
+}
@@ -154,6 +154,9 @@ func (f *debugFlags) runDebugTitleCommand(cmd *cobra.Command, args []string) err
 
 	// Use the same title generation code path as the TUI (see runTUI in new.go)
 	gen := sessiontitle.New(model, agent.FallbackModels()...)
+	if titleModel := t.TitleModel(); titleModel != nil {
+		gen = sessiontitle.New(titleModel)
+	}
 
 	title, err := gen.Generate(ctx, "debug", []string{args[1]})
 	if err != nil {
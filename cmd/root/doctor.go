@@ -0,0 +1,165 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/doctor"
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+type doctorFlags struct {
+	runConfig  config.RuntimeConfig
+	sessionDB  string
+	offline    bool
+	jsonOutput bool
+}
+
+func newDoctorCmd() *cobra.Command {
+	var flags doctorFlags
+
+	cmd := &cobra.Command{
+		Use:   "doctor [<agent-file>|<registry-ref>]",
+		Short: "Diagnose problems with the environment",
+		Long: `Check the environment docker-agent runs in and print a report, with a fix
+for every problem found:
+
+  - the API keys of the model providers, validated with a free call that
+    lists the models of each provider
+  - Docker, the Docker daemon and Docker Model Runner
+  - git, and the commands of the MCP servers of an agent
+  - the capabilities of the terminal
+  - the session database
+
+With an agent file, the keys its models need are required, and its MCP
+servers are checked. The command fails if any problem is found.`,
+		Example: `  # Check the environment
+  docker-agent doctor
+
+  # Also check what an agent needs
+  docker-agent doctor ./agent.yaml
+
+  # Don't call the model providers
+  docker-agent doctor --offline`,
+		GroupID: "advanced",
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    flags.runDoctorCommand,
+	}
+
+	cmd.Flags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
+	cmd.Flags().BoolVar(&flags.offline, "offline", false, "Only check that API keys are set, without validating them")
+	cmd.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+	addRuntimeConfigFlags(cmd, &flags.runConfig)
+
+	return cmd
+}
+
+func (f *doctorFlags) runDoctorCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("doctor", args)
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	env := f.runConfig.EnvProvider()
+
+	providerOpts := doctor.ProviderOptions{Env: env, Offline: f.offline}
+	var mcpChecks []doctor.Check
+	if len(args) > 0 {
+		agentSource, err := config.Resolve(args[0], env)
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(ctx, agentSource)
+		if err != nil {
+			return err
+		}
+		providerOpts.Required = config.GatherEnvVarsForModels(cfg)
+		mcpChecks = doctor.CheckMCP(cfg, args[0])
+	}
+
+	sessionDB, err := expandTilde(f.sessionDB)
+	if err != nil {
+		return err
+	}
+
+	isTerminal := false
+	if file, ok := cmd.OutOrStdout().(*os.File); ok {
+		isTerminal = term.IsTerminal(int(file.Fd()))
+	}
+
+	checks := doctor.CheckProviders(ctx, providerOpts)
+	checks = append(checks, doctor.CheckDocker(ctx)...)
+	checks = append(checks, doctor.CheckGit(ctx))
+	checks = append(checks, mcpChecks...)
+	checks = append(checks, doctor.CheckTerminal(isTerminal, os.Getenv)...)
+	checks = append(checks, doctor.CheckSessions(ctx, sessionDB)...)
+
+	if f.jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(checks); err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(out, checks)
+	}
+
+	problems := doctor.Count(checks, doctor.StatusError)
+	warnings := doctor.Count(checks, doctor.StatusWarning)
+	switch {
+	case problems > 0:
+		return fmt.Errorf("%d problem(s) and %d warning(s) found", problems, warnings)
+	case f.jsonOutput:
+	case warnings > 0:
+		out.Printf("No problems found, %d warning(s)\n", warnings)
+	default:
+		out.Println("No problems found")
+	}
+	return nil
+}
+
+func printDoctorReport(out *cli.Printer, checks []doctor.Check) {
+	// Names are aligned within each category.
+	widths := map[string]int{}
+	for _, check := range checks {
+		widths[check.Category] = max(widths[check.Category], len(check.Name))
+	}
+
+	category := ""
+	for _, check := range checks {
+		if check.Category != category {
+			if category != "" {
+				out.Println()
+			}
+			category = check.Category
+			out.Println(category)
+		}
+
+		width := widths[category]
+		out.Printf("  %s %-*s  %s\n", doctorStatusSymbol(check.Status), width, check.Name, check.Detail)
+		if check.Fix != "" {
+			out.Printf("    %-*s  Fix: %s\n", width, "", check.Fix)
+		}
+	}
+	out.Println()
+}
+
+func doctorStatusSymbol(status doctor.Status) string {
+	switch status {
+	case doctor.StatusOK:
+		return "✓"
+	case doctor.StatusWarning:
+		return "!"
+	case doctor.StatusError:
+		return "✗"
+	default:
+		return "-"
+	}
+}
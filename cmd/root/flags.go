@@ -30,6 +30,8 @@ func addRuntimeConfigFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig)
 	cmd.PersistentFlags().StringSliceVar(&runConfig.EnvFiles, "env-from-file", nil, "Set environment variables from file")
 	cmd.PersistentFlags().BoolVar(&runConfig.GlobalCodeMode, "code-mode-tools", false, "Provide a single tool to call other tools via Javascript")
 	cmd.PersistentFlags().StringVar(&runConfig.WorkingDir, "working-dir", "", "Set the working directory for the session (applies to tools and relative paths)")
+	cmd.PersistentFlags().StringArrayVar(&runConfig.AdditionalDirs, "add-dir", nil, "Add a root directory to the session's workspace, besides the working directory (repeatable)")
+	cmd.PersistentFlags().StringToStringVar(&runConfig.Vars, "var", nil, "Set an instruction template variable: key=value (repeatable)")
 }
 
 func setupWorkingDirectory(workingDir string) error {
@@ -57,6 +59,23 @@ func setupWorkingDirectory(workingDir string) error {
 	return nil
 }
 
+// absoluteDirs makes the additional roots of the workspace absolute and
+// checks that they are directories.
+func absoluteDirs(dirs []string) ([]string, error) {
+	var absDirs []string
+	for _, dir := range dirs {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid directory: %w", err)
+		}
+		if info, err := os.Stat(absDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("directory does not exist or is not a directory: %s", absDir)
+		}
+		absDirs = append(absDirs, absDir)
+	}
+	return absDirs, nil
+}
+
 func canonize(endpoint string) string {
 	return strings.TrimSuffix(strings.TrimSpace(endpoint), "/")
 }
@@ -100,6 +119,13 @@ func addGatewayFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
 			runConfig.DefaultModel = &userCfg.DefaultModel.ModelConfig
 		}
 
+		// Resolve the additional roots before the working directory changes.
+		additionalDirs, err := absoluteDirs(runConfig.AdditionalDirs)
+		if err != nil {
+			return err
+		}
+		runConfig.AdditionalDirs = additionalDirs
+
 		if err := setupWorkingDirectory(runConfig.WorkingDir); err != nil {
 			return err
 		}
@@ -264,3 +264,32 @@ func TestDefaultModelLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateContinueIterations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flags   *runExecFlags
+		wantErr string
+	}{
+		{name: "not set", flags: &runExecFlags{}},
+		{name: "session", flags: &runExecFlags{continueIters: 20, sessionID: "-1"}},
+		{name: "continue", flags: &runExecFlags{continueIters: 20, continueSession: true}},
+		{name: "no session", flags: &runExecFlags{continueIters: 20}, wantErr: "needs a session"},
+		{name: "negative", flags: &runExecFlags{continueIters: -1, continueSession: true}, wantErr: "must be positive"},
+		{name: "remote", flags: &runExecFlags{continueIters: 20, continueSession: true, remoteAddress: "localhost:8080"}, wantErr: "--remote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.flags.validateContinueIterations()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
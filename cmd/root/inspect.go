@@ -0,0 +1,206 @@
+package root
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/remote"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+// sensitiveToolsets are the toolset types whose tools act on the user's
+// machine or on external services, and so ask for approval before running.
+var sensitiveToolsets = map[string]string{
+	"shell":      "runs shell commands",
+	"script":     "runs scripts",
+	"filesystem": "reads and writes files",
+	"mcp":        "calls MCP server tools",
+	"api":        "calls HTTP APIs",
+	"openapi":    "calls HTTP APIs",
+	"a2a":        "talks to remote agents",
+}
+
+func newInspectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect <registry-ref>",
+		Short: "Show what an agent from a registry needs before pulling it",
+		Long: `Show the agents, models, toolsets and environment variables of an agent
+shared on an OCI registry, without pulling it.
+
+Tools that can run commands, change files or call external services are
+listed so you know what you'll be asked to approve.`,
+		Example: `  docker agent inspect agentcatalog/pirate`,
+		GroupID: "core",
+		Args:    cobra.ExactArgs(1),
+		RunE:    runInspectCommand,
+	}
+}
+
+func runInspectCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("inspect", args)
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	registryRef := args[0]
+
+	artifact, err := remote.Fetch(ctx, registryRef)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(ctx, config.NewBytesSource(registryRef, artifact.Config))
+	if err != nil {
+		return fmt.Errorf("reading agent configuration: %w", err)
+	}
+
+	summary := summarizeConfig(cfg)
+
+	// Looking up the secrets of MCP servers needs the MCP catalog; show
+	// what we have if it can't be reached.
+	toolEnvVars, err := config.GatherEnvVarsForTools(ctx, cfg)
+	if err != nil {
+		summary.warnings = append(summary.warnings, err.Error())
+	}
+	summary.envVars = append(summary.envVars, toolEnvVars...)
+	slices.Sort(summary.envVars)
+	summary.envVars = slices.Compact(summary.envVars)
+
+	out.Printf("%s\n", artifact.Reference)
+	out.Printf("  Digest: %s\n", artifact.Digest)
+	if cfg.Metadata.Description != "" {
+		out.Printf("  Description: %s\n", cfg.Metadata.Description)
+	}
+	if cfg.Metadata.Author != "" {
+		out.Printf("  Author: %s\n", cfg.Metadata.Author)
+	}
+	if cfg.Metadata.Version != "" {
+		out.Printf("  Version: %s\n", cfg.Metadata.Version)
+	}
+
+	out.Println("\nAgents:")
+	for _, a := range summary.agents {
+		out.Printf("  %s (%s): %s\n", a.name, a.model, a.description)
+	}
+
+	printList(out, "Models", summary.models)
+	printList(out, "Toolsets", summary.toolsets)
+	printList(out, "Required environment variables", summary.envVars)
+
+	if len(summary.approvals) > 0 {
+		out.Println("\n⚠ Tools that will ask for approval:")
+		for _, a := range summary.approvals {
+			out.Printf("  - %s\n", a)
+		}
+	}
+	for _, w := range summary.warnings {
+		out.Printf("\nWarning: %s\n", w)
+	}
+
+	out.Printf("\nRun it with: docker agent run %s\n", registryRef)
+	return nil
+}
+
+func printList(out *cli.Printer, title string, items []string) {
+	out.Printf("\n%s:\n", title)
+	if len(items) == 0 {
+		out.Println("  (none)")
+		return
+	}
+	for _, item := range items {
+		out.Printf("  - %s\n", item)
+	}
+}
+
+type agentSummary struct {
+	name        string
+	model       string
+	description string
+}
+
+type configSummary struct {
+	agents    []agentSummary
+	models    []string
+	toolsets  []string
+	envVars   []string
+	approvals []string
+	warnings  []string
+}
+
+// summarizeConfig lists what an agent configuration needs to run.
+func summarizeConfig(cfg *latest.Config) configSummary {
+	var summary configSummary
+
+	for _, a := range cfg.Agents {
+		summary.agents = append(summary.agents, agentSummary{
+			name:        a.Name,
+			model:       cmp.Or(a.Model, "auto"),
+			description: a.Description,
+		})
+
+		for ref := range strings.SplitSeq(a.Model, ",") {
+			summary.models = append(summary.models, resolveModelNames(cfg, strings.TrimSpace(ref))...)
+		}
+
+		for _, ts := range a.Toolsets {
+			summary.toolsets = append(summary.toolsets, toolsetName(ts))
+			if why, ok := sensitiveToolsets[ts.Type]; ok {
+				summary.approvals = append(summary.approvals, fmt.Sprintf("%s: %s %s", a.Name, toolsetName(ts), why))
+			}
+		}
+	}
+
+	summary.envVars = config.GatherEnvVarsForModels(cfg)
+
+	slices.Sort(summary.models)
+	summary.models = slices.Compact(summary.models)
+	slices.Sort(summary.toolsets)
+	summary.toolsets = slices.Compact(summary.toolsets)
+
+	return summary
+}
+
+// resolveModelNames turns a model reference into "provider/model" names,
+// expanding named models and alloys.
+func resolveModelNames(cfg *latest.Config, ref string) []string {
+	if ref == "" {
+		return nil
+	}
+
+	model, ok := cfg.Models[ref]
+	if !ok {
+		return []string{ref}
+	}
+	if model.Provider == "" {
+		// Alloy of other models
+		var names []string
+		for part := range strings.SplitSeq(model.Model, ",") {
+			if part = strings.TrimSpace(part); part != "" && part != ref {
+				names = append(names, resolveModelNames(cfg, part)...)
+			}
+		}
+		return names
+	}
+	return []string{model.Provider + "/" + model.Model}
+}
+
+func toolsetName(ts latest.Toolset) string {
+	switch {
+	case ts.Ref != "":
+		return fmt.Sprintf("%s (%s)", ts.Type, ts.Ref)
+	case ts.Command != "":
+		return fmt.Sprintf("%s (%s)", ts.Type, ts.Command)
+	case ts.Remote.URL != "":
+		return fmt.Sprintf("%s (%s)", ts.Type, ts.Remote.URL)
+	case ts.URL != "":
+		return fmt.Sprintf("%s (%s)", ts.Type, ts.URL)
+	default:
+		return ts.Type
+	}
+}
@@ -0,0 +1,59 @@
+package root
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/hub"
+)
+
+func TestSummarizeConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.Load(t.Context(), config.NewBytesSource("test", []byte(`
+agents:
+  root:
+    model: mix
+    description: The coordinator
+    sub_agents: [helper]
+    toolsets:
+      - type: shell
+      - type: think
+  helper:
+    model: anthropic/claude-sonnet-4-5
+    description: The helper
+    toolsets:
+      - type: mcp
+        command: my-mcp-server
+models:
+  fast:
+    provider: openai
+    model: gpt-5-mini
+  mix:
+    model: fast,anthropic/claude-haiku-4-5
+`)))
+	require.NoError(t, err)
+
+	summary := summarizeConfig(cfg)
+
+	assert.Equal(t, []string{"anthropic/claude-haiku-4-5", "anthropic/claude-sonnet-4-5", "openai/gpt-5-mini"}, summary.models)
+	assert.Equal(t, []string{"mcp (my-mcp-server)", "shell", "think"}, summary.toolsets)
+	assert.ElementsMatch(t, []string{"root: shell runs shell commands", "helper: mcp (my-mcp-server) calls MCP server tools"}, summary.approvals)
+	assert.Contains(t, summary.envVars, "OPENAI_API_KEY")
+	assert.Contains(t, summary.envVars, "ANTHROPIC_API_KEY")
+}
+
+func TestFilterAgents(t *testing.T) {
+	t.Parallel()
+
+	repos := []hub.Repository{{Name: "org/a"}, {Name: "org/image"}, {Name: "org/b"}}
+	agents := filterAgents(t.Context(), repos, func(_ context.Context, repo string) bool {
+		return repo != "org/image"
+	})
+
+	assert.Equal(t, []hub.Repository{{Name: "org/a"}, {Name: "org/b"}}, agents)
+}
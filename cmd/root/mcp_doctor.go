@@ -0,0 +1,156 @@
+package root
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/teamloader"
+	"github.com/docker/docker-agent/pkg/telemetry"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/mcp"
+)
+
+type mcpDoctorFlags struct {
+	runConfig config.RuntimeConfig
+}
+
+func newMCPToolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Check the MCP servers used by agents",
+		Example: `  # Check the MCP servers of an agent
+  docker-agent mcp doctor ./agent.yaml`,
+		GroupID: "advanced",
+	}
+
+	cmd.AddCommand(newMCPDoctorCmd())
+
+	return cmd
+}
+
+func newMCPDoctorCmd() *cobra.Command {
+	var flags mcpDoctorFlags
+
+	cmd := &cobra.Command{
+		Use:   "doctor <agent-file>|<registry-ref>",
+		Short: "Start the MCP servers of an agent and report their state",
+		Long: `Start every MCP server configured in an agent file, without starting a chat,
+and report its version, capabilities, number of tools and authentication.
+
+Servers that can't be started, that need an OAuth authorization or that
+expose no tools are reported, with hints about common misconfigurations
+such as missing commands or empty headers. The command fails if any server
+has a problem.`,
+		Args: cobra.ExactArgs(1),
+		RunE: flags.runMCPDoctorCommand,
+	}
+
+	addRuntimeConfigFlags(cmd, &flags.runConfig)
+
+	return cmd
+}
+
+func (f *mcpDoctorFlags) runMCPDoctorCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("mcp", append([]string{"doctor"}, args...))
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	agentSource, err := config.Resolve(args[0], f.runConfig.EnvProvider())
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(ctx, agentSource)
+	if err != nil {
+		return err
+	}
+
+	parentDir := cmp.Or(agentSource.ParentDir(), f.runConfig.WorkingDir)
+	registry := teamloader.NewDefaultToolsetRegistry()
+
+	var checked, failed int
+	for _, agentConfig := range cfg.Agents {
+		for _, toolset := range agentConfig.Toolsets {
+			if toolset.Type != "mcp" {
+				continue
+			}
+
+			checked++
+			out.Printf("%s: %s\n", agentConfig.Name, toolsetName(toolset))
+			if !f.diagnoseMCPToolset(ctx, out, registry, toolset, parentDir, agentSource.Name()) {
+				failed++
+			}
+			out.Println()
+		}
+	}
+
+	switch {
+	case checked == 0:
+		out.Printf("No MCP servers in %s\n", args[0])
+	case failed > 0:
+		return fmt.Errorf("%d of %d MCP servers have problems", failed, checked)
+	default:
+		out.Printf("%d MCP server(s) checked, no problems found\n", checked)
+	}
+	return nil
+}
+
+// diagnoseMCPToolset starts an MCP server, prints what it found and returns
+// whether the server works.
+func (f *mcpDoctorFlags) diagnoseMCPToolset(ctx context.Context, out *cli.Printer, registry *teamloader.ToolsetRegistry, toolset latest.Toolset, parentDir, configName string) bool {
+	toolSet, err := registry.CreateTool(ctx, toolset, parentDir, &f.runConfig, configName)
+	if err != nil {
+		out.Printf("  Error: %s\n", err)
+		if _, lookErr := exec.LookPath(toolset.Command); toolset.Command != "" && lookErr != nil {
+			out.Printf("  Hint: Command %q is not in the PATH and could not be installed. Install it, or fix the command of the toolset.\n", toolset.Command)
+		}
+		return false
+	}
+	// Both plain and MCP Gateway toolsets can be diagnosed.
+	server, ok := toolSet.(interface {
+		Diagnose(ctx context.Context) *mcp.Diagnosis
+		Stop(ctx context.Context) error
+	})
+	if !ok {
+		out.Printf("  Error: unexpected toolset %s\n", tools.DescribeToolSet(toolSet))
+		return false
+	}
+	defer func() {
+		_ = server.Stop(context.WithoutCancel(ctx))
+	}()
+
+	d := server.Diagnose(ctx)
+	printMCPDiagnosis(out, d)
+
+	return d.Err == nil && !d.OAuthRequired && d.Tools > 0
+}
+
+func printMCPDiagnosis(out *cli.Printer, d *mcp.Diagnosis) {
+	if d.ServerName != "" {
+		out.Printf("  Server: %s %s\n", d.ServerName, d.ServerVersion)
+	}
+	if d.ProtocolVersion != "" {
+		out.Printf("  Protocol: %s\n", d.ProtocolVersion)
+	}
+	if d.Err == nil {
+		out.Printf("  Capabilities: %s\n", cmp.Or(strings.Join(d.Capabilities, ", "), "none"))
+		out.Printf("  Tools: %d\n", d.Tools)
+	}
+	if d.Auth != "" {
+		out.Printf("  Auth: %s\n", d.Auth)
+	}
+	if d.Err != nil {
+		out.Printf("  Error: %s\n", d.Err)
+	}
+	for _, hint := range d.Hints {
+		out.Printf("  Hint: %s\n", hint)
+	}
+}
@@ -0,0 +1,121 @@
+package root
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider/dmr"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+type modelsFlags struct {
+	runConfig config.RuntimeConfig
+}
+
+func newModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "Manage local models",
+		Long:  "Manage the local models run by Docker Model Runner.",
+		Example: `  # Pull a model
+  docker-agent models pull ai/qwen3
+
+  # Pull every Docker Model Runner model used by an agent
+  docker-agent models pull ./agent.yaml`,
+		GroupID: "advanced",
+	}
+
+	cmd.AddCommand(newModelsPullCmd())
+
+	return cmd
+}
+
+func newModelsPullCmd() *cobra.Command {
+	var flags modelsFlags
+
+	cmd := &cobra.Command{
+		Use:   "pull <model>|<agent-file>...",
+		Short: "Pull local models through Docker Model Runner",
+		Long: `Pull models through Docker Model Runner so that agents using them start right away.
+
+Arguments ending in .yaml or .yml are read as agent files and every dmr model
+they reference is pulled. Other arguments are model names, with or without the
+dmr/ prefix. Models that are already available locally are skipped.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: flags.runModelsPullCommand,
+	}
+
+	return cmd
+}
+
+func (f *modelsFlags) runModelsPullCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("models", append([]string{"pull"}, args...))
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	var models []string
+	for _, arg := range args {
+		if !isAgentFile(arg) {
+			models = append(models, strings.TrimPrefix(arg, "dmr/"))
+			continue
+		}
+
+		agentSource, err := config.Resolve(arg, f.runConfig.EnvProvider())
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(ctx, agentSource)
+		if err != nil {
+			return err
+		}
+
+		dmrModels := localModels(cfg)
+		if len(dmrModels) == 0 {
+			out.Printf("No Docker Model Runner models in %s\n", arg)
+		}
+		models = append(models, dmrModels...)
+	}
+
+	slices.Sort(models)
+	for _, model := range slices.Compact(models) {
+		if dmr.ModelExists(ctx, model) {
+			out.Printf("Model %s is already available\n", model)
+			continue
+		}
+
+		out.Println("Pulling model", model)
+		if err := dmr.PullModel(ctx, model, nil); err != nil {
+			return err
+		}
+		out.Printf("Model %s pulled successfully\n", model)
+	}
+
+	return nil
+}
+
+// isAgentFile reports whether a `models pull` argument names an agent file
+// rather than a model. Model names look like OCI references, so only the
+// extension tells them apart.
+func isAgentFile(arg string) bool {
+	ext := strings.ToLower(filepath.Ext(arg))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// localModels returns the Docker Model Runner models referenced by a config.
+func localModels(cfg *latest.Config) []string {
+	var models []string
+	for _, model := range cfg.Models {
+		if model.Provider == "dmr" && model.Model != "" {
+			models = append(models, model.Model)
+		}
+	}
+	slices.Sort(models)
+	return slices.Compact(models)
+}
@@ -0,0 +1,33 @@
+package root
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+)
+
+func TestIsAgentFile(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isAgentFile("agent.yaml"))
+	assert.True(t, isAgentFile("./agents/Agent.YML"))
+	assert.False(t, isAgentFile("ai/qwen3"))
+	assert.False(t, isAgentFile("dmr/ai/gemma3:4B-Q4_K_M"))
+}
+
+func TestLocalModels(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Models: map[string]latest.ModelConfig{
+			"dmr/ai/qwen3": {Provider: "dmr", Model: "ai/qwen3"},
+			"local":        {Provider: "dmr", Model: "ai/qwen3"},
+			"small":        {Provider: "dmr", Model: "ai/gemma3"},
+			"cloud":        {Provider: "openai", Model: "gpt-4o"},
+		},
+	}
+
+	assert.Equal(t, []string{"ai/gemma3", "ai/qwen3"}, localModels(cfg))
+}
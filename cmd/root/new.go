@@ -2,6 +2,7 @@ package root
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/docker-agent/pkg/app"
+	"github.com/docker/docker-agent/pkg/cli"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/creator"
 	"github.com/docker/docker-agent/pkg/runtime"
@@ -21,6 +23,9 @@ import (
 type newFlags struct {
 	modelParam         string
 	maxIterationsParam int
+	wizard             bool
+	prompt             string
+	output             string
 	runConfig          config.RuntimeConfig
 }
 
@@ -35,17 +40,32 @@ func newNewCmd() *cobra.Command {
 The agent builder will ask questions about what you want the agent to do,
 then generate a YAML configuration file you can use with 'docker-agent run'.
 
-Optionally provide a description as an argument to skip the initial prompt.`,
+Optionally provide a description as an argument to skip the initial prompt.
+
+With --prompt, the agent is generated in one go from its description: the
+model writes the YAML file, which is validated and linted before being saved
+with a .env template listing the environment variables the agent needs.
+
+With --wizard, no model is involved: answer a few questions to pick the
+provider, model and toolsets, and get a validated YAML file along with the
+same .env template.`,
 		Example: `  docker-agent new
   docker-agent new "a web scraper that extracts product prices"
-  docker-agent new --model openai/gpt-4o "a code reviewer agent"`,
+  docker-agent new --model openai/gpt-4o "a code reviewer agent"
+  docker-agent new --prompt "an agent that triages Sentry alerts and posts to Slack"
+  docker-agent new --wizard`,
 		GroupID: "core",
 		RunE:    flags.runNewCommand,
 	}
 
 	cmd.PersistentFlags().StringVar(&flags.modelParam, "model", "", "Model to use, optionally as provider/model where provider is one of: anthropic, openai, google, dmr. If omitted, provider is auto-selected based on available credentials or gateway")
+	cmd.PersistentFlags().BoolVar(&flags.wizard, "wizard", false, "Scaffold the agent by answering questions, without using a model")
+	cmd.PersistentFlags().StringVar(&flags.prompt, "prompt", "", "Generate the agent from this description without the interactive builder")
+	cmd.PersistentFlags().StringVarP(&flags.output, "output", "o", "agent.yaml", "File to write the agent generated with --prompt to")
+	cmd.MarkFlagsMutuallyExclusive("wizard", "prompt")
 	cmd.PersistentFlags().IntVar(&flags.maxIterationsParam, "max-iterations", 0, "Maximum number of agentic loop iterations to prevent infinite loops (default: 20 for DMR, unlimited for other providers)")
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
+	_ = cmd.RegisterFlagCompletionFunc("model", completeModel)
 
 	return cmd
 }
@@ -55,6 +75,13 @@ func (f *newFlags) runNewCommand(cmd *cobra.Command, args []string) error {
 
 	ctx := cmd.Context()
 
+	if f.wizard {
+		return f.runWizard(ctx, newWizard(cmd.InOrStdin(), cmd.OutOrStdout()), args)
+	}
+	if f.prompt != "" {
+		return f.runPrompt(ctx, cli.NewPrinter(cmd.OutOrStdout()))
+	}
+
 	t, err := creator.Agent(ctx, &f.runConfig, f.modelParam)
 	if err != nil {
 		return err
@@ -83,6 +110,36 @@ func (f *newFlags) runNewCommand(cmd *cobra.Command, args []string) error {
 	return runTUI(ctx, rt, sess, nil, nil, appOpts...)
 }
 
+// runPrompt generates an agent from the --prompt description with the model
+// of the agent builder.
+func (f *newFlags) runPrompt(ctx context.Context, out *cli.Printer) error {
+	if _, err := os.Stat(f.output); err == nil {
+		return fmt.Errorf("%s already exists, pick another file with --output", f.output)
+	}
+
+	t, err := creator.Agent(ctx, &f.runConfig, f.modelParam)
+	if err != nil {
+		return err
+	}
+	defer stopToolSets(t)
+
+	builder, err := t.Agent("root")
+	if err != nil {
+		return err
+	}
+
+	out.Printf("Generating the agent with %s...\n", builder.Model().ID())
+	generated, err := creator.Generate(ctx, &f.runConfig, builder.Model(), f.prompt, AgentSchema)
+	if err != nil {
+		return err
+	}
+	for _, warning := range generated.Warnings {
+		out.Println("Warning:", warning)
+	}
+
+	return writeAgentFiles(ctx, out, f.output, generated.YAML)
+}
+
 func runTUI(ctx context.Context, rt runtime.Runtime, sess *session.Session, spawner tui.SessionSpawner, cleanup func(), opts ...app.Opt) error {
 	if gen := rt.TitleGenerator(); gen != nil {
 		opts = append(opts, app.WithTitleGenerator(gen))
@@ -0,0 +1,228 @@
+package root
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/creator"
+)
+
+// wizard asks the questions of `new --wizard` on a terminal.
+type wizard struct {
+	in  *bufio.Reader
+	out *cli.Printer
+}
+
+func newWizard(in io.Reader, out io.Writer) *wizard {
+	return &wizard{
+		in:  bufio.NewReader(in),
+		out: cli.NewPrinter(out),
+	}
+}
+
+// ask asks a free-form question. An empty answer selects the default.
+func (w *wizard) ask(question, def string) (string, error) {
+	if def != "" {
+		w.out.Printf("%s [%s]: ", question, def)
+	} else {
+		w.out.Printf("%s: ", question)
+	}
+
+	line, err := w.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		// Don't keep asking once the input is closed.
+		return "", io.ErrUnexpectedEOF
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// choose asks to pick options, by number or by name. Several options can be
+// picked, separated by commas, when many is true.
+func (w *wizard) choose(question string, options, descriptions, defaults []string, many bool) ([]string, error) {
+	w.out.Println(question)
+	for i, option := range options {
+		if descriptions[i] != "" {
+			w.out.Printf("  %d) %s - %s\n", i+1, option, descriptions[i])
+		} else {
+			w.out.Printf("  %d) %s\n", i+1, option)
+		}
+	}
+
+	prompt := "Choice"
+	if many {
+		prompt = "Choices (comma separated)"
+	}
+	for {
+		answer, err := w.ask(prompt, strings.Join(defaults, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		selected, err := parseChoices(answer, options)
+		switch {
+		case err != nil:
+			w.out.Println(err)
+		case !many && len(selected) != 1:
+			w.out.Println("Please pick a single option.")
+		default:
+			return selected, nil
+		}
+	}
+}
+
+// parseChoices maps a comma separated list of option numbers or names to
+// option names.
+func parseChoices(answer string, options []string) ([]string, error) {
+	var selected []string
+	for field := range strings.SplitSeq(answer, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		option := field
+		if n, err := strconv.Atoi(field); err == nil {
+			if n < 1 || n > len(options) {
+				return nil, fmt.Errorf("%d is not a valid choice", n)
+			}
+			option = options[n-1]
+		} else if !slices.Contains(options, field) {
+			return nil, fmt.Errorf("%q is not a valid choice", field)
+		}
+
+		if !slices.Contains(selected, option) {
+			selected = append(selected, option)
+		}
+	}
+	return selected, nil
+}
+
+func (f *newFlags) runWizard(ctx context.Context, w *wizard, args []string) error {
+	var s creator.Scaffold
+
+	var err error
+	s.Description = strings.Join(args, " ")
+	if s.Description == "" {
+		if s.Description, err = w.ask("What should the agent do?", ""); err != nil {
+			return err
+		}
+	}
+
+	defaultInstruction := "You are a helpful assistant."
+	if s.Description != "" {
+		defaultInstruction = "You are an agent that helps with the following: " + s.Description
+	}
+	if s.Instruction, err = w.ask("Instruction", defaultInstruction); err != nil {
+		return err
+	}
+
+	if provider, model, ok := strings.Cut(f.modelParam, "/"); ok {
+		s.Provider, s.Model = provider, model
+	} else {
+		providers := slices.Sorted(maps.Keys(config.DefaultModels))
+		env := f.runConfig.EnvProvider()
+		available := config.AvailableProviders(ctx, f.runConfig.ModelsGateway, env)
+
+		descriptions := make([]string, len(providers))
+		for i, p := range providers {
+			if slices.Contains(available, p) && p != "dmr" {
+				descriptions[i] = "credentials found"
+			}
+		}
+		fallback := "dmr"
+		if len(available) > 0 {
+			fallback = available[0]
+		}
+		defaultProvider := providerFromFlag(f.modelParam, fallback)
+
+		choice, err := w.choose("Model provider:", providers, descriptions, []string{defaultProvider}, false)
+		if err != nil {
+			return err
+		}
+		s.Provider = choice[0]
+		if s.Model, err = w.ask("Model", config.DefaultModels[s.Provider]); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, len(creator.ToolsetChoices))
+	descriptions := make([]string, len(creator.ToolsetChoices))
+	for i, c := range creator.ToolsetChoices {
+		names[i] = c.Name
+		descriptions[i] = c.Description
+	}
+	if s.Toolsets, err = w.choose("Toolsets:", names, descriptions, creator.DefaultToolsets, true); err != nil {
+		return err
+	}
+
+	data, err := s.YAML(ctx)
+	if err != nil {
+		return err
+	}
+
+	path, err := w.ask("Save to", "agent.yaml")
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		answer, err := w.ask(path+" already exists. Overwrite? (y/N)", "n")
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			return errors.New("aborted")
+		}
+	}
+	w.out.Println()
+	return writeAgentFiles(ctx, w.out, path, data)
+}
+
+// writeAgentFiles writes an agent configuration along with a .env template
+// listing the environment variables it needs.
+func writeAgentFiles(ctx context.Context, out *cli.Printer, path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+
+	envTemplate, err := creator.EnvTemplate(ctx, data)
+	if err != nil {
+		return err
+	}
+	envPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".env.example"
+	if err := os.WriteFile(envPath, []byte(envTemplate), 0o644); err != nil {
+		return err
+	}
+
+	out.Println("Wrote", path, "and", envPath)
+	out.Println("Fill in the .env file, then run the agent with:")
+	out.Printf("  cp %s .env && docker agent run %s --env-from-file .env\n", envPath, path)
+	return nil
+}
+
+// providerFromFlag returns the provider given by a --model flag without a
+// model name, or the fallback.
+func providerFromFlag(modelParam, fallback string) string {
+	if modelParam != "" {
+		if _, ok := config.DefaultModels[modelParam]; ok {
+			return modelParam
+		}
+	}
+	return fallback
+}
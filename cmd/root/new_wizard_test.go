@@ -0,0 +1,75 @@
+package root
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChoices(t *testing.T) {
+	t.Parallel()
+
+	options := []string{"filesystem", "shell", "think"}
+
+	selected, err := parseChoices("1, think,1", options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"filesystem", "think"}, selected)
+
+	selected, err = parseChoices("", options)
+	require.NoError(t, err)
+	assert.Empty(t, selected)
+
+	_, err = parseChoices("4", options)
+	require.ErrorContains(t, err, "4 is not a valid choice")
+
+	_, err = parseChoices("browser", options)
+	require.ErrorContains(t, err, `"browser" is not a valid choice`)
+}
+
+func TestRunWizard(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	f := &newFlags{modelParam: "openai/gpt-5-mini"}
+	input := strings.Join([]string{
+		"",        // default instruction
+		"9",       // invalid toolset
+		"1,think", // toolsets
+		"",        // default path
+	}, "\n") + "\n"
+	var out bytes.Buffer
+
+	err := f.runWizard(t.Context(), newWizard(strings.NewReader(input), &out), []string{"a", "code", "reviewer"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "9 is not a valid choice")
+
+	data, err := os.ReadFile("agent.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "model: openai/gpt-5-mini")
+	assert.Contains(t, string(data), "description: a code reviewer")
+	assert.Contains(t, string(data), "type: filesystem")
+	assert.Contains(t, string(data), "type: think")
+	assert.NotContains(t, string(data), "type: shell")
+
+	env, err := os.ReadFile("agent.env.example")
+	require.NoError(t, err)
+	assert.Contains(t, string(env), "OPENAI_API_KEY=")
+}
+
+func TestRunWizard_KeepsExistingFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("agent.yaml", []byte("keep"), 0o644))
+
+	f := &newFlags{modelParam: "openai/gpt-5-mini"}
+	input := "\n\n\n\n" // defaults, then refuse to overwrite
+
+	err := f.runWizard(t.Context(), newWizard(strings.NewReader(input), &bytes.Buffer{}), []string{"helper"})
+	require.EqualError(t, err, "aborted")
+
+	data, err := os.ReadFile("agent.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "keep", string(data))
+}
@@ -0,0 +1,141 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/childprocess"
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+type psFlags struct {
+	orphaned bool
+}
+
+func newPsCmd() *cobra.Command {
+	var flags psFlags
+
+	cmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List the processes started by the tools of the agents",
+		Long: `List the MCP servers, LSP servers and shell commands started by the tools of
+the agents, for all the running docker agents. Processes are orphaned when the
+docker agent that started them exited without stopping them, after a crash for
+example.`,
+		Example: `  # List the processes
+  docker-agent ps
+
+  # Kill the orphaned processes
+  docker-agent ps kill --orphaned`,
+		Args:    cobra.NoArgs,
+		GroupID: "advanced",
+		RunE:    flags.runPsCommand,
+	}
+
+	cmd.Flags().BoolVar(&flags.orphaned, "orphaned", false, "Only list the orphaned processes")
+
+	cmd.AddCommand(newPsKillCmd())
+
+	return cmd
+}
+
+func (f *psFlags) runPsCommand(cmd *cobra.Command, _ []string) error {
+	telemetry.TrackCommand("ps", nil)
+
+	processes, err := listProcesses(f.orphaned)
+	if err != nil {
+		return err
+	}
+	if len(processes) == 0 {
+		cli.NewPrinter(cmd.OutOrStdout()).Println("No processes")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PID\tPARENT\tOWNER\tSTARTED\tSTATUS\tCOMMAND")
+	for _, p := range processes {
+		status := "running"
+		if p.Orphaned() {
+			status = "orphaned"
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", p.PID, p.ParentPID, p.Owner, p.StartedAt.Local().Format(time.DateTime), status, p.Command)
+	}
+	return w.Flush()
+}
+
+func newPsKillCmd() *cobra.Command {
+	var flags psFlags
+
+	cmd := &cobra.Command{
+		Use:   "kill [<pid>...]",
+		Short: "Kill processes started by the tools of the agents, and their children",
+		RunE:  flags.runPsKillCommand,
+	}
+
+	cmd.Flags().BoolVar(&flags.orphaned, "orphaned", false, "Kill all the orphaned processes")
+
+	return cmd
+}
+
+func (f *psFlags) runPsKillCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("ps", append([]string{"kill"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	if f.orphaned == (len(args) > 0) {
+		return errors.New("either give process IDs or use --orphaned")
+	}
+
+	var pids []int
+	for _, arg := range args {
+		pid, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid process ID %q", arg)
+		}
+		pids = append(pids, pid)
+	}
+	if f.orphaned {
+		processes, err := listProcesses(true)
+		if err != nil {
+			return err
+		}
+		for _, p := range processes {
+			pids = append(pids, p.PID)
+		}
+	}
+
+	for _, pid := range pids {
+		if err := childprocess.Kill(pid); err != nil {
+			return err
+		}
+		out.Printf("Killed process %d\n", pid)
+	}
+	if len(pids) == 0 {
+		out.Println("No orphaned processes")
+	}
+	return nil
+}
+
+func listProcesses(orphanedOnly bool) ([]childprocess.Process, error) {
+	processes, err := childprocess.List()
+	if err != nil {
+		return nil, err
+	}
+	if !orphanedOnly {
+		return processes, nil
+	}
+
+	var orphaned []childprocess.Process
+	for _, p := range processes {
+		if p.Orphaned() {
+			orphaned = append(orphaned, p)
+		}
+	}
+	return orphaned, nil
+}
@@ -0,0 +1,84 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/runlog"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+type replayFlags struct {
+	speed float64
+}
+
+func newReplayCmd() *cobra.Command {
+	var flags replayFlags
+
+	cmd := &cobra.Command{
+		Use:   "replay <run-log>|<session-id>",
+		Short: "Replay a recorded run in the TUI",
+		Long: `Replay a run recorded with ` + "`docker agent run --run-log`" + ` in the TUI.
+
+The run is played back from its run log: no model provider is called and no
+tool is run. Run logs are stored in ~/.cagent/runs, one file per session.`,
+		Example: `  docker agent replay ~/.cagent/runs/0b7f3c2e-5c4e-4d7a-9f0e-2f1d5b6a8c9d.jsonl
+  docker agent replay 0b7f3c2e-5c4e-4d7a-9f0e-2f1d5b6a8c9d --speed 4`,
+		GroupID: "advanced",
+		Args:    cobra.ExactArgs(1),
+		RunE:    flags.runReplayCommand,
+	}
+
+	cmd.Flags().Float64Var(&flags.speed, "speed", 1, "Playback speed (0 replays without pauses)")
+
+	return cmd
+}
+
+func (f *replayFlags) runReplayCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("replay", args)
+
+	if f.speed < 0 {
+		return errors.New("--speed must not be negative")
+	}
+
+	path, err := resolveRunLog(args[0], filepath.Join(paths.GetDataDir(), "runs"))
+	if err != nil {
+		return err
+	}
+
+	entries, err := runlog.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	rt, err := runtime.NewReplayRuntime(entries, runtime.WithReplaySpeed(f.speed))
+	if err != nil {
+		return fmt.Errorf("replaying %s: %w", path, err)
+	}
+
+	sess := session.New(session.WithTitle("Replay of " + strings.TrimSuffix(filepath.Base(path), ".jsonl")))
+
+	applyTheme()
+	return runTUI(cmd.Context(), rt, sess, nil, nil)
+}
+
+// resolveRunLog finds the run log file for a path or a session ID.
+func resolveRunLog(ref, runsDir string) (string, error) {
+	if _, err := os.Stat(ref); err == nil {
+		return ref, nil
+	}
+
+	path := filepath.Join(runsDir, ref+".jsonl")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("no run log found for %q", ref)
+	}
+	return path, nil
+}
@@ -0,0 +1,31 @@
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRunLog(t *testing.T) {
+	t.Parallel()
+
+	runsDir := t.TempDir()
+	byID := filepath.Join(runsDir, "abc.jsonl")
+	require.NoError(t, os.WriteFile(byID, nil, 0o600))
+	byPath := filepath.Join(t.TempDir(), "run.jsonl")
+	require.NoError(t, os.WriteFile(byPath, nil, 0o600))
+
+	path, err := resolveRunLog(byPath, runsDir)
+	require.NoError(t, err)
+	assert.Equal(t, byPath, path)
+
+	path, err = resolveRunLog("abc", runsDir)
+	require.NoError(t, err)
+	assert.Equal(t, byID, path)
+
+	_, err = resolveRunLog("missing", runsDir)
+	require.ErrorContains(t, err, `no run log found for "missing"`)
+}
@@ -156,9 +156,21 @@ We collect anonymous usage data to help improve docker agent. To disable:
 		newRunCmd(),
 		newNewCmd(),
 		newEvalCmd(),
+		newCompareCmd(),
 		newShareCmd(),
+		newSearchCmd(),
+		newInspectCmd(),
+		newReplayCmd(),
+		newAuditCmd(),
 		newDebugCmd(),
 		newAliasCmd(),
+		newModelsCmd(),
+		newPsCmd(),
+		newSessionsCmd(),
+		newMCPToolsCmd(),
+		newDoctorCmd(),
+		newAuthCmd(),
+		newConfigCmd(),
 		newServeCmd(),
 	)
 
@@ -255,6 +267,14 @@ func (e RuntimeError) Unwrap() error {
 	return e.Err
 }
 
+// withExitCode makes the command exit with the given code when err is not nil.
+func withExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return cli.StatusError{Cause: err, StatusCode: code}
+}
+
 // isFirstRun checks if this is the first time docker agent is being run.
 // It atomically creates a marker file in the user's config directory
 // using os.O_EXCL to avoid a race condition when multiple processes
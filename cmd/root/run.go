@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	goruntime "runtime"
 	"runtime/pprof"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,31 +20,50 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"github.com/docker/docker-agent/pkg/app"
+	"github.com/docker/docker-agent/pkg/audit"
 	"github.com/docker/docker-agent/pkg/cli"
 	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/confighistory"
+	"github.com/docker/docker-agent/pkg/httpclient"
 	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/project"
+	"github.com/docker/docker-agent/pkg/runlog"
 	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
-	"github.com/docker/docker-agent/pkg/sessiontitle"
 	"github.com/docker/docker-agent/pkg/teamloader"
 	"github.com/docker/docker-agent/pkg/telemetry"
 	"github.com/docker/docker-agent/pkg/tui"
 	"github.com/docker/docker-agent/pkg/tui/styles"
+	"github.com/docker/docker-agent/pkg/usercommands"
 	"github.com/docker/docker-agent/pkg/userconfig"
+	"github.com/docker/docker-agent/pkg/version"
 )
 
 type runExecFlags struct {
 	agentName         string
 	autoApprove       bool
-	attachmentPath    string
+	attachmentPaths   []string
 	remoteAddress     string
 	modelOverrides    []string
+	temperatures      []string
+	maxIterations     []string
 	promptFiles       []string
 	dryRun            bool
 	runConfig         config.RuntimeConfig
 	sessionDB         string
 	sessionID         string
+	continueSession   bool
+	resumeSession     string
+	continueIters     int
 	recordPath        string
+	runLog            bool
+	runLogger         *runlog.Log
+	debugLLM          bool
+	simulate          bool
+	eventLog          string
+	eventSinks        []runtime.EventSink
+	auditLog          string
+	auditLogger       *audit.Log
 	fakeResponses     string
 	fakeStreamDelay   int
 	exitAfterResponse bool
@@ -51,11 +72,17 @@ type runExecFlags struct {
 	forceTUI          bool
 	sandbox           bool
 	sandboxTemplate   string
+	project           *project.Project
 
 	// Exec only
 	exec          bool
 	hideToolCalls bool
+	output        string
 	outputJSON    bool
+	answers       []string
+	answersFile   string
+	maxCost       float64
+	artifactsDir  string
 
 	// Run only
 	hideToolResults bool
@@ -92,18 +119,29 @@ func addRunOrExecFlags(cmd *cobra.Command, flags *runExecFlags) {
 	cmd.PersistentFlags().StringVarP(&flags.agentName, "agent", "a", "root", "Name of the agent to run")
 	cmd.PersistentFlags().BoolVar(&flags.autoApprove, "yolo", false, "Automatically approve all tool calls without prompting")
 	cmd.PersistentFlags().BoolVar(&flags.hideToolResults, "hide-tool-results", false, "Hide tool call results")
-	cmd.PersistentFlags().StringVar(&flags.attachmentPath, "attach", "", "Attach an image file to the message")
+	cmd.PersistentFlags().StringArrayVar(&flags.attachmentPaths, "attach", nil, "Attach a file (text, image or PDF) to the message (repeatable)")
 	cmd.PersistentFlags().StringArrayVar(&flags.promptFiles, "prompt-file", nil, "Append file contents to the prompt (repeatable)")
 	cmd.PersistentFlags().StringArrayVar(&flags.modelOverrides, "model", nil, "Override agent model: [agent=]provider/model (repeatable)")
+	cmd.PersistentFlags().StringArrayVar(&flags.temperatures, "temperature", nil, "Override the temperature of agent models: [agent=]temperature (repeatable)")
+	cmd.PersistentFlags().StringArrayVar(&flags.maxIterations, "max-iterations", nil, "Override the maximum number of tool-calling loops of agents: [agent=]iterations (repeatable)")
 	cmd.PersistentFlags().BoolVar(&flags.dryRun, "dry-run", false, "Initialize the agent without executing anything")
+	cmd.PersistentFlags().BoolVar(&flags.simulate, "simulate", false, "Simulate the tool calls that change things instead of running them: the agent narrates the plan of actions it would carry out")
 	cmd.PersistentFlags().StringVar(&flags.remoteAddress, "remote", "", "Use remote runtime with specified address")
 	cmd.PersistentFlags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
 	cmd.PersistentFlags().StringVar(&flags.sessionID, "session", "", "Continue from a previous session by ID or relative offset (e.g., -1 for last session)")
+	cmd.PersistentFlags().BoolVarP(&flags.continueSession, "continue", "c", false, "Continue the most recent session of the current directory")
+	cmd.PersistentFlags().StringVar(&flags.resumeSession, "resume", "", "Resume a previous session by ID (--resume=<id>), or the most recent session of the current directory")
+	cmd.PersistentFlags().Lookup("resume").NoOptDefVal = resumeLatest // --resume without value picks the latest session
+	cmd.PersistentFlags().IntVar(&flags.continueIters, "continue-iterations", 0, "Continue the run of a resumed session that paused at its iteration limit, for this many iterations")
 	cmd.PersistentFlags().StringVar(&flags.fakeResponses, "fake", "", "Replay AI responses from cassette file (for testing)")
 	cmd.PersistentFlags().IntVar(&flags.fakeStreamDelay, "fake-stream", 0, "Simulate streaming with delay in ms between chunks (default 15ms if no value given)")
 	cmd.Flag("fake-stream").NoOptDefVal = "15" // --fake-stream without value uses 15ms
 	cmd.PersistentFlags().StringVar(&flags.recordPath, "record", "", "Record AI API interactions to cassette file (auto-generates filename if empty)")
 	cmd.PersistentFlags().Lookup("record").NoOptDefVal = "true"
+	cmd.PersistentFlags().BoolVar(&flags.runLog, "run-log", false, "Record a local run log of every session that can be replayed with `docker agent replay`")
+	cmd.PersistentFlags().BoolVar(&flags.debugLLM, "debug-llm", false, "Dump every request sent to the model providers, and their responses, to files (credentials are redacted)")
+	cmd.PersistentFlags().StringVar(&flags.eventLog, "event-log", "", "Write every runtime event to a JSONL file")
+	cmd.PersistentFlags().StringVar(&flags.auditLog, "audit-log", "", "Append a tamper-evident log of the tool calls and approvals to a file, syslog (syslog or syslog://host:port) or an HTTP(S) endpoint")
 	cmd.PersistentFlags().BoolVar(&flags.exitAfterResponse, "exit-after-response", false, "Exit TUI after first assistant response completes")
 	_ = cmd.PersistentFlags().MarkHidden("exit-after-response")
 	cmd.PersistentFlags().StringVar(&flags.cpuProfile, "cpuprofile", "", "Write CPU profile to file")
@@ -115,11 +153,23 @@ func addRunOrExecFlags(cmd *cobra.Command, flags *runExecFlags) {
 	cmd.PersistentFlags().BoolVar(&flags.sandbox, "sandbox", false, "Run the agent inside a Docker sandbox (requires Docker Desktop with sandbox support)")
 	cmd.PersistentFlags().StringVar(&flags.sandboxTemplate, "template", "", "Template image for the sandbox (passed to docker sandbox create -t)")
 	cmd.MarkFlagsMutuallyExclusive("fake", "record")
+	cmd.MarkFlagsMutuallyExclusive("session", "continue", "resume")
+	_ = cmd.RegisterFlagCompletionFunc("agent", completeAgentName)
+	_ = cmd.RegisterFlagCompletionFunc("model", completeModel)
+	_ = cmd.RegisterFlagCompletionFunc("session", completeSessionID)
+	_ = cmd.RegisterFlagCompletionFunc("resume", completeSessionID)
 
 	// --exec only
 	cmd.PersistentFlags().BoolVar(&flags.exec, "exec", false, "Execute without a TUI")
 	cmd.PersistentFlags().BoolVar(&flags.hideToolCalls, "hide-tool-calls", false, "Hide the tool calls in the output")
+	cmd.PersistentFlags().StringVar(&flags.output, "output", string(cli.OutputText), "Output format of --exec: text, json (a single result once done) or stream-json (one event per line)")
 	cmd.PersistentFlags().BoolVar(&flags.outputJSON, "json", false, "Output results in JSON format")
+	_ = cmd.PersistentFlags().MarkDeprecated("json", "use --output stream-json instead")
+	_ = cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json", "stream-json"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.PersistentFlags().StringArrayVar(&flags.answers, "answer", nil, "Answer the agent's questions without prompting: key=value (repeatable)")
+	cmd.PersistentFlags().StringVar(&flags.answersFile, "answers-file", "", "JSON file with answers to the agent's questions")
+	cmd.PersistentFlags().Float64Var(&flags.maxCost, "max-cost", 0, "Stop once the run costs more than this many dollars (0 for no limit)")
+	cmd.PersistentFlags().StringVar(&flags.artifactsDir, "artifacts-dir", "", "Copy the artifacts of the session to this directory once the run is over")
 }
 
 func (f *runExecFlags) runRunCommand(cmd *cobra.Command, args []string) error {
@@ -134,6 +184,16 @@ func (f *runExecFlags) runRunCommand(cmd *cobra.Command, args []string) error {
 		telemetry.TrackCommand("run", args)
 	}
 
+	if err := cli.ValidateAttachments(f.attachmentPaths); err != nil {
+		return err
+	}
+	if err := f.validateContinueIterations(); err != nil {
+		return err
+	}
+	if f.simulate && f.remoteAddress != "" {
+		return errors.New("--simulate can't be used with --remote")
+	}
+
 	ctx := cmd.Context()
 	out := cli.NewPrinter(cmd.OutOrStdout())
 
@@ -183,6 +243,7 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 
 	// Apply global user settings first (lowest priority)
 	// User settings only apply if the flag wasn't explicitly set by the user
+	yoloFlag := f.autoApprove
 	userSettings := userconfig.Get()
 	if userSettings.HideToolResults && !f.hideToolResults {
 		f.hideToolResults = true
@@ -192,6 +253,20 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 		f.autoApprove = true
 		slog.Debug("Applying user settings", "YOLO", true)
 	}
+	if userSettings.RunLog && !f.runLog {
+		f.runLog = true
+		slog.Debug("Applying user settings", "run_log", true)
+	}
+	if userSettings.AuditLog != "" && f.auditLog == "" {
+		f.auditLog = userSettings.AuditLog
+		slog.Debug("Applying user settings", "audit_log", f.auditLog)
+	}
+
+	// Apply project settings, which take precedence over the user settings
+	agentFileName, err := f.applyProjectSettings(ctx, out, agentFileName, yoloFlag)
+	if err != nil {
+		return withExitCode(err, cli.ExitCodeConfigInvalid)
+	}
 
 	// Apply alias options if this is an alias reference
 	// Alias options only apply if the flag wasn't explicitly set by the user
@@ -233,6 +308,49 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 		out.Println("Recording mode enabled, cassette: " + cassettePath)
 	}
 
+	// Record a local run log of every session if --run-log is specified.
+	if f.runLog && f.remoteAddress == "" {
+		f.runLogger = runlog.New(filepath.Join(paths.GetDataDir(), "runs"))
+		defer func() {
+			if err := f.runLogger.Close(); err != nil {
+				slog.Error("Failed to close run log", "error", err)
+			}
+		}()
+		out.Println("Run log enabled, directory: " + f.runLogger.Dir())
+	}
+
+	// Dump the model requests and responses of every session if --debug-llm
+	// is specified.
+	if f.debugLLM && f.remoteAddress == "" {
+		dump := httpclient.NewDebugDump(filepath.Join(paths.GetDataDir(), "debug-llm"))
+		defer httpclient.UseDebugDump(dump)()
+		out.Println("LLM debug dump enabled, directory: " + dump.Dir())
+	}
+
+	// Write every runtime event to a file if --event-log is specified.
+	if f.eventLog != "" && f.remoteAddress == "" {
+		eventLog, err := os.Create(f.eventLog)
+		if err != nil {
+			return fmt.Errorf("creating event log: %w", err)
+		}
+		defer eventLog.Close()
+		f.eventSinks = append(f.eventSinks, runtime.NewJSONLSink(eventLog))
+	}
+
+	// Append the tool calls and approvals to an audit log if --audit-log is
+	// specified. Runs must not go unaudited, so failing to open it fails.
+	if f.auditLog != "" && f.remoteAddress == "" {
+		f.auditLogger, err = audit.Open(f.auditLog)
+		if err != nil {
+			return fmt.Errorf("opening audit log: %w", err)
+		}
+		defer func() {
+			if err := f.auditLogger.Close(); err != nil {
+				slog.Error("Failed to close audit log", "error", err)
+			}
+		}()
+	}
+
 	// Remote runtime
 	if f.remoteAddress != "" {
 		rt, sess, err := f.createRemoteRuntimeAndSession(ctx, agentFileName)
@@ -245,11 +363,16 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 	// Local runtime
 	agentSource, err := config.Resolve(agentFileName, f.runConfig.EnvProvider())
 	if err != nil {
-		return err
+		return withExitCode(err, cli.ExitCodeConfigInvalid)
 	}
 
 	loadResult, err := f.loadAgentFrom(ctx, agentSource)
 	if err != nil {
+		return withExitCode(err, cli.ExitCodeConfigInvalid)
+	}
+
+	configHash := recordConfigVersion(ctx, agentSource)
+	if err := f.auditConfig(agentSource, configHash); err != nil {
 		return err
 	}
 
@@ -257,6 +380,10 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 	if err != nil {
 		return err
 	}
+	configWarning := configChangeWarning(sess, configHash)
+	if configHash != "" {
+		sess.ConfigHash = configHash
+	}
 	defer func() {
 		if err := rt.Close(); err != nil {
 			slog.Error("Failed to close runtime", "error", err)
@@ -280,6 +407,9 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 	}
 
 	if !useTUI {
+		if configWarning != "" {
+			out.Printf("Warning: %s\n", configWarning)
+		}
 		return f.handleExecMode(ctx, out, rt, sess, args)
 	}
 
@@ -287,6 +417,9 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 	if err != nil {
 		return err
 	}
+	if configWarning != "" {
+		opts = append(opts, app.WithStartupWarnings(configWarning))
+	}
 
 	var sessStore session.Store
 	switch typedRt := rt.(type) {
@@ -302,6 +435,8 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 func (f *runExecFlags) loadAgentFrom(ctx context.Context, agentSource config.Source) (*teamloader.LoadResult, error) {
 	opts := []teamloader.Opt{
 		teamloader.WithModelOverrides(f.modelOverrides),
+		teamloader.WithTemperatureOverrides(f.temperatures),
+		teamloader.WithMaxIterationsOverrides(f.maxIterations),
 	}
 	if len(f.promptFiles) > 0 {
 		opts = append(opts, teamloader.WithPromptFiles(f.promptFiles))
@@ -350,7 +485,11 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 		return nil, nil, err
 	}
 
-	sessStore, err := session.NewSQLiteSessionStore(sessionDB)
+	storeOpts, err := sessionStoreOpts()
+	if err != nil {
+		return nil, nil, err
+	}
+	sessStore, err := session.NewSQLiteSessionStore(sessionDB, storeOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating session store: %w", err)
 	}
@@ -369,17 +508,28 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 		runtime.WithCurrentAgent(f.agentName),
 		runtime.WithTracer(otel.Tracer(AppName)),
 		runtime.WithModelSwitcherConfig(modelSwitcherCfg),
+		runtime.WithRunLog(f.runLogger),
+		runtime.WithAuditLog(f.auditLogger),
+		f.withPermissionsStore(),
+		runtime.WithUserCommands(usercommands.Load(f.runConfig.WorkingDir)),
+		f.withEventSinks(),
+		withContextWarnings(),
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating runtime: %w", err)
 	}
 
+	sessionRef, err := f.sessionRef(ctx, sessStore)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var sess *session.Session
-	if f.sessionID != "" {
+	if sessionRef != "" {
 		// Resolve relative session references (e.g., "-1" for last session)
-		resolvedID, err := session.ResolveSessionID(ctx, sessStore, f.sessionID)
+		resolvedID, err := session.ResolveSessionID(ctx, sessStore, sessionRef)
 		if err != nil {
-			return nil, nil, fmt.Errorf("resolving session %q: %w", f.sessionID, err)
+			return nil, nil, fmt.Errorf("resolving session %q: %w", sessionRef, err)
 		}
 
 		// Load existing session
@@ -387,8 +537,10 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 		if err != nil {
 			return nil, nil, fmt.Errorf("loading session %q: %w", resolvedID, err)
 		}
-		sess.ToolsApproved = f.autoApprove
+		// Keep the approval state of the session, unless --yolo approves everything.
+		sess.ToolsApproved = sess.ToolsApproved || f.autoApprove
 		sess.HideToolResults = f.hideToolResults
+		sess.Simulate = f.simulate
 
 		// Apply any stored model overrides from the session
 		if len(sess.AgentModelOverrides) > 0 {
@@ -401,7 +553,7 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 			}
 		}
 
-		slog.Debug("Loaded existing session", "session_id", resolvedID, "session_ref", f.sessionID, "agent", f.agentName)
+		slog.Debug("Loaded existing session", "session_id", resolvedID, "session_ref", sessionRef, "agent", f.agentName)
 	} else {
 		wd, _ := os.Getwd()
 		sess = session.New(f.buildSessionOpts(agent.MaxIterations(), agent.ThinkingConfigured(), wd)...)
@@ -413,21 +565,195 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 	return localRt, sess, nil
 }
 
+// recordConfigVersion records the version of the agent configuration being
+// run in the configuration history and returns its hash. The history is best
+// effort: failures are logged and return an empty hash.
+func recordConfigVersion(ctx context.Context, agentSource config.Source) string {
+	data, err := agentSource.Read(ctx)
+	if err != nil {
+		slog.Warn("Failed to read the agent configuration for its history", "source", agentSource.Name(), "error", err)
+		return ""
+	}
+	hash, err := confighistory.Default().Record(confighistory.SourceKey(agentSource), data)
+	if err != nil {
+		slog.Warn("Failed to record the agent configuration in its history", "source", agentSource.Name(), "error", err)
+		return ""
+	}
+	return hash
+}
+
+// auditConfig records the configuration of the run in the audit log, if any.
+func (f *runExecFlags) auditConfig(agentSource config.Source, configHash string) error {
+	if f.auditLogger == nil {
+		return nil
+	}
+	host, _ := os.Hostname()
+	err := f.auditLogger.Record("", audit.TypeConfig, audit.Config{
+		Source:      agentSource.Name(),
+		Hash:        configHash,
+		Version:     version.Version,
+		Host:        host,
+		WorkingDir:  f.runConfig.WorkingDir,
+		AutoApprove: f.autoApprove,
+	})
+	if err != nil {
+		return fmt.Errorf("recording the configuration in the audit log: %w", err)
+	}
+	return nil
+}
+
+// configChangeWarning returns a warning when a resumed session was started
+// with another version of the agent configuration than the one being run.
+func configChangeWarning(sess *session.Session, configHash string) string {
+	if sess.ConfigHash == "" || configHash == "" || sess.ConfigHash == configHash {
+		return ""
+	}
+	from, to := confighistory.Short(sess.ConfigHash), confighistory.Short(configHash)
+	return fmt.Sprintf("The agent configuration changed since this session was started. See the changes with: docker agent config diff %s %s", from, to)
+}
+
+// validateContinueIterations checks that --continue-iterations continues a
+// local session picked with --session, --continue or --resume.
+func (f *runExecFlags) validateContinueIterations() error {
+	switch {
+	case f.continueIters < 0:
+		return errors.New("--continue-iterations must be positive")
+	case f.continueIters == 0:
+		return nil
+	case f.remoteAddress != "":
+		return errors.New("--continue-iterations can't be used with --remote")
+	case f.sessionID == "" && !f.continueSession && f.resumeSession == "":
+		return errors.New("--continue-iterations needs a session to continue: use --session, --continue or --resume")
+	default:
+		return nil
+	}
+}
+
+// resumeLatest is the value of --resume when no session ID is given.
+const resumeLatest = "latest"
+
+// sessionRef returns the reference of the session to continue, if any:
+// the --session or --resume ID, or the most recent session of the current
+// directory for --continue and --resume without an ID.
+func (f *runExecFlags) sessionRef(ctx context.Context, sessStore session.Store) (string, error) {
+	switch {
+	case f.continueSession, f.resumeSession == resumeLatest:
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("getting working directory: %w", err)
+		}
+		id, err := session.LatestSessionInDir(ctx, sessStore, wd)
+		if errors.Is(err, session.ErrNotFound) {
+			return "", fmt.Errorf("no previous session to continue in %s", wd)
+		}
+		return id, err
+	case f.resumeSession != "":
+		return f.resumeSession, nil
+	default:
+		return f.sessionID, nil
+	}
+}
+
+// withEventSinks subscribes the sinks enabled by flags to the runtime's events.
+func (f *runExecFlags) withEventSinks() runtime.Opt {
+	return func(r *runtime.LocalRuntime) {
+		for _, sink := range f.eventSinks {
+			r.EventBus().Subscribe(sink)
+		}
+	}
+}
+
+// withPermissionsStore saves the tool calls the user always approves to the
+// project settings.
+func (f *runExecFlags) withPermissionsStore() runtime.Opt {
+	return func(r *runtime.LocalRuntime) {
+		if f.project != nil {
+			runtime.WithPermissionsStore(f.project)(r)
+		}
+	}
+}
+
+// withContextWarnings applies the context warning thresholds of the user
+// settings, if any.
+func withContextWarnings() runtime.Opt {
+	thresholds := userconfig.Get().ContextWarningThresholds
+	if thresholds == nil {
+		return func(*runtime.LocalRuntime) {}
+	}
+	return runtime.WithContextWarningThresholds(thresholds...)
+}
+
 func (f *runExecFlags) handleExecMode(ctx context.Context, out *cli.Printer, rt runtime.Runtime, sess *session.Session, args []string) error {
 	// args[0] is the agent file; args[1:] are user messages for multi-turn conversation
 	userMessages := args[1:]
 
-	err := cli.Run(ctx, out, cli.Config{
-		AppName:        AppName,
-		AttachmentPath: f.attachmentPath,
-		HideToolCalls:  f.hideToolCalls,
-		OutputJSON:     f.outputJSON,
-		AutoApprove:    f.autoApprove,
+	answers, err := cli.ParseAnswers(f.answersFile, f.answers)
+	if err != nil {
+		return err
+	}
+
+	stdin, err := readPipedStdin(userMessages)
+	if err != nil {
+		return err
+	}
+
+	output, err := cli.ParseOutputFormat(f.output)
+	if err != nil {
+		return err
+	}
+	if f.outputJSON {
+		output = cli.OutputStreamJSON
+	}
+
+	if f.continueIters > 0 {
+		sess.IterationBudget = f.continueIters
+	}
+
+	err = cli.Run(ctx, out, cli.Config{
+		AppName:         AppName,
+		AttachmentPaths: f.attachmentPaths,
+		Stdin:           stdin,
+		HideToolCalls:   f.hideToolCalls,
+		Output:          output,
+		AutoApprove:     f.autoApprove,
+		Answers:         answers,
+		MaxCost:         f.maxCost,
+		ArtifactsDir:    f.artifactsDir,
+		Continue:        f.continueIters > 0,
 	}, rt, sess, userMessages)
+	if errors.Is(err, cli.ErrMaxIterations) && output == cli.OutputText {
+		out.Printf("\nThe run is paused. Continue it with: docker agent run --exec --session %s --continue-iterations %d\n", sess.ID, runtime.DefaultContinueIterations)
+	}
+	code := cli.ExitCode(err)
 	if cliErr, ok := errors.AsType[cli.RuntimeError](err); ok {
-		return RuntimeError{Err: cliErr.Err}
+		err = RuntimeError{Err: cliErr.Err}
 	}
-	return err
+	return withExitCode(err, code)
+}
+
+// readPipedStdin reads the content piped to the command, to attach it to the
+// messages given as arguments: `cat report.csv | docker agent run --exec
+// agent.yaml "summarize"`. Nothing is read when stdin is a terminal or when
+// it is the message itself ("-").
+func readPipedStdin(userMessages []string) (string, error) {
+	if len(userMessages) == 0 || slices.Contains(userMessages, "-") {
+		return "", nil
+	}
+
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return "", nil
+	}
+	// Only read from pipes and redirected files, never wait for a terminal.
+	if fi.Mode()&os.ModeNamedPipe == 0 && !fi.Mode().IsRegular() {
+		return "", nil
+	}
+
+	buf, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	}
+	return string(buf), nil
 }
 
 func readInitialMessage(args []string) (*string, error) {
@@ -482,12 +808,15 @@ func (f *runExecFlags) buildAppOpts(args []string) ([]app.Opt, error) {
 	if len(args) > 2 {
 		opts = append(opts, app.WithQueuedMessages(args[2:]))
 	}
-	if f.attachmentPath != "" {
-		opts = append(opts, app.WithFirstMessageAttachment(f.attachmentPath))
+	if len(f.attachmentPaths) > 0 {
+		opts = append(opts, app.WithFirstMessageAttachment(f.attachmentPaths...))
 	}
 	if f.exitAfterResponse {
 		opts = append(opts, app.WithExitAfterFirstResponse())
 	}
+	if f.continueIters > 0 {
+		opts = append(opts, app.WithContinueIterations(f.continueIters))
+	}
 	return opts, nil
 }
 
@@ -499,8 +828,9 @@ func (f *runExecFlags) buildSessionOpts(maxIterations int, thinking bool, workin
 		session.WithMaxIterations(maxIterations),
 		session.WithToolsApproved(f.autoApprove),
 		session.WithHideToolResults(f.hideToolResults),
+		session.WithSimulate(f.simulate),
 		session.WithThinking(thinking),
-		session.WithWorkingDir(workingDir),
+		session.WithWorkingDirs(append([]string{workingDir}, f.runConfig.AdditionalDirs...)...),
 	}
 }
 
@@ -512,7 +842,11 @@ func (f *runExecFlags) createSessionSpawner(agentSource config.Source, sessStore
 		runConfigCopy.WorkingDir = workingDir
 
 		// Load team with the new working directory
-		loadResult, err := teamloader.LoadWithConfig(spawnCtx, agentSource, runConfigCopy, teamloader.WithModelOverrides(f.modelOverrides))
+		loadResult, err := teamloader.LoadWithConfig(spawnCtx, agentSource, runConfigCopy,
+			teamloader.WithModelOverrides(f.modelOverrides),
+			teamloader.WithTemperatureOverrides(f.temperatures),
+			teamloader.WithMaxIterationsOverrides(f.maxIterations),
+		)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -538,6 +872,11 @@ func (f *runExecFlags) createSessionSpawner(agentSource config.Source, sessStore
 			runtime.WithCurrentAgent(f.agentName),
 			runtime.WithTracer(otel.Tracer(AppName)),
 			runtime.WithModelSwitcherConfig(modelSwitcherCfg),
+			runtime.WithRunLog(f.runLogger),
+			f.withPermissionsStore(),
+			runtime.WithUserCommands(usercommands.Load(workingDir)),
+			f.withEventSinks(),
+			withContextWarnings(),
 		)
 		if err != nil {
 			return nil, nil, nil, err
@@ -545,6 +884,7 @@ func (f *runExecFlags) createSessionSpawner(agentSource config.Source, sessStore
 
 		// Create a new session
 		newSess := session.New(f.buildSessionOpts(agent.MaxIterations(), agent.ThinkingConfigured(), workingDir)...)
+		newSess.ConfigHash = recordConfigVersion(spawnCtx, agentSource)
 
 		// Create cleanup function
 		cleanup := func() {
@@ -553,10 +893,8 @@ func (f *runExecFlags) createSessionSpawner(agentSource config.Source, sessStore
 
 		// Create the app
 		var appOpts []app.Opt
-		if pr, ok := localRt.(*runtime.PersistentRuntime); ok {
-			if model := pr.CurrentAgent().Model(); model != nil {
-				appOpts = append(appOpts, app.WithTitleGenerator(sessiontitle.New(model)))
-			}
+		if gen := localRt.TitleGenerator(); gen != nil {
+			appOpts = append(appOpts, app.WithTitleGenerator(gen))
 		}
 
 		a := app.New(spawnCtx, localRt, newSess, appOpts...)
@@ -580,6 +918,74 @@ func stopToolSets(t toolStopper) {
 	}
 }
 
+// applyProjectSettings applies the settings of the project of the working
+// directory, if any, and returns the agent to run. Flags take precedence.
+func (f *runExecFlags) applyProjectSettings(ctx context.Context, out *cli.Printer, agentFileName string, yoloFlag bool) (string, error) {
+	prj, err := project.Find(".")
+	if err != nil {
+		return agentFileName, err
+	}
+	if prj == nil {
+		// Without a project, the tool calls the user always approves are
+		// only approved for the session: no project is created on their
+		// behalf in whatever directory they run from.
+		return agentFileName, nil
+	}
+	slog.Debug("Applying project settings", "root", prj.Root)
+	f.project = prj
+	f.trustProject(ctx, out, prj)
+
+	if agentFileName == "" {
+		agentFileName = prj.AgentRef()
+	}
+	switch prj.ApprovalPolicy() {
+	case project.ApprovalYOLO:
+		f.autoApprove = true
+	case project.ApprovalAsk:
+		f.autoApprove = yoloFlag
+	}
+	f.runConfig.EnvFiles = append(f.runConfig.EnvFiles, prj.EnvFilePaths()...)
+	f.runConfig.TasksPath = prj.TasksPath()
+	f.runConfig.MemoryPath = prj.MemoryPath()
+
+	return agentFileName, nil
+}
+
+// trustProject trusts the project if its settings auto-approve tool calls and
+// the user trusts it: either it's in the trusted projects of the user
+// config, or the user answers yes when asked the first time, in interactive
+// runs. Otherwise, these settings are ignored.
+func (f *runExecFlags) trustProject(ctx context.Context, out *cli.Printer, prj *project.Project) {
+	settings := prj.UntrustedSettings()
+	if len(settings) == 0 {
+		return
+	}
+
+	cfg, err := userconfig.Load()
+	if err != nil {
+		slog.Warn("Failed to load the trusted projects", "error", err)
+		cfg = &userconfig.Config{}
+	}
+	if cfg.IsProjectTrusted(prj.Root) {
+		prj.Trust()
+		return
+	}
+
+	if f.exec || !isatty.IsTerminal(os.Stdin.Fd()) {
+		out.Printf("Warning: ignoring the settings of the untrusted project %s that auto-approve tool calls (%s). Run docker agent interactively in it to trust it.\n", prj.Root, strings.Join(settings, "; "))
+		return
+	}
+	if out.PromptProjectTrust(ctx, prj.Root, settings) != cli.ConfirmationApprove {
+		return
+	}
+
+	prj.Trust()
+	cfg.TrustProject(prj.Root)
+	if err := cfg.Save(); err != nil {
+		slog.Warn("Failed to save the trusted project, it's only trusted for this run", "root", prj.Root, "error", err)
+	}
+}
+
 // applyTheme applies the theme from user config, or the built-in default.
 func applyTheme() {
 	// Resolve theme from user config > built-in default
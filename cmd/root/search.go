@@ -0,0 +1,101 @@
+package root
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/hub"
+	"github.com/docker/docker-agent/pkg/remote"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+// searchConcurrency bounds the number of registry requests made in parallel
+// to tell agents apart from other images.
+const searchConcurrency = 8
+
+type searchFlags struct {
+	limit int
+}
+
+func newSearchCmd() *cobra.Command {
+	var flags searchFlags
+
+	cmd := &cobra.Command{
+		Use:   "search <term>",
+		Short: "Search Docker Hub for shared agents",
+		Long: `Search Docker Hub for agents shared with ` + "`docker agent share push`" + `.
+
+Only repositories whose latest tag is an agent are listed. Use
+` + "`docker agent inspect`" + ` to see what an agent needs before running it.`,
+		Example: `  docker agent search pirate`,
+		GroupID: "core",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    flags.runSearchCommand,
+	}
+
+	cmd.Flags().IntVar(&flags.limit, "limit", 25, "Maximum number of repositories to search")
+
+	return cmd
+}
+
+func (f *searchFlags) runSearchCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("search", args)
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	term := strings.Join(args, " ")
+
+	repos, err := hub.NewClient().Search(ctx, term, f.limit)
+	if err != nil {
+		return err
+	}
+
+	agents := filterAgents(ctx, repos, func(ctx context.Context, repo string) bool {
+		return remote.IsAgent(ctx, repo+":latest")
+	})
+	if len(agents) == 0 {
+		out.Printf("No agents found for %q\n", term)
+		return nil
+	}
+
+	maxLen := 0
+	for _, repo := range agents {
+		maxLen = max(maxLen, runewidth.StringWidth(repo.Name))
+	}
+	for _, repo := range agents {
+		padding := strings.Repeat(" ", maxLen-runewidth.StringWidth(repo.Name))
+		out.Printf("  %s%s  ★ %-4d %s\n", repo.Name, padding, repo.Stars, repo.Description)
+	}
+
+	out.Println("\nInspect an agent with: docker agent inspect <name>")
+	return nil
+}
+
+// filterAgents keeps the repositories that are agents, preserving the
+// search order.
+func filterAgents(ctx context.Context, repos []hub.Repository, isAgent func(ctx context.Context, repo string) bool) []hub.Repository {
+	keep := make([]bool, len(repos))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(searchConcurrency)
+	for i, repo := range repos {
+		g.Go(func() error {
+			keep[i] = isAgent(ctx, repo.Name)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var agents []hub.Repository
+	for i, repo := range repos {
+		if keep[i] {
+			agents = append(agents, repo)
+		}
+	}
+	return agents
+}
@@ -0,0 +1,190 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/telemetry"
+	"github.com/docker/docker-agent/pkg/userconfig"
+)
+
+// sessionStoreOpts returns the options of the session store: the key
+// encrypting the sessions, from DOCKER_AGENT_SESSION_KEY or, with the
+// encrypt_sessions setting, from the system keychain, and the retention
+// policy of the session_retention setting.
+func sessionStoreOpts() ([]session.SQLiteOpt, error) {
+	key, err := session.EncryptionKey(userconfig.Get().EncryptSessions, paths.GetDataDir())
+	if err != nil {
+		return nil, err
+	}
+	policy, err := sessionRetentionPolicy(userconfig.Get().SessionRetention)
+	if err != nil {
+		return nil, err
+	}
+	return []session.SQLiteOpt{session.WithEncryptionKey(key), session.WithRetention(policy)}, nil
+}
+
+// sessionRetentionPolicy parses the session_retention setting.
+func sessionRetentionPolicy(retention *userconfig.SessionRetention) (session.RetentionPolicy, error) {
+	var policy session.RetentionPolicy
+	if retention == nil {
+		return policy, nil
+	}
+	if retention.MaxAge != "" {
+		maxAge, err := session.ParseRetentionAge(retention.MaxAge)
+		if err != nil {
+			return policy, fmt.Errorf("session_retention.max_age: %w", err)
+		}
+		policy.MaxAge = maxAge
+	}
+	if retention.MaxSize != "" {
+		maxSize, err := session.ParseRetentionSize(retention.MaxSize)
+		if err != nil {
+			return policy, fmt.Errorf("session_retention.max_size: %w", err)
+		}
+		policy.MaxSize = maxSize
+	}
+	policy.MaxCount = retention.MaxCount
+	return policy, nil
+}
+
+type sessionsPruneFlags struct {
+	sessionDB string
+	olderThan string
+	keep      int
+	maxSize   string
+	dryRun    bool
+}
+
+func newSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Manage the sessions of the session database",
+		Example: `  # List the sessions older than 30 days without deleting them
+  docker-agent sessions prune --older-than 30d --dry-run
+
+  # Keep only the 100 most recent sessions
+  docker-agent sessions prune --keep 100`,
+		GroupID: "advanced",
+	}
+
+	cmd.AddCommand(newSessionsPruneCmd())
+
+	return cmd
+}
+
+func newSessionsPruneCmd() *cobra.Command {
+	var flags sessionsPruneFlags
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the old sessions of the session database",
+		Long: `Delete the sessions older than --older-than, beyond the --keep most recent
+ones, or the oldest ones until the database is smaller than --max-size. Without
+any of these flags, the session_retention setting of the user config is used.
+Starred sessions are never deleted.`,
+		Args: cobra.NoArgs,
+		RunE: flags.runSessionsPruneCommand,
+	}
+
+	cmd.Flags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
+	cmd.Flags().StringVar(&flags.olderThan, "older-than", "", "Delete the sessions older than this (e.g. 30d, 12h)")
+	cmd.Flags().IntVar(&flags.keep, "keep", 0, "Keep at most this number of sessions")
+	cmd.Flags().StringVar(&flags.maxSize, "max-size", "", "Delete the oldest sessions until the database is smaller than this (e.g. 500MB)")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "List the sessions that would be deleted without deleting them")
+
+	return cmd
+}
+
+func (f *sessionsPruneFlags) runSessionsPruneCommand(cmd *cobra.Command, _ []string) error {
+	telemetry.TrackCommand("sessions", []string{"prune"})
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	policy, err := f.policy()
+	if err != nil {
+		return err
+	}
+	if policy.IsZero() {
+		return errors.New("nothing to prune: set --older-than, --keep or --max-size, or the session_retention setting")
+	}
+
+	sessionDB, err := expandTilde(f.sessionDB)
+	if err != nil {
+		return err
+	}
+	store, err := session.NewSQLiteSessionStore(sessionDB)
+	if err != nil {
+		return fmt.Errorf("opening session store: %w", err)
+	}
+	defer store.Close()
+
+	sqliteStore, ok := store.(*session.SQLiteSessionStore)
+	if !ok {
+		return errors.New("the session store can't be pruned")
+	}
+
+	pruned, err := sqliteStore.Prune(ctx, policy, f.dryRun)
+	if err != nil {
+		return fmt.Errorf("pruning sessions: %w", err)
+	}
+	if len(pruned) == 0 {
+		out.Println("No sessions to prune")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCREATED\tTITLE")
+	for _, s := range pruned {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.ID, s.CreatedAt.Local().Format(time.DateTime), s.Title)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if f.dryRun {
+		out.Printf("%d sessions would be deleted\n", len(pruned))
+		return nil
+	}
+
+	// Give the space of the deleted sessions back to the file system.
+	if err := sqliteStore.Vacuum(ctx); err != nil {
+		return fmt.Errorf("compacting the session database: %w", err)
+	}
+	out.Printf("Deleted %d sessions\n", len(pruned))
+	return nil
+}
+
+// policy returns the retention policy of the flags, or of the
+// session_retention setting when no flag is set.
+func (f *sessionsPruneFlags) policy() (session.RetentionPolicy, error) {
+	if f.olderThan == "" && f.keep <= 0 && f.maxSize == "" {
+		return sessionRetentionPolicy(userconfig.Get().SessionRetention)
+	}
+
+	policy := session.RetentionPolicy{MaxCount: f.keep}
+	if f.olderThan != "" {
+		maxAge, err := session.ParseRetentionAge(f.olderThan)
+		if err != nil {
+			return policy, fmt.Errorf("--older-than: %w", err)
+		}
+		policy.MaxAge = maxAge
+	}
+	if f.maxSize != "" {
+		maxSize, err := session.ParseRetentionSize(f.maxSize)
+		if err != nil {
+			return policy, fmt.Errorf("--max-size: %w", err)
+		}
+		policy.MaxSize = maxSize
+	}
+	return policy, nil
+}
@@ -1,16 +1,116 @@
 package root
 
-import "github.com/spf13/cobra"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/app/export"
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/telemetry"
+	"github.com/docker/docker-agent/pkg/userconfig"
+)
+
+type shareFlags struct {
+	sessionDB string
+	output    string
+	uploadURL string
+}
 
 func newShareCmd() *cobra.Command {
+	var flags shareFlags
+
 	cmd := &cobra.Command{
-		Use:     "share",
-		Short:   "Share agents",
-		GroupID: "core",
+		Use:   "share [<session-id>]",
+		Short: "Share agents and sessions",
+		Long: `Export a read-only HTML transcript of a session, with highlighted code and
+collapsed tool calls, to share it in pull requests and incident docs. The
+transcript is written to a file, or uploaded with --upload-url or the
+share_upload_url setting. "-1", after "--", is the last session.
+
+The push and pull subcommands share agents through OCI registries.`,
+		Example: `  # Write the transcript of the last session to a file
+  docker-agent share --output transcript.html -- -1
+
+  # Upload it, with an HTTP PUT, to a bucket served over HTTPS
+  docker-agent share 1f0c6c2e-6c7a-4a4e-9d0b-2f1f5d3e8a10 --upload-url https://transcripts.example.com/`,
+		GroupID:           "core",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionID,
+		RunE:              flags.runShareCommand,
 	}
 
+	cmd.Flags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
+	cmd.Flags().StringVar(&flags.output, "output", "", "File to write the transcript to (defaults to a name based on the title of the session)")
+	cmd.Flags().StringVar(&flags.uploadURL, "upload-url", "", "URL to upload the transcript to with an HTTP PUT, or directory URL ending with a slash")
+
 	cmd.AddCommand(newPushCmd())
 	cmd.AddCommand(newPullCmd())
 
 	return cmd
 }
+
+func (f *shareFlags) runShareCommand(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+
+	telemetry.TrackCommand("share", []string{"session"})
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	sessionDB, err := expandTilde(f.sessionDB)
+	if err != nil {
+		return err
+	}
+	storeOpts, err := sessionStoreOpts()
+	if err != nil {
+		return err
+	}
+	store, err := session.NewSQLiteSessionStore(sessionDB, storeOpts...)
+	if err != nil {
+		return fmt.Errorf("opening session store: %w", err)
+	}
+	defer store.Close()
+
+	sessionID, err := session.ResolveSessionID(ctx, store, args[0])
+	if err != nil {
+		return err
+	}
+	sess, err := store.GetSession(ctx, sessionID)
+	if errors.Is(err, session.ErrNotFound) {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	uploadURL := cmp.Or(f.uploadURL, userconfig.Get().ShareUploadURL)
+	if uploadURL == "" || f.output != "" {
+		path, err := export.SessionToFile(sess, "", f.output)
+		if err != nil {
+			return err
+		}
+		out.Printf("Transcript written to %s\n", path)
+	}
+	if uploadURL == "" {
+		return nil
+	}
+
+	page, err := export.SessionHTML(sess, "")
+	if err != nil {
+		return err
+	}
+	url, err := export.Upload(ctx, uploadURL, sess.ID+".html", page)
+	if err != nil {
+		return fmt.Errorf("uploading the transcript: %w", err)
+	}
+	out.Printf("Transcript uploaded to %s\n", url)
+	return nil
+}
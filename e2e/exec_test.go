@@ -128,5 +128,11 @@ func TestExec_Mistral_ToolCall(t *testing.T) {
 func TestExec_ToolCallsNeedAcceptance(t *testing.T) {
 	out := runCLI(t, "run", "--exec", "testdata/file_writer.yaml", "Create a hello.txt file with \"Hello, World!\" content. Try only once. On error, exit without further message.")
 
-	require.Contains(t, out, `Can I run this tool? ([y]es/[a]ll/[n]o)`)
+	require.Contains(t, out, `Can I run this tool? ([y]es/[a]ll/always [t]his tool/[n]o)`)
+}
+
+func TestExec_Mock_ToolCall(t *testing.T) {
+	out := runCLI(t, "run", "--exec", "testdata/mock_tools.yaml", "How many files in testdata/working_dir? Only output the number.")
+
+	require.Equal(t, "\n--- Agent: root ---\n\nCalling list_directory(path: \"testdata/working_dir\")\n\nlist_directory response → \"FILE README.me\\n\"\n1", out)
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider/openai"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx); err != nil {
+		log.Println(err)
+	}
+}
+
+type AddArgs struct {
+	A int `json:"a" jsonschema:"The first number"`
+	B int `json:"b" jsonschema:"The second number"`
+}
+
+type MultiplyArgs struct {
+	A int `json:"a" jsonschema:"The first number"`
+	B int `json:"b" jsonschema:"The second number"`
+}
+
+type Result struct {
+	Value int `json:"value"`
+}
+
+func add(_ context.Context, args AddArgs) (Result, error) {
+	fmt.Println("Adding numbers", args.A, args.B)
+	return Result{Value: args.A + args.B}, nil
+}
+
+func multiply(_ context.Context, args MultiplyArgs) (Result, error) {
+	fmt.Println("Multiplying numbers", args.A, args.B)
+	return Result{Value: args.A * args.B}, nil
+}
+
+func run(ctx context.Context) error {
+	llm, err := openai.NewClient(
+		ctx,
+		&latest.ModelConfig{
+			Provider: "openai",
+			Model:    "gpt-4o",
+		},
+		environment.NewDefaultProvider(),
+	)
+	if err != nil {
+		return err
+	}
+
+	mathTools := tools.NewFuncToolSet("math",
+		tools.NewFunc("add", "Add two numbers", add),
+		tools.NewFunc("multiply", "Multiply two numbers", multiply),
+	).
+		WithInstructions("Always use the math tools for arithmetic.").
+		OnStart(func(context.Context) error {
+			fmt.Println("Starting the math tools")
+			return nil
+		}).
+		OnStop(func(context.Context) error {
+			fmt.Println("Stopping the math tools")
+			return nil
+		})
+
+	calculator := agent.New(
+		"root",
+		"You are a calculator.",
+		agent.WithModel(llm),
+		agent.WithToolSets(mathTools),
+	)
+
+	calculatorTeam := team.New(team.WithAgents(calculator))
+	defer func() {
+		_ = calculatorTeam.StopToolSets(context.WithoutCancel(ctx))
+	}()
+
+	rt, err := runtime.New(calculatorTeam)
+	if err != nil {
+		return err
+	}
+
+	sess := session.New(session.WithUserMessage("What is (1 + 2) * 3?"), session.WithToolsApproved(true))
+
+	messages, err := rt.Run(ctx, sess)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(messages[len(messages)-1].Message.Content)
+	return nil
+}
@@ -0,0 +1,8 @@
+#!/bin/sh
+# Usage: check.sh <message-file>
+# Fails if the subject line of the commit message is too long.
+subject=$(head -n 1 "$1")
+if [ "${#subject}" -gt 72 ]; then
+  echo "subject line is ${#subject} characters long (max 72)" >&2
+  exit 1
+fi
@@ -0,0 +1,71 @@
+//go:build wasip1
+
+// Command wordcount is a wasm tool counting the lines, words and characters
+// of a text. Build it with:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o examples/wasm/wordcount.wasm ./examples/wasm/wordcount
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+const tools = `[{
+	"name": "count_words",
+	"description": "Counts the lines, words and characters of a text",
+	"parameters": {"type": "object", "properties": {"text": {"type": "string"}}, "required": ["text"]}
+}]`
+
+// buffers keeps the buffers given to the host alive. Every call gets a fresh
+// instance of the module, so they're never freed.
+var buffers [][]byte
+
+//go:wasmexport cagent_alloc
+func alloc(size int32) unsafe.Pointer {
+	buf := make([]byte, max(size, 1))
+	buffers = append(buffers, buf)
+	return unsafe.Pointer(&buf[0])
+}
+
+//go:wasmexport cagent_tools
+func listTools() int64 {
+	return pack([]byte(tools))
+}
+
+//go:wasmexport cagent_call
+func call(namePtr unsafe.Pointer, nameLen int32, argsPtr unsafe.Pointer, argsLen int32) int64 {
+	var args struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(bytesAt(argsPtr, argsLen), &args); err != nil {
+		return pack([]byte("invalid arguments: " + err.Error()))
+	}
+
+	output, _ := json.Marshal(map[string]int{
+		"lines":      strings.Count(args.Text, "\n") + 1,
+		"words":      len(strings.Fields(args.Text)),
+		"characters": utf8.RuneCountInString(args.Text),
+	})
+	return pack(output)
+}
+
+func bytesAt(p unsafe.Pointer, n int32) []byte {
+	if n == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(p), n)
+}
+
+// pack returns the pointer and the length of buf in an i64.
+func pack(buf []byte) int64 {
+	if len(buf) == 0 {
+		return 0
+	}
+	buffers = append(buffers, buf)
+	return int64(uintptr(unsafe.Pointer(&buf[0])))<<32 | int64(len(buf))
+}
+
+func main() {}
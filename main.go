@@ -10,12 +10,16 @@ import (
 	"github.com/docker/cli/cli"
 
 	"github.com/docker/docker-agent/cmd/root"
+	"github.com/docker/docker-agent/pkg/childprocess"
 )
 
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 
-	if err := root.Execute(ctx, os.Stdin, os.Stdout, os.Stderr, os.Args[1:]...); err != nil {
+	err := root.Execute(ctx, os.Stdin, os.Stdout, os.Stderr, os.Args[1:]...)
+	// Don't leave the processes started by tools behind.
+	childprocess.KillAll()
+	if err != nil {
 		cancel()
 		if statusErr, ok := errors.AsType[cli.StatusError](err); ok {
 			os.Exit(statusErr.StatusCode)
@@ -92,7 +92,7 @@ func runDockerAgent(ctx agent.InvocationContext, t *team.Team, agentName string,
 
 			case *runtime.ErrorEvent:
 				// Yield error and stop
-				yield(nil, fmt.Errorf("%s", e.Error))
+				yield(nil, fmt.Errorf("%s", e.Text()))
 				return
 
 			case *runtime.StreamStoppedEvent:
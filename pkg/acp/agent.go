@@ -440,7 +440,7 @@ func (a *Agent) runAgent(ctx context.Context, acpSess *Session) error {
 		case *runtime.ErrorEvent:
 			if err := a.conn.SessionUpdate(ctx, acp.SessionNotification{
 				SessionId: acp.SessionId(acpSess.id),
-				Update:    acp.UpdateAgentMessageText(fmt.Sprintf("\n\nError: %s\n", e.Error)),
+				Update:    acp.UpdateAgentMessageText(fmt.Sprintf("\n\nError: %s\n", e.Text())),
 			}); err != nil {
 				return err
 			}
@@ -449,6 +449,11 @@ func (a *Agent) runAgent(ctx context.Context, acpSess *Session) error {
 			if err := a.handleMaxIterationsReached(ctx, acpSess, e); err != nil {
 				return err
 			}
+
+		case *runtime.ToolResultConfirmationEvent:
+			if err := a.handleToolResultConfirmation(ctx, acpSess, e); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -545,6 +550,49 @@ func (a *Agent) handleMaxIterationsReached(ctx context.Context, acpSess *Session
 	return nil
 }
 
+// handleToolResultConfirmation asks whether a tool result flagged by content
+// filters can be sent to the model, showing the flagged text.
+func (a *Agent) handleToolResultConfirmation(ctx context.Context, acpSess *Session, e *runtime.ToolResultConfirmationEvent) error {
+	var content []acp.ToolCallContent
+	for _, match := range e.Matches {
+		content = append(content, acp.ToolContent(acp.TextBlock(match)))
+	}
+
+	permResp, err := a.conn.RequestPermission(ctx, acp.RequestPermissionRequest{
+		SessionId: acp.SessionId(acpSess.id),
+		ToolCall: acp.RequestPermissionToolCall{
+			ToolCallId: acp.ToolCallId(e.ToolCall.ID),
+			Title:      new(fmt.Sprintf("The result of %s looks like a prompt injection (%s)", e.ToolCall.Function.Name, strings.Join(e.Filters, ", "))),
+			Content:    content,
+			Status:     acp.Ptr(acp.ToolCallStatusPending),
+		},
+		Options: []acp.PermissionOption{
+			{
+				Kind:     acp.PermissionOptionKindAllowOnce,
+				Name:     "Send it to the model",
+				OptionId: "send",
+			},
+			{
+				Kind:     acp.PermissionOptionKindRejectOnce,
+				Name:     "Withhold it",
+				OptionId: "withhold",
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if permResp.Outcome.Cancelled != nil || permResp.Outcome.Selected == nil ||
+		string(permResp.Outcome.Selected.OptionId) == "withhold" {
+		acpSess.rt.Resume(ctx, runtime.ResumeRequest{Type: runtime.ResumeTypeReject})
+	} else {
+		acpSess.rt.Resume(ctx, runtime.ResumeRequest{Type: runtime.ResumeTypeApprove})
+	}
+
+	return nil
+}
+
 // buildToolCallStart creates a tool call start update
 func buildToolCallStart(toolCall tools.ToolCall, tool tools.Tool) acp.SessionUpdate {
 	kind := determineToolKind(toolCall.Function.Name, tool)
@@ -12,7 +12,7 @@ import (
 	"github.com/docker/docker-agent/pkg/session"
 )
 
-func Run(ctx context.Context, agentFilename string, stdin io.Reader, stdout io.Writer, runConfig *config.RuntimeConfig, sessionDB string) error {
+func Run(ctx context.Context, agentFilename string, stdin io.Reader, stdout io.Writer, runConfig *config.RuntimeConfig, sessionDB string, storeOpts ...session.SQLiteOpt) error {
 	slog.Debug("Starting ACP server", "agent", agentFilename, "session_db", sessionDB)
 
 	agentSource, err := config.Resolve(agentFilename, nil)
@@ -21,7 +21,7 @@ func Run(ctx context.Context, agentFilename string, stdin io.Reader, stdout io.W
 	}
 
 	// Create SQLite session store for persistent sessions
-	sessStore, err := session.NewSQLiteSessionStore(sessionDB)
+	sessStore, err := session.NewSQLiteSessionStore(sessionDB, storeOpts...)
 	if err != nil {
 		return fmt.Errorf("creating session store: %w", err)
 	}
@@ -2,14 +2,20 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/rand"
+	"slices"
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/contentfilter"
+	"github.com/docker/docker-agent/pkg/guardrails"
 	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/tools"
 )
@@ -25,6 +31,8 @@ type Agent struct {
 	fallbackModels          []provider.Provider                 // Fallback models to try if primary fails
 	fallbackRetries         int                                 // Number of retries per fallback model with exponential backoff
 	fallbackCooldown        time.Duration                       // Duration to stick with fallback after non-retryable error
+	fallbackBackoff         time.Duration                       // Delay before the first retry of a model
+	fallbackMaxBackoff      time.Duration                       // Maximum delay between two retries
 	modelOverrides          atomic.Pointer[[]provider.Provider] // Optional model override(s) set at runtime (supports alloy)
 	subAgents               []*Agent
 	handoffs                []*Agent
@@ -33,12 +41,24 @@ type Agent struct {
 	addEnvironmentInfo      bool
 	addDescriptionParameter bool
 	maxIterations           int
+	streamIdleTimeout       time.Duration
+	toolTimeout             time.Duration
+	maxToolOutputTokens     int
+	toolOutputSummaryModel  provider.Provider
+	toolSearchThreshold     int
+	toolSearchMaxResults    int
 	numHistoryItems         int
 	addPromptFiles          []string
 	tools                   []tools.Tool
 	commands                types.Commands
 	pendingWarnings         []string
+	reportedConflicts       map[string]bool
 	hooks                   *latest.HooksConfig
+	contexts                []latest.ContextConfig
+	continuity              bool
+	taskOutput              *latest.StructuredOutput
+	guardrails              []*guardrails.Guardrail
+	contentFilters          []*contentfilter.Filter
 	thinkingConfigured      bool // true if thinking_budget was explicitly set in config
 }
 
@@ -77,6 +97,42 @@ func (a *Agent) MaxIterations() int {
 	return a.maxIterations
 }
 
+// StreamIdleTimeout returns how long a model stream can go without producing
+// any data before the request is cancelled. Returns 0 if not configured.
+func (a *Agent) StreamIdleTimeout() time.Duration {
+	return a.streamIdleTimeout
+}
+
+// ToolTimeout returns how long a tool call can run before it's cancelled.
+// Returns 0 if not configured.
+func (a *Agent) ToolTimeout() time.Duration {
+	return a.toolTimeout
+}
+
+// MaxToolOutputTokens returns the maximum size of tool outputs added to the
+// conversation, in tokens. Returns 0 if not configured.
+func (a *Agent) MaxToolOutputTokens() int {
+	return a.maxToolOutputTokens
+}
+
+// ToolOutputSummaryModel returns the model summarizing the tool outputs
+// longer than MaxToolOutputTokens, or nil if they are truncated.
+func (a *Agent) ToolOutputSummaryModel() provider.Provider {
+	return a.toolOutputSummaryModel
+}
+
+// ToolSearchThreshold returns the number of tools above which tools are
+// hidden until the agent searches for them. Returns 0 if not configured.
+func (a *Agent) ToolSearchThreshold() int {
+	return a.toolSearchThreshold
+}
+
+// ToolSearchMaxResults returns the maximum number of tools a search makes
+// available, or 0 for the default.
+func (a *Agent) ToolSearchMaxResults() int {
+	return a.toolSearchMaxResults
+}
+
 func (a *Agent) NumHistoryItems() int {
 	return a.numHistoryItems
 }
@@ -187,6 +243,12 @@ func (a *Agent) FallbackCooldown() time.Duration {
 	return a.fallbackCooldown
 }
 
+// FallbackBackoff returns the delay before the first retry of a model and the
+// maximum delay between two retries. Zero values mean the defaults apply.
+func (a *Agent) FallbackBackoff() (initial, maxBackoff time.Duration) {
+	return a.fallbackBackoff, a.fallbackMaxBackoff
+}
+
 // Commands returns the named commands configured for this agent.
 func (a *Agent) Commands() types.Commands {
 	return a.commands
@@ -197,11 +259,41 @@ func (a *Agent) Hooks() *latest.HooksConfig {
 	return a.hooks
 }
 
+// Contexts returns the dynamic context sources injected into the system prompt.
+func (a *Agent) Contexts() []latest.ContextConfig {
+	return a.contexts
+}
+
+// Continuity returns whether a summary of the previous sessions is carried
+// into new sessions.
+func (a *Agent) Continuity() bool {
+	return a.continuity
+}
+
+// TaskOutput returns the JSON Schema the results of the tasks the agent
+// delegates must follow, or nil.
+func (a *Agent) TaskOutput() *latest.StructuredOutput {
+	return a.taskOutput
+}
+
+// Guardrails returns the rules the final answers of the agent must follow.
+func (a *Agent) Guardrails() []*guardrails.Guardrail {
+	return a.guardrails
+}
+
+// ContentFilters returns the filters scanning the results of the agent's
+// tool calls for prompt injections.
+func (a *Agent) ContentFilters() []*contentfilter.Filter {
+	return a.contentFilters
+}
+
 // Tools returns the tools available to this agent
 func (a *Agent) Tools(ctx context.Context) ([]tools.Tool, error) {
-	a.ensureToolSetsAreStarted(ctx)
+	// Failures are recorded as warnings and the toolsets skipped below.
+	_ = a.StartToolSets(ctx, nil)
 
 	var agentTools []tools.Tool
+	names := make(map[string]bool)
 	for _, toolSet := range a.toolsets {
 		if !toolSet.IsStarted() {
 			// Toolset failed to start; skip it
@@ -214,7 +306,7 @@ func (a *Agent) Tools(ctx context.Context) ([]tools.Tool, error) {
 			a.addToolWarning(fmt.Sprintf("%s list failed: %v", desc, err))
 			continue
 		}
-		agentTools = append(agentTools, ta...)
+		agentTools = append(agentTools, a.resolveToolNameConflicts(toolSet, ta, names)...)
 	}
 
 	agentTools = append(agentTools, a.tools...)
@@ -236,20 +328,106 @@ func (a *Agent) ToolSets() []tools.ToolSet {
 	return toolSets
 }
 
-func (a *Agent) ensureToolSetsAreStarted(ctx context.Context) {
-	for _, toolSet := range a.toolsets {
-		if err := toolSet.Start(ctx); err != nil {
-			desc := tools.DescribeToolSet(toolSet)
-			slog.Warn("Toolset start failed; skipping", "agent", a.Name(), "toolset", desc, "error", err)
-			a.addToolWarning(fmt.Sprintf("%s start failed: %v", desc, err))
+// maxConcurrentToolSetStarts bounds the number of toolsets started at the
+// same time, so that big configs don't spawn all their servers at once.
+const maxConcurrentToolSetStarts = 8
+
+// ToolSetStatus is the startup state of a toolset.
+type ToolSetStatus string
+
+const (
+	ToolSetStarting ToolSetStatus = "starting"
+	ToolSetReady    ToolSetStatus = "ready"
+	ToolSetFailed   ToolSetStatus = "failed"
+)
+
+// StartToolSets starts the toolsets of the agent that aren't started yet,
+// concurrently. When report isn't nil, it's called, possibly from several
+// goroutines, as each toolset that needs starting is starting and then ready
+// or failed. Toolsets that fail to start are skipped and reported as
+// warnings; their errors are joined in the returned error.
+func (a *Agent) StartToolSets(ctx context.Context, report func(toolSet string, status ToolSetStatus, err error)) error {
+	if report == nil {
+		report = func(string, ToolSetStatus, error) {}
+	}
+
+	errs := make([]error, len(a.toolsets))
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentToolSetStarts)
+	for i, toolSet := range a.toolsets {
+		if toolSet.IsStarted() {
 			continue
 		}
+		// Toolsets without a lifecycle start instantly, there's nothing to report.
+		_, startable := tools.As[tools.Startable](toolSet)
+		desc := tools.DescribeToolSet(toolSet)
+
+		g.Go(func() error {
+			if startable {
+				report(desc, ToolSetStarting, nil)
+			}
+			if err := toolSet.Start(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s start failed: %w", desc, err)
+				report(desc, ToolSetFailed, err)
+				return nil
+			}
+			if startable {
+				report(desc, ToolSetReady, nil)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			slog.Warn("Toolset start failed; skipping", "agent", a.Name(), "error", err)
+			a.addToolWarning(err.Error())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveToolNameConflicts renames the tools of a toolset whose names are
+// already used by the tools of the toolsets before it, by prefixing them
+// with the namespace of the toolset. Tools that still conflict are left
+// out. Each conflict is reported once.
+func (a *Agent) resolveToolNameConflicts(toolSet tools.ToolSet, toolsList []tools.Tool, names map[string]bool) []tools.Tool {
+	var resolved []tools.Tool
+	for _, tool := range toolsList {
+		if !names[tool.Name] {
+			names[tool.Name] = true
+			resolved = append(resolved, tool)
+			continue
+		}
+
+		desc := tools.DescribeToolSet(toolSet)
+		var warning string
+		if ns, ok := tools.As[tools.Namespacer](toolSet); ok && !names[ns.Namespace()+"_"+tool.Name] {
+			renamed := tools.Rename(tool, ns.Namespace()+"_"+tool.Name)
+			names[renamed.Name] = true
+			resolved = append(resolved, renamed)
+			warning = fmt.Sprintf("tool %s of %s is also defined by another toolset; renamed to %s", tool.Name, desc, renamed.Name)
+		} else {
+			warning = fmt.Sprintf("tool %s of %s is also defined by another toolset; left out, set an alias for it", tool.Name, desc)
+		}
+
+		if !a.reportedConflicts[warning] {
+			if a.reportedConflicts == nil {
+				a.reportedConflicts = make(map[string]bool)
+			}
+			a.reportedConflicts[warning] = true
+			slog.Warn("Tool name conflict", "agent", a.Name(), "tool", tool.Name, "toolset", desc)
+			a.addToolWarning(warning)
+		}
 	}
+	return resolved
 }
 
 // addToolWarning records a warning generated while loading or starting toolsets.
 func (a *Agent) addToolWarning(msg string) {
-	if msg == "" {
+	// Failed toolsets are retried on each start, report them once until drained.
+	if msg == "" || slices.Contains(a.pendingWarnings, msg) {
 		return
 	}
 	a.pendingWarnings = append(a.pendingWarnings, msg)
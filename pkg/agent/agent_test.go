@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -93,6 +94,95 @@ func TestAgentTools(t *testing.T) {
 	}
 }
 
+type namespacedStubToolSet struct {
+	stubToolSet
+	namespace string
+}
+
+func (s *namespacedStubToolSet) Namespace() string { return s.namespace }
+
+func TestAgentTools_NameConflicts(t *testing.T) {
+	var calledWith string
+	handler := func(_ context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+		calledWith = toolCall.Function.Name
+		return tools.ResultSuccess("ok"), nil
+	}
+
+	github := newStubToolSet(nil, []tools.Tool{{Name: "search", Handler: handler}, {Name: "read"}}, nil)
+	gitlab := &namespacedStubToolSet{stubToolSet: stubToolSet{tools: []tools.Tool{{Name: "search", Handler: handler}, {Name: "write"}}}, namespace: "gitlab"}
+	other := newStubToolSet(nil, []tools.Tool{{Name: "search"}, {Name: "list"}}, nil)
+
+	a := New("root", "test", WithToolSets(github, gitlab, other))
+	got, err := a.Tools(t.Context())
+	require.NoError(t, err)
+
+	var names []string
+	for _, tool := range got {
+		names = append(names, tool.Name)
+	}
+	assert.Equal(t, []string{"search", "read", "gitlab_search", "write", "list"}, names)
+
+	// The renamed tool is called with its original name
+	_, err = got[2].Handler(t.Context(), tools.ToolCall{Function: tools.FunctionCall{Name: "gitlab_search"}})
+	require.NoError(t, err)
+	assert.Equal(t, "search", calledWith)
+
+	warnings := a.DrainWarnings()
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "renamed to gitlab_search")
+	assert.Contains(t, warnings[1], "left out")
+
+	// Conflicts are only reported once
+	_, err = a.Tools(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, a.DrainWarnings())
+}
+
+// barrierToolSet only finishes starting once every toolset sharing its
+// barrier is starting, so that sequential starts would never finish.
+type barrierToolSet struct {
+	stubToolSet
+	barrier *sync.WaitGroup
+}
+
+func (s *barrierToolSet) Start(ctx context.Context) error {
+	s.barrier.Done()
+	s.barrier.Wait()
+	return s.startErr
+}
+
+func TestStartToolSets(t *testing.T) {
+	var barrier sync.WaitGroup
+	barrier.Add(3)
+	a := New("root", "test", WithToolSets(
+		&barrierToolSet{barrier: &barrier},
+		&barrierToolSet{barrier: &barrier, stubToolSet: stubToolSet{startErr: errors.New("boom")}},
+		&barrierToolSet{barrier: &barrier},
+	))
+
+	var (
+		mu       sync.Mutex
+		statuses = map[ToolSetStatus]int{}
+	)
+	err := a.StartToolSets(t.Context(), func(_ string, status ToolSetStatus, _ error) {
+		mu.Lock()
+		defer mu.Unlock()
+		statuses[status]++
+	})
+
+	require.ErrorContains(t, err, "boom")
+	assert.Equal(t, map[ToolSetStatus]int{ToolSetStarting: 3, ToolSetReady: 2, ToolSetFailed: 1}, statuses)
+	assert.Len(t, a.DrainWarnings(), 1)
+
+	// Started toolsets aren't started, nor reported, again.
+	statuses = map[ToolSetStatus]int{}
+	barrier.Add(1)
+	require.ErrorContains(t, a.StartToolSets(t.Context(), func(_ string, status ToolSetStatus, _ error) {
+		statuses[status]++
+	}), "boom")
+	assert.Equal(t, map[ToolSetStatus]int{ToolSetStarting: 1, ToolSetFailed: 1}, statuses)
+}
+
 // mockProvider implements provider.Provider for testing
 type mockProvider struct {
 	id string
@@ -5,6 +5,8 @@ import (
 
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/contentfilter"
+	"github.com/docker/docker-agent/pkg/guardrails"
 	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/tools"
 )
@@ -82,6 +84,15 @@ func WithFallbackCooldown(cooldown time.Duration) Opt {
 	}
 }
 
+// WithFallbackBackoff sets the delay before the first retry of a model, which
+// doubles on each retry, and the maximum delay between two retries.
+func WithFallbackBackoff(initial, maxBackoff time.Duration) Opt {
+	return func(a *Agent) {
+		a.fallbackBackoff = initial
+		a.fallbackMaxBackoff = maxBackoff
+	}
+}
+
 func WithSubAgents(subAgents ...*Agent) Opt {
 	return func(a *Agent) {
 		a.subAgents = subAgents
@@ -127,6 +138,42 @@ func WithMaxIterations(maxIterations int) Opt {
 	}
 }
 
+// WithStreamIdleTimeout sets how long a model stream can go without producing
+// any data before the request is cancelled and retried.
+func WithStreamIdleTimeout(timeout time.Duration) Opt {
+	return func(a *Agent) {
+		a.streamIdleTimeout = timeout
+	}
+}
+
+// WithToolTimeout sets how long a tool call can run before it's cancelled and
+// reported to the model as timed out.
+func WithToolTimeout(timeout time.Duration) Opt {
+	return func(a *Agent) {
+		a.toolTimeout = timeout
+	}
+}
+
+// WithToolOutputLimit caps the size of the tool outputs added to the
+// conversation, in tokens. Longer outputs are summarized with summaryModel,
+// if not nil, or truncated.
+func WithToolOutputLimit(maxTokens int, summaryModel provider.Provider) Opt {
+	return func(a *Agent) {
+		a.maxToolOutputTokens = maxTokens
+		a.toolOutputSummaryModel = summaryModel
+	}
+}
+
+// WithToolSearch hides the tools of the agent when it has more than
+// threshold tools. The agent finds them with search_tools, which makes at
+// most maxResults tools available per search.
+func WithToolSearch(threshold, maxResults int) Opt {
+	return func(a *Agent) {
+		a.toolSearchThreshold = threshold
+		a.toolSearchMaxResults = maxResults
+	}
+}
+
 func WithNumHistoryItems(numHistoryItems int) Opt {
 	return func(a *Agent) {
 		a.numHistoryItems = numHistoryItems
@@ -153,6 +200,45 @@ func WithHooks(hooks *latest.HooksConfig) Opt {
 	}
 }
 
+// WithContexts sets the commands and files whose output is injected into
+// the system prompt before each model call.
+func WithContexts(contexts []latest.ContextConfig) Opt {
+	return func(a *Agent) {
+		a.contexts = contexts
+	}
+}
+
+// WithContinuity sets whether a summary of the previous sessions is carried
+// into new sessions.
+func WithContinuity(continuity bool) Opt {
+	return func(a *Agent) {
+		a.continuity = continuity
+	}
+}
+
+// WithTaskOutput sets the JSON Schema the results of the tasks the agent
+// delegates must follow.
+func WithTaskOutput(taskOutput *latest.StructuredOutput) Opt {
+	return func(a *Agent) {
+		a.taskOutput = taskOutput
+	}
+}
+
+// WithGuardrails sets the rules the final answers of the agent must follow.
+func WithGuardrails(g []*guardrails.Guardrail) Opt {
+	return func(a *Agent) {
+		a.guardrails = g
+	}
+}
+
+// WithContentFilters sets the filters scanning the results of the agent's
+// tool calls for prompt injections.
+func WithContentFilters(filters []*contentfilter.Filter) Opt {
+	return func(a *Agent) {
+		a.contentFilters = filters
+	}
+}
+
 // WithThinkingConfigured sets whether thinking_budget was explicitly configured in the agent's YAML.
 // When true, the session will initialize with thinking enabled.
 func WithThinkingConfigured(configured bool) Opt {
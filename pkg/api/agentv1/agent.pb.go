@@ -0,0 +1,2762 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: pkg/api/agentv1/agent.proto
+
+// The gRPC API of the docker agent server: the same API as the HTTP one, with
+// typed messages and streams instead of SSE. The values that have no fixed
+// shape, like agent configurations, session messages, JSON schemas and the
+// less common events, are the JSON objects of the HTTP API.
+
+package agentv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Empty is the request or the response of the methods that have none.
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{0}
+}
+
+// AgentRequest identifies an agent, and optionally one of its sub-agents.
+type AgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentName     string                 `protobuf:"bytes,2,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentRequest) Reset() {
+	*x = AgentRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentRequest) ProtoMessage() {}
+
+func (x *AgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentRequest.ProtoReflect.Descriptor instead.
+func (*AgentRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AgentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AgentRequest) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+type Agent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Multi         bool                   `protobuf:"varint,3,opt,name=multi,proto3" json:"multi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Agent) Reset() {
+	*x = Agent{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Agent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Agent) ProtoMessage() {}
+
+func (x *Agent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
+func (*Agent) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Agent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Agent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Agent) GetMulti() bool {
+	if x != nil {
+		return x.Multi
+	}
+	return false
+}
+
+type ListAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*Agent               `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsResponse) ProtoMessage() {}
+
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListAgentsResponse) GetAgents() []*Agent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// AgentConfig is the configuration of an agent.
+type AgentConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The configuration, as a JSON object.
+	Json          []byte `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentConfig) Reset() {
+	*x = AgentConfig{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentConfig) ProtoMessage() {}
+
+func (x *AgentConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentConfig.ProtoReflect.Descriptor instead.
+func (*AgentConfig) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AgentConfig) GetJson() []byte {
+	if x != nil {
+		return x.Json
+	}
+	return nil
+}
+
+type AgentToolCountResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AvailableTools int64                  `protobuf:"varint,1,opt,name=available_tools,json=availableTools,proto3" json:"available_tools,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AgentToolCountResponse) Reset() {
+	*x = AgentToolCountResponse{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentToolCountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentToolCountResponse) ProtoMessage() {}
+
+func (x *AgentToolCountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentToolCountResponse.ProtoReflect.Descriptor instead.
+func (*AgentToolCountResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AgentToolCountResponse) GetAvailableTools() int64 {
+	if x != nil {
+		return x.AvailableTools
+	}
+	return 0
+}
+
+// SessionSummary is the metadata of a session.
+type SessionSummary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// RFC 3339 creation time.
+	CreatedAt     string `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	NumMessages   int64  `protobuf:"varint,4,opt,name=num_messages,json=numMessages,proto3" json:"num_messages,omitempty"`
+	InputTokens   int64  `protobuf:"varint,5,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens  int64  `protobuf:"varint,6,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	WorkingDir    string `protobuf:"bytes,7,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionSummary) Reset() {
+	*x = SessionSummary{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionSummary) ProtoMessage() {}
+
+func (x *SessionSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionSummary.ProtoReflect.Descriptor instead.
+func (*SessionSummary) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SessionSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SessionSummary) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SessionSummary) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *SessionSummary) GetNumMessages() int64 {
+	if x != nil {
+		return x.NumMessages
+	}
+	return 0
+}
+
+func (x *SessionSummary) GetInputTokens() int64 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *SessionSummary) GetOutputTokens() int64 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *SessionSummary) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*SessionSummary      `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*SessionSummary {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// SessionRequest identifies a session.
+type SessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionRequest) Reset() {
+	*x = SessionRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRequest) ProtoMessage() {}
+
+func (x *SessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRequest.ProtoReflect.Descriptor instead.
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type Session struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// RFC 3339 creation time.
+	CreatedAt     string   `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ToolsApproved bool     `protobuf:"varint,4,opt,name=tools_approved,json=toolsApproved,proto3" json:"tools_approved,omitempty"`
+	Thinking      bool     `protobuf:"varint,5,opt,name=thinking,proto3" json:"thinking,omitempty"`
+	InputTokens   int64    `protobuf:"varint,6,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens  int64    `protobuf:"varint,7,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	WorkingDir    string   `protobuf:"bytes,8,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+	WorkingDirs   []string `protobuf:"bytes,9,rep,name=working_dirs,json=workingDirs,proto3" json:"working_dirs,omitempty"`
+	// The messages of the session, as a JSON array.
+	MessagesJson []byte `protobuf:"bytes,10,opt,name=messages_json,json=messagesJson,proto3" json:"messages_json,omitempty"`
+	// The permissions of the session, as a JSON object.
+	PermissionsJson []byte `protobuf:"bytes,11,opt,name=permissions_json,json=permissionsJson,proto3" json:"permissions_json,omitempty"`
+	// The artifacts of the session, as a JSON array.
+	ArtifactsJson []byte `protobuf:"bytes,12,opt,name=artifacts_json,json=artifactsJson,proto3" json:"artifacts_json,omitempty"`
+	// The findings the agents of the team shared, as a JSON array.
+	BlackboardJson []byte `protobuf:"bytes,13,opt,name=blackboard_json,json=blackboardJson,proto3" json:"blackboard_json,omitempty"`
+	// The tool approvals, elicitations, escalations, model switches and
+	// compactions of the session, as a JSON array.
+	RecordsJson   []byte `protobuf:"bytes,14,opt,name=records_json,json=recordsJson,proto3" json:"records_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetToolsApproved() bool {
+	if x != nil {
+		return x.ToolsApproved
+	}
+	return false
+}
+
+func (x *Session) GetThinking() bool {
+	if x != nil {
+		return x.Thinking
+	}
+	return false
+}
+
+func (x *Session) GetInputTokens() int64 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *Session) GetOutputTokens() int64 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *Session) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *Session) GetWorkingDirs() []string {
+	if x != nil {
+		return x.WorkingDirs
+	}
+	return nil
+}
+
+func (x *Session) GetMessagesJson() []byte {
+	if x != nil {
+		return x.MessagesJson
+	}
+	return nil
+}
+
+func (x *Session) GetPermissionsJson() []byte {
+	if x != nil {
+		return x.PermissionsJson
+	}
+	return nil
+}
+
+func (x *Session) GetArtifactsJson() []byte {
+	if x != nil {
+		return x.ArtifactsJson
+	}
+	return nil
+}
+
+func (x *Session) GetBlackboardJson() []byte {
+	if x != nil {
+		return x.BlackboardJson
+	}
+	return nil
+}
+
+func (x *Session) GetRecordsJson() []byte {
+	if x != nil {
+		return x.RecordsJson
+	}
+	return nil
+}
+
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkingDirs   []string               `protobuf:"bytes,1,rep,name=working_dirs,json=workingDirs,proto3" json:"working_dirs,omitempty"`
+	MaxIterations int64                  `protobuf:"varint,2,opt,name=max_iterations,json=maxIterations,proto3" json:"max_iterations,omitempty"`
+	ToolsApproved bool                   `protobuf:"varint,3,opt,name=tools_approved,json=toolsApproved,proto3" json:"tools_approved,omitempty"`
+	// The permissions of the session, as a JSON object.
+	PermissionsJson []byte `protobuf:"bytes,4,opt,name=permissions_json,json=permissionsJson,proto3" json:"permissions_json,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateSessionRequest) GetWorkingDirs() []string {
+	if x != nil {
+		return x.WorkingDirs
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetMaxIterations() int64 {
+	if x != nil {
+		return x.MaxIterations
+	}
+	return 0
+}
+
+func (x *CreateSessionRequest) GetToolsApproved() bool {
+	if x != nil {
+		return x.ToolsApproved
+	}
+	return false
+}
+
+func (x *CreateSessionRequest) GetPermissionsJson() []byte {
+	if x != nil {
+		return x.PermissionsJson
+	}
+	return nil
+}
+
+type UpdateSessionTitleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSessionTitleRequest) Reset() {
+	*x = UpdateSessionTitleRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSessionTitleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSessionTitleRequest) ProtoMessage() {}
+
+func (x *UpdateSessionTitleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSessionTitleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSessionTitleRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateSessionTitleRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *UpdateSessionTitleRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type UpdateSessionTitleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSessionTitleResponse) Reset() {
+	*x = UpdateSessionTitleResponse{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSessionTitleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSessionTitleResponse) ProtoMessage() {}
+
+func (x *UpdateSessionTitleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSessionTitleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateSessionTitleResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateSessionTitleResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSessionTitleResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+// ResumeSessionRequest answers a tool call confirmation, or continues a run
+// that reached its iteration limit.
+type ResumeSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The session, only used by the unary method.
+	SessionId     string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Confirmation  string `protobuf:"bytes,2,opt,name=confirmation,proto3" json:"confirmation,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ToolName      string `protobuf:"bytes,4,opt,name=tool_name,json=toolName,proto3" json:"tool_name,omitempty"`
+	Iterations    int64  `protobuf:"varint,5,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeSessionRequest) Reset() {
+	*x = ResumeSessionRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeSessionRequest) ProtoMessage() {}
+
+func (x *ResumeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeSessionRequest.ProtoReflect.Descriptor instead.
+func (*ResumeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ResumeSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ResumeSessionRequest) GetConfirmation() string {
+	if x != nil {
+		return x.Confirmation
+	}
+	return ""
+}
+
+func (x *ResumeSessionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ResumeSessionRequest) GetToolName() string {
+	if x != nil {
+		return x.ToolName
+	}
+	return ""
+}
+
+func (x *ResumeSessionRequest) GetIterations() int64 {
+	if x != nil {
+		return x.Iterations
+	}
+	return 0
+}
+
+// ResumeElicitationRequest answers an elicitation.
+type ResumeElicitationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The session, only used by the unary method.
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// "accept", "decline" or "cancel".
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// The submitted form data, as a JSON object.
+	ContentJson   []byte `protobuf:"bytes,3,opt,name=content_json,json=contentJson,proto3" json:"content_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeElicitationRequest) Reset() {
+	*x = ResumeElicitationRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeElicitationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeElicitationRequest) ProtoMessage() {}
+
+func (x *ResumeElicitationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeElicitationRequest.ProtoReflect.Descriptor instead.
+func (*ResumeElicitationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ResumeElicitationRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ResumeElicitationRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ResumeElicitationRequest) GetContentJson() []byte {
+	if x != nil {
+		return x.ContentJson
+	}
+	return nil
+}
+
+// Message is a message sent to an agent.
+type Message struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Role    string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Content string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// The parts of a message with attachments, as a JSON array.
+	MultiContentJson []byte `protobuf:"bytes,3,opt,name=multi_content_json,json=multiContentJson,proto3" json:"multi_content_json,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Message) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Message) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Message) GetMultiContentJson() []byte {
+	if x != nil {
+		return x.MultiContentJson
+	}
+	return nil
+}
+
+type RunAgentRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// The agent file, or directory, as listed by ListAgents.
+	Agent string `protobuf:"bytes,2,opt,name=agent,proto3" json:"agent,omitempty"`
+	// The agent of the team to run, the root agent by default.
+	AgentName     string     `protobuf:"bytes,3,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	Messages      []*Message `protobuf:"bytes,4,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunAgentRequest) Reset() {
+	*x = RunAgentRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunAgentRequest) ProtoMessage() {}
+
+func (x *RunAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunAgentRequest.ProtoReflect.Descriptor instead.
+func (*RunAgentRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RunAgentRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RunAgentRequest) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *RunAgentRequest) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *RunAgentRequest) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// SessionStreamRequest is a message sent by the client on a session stream.
+type SessionStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*SessionStreamRequest_Run
+	//	*SessionStreamRequest_Resume
+	//	*SessionStreamRequest_Elicitation
+	Request       isSessionStreamRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionStreamRequest) Reset() {
+	*x = SessionStreamRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionStreamRequest) ProtoMessage() {}
+
+func (x *SessionStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionStreamRequest.ProtoReflect.Descriptor instead.
+func (*SessionStreamRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SessionStreamRequest) GetRequest() isSessionStreamRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *SessionStreamRequest) GetRun() *RunAgentRequest {
+	if x != nil {
+		if x, ok := x.Request.(*SessionStreamRequest_Run); ok {
+			return x.Run
+		}
+	}
+	return nil
+}
+
+func (x *SessionStreamRequest) GetResume() *ResumeSessionRequest {
+	if x != nil {
+		if x, ok := x.Request.(*SessionStreamRequest_Resume); ok {
+			return x.Resume
+		}
+	}
+	return nil
+}
+
+func (x *SessionStreamRequest) GetElicitation() *ResumeElicitationRequest {
+	if x != nil {
+		if x, ok := x.Request.(*SessionStreamRequest_Elicitation); ok {
+			return x.Elicitation
+		}
+	}
+	return nil
+}
+
+type isSessionStreamRequest_Request interface {
+	isSessionStreamRequest_Request()
+}
+
+type SessionStreamRequest_Run struct {
+	Run *RunAgentRequest `protobuf:"bytes,1,opt,name=run,proto3,oneof"`
+}
+
+type SessionStreamRequest_Resume struct {
+	Resume *ResumeSessionRequest `protobuf:"bytes,2,opt,name=resume,proto3,oneof"`
+}
+
+type SessionStreamRequest_Elicitation struct {
+	Elicitation *ResumeElicitationRequest `protobuf:"bytes,3,opt,name=elicitation,proto3,oneof"`
+}
+
+func (*SessionStreamRequest_Run) isSessionStreamRequest_Request() {}
+
+func (*SessionStreamRequest_Resume) isSessionStreamRequest_Request() {}
+
+func (*SessionStreamRequest_Elicitation) isSessionStreamRequest_Request() {}
+
+// Event is an event of a run.
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The agent that sent the event, if any.
+	AgentName string `protobuf:"bytes,1,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	// RFC 3339 time of the event.
+	Timestamp string `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_StreamStarted
+	//	*Event_StreamStopped
+	//	*Event_AgentChoice
+	//	*Event_AgentChoiceReasoning
+	//	*Event_ToolCall
+	//	*Event_PartialToolCall
+	//	*Event_ToolCallConfirmation
+	//	*Event_ToolCallResponse
+	//	*Event_Error
+	//	*Event_ElicitationRequest
+	//	*Event_MaxIterationsReached
+	//	*Event_SessionTitle
+	//	*Event_Warning
+	//	*Event_Other
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Event) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetStreamStarted() *StreamStarted {
+	if x != nil {
+		if x, ok := x.Event.(*Event_StreamStarted); ok {
+			return x.StreamStarted
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetStreamStopped() *StreamStopped {
+	if x != nil {
+		if x, ok := x.Event.(*Event_StreamStopped); ok {
+			return x.StreamStopped
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetAgentChoice() *AgentChoice {
+	if x != nil {
+		if x, ok := x.Event.(*Event_AgentChoice); ok {
+			return x.AgentChoice
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetAgentChoiceReasoning() *AgentChoice {
+	if x != nil {
+		if x, ok := x.Event.(*Event_AgentChoiceReasoning); ok {
+			return x.AgentChoiceReasoning
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetToolCall() *ToolCallEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ToolCall); ok {
+			return x.ToolCall
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetPartialToolCall() *ToolCallEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_PartialToolCall); ok {
+			return x.PartialToolCall
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetToolCallConfirmation() *ToolCallConfirmation {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ToolCallConfirmation); ok {
+			return x.ToolCallConfirmation
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetToolCallResponse() *ToolCallResponse {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ToolCallResponse); ok {
+			return x.ToolCallResponse
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetElicitationRequest() *ElicitationRequest {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ElicitationRequest); ok {
+			return x.ElicitationRequest
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetMaxIterationsReached() *MaxIterationsReached {
+	if x != nil {
+		if x, ok := x.Event.(*Event_MaxIterationsReached); ok {
+			return x.MaxIterationsReached
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetSessionTitle() *SessionTitle {
+	if x != nil {
+		if x, ok := x.Event.(*Event_SessionTitle); ok {
+			return x.SessionTitle
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetWarning() *Warning {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Warning); ok {
+			return x.Warning
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetOther() *OtherEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Other); ok {
+			return x.Other
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_StreamStarted struct {
+	StreamStarted *StreamStarted `protobuf:"bytes,3,opt,name=stream_started,json=streamStarted,proto3,oneof"`
+}
+
+type Event_StreamStopped struct {
+	StreamStopped *StreamStopped `protobuf:"bytes,4,opt,name=stream_stopped,json=streamStopped,proto3,oneof"`
+}
+
+type Event_AgentChoice struct {
+	AgentChoice *AgentChoice `protobuf:"bytes,5,opt,name=agent_choice,json=agentChoice,proto3,oneof"`
+}
+
+type Event_AgentChoiceReasoning struct {
+	AgentChoiceReasoning *AgentChoice `protobuf:"bytes,6,opt,name=agent_choice_reasoning,json=agentChoiceReasoning,proto3,oneof"`
+}
+
+type Event_ToolCall struct {
+	ToolCall *ToolCallEvent `protobuf:"bytes,7,opt,name=tool_call,json=toolCall,proto3,oneof"`
+}
+
+type Event_PartialToolCall struct {
+	PartialToolCall *ToolCallEvent `protobuf:"bytes,8,opt,name=partial_tool_call,json=partialToolCall,proto3,oneof"`
+}
+
+type Event_ToolCallConfirmation struct {
+	ToolCallConfirmation *ToolCallConfirmation `protobuf:"bytes,9,opt,name=tool_call_confirmation,json=toolCallConfirmation,proto3,oneof"`
+}
+
+type Event_ToolCallResponse struct {
+	ToolCallResponse *ToolCallResponse `protobuf:"bytes,10,opt,name=tool_call_response,json=toolCallResponse,proto3,oneof"`
+}
+
+type Event_Error struct {
+	Error *Error `protobuf:"bytes,11,opt,name=error,proto3,oneof"`
+}
+
+type Event_ElicitationRequest struct {
+	ElicitationRequest *ElicitationRequest `protobuf:"bytes,12,opt,name=elicitation_request,json=elicitationRequest,proto3,oneof"`
+}
+
+type Event_MaxIterationsReached struct {
+	MaxIterationsReached *MaxIterationsReached `protobuf:"bytes,13,opt,name=max_iterations_reached,json=maxIterationsReached,proto3,oneof"`
+}
+
+type Event_SessionTitle struct {
+	SessionTitle *SessionTitle `protobuf:"bytes,14,opt,name=session_title,json=sessionTitle,proto3,oneof"`
+}
+
+type Event_Warning struct {
+	Warning *Warning `protobuf:"bytes,15,opt,name=warning,proto3,oneof"`
+}
+
+type Event_Other struct {
+	// Any other event.
+	Other *OtherEvent `protobuf:"bytes,16,opt,name=other,proto3,oneof"`
+}
+
+func (*Event_StreamStarted) isEvent_Event() {}
+
+func (*Event_StreamStopped) isEvent_Event() {}
+
+func (*Event_AgentChoice) isEvent_Event() {}
+
+func (*Event_AgentChoiceReasoning) isEvent_Event() {}
+
+func (*Event_ToolCall) isEvent_Event() {}
+
+func (*Event_PartialToolCall) isEvent_Event() {}
+
+func (*Event_ToolCallConfirmation) isEvent_Event() {}
+
+func (*Event_ToolCallResponse) isEvent_Event() {}
+
+func (*Event_Error) isEvent_Event() {}
+
+func (*Event_ElicitationRequest) isEvent_Event() {}
+
+func (*Event_MaxIterationsReached) isEvent_Event() {}
+
+func (*Event_SessionTitle) isEvent_Event() {}
+
+func (*Event_Warning) isEvent_Event() {}
+
+func (*Event_Other) isEvent_Event() {}
+
+type StreamStarted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamStarted) Reset() {
+	*x = StreamStarted{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamStarted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStarted) ProtoMessage() {}
+
+func (x *StreamStarted) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStarted.ProtoReflect.Descriptor instead.
+func (*StreamStarted) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StreamStarted) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type StreamStopped struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamStopped) Reset() {
+	*x = StreamStopped{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamStopped) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStopped) ProtoMessage() {}
+
+func (x *StreamStopped) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStopped.ProtoReflect.Descriptor instead.
+func (*StreamStopped) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StreamStopped) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// AgentChoice is a part of the answer, or of the reasoning, of an agent.
+type AgentChoice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentChoice) Reset() {
+	*x = AgentChoice{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentChoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentChoice) ProtoMessage() {}
+
+func (x *AgentChoice) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentChoice.ProtoReflect.Descriptor instead.
+func (*AgentChoice) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AgentChoice) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AgentChoice) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// ToolCall is a call of a tool by an agent.
+type ToolCall struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// "function".
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// The arguments, as a JSON object. They are incomplete in partial tool calls.
+	Arguments     string `protobuf:"bytes,4,opt,name=arguments,proto3" json:"arguments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolCall) Reset() {
+	*x = ToolCall{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolCall) ProtoMessage() {}
+
+func (x *ToolCall) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolCall.ProtoReflect.Descriptor instead.
+func (*ToolCall) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ToolCall) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ToolCall) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ToolCall) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ToolCall) GetArguments() string {
+	if x != nil {
+		return x.Arguments
+	}
+	return ""
+}
+
+type ToolAnnotations struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Title           string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	ReadOnlyHint    bool                   `protobuf:"varint,2,opt,name=read_only_hint,json=readOnlyHint,proto3" json:"read_only_hint,omitempty"`
+	DestructiveHint *bool                  `protobuf:"varint,3,opt,name=destructive_hint,json=destructiveHint,proto3,oneof" json:"destructive_hint,omitempty"`
+	IdempotentHint  bool                   `protobuf:"varint,4,opt,name=idempotent_hint,json=idempotentHint,proto3" json:"idempotent_hint,omitempty"`
+	OpenWorldHint   *bool                  `protobuf:"varint,5,opt,name=open_world_hint,json=openWorldHint,proto3,oneof" json:"open_world_hint,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ToolAnnotations) Reset() {
+	*x = ToolAnnotations{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolAnnotations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolAnnotations) ProtoMessage() {}
+
+func (x *ToolAnnotations) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolAnnotations.ProtoReflect.Descriptor instead.
+func (*ToolAnnotations) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ToolAnnotations) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ToolAnnotations) GetReadOnlyHint() bool {
+	if x != nil {
+		return x.ReadOnlyHint
+	}
+	return false
+}
+
+func (x *ToolAnnotations) GetDestructiveHint() bool {
+	if x != nil && x.DestructiveHint != nil {
+		return *x.DestructiveHint
+	}
+	return false
+}
+
+func (x *ToolAnnotations) GetIdempotentHint() bool {
+	if x != nil {
+		return x.IdempotentHint
+	}
+	return false
+}
+
+func (x *ToolAnnotations) GetOpenWorldHint() bool {
+	if x != nil && x.OpenWorldHint != nil {
+		return *x.OpenWorldHint
+	}
+	return false
+}
+
+// ToolDefinition is the definition of a called tool.
+type ToolDefinition struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Category    string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Annotations *ToolAnnotations       `protobuf:"bytes,4,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	// The JSON schema of the parameters.
+	ParametersJson []byte `protobuf:"bytes,5,opt,name=parameters_json,json=parametersJson,proto3" json:"parameters_json,omitempty"`
+	// The JSON schema of the output, if any.
+	OutputSchemaJson []byte `protobuf:"bytes,6,opt,name=output_schema_json,json=outputSchemaJson,proto3" json:"output_schema_json,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ToolDefinition) Reset() {
+	*x = ToolDefinition{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolDefinition) ProtoMessage() {}
+
+func (x *ToolDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolDefinition.ProtoReflect.Descriptor instead.
+func (*ToolDefinition) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ToolDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ToolDefinition) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ToolDefinition) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ToolDefinition) GetAnnotations() *ToolAnnotations {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *ToolDefinition) GetParametersJson() []byte {
+	if x != nil {
+		return x.ParametersJson
+	}
+	return nil
+}
+
+func (x *ToolDefinition) GetOutputSchemaJson() []byte {
+	if x != nil {
+		return x.OutputSchemaJson
+	}
+	return nil
+}
+
+// ToolCallEvent is sent when an agent calls a tool, and while it's streaming
+// the call.
+type ToolCallEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ToolCall       *ToolCall              `protobuf:"bytes,1,opt,name=tool_call,json=toolCall,proto3" json:"tool_call,omitempty"`
+	ToolDefinition *ToolDefinition        `protobuf:"bytes,2,opt,name=tool_definition,json=toolDefinition,proto3" json:"tool_definition,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ToolCallEvent) Reset() {
+	*x = ToolCallEvent{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolCallEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolCallEvent) ProtoMessage() {}
+
+func (x *ToolCallEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolCallEvent.ProtoReflect.Descriptor instead.
+func (*ToolCallEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ToolCallEvent) GetToolCall() *ToolCall {
+	if x != nil {
+		return x.ToolCall
+	}
+	return nil
+}
+
+func (x *ToolCallEvent) GetToolDefinition() *ToolDefinition {
+	if x != nil {
+		return x.ToolDefinition
+	}
+	return nil
+}
+
+// ToolCallConfirmation asks to approve a tool call. It's answered with a
+// ResumeSessionRequest.
+type ToolCallConfirmation struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ToolCall       *ToolCall              `protobuf:"bytes,1,opt,name=tool_call,json=toolCall,proto3" json:"tool_call,omitempty"`
+	ToolDefinition *ToolDefinition        `protobuf:"bytes,2,opt,name=tool_definition,json=toolDefinition,proto3" json:"tool_definition,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ToolCallConfirmation) Reset() {
+	*x = ToolCallConfirmation{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolCallConfirmation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolCallConfirmation) ProtoMessage() {}
+
+func (x *ToolCallConfirmation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolCallConfirmation.ProtoReflect.Descriptor instead.
+func (*ToolCallConfirmation) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ToolCallConfirmation) GetToolCall() *ToolCall {
+	if x != nil {
+		return x.ToolCall
+	}
+	return nil
+}
+
+func (x *ToolCallConfirmation) GetToolDefinition() *ToolDefinition {
+	if x != nil {
+		return x.ToolDefinition
+	}
+	return nil
+}
+
+type ToolCallResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ToolCall       *ToolCall              `protobuf:"bytes,1,opt,name=tool_call,json=toolCall,proto3" json:"tool_call,omitempty"`
+	ToolDefinition *ToolDefinition        `protobuf:"bytes,2,opt,name=tool_definition,json=toolDefinition,proto3" json:"tool_definition,omitempty"`
+	Response       string                 `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+	// The result, with its attachments and structured content, as a JSON object.
+	ResultJson    []byte `protobuf:"bytes,4,opt,name=result_json,json=resultJson,proto3" json:"result_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolCallResponse) Reset() {
+	*x = ToolCallResponse{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolCallResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolCallResponse) ProtoMessage() {}
+
+func (x *ToolCallResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolCallResponse.ProtoReflect.Descriptor instead.
+func (*ToolCallResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ToolCallResponse) GetToolCall() *ToolCall {
+	if x != nil {
+		return x.ToolCall
+	}
+	return nil
+}
+
+func (x *ToolCallResponse) GetToolDefinition() *ToolDefinition {
+	if x != nil {
+		return x.ToolDefinition
+	}
+	return nil
+}
+
+func (x *ToolCallResponse) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *ToolCallResponse) GetResultJson() []byte {
+	if x != nil {
+		return x.ResultJson
+	}
+	return nil
+}
+
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Error string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// "model_error" or "tool_failure", when known.
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// The kind of model errors, when known, e.g. "rate_limit".
+	Kind          string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Remediation   string `protobuf:"bytes,4,opt,name=remediation,proto3" json:"remediation,omitempty"`
+	Detail        string `protobuf:"bytes,5,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Error) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Error) GetRemediation() string {
+	if x != nil {
+		return x.Remediation
+	}
+	return ""
+}
+
+func (x *Error) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// ElicitationRequest asks the user for input. It's answered with a
+// ResumeElicitationRequest.
+type ElicitationRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// "form" or "url".
+	Mode string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// The JSON schema of the form.
+	SchemaJson    []byte `protobuf:"bytes,3,opt,name=schema_json,json=schemaJson,proto3" json:"schema_json,omitempty"`
+	Url           string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	ElicitationId string `protobuf:"bytes,5,opt,name=elicitation_id,json=elicitationId,proto3" json:"elicitation_id,omitempty"`
+	// The metadata of the request, as a JSON object.
+	MetaJson      []byte `protobuf:"bytes,6,opt,name=meta_json,json=metaJson,proto3" json:"meta_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ElicitationRequest) Reset() {
+	*x = ElicitationRequest{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ElicitationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ElicitationRequest) ProtoMessage() {}
+
+func (x *ElicitationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ElicitationRequest.ProtoReflect.Descriptor instead.
+func (*ElicitationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ElicitationRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ElicitationRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ElicitationRequest) GetSchemaJson() []byte {
+	if x != nil {
+		return x.SchemaJson
+	}
+	return nil
+}
+
+func (x *ElicitationRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ElicitationRequest) GetElicitationId() string {
+	if x != nil {
+		return x.ElicitationId
+	}
+	return ""
+}
+
+func (x *ElicitationRequest) GetMetaJson() []byte {
+	if x != nil {
+		return x.MetaJson
+	}
+	return nil
+}
+
+// MaxIterationsReached is sent when a run reached its iteration limit. It's
+// answered with a ResumeSessionRequest.
+type MaxIterationsReached struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxIterations int64                  `protobuf:"varint,1,opt,name=max_iterations,json=maxIterations,proto3" json:"max_iterations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaxIterationsReached) Reset() {
+	*x = MaxIterationsReached{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaxIterationsReached) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaxIterationsReached) ProtoMessage() {}
+
+func (x *MaxIterationsReached) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaxIterationsReached.ProtoReflect.Descriptor instead.
+func (*MaxIterationsReached) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *MaxIterationsReached) GetMaxIterations() int64 {
+	if x != nil {
+		return x.MaxIterations
+	}
+	return 0
+}
+
+type SessionTitle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionTitle) Reset() {
+	*x = SessionTitle{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionTitle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionTitle) ProtoMessage() {}
+
+func (x *SessionTitle) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionTitle.ProtoReflect.Descriptor instead.
+func (*SessionTitle) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SessionTitle) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionTitle) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type Warning struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Warning) Reset() {
+	*x = Warning{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Warning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Warning) ProtoMessage() {}
+
+func (x *Warning) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Warning.ProtoReflect.Descriptor instead.
+func (*Warning) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Warning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// OtherEvent is an event that has no message of its own.
+type OtherEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The type of the event, e.g. "token_usage".
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// The event, as the JSON object of the HTTP API.
+	Json          []byte `protobuf:"bytes,2,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OtherEvent) Reset() {
+	*x = OtherEvent{}
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OtherEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OtherEvent) ProtoMessage() {}
+
+func (x *OtherEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_api_agentv1_agent_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OtherEvent.ProtoReflect.Descriptor instead.
+func (*OtherEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_api_agentv1_agent_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *OtherEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *OtherEvent) GetJson() []byte {
+	if x != nil {
+		return x.Json
+	}
+	return nil
+}
+
+var File_pkg_api_agentv1_agent_proto protoreflect.FileDescriptor
+
+const file_pkg_api_agentv1_agent_proto_rawDesc = "" +
+	"\n" +
+	"\x1bpkg/api/agentv1/agent.proto\x12\x0fdocker.agent.v1\"\a\n" +
+	"\x05Empty\"=\n" +
+	"\fAgentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"agent_name\x18\x02 \x01(\tR\tagentName\"S\n" +
+	"\x05Agent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05multi\x18\x03 \x01(\bR\x05multi\"D\n" +
+	"\x12ListAgentsResponse\x12.\n" +
+	"\x06agents\x18\x01 \x03(\v2\x16.docker.agent.v1.AgentR\x06agents\"!\n" +
+	"\vAgentConfig\x12\x12\n" +
+	"\x04json\x18\x01 \x01(\fR\x04json\"A\n" +
+	"\x16AgentToolCountResponse\x12'\n" +
+	"\x0favailable_tools\x18\x01 \x01(\x03R\x0eavailableTools\"\xe1\x01\n" +
+	"\x0eSessionSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12!\n" +
+	"\fnum_messages\x18\x04 \x01(\x03R\vnumMessages\x12!\n" +
+	"\finput_tokens\x18\x05 \x01(\x03R\vinputTokens\x12#\n" +
+	"\routput_tokens\x18\x06 \x01(\x03R\foutputTokens\x12\x1f\n" +
+	"\vworking_dir\x18\a \x01(\tR\n" +
+	"workingDir\"S\n" +
+	"\x14ListSessionsResponse\x12;\n" +
+	"\bsessions\x18\x01 \x03(\v2\x1f.docker.agent.v1.SessionSummaryR\bsessions\"/\n" +
+	"\x0eSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\xe0\x03\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12%\n" +
+	"\x0etools_approved\x18\x04 \x01(\bR\rtoolsApproved\x12\x1a\n" +
+	"\bthinking\x18\x05 \x01(\bR\bthinking\x12!\n" +
+	"\finput_tokens\x18\x06 \x01(\x03R\vinputTokens\x12#\n" +
+	"\routput_tokens\x18\a \x01(\x03R\foutputTokens\x12\x1f\n" +
+	"\vworking_dir\x18\b \x01(\tR\n" +
+	"workingDir\x12!\n" +
+	"\fworking_dirs\x18\t \x03(\tR\vworkingDirs\x12#\n" +
+	"\rmessages_json\x18\n" +
+	" \x01(\fR\fmessagesJson\x12)\n" +
+	"\x10permissions_json\x18\v \x01(\fR\x0fpermissionsJson\x12%\n" +
+	"\x0eartifacts_json\x18\f \x01(\fR\rartifactsJson\x12'\n" +
+	"\x0fblackboard_json\x18\r \x01(\fR\x0eblackboardJson\x12!\n" +
+	"\frecords_json\x18\x0e \x01(\fR\vrecordsJson\"\xb2\x01\n" +
+	"\x14CreateSessionRequest\x12!\n" +
+	"\fworking_dirs\x18\x01 \x03(\tR\vworkingDirs\x12%\n" +
+	"\x0emax_iterations\x18\x02 \x01(\x03R\rmaxIterations\x12%\n" +
+	"\x0etools_approved\x18\x03 \x01(\bR\rtoolsApproved\x12)\n" +
+	"\x10permissions_json\x18\x04 \x01(\fR\x0fpermissionsJson\"P\n" +
+	"\x19UpdateSessionTitleRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"B\n" +
+	"\x1aUpdateSessionTitleResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"\xae\x01\n" +
+	"\x14ResumeSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\"\n" +
+	"\fconfirmation\x18\x02 \x01(\tR\fconfirmation\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1b\n" +
+	"\ttool_name\x18\x04 \x01(\tR\btoolName\x12\x1e\n" +
+	"\n" +
+	"iterations\x18\x05 \x01(\x03R\n" +
+	"iterations\"t\n" +
+	"\x18ResumeElicitationRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12!\n" +
+	"\fcontent_json\x18\x03 \x01(\fR\vcontentJson\"e\n" +
+	"\aMessage\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12,\n" +
+	"\x12multi_content_json\x18\x03 \x01(\fR\x10multiContentJson\"\x9b\x01\n" +
+	"\x0fRunAgentRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05agent\x18\x02 \x01(\tR\x05agent\x12\x1d\n" +
+	"\n" +
+	"agent_name\x18\x03 \x01(\tR\tagentName\x124\n" +
+	"\bmessages\x18\x04 \x03(\v2\x18.docker.agent.v1.MessageR\bmessages\"\xe7\x01\n" +
+	"\x14SessionStreamRequest\x124\n" +
+	"\x03run\x18\x01 \x01(\v2 .docker.agent.v1.RunAgentRequestH\x00R\x03run\x12?\n" +
+	"\x06resume\x18\x02 \x01(\v2%.docker.agent.v1.ResumeSessionRequestH\x00R\x06resume\x12M\n" +
+	"\velicitation\x18\x03 \x01(\v2).docker.agent.v1.ResumeElicitationRequestH\x00R\velicitationB\t\n" +
+	"\arequest\"\xcf\b\n" +
+	"\x05Event\x12\x1d\n" +
+	"\n" +
+	"agent_name\x18\x01 \x01(\tR\tagentName\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\x12G\n" +
+	"\x0estream_started\x18\x03 \x01(\v2\x1e.docker.agent.v1.StreamStartedH\x00R\rstreamStarted\x12G\n" +
+	"\x0estream_stopped\x18\x04 \x01(\v2\x1e.docker.agent.v1.StreamStoppedH\x00R\rstreamStopped\x12A\n" +
+	"\fagent_choice\x18\x05 \x01(\v2\x1c.docker.agent.v1.AgentChoiceH\x00R\vagentChoice\x12T\n" +
+	"\x16agent_choice_reasoning\x18\x06 \x01(\v2\x1c.docker.agent.v1.AgentChoiceH\x00R\x14agentChoiceReasoning\x12=\n" +
+	"\ttool_call\x18\a \x01(\v2\x1e.docker.agent.v1.ToolCallEventH\x00R\btoolCall\x12L\n" +
+	"\x11partial_tool_call\x18\b \x01(\v2\x1e.docker.agent.v1.ToolCallEventH\x00R\x0fpartialToolCall\x12]\n" +
+	"\x16tool_call_confirmation\x18\t \x01(\v2%.docker.agent.v1.ToolCallConfirmationH\x00R\x14toolCallConfirmation\x12Q\n" +
+	"\x12tool_call_response\x18\n" +
+	" \x01(\v2!.docker.agent.v1.ToolCallResponseH\x00R\x10toolCallResponse\x12.\n" +
+	"\x05error\x18\v \x01(\v2\x16.docker.agent.v1.ErrorH\x00R\x05error\x12V\n" +
+	"\x13elicitation_request\x18\f \x01(\v2#.docker.agent.v1.ElicitationRequestH\x00R\x12elicitationRequest\x12]\n" +
+	"\x16max_iterations_reached\x18\r \x01(\v2%.docker.agent.v1.MaxIterationsReachedH\x00R\x14maxIterationsReached\x12D\n" +
+	"\rsession_title\x18\x0e \x01(\v2\x1d.docker.agent.v1.SessionTitleH\x00R\fsessionTitle\x124\n" +
+	"\awarning\x18\x0f \x01(\v2\x18.docker.agent.v1.WarningH\x00R\awarning\x123\n" +
+	"\x05other\x18\x10 \x01(\v2\x1b.docker.agent.v1.OtherEventH\x00R\x05otherB\a\n" +
+	"\x05event\".\n" +
+	"\rStreamStarted\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\".\n" +
+	"\rStreamStopped\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"F\n" +
+	"\vAgentChoice\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"`\n" +
+	"\bToolCall\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1c\n" +
+	"\targuments\x18\x04 \x01(\tR\targuments\"\xfc\x01\n" +
+	"\x0fToolAnnotations\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12$\n" +
+	"\x0eread_only_hint\x18\x02 \x01(\bR\freadOnlyHint\x12.\n" +
+	"\x10destructive_hint\x18\x03 \x01(\bH\x00R\x0fdestructiveHint\x88\x01\x01\x12'\n" +
+	"\x0fidempotent_hint\x18\x04 \x01(\bR\x0eidempotentHint\x12+\n" +
+	"\x0fopen_world_hint\x18\x05 \x01(\bH\x01R\ropenWorldHint\x88\x01\x01B\x13\n" +
+	"\x11_destructive_hintB\x12\n" +
+	"\x10_open_world_hint\"\xfd\x01\n" +
+	"\x0eToolDefinition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12B\n" +
+	"\vannotations\x18\x04 \x01(\v2 .docker.agent.v1.ToolAnnotationsR\vannotations\x12'\n" +
+	"\x0fparameters_json\x18\x05 \x01(\fR\x0eparametersJson\x12,\n" +
+	"\x12output_schema_json\x18\x06 \x01(\fR\x10outputSchemaJson\"\x91\x01\n" +
+	"\rToolCallEvent\x126\n" +
+	"\ttool_call\x18\x01 \x01(\v2\x19.docker.agent.v1.ToolCallR\btoolCall\x12H\n" +
+	"\x0ftool_definition\x18\x02 \x01(\v2\x1f.docker.agent.v1.ToolDefinitionR\x0etoolDefinition\"\x98\x01\n" +
+	"\x14ToolCallConfirmation\x126\n" +
+	"\ttool_call\x18\x01 \x01(\v2\x19.docker.agent.v1.ToolCallR\btoolCall\x12H\n" +
+	"\x0ftool_definition\x18\x02 \x01(\v2\x1f.docker.agent.v1.ToolDefinitionR\x0etoolDefinition\"\xd1\x01\n" +
+	"\x10ToolCallResponse\x126\n" +
+	"\ttool_call\x18\x01 \x01(\v2\x19.docker.agent.v1.ToolCallR\btoolCall\x12H\n" +
+	"\x0ftool_definition\x18\x02 \x01(\v2\x1f.docker.agent.v1.ToolDefinitionR\x0etoolDefinition\x12\x1a\n" +
+	"\bresponse\x18\x03 \x01(\tR\bresponse\x12\x1f\n" +
+	"\vresult_json\x18\x04 \x01(\fR\n" +
+	"resultJson\"\x7f\n" +
+	"\x05Error\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12 \n" +
+	"\vremediation\x18\x04 \x01(\tR\vremediation\x12\x16\n" +
+	"\x06detail\x18\x05 \x01(\tR\x06detail\"\xb9\x01\n" +
+	"\x12ElicitationRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x12\x1f\n" +
+	"\vschema_json\x18\x03 \x01(\fR\n" +
+	"schemaJson\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12%\n" +
+	"\x0eelicitation_id\x18\x05 \x01(\tR\relicitationId\x12\x1b\n" +
+	"\tmeta_json\x18\x06 \x01(\fR\bmetaJson\"=\n" +
+	"\x14MaxIterationsReached\x12%\n" +
+	"\x0emax_iterations\x18\x01 \x01(\x03R\rmaxIterations\"C\n" +
+	"\fSessionTitle\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"#\n" +
+	"\aWarning\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"4\n" +
+	"\n" +
+	"OtherEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04json\x18\x02 \x01(\fR\x04json2\xce\b\n" +
+	"\fAgentService\x12I\n" +
+	"\n" +
+	"ListAgents\x12\x16.docker.agent.v1.Empty\x1a#.docker.agent.v1.ListAgentsResponse\x12G\n" +
+	"\bGetAgent\x12\x1d.docker.agent.v1.AgentRequest\x1a\x1c.docker.agent.v1.AgentConfig\x12[\n" +
+	"\x11GetAgentToolCount\x12\x1d.docker.agent.v1.AgentRequest\x1a'.docker.agent.v1.AgentToolCountResponse\x12M\n" +
+	"\fListSessions\x12\x16.docker.agent.v1.Empty\x1a%.docker.agent.v1.ListSessionsResponse\x12G\n" +
+	"\n" +
+	"GetSession\x12\x1f.docker.agent.v1.SessionRequest\x1a\x18.docker.agent.v1.Session\x12P\n" +
+	"\rCreateSession\x12%.docker.agent.v1.CreateSessionRequest\x1a\x18.docker.agent.v1.Session\x12H\n" +
+	"\rDeleteSession\x12\x1f.docker.agent.v1.SessionRequest\x1a\x16.docker.agent.v1.Empty\x12m\n" +
+	"\x12UpdateSessionTitle\x12*.docker.agent.v1.UpdateSessionTitleRequest\x1a+.docker.agent.v1.UpdateSessionTitleResponse\x12f\n" +
+	"\x16RegenerateSessionTitle\x12\x1f.docker.agent.v1.SessionRequest\x1a+.docker.agent.v1.UpdateSessionTitleResponse\x12N\n" +
+	"\rResumeSession\x12%.docker.agent.v1.ResumeSessionRequest\x1a\x16.docker.agent.v1.Empty\x12V\n" +
+	"\x11ResumeElicitation\x12).docker.agent.v1.ResumeElicitationRequest\x1a\x16.docker.agent.v1.Empty\x12F\n" +
+	"\bRunAgent\x12 .docker.agent.v1.RunAgentRequest\x1a\x16.docker.agent.v1.Event0\x01\x12R\n" +
+	"\rStreamSession\x12%.docker.agent.v1.SessionStreamRequest\x1a\x16.docker.agent.v1.Event(\x010\x01B8Z6github.com/docker/docker-agent/pkg/api/agentv1;agentv1b\x06proto3"
+
+var (
+	file_pkg_api_agentv1_agent_proto_rawDescOnce sync.Once
+	file_pkg_api_agentv1_agent_proto_rawDescData []byte
+)
+
+func file_pkg_api_agentv1_agent_proto_rawDescGZIP() []byte {
+	file_pkg_api_agentv1_agent_proto_rawDescOnce.Do(func() {
+		file_pkg_api_agentv1_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_api_agentv1_agent_proto_rawDesc), len(file_pkg_api_agentv1_agent_proto_rawDesc)))
+	})
+	return file_pkg_api_agentv1_agent_proto_rawDescData
+}
+
+var file_pkg_api_agentv1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_pkg_api_agentv1_agent_proto_goTypes = []any{
+	(*Empty)(nil),                      // 0: docker.agent.v1.Empty
+	(*AgentRequest)(nil),               // 1: docker.agent.v1.AgentRequest
+	(*Agent)(nil),                      // 2: docker.agent.v1.Agent
+	(*ListAgentsResponse)(nil),         // 3: docker.agent.v1.ListAgentsResponse
+	(*AgentConfig)(nil),                // 4: docker.agent.v1.AgentConfig
+	(*AgentToolCountResponse)(nil),     // 5: docker.agent.v1.AgentToolCountResponse
+	(*SessionSummary)(nil),             // 6: docker.agent.v1.SessionSummary
+	(*ListSessionsResponse)(nil),       // 7: docker.agent.v1.ListSessionsResponse
+	(*SessionRequest)(nil),             // 8: docker.agent.v1.SessionRequest
+	(*Session)(nil),                    // 9: docker.agent.v1.Session
+	(*CreateSessionRequest)(nil),       // 10: docker.agent.v1.CreateSessionRequest
+	(*UpdateSessionTitleRequest)(nil),  // 11: docker.agent.v1.UpdateSessionTitleRequest
+	(*UpdateSessionTitleResponse)(nil), // 12: docker.agent.v1.UpdateSessionTitleResponse
+	(*ResumeSessionRequest)(nil),       // 13: docker.agent.v1.ResumeSessionRequest
+	(*ResumeElicitationRequest)(nil),   // 14: docker.agent.v1.ResumeElicitationRequest
+	(*Message)(nil),                    // 15: docker.agent.v1.Message
+	(*RunAgentRequest)(nil),            // 16: docker.agent.v1.RunAgentRequest
+	(*SessionStreamRequest)(nil),       // 17: docker.agent.v1.SessionStreamRequest
+	(*Event)(nil),                      // 18: docker.agent.v1.Event
+	(*StreamStarted)(nil),              // 19: docker.agent.v1.StreamStarted
+	(*StreamStopped)(nil),              // 20: docker.agent.v1.StreamStopped
+	(*AgentChoice)(nil),                // 21: docker.agent.v1.AgentChoice
+	(*ToolCall)(nil),                   // 22: docker.agent.v1.ToolCall
+	(*ToolAnnotations)(nil),            // 23: docker.agent.v1.ToolAnnotations
+	(*ToolDefinition)(nil),             // 24: docker.agent.v1.ToolDefinition
+	(*ToolCallEvent)(nil),              // 25: docker.agent.v1.ToolCallEvent
+	(*ToolCallConfirmation)(nil),       // 26: docker.agent.v1.ToolCallConfirmation
+	(*ToolCallResponse)(nil),           // 27: docker.agent.v1.ToolCallResponse
+	(*Error)(nil),                      // 28: docker.agent.v1.Error
+	(*ElicitationRequest)(nil),         // 29: docker.agent.v1.ElicitationRequest
+	(*MaxIterationsReached)(nil),       // 30: docker.agent.v1.MaxIterationsReached
+	(*SessionTitle)(nil),               // 31: docker.agent.v1.SessionTitle
+	(*Warning)(nil),                    // 32: docker.agent.v1.Warning
+	(*OtherEvent)(nil),                 // 33: docker.agent.v1.OtherEvent
+}
+var file_pkg_api_agentv1_agent_proto_depIdxs = []int32{
+	2,  // 0: docker.agent.v1.ListAgentsResponse.agents:type_name -> docker.agent.v1.Agent
+	6,  // 1: docker.agent.v1.ListSessionsResponse.sessions:type_name -> docker.agent.v1.SessionSummary
+	15, // 2: docker.agent.v1.RunAgentRequest.messages:type_name -> docker.agent.v1.Message
+	16, // 3: docker.agent.v1.SessionStreamRequest.run:type_name -> docker.agent.v1.RunAgentRequest
+	13, // 4: docker.agent.v1.SessionStreamRequest.resume:type_name -> docker.agent.v1.ResumeSessionRequest
+	14, // 5: docker.agent.v1.SessionStreamRequest.elicitation:type_name -> docker.agent.v1.ResumeElicitationRequest
+	19, // 6: docker.agent.v1.Event.stream_started:type_name -> docker.agent.v1.StreamStarted
+	20, // 7: docker.agent.v1.Event.stream_stopped:type_name -> docker.agent.v1.StreamStopped
+	21, // 8: docker.agent.v1.Event.agent_choice:type_name -> docker.agent.v1.AgentChoice
+	21, // 9: docker.agent.v1.Event.agent_choice_reasoning:type_name -> docker.agent.v1.AgentChoice
+	25, // 10: docker.agent.v1.Event.tool_call:type_name -> docker.agent.v1.ToolCallEvent
+	25, // 11: docker.agent.v1.Event.partial_tool_call:type_name -> docker.agent.v1.ToolCallEvent
+	26, // 12: docker.agent.v1.Event.tool_call_confirmation:type_name -> docker.agent.v1.ToolCallConfirmation
+	27, // 13: docker.agent.v1.Event.tool_call_response:type_name -> docker.agent.v1.ToolCallResponse
+	28, // 14: docker.agent.v1.Event.error:type_name -> docker.agent.v1.Error
+	29, // 15: docker.agent.v1.Event.elicitation_request:type_name -> docker.agent.v1.ElicitationRequest
+	30, // 16: docker.agent.v1.Event.max_iterations_reached:type_name -> docker.agent.v1.MaxIterationsReached
+	31, // 17: docker.agent.v1.Event.session_title:type_name -> docker.agent.v1.SessionTitle
+	32, // 18: docker.agent.v1.Event.warning:type_name -> docker.agent.v1.Warning
+	33, // 19: docker.agent.v1.Event.other:type_name -> docker.agent.v1.OtherEvent
+	23, // 20: docker.agent.v1.ToolDefinition.annotations:type_name -> docker.agent.v1.ToolAnnotations
+	22, // 21: docker.agent.v1.ToolCallEvent.tool_call:type_name -> docker.agent.v1.ToolCall
+	24, // 22: docker.agent.v1.ToolCallEvent.tool_definition:type_name -> docker.agent.v1.ToolDefinition
+	22, // 23: docker.agent.v1.ToolCallConfirmation.tool_call:type_name -> docker.agent.v1.ToolCall
+	24, // 24: docker.agent.v1.ToolCallConfirmation.tool_definition:type_name -> docker.agent.v1.ToolDefinition
+	22, // 25: docker.agent.v1.ToolCallResponse.tool_call:type_name -> docker.agent.v1.ToolCall
+	24, // 26: docker.agent.v1.ToolCallResponse.tool_definition:type_name -> docker.agent.v1.ToolDefinition
+	0,  // 27: docker.agent.v1.AgentService.ListAgents:input_type -> docker.agent.v1.Empty
+	1,  // 28: docker.agent.v1.AgentService.GetAgent:input_type -> docker.agent.v1.AgentRequest
+	1,  // 29: docker.agent.v1.AgentService.GetAgentToolCount:input_type -> docker.agent.v1.AgentRequest
+	0,  // 30: docker.agent.v1.AgentService.ListSessions:input_type -> docker.agent.v1.Empty
+	8,  // 31: docker.agent.v1.AgentService.GetSession:input_type -> docker.agent.v1.SessionRequest
+	10, // 32: docker.agent.v1.AgentService.CreateSession:input_type -> docker.agent.v1.CreateSessionRequest
+	8,  // 33: docker.agent.v1.AgentService.DeleteSession:input_type -> docker.agent.v1.SessionRequest
+	11, // 34: docker.agent.v1.AgentService.UpdateSessionTitle:input_type -> docker.agent.v1.UpdateSessionTitleRequest
+	8,  // 35: docker.agent.v1.AgentService.RegenerateSessionTitle:input_type -> docker.agent.v1.SessionRequest
+	13, // 36: docker.agent.v1.AgentService.ResumeSession:input_type -> docker.agent.v1.ResumeSessionRequest
+	14, // 37: docker.agent.v1.AgentService.ResumeElicitation:input_type -> docker.agent.v1.ResumeElicitationRequest
+	16, // 38: docker.agent.v1.AgentService.RunAgent:input_type -> docker.agent.v1.RunAgentRequest
+	17, // 39: docker.agent.v1.AgentService.StreamSession:input_type -> docker.agent.v1.SessionStreamRequest
+	3,  // 40: docker.agent.v1.AgentService.ListAgents:output_type -> docker.agent.v1.ListAgentsResponse
+	4,  // 41: docker.agent.v1.AgentService.GetAgent:output_type -> docker.agent.v1.AgentConfig
+	5,  // 42: docker.agent.v1.AgentService.GetAgentToolCount:output_type -> docker.agent.v1.AgentToolCountResponse
+	7,  // 43: docker.agent.v1.AgentService.ListSessions:output_type -> docker.agent.v1.ListSessionsResponse
+	9,  // 44: docker.agent.v1.AgentService.GetSession:output_type -> docker.agent.v1.Session
+	9,  // 45: docker.agent.v1.AgentService.CreateSession:output_type -> docker.agent.v1.Session
+	0,  // 46: docker.agent.v1.AgentService.DeleteSession:output_type -> docker.agent.v1.Empty
+	12, // 47: docker.agent.v1.AgentService.UpdateSessionTitle:output_type -> docker.agent.v1.UpdateSessionTitleResponse
+	12, // 48: docker.agent.v1.AgentService.RegenerateSessionTitle:output_type -> docker.agent.v1.UpdateSessionTitleResponse
+	0,  // 49: docker.agent.v1.AgentService.ResumeSession:output_type -> docker.agent.v1.Empty
+	0,  // 50: docker.agent.v1.AgentService.ResumeElicitation:output_type -> docker.agent.v1.Empty
+	18, // 51: docker.agent.v1.AgentService.RunAgent:output_type -> docker.agent.v1.Event
+	18, // 52: docker.agent.v1.AgentService.StreamSession:output_type -> docker.agent.v1.Event
+	40, // [40:53] is the sub-list for method output_type
+	27, // [27:40] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
+}
+
+func init() { file_pkg_api_agentv1_agent_proto_init() }
+func file_pkg_api_agentv1_agent_proto_init() {
+	if File_pkg_api_agentv1_agent_proto != nil {
+		return
+	}
+	file_pkg_api_agentv1_agent_proto_msgTypes[17].OneofWrappers = []any{
+		(*SessionStreamRequest_Run)(nil),
+		(*SessionStreamRequest_Resume)(nil),
+		(*SessionStreamRequest_Elicitation)(nil),
+	}
+	file_pkg_api_agentv1_agent_proto_msgTypes[18].OneofWrappers = []any{
+		(*Event_StreamStarted)(nil),
+		(*Event_StreamStopped)(nil),
+		(*Event_AgentChoice)(nil),
+		(*Event_AgentChoiceReasoning)(nil),
+		(*Event_ToolCall)(nil),
+		(*Event_PartialToolCall)(nil),
+		(*Event_ToolCallConfirmation)(nil),
+		(*Event_ToolCallResponse)(nil),
+		(*Event_Error)(nil),
+		(*Event_ElicitationRequest)(nil),
+		(*Event_MaxIterationsReached)(nil),
+		(*Event_SessionTitle)(nil),
+		(*Event_Warning)(nil),
+		(*Event_Other)(nil),
+	}
+	file_pkg_api_agentv1_agent_proto_msgTypes[23].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_api_agentv1_agent_proto_rawDesc), len(file_pkg_api_agentv1_agent_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   34,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_api_agentv1_agent_proto_goTypes,
+		DependencyIndexes: file_pkg_api_agentv1_agent_proto_depIdxs,
+		MessageInfos:      file_pkg_api_agentv1_agent_proto_msgTypes,
+	}.Build()
+	File_pkg_api_agentv1_agent_proto = out.File
+	file_pkg_api_agentv1_agent_proto_goTypes = nil
+	file_pkg_api_agentv1_agent_proto_depIdxs = nil
+}
@@ -124,6 +124,11 @@ type SessionsResponse struct {
 	WorkingDir   string `json:"working_dir,omitempty"`
 }
 
+// SessionItemsResponse represents a page of the items of a session
+type SessionItemsResponse struct {
+	Items []session.Item `json:"items"`
+}
+
 // SessionResponse represents a detailed session
 type SessionResponse struct {
 	ID            string                     `json:"id"`
@@ -135,7 +140,14 @@ type SessionResponse struct {
 	InputTokens   int64                      `json:"input_tokens"`
 	OutputTokens  int64                      `json:"output_tokens"`
 	WorkingDir    string                     `json:"working_dir,omitempty"`
+	WorkingDirs   []string                   `json:"working_dirs,omitempty"`
 	Permissions   *session.PermissionsConfig `json:"permissions,omitempty"`
+	Artifacts     []session.Artifact         `json:"artifacts,omitempty"`
+	// Blackboard holds the findings the agents of the team shared.
+	Blackboard []session.BlackboardEntry `json:"blackboard,omitempty"`
+	// Records are the tool approvals, elicitations, escalations, model
+	// switches and compactions of the session.
+	Records []*session.Record `json:"records,omitempty"`
 }
 
 // UpdateSessionPermissionsRequest represents a request to update session permissions.
@@ -148,6 +160,9 @@ type ResumeSessionRequest struct {
 	Confirmation string `json:"confirmation"`
 	Reason       string `json:"reason,omitempty"`    // e.g reason for tool call rejection
 	ToolName     string `json:"tool_name,omitempty"` // tool name for approve-tool confirmation
+	// Iterations is how many more iterations a run that reached its
+	// max_iterations limit may take once approved (default 10).
+	Iterations int `json:"iterations,omitempty"`
 }
 
 // DesktopTokenResponse represents the response from getting a desktop token
@@ -155,6 +170,18 @@ type DesktopTokenResponse struct {
 	Token string `json:"token"`
 }
 
+// ShareSessionResponse is the read-only link of a shared session.
+type ShareSessionResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// SetSessionMemberRequest gives a role in a shared session to a user:
+// "editor", "reviewer" or "viewer".
+type SetSessionMemberRequest struct {
+	Role string `json:"role"`
+}
+
 // ResumeElicitationRequest represents a request to resume with an elicitation response
 type ResumeElicitationRequest struct {
 	Action  string         `json:"action"`  // "accept", "decline", or "cancel"
@@ -2,11 +2,9 @@ package app
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"os/exec"
 	"slices"
 	"strings"
@@ -33,8 +31,10 @@ type App struct {
 	runtime                runtime.Runtime
 	session                *session.Session
 	firstMessage           *string
-	firstMessageAttach     string
+	firstMessageAttach     []string
 	queuedMessages         []string
+	startupWarnings        []string
+	continueIterations     int
 	events                 chan tea.Msg
 	throttleDuration       time.Duration
 	cancel                 context.CancelFunc
@@ -54,10 +54,10 @@ func WithFirstMessage(msg string) Opt {
 	}
 }
 
-// WithFirstMessageAttachment sets the attachment path for the first message.
-func WithFirstMessageAttachment(path string) Opt {
+// WithFirstMessageAttachment sets the attachment paths for the first message.
+func WithFirstMessageAttachment(paths ...string) Opt {
 	return func(a *App) {
-		a.firstMessageAttach = path
+		a.firstMessageAttach = paths
 	}
 }
 
@@ -77,6 +77,21 @@ func WithQueuedMessages(msgs []string) Opt {
 	}
 }
 
+// WithStartupWarnings sets warnings to show to the user when the TUI starts.
+func WithStartupWarnings(warnings ...string) Opt {
+	return func(a *App) {
+		a.startupWarnings = append(a.startupWarnings, warnings...)
+	}
+}
+
+// WithContinueIterations continues the run of the session for this many
+// iterations when the TUI starts, before the first message is sent.
+func WithContinueIterations(iterations int) Opt {
+	return func(a *App) {
+		a.continueIterations = iterations
+	}
+}
+
 // WithTitleGenerator sets the title generator for local title generation.
 // If not set, title generation will be handled by the runtime (for remote) or skipped.
 func WithTitleGenerator(gen *sessiontitle.Generator) Opt {
@@ -97,6 +112,10 @@ func New(ctx context.Context, rt runtime.Runtime, sess *session.Session, opts ..
 		opt(app)
 	}
 
+	for _, warning := range app.startupWarnings {
+		app.events <- runtime.Warning(warning, "")
+	}
+
 	// Emit startup info (agent, team, tools) through the events channel.
 	// This runs in the background so the TUI can start immediately while
 	// slow operations (like MCP tool loading) complete asynchronously.
@@ -138,18 +157,37 @@ func New(ctx context.Context, rt runtime.Runtime, sess *session.Session, opts ..
 		})
 	}
 
+	// Report progress when switching to a local model that must be pulled first.
+	if mps, ok := rt.(runtime.ModelPullSubscriber); ok {
+		mps.OnModelPullProgress(func(event runtime.Event) {
+			select {
+			case app.events <- event:
+			case <-ctx.Done():
+			}
+		})
+	}
+
 	return app
 }
 
 func (a *App) SendFirstMessage() tea.Cmd {
+	var cmds []tea.Cmd
+	if a.continueIterations > 0 {
+		cmds = append(cmds, func() tea.Msg {
+			return messages.ContinueRunMsg{Iterations: a.continueIterations}
+		})
+	}
 	if a.firstMessage == nil {
-		return nil
+		if len(cmds) == 0 {
+			return nil
+		}
+		return tea.Sequence(cmds...)
 	}
 
-	cmds := []tea.Cmd{
+	cmds = append(cmds,
 		func() tea.Msg {
 			// Use the shared PrepareUserMessage function for consistent attachment handling
-			userMsg := cli.PrepareUserMessage(context.Background(), a.runtime, *a.firstMessage, a.firstMessageAttach)
+			userMsg := cli.PrepareUserMessage(context.Background(), a.runtime, *a.firstMessage, cli.Attachments{Paths: a.firstMessageAttach})
 
 			// If the message has multi-content (attachments), we need to handle it specially
 			if len(userMsg.Message.MultiContent) > 0 {
@@ -162,7 +200,7 @@ func (a *App) SendFirstMessage() tea.Cmd {
 				Content: userMsg.Message.Content,
 			}
 		},
-	}
+	)
 
 	// Queue additional messages to be sent after the first one.
 	// The TUI's message queue will hold them until the agent finishes
@@ -345,100 +383,39 @@ func (a *App) Run(ctx context.Context, cancel context.CancelFunc, message string
 	}()
 }
 
+// Continue runs the agent loop without a new message, for at most the given
+// number of iterations. It continues a run that paused at its max_iterations
+// limit, with its pending tool results.
+func (a *App) Continue(ctx context.Context, cancel context.CancelFunc, iterations int) {
+	a.cancel = cancel
+	a.session.IterationBudget = iterations
+
+	go func() {
+		for event := range a.runtime.RunStream(ctx, a.session) {
+			if ctx.Err() != nil {
+				if _, ok := event.(*runtime.StreamStoppedEvent); ok {
+					a.sendEvent(context.Background(), event)
+				}
+				continue
+			}
+			a.sendEvent(ctx, event)
+		}
+	}()
+}
+
 // processFileAttachment reads a file from disk, classifies it, and either
 // appends its text content to textBuilder or adds a binary part to binaryParts.
 func (a *App) processFileAttachment(ctx context.Context, att messages.Attachment, textBuilder *strings.Builder, binaryParts *[]chat.MessagePart) {
-	absPath := att.FilePath
-
-	fi, err := os.Stat(absPath)
+	attachment, err := chat.AttachFile(att.FilePath)
 	if err != nil {
-		var reason string
-		switch {
-		case os.IsNotExist(err):
-			reason = "file does not exist"
-		case os.IsPermission(err):
-			reason = "permission denied"
-		default:
-			reason = fmt.Sprintf("cannot access file: %v", err)
-		}
-		slog.Warn("skipping attachment", "path", absPath, "reason", reason)
-		a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: %s", att.Name, reason), ""))
+		slog.Warn("skipping attachment", "path", att.FilePath, "reason", err)
+		a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: %s", att.Name, err), ""))
 		return
 	}
 
-	if !fi.Mode().IsRegular() {
-		slog.Warn("skipping attachment: not a regular file", "path", absPath, "mode", fi.Mode().String())
-		a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: not a regular file", att.Name), ""))
-		return
-	}
-
-	const maxAttachmentSize = 100 * 1024 * 1024 // 100MB
-	if fi.Size() > maxAttachmentSize {
-		slog.Warn("skipping attachment: file too large", "path", absPath, "size", fi.Size(), "max", maxAttachmentSize)
-		a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: file too large (max 100MB)", att.Name), ""))
-		return
-	}
-
-	mimeType := chat.DetectMimeType(absPath)
-
-	switch {
-	case chat.IsTextFile(absPath):
-		if fi.Size() > chat.MaxInlineFileSize {
-			slog.Warn("skipping attachment: text file too large to inline", "path", absPath, "size", fi.Size(), "max", chat.MaxInlineFileSize)
-			a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: text file too large to inline (max 5MB)", att.Name), ""))
-			return
-		}
-		content, err := chat.ReadFileForInline(absPath)
-		if err != nil {
-			slog.Warn("skipping attachment: failed to read file", "path", absPath, "error", err)
-			a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: failed to read file", att.Name), ""))
-			return
-		}
-		textBuilder.WriteString("\n\n")
-		textBuilder.WriteString(content)
-
-	case chat.IsSupportedMimeType(mimeType):
-		if chat.IsImageMimeType(mimeType) {
-			// Read, resize if needed, and inline as base64 data URL.
-			// This works across all providers (not just Anthropic's File API).
-			imgData, readErr := os.ReadFile(absPath)
-			if readErr != nil {
-				slog.Warn("skipping attachment: failed to read image", "path", absPath, "error", readErr)
-				a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: failed to read image", att.Name), ""))
-				return
-			}
-			resized, resizeErr := chat.ResizeImage(imgData, mimeType)
-			if resizeErr != nil {
-				// Don't bypass security checks - reject the file if resize failed
-				slog.Warn("skipping attachment: image resize failed", "path", absPath, "error", resizeErr)
-				a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: %s", att.Name, resizeErr), ""))
-				return
-			}
-			dataURL := fmt.Sprintf("data:%s;base64,%s", resized.MimeType, base64.StdEncoding.EncodeToString(resized.Data))
-			*binaryParts = append(*binaryParts, chat.MessagePart{
-				Type: chat.MessagePartTypeImageURL,
-				ImageURL: &chat.MessageImageURL{
-					URL:    dataURL,
-					Detail: chat.ImageURLDetailAuto,
-				},
-			})
-			if note := chat.FormatDimensionNote(resized); note != "" {
-				textBuilder.WriteString("\n" + note)
-			}
-		} else {
-			// Non-image supported types (e.g. PDF) use the file upload path.
-			*binaryParts = append(*binaryParts, chat.MessagePart{
-				Type: chat.MessagePartTypeFile,
-				File: &chat.MessageFile{
-					Path:     absPath,
-					MimeType: mimeType,
-				},
-			})
-		}
-
-	default:
-		slog.Warn("skipping attachment: unsupported file type", "path", absPath, "mime_type", mimeType)
-		a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: unsupported file type", att.Name), ""))
+	textBuilder.WriteString(attachment.Text)
+	if attachment.Part != nil {
+		*binaryParts = append(*binaryParts, *attachment.Part)
 	}
 }
 
@@ -551,6 +528,7 @@ func (a *App) NewSession() {
 		a.cancel()
 		a.cancel = nil
 	}
+	a.endSession(context.Background())
 	// Preserve user-controlled session flags (like /think toggle)
 	// so they don't reset to default on /new
 	var opts []session.Opt
@@ -558,6 +536,7 @@ func (a *App) NewSession() {
 		opts = append(opts,
 			session.WithThinking(a.session.Thinking),
 			session.WithToolsApproved(a.session.ToolsApproved),
+			session.WithSimulate(a.session.Simulate),
 			session.WithHideToolResults(a.session.HideToolResults),
 			session.WithWorkingDir(a.session.WorkingDir),
 		)
@@ -565,7 +544,18 @@ func (a *App) NewSession() {
 	a.session = session.New(opts...)
 	// Clear first message so it won't be re-sent on re-init
 	a.firstMessage = nil
-	a.firstMessageAttach = ""
+	a.firstMessageAttach = nil
+}
+
+// endSession releases the resources of the current session, such as the
+// background jobs started by its shell tools, before switching to another one.
+func (a *App) endSession(ctx context.Context) {
+	if a.session == nil {
+		return
+	}
+	if ender, ok := a.runtime.(runtime.SessionEnder); ok {
+		ender.EndSession(ctx, a.session)
+	}
 }
 
 func (a *App) Session() *session.Session {
@@ -632,12 +622,20 @@ func (a *App) SetCurrentAgentModel(ctx context.Context, modelRef string) error {
 	}
 
 	agentName := a.runtime.CurrentAgentName()
+	previousModel := a.session.AgentModelOverrides[agentName]
 
 	// Set the model override on the runtime (empty modelRef clears the override)
 	if err := modelSwitcher.SetAgentModel(ctx, agentName, modelRef); err != nil {
 		return err
 	}
 
+	record := &session.Record{
+		AgentName:   agentName,
+		CreatedAt:   time.Now(),
+		ModelSwitch: &session.ModelSwitchRecord{From: previousModel, To: modelRef, Reason: "user"},
+	}
+	a.session.AddRecord(record)
+
 	// Update the session's model overrides
 	if modelRef == "" {
 		// Clear the override - remove from map
@@ -662,6 +660,9 @@ func (a *App) SetCurrentAgentModel(ctx context.Context, modelRef string) error {
 		if err := store.UpdateSession(ctx, a.session); err != nil {
 			return fmt.Errorf("failed to persist model override: %w", err)
 		}
+		if err := store.AddRecord(ctx, a.session.ID, record); err != nil {
+			return fmt.Errorf("failed to persist model switch: %w", err)
+		}
 		slog.Debug("Persisted session with model override", "session_id", a.session.ID, "overrides", a.session.AgentModelOverrides)
 	}
 
@@ -831,10 +832,13 @@ func (a *App) ReplaceSession(ctx context.Context, sess *session.Session) {
 		a.cancel()
 		a.cancel = nil
 	}
+	if a.session == nil || a.session.ID != sess.ID {
+		a.endSession(ctx)
+	}
 	a.session = sess
 	// Clear first message so it won't be re-sent on re-init
 	a.firstMessage = nil
-	a.firstMessageAttach = ""
+	a.firstMessageAttach = nil
 
 	// Apply any stored model overrides from the session
 	a.applySessionModelOverrides(ctx, sess)
@@ -1133,8 +1137,31 @@ func (a *App) RegenerateSessionTitle(ctx context.Context) error {
 		return nil
 	}
 
-	// For remote runtime, title regeneration is not yet supported
-	// (the server would need to implement this)
-	slog.Debug("Title regeneration not available for remote runtime", "session_id", a.session.ID)
+	// For remote runtime, the server generates the title
+	if regenerator, ok := a.runtime.(titleRegenerator); ok {
+		a.titleGenerating.Store(true)
+		go func() {
+			defer a.titleGenerating.Store(false)
+
+			title, err := regenerator.RegenerateSessionTitle(ctx, a.session)
+			if err != nil {
+				slog.Error("Failed to regenerate session title", "session_id", a.session.ID, "error", err)
+				title = ""
+			}
+			select {
+			case a.events <- runtime.SessionTitle(a.session.ID, title):
+			case <-ctx.Done():
+			}
+		}()
+		return nil
+	}
+
+	slog.Debug("Title regeneration not available", "session_id", a.session.ID)
 	return errors.New("title regeneration not available")
 }
+
+// titleRegenerator is implemented by runtimes that generate titles
+// themselves, like remote runtimes.
+type titleRegenerator interface {
+	RegenerateSessionTitle(ctx context.Context, sess *session.Session) (string, error)
+}
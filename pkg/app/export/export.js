@@ -8,6 +8,11 @@ function toggle(header) {
     if (chevronDown) chevronDown.style.display = isHidden ? 'block' : 'none';
 }
 
+document.addEventListener('click', (e) => {
+    const header = e.target.closest('[data-toggle]');
+    if (header) toggle(header);
+});
+
 document.addEventListener('keydown', (e) => {
     if (e.key === 'Escape') {
         document.querySelectorAll('.collapsible-content').forEach(el => el.style.display = 'none');
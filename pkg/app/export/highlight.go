@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// highlightStyle is the chroma style of the code blocks, matching the dark
+// theme of the export.
+var highlightStyle = styles.Get("github-dark")
+
+var (
+	// codeFormatter renders code blocks with CSS classes, so that the
+	// colors are defined once in the page.
+	codeFormatter = chromahtml.New(chromahtml.WithClasses(true))
+	// inlineFormatter renders code inside the <pre> of a tool call.
+	inlineFormatter = chromahtml.New(chromahtml.WithClasses(true), chromahtml.PreventSurroundingPre(true))
+)
+
+// highlightCSS returns the CSS classes of the syntax highlighting.
+func highlightCSS() string {
+	var buf bytes.Buffer
+	if err := codeFormatter.WriteCSS(&buf, highlightStyle); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// highlight writes code highlighted as the given language, or guessed from
+// the code when the language is empty or unknown.
+func highlight(w io.Writer, formatter *chromahtml.Formatter, code, language string) error {
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		lexer = lexers.Analyse(code)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return err
+	}
+	return formatter.Format(w, highlightStyle, iterator)
+}
+
+// highlightJSON returns the HTML of a tool call argument or result,
+// highlighted when it's JSON and escaped otherwise.
+func highlightJSON(s string) template.HTML {
+	if !strings.HasPrefix(strings.TrimSpace(s), "{") && !strings.HasPrefix(strings.TrimSpace(s), "[") {
+		return template.HTML(template.HTMLEscapeString(s)) //nolint:gosec // Content is escaped
+	}
+	var buf bytes.Buffer
+	if err := highlight(&buf, inlineFormatter, s, "json"); err != nil {
+		return template.HTML(template.HTMLEscapeString(s)) //nolint:gosec // Content is escaped
+	}
+	return template.HTML(buf.String()) //nolint:gosec // chroma escapes the tokens
+}
+
+// codeBlockRenderer renders the fenced code blocks of the messages with
+// syntax highlighting.
+type codeBlockRenderer struct{}
+
+func (r codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+}
+
+func (codeBlockRenderer) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	block := node.(*ast.FencedCodeBlock)
+	var code strings.Builder
+	lines := block.Lines()
+	for i := range lines.Len() {
+		line := lines.At(i)
+		code.Write(line.Value(source))
+	}
+
+	if err := highlight(w, codeFormatter, code.String(), string(block.Language(source))); err != nil {
+		_, _ = w.WriteString("<pre><code>" + template.HTMLEscapeString(code.String()) + "</code></pre>\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
@@ -3,7 +3,10 @@ package export
 
 import (
 	"bytes"
+	"cmp"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,7 +19,8 @@ import (
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
 
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/session"
@@ -31,6 +35,13 @@ var htmlTemplate string
 //go:embed export.js
 var jsCode string
 
+// ScriptHash is the Content-Security-Policy source that allows the script of
+// the exported pages, and no other script.
+func ScriptHash() string {
+	sum := sha256.Sum256([]byte(jsCode))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
 // SVG icons used in the template.
 const (
 	svgChevronRight      = `<svg class="chevron-right size-3" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="m9 18 6-6-6-6"/></svg>`
@@ -40,13 +51,15 @@ const (
 	svgCheckCircle       = `<svg class="size-3 text-tui-green" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><circle cx="12" cy="12" r="10"/><path d="m9 12 2 2 4-4"/></svg>`
 )
 
-// markdown is the goldmark Markdown parser with common extensions.
+// markdown is the goldmark Markdown parser with common extensions. The raw
+// HTML of messages is omitted: models and tools could otherwise inject
+// scripts into the exported pages.
 var markdown = goldmark.New(
 	goldmark.WithExtensions(
 		extension.GFM, // GitHub Flavored Markdown (tables, strikethrough, etc.)
 	),
 	goldmark.WithRendererOptions(
-		html.WithUnsafe(), // Allow raw HTML in markdown
+		renderer.WithNodeRenderers(util.Prioritized(codeBlockRenderer{}, 100)), // Highlight code blocks
 	),
 )
 
@@ -69,7 +82,12 @@ type Message struct {
 	ToolCallID       string
 	ToolCalls        []ToolCall
 	AgentName        string
-	Implicit         bool
+	// User is the user who sent a user message, in shared sessions.
+	User     string
+	Implicit bool
+	// Record describes an interaction that isn't a message, like a tool
+	// approval or a model switch. The other fields are empty then.
+	Record string
 }
 
 // ToolCall represents a tool invocation.
@@ -91,10 +109,25 @@ func SessionToFile(sess *session.Session, agentDescription, filename string) (st
 	return ToFile(data, filename)
 }
 
+// SessionHTML exports a session to a self-contained HTML page.
+func SessionHTML(sess *session.Session, agentDescription string) (string, error) {
+	if sess == nil {
+		return "", errors.New("no session to export")
+	}
+	data := sessionToData(sess)
+	data.AgentDescription = agentDescription
+	return Generate(data)
+}
+
 func sessionToData(sess *session.Session) SessionData {
-	messages := sess.GetAllMessages()
-	exportMessages := make([]Message, len(messages))
-	for i, msg := range messages {
+	history := sess.GetHistory()
+	exportMessages := make([]Message, len(history))
+	for i, item := range history {
+		if item.IsRecord() {
+			exportMessages[i] = Message{AgentName: item.Record.AgentName, Record: item.Record.String()}
+			continue
+		}
+		msg := item.Message
 		toolCalls := make([]ToolCall, len(msg.Message.ToolCalls))
 		for j, tc := range msg.Message.ToolCalls {
 			toolCalls[j] = ToolCall{
@@ -110,6 +143,7 @@ func sessionToData(sess *session.Session) SessionData {
 			ToolCallID:       msg.Message.ToolCallID,
 			ToolCalls:        toolCalls,
 			AgentName:        msg.AgentName,
+			User:             msg.User,
 			Implicit:         msg.Implicit,
 		}
 	}
@@ -218,8 +252,8 @@ type messageViewData struct {
 // toolCallViewData holds data for rendering a tool call.
 type toolCallViewData struct {
 	Name              string
-	Arguments         string
-	Result            string
+	Arguments         template.HTML
+	Result            template.HTML
 	HasArguments      bool
 	HasResult         bool
 	ChevronRightMuted template.HTML
@@ -250,7 +284,7 @@ var messageTemplate = template.Must(template.New("message").Parse(`
     <div class="flex-1 flex flex-col gap-3 overflow-hidden text-sm">
         {{if .HasReasoning}}
         <div class="border-l-2 border-tui-purple bg-tui-purple/5">
-            <div class="flex items-center gap-2 px-3 py-2 cursor-pointer text-xs font-bold text-tui-purple select-none hover:bg-tui-purple/10" onclick="toggle(this)">
+            <div class="flex items-center gap-2 px-3 py-2 cursor-pointer text-xs font-bold text-tui-purple select-none hover:bg-tui-purple/10" data-toggle>
                 {{.ChevronRightIcon}}{{.ChevronDownIcon}} Thinking
             </div>
             <div class="collapsible-content p-3 pl-5 text-muted-foreground text-xs border-t border-tui-purple/30" style="display: none;">{{.ReasoningHTML}}</div>
@@ -267,10 +301,19 @@ var messageTemplate = template.Must(template.New("message").Parse(`
 {{end}}
 `))
 
+// recordTemplate is the template for rendering the records of a session,
+// like tool approvals and model switches.
+var recordTemplate = template.Must(template.New("record").Parse(`
+<div class="flex w-full py-2 border-b border-border flex-col gap-1 sm:flex-row sm:items-start sm:gap-3">
+    <div class="hidden sm:block sm:w-14 shrink-0"></div>
+    <div class="flex-1 text-xs italic text-muted-foreground">• {{.}}</div>
+</div>
+`))
+
 // toolCallTemplate is the template for rendering tool calls.
 var toolCallTemplate = template.Must(template.New("toolcall").Parse(`
 <div class="text-sm">
-    <div class="flex w-full items-center gap-2 py-1 cursor-pointer select-none hover:bg-secondary/50 transition-colors" onclick="toggle(this)">
+    <div class="flex w-full items-center gap-2 py-1 cursor-pointer select-none hover:bg-secondary/50 transition-colors" data-toggle>
         {{.ChevronRightMuted}}{{.ChevronDownMuted}}
         <span class="text-tui-purple">⚡</span>
         <span class="font-medium text-tui-blue">{{.Name}}</span>
@@ -327,6 +370,14 @@ func Generate(data SessionData) (string, error) {
 		if msg.Implicit {
 			continue
 		}
+		if msg.Record != "" {
+			var buf bytes.Buffer
+			if err := recordTemplate.Execute(&buf, msg.Record); err != nil {
+				return "", fmt.Errorf("failed to render record: %w", err)
+			}
+			messagesBuilder.WriteString(buf.String())
+			continue
+		}
 		// Skip tool messages - they're rendered inline with their tool calls
 		if msg.Role == chat.MessageRoleTool {
 			continue
@@ -356,7 +407,7 @@ func Generate(data SessionData) (string, error) {
 
 	tplData := templateData{
 		Title:            title,
-		CSS:              template.CSS(cssStyles),
+		CSS:              template.CSS(cssStyles + highlightCSS()),
 		JS:               template.JS(jsCode),
 		FormattedDate:    data.CreatedAt.Format("January 2, 2006 at 3:04 PM"),
 		SidebarDate:      data.CreatedAt.Format("Jan 2, 2006"),
@@ -379,7 +430,7 @@ func Generate(data SessionData) (string, error) {
 
 func getSender(msg Message) string {
 	if msg.Role == chat.MessageRoleUser {
-		return "you"
+		return cmp.Or(msg.User, "you")
 	}
 	if msg.AgentName != "" {
 		return msg.AgentName
@@ -422,7 +473,7 @@ func renderUserMessage(msg Message, showLabel bool) (string, error) {
 
 	data := messageViewData{
 		IsUser:       true,
-		LabelName:    "you",
+		LabelName:    getSender(msg),
 		LabelClasses: "bg-tui-yellow/20 text-tui-yellow",
 		ShowLabel:    showLabel,
 		ContentHTML:  template.HTML(content), //nolint:gosec // Content is escaped above
@@ -498,8 +549,8 @@ func renderAssistantMessage(msg Message, toolResults map[string]string, showLabe
 func renderToolCall(name, args, result string) (string, error) {
 	data := toolCallViewData{
 		Name:              name,
-		Arguments:         args,
-		Result:            result,
+		Arguments:         highlightJSON(args),
+		Result:            highlightJSON(result),
 		HasArguments:      args != "" && args != "{}" && args != "null",
 		HasResult:         result != "",
 		ChevronRightMuted: template.HTML(svgChevronRightMuted), //nolint:gosec // Constant SVG
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+)
+
+func TestGenerate_HighlightsCode(t *testing.T) {
+	t.Parallel()
+
+	page, err := Generate(SessionData{
+		Messages: []Message{
+			{Role: chat.MessageRoleUser, Content: "Print <hello>", User: "alice"},
+			{
+				Role:      chat.MessageRoleAssistant,
+				AgentName: "root",
+				Content:   "```go\nfunc main() {}\n```",
+				ToolCalls: []ToolCall{{ID: "call_1", Name: "shell", Arguments: `{"cmd": "go run ."}`}},
+			},
+			{Role: chat.MessageRoleTool, ToolCallID: "call_1", Content: "<hello>"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, page, `<span class="kd">func</span>`, "code blocks are highlighted")
+	assert.Contains(t, page, `<span class="nt">&#34;cmd&#34;</span>`, "tool call arguments are highlighted")
+	assert.Contains(t, page, "Print &lt;hello&gt;")
+	assert.Contains(t, page, "&lt;hello&gt;</pre>", "tool results are escaped")
+	assert.Contains(t, page, ">alice</span>", "user messages are labeled with their user")
+	assert.Contains(t, page, ".chroma")
+}
+
+func TestGenerate_OmitsRawHTML(t *testing.T) {
+	t.Parallel()
+
+	page, err := Generate(SessionData{
+		Messages: []Message{
+			{Role: chat.MessageRoleAssistant, Content: "Done.\n\n<script>alert(1)</script>\n\n<img src=x onerror=alert(1)> [link](javascript:alert(1))"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, page, "alert(1)</script>")
+	assert.NotContains(t, page, "onerror=")
+	assert.NotContains(t, page, `href="javascript:`)
+	assert.Equal(t, 1, strings.Count(page, "<script>"), "only the page's own script")
+
+	// The script is allowed by its hash.
+	script := page[strings.Index(page, "<script>")+len("<script>") : strings.Index(page, "</script>")]
+	sum := sha256.Sum256([]byte(script))
+	assert.Equal(t, "'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'", ScriptHash())
+}
+
+func TestUpload(t *testing.T) {
+	t.Setenv("DOCKER_AGENT_SHARE_TOKEN", "secret")
+
+	var path, auth, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	url, err := Upload(t.Context(), server.URL+"/transcripts/", "abc.html", "<html></html>")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/transcripts/abc.html", url)
+	assert.Equal(t, "/transcripts/abc.html", path)
+	assert.Equal(t, "Bearer secret", auth)
+	assert.Equal(t, "<html></html>", body)
+
+	_, err = Upload(t.Context(), server.URL+"/missing\x7f", "abc.html", "")
+	require.Error(t, err)
+}
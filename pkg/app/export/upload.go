@@ -0,0 +1,54 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/httpclient"
+)
+
+// uploadTimeout is how long an upload of a transcript can take.
+const uploadTimeout = time.Minute
+
+// Upload PUTs an HTML transcript to a URL and returns the URL it can be read
+// at. A URL ending with a slash is a directory: the file name is appended to
+// it. DOCKER_AGENT_SHARE_TOKEN, if set, is sent as a bearer token.
+func Upload(ctx context.Context, target, filename, page string) (string, error) {
+	if strings.HasSuffix(target, "/") {
+		target += filename
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, strings.NewReader(page))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/html; charset=utf-8")
+	if token := os.Getenv("DOCKER_AGENT_SHARE_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpclient.NewHTTPClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("upload target returned %s", resp.Status)
+	}
+
+	// Servers storing the file elsewhere tell where.
+	if location, err := resp.Location(); err == nil {
+		return location.String(), nil
+	}
+	return target, nil
+}
@@ -0,0 +1,56 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoPlayer is returned when no command to play audio is available.
+var ErrNoPlayer = errors.New("no audio player available")
+
+// Play plays WAV audio and blocks until it is done or ctx is cancelled.
+func Play(ctx context.Context, wav []byte) error {
+	f, err := os.CreateTemp("", "cagent-speech-*.wav")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(wav); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	cmd, err := playCommand(ctx, f.Name())
+	if err != nil {
+		return err
+	}
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+func playCommand(ctx context.Context, path string) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.CommandContext(ctx, "afplay", path), nil
+	case "windows":
+		script := `(New-Object Media.SoundPlayer '` + strings.ReplaceAll(path, "'", "''") + `').PlaySync()`
+		return exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script), nil
+	default:
+		for _, player := range []string{"paplay", "aplay", "play"} {
+			if p, err := exec.LookPath(player); err == nil {
+				return exec.CommandContext(ctx, p, path), nil
+			}
+		}
+		return nil, ErrNoPlayer
+	}
+}
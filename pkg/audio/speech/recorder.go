@@ -0,0 +1,132 @@
+package speech
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/audio/capture"
+)
+
+// SampleRate is the sample rate of recordings, supported by all
+// transcription backends.
+const SampleRate = capture.SampleRate24000
+
+// ErrNoRecorder is returned when neither the native audio capture nor a
+// recording command (parecord, arecord or sox) is available.
+var ErrNoRecorder = errors.New("no audio recorder available: install PulseAudio (parecord), ALSA (arecord) or SoX")
+
+// Recorder records speech from the microphone, for push-to-talk. It uses the
+// native audio capture where supported and a recording command otherwise.
+type Recorder struct {
+	mu        sync.Mutex
+	pcm       bytes.Buffer
+	capturer  *capture.Capturer
+	cmd       *exec.Cmd
+	recording bool
+}
+
+// NewRecorder creates a new recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{capturer: capture.NewCapturer(SampleRate)}
+}
+
+// Start starts recording.
+func (r *Recorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recording {
+		return errors.New("already recording")
+	}
+	r.pcm.Reset()
+
+	err := r.capturer.Start("", r.write)
+	if errors.Is(err, capture.ErrNotSupported) {
+		err = r.startCommand()
+	}
+	if err != nil {
+		return err
+	}
+	r.recording = true
+	return nil
+}
+
+// Stop stops recording and returns the recorded speech as WAV audio.
+func (r *Recorder) Stop() ([]byte, error) {
+	r.mu.Lock()
+	if !r.recording {
+		r.mu.Unlock()
+		return nil, errors.New("not recording")
+	}
+	r.recording = false
+	cmd := r.cmd
+	r.cmd = nil
+	r.mu.Unlock()
+
+	if cmd != nil {
+		// The recording commands flush their output and exit on interrupt.
+		_ = cmd.Process.Signal(os.Interrupt)
+		_ = cmd.Wait()
+	} else if err := r.capturer.Stop(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return EncodeWAV(bytes.Clone(r.pcm.Bytes()), SampleRate), nil
+}
+
+// IsRecording returns true while recording.
+func (r *Recorder) IsRecording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recording
+}
+
+func (r *Recorder) write(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pcm.Write(data)
+}
+
+// writerFunc adapts a function to io.Writer, for the output of recording
+// commands.
+type writerFunc func([]byte)
+
+func (f writerFunc) Write(data []byte) (int, error) {
+	f(data)
+	return len(data), nil
+}
+
+func (r *Recorder) startCommand() error {
+	cmd, err := recordCommand()
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = writerFunc(r.write)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	r.cmd = cmd
+	return nil
+}
+
+// recordCommand returns a command that records mono, 16-bit PCM from the
+// default input device to its standard output.
+func recordCommand() (*exec.Cmd, error) {
+	rate := strconv.Itoa(SampleRate)
+	if path, err := exec.LookPath("parecord"); err == nil {
+		return exec.Command(path, "--raw", "--format=s16le", "--channels=1", "--rate="+rate), nil
+	}
+	if path, err := exec.LookPath("arecord"); err == nil {
+		return exec.Command(path, "-q", "-t", "raw", "-f", "S16_LE", "-c", "1", "-r", rate), nil
+	}
+	if path, err := exec.LookPath("sox"); err == nil {
+		return exec.Command(path, "-q", "-d", "-t", "raw", "-b", "16", "-e", "signed-integer", "-c", "1", "-r", rate, "-"), nil
+	}
+	return nil, ErrNoRecorder
+}
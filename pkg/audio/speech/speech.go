@@ -0,0 +1,94 @@
+// Package speech provides the voice mode of the TUI: transcription of
+// recorded speech and playback of synthesized speech, backed by a model
+// provider or by a local whisper.cpp server.
+package speech
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider"
+)
+
+// ProviderWhisperCPP selects a local whisper.cpp server for transcription.
+const ProviderWhisperCPP = "whisper_cpp"
+
+// Default models, used when none is configured.
+const (
+	DefaultTranscriptionProvider = "openai"
+	DefaultTranscriptionModel    = "gpt-4o-mini-transcribe"
+	DefaultSpeechProvider        = "openai"
+	DefaultSpeechModel           = "gpt-4o-mini-tts"
+)
+
+// Transcriber turns recorded speech into text.
+type Transcriber interface {
+	// Transcribe returns the text spoken in the given WAV audio.
+	Transcribe(ctx context.Context, wav []byte) (string, error)
+}
+
+// Synthesizer turns text into speech.
+type Synthesizer interface {
+	// Synthesize returns the given text spoken with the given voice, as WAV
+	// audio.
+	Synthesize(ctx context.Context, text, voice string) ([]byte, error)
+}
+
+// Config selects the model used for transcription or speech synthesis.
+type Config struct {
+	// Provider is the model provider, e.g. "openai", or "whisper_cpp" for
+	// transcription with a local whisper.cpp server.
+	Provider string
+	// Model is the model name, e.g. "whisper-1".
+	Model string
+	// BaseURL overrides the URL of the provider's API.
+	BaseURL string
+}
+
+// NewTranscriber creates the transcriber selected by cfg. Empty fields of
+// cfg default to OpenAI's gpt-4o-mini-transcribe.
+func NewTranscriber(ctx context.Context, cfg Config, env environment.Provider) (Transcriber, error) {
+	if cfg.Provider == ProviderWhisperCPP {
+		return NewWhisperCPP(cfg.BaseURL), nil
+	}
+
+	p, err := newProvider(ctx, cfg, DefaultTranscriptionProvider, DefaultTranscriptionModel, env)
+	if err != nil {
+		return nil, err
+	}
+	transcriber, ok := p.(provider.SpeechToTextProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %q does not support speech-to-text", p.ID())
+	}
+	return transcriber, nil
+}
+
+// NewSynthesizer creates the synthesizer selected by cfg. Empty fields of cfg
+// default to OpenAI's gpt-4o-mini-tts.
+func NewSynthesizer(ctx context.Context, cfg Config, env environment.Provider) (Synthesizer, error) {
+	p, err := newProvider(ctx, cfg, DefaultSpeechProvider, DefaultSpeechModel, env)
+	if err != nil {
+		return nil, err
+	}
+	synthesizer, ok := p.(provider.TextToSpeechProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %q does not support text-to-speech", p.ID())
+	}
+	return synthesizer, nil
+}
+
+func newProvider(ctx context.Context, cfg Config, defaultProvider, defaultModel string, env environment.Provider) (provider.Provider, error) {
+	modelCfg := &latest.ModelConfig{
+		Provider: cmp.Or(cfg.Provider, defaultProvider),
+		Model:    cmp.Or(cfg.Model, defaultModel),
+		BaseURL:  cfg.BaseURL,
+	}
+	p, err := provider.New(ctx, modelCfg, env)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s/%s provider: %w", modelCfg.Provider, modelCfg.Model, err)
+	}
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package speech
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+func TestEncodeWAV(t *testing.T) {
+	pcm := []byte{1, 2, 3, 4}
+	wav := EncodeWAV(pcm, 24000)
+
+	require.Len(t, wav, 44+len(pcm))
+	assert.Equal(t, "RIFF", string(wav[0:4]))
+	assert.Equal(t, uint32(36+len(pcm)), binary.LittleEndian.Uint32(wav[4:8]))
+	assert.Equal(t, "WAVEfmt ", string(wav[8:16]))
+	assert.Equal(t, uint16(1), binary.LittleEndian.Uint16(wav[22:24]))
+	assert.Equal(t, uint32(24000), binary.LittleEndian.Uint32(wav[24:28]))
+	assert.Equal(t, uint32(48000), binary.LittleEndian.Uint32(wav[28:32]))
+	assert.Equal(t, uint16(16), binary.LittleEndian.Uint16(wav[34:36]))
+	assert.Equal(t, "data", string(wav[36:40]))
+	assert.Equal(t, uint32(len(pcm)), binary.LittleEndian.Uint32(wav[40:44]))
+	assert.Equal(t, pcm, wav[44:])
+}
+
+func TestWhisperCPP_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/inference", r.URL.Path)
+		assert.Equal(t, "json", r.FormValue("response_format"))
+
+		file, _, err := r.FormFile("file")
+		if assert.NoError(t, err) {
+			defer file.Close()
+			buf, _ := io.ReadAll(file)
+			assert.Equal(t, "RIFF", string(buf))
+		}
+
+		_, _ = w.Write([]byte(`{"text":" Hello there.\n"}`))
+	}))
+	defer server.Close()
+
+	transcriber, err := NewTranscriber(t.Context(), Config{Provider: ProviderWhisperCPP, BaseURL: server.URL + "/"}, environment.NewMapEnvProvider(nil))
+	require.NoError(t, err)
+
+	text, err := transcriber.Transcribe(t.Context(), []byte("RIFF"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello there.", text)
+}
+
+func TestWhisperCPP_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "no model loaded", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := NewWhisperCPP(server.URL).Transcribe(t.Context(), []byte("RIFF"))
+	require.ErrorContains(t, err, "no model loaded")
+}
+
+func TestNewSynthesizer_Unsupported(t *testing.T) {
+	env := environment.NewMapEnvProvider(map[string]string{"ANTHROPIC_API_KEY": "test-key"})
+
+	_, err := NewSynthesizer(t.Context(), Config{Provider: "anthropic", Model: "claude-sonnet-4-5"}, env)
+	require.ErrorContains(t, err, "does not support text-to-speech")
+}
@@ -0,0 +1,79 @@
+package speech
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/httpclient"
+)
+
+// DefaultWhisperCPPURL is the default address of the whisper.cpp server.
+const DefaultWhisperCPPURL = "http://127.0.0.1:8080"
+
+// WhisperCPP transcribes speech with a local whisper.cpp server, started
+// with `whisper-server`.
+type WhisperCPP struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewWhisperCPP creates a transcriber for the whisper.cpp server at baseURL,
+// or at DefaultWhisperCPPURL when empty.
+func NewWhisperCPP(baseURL string) *WhisperCPP {
+	return &WhisperCPP{
+		baseURL: strings.TrimSuffix(cmp.Or(baseURL, DefaultWhisperCPPURL), "/"),
+		client:  httpclient.NewHTTPClient(),
+	}
+}
+
+// Transcribe posts the audio to the /inference endpoint of the server and
+// returns the transcribed text.
+func (w *WhisperCPP) Transcribe(ctx context.Context, wav []byte) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "speech.wav")
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(wav); err != nil {
+		return "", err
+	}
+	if err := form.WriteField("response_format", "json"); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.baseURL+"/inference", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("calling whisper.cpp server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("whisper.cpp server returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding whisper.cpp response: %w", err)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
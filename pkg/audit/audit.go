@@ -0,0 +1,240 @@
+// Package audit writes a tamper-evident, append-only log of what agents did.
+//
+// Every line is an [Entry]: the configuration a run used, the tool calls the
+// agents executed and the answers of the user to tool call confirmations.
+// Entries are chained: each one holds the hash of the previous one, so
+// removing, reordering or changing an entry breaks the chain, which [Verify]
+// detects. Audit logs are written to a file, to syslog or to an HTTP endpoint.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Entry types.
+const (
+	TypeConfig   = "config"
+	TypeToolCall = "tool_call"
+	TypeApproval = "approval"
+)
+
+// maxLineSize is the largest entry Verify accepts.
+const maxLineSize = 16 * 1024 * 1024
+
+// Entry is a line of an audit log.
+type Entry struct {
+	// Seq is the position of the entry in the chain, starting at 1.
+	Seq       int64           `json:"seq"`
+	Time      time.Time       `json:"time"`
+	Type      string          `json:"type"`
+	SessionID string          `json:"session_id,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	// PrevHash is the hash of the previous entry, empty for the first one.
+	PrevHash string `json:"prev_hash"`
+	// Hash is the SHA-256 of the entry without its hash.
+	Hash string `json:"hash"`
+}
+
+// Config is the configuration a run used.
+type Config struct {
+	// Source is the agent file, or the reference of the agent.
+	Source string `json:"source"`
+	// Hash is the version of the configuration in the configuration history.
+	Hash        string `json:"hash,omitempty"`
+	Version     string `json:"version"`
+	Host        string `json:"host,omitempty"`
+	WorkingDir  string `json:"working_dir,omitempty"`
+	AutoApprove bool   `json:"auto_approve,omitempty"`
+}
+
+// ToolCall is a tool call an agent executed. The output isn't kept, only its
+// hash: it can be large or hold secrets.
+type ToolCall struct {
+	Agent      string `json:"agent"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	OutputHash string `json:"output_hash"`
+	IsError    bool   `json:"is_error,omitempty"`
+}
+
+// Approval is the answer of the user to a tool call confirmation.
+type Approval struct {
+	Agent      string `json:"agent"`
+	ToolCallID string `json:"tool_call_id"`
+	ToolName   string `json:"tool_name"`
+	Arguments  string `json:"arguments,omitempty"`
+	Decision   string `json:"decision"`
+	Pattern    string `json:"pattern,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Sink is where the lines of an audit log are written.
+type Sink interface {
+	Write(line []byte) error
+	Close() error
+}
+
+// sharedSink is a sink other processes may write to as well, like a file
+// several runs append to. Each entry is chained to the last one of the sink.
+type sharedSink interface {
+	Sink
+	// lock takes an exclusive lock on the sink, and returns its last entry,
+	// or nil if it has none.
+	lock() (*Entry, error)
+	unlock() error
+}
+
+// Log appends hash-chained entries to a sink. A nil *Log records nothing.
+type Log struct {
+	sink Sink
+	now  func() time.Time
+
+	mu   sync.Mutex
+	seq  int64
+	prev string
+}
+
+// New returns a Log writing a new chain to sink.
+func New(sink Sink) *Log {
+	return &Log{
+		sink: sink,
+		now:  time.Now,
+	}
+}
+
+// Record appends an entry to the audit log.
+func (l *Log) Record(sessionID, entryType string, data any) error {
+	if l == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding %s entry: %w", entryType, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if shared, ok := l.sink.(sharedSink); ok {
+		last, err := shared.lock()
+		if err != nil {
+			return fmt.Errorf("writing %s entry: %w", entryType, err)
+		}
+		defer func() { _ = shared.unlock() }()
+		if last != nil {
+			l.seq = last.Seq
+			l.prev = last.Hash
+		}
+	}
+
+	entry := Entry{
+		Seq:       l.seq + 1,
+		Time:      l.now().UTC(),
+		Type:      entryType,
+		SessionID: sessionID,
+		Data:      raw,
+		PrevHash:  l.prev,
+	}
+	entry.Hash, err = hash(entry)
+	if err != nil {
+		return fmt.Errorf("hashing %s entry: %w", entryType, err)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding %s entry: %w", entryType, err)
+	}
+
+	if err := l.sink.Write(line); err != nil {
+		return fmt.Errorf("writing %s entry: %w", entryType, err)
+	}
+	l.seq = entry.Seq
+	l.prev = entry.Hash
+	return nil
+}
+
+// Close closes the sink of the audit log.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sink.Close()
+}
+
+// hash returns the hash of an entry, computed without its hash.
+func hash(entry Entry) (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HashOutput returns the hash recorded for the output of a tool call.
+func HashOutput(output string) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks that the entries of an audit log form an unbroken chain and
+// returns the number of entries. A log written to syslog or HTTP may start
+// in the middle of a chain: the previous hash of its first entry is trusted.
+func Verify(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var (
+		count int
+		prev  *Entry
+	)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return count, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		expected, err := hash(entry)
+		if err != nil {
+			return count, fmt.Errorf("line %d: %w", line, err)
+		}
+		if entry.Hash != expected {
+			return count, fmt.Errorf("line %d: entry %d was modified", line, entry.Seq)
+		}
+		if prev != nil {
+			if entry.PrevHash != prev.Hash {
+				return count, fmt.Errorf("line %d: the chain is broken before entry %d", line, entry.Seq)
+			}
+			if entry.Seq != prev.Seq+1 {
+				return count, fmt.Errorf("line %d: entry %d follows entry %d", line, entry.Seq, prev.Seq)
+			}
+		}
+
+		prev = &entry
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+	if count == 0 {
+		return 0, errors.New("the audit log is empty")
+	}
+	return count, nil
+}
@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_ChainGoesOnInExistingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+
+	l, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Record("", TypeConfig, Config{Source: "agent.yaml", Version: "dev"}))
+	require.NoError(t, l.Record("s1", TypeApproval, Approval{Agent: "root", ToolName: "shell", Decision: "approve"}))
+	require.NoError(t, l.Close())
+
+	l, err = Open(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Record("s1", TypeToolCall, ToolCall{Agent: "root", Name: "shell", OutputHash: HashOutput("ok")}))
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	count, err := Verify(f)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestLog_ConcurrentRunsShareTheChain(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	first, err := Open(path)
+	require.NoError(t, err)
+	second, err := Open(path)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, l := range []*Log{first, second} {
+		wg.Go(func() {
+			for range 20 {
+				assert.NoError(t, l.Record("s1", TypeApproval, Approval{ToolName: "shell", Decision: "approve"}))
+			}
+		})
+	}
+	wg.Wait()
+	require.NoError(t, first.Close())
+	require.NoError(t, second.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	count, err := Verify(f)
+	require.NoError(t, err)
+	assert.Equal(t, 40, count)
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path)
+	require.NoError(t, err)
+	for _, decision := range []string{"approve", "reject", "approve"} {
+		require.NoError(t, l.Record("s1", TypeApproval, Approval{ToolName: "shell", Decision: decision}))
+	}
+	require.NoError(t, l.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	_, err = Verify(strings.NewReader(strings.Replace(string(data), `"reject"`, `"approve"`, 1)))
+	require.ErrorContains(t, err, "line 2: entry 2 was modified")
+
+	_, err = Verify(strings.NewReader(lines[0] + "\n" + lines[2]))
+	require.ErrorContains(t, err, "line 2: the chain is broken before entry 3")
+
+	// A log may start in the middle of a chain
+	count, err := Verify(strings.NewReader(lines[1] + "\n" + lines[2]))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = Verify(strings.NewReader(""))
+	require.ErrorContains(t, err, "empty")
+}
+
+func TestLog_HTTPSink(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		entries []Entry
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var entry Entry
+		if err := json.Unmarshal(body, &entry); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		entries = append(entries, entry)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	l, err := Open(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, l.Record("s1", TypeToolCall, ToolCall{Name: "shell"}))
+	require.NoError(t, l.Record("s1", TypeToolCall, ToolCall{Name: "read_file"}))
+	require.NoError(t, l.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(2), entries[1].Seq)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	l, err = Open(failing.URL)
+	require.NoError(t, err)
+	require.ErrorContains(t, l.Record("s1", TypeToolCall, ToolCall{Name: "shell"}), "503")
+}
+
+func TestLog_Nil(t *testing.T) {
+	t.Parallel()
+
+	var l *Log
+	require.NoError(t, l.Record("s1", TypeToolCall, nil))
+	require.NoError(t, l.Close())
+}
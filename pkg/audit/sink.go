@@ -0,0 +1,208 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/httpclient"
+)
+
+// httpTimeout bounds the time an HTTP sink waits for an entry to be accepted.
+const httpTimeout = 10 * time.Second
+
+// Open returns a Log writing to a destination:
+//   - "syslog" for the local syslog daemon, "syslog://host:port" for a remote
+//     one over UDP,
+//   - an "http://" or "https://" URL each entry is POSTed to,
+//   - otherwise the path of a file. The chain of an existing file goes on.
+func Open(destination string) (*Log, error) {
+	switch {
+	case destination == "":
+		return nil, errors.New("no audit log destination")
+	case destination == "syslog" || strings.HasPrefix(destination, "syslog://"):
+		var addr string
+		if destination != "syslog" {
+			addr = strings.TrimPrefix(destination, "syslog://")
+		}
+		sink, err := newSyslogSink(addr)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to syslog: %w", err)
+		}
+		return New(sink), nil
+	case strings.HasPrefix(destination, "http://") || strings.HasPrefix(destination, "https://"):
+		return New(newHTTPSink(destination)), nil
+	default:
+		return openFile(destination)
+	}
+}
+
+// fileSink appends the lines to a file, which other runs may append to as
+// well: each entry is chained to the last one of the file, read under an
+// exclusive lock of the file.
+type fileSink struct {
+	f    *os.File
+	path string
+
+	// size and lastLine are the size of the file and the line after the
+	// last write of this sink, so the file is only read again if another
+	// run appended to it since.
+	size     int64
+	lastLine []byte
+}
+
+func (s *fileSink) lock() (*Entry, error) {
+	if err := lockFile(s.f); err != nil {
+		return nil, fmt.Errorf("locking audit log %s: %w", s.path, err)
+	}
+
+	info, err := s.f.Stat()
+	if err != nil {
+		_ = unlockFile(s.f)
+		return nil, err
+	}
+	if info.Size() == s.size && s.lastLine != nil {
+		var entry Entry
+		if err := json.Unmarshal(s.lastLine, &entry); err != nil {
+			_ = unlockFile(s.f)
+			return nil, err
+		}
+		return &entry, nil
+	}
+
+	last, err := lastEntry(s.path)
+	if err != nil {
+		_ = unlockFile(s.f)
+		return nil, fmt.Errorf("reading audit log %s: %w", s.path, err)
+	}
+	s.size = info.Size()
+	return last, nil
+}
+
+func (s *fileSink) unlock() error {
+	return unlockFile(s.f)
+}
+
+func (s *fileSink) Write(line []byte) error {
+	n, err := s.f.Write(append(line, '\n'))
+	s.size += int64(n)
+	if err != nil {
+		s.lastLine = nil
+		return err
+	}
+	s.lastLine = append(s.lastLine[:0], line...)
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	return s.f.Close()
+}
+
+// openFile opens an audit log file for appending, going on with the chain of
+// its last entry.
+func openFile(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+
+	// Fail early on a file that isn't an audit log.
+	sink := &fileSink{f: f, path: path, size: -1}
+	if _, err := sink.lock(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := sink.unlock(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return New(sink), nil
+}
+
+// lastEntry returns the last entry of an audit log file, or nil if the file
+// doesn't exist or is empty.
+func lastEntry(path string) (*Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var last []byte
+	for scanner.Scan() {
+		if len(scanner.Bytes()) > 0 {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, nil
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(last, &entry); err != nil {
+		return nil, fmt.Errorf("last entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// httpSink POSTs each line to a URL.
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSink(url string) *httpSink {
+	return &httpSink{
+		url:    url,
+		client: httpclient.NewHTTPClient(),
+	}
+}
+
+func (s *httpSink) Write(line []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(line))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package audit
+
+import (
+	"log/syslog"
+)
+
+// syslogSink sends each line to syslog.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// newSyslogSink connects to the local syslog daemon, or to a remote one over
+// UDP when addr isn't empty.
+func newSyslogSink(addr string) (Sink, error) {
+	var (
+		w   *syslog.Writer
+		err error
+	)
+	if addr == "" {
+		w, err = syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, "docker-agent")
+	} else {
+		w, err = syslog.Dial("udp", addr, syslog.LOG_INFO|syslog.LOG_AUTH, "docker-agent")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Write(line []byte) error {
+	return s.w.Info(string(line))
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
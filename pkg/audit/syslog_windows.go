@@ -0,0 +1,9 @@
+package audit
+
+import (
+	"errors"
+)
+
+func newSyslogSink(string) (Sink, error) {
+	return nil, errors.New("syslog is not supported on Windows")
+}
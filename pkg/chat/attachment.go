@@ -0,0 +1,108 @@
+package chat
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// MaxAttachmentSize is the maximum size of an attached file.
+const MaxAttachmentSize = 100 * 1024 * 1024 // 100MB
+
+// Attachment is a file attached to a user message.
+type Attachment struct {
+	// Text is appended to the text of the message: the content of text
+	// files, or a note on the dimensions of resized images.
+	Text string
+	// Part is set for images and documents that can't be inlined as text.
+	Part *MessagePart
+}
+
+// AttachFile reads a file to attach to a user message:
+//   - small text files are inlined, wrapped in an attached_file tag,
+//   - larger text files are text/plain file parts, which the runtime
+//     replaces with a preview that agents can read further in chunks,
+//   - images are resized and inlined as data URLs, which works with every
+//     provider,
+//   - other supported files (e.g. PDFs) are file parts, uploaded with the
+//     providers' file APIs,
+//   - audio and video files are file parts too, transcribed by the runtime
+//     before they are sent to models.
+//
+// The error explains why the file can't be attached, for the user.
+func AttachFile(path string) (Attachment, error) {
+	fi, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return Attachment{}, errors.New("file does not exist")
+	case errors.Is(err, os.ErrPermission):
+		return Attachment{}, errors.New("permission denied")
+	case err != nil:
+		return Attachment{}, fmt.Errorf("cannot access file: %w", err)
+	case !fi.Mode().IsRegular():
+		return Attachment{}, errors.New("not a regular file")
+	case fi.Size() > MaxAttachmentSize:
+		return Attachment{}, errors.New("file too large (max 100MB)")
+	}
+
+	mimeType := DetectMimeType(path)
+
+	switch {
+	case IsTextFile(path):
+		if fi.Size() > MaxInlineFileSize {
+			return Attachment{
+				Part: &MessagePart{
+					Type: MessagePartTypeFile,
+					File: &MessageFile{
+						Path:     path,
+						MimeType: "text/plain",
+					},
+				},
+			}, nil
+		}
+		content, err := ReadFileForInline(path)
+		if err != nil {
+			return Attachment{}, errors.New("failed to read file")
+		}
+		return Attachment{Text: "\n\n" + content}, nil
+
+	case IsImageMimeType(mimeType):
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Attachment{}, errors.New("failed to read image")
+		}
+		// Don't bypass security checks: reject the file if resizing failed.
+		resized, err := ResizeImage(data, mimeType)
+		if err != nil {
+			return Attachment{}, err
+		}
+		attachment := Attachment{
+			Part: &MessagePart{
+				Type: MessagePartTypeImageURL,
+				ImageURL: &MessageImageURL{
+					URL:    fmt.Sprintf("data:%s;base64,%s", resized.MimeType, base64.StdEncoding.EncodeToString(resized.Data)),
+					Detail: ImageURLDetailAuto,
+				},
+			},
+		}
+		if note := FormatDimensionNote(resized); note != "" {
+			attachment.Text = "\n" + note
+		}
+		return attachment, nil
+
+	case IsSupportedMimeType(mimeType), IsMediaMimeType(mimeType):
+		return Attachment{
+			Part: &MessagePart{
+				Type: MessagePartTypeFile,
+				File: &MessageFile{
+					Path:     path,
+					MimeType: mimeType,
+				},
+			},
+		}, nil
+
+	default:
+		return Attachment{}, errors.New("unsupported file type")
+	}
+}
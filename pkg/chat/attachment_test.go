@@ -0,0 +1,85 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	textFile := filepath.Join(dir, "report.csv")
+	require.NoError(t, os.WriteFile(textFile, []byte("a,b\n1,2\n"), 0o644))
+	largeTextFile := filepath.Join(dir, "server.log")
+	require.NoError(t, os.WriteFile(largeTextFile, []byte(strings.Repeat("GET /health 200\n", MaxInlineFileSize/16+1)), 0o644))
+	pngFile := filepath.Join(dir, "screenshot.png")
+	require.NoError(t, os.WriteFile(pngFile, createTestPNG(t, 10, 10), 0o644))
+	pdfFile := filepath.Join(dir, "spec.pdf")
+	require.NoError(t, os.WriteFile(pdfFile, []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"), 0o644))
+	audioFile := filepath.Join(dir, "meeting.mp3")
+	require.NoError(t, os.WriteFile(audioFile, []byte("ID3\x04\x00\x00\x00\x00\x00\x00"), 0o644))
+	binFile := filepath.Join(dir, "archive.bin")
+	require.NoError(t, os.WriteFile(binFile, []byte{0x1f, 0x8b, 0x08, 0x00, 0x00}, 0o644))
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+		attachment, err := AttachFile(textFile)
+		require.NoError(t, err)
+		assert.Nil(t, attachment.Part)
+		assert.Contains(t, attachment.Text, `<attached_file path="`+textFile+`">`)
+		assert.Contains(t, attachment.Text, "1,2")
+	})
+
+	t.Run("large text", func(t *testing.T) {
+		t.Parallel()
+		attachment, err := AttachFile(largeTextFile)
+		require.NoError(t, err)
+		assert.Empty(t, attachment.Text)
+		require.NotNil(t, attachment.Part)
+		assert.Equal(t, MessagePartTypeFile, attachment.Part.Type)
+		assert.Equal(t, &MessageFile{Path: largeTextFile, MimeType: "text/plain"}, attachment.Part.File)
+	})
+
+	t.Run("image", func(t *testing.T) {
+		t.Parallel()
+		attachment, err := AttachFile(pngFile)
+		require.NoError(t, err)
+		require.NotNil(t, attachment.Part)
+		assert.Equal(t, MessagePartTypeImageURL, attachment.Part.Type)
+		assert.True(t, strings.HasPrefix(attachment.Part.ImageURL.URL, "data:image/png;base64,"))
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		t.Parallel()
+		attachment, err := AttachFile(pdfFile)
+		require.NoError(t, err)
+		require.NotNil(t, attachment.Part)
+		assert.Equal(t, MessagePartTypeFile, attachment.Part.Type)
+		assert.Equal(t, "application/pdf", attachment.Part.File.MimeType)
+	})
+
+	t.Run("audio", func(t *testing.T) {
+		t.Parallel()
+		attachment, err := AttachFile(audioFile)
+		require.NoError(t, err)
+		require.NotNil(t, attachment.Part)
+		assert.Equal(t, MessagePartTypeFile, attachment.Part.Type)
+		assert.Equal(t, "audio/mpeg", attachment.Part.File.MimeType)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := AttachFile(filepath.Join(dir, "missing.txt"))
+		require.EqualError(t, err, "file does not exist")
+		_, err = AttachFile(dir)
+		require.EqualError(t, err, "not a regular file")
+		_, err = AttachFile(binFile)
+		require.EqualError(t, err, "unsupported file type")
+	})
+}
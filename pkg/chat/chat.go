@@ -12,10 +12,10 @@ import (
 )
 
 // MaxInlineFileSize is the maximum size of a text file that can be inlined
-// directly into a message. Files larger than this are skipped with a warning.
-// This is much smaller than the general upload limit because inline content
+// directly into a message. Larger text files are attached as file parts that
+// agents read in chunks with the read_attachment tool, because inline content
 // expands token usage significantly.
-const MaxInlineFileSize = 5 * 1024 * 1024 // 5MB
+const MaxInlineFileSize = 100 * 1024 // 100KB
 
 type MessageRole string
 
@@ -32,6 +32,11 @@ const (
 	MessagePartTypeText     MessagePartType = "text"
 	MessagePartTypeImageURL MessagePartType = "image_url"
 	MessagePartTypeFile     MessagePartType = "file"
+
+	// MessagePartTypeSearchResult is a search result returned by a tool.
+	// Providers that support citations (Anthropic) can cite these blocks
+	// in their answers.
+	MessagePartTypeSearchResult MessagePartType = "search_result"
 )
 
 type ImageURLDetail string
@@ -78,6 +83,11 @@ type Message struct {
 	// IsError indicates the tool call failed (only for Role=tool messages).
 	IsError bool `json:"is_error,omitempty"`
 
+	// StructuredContent is the structured output of the tool call, if any
+	// (only for Role=tool messages). It's kept for UIs and automation, and
+	// isn't sent to models, which only see Content.
+	StructuredContent any `json:"structured_content,omitempty"`
+
 	CreatedAt string `json:"created_at,omitempty"`
 
 	// Usage tracks token usage for this message (only set for assistant messages)
@@ -89,8 +99,94 @@ type Message struct {
 	// Cost is the cost of this message in dollars (only set for assistant messages)
 	Cost float64 `json:"cost,omitempty"`
 
+	// LatencyMs is the wall-clock time the model took to generate this
+	// message, in milliseconds (only set for assistant messages)
+	LatencyMs int64 `json:"latency_ms,omitempty"`
+
+	// ResponseID is the ID the provider gave to the response, to find the
+	// request in its logs (only set for assistant messages)
+	ResponseID string `json:"response_id,omitempty"`
+
 	// CacheControl indicates whether this message is a cached message (only used by anthropic)
 	CacheControl bool `json:"cache_control,omitempty"`
+
+	// Citations lists the sources the model cited in Content (only set for assistant messages)
+	Citations []Citation `json:"citations,omitempty"`
+
+	// ReasoningItems holds encrypted reasoning returned by the OpenAI Responses API
+	// so that it can be replayed on the next request (only set for assistant messages)
+	ReasoningItems []ReasoningItem `json:"reasoning_items,omitempty"`
+
+	// Ensemble holds the answers of all the models of an ensemble alloy for
+	// this turn, and how this one was chosen (only set for assistant messages)
+	Ensemble *Ensemble `json:"ensemble,omitempty"`
+
+	// RequestDump is the file --debug-llm dumped the model request and
+	// response of this turn to (only set for assistant messages)
+	RequestDump string `json:"request_dump,omitempty"`
+}
+
+// Ensemble is how an ensemble alloy chose an answer among the answers of its
+// models to the same request.
+type Ensemble struct {
+	// Method is how the answer was chosen: "majority" or "judge".
+	Method string `json:"method"`
+	// Chosen is the index of the chosen answer in Variants.
+	Chosen int `json:"chosen"`
+	// Agreeing is the number of models that gave the chosen answer.
+	Agreeing int `json:"agreeing"`
+	// Disagreement describes the answers that differ from the chosen one.
+	// It is empty when all the models agreed.
+	Disagreement string `json:"disagreement,omitempty"`
+	// Variants are the answers of all the models, in the order of the alloy.
+	Variants []EnsembleVariant `json:"variants"`
+}
+
+// EnsembleVariant is the answer of one of the models of an ensemble alloy.
+type EnsembleVariant struct {
+	Model     string           `json:"model"`
+	Content   string           `json:"content,omitempty"`
+	ToolCalls []tools.ToolCall `json:"tool_calls,omitempty"`
+	Usage     *Usage           `json:"usage,omitempty"`
+	// Error is why the model failed to answer.
+	Error string `json:"error,omitempty"`
+}
+
+// ReasoningItem is an opaque reasoning item returned by the OpenAI Responses
+// API. It is sent back verbatim so the model can pick up its chain of thought
+// without the provider storing the response server-side.
+type ReasoningItem struct {
+	ID               string   `json:"id"`
+	EncryptedContent string   `json:"encrypted_content,omitempty"`
+	Summary          []string `json:"summary,omitempty"`
+}
+
+// Citation links a span of an assistant answer to the source it was drawn from.
+type Citation struct {
+	// Type is the provider's citation type (e.g. "search_result_location").
+	Type string `json:"type,omitempty"`
+	// CitedText is the text quoted from the source.
+	CitedText string `json:"cited_text,omitempty"`
+	// Source identifies where the cited content came from (URL, file path, ...).
+	Source string `json:"source,omitempty"`
+	// Title is the title of the cited source, if any.
+	Title string `json:"title,omitempty"`
+	// SearchResultIndex is the index of the cited search result block.
+	SearchResultIndex int64 `json:"search_result_index,omitempty"`
+	// StartBlockIndex and EndBlockIndex delimit the cited content blocks
+	// inside the search result.
+	StartBlockIndex int64 `json:"start_block_index,omitempty"`
+	EndBlockIndex   int64 `json:"end_block_index,omitempty"`
+	// ContentOffset is the position in the assistant's content at which
+	// the citation was emitted.
+	ContentOffset int `json:"content_offset,omitempty"`
+}
+
+// MessageSearchResult is a search result that can be cited by the model.
+type MessageSearchResult struct {
+	Source  string   `json:"source"`
+	Title   string   `json:"title,omitempty"`
+	Content []string `json:"content"`
 }
 
 // MessageFile represents a file attachment that can be uploaded to a provider's file storage.
@@ -105,6 +201,8 @@ type MessagePart struct {
 	Text     string           `json:"text,omitempty"`
 	ImageURL *MessageImageURL `json:"image_url,omitempty"`
 	File     *MessageFile     `json:"file,omitempty"`
+
+	SearchResult *MessageSearchResult `json:"search_result,omitempty"`
 }
 
 // FinishReason represents the reason why the model finished generating a response
@@ -130,6 +228,9 @@ type MessageDelta struct {
 	ThoughtSignature  []byte              `json:"thought_signature,omitempty"`
 	FunctionCall      *tools.FunctionCall `json:"function_call,omitempty"`
 	ToolCalls         []tools.ToolCall    `json:"tool_calls,omitempty"`
+	Citations         []Citation          `json:"citations,omitempty"`
+	ReasoningItems    []ReasoningItem     `json:"reasoning_items,omitempty"`
+	Ensemble          *Ensemble           `json:"ensemble,omitempty"`
 }
 
 // MessageStreamChoice represents a choice in a streaming response
@@ -186,12 +287,39 @@ func DetectMimeType(filePath string) string {
 
 	// http.DetectContentType returns "application/octet-stream" for text
 	// files it can't classify, so fall back to extension for those.
-	if isTextExtension(strings.ToLower(filepath.Ext(filePath))) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if isTextExtension(ext) {
 		return "text/plain"
 	}
+	// Same for audio formats without magic bytes, e.g. MP3 files without
+	// an ID3 tag.
+	if mimeType, ok := mediaExtensions[ext]; ok {
+		return mimeType
+	}
 	return "application/octet-stream"
 }
 
+// mediaExtensions maps the extensions of audio and video files to their
+// MIME type.
+var mediaExtensions = map[string]string{
+	".mp3":  "audio/mpeg",
+	".mpga": "audio/mpeg",
+	".wav":  "audio/wav",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".flac": "audio/flac",
+	".ogg":  "audio/ogg",
+	".oga":  "audio/ogg",
+	".opus": "audio/ogg",
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mov":  "video/quicktime",
+	".mpeg": "video/mpeg",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+	".avi":  "video/x-msvideo",
+}
+
 // detectMimeTypeFromFile reads the first 512 bytes of a file and uses
 // content-based detection (magic bytes) to determine the MIME type.
 func detectMimeTypeFromFile(filePath string) string {
@@ -238,6 +366,13 @@ func IsSupportedMimeType(mimeType string) bool {
 	}
 }
 
+// IsMediaMimeType returns true if the MIME type is an audio or video type.
+// Audio and video attachments are transcribed before they are sent to
+// models.
+func IsMediaMimeType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "audio/") || strings.HasPrefix(mimeType, "video/") || mimeType == "application/ogg"
+}
+
 // IsTextFile determines if a file at the given path is a text file that should
 // be inlined into the message rather than uploaded via a provider's file API.
 // It first checks the file extension against a broad allowlist of known text
@@ -38,10 +38,13 @@ func TestDetectMimeType(t *testing.T) {
 		{"query.graphql", "text/plain"},
 		{"icon.svg", "text/plain"},
 		{"changes.diff", "text/plain"},
+		// Audio and video (detected by extension fallback)
+		{"movie.mp4", "video/mp4"},
+		{"talk.mp3", "audio/mpeg"},
+		{"memo.m4a", "audio/mp4"},
 		// Unknown binary (no file to sniff, unknown extension)
 		{"archive.tar.gz", "application/octet-stream"},
 		{"program.exe", "application/octet-stream"},
-		{"movie.mp4", "application/octet-stream"},
 	}
 
 	for _, tt := range tests {
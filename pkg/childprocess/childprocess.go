@@ -0,0 +1,266 @@
+// Package childprocess tracks the processes started by the tools of the
+// agents: MCP servers, LSP servers and shell commands.
+//
+// Every tracked process runs in its own process group, or job object on
+// Windows, so that it's killed along with its children. Tracked processes
+// are also recorded on disk, so that the ones left behind by a docker agent
+// that crashed or was killed can be listed and killed later.
+package childprocess
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+// Kinds of processes.
+const (
+	OwnerMCP    = "mcp"
+	OwnerLSP    = "lsp"
+	OwnerShell  = "shell"
+	OwnerScript = "script"
+)
+
+// Process is a process started by a docker agent.
+type Process struct {
+	PID       int       `json:"pid"`
+	ParentPID int       `json:"parent_pid"`
+	Owner     string    `json:"owner"`
+	Command   string    `json:"command"`
+	StartedAt time.Time `json:"started_at"`
+
+	// Start and ParentStart are when the process and the docker agent
+	// started, as counted by the system, to tell them from the processes
+	// that reuse their PIDs once they exited. Zero if unknown.
+	Start       uint64 `json:"start,omitempty"`
+	ParentStart uint64 `json:"parent_start,omitempty"`
+}
+
+// Orphaned returns whether the docker agent that started the process exited.
+func (p Process) Orphaned() bool {
+	return !running(p.ParentPID, p.ParentStart)
+}
+
+// Handle is a tracked process.
+type Handle struct {
+	proc  *os.Process
+	group *group
+	path  string
+
+	mu       sync.Mutex
+	released bool
+}
+
+var (
+	mu      sync.Mutex
+	handles = map[*Handle]struct{}{}
+)
+
+// Prepare makes cmd start in its own process group. It must be called before
+// the command is started, for the commands that will be tracked.
+func Prepare(cmd *exec.Cmd) {
+	prepare(cmd)
+}
+
+// Track tracks the started command on behalf of owner. The returned handle
+// must be released once the process exited. The command must have been
+// prepared with Prepare, or started in its own session.
+func Track(cmd *exec.Cmd, owner string) (*Handle, error) {
+	if cmd.Process == nil {
+		return nil, errors.New("the command isn't started")
+	}
+
+	g, err := newGroup(cmd.Process)
+	if err != nil {
+		return nil, fmt.Errorf("creating process group: %w", err)
+	}
+
+	h := &Handle{
+		proc:  cmd.Process,
+		group: g,
+	}
+
+	p := Process{
+		PID:       cmd.Process.Pid,
+		ParentPID: os.Getpid(),
+		Owner:     owner,
+		Command:   strings.Join(cmd.Args, " "),
+		StartedAt: time.Now(),
+	}
+	p.Start, _ = startTime(p.PID)
+	p.ParentStart, _ = startTime(p.ParentPID)
+	if path, err := record(p); err != nil {
+		slog.Debug("Failed to record process", "pid", p.PID, "error", err)
+	} else {
+		h.path = path
+	}
+
+	mu.Lock()
+	handles[h] = struct{}{}
+	mu.Unlock()
+
+	return h, nil
+}
+
+// Kill kills the process and its children, and releases the handle.
+func (h *Handle) Kill() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.released {
+		return nil
+	}
+	err := h.group.kill(h.proc)
+	h.releaseLocked()
+	return err
+}
+
+// Release stops tracking the process. The process is expected to have exited.
+func (h *Handle) Release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.released {
+		h.releaseLocked()
+	}
+}
+
+func (h *Handle) releaseLocked() {
+	h.released = true
+
+	mu.Lock()
+	delete(handles, h)
+	mu.Unlock()
+
+	h.group.close()
+	if h.path != "" {
+		_ = os.Remove(h.path)
+	}
+}
+
+// KillAll kills the processes tracked by this docker agent. It's called when
+// docker agent exits.
+func KillAll() {
+	mu.Lock()
+	all := make([]*Handle, 0, len(handles))
+	for h := range handles {
+		all = append(all, h)
+	}
+	mu.Unlock()
+
+	for _, h := range all {
+		if err := h.Kill(); err != nil {
+			slog.Debug("Failed to kill process", "pid", h.proc.Pid, "error", err)
+		}
+	}
+}
+
+// List returns the running processes started by all the docker agents,
+// sorted by start time. The records of the processes that exited are removed.
+func List() ([]Process, error) {
+	entries, err := os.ReadDir(dir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []Process
+	for _, entry := range entries {
+		path := filepath.Join(dir(), entry.Name())
+
+		p, err := read(path)
+		if err != nil {
+			slog.Debug("Failed to read process record", "path", path, "error", err)
+			continue
+		}
+		if !running(p.PID, p.Start) {
+			_ = os.Remove(path)
+			continue
+		}
+		processes = append(processes, p)
+	}
+
+	slices.SortFunc(processes, func(a, b Process) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return processes, nil
+}
+
+// Kill kills a process started by any docker agent, and its children. A
+// process that exited is forgotten, even if another process reused its PID.
+func Kill(pid int) error {
+	path := recordPath(pid)
+	p, err := read(path)
+	if err != nil {
+		return fmt.Errorf("process %d wasn't started by docker agent", pid)
+	}
+	if !running(pid, p.Start) {
+		_ = os.Remove(path)
+		return fmt.Errorf("process %d already exited", pid)
+	}
+
+	if err := killGroup(pid); err != nil && alive(pid) {
+		return fmt.Errorf("killing process %d: %w", pid, err)
+	}
+	_ = os.Remove(path)
+	return nil
+}
+
+// running returns whether the process with the given PID is alive and is
+// the one that started at start, when it's known.
+func running(pid int, start uint64) bool {
+	if !alive(pid) {
+		return false
+	}
+	if start == 0 {
+		return true
+	}
+	current, err := startTime(pid)
+	return err == nil && current == start
+}
+
+func dir() string {
+	return filepath.Join(paths.GetDataDir(), "processes")
+}
+
+func recordPath(pid int) string {
+	return filepath.Join(dir(), strconv.Itoa(pid)+".json")
+}
+
+func record(p Process) (string, error) {
+	if err := os.MkdirAll(dir(), 0o700); err != nil {
+		return "", err
+	}
+
+	buf, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	path := recordPath(p.PID)
+	return path, os.WriteFile(path, buf, 0o600)
+}
+
+func read(path string) (Process, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return Process{}, err
+	}
+
+	var p Process
+	err = json.Unmarshal(buf, &p)
+	return p, err
+}
@@ -0,0 +1,26 @@
+package childprocess
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// startTime returns when a process started, in clock ticks since boot.
+func startTime(pid int) (uint64, error) {
+	buf, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name, in parentheses, may contain spaces: the fields are
+	// counted after it, starting at the state, which is the third one.
+	stat := string(buf)
+	fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+	const startTimeField = 22 - 3
+	if len(fields) <= startTimeField {
+		return 0, errors.New("invalid process stat")
+	}
+	return strconv.ParseUint(fields[startTimeField], 10, 64)
+}
@@ -0,0 +1,98 @@
+//go:build !windows
+
+package childprocess
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+func TestTrackAndKill(t *testing.T) {
+	paths.SetDataDir(t.TempDir())
+	t.Cleanup(func() { paths.SetDataDir("") })
+
+	// The shell starts a child that must be killed with it.
+	cmd := exec.Command("sh", "-c", "sleep 60 & wait")
+	Prepare(cmd)
+	require.NoError(t, cmd.Start())
+
+	proc, err := Track(cmd, OwnerShell)
+	require.NoError(t, err)
+
+	processes, err := List()
+	require.NoError(t, err)
+	require.Len(t, processes, 1)
+	assert.Equal(t, cmd.Process.Pid, processes[0].PID)
+	assert.Equal(t, os.Getpid(), processes[0].ParentPID)
+	assert.Equal(t, OwnerShell, processes[0].Owner)
+	assert.Equal(t, "sh -c sleep 60 & wait", processes[0].Command)
+	assert.False(t, processes[0].Orphaned())
+
+	require.NoError(t, proc.Kill())
+	require.Error(t, cmd.Wait())
+
+	processes, err = List()
+	require.NoError(t, err)
+	assert.Empty(t, processes)
+}
+
+func TestList_RemovesExitedProcesses(t *testing.T) {
+	paths.SetDataDir(t.TempDir())
+	t.Cleanup(func() { paths.SetDataDir("") })
+
+	cmd := exec.Command("true")
+	Prepare(cmd)
+	require.NoError(t, cmd.Start())
+	_, err := Track(cmd, OwnerMCP)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Wait())
+
+	processes, err := List()
+	require.NoError(t, err)
+	assert.Empty(t, processes)
+	assert.NoFileExists(t, recordPath(cmd.Process.Pid))
+}
+
+func TestPIDReusedByAnotherProcess(t *testing.T) {
+	paths.SetDataDir(t.TempDir())
+	t.Cleanup(func() { paths.SetDataDir("") })
+
+	cmd := exec.Command("sleep", "60")
+	Prepare(cmd)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	// The record of a process that exited, whose PID the sleep reused.
+	start, err := startTime(cmd.Process.Pid)
+	require.NoError(t, err)
+	_, err = record(Process{PID: cmd.Process.Pid, ParentPID: os.Getpid(), Start: start + 1, ParentStart: 1})
+	require.NoError(t, err)
+
+	processes, err := List()
+	require.NoError(t, err)
+	assert.Empty(t, processes)
+
+	_, err = record(Process{PID: cmd.Process.Pid, ParentPID: os.Getpid(), Start: start + 1})
+	require.NoError(t, err)
+	require.ErrorContains(t, Kill(cmd.Process.Pid), "already exited")
+	assert.True(t, alive(cmd.Process.Pid), "the process reusing the PID isn't killed")
+
+	assert.True(t, Process{ParentPID: os.Getpid(), ParentStart: 1}.Orphaned(), "the docker agent that started the process exited")
+}
+
+func TestKill_UnknownProcess(t *testing.T) {
+	paths.SetDataDir(t.TempDir())
+	t.Cleanup(func() { paths.SetDataDir("") })
+
+	err := Kill(os.Getpid())
+	require.ErrorContains(t, err, "wasn't started by docker agent")
+}
@@ -0,0 +1,45 @@
+//go:build !windows
+
+package childprocess
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// group is the process group of a process. Unix doesn't need to store
+// handles, process groups are managed by the kernel.
+type group struct{}
+
+func prepare(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	if !cmd.SysProcAttr.Setsid {
+		cmd.SysProcAttr.Setpgid = true
+	}
+}
+
+func newGroup(*os.Process) (*group, error) {
+	return &group{}, nil
+}
+
+func (*group) kill(proc *os.Process) error {
+	return killGroup(proc.Pid)
+}
+
+func (*group) close() {}
+
+func killGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
+
+func alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,118 @@
+package childprocess
+
+import (
+	"os"
+	"os/exec"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const stillActive = 259 // STILL_ACTIVE
+
+// group is the job object of a process. The processes of the job are killed
+// when its last handle is closed, including when docker agent exits.
+type group struct {
+	jobHandle     windows.Handle
+	processHandle windows.Handle
+}
+
+func prepare(*exec.Cmd) {}
+
+func newGroup(proc *os.Process) (*group, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info))); err != nil {
+		_ = windows.CloseHandle(job)
+		return nil, err
+	}
+
+	handle, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(proc.Pid))
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		return nil, err
+	}
+
+	if err := windows.AssignProcessToJobObject(job, handle); err != nil {
+		_ = windows.CloseHandle(handle)
+		_ = windows.CloseHandle(job)
+		return nil, err
+	}
+
+	return &group{
+		jobHandle:     job,
+		processHandle: handle,
+	}, nil
+}
+
+func (g *group) kill(proc *os.Process) error {
+	// Closing the handles terminates all the processes of the job.
+	g.close()
+
+	// Also kill the process as a fallback
+	return proc.Kill()
+}
+
+func (g *group) close() {
+	if g.processHandle != 0 {
+		_ = windows.CloseHandle(g.processHandle)
+		g.processHandle = 0
+	}
+	if g.jobHandle != 0 {
+		_ = windows.CloseHandle(g.jobHandle)
+		g.jobHandle = 0
+	}
+}
+
+func killGroup(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
+
+func alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle) //nolint:errcheck // nothing to do
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// startTime returns when a process started, in 100-nanosecond intervals
+// since 1601.
+func startTime(pid int) (uint64, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(handle) //nolint:errcheck // nothing to do
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return 0, err
+	}
+	return uint64(creation.HighDateTime)<<32 | uint64(creation.LowDateTime), nil
+}
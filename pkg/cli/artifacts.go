@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// CopyArtifacts copies the files of the artifacts to dir, creating it if
+// needed, and returns the artifacts with their new path. Files with the same
+// name are numbered so that none is overwritten. An artifact whose file
+// can't be copied is reported in the error and left out of the result.
+func CopyArtifacts(dir string, artifacts []session.Artifact) ([]session.Artifact, error) {
+	if len(artifacts) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating artifacts directory: %w", err)
+	}
+
+	var (
+		copied []session.Artifact
+		errs   []error
+	)
+	used := map[string]bool{}
+	for _, artifact := range artifacts {
+		dst := filepath.Join(dir, uniqueName(artifact.Name, used))
+		if err := copyFile(artifact.Path, dst); err != nil {
+			errs = append(errs, fmt.Errorf("copying artifact %s: %w", artifact.Name, err))
+			continue
+		}
+		artifact.Path = dst
+		copied = append(copied, artifact)
+	}
+	return copied, errors.Join(errs...)
+}
+
+// uniqueName returns name, or name with a number before its extension if it
+// was already used.
+func uniqueName(name string, used map[string]bool) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = base + "-" + strconv.Itoa(i) + ext
+	}
+	used[candidate] = true
+	return candidate
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestCopyArtifacts(t *testing.T) {
+	src := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(src, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	copied, err := CopyArtifacts(dir, []session.Artifact{
+		{ID: "1", Name: "report.md", Path: write("report.md", "first")},
+		{ID: "2", Name: "report.md", Path: write("sub/report.md", "second")},
+		{ID: "3", Name: "gone.txt", Path: filepath.Join(src, "gone.txt")},
+	})
+	require.ErrorContains(t, err, "copying artifact gone.txt")
+
+	require.Len(t, copied, 2)
+	assert.Equal(t, filepath.Join(dir, "report.md"), copied[0].Path)
+	assert.Equal(t, filepath.Join(dir, "report-2.md"), copied[1].Path)
+
+	buf, err := os.ReadFile(copied[1].Path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(buf))
+}
+
+func TestCopyArtifacts_Empty(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+
+	copied, err := CopyArtifacts(dir, nil)
+	require.NoError(t, err)
+	assert.Empty(t, copied)
+	assert.NoDirExists(t, dir)
+}
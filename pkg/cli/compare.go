@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// Contender is one of the two runs of a comparison.
+type Contender struct {
+	// Label names the agent or the model that ran.
+	Label    string        `json:"label"`
+	Duration time.Duration `json:"duration_ns"`
+	Result   RunResult     `json:"result"`
+}
+
+// Comparison is the result of running the same messages with two agents or
+// two models.
+type Comparison struct {
+	A Contender `json:"a"`
+	B Contender `json:"b"`
+}
+
+// Collect runs the agent like Run with the JSON output, and returns the
+// result of the run instead of printing it.
+func Collect(ctx context.Context, cfg Config, rt runtime.Runtime, sess *session.Session, userMessages []string) RunResult {
+	cfg.Output = OutputJSON
+
+	collector := newResultCollector(sess.ID)
+	err := run(ctx, NewPrinter(io.Discard), cfg, rt, sess, userMessages, collector)
+	return collector.finish(err)
+}
+
+type comparisonRow struct {
+	name  string
+	a, b  string
+	delta string
+}
+
+func (c Comparison) rows() []comparisonRow {
+	a, b := c.A.Result, c.B.Result
+	return []comparisonRow{
+		{"Status", a.Status, b.Status, ""},
+		{"Duration", c.A.Duration.Round(time.Millisecond).String(), c.B.Duration.Round(time.Millisecond).String(), durationDelta(c.A.Duration, c.B.Duration)},
+		{"Input tokens", fmt.Sprint(a.Usage.InputTokens), fmt.Sprint(b.Usage.InputTokens), countDelta(a.Usage.InputTokens, b.Usage.InputTokens)},
+		{"Output tokens", fmt.Sprint(a.Usage.OutputTokens), fmt.Sprint(b.Usage.OutputTokens), countDelta(a.Usage.OutputTokens, b.Usage.OutputTokens)},
+		{"Cost", fmt.Sprintf("$%.4f", a.Usage.Cost), fmt.Sprintf("$%.4f", b.Usage.Cost), costDelta(a.Usage.Cost, b.Usage.Cost)},
+		{"Tool calls", fmt.Sprint(len(a.ToolCalls)), fmt.Sprint(len(b.ToolCalls)), countDelta(int64(len(a.ToolCalls)), int64(len(b.ToolCalls)))},
+	}
+}
+
+// PrintMarkdown prints the comparison as markdown: a table of the metrics,
+// with the deltas of B compared to A, and the answers side by side.
+func (c Comparison) PrintMarkdown(w io.Writer) {
+	fmt.Fprintf(w, "| | A: %s | B: %s | B vs A |\n", escapeCell(c.A.Label), escapeCell(c.B.Label))
+	fmt.Fprintln(w, "| --- | --- | --- | --- |")
+	for _, row := range c.rows() {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n", row.name, row.a, row.b, row.delta)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "<table>\n<tr><th>A: %s</th><th>B: %s</th></tr>\n", escapeHTML(c.A.Label), escapeHTML(c.B.Label))
+	fmt.Fprintf(w, "<tr>\n<td>\n\n%s\n\n</td>\n<td>\n\n%s\n\n</td>\n</tr>\n</table>\n", answer(c.A.Result), answer(c.B.Result))
+}
+
+// PrintColumns prints the answers in two columns, side by side, followed by
+// a table of the metrics, for a terminal of the given width.
+func (c Comparison) PrintColumns(w io.Writer, width int) {
+	columnWidth := max((width-3)/2, 20)
+	column := lipgloss.NewStyle().Width(columnWidth)
+	title := column.Bold(true)
+
+	// Styles are only kept on terminals.
+	_, _ = lipgloss.Fprintln(w, lipgloss.JoinHorizontal(lipgloss.Top,
+		title.Render("A: "+c.A.Label), " │ ", title.Render("B: "+c.B.Label)))
+
+	left := column.Render(answer(c.A.Result))
+	right := column.Render(answer(c.B.Result))
+	separator := strings.TrimSuffix(strings.Repeat(" │ \n", max(lipgloss.Height(left), lipgloss.Height(right))), "\n")
+	_, _ = lipgloss.Fprintln(w, lipgloss.JoinHorizontal(lipgloss.Top, left, separator, right))
+	fmt.Fprintln(w)
+
+	rows := c.rows()
+	nameWidth, aWidth, bWidth := 0, len("A"), len("B")
+	for _, row := range rows {
+		nameWidth = max(nameWidth, len(row.name))
+		aWidth = max(aWidth, len(row.a))
+		bWidth = max(bWidth, len(row.b))
+	}
+	fmt.Fprintf(w, "%-*s  %-*s  %-*s  %s\n", nameWidth, "", aWidth, "A", bWidth, "B", "B vs A")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%-*s  %-*s  %-*s  %s\n", nameWidth, row.name, aWidth, row.a, bWidth, row.b, row.delta)
+	}
+}
+
+// answer returns the final message of a run, or its error.
+func answer(r RunResult) string {
+	if r.Error != "" {
+		return "Error: " + r.Error
+	}
+	return r.FinalMessage
+}
+
+func durationDelta(a, b time.Duration) string {
+	d := (b - a).Round(time.Millisecond)
+	sign := ""
+	if d >= 0 {
+		sign = "+"
+	}
+	return sign + d.String() + percent(float64(a), float64(b))
+}
+
+func countDelta(a, b int64) string {
+	return fmt.Sprintf("%+d", b-a) + percent(float64(a), float64(b))
+}
+
+func costDelta(a, b float64) string {
+	sign := "+"
+	if b < a {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s$%.4f", sign, math.Abs(b-a)) + percent(a, b)
+}
+
+// percent returns the change from a to b, as a percentage of a.
+func percent(a, b float64) string {
+	if a == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.0f%%)", (b-a)/a*100)
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
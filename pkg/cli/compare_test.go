@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testComparison() Comparison {
+	return Comparison{
+		A: Contender{
+			Label:    "openai/gpt-5",
+			Duration: 2 * time.Second,
+			Result: RunResult{
+				Status:       "success",
+				FinalMessage: "Answer A",
+				Usage:        UsageSummary{InputTokens: 1000, OutputTokens: 200, Cost: 0.01},
+			},
+		},
+		B: Contender{
+			Label:    "openai/gpt-5-mini",
+			Duration: 1500 * time.Millisecond,
+			Result: RunResult{
+				Status:       "success",
+				FinalMessage: "Answer B",
+				ToolCalls:    []ToolCallSummary{{ID: "1", Name: "shell"}},
+				Usage:        UsageSummary{InputTokens: 1100, OutputTokens: 150, Cost: 0.002},
+			},
+		},
+	}
+}
+
+func TestComparison_PrintMarkdown(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	testComparison().PrintMarkdown(&out)
+
+	assert.Contains(t, out.String(), "| | A: openai/gpt-5 | B: openai/gpt-5-mini | B vs A |")
+	assert.Contains(t, out.String(), "| Duration | 2s | 1.5s | -500ms (-25%) |")
+	assert.Contains(t, out.String(), "| Input tokens | 1000 | 1100 | +100 (+10%) |")
+	assert.Contains(t, out.String(), "| Output tokens | 200 | 150 | -50 (-25%) |")
+	assert.Contains(t, out.String(), "| Cost | $0.0100 | $0.0020 | -$0.0080 (-80%) |")
+	assert.Contains(t, out.String(), "| Tool calls | 0 | 1 | +1 |")
+	assert.Contains(t, out.String(), "<td>\n\nAnswer A\n\n</td>")
+	assert.Contains(t, out.String(), "<td>\n\nAnswer B\n\n</td>")
+}
+
+func TestComparison_PrintColumns(t *testing.T) {
+	t.Parallel()
+
+	c := testComparison()
+	c.B.Result.Error = "model not found"
+
+	var out bytes.Buffer
+	c.PrintColumns(&out, 80)
+
+	assert.Contains(t, out.String(), "Answer A")
+	assert.Contains(t, out.String(), "Error: model not found")
+	assert.Contains(t, out.String(), "Cost           $0.0100  $0.0020  -$0.0080 (-80%)")
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// freeFormAnswerKey is the key of the answer to an elicitation that has no
+// schema, the same one the TUI uses for free-form responses.
+const freeFormAnswerKey = "response"
+
+// ParseAnswers builds the answers to elicitation requests from an optional
+// JSON answers file and from key=value flags. Flags take precedence over the
+// file.
+func ParseAnswers(answersFile string, flags []string) (map[string]any, error) {
+	answers := map[string]any{}
+
+	if answersFile != "" {
+		buf, err := os.ReadFile(answersFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading answers file: %w", err)
+		}
+		if err := json.Unmarshal(buf, &answers); err != nil {
+			return nil, fmt.Errorf("parsing answers file %s: %w", answersFile, err)
+		}
+	}
+
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid answer %q, expected key=value", flag)
+		}
+		answers[strings.TrimSpace(key)] = value
+	}
+
+	return answers, nil
+}
+
+// answerElicitation answers an elicitation request without asking anyone,
+// using the given answers and, for the fields that have none, the defaults
+// declared in the request's schema.
+//
+// When a required field has neither an answer nor a default, the request is
+// declined with a structured result listing the missing fields, so that the
+// agent can carry on instead of waiting for a user that isn't there.
+func answerElicitation(e *runtime.ElicitationRequestEvent, answers map[string]any) (tools.ElicitationAction, map[string]any) {
+	if e.Mode == "url" {
+		return tools.ElicitationActionDecline, unavailable("opening URLs is not supported in non-interactive mode", nil)
+	}
+
+	schema := schemaMap(e.Schema)
+	properties, _ := schema["properties"].(map[string]any)
+
+	// No schema: a single free-form response.
+	if len(properties) == 0 && (schema["type"] == nil || schema["type"] == "object") {
+		if answer, ok := answers[freeFormAnswerKey]; ok {
+			return tools.ElicitationActionAccept, map[string]any{freeFormAnswerKey: answer}
+		}
+		return tools.ElicitationActionDecline, unavailable("no answer was provided", []string{freeFormAnswerKey})
+	}
+
+	// Primitive schema: a single value, named after the schema's title.
+	if len(properties) == 0 {
+		name := "value"
+		if title, _ := schema["title"].(string); title != "" {
+			name = title
+		}
+		properties = map[string]any{name: schema}
+		schema = map[string]any{"required": []any{name}}
+	}
+
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	content := map[string]any{}
+	var missing []string
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		prop, _ := properties[name].(map[string]any)
+
+		if answer, ok := answers[name]; ok {
+			value, err := coerceAnswer(answer, prop)
+			if err != nil {
+				return tools.ElicitationActionDecline, unavailable(fmt.Sprintf("invalid answer for %s: %s", name, err), []string{name})
+			}
+			content[name] = value
+			continue
+		}
+		if def, ok := prop["default"]; ok {
+			content[name] = def
+			continue
+		}
+		if required[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return tools.ElicitationActionDecline, unavailable("no answer was provided for the required fields", missing)
+	}
+	return tools.ElicitationActionAccept, content
+}
+
+// coerceAnswer converts an answer given as a string on the command line to
+// the type declared by the schema of its field. Answers coming from an
+// answers file are already typed and are kept as they are.
+func coerceAnswer(answer any, prop map[string]any) (any, error) {
+	s, ok := answer.(string)
+	if !ok {
+		return answer, nil
+	}
+
+	switch prop["type"] {
+	case "boolean":
+		return strconv.ParseBool(s)
+	case "integer":
+		return strconv.Atoi(s)
+	case "number":
+		return strconv.ParseFloat(s, 64)
+	}
+
+	if enum, ok := prop["enum"].([]any); ok && !slices.Contains(enum, any(s)) {
+		return nil, fmt.Errorf("%q is not one of %v", s, enum)
+	}
+	return s, nil
+}
+
+// unavailable is the content of a declined elicitation in non-interactive
+// mode, telling the agent why no answer is available.
+func unavailable(reason string, missing []string) map[string]any {
+	content := map[string]any{
+		"unavailable": true,
+		"reason":      reason,
+	}
+	if len(missing) > 0 {
+		content["missing"] = missing
+	}
+	return content
+}
+
+// schemaMap converts the requested schema of an elicitation to a map.
+func schemaMap(schema any) map[string]any {
+	if m, ok := schema.(map[string]any); ok {
+		return m
+	}
+	if schema == nil {
+		return nil
+	}
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if json.Unmarshal(buf, &m) != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestParseAnswers(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "answers.json")
+	err := os.WriteFile(file, []byte(`{"env": "prod", "replicas": 3}`), 0o644)
+	assert.NilError(t, err)
+
+	answers, err := ParseAnswers(file, []string{"env=staging", "note=a=b"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, answers, map[string]any{"env": "staging", "replicas": float64(3), "note": "a=b"})
+
+	_, err = ParseAnswers("", []string{"env"})
+	assert.ErrorContains(t, err, `invalid answer "env"`)
+}
+
+func TestAnswerElicitation(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"env":      map[string]any{"type": "string", "enum": []any{"staging", "prod"}},
+			"replicas": map[string]any{"type": "integer", "default": float64(1)},
+			"confirm":  map[string]any{"type": "boolean"},
+			"comment":  map[string]any{"type": "string"},
+		},
+		"required": []any{"env", "confirm"},
+	}
+
+	tests := []struct {
+		name        string
+		schema      any
+		answers     map[string]any
+		wantAction  tools.ElicitationAction
+		wantContent map[string]any
+	}{
+		{
+			name:        "answers are typed after the schema and defaults fill the gaps",
+			schema:      schema,
+			answers:     map[string]any{"env": "prod", "confirm": "true"},
+			wantAction:  tools.ElicitationActionAccept,
+			wantContent: map[string]any{"env": "prod", "confirm": true, "replicas": float64(1)},
+		},
+		{
+			name:       "missing required fields",
+			schema:     schema,
+			answers:    map[string]any{"comment": "hi"},
+			wantAction: tools.ElicitationActionDecline,
+			wantContent: map[string]any{
+				"unavailable": true,
+				"reason":      "no answer was provided for the required fields",
+				"missing":     []string{"confirm", "env"},
+			},
+		},
+		{
+			name:       "answer not in the enum",
+			schema:     schema,
+			answers:    map[string]any{"env": "dev", "confirm": "true"},
+			wantAction: tools.ElicitationActionDecline,
+			wantContent: map[string]any{
+				"unavailable": true,
+				"reason":      `invalid answer for env: "dev" is not one of [staging prod]`,
+				"missing":     []string{"env"},
+			},
+		},
+		{
+			name:        "primitive schema",
+			schema:      map[string]any{"type": "string", "title": "color", "default": "blue"},
+			wantAction:  tools.ElicitationActionAccept,
+			wantContent: map[string]any{"color": "blue"},
+		},
+		{
+			name:        "free-form response",
+			answers:     map[string]any{"response": "yes"},
+			wantAction:  tools.ElicitationActionAccept,
+			wantContent: map[string]any{"response": "yes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event := runtime.ElicitationRequest("Deploy?", "", tt.schema, "", "", nil, "root").(*runtime.ElicitationRequestEvent)
+			action, content := answerElicitation(event, tt.answers)
+
+			assert.Equal(t, action, tt.wantAction)
+			assert.DeepEqual(t, content, tt.wantContent)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+)
+
+// Exit codes of non-interactive runs, so that automation can tell why a run
+// didn't complete.
+const (
+	ExitCodeSuccess          = 0
+	ExitCodeFailure          = 1
+	ExitCodeConfigInvalid    = 2
+	ExitCodeModelError       = 3
+	ExitCodeToolFailure      = 4
+	ExitCodeBudgetExceeded   = 5
+	ExitCodeMaxIterations    = 6
+	ExitCodeInputUnavailable = 7
+)
+
+var (
+	// ErrMaxIterations is returned when a run stops at its maximum number
+	// of iterations.
+	ErrMaxIterations = errors.New("maximum number of iterations reached")
+	// ErrBudgetExceeded is returned when a run costs more than its budget.
+	ErrBudgetExceeded = errors.New("cost budget exceeded")
+	// ErrInputUnavailable is returned when the agent asked questions that
+	// couldn't be answered.
+	ErrInputUnavailable = errors.New("the agent asked questions that could not be answered")
+)
+
+// ExitCode returns the exit code of a run that ended with the given error.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitCodeSuccess
+	case errors.Is(err, ErrMaxIterations):
+		return ExitCodeMaxIterations
+	case errors.Is(err, ErrBudgetExceeded):
+		return ExitCodeBudgetExceeded
+	case errors.Is(err, ErrInputUnavailable):
+		return ExitCodeInputUnavailable
+	}
+	if rtErr, ok := errors.AsType[RuntimeError](err); ok && rtErr.Code != 0 {
+		return rtErr.Code
+	}
+	return ExitCodeFailure
+}
+
+// exitStatuses names the exit codes in RunResult.
+var exitStatuses = map[int]string{
+	ExitCodeSuccess:          "success",
+	ExitCodeFailure:          "error",
+	ExitCodeConfigInvalid:    "config_invalid",
+	ExitCodeModelError:       "model_error",
+	ExitCodeToolFailure:      "tool_failure",
+	ExitCodeBudgetExceeded:   "budget_exceeded",
+	ExitCodeMaxIterations:    "max_iterations",
+	ExitCodeInputUnavailable: "input_unavailable",
+}
@@ -14,6 +14,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/docker/docker-agent/pkg/input"
+	"github.com/docker/docker-agent/pkg/termimage"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
@@ -23,6 +24,8 @@ type ConfirmationResult string
 const (
 	ConfirmationApprove        ConfirmationResult = "approve"
 	ConfirmationApproveSession ConfirmationResult = "approve_session"
+	ConfirmationApproveTool    ConfirmationResult = "approve_tool"
+	ConfirmationApprovePattern ConfirmationResult = "approve_pattern"
 	ConfirmationReject         ConfirmationResult = "reject"
 	ConfirmationAbort          ConfirmationResult = "abort"
 )
@@ -71,11 +74,19 @@ func (p *Printer) PrintToolCall(toolCall tools.ToolCall) {
 	p.Printf("\nCalling %s%s\n", bold(toolCall.Function.Name), formatToolCallArguments(toolCall.Function.Arguments))
 }
 
-// PrintToolCallWithConfirmation prints a tool call and prompts for confirmation
-func (p *Printer) PrintToolCallWithConfirmation(ctx context.Context, toolCall tools.ToolCall, rd io.Reader) ConfirmationResult {
+// PrintToolCallWithConfirmation prints a tool call and prompts for confirmation.
+// Besides approving it once or all the tool calls of the session, the user
+// can always allow the tool or, if pattern isn't the tool name, the tool calls
+// matching pattern.
+func (p *Printer) PrintToolCallWithConfirmation(ctx context.Context, toolCall tools.ToolCall, pattern string, rd io.Reader) ConfirmationResult {
 	p.Printf("\n%s\n", bold("🛠️ Tool call requires confirmation 🛠️"))
 	p.PrintToolCall(toolCall)
-	p.Printf("\n%s", bold("Can I run this tool? ([y]es/[a]ll/[n]o): "))
+	withPattern := pattern != toolCall.Function.Name
+	if withPattern {
+		p.Printf("\n%s", bold("Can I run this tool? ([y]es/[a]ll/always [t]his tool/always [p]attern %s/[n]o): ", pattern))
+	} else {
+		p.Printf("\n%s", bold("Can I run this tool? ([y]es/[a]ll/always [t]his tool/[n]o): "))
+	}
 
 	if !isatty.IsTerminal(os.Stdout.Fd()) {
 		return ConfirmationReject
@@ -101,6 +112,14 @@ func (p *Printer) PrintToolCallWithConfirmation(ctx context.Context, toolCall to
 			case 'a', 'A':
 				p.Print(bold("Yes to all 👍"))
 				return ConfirmationApproveSession
+			case 't', 'T':
+				p.Print(bold("Always 👍"))
+				return ConfirmationApproveTool
+			case 'p', 'P':
+				if withPattern {
+					p.Print(bold("Always 👍"))
+					return ConfirmationApprovePattern
+				}
 			case 'n', 'N':
 				p.Print(bold("No 👎"))
 				return ConfirmationReject
@@ -125,6 +144,13 @@ func (p *Printer) PrintToolCallWithConfirmation(ctx context.Context, toolCall to
 		return ConfirmationApprove
 	case "a":
 		return ConfirmationApproveSession
+	case "t":
+		return ConfirmationApproveTool
+	case "p":
+		if withPattern {
+			return ConfirmationApprovePattern
+		}
+		return ConfirmationReject
 	case "n":
 		return ConfirmationReject
 	default:
@@ -133,6 +159,19 @@ func (p *Printer) PrintToolCallWithConfirmation(ctx context.Context, toolCall to
 	}
 }
 
+// PrintPlan prints the tool calls a run simulated instead of running them,
+// in order: the plan of actions it would carry out.
+func (p *Printer) PrintPlan(plan []tools.ToolCall) {
+	if len(plan) == 0 {
+		return
+	}
+	p.Printf("\n\n%s\n", bold("--- Plan: %d simulated tool call(s), nothing was run ---", len(plan)))
+	for i, toolCall := range plan {
+		p.Printf("%d. %s %s\n", i+1, bold(toolCall.Function.Name), toolCall.Function.Arguments)
+	}
+	p.Println("Run the session again without --simulate to carry out the plan, e.g. with --continue.")
+}
+
 // PrintToolCallResponse prints a tool call response
 func (p *Printer) PrintToolCallResponse(toolCall tools.ToolCall, response string) {
 	p.Printf("\n%s response%s\n", bold(toolCall.Function.Name), formatToolCallResponse(response))
@@ -160,6 +199,54 @@ func (p *Printer) PromptMaxIterationsContinue(ctx context.Context, maxIterations
 	}
 }
 
+// PromptProjectTrust asks the user whether to trust a project whose settings
+// auto-approve tool calls.
+func (p *Printer) PromptProjectTrust(ctx context.Context, root string, settings []string) ConfirmationResult {
+	p.Printf("\n%s\n", bold("The project "+root+" auto-approves tool calls:"))
+	for _, setting := range settings {
+		p.Printf("  %s\n", setting)
+	}
+	p.Printf("\n%s (y/n): ", "Do you trust this project? Otherwise these settings are ignored")
+
+	response, err := input.ReadLine(ctx, os.Stdin)
+	if err != nil {
+		p.Println("\nFailed to read input, the settings are ignored.")
+		return ConfirmationAbort
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response == "y" || response == "yes" {
+		p.Print("✓ Project trusted\n\n")
+		return ConfirmationApprove
+	}
+	p.Print("The settings are ignored.\n\n")
+	return ConfirmationReject
+}
+
+// PromptToolResultApproval asks the user whether the result of a tool call,
+// flagged by content filters, can be sent to the model.
+func (p *Printer) PromptToolResultApproval(ctx context.Context, toolName string, filters, matches []string) ConfirmationResult {
+	p.Printf("\n%s\n", bold("The result of "+toolName+" looks like a prompt injection ("+strings.Join(filters, ", ")+"):"))
+	for _, match := range matches {
+		p.Printf("  %q\n", match)
+	}
+	p.Printf("\n%s (y/n): ", "Send it to the model anyway? Otherwise it's withheld")
+
+	response, err := input.ReadLine(ctx, os.Stdin)
+	if err != nil {
+		p.Println("\nFailed to read input, the result is withheld.")
+		return ConfirmationAbort
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response == "y" || response == "yes" {
+		p.Print("✓ Result sent\n\n")
+		return ConfirmationApprove
+	}
+	p.Print("The result is withheld.\n\n")
+	return ConfirmationReject
+}
+
 // PromptOAuthAuthorization prompts the user for OAuth authorization
 func (p *Printer) PromptOAuthAuthorization(ctx context.Context, serverURL string) ConfirmationResult {
 	p.Println("\n🔐 OAuth Authorization Required")
@@ -186,6 +273,36 @@ func (p *Printer) PromptOAuthAuthorization(ctx context.Context, serverURL string
 	}
 }
 
+// PrintElicitationAnswer prints a question asked by the agent and the answer
+// given to it in non-interactive mode
+func (p *Printer) PrintElicitationAnswer(message string, action tools.ElicitationAction, content map[string]any) {
+	p.Printf("\n%s %s\n", bold("Question:"), message)
+	if action != tools.ElicitationActionAccept {
+		p.Printf("No answer available (%s)\n", content["reason"])
+		return
+	}
+	buf, _ := json.Marshal(content)
+	p.Printf("%s %s\n", bold("Answer:"), formatToolCallArguments(string(buf)))
+}
+
+// PrintImages displays the images returned by a tool, when printing to a
+// terminal
+func (p *Printer) PrintImages(images []tools.MediaContent) {
+	f, ok := p.out.(*os.File)
+	if !ok || !isatty.IsTerminal(f.Fd()) {
+		return
+	}
+
+	protocol := termimage.Detect()
+	for _, image := range images {
+		img, err := termimage.Decode(image.Data)
+		if err != nil {
+			continue
+		}
+		p.Print(img.Inline(protocol))
+	}
+}
+
 func formatToolCallArguments(arguments string) string {
 	if arguments == "" {
 		return "()"
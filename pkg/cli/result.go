@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// OutputFormat is the format of the output of a non-interactive run.
+type OutputFormat string
+
+const (
+	// OutputText prints the conversation for humans.
+	OutputText OutputFormat = "text"
+	// OutputJSON prints a single RunResult once the run is over.
+	OutputJSON OutputFormat = "json"
+	// OutputStreamJSON prints every runtime event as a line of JSON.
+	OutputStreamJSON OutputFormat = "stream-json"
+)
+
+// OutputFormats lists the supported output formats.
+var OutputFormats = []OutputFormat{OutputText, OutputJSON, OutputStreamJSON}
+
+// ParseOutputFormat parses the value of the --output flag.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	format := OutputFormat(s)
+	if !slices.Contains(OutputFormats, format) {
+		return "", fmt.Errorf("invalid output format %q: must be one of text, json, stream-json", s)
+	}
+	return format, nil
+}
+
+// Statuses of a ToolCallSummary.
+const (
+	ToolCallPending  = "pending"
+	ToolCallSuccess  = "success"
+	ToolCallError    = "error"
+	ToolCallRejected = "rejected"
+	// ToolCallSimulated is the status of the tool calls simulated instead
+	// of run, with --simulate.
+	ToolCallSimulated = "simulated"
+)
+
+// RunResult is the machine-readable summary of a non-interactive run,
+// printed with --output json.
+type RunResult struct {
+	// Status names the exit code: success, error, model_error,
+	// tool_failure, budget_exceeded, max_iterations or input_unavailable.
+	Status string `json:"status"`
+	// ExitCode is the exit code of the command.
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+	// ErrorKind is the kind of model errors, when known: context_length,
+	// authentication, content_filter, overloaded, rate_limit, quota or
+	// model_not_found.
+	ErrorKind string `json:"error_kind,omitempty"`
+
+	SessionID string `json:"session_id"`
+	// FinalMessage is the last answer of the agent the user talks to.
+	FinalMessage string            `json:"final_message"`
+	Agents       []AgentSummary    `json:"agents"`
+	ToolCalls    []ToolCallSummary `json:"tool_calls"`
+	Usage        UsageSummary      `json:"usage"`
+	// Turns is the usage of every model call, in order, to find the ones
+	// that cost the most.
+	Turns []TurnSummary `json:"turns"`
+	// Artifacts are the files registered by tools during the run. With
+	// --artifacts-dir, their path is the path of the copy.
+	Artifacts []session.Artifact `json:"artifacts,omitempty"`
+}
+
+// AgentSummary summarizes the part of the transcript produced by one agent.
+type AgentSummary struct {
+	Name string `json:"name"`
+	// Messages is the number of messages the agent produced.
+	Messages    int          `json:"messages"`
+	ToolCalls   int          `json:"tool_calls"`
+	LastMessage string       `json:"last_message,omitempty"`
+	Usage       UsageSummary `json:"usage"`
+}
+
+// ToolCallSummary describes a tool call made during the run.
+type ToolCallSummary struct {
+	ID        string          `json:"id"`
+	Agent     string          `json:"agent"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	// Status is pending, success, error, rejected or simulated.
+	Status string `json:"status"`
+}
+
+// TurnSummary is the usage of one model call of the run.
+type TurnSummary struct {
+	Agent string `json:"agent"`
+	Model string `json:"model,omitempty"`
+	UsageSummary
+	LatencyMs int64 `json:"latency_ms"`
+	// ResponseID is the ID the provider gave to the response, to find the
+	// request in its logs.
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+// UsageSummary is the token usage and cost of a run or of an agent.
+type UsageSummary struct {
+	InputTokens       int64   `json:"input_tokens"`
+	OutputTokens      int64   `json:"output_tokens"`
+	CachedInputTokens int64   `json:"cached_input_tokens"`
+	CacheWriteTokens  int64   `json:"cache_write_tokens"`
+	ReasoningTokens   int64   `json:"reasoning_tokens"`
+	Cost              float64 `json:"cost"`
+}
+
+func (u *UsageSummary) add(m *runtime.MessageUsage) {
+	u.InputTokens += m.InputTokens
+	u.OutputTokens += m.OutputTokens
+	u.CachedInputTokens += m.CachedInputTokens
+	u.CacheWriteTokens += m.CacheWriteTokens
+	u.ReasoningTokens += m.ReasoningTokens
+	u.Cost += m.Cost
+}
+
+// resultCollector builds a RunResult from the events of a run.
+type resultCollector struct {
+	result RunResult
+	// pending accumulates the streamed content of the message being
+	// generated, by session. Sub-agents run in their own sessions.
+	pending map[string]*pendingMessage
+}
+
+type pendingMessage struct {
+	agent   string
+	content strings.Builder
+}
+
+func newResultCollector(sessionID string) *resultCollector {
+	return &resultCollector{
+		result: RunResult{
+			SessionID: sessionID,
+			Agents:    []AgentSummary{},
+			ToolCalls: []ToolCallSummary{},
+			Turns:     []TurnSummary{},
+		},
+		pending: map[string]*pendingMessage{},
+	}
+}
+
+func (c *resultCollector) agent(name string) *AgentSummary {
+	i := slices.IndexFunc(c.result.Agents, func(a AgentSummary) bool { return a.Name == name })
+	if i < 0 {
+		c.result.Agents = append(c.result.Agents, AgentSummary{Name: name})
+		i = len(c.result.Agents) - 1
+	}
+	return &c.result.Agents[i]
+}
+
+func (c *resultCollector) toolCall(agentName string, id, name, arguments string) *ToolCallSummary {
+	i := slices.IndexFunc(c.result.ToolCalls, func(tc ToolCallSummary) bool { return tc.ID == id })
+	if i < 0 {
+		args := json.RawMessage(arguments)
+		if !json.Valid(args) {
+			args, _ = json.Marshal(arguments)
+		}
+		c.result.ToolCalls = append(c.result.ToolCalls, ToolCallSummary{
+			ID:        id,
+			Agent:     agentName,
+			Name:      name,
+			Arguments: args,
+			Status:    ToolCallPending,
+		})
+		c.agent(agentName).ToolCalls++
+		i = len(c.result.ToolCalls) - 1
+	}
+	return &c.result.ToolCalls[i]
+}
+
+func (c *resultCollector) add(event runtime.Event) {
+	switch e := event.(type) {
+	case *runtime.AgentChoiceEvent:
+		m, ok := c.pending[e.SessionID]
+		if !ok {
+			m = &pendingMessage{agent: e.AgentName}
+			c.pending[e.SessionID] = m
+		}
+		m.content.WriteString(e.Content)
+	case *runtime.TokenUsageEvent:
+		// A usage event closes every model call.
+		c.flush(e.SessionID)
+		if e.Usage != nil && e.Usage.LastMessage != nil {
+			c.agent(e.AgentName).Usage.add(e.Usage.LastMessage)
+			c.result.Usage.add(e.Usage.LastMessage)
+
+			turn := TurnSummary{
+				Agent:      e.AgentName,
+				Model:      e.Usage.LastMessage.Model,
+				LatencyMs:  e.Usage.LastMessage.LatencyMs,
+				ResponseID: e.Usage.LastMessage.ResponseID,
+			}
+			turn.add(e.Usage.LastMessage)
+			c.result.Turns = append(c.result.Turns, turn)
+		}
+	case *runtime.ToolCallEvent:
+		c.toolCall(e.AgentName, e.ToolCall.ID, e.ToolCall.Function.Name, e.ToolCall.Function.Arguments)
+	case *runtime.ToolCallResponseEvent:
+		tc := c.toolCall(e.AgentName, e.ToolCall.ID, e.ToolCall.Function.Name, e.ToolCall.Function.Arguments)
+		switch {
+		case tc.Status == ToolCallRejected, tc.Status == ToolCallSimulated:
+			// The response explains the rejection, or the simulation, to the model.
+		case e.Result != nil && e.Result.IsError:
+			tc.Status = ToolCallError
+		default:
+			tc.Status = ToolCallSuccess
+		}
+	case *runtime.ToolCallSimulatedEvent:
+		c.toolCall(e.AgentName, e.ToolCall.ID, e.ToolCall.Function.Name, e.ToolCall.Function.Arguments).Status = ToolCallSimulated
+	case *runtime.GuardrailEvent:
+		// The answer streamed so far was withheld.
+		if e.Discarded {
+			delete(c.pending, e.SessionID)
+		}
+	case *runtime.StreamStoppedEvent:
+		c.flush(e.SessionID)
+	}
+}
+
+// reject records a tool call that was not approved.
+func (c *resultCollector) reject(e *runtime.ToolCallConfirmationEvent) {
+	c.toolCall(e.AgentName, e.ToolCall.ID, e.ToolCall.Function.Name, e.ToolCall.Function.Arguments).Status = ToolCallRejected
+}
+
+// flush records the message streamed in a session.
+func (c *resultCollector) flush(sessionID string) {
+	m, ok := c.pending[sessionID]
+	if !ok {
+		return
+	}
+	delete(c.pending, sessionID)
+
+	content := strings.TrimSpace(m.content.String())
+	if content == "" {
+		return
+	}
+	agent := c.agent(m.agent)
+	agent.Messages++
+	agent.LastMessage = content
+	if sessionID == "" || sessionID == c.result.SessionID {
+		c.result.FinalMessage = content
+	}
+}
+
+// finish returns the result of a run that ended with the given error.
+func (c *resultCollector) finish(err error) RunResult {
+	for sessionID := range c.pending {
+		c.flush(sessionID)
+	}
+
+	result := c.result
+	result.ExitCode = ExitCode(err)
+	result.Status = exitStatuses[result.ExitCode]
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if rtErr, ok := errors.AsType[RuntimeError](err); ok {
+		result.ErrorKind = rtErr.Kind
+	}
+	return result
+}
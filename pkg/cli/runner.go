@@ -10,18 +10,26 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/input"
+	"github.com/docker/docker-agent/pkg/permissions"
 	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/telemetry"
+	"github.com/docker/docker-agent/pkg/tools"
 )
 
 // RuntimeError wraps runtime errors to distinguish them from usage errors
 type RuntimeError struct {
 	Err error
+	// Code is the exit code for the error, if more specific than
+	// ExitCodeFailure.
+	Code int
+	// Kind is the kind of model errors, when known.
+	Kind string
 }
 
 func (e RuntimeError) Error() string {
@@ -67,17 +75,76 @@ func handleMaxIterationsAutoApprove(autoApprove bool, autoExtensions *int, maxIt
 
 // Config holds configuration for running an agent in CLI mode
 type Config struct {
-	AppName        string
-	AttachmentPath string
-	AutoApprove    bool
-	HideToolCalls  bool
-	OutputJSON     bool
+	AppName string
+	// AttachmentPaths are files attached to every message.
+	AttachmentPaths []string
+	// Stdin is content piped to the command, attached to the first message.
+	Stdin         string
+	AutoApprove   bool
+	HideToolCalls bool
+	// Output is the output format. The zero value prints text.
+	Output OutputFormat
+	// Answers are used to answer elicitation requests, e.g. from the
+	// user_prompt tool, since there is no one to ask in non-interactive mode.
+	Answers map[string]any
+	// MaxCost stops the run once it costs more than this many dollars.
+	// Zero means no limit.
+	MaxCost float64
+	// ArtifactsDir is the directory the artifacts of the session are copied
+	// to once the run is over. Empty means they are not copied.
+	ArtifactsDir string
+	// Continue continues the run of the session before sending the user
+	// messages, e.g. after it paused at its max_iterations limit.
+	Continue bool
 }
 
 // Run executes an agent in non-TUI mode, handling user input and runtime events.
 // userMessages contains the user messages to send. If a single message is "-",
 // input is read from stdin. If empty, an interactive prompt loop is started.
 func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess *session.Session, userMessages []string) error {
+	if cfg.Output != OutputJSON {
+		err := run(ctx, out, cfg, rt, sess, userMessages, nil)
+		artifacts, copyErr := exportArtifacts(cfg, sess)
+		if cfg.ArtifactsDir != "" && cfg.Output == OutputText {
+			for _, artifact := range artifacts {
+				out.Printf("Artifact: %s\n", artifact.Path)
+			}
+		}
+		return cmp.Or(err, copyErr)
+	}
+
+	collector := newResultCollector(sess.ID)
+	err := run(ctx, out, cfg, rt, sess, userMessages, collector)
+	artifacts, copyErr := exportArtifacts(cfg, sess)
+	err = cmp.Or(err, copyErr)
+
+	result := collector.finish(err)
+	result.Artifacts = artifacts
+	buf, marshalErr := json.Marshal(result)
+	if marshalErr != nil {
+		return marshalErr
+	}
+	out.Println(string(buf))
+
+	// The error is part of the result: don't print it again.
+	if _, ok := errors.AsType[RuntimeError](err); err != nil && !ok {
+		return RuntimeError{Err: err}
+	}
+	return err
+}
+
+// exportArtifacts returns the artifacts of the session, after copying them
+// to the artifacts directory if one is configured.
+func exportArtifacts(cfg Config, sess *session.Session) ([]session.Artifact, error) {
+	if cfg.ArtifactsDir == "" {
+		return sess.GetArtifacts(), nil
+	}
+	return CopyArtifacts(cfg.ArtifactsDir, sess.GetArtifacts())
+}
+
+// run runs the agent. With a collector, events are collected into a
+// RunResult instead of being printed.
+func run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess *session.Session, userMessages []string, collector *resultCollector) error {
 	// Create a cancellable context for this agentic loop and wire Ctrl+C to cancel it
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -92,41 +159,91 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 	// If the last received event was an error, return it. That way the exit code
 	// will be non-zero if the agent failed.
 	var lastErr error
+	// unanswered is set when the agent asked questions that couldn't be
+	// answered, which makes the result of the run doubtful.
+	var unanswered bool
+	stdin := cfg.Stdin
+
+	var cost float64
+	overBudget := func(event runtime.Event) bool {
+		e, ok := event.(*runtime.TokenUsageEvent)
+		if !ok || e.Usage == nil || e.Usage.LastMessage == nil {
+			return false
+		}
+		cost += e.Usage.LastMessage.Cost
+		return cfg.MaxCost > 0 && cost > cfg.MaxCost
+	}
+	budgetError := func() error {
+		cancel()
+		return RuntimeError{
+			Err:  fmt.Errorf("%w: spent $%.4f, the limit is $%.4f", ErrBudgetExceeded, cost, cfg.MaxCost),
+			Code: ExitCodeBudgetExceeded,
+		}
+	}
 
+	// continuing is set until the run of the session is continued, without
+	// a new message.
+	continuing := cfg.Continue
 	oneLoop := func(text string, rd io.Reader) error {
 		autoExtensions := 0
 
 		userInput := strings.TrimSpace(text)
-		if userInput == "" {
+		switch {
+		case continuing:
+			continuing = false
+		case userInput == "":
 			return nil
+		default:
+			sess.AddMessage(PrepareUserMessage(ctx, rt, userInput, Attachments{Paths: cfg.AttachmentPaths, Stdin: stdin}))
+			stdin = ""
 		}
 
-		sess.AddMessage(PrepareUserMessage(ctx, rt, userInput, cfg.AttachmentPath))
-
-		if cfg.OutputJSON {
+		if cfg.Output == OutputJSON || cfg.Output == OutputStreamJSON {
 			for event := range rt.RunStream(ctx, sess) {
 				switch e := event.(type) {
 				case *runtime.ToolCallConfirmationEvent:
 					if !cfg.AutoApprove {
+						if collector != nil {
+							collector.reject(e)
+						}
 						rt.Resume(ctx, runtime.ResumeReject(""))
 					}
+				case *runtime.ToolResultConfirmationEvent:
+					// Nobody can look at the flagged text: withhold it.
+					rt.Resume(ctx, runtime.ResumeReject(""))
 				case *runtime.MaxIterationsReachedEvent:
 					switch handleMaxIterationsAutoApprove(cfg.AutoApprove, &autoExtensions, e.MaxIterations) {
 					case maxIterContinue:
 						rt.Resume(ctx, runtime.ResumeApprove())
 					default: // maxIterStop or maxIterPrompt (no interactive prompt in JSON mode)
 						rt.Resume(ctx, runtime.ResumeReject(""))
-						return nil
+						return ErrMaxIterations
+					}
+				case *runtime.ElicitationRequestEvent:
+					if _, isOAuth := e.Meta["cagent/server_url"]; isOAuth {
+						_ = rt.ResumeElicitation(ctx, tools.ElicitationActionDecline, nil)
+					} else {
+						action, content := answerElicitation(e, cfg.Answers)
+						unanswered = unanswered || action == tools.ElicitationActionDecline
+						_ = rt.ResumeElicitation(ctx, action, content)
 					}
 				case *runtime.ErrorEvent:
-					return fmt.Errorf("%s", e.Error)
+					return eventError(e)
+				}
+
+				if collector != nil {
+					collector.add(event)
+				} else {
+					buf, err := json.Marshal(event)
+					if err != nil {
+						return err
+					}
+					out.Println(string(buf))
 				}
 
-				buf, err := json.Marshal(event)
-				if err != nil {
-					return err
+				if overBudget(event) {
+					return budgetError()
 				}
-				out.Println(string(buf))
 			}
 
 			return nil
@@ -135,7 +252,14 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 		firstLoop := true
 		lastAgent := rt.CurrentAgentName()
 		var lastConfirmedToolCallID string
+		// plan are the tool calls simulated instead of run.
+		var plan []tools.ToolCall
+		defer func() { out.PrintPlan(plan) }()
 		for event := range rt.RunStream(ctx, sess) {
+			if overBudget(event) {
+				return budgetError()
+			}
+
 			agentName := event.GetAgentName()
 			if agentName != "" && (firstLoop || lastAgent != agentName) {
 				if !firstLoop {
@@ -150,8 +274,15 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 				out.Print(e.Content)
 			case *runtime.AgentChoiceReasoningEvent:
 				out.Print(e.Content)
+			case *runtime.GuardrailEvent:
+				out.Printf("\n[guardrail %s: %s, %s]\n", e.Guardrail, e.Reason, e.Action)
+			case *runtime.ContentFilteredEvent:
+				out.Printf("\n[content filter %s: the result of %s looks like a prompt injection, %s]\n", strings.Join(e.Filters, ", "), e.ToolCall.Function.Name, e.Action)
+			case *runtime.ToolCallSimulatedEvent:
+				plan = append(plan, e.ToolCall)
 			case *runtime.ToolCallConfirmationEvent:
-				result := out.PrintToolCallWithConfirmation(ctx, e.ToolCall, rd)
+				pattern := permissions.SuggestPattern(e.ToolCall.Function.Name, e.ToolCall.Function.Arguments)
+				result := out.PrintToolCallWithConfirmation(ctx, e.ToolCall, pattern, rd)
 				// If interrupted, skip resuming; the runtime will notice context cancellation and stop
 				if ctx.Err() != nil {
 					continue
@@ -163,6 +294,10 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 				case ConfirmationApproveSession:
 					sess.ToolsApproved = true
 					rt.Resume(ctx, runtime.ResumeApproveSession())
+				case ConfirmationApproveTool:
+					rt.Resume(ctx, runtime.ResumeApproveAlways(e.ToolCall.Function.Name))
+				case ConfirmationApprovePattern:
+					rt.Resume(ctx, runtime.ResumeApproveAlways(pattern))
 				case ConfirmationReject:
 					rt.Resume(ctx, runtime.ResumeReject(""))
 					lastConfirmedToolCallID = "" // Clear on reject since tool won't execute
@@ -171,6 +306,16 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 					cancel()
 					continue
 				}
+			case *runtime.ToolResultConfirmationEvent:
+				result := out.PromptToolResultApproval(ctx, e.ToolCall.Function.Name, e.Filters, e.Matches)
+				if ctx.Err() != nil {
+					continue
+				}
+				if result == ConfirmationApprove {
+					rt.Resume(ctx, runtime.ResumeApprove())
+				} else {
+					rt.Resume(ctx, runtime.ResumeReject(""))
+				}
 			case *runtime.ToolCallEvent:
 				if cfg.HideToolCalls {
 					continue
@@ -184,6 +329,9 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 					continue
 				}
 				out.PrintToolCallResponse(e.ToolCall, e.Response)
+				if e.Result != nil {
+					out.PrintImages(e.Result.Images)
+				}
 				// Clear the confirmed ID after the tool completes
 				if e.ToolCall.ID == lastConfirmedToolCallID {
 					lastConfirmedToolCallID = ""
@@ -193,7 +341,7 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 				if strings.Contains(lowerErr, "context cancel") && ctx.Err() != nil { // treat Ctrl+C cancellations as non-errors
 					lastErr = nil
 				} else {
-					lastErr = fmt.Errorf("%s", e.Error)
+					lastErr = eventError(e)
 					out.PrintError(lastErr)
 				}
 			case *runtime.MaxIterationsReachedEvent:
@@ -202,7 +350,7 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 					rt.Resume(ctx, runtime.ResumeApprove())
 				case maxIterStop:
 					rt.Resume(ctx, runtime.ResumeReject(""))
-					return nil
+					return ErrMaxIterations
 				case maxIterPrompt:
 					result := out.PromptMaxIterationsContinue(ctx, e.MaxIterations)
 					switch result {
@@ -210,18 +358,20 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 						rt.Resume(ctx, runtime.ResumeApprove())
 					case ConfirmationReject:
 						rt.Resume(ctx, runtime.ResumeReject(""))
-						return nil
+						return ErrMaxIterations
 					case ConfirmationAbort:
 						rt.Resume(ctx, runtime.ResumeReject(""))
-						return nil
+						return ErrMaxIterations
 					}
 				}
 			case *runtime.ElicitationRequestEvent:
 				serverURL, ok := e.Meta["cagent/server_url"].(string)
 				if !ok || serverURL == "" {
-					slog.Warn("Skipping elicitation: missing or invalid server_url (non-interactive session?)")
-					_ = rt.ResumeElicitation(ctx, "decline", nil)
-					return nil
+					action, content := answerElicitation(e, cfg.Answers)
+					unanswered = unanswered || action == tools.ElicitationActionDecline
+					out.PrintElicitationAnswer(e.Message, action, content)
+					_ = rt.ResumeElicitation(ctx, action, content)
+					continue
 				}
 
 				result := out.PromptOAuthAuthorization(ctx, serverURL)
@@ -240,14 +390,19 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 			}
 		}
 
-		// Wrap runtime errors to prevent duplicate error messages and usage display
-		if lastErr != nil {
-			return RuntimeError{Err: lastErr}
+		// lastErr is a RuntimeError, to prevent duplicate error messages and usage display
+		return lastErr
+	}
+
+	if continuing {
+		if err := oneLoop("", os.Stdin); err != nil {
+			return err
 		}
-		return nil
 	}
 
 	switch {
+	case cfg.Continue && len(userMessages) == 0:
+		// The run was continued and there's no message to send.
 	case len(userMessages) == 1 && userMessages[0] == "-":
 		// Single "-" argument: read from stdin
 		buf, err := io.ReadAll(os.Stdin)
@@ -288,35 +443,60 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 		}
 	}
 
-	// Wrap runtime errors to prevent duplicate error messages and usage display
 	if lastErr != nil {
-		return RuntimeError{Err: lastErr}
+		return lastErr
+	}
+	if unanswered {
+		return ErrInputUnavailable
 	}
 	return nil
 }
 
+// eventError converts an error event to a RuntimeError, with the exit code
+// matching the kind of error.
+func eventError(e *runtime.ErrorEvent) error {
+	err := RuntimeError{Err: errors.New(e.Text()), Kind: e.Kind}
+	switch e.Code {
+	case runtime.ErrorCodeModel:
+		err.Code = ExitCodeModelError
+	case runtime.ErrorCodeTools:
+		err.Code = ExitCodeToolFailure
+	}
+	return err
+}
+
+// Attachments are the files and piped content attached to a message.
+type Attachments struct {
+	// Paths are the paths of the attached files.
+	Paths []string
+	// Stdin is the content piped to the command, if any.
+	Stdin string
+}
+
 // PrepareUserMessage resolves commands, parses /attach directives, and creates
-// a user message with optional image attachment. This is the common flow for
+// a user message with the given attachments. This is the common flow for
 // both TUI and CLI modes.
 //
 // Parameters:
 //   - ctx: context for command resolution
 //   - rt: runtime for command resolution
 //   - userInput: the raw user input (may contain /commands and /attach directives)
-//   - globalAttachPath: attachment path from --attach flag (can be empty)
+//   - attachments: attachments from the --attach flags and stdin (can be empty)
 //
 // Returns the prepared session.Message ready to be added to the session.
-func PrepareUserMessage(ctx context.Context, rt runtime.Runtime, userInput, globalAttachPath string) *session.Message {
+func PrepareUserMessage(ctx context.Context, rt runtime.Runtime, userInput string, attachments Attachments) *session.Message {
 	// Resolve any /command to its prompt text
 	resolvedContent := runtime.ResolveCommand(ctx, rt, userInput)
 
 	// Parse for /attach commands in the message
 	messageText, attachPath := ParseAttachCommand(resolvedContent)
 
-	// Use either the per-message attachment or the global one
-	finalAttachPath := cmp.Or(attachPath, globalAttachPath)
+	// Attach the per-message attachment along with the global ones
+	if attachPath != "" {
+		attachments.Paths = append(slices.Clip(attachments.Paths), attachPath)
+	}
 
-	return CreateUserMessageWithAttachment(messageText, finalAttachPath)
+	return CreateUserMessageWithAttachments(messageText, attachments)
 }
 
 // ParseAttachCommand parses user input for /attach commands
@@ -373,24 +553,42 @@ func ParseAttachCommand(userInput string) (messageText, attachPath string) {
 	return messageText, attachPath
 }
 
-// CreateUserMessageWithAttachment creates a user message with optional file attachment.
-// Text files are inlined directly as text content for cross-provider compatibility.
-// Binary files (images, PDFs) are stored as file references for provider-specific upload.
-func CreateUserMessageWithAttachment(userContent, attachmentPath string) *session.Message {
-	if attachmentPath == "" {
-		return session.UserMessage(userContent)
+// CreateUserMessageWithAttachments creates a user message with optional
+// attachments, the same way the TUI editor does. Text files and stdin are
+// inlined as text content for cross-provider compatibility. Images are
+// inlined as data URLs. Other binary files (PDFs) are stored as file
+// references for provider-specific upload.
+func CreateUserMessageWithAttachments(userContent string, attachments Attachments) *session.Message {
+	// Keep everything in one text block, so that the model sees the content
+	// of the files together with the message.
+	var text strings.Builder
+	var binaryParts []chat.MessagePart
+
+	if stdin := strings.TrimRight(attachments.Stdin, "\n"); stdin != "" {
+		fmt.Fprintf(&text, "\n\n<attached_file path=%q>\n%s\n</attached_file>", "stdin", stdin)
 	}
 
-	// Validate file exists
-	absPath, err := filepath.Abs(attachmentPath)
-	if err != nil {
-		slog.Warn("Failed to get absolute path for attachment", "path", attachmentPath, "error", err)
-		return session.UserMessage(userContent)
+	for _, path := range attachments.Paths {
+		if path == "" {
+			continue
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			slog.Warn("Failed to get absolute path for attachment", "path", path, "error", err)
+			continue
+		}
+		attachment, err := chat.AttachFile(absPath)
+		if err != nil {
+			slog.Warn("Skipping attachment", "path", absPath, "reason", err)
+			continue
+		}
+		text.WriteString(attachment.Text)
+		if attachment.Part != nil {
+			binaryParts = append(binaryParts, *attachment.Part)
+		}
 	}
 
-	fi, err := os.Stat(absPath)
-	if err != nil {
-		slog.Warn("Attachment file not accessible", "path", absPath, "error", err)
+	if text.Len() == 0 && len(binaryParts) == 0 {
 		return session.UserMessage(userContent)
 	}
 
@@ -400,42 +598,21 @@ func CreateUserMessageWithAttachment(userContent, attachmentPath string) *sessio
 	multiContent := []chat.MessagePart{
 		{
 			Type: chat.MessagePartTypeText,
-			Text: textContent,
+			Text: textContent + text.String(),
 		},
 	}
+	multiContent = append(multiContent, binaryParts...)
 
-	switch {
-	case chat.IsTextFile(absPath):
-		// Text files are inlined directly as text content.
-		if fi.Size() > chat.MaxInlineFileSize {
-			slog.Warn("Attachment text file too large to inline", "path", absPath, "size", fi.Size())
-			return session.UserMessage(userContent)
-		}
-		content, err := chat.ReadFileForInline(absPath)
-		if err != nil {
-			slog.Warn("Failed to read attachment file", "path", absPath, "error", err)
-			return session.UserMessage(userContent)
-		}
-		multiContent = append(multiContent, chat.MessagePart{
-			Type: chat.MessagePartTypeText,
-			Text: content,
-		})
+	return session.UserMessage(textContent, multiContent...)
+}
 
-	default:
-		// Binary files (images, PDFs) are kept as file references.
-		mimeType := chat.DetectMimeType(absPath)
-		if !chat.IsSupportedMimeType(mimeType) {
-			slog.Warn("Unsupported attachment file type", "path", absPath, "mime_type", mimeType)
-			return session.UserMessage(userContent)
+// ValidateAttachments checks that files can be attached, to report errors
+// before the agent starts.
+func ValidateAttachments(paths []string) error {
+	for _, path := range paths {
+		if _, err := chat.AttachFile(path); err != nil {
+			return fmt.Errorf("cannot attach %s: %w", path, err)
 		}
-		multiContent = append(multiContent, chat.MessagePart{
-			Type: chat.MessagePartTypeFile,
-			File: &chat.MessageFile{
-				Path:     absPath,
-				MimeType: mimeType,
-			},
-		})
 	}
-
-	return session.UserMessage(textContent, multiContent...)
+	return nil
 }
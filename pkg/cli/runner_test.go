@@ -3,11 +3,20 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
 	"gotest.tools/v3/assert"
 
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/modelerrors"
 	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/sessiontitle"
@@ -21,8 +30,9 @@ import (
 type mockRuntime struct {
 	events []runtime.Event
 
-	mu      sync.Mutex
-	resumes []runtime.ResumeRequest
+	mu           sync.Mutex
+	resumes      []runtime.ResumeRequest
+	elicitations []tools.ElicitationResult
 }
 
 func (m *mockRuntime) CurrentAgentName() string { return "test" }
@@ -37,7 +47,10 @@ func (m *mockRuntime) Run(context.Context, *session.Session) ([]session.Message,
 	return nil, nil
 }
 
-func (m *mockRuntime) ResumeElicitation(context.Context, tools.ElicitationAction, map[string]any) error {
+func (m *mockRuntime) ResumeElicitation(_ context.Context, action tools.ElicitationAction, content map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.elicitations = append(m.elicitations, tools.ElicitationResult{Action: action, Content: content})
 	return nil
 }
 func (m *mockRuntime) SessionStore() session.Store                                             { return nil }
@@ -123,7 +136,8 @@ func TestMaxIterationsAutoApproveSafetyCap(t *testing.T) {
 	cfg := Config{AutoApprove: true}
 
 	err := Run(t.Context(), out, cfg, rt, sess, []string{"hello"})
-	assert.NilError(t, err)
+	assert.ErrorIs(t, err, ErrMaxIterations)
+	assert.Equal(t, ExitCode(err), ExitCodeMaxIterations)
 
 	resumes := rt.getResumes()
 	assert.Equal(t, len(resumes), maxAutoExtensions+1)
@@ -148,7 +162,7 @@ func TestMaxIterationsAutoApproveJSONMode(t *testing.T) {
 	var buf bytes.Buffer
 	out := NewPrinter(&buf)
 	sess := session.New()
-	cfg := Config{AutoApprove: true, OutputJSON: true}
+	cfg := Config{AutoApprove: true, Output: OutputStreamJSON}
 
 	err := Run(t.Context(), out, cfg, rt, sess, []string{"hello"})
 	assert.NilError(t, err)
@@ -168,10 +182,11 @@ func TestMaxIterationsRejectInJSONModeWithoutYolo(t *testing.T) {
 	var buf bytes.Buffer
 	out := NewPrinter(&buf)
 	sess := session.New()
-	cfg := Config{AutoApprove: false, OutputJSON: true}
+	cfg := Config{AutoApprove: false, Output: OutputStreamJSON}
 
 	err := Run(t.Context(), out, cfg, rt, sess, []string{"hello"})
-	assert.NilError(t, err)
+	assert.ErrorIs(t, err, ErrMaxIterations)
+	assert.Equal(t, ExitCode(err), ExitCodeMaxIterations)
 
 	resumes := rt.getResumes()
 	assert.Equal(t, len(resumes), 1)
@@ -191,10 +206,11 @@ func TestMaxIterationsSafetyCapJSONMode(t *testing.T) {
 	var buf bytes.Buffer
 	out := NewPrinter(&buf)
 	sess := session.New()
-	cfg := Config{AutoApprove: true, OutputJSON: true}
+	cfg := Config{AutoApprove: true, Output: OutputStreamJSON}
 
 	err := Run(t.Context(), out, cfg, rt, sess, []string{"hello"})
-	assert.NilError(t, err)
+	assert.ErrorIs(t, err, ErrMaxIterations)
+	assert.Equal(t, ExitCode(err), ExitCodeMaxIterations)
 
 	resumes := rt.getResumes()
 	assert.Equal(t, len(resumes), maxAutoExtensions+1)
@@ -204,3 +220,255 @@ func TestMaxIterationsSafetyCapJSONMode(t *testing.T) {
 	}
 	assert.Equal(t, resumes[maxAutoExtensions].Type, runtime.ResumeTypeReject)
 }
+
+func TestElicitationAnsweredInExecMode(t *testing.T) {
+	t.Parallel()
+
+	for _, output := range []OutputFormat{OutputText, OutputStreamJSON, OutputJSON} {
+		rt := &mockRuntime{
+			events: []runtime.Event{
+				runtime.ElicitationRequest("Which environment?", "", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"env": map[string]any{"type": "string"}},
+					"required":   []any{"env"},
+				}, "", "", nil, "test"),
+				runtime.ElicitationRequest("Anything else?", "", nil, "", "", nil, "test"),
+			},
+		}
+
+		var buf bytes.Buffer
+		cfg := Config{Output: output, Answers: map[string]any{"env": "staging"}}
+
+		err := Run(t.Context(), NewPrinter(&buf), cfg, rt, session.New(), []string{"hello"})
+		assert.ErrorIs(t, err, ErrInputUnavailable)
+
+		// The run carries on after an elicitation that can't be answered,
+		// but doesn't succeed.
+		assert.Equal(t, len(rt.elicitations), 2)
+		assert.DeepEqual(t, rt.elicitations[0], tools.ElicitationResult{
+			Action:  tools.ElicitationActionAccept,
+			Content: map[string]any{"env": "staging"},
+		})
+		assert.Equal(t, rt.elicitations[1].Action, tools.ElicitationActionDecline)
+		assert.Equal(t, rt.elicitations[1].Content["unavailable"], true)
+	}
+}
+
+func TestRunResultInJSONMode(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New()
+	readFile := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "read_file", Arguments: `{"path":"go.mod"}`}}
+	shell := tools.ToolCall{ID: "call_2", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"rm -rf /"}`}}
+	usage := func(input, output int64, cost float64) *runtime.Usage {
+		return &runtime.Usage{LastMessage: &runtime.MessageUsage{
+			Usage: chat.Usage{InputTokens: input, OutputTokens: output},
+			Cost:  cost,
+		}}
+	}
+
+	rt := &mockRuntime{
+		events: []runtime.Event{
+			runtime.AgentChoice("root", sess.ID, "Let me check."),
+			runtime.NewTokenUsageEvent(sess.ID, "root", usage(10, 5, 0.01)),
+			runtime.ToolCall(readFile, tools.Tool{}, "root"),
+			runtime.ToolCallResponse(readFile, tools.Tool{}, tools.ResultSuccess("module x"), "module x", "root"),
+			runtime.ToolCallConfirmation(shell, tools.Tool{}, "root"),
+			runtime.ToolCallResponse(shell, tools.Tool{}, tools.ResultError("rejected"), "rejected", "root"),
+			runtime.AgentChoice("reviewer", "sub", "Looks good."),
+			runtime.NewTokenUsageEvent("sub", "reviewer", usage(3, 2, 0.002)),
+			runtime.AgentChoice("root", sess.ID, "The module is "),
+			runtime.AgentChoice("root", sess.ID, "x."),
+			runtime.NewTokenUsageEvent(sess.ID, "root", usage(20, 4, 0.02)),
+			runtime.StreamStopped(sess.ID, "root"),
+		},
+	}
+
+	var buf bytes.Buffer
+	err := Run(t.Context(), NewPrinter(&buf), Config{Output: OutputJSON}, rt, sess, []string{"hello"})
+	assert.NilError(t, err)
+
+	var result RunResult
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, result.Status, "success")
+	assert.Equal(t, result.ExitCode, 0)
+	assert.Equal(t, result.SessionID, sess.ID)
+	assert.Equal(t, result.FinalMessage, "The module is x.")
+
+	assert.Equal(t, len(result.ToolCalls), 2)
+	assert.Equal(t, result.ToolCalls[0].Name, "read_file")
+	assert.Equal(t, string(result.ToolCalls[0].Arguments), `{"path":"go.mod"}`)
+	assert.Equal(t, result.ToolCalls[0].Status, ToolCallSuccess)
+	assert.Equal(t, result.ToolCalls[1].Status, ToolCallRejected)
+
+	assert.Equal(t, len(result.Agents), 2)
+	assert.DeepEqual(t, result.Agents[0], AgentSummary{
+		Name:        "root",
+		Messages:    2,
+		ToolCalls:   2,
+		LastMessage: "The module is x.",
+		Usage:       UsageSummary{InputTokens: 30, OutputTokens: 9, Cost: 0.03},
+	})
+	assert.Equal(t, result.Agents[1].Name, "reviewer")
+	assert.Equal(t, result.Agents[1].LastMessage, "Looks good.")
+
+	assert.Equal(t, result.Usage.InputTokens, int64(33))
+	assert.Equal(t, result.Usage.OutputTokens, int64(11))
+
+	assert.Equal(t, len(result.Turns), 3)
+	assert.Equal(t, result.Turns[1].Agent, "reviewer")
+	assert.Equal(t, result.Turns[2].Agent, "root")
+	assert.Equal(t, result.Turns[2].InputTokens, int64(20))
+	assert.Equal(t, result.Turns[2].Cost, 0.02)
+}
+
+func TestRunResultInJSONModeOnError(t *testing.T) {
+	t.Parallel()
+
+	rt := &mockRuntime{
+		events: []runtime.Event{runtime.ErrorWithCode(runtime.ErrorCodeModel, "model unavailable")},
+	}
+
+	var buf bytes.Buffer
+	err := Run(t.Context(), NewPrinter(&buf), Config{Output: OutputJSON}, rt, session.New(), []string{"hello"})
+	assert.ErrorContains(t, err, "model unavailable")
+	assert.Equal(t, ExitCode(err), ExitCodeModelError)
+
+	var result RunResult
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, result.Status, "model_error")
+	assert.Equal(t, result.ExitCode, ExitCodeModelError)
+	assert.Equal(t, result.Error, "model unavailable")
+}
+
+func TestRunResultErrorKind(t *testing.T) {
+	t.Parallel()
+
+	rt := &mockRuntime{
+		events: []runtime.Event{runtime.ModelError(&modelerrors.StatusError{StatusCode: http.StatusTooManyRequests, Err: errors.New("429 Too Many Requests")})},
+	}
+
+	var buf bytes.Buffer
+	err := Run(t.Context(), NewPrinter(&buf), Config{Output: OutputJSON}, rt, session.New(), []string{"hello"})
+	assert.ErrorContains(t, err, "configure fallback models")
+
+	var result RunResult
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, result.Status, "model_error")
+	assert.Equal(t, result.ErrorKind, string(modelerrors.KindRateLimit))
+	assert.Assert(t, !strings.Contains(result.Error, "429"), "the raw error isn't shown")
+}
+
+func TestBudgetExceeded(t *testing.T) {
+	t.Parallel()
+
+	usage := &runtime.Usage{LastMessage: &runtime.MessageUsage{Cost: 0.6}}
+	for _, output := range []OutputFormat{OutputText, OutputStreamJSON, OutputJSON} {
+		rt := &mockRuntime{
+			events: []runtime.Event{
+				runtime.NewTokenUsageEvent("s", "root", usage),
+				runtime.NewTokenUsageEvent("s", "root", usage),
+				runtime.AgentChoice("root", "s", "never printed"),
+			},
+		}
+
+		var buf bytes.Buffer
+		err := Run(t.Context(), NewPrinter(&buf), Config{Output: output, MaxCost: 1}, rt, session.New(), []string{"hello"})
+		assert.ErrorIs(t, err, ErrBudgetExceeded)
+		assert.Equal(t, ExitCode(err), ExitCodeBudgetExceeded)
+		assert.Assert(t, !bytes.Contains(buf.Bytes(), []byte("never printed")))
+	}
+}
+
+func TestContinueRun(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New()
+	rt := &mockRuntime{
+		events: []runtime.Event{runtime.AgentChoice("root", sess.ID, "Picking up where I left off.")},
+	}
+
+	var buf bytes.Buffer
+	err := Run(t.Context(), NewPrinter(&buf), Config{Continue: true}, rt, sess, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(buf.String(), "Picking up where I left off."))
+	assert.Equal(t, len(sess.GetAllMessages()), 0, "no message is sent to continue a run")
+
+	buf.Reset()
+	err = Run(t.Context(), NewPrinter(&buf), Config{Continue: true}, rt, sess, []string{"And then?"})
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(buf.String(), "Picking up where I left off."), 2, "the run is continued before the message is sent")
+	assert.Equal(t, len(sess.GetAllMessages()), 1)
+}
+
+func TestSimulatedRun(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New()
+	shell := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"go mod tidy"}`}}
+	events := []runtime.Event{
+		runtime.ToolCall(shell, tools.Tool{}, "root"),
+		runtime.ToolCallSimulated(sess.ID, shell, tools.Tool{}, "root"),
+		runtime.ToolCallResponse(shell, tools.Tool{}, tools.ResultSuccess(runtime.SimulatedToolResult), runtime.SimulatedToolResult, "root"),
+		runtime.AgentChoice("root", sess.ID, "This would tidy go.mod."),
+	}
+
+	var buf bytes.Buffer
+	err := Run(t.Context(), NewPrinter(&buf), Config{}, &mockRuntime{events: events}, sess, []string{"hello"})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(buf.String(), "Plan: 1 simulated tool call(s), nothing was run"))
+	assert.Assert(t, strings.Contains(buf.String(), `1. shell {"cmd":"go mod tidy"}`))
+
+	buf.Reset()
+	err = Run(t.Context(), NewPrinter(&buf), Config{Output: OutputJSON}, &mockRuntime{events: events}, sess, []string{"hello"})
+	assert.NilError(t, err)
+
+	var result RunResult
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, len(result.ToolCalls), 1)
+	assert.Equal(t, result.ToolCalls[0].Status, ToolCallSimulated)
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ExitCode(nil), ExitCodeSuccess)
+	assert.Equal(t, ExitCode(errors.New("boom")), ExitCodeFailure)
+	assert.Equal(t, ExitCode(RuntimeError{Err: errors.New("boom")}), ExitCodeFailure)
+	assert.Equal(t, ExitCode(eventError(&runtime.ErrorEvent{Error: "boom", Code: runtime.ErrorCodeTools})), ExitCodeToolFailure)
+	assert.Equal(t, ExitCode(fmt.Errorf("run: %w", ErrMaxIterations)), ExitCodeMaxIterations)
+	assert.Equal(t, ExitCode(ErrInputUnavailable), ExitCodeInputUnavailable)
+}
+
+func TestCreateUserMessageWithAttachments(t *testing.T) {
+	t.Parallel()
+
+	notes := filepath.Join(t.TempDir(), "notes.md")
+	assert.NilError(t, os.WriteFile(notes, []byte("# Notes"), 0o644))
+
+	msg := CreateUserMessageWithAttachments("summarize", Attachments{
+		Paths: []string{notes},
+		Stdin: "a,b\n1,2\n",
+	})
+
+	assert.Equal(t, msg.Message.Content, "summarize")
+	assert.Equal(t, len(msg.Message.MultiContent), 1)
+	text := msg.Message.MultiContent[0].Text
+	assert.Assert(t, strings.HasPrefix(text, "summarize\n\n<attached_file path=\"stdin\">\na,b\n1,2\n</attached_file>"))
+	assert.Assert(t, strings.Contains(text, "# Notes"))
+}
+
+func TestCreateUserMessageWithoutAttachments(t *testing.T) {
+	t.Parallel()
+
+	msg := CreateUserMessageWithAttachments("hello", Attachments{Paths: []string{""}})
+	assert.Equal(t, msg.Message.Content, "hello")
+	assert.Equal(t, len(msg.Message.MultiContent), 0)
+}
+
+func TestValidateAttachments(t *testing.T) {
+	t.Parallel()
+
+	err := ValidateAttachments([]string{filepath.Join(t.TempDir(), "missing.pdf")})
+	assert.ErrorContains(t, err, "file does not exist")
+}
@@ -12,9 +12,11 @@ import (
 	"strings"
 
 	"github.com/goccy/go-yaml"
+	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/skills"
 )
 
 func Load(ctx context.Context, source Source) (*latest.Config, error) {
@@ -147,6 +149,45 @@ func validateConfig(cfg *latest.Config) error {
 		}
 	}
 
+	return validateDelegation(cfg)
+}
+
+// validateDelegation checks that the delegation graph only references agents
+// and delegations that exist.
+func validateDelegation(cfg *latest.Config) error {
+	d := cfg.Delegation
+	if d == nil {
+		return nil
+	}
+
+	if d.MaxDepth < 0 {
+		return fmt.Errorf("delegation.max_depth must be positive, got %d", d.MaxDepth)
+	}
+	if d.LoopLimit < 0 {
+		return fmt.Errorf("delegation.loop_limit must be positive, got %d", d.LoopLimit)
+	}
+
+	for name, targets := range d.Allow {
+		agent, ok := cfg.Agents.Lookup(name)
+		if !ok {
+			return fmt.Errorf("delegation.allow references non-existent agent '%s'", name)
+		}
+		for _, target := range targets {
+			if !slices.Contains(agent.SubAgents, target) && !slices.Contains(agent.Handoffs, target) {
+				return fmt.Errorf("delegation.allow lets agent '%s' delegate to '%s', which is neither one of its sub-agents nor one of its handoffs", name, target)
+			}
+		}
+	}
+
+	for name, budget := range d.Budgets {
+		if _, ok := cfg.Agents.Lookup(name); !ok {
+			return fmt.Errorf("delegation.budgets references non-existent agent '%s'", name)
+		}
+		if budget.MaxTurns < 0 || budget.MaxCost < 0 || budget.MaxTime.Duration < 0 {
+			return fmt.Errorf("delegation.budgets.%s: limits must be positive", name)
+		}
+	}
+
 	return nil
 }
 
@@ -203,6 +244,17 @@ func validateProviderName(name string) error {
 	return nil
 }
 
+// isOCISkillsReference reports whether a skills source is a reference to a
+// skill bundle in a registry, e.g. "docker.io/org/skills:latest". Bare names
+// are rejected to catch typos of the other kinds of sources.
+func isOCISkillsReference(source string) bool {
+	if !strings.Contains(source, "/") {
+		return false
+	}
+	_, err := name.ParseReference(source)
+	return err == nil
+}
+
 // validateSkillsConfiguration validates the skills configuration for an agent.
 func validateSkillsConfiguration(_ string, agent *latest.AgentConfig) error {
 	for _, source := range agent.Skills.Sources {
@@ -213,8 +265,12 @@ func validateSkillsConfiguration(_ string, agent *latest.AgentConfig) error {
 			if _, err := url.Parse(source); err != nil {
 				return fmt.Errorf("agent '%s' has invalid skills source URL '%s': %w", agent.Name, source, err)
 			}
+		case skills.IsPath(source):
+			// valid: skill bundle directory
+		case isOCISkillsReference(source):
+			// valid: skill bundle pushed to a registry
 		default:
-			return fmt.Errorf("agent '%s' has unknown skills source '%s' (must be 'local' or an HTTP/HTTPS URL)", agent.Name, source)
+			return fmt.Errorf("agent '%s' has unknown skills source '%s' (must be 'local', an HTTP/HTTPS URL, a directory or an OCI reference)", agent.Name, source)
 		}
 	}
 	return nil
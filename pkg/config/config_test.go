@@ -3,7 +3,9 @@ package config
 import (
 	"context"
 	"os"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,6 +71,21 @@ func TestAlloyModelNestedComposition(t *testing.T) {
 	assert.Equal(t, "google", cfg.Models["gemini"].Provider)
 }
 
+func TestAlloyModelWithStrategy(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := Load(t.Context(), NewFileSource("testdata/alloy_model_strategy.yaml"))
+	require.NoError(t, err)
+
+	// Alloys with a strategy are kept as a single model
+	assert.Equal(t, "fast", cfg.Agents.First().Model)
+	assert.Equal(t, latest.AlloyStrategyCheapest, cfg.Models["fast"].Alloy.Strategy)
+
+	// The constituent models are registered
+	assert.Equal(t, "openai", cfg.Models["openai/gpt-5-mini"].Provider)
+	assert.Equal(t, "anthropic", cfg.Models["anthropic/claude-haiku-4-5"].Provider)
+}
+
 func TestMigrate_v0_v1_provider(t *testing.T) {
 	t.Parallel()
 
@@ -466,6 +483,77 @@ func TestApplyModelOverrides(t *testing.T) {
 	}
 }
 
+func TestApplyTemperatureOverrides(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func() *latest.Config {
+		return &latest.Config{
+			Agents: []latest.AgentConfig{
+				{Name: "root", Model: "shared"},
+				{Name: "reviewer", Model: "shared"},
+				{Name: "writer", Model: "anthropic/claude-sonnet-4-0"},
+			},
+			Models: map[string]latest.ModelConfig{
+				"shared": {Provider: "openai", Model: "gpt-4o"},
+			},
+		}
+	}
+
+	t.Run("global override", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newConfig()
+		require.NoError(t, ApplyModelOverrides(cfg, nil))
+		require.NoError(t, ApplyTemperatureOverrides(cfg, []string{"0.2"}))
+
+		assert.InDelta(t, 0.2, *cfg.Models["shared"].Temperature, 0.0001)
+		assert.InDelta(t, 0.2, *cfg.Models["anthropic/claude-sonnet-4-0"].Temperature, 0.0001)
+		assert.Equal(t, "shared", cfg.Agents[0].Model)
+	})
+
+	t.Run("per-agent override of a shared model", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newConfig()
+		require.NoError(t, ApplyModelOverrides(cfg, nil))
+		require.NoError(t, ApplyTemperatureOverrides(cfg, []string{"reviewer=0", "writer=1.1"}))
+
+		assert.Equal(t, "shared", cfg.Agents[0].Model)
+		assert.Nil(t, cfg.Models["shared"].Temperature)
+		assert.Equal(t, "shared@reviewer", cfg.Agents[1].Model)
+		assert.Equal(t, "gpt-4o", cfg.Models["shared@reviewer"].Model)
+		assert.InDelta(t, 0.0, *cfg.Models["shared@reviewer"].Temperature, 0.0001)
+		assert.Equal(t, "anthropic/claude-sonnet-4-0", cfg.Agents[2].Model)
+		assert.InDelta(t, 1.1, *cfg.Models["anthropic/claude-sonnet-4-0"].Temperature, 0.0001)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newConfig()
+		require.ErrorContains(t, ApplyTemperatureOverrides(cfg, []string{"unknown=0.5"}), "unknown agent 'unknown'")
+		require.ErrorContains(t, ApplyTemperatureOverrides(cfg, []string{"root=hot"}), "invalid temperature 'hot'")
+		require.ErrorContains(t, ApplyTemperatureOverrides(cfg, []string{"=0.5"}), "empty agent name")
+	})
+}
+
+func TestApplyMaxIterationsOverrides(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Agents: []latest.AgentConfig{
+			{Name: "root", MaxIterations: 10},
+			{Name: "other"},
+		},
+	}
+
+	require.NoError(t, ApplyMaxIterationsOverrides(cfg, []string{"20", "other=5"}))
+	assert.Equal(t, 20, cfg.Agents[0].MaxIterations)
+	assert.Equal(t, 5, cfg.Agents[1].MaxIterations)
+
+	require.ErrorContains(t, ApplyMaxIterationsOverrides(cfg, []string{"root=-1"}), "invalid max iterations '-1'")
+}
+
 func TestValidateConfig_ExternalSubAgentReferences(t *testing.T) {
 	t.Parallel()
 
@@ -559,6 +647,126 @@ func TestValidateConfig_ExternalSubAgentReferences(t *testing.T) {
 	}
 }
 
+func TestValidateConfig_Delegation(t *testing.T) {
+	t.Parallel()
+
+	agents := []latest.AgentConfig{
+		{Name: "root", Model: "openai/gpt-4o", SubAgents: []string{"helper"}, Handoffs: []string{"reviewer"}},
+		{Name: "helper", Model: "openai/gpt-4o"},
+		{Name: "reviewer", Model: "openai/gpt-4o"},
+	}
+
+	tests := []struct {
+		name       string
+		delegation *latest.DelegationConfig
+		wantErr    string
+	}{
+		{
+			name:       "sub-agents and handoffs can be allowed",
+			delegation: &latest.DelegationConfig{Allow: map[string][]string{"root": {"helper", "reviewer"}, "helper": {}}, MaxDepth: 2, LoopLimit: 3},
+		},
+		{
+			name:       "unknown agent",
+			delegation: &latest.DelegationConfig{Allow: map[string][]string{"nobody": {}}},
+			wantErr:    "non-existent agent 'nobody'",
+		},
+		{
+			name:       "target that isn't a sub-agent nor a handoff",
+			delegation: &latest.DelegationConfig{Allow: map[string][]string{"helper": {"root"}}},
+			wantErr:    "lets agent 'helper' delegate to 'root'",
+		},
+		{
+			name:       "negative max depth",
+			delegation: &latest.DelegationConfig{MaxDepth: -1},
+			wantErr:    "delegation.max_depth",
+		},
+		{
+			name:       "negative loop limit",
+			delegation: &latest.DelegationConfig{LoopLimit: -1},
+			wantErr:    "delegation.loop_limit",
+		},
+		{
+			name:       "budgets",
+			delegation: &latest.DelegationConfig{Budgets: map[string]latest.TaskBudget{"helper": {MaxTurns: 10, MaxCost: 0.5, MaxTime: latest.Duration{Duration: time.Minute}}}},
+		},
+		{
+			name:       "budget of an unknown agent",
+			delegation: &latest.DelegationConfig{Budgets: map[string]latest.TaskBudget{"nobody": {MaxTurns: 10}}},
+			wantErr:    "delegation.budgets references non-existent agent 'nobody'",
+		},
+		{
+			name:       "negative budget",
+			delegation: &latest.DelegationConfig{Budgets: map[string]latest.TaskBudget{"helper": {MaxCost: -1}}},
+			wantErr:    "delegation.budgets.helper",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateConfig(&latest.Config{Agents: slices.Clone(agents), Delegation: tt.delegation})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_TitlesModel(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Agents: []latest.AgentConfig{{Name: "root", Model: "openai/gpt-4o"}},
+		Titles: &latest.TitlesConfig{Model: "dmr/ai/qwen3"},
+	}
+	require.NoError(t, validateConfig(cfg))
+	assert.Equal(t, "dmr", cfg.Models["dmr/ai/qwen3"].Provider)
+
+	cfg = &latest.Config{
+		Agents: []latest.AgentConfig{{Name: "root", Model: "openai/gpt-4o"}},
+		Titles: &latest.TitlesConfig{Model: "cheap"},
+	}
+	require.ErrorContains(t, validateConfig(cfg), "titles references non-existent model 'cheap'")
+}
+
+func TestValidateConfig_PromptCompressionModel(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Agents:            []latest.AgentConfig{{Name: "root", Model: "openai/gpt-4o"}},
+		PromptCompression: &latest.PromptCompressionConfig{Model: "dmr/ai/qwen3"},
+	}
+	require.NoError(t, validateConfig(cfg))
+	assert.Equal(t, "dmr", cfg.Models["dmr/ai/qwen3"].Provider)
+
+	cfg = &latest.Config{
+		Agents:            []latest.AgentConfig{{Name: "root", Model: "openai/gpt-4o"}},
+		PromptCompression: &latest.PromptCompressionConfig{},
+	}
+	require.ErrorContains(t, validateConfig(cfg), "prompt_compression.model is required")
+}
+
+func TestValidateConfig_TranscriptionModels(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Agents:        []latest.AgentConfig{{Name: "root", Model: "openai/gpt-4o"}},
+		Transcription: &latest.TranscriptionConfig{Model: "openai/whisper-1", SummaryModel: "openai/gpt-4o-mini"},
+	}
+	require.NoError(t, validateConfig(cfg))
+	assert.Equal(t, "whisper-1", cfg.Models["openai/whisper-1"].Model)
+	assert.Equal(t, "gpt-4o-mini", cfg.Models["openai/gpt-4o-mini"].Model)
+
+	cfg = &latest.Config{
+		Agents:        []latest.AgentConfig{{Name: "root", Model: "openai/gpt-4o"}},
+		Transcription: &latest.TranscriptionConfig{},
+	}
+	require.ErrorContains(t, validateConfig(cfg), "transcription.model is required")
+}
+
 func TestProviders_Validation(t *testing.T) {
 	t.Parallel()
 
@@ -56,7 +56,7 @@ func TestParseExamples(t *testing.T) {
 				require.NotEmpty(t, model.Provider)
 				require.NotEmpty(t, model.Model)
 				// Skip providers that don't have entries in models.dev
-				if model.Provider == "dmr" {
+				if model.Provider == "dmr" || model.Provider == "mock" {
 					continue
 				}
 				// Skip models with routing rules - they use multiple providers
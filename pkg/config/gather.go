@@ -66,7 +66,7 @@ func GatherEnvVarsForModels(cfg *latest.Config) []string {
 }
 
 // gatherEnvVarsForModel collects required environment variables for a single model,
-// including any models referenced in its routing rules.
+// including any models referenced in its routing rules or alloy.
 func gatherEnvVarsForModel(cfg *latest.Config, modelName string, requiredEnv map[string]bool) {
 	model := cfg.Models[modelName]
 
@@ -75,18 +75,33 @@ func gatherEnvVarsForModel(cfg *latest.Config, modelName string, requiredEnv map
 
 	// If the model has routing rules, also check all referenced models
 	for _, rule := range model.Routing {
-		ruleModelName := rule.Model
-		if ruleModel, exists := cfg.Models[ruleModelName]; exists {
-			// Model reference - add its env vars
-			addEnvVarsForModelConfig(&ruleModel, cfg.Providers, requiredEnv)
-		} else if providerName, _, ok := strings.Cut(ruleModelName, "/"); ok {
-			// Inline spec (e.g., "openai/gpt-4o") - infer env vars from provider
-			inlineModel := latest.ModelConfig{Provider: providerName}
-			addEnvVarsForModelConfig(&inlineModel, cfg.Providers, requiredEnv)
+		addEnvVarsForModelRef(cfg, rule.Model, requiredEnv)
+	}
+
+	// Alloys with a strategy keep their models in the model field
+	if model.Alloy != nil {
+		for part := range strings.SplitSeq(model.Model, ",") {
+			addEnvVarsForModelRef(cfg, strings.TrimSpace(part), requiredEnv)
+		}
+		if model.Alloy.Judge != "" {
+			addEnvVarsForModelRef(cfg, model.Alloy.Judge, requiredEnv)
 		}
 	}
 }
 
+// addEnvVarsForModelRef adds required environment variables for a model
+// reference, either a model name or an inline spec.
+func addEnvVarsForModelRef(cfg *latest.Config, modelRef string, requiredEnv map[string]bool) {
+	if refModel, exists := cfg.Models[modelRef]; exists {
+		// Model reference - add its env vars
+		addEnvVarsForModelConfig(&refModel, cfg.Providers, requiredEnv)
+	} else if providerName, _, ok := strings.Cut(modelRef, "/"); ok {
+		// Inline spec (e.g., "openai/gpt-4o") - infer env vars from provider
+		inlineModel := latest.ModelConfig{Provider: providerName}
+		addEnvVarsForModelConfig(&inlineModel, cfg.Providers, requiredEnv)
+	}
+}
+
 // addEnvVarsForModelConfig adds required environment variables for a model config.
 // It checks custom providers first, then built-in aliases, then hardcoded fallbacks.
 func addEnvVarsForModelConfig(model *latest.ModelConfig, customProviders map[string]latest.ProviderConfig, requiredEnv map[string]bool) {
@@ -0,0 +1,42 @@
+package latest
+
+import "slices"
+
+// applyDefaults gives each agent the default settings it doesn't set itself.
+func (t *Config) applyDefaults() {
+	d := t.Defaults
+	if d == nil {
+		return
+	}
+
+	for i := range t.Agents {
+		agent := &t.Agents[i]
+
+		if agent.Model == "" {
+			agent.Model = d.Model
+		}
+		if agent.Fallback == nil {
+			agent.Fallback = d.Fallback
+		}
+		if agent.Timeouts == nil {
+			agent.Timeouts = d.Timeouts
+		}
+		if agent.ToolOutput == nil {
+			agent.ToolOutput = d.ToolOutput
+		}
+		if agent.MaxIterations == 0 {
+			agent.MaxIterations = d.MaxIterations
+		}
+		if agent.NumHistoryItems == 0 {
+			agent.NumHistoryItems = d.NumHistoryItems
+		}
+		// A nil slice means the agent doesn't set toolsets, an empty one
+		// that it has none.
+		if agent.Toolsets == nil {
+			agent.Toolsets = slices.Clone(d.Toolsets)
+		}
+		if agent.Guardrails == nil {
+			agent.Guardrails = slices.Clone(d.Guardrails)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package latest
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Defaults(t *testing.T) {
+	t.Parallel()
+
+	var cfg Config
+	err := yaml.Unmarshal([]byte(`
+defaults:
+  model: openai/gpt-4o
+  max_iterations: 20
+  toolsets:
+    - type: filesystem
+    - type: shell
+agents:
+  root:
+    sub_agents: [writer, reviewer]
+  writer:
+    model: anthropic/claude-sonnet-4-5
+    toolsets:
+      - type: think
+  reviewer:
+    max_iterations: 5
+    toolsets: []
+`), &cfg)
+	require.NoError(t, err)
+
+	root, _ := cfg.Agents.Lookup("root")
+	assert.Equal(t, "openai/gpt-4o", root.Model)
+	assert.Equal(t, 20, root.MaxIterations)
+	require.Len(t, root.Toolsets, 2)
+	assert.Equal(t, "filesystem", root.Toolsets[0].Type)
+
+	writer, _ := cfg.Agents.Lookup("writer")
+	assert.Equal(t, "anthropic/claude-sonnet-4-5", writer.Model)
+	assert.Equal(t, 20, writer.MaxIterations)
+	require.Len(t, writer.Toolsets, 1)
+	assert.Equal(t, "think", writer.Toolsets[0].Type)
+
+	reviewer, _ := cfg.Agents.Lookup("reviewer")
+	assert.Equal(t, "openai/gpt-4o", reviewer.Model)
+	assert.Equal(t, 5, reviewer.MaxIterations)
+	assert.Empty(t, reviewer.Toolsets)
+
+	// Agents don't share the default toolsets
+	root.Toolsets[0].Name = "changed"
+	assert.Empty(t, cfg.Defaults.Toolsets[0].Name)
+}
+
+func TestConfig_DefaultsToolsetsAreValidated(t *testing.T) {
+	t.Parallel()
+
+	var cfg Config
+	err := yaml.Unmarshal([]byte(`
+defaults:
+  toolsets:
+    - type: mcp
+agents:
+  root:
+    model: openai/gpt-4o
+`), &cfg)
+	require.Error(t, err)
+}
@@ -27,6 +27,35 @@ type Config struct {
 	RAG         map[string]RAGConfig      `json:"rag,omitempty"`
 	Metadata    Metadata                  `json:"metadata"`
 	Permissions *PermissionsConfig        `json:"permissions,omitempty"`
+	Delegation  *DelegationConfig         `json:"delegation,omitempty"`
+	Titles      *TitlesConfig             `json:"titles,omitempty"`
+	// PromptCompression compresses old tool outputs with a small model
+	// before requests are sent to the models of the agents.
+	PromptCompression *PromptCompressionConfig `json:"prompt_compression,omitempty"`
+	// Transcription transcribes the audio and video files attached to user
+	// messages, so that every model can use them.
+	Transcription *TranscriptionConfig `json:"transcription,omitempty"`
+	// Vars are the variables available to every agent's instruction template.
+	Vars map[string]string `json:"vars,omitempty"`
+	// Partials are named instruction snippets that instruction templates can
+	// include with {{ template "name" . }}.
+	Partials map[string]string `json:"partials,omitempty"`
+	// Defaults are the settings all the agents inherit, unless they set their own.
+	Defaults *AgentDefaults `json:"defaults,omitempty"`
+}
+
+// AgentDefaults are settings shared by all the agents of a config. A setting
+// of an agent replaces the default one: toolsets aren't merged, and an agent
+// with `toolsets: []` has no toolsets.
+type AgentDefaults struct {
+	Model           string            `json:"model,omitempty"`
+	Fallback        *FallbackConfig   `json:"fallback,omitempty"`
+	Timeouts        *TimeoutsConfig   `json:"timeouts,omitempty"`
+	ToolOutput      *ToolOutputConfig `json:"tool_output,omitempty"`
+	MaxIterations   int               `json:"max_iterations,omitempty"`
+	NumHistoryItems int               `json:"num_history_items,omitempty"`
+	Toolsets        []Toolset         `json:"toolsets,omitempty"`
+	Guardrails      []GuardrailConfig `json:"guardrails,omitempty"`
 }
 
 // MCPToolset is a reusable MCP server definition stored in the top-level
@@ -153,6 +182,47 @@ type FallbackConfig struct {
 	// retrying the primary. Only applies after a non-retryable error (e.g., 429).
 	// Default is 1 minute. Use Go duration format (e.g., "1m", "30s", "2m30s").
 	Cooldown Duration `json:"cooldown"`
+	// Backoff is the delay before the first retry of a model. It doubles on
+	// each subsequent retry. Default is 200ms.
+	Backoff Duration `json:"backoff"`
+	// MaxBackoff caps the delay between two retries. Default is 2s.
+	MaxBackoff Duration `json:"max_backoff"`
+}
+
+// TimeoutsConfig bounds how long the runtime waits on models and tools, so
+// that a hung provider or tool doesn't wedge the session.
+type TimeoutsConfig struct {
+	// StreamIdle is how long a model stream can go without producing any data
+	// before the request is cancelled and retried like any retryable error.
+	// Disabled by default.
+	StreamIdle Duration `json:"stream_idle"`
+	// Tool is how long a tool call can run before it's cancelled. The model is
+	// told that the call timed out and carries on. Disabled by default.
+	Tool Duration `json:"tool"`
+}
+
+// ToolOutputConfig bounds the size of the tool outputs added to the
+// conversation, so that a giant shell or fetch output doesn't fill the context.
+type ToolOutputConfig struct {
+	// MaxTokens caps tool outputs, estimated at 4 characters per token.
+	// Longer outputs keep their beginning and their end, and the agent can
+	// page through the rest with get_more_output. Disabled by default.
+	MaxTokens int `json:"max_tokens"`
+	// SummaryModel summarizes the outputs longer than MaxTokens instead of
+	// truncating them. It is the name of a model of the models section.
+	SummaryModel string `json:"summary_model,omitempty"`
+}
+
+// ToolSearchConfig hides the tools of agents with many tools behind a
+// search_tools tool, so that hundreds of tool definitions aren't sent to the
+// model with every request.
+type ToolSearchConfig struct {
+	// Threshold is the number of tools above which tools are hidden until the
+	// agent searches for them. Disabled by default.
+	Threshold int `json:"threshold"`
+	// MaxResults is the maximum number of tools each search makes available.
+	// Defaults to 5.
+	MaxResults int `json:"max_results,omitempty"`
 }
 
 // Duration is a wrapper around time.Duration that supports YAML/JSON unmarshaling
@@ -238,6 +308,9 @@ type AgentConfig struct {
 	Name                    string
 	Model                   string            `json:"model,omitempty"`
 	Fallback                *FallbackConfig   `json:"fallback,omitempty"`
+	Timeouts                *TimeoutsConfig   `json:"timeouts,omitempty"`
+	ToolOutput              *ToolOutputConfig `json:"tool_output,omitempty"`
+	ToolSearch              *ToolSearchConfig `json:"tool_search,omitempty"`
 	Description             string            `json:"description,omitempty"`
 	WelcomeMessage          string            `json:"welcome_message,omitempty"`
 	Toolsets                []Toolset         `json:"toolsets,omitempty"`
@@ -256,6 +329,99 @@ type AgentConfig struct {
 	StructuredOutput        *StructuredOutput `json:"structured_output,omitempty"`
 	Skills                  SkillsConfig      `json:"skills,omitzero"`
 	Hooks                   *HooksConfig      `json:"hooks,omitempty"`
+	// Vars are instruction template variables for this agent. They override
+	// the top-level vars with the same name.
+	Vars map[string]string `json:"vars,omitempty"`
+	// Context lists commands and files whose output is added to the system
+	// prompt, read at the start of each run.
+	Context []ContextConfig `json:"context,omitempty"`
+	// SubAgentTraces gives the agent a tool to read the transcripts of the
+	// tasks its sub-agents completed, and not only their final answers.
+	SubAgentTraces bool `json:"subagent_traces,omitempty"`
+	// TaskOutput is the JSON Schema the results of the tasks the agent
+	// delegates with transfer_task must follow.
+	TaskOutput *StructuredOutput `json:"task_output,omitempty"`
+	// Continuity keeps a rolling summary of the agent's sessions in each
+	// project and injects it into the next sessions.
+	Continuity bool `json:"continuity,omitempty"`
+	// Guardrails check the final answers of the agent before they're
+	// considered done.
+	Guardrails []GuardrailConfig `json:"guardrails,omitempty"`
+	// ContentFilters scan the results of tool calls for prompt injections
+	// before they reach the model.
+	ContentFilters []ContentFilterConfig `json:"content_filters,omitempty"`
+}
+
+const (
+	GuardrailActionBlock    = "block"
+	GuardrailActionRetry    = "retry"
+	GuardrailActionAnnotate = "annotate"
+)
+
+// GuardrailConfig is a rule the final answers of an agent must follow.
+// Exactly one of Deny, JSONSchema and Policy is set.
+type GuardrailConfig struct {
+	// Name identifies the guardrail in events and messages.
+	Name string `json:"name,omitempty"`
+	// Deny lists regular expressions the answers must not match.
+	Deny []string `json:"deny,omitempty"`
+	// JSONSchema is a JSON Schema the answers must be valid JSON documents of.
+	JSONSchema map[string]any `json:"json_schema,omitempty"`
+	// Policy is checked by a model, e.g. "Never promise a refund".
+	Policy string `json:"policy,omitempty"`
+	// Model is the model checking the policy, from the models section.
+	// Defaults to the model of the agent.
+	Model string `json:"model,omitempty"`
+	// Action is what happens to failing answers: block (the default)
+	// replaces them with Message, retry asks the model for a new answer,
+	// annotate keeps them but reports the failure.
+	Action string `json:"action,omitempty"`
+	// Message replaces blocked answers.
+	Message string `json:"message,omitempty"`
+	// MaxRetries is how many new answers retry asks for before blocking.
+	// Defaults to 2.
+	MaxRetries int `json:"max_retries,omitempty"`
+}
+
+const (
+	ContentFilterActionWarn    = "warn"
+	ContentFilterActionStrip   = "strip"
+	ContentFilterActionApprove = "approve"
+)
+
+// ContentFilterConfig scans the results of tool calls, e.g. fetched web
+// pages, for text trying to give instructions to the model.
+type ContentFilterConfig struct {
+	// Name identifies the filter in events and messages.
+	Name string `json:"name,omitempty"`
+	// Tools lists the tools whose results are scanned, as glob patterns,
+	// e.g. "fetch" or "mcp_*". Defaults to all tools.
+	Tools []string `json:"tools,omitempty"`
+	// Patterns lists the regular expressions flagging the results.
+	// Defaults to common prompt injection phrases.
+	Patterns []string `json:"patterns,omitempty"`
+	// Action is what happens to flagged results: warn (the default) wraps
+	// them in a warning that they're untrusted data, strip removes the
+	// flagged lines, approve asks the user before sending them to the model.
+	Action string `json:"action,omitempty"`
+}
+
+// ContextConfig is a source of dynamic context: either a shell command whose
+// output, or a file whose content, is injected into the agent's system prompt.
+type ContextConfig struct {
+	// Command is a shell command run in the working directory.
+	Command string `json:"command,omitempty"`
+	// File is a file path, relative to the working directory.
+	File string `json:"file,omitempty"`
+	// Cache is how long the content is reused during a run before being
+	// read again, before the next model call. By default, the content is
+	// read once per run.
+	Cache Duration `json:"cache,omitzero"`
+	// MaxSize is the maximum number of bytes injected; longer content is
+	// truncated. Default is 16384.
+	MaxSize int `json:"max_size,omitempty"`
+	// Timeout is the command timeout in seconds. Default is 10.
+	Timeout int `json:"timeout,omitempty"`
 }
 
 const SkillSourceLocal = "local"
@@ -372,6 +538,41 @@ func (a *AgentConfig) GetFallbackCooldown() time.Duration {
 	return 0
 }
 
+// GetStreamIdleTimeout returns how long a model stream can stay idle, or 0
+// if not set.
+func (a *AgentConfig) GetStreamIdleTimeout() time.Duration {
+	if a.Timeouts != nil {
+		return a.Timeouts.StreamIdle.Duration
+	}
+	return 0
+}
+
+// GetToolTimeout returns how long a tool call can run, or 0 if not set.
+func (a *AgentConfig) GetToolTimeout() time.Duration {
+	if a.Timeouts != nil {
+		return a.Timeouts.Tool.Duration
+	}
+	return 0
+}
+
+// GetMaxToolOutputTokens returns the maximum size of tool outputs, in
+// tokens, or 0 if not set.
+func (a *AgentConfig) GetMaxToolOutputTokens() int {
+	if a.ToolOutput != nil {
+		return a.ToolOutput.MaxTokens
+	}
+	return 0
+}
+
+// GetFallbackBackoff returns the initial and maximum retry backoff from the
+// config. Zero values mean the defaults apply.
+func (a *AgentConfig) GetFallbackBackoff() (initial, maxBackoff time.Duration) {
+	if a.Fallback != nil {
+		return a.Fallback.Backoff.Duration, a.Fallback.MaxBackoff.Duration
+	}
+	return 0, 0
+}
+
 // ModelConfig represents the configuration for a model
 type ModelConfig struct {
 	// Name is the manifest model name (map key), populated at runtime.
@@ -405,6 +606,9 @@ type ModelConfig struct {
 	// - The provider/model fields define the fallback model
 	// - Each routing rule maps to a different model based on examples
 	Routing []RoutingRule `json:"routing,omitempty"`
+	// Alloy configures how an alloy model (no provider and a comma-separated
+	// list of models) picks one of its models for each request.
+	Alloy *AlloyConfig `json:"alloy,omitempty"`
 }
 
 // Clone returns a deep copy of the ModelConfig.
@@ -478,7 +682,8 @@ func (f *FlexibleModelConfig) isShorthandOnly() bool {
 		len(f.ProviderOpts) == 0 &&
 		f.TrackUsage == nil &&
 		f.ThinkingBudget == nil &&
-		len(f.Routing) == 0
+		len(f.Routing) == 0 &&
+		f.Alloy == nil
 }
 
 // RoutingRule defines a single routing rule for model selection.
@@ -490,6 +695,35 @@ type RoutingRule struct {
 	Examples []string `json:"examples"`
 }
 
+// Alloy selection strategies.
+const (
+	// AlloyStrategyRandom picks a random model for each request (default).
+	AlloyStrategyRandom = "random"
+	// AlloyStrategyCheapest tries the cheapest model first and escalates to
+	// the next cheapest one when a request fails.
+	AlloyStrategyCheapest = "cheapest"
+	// AlloyStrategyLatency prefers the model with the lowest observed time
+	// to first token.
+	AlloyStrategyLatency = "latency"
+	// AlloyStrategyCapability routes requests with images to vision-capable
+	// models and everything else to the other models.
+	AlloyStrategyCapability = "capability"
+	// AlloyStrategyEnsemble asks all the models for each turn and keeps the
+	// answer most of them agree on, or the one a judge model picks.
+	AlloyStrategyEnsemble = "ensemble"
+)
+
+// AlloyConfig configures the model selection of an alloy model.
+type AlloyConfig struct {
+	// Strategy is one of "random" (default), "cheapest", "latency",
+	// "capability" or "ensemble".
+	Strategy string `json:"strategy,omitempty"`
+	// Judge picks the best answer of an ensemble when no answer is given by
+	// a majority of the models. It is the name of a model of the models
+	// section or a provider/model reference.
+	Judge string `json:"judge,omitempty"`
+}
+
 type Metadata struct {
 	Author      string `json:"author,omitempty"`
 	License     string `json:"license,omitempty"`
@@ -526,6 +760,15 @@ type ScriptShellToolConfig struct {
 
 	Env        map[string]string `json:"env,omitempty"`
 	WorkingDir string            `json:"working_dir,omitempty"`
+
+	// Interpreter is the command, with its arguments, that runs Cmd, given
+	// as its last argument, e.g. [python3, -c]. Defaults to the shell of the
+	// user with -c.
+	Interpreter []string `json:"interpreter,omitempty"`
+
+	// ArgsStdin is whether the arguments are also written to the standard
+	// input of the command, as a JSON object.
+	ArgsStdin bool `json:"args_stdin,omitempty"`
 }
 
 type APIToolConfig struct {
@@ -540,6 +783,20 @@ type APIToolConfig struct {
 	OutputSchema map[string]any `json:"output_schema,omitempty"`
 }
 
+// WASMToolConfig limits what the module of a `wasm` tool can do.
+type WASMToolConfig struct {
+	// MemoryLimit is the maximum memory of the module, in MiB. Defaults to 64.
+	MemoryLimit int `json:"memory_limit,omitempty"`
+	// Timeout is the maximum duration of a tool call, or of listing the
+	// tools, in seconds. Defaults to 30.
+	Timeout int `json:"timeout,omitempty"`
+	// FS is the access of the module to the working directory: "none"
+	// (default), "read" or "write".
+	FS string `json:"fs,omitempty"`
+	// Net lets the module send HTTP requests.
+	Net bool `json:"net,omitempty"`
+}
+
 // PostEditConfig represents a post-edit command configuration
 type PostEditConfig struct {
 	Path string `json:"path"`
@@ -553,6 +810,12 @@ type Toolset struct {
 	Instruction string   `json:"instruction,omitempty"`
 	Toon        string   `json:"toon,omitempty"`
 
+	// Exclude lists tools of the toolset that are left out.
+	Exclude []string `json:"exclude,omitempty"`
+	// Alias renames tools of the toolset, from their original name to the
+	// name the agent sees.
+	Alias map[string]string `json:"alias,omitempty"`
+
 	// Model overrides the LLM used for the turn that processes tool results
 	// from this toolset, enabling per-toolset model routing. Value can be a
 	// model name from the models section or "provider/model" (e.g. "openai/gpt-4o-mini").
@@ -566,6 +829,13 @@ type Toolset struct {
 	Ref     string   `json:"ref,omitempty"`
 	Remote  Remote   `json:"remote"`
 	Config  any      `json:"config,omitempty"`
+	// Lazy defers starting the MCP server until one of its tools is first
+	// called. Its tools are described from the list cached the last time
+	// it started.
+	Lazy bool `json:"lazy,omitempty"`
+	// ApproveToolChanges makes the tools the MCP server adds or changes in
+	// the middle of a session always ask for the user's confirmation.
+	ApproveToolChanges bool `json:"approve_tool_changes,omitempty"`
 
 	// For `mcp` and `lsp` tools - version/package reference for auto-installation.
 	// Format: "owner/repo" or "owner/repo@version"
@@ -573,18 +843,23 @@ type Toolset struct {
 	// Set to "false" or "off" to disable auto-install for this toolset.
 	Version string `json:"version,omitempty"`
 
-	// For the `a2a` and `openapi` tools
+	// For the `a2a` and `openapi` tools. The `github` tool uses URL as the
+	// URL of the GitHub REST API.
 	Name    string            `json:"name,omitempty"`
 	URL     string            `json:"url,omitempty"`
 	Headers map[string]string `json:"headers,omitempty"`
 
 	// For `shell`, `script`, `mcp` or `lsp` tools
 	Env map[string]string `json:"env,omitempty"`
+	// InheritEnv is whether the processes of the tool inherit the whole
+	// environment, which is the default. When false, they only get Env and
+	// the variables processes need to run, like PATH and HOME.
+	InheritEnv *bool `json:"inherit_env,omitempty"`
 
 	// For the `todo` tool
 	Shared bool `json:"shared,omitempty"`
 
-	// For the `memory` and `tasks` tools
+	// For the `memory` and `tasks` tools, and the module of the `wasm` tool
 	Path string `json:"path,omitempty"`
 
 	// For the `script` tool
@@ -595,17 +870,38 @@ type Toolset struct {
 
 	APIConfig APIToolConfig `json:"api_config"`
 
+	// For the `wasm` tool
+	WASMConfig WASMToolConfig `json:"wasm_config"`
+
 	// For the `filesystem` tool - VCS integration
 	IgnoreVCS *bool `json:"ignore_vcs,omitempty"`
 
 	// For the `lsp` tool
 	FileTypes []string `json:"file_types,omitempty"`
 
+	// For the `filesystem`, `shell` and `lsp` tools - the root of the
+	// workspace relative paths resolve from, by name or directory.
+	Root string `json:"root,omitempty"`
+
+	// For the `shell` tool - run commands in a persistent interactive shell,
+	// in a pseudo-terminal.
+	PTY bool `json:"pty,omitempty"`
+
 	// For the `fetch` tool
 	Timeout int `json:"timeout,omitempty"`
 
 	// For the `model_picker` tool
 	Models []string `json:"models,omitempty"`
+
+	// For the `image_generation` tool: the model that generates images, as a
+	// "provider/model" reference. Defaults to "openai/gpt-image-1".
+	ImageModel string `json:"image_model,omitempty"`
+
+	// For the `escalate_to_human` tool: a URL notified of every escalation
+	// with an HTTP POST, with the headers in Headers, and whether to show a
+	// desktop notification.
+	Webhook string `json:"webhook,omitempty"`
+	Desktop bool   `json:"desktop,omitempty"`
 }
 
 func (t *Toolset) UnmarshalYAML(unmarshal func(any) error) error {
@@ -622,6 +918,10 @@ type Remote struct {
 	URL           string            `json:"url"`
 	TransportType string            `json:"transport_type,omitempty"`
 	Headers       map[string]string `json:"headers,omitempty"`
+	// Account selects which of the user's OAuth authorizations on the server
+	// is used, for users with several accounts. Each account is authorized
+	// once and its token is stored separately.
+	Account string `json:"account,omitempty"`
 }
 
 // DeferConfig represents the deferred loading configuration for a toolset.
@@ -1233,6 +1533,109 @@ type PermissionsConfig struct {
 	Deny []string `json:"deny,omitempty"`
 }
 
+// TitlesConfig controls how session titles are generated.
+type TitlesConfig struct {
+	// Model generates the titles instead of the model of the agent the user
+	// talks to, e.g. a cheap local model. It is the name of a model of the
+	// models section or a provider/model reference.
+	Model string `json:"model,omitempty"`
+	// Disabled turns title generation off. Titles can still be set manually.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// PromptCompressionConfig configures the compression of old tool outputs
+// and retrieved documents with a small model, e.g. a local DMR model.
+type PromptCompressionConfig struct {
+	// Model compresses the tool outputs. It is the name of a model of the
+	// models section or a provider/model reference.
+	Model string `json:"model"`
+	// MinLength is the length, in characters, from which a tool output is
+	// compressed. Defaults to 4000.
+	MinLength int `json:"min_length,omitempty"`
+	// KeepRecent is the number of most recent tool outputs that are never
+	// compressed. Defaults to 3.
+	KeepRecent int `json:"keep_recent,omitempty"`
+}
+
+// TranscriptionConfig configures the transcription of the audio and video
+// files attached to user messages.
+type TranscriptionConfig struct {
+	// Model transcribes the files: an OpenAI transcription model, e.g.
+	// openai/gpt-4o-mini-transcribe, or a Gemini model. It is the name of a
+	// model of the models section or a provider/model reference.
+	Model string `json:"model"`
+	// SummaryModel summarizes, chunk by chunk, the transcripts longer than
+	// MaxLength. Without it, long transcripts are truncated.
+	SummaryModel string `json:"summary_model,omitempty"`
+	// MaxLength is the length, in characters, from which a transcript is
+	// summarized. Defaults to 20000.
+	MaxLength int `json:"max_length,omitempty"`
+}
+
+// DelegationConfig controls how agents transfer tasks and hand off
+// conversations to each other.
+type DelegationConfig struct {
+	// Allow restricts, for the agents it lists, which of their sub-agents and
+	// handoff agents they can delegate to. Agents not listed are unrestricted.
+	Allow map[string][]string `json:"allow,omitempty"`
+	// MaxDepth is the maximum number of nested task transfers. 0 means unlimited.
+	MaxDepth int `json:"max_depth,omitempty"`
+	// LoopLimit is the number of times the same delegation, from one agent to
+	// another, can repeat within a chain of nested transfers or within a turn
+	// of handoffs before the run is stopped. 0 means loops aren't detected.
+	LoopLimit int `json:"loop_limit,omitempty"`
+	// Budgets limits, for the agents it lists, each task delegated to them,
+	// so that one runaway sub-agent can't consume the budget of the session.
+	Budgets map[string]TaskBudget `json:"budgets,omitempty"`
+}
+
+// TaskBudget limits a task delegated to an agent. When a limit is reached,
+// the task stops and the agent that delegated it gets a failure instead of a
+// result. Zero values mean unlimited.
+type TaskBudget struct {
+	// MaxTurns is the maximum number of model calls.
+	MaxTurns int `json:"max_turns,omitempty"`
+	// MaxCost is the maximum cost in dollars, including the cost of the
+	// tasks the agent delegates itself.
+	MaxCost float64 `json:"max_cost,omitempty"`
+	// MaxTime is the maximum wall time.
+	MaxTime Duration `json:"max_time,omitzero"`
+}
+
+// AllowedTargets returns the agents the given agent can delegate to, and
+// whether the agent is restricted at all.
+func (c *DelegationConfig) AllowedTargets(agentName string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	targets, ok := c.Allow[agentName]
+	return targets, ok
+}
+
+// GetMaxDepth returns the maximum depth of nested task transfers, 0 meaning unlimited.
+func (c *DelegationConfig) GetMaxDepth() int {
+	if c == nil {
+		return 0
+	}
+	return c.MaxDepth
+}
+
+// GetBudgets returns the limits of the tasks delegated to each agent.
+func (c *DelegationConfig) GetBudgets() map[string]TaskBudget {
+	if c == nil {
+		return nil
+	}
+	return c.Budgets
+}
+
+// GetLoopLimit returns the loop limit, 0 meaning loops aren't detected.
+func (c *DelegationConfig) GetLoopLimit() int {
+	if c == nil {
+		return 0
+	}
+	return c.LoopLimit
+}
+
 // HooksConfig represents the hooks configuration for an agent.
 // Hooks allow running shell commands at various points in the agent lifecycle.
 type HooksConfig struct {
@@ -2,6 +2,10 @@ package latest
 
 import (
 	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
 )
 
 func (t *Config) UnmarshalYAML(unmarshal func(any) error) error {
@@ -11,6 +15,7 @@ func (t *Config) UnmarshalYAML(unmarshal func(any) error) error {
 		return err
 	}
 	*t = Config(tmp)
+	t.applyDefaults()
 	return t.validate()
 }
 
@@ -33,11 +38,72 @@ func (t *Config) validate() error {
 				return err
 			}
 		}
+		if agent.ToolOutput != nil {
+			if err := agent.ToolOutput.validate(); err != nil {
+				return fmt.Errorf("agent '%s': tool_output: %w", agent.Name, err)
+			}
+		}
+		if agent.ToolSearch != nil {
+			if err := agent.ToolSearch.validate(); err != nil {
+				return fmt.Errorf("agent '%s': tool_search: %w", agent.Name, err)
+			}
+		}
+		for j := range agent.Context {
+			if err := agent.Context[j].validate(); err != nil {
+				return fmt.Errorf("agent '%s': context[%d]: %w", agent.Name, j, err)
+			}
+		}
+		for j := range agent.Guardrails {
+			if err := agent.Guardrails[j].validate(); err != nil {
+				return fmt.Errorf("agent '%s': guardrails[%d]: %w", agent.Name, j, err)
+			}
+		}
+		if agent.TaskOutput != nil {
+			if len(agent.SubAgents) == 0 {
+				return fmt.Errorf("agent '%s': task_output can only be set on agents with sub_agents", agent.Name)
+			}
+			if agent.TaskOutput.Schema == nil {
+				return fmt.Errorf("agent '%s': task_output: schema is required", agent.Name)
+			}
+		}
+		for j := range agent.ContentFilters {
+			if err := agent.ContentFilters[j].validate(); err != nil {
+				return fmt.Errorf("agent '%s': content_filters[%d]: %w", agent.Name, j, err)
+			}
+		}
+	}
+
+	for name, model := range t.Models {
+		if err := model.validateAlloy(); err != nil {
+			return fmt.Errorf("model '%s': %w", name, err)
+		}
 	}
 
 	return nil
 }
 
+// validateAlloy validates the alloy configuration of a model
+func (m *ModelConfig) validateAlloy() error {
+	if m.Alloy == nil {
+		return nil
+	}
+
+	if m.Provider != "" || !strings.Contains(m.Model, ",") {
+		return errors.New("alloy can only be set on alloy models (no provider and a comma-separated list of models)")
+	}
+
+	switch m.Alloy.Strategy {
+	case "", AlloyStrategyRandom, AlloyStrategyCheapest, AlloyStrategyLatency, AlloyStrategyCapability, AlloyStrategyEnsemble:
+	default:
+		return fmt.Errorf("unknown alloy strategy %q (expected one of: random, cheapest, latency, capability, ensemble)", m.Alloy.Strategy)
+	}
+
+	if m.Alloy.Judge != "" && m.Alloy.Strategy != AlloyStrategyEnsemble {
+		return errors.New("alloy judge can only be set with the ensemble strategy")
+	}
+	return nil
+}
+
 // validateFallback validates the fallback configuration for an agent
 func (a *AgentConfig) validateFallback() error {
 	if a.Fallback == nil {
@@ -55,13 +121,142 @@ func (a *AgentConfig) validateFallback() error {
 	return nil
 }
 
+// validate validates the limits of tool outputs
+func (c *ToolOutputConfig) validate() error {
+	if c.MaxTokens < 0 {
+		return errors.New("max_tokens must be non-negative")
+	}
+	if c.SummaryModel != "" && c.MaxTokens == 0 {
+		return errors.New("summary_model requires max_tokens")
+	}
+	return nil
+}
+
+// validate validates the tool search settings
+func (c *ToolSearchConfig) validate() error {
+	if c.Threshold < 0 {
+		return errors.New("threshold must be non-negative")
+	}
+	if c.MaxResults < 0 {
+		return errors.New("max_results must be non-negative")
+	}
+	return nil
+}
+
+// validateToolAliases checks that aliases give different tools different,
+// non-empty, names.
+func validateToolAliases(aliases map[string]string) error {
+	names := make(map[string]string, len(aliases))
+	for tool, alias := range aliases {
+		if tool == "" || alias == "" {
+			return errors.New("alias can't rename a tool to or from an empty name")
+		}
+		if other, ok := names[alias]; ok {
+			first, second := min(tool, other), max(tool, other)
+			return fmt.Errorf("alias renames both %q and %q to %q", first, second, alias)
+		}
+		names[alias] = tool
+	}
+	return nil
+}
+
+// validate validates a dynamic context source
+func (c *ContextConfig) validate() error {
+	if (c.Command == "") == (c.File == "") {
+		return errors.New("exactly one of command or file is required")
+	}
+	if c.Cache.Duration < 0 {
+		return errors.New("cache must be non-negative")
+	}
+	if c.MaxSize < 0 {
+		return errors.New("max_size must be non-negative")
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout must be non-negative")
+	}
+	if c.File != "" && c.Timeout != 0 {
+		return errors.New("timeout can only be set on commands")
+	}
+	return nil
+}
+
+// validate validates the limits of a wasm module
+func (c *WASMToolConfig) validate() error {
+	if c.MemoryLimit < 0 {
+		return errors.New("wasm_config.memory_limit must be non-negative")
+	}
+	if c.Timeout < 0 {
+		return errors.New("wasm_config.timeout must be non-negative")
+	}
+	switch c.FS {
+	case "", "none", "read", "write":
+	default:
+		return fmt.Errorf("wasm_config.fs must be 'none', 'read' or 'write', got %q", c.FS)
+	}
+	return nil
+}
+
+func (g *GuardrailConfig) validate() error {
+	checks := 0
+	for _, set := range []bool{len(g.Deny) > 0, g.JSONSchema != nil, g.Policy != ""} {
+		if set {
+			checks++
+		}
+	}
+	if checks != 1 {
+		return errors.New("exactly one of deny, json_schema or policy is required")
+	}
+	for _, pattern := range g.Deny {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid deny pattern %q: %w", pattern, err)
+		}
+	}
+	if g.Model != "" && g.Policy == "" {
+		return errors.New("model can only be set on policy guardrails")
+	}
+	switch g.Action {
+	case "", GuardrailActionBlock, GuardrailActionRetry, GuardrailActionAnnotate:
+	default:
+		return fmt.Errorf("unknown action %q (expected one of: block, retry, annotate)", g.Action)
+	}
+	if g.MaxRetries < 0 {
+		return errors.New("max_retries must be non-negative")
+	}
+	if g.MaxRetries > 0 && g.Action != GuardrailActionRetry {
+		return errors.New("max_retries can only be set with the retry action")
+	}
+	return nil
+}
+
+func (f *ContentFilterConfig) validate() error {
+	for _, pattern := range f.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	for _, tool := range f.Tools {
+		if _, err := path.Match(tool, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", tool, err)
+		}
+	}
+	switch f.Action {
+	case "", ContentFilterActionWarn, ContentFilterActionStrip, ContentFilterActionApprove:
+	default:
+		return fmt.Errorf("unknown action %q (expected one of: warn, strip, approve)", f.Action)
+	}
+	return nil
+}
+
 func (t *Toolset) validate() error {
 	// Attributes used on the wrong toolset type.
 	if len(t.Shell) > 0 && t.Type != "script" {
 		return errors.New("shell can only be used with type 'script'")
 	}
-	if t.Path != "" && t.Type != "memory" && t.Type != "tasks" {
-		return errors.New("path can only be used with type 'memory' or 'tasks'")
+	if t.Path != "" && t.Type != "memory" && t.Type != "tasks" && t.Type != "wasm" {
+		return errors.New("path can only be used with type 'memory', 'tasks' or 'wasm'")
+	}
+	if t.WASMConfig != (WASMToolConfig{}) && t.Type != "wasm" {
+		return errors.New("wasm_config can only be used with type 'wasm'")
 	}
 	if len(t.PostEdit) > 0 && t.Type != "filesystem" {
 		return errors.New("post_edit can only be used with type 'filesystem'")
@@ -72,12 +267,34 @@ func (t *Toolset) validate() error {
 	if len(t.Env) > 0 && (t.Type != "shell" && t.Type != "script" && t.Type != "mcp" && t.Type != "lsp") {
 		return errors.New("env can only be used with type 'shell', 'script', 'mcp' or 'lsp'")
 	}
+	if t.InheritEnv != nil && (t.Type != "shell" && t.Type != "script" && t.Type != "mcp" && t.Type != "lsp") {
+		return errors.New("inherit_env can only be used with type 'shell', 'script', 'mcp' or 'lsp'")
+	}
+	// References to MCP definitions are checked once resolved.
+	if t.InheritEnv != nil && t.Type == "mcp" && (t.Remote.URL != "" || strings.HasPrefix(t.Ref, "docker:")) {
+		return errors.New("inherit_env can only be used with MCP servers started with a command")
+	}
+	if t.Lazy && t.Type != "mcp" {
+		return errors.New("lazy can only be used with type 'mcp'")
+	}
+	if t.ApproveToolChanges && t.Type != "mcp" {
+		return errors.New("approve_tool_changes can only be used with type 'mcp'")
+	}
 	if len(t.FileTypes) > 0 && t.Type != "lsp" {
 		return errors.New("file_types can only be used with type 'lsp'")
 	}
+	if t.Root != "" && t.Type != "filesystem" && t.Type != "shell" && t.Type != "lsp" {
+		return errors.New("root can only be used with type 'filesystem', 'shell' or 'lsp'")
+	}
+	if t.PTY && t.Type != "shell" {
+		return errors.New("pty can only be used with type 'shell'")
+	}
 	if len(t.Models) > 0 && t.Type != "model_picker" {
 		return errors.New("models can only be used with type 'model_picker'")
 	}
+	if t.ImageModel != "" && t.Type != "image_generation" {
+		return errors.New("image_model can only be used with type 'image_generation'")
+	}
 	if t.Shared && t.Type != "todo" {
 		return errors.New("shared can only be used with type 'todo'")
 	}
@@ -93,25 +310,35 @@ func (t *Toolset) validate() error {
 	if t.Ref != "" && t.Type != "mcp" {
 		return errors.New("ref can only be used with type 'mcp'")
 	}
-	if (t.Remote.URL != "" || t.Remote.TransportType != "") && t.Type != "mcp" {
+	if (t.Remote.URL != "" || t.Remote.TransportType != "" || t.Remote.Account != "") && t.Type != "mcp" {
 		return errors.New("remote can only be used with type 'mcp'")
 	}
 	if (len(t.Remote.Headers) > 0) && (t.Type != "mcp" && t.Type != "a2a") {
 		return errors.New("remote headers can only be used with type 'mcp' or 'a2a'")
 	}
-	if len(t.Headers) > 0 && t.Type != "openapi" && t.Type != "a2a" {
-		return errors.New("headers can only be used with type 'openapi' or 'a2a'")
+	if len(t.Headers) > 0 && t.Type != "openapi" && t.Type != "a2a" && t.Type != "escalate_to_human" {
+		return errors.New("headers can only be used with type 'openapi', 'a2a' or 'escalate_to_human'")
+	}
+	if t.Webhook != "" && t.Type != "escalate_to_human" {
+		return errors.New("webhook can only be used with type 'escalate_to_human'")
+	}
+	if t.Desktop && t.Type != "escalate_to_human" {
+		return errors.New("desktop can only be used with type 'escalate_to_human'")
 	}
 	if t.Config != nil && t.Type != "mcp" {
 		return errors.New("config can only be used with type 'mcp'")
 	}
-	if t.URL != "" && t.Type != "a2a" && t.Type != "openapi" {
-		return errors.New("url can only be used with type 'a2a' or 'openapi'")
+	if t.URL != "" && t.Type != "a2a" && t.Type != "openapi" && t.Type != "github" {
+		return errors.New("url can only be used with type 'a2a', 'openapi' or 'github'")
 	}
 	if t.Name != "" && (t.Type != "mcp" && t.Type != "a2a") {
 		return errors.New("name can only be used with type 'mcp' or 'a2a'")
 	}
 
+	if err := validateToolAliases(t.Alias); err != nil {
+		return err
+	}
+
 	switch t.Type {
 	case "shell":
 		// no additional validation needed
@@ -152,8 +379,23 @@ func (t *Toolset) validate() error {
 		if len(t.Models) == 0 {
 			return errors.New("model_picker toolset requires at least one model in the 'models' list")
 		}
-	case "background_agents":
+	case "image_generation":
+		if t.ImageModel != "" {
+			if _, err := ParseModelRef(t.ImageModel); err != nil {
+				return fmt.Errorf("image_model: %w", err)
+			}
+		}
+	case "background_agents", "artifacts", "agent_config", "blackboard":
 		// no additional validation needed
+	case "google_search", "code_execution":
+		// provider-native tools, only sent to Gemini models
+	case "github":
+		// url defaults to https://api.github.com
+	case "wasm":
+		if t.Path == "" {
+			return errors.New("wasm toolset requires a path to be set")
+		}
+		return t.WASMConfig.validate()
 	}
 
 	return nil
@@ -2,8 +2,10 @@ package latest
 
 import (
 	"testing"
+	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -97,6 +99,351 @@ agents:
 `,
 			wantErr: "file_types can only be used with type 'lsp'",
 		},
+		{
+			name: "root on non-workspace toolset",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: think
+        root: backend
+`,
+			wantErr: "root can only be used with type 'filesystem', 'shell' or 'lsp'",
+		},
+		{
+			name: "pty on non-shell toolset",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: filesystem
+        pty: true
+`,
+			wantErr: "pty can only be used with type 'shell'",
+		},
+		{
+			name: "image_model on non-image_generation toolset",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: think
+        image_model: openai/gpt-image-1
+`,
+			wantErr: "image_model can only be used with type 'image_generation'",
+		},
+		{
+			name: "invalid image_model",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: image_generation
+        image_model: gpt-image-1
+`,
+			wantErr: "image_model: invalid model reference",
+		},
+		{
+			name: "image_generation toolset",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: image_generation
+        image_model: google/gemini-2.5-flash-image
+`,
+		},
+		{
+			name: "inherit_env on an mcp command",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: mcp
+        command: my-mcp-server
+        inherit_env: false
+        env:
+          API_KEY: ${API_KEY}
+`,
+		},
+		{
+			name: "inherit_env on non-process toolset",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: filesystem
+        inherit_env: false
+`,
+			wantErr: "inherit_env can only be used with type 'shell', 'script', 'mcp' or 'lsp'",
+		},
+		{
+			name: "inherit_env on remote mcp",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: mcp
+        remote:
+          url: https://mcp.example.com
+        inherit_env: false
+`,
+			wantErr: "inherit_env can only be used with MCP servers started with a command",
+		},
+		{
+			name: "lazy mcp",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: mcp
+        ref: docker:duckduckgo
+        lazy: true
+`,
+		},
+		{
+			name: "lazy on non-mcp toolset",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: shell
+        lazy: true
+`,
+			wantErr: "lazy can only be used with type 'mcp'",
+		},
+		{
+			name: "approve_tool_changes on non-mcp toolset",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: shell
+        approve_tool_changes: true
+`,
+			wantErr: "approve_tool_changes can only be used with type 'mcp'",
+		},
+		{
+			name: "exclude and alias",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: filesystem
+        exclude: [write_file]
+        alias:
+          read_file: read
+`,
+		},
+		{
+			name: "alias to an empty name",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: filesystem
+        alias:
+          read_file: ""
+`,
+			wantErr: "alias can't rename a tool to or from an empty name",
+		},
+		{
+			name: "two tools with the same alias",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: filesystem
+        alias:
+          read_file: read
+          read_multiple_files: read
+`,
+			wantErr: `alias renames both "read_file" and "read_multiple_files" to "read"`,
+		},
+		{
+			name: "wasm with limits",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: wasm
+        path: ./tools.wasm
+        wasm_config:
+          memory_limit: 16
+          fs: read
+          net: true
+`,
+			wantErr: "",
+		},
+		{
+			name: "wasm missing path",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: wasm
+`,
+			wantErr: "wasm toolset requires a path to be set",
+		},
+		{
+			name: "wasm with unknown fs access",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: wasm
+        path: ./tools.wasm
+        wasm_config:
+          fs: all
+`,
+			wantErr: "wasm_config.fs must be 'none', 'read' or 'write'",
+		},
+		{
+			name: "wasm_config on shell",
+			config: `
+version: "5"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: shell
+        wasm_config:
+          net: true
+`,
+			wantErr: "wasm_config can only be used with type 'wasm'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			err := yaml.Unmarshal([]byte(tt.config), &cfg)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestModelConfig_ValidateAlloy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "valid strategy",
+			config: `
+agents:
+  root:
+    model: mix
+models:
+  mix:
+    model: openai/gpt-5-mini,anthropic/claude-haiku-4-5
+    alloy:
+      strategy: capability
+`,
+		},
+		{
+			name: "unknown strategy",
+			config: `
+agents:
+  root:
+    model: mix
+models:
+  mix:
+    model: openai/gpt-5-mini,anthropic/claude-haiku-4-5
+    alloy:
+      strategy: fastest
+`,
+			wantErr: `unknown alloy strategy "fastest"`,
+		},
+		{
+			name: "ensemble with a judge",
+			config: `
+agents:
+  root:
+    model: mix
+models:
+  mix:
+    model: openai/gpt-5-mini,anthropic/claude-haiku-4-5,google/gemini-2.5-flash
+    alloy:
+      strategy: ensemble
+      judge: openai/gpt-5
+`,
+		},
+		{
+			name: "judge without ensemble",
+			config: `
+agents:
+  root:
+    model: mix
+models:
+  mix:
+    model: openai/gpt-5-mini,anthropic/claude-haiku-4-5
+    alloy:
+      strategy: cheapest
+      judge: openai/gpt-5
+`,
+			wantErr: "alloy judge can only be set with the ensemble strategy",
+		},
+		{
+			name: "alloy on a single model",
+			config: `
+agents:
+  root:
+    model: mix
+models:
+  mix:
+    provider: openai
+    model: gpt-5-mini
+    alloy:
+      strategy: cheapest
+`,
+			wantErr: "alloy can only be set on alloy models",
+		},
 	}
 
 	for _, tt := range tests {
@@ -115,3 +462,450 @@ agents:
 		})
 	}
 }
+
+func TestContextConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "command and file",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    context:
+      - command: git status --short
+        cache: 30s
+        max_size: 4096
+      - file: TODO.md
+`,
+		},
+		{
+			name: "neither command nor file",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    context:
+      - max_size: 4096
+`,
+			wantErr: "agent 'root': context[0]: exactly one of command or file is required",
+		},
+		{
+			name: "both command and file",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    context:
+      - command: cat TODO.md
+        file: TODO.md
+`,
+			wantErr: "exactly one of command or file is required",
+		},
+		{
+			name: "timeout on a file",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    context:
+      - file: TODO.md
+        timeout: 5
+`,
+			wantErr: "timeout can only be set on commands",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			err := yaml.Unmarshal([]byte(tt.config), &cfg)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, cfg.Agents[0].Context, 2)
+				assert.Equal(t, 30*time.Second, cfg.Agents[0].Context[0].Cache.Duration)
+			}
+		})
+	}
+}
+
+func TestToolOutputConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "max tokens and summary model",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    tool_output:
+      max_tokens: 8000
+      summary_model: mini
+`,
+		},
+		{
+			name: "negative max tokens",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    tool_output:
+      max_tokens: -1
+`,
+			wantErr: "agent 'root': tool_output: max_tokens must be non-negative",
+		},
+		{
+			name: "summary model without max tokens",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    tool_output:
+      summary_model: mini
+`,
+			wantErr: "summary_model requires max_tokens",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			err := yaml.Unmarshal([]byte(tt.config), &cfg)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 8000, cfg.Agents[0].GetMaxToolOutputTokens())
+			}
+		})
+	}
+}
+
+func TestToolSearchConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "threshold and max results",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    tool_search:
+      threshold: 30
+      max_results: 5
+`,
+		},
+		{
+			name: "negative threshold",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    tool_search:
+      threshold: -1
+`,
+			wantErr: "agent 'root': tool_search: threshold must be non-negative",
+		},
+		{
+			name: "negative max results",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    tool_search:
+      threshold: 30
+      max_results: -5
+`,
+			wantErr: "agent 'root': tool_search: max_results must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			err := yaml.Unmarshal([]byte(tt.config), &cfg)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGuardrailConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "deny, json_schema and policy",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    guardrails:
+      - name: no-keys
+        deny: ["sk-[A-Za-z0-9]{20,}"]
+      - json_schema:
+          type: object
+        action: retry
+        max_retries: 1
+      - policy: Never promise a refund.
+        model: openai/gpt-4o-mini
+        action: annotate
+`,
+		},
+		{
+			name: "no check",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    guardrails:
+      - name: empty
+`,
+			wantErr: "agent 'root': guardrails[0]: exactly one of deny, json_schema or policy is required",
+		},
+		{
+			name: "invalid pattern",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    guardrails:
+      - deny: ["("]
+`,
+			wantErr: "invalid deny pattern",
+		},
+		{
+			name: "unknown action",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    guardrails:
+      - deny: [secret]
+        action: drop
+`,
+			wantErr: `unknown action "drop"`,
+		},
+		{
+			name: "max_retries without retry",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    guardrails:
+      - deny: [secret]
+        max_retries: 3
+`,
+			wantErr: "max_retries can only be set with the retry action",
+		},
+		{
+			name: "model without policy",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    guardrails:
+      - deny: [secret]
+        model: openai/gpt-4o-mini
+`,
+			wantErr: "model can only be set on policy guardrails",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			err := yaml.Unmarshal([]byte(tt.config), &cfg)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, cfg.Agents[0].Guardrails, 3)
+			}
+		})
+	}
+}
+
+func TestAgentConfig_ValidateTaskOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    sub_agents: [reviewer]
+    task_output:
+      name: review
+      schema:
+        type: object
+        properties:
+          approved:
+            type: boolean
+  reviewer:
+    model: openai/gpt-4o
+`,
+		},
+		{
+			name: "no sub-agents",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    task_output:
+      name: review
+      schema:
+        type: object
+`,
+			wantErr: "agent 'root': task_output can only be set on agents with sub_agents",
+		},
+		{
+			name: "no schema",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    sub_agents: [reviewer]
+    task_output:
+      name: review
+  reviewer:
+    model: openai/gpt-4o
+`,
+			wantErr: "agent 'root': task_output: schema is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			err := yaml.Unmarshal([]byte(tt.config), &cfg)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestContentFilterConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    content_filters:
+      - name: web
+        tools: [fetch, "mcp_*"]
+        action: approve
+      - patterns: ["(?i)send .* to http"]
+        action: strip
+`,
+		},
+		{
+			name: "invalid pattern",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    content_filters:
+      - patterns: ["("]
+`,
+			wantErr: "agent 'root': content_filters[0]: invalid pattern",
+		},
+		{
+			name: "invalid tool pattern",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    content_filters:
+      - tools: ["["]
+`,
+			wantErr: "invalid tool pattern",
+		},
+		{
+			name: "unknown action",
+			config: `
+agents:
+  root:
+    model: openai/gpt-4o
+    content_filters:
+      - action: block
+`,
+			wantErr: `unknown action "block"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			err := yaml.Unmarshal([]byte(tt.config), &cfg)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, cfg.Agents[0].ContentFilters, 2)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
 	"maps"
 	"strings"
@@ -63,7 +64,9 @@ func applyMCPDefaults(ts, def *latest.Toolset) {
 		ts.Command = def.Command
 	}
 	if ts.Remote.URL == "" {
+		account := ts.Remote.Account
 		ts.Remote = def.Remote
+		ts.Remote.Account = cmp.Or(account, def.Remote.Account)
 	}
 	if len(ts.Args) == 0 {
 		ts.Args = def.Args
@@ -83,9 +86,24 @@ func applyMCPDefaults(ts, def *latest.Toolset) {
 	if len(ts.Tools) == 0 {
 		ts.Tools = def.Tools
 	}
+	if len(ts.Exclude) == 0 {
+		ts.Exclude = def.Exclude
+	}
+	if len(ts.Alias) == 0 {
+		ts.Alias = def.Alias
+	}
 	if ts.Defer.IsEmpty() {
 		ts.Defer = def.Defer
 	}
+	if ts.InheritEnv == nil {
+		ts.InheritEnv = def.InheritEnv
+	}
+	if !ts.Lazy {
+		ts.Lazy = def.Lazy
+	}
+	if !ts.ApproveToolChanges {
+		ts.ApproveToolChanges = def.ApproveToolChanges
+	}
 	if len(def.Env) > 0 {
 		merged := make(map[string]string, len(def.Env)+len(ts.Env))
 		maps.Copy(merged, def.Env)
@@ -136,3 +136,18 @@ func TestMCPDefinitions_EnvMerge(t *testing.T) {
 	// Toolset-only key is preserved
 	assert.Equal(t, "from_toolset", ts.Env["EXTRA"])
 }
+
+func TestMCPDefinitions_InheritEnv(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := Load(t.Context(), NewFileSource("testdata/mcp_definitions_inherit_env.yaml"))
+	require.NoError(t, err)
+
+	root, ok := cfg.Agents.Lookup("root")
+	require.True(t, ok)
+	require.Len(t, root.Toolsets, 2)
+
+	// The definition's value applies unless the toolset sets its own.
+	assert.Equal(t, new(false), root.Toolsets[0].InheritEnv)
+	assert.Equal(t, new(true), root.Toolsets[1].InheritEnv)
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/docker/docker-agent/pkg/config/latest"
@@ -74,6 +76,108 @@ func applySingleOverride(cfg *latest.Config, override string) error {
 	return nil
 }
 
+// ApplyTemperatureOverrides applies CLI temperature overrides to the
+// configuration. Overrides use the same [agent=]value format as model
+// overrides. When an agent shares its model with other agents, the agent gets
+// its own copy of the model so that the override only applies to it.
+// It must be called after ApplyModelOverrides so that every model of the
+// agents has an entry in cfg.Models.
+func ApplyTemperatureOverrides(cfg *latest.Config, overrides []string) error {
+	return applyAgentOverrides(cfg, overrides, func(agent *latest.AgentConfig, value string, global bool) error {
+		temperature, err := strconv.ParseFloat(value, 64)
+		if err != nil || temperature < 0 {
+			return fmt.Errorf("invalid temperature '%s'", value)
+		}
+
+		var models []string
+		for name := range strings.SplitSeq(agent.Model, ",") {
+			modelCfg, exists := cfg.Models[name]
+			if !exists {
+				// "auto" models are picked at runtime.
+				models = append(models, name)
+				continue
+			}
+			modelCfg.Temperature = &temperature
+			if !global && isModelShared(cfg, name, agent.Name) {
+				name += "@" + agent.Name
+			}
+			cfg.Models[name] = modelCfg
+			models = append(models, name)
+		}
+		agent.Model = strings.Join(models, ",")
+		return nil
+	})
+}
+
+// ApplyMaxIterationsOverrides applies CLI overrides of the maximum number of
+// tool-calling loops of agents. Overrides use the same [agent=]value format as
+// model overrides.
+func ApplyMaxIterationsOverrides(cfg *latest.Config, overrides []string) error {
+	return applyAgentOverrides(cfg, overrides, func(agent *latest.AgentConfig, value string, _ bool) error {
+		maxIterations, err := strconv.Atoi(value)
+		if err != nil || maxIterations < 0 {
+			return fmt.Errorf("invalid max iterations '%s'", value)
+		}
+		agent.MaxIterations = maxIterations
+		return nil
+	})
+}
+
+// applyAgentOverrides parses [agent=]value overrides, comma-separated or not,
+// and applies each of them to the named agent, or to all agents when no agent
+// is named.
+func applyAgentOverrides(cfg *latest.Config, overrides []string, apply func(agent *latest.AgentConfig, value string, global bool) error) error {
+	for _, override := range overrides {
+		for part := range strings.SplitSeq(override, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			agentName, value, ok := strings.Cut(part, "=")
+			if !ok {
+				value = part
+			}
+			agentName = strings.TrimSpace(agentName)
+			value = strings.TrimSpace(value)
+			if ok && agentName == "" {
+				return fmt.Errorf("empty agent name in override: %s", part)
+			}
+			if value == "" {
+				return fmt.Errorf("empty value in override: %s", part)
+			}
+
+			if !ok {
+				for i := range cfg.Agents {
+					if err := apply(&cfg.Agents[i], value, true); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			var err error
+			if !cfg.Agents.Update(agentName, func(a *latest.AgentConfig) { err = apply(a, value, false) }) {
+				return fmt.Errorf("unknown agent '%s'", agentName)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isModelShared reports whether a model is used by agents other than the given one.
+func isModelShared(cfg *latest.Config, modelName, agentName string) bool {
+	for _, agent := range cfg.Agents {
+		if agent.Name != agentName && slices.Contains(strings.Split(agent.Model, ","), modelName) {
+			return true
+		}
+	}
+	return false
+}
+
 // ensureModelsExist ensures that all models referenced by agents exist in cfg.Models
 // This handles inline model specs that may have been added via CLI overrides
 func ensureModelsExist(cfg *latest.Config) error {
@@ -98,15 +202,79 @@ func ensureModelsExist(cfg *latest.Config) error {
 				return err
 			}
 		}
+
+		// Ensure the models checking the policies of guardrails exist
+		for i, g := range agent.Guardrails {
+			if g.Model == "" {
+				continue
+			}
+			if g.Model == "auto" {
+				return fmt.Errorf("agent '%s': guardrails[%d].model can't be auto", agent.Name, i)
+			}
+			if err := ensureSingleModelExists(cfg, g.Model, fmt.Sprintf("guardrail %d of agent '%s'", i, agent.Name)); err != nil {
+				return err
+			}
+		}
 	}
 
-	// Ensure models referenced by routing rules exist
+	// Ensure models referenced by routing rules and alloys exist
 	for modelName, modelCfg := range cfg.Models {
 		for i, rule := range modelCfg.Routing {
 			if err := ensureSingleModelExists(cfg, rule.Model, fmt.Sprintf("routing rule %d in model '%s'", i, modelName)); err != nil {
 				return err
 			}
 		}
+		if modelCfg.Alloy != nil {
+			for part := range strings.SplitSeq(modelCfg.Model, ",") {
+				if err := ensureSingleModelExists(cfg, part, fmt.Sprintf("alloy model '%s'", modelName)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Ensure the model that generates session titles exists
+	if cfg.Titles != nil && cfg.Titles.Model != "" {
+		if cfg.Titles.Model == "auto" {
+			return errors.New("titles.model can't be auto")
+		}
+		if err := ensureSingleModelExists(cfg, cfg.Titles.Model, "titles"); err != nil {
+			return err
+		}
+	}
+
+	// Ensure the model that compresses prompts exists
+	if cfg.PromptCompression != nil {
+		switch cfg.PromptCompression.Model {
+		case "":
+			return errors.New("prompt_compression.model is required")
+		case "auto":
+			return errors.New("prompt_compression.model can't be auto")
+		}
+		if err := ensureSingleModelExists(cfg, cfg.PromptCompression.Model, "prompt_compression"); err != nil {
+			return err
+		}
+	}
+
+	// Ensure the models that transcribe attached files exist
+	if cfg.Transcription != nil {
+		switch cfg.Transcription.Model {
+		case "":
+			return errors.New("transcription.model is required")
+		case "auto":
+			return errors.New("transcription.model can't be auto")
+		}
+		if err := ensureSingleModelExists(cfg, cfg.Transcription.Model, "transcription"); err != nil {
+			return err
+		}
+		if cfg.Transcription.SummaryModel == "auto" {
+			return errors.New("transcription.summary_model can't be auto")
+		}
+		if cfg.Transcription.SummaryModel != "" {
+			if err := ensureSingleModelExists(cfg, cfg.Transcription.SummaryModel, "transcription"); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Ensure models referenced by RAG strategies exist
@@ -146,7 +314,9 @@ func expandAlloyModelRef(cfg *latest.Config, modelRef string) (string, error) {
 	// Fast path for non-compositions.
 	if !strings.Contains(modelRef, ",") {
 		modelCfg, exists := cfg.Models[modelRef]
-		if !exists || !isAlloyModelConfig(modelCfg) {
+		// Alloys with an explicit strategy are kept as a single model: the
+		// alloy provider picks among its models for each request.
+		if !exists || !isAlloyModelConfig(modelCfg) || modelCfg.Alloy != nil {
 			return modelRef, nil
 		}
 		return expandAlloyModelRef(cfg, modelCfg.Model)
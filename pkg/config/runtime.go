@@ -2,11 +2,13 @@ package config
 
 import (
 	"log/slog"
+	"maps"
 	"slices"
 	"sync"
 
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/workspace"
 )
 
 type RuntimeConfig struct {
@@ -23,6 +25,15 @@ type Config struct {
 	DefaultModel   *latest.ModelConfig
 	GlobalCodeMode bool
 	WorkingDir     string
+	// AdditionalDirs are the roots of the workspace besides WorkingDir.
+	AdditionalDirs []string
+	// Vars are instruction template variables set on the command line.
+	// They override the variables defined in the agent configuration.
+	Vars map[string]string
+	// TasksPath and MemoryPath are the storage of the tasks and memory
+	// toolsets that don't set a path, when set by the project.
+	TasksPath  string
+	MemoryPath string
 }
 
 func (runConfig *RuntimeConfig) Clone() *RuntimeConfig {
@@ -30,10 +41,18 @@ func (runConfig *RuntimeConfig) Clone() *RuntimeConfig {
 		Config: runConfig.Config,
 	}
 	clone.EnvFiles = slices.Clone(runConfig.EnvFiles)
+	clone.AdditionalDirs = slices.Clone(runConfig.AdditionalDirs)
+	clone.Vars = maps.Clone(runConfig.Vars)
 	clone.DefaultModel = runConfig.DefaultModel.Clone()
 	return clone
 }
 
+// Workspace returns the roots of the workspace: the working directory, then
+// the additional directories.
+func (runConfig *RuntimeConfig) Workspace() workspace.Workspace {
+	return workspace.New(runConfig.WorkingDir, runConfig.AdditionalDirs...)
+}
+
 func (runConfig *RuntimeConfig) EnvProvider() environment.Provider {
 	if runConfig.EnvProviderForTests != nil {
 		return runConfig.EnvProviderForTests
@@ -76,6 +76,7 @@ func TestSchemaMatchesGoTypes(t *testing.T) {
 	// Definitions that map 1:1 to a Go struct.
 	definitionMap := map[string]reflect.Type{
 		"AgentConfig":           reflect.TypeFor[latest.AgentConfig](),
+		"AgentDefaults":         reflect.TypeFor[latest.AgentDefaults](),
 		"FallbackConfig":        reflect.TypeFor[latest.FallbackConfig](),
 		"ModelConfig":           reflect.TypeFor[latest.ModelConfig](),
 		"Metadata":              reflect.TypeFor[latest.Metadata](),
@@ -90,6 +91,7 @@ func TestSchemaMatchesGoTypes(t *testing.T) {
 		"HookDefinition":        reflect.TypeFor[latest.HookDefinition](),
 		"RoutingRule":           reflect.TypeFor[latest.RoutingRule](),
 		"ApiConfig":             reflect.TypeFor[latest.APIToolConfig](),
+		"WasmConfig":            reflect.TypeFor[latest.WASMToolConfig](),
 	}
 
 	for name, goType := range definitionMap {
@@ -66,6 +66,15 @@ func (a fileSource) Read(context.Context) ([]byte, error) {
 	return data, nil
 }
 
+// FilePath returns the path of the local file an agent configuration is
+// loaded from, or "" if it's not loaded from a local file.
+func FilePath(source Source) string {
+	if s, ok := source.(fileSource); ok {
+		return s.path
+	}
+	return ""
+}
+
 // bytesSource is used to load an agent configuration from a []byte.
 type bytesSource struct {
 	name string
@@ -137,3 +137,20 @@ agents:
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown skills source")
 }
+
+func TestSkillBundleSources(t *testing.T) {
+	t.Parallel()
+
+	cfgStr := `version: "7"
+agents:
+  root:
+    model: openai/gpt-4o
+    skills:
+      - local
+      - ./skills/pdf
+      - ~/skills
+      - docker.io/org/skills:latest
+`
+	_, err := Load(t.Context(), NewBytesSource("test", []byte(cfgStr)))
+	require.NoError(t, err)
+}
@@ -0,0 +1,224 @@
+// Package confighistory keeps the versions of the agent configurations that
+// were run, so that sessions can tell which configuration they were started
+// with and users can compare and restore previous versions.
+//
+// Versions are content-addressed: each one is stored once, named after the
+// SHA-256 of its content, and an index records when each source was run with
+// which version.
+package confighistory
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aymanbagabas/go-udiff"
+
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+// ShortHashLen is the length of the hashes shown to users.
+const ShortHashLen = 12
+
+// minRefLen is the minimum length of a hash prefix referencing a version.
+const minRefLen = 4
+
+// ErrNotFound is returned when no version matches a reference.
+var ErrNotFound = errors.New("configuration version not found")
+
+// Version is a version of an agent configuration that was run.
+type Version struct {
+	Hash   string    `json:"hash"`
+	Source string    `json:"source"`
+	Time   time.Time `json:"time"`
+}
+
+// Store stores the versions of agent configurations in a directory.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New creates a store in dir.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Default returns the store of the user's data directory.
+func Default() *Store {
+	return New(filepath.Join(paths.GetDataDir(), "config-history"))
+}
+
+// Hash returns the hash identifying the version of a configuration.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Short returns the abbreviated form of a hash shown to users.
+func Short(hash string) string {
+	if len(hash) > ShortHashLen {
+		return hash[:ShortHashLen]
+	}
+	return hash
+}
+
+// SourceKey returns the name a configuration's versions are recorded under:
+// the absolute path of local files, the name of the source otherwise.
+func SourceKey(source config.Source) string {
+	if path := config.FilePath(source); path != "" {
+		return FileKey(path)
+	}
+	return source.Name()
+}
+
+// FileKey returns the name the versions of a local file are recorded under.
+func FileKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// Record stores a version of the configuration of a source and returns its
+// hash. A version identical to the latest one of the source is only stored once.
+func (s *Store) Record(source string, data []byte) (string, error) {
+	hash := Hash(data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	blob := s.blobPath(hash)
+	if _, err := os.Stat(blob); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(blob, data, 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	versions, err := s.versions(source)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) > 0 && versions[len(versions)-1].Hash == hash {
+		return hash, nil
+	}
+
+	line, err := json.Marshal(Version{Hash: hash, Source: source, Time: time.Now()})
+	if err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(s.indexPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// Versions returns the versions recorded for a source, oldest first. An empty
+// source returns the versions of every source.
+func (s *Store) Versions(source string) ([]Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.versions(source)
+}
+
+func (s *Store) versions(source string) ([]Version, error) {
+	f, err := os.Open(s.indexPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var versions []Version
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var v Version
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			// Skip corrupted lines rather than losing the whole history.
+			continue
+		}
+		if source == "" || v.Source == source {
+			versions = append(versions, v)
+		}
+	}
+	return versions, scanner.Err()
+}
+
+// Get returns the full hash and the content of the version referenced by a
+// hash or a prefix of at least four characters of it.
+func (s *Store) Get(ref string) (string, []byte, error) {
+	ref = strings.ToLower(ref)
+	if len(ref) < minRefLen {
+		return "", nil, fmt.Errorf("version %q is too short: use at least %d characters of its hash", ref, minRefLen)
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil, ErrNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		hash, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if ok && strings.HasPrefix(hash, ref) {
+			matches = append(matches, hash)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil, ErrNotFound
+	case 1:
+	default:
+		return "", nil, fmt.Errorf("version %q is ambiguous: it matches %d versions", ref, len(matches))
+	}
+
+	data, err := os.ReadFile(s.blobPath(matches[0]))
+	if err != nil {
+		return "", nil, err
+	}
+	return matches[0], data, nil
+}
+
+// Diff returns the unified diff between two versions of a configuration,
+// empty when they're identical.
+func Diff(fromLabel string, from []byte, toLabel string, to []byte) string {
+	if bytes.Equal(from, to) {
+		return ""
+	}
+	return udiff.Unified(fromLabel, toLabel, string(from), string(to))
+}
+
+func (s *Store) blobPath(hash string) string {
+	return filepath.Join(s.dir, hash+".yaml")
+}
+
+func (s *Store) indexPath() string {
+	return filepath.Join(s.dir, "index.jsonl")
+}
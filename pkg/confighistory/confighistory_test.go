@@ -0,0 +1,74 @@
+package confighistory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	v1 = "agents:\n  root:\n    model: openai/gpt-4o\n    instruction: Be helpful.\n"
+	v2 = "agents:\n  root:\n    model: openai/gpt-4o\n    instruction: Be concise.\n"
+)
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	store := New(t.TempDir())
+
+	hash1, err := store.Record("/work/agent.yaml", []byte(v1))
+	require.NoError(t, err)
+	assert.Equal(t, Hash([]byte(v1)), hash1)
+
+	// Running the same version again doesn't add a version.
+	_, err = store.Record("/work/agent.yaml", []byte(v1))
+	require.NoError(t, err)
+
+	hash2, err := store.Record("/work/agent.yaml", []byte(v2))
+	require.NoError(t, err)
+	_, err = store.Record("/work/other.yaml", []byte(v1))
+	require.NoError(t, err)
+
+	versions, err := store.Versions("/work/agent.yaml")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, hash1, versions[0].Hash)
+	assert.Equal(t, hash2, versions[1].Hash)
+
+	all, err := store.Versions("")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	store := New(t.TempDir())
+
+	_, _, err := store.Get("abcd")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	hash, err := store.Record("agent", []byte(v1))
+	require.NoError(t, err)
+
+	full, data, err := store.Get(Short(hash))
+	require.NoError(t, err)
+	assert.Equal(t, hash, full)
+	assert.Equal(t, v1, string(data))
+
+	_, _, err = store.Get(hash[:2])
+	require.ErrorContains(t, err, "too short")
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, Diff("a", []byte(v1), "b", []byte(v1)))
+
+	diff := Diff("a", []byte(v1), "b", []byte(v2))
+	assert.Contains(t, diff, "--- a")
+	assert.Contains(t, diff, "+++ b")
+	assert.Contains(t, diff, "-    instruction: Be helpful.")
+	assert.Contains(t, diff, "+    instruction: Be concise.")
+}
@@ -0,0 +1,172 @@
+// Package continuity keeps a rolling summary of the sessions of an agent in a
+// project: the decisions made, the facts learned and the open items. The
+// summary is injected into the next sessions, so that agents pick up where
+// the previous session left off without a vector memory.
+//
+// Summaries are markdown files stored per project directory and agent, and
+// are generated with a one-shot model call, independently of pkg/runtime.
+package continuity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+const (
+	systemPrompt = `You maintain the continuity notes of an AI agent working in a project. The notes are given to the agent at the start of its next sessions.
+
+Merge the previous notes with what happened in the latest session and return the updated notes, in markdown, with exactly these sections:
+
+## Decisions
+## Facts
+## Open items
+
+Keep the notes short: at most 15 bullet points in total. Drop what is no longer relevant, outdated, or resolved. Never invent anything. Return only the notes.`
+
+	// maxTranscriptLength bounds the length of the transcript sent to the
+	// model, in characters. The end of long sessions is kept.
+	maxTranscriptLength = 40_000
+
+	summarizeTimeout = 2 * time.Minute
+)
+
+// Store stores summaries in a directory, per project and agent.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store of summaries in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultStore stores summaries in the data directory.
+func DefaultStore() *Store {
+	return NewStore(filepath.Join(paths.GetDataDir(), "continuity"))
+}
+
+func (s *Store) path(projectDir, agentName string) string {
+	if abs, err := filepath.Abs(projectDir); err == nil {
+		projectDir = abs
+	}
+	sum := sha256.Sum256([]byte(projectDir))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:8]), agentName+".md")
+}
+
+// Load returns the summary of the previous sessions of an agent in a
+// project, or "" if there is none.
+func (s *Store) Load(projectDir, agentName string) (string, error) {
+	data, err := os.ReadFile(s.path(projectDir, agentName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Save replaces the summary of an agent in a project.
+func (s *Store) Save(projectDir, agentName, summary string) error {
+	path := s.path(projectDir, agentName)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(summary), 0o600)
+}
+
+// Summarize merges the previous summary with the conversation of a session
+// and returns the new summary. Only the text of user and assistant messages
+// is summarized: tool calls and their outputs are left out.
+func Summarize(ctx context.Context, model provider.Provider, previous string, messages []chat.Message) (string, error) {
+	transcript := Transcript(messages)
+	if transcript == "" {
+		return previous, nil
+	}
+
+	var prompt strings.Builder
+	if previous != "" {
+		prompt.WriteString("Previous notes:\n\n")
+		prompt.WriteString(previous)
+		prompt.WriteString("\n\n")
+	}
+	prompt.WriteString("Latest session:\n\n")
+	prompt.WriteString(transcript)
+
+	ctx, cancel := context.WithTimeout(ctx, summarizeTimeout)
+	defer cancel()
+
+	model = provider.CloneWithOptions(ctx, model,
+		options.WithStructuredOutput(nil),
+		options.WithThinking(false),
+	)
+	stream, err := model.CreateChatCompletionStream(ctx, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: systemPrompt},
+		{Role: chat.MessageRoleUser, Content: prompt.String()},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var out strings.Builder
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(response.Choices) > 0 {
+			out.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+
+	summary := strings.TrimSpace(out.String())
+	if summary == "" {
+		return "", fmt.Errorf("empty summary from model %q", model.ID())
+	}
+	return summary, nil
+}
+
+// Transcript formats the user and assistant messages of a conversation,
+// keeping the end of conversations longer than maxTranscriptLength.
+func Transcript(messages []chat.Message) string {
+	var b strings.Builder
+	for i := range messages {
+		msg := &messages[i]
+		if msg.Role != chat.MessageRoleUser && msg.Role != chat.MessageRoleAssistant {
+			continue
+		}
+		content := strings.TrimSpace(msg.Content)
+		for _, part := range msg.MultiContent {
+			if part.Type == chat.MessagePartTypeText {
+				content = strings.TrimSpace(content + "\n" + part.Text)
+			}
+		}
+		if content == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n\n", msg.Role, content)
+	}
+
+	transcript := strings.TrimSpace(b.String())
+	if len(transcript) > maxTranscriptLength {
+		transcript = "...\n" + transcript[len(transcript)-maxTranscriptLength:]
+	}
+	return transcript
+}
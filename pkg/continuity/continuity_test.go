@@ -0,0 +1,128 @@
+package continuity
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+type mockProvider struct {
+	messages []chat.Message
+	output   string
+	err      error
+}
+
+func (p *mockProvider) ID() string { return "mock/model" }
+
+func (p *mockProvider) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	p.messages = messages
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &mockStream{content: p.output}, nil
+}
+
+func (p *mockProvider) BaseConfig() base.Config { return base.Config{} }
+
+type mockStream struct {
+	content string
+	done    bool
+}
+
+func (s *mockStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.done {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.done = true
+	return chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.content}}},
+	}, nil
+}
+
+func (s *mockStream) Close() {}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+
+	summary, err := store.Load("/projects/a", "root")
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+
+	require.NoError(t, store.Save("/projects/a", "root", "summary of a"))
+
+	summary, err = store.Load("/projects/a", "root")
+	require.NoError(t, err)
+	assert.Equal(t, "summary of a", summary)
+
+	// Summaries are per project and per agent.
+	summary, err = store.Load("/projects/b", "root")
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+	summary, err = store.Load("/projects/a", "reviewer")
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+}
+
+func TestTranscript(t *testing.T) {
+	t.Parallel()
+
+	transcript := Transcript([]chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "instructions"},
+		{Role: chat.MessageRoleUser, Content: "Use sqlite"},
+		{Role: chat.MessageRoleAssistant, ToolCalls: []tools.ToolCall{{ID: "call"}}},
+		{Role: chat.MessageRoleTool, Content: "tool output"},
+		{Role: chat.MessageRoleAssistant, Content: "Done"},
+	})
+	assert.Equal(t, "user: Use sqlite\n\nassistant: Done", transcript)
+
+	long := Transcript([]chat.Message{{Role: chat.MessageRoleUser, Content: strings.Repeat("x", 2*maxTranscriptLength)}})
+	assert.Len(t, long, maxTranscriptLength+len("...\n"))
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	model := &mockProvider{output: "## Decisions\n- Use sqlite\n"}
+	messages := []chat.Message{{Role: chat.MessageRoleUser, Content: "Use sqlite"}}
+
+	summary, err := Summarize(t.Context(), model, "## Facts\n- Go project", messages)
+	require.NoError(t, err)
+	assert.Equal(t, "## Decisions\n- Use sqlite", summary)
+
+	require.Len(t, model.messages, 2)
+	assert.Contains(t, model.messages[1].Content, "- Go project")
+	assert.Contains(t, model.messages[1].Content, "user: Use sqlite")
+}
+
+func TestSummarizeEmptyConversation(t *testing.T) {
+	t.Parallel()
+
+	model := &mockProvider{}
+	summary, err := Summarize(t.Context(), model, "previous", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", summary)
+	assert.Nil(t, model.messages)
+}
+
+func TestSummarizeError(t *testing.T) {
+	t.Parallel()
+
+	messages := []chat.Message{{Role: chat.MessageRoleUser, Content: "hello"}}
+
+	_, err := Summarize(t.Context(), &mockProvider{err: errors.New("unavailable")}, "", messages)
+	require.Error(t, err)
+
+	_, err = Summarize(t.Context(), &mockProvider{output: "  "}, "", messages)
+	require.Error(t, err)
+}
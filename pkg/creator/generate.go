@@ -0,0 +1,178 @@
+package creator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+)
+
+// maxGenerateAttempts is how many times the model can try to write a valid
+// configuration, its previous attempt's problems being sent back each time.
+const maxGenerateAttempts = 3
+
+const generatePrompt = `You write agent configurations in YAML for docker-agent.
+
+Answer with the complete YAML configuration only, without any explanation. Don't use any tool: the configuration will be saved for the user.
+The configuration must follow the reference and the JSON Schema below. Don't set the version field.`
+
+// Generated is an agent configuration written by a model.
+type Generated struct {
+	YAML []byte
+	// Warnings are the lint problems the model didn't fix.
+	Warnings []string
+}
+
+// Generate asks a model to write the configuration of an agent matching a
+// description. The model is given the configuration reference and, when not
+// empty, the JSON Schema of the configurations. The configuration is validated
+// and linted: the problems are sent back to the model until it fixes them, or
+// runs out of attempts.
+func Generate(ctx context.Context, runConfig *config.RuntimeConfig, model provider.Provider, description string, schema []byte) (*Generated, error) {
+	var system strings.Builder
+	system.WriteString(generatePrompt)
+	system.WriteString("\n\n# Reference\n\n")
+	system.WriteString(buildInstructions(ctx, runConfig))
+	if len(schema) > 0 {
+		system.WriteString("\n\n# JSON Schema\n\n")
+		system.Write(schema)
+	}
+
+	model = provider.CloneWithOptions(ctx, model,
+		options.WithStructuredOutput(nil),
+	)
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: system.String()},
+		{Role: chat.MessageRoleUser, Content: "Write the configuration of this agent: " + description},
+	}
+
+	for attempt := 1; ; attempt++ {
+		answer, err := complete(ctx, model, messages)
+		if err != nil {
+			return nil, err
+		}
+
+		data := extractYAML(answer)
+		cfg, loadErr := config.Load(ctx, config.NewBytesSource("agent", data))
+		var problems []string
+		if loadErr != nil {
+			problems = []string{loadErr.Error()}
+		} else if problems = Lint(cfg); len(problems) == 0 {
+			return &Generated{YAML: data}, nil
+		}
+
+		if attempt == maxGenerateAttempts {
+			if loadErr != nil {
+				return nil, fmt.Errorf("invalid agent configuration: %w", loadErr)
+			}
+			return &Generated{YAML: data, Warnings: problems}, nil
+		}
+
+		messages = append(messages,
+			chat.Message{Role: chat.MessageRoleAssistant, Content: answer},
+			chat.Message{Role: chat.MessageRoleUser, Content: "The configuration has problems:\n- " + strings.Join(problems, "\n- ") + "\n\nFix them and answer with the complete corrected YAML only."},
+		)
+	}
+}
+
+// complete returns the answer of a model to a conversation.
+func complete(ctx context.Context, model provider.Provider, messages []chat.Message) (string, error) {
+	stream, err := model.CreateChatCompletionStream(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var out strings.Builder
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(response.Choices) > 0 {
+			out.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+	return out.String(), nil
+}
+
+// extractYAML returns the YAML of an answer, without the fences of a
+// markdown code block around it.
+func extractYAML(answer string) []byte {
+	answer = strings.TrimSpace(answer)
+	if start := strings.Index(answer, "```"); start >= 0 {
+		block := answer[start+3:]
+		// Skip the language of the block.
+		if eol := strings.IndexByte(block, '\n'); eol >= 0 {
+			block = block[eol+1:]
+		}
+		if end := strings.Index(block, "```"); end >= 0 {
+			block = block[:end]
+		}
+		answer = block
+	}
+	return []byte(strings.TrimSpace(answer) + "\n")
+}
+
+// Lint returns the problems of a valid agent configuration that make it
+// unlikely to work as intended.
+func Lint(cfg *latest.Config) []string {
+	var problems []string
+
+	if _, ok := cfg.Agents.Lookup("root"); !ok {
+		problems = append(problems, `there is no agent named "root": it's the entrypoint of the configuration`)
+	}
+
+	reachable := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		agent, ok := cfg.Agents.Lookup(name)
+		if !ok || reachable[name] {
+			return
+		}
+		reachable[name] = true
+		for _, sub := range agent.SubAgents {
+			visit(sub)
+		}
+		for _, handoff := range agent.Handoffs {
+			visit(handoff)
+		}
+	}
+	visit("root")
+
+	for _, agent := range cfg.Agents {
+		if strings.TrimSpace(agent.Instruction) == "" {
+			problems = append(problems, fmt.Sprintf("agent %q has no instruction", agent.Name))
+		}
+		if len(reachable) > 0 && !reachable[agent.Name] {
+			problems = append(problems, fmt.Sprintf("agent %q is never used: it's not a sub-agent or a handoff of root, directly or not", agent.Name))
+		}
+		if agent.Name != "root" && reachable[agent.Name] && agent.Description == "" {
+			problems = append(problems, fmt.Sprintf("agent %q has no description: other agents use it to know when to delegate to it", agent.Name))
+		}
+
+		var types []string
+		for _, toolset := range agent.Toolsets {
+			if toolset.Type == "mcp" || toolset.Type == "script" || toolset.Type == "api" {
+				continue
+			}
+			if slices.Contains(types, toolset.Type) {
+				problems = append(problems, fmt.Sprintf("agent %q has the %s toolset twice", agent.Name, toolset.Type))
+			}
+			types = append(types, toolset.Type)
+		}
+	}
+
+	return problems
+}
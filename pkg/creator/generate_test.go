@@ -0,0 +1,126 @@
+package creator
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// scriptedProvider answers each request with the next of its answers.
+type scriptedProvider struct {
+	answers  []string
+	requests [][]chat.Message
+}
+
+func (p *scriptedProvider) ID() string { return "mock/model" }
+
+func (p *scriptedProvider) BaseConfig() base.Config { return base.Config{} }
+
+func (p *scriptedProvider) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	answer := p.answers[len(p.requests)]
+	p.requests = append(p.requests, messages)
+	return &scriptedStream{content: answer}, nil
+}
+
+type scriptedStream struct {
+	content string
+	done    bool
+}
+
+func (s *scriptedStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.done {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.done = true
+	return chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.content}}},
+	}, nil
+}
+
+func (s *scriptedStream) Close() {}
+
+const validAgent = "```yaml\nagents:\n  root:\n    model: openai/gpt-4o\n    instruction: Triage the alerts.\n```"
+
+func newTestRunConfig(t *testing.T) *config.RuntimeConfig {
+	t.Helper()
+
+	return &config.RuntimeConfig{
+		Config: config.Config{
+			WorkingDir: t.TempDir(),
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	model := &scriptedProvider{answers: []string{validAgent}}
+	generated, err := Generate(t.Context(), newTestRunConfig(t), model, "an alert triager", []byte(`{"title":"schema"}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "agents:\n  root:\n    model: openai/gpt-4o\n    instruction: Triage the alerts.\n", string(generated.YAML))
+	assert.Empty(t, generated.Warnings)
+	require.Len(t, model.requests, 1)
+	assert.Contains(t, model.requests[0][0].Content, `{"title":"schema"}`)
+	assert.Contains(t, model.requests[0][1].Content, "an alert triager")
+}
+
+func TestGenerate_FixesProblems(t *testing.T) {
+	t.Parallel()
+
+	model := &scriptedProvider{answers: []string{
+		"agents:\n  root:\n    model: openai/gpt-4o\n    unknown_field: true\n",
+		"agents:\n  root:\n    model: openai/gpt-4o\n",
+		validAgent,
+	}}
+	generated, err := Generate(t.Context(), newTestRunConfig(t), model, "an alert triager", nil)
+	require.NoError(t, err)
+	assert.Empty(t, generated.Warnings)
+
+	require.Len(t, model.requests, 3)
+	assert.Contains(t, model.requests[1][3].Content, "unknown_field")
+	assert.Contains(t, model.requests[2][5].Content, `agent "root" has no instruction`)
+}
+
+func TestGenerate_GivesUp(t *testing.T) {
+	t.Parallel()
+
+	invalid := "agents: [not, a, map]"
+	model := &scriptedProvider{answers: []string{invalid, invalid, invalid}}
+	_, err := Generate(t.Context(), newTestRunConfig(t), model, "an alert triager", nil)
+	require.ErrorContains(t, err, "invalid agent configuration")
+
+	noInstruction := "agents:\n  root:\n    model: openai/gpt-4o\n"
+	model = &scriptedProvider{answers: []string{noInstruction, noInstruction, noInstruction}}
+	generated, err := Generate(t.Context(), newTestRunConfig(t), model, "an alert triager", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`agent "root" has no instruction`}, generated.Warnings)
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Agents: []latest.AgentConfig{
+			{Name: "root", Instruction: "Coordinate.", SubAgents: []string{"researcher"}, Toolsets: []latest.Toolset{{Type: "shell"}, {Type: "shell"}}},
+			{Name: "researcher", Instruction: "Research."},
+			{Name: "orphan", Description: "Unused", Instruction: " "},
+		},
+	}
+
+	assert.Equal(t, []string{
+		`agent "root" has the shell toolset twice`,
+		`agent "researcher" has no description: other agents use it to know when to delegate to it`,
+		`agent "orphan" has no instruction`,
+		`agent "orphan" is never used: it's not a sub-agent or a handoff of root, directly or not`,
+	}, Lint(cfg))
+}
@@ -0,0 +1,107 @@
+package creator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/docker-agent/pkg/config"
+)
+
+// ToolsetChoice is a toolset offered by the agent wizard.
+type ToolsetChoice struct {
+	Name        string
+	Description string
+	Toolset     map[string]any
+}
+
+// ToolsetChoices lists the toolsets the agent wizard can add to an agent.
+var ToolsetChoices = []ToolsetChoice{
+	{Name: "filesystem", Description: "Read and write files", Toolset: map[string]any{"type": "filesystem"}},
+	{Name: "shell", Description: "Run shell commands", Toolset: map[string]any{"type": "shell"}},
+	{Name: "think", Description: "Reason step by step before acting", Toolset: map[string]any{"type": "think"}},
+	{Name: "todo", Description: "Keep track of a task list", Toolset: map[string]any{"type": "todo"}},
+	{Name: "memory", Description: "Remember facts across sessions", Toolset: map[string]any{"type": "memory"}},
+	{Name: "fetch", Description: "Fetch web pages", Toolset: map[string]any{"type": "fetch"}},
+	{Name: "search", Description: "Search the web with DuckDuckGo (Docker MCP Gateway)", Toolset: map[string]any{"type": "mcp", "ref": "docker:duckduckgo"}},
+}
+
+// DefaultToolsets are the toolsets the agent wizard selects by default.
+var DefaultToolsets = []string{"filesystem", "shell", "think", "todo"}
+
+// Scaffold describes an agent configuration built from the answers to the
+// agent wizard, without the help of a model.
+type Scaffold struct {
+	Description string
+	Instruction string
+	Provider    string
+	Model       string
+	// Toolsets are names of ToolsetChoices.
+	Toolsets []string
+}
+
+// YAML returns the agent configuration, after checking that it loads.
+func (s *Scaffold) YAML(ctx context.Context) ([]byte, error) {
+	toolsets := make([]map[string]any, 0, len(s.Toolsets))
+	for _, name := range s.Toolsets {
+		i := slices.IndexFunc(ToolsetChoices, func(c ToolsetChoice) bool { return c.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown toolset %q", name)
+		}
+		toolsets = append(toolsets, ToolsetChoices[i].Toolset)
+	}
+
+	rootAgent := yaml.MapSlice{
+		{Key: "model", Value: s.Provider + "/" + s.Model},
+	}
+	if s.Description != "" {
+		rootAgent = append(rootAgent, yaml.MapItem{Key: "description", Value: s.Description})
+	}
+	rootAgent = append(rootAgent, yaml.MapItem{Key: "instruction", Value: s.Instruction})
+	if len(toolsets) > 0 {
+		rootAgent = append(rootAgent, yaml.MapItem{Key: "toolsets", Value: toolsets})
+	}
+
+	data, err := yaml.MarshalWithOptions(yaml.MapSlice{
+		{Key: "agents", Value: yaml.MapSlice{{Key: "root", Value: rootAgent}}},
+	}, yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := config.Load(ctx, config.NewBytesSource("agent", data)); err != nil {
+		return nil, fmt.Errorf("invalid agent configuration: %w", err)
+	}
+	return data, nil
+}
+
+// EnvTemplate returns a .env template listing the environment variables
+// needed by the models and tools of an agent configuration.
+func EnvTemplate(ctx context.Context, data []byte) (string, error) {
+	cfg, err := config.Load(ctx, config.NewBytesSource("agent", data))
+	if err != nil {
+		return "", err
+	}
+
+	names := config.GatherEnvVarsForModels(cfg)
+	// Toolsets that can't be inspected (e.g. Docker is not running) are
+	// skipped: the template is a starting point, not a validation.
+	toolNames, _ := config.GatherEnvVarsForTools(ctx, cfg)
+	names = append(names, toolNames...)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	var b strings.Builder
+	b.WriteString("# Environment variables for this agent.\n")
+	b.WriteString("# Fill in the values, then run it with --env-from-file.\n")
+	if len(names) == 0 {
+		b.WriteString("# This agent doesn't need any.\n")
+	}
+	for _, name := range names {
+		b.WriteString(name + "=\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,56 @@
+package creator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config"
+)
+
+func TestScaffoldYAML(t *testing.T) {
+	t.Parallel()
+
+	s := Scaffold{
+		Description: "A code reviewer",
+		Instruction: "Review the code.\nBe concise.",
+		Provider:    "openai",
+		Model:       "gpt-5-mini",
+		Toolsets:    []string{"filesystem", "think"},
+	}
+
+	data, err := s.YAML(t.Context())
+	require.NoError(t, err)
+
+	cfg, err := config.Load(t.Context(), config.NewBytesSource("agent", data))
+	require.NoError(t, err)
+	root, found := cfg.Agents.Lookup("root")
+	require.True(t, found)
+	assert.Equal(t, "A code reviewer", root.Description)
+	assert.Equal(t, "Review the code.\nBe concise.", root.Instruction)
+	require.Len(t, root.Toolsets, 2)
+	assert.Equal(t, "filesystem", root.Toolsets[0].Type)
+	assert.Equal(t, "think", root.Toolsets[1].Type)
+}
+
+func TestScaffoldYAML_UnknownToolset(t *testing.T) {
+	t.Parallel()
+
+	s := Scaffold{Instruction: "Help.", Provider: "openai", Model: "gpt-5-mini", Toolsets: []string{"teleport"}}
+
+	_, err := s.YAML(t.Context())
+	require.ErrorContains(t, err, `unknown toolset "teleport"`)
+}
+
+func TestEnvTemplate(t *testing.T) {
+	t.Parallel()
+
+	s := Scaffold{Instruction: "Help.", Provider: "openai", Model: "gpt-5-mini", Toolsets: []string{"filesystem"}}
+	data, err := s.YAML(t.Context())
+	require.NoError(t, err)
+
+	env, err := EnvTemplate(t.Context(), data)
+	require.NoError(t, err)
+	assert.Contains(t, env, "\nOPENAI_API_KEY=\n")
+}
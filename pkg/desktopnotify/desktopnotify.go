@@ -0,0 +1,65 @@
+// Package desktopnotify provides cross-platform desktop notifications.
+// It sends notifications asynchronously so that users can step away from
+// the terminal while an agent works.
+package desktopnotify
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Send shows a desktop notification in the background.
+// It is non-blocking and safe to call from any goroutine.
+// If the notification cannot be shown, the error is logged and silently ignored.
+func Send(title, message string) {
+	go func() {
+		if err := send(title, message); err != nil {
+			slog.Debug("Failed to send desktop notification", "title", title, "error", err)
+		}
+	}()
+}
+
+func send(title, message string) error {
+	name, args := command(runtime.GOOS, title, message)
+	if name == "" {
+		return nil
+	}
+	return exec.Command(name, args...).Run()
+}
+
+// command returns the command that shows a notification on the given OS,
+// or an empty name if notifications are not supported.
+func command(goos, title, message string) (string, []string) {
+	switch goos {
+	case "darwin":
+		// Use AppleScript, available on every macOS install
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return "osascript", []string{"-e", script}
+	case "linux":
+		// notify-send talks to the freedesktop notification daemon
+		return "notify-send", []string{"--app-name=docker agent", title, message}
+	case "windows":
+		// Use PowerShell to show a balloon tip from the notification area
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, %s, %s, [System.Windows.Forms.ToolTipIcon]::Info)
+Start-Sleep -Seconds 6
+$n.Dispose()`, powerShellString(title), powerShellString(message))
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	default:
+		return "", nil
+	}
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
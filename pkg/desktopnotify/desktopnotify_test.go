@@ -0,0 +1,26 @@
+package desktopnotify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	name, args := command("darwin", "docker agent", `Run "build"`)
+	assert.Equal(t, "osascript", name)
+	assert.Equal(t, []string{"-e", `display notification "Run \"build\"" with title "docker agent"`}, args)
+
+	name, args = command("linux", "docker agent", "Done")
+	assert.Equal(t, "notify-send", name)
+	assert.Equal(t, []string{"--app-name=docker agent", "docker agent", "Done"}, args)
+
+	name, args = command("windows", "docker agent", "It's done")
+	assert.Equal(t, "powershell", name)
+	assert.Contains(t, args[len(args)-1], `'docker agent', 'It''s done'`)
+
+	name, _ = command("plan9", "docker agent", "Done")
+	assert.Empty(t, name)
+}
@@ -0,0 +1,45 @@
+// Package doctor diagnoses the environment docker-agent runs in: API keys,
+// Docker and Docker Model Runner, git, MCP servers, the terminal and the
+// session database. Each check reports what it found and how to fix it.
+package doctor
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusError   Status = "error"
+	// StatusSkipped is used for optional things that aren't set up, such
+	// as the API key of a provider that isn't used.
+	StatusSkipped Status = "skipped"
+)
+
+// Check is the result of one diagnostic.
+type Check struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Status   Status `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	// Fix tells how to solve a problem.
+	Fix string `json:"fix,omitempty"`
+}
+
+const (
+	CategoryProviders = "Model providers"
+	CategoryDocker    = "Docker"
+	CategoryTools     = "Tools"
+	CategoryTerminal  = "Terminal"
+	CategorySessions  = "Sessions"
+)
+
+// Count returns the number of checks with the given status.
+func Count(checks []Check, status Status) int {
+	var n int
+	for _, c := range checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,203 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider"
+)
+
+// ProviderOptions configures CheckProviders.
+type ProviderOptions struct {
+	Env environment.Provider
+	// Required lists the environment variables needed by the models of an
+	// agent file. A missing required key is an error, a missing optional
+	// key is skipped.
+	Required []string
+	// Offline only checks that the keys are set, without validating them.
+	Offline    bool
+	HTTPClient *http.Client
+}
+
+// providerKey describes how to find and validate the API key of a provider.
+type providerKey struct {
+	provider string
+	// envVars hold the key, in order of preference.
+	envVars []string
+	// url is called with the key to validate it. Keys are only checked
+	// for presence when it's empty.
+	url  string
+	auth func(req *http.Request, key string)
+}
+
+func bearer(req *http.Request, key string) {
+	req.Header.Set("Authorization", "Bearer "+key)
+}
+
+// providerKeys lists the providers whose key can be checked: listing the
+// available models is free with every provider.
+func providerKeys() []providerKey {
+	keys := []providerKey{
+		{
+			provider: "anthropic",
+			envVars:  []string{"ANTHROPIC_API_KEY"},
+			url:      "https://api.anthropic.com/v1/models",
+			auth: func(req *http.Request, key string) {
+				req.Header.Set("x-api-key", key)
+				req.Header.Set("anthropic-version", "2023-06-01")
+			},
+		},
+		{
+			provider: "openai",
+			envVars:  []string{"OPENAI_API_KEY"},
+			url:      "https://api.openai.com/v1/models",
+			auth:     bearer,
+		},
+		{
+			provider: "google",
+			envVars:  []string{"GOOGLE_API_KEY", "GEMINI_API_KEY"},
+			url:      "https://generativelanguage.googleapis.com/v1beta/models",
+			auth: func(req *http.Request, key string) {
+				req.Header.Set("x-goog-api-key", key)
+			},
+		},
+		{
+			provider: "amazon-bedrock",
+			envVars:  []string{"AWS_BEARER_TOKEN_BEDROCK", "AWS_ACCESS_KEY_ID", "AWS_PROFILE", "AWS_ROLE_ARN"},
+		},
+	}
+
+	var aliases []providerKey
+	for name, alias := range provider.Aliases {
+		if alias.TokenEnvVar == "" {
+			continue
+		}
+		key := providerKey{provider: name, envVars: []string{alias.TokenEnvVar}}
+		if alias.BaseURL != "" {
+			key.url = strings.TrimSuffix(alias.BaseURL, "/") + "/models"
+			key.auth = bearer
+		}
+		aliases = append(aliases, key)
+	}
+	slices.SortFunc(aliases, func(a, b providerKey) int { return strings.Compare(a.provider, b.provider) })
+
+	return append(keys, aliases...)
+}
+
+// CheckProviders checks the API keys of the model providers, and validates
+// them with a cheap call to each provider.
+func CheckProviders(ctx context.Context, opts ProviderOptions) []Check {
+	return checkProviderKeys(ctx, opts, providerKeys())
+}
+
+func checkProviderKeys(ctx context.Context, opts ProviderOptions, keys []providerKey) []Check {
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var checks []Check
+	known := map[string]bool{}
+	for _, key := range keys {
+		for _, envVar := range key.envVars {
+			known[envVar] = true
+		}
+		checks = append(checks, checkProviderKey(ctx, opts, httpClient, key))
+	}
+
+	// Keys of custom providers can only be checked for presence.
+	for _, envVar := range opts.Required {
+		if known[envVar] {
+			continue
+		}
+		check := Check{Category: CategoryProviders, Name: envVar, Status: StatusOK, Detail: envVar + " is set"}
+		if value, _ := opts.Env.Get(ctx, envVar); value == "" {
+			check.Status = StatusError
+			check.Detail = envVar + " is not set, but the agent needs it"
+			check.Fix = setFix(envVar)
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+func checkProviderKey(ctx context.Context, opts ProviderOptions, httpClient *http.Client, key providerKey) Check {
+	check := Check{Category: CategoryProviders, Name: key.provider}
+
+	var envVar, value string
+	for _, name := range key.envVars {
+		if v, _ := opts.Env.Get(ctx, name); v != "" {
+			envVar, value = name, v
+			break
+		}
+	}
+
+	if value == "" {
+		names := strings.Join(key.envVars, " or ")
+		if slices.ContainsFunc(key.envVars, func(name string) bool { return slices.Contains(opts.Required, name) }) {
+			check.Status = StatusError
+			check.Detail = names + " is not set, but the agent needs it"
+			check.Fix = setFix(key.envVars[0])
+		} else {
+			check.Status = StatusSkipped
+			check.Detail = names + " is not set"
+		}
+		return check
+	}
+
+	if key.url == "" || opts.Offline {
+		check.Status = StatusOK
+		check.Detail = envVar + " is set"
+		return check
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key.url, http.NoBody)
+	if err != nil {
+		check.Status = StatusError
+		check.Detail = err.Error()
+		return check
+	}
+	key.auth(req, value)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		host := key.url
+		if u, parseErr := url.Parse(key.url); parseErr == nil {
+			host = u.Host
+		}
+		check.Status = StatusError
+		check.Detail = fmt.Sprintf("can't reach %s: %s", host, err)
+		check.Fix = "Check your network connection, and the HTTPS_PROXY environment variable if you're behind a proxy"
+		return check
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		check.Status = StatusOK
+		check.Detail = envVar + " is valid"
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		check.Status = StatusError
+		check.Detail = fmt.Sprintf("%s was rejected (%s)", envVar, resp.Status)
+		check.Fix = fmt.Sprintf("The key is invalid, expired or revoked: create a new %s key and update %s", key.provider, envVar)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		check.Status = StatusWarning
+		check.Detail = fmt.Sprintf("%s is rate limited (%s)", envVar, resp.Status)
+		check.Fix = "Wait a moment, or check the quota and billing of your account"
+	default:
+		check.Status = StatusWarning
+		check.Detail = fmt.Sprintf("%s couldn't be validated (%s)", envVar, resp.Status)
+	}
+	return check
+}
+
+func setFix(envVar string) string {
+	return fmt.Sprintf("Set %s in your environment, or in a file passed with --env-from-file", envVar)
+}
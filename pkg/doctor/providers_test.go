@@ -0,0 +1,116 @@
+package doctor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+func TestCheckProviderKeys(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer good":
+			w.WriteHeader(http.StatusOK)
+		case "Bearer limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	keys := []providerKey{
+		{provider: "good", envVars: []string{"GOOD_KEY"}, url: server.URL, auth: bearer},
+		{provider: "bad", envVars: []string{"BAD_KEY"}, url: server.URL, auth: bearer},
+		{provider: "limited", envVars: []string{"LIMITED_KEY"}, url: server.URL, auth: bearer},
+		{provider: "unused", envVars: []string{"UNUSED_KEY"}, url: server.URL, auth: bearer},
+		{provider: "needed", envVars: []string{"NEEDED_KEY"}, url: server.URL, auth: bearer},
+		{provider: "presence", envVars: []string{"FIRST_KEY", "SECOND_KEY"}},
+	}
+	opts := ProviderOptions{
+		Env: environment.NewMapEnvProvider(map[string]string{
+			"GOOD_KEY":    "good",
+			"BAD_KEY":     "bad",
+			"LIMITED_KEY": "limited",
+			"SECOND_KEY":  "anything",
+			"CUSTOM_KEY":  "anything",
+		}),
+		Required: []string{"NEEDED_KEY", "CUSTOM_KEY", "MISSING_KEY"},
+	}
+
+	checks := checkProviderKeys(t.Context(), opts, keys)
+	require.Len(t, checks, 8)
+
+	statuses := map[string]Status{}
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, map[string]Status{
+		"good":        StatusOK,
+		"bad":         StatusError,
+		"limited":     StatusWarning,
+		"unused":      StatusSkipped,
+		"needed":      StatusError,
+		"presence":    StatusOK,
+		"CUSTOM_KEY":  StatusOK,
+		"MISSING_KEY": StatusError,
+	}, statuses)
+
+	assert.Equal(t, "BAD_KEY was rejected (401 Unauthorized)", checks[1].Detail)
+	assert.NotEmpty(t, checks[1].Fix)
+	assert.Equal(t, "SECOND_KEY is set", checks[5].Detail)
+}
+
+func TestCheckProviderKeys_Offline(t *testing.T) {
+	t.Parallel()
+
+	keys := []providerKey{{provider: "p", envVars: []string{"P_KEY"}, url: "http://127.0.0.1:0", auth: bearer}}
+	opts := ProviderOptions{
+		Env:     environment.NewMapEnvProvider(map[string]string{"P_KEY": "key"}),
+		Offline: true,
+	}
+
+	checks := checkProviderKeys(t.Context(), opts, keys)
+
+	require.Len(t, checks, 1)
+	assert.Equal(t, StatusOK, checks[0].Status)
+	assert.Equal(t, "P_KEY is set", checks[0].Detail)
+}
+
+func TestCheckProviderKeys_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	keys := []providerKey{{provider: "p", envVars: []string{"P_KEY"}, url: server.URL, auth: bearer}}
+	opts := ProviderOptions{Env: environment.NewMapEnvProvider(map[string]string{"P_KEY": "key"})}
+
+	checks := checkProviderKeys(t.Context(), opts, keys)
+
+	require.Len(t, checks, 1)
+	assert.Equal(t, StatusError, checks[0].Status)
+	assert.Contains(t, checks[0].Detail, "can't reach 127.0.0.1")
+	assert.Contains(t, checks[0].Fix, "HTTPS_PROXY")
+}
+
+func TestProviderKeys(t *testing.T) {
+	t.Parallel()
+
+	names := map[string]providerKey{}
+	for _, key := range providerKeys() {
+		names[key.provider] = key
+	}
+
+	assert.Equal(t, []string{"GOOGLE_API_KEY", "GEMINI_API_KEY"}, names["google"].envVars)
+	assert.Equal(t, "https://api.mistral.ai/v1/models", names["mistral"].url)
+	assert.Empty(t, names["azure"].url, "azure has no default endpoint")
+	assert.NotContains(t, names, "ollama", "ollama doesn't use a key")
+}
@@ -0,0 +1,221 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider/dmr"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+const commandTimeout = 10 * time.Second
+
+// output runs a command and returns its trimmed output, or its first line
+// of error output.
+func output(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if line, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); line != "" {
+			return "", errors.New(line)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// CheckDocker checks the Docker CLI, the Docker daemon and Docker Model
+// Runner. Docker is optional: it's needed for local models, sandboxes and
+// the MCP Gateway.
+func CheckDocker(ctx context.Context) []Check {
+	cli := Check{Category: CategoryDocker, Name: "docker", Status: StatusOK}
+	path, err := exec.LookPath("docker")
+	if err != nil {
+		cli.Status = StatusWarning
+		cli.Detail = "docker is not in the PATH"
+		cli.Fix = "Install Docker Desktop (https://docs.docker.com/desktop/) to use local models, sandboxes and the MCP Gateway"
+		return []Check{cli}
+	}
+	cli.Detail = path
+
+	daemon := Check{Category: CategoryDocker, Name: "daemon", Status: StatusOK}
+	version, err := output(ctx, "docker", "version", "--format", "{{.Server.Version}}")
+	if err != nil {
+		daemon.Status = StatusWarning
+		daemon.Detail = "the Docker daemon isn't reachable: " + err.Error()
+		daemon.Fix = "Start Docker Desktop, or the Docker daemon"
+		return []Check{cli, daemon}
+	}
+	daemon.Detail = "Docker Engine " + version
+
+	modelRunner := Check{Category: CategoryDocker, Name: "model runner", Status: StatusOK}
+	statusCtx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	engine, err := dmr.Status(statusCtx)
+	switch {
+	case errors.Is(err, dmr.ErrNotRunning):
+		modelRunner.Status = StatusWarning
+		modelRunner.Detail = "Docker Model Runner is not running"
+		modelRunner.Fix = "Run `docker desktop enable model-runner` (Docker Desktop) or `docker model install-runner` (Docker Engine)"
+	case err != nil:
+		modelRunner.Status = StatusSkipped
+		modelRunner.Detail = "Docker Model Runner is not installed"
+		modelRunner.Fix = "Install it to run models locally: https://docs.docker.com/ai/model-runner/get-started/"
+	default:
+		modelRunner.Detail = "running with " + engine
+	}
+
+	return []Check{cli, daemon, modelRunner}
+}
+
+// CheckGit checks that git is installed.
+func CheckGit(ctx context.Context) Check {
+	check := Check{Category: CategoryTools, Name: "git", Status: StatusOK}
+	if _, err := exec.LookPath("git"); err != nil {
+		check.Status = StatusWarning
+		check.Detail = "git is not in the PATH"
+		check.Fix = "Install git (https://git-scm.com/downloads): it's used to record the repository and branch of sessions, and by git tools"
+		return check
+	}
+
+	version, err := output(ctx, "git", "--version")
+	if err != nil {
+		check.Status = StatusWarning
+		check.Detail = "git doesn't run: " + err.Error()
+		return check
+	}
+	check.Detail = version
+	return check
+}
+
+// CheckMCP checks that the MCP servers of the agents can be started: that
+// their command is in the PATH, or that Docker is available for servers of
+// the MCP Gateway. It doesn't start them.
+func CheckMCP(cfg *latest.Config, agentFile string) []Check {
+	var checks []Check
+	seen := map[string]bool{}
+	for _, agentConfig := range cfg.Agents {
+		for _, toolset := range agentConfig.Toolsets {
+			if toolset.Type != "mcp" {
+				continue
+			}
+
+			check := Check{Category: CategoryTools, Status: StatusOK}
+			switch {
+			case toolset.Ref != "":
+				check.Name = toolset.Ref
+				if _, err := exec.LookPath("docker"); err != nil {
+					check.Status = StatusError
+					check.Detail = "the MCP Gateway needs Docker, which is not in the PATH"
+					check.Fix = "Install Docker Desktop (https://docs.docker.com/desktop/)"
+				} else {
+					check.Detail = "runs with the Docker MCP Gateway"
+				}
+			case toolset.Command != "":
+				check.Name = toolset.Command
+				if path, err := exec.LookPath(toolset.Command); err == nil {
+					check.Detail = path
+					break
+				}
+				check.Detail = fmt.Sprintf("command %q is not in the PATH", toolset.Command)
+				check.Fix = fmt.Sprintf("Install it, or fix the command of the toolset. Run `docker-agent mcp doctor %s` to start the server", agentFile)
+				if version := strings.ToLower(toolset.Version); version == "false" || version == "off" {
+					check.Status = StatusError
+				} else {
+					check.Status = StatusWarning
+					check.Detail += ", docker-agent will try to install it"
+				}
+			case toolset.Remote.URL != "":
+				check.Name = toolset.Remote.URL
+				check.Detail = "remote server"
+			default:
+				continue
+			}
+
+			if seen[check.Name] {
+				continue
+			}
+			seen[check.Name] = true
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// CheckSessions checks the session database, without changing it.
+func CheckSessions(ctx context.Context, path string) []Check {
+	db := Check{Category: CategorySessions, Name: "database", Status: StatusOK}
+	health, err := session.CheckSQLiteSessionStore(ctx, path)
+	switch {
+	case err != nil:
+		db.Status = StatusError
+		db.Detail = fmt.Sprintf("%s can't be read: %s", path, err)
+		db.Fix = fmt.Sprintf("docker-agent will move it to %s.bak and start a new database. Remove it to start afresh now", path)
+	case !health.Exists:
+		db.Detail = path + " will be created on first use"
+	case len(health.Integrity) > 0:
+		db.Status = StatusError
+		db.Detail = fmt.Sprintf("%s is corrupted: %s", path, strings.Join(health.Integrity, "; "))
+		db.Fix = fmt.Sprintf("Move %s away to start a new database", path)
+	default:
+		db.Detail = fmt.Sprintf("%s, %d sessions, %s", path, health.Sessions, formatSize(health.Size))
+		if len(health.PendingMigrations) > 0 {
+			db.Detail += fmt.Sprintf(", %d migrations will be applied on next use", len(health.PendingMigrations))
+		}
+	}
+
+	return []Check{db, checkWritable(filepath.Dir(path))}
+}
+
+// checkWritable checks that new files can be created in dir, or in its
+// closest existing parent.
+func checkWritable(dir string) Check {
+	check := Check{Category: CategorySessions, Name: "directory", Status: StatusOK, Detail: dir + " is writable"}
+
+	existing := dir
+	for {
+		if _, err := os.Stat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	f, err := os.CreateTemp(existing, ".docker-agent-doctor-*")
+	if err != nil {
+		check.Status = StatusError
+		check.Detail = fmt.Sprintf("%s is not writable: %s", existing, err)
+		check.Fix = fmt.Sprintf("Fix the permissions of %s, or pass another database with --session-db", existing)
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return check
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,77 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+)
+
+func TestCheckMCP(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Agents: latest.Agents{
+			{Name: "root", Toolsets: []latest.Toolset{
+				{Type: "mcp", Command: "go"},
+				{Type: "mcp", Command: "surely-not-installed-mcp"},
+				{Type: "mcp", Command: "surely-not-installed-either", Version: "off"},
+				{Type: "mcp", Remote: latest.Remote{URL: "https://mcp.example.com"}},
+				{Type: "filesystem"},
+			}},
+			{Name: "helper", Toolsets: []latest.Toolset{
+				{Type: "mcp", Command: "go"},
+			}},
+		},
+	}
+
+	checks := CheckMCP(cfg, "agent.yaml")
+
+	require.Len(t, checks, 4)
+	assert.Equal(t, StatusOK, checks[0].Status)
+	assert.Equal(t, StatusWarning, checks[1].Status)
+	assert.Contains(t, checks[1].Fix, "docker-agent mcp doctor agent.yaml")
+	assert.Equal(t, StatusError, checks[2].Status, "commands that can't be installed are errors")
+	assert.Equal(t, "https://mcp.example.com", checks[3].Name)
+}
+
+func TestCheckSessions(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "new", "session.db")
+	checks := CheckSessions(t.Context(), dbPath)
+
+	require.Len(t, checks, 2)
+	assert.Equal(t, StatusOK, checks[0].Status)
+	assert.Contains(t, checks[0].Detail, "will be created on first use")
+	assert.Equal(t, StatusOK, checks[1].Status)
+	assert.NoDirExists(t, filepath.Dir(dbPath), "the check must not create anything")
+}
+
+func TestCheckSessions_Corrupt(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "session.db")
+	require.NoError(t, os.WriteFile(dbPath, []byte("not a database"), 0o600))
+
+	checks := CheckSessions(t.Context(), dbPath)
+
+	assert.Equal(t, StatusError, checks[0].Status)
+	assert.Contains(t, checks[0].Fix, dbPath+".bak")
+}
+
+func TestCheckGitAndDocker_NotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	git := CheckGit(t.Context())
+	assert.Equal(t, StatusWarning, git.Status)
+	assert.NotEmpty(t, git.Fix)
+
+	docker := CheckDocker(t.Context())
+	require.Len(t, docker, 1)
+	assert.Equal(t, StatusWarning, docker[0].Status)
+}
@@ -0,0 +1,80 @@
+package doctor
+
+import (
+	"cmp"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/termimage"
+)
+
+// CheckTerminal checks the capabilities of the terminal the TUI runs in,
+// guessed from the environment variables returned by getenv.
+func CheckTerminal(isTerminal bool, getenv func(string) string) []Check {
+	interactive := Check{Category: CategoryTerminal, Name: "interactive", Status: StatusOK, Detail: "stdout is a terminal"}
+	if !isTerminal {
+		interactive.Status = StatusWarning
+		interactive.Detail = "stdout is not a terminal"
+		interactive.Fix = "The TUI needs a terminal. Use `docker-agent run --exec` in scripts and pipelines"
+	}
+
+	term := getenv("TERM")
+	colors := Check{Category: CategoryTerminal, Name: "colors", Status: StatusOK}
+	switch colorTerm := getenv("COLORTERM"); {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		colors.Detail = "true color"
+	case strings.Contains(term, "256color"):
+		colors.Detail = "256 colors"
+	case runtime.GOOS == "windows":
+		colors.Detail = "Windows console"
+	case term == "" || term == "dumb":
+		colors.Status = StatusWarning
+		colors.Detail = "TERM is " + cmp.Or(term, "not set")
+		colors.Fix = "Set TERM=xterm-256color, or use a terminal that supports colors"
+	default:
+		colors.Detail = "TERM is " + term
+	}
+
+	unicode := Check{Category: CategoryTerminal, Name: "unicode", Status: StatusOK}
+	// The first of LC_ALL, LC_CTYPE and LANG that is set wins.
+	locale := cmp.Or(getenv("LC_ALL"), getenv("LC_CTYPE"), getenv("LANG"))
+	switch normalized := strings.ToLower(strings.ReplaceAll(locale, "-", "")); {
+	case strings.Contains(normalized, "utf8"):
+		unicode.Detail = "locale is " + locale
+	case runtime.GOOS == "windows":
+		unicode.Detail = "Windows console"
+	default:
+		unicode.Status = StatusWarning
+		unicode.Detail = "the locale is " + cmp.Or(locale, "not set") + ", so icons and borders may not render"
+		unicode.Fix = "Set LANG to a UTF-8 locale, for example LANG=en_US.UTF-8"
+	}
+
+	images := Check{Category: CategoryTerminal, Name: "images", Status: StatusOK}
+	if protocol := termimage.Detect(); protocol != termimage.None {
+		images.Detail = "full resolution with the " + protocol.String() + " protocol"
+	} else {
+		images.Detail = "colored blocks"
+		if getenv("TMUX") != "" {
+			images.Detail += " inside tmux"
+		}
+	}
+
+	editor := Check{Category: CategoryTerminal, Name: "editor", Status: StatusOK}
+	if name := cmp.Or(getenv("VISUAL"), getenv("EDITOR")); name != "" {
+		editor.Detail = name
+	} else {
+		fallback := "vi"
+		if runtime.GOOS == "windows" {
+			fallback = "notepad"
+		}
+		editor.Detail = "$VISUAL and $EDITOR are not set, " + fallback + " is used"
+		if _, err := exec.LookPath(fallback); err != nil {
+			editor.Status = StatusWarning
+			editor.Detail = "$VISUAL and $EDITOR are not set, and " + fallback + " is not in the PATH"
+			editor.Fix = "Set $EDITOR to edit messages in your editor with Ctrl+G, for example EDITOR=\"code --wait\""
+		}
+	}
+
+	return []Check{interactive, colors, unicode, images, editor}
+}
@@ -0,0 +1,52 @@
+package doctor
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTerminal(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"TERM":      "xterm-256color",
+		"COLORTERM": "truecolor",
+		"LANG":      "en_US.UTF-8",
+		"EDITOR":    "nvim",
+	}
+
+	checks := CheckTerminal(true, func(name string) string { return env[name] })
+
+	for _, check := range checks {
+		assert.Equal(t, StatusOK, check.Status, check.Name)
+	}
+	assert.Equal(t, "true color", checks[1].Detail)
+	assert.Equal(t, "nvim", checks[4].Detail)
+}
+
+func TestCheckTerminal_Problems(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the Windows console supports colors and unicode")
+	}
+
+	env := map[string]string{
+		"TERM":   "dumb",
+		"LC_ALL": "C",
+		"LANG":   "en_US.UTF-8",
+	}
+
+	checks := CheckTerminal(false, func(name string) string { return env[name] })
+
+	statuses := map[string]Status{}
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, StatusWarning, statuses["interactive"])
+	assert.Equal(t, StatusWarning, statuses["colors"])
+	assert.Equal(t, StatusWarning, statuses["unicode"], "LC_ALL wins over LANG")
+	assert.Contains(t, checks[2].Detail, "the locale is C")
+}
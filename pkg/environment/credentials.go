@@ -0,0 +1,73 @@
+package environment
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// credentialService is the name the secrets of docker agent are stored under
+// in the system's credential store.
+const credentialService = "docker-agent"
+
+// CredentialStore stores secrets, like the API keys of model providers, in
+// the system's credential store: the macOS Keychain, the Windows Credential
+// Manager or the Secret Service on Linux.
+type CredentialStore interface {
+	// Get returns the secret stored under name, if any.
+	Get(ctx context.Context, name string) (string, bool, error)
+	// Set stores a secret under name, replacing the previous one.
+	Set(ctx context.Context, name, value string) error
+	// Delete removes the secret stored under name, if any.
+	Delete(ctx context.Context, name string) error
+}
+
+type CredentialStoreNotAvailableError struct{}
+
+func (CredentialStoreNotAvailableError) Error() string {
+	return "no credential store available on this system"
+}
+
+// NewCredentialStore returns the credential store of the system, or a
+// CredentialStoreNotAvailableError if there's none.
+func NewCredentialStore() (CredentialStore, error) {
+	return newSystemCredentialStore()
+}
+
+// CredentialStoreProvider is a provider that retrieves secrets stored with
+// `docker agent auth set`. Lookups are cached, since they run a command on
+// most systems.
+type CredentialStoreProvider struct {
+	store CredentialStore
+	cache sync.Map // name -> credentialLookup
+}
+
+type credentialLookup struct {
+	value string
+	found bool
+}
+
+// NewCredentialStoreProvider creates a provider reading from the credential
+// store of the system.
+func NewCredentialStoreProvider() (*CredentialStoreProvider, error) {
+	store, err := NewCredentialStore()
+	if err != nil {
+		return nil, err
+	}
+	return &CredentialStoreProvider{store: store}, nil
+}
+
+func (p *CredentialStoreProvider) Get(ctx context.Context, name string) (string, bool) {
+	if cached, ok := p.cache.Load(name); ok {
+		lookup := cached.(credentialLookup)
+		return lookup.value, lookup.found
+	}
+
+	value, found, err := p.store.Get(ctx, name)
+	if err != nil {
+		slog.Debug("Failed to find secret in credential store", "error", err)
+		return "", false
+	}
+	p.cache.Store(name, credentialLookup{value: value, found: found})
+	return value, found
+}
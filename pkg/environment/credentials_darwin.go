@@ -0,0 +1,41 @@
+package environment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/docker/docker-agent/pkg/secretkey"
+)
+
+// keychainCredentialStore stores secrets in the macOS Keychain, as generic
+// passwords of the docker-agent service.
+type keychainCredentialStore struct {
+	keychain *secretkey.Keychain
+}
+
+func newSystemCredentialStore() (CredentialStore, error) {
+	keychain, ok := secretkey.NewKeychain()
+	if !ok {
+		return nil, CredentialStoreNotAvailableError{}
+	}
+	return &keychainCredentialStore{keychain: keychain}, nil
+}
+
+func (s *keychainCredentialStore) Get(ctx context.Context, name string) (string, bool, error) {
+	value, err := s.keychain.Find(ctx, credentialService, name)
+	if errors.Is(err, secretkey.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (s *keychainCredentialStore) Set(ctx context.Context, name, value string) error {
+	return s.keychain.Add(ctx, credentialService, name, value, true)
+}
+
+func (s *keychainCredentialStore) Delete(ctx context.Context, name string) error {
+	return s.keychain.Delete(ctx, credentialService, name)
+}
@@ -0,0 +1,41 @@
+package environment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/docker/docker-agent/pkg/secretkey"
+)
+
+// secretServiceCredentialStore stores secrets in the Secret Service, like
+// GNOME Keyring or KWallet, through secret-tool.
+type secretServiceCredentialStore struct {
+	secretService *secretkey.SecretService
+}
+
+func newSystemCredentialStore() (CredentialStore, error) {
+	secretService, ok := secretkey.NewSecretService()
+	if !ok {
+		return nil, CredentialStoreNotAvailableError{}
+	}
+	return &secretServiceCredentialStore{secretService: secretService}, nil
+}
+
+func (s *secretServiceCredentialStore) Get(ctx context.Context, name string) (string, bool, error) {
+	value, err := s.secretService.Lookup(ctx, "service", credentialService, "name", name)
+	if errors.Is(err, secretkey.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (s *secretServiceCredentialStore) Set(ctx context.Context, name, value string) error {
+	return s.secretService.Store(ctx, "docker agent: "+name, value, "service", credentialService, "name", name)
+}
+
+func (s *secretServiceCredentialStore) Delete(ctx context.Context, name string) error {
+	return s.secretService.Clear(ctx, "service", credentialService, "name", name)
+}
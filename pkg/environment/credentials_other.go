@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !windows
+
+package environment
+
+func newSystemCredentialStore() (CredentialStore, error) {
+	return nil, CredentialStoreNotAvailableError{}
+}
@@ -0,0 +1,49 @@
+package environment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCredentialStore struct {
+	secrets map[string]string
+	gets    int
+}
+
+func (s *fakeCredentialStore) Get(_ context.Context, name string) (string, bool, error) {
+	s.gets++
+	value, found := s.secrets[name]
+	return value, found, nil
+}
+
+func (s *fakeCredentialStore) Set(_ context.Context, name, value string) error {
+	s.secrets[name] = value
+	return nil
+}
+
+func (s *fakeCredentialStore) Delete(_ context.Context, name string) error {
+	delete(s.secrets, name)
+	return nil
+}
+
+func TestCredentialStoreProvider(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeCredentialStore{secrets: map[string]string{"OPENAI_API_KEY": "sk-stored"}}
+	provider := &CredentialStoreProvider{store: store}
+
+	value, found := provider.Get(t.Context(), "OPENAI_API_KEY")
+	require.True(t, found)
+	assert.Equal(t, "sk-stored", value)
+
+	_, found = provider.Get(t.Context(), "ANTHROPIC_API_KEY")
+	assert.False(t, found)
+
+	// Lookups are cached
+	_, _ = provider.Get(t.Context(), "OPENAI_API_KEY")
+	_, _ = provider.Get(t.Context(), "ANTHROPIC_API_KEY")
+	assert.Equal(t, 2, store.gets)
+}
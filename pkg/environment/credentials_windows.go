@@ -0,0 +1,112 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	maxCredentialBlobSize   = 5 * 512
+	errorNotFound           = windows.Errno(1168) // ERROR_NOT_FOUND
+)
+
+var (
+	advapi32       = windows.NewLazySystemDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// credential is the CREDENTIALW structure of the Windows Credential Manager.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// winCredentialStore stores secrets in the Windows Credential Manager, as
+// generic credentials named docker-agent:<name>.
+type winCredentialStore struct{}
+
+func newSystemCredentialStore() (CredentialStore, error) {
+	if err := procCredReadW.Find(); err != nil {
+		return nil, CredentialStoreNotAvailableError{}
+	}
+	return winCredentialStore{}, nil
+}
+
+func credentialTarget(name string) (*uint16, error) {
+	return windows.UTF16PtrFromString(credentialService + ":" + name)
+}
+
+func (winCredentialStore) Get(_ context.Context, name string) (string, bool, error) {
+	target, err := credentialTarget(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	var cred *credential
+	ret, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errors.Is(err, errorNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred))) //nolint:errcheck // CredFree returns nothing
+
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), true, nil
+}
+
+func (winCredentialStore) Set(_ context.Context, name, value string) error {
+	if len(value) > maxCredentialBlobSize {
+		return fmt.Errorf("%s is too long for the Credential Manager", name)
+	}
+	target, err := credentialTarget(name)
+	if err != nil {
+		return err
+	}
+	blob := []byte(value)
+
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	if ret, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); ret == 0 {
+		return fmt.Errorf("storing %s in the Credential Manager: %w", name, err)
+	}
+	return nil
+}
+
+func (winCredentialStore) Delete(_ context.Context, name string) error {
+	target, err := credentialTarget(name)
+	if err != nil {
+		return err
+	}
+	if ret, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); ret == 0 && !errors.Is(err, errorNotFound) {
+		return fmt.Errorf("removing %s from the Credential Manager: %w", name, err)
+	}
+	return nil
+}
@@ -5,8 +5,9 @@ import (
 	"github.com/docker/docker-agent/pkg/userconfig"
 )
 
-// NewDefaultProvider creates a provider chain with OS env, run secrets,
-// credential helper (if configured), Docker Desktop, pass, and keychain providers.
+// NewDefaultProvider creates a provider chain with OS env, run secrets, the
+// credential store, credential helper (if configured), Docker Desktop, pass,
+// and keychain providers.
 //
 // When running inside a Docker sandbox (detected via SANDBOX_VM_ID), a
 // [SandboxTokenProvider] is prepended so that DOCKER_TOKEN is read from the
@@ -30,6 +31,11 @@ func NewDefaultProvider() Provider {
 		NewRunSecretsProvider(),
 	)
 
+	// API keys stored with `docker agent auth set`
+	if credentialStoreProvider, err := NewCredentialStoreProvider(); err == nil {
+		providers = append(providers, credentialStoreProvider)
+	}
+
 	// Add credential helper provider if configured
 	if cfg, err := userconfig.Load(); err == nil && cfg.CredentialHelper != nil && cfg.CredentialHelper.Command != "" {
 		providers = append(providers, NewCredentialHelperProvider(cfg.CredentialHelper.Command, cfg.CredentialHelper.Args...))
@@ -0,0 +1,25 @@
+package environment
+
+import "os"
+
+// systemVars are the variables that processes need to run at all, e.g. to
+// find executables or a temporary directory.
+var systemVars = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TMPDIR", "TERM", "TZ", "LANG", "LC_ALL", "LC_CTYPE",
+	// Windows
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "TEMP", "TMP",
+	"USERPROFILE", "APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES",
+}
+
+// SystemEnv returns the variables of the environment that processes need to
+// run, in the KEY=VALUE form of os.Environ. It's the environment of tools
+// that don't inherit the whole environment.
+func SystemEnv() []string {
+	var env []string
+	for _, name := range systemVars {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
@@ -0,0 +1,223 @@
+// Package guardrails checks the final answers of agents against rules (denied
+// patterns, a JSON Schema or a policy checked by a model) before they're
+// shown to users.
+package guardrails
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+)
+
+// DefaultMaxRetries is how many new answers the retry action asks for
+// before blocking.
+const DefaultMaxRetries = 2
+
+const policyPrompt = `You check whether the answer of an AI assistant follows a policy.
+
+Policy:
+%s
+
+Reply with PASS if the answer follows the policy. Otherwise reply with FAIL, followed by a short explanation of what breaks the policy.`
+
+// Guardrail is a rule the final answers of an agent must follow.
+type Guardrail struct {
+	Name string
+	// Action is what happens to failing answers: block, retry or annotate.
+	Action string
+	// Message replaces blocked answers.
+	Message string
+	// MaxRetries is how many new answers retry asks for before blocking.
+	MaxRetries int
+
+	deny   []*regexp.Regexp
+	schema *jsonschema.Resolved
+	policy string
+	model  provider.Provider
+}
+
+// Violation is a guardrail an answer doesn't follow.
+type Violation struct {
+	Guardrail *Guardrail
+	Reason    string
+}
+
+// New creates a guardrail from its configuration. model checks policies; when
+// nil, the model of the agent is used.
+func New(index int, cfg latest.GuardrailConfig, model provider.Provider) (*Guardrail, error) {
+	g := &Guardrail{
+		Name:       cfg.Name,
+		Action:     cfg.Action,
+		Message:    cfg.Message,
+		MaxRetries: cfg.MaxRetries,
+		policy:     cfg.Policy,
+		model:      model,
+	}
+	if g.Name == "" {
+		g.Name = fmt.Sprintf("guardrail %d", index+1)
+	}
+	if g.Action == "" {
+		g.Action = latest.GuardrailActionBlock
+	}
+	if g.MaxRetries == 0 {
+		g.MaxRetries = DefaultMaxRetries
+	}
+	if g.Message == "" {
+		g.Message = fmt.Sprintf("This answer was withheld: it doesn't follow the %q guardrail.", g.Name)
+	}
+
+	for _, pattern := range cfg.Deny {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %q: invalid deny pattern %q: %w", g.Name, pattern, err)
+		}
+		g.deny = append(g.deny, re)
+	}
+
+	if cfg.JSONSchema != nil {
+		buf, err := json.Marshal(cfg.JSONSchema)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %q: %w", g.Name, err)
+		}
+		var schema jsonschema.Schema
+		if err := json.Unmarshal(buf, &schema); err != nil {
+			return nil, fmt.Errorf("guardrail %q: invalid JSON Schema: %w", g.Name, err)
+		}
+		if g.schema, err = schema.Resolve(nil); err != nil {
+			return nil, fmt.Errorf("guardrail %q: invalid JSON Schema: %w", g.Name, err)
+		}
+	}
+
+	return g, nil
+}
+
+// Check returns why an answer doesn't follow the guardrail, or an empty
+// string when it does. agentModel checks the policy when the guardrail has no
+// model of its own. A policy that can't be checked counts as not followed.
+func (g *Guardrail) Check(ctx context.Context, answer string, agentModel provider.Provider) string {
+	for _, re := range g.deny {
+		if match := re.FindString(answer); match != "" {
+			return fmt.Sprintf("the answer contains %q, which matches the denied pattern %q", match, re.String())
+		}
+	}
+
+	if g.schema != nil {
+		var instance any
+		if err := json.Unmarshal([]byte(StripCodeFence(answer)), &instance); err != nil {
+			return fmt.Sprintf("the answer is not valid JSON: %v", err)
+		}
+		if err := g.schema.Validate(instance); err != nil {
+			return fmt.Sprintf("the answer doesn't match the JSON Schema: %v", err)
+		}
+	}
+
+	if g.policy != "" {
+		model := g.model
+		if model == nil {
+			model = agentModel
+		}
+		reason, err := g.checkPolicy(ctx, answer, model)
+		if err != nil {
+			return fmt.Sprintf("the policy couldn't be checked: %v", err)
+		}
+		return reason
+	}
+
+	return ""
+}
+
+// Check returns the guardrails an answer doesn't follow, in order.
+func Check(ctx context.Context, guardrails []*Guardrail, answer string, agentModel provider.Provider) []Violation {
+	var violations []Violation
+	for _, g := range guardrails {
+		if reason := g.Check(ctx, answer, agentModel); reason != "" {
+			violations = append(violations, Violation{Guardrail: g, Reason: reason})
+		}
+	}
+	return violations
+}
+
+// Feedback is the message asking the model for a new answer that follows
+// the guardrails.
+func Feedback(violations []Violation) string {
+	var b strings.Builder
+	b.WriteString("Your answer was rejected and not shown to the user:\n")
+	for _, v := range violations {
+		fmt.Fprintf(&b, "- %s: %s\n", v.Guardrail.Name, v.Reason)
+	}
+	b.WriteString("\nWrite a new answer that fixes these problems. Don't mention that a previous answer was rejected.")
+	return b.String()
+}
+
+func (g *Guardrail) checkPolicy(ctx context.Context, answer string, model provider.Provider) (string, error) {
+	if model == nil {
+		return "", errors.New("no model to check the policy")
+	}
+
+	model = provider.CloneWithOptions(ctx, model,
+		options.WithStructuredOutput(nil),
+		options.WithThinking(false),
+	)
+	stream, err := model.CreateChatCompletionStream(ctx, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: fmt.Sprintf(policyPrompt, g.policy)},
+		{Role: chat.MessageRoleUser, Content: answer},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var out strings.Builder
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(response.Choices) > 0 {
+			out.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+
+	verdict := strings.TrimSpace(out.String())
+	switch {
+	case strings.HasPrefix(strings.ToUpper(verdict), "PASS"):
+		return "", nil
+	case strings.HasPrefix(strings.ToUpper(verdict), "FAIL"):
+		reason := strings.TrimLeft(verdict[len("FAIL"):], ":- \n")
+		if reason == "" {
+			reason = "the answer doesn't follow the policy"
+		}
+		return reason, nil
+	default:
+		return "", fmt.Errorf("unexpected verdict from model %q: %q", model.ID(), verdict)
+	}
+}
+
+// StripCodeFence returns the content of a markdown code block surrounding a
+// whole answer, or the answer itself.
+func StripCodeFence(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if !strings.HasPrefix(answer, "```") || !strings.HasSuffix(answer, "```") || len(answer) < 6 {
+		return answer
+	}
+	block := answer[3 : len(answer)-3]
+	// Skip the language of the block.
+	if eol := strings.IndexByte(block, '\n'); eol >= 0 {
+		block = block[eol+1:]
+	}
+	return block
+}
@@ -0,0 +1,116 @@
+package guardrails
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+type mockProvider struct {
+	output   string
+	messages []chat.Message
+}
+
+func (p *mockProvider) ID() string { return "mock/judge" }
+
+func (p *mockProvider) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	p.messages = messages
+	return &mockStream{content: p.output}, nil
+}
+
+func (p *mockProvider) BaseConfig() base.Config { return base.Config{} }
+
+type mockStream struct {
+	content string
+	done    bool
+}
+
+func (s *mockStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.done {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.done = true
+	return chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.content}}},
+	}, nil
+}
+
+func (s *mockStream) Close() {}
+
+func TestNew_Defaults(t *testing.T) {
+	t.Parallel()
+
+	g, err := New(0, latest.GuardrailConfig{Deny: []string{"secret"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "guardrail 1", g.Name)
+	assert.Equal(t, latest.GuardrailActionBlock, g.Action)
+	assert.Equal(t, DefaultMaxRetries, g.MaxRetries)
+	assert.Contains(t, g.Message, "guardrail 1")
+}
+
+func TestCheck_Deny(t *testing.T) {
+	t.Parallel()
+
+	g, err := New(0, latest.GuardrailConfig{Name: "no-keys", Deny: []string{`sk-[A-Za-z0-9]{8,}`}}, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, g.Check(t.Context(), "Set the OPENAI_API_KEY variable.", nil))
+	assert.Contains(t, g.Check(t.Context(), "Use sk-abcdefgh1234.", nil), `"sk-abcdefgh1234"`)
+}
+
+func TestCheck_JSONSchema(t *testing.T) {
+	t.Parallel()
+
+	g, err := New(0, latest.GuardrailConfig{JSONSchema: map[string]any{
+		"type":     "object",
+		"required": []any{"status"},
+		"properties": map[string]any{
+			"status": map[string]any{"type": "string", "enum": []any{"open", "closed"}},
+		},
+	}}, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, g.Check(t.Context(), `{"status": "open"}`, nil))
+	assert.Empty(t, g.Check(t.Context(), "```json\n{\"status\": \"closed\"}\n```", nil))
+	assert.Contains(t, g.Check(t.Context(), "The ticket is open.", nil), "not valid JSON")
+	assert.Contains(t, g.Check(t.Context(), `{"status": "pending"}`, nil), "JSON Schema")
+}
+
+func TestCheck_Policy(t *testing.T) {
+	t.Parallel()
+
+	g, err := New(0, latest.GuardrailConfig{Policy: "Never promise a refund."}, nil)
+	require.NoError(t, err)
+
+	judge := &mockProvider{output: "PASS"}
+	assert.Empty(t, g.Check(t.Context(), "I've opened a ticket.", judge))
+	assert.Contains(t, judge.messages[0].Content, "Never promise a refund.")
+	assert.Equal(t, "I've opened a ticket.", judge.messages[1].Content)
+
+	judge.output = "FAIL: the answer promises a refund"
+	assert.Equal(t, "the answer promises a refund", g.Check(t.Context(), "You'll get a refund.", judge))
+
+	// The guardrail's own model takes precedence over the agent's.
+	g, err = New(0, latest.GuardrailConfig{Policy: "Never promise a refund."}, &mockProvider{output: "Maybe"})
+	require.NoError(t, err)
+	assert.Contains(t, g.Check(t.Context(), "You'll get a refund.", judge), "couldn't be checked")
+}
+
+func TestFeedback(t *testing.T) {
+	t.Parallel()
+
+	g, err := New(0, latest.GuardrailConfig{Name: "json", JSONSchema: map[string]any{"type": "object"}, Action: latest.GuardrailActionRetry}, nil)
+	require.NoError(t, err)
+
+	violations := Check(t.Context(), []*Guardrail{g}, "not json", nil)
+	require.Len(t, violations, 1)
+	assert.Contains(t, Feedback(violations), "- json: the answer is not valid JSON")
+}
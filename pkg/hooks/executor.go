@@ -245,7 +245,7 @@ func (e *Executor) executeHook(ctx context.Context, hook Hook, inputJSON []byte)
 	defer cancel()
 
 	// Build command
-	cmd := exec.CommandContext(timeoutCtx, e.shell, append(e.shellArgsPrefix, hook.Command)...)
+	cmd := shellpath.Command(timeoutCtx, e.shell, e.shellArgsPrefix, hook.Command)
 	cmd.Dir = e.workingDir
 	cmd.Env = e.env
 
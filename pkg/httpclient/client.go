@@ -33,6 +33,12 @@ func NewHTTPClient(opts ...Opt) *http.Client {
 	// and decompresses responses, which is incompatible with SSE streaming.
 	// See https://github.com/docker/docker-agent/issues/1956
 	rt := newTransport()
+	if r := currentRecorder(); r != nil {
+		rt = r
+	}
+	if d := currentDebugDump(); d != nil {
+		rt = d.Wrap(rt)
+	}
 
 	return &http.Client{
 		Transport: &userAgentTransport{
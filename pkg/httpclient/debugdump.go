@@ -0,0 +1,266 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// DebugDump writes the requests sent to the model providers, and the
+// responses they stream back, to files: one file per model call, in a
+// directory per session. Credentials are redacted from the dumps.
+//
+// Only the requests whose context carries a session, see WithDebugSession,
+// are dumped.
+type DebugDump struct {
+	dir string
+
+	mu   sync.Mutex
+	seq  map[string]int
+	last string
+}
+
+// NewDebugDump returns a DebugDump writing to dir. Files are only created
+// once a request is dumped.
+func NewDebugDump(dir string) *DebugDump {
+	return &DebugDump{
+		dir: dir,
+		seq: make(map[string]int),
+	}
+}
+
+// Dir returns the directory the dumps are written to.
+func (d *DebugDump) Dir() string {
+	return d.dir
+}
+
+// Last returns the file of the last dumped request, or an empty string if
+// nothing was dumped yet.
+func (d *DebugDump) Last() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.last
+}
+
+type debugSessionKey struct{}
+
+// WithDebugSession returns a context whose model requests are dumped to the
+// directory of a session, when a DebugDump is in use.
+func WithDebugSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, debugSessionKey{}, sessionID)
+}
+
+func debugSessionFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(debugSessionKey{}).(string)
+	return sessionID
+}
+
+type debugRequestKey struct{}
+
+// debugRequest records the file a model request is dumped to.
+type debugRequest struct {
+	mu   sync.Mutex
+	file string
+}
+
+func (r *debugRequest) set(file string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.file = file
+}
+
+func (r *debugRequest) get() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file
+}
+
+// WithDebugRequest returns a context that records the file its model
+// requests are dumped to, and a function returning the file of the last one,
+// or an empty string if none was dumped. Unlike the last dump of a session,
+// it can't be the dump of another request made meanwhile.
+func WithDebugRequest(ctx context.Context) (context.Context, func() string) {
+	r := &debugRequest{}
+	return context.WithValue(ctx, debugRequestKey{}, r), r.get
+}
+
+var activeDebugDump *DebugDump
+
+// UseDebugDump makes the clients created afterwards by NewHTTPClient, and
+// the provider clients that use Transport, dump their requests to d. It
+// returns a function that stops dumping.
+func UseDebugDump(d *DebugDump) (restore func()) {
+	transportMu.Lock()
+	previous := activeDebugDump
+	activeDebugDump = d
+	transportMu.Unlock()
+
+	return func() {
+		transportMu.Lock()
+		activeDebugDump = previous
+		transportMu.Unlock()
+	}
+}
+
+// DebugDumping returns whether a DebugDump is in use.
+func DebugDumping() bool {
+	return currentDebugDump() != nil
+}
+
+// LastDebugDump returns the file of the last request dumped by the DebugDump
+// in use, or an empty string.
+func LastDebugDump() string {
+	if d := currentDebugDump(); d != nil {
+		return d.Last()
+	}
+	return ""
+}
+
+func currentDebugDump() *DebugDump {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return activeDebugDump
+}
+
+// Wrap returns a transport that dumps the requests sent through rt.
+func (d *DebugDump) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return &debugDumpTransport{dump: d, rt: rt}
+}
+
+// create creates the file of the next request of a session.
+func (d *DebugDump) create(sessionID string) (*os.File, error) {
+	dir := filepath.Join(d.dir, sessionID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seq, ok := d.seq[sessionID]
+	if !ok {
+		// Continue the numbering of a session dumped by a previous run.
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		seq = len(entries)
+	}
+	seq++
+	d.seq[sessionID] = seq
+
+	path := filepath.Join(dir, fmt.Sprintf("%04d.http", seq))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	d.last = path
+	return f, nil
+}
+
+type debugDumpTransport struct {
+	dump *DebugDump
+	rt   http.RoundTripper
+}
+
+func (t *debugDumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sessionID := debugSessionFromContext(req.Context())
+	if sessionID == "" {
+		return t.rt.RoundTrip(req)
+	}
+
+	f, err := t.dump.create(sessionID)
+	if err != nil {
+		slog.Warn("Failed to create LLM debug dump", "session_id", sessionID, "error", err)
+		return t.rt.RoundTrip(req)
+	}
+	if r, ok := req.Context().Value(debugRequestKey{}).(*debugRequest); ok {
+		r.set(f.Name())
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	fmt.Fprintf(f, "%s %s\n", req.Method, redactURL(req.URL.String()))
+	writeHeaders(f, req.Header)
+	fmt.Fprintf(f, "\n%s\n\n", indentJSON(body))
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		fmt.Fprintf(f, "### Error\n\n%v\n", err)
+		f.Close()
+		return nil, err
+	}
+
+	fmt.Fprintf(f, "### Response\n\n%s %s\n", resp.Proto, resp.Status)
+	writeHeaders(f, resp.Header)
+	fmt.Fprintln(f)
+	resp.Body = &teeBody{ReadCloser: resp.Body, file: f}
+	return resp, nil
+}
+
+// writeHeaders writes headers, sorted and with their credentials redacted.
+func writeHeaders(w io.Writer, headers http.Header) {
+	headers = headers.Clone()
+	for _, header := range sensitiveHeaders {
+		redactHeader(headers, header)
+	}
+	for _, key := range slices.Sorted(maps.Keys(headers)) {
+		for _, value := range headers[key] {
+			fmt.Fprintf(w, "%s: %s\n", key, value)
+		}
+	}
+}
+
+// indentJSON indents JSON bodies so the dumps can be diffed. Other bodies
+// are left as is.
+func indentJSON(body []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		return body
+	}
+	return buf.Bytes()
+}
+
+// teeBody copies a response body to the dump as it's read, so streamed
+// responses are dumped as they arrive.
+type teeBody struct {
+	io.ReadCloser
+
+	file *os.File
+	once sync.Once
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = b.file.Write(p[:n])
+	}
+	return n, err
+}
+
+func (b *teeBody) Close() error {
+	b.once.Do(func() { b.file.Close() })
+	return b.ReadCloser.Close()
+}
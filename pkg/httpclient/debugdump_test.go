@@ -0,0 +1,128 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugDump(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+		_, _ = w.Write([]byte("data: " + string(body) + "\n\n"))
+	}))
+	defer server.Close()
+
+	dump := NewDebugDump(t.TempDir())
+	rt := dump.Wrap(http.DefaultTransport)
+
+	// Requests without a session aren't dumped.
+	assert.Equal(t, `data: {"model":"m"}`+"\n\n", post(t, rt, server.URL+"/v1/messages", `{"model":"m"}`))
+	assert.Empty(t, dump.Last())
+
+	for range 2 {
+		req, err := http.NewRequestWithContext(WithDebugSession(t.Context(), "sess-1"), http.MethodPost, server.URL+"/v1/messages?key=secret-key", strings.NewReader(`{"model":"m"}`))
+		require.NoError(t, err)
+		req.Header.Set("X-Api-Key", "secret-token")
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, `data: {"model":"m"}`+"\n\n", string(body), "the request is sent unchanged")
+	}
+
+	assert.Equal(t, filepath.Join(dump.Dir(), "sess-1", "0002.http"), dump.Last())
+
+	buf, err := os.ReadFile(dump.Last())
+	require.NoError(t, err)
+	content := string(buf)
+	assert.NotContains(t, content, "secret-key")
+	assert.NotContains(t, content, "secret-token")
+	assert.NotContains(t, content, "secret-cookie")
+	assert.Contains(t, content, "X-Api-Key: "+redacted)
+	assert.Contains(t, content, "{\n  \"model\": \"m\"\n}")
+	assert.Contains(t, content, "### Response")
+	assert.Contains(t, content, `data: {"model":"m"}`)
+
+	// A new run continues the numbering of the session.
+	assert.Equal(t, filepath.Join(dump.Dir(), "sess-1", "0003.http"), mustCreate(t, NewDebugDump(dump.Dir()), "sess-1"))
+}
+
+func TestUseDebugDump(t *testing.T) {
+	dump := NewDebugDump(t.TempDir())
+
+	restore := UseDebugDump(dump)
+	assert.True(t, DebugDumping())
+	assert.Empty(t, LastDebugDump())
+
+	mustCreate(t, dump, "sess-1")
+	mustCreate(t, dump, "sess-2")
+	assert.Equal(t, dump.Last(), LastDebugDump())
+
+	restore()
+	assert.False(t, DebugDumping())
+	assert.Empty(t, LastDebugDump())
+	assert.Equal(t, http.DefaultTransport, Transport())
+}
+
+func TestWithDebugRequest(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("data: {}\n\n"))
+	}))
+	defer server.Close()
+
+	dump := NewDebugDump(t.TempDir())
+	rt := dump.Wrap(http.DefaultTransport)
+
+	turnCtx, requestDump := WithDebugRequest(WithDebugSession(t.Context(), "sess-1"))
+	assert.Empty(t, requestDump(), "nothing is dumped yet")
+
+	roundTrip(t, rt, turnCtx, server.URL)
+	turnDump := requestDump()
+	assert.Equal(t, filepath.Join(dump.Dir(), "sess-1", "0001.http"), turnDump)
+
+	// Another request of the same session isn't mistaken for the turn's.
+	roundTrip(t, rt, WithDebugSession(t.Context(), "sess-1"), server.URL)
+	assert.Equal(t, filepath.Join(dump.Dir(), "sess-1", "0002.http"), dump.Last())
+	assert.Equal(t, turnDump, requestDump())
+
+	// Requests that aren't dumped record nothing.
+	_, notDumped := WithDebugRequest(t.Context())
+	assert.Empty(t, notDumped())
+}
+
+func roundTrip(t *testing.T, rt http.RoundTripper, ctx context.Context, url string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	_, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+}
+
+func mustCreate(t *testing.T, dump *DebugDump, sessionID string) string {
+	t.Helper()
+
+	f, err := dump.create(sessionID)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	return f.Name()
+}
@@ -0,0 +1,216 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"gopkg.in/dnaeon/go-vcr.v4/pkg/cassette"
+	"gopkg.in/dnaeon/go-vcr.v4/pkg/recorder"
+)
+
+// RecordFixturesEnv is the environment variable that makes the tests record
+// new fixtures from the live provider APIs instead of replaying them.
+const RecordFixturesEnv = "DOCKER_AGENT_RECORD_FIXTURES"
+
+// RecordMode is whether a Recorder records or replays the traffic.
+type RecordMode int
+
+const (
+	// Replay serves the responses from the fixture. Requests that aren't in
+	// the fixture fail.
+	Replay RecordMode = iota
+	// Record sends the requests to the providers and saves the requests and
+	// their responses to the fixture, replacing it.
+	Record
+)
+
+// RecordModeFromEnv returns Record if RecordFixturesEnv is set, Replay otherwise.
+func RecordModeFromEnv() RecordMode {
+	if os.Getenv(RecordFixturesEnv) != "" {
+		return Record
+	}
+	return Replay
+}
+
+// redacted replaces the secrets in the fixtures.
+const redacted = "REDACTED"
+
+// sensitiveHeaders are the headers that carry credentials.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"Api-Key",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+	"X-Amz-Security-Token",
+}
+
+// matchedHeaders are the headers that select the API features, and must match
+// the fixture.
+var matchedHeaders = []string{"Anthropic-Beta", "Anthropic-Version"}
+
+// sensitiveQueryParams are the query parameters that carry credentials.
+var sensitiveQueryParams = []string{"key", "api_key", "access_token"}
+
+// Recorder is an http.RoundTripper that records the traffic with the
+// providers to a fixture file, or replays it. Credentials are redacted from
+// the fixtures, so they can be committed.
+type Recorder struct {
+	rec *recorder.Recorder
+}
+
+// NewRecorder creates a recorder for the fixture at path, without the .yaml
+// extension.
+func NewRecorder(path string, mode RecordMode) (*Recorder, error) {
+	vcrMode := recorder.ModeReplayOnly
+	if mode == Record {
+		vcrMode = recorder.ModeRecordOnly
+	}
+
+	rec, err := recorder.New(path,
+		recorder.WithMode(vcrMode),
+		recorder.WithRealTransport(newTransport()),
+		recorder.WithMatcher(matchRequest),
+		recorder.WithSkipRequestLatency(true),
+		recorder.WithHook(sanitize, recorder.BeforeSaveHook),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{rec: rec}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.rec.RoundTrip(req)
+}
+
+// Stop saves the fixture when recording.
+func (r *Recorder) Stop() error {
+	return r.rec.Stop()
+}
+
+var (
+	transportMu    sync.RWMutex
+	activeRecorder *Recorder
+)
+
+// UseRecorder makes the clients created afterwards by NewHTTPClient, and the
+// provider clients that use Transport, send their requests through r. It
+// returns a function that restores the default transport. It's meant for
+// tests, that must not run in parallel while a recorder is in use.
+func UseRecorder(r *Recorder) (restore func()) {
+	transportMu.Lock()
+	previous := activeRecorder
+	activeRecorder = r
+	transportMu.Unlock()
+
+	return func() {
+		transportMu.Lock()
+		activeRecorder = previous
+		transportMu.Unlock()
+	}
+}
+
+// Recording returns whether a recorder is in use.
+func Recording() bool {
+	return currentRecorder() != nil
+}
+
+// Transport returns the transport the provider clients that don't use
+// NewHTTPClient must send their requests with: the recorder in use, if any,
+// http.DefaultTransport otherwise, dumping the requests when a DebugDump is
+// in use.
+func Transport() http.RoundTripper {
+	var rt http.RoundTripper = http.DefaultTransport
+	if r := currentRecorder(); r != nil {
+		rt = r
+	}
+	if d := currentDebugDump(); d != nil {
+		rt = d.Wrap(rt)
+	}
+	return rt
+}
+
+func currentRecorder() *Recorder {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return activeRecorder
+}
+
+// matchRequest matches requests on their method, URL, body and the headers
+// that select API features. Other headers are ignored since they carry
+// credentials and client versions.
+func matchRequest(r *http.Request, i cassette.Request) bool {
+	if r.Method != i.Method || redactURL(r.URL.String()) != i.URL {
+		return false
+	}
+	for _, header := range matchedHeaders {
+		if r.Header.Get(header) != i.Headers.Get(header) {
+			return false
+		}
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return i.Body == ""
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("Failed to read request body for matching", "error", err)
+		return false
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return string(body) == i.Body
+}
+
+// sanitize redacts the credentials from an interaction before it's saved.
+func sanitize(i *cassette.Interaction) error {
+	for _, header := range sensitiveHeaders {
+		redactHeader(i.Request.Headers, header)
+		redactHeader(i.Response.Headers, header)
+	}
+	i.Request.URL = redactURL(i.Request.URL)
+	for _, param := range sensitiveQueryParams {
+		if i.Request.Form.Has(param) {
+			i.Request.Form.Set(param, redacted)
+		}
+	}
+	i.Request.RequestURI = ""
+	i.Request.RemoteAddr = ""
+	return nil
+}
+
+func redactHeader(headers http.Header, key string) {
+	if headers.Get(key) != "" {
+		headers.Set(key, redacted)
+	}
+}
+
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := u.Query()
+	changed := false
+	for _, param := range sensitiveQueryParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
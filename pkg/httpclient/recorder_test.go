@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+		_, _ = w.Write([]byte("echo: " + string(body)))
+	}))
+
+	fixture := filepath.Join(t.TempDir(), "fixture")
+
+	rec, err := NewRecorder(fixture, Record)
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hello", post(t, rec, server.URL+"/v1/messages?key=secret-key", "hello"))
+	require.NoError(t, rec.Stop())
+	server.Close()
+
+	buf, err := os.ReadFile(fixture + ".yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(buf), "secret-key")
+	assert.NotContains(t, string(buf), "secret-token")
+	assert.NotContains(t, string(buf), "secret-cookie")
+	assert.Contains(t, string(buf), redacted)
+
+	rec, err = NewRecorder(fixture, Replay)
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hello", post(t, rec, server.URL+"/v1/messages?key=other-key", "hello"))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL+"/v1/messages", strings.NewReader("goodbye"))
+	require.NoError(t, err)
+	_, err = rec.RoundTrip(req)
+	require.Error(t, err)
+}
+
+func TestRecorder_MissingFixture(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing"), Replay)
+	require.Error(t, err)
+}
+
+func TestUseRecorder(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture")
+	require.NoError(t, os.WriteFile(fixture+".yaml", []byte(`version: 2
+interactions:
+  - id: 0
+    request:
+      body: hello
+      url: https://api.example.com/v1/messages
+      method: POST
+    response:
+      body: replayed
+      headers: {}
+      status: 200 OK
+      code: 200
+`), 0o600))
+
+	rec, err := NewRecorder(fixture, Replay)
+	require.NoError(t, err)
+
+	restore := UseRecorder(rec)
+	assert.True(t, Recording())
+	assert.Same(t, rec, Transport())
+	assert.Equal(t, "replayed", post(t, NewHTTPClient().Transport, "https://api.example.com/v1/messages", "hello"))
+
+	restore()
+	assert.False(t, Recording())
+	assert.Equal(t, http.DefaultTransport, Transport())
+}
+
+func post(t *testing.T, rt http.RoundTripper, url, body string) string {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	buf, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(buf)
+}
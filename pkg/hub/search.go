@@ -0,0 +1,83 @@
+// Package hub queries Docker Hub.
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/httpclient"
+)
+
+const defaultBaseURL = "https://hub.docker.com"
+
+// Repository is a Docker Hub repository returned by a search.
+type Repository struct {
+	Name        string `json:"repo_name"`
+	Description string `json:"short_description"`
+	Stars       int    `json:"star_count"`
+	Pulls       int64  `json:"pull_count"`
+}
+
+// Client is a Docker Hub client.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type Opt func(*Client)
+
+// WithBaseURL sets the URL of the Docker Hub API, mostly for tests.
+func WithBaseURL(baseURL string) Opt {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+func NewClient(opts ...Opt) *Client {
+	c := &Client{
+		baseURL:    defaultBaseURL,
+		httpClient: httpclient.NewHTTPClient(),
+	}
+	c.httpClient.Timeout = 30 * time.Second
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Search searches the repositories whose name or description match query.
+func (c *Client) Search(ctx context.Context, query string, limit int) ([]Repository, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("page_size", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v2/search/repositories/?"+params.Encode(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("searching Docker Hub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("searching Docker Hub: unexpected status %s", resp.Status)
+	}
+
+	var result struct {
+		Results []Repository `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding Docker Hub search results: %w", err)
+	}
+	return result.Results, nil
+}
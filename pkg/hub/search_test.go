@@ -0,0 +1,40 @@
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/search/repositories/", r.URL.Path)
+		assert.Equal(t, "pirate", r.URL.Query().Get("query"))
+		assert.Equal(t, "10", r.URL.Query().Get("page_size"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count": 1, "results": [{"repo_name": "agentcatalog/pirate", "short_description": "Talks like a pirate", "star_count": 3, "pull_count": 1200}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	repos, err := NewClient(WithBaseURL(server.URL)).Search(t.Context(), "pirate", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []Repository{{Name: "agentcatalog/pirate", Description: "Talks like a pirate", Stars: 3, Pulls: 1200}}, repos)
+}
+
+func TestSearch_Error(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewClient(WithBaseURL(server.URL)).Search(t.Context(), "pirate", 10)
+	require.ErrorContains(t, err, "429")
+}
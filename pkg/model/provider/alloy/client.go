@@ -0,0 +1,319 @@
+// Package alloy provides a provider that combines several models and picks
+// one of them for each request according to a selection strategy.
+//
+// A model becomes an alloy provider when it has no provider, a comma-separated
+// list of models and an alloy block:
+//
+//	models:
+//	  mix:
+//	    model: openai/gpt-5-mini,anthropic/claude-sonnet-4-5
+//	    alloy:
+//	      strategy: cheapest
+//
+// Whatever the strategy, a request that fails before producing any output is
+// retried on the next candidate model. The ensemble strategy is the
+// exception: it asks all the models and keeps the answer most of them agree
+// on, or the one a judge model picks.
+package alloy
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Provider defines the minimal interface needed for model providers.
+type Provider interface {
+	ID() string
+	CreateChatCompletionStream(
+		ctx context.Context,
+		messages []chat.Message,
+		availableTools []tools.Tool,
+	) (chat.MessageStream, error)
+	BaseConfig() base.Config
+}
+
+// ProviderFactory creates a provider from a model reference.
+// The models parameter provides access to all configured models for resolving references.
+type ProviderFactory func(ctx context.Context, modelSpec string, models map[string]latest.ModelConfig, env environment.Provider, opts ...options.Opt) (Provider, error)
+
+// ModelInfo describes the pricing and capabilities of a model.
+type ModelInfo struct {
+	// Cost is the price of one million input plus one million output tokens.
+	Cost float64
+	// Vision is true if the model accepts image input.
+	Vision bool
+}
+
+// ModelInfoFunc looks up the pricing and capabilities of a model by its
+// "provider/model" ID. It returns nil when the model is unknown.
+type ModelInfoFunc func(ctx context.Context, id string) *ModelInfo
+
+// member is one of the models of an alloy.
+type member struct {
+	provider Provider
+	info     *ModelInfo
+}
+
+// Client implements the Provider interface for alloy models.
+type Client struct {
+	base.Config
+	strategy string
+	members  []member
+	// judge picks the answer of ensembles without a majority.
+	judge Provider
+}
+
+// NewClient creates a new alloy client. The cfg parameter must list the
+// models of the alloy, comma-separated, in its model field.
+func NewClient(ctx context.Context, cfg *latest.ModelConfig, models map[string]latest.ModelConfig, env environment.Provider, providerFactory ProviderFactory, modelInfo ModelInfoFunc, opts ...options.Opt) (*Client, error) {
+	slog.Debug("Creating alloy model", "models", cfg.Model)
+
+	strategy := latest.AlloyStrategyRandom
+	if cfg.Alloy != nil {
+		strategy = cmp.Or(cfg.Alloy.Strategy, latest.AlloyStrategyRandom)
+	}
+
+	client := &Client{
+		Config: base.Config{
+			ModelConfig: *cfg,
+			Models:      models,
+			Env:         env,
+		},
+		strategy: strategy,
+	}
+
+	memberOpts := filterOutMaxTokens(opts)
+	for spec := range strings.SplitSeq(cfg.Model, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		p, err := providerFactory(ctx, spec, models, env, memberOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating provider for alloy model %q: %w", spec, err)
+		}
+
+		var info *ModelInfo
+		if modelInfo != nil {
+			info = modelInfo(ctx, p.ID())
+		}
+		client.members = append(client.members, member{provider: p, info: info})
+	}
+
+	if len(client.members) == 0 {
+		return nil, errors.New("alloy has no models")
+	}
+
+	if cfg.Alloy != nil && cfg.Alloy.Judge != "" {
+		judge, err := providerFactory(ctx, cfg.Alloy.Judge, models, env)
+		if err != nil {
+			return nil, fmt.Errorf("creating provider for alloy judge %q: %w", cfg.Alloy.Judge, err)
+		}
+		client.judge = judge
+	}
+
+	return client, nil
+}
+
+// filterOutMaxTokens removes WithMaxTokens options from the slice.
+// Member models may have different token limits than the alloy.
+func filterOutMaxTokens(opts []options.Opt) []options.Opt {
+	var filtered []options.Opt
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		var probe options.ModelOptions
+		opt(&probe)
+		if probe.MaxTokens() != 0 {
+			continue
+		}
+		filtered = append(filtered, opt)
+	}
+	return filtered
+}
+
+// ID returns the name of the alloy model, or the list of its models for
+// inline alloys.
+func (c *Client) ID() string {
+	return cmp.Or(c.ModelConfig.Name, c.ModelConfig.Model)
+}
+
+// CreateChatCompletionStream orders the models according to the strategy and
+// streams from the first one that starts answering. A model that fails
+// before producing any output is skipped in favor of the next one. Ensembles
+// ask all the models instead.
+func (c *Client) CreateChatCompletionStream(
+	ctx context.Context,
+	messages []chat.Message,
+	availableTools []tools.Tool,
+) (chat.MessageStream, error) {
+	if c.strategy == latest.AlloyStrategyEnsemble {
+		return c.createEnsembleStream(ctx, messages, availableTools)
+	}
+
+	var errs []error
+	for _, m := range c.candidates(messages) {
+		p := m.provider
+		slog.Debug("Alloy selected model", "alloy", c.ID(), "strategy", c.strategy, "selected_model", p.ID())
+
+		start := time.Now()
+		stream, err := p.CreateChatCompletionStream(ctx, messages, tools.ForProvider(availableTools, p.BaseConfig().ModelConfig.Provider))
+		if err != nil {
+			slog.Warn("Alloy model failed, trying the next one", "alloy", c.ID(), "model", p.ID(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.ID(), err))
+			continue
+		}
+
+		first, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			stream.Close()
+			slog.Warn("Alloy model failed, trying the next one", "alloy", c.ID(), "model", p.ID(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.ID(), err))
+			continue
+		}
+		latencies.record(p.ID(), time.Since(start))
+
+		return &peekedStream{MessageStream: stream, first: first, firstErr: err}, nil
+	}
+
+	// Context cancellation isn't a model failure: surface it as-is
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("all alloy models failed: %w", errors.Join(errs...))
+}
+
+// candidates returns the members in the order they should be tried.
+func (c *Client) candidates(messages []chat.Message) []member {
+	members := slices.Clone(c.members)
+
+	switch c.strategy {
+	case latest.AlloyStrategyCheapest:
+		// Unknown prices sort last
+		slices.SortStableFunc(members, func(a, b member) int {
+			return cmp.Compare(costOf(a), costOf(b))
+		})
+	case latest.AlloyStrategyLatency:
+		// Models without measurements sort first so they get measured
+		slices.SortStableFunc(members, func(a, b member) int {
+			return cmp.Compare(latencies.get(a.provider.ID()), latencies.get(b.provider.ID()))
+		})
+	case latest.AlloyStrategyCapability:
+		wantVision := hasImages(messages)
+		// Stable partition: preferred models first, in configuration order
+		slices.SortStableFunc(members, func(a, b member) int {
+			return cmp.Compare(capabilityRank(a, wantVision), capabilityRank(b, wantVision))
+		})
+		if wantVision {
+			// Text-only models can't handle the request at all
+			if i := slices.IndexFunc(members, func(m member) bool { return !isVision(m) }); i > 0 {
+				members = members[:i]
+			}
+		}
+	default:
+		rand.Shuffle(len(members), func(i, j int) {
+			members[i], members[j] = members[j], members[i]
+		})
+	}
+
+	return members
+}
+
+func costOf(m member) float64 {
+	if m.info == nil {
+		return math.Inf(1)
+	}
+	return m.info.Cost
+}
+
+func isVision(m member) bool {
+	return m.info != nil && m.info.Vision
+}
+
+// capabilityRank ranks vision-capable models first for requests with images,
+// and last otherwise.
+func capabilityRank(m member, wantVision bool) int {
+	if isVision(m) == wantVision {
+		return 0
+	}
+	return 1
+}
+
+// hasImages reports whether the conversation contains images.
+func hasImages(messages []chat.Message) bool {
+	for i := range messages {
+		for _, part := range messages[i].MultiContent {
+			switch {
+			case part.Type == chat.MessagePartTypeImageURL:
+				return true
+			case part.Type == chat.MessagePartTypeFile && part.File != nil && strings.HasPrefix(part.File.MimeType, "image/"):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// peekedStream replays the first response that was read to make sure the
+// model was answering before handing the stream over.
+type peekedStream struct {
+	chat.MessageStream
+	first    chat.MessageStreamResponse
+	firstErr error
+	consumed bool
+}
+
+func (s *peekedStream) Recv() (chat.MessageStreamResponse, error) {
+	if !s.consumed {
+		s.consumed = true
+		return s.first, s.firstErr
+	}
+	return s.MessageStream.Recv()
+}
+
+// latencyTracker keeps a moving average of the time to first response per
+// model. It's shared by all alloys since providers are re-created for every
+// request.
+type latencyTracker struct {
+	mu      sync.Mutex
+	average map[string]time.Duration
+}
+
+var latencies = &latencyTracker{average: map[string]time.Duration{}}
+
+func (t *latencyTracker) record(id string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if avg, ok := t.average[id]; ok {
+		// Exponential moving average that favors recent measurements
+		d = (avg*7 + d*3) / 10
+	}
+	t.average[id] = d
+}
+
+func (t *latencyTracker) get(id string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.average[id]
+}
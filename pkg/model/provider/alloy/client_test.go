@@ -0,0 +1,235 @@
+package alloy
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// mockProvider is a provider that answers with its content, or its own ID,
+// or fails.
+type mockProvider struct {
+	id        string
+	content   string
+	createErr error
+	recvErr   error
+	calls     int
+}
+
+func (m *mockProvider) ID() string {
+	return m.id
+}
+
+func (m *mockProvider) CreateChatCompletionStream(context.Context, []chat.Message, []tools.Tool) (chat.MessageStream, error) {
+	m.calls++
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	return &mockStream{content: cmp.Or(m.content, m.id), err: m.recvErr}, nil
+}
+
+func (m *mockProvider) BaseConfig() base.Config {
+	return base.Config{}
+}
+
+type mockStream struct {
+	content string
+	err     error
+	done    bool
+}
+
+func (s *mockStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.err != nil {
+		return chat.MessageStreamResponse{}, s.err
+	}
+	if s.done {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.done = true
+	return chat.MessageStreamResponse{Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.content}}}}, nil
+}
+
+func (s *mockStream) Close() {}
+
+// newTestClient creates an alloy over the given mock providers.
+func newTestClient(t *testing.T, strategy string, providers []*mockProvider, infos map[string]*ModelInfo) *Client {
+	t.Helper()
+
+	byID := map[string]*mockProvider{}
+	var specs []string
+	for _, p := range providers {
+		byID[p.id] = p
+		specs = append(specs, p.id)
+	}
+
+	factory := func(_ context.Context, modelSpec string, _ map[string]latest.ModelConfig, _ environment.Provider, _ ...options.Opt) (Provider, error) {
+		return byID[modelSpec], nil
+	}
+	modelInfo := func(_ context.Context, id string) *ModelInfo {
+		return infos[id]
+	}
+
+	cfg := &latest.ModelConfig{
+		Model: strings.Join(specs, ","),
+		Alloy: &latest.AlloyConfig{Strategy: strategy},
+	}
+	client, err := NewClient(t.Context(), cfg, nil, nil, factory, modelInfo)
+	require.NoError(t, err)
+	return client
+}
+
+// answer returns the model that answered the request.
+func answer(t *testing.T, client *Client, messages []chat.Message) string {
+	t.Helper()
+
+	stream, err := client.CreateChatCompletionStream(t.Context(), messages, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	return resp.Choices[0].Delta.Content
+}
+
+var textMessages = []chat.Message{{Role: chat.MessageRoleUser, Content: "hello"}}
+
+var imageMessages = []chat.Message{{
+	Role: chat.MessageRoleUser,
+	MultiContent: []chat.MessagePart{
+		{Type: chat.MessagePartTypeText, Text: "what's this?"},
+		{Type: chat.MessagePartTypeImageURL, ImageURL: &chat.MessageImageURL{URL: "data:image/png;base64,AAAA"}},
+	},
+}}
+
+func TestCheapest(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyCheapest,
+		[]*mockProvider{{id: "a/unknown"}, {id: "a/expensive"}, {id: "a/cheap"}},
+		map[string]*ModelInfo{
+			"a/expensive": {Cost: 20},
+			"a/cheap":     {Cost: 1},
+		})
+
+	assert.Equal(t, "a/cheap", answer(t, client, textMessages))
+}
+
+func TestCheapest_EscalatesOnFailure(t *testing.T) {
+	t.Parallel()
+
+	cheap := &mockProvider{id: "b/cheap", createErr: errors.New("overloaded")}
+	flaky := &mockProvider{id: "b/flaky", recvErr: errors.New("stream reset")}
+	expensive := &mockProvider{id: "b/expensive"}
+	client := newTestClient(t, latest.AlloyStrategyCheapest,
+		[]*mockProvider{expensive, flaky, cheap},
+		map[string]*ModelInfo{
+			"b/cheap":     {Cost: 1},
+			"b/flaky":     {Cost: 2},
+			"b/expensive": {Cost: 20},
+		})
+
+	assert.Equal(t, "b/expensive", answer(t, client, textMessages))
+	assert.Equal(t, 1, cheap.calls)
+	assert.Equal(t, 1, flaky.calls)
+}
+
+func TestAllModelsFail(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyRandom,
+		[]*mockProvider{{id: "c/one", createErr: errors.New("boom")}, {id: "c/two", recvErr: errors.New("bang")}},
+		nil)
+
+	_, err := client.CreateChatCompletionStream(t.Context(), textMessages, nil)
+	require.ErrorContains(t, err, "all alloy models failed")
+	require.ErrorContains(t, err, "c/one: boom")
+	require.ErrorContains(t, err, "c/two: bang")
+}
+
+func TestCapability(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyCapability,
+		[]*mockProvider{{id: "d/vision"}, {id: "d/text"}},
+		map[string]*ModelInfo{
+			"d/vision": {Vision: true},
+			"d/text":   {},
+		})
+
+	assert.Equal(t, "d/text", answer(t, client, textMessages))
+	assert.Equal(t, "d/vision", answer(t, client, imageMessages))
+}
+
+func TestCapability_NoTextFallbackForImages(t *testing.T) {
+	t.Parallel()
+
+	vision := &mockProvider{id: "e/vision", createErr: errors.New("down")}
+	text := &mockProvider{id: "e/text"}
+	client := newTestClient(t, latest.AlloyStrategyCapability,
+		[]*mockProvider{text, vision},
+		map[string]*ModelInfo{"e/vision": {Vision: true}})
+
+	_, err := client.CreateChatCompletionStream(t.Context(), imageMessages, nil)
+	require.Error(t, err)
+	assert.Zero(t, text.calls)
+}
+
+func TestLatency(t *testing.T) {
+	t.Parallel()
+
+	latencies.record("f/slow", 2*time.Second)
+	latencies.record("f/fast", 100*time.Millisecond)
+
+	client := newTestClient(t, latest.AlloyStrategyLatency,
+		[]*mockProvider{{id: "f/slow"}, {id: "f/fast"}},
+		nil)
+	assert.Equal(t, "f/fast", answer(t, client, textMessages))
+
+	// Unmeasured models are tried first
+	client = newTestClient(t, latest.AlloyStrategyLatency,
+		[]*mockProvider{{id: "f/slow"}, {id: "f/fast"}, {id: "f/new"}},
+		nil)
+	assert.Equal(t, "f/new", answer(t, client, textMessages))
+}
+
+func TestID(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyRandom, []*mockProvider{{id: "g/one"}, {id: "g/two"}}, nil)
+	assert.Equal(t, "g/one,g/two", client.ID())
+
+	client.ModelConfig.Name = "mix"
+	assert.Equal(t, "mix", client.ID())
+}
+
+func TestHasImages(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, hasImages(textMessages))
+	assert.True(t, hasImages(imageMessages))
+	assert.True(t, hasImages([]chat.Message{{
+		Role:         chat.MessageRoleUser,
+		MultiContent: []chat.MessagePart{{Type: chat.MessagePartTypeFile, File: &chat.MessageFile{Path: "a.png", MimeType: "image/png"}}},
+	}}))
+	assert.False(t, hasImages([]chat.Message{{
+		Role:         chat.MessageRoleUser,
+		MultiContent: []chat.MessagePart{{Type: chat.MessagePartTypeFile, File: &chat.MessageFile{Path: "a.pdf", MimeType: "application/pdf"}}},
+	}}))
+}
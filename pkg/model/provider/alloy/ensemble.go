@@ -0,0 +1,304 @@
+package alloy
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Ways an ensemble chooses its answer.
+const (
+	EnsembleMethodMajority = "majority"
+	EnsembleMethodJudge    = "judge"
+)
+
+// ensembleNoteChars is the length the differing answers are shortened to in
+// the disagreement notes.
+const ensembleNoteChars = 200
+
+const judgePrompt = `Several AI models answered the same conversation. Pick the best answer: the most correct, complete and helpful one, for the last message of the user.
+
+Reply with the number of the best answer only.`
+
+// sample is the answer of one model of an ensemble: what it streamed, to
+// replay it if it's chosen, and the answer it adds up to, to compare it.
+type sample struct {
+	responses []chat.MessageStreamResponse
+	variant   chat.EnsembleVariant
+}
+
+// createEnsembleStream asks all the models of the alloy for the same turn and
+// replays the answer most of them gave. When no answer has a majority and
+// the alloy has a judge, the judge picks one. The answers of all the models
+// are attached to the first response of the stream.
+func (c *Client) createEnsembleStream(ctx context.Context, messages []chat.Message, availableTools []tools.Tool) (chat.MessageStream, error) {
+	samples := make([]sample, len(c.members))
+	var wg sync.WaitGroup
+	for i, m := range c.members {
+		wg.Go(func() {
+			samples[i] = collectSample(ctx, m.provider, messages, tools.ForProvider(availableTools, m.provider.BaseConfig().ModelConfig.Provider))
+		})
+	}
+	wg.Wait()
+
+	// Context cancellation isn't a model failure: surface it as-is
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var answered []int
+	var errs []error
+	for i, s := range samples {
+		if s.variant.Error != "" {
+			slog.Warn("Ensemble model failed", "alloy", c.ID(), "model", s.variant.Model, "error", s.variant.Error)
+			errs = append(errs, fmt.Errorf("%s: %s", s.variant.Model, s.variant.Error))
+			continue
+		}
+		answered = append(answered, i)
+	}
+	if len(answered) == 0 {
+		return nil, fmt.Errorf("all alloy models failed: %w", errors.Join(errs...))
+	}
+
+	groups := groupAnswers(samples, answered)
+	chosen, method := groups[0][0], EnsembleMethodMajority
+	if len(groups[0])*2 <= len(answered) && c.judge != nil {
+		if judged, err := c.judgeAnswers(ctx, messages, samples, groups); err != nil {
+			slog.Warn("Ensemble judge failed, keeping the most common answer", "alloy", c.ID(), "judge", c.judge.ID(), "error", err)
+		} else {
+			chosen, method = judged, EnsembleMethodJudge
+		}
+	}
+
+	ensemble := &chat.Ensemble{
+		Method:       method,
+		Chosen:       chosen,
+		Disagreement: disagreement(samples, groups, chosen, len(answered)),
+	}
+	for _, g := range groups {
+		if slices.Contains(g, chosen) {
+			ensemble.Agreeing = len(g)
+		}
+	}
+	for _, s := range samples {
+		ensemble.Variants = append(ensemble.Variants, s.variant)
+	}
+	slog.Debug("Ensemble chose an answer", "alloy", c.ID(), "method", method, "model", samples[chosen].variant.Model, "agreeing", ensemble.Agreeing, "answered", len(answered))
+
+	first := chat.MessageStreamResponse{
+		Model:   samples[chosen].variant.Model,
+		Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Ensemble: ensemble}}},
+	}
+	return &replayStream{responses: append([]chat.MessageStreamResponse{first}, samples[chosen].responses...)}, nil
+}
+
+// collectSample reads the whole answer of a model.
+func collectSample(ctx context.Context, p Provider, messages []chat.Message, availableTools []tools.Tool) sample {
+	s := sample{variant: chat.EnsembleVariant{Model: p.ID()}}
+
+	stream, err := p.CreateChatCompletionStream(ctx, messages, availableTools)
+	if err != nil {
+		s.variant.Error = err.Error()
+		return s
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	callIndex := map[string]int{}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			s.variant.Error = err.Error()
+			return s
+		}
+		s.responses = append(s.responses, resp)
+		if resp.Usage != nil {
+			s.variant.Usage = resp.Usage
+		}
+		if len(resp.Choices) == 0 {
+			continue
+		}
+		delta := resp.Choices[0].Delta
+		content.WriteString(delta.Content)
+		for _, d := range delta.ToolCalls {
+			idx, ok := callIndex[d.ID]
+			if !ok {
+				idx = len(s.variant.ToolCalls)
+				callIndex[d.ID] = idx
+				s.variant.ToolCalls = append(s.variant.ToolCalls, tools.ToolCall{ID: d.ID, Type: d.Type})
+			}
+			tc := &s.variant.ToolCalls[idx]
+			if d.Function.Name != "" {
+				tc.Function.Name = d.Function.Name
+			}
+			tc.Function.Arguments += d.Function.Arguments
+		}
+	}
+	s.variant.Content = content.String()
+	return s
+}
+
+// groupAnswers groups the models that gave the same answer, the largest
+// groups first. Groups of the same size keep the order of the alloy.
+func groupAnswers(samples []sample, answered []int) [][]int {
+	var groups [][]int
+	keys := map[string]int{}
+	for _, i := range answered {
+		key := answerKey(samples[i].variant)
+		if g, ok := keys[key]; ok {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		keys[key] = len(groups)
+		groups = append(groups, []int{i})
+	}
+	slices.SortStableFunc(groups, func(a, b []int) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	return groups
+}
+
+// answerKey identifies an answer for the vote: the tool calls, when there
+// are some, with their arguments in a canonical form, or the text, without
+// differences of case and spacing.
+func answerKey(v chat.EnsembleVariant) string {
+	if len(v.ToolCalls) == 0 {
+		return strings.ToLower(strings.Join(strings.Fields(v.Content), " "))
+	}
+	calls := make([]string, 0, len(v.ToolCalls))
+	for _, tc := range v.ToolCalls {
+		args := tc.Function.Arguments
+		var parsed any
+		if json.Unmarshal([]byte(args), &parsed) == nil {
+			if canonical, err := json.Marshal(parsed); err == nil {
+				args = string(canonical)
+			}
+		}
+		calls = append(calls, tc.Function.Name+args)
+	}
+	slices.Sort(calls)
+	return "tools:" + strings.Join(calls, "\n")
+}
+
+// judgeAnswers asks the judge of the alloy to pick the best of the distinct
+// answers, and returns the index of the sample it picked.
+func (c *Client) judgeAnswers(ctx context.Context, messages []chat.Message, samples []sample, groups [][]int) (int, error) {
+	var prompt strings.Builder
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == chat.MessageRoleUser {
+			fmt.Fprintf(&prompt, "<last_user_message>\n%s\n</last_user_message>\n\n", messages[i].Content)
+			break
+		}
+	}
+	for n, g := range groups {
+		fmt.Fprintf(&prompt, "<answer number=\"%d\">\n%s\n</answer>\n\n", n+1, describeAnswer(samples[g[0]].variant, 0))
+	}
+
+	stream, err := c.judge.CreateChatCompletionStream(ctx, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: judgePrompt},
+		{Role: chat.MessageRoleUser, Content: strings.TrimSpace(prompt.String())},
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+
+	var reply strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if len(resp.Choices) > 0 {
+			reply.WriteString(resp.Choices[0].Delta.Content)
+		}
+	}
+
+	digits := strings.TrimFunc(reply.String(), func(r rune) bool { return r < '0' || r > '9' })
+	if end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+		digits = digits[:end]
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 1 || n > len(groups) {
+		return 0, fmt.Errorf("unexpected verdict %q", reply.String())
+	}
+	return groups[n-1][0], nil
+}
+
+// describeAnswer returns the text of an answer, or the tool calls it makes,
+// shortened to maxChars when it's positive.
+func describeAnswer(v chat.EnsembleVariant, maxChars int) string {
+	text := strings.TrimSpace(v.Content)
+	if len(v.ToolCalls) > 0 {
+		var calls []string
+		for _, tc := range v.ToolCalls {
+			calls = append(calls, fmt.Sprintf("%s(%s)", tc.Function.Name, tc.Function.Arguments))
+		}
+		text = "calls " + strings.Join(calls, ", ")
+	}
+	if maxChars > 0 && len(text) > maxChars {
+		n := maxChars
+		for n > 0 && !utf8.RuneStart(text[n]) {
+			n--
+		}
+		text = text[:n] + "…"
+	}
+	return text
+}
+
+// disagreement describes the answers that differ from the chosen one, or
+// returns an empty string when all the models agreed.
+func disagreement(samples []sample, groups [][]int, chosen, answered int) string {
+	if len(groups) == 1 {
+		return ""
+	}
+
+	var agreeing int
+	var others []string
+	for _, g := range groups {
+		if slices.Contains(g, chosen) {
+			agreeing = len(g)
+			continue
+		}
+		var models []string
+		for _, i := range g {
+			models = append(models, samples[i].variant.Model)
+		}
+		others = append(others, fmt.Sprintf("%s: %s", strings.Join(models, ", "), describeAnswer(samples[g[0]].variant, ensembleNoteChars)))
+	}
+	return fmt.Sprintf("%d of %d models gave this answer. The other answers were:\n- %s", agreeing, answered, strings.Join(others, "\n- "))
+}
+
+// replayStream streams responses that were already received.
+type replayStream struct {
+	responses []chat.MessageStreamResponse
+}
+
+func (s *replayStream) Recv() (chat.MessageStreamResponse, error) {
+	if len(s.responses) == 0 {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func (s *replayStream) Close() {}
@@ -0,0 +1,148 @@
+package alloy
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// vote returns the answer an ensemble streamed, and how it chose it.
+func vote(t *testing.T, client *Client) (string, *chat.Ensemble) {
+	t.Helper()
+
+	stream, err := client.CreateChatCompletionStream(t.Context(), textMessages, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content string
+	var ensemble *chat.Ensemble
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		if resp.Choices[0].Delta.Ensemble != nil {
+			ensemble = resp.Choices[0].Delta.Ensemble
+		}
+		content += resp.Choices[0].Delta.Content
+	}
+	require.NotNil(t, ensemble)
+	return content, ensemble
+}
+
+func TestEnsemble_Majority(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyEnsemble,
+		[]*mockProvider{{id: "a/one", content: "Paris"}, {id: "a/two", content: "Lyon"}, {id: "a/three", content: "  paris "}}, nil)
+
+	content, ensemble := vote(t, client)
+
+	assert.Equal(t, "Paris", content)
+	assert.Equal(t, EnsembleMethodMajority, ensemble.Method)
+	assert.Equal(t, 0, ensemble.Chosen)
+	assert.Equal(t, 2, ensemble.Agreeing)
+	assert.Len(t, ensemble.Variants, 3)
+	assert.Equal(t, "2 of 3 models gave this answer. The other answers were:\n- a/two: Lyon", ensemble.Disagreement)
+}
+
+func TestEnsemble_Unanimous(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyEnsemble,
+		[]*mockProvider{{id: "a/one", content: "42"}, {id: "a/two", content: "42"}}, nil)
+
+	content, ensemble := vote(t, client)
+
+	assert.Equal(t, "42", content)
+	assert.Equal(t, 2, ensemble.Agreeing)
+	assert.Empty(t, ensemble.Disagreement)
+}
+
+func TestEnsemble_Judge(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyEnsemble,
+		[]*mockProvider{{id: "a/one", content: "Paris"}, {id: "a/two", content: "Lyon"}}, nil)
+	judge := &mockProvider{id: "a/judge", content: "Answer 2."}
+	client.judge = judge
+
+	content, ensemble := vote(t, client)
+
+	assert.Equal(t, "Lyon", content)
+	assert.Equal(t, EnsembleMethodJudge, ensemble.Method)
+	assert.Equal(t, 1, ensemble.Chosen)
+	assert.Equal(t, 1, judge.calls)
+}
+
+func TestEnsemble_JudgeNotNeededWithMajority(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyEnsemble,
+		[]*mockProvider{{id: "a/one", content: "Paris"}, {id: "a/two", content: "Paris"}, {id: "a/three", content: "Lyon"}}, nil)
+	judge := &mockProvider{id: "a/judge", content: "2"}
+	client.judge = judge
+
+	content, ensemble := vote(t, client)
+
+	assert.Equal(t, "Paris", content)
+	assert.Equal(t, EnsembleMethodMajority, ensemble.Method)
+	assert.Zero(t, judge.calls)
+}
+
+func TestEnsemble_BadVerdictKeepsMostCommon(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyEnsemble,
+		[]*mockProvider{{id: "a/one", content: "Paris"}, {id: "a/two", content: "Lyon"}}, nil)
+	client.judge = &mockProvider{id: "a/judge", content: "I can't decide"}
+
+	content, ensemble := vote(t, client)
+
+	assert.Equal(t, "Paris", content)
+	assert.Equal(t, EnsembleMethodMajority, ensemble.Method)
+}
+
+func TestEnsemble_FailedModelsDontVote(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyEnsemble,
+		[]*mockProvider{{id: "a/one", createErr: errors.New("boom")}, {id: "a/two", content: "Lyon"}}, nil)
+
+	content, ensemble := vote(t, client)
+
+	assert.Equal(t, "Lyon", content)
+	assert.Equal(t, 1, ensemble.Chosen)
+	assert.Equal(t, "boom", ensemble.Variants[0].Error)
+	assert.Empty(t, ensemble.Disagreement)
+}
+
+func TestEnsemble_AllModelsFail(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, latest.AlloyStrategyEnsemble,
+		[]*mockProvider{{id: "a/one", createErr: errors.New("boom")}, {id: "a/two", recvErr: errors.New("bang")}}, nil)
+
+	_, err := client.CreateChatCompletionStream(t.Context(), textMessages, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	assert.Contains(t, err.Error(), "bang")
+}
+
+func TestAnswerKey_ToolCalls(t *testing.T) {
+	t.Parallel()
+
+	a := chat.EnsembleVariant{ToolCalls: []tools.ToolCall{{Function: tools.FunctionCall{Name: "read", Arguments: `{"path": "a", "n": 1}`}}}}
+	b := chat.EnsembleVariant{Content: "Let me read it", ToolCalls: []tools.ToolCall{{Function: tools.FunctionCall{Name: "read", Arguments: `{"n":1,"path":"a"}`}}}}
+
+	assert.Equal(t, answerKey(a), answerKey(b))
+	assert.NotEqual(t, answerKey(a), answerKey(chat.EnsembleVariant{Content: "read"}))
+}
@@ -1,6 +1,7 @@
 package anthropic
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -23,6 +24,8 @@ type streamAdapter struct {
 	toolCall           bool
 	toolID             string
 	getResponseTrailer func() http.Header
+	// startUsage holds the input and cache counters reported by message_start.
+	startUsage chat.Usage
 }
 
 func (c *Client) newStreamAdapter(stream *ssestream.Stream[anthropic.MessageStreamEventUnion], trackUsage bool) *streamAdapter {
@@ -121,6 +124,8 @@ func (a *streamAdapter) Recv() (chat.MessageStreamResponse, error) {
 			response.Choices[0].Delta.ReasoningContent = deltaVariant.Thinking
 		case anthropic.SignatureDelta:
 			response.Choices[0].Delta.ThinkingSignature = deltaVariant.Signature
+		case anthropic.CitationsDelta:
+			response.Choices[0].Delta.Citations = []chat.Citation{convertCitation(deltaVariant.Citation)}
 		case anthropic.InputJSONDelta:
 			inputBytes := deltaVariant.PartialJSON
 			toolCall := tools.ToolCall{
@@ -135,13 +140,21 @@ func (a *streamAdapter) Recv() (chat.MessageStreamResponse, error) {
 		default:
 			return response, fmt.Errorf("unknown delta type: %T", deltaVariant)
 		}
+	case anthropic.MessageStartEvent:
+		// Input and cache counters are reported when the message starts;
+		// message_delta only repeats them on recent API versions.
+		a.startUsage = chat.Usage{
+			InputTokens:       eventVariant.Message.Usage.InputTokens,
+			CachedInputTokens: eventVariant.Message.Usage.CacheReadInputTokens,
+			CacheWriteTokens:  eventVariant.Message.Usage.CacheCreationInputTokens,
+		}
 	case anthropic.MessageDeltaEvent:
 		if a.trackUsage {
 			response.Usage = &chat.Usage{
-				InputTokens:       eventVariant.Usage.InputTokens,
+				InputTokens:       cmp.Or(eventVariant.Usage.InputTokens, a.startUsage.InputTokens),
 				OutputTokens:      eventVariant.Usage.OutputTokens,
-				CachedInputTokens: eventVariant.Usage.CacheReadInputTokens,
-				CacheWriteTokens:  eventVariant.Usage.CacheCreationInputTokens,
+				CachedInputTokens: cmp.Or(eventVariant.Usage.CacheReadInputTokens, a.startUsage.CachedInputTokens),
+				CacheWriteTokens:  cmp.Or(eventVariant.Usage.CacheCreationInputTokens, a.startUsage.CacheWriteTokens),
 			}
 		}
 	case anthropic.MessageStopEvent:
@@ -178,3 +191,17 @@ func parseHeaderInt64(headerValue string) int64 {
 func (a *streamAdapter) Close() {
 	a.stream.Close()
 }
+
+// convertCitation converts an Anthropic citation to a chat.Citation.
+// Search result citations carry a source, web search citations a URL.
+func convertCitation(c anthropic.CitationsDeltaCitationUnion) chat.Citation {
+	return chat.Citation{
+		Type:              c.Type,
+		CitedText:         c.CitedText,
+		Source:            cmp.Or(c.Source, c.URL),
+		Title:             cmp.Or(c.Title, c.DocumentTitle),
+		SearchResultIndex: c.SearchResultIndex,
+		StartBlockIndex:   c.StartBlockIndex,
+		EndBlockIndex:     c.EndBlockIndex,
+	}
+}
@@ -1,6 +1,7 @@
 package anthropic
 
 import (
+	"cmp"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -19,6 +20,8 @@ type betaStreamAdapter struct {
 	toolCall           bool
 	toolID             string
 	getResponseTrailer func() http.Header
+	// startUsage holds the input and cache counters reported by message_start.
+	startUsage chat.Usage
 }
 
 // newBetaStreamAdapter creates a new Beta stream adapter
@@ -96,16 +99,26 @@ func (a *betaStreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 		case anthropic.BetaSignatureDelta:
 			// Signature delta is for thinking blocks - capture it so we can replay thinking in history
 			response.Choices[0].Delta.ThinkingSignature = deltaVariant.Signature
+		case anthropic.BetaCitationsDelta:
+			response.Choices[0].Delta.Citations = []chat.Citation{convertBetaCitation(deltaVariant.Citation)}
 		default:
 			return response, fmt.Errorf("unknown delta type: %T", deltaVariant)
 		}
+	case anthropic.BetaRawMessageStartEvent:
+		// Input and cache counters are reported when the message starts;
+		// message_delta only repeats them on recent API versions.
+		a.startUsage = chat.Usage{
+			InputTokens:       eventVariant.Message.Usage.InputTokens,
+			CachedInputTokens: eventVariant.Message.Usage.CacheReadInputTokens,
+			CacheWriteTokens:  eventVariant.Message.Usage.CacheCreationInputTokens,
+		}
 	case anthropic.BetaRawMessageDeltaEvent:
 		if a.trackUsage {
 			response.Usage = &chat.Usage{
-				InputTokens:       eventVariant.Usage.InputTokens,
+				InputTokens:       cmp.Or(eventVariant.Usage.InputTokens, a.startUsage.InputTokens),
 				OutputTokens:      eventVariant.Usage.OutputTokens,
-				CachedInputTokens: eventVariant.Usage.CacheReadInputTokens,
-				CacheWriteTokens:  eventVariant.Usage.CacheCreationInputTokens,
+				CachedInputTokens: cmp.Or(eventVariant.Usage.CacheReadInputTokens, a.startUsage.CachedInputTokens),
+				CacheWriteTokens:  cmp.Or(eventVariant.Usage.CacheCreationInputTokens, a.startUsage.CacheWriteTokens),
 			}
 		}
 	case anthropic.BetaRawMessageStopEvent:
@@ -123,3 +136,16 @@ func (a *betaStreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 func (a *betaStreamAdapter) Close() {
 	a.stream.Close()
 }
+
+// convertBetaCitation converts a Beta API citation to a chat.Citation.
+func convertBetaCitation(c anthropic.BetaCitationsDeltaCitationUnion) chat.Citation {
+	return chat.Citation{
+		Type:              c.Type,
+		CitedText:         c.CitedText,
+		Source:            cmp.Or(c.Source, c.URL),
+		Title:             cmp.Or(c.Title, c.DocumentTitle),
+		SearchResultIndex: c.SearchResultIndex,
+		StartBlockIndex:   c.StartBlockIndex,
+		EndBlockIndex:     c.EndBlockIndex,
+	}
+}
@@ -137,7 +137,9 @@ func (c *Client) convertBetaMessages(ctx context.Context, messages []chat.Messag
 // convertBetaToolResultBlock converts a tool message to a Beta API tool_result block,
 // including any image content from MultiContent.
 func convertBetaToolResultBlock(msg *chat.Message) anthropic.BetaContentBlockParamUnion {
-	if !hasImageMultiContent(msg.MultiContent) {
+	hasSearchResults := hasSearchResultMultiContent(msg.MultiContent)
+
+	if !hasImageMultiContent(msg.MultiContent) && !hasSearchResults {
 		return anthropic.BetaContentBlockParamUnion{
 			OfToolResult: &anthropic.BetaToolResultBlockParam{
 				ToolUseID: msg.ToolCallID,
@@ -152,11 +154,27 @@ func convertBetaToolResultBlock(msg *chat.Message) anthropic.BetaContentBlockPar
 	for _, part := range msg.MultiContent {
 		switch part.Type {
 		case chat.MessagePartTypeText:
+			if hasSearchResults {
+				continue
+			}
 			if txt := strings.TrimSpace(part.Text); txt != "" {
 				content = append(content, anthropic.BetaToolResultBlockParamContentUnion{
 					OfText: &anthropic.BetaTextBlockParam{Text: txt},
 				})
 			}
+		case chat.MessagePartTypeSearchResult:
+			if part.SearchResult == nil {
+				continue
+			}
+			block := anthropic.BetaSearchResultBlockParam{
+				Source:    part.SearchResult.Source,
+				Title:     part.SearchResult.Title,
+				Citations: anthropic.BetaCitationsConfigParam{Enabled: anthropic.Bool(true)},
+			}
+			for _, txt := range part.SearchResult.Content {
+				block.Content = append(block.Content, anthropic.BetaTextBlockParam{Text: txt})
+			}
+			content = append(content, anthropic.BetaToolResultBlockParamContentUnion{OfSearchResult: &block})
 		case chat.MessagePartTypeImageURL:
 			if part.ImageURL == nil {
 				continue
@@ -188,3 +188,30 @@ func TestConvertBetaMessages_NonConsecutiveToolMessages(t *testing.T) {
 	err = validateAnthropicSequencingBeta(betaMessages)
 	require.NoError(t, err, "Messages with non-consecutive tool calls should still validate")
 }
+
+func TestConvertBetaToolResultBlock_SearchResults(t *testing.T) {
+	msg := chat.Message{
+		Role:       chat.MessageRoleTool,
+		ToolCallID: "tool-1",
+		Content:    "plain text fallback",
+		MultiContent: []chat.MessagePart{
+			{Type: chat.MessagePartTypeText, Text: "plain text fallback"},
+			{Type: chat.MessagePartTypeSearchResult, SearchResult: &chat.MessageSearchResult{
+				Source:  "docs/france.md",
+				Content: []string{"Paris is the capital of France.", "Lyon is the third-largest city."},
+			}},
+		},
+	}
+
+	block := convertBetaToolResultBlock(&msg)
+	require.NotNil(t, block.OfToolResult)
+
+	content := block.OfToolResult.Content
+	require.Len(t, content, 1)
+	require.NotNil(t, content[0].OfSearchResult)
+	sr := content[0].OfSearchResult
+	assert.Equal(t, "docs/france.md", sr.Source)
+	require.Len(t, sr.Content, 2)
+	assert.Equal(t, "Lyon is the third-largest city.", sr.Content[1].Text)
+	assert.True(t, sr.Citations.Enabled.Value)
+}
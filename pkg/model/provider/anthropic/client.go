@@ -489,23 +489,42 @@ func (c *Client) convertMessages(ctx context.Context, messages []chat.Message) (
 
 // convertToolResultBlock converts a tool message to an Anthropic tool_result block.
 // If the message contains image content in MultiContent, the images are included
-// as image blocks within the tool_result.
+// as image blocks within the tool_result. Search results are sent as citable
+// search_result blocks and replace the plain-text output.
 func convertToolResultBlock(msg *chat.Message) anthropic.ContentBlockParamUnion {
-	// If there are no images in MultiContent, use the simple text-only format.
-	if !hasImageMultiContent(msg.MultiContent) {
+	hasSearchResults := hasSearchResultMultiContent(msg.MultiContent)
+
+	// If there are no images or search results in MultiContent, use the simple text-only format.
+	if !hasImageMultiContent(msg.MultiContent) && !hasSearchResults {
 		return anthropic.NewToolResultBlock(msg.ToolCallID, strings.TrimSpace(msg.Content), msg.IsError)
 	}
 
-	// Build content blocks with text + images for the tool result.
+	// Build content blocks with text + images + search results for the tool result.
 	var content []anthropic.ToolResultBlockParamContentUnion
 	for _, part := range msg.MultiContent {
 		switch part.Type {
 		case chat.MessagePartTypeText:
+			if hasSearchResults {
+				continue
+			}
 			if txt := strings.TrimSpace(part.Text); txt != "" {
 				content = append(content, anthropic.ToolResultBlockParamContentUnion{
 					OfText: &anthropic.TextBlockParam{Text: txt},
 				})
 			}
+		case chat.MessagePartTypeSearchResult:
+			if part.SearchResult == nil {
+				continue
+			}
+			block := anthropic.SearchResultBlockParam{
+				Source:    part.SearchResult.Source,
+				Title:     part.SearchResult.Title,
+				Citations: anthropic.CitationsConfigParam{Enabled: anthropic.Bool(true)},
+			}
+			for _, txt := range part.SearchResult.Content {
+				block.Content = append(block.Content, anthropic.TextBlockParam{Text: txt})
+			}
+			content = append(content, anthropic.ToolResultBlockParamContentUnion{OfSearchResult: &block})
 		case chat.MessagePartTypeImageURL:
 			if part.ImageURL == nil {
 				continue
@@ -547,6 +566,16 @@ func hasImageMultiContent(parts []chat.MessagePart) bool {
 	return false
 }
 
+// hasSearchResultMultiContent returns true if the multi-content parts contain any search result.
+func hasSearchResultMultiContent(parts []chat.MessagePart) bool {
+	for _, part := range parts {
+		if part.Type == chat.MessagePartTypeSearchResult && part.SearchResult != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // extractMediaType extracts the media type from a data URL prefix (e.g. "data:image/png;base64").
 func extractMediaType(prefix string) string {
 	switch {
@@ -915,6 +944,33 @@ func clampMaxTokens(limit, used, configured int64) int64 {
 	return configured
 }
 
+// CanResumeResponse reports whether the model continues a trailing
+// assistant message, which Anthropic only allows without extended thinking.
+func (c *Client) CanResumeResponse() bool {
+	return c.ModelConfig.ThinkingBudget == nil || c.ModelConfig.ThinkingBudget.IsDisabled()
+}
+
+// CountTokens counts the input tokens of a request with Anthropic's Count
+// Tokens API.
+func (c *Client) CountTokens(ctx context.Context, messages []chat.Message, requestTools []tools.Tool) (int64, error) {
+	client, err := c.clientFn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	allTools, err := convertTools(requestTools)
+	if err != nil {
+		return 0, err
+	}
+	converted, err := c.convertMessages(ctx, messages)
+	if err != nil {
+		return 0, err
+	}
+	if len(converted) == 0 {
+		return 0, errors.New("no messages to count")
+	}
+	return countAnthropicTokens(ctx, client, anthropic.Model(c.ModelConfig.Model), converted, extractSystemBlocks(messages), allTools)
+}
+
 // countAnthropicTokens calls Anthropic's Count Tokens API for the provided payload
 // and returns the number of input tokens.
 func countAnthropicTokens(
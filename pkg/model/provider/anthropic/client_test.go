@@ -355,6 +355,35 @@ func TestConvertMessages_GroupToolResults_AfterAssistantToolUse(t *testing.T) {
 	assert.Contains(t, ids, "tool-2")
 }
 
+func TestConvertToolResultBlock_SearchResults(t *testing.T) {
+	msg := chat.Message{
+		Role:       chat.MessageRoleTool,
+		ToolCallID: "tool-1",
+		Content:    "plain text fallback",
+		MultiContent: []chat.MessagePart{
+			{Type: chat.MessagePartTypeText, Text: "plain text fallback"},
+			{Type: chat.MessagePartTypeSearchResult, SearchResult: &chat.MessageSearchResult{
+				Source:  "https://example.com/france",
+				Title:   "France",
+				Content: []string{"Paris is the capital of France."},
+			}},
+		},
+	}
+
+	block := convertToolResultBlock(&msg)
+	require.NotNil(t, block.OfToolResult)
+
+	content := block.OfToolResult.Content
+	require.Len(t, content, 1, "text fallback should be replaced by the search result")
+	require.NotNil(t, content[0].OfSearchResult)
+	sr := content[0].OfSearchResult
+	assert.Equal(t, "https://example.com/france", sr.Source)
+	assert.Equal(t, "France", sr.Title)
+	require.Len(t, sr.Content, 1)
+	assert.Equal(t, "Paris is the capital of France.", sr.Content[0].Text)
+	assert.True(t, sr.Citations.Enabled.Value)
+}
+
 // TestCountAnthropicTokens_Success tests successful token counting for standard API
 func TestCountAnthropicTokens_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -600,3 +629,24 @@ func TestExtractSystemBlocksCacheControl(t *testing.T) {
 	assert.Equal(t, "ephemeral", string(blocks[3].CacheControl.Type))
 	assert.Empty(t, string(blocks[3].CacheControl.TTL))
 }
+
+func TestCanResumeResponse(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name   string
+		budget *latest.ThinkingBudget
+		want   bool
+	}{
+		{name: "no thinking", want: true},
+		{name: "thinking disabled", budget: &latest.ThinkingBudget{Effort: "none"}, want: true},
+		{name: "extended thinking", budget: &latest.ThinkingBudget{Tokens: 1024}, want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{Config: base.Config{ModelConfig: latest.ModelConfig{Provider: "anthropic", ThinkingBudget: tt.budget}}}
+			assert.Equal(t, tt.want, client.CanResumeResponse())
+		})
+	}
+}
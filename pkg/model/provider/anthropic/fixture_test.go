@@ -0,0 +1,72 @@
+package anthropic
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/httpclient"
+)
+
+// TestCreateBetaStream_Fixture replays a Beta Messages API stream with
+// interleaved thinking. Set DOCKER_AGENT_RECORD_FIXTURES and ANTHROPIC_API_KEY
+// to record the fixture again.
+func TestCreateBetaStream_Fixture(t *testing.T) {
+	rec, err := httpclient.NewRecorder("testdata/beta_stream", httpclient.RecordModeFromEnv())
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, rec.Stop()) })
+	t.Cleanup(httpclient.UseRecorder(rec))
+
+	cfg := &latest.ModelConfig{
+		Provider:       "anthropic",
+		Model:          "claude-haiku-4-5",
+		BaseURL:        "https://api.anthropic.com/",
+		ThinkingBudget: &latest.ThinkingBudget{Tokens: 1024},
+		ProviderOpts:   map[string]any{"interleaved_thinking": true},
+	}
+	env := environment.NewMapEnvProvider(map[string]string{
+		"ANTHROPIC_API_KEY": cmp.Or(os.Getenv("ANTHROPIC_API_KEY"), "test-key"),
+	})
+	client, err := NewClient(t.Context(), cfg, env)
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(t.Context(), []chat.Message{
+		{Role: chat.MessageRoleUser, Content: "Say hello in one word."},
+	}, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content, reasoning strings.Builder
+	var usage *chat.Usage
+	var finishReason chat.FinishReason
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		for _, choice := range resp.Choices {
+			content.WriteString(choice.Delta.Content)
+			reasoning.WriteString(choice.Delta.ReasoningContent)
+			finishReason = cmp.Or(choice.FinishReason, finishReason)
+		}
+		usage = cmp.Or(resp.Usage, usage)
+	}
+
+	assert.Equal(t, "Hello!", content.String())
+	assert.NotEmpty(t, reasoning.String())
+	assert.Equal(t, chat.FinishReasonStop, finishReason)
+	require.NotNil(t, usage)
+	assert.Equal(t, int64(18), usage.InputTokens)
+	assert.Equal(t, int64(25), usage.OutputTokens)
+}
@@ -43,3 +43,26 @@ type BatchEmbeddingResult struct {
 	TotalTokens int64
 	Cost        float64
 }
+
+// Aspect ratios of generated images.
+const (
+	AspectRatioSquare    = "1:1"
+	AspectRatioLandscape = "16:9"
+	AspectRatioPortrait  = "9:16"
+)
+
+// ImageGenerationOptions contains the options of an image generation request.
+type ImageGenerationOptions struct {
+	// AspectRatio is one of AspectRatioSquare, AspectRatioLandscape or
+	// AspectRatioPortrait. Empty selects the model's default.
+	AspectRatio string
+}
+
+// ImageGenerationResult contains a generated image.
+type ImageGenerationResult struct {
+	Data     []byte
+	MimeType string
+	// RevisedPrompt is the prompt the model used, when it rewrote the
+	// original one.
+	RevisedPrompt string
+}
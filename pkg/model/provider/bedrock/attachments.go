@@ -0,0 +1,112 @@
+package bedrock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+
+	"github.com/docker/docker-agent/pkg/chat"
+)
+
+// Converse API limits for inline attachments.
+// See https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_ImageBlock.html
+// and https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_DocumentBlock.html
+const (
+	maxImageBytes    = 3_750_000
+	maxDocumentBytes = 4_500_000
+)
+
+var imageFormats = map[string]types.ImageFormat{
+	"image/jpeg": types.ImageFormatJpeg,
+	"image/png":  types.ImageFormatPng,
+	"image/gif":  types.ImageFormatGif,
+	"image/webp": types.ImageFormatWebp,
+}
+
+var documentFormats = map[string]types.DocumentFormat{
+	"application/pdf":    types.DocumentFormatPdf,
+	"text/csv":           types.DocumentFormatCsv,
+	"text/html":          types.DocumentFormatHtml,
+	"text/markdown":      types.DocumentFormatMd,
+	"text/plain":         types.DocumentFormatTxt,
+	"application/msword": types.DocumentFormatDoc,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": types.DocumentFormatDocx,
+	"application/vnd.ms-excel": types.DocumentFormatXls,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": types.DocumentFormatXlsx,
+}
+
+// documentNameInvalidChars matches the characters Bedrock rejects in document
+// names. Only alphanumerics, single spaces, hyphens, parentheses and square
+// brackets are allowed.
+var documentNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9\s\-()\[\]]+`)
+
+// convertFile reads a file attachment and maps it to a Converse image or
+// document block. Bedrock has no file storage API, so files are always sent
+// inline and provider file IDs can't be used.
+func convertFile(file *chat.MessageFile) (types.ContentBlock, error) {
+	if file.Path == "" {
+		return nil, fmt.Errorf("bedrock does not support provider file references (file_id=%q): attach a local file instead", file.FileID)
+	}
+
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = chat.DetectMimeType(file.Path)
+	}
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+
+	imageFormat, isImage := imageFormats[mimeType]
+	documentFormat, isDocument := documentFormats[mimeType]
+	if !isImage && !isDocument {
+		return nil, fmt.Errorf("unsupported attachment %s for Bedrock: type %s is not an image (jpeg, png, gif, webp) or a document (pdf, csv, doc, docx, xls, xlsx, html, txt, md)", filepath.Base(file.Path), mimeType)
+	}
+
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading attachment: %w", err)
+	}
+	limit := int64(maxDocumentBytes)
+	if isImage {
+		limit = maxImageBytes
+	}
+	if info.Size() > limit {
+		return nil, fmt.Errorf("attachment %s is too large for Bedrock: %d bytes, the limit is %d bytes", filepath.Base(file.Path), info.Size(), limit)
+	}
+
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading attachment: %w", err)
+	}
+
+	if isImage {
+		return &types.ContentBlockMemberImage{
+			Value: types.ImageBlock{
+				Format: imageFormat,
+				Source: &types.ImageSourceMemberBytes{Value: data},
+			},
+		}, nil
+	}
+
+	return &types.ContentBlockMemberDocument{
+		Value: types.DocumentBlock{
+			Format: documentFormat,
+			Name:   aws.String(documentName(file.Path)),
+			Source: &types.DocumentSourceMemberBytes{Value: data},
+		},
+	}, nil
+}
+
+// documentName derives a Bedrock-compatible document name from a file path.
+func documentName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = documentNameInvalidChars.ReplaceAllString(name, "-")
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "document"
+	}
+	return name
+}
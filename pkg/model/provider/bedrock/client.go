@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -14,10 +15,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/auth/bearer"
 
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/httpclient"
 	"github.com/docker/docker-agent/pkg/model/provider/base"
 	"github.com/docker/docker-agent/pkg/model/provider/options"
 	"github.com/docker/docker-agent/pkg/modelsdev"
@@ -28,9 +31,20 @@ import (
 type Client struct {
 	base.Config
 	bedrockClient    *bedrockruntime.Client
-	cachingSupported bool // Cached at init time for efficiency
+	failoverClients  []regionalClient // Tried in order when the primary region is unavailable
+	cachingSupported bool             // Cached at init time for efficiency
 }
 
+// regionalClient is a Bedrock Runtime client bound to a single AWS region.
+type regionalClient struct {
+	region string
+	client *bedrockruntime.Client
+}
+
+// crossRegionPrefixes are the geography prefixes of Bedrock's system-defined
+// cross-region inference profiles.
+var crossRegionPrefixes = []string{"global", "us", "us-gov", "eu", "apac", "jp", "au", "ca"}
+
 // bearerTokenTransport adds Authorization header with bearer token to requests
 type bearerTokenTransport struct {
 	token string
@@ -92,17 +106,27 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, env environment.Pro
 		})
 	}
 
+	// Send the requests through the recorder of the tests that use one, or
+	// the debug dump of --debug-llm
+	if httpclient.Recording() || httpclient.DebugDumping() {
+		clientOpts = append(clientOpts, func(o *bedrockruntime.Options) {
+			o.HTTPClient = &http.Client{Transport: httpclient.Transport()}
+		})
+	}
+
 	// If bearer token is set, use it instead of SigV4
 	if bearerToken != "" {
 		slog.Debug("Bedrock using bearer token authentication")
 		clientOpts = append(clientOpts, func(o *bedrockruntime.Options) {
 			// Use anonymous credentials to skip SigV4 signing
 			o.Credentials = aws.AnonymousCredentials{}
+			// The SDK then picks its bearer auth scheme, that needs a token provider
+			o.BearerAuthTokenProvider = bearer.StaticTokenProvider{Token: bearer.Token{Value: bearerToken}}
 			// Add bearer token via custom HTTP client
 			o.HTTPClient = &http.Client{
 				Transport: &bearerTokenTransport{
 					token: bearerToken,
-					base:  http.DefaultTransport,
+					base:  httpclient.Transport(),
 				},
 			}
 		})
@@ -110,6 +134,21 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, env environment.Pro
 
 	bedrockClient := bedrockruntime.NewFromConfig(awsCfg, clientOpts...)
 
+	// Build one client per failover region, sharing auth and endpoint settings
+	regions, err := failoverRegions(cfg.ProviderOpts)
+	if err != nil {
+		return nil, err
+	}
+	var failoverClients []regionalClient
+	for _, region := range regions {
+		regionCfg := awsCfg.Copy()
+		regionCfg.Region = region
+		failoverClients = append(failoverClients, regionalClient{
+			region: region,
+			client: bedrockruntime.NewFromConfig(regionCfg, clientOpts...),
+		})
+	}
+
 	// Detect prompt caching capability at init time for efficiency.
 	// Uses models.dev cache pricing as proxy for capability detection.
 	cachingSupported := detectCachingSupport(ctx, cfg.Model)
@@ -117,6 +156,7 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, env environment.Pro
 	slog.Debug("Bedrock client created successfully",
 		"model", cfg.Model,
 		"region", awsCfg.Region,
+		"failover_regions", regions,
 		"caching_supported", cachingSupported)
 
 	return &Client{
@@ -126,6 +166,7 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, env environment.Pro
 			Env:          env,
 		},
 		bedrockClient:    bedrockClient,
+		failoverClients:  failoverClients,
 		cachingSupported: cachingSupported,
 	}, nil
 }
@@ -213,28 +254,114 @@ func (c *Client) CreateChatCompletionStream(
 	}
 
 	// Build Converse input
-	input := c.buildConverseStreamInput(messages, requestTools)
-
-	// Call ConverseStream
-	output, err := c.bedrockClient.ConverseStream(ctx, input)
+	input, err := c.buildConverseStreamInput(messages, requestTools)
 	if err != nil {
-		slog.Error("Bedrock ConverseStream failed", "error", err)
-		return nil, fmt.Errorf("bedrock converse stream failed: %w", err)
+		return nil, err
+	}
+
+	// Call ConverseStream, failing over to the next region when the current
+	// one is throttled or unavailable
+	clients := append([]regionalClient{{client: c.bedrockClient}}, c.failoverClients...)
+	for i, rc := range clients {
+		var output *bedrockruntime.ConverseStreamOutput
+		output, err = rc.client.ConverseStream(ctx, input)
+		if err == nil {
+			trackUsage := c.ModelConfig.TrackUsage == nil || *c.ModelConfig.TrackUsage
+			return newStreamAdapter(output.GetStream(), c.ModelConfig.Model, trackUsage), nil
+		}
+
+		if i == len(clients)-1 || ctx.Err() != nil || !isRegionalFailure(err) {
+			break
+		}
+		slog.Warn("Bedrock region unavailable, failing over",
+			"model", c.ModelConfig.Model,
+			"next_region", clients[i+1].region,
+			"error", err)
 	}
 
-	trackUsage := c.ModelConfig.TrackUsage == nil || *c.ModelConfig.TrackUsage
-	return newStreamAdapter(output.GetStream(), c.ModelConfig.Model, trackUsage), nil
+	slog.Error("Bedrock ConverseStream failed", "error", err)
+	return nil, fmt.Errorf("bedrock converse stream failed: %w", err)
+}
+
+// isRegionalFailure reports whether err is a transient, region-level failure
+// worth retrying in another region.
+func isRegionalFailure(err error) bool {
+	var (
+		throttling  *types.ThrottlingException
+		unavailable *types.ServiceUnavailableException
+		internal    *types.InternalServerException
+		notReady    *types.ModelNotReadyException
+		timeout     *types.ModelTimeoutException
+	)
+	return errors.As(err, &throttling) ||
+		errors.As(err, &unavailable) ||
+		errors.As(err, &internal) ||
+		errors.As(err, &notReady) ||
+		errors.As(err, &timeout)
 }
 
-func (c *Client) buildConverseStreamInput(messages []chat.Message, requestTools []tools.Tool) *bedrockruntime.ConverseStreamInput {
+// failoverRegions reads the failover_regions provider option.
+func failoverRegions(opts map[string]any) ([]string, error) {
+	switch v := opts["failover_regions"].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []any:
+		regions := make([]string, 0, len(v))
+		for _, item := range v {
+			region, ok := item.(string)
+			if !ok || region == "" {
+				return nil, fmt.Errorf("provider_opts.failover_regions must be a list of region names, got %v", item)
+			}
+			regions = append(regions, region)
+		}
+		return regions, nil
+	default:
+		return nil, fmt.Errorf("provider_opts.failover_regions must be a list of region names, got %T", v)
+	}
+}
+
+// modelID returns the model identifier sent to Bedrock. An explicit
+// inference_profile (ID or ARN) wins; otherwise cross_region prefixes the
+// model with a geography to use a system-defined cross-region profile.
+func (c *Client) modelID() string {
+	if profile := getProviderOpt[string](c.ModelConfig.ProviderOpts, "inference_profile"); profile != "" {
+		return profile
+	}
+
+	model := c.ModelConfig.Model
+	geo := getProviderOpt[string](c.ModelConfig.ProviderOpts, "cross_region")
+	if geo == "" || BaseModelID(model) != model {
+		return model
+	}
+	return geo + "." + model
+}
+
+// BaseModelID strips a cross-region inference profile prefix (e.g. "global."
+// or "eu.") from a Bedrock model ID.
+func BaseModelID(model string) string {
+	for _, prefix := range crossRegionPrefixes {
+		if rest, ok := strings.CutPrefix(model, prefix+"."); ok {
+			return rest
+		}
+	}
+	return model
+}
+
+func (c *Client) buildConverseStreamInput(messages []chat.Message, requestTools []tools.Tool) (*bedrockruntime.ConverseStreamInput, error) {
 	input := &bedrockruntime.ConverseStreamInput{
-		ModelId: aws.String(c.ModelConfig.Model),
+		ModelId: aws.String(c.modelID()),
 	}
 
 	enableCaching := c.promptCachingEnabled()
 
 	// Convert and set messages (excluding system)
-	input.Messages, input.System = convertMessages(messages, enableCaching)
+	var err error
+	input.Messages, input.System, err = convertMessages(messages, enableCaching)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set inference configuration
 	input.InferenceConfig = c.buildInferenceConfig()
@@ -249,7 +376,7 @@ func (c *Client) buildConverseStreamInput(messages []chat.Message, requestTools
 		input.AdditionalModelRequestFields = additionalFields
 	}
 
-	return input
+	return input, nil
 }
 
 func (c *Client) buildInferenceConfig() *types.InferenceConfiguration {
@@ -275,15 +402,35 @@ func (c *Client) buildInferenceConfig() *types.InferenceConfiguration {
 	return cfg
 }
 
+// thinkingEffortTokens maps thinking_budget effort levels to Claude token budgets.
+var thinkingEffortTokens = map[string]int{
+	"minimal": 1024,
+	"low":     4096,
+	"medium":  8192,
+	"high":    16384,
+}
+
+// thinkingTokens returns the configured thinking budget in tokens, resolving
+// effort levels (low, medium, ...) to their token equivalent.
+func (c *Client) thinkingTokens() int {
+	budget := c.ModelConfig.ThinkingBudget
+	if budget == nil {
+		return 0
+	}
+	if budget.Effort != "" {
+		return thinkingEffortTokens[strings.ToLower(budget.Effort)]
+	}
+	return budget.Tokens
+}
+
 // isThinkingEnabled mirrors the validation in buildAdditionalModelRequestFields
 // to determine if thinking params will affect inference config (temp/topP constraints).
 func (c *Client) isThinkingEnabled() bool {
-	if c.ModelConfig.ThinkingBudget == nil || c.ModelConfig.ThinkingBudget.Tokens <= 0 {
+	tokens := c.thinkingTokens()
+	if tokens <= 0 {
 		return false
 	}
 
-	tokens := c.ModelConfig.ThinkingBudget.Tokens
-
 	// Check minimum (Claude requires at least 1024 tokens for thinking)
 	if tokens < 1024 {
 		return false
@@ -310,12 +457,11 @@ func (c *Client) promptCachingEnabled() bool {
 
 // buildAdditionalModelRequestFields configures Claude's extended thinking (reasoning) mode.
 func (c *Client) buildAdditionalModelRequestFields() document.Interface {
-	if c.ModelConfig.ThinkingBudget == nil || c.ModelConfig.ThinkingBudget.Tokens <= 0 {
+	tokens := c.thinkingTokens()
+	if tokens <= 0 {
 		return nil
 	}
 
-	tokens := c.ModelConfig.ThinkingBudget.Tokens
-
 	// Validate minimum (Claude requires at least 1024 tokens for thinking)
 	if tokens < 1024 {
 		slog.Warn("Bedrock thinking_budget below minimum (1024), ignoring",
@@ -2,8 +2,12 @@ package bedrock
 
 import (
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
@@ -25,7 +29,8 @@ func TestConvertMessages_UserText(t *testing.T) {
 		Content: "Hello, world!",
 	}}
 
-	bedrockMsgs, system := convertMessages(msgs, false)
+	bedrockMsgs, system, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 
 	require.Len(t, bedrockMsgs, 1)
 	assert.Empty(t, system)
@@ -45,7 +50,8 @@ func TestConvertMessages_SystemExtraction(t *testing.T) {
 		{Role: chat.MessageRoleUser, Content: "Hi"},
 	}
 
-	bedrockMsgs, system := convertMessages(msgs, false)
+	bedrockMsgs, system, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 
 	require.Len(t, bedrockMsgs, 1) // Only user message
 	require.Len(t, system, 1)      // System extracted
@@ -70,7 +76,8 @@ func TestConvertMessages_AssistantWithToolCalls(t *testing.T) {
 		}},
 	}}
 
-	bedrockMsgs, _ := convertMessages(msgs, false)
+	bedrockMsgs, _, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 
 	require.Len(t, bedrockMsgs, 1)
 	require.Len(t, bedrockMsgs[0].Content, 1)
@@ -91,7 +98,8 @@ func TestConvertMessages_ToolResult(t *testing.T) {
 		Content:    "Weather is sunny",
 	}}
 
-	bedrockMsgs, _ := convertMessages(msgs, false)
+	bedrockMsgs, _, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 
 	require.Len(t, bedrockMsgs, 1)
 	assert.Equal(t, types.ConversationRoleUser, bedrockMsgs[0].Role)
@@ -110,7 +118,8 @@ func TestConvertMessages_EmptyContent(t *testing.T) {
 		{Role: chat.MessageRoleUser, Content: "   "},
 	}
 
-	bedrockMsgs, _ := convertMessages(msgs, false)
+	bedrockMsgs, _, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 	assert.Empty(t, bedrockMsgs)
 }
 
@@ -175,7 +184,8 @@ func TestConvertMessages_MultiContent(t *testing.T) {
 		},
 	}}
 
-	bedrockMsgs, _ := convertMessages(msgs, false)
+	bedrockMsgs, _, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 
 	require.Len(t, bedrockMsgs, 1)
 	require.Len(t, bedrockMsgs[0].Content, 2)
@@ -199,7 +209,8 @@ func TestConvertMessages_ConsecutiveToolResults(t *testing.T) {
 		{Role: chat.MessageRoleUser, Content: "Continue"},
 	}
 
-	bedrockMsgs, _ := convertMessages(msgs, false)
+	bedrockMsgs, _, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 
 	// Expect: user, assistant, user (grouped tool results), user
 	require.Len(t, bedrockMsgs, 4)
@@ -1130,7 +1141,8 @@ func TestConvertMessages_WithCaching(t *testing.T) {
 		{Role: chat.MessageRoleUser, Content: "How are you?"},
 	}
 
-	bedrockMsgs, system := convertMessages(msgs, true)
+	bedrockMsgs, system, err := convertMessages(msgs, true)
+	require.NoError(t, err)
 
 	// System should have text block + cache point
 	require.Len(t, system, 2)
@@ -1161,7 +1173,8 @@ func TestConvertMessages_WithoutCaching(t *testing.T) {
 		{Role: chat.MessageRoleUser, Content: "Hello"},
 	}
 
-	bedrockMsgs, system := convertMessages(msgs, false)
+	bedrockMsgs, system, err := convertMessages(msgs, false)
+	require.NoError(t, err)
 
 	// System should only have text block, no cache point
 	require.Len(t, system, 1)
@@ -1252,7 +1265,8 @@ func TestConvertMessages_EmptyWithCaching(t *testing.T) {
 	t.Parallel()
 
 	// Empty message list should not panic with caching enabled
-	bedrockMsgs, system := convertMessages([]chat.Message{}, true)
+	bedrockMsgs, system, err := convertMessages([]chat.Message{}, true)
+	require.NoError(t, err)
 
 	assert.Empty(t, bedrockMsgs)
 	assert.Empty(t, system)
@@ -1265,7 +1279,8 @@ func TestConvertMessages_SingleMessageWithCaching(t *testing.T) {
 		{Role: chat.MessageRoleUser, Content: "Hello"},
 	}
 
-	bedrockMsgs, _ := convertMessages(msgs, true)
+	bedrockMsgs, _, err := convertMessages(msgs, true)
+	require.NoError(t, err)
 
 	require.Len(t, bedrockMsgs, 1)
 	// Single message should get a cache point appended
@@ -1285,7 +1300,8 @@ func TestConvertMessages_MultiContentWithCaching(t *testing.T) {
 		},
 	}}
 
-	bedrockMsgs, _ := convertMessages(msgs, true)
+	bedrockMsgs, _, err := convertMessages(msgs, true)
+	require.NoError(t, err)
 
 	require.Len(t, bedrockMsgs, 1)
 	// 2 text blocks + cache point = 3 content blocks
@@ -1308,7 +1324,8 @@ func TestConvertMessages_ToolResultWithCaching(t *testing.T) {
 		{Role: chat.MessageRoleTool, ToolCallID: "tool-1", Content: "Result"},
 	}
 
-	bedrockMsgs, _ := convertMessages(msgs, true)
+	bedrockMsgs, _, err := convertMessages(msgs, true)
+	require.NoError(t, err)
 
 	// Expect: user, assistant, user (tool result)
 	require.Len(t, bedrockMsgs, 3)
@@ -1325,3 +1342,191 @@ func TestConvertMessages_ToolResultWithCaching(t *testing.T) {
 	_, isCachePoint = secondLastContent.(*types.ContentBlockMemberCachePoint)
 	assert.True(t, isCachePoint, "assistant tool call message should have cache point")
 }
+
+func TestModelID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts map[string]any
+		want string
+	}{
+		{name: "plain model", want: "anthropic.claude-sonnet-4-5-20250929-v1:0"},
+		{name: "cross region", opts: map[string]any{"cross_region": "eu"}, want: "eu.anthropic.claude-sonnet-4-5-20250929-v1:0"},
+		{
+			name: "inference profile wins",
+			opts: map[string]any{"cross_region": "eu", "inference_profile": "arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/abc"},
+			want: "arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/abc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{Config: base.Config{ModelConfig: latest.ModelConfig{
+				Provider:     "amazon-bedrock",
+				Model:        "anthropic.claude-sonnet-4-5-20250929-v1:0",
+				ProviderOpts: tt.opts,
+			}}}
+			assert.Equal(t, tt.want, client.modelID())
+		})
+	}
+}
+
+func TestModelID_AlreadyPrefixed(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{Config: base.Config{ModelConfig: latest.ModelConfig{
+		Provider:     "amazon-bedrock",
+		Model:        "global.anthropic.claude-sonnet-4-5-20250929-v1:0",
+		ProviderOpts: map[string]any{"cross_region": "us"},
+	}}}
+	assert.Equal(t, "global.anthropic.claude-sonnet-4-5-20250929-v1:0", client.modelID())
+}
+
+func TestBaseModelID(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "anthropic.claude-3-haiku", BaseModelID("anthropic.claude-3-haiku"))
+	assert.Equal(t, "anthropic.claude-3-haiku", BaseModelID("us.anthropic.claude-3-haiku"))
+	assert.Equal(t, "anthropic.claude-3-haiku", BaseModelID("apac.anthropic.claude-3-haiku"))
+	assert.Equal(t, "amazon.nova-pro-v1:0", BaseModelID("global.amazon.nova-pro-v1:0"))
+}
+
+func TestFailoverRegions(t *testing.T) {
+	t.Parallel()
+
+	regions, err := failoverRegions(nil)
+	require.NoError(t, err)
+	assert.Empty(t, regions)
+
+	regions, err = failoverRegions(map[string]any{"failover_regions": []any{"us-west-2", "eu-west-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"us-west-2", "eu-west-1"}, regions)
+
+	_, err = failoverRegions(map[string]any{"failover_regions": "us-west-2"})
+	require.ErrorContains(t, err, "must be a list of region names")
+
+	_, err = failoverRegions(map[string]any{"failover_regions": []any{42}})
+	require.ErrorContains(t, err, "must be a list of region names")
+}
+
+func TestNewClient_FailoverRegions(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.ModelConfig{
+		Provider: "amazon-bedrock",
+		Model:    "anthropic.claude-3-sonnet-20240229-v1:0",
+		ProviderOpts: map[string]any{
+			"region":           "us-east-1",
+			"failover_regions": []any{"us-west-2"},
+		},
+	}
+
+	client, err := NewClient(t.Context(), cfg, environment.NewNoEnvProvider())
+	require.NoError(t, err)
+	require.Len(t, client.failoverClients, 1)
+	assert.Equal(t, "us-west-2", client.failoverClients[0].region)
+	assert.Equal(t, "us-west-2", client.failoverClients[0].client.Options().Region)
+}
+
+func TestIsRegionalFailure(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isRegionalFailure(&types.ThrottlingException{}))
+	assert.True(t, isRegionalFailure(fmt.Errorf("wrapped: %w", &types.ServiceUnavailableException{})))
+	assert.True(t, isRegionalFailure(&types.ModelNotReadyException{}))
+	assert.False(t, isRegionalFailure(&types.ValidationException{}))
+	assert.False(t, isRegionalFailure(errors.New("boom")))
+}
+
+func TestThinkingTokens_Effort(t *testing.T) {
+	t.Parallel()
+
+	maxTokens := int64(64000)
+	client := &Client{Config: base.Config{ModelConfig: latest.ModelConfig{
+		Provider:       "amazon-bedrock",
+		Model:          "us.anthropic.claude-sonnet-4-5-20250929-v1:0",
+		MaxTokens:      &maxTokens,
+		ThinkingBudget: &latest.ThinkingBudget{Effort: "high"},
+	}}}
+
+	assert.Equal(t, 16384, client.thinkingTokens())
+	assert.True(t, client.isThinkingEnabled())
+	require.NotNil(t, client.buildAdditionalModelRequestFields())
+
+	client.ModelConfig.ThinkingBudget = &latest.ThinkingBudget{Effort: "none"}
+	assert.Equal(t, 0, client.thinkingTokens())
+	assert.Nil(t, client.buildAdditionalModelRequestFields())
+}
+
+// File attachment tests
+
+func TestConvertMessages_FileAttachments(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pdfPath := filepath.Join(dir, "Q3 report (final).pdf")
+	require.NoError(t, os.WriteFile(pdfPath, []byte("%PDF-1.4 fake"), 0o644))
+	pngPath := filepath.Join(dir, "screenshot.png")
+	require.NoError(t, os.WriteFile(pngPath, []byte{0x89, 0x50, 0x4E, 0x47}, 0o644))
+
+	msgs := []chat.Message{{
+		Role: chat.MessageRoleUser,
+		MultiContent: []chat.MessagePart{
+			{Type: chat.MessagePartTypeText, Text: "Summarize these"},
+			{Type: chat.MessagePartTypeFile, File: &chat.MessageFile{Path: pdfPath, MimeType: "application/pdf"}},
+			{Type: chat.MessagePartTypeFile, File: &chat.MessageFile{Path: pngPath, MimeType: "image/png"}},
+		},
+	}}
+
+	bedrockMsgs, _, err := convertMessages(msgs, false)
+	require.NoError(t, err)
+	require.Len(t, bedrockMsgs, 1)
+	require.Len(t, bedrockMsgs[0].Content, 3)
+
+	doc, ok := bedrockMsgs[0].Content[1].(*types.ContentBlockMemberDocument)
+	require.True(t, ok)
+	assert.Equal(t, types.DocumentFormatPdf, doc.Value.Format)
+	assert.Equal(t, "Q3 report (final)", *doc.Value.Name)
+	docSource, ok := doc.Value.Source.(*types.DocumentSourceMemberBytes)
+	require.True(t, ok)
+	assert.Equal(t, []byte("%PDF-1.4 fake"), docSource.Value)
+
+	img, ok := bedrockMsgs[0].Content[2].(*types.ContentBlockMemberImage)
+	require.True(t, ok)
+	assert.Equal(t, types.ImageFormatPng, img.Value.Format)
+}
+
+func TestConvertFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "archive.zip")
+	require.NoError(t, os.WriteFile(zipPath, []byte("PK"), 0o644))
+	bigPath := filepath.Join(dir, "big.png")
+	require.NoError(t, os.WriteFile(bigPath, make([]byte, maxImageBytes+1), 0o644))
+
+	_, err := convertFile(&chat.MessageFile{FileID: "file_123", MimeType: "application/pdf"})
+	require.ErrorContains(t, err, "does not support provider file references")
+
+	_, err = convertFile(&chat.MessageFile{Path: zipPath, MimeType: "application/zip"})
+	require.ErrorContains(t, err, "unsupported attachment archive.zip")
+
+	_, err = convertFile(&chat.MessageFile{Path: bigPath, MimeType: "image/png"})
+	require.ErrorContains(t, err, "too large")
+
+	_, _, err = convertMessages([]chat.Message{{
+		Role:         chat.MessageRoleUser,
+		MultiContent: []chat.MessagePart{{Type: chat.MessagePartTypeFile, File: &chat.MessageFile{Path: zipPath, MimeType: "application/zip"}}},
+	}}, false)
+	require.Error(t, err)
+}
+
+func TestDocumentName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "notes", documentName("/tmp/notes.md"))
+	assert.Equal(t, "my-file v2 [draft]", documentName("/tmp/my_file  v2 [draft].docx"))
+	assert.Equal(t, "document", documentName("/tmp/.pdf"))
+}
@@ -17,7 +17,7 @@ import (
 // convertMessages handles Bedrock's Converse API constraints:
 // - Tool results must immediately follow the assistant message with tool_use
 // - Multiple consecutive tool results must be grouped into a single user message
-func convertMessages(messages []chat.Message, enableCaching bool) ([]types.Message, []types.SystemContentBlock) {
+func convertMessages(messages []chat.Message, enableCaching bool) ([]types.Message, []types.SystemContentBlock, error) {
 	var bedrockMessages []types.Message
 	var systemBlocks []types.SystemContentBlock
 
@@ -42,7 +42,10 @@ func convertMessages(messages []chat.Message, enableCaching bool) ([]types.Messa
 			}
 
 		case chat.MessageRoleUser:
-			contentBlocks := convertUserContent(msg)
+			contentBlocks, err := convertUserContent(msg)
+			if err != nil {
+				return nil, nil, err
+			}
 			if len(contentBlocks) > 0 {
 				bedrockMessages = append(bedrockMessages, types.Message{
 					Role:    types.ConversationRoleUser,
@@ -102,7 +105,7 @@ func convertMessages(messages []chat.Message, enableCaching bool) ([]types.Messa
 		applyCachePointsToMessages(bedrockMessages)
 	}
 
-	return bedrockMessages, systemBlocks
+	return bedrockMessages, systemBlocks, nil
 }
 
 func applyCachePointsToMessages(messages []types.Message) {
@@ -119,7 +122,7 @@ func applyCachePointsToMessages(messages []types.Message) {
 	}
 }
 
-func convertUserContent(msg *chat.Message) []types.ContentBlock {
+func convertUserContent(msg *chat.Message) ([]types.ContentBlock, error) {
 	var blocks []types.ContentBlock
 
 	if len(msg.MultiContent) > 0 {
@@ -135,7 +138,17 @@ func convertUserContent(msg *chat.Message) []types.ContentBlock {
 				if part.ImageURL != nil {
 					if imageBlock := convertImageURL(part.ImageURL); imageBlock != nil {
 						blocks = append(blocks, imageBlock)
+					} else {
+						slog.Warn("Bedrock only supports base64 data URL images, skipping image")
+					}
+				}
+			case chat.MessagePartTypeFile:
+				if part.File != nil {
+					fileBlock, err := convertFile(part.File)
+					if err != nil {
+						return nil, err
 					}
+					blocks = append(blocks, fileBlock)
 				}
 			}
 		}
@@ -145,7 +158,7 @@ func convertUserContent(msg *chat.Message) []types.ContentBlock {
 		})
 	}
 
-	return blocks
+	return blocks, nil
 }
 
 func convertImageURL(imageURL *chat.MessageImageURL) types.ContentBlock {
@@ -0,0 +1,71 @@
+package bedrock
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/httpclient"
+)
+
+// TestCreateChatCompletionStream_Fixture replays a ConverseStream call. Set
+// DOCKER_AGENT_RECORD_FIXTURES and AWS_BEARER_TOKEN_BEDROCK to record the
+// fixture again.
+func TestCreateChatCompletionStream_Fixture(t *testing.T) {
+	rec, err := httpclient.NewRecorder("testdata/converse_stream", httpclient.RecordModeFromEnv())
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, rec.Stop()) })
+	t.Cleanup(httpclient.UseRecorder(rec))
+
+	cfg := &latest.ModelConfig{
+		Provider: "amazon-bedrock",
+		Model:    "us.anthropic.claude-haiku-4-5-20251001-v1:0",
+		ProviderOpts: map[string]any{
+			"region": "us-east-1",
+		},
+	}
+	env := environment.NewMapEnvProvider(map[string]string{
+		"AWS_BEARER_TOKEN_BEDROCK": cmp.Or(os.Getenv("AWS_BEARER_TOKEN_BEDROCK"), "test-token"),
+	})
+	client, err := NewClient(t.Context(), cfg, env)
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(t.Context(), []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "Answer in one word."},
+		{Role: chat.MessageRoleUser, Content: "Say hello."},
+	}, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content strings.Builder
+	var usage *chat.Usage
+	var finishReason chat.FinishReason
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		for _, choice := range resp.Choices {
+			content.WriteString(choice.Delta.Content)
+			finishReason = cmp.Or(choice.FinishReason, finishReason)
+		}
+		usage = cmp.Or(resp.Usage, usage)
+	}
+
+	assert.Equal(t, "Hello!", content.String())
+	assert.Equal(t, chat.FinishReasonStop, finishReason)
+	require.NotNil(t, usage)
+	assert.Equal(t, int64(17), usage.InputTokens)
+	assert.Equal(t, int64(5), usage.OutputTokens)
+}
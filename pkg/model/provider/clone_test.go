@@ -219,3 +219,18 @@ func TestCloneWithOptions_OverridesMaxTokens(t *testing.T) {
 	assert.Equal(t, newMaxTokens, *clonedConfig.ModelConfig.MaxTokens,
 		"MaxTokens should be updated to the new value")
 }
+
+func TestNewWithModels_NestedAlloy(t *testing.T) {
+	t.Parallel()
+
+	models := map[string]latest.ModelConfig{
+		"inner": {Model: "openai/gpt-4o,anthropic/claude-sonnet-4-0"},
+	}
+	cfg := &latest.ModelConfig{
+		Model: "inner,openai/gpt-4o-mini",
+		Alloy: &latest.AlloyConfig{Strategy: latest.AlloyStrategyCheapest},
+	}
+
+	_, err := NewWithModels(t.Context(), cfg, models, environment.NewMapEnvProvider(nil))
+	require.ErrorContains(t, err, `model "inner" is an alloy and cannot be used in another alloy`)
+}
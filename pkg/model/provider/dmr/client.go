@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/openai/openai-go/v3"
@@ -36,6 +37,10 @@ const (
 // ErrNotInstalled is returned when Docker Model Runner is not installed.
 var ErrNotInstalled = errors.New("docker model runner is not available\nplease install it and try again (https://docs.docker.com/ai/model-runner/get-started/)")
 
+// ErrNotRunning is returned when Docker Model Runner is installed but not
+// running and couldn't be started.
+var ErrNotRunning = errors.New("docker model runner is not running\nstart it with `docker desktop enable model-runner` (Docker Desktop) or `docker model install-runner` (Docker Engine)")
+
 const (
 	// dmrInferencePrefix mirrors github.com/docker/model-runner/pkg/inference.InferencePrefix.
 	dmrInferencePrefix = "/engines"
@@ -76,8 +81,22 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, opts ...options.Opt
 	// This avoids unnecessary exec calls and speeds up tests/CI scenarios.
 	var endpoint, engine string
 	if cfg.BaseURL == "" && os.Getenv("MODEL_RUNNER_HOST") == "" {
-		var err error
-		endpoint, engine, err = getDockerModelEndpointAndEngine(ctx)
+		var (
+			running bool
+			err     error
+		)
+		endpoint, engine, running, err = getDockerModelEndpointAndEngine(ctx)
+		if err != nil && strings.Contains(strings.ToLower(err.Error()), "not running") {
+			running = false
+			err = nil
+		}
+		if err == nil && !running {
+			// Start the runner, then query its endpoint again now that it's up
+			if err := startModelRunner(ctx, globalOptions.PullProgress() == nil); err != nil {
+				return nil, err
+			}
+			endpoint, engine, _, err = getDockerModelEndpointAndEngine(ctx)
+		}
 		if err != nil {
 			if err.Error() == "unknown flag: --json\n\nUsage:  docker [OPTIONS] COMMAND [ARG...]\n\nRun 'docker --help' for more information" {
 				slog.Debug("docker model status query failed", "error", err)
@@ -86,7 +105,7 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, opts ...options.Opt
 			slog.Error("docker model status query failed", "error", err)
 		} else {
 			// Auto-pull the model if needed
-			if err := pullDockerModelIfNeeded(ctx, cfg.Model); err != nil {
+			if err := pullDockerModelIfNeeded(ctx, cfg.Model, globalOptions.PullProgress()); err != nil {
 				slog.Debug("docker model pull failed", "error", err)
 				return nil, err
 			}
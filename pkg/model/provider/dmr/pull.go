@@ -1,6 +1,7 @@
 package dmr
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -10,48 +11,174 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"golang.org/x/term"
 
 	"github.com/docker/docker-agent/pkg/input"
 )
 
-func pullDockerModelIfNeeded(ctx context.Context, model string) error {
-	if modelExists(ctx, model) {
+// pullProgressInterval throttles the progress updates reported while a model
+// is being pulled. `docker model pull` rewrites its progress line many times
+// per second, which is far more than a UI needs.
+const pullProgressInterval = time.Second
+
+// pullDockerModelIfNeeded pulls the model if it isn't available locally.
+// Without a progress callback the user is asked for confirmation on
+// interactive terminals and the output of `docker model pull` is shown as-is.
+// With a progress callback (e.g. when the TUI is running and owns the
+// terminal) the pull starts right away and progress is reported through it.
+func pullDockerModelIfNeeded(ctx context.Context, model string, progress func(model, status string)) error {
+	if ModelExists(ctx, model) {
 		slog.Debug("Model already exists, skipping pull", "model", model)
 		return nil
 	}
 
-	if err := confirmModelPull(ctx, model); err != nil {
-		return err
+	if progress != nil {
+		slog.Info("Pulling DMR model", "model", model)
+		return PullModel(ctx, model, func(status string) {
+			progress(model, status)
+		})
+	}
+
+	if err := confirm(ctx, fmt.Sprintf("\nModel %s not found locally.\nDo you want to pull it now? ([y]es/[n]o): ", model)); err != nil {
+		return fmt.Errorf("model pull declined: %w", err)
 	}
 
 	slog.Info("Pulling DMR model", "model", model)
 	fmt.Printf("Pulling model %s...\n", model)
 
+	if err := PullModel(ctx, model, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("Model %s pulled successfully.\n", model)
+	return nil
+}
+
+// PullModel pulls a model through Docker Model Runner. If progress is nil,
+// the output of `docker model pull` goes straight to the terminal. Otherwise
+// each progress line is passed to progress, at most once per
+// pullProgressInterval, and the last line is always reported.
+func PullModel(ctx context.Context, model string, progress func(status string)) error {
 	cmd := exec.CommandContext(ctx, "docker", "model", "pull", model)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+
+	if progress == nil {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to pull model %s: %w", model, err)
+		}
+		slog.Info("Model pulled successfully", "model", model)
+		return nil
+	}
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to pull model %s: %w", model, err)
+	}
+
+	done := make(chan string)
+	go func() {
+		done <- reportPullProgress(pr, progress)
+	}()
+
+	err := cmd.Wait()
+	_ = pw.Close()
+	last := <-done
+
+	if err != nil {
+		if last != "" {
+			return fmt.Errorf("failed to pull model %s: %w: %s", model, err, last)
+		}
 		return fmt.Errorf("failed to pull model %s: %w", model, err)
 	}
 
 	slog.Info("Model pulled successfully", "model", model)
-	fmt.Printf("Model %s pulled successfully.\n", model)
+	return nil
+}
+
+// reportPullProgress reads `docker model pull` output and forwards throttled
+// progress lines. It returns the last non-empty line.
+func reportPullProgress(r io.Reader, progress func(status string)) string {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanProgressLines)
+
+	var (
+		last     string
+		reported string
+		lastSent time.Time
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		last = line
+		if time.Since(lastSent) >= pullProgressInterval {
+			progress(line)
+			reported = line
+			lastSent = time.Now()
+		}
+	}
+	// Drain whatever is left so the command never blocks on a full pipe.
+	_, _ = io.Copy(io.Discard, r)
+
+	if last != "" && last != reported {
+		progress(last)
+	}
+	return last
+}
+
+// scanProgressLines is a bufio.SplitFunc that splits on both '\n' and '\r',
+// since progress bars redraw the current line with carriage returns.
+func scanProgressLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// startModelRunner starts a stopped Docker Model Runner after asking for
+// confirmation on interactive terminals.
+func startModelRunner(ctx context.Context, interactive bool) error {
+	if interactive {
+		if err := confirm(ctx, "\nDocker Model Runner is not running.\nDo you want to start it now? ([y]es/[n]o): "); err != nil {
+			return ErrNotRunning
+		}
+	}
+
+	slog.Info("Starting Docker Model Runner")
+
+	cmd := exec.CommandContext(ctx, "docker", "model", "start-runner")
+	var stderr bytes.Buffer
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		slog.Debug("Failed to start Docker Model Runner", "error", err, "stderr", strings.TrimSpace(stderr.String()))
+		return ErrNotRunning
+	}
 
 	return nil
 }
 
-// confirmModelPull asks for user confirmation in interactive mode.
+// confirm asks the user a yes/no question in interactive mode.
 // In non-interactive mode (e.g. devcontainers, CI), it proceeds automatically.
-func confirmModelPull(ctx context.Context, model string) error {
+func confirm(ctx context.Context, question string) error {
 	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		slog.Info("Model not found locally, pulling automatically (non-interactive mode)", "model", model)
+		slog.Info("Proceeding automatically (non-interactive mode)", "question", strings.TrimSpace(question))
 		return nil
 	}
 
-	fmt.Printf("\nModel %s not found locally.\n", model)
-	fmt.Printf("Do you want to pull it now? ([y]es/[n]o): ")
+	fmt.Print(question)
 
 	response, err := input.ReadLine(ctx, os.Stdin)
 	if err != nil {
@@ -60,13 +187,14 @@ func confirmModelPull(ctx context.Context, model string) error {
 
 	response = strings.TrimSpace(strings.ToLower(response))
 	if response != "y" && response != "yes" {
-		return errors.New("model pull declined by user")
+		return errors.New("declined by user")
 	}
 
 	return nil
 }
 
-func modelExists(ctx context.Context, model string) bool {
+// ModelExists reports whether the model has already been pulled.
+func ModelExists(ctx context.Context, model string) bool {
 	cmd := exec.CommandContext(ctx, "docker", "model", "inspect", model)
 	var stderr bytes.Buffer
 	cmd.Stdout = io.Discard
@@ -0,0 +1,36 @@
+package dmr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportPullProgress(t *testing.T) {
+	t.Parallel()
+
+	output := "Downloaded: 10.00 MB\rDownloaded: 20.00 MB\rDownloaded: 30.00 MB\n\nModel pulled successfully\n"
+
+	var statuses []string
+	last := reportPullProgress(strings.NewReader(output), func(status string) {
+		statuses = append(statuses, status)
+	})
+
+	assert.Equal(t, "Model pulled successfully", last)
+	// The first line is reported right away, later ones are throttled,
+	// and the final line is always reported.
+	assert.Equal(t, []string{"Downloaded: 10.00 MB", "Model pulled successfully"}, statuses)
+}
+
+func TestReportPullProgress_NoTrailingNewline(t *testing.T) {
+	t.Parallel()
+
+	var statuses []string
+	last := reportPullProgress(strings.NewReader("error: model not found"), func(status string) {
+		statuses = append(statuses, status)
+	})
+
+	assert.Equal(t, "error: model not found", last)
+	assert.Equal(t, []string{"error: model not found"}, statuses)
+}
@@ -209,24 +209,25 @@ func resolvePrimaryDMRURL(endpoint string) (string, []option.RequestOption, *htt
 }
 
 // getDockerModelEndpointAndEngine shells out to `docker model status --json`
-// and returns the resolved endpoint URL and the active inference engine name.
-func getDockerModelEndpointAndEngine(ctx context.Context) (endpoint, engine string, err error) {
+// and returns the resolved endpoint URL, the active inference engine name and
+// whether the runner is currently running.
+func getDockerModelEndpointAndEngine(ctx context.Context) (endpoint, engine string, running bool, err error) {
 	cmd := exec.CommandContext(ctx, "docker", "model", "status", "--json")
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
-		return "", "", errors.New(strings.TrimSpace(stderr.String()))
+		return "", "", false, errors.New(strings.TrimSpace(stderr.String()))
 	}
 
 	var st struct {
-		Running  bool              `json:"running"`
+		Running  *bool             `json:"running"`
 		Backends map[string]string `json:"backends"`
 		Endpoint string            `json:"endpoint"`
 		Engine   string            `json:"engine"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &st); err != nil {
-		return "", "", err
+		return "", "", false, err
 	}
 
 	endpoint = strings.TrimSpace(st.Endpoint)
@@ -244,5 +245,25 @@ func getDockerModelEndpointAndEngine(ctx context.Context) (endpoint, engine stri
 	}
 	engine = cmp.Or(engine, "llama.cpp")
 
-	return endpoint, engine, nil
+	// Older versions don't report the running state.
+	running = st.Running == nil || *st.Running
+
+	return endpoint, engine, running, nil
+}
+
+// Status reports whether Docker Model Runner can serve models, without
+// starting it. It returns the active inference engine, or ErrNotInstalled or
+// ErrNotRunning.
+func Status(ctx context.Context) (engine string, err error) {
+	_, engine, running, err := getDockerModelEndpointAndEngine(ctx)
+	switch {
+	case err != nil && strings.Contains(strings.ToLower(err.Error()), "not running"):
+		return "", ErrNotRunning
+	case err != nil:
+		slog.Debug("docker model status query failed", "error", err)
+		return "", ErrNotInstalled
+	case !running:
+		return "", ErrNotRunning
+	}
+	return engine, nil
 }
@@ -1,6 +1,7 @@
 package gemini
 
 import (
+	"cmp"
 	"encoding/json"
 	"io"
 	"log/slog"
@@ -92,12 +93,17 @@ func NewStreamAdapter(iter func(func(*genai.GenerateContentResponse, error) bool
 			}
 
 			if resp != nil {
-				// Check for text content without using Text() to avoid warnings
+				// Check for text content without using Text() to avoid warnings.
+				// Code execution parts and grounding metadata count as text
+				// since they are rendered into the message content.
 				hasText := false
 				for _, candidate := range resp.Candidates {
+					if candidate.GroundingMetadata != nil && len(candidate.GroundingMetadata.GroundingSupports) > 0 {
+						hasText = true
+					}
 					if candidate.Content != nil {
 						for _, part := range candidate.Content.Parts {
-							if part.Text != "" {
+							if part.Text != "" || part.ExecutableCode != nil || part.CodeExecutionResult != nil {
 								hasText = true
 								break
 							}
@@ -181,6 +187,7 @@ func (g *StreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 		var reasoningTextSb strings.Builder
 		var textContentSb strings.Builder
 		var thoughtSignature []byte
+		var citations []chat.Citation
 		for _, candidate := range res.resp.Candidates {
 			if candidate.Content != nil {
 				for _, part := range candidate.Content.Parts {
@@ -195,8 +202,17 @@ func (g *StreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 							textContentSb.WriteString(part.Text)
 						}
 					}
+
+					// Code run by the native code_execution tool is shown inline
+					if part.ExecutableCode != nil {
+						textContentSb.WriteString(formatExecutableCode(part.ExecutableCode))
+					}
+					if part.CodeExecutionResult != nil {
+						textContentSb.WriteString(formatCodeExecutionResult(part.CodeExecutionResult))
+					}
 				}
 			}
+			citations = append(citations, convertGroundingMetadata(candidate.GroundingMetadata)...)
 		}
 		reasoningText := reasoningTextSb.String()
 		textContent := textContentSb.String()
@@ -209,6 +225,9 @@ func (g *StreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 		if len(thoughtSignature) > 0 {
 			resp.Choices[0].Delta.ThoughtSignature = thoughtSignature
 		}
+		if len(citations) > 0 {
+			resp.Choices[0].Delta.Citations = citations
+		}
 
 		// Handle function calls
 		if funcs := res.resp.FunctionCalls(); len(funcs) > 0 {
@@ -233,6 +252,61 @@ func (g *StreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 	return resp, nil
 }
 
+// formatExecutableCode renders code generated by the code_execution tool as a
+// fenced markdown block.
+func formatExecutableCode(code *genai.ExecutableCode) string {
+	lang := strings.ToLower(string(code.Language))
+	return "\n```" + lang + "\n" + strings.TrimRight(code.Code, "\n") + "\n```\n"
+}
+
+// formatCodeExecutionResult renders the output of the code_execution tool as a
+// fenced markdown block.
+func formatCodeExecutionResult(res *genai.CodeExecutionResult) string {
+	output := strings.TrimRight(res.Output, "\n")
+	if res.Outcome != "" && res.Outcome != genai.OutcomeOK {
+		output = strings.TrimSpace(string(res.Outcome) + "\n" + output)
+	}
+	if output == "" {
+		return ""
+	}
+	return "\n```\n" + output + "\n```\n"
+}
+
+// convertGroundingMetadata maps Google Search grounding supports to citations,
+// one per (segment, web source) pair.
+func convertGroundingMetadata(metadata *genai.GroundingMetadata) []chat.Citation {
+	if metadata == nil {
+		return nil
+	}
+
+	var citations []chat.Citation
+	for _, support := range metadata.GroundingSupports {
+		if support == nil {
+			continue
+		}
+		var citedText string
+		if support.Segment != nil {
+			citedText = support.Segment.Text
+		}
+		for _, idx := range support.GroundingChunkIndices {
+			if int(idx) >= len(metadata.GroundingChunks) {
+				continue
+			}
+			chunk := metadata.GroundingChunks[idx]
+			if chunk == nil || chunk.Web == nil {
+				continue
+			}
+			citations = append(citations, chat.Citation{
+				Type:      "grounding",
+				CitedText: citedText,
+				Source:    chunk.Web.URI,
+				Title:     cmp.Or(chunk.Web.Title, chunk.Web.Domain),
+			})
+		}
+	}
+	return citations
+}
+
 // Close closes the stream
 func (g *StreamAdapter) Close() {
 	// Drain channel to let goroutine exit
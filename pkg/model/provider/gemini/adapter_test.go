@@ -54,3 +54,47 @@ func TestStreamAdapter_FunctionCalls(t *testing.T) {
 		require.Empty(t, finalResp.Choices[0].Delta.ToolCalls)
 	})
 }
+
+func TestStreamAdapter_NativeTools(t *testing.T) {
+	mockResp := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content: &genai.Content{
+				Parts: []*genai.Part{
+					{ExecutableCode: &genai.ExecutableCode{Code: "print(1 + 1)\n", Language: genai.LanguagePython}},
+					{CodeExecutionResult: &genai.CodeExecutionResult{Outcome: genai.OutcomeOK, Output: "2\n"}},
+					{Text: "The answer is 2."},
+				},
+			},
+			GroundingMetadata: &genai.GroundingMetadata{
+				GroundingChunks: []*genai.GroundingChunk{
+					{Web: &genai.GroundingChunkWeb{URI: "https://example.com/math", Title: "Math"}},
+					{Web: &genai.GroundingChunkWeb{URI: "https://example.org/sums", Domain: "example.org"}},
+				},
+				GroundingSupports: []*genai.GroundingSupport{{
+					Segment:               &genai.Segment{Text: "The answer is 2."},
+					GroundingChunkIndices: []int32{0, 1, 5},
+				}},
+			},
+		}},
+	}
+
+	iter := func(fn func(*genai.GenerateContentResponse, error) bool) {
+		fn(mockResp, nil)
+	}
+
+	adapter := NewStreamAdapter(iter, "test-model", true)
+
+	resp, err := adapter.Recv()
+	require.NoError(t, err)
+
+	delta := resp.Choices[0].Delta
+	require.Equal(t, "\n```python\nprint(1 + 1)\n```\n\n```\n2\n```\nThe answer is 2.", delta.Content)
+	require.Equal(t, []chat.Citation{
+		{Type: "grounding", CitedText: "The answer is 2.", Source: "https://example.com/math", Title: "Math"},
+		{Type: "grounding", CitedText: "The answer is 2.", Source: "https://example.org/sums", Title: "example.org"},
+	}, delta.Citations)
+
+	finalResp, err := adapter.Recv()
+	require.NoError(t, err)
+	require.Equal(t, chat.FinishReasonStop, finalResp.Choices[0].FinishReason)
+}
@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 
 	"google.golang.org/genai"
@@ -480,14 +481,29 @@ func (c *Client) applyGemini25ThinkingBudget(config *genai.GenerateContentConfig
 	}
 }
 
-// convertToolsToGemini converts tools to Gemini format
+// convertToolsToGemini converts tools to Gemini format. Provider-native tools
+// (Google Search grounding, code execution) are mapped to their dedicated
+// genai.Tool fields; everything else becomes a function declaration.
 func convertToolsToGemini(requestTools []tools.Tool) ([]*genai.Tool, error) {
 	if len(requestTools) == 0 {
 		return nil, nil
 	}
 
+	var geminiTools []*genai.Tool
 	funcs := make([]*genai.FunctionDeclaration, 0, len(requestTools))
 	for _, tool := range requestTools {
+		if tool.Provider == "google" {
+			switch tool.Name {
+			case "google_search":
+				geminiTools = append(geminiTools, &genai.Tool{GoogleSearch: &genai.GoogleSearch{}})
+			case "code_execution":
+				geminiTools = append(geminiTools, &genai.Tool{CodeExecution: &genai.ToolCodeExecution{}})
+			default:
+				return nil, fmt.Errorf("unsupported Gemini native tool: %s", tool.Name)
+			}
+			continue
+		}
+
 		parameters, err := ConvertParametersToSchema(tool.Parameters)
 		if err != nil {
 			return nil, err
@@ -500,9 +516,18 @@ func convertToolsToGemini(requestTools []tools.Tool) ([]*genai.Tool, error) {
 		})
 	}
 
-	return []*genai.Tool{{
-		FunctionDeclarations: funcs,
-	}}, nil
+	if len(funcs) > 0 {
+		geminiTools = append(geminiTools, &genai.Tool{FunctionDeclarations: funcs})
+	}
+
+	return geminiTools, nil
+}
+
+// hasFunctionDeclarations reports whether any of the tools declares functions.
+func hasFunctionDeclarations(geminiTools []*genai.Tool) bool {
+	return slices.ContainsFunc(geminiTools, func(t *genai.Tool) bool {
+		return len(t.FunctionDeclarations) > 0
+	})
 }
 
 // ConvertParametersToSchema converts parameters to Gemini Schema format
@@ -582,10 +607,12 @@ func (c *Client) CreateChatCompletionStream(
 		config.Tools = allTools
 
 		// Enable function calling
-		config.ToolConfig = &genai.ToolConfig{
-			FunctionCallingConfig: &genai.FunctionCallingConfig{
-				Mode: genai.FunctionCallingConfigModeAuto,
-			},
+		if hasFunctionDeclarations(allTools) {
+			config.ToolConfig = &genai.ToolConfig{
+				FunctionCallingConfig: &genai.FunctionCallingConfig{
+					Mode: genai.FunctionCallingConfigModeAuto,
+				},
+			}
 		}
 
 		// Debug: Log the tools we're sending
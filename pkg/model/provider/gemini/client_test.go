@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/model/provider/base"
 	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/tools"
 )
 
 func TestBuildConfig_Gemini25_ThinkingBudget(t *testing.T) {
@@ -432,3 +433,31 @@ func TestBuildConfig_ThinkingNotSet(t *testing.T) {
 	assert.True(t, config.ThinkingConfig.IncludeThoughts, "IncludeThoughts should be true")
 	assert.Equal(t, genai.ThinkingLevelHigh, config.ThinkingConfig.ThinkingLevel, "ThinkingLevel should match ThinkingBudget")
 }
+
+func TestConvertToolsToGemini_NativeTools(t *testing.T) {
+	t.Parallel()
+
+	geminiTools, err := convertToolsToGemini([]tools.Tool{
+		{Name: "google_search", Provider: "google"},
+		{Name: "code_execution", Provider: "google"},
+	})
+	require.NoError(t, err)
+	require.Len(t, geminiTools, 2)
+	assert.NotNil(t, geminiTools[0].GoogleSearch)
+	assert.NotNil(t, geminiTools[1].CodeExecution)
+	assert.False(t, hasFunctionDeclarations(geminiTools))
+
+	geminiTools, err = convertToolsToGemini([]tools.Tool{
+		{Name: "google_search", Provider: "google"},
+		{Name: "read_file", Description: "Read a file", Parameters: map[string]any{"type": "object"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, geminiTools, 2)
+	assert.NotNil(t, geminiTools[0].GoogleSearch)
+	require.Len(t, geminiTools[1].FunctionDeclarations, 1)
+	assert.Equal(t, "read_file", geminiTools[1].FunctionDeclarations[0].Name)
+	assert.True(t, hasFunctionDeclarations(geminiTools))
+
+	_, err = convertToolsToGemini([]tools.Tool{{Name: "url_context", Provider: "google"}})
+	require.ErrorContains(t, err, "unsupported Gemini native tool")
+}
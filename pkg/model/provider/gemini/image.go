@@ -0,0 +1,80 @@
+package gemini
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+)
+
+// GenerateImage generates an image from a text prompt. Imagen models use the
+// image generation API; Gemini image models, such as
+// gemini-2.5-flash-image, answer with an inline image.
+func (c *Client) GenerateImage(ctx context.Context, prompt string, opts base.ImageGenerationOptions) (*base.ImageGenerationResult, error) {
+	slog.Debug("Creating Gemini image", "model", c.ModelConfig.Model, "prompt_length", len(prompt), "aspect_ratio", opts.AspectRatio)
+
+	client, err := c.clientFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(c.ModelConfig.Model, "imagen-") {
+		return generateImagen(ctx, client, c.ModelConfig.Model, prompt, opts)
+	}
+
+	config := &genai.GenerateContentConfig{
+		ResponseModalities: []string{string(genai.ModalityText), string(genai.ModalityImage)},
+	}
+	if opts.AspectRatio != "" {
+		config.ImageConfig = &genai.ImageConfig{AspectRatio: opts.AspectRatio}
+	}
+
+	response, err := client.Models.GenerateContent(ctx, c.ModelConfig.Model, genai.Text(prompt), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate image: %w", err)
+	}
+	for _, candidate := range response.Candidates {
+		if candidate.Content == nil {
+			continue
+		}
+		for _, part := range candidate.Content.Parts {
+			if part.InlineData != nil && len(part.InlineData.Data) > 0 {
+				return &base.ImageGenerationResult{
+					Data:     part.InlineData.Data,
+					MimeType: cmp.Or(part.InlineData.MIMEType, http.DetectContentType(part.InlineData.Data)),
+				}, nil
+			}
+		}
+	}
+	return nil, errors.New("no image returned from Gemini")
+}
+
+func generateImagen(ctx context.Context, client *genai.Client, model, prompt string, opts base.ImageGenerationOptions) (*base.ImageGenerationResult, error) {
+	response, err := client.Models.GenerateImages(ctx, model, prompt, &genai.GenerateImagesConfig{
+		NumberOfImages: 1,
+		AspectRatio:    opts.AspectRatio,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate image: %w", err)
+	}
+	if len(response.GeneratedImages) == 0 || response.GeneratedImages[0].Image == nil {
+		return nil, errors.New("no image returned from Imagen")
+	}
+
+	generated := response.GeneratedImages[0]
+	if generated.RAIFilteredReason != "" {
+		return nil, fmt.Errorf("image filtered: %s", generated.RAIFilteredReason)
+	}
+	return &base.ImageGenerationResult{
+		Data:          generated.Image.ImageBytes,
+		MimeType:      cmp.Or(generated.Image.MIMEType, http.DetectContentType(generated.Image.ImageBytes)),
+		RevisedPrompt: generated.EnhancedPrompt,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package gemini
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+)
+
+func TestGenerateImage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Path, "/models/gemini-2.5-flash-image:generateContent")
+
+		var body struct {
+			GenerationConfig struct {
+				ResponseModalities []string `json:"responseModalities"`
+				ImageConfig        struct {
+					AspectRatio string `json:"aspectRatio"`
+				} `json:"imageConfig"`
+			} `json:"generationConfig"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []string{"TEXT", "IMAGE"}, body.GenerationConfig.ResponseModalities)
+		assert.Equal(t, "16:9", body.GenerationConfig.ImageConfig.AspectRatio)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Here it is"},{"inlineData":{"mimeType":"image/png","data":"iVBORw0KGgo="}}]}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &latest.ModelConfig{Provider: "google", Model: "gemini-2.5-flash-image", BaseURL: server.URL}
+	env := environment.NewMapEnvProvider(map[string]string{"GOOGLE_API_KEY": "test-key"})
+	client, err := NewClient(t.Context(), cfg, env)
+	require.NoError(t, err)
+
+	image, err := client.GenerateImage(t.Context(), "a cat", base.ImageGenerationOptions{AspectRatio: base.AspectRatioLandscape})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", image.MimeType)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n"), image.Data)
+}
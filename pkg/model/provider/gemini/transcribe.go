@@ -0,0 +1,49 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// maxInlineMediaSize is the largest file that can be sent inline in a
+// request.
+const maxInlineMediaSize = 20 * 1024 * 1024
+
+const transcriptionPrompt = `Transcribe the speech of this file verbatim, in its original language. Start a new paragraph when the speaker changes, prefixed with the speaker's name or "Speaker 1:", "Speaker 2:"... when there are several. For a video, also describe what is shown on screen, such as slides, code or demos, in square brackets where it appears. Return only the transcript.`
+
+// TranscribeMedia returns the transcript of the given audio or video file,
+// using the model of the client, e.g. gemini-2.5-flash.
+func (c *Client) TranscribeMedia(ctx context.Context, data []byte, mimeType string) (string, error) {
+	slog.Debug("Creating Gemini transcription", "model", c.ModelConfig.Model, "media_bytes", len(data), "mime_type", mimeType)
+
+	if len(data) > maxInlineMediaSize {
+		return "", fmt.Errorf("file too large for Gemini transcription (%d bytes, max 20MB)", len(data))
+	}
+
+	client, err := c.clientFn(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	contents := []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			genai.NewPartFromBytes(data, mimeType),
+			genai.NewPartFromText(transcriptionPrompt),
+		}, genai.RoleUser),
+	}
+	response, err := client.Models.GenerateContent(ctx, c.ModelConfig.Model, contents, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to transcribe media: %w", err)
+	}
+
+	transcript := strings.TrimSpace(response.Text())
+	if transcript == "" {
+		return "", errors.New("no transcript returned from Gemini")
+	}
+	return transcript, nil
+}
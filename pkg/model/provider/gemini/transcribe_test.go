@@ -0,0 +1,53 @@
+package gemini
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+func TestTranscribeMedia(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Path, "/models/gemini-2.5-flash:generateContent")
+
+		var body struct {
+			Contents []struct {
+				Parts []struct {
+					InlineData *struct {
+						MIMEType string `json:"mimeType"`
+						Data     string `json:"data"`
+					} `json:"inlineData"`
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"contents"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if assert.Len(t, body.Contents, 1) && assert.Len(t, body.Contents[0].Parts, 2) {
+			assert.Equal(t, "audio/mpeg", body.Contents[0].Parts[0].InlineData.MIMEType)
+			assert.Equal(t, "SUQz", body.Contents[0].Parts[0].InlineData.Data)
+			assert.Contains(t, body.Contents[0].Parts[1].Text, "Transcribe")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Hello everyone.\n"}]}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &latest.ModelConfig{Provider: "google", Model: "gemini-2.5-flash", BaseURL: server.URL}
+	env := environment.NewMapEnvProvider(map[string]string{"GOOGLE_API_KEY": "test-key"})
+	client, err := NewClient(t.Context(), cfg, env)
+	require.NoError(t, err)
+
+	transcript, err := client.TranscribeMedia(t.Context(), []byte("ID3"), "audio/mpeg")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello everyone.", transcript)
+}
@@ -0,0 +1,201 @@
+// Package mock provides a deterministic model provider that plays back
+// scripted responses and tool calls from a fixture.
+//
+// It lets tests and demos exercise the whole runtime and toolset pipeline
+// without API keys nor network access:
+//
+//	models:
+//	  scripted:
+//	    provider: mock
+//	    model: demo
+//	    provider_opts:
+//	      fixture: ./fixtures/demo.yaml
+//
+// The fixture can also be written inline under provider_opts.responses.
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Client implements the provider.Provider interface by playing back a fixture.
+type Client struct {
+	base.Config
+	fixture *Fixture
+}
+
+// NewClient creates a mock client from a model configuration. The fixture
+// is read from the file set in provider_opts.fixture, or from the responses
+// set inline in provider_opts.responses.
+func NewClient(_ context.Context, cfg *latest.ModelConfig, opts ...options.Opt) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("model configuration is required")
+	}
+
+	fixture, err := fixtureFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(cfg, fixture, opts...), nil
+}
+
+// New creates a mock client playing back the given fixture. It is meant
+// for library users and tests that build their fixture in Go.
+func New(cfg *latest.ModelConfig, fixture *Fixture, opts ...options.Opt) *Client {
+	var globalOptions options.ModelOptions
+	for _, opt := range opts {
+		opt(&globalOptions)
+	}
+
+	return &Client{
+		Config: base.Config{
+			ModelConfig:  *cfg,
+			ModelOptions: globalOptions,
+		},
+		fixture: fixture,
+	}
+}
+
+func fixtureFromConfig(cfg *latest.ModelConfig) (*Fixture, error) {
+	if path, ok := cfg.ProviderOpts["fixture"].(string); ok && path != "" {
+		return LoadFixture(path)
+	}
+
+	if responses, ok := cfg.ProviderOpts["responses"]; ok {
+		data, err := json.Marshal(map[string]any{"responses": responses})
+		if err != nil {
+			return nil, fmt.Errorf("encoding mock responses: %w", err)
+		}
+		f, err := ParseFixture(data)
+		if err != nil {
+			return nil, fmt.Errorf("mock responses: %w", err)
+		}
+		return f, nil
+	}
+
+	return nil, errors.New("mock provider requires provider_opts.fixture or provider_opts.responses")
+}
+
+// CreateChatCompletionStream plays back the first response of the fixture
+// matching the conversation.
+func (c *Client) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	response, err := c.fixture.find(messages)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+
+	slog.Debug("Playing back mock response", "model", c.ModelConfig.Model, "content_length", len(response.Content), "tool_calls", len(response.ToolCalls))
+
+	return newStream(c.ModelConfig.Model, response, messages), nil
+}
+
+// stream streams a scripted response chunk by chunk.
+type stream struct {
+	chunks []chat.MessageStreamResponse
+}
+
+func newStream(model string, response *Response, messages []chat.Message) *stream {
+	var chunks []chat.MessageStreamResponse
+	add := func(delta chat.MessageDelta) {
+		chunks = append(chunks, chat.MessageStreamResponse{
+			Model:   model,
+			Choices: []chat.MessageStreamChoice{{Delta: delta}},
+		})
+	}
+
+	for _, word := range splitWords(response.Reasoning) {
+		add(chat.MessageDelta{ReasoningContent: word})
+	}
+	for _, word := range splitWords(response.Content) {
+		add(chat.MessageDelta{Content: word})
+	}
+	for i, call := range response.ToolCalls {
+		add(chat.MessageDelta{ToolCalls: []tools.ToolCall{{
+			// Tool call IDs only depend on the conversation, so that runs
+			// are reproducible.
+			ID:   fmt.Sprintf("call_%d_%d", len(messages), i),
+			Type: "function",
+			Function: tools.FunctionCall{
+				Name:      call.Name,
+				Arguments: call.arguments(),
+			},
+		}}})
+	}
+
+	finishReason := chat.FinishReasonStop
+	if len(response.ToolCalls) > 0 {
+		finishReason = chat.FinishReasonToolCalls
+	}
+	usage := response.Usage
+	if usage == nil {
+		usage = estimateUsage(response, messages)
+	}
+	chunks = append(chunks, chat.MessageStreamResponse{
+		Model:   model,
+		Choices: []chat.MessageStreamChoice{{FinishReason: finishReason}},
+		Usage:   usage,
+	})
+
+	return &stream{chunks: chunks}
+}
+
+func (s *stream) Recv() (chat.MessageStreamResponse, error) {
+	if len(s.chunks) == 0 {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *stream) Close() {}
+
+// splitWords splits text into chunks that keep their trailing spaces, so
+// that joining them gives back the text.
+func splitWords(text string) []string {
+	var words []string
+	for text != "" {
+		i := strings.IndexByte(text, ' ')
+		if i < 0 {
+			words = append(words, text)
+			break
+		}
+		words = append(words, text[:i+1])
+		text = text[i+1:]
+	}
+	return words
+}
+
+// estimateUsage estimates token usage from the size of the messages, with
+// the usual four characters per token.
+func estimateUsage(response *Response, messages []chat.Message) *chat.Usage {
+	var input int
+	for _, msg := range messages {
+		input += len(messageText(msg))
+	}
+	output := len(response.Content) + len(response.Reasoning)
+	for _, call := range response.ToolCalls {
+		output += len(call.Name) + len(call.arguments())
+	}
+
+	return &chat.Usage{
+		InputTokens:  int64(input+3) / 4,
+		OutputTokens: int64(output+3) / 4,
+	}
+}
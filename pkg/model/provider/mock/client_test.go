@@ -0,0 +1,165 @@
+package mock
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const weatherFixture = `
+responses:
+  - match: weather
+    role: user
+    reasoning: Let me look it up.
+    tool_calls:
+      - name: get_weather
+        arguments:
+          city: Paris
+  - tool: get_weather
+    content: It is sunny in Paris.
+  - regex: "^fail"
+    error: scripted failure
+  - content: I don't know.
+`
+
+// collect reads a stream to the end.
+func collect(t *testing.T, s chat.MessageStream) (content, reasoning string, calls []tools.ToolCall, last chat.MessageStreamResponse) {
+	t.Helper()
+
+	var contentBuilder, reasoningBuilder strings.Builder
+	for {
+		resp, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			return contentBuilder.String(), reasoningBuilder.String(), calls, last
+		}
+		require.NoError(t, err)
+
+		delta := resp.Choices[0].Delta
+		contentBuilder.WriteString(delta.Content)
+		reasoningBuilder.WriteString(delta.ReasoningContent)
+		calls = append(calls, delta.ToolCalls...)
+		last = resp
+	}
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "fixture.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(weatherFixture), 0o644))
+
+	client, err := NewClient(t.Context(), &latest.ModelConfig{
+		Provider:     "mock",
+		Model:        "weather",
+		ProviderOpts: map[string]any{"fixture": path},
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_ToolCallFlow(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t)
+	assert.Equal(t, "mock/weather", client.ID())
+
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "You are a weather agent"},
+		{Role: chat.MessageRoleUser, Content: "What's the Weather like?"},
+	}
+	s, err := client.CreateChatCompletionStream(t.Context(), messages, nil)
+	require.NoError(t, err)
+
+	content, reasoning, calls, last := collect(t, s)
+	assert.Empty(t, content)
+	assert.Equal(t, "Let me look it up.", reasoning)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "call_2_0", calls[0].ID)
+	assert.Equal(t, "get_weather", calls[0].Function.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, calls[0].Function.Arguments)
+	assert.Equal(t, chat.FinishReasonToolCalls, last.Choices[0].FinishReason)
+	require.NotNil(t, last.Usage)
+	assert.Positive(t, last.Usage.InputTokens)
+
+	messages = append(messages,
+		chat.Message{Role: chat.MessageRoleAssistant, ToolCalls: calls},
+		chat.Message{Role: chat.MessageRoleTool, ToolCallID: calls[0].ID, Content: "sunny"},
+	)
+	s, err = client.CreateChatCompletionStream(t.Context(), messages, nil)
+	require.NoError(t, err)
+
+	content, _, calls, last = collect(t, s)
+	assert.Equal(t, "It is sunny in Paris.", content)
+	assert.Empty(t, calls)
+	assert.Equal(t, chat.FinishReasonStop, last.Choices[0].FinishReason)
+}
+
+func TestClient_DefaultAndError(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t)
+
+	s, err := client.CreateChatCompletionStream(t.Context(), []chat.Message{{Role: chat.MessageRoleUser, Content: "Hello"}}, nil)
+	require.NoError(t, err)
+	content, _, _, _ := collect(t, s)
+	assert.Equal(t, "I don't know.", content)
+
+	_, err = client.CreateChatCompletionStream(t.Context(), []chat.Message{{Role: chat.MessageRoleUser, Content: "fail now"}}, nil)
+	require.EqualError(t, err, "scripted failure")
+}
+
+func TestNewClient_InlineResponses(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(t.Context(), &latest.ModelConfig{
+		Provider: "mock",
+		Model:    "inline",
+		ProviderOpts: map[string]any{
+			"responses": []any{
+				map[string]any{"match": "ping", "content": "pong"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	s, err := client.CreateChatCompletionStream(t.Context(), []chat.Message{{Role: chat.MessageRoleUser, Content: "ping"}}, nil)
+	require.NoError(t, err)
+	content, _, _, _ := collect(t, s)
+	assert.Equal(t, "pong", content)
+
+	_, err = client.CreateChatCompletionStream(t.Context(), []chat.Message{{Role: chat.MessageRoleUser, Content: "hello"}}, nil)
+	require.ErrorContains(t, err, "no mock response matches")
+}
+
+func TestNewClient_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient(t.Context(), &latest.ModelConfig{Provider: "mock", Model: "none"})
+	require.ErrorContains(t, err, "provider_opts.fixture")
+
+	_, err = ParseFixture([]byte("responses:\n  - regex: '('\n"))
+	require.ErrorContains(t, err, "invalid regex")
+
+	_, err = ParseFixture([]byte("responses:\n  - tool_calls:\n      - arguments: {}\n"))
+	require.ErrorContains(t, err, "name is required")
+
+	_, err = ParseFixture([]byte("responses:\n  - contents: typo\n"))
+	require.Error(t, err)
+}
+
+func TestSplitWords(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"It ", "is ", "sunny."}, splitWords("It is sunny."))
+	assert.Empty(t, splitWords(""))
+}
@@ -0,0 +1,184 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/docker-agent/pkg/chat"
+)
+
+// Fixture is a script of the responses the mock model plays back.
+type Fixture struct {
+	// Responses are tried in order: the first one whose rules match the
+	// conversation is played back.
+	Responses []Response `json:"responses"`
+}
+
+// Response is a scripted model response and the rules selecting it.
+//
+// Rules look at the last message of the conversation, i.e. the user's
+// prompt or the result of the last tool call. A response without any rule
+// always matches and can be used as a default.
+type Response struct {
+	// Match selects the response when the last message contains this text
+	// (case-insensitive).
+	Match string `json:"match,omitempty"`
+	// Regex selects the response when the last message matches this
+	// regular expression.
+	Regex string `json:"regex,omitempty"`
+	// Role selects the response when the last message has this role
+	// (user or tool).
+	Role string `json:"role,omitempty"`
+	// Tool selects the response when the last message is the result of a
+	// call to this tool.
+	Tool string `json:"tool,omitempty"`
+
+	// Content is the text of the response.
+	Content string `json:"content,omitempty"`
+	// Reasoning is streamed as reasoning content before the text.
+	Reasoning string `json:"reasoning,omitempty"`
+	// ToolCalls are the tools the model calls.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// Error makes the request fail with this message instead.
+	Error string `json:"error,omitempty"`
+	// Usage overrides the token usage, estimated from the size of the
+	// messages by default.
+	Usage *chat.Usage `json:"usage,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// ToolCall is a scripted tool call.
+type ToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// LoadFixture reads a fixture from a YAML or JSON file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading mock fixture: %w", err)
+	}
+
+	f, err := ParseFixture(data)
+	if err != nil {
+		return nil, fmt.Errorf("mock fixture %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// ParseFixture parses a YAML or JSON fixture.
+func ParseFixture(data []byte) (*Fixture, error) {
+	var f Fixture
+	if err := yaml.UnmarshalWithOptions(data, &f, yaml.Strict()); err != nil {
+		return nil, err
+	}
+	if err := f.compile(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (f *Fixture) compile() error {
+	if len(f.Responses) == 0 {
+		return errors.New("no responses")
+	}
+
+	for i := range f.Responses {
+		r := &f.Responses[i]
+		if r.Regex != "" {
+			re, err := regexp.Compile(r.Regex)
+			if err != nil {
+				return fmt.Errorf("responses[%d]: invalid regex: %w", i, err)
+			}
+			r.regex = re
+		}
+		for j, call := range r.ToolCalls {
+			if call.Name == "" {
+				return fmt.Errorf("responses[%d].tool_calls[%d]: name is required", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// find returns the first response matching the conversation.
+func (f *Fixture) find(messages []chat.Message) (*Response, error) {
+	var last chat.Message
+	if len(messages) > 0 {
+		last = messages[len(messages)-1]
+	}
+	prompt := messageText(last)
+	tool := toolName(messages, last)
+
+	for i := range f.Responses {
+		if r := &f.Responses[i]; r.matches(last.Role, prompt, tool) {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("no mock response matches the last message: %q", prompt)
+}
+
+func (r *Response) matches(role chat.MessageRole, prompt, tool string) bool {
+	if r.Match != "" && !strings.Contains(strings.ToLower(prompt), strings.ToLower(r.Match)) {
+		return false
+	}
+	if r.regex != nil && !r.regex.MatchString(prompt) {
+		return false
+	}
+	if r.Role != "" && r.Role != string(role) {
+		return false
+	}
+	if r.Tool != "" && r.Tool != tool {
+		return false
+	}
+	return true
+}
+
+func messageText(msg chat.Message) string {
+	if msg.Content != "" || len(msg.MultiContent) == 0 {
+		return msg.Content
+	}
+
+	var parts []string
+	for _, part := range msg.MultiContent {
+		if part.Type == chat.MessagePartTypeText {
+			parts = append(parts, part.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// toolName returns the name of the tool whose result is the last message.
+func toolName(messages []chat.Message, last chat.Message) string {
+	if last.Role != chat.MessageRoleTool {
+		return ""
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		for _, call := range messages[i].ToolCalls {
+			if call.ID == last.ToolCallID {
+				return call.Function.Name
+			}
+		}
+	}
+	return ""
+}
+
+// arguments encodes the arguments of a tool call.
+func (c ToolCall) arguments() string {
+	if len(c.Arguments) == 0 {
+		return "{}"
+	}
+	args, err := json.Marshal(c.Arguments)
+	if err != nil {
+		return "{}"
+	}
+	return string(args)
+}
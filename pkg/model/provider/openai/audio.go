@@ -0,0 +1,103 @@
+package openai
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/openai/openai-go/v3"
+)
+
+// defaultVoice is the voice used when none is configured.
+const defaultVoice = "alloy"
+
+// Transcribe returns the text spoken in the given WAV audio, using the
+// transcription model of the client, such as whisper-1 or
+// gpt-4o-mini-transcribe.
+func (c *Client) Transcribe(ctx context.Context, wav []byte) (string, error) {
+	return c.transcribe(ctx, wav, "speech.wav", "audio/wav")
+}
+
+// TranscribeMedia returns the transcript of the given audio or video file,
+// using the transcription model of the client. The API accepts files of up
+// to 25MB in the flac, mp3, mp4, mpeg, m4a, ogg, wav and webm formats.
+func (c *Client) TranscribeMedia(ctx context.Context, data []byte, mimeType string) (string, error) {
+	ext, ok := transcriptionFormats[mimeType]
+	if !ok {
+		return "", fmt.Errorf("unsupported media type %s for OpenAI transcription", mimeType)
+	}
+	if len(data) > maxTranscriptionSize {
+		return "", fmt.Errorf("file too large for OpenAI transcription (%d bytes, max 25MB)", len(data))
+	}
+	return c.transcribe(ctx, data, "media"+ext, mimeType)
+}
+
+// maxTranscriptionSize is the largest file the transcription API accepts.
+const maxTranscriptionSize = 25 * 1024 * 1024
+
+// transcriptionFormats maps the media types the transcription API accepts to
+// their file extension.
+var transcriptionFormats = map[string]string{
+	"audio/mpeg":      ".mp3",
+	"audio/wav":       ".wav",
+	"audio/wave":      ".wav",
+	"audio/x-wav":     ".wav",
+	"audio/mp4":       ".m4a",
+	"audio/x-m4a":     ".m4a",
+	"audio/flac":      ".flac",
+	"audio/ogg":       ".ogg",
+	"application/ogg": ".ogg",
+	"audio/webm":      ".webm",
+	"video/webm":      ".webm",
+	"video/mp4":       ".mp4",
+	"video/mpeg":      ".mpeg",
+}
+
+func (c *Client) transcribe(ctx context.Context, data []byte, filename, mimeType string) (string, error) {
+	slog.Debug("Creating OpenAI transcription", "model", c.ModelConfig.Model, "audio_bytes", len(data), "mime_type", mimeType)
+
+	client, err := c.clientFn(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := client.Audio.Transcriptions.New(ctx, openai.AudioTranscriptionNewParams{
+		File:  openai.File(bytes.NewReader(data), filename, mimeType),
+		Model: c.requestModel(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to transcribe audio: %w", err)
+	}
+	return response.Text, nil
+}
+
+// Synthesize returns the given text spoken with the given voice as WAV audio,
+// using the speech model of the client, such as gpt-4o-mini-tts.
+func (c *Client) Synthesize(ctx context.Context, text, voice string) ([]byte, error) {
+	slog.Debug("Creating OpenAI speech", "model", c.ModelConfig.Model, "text_length", len(text))
+
+	client, err := c.clientFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Audio.Speech.New(ctx, openai.AudioSpeechNewParams{
+		Input:          text,
+		Model:          c.requestModel(),
+		Voice:          openai.AudioSpeechNewParamsVoice(cmp.Or(voice, defaultVoice)),
+		ResponseFormat: openai.AudioSpeechNewParamsResponseFormatWAV,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to synthesize speech: %w", err)
+	}
+	defer response.Body.Close()
+
+	wav, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading synthesized speech: %w", err)
+	}
+	return wav, nil
+}
@@ -0,0 +1,103 @@
+package openai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+func TestTranscribe(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/transcriptions", r.URL.Path)
+		assert.Equal(t, "whisper-1", r.FormValue("model"))
+
+		file, header, err := r.FormFile("file")
+		if assert.NoError(t, err) {
+			defer file.Close()
+			buf, _ := io.ReadAll(file)
+			assert.Equal(t, "RIFF", string(buf))
+			assert.Equal(t, "speech.wav", header.Filename)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"hello there"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "whisper-1")
+	text, err := client.Transcribe(t.Context(), []byte("RIFF"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", text)
+}
+
+func TestTranscribeMedia(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/transcriptions", r.URL.Path)
+
+		file, header, err := r.FormFile("file")
+		if assert.NoError(t, err) {
+			defer file.Close()
+			assert.Equal(t, "media.m4a", header.Filename)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"welcome to the meeting"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "gpt-4o-mini-transcribe")
+	text, err := client.TranscribeMedia(t.Context(), []byte("ftyp"), "audio/mp4")
+	require.NoError(t, err)
+	assert.Equal(t, "welcome to the meeting", text)
+
+	_, err = client.TranscribeMedia(t.Context(), []byte("RIFF"), "video/quicktime")
+	require.ErrorContains(t, err, "unsupported media type video/quicktime")
+}
+
+func TestSynthesize(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/speech", r.URL.Path)
+
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"input":           "hello there",
+			"model":           "gpt-4o-mini-tts",
+			"voice":           "alloy",
+			"response_format": "wav",
+		}, body)
+
+		w.Header().Set("Content-Type", "audio/wav")
+		_, _ = w.Write([]byte("RIFF"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "gpt-4o-mini-tts")
+	wav, err := client.Synthesize(t.Context(), "hello there", "")
+	require.NoError(t, err)
+	assert.Equal(t, "RIFF", string(wav))
+}
+
+func newTestClient(t *testing.T, baseURL, model string) *Client {
+	t.Helper()
+
+	cfg := &latest.ModelConfig{Provider: "openai", Model: model, BaseURL: baseURL}
+	env := environment.NewMapEnvProvider(map[string]string{"OPENAI_API_KEY": "test-key"})
+	client, err := NewClient(t.Context(), cfg, env)
+	require.NoError(t, err)
+	return client
+}
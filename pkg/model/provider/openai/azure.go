@@ -0,0 +1,84 @@
+package openai
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/openai/openai-go/v3/azure"
+	"github.com/openai/openai-go/v3/option"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+// defaultAzureAPIVersion is the Azure OpenAI API version used when
+// provider_opts.api_version isn't set.
+const defaultAzureAPIVersion = "2024-10-21"
+
+// azureClientOptions configures the OpenAI SDK for Azure OpenAI: requests are
+// routed to /openai/deployments/<deployment>/..., the api-version query
+// parameter is pinned, and authentication uses either an API key (api-key
+// header) or Entra ID through DefaultAzureCredential when no key is set.
+func azureClientOptions(ctx context.Context, cfg *latest.ModelConfig, env environment.Provider) ([]option.RequestOption, error) {
+	endpoint, _ := splitAzureBaseURL(cfg.BaseURL)
+	if endpoint == "" {
+		return nil, errors.New("azure provider requires base_url to be set to the resource endpoint, e.g. https://<resource>.openai.azure.com")
+	}
+
+	apiVersion, _ := cfg.ProviderOpts["api_version"].(string)
+	apiVersion = cmp.Or(apiVersion, defaultAzureAPIVersion)
+
+	clientOptions := []option.RequestOption{
+		// Never forward an OPENAI_API_KEY picked up from the environment
+		option.WithHeaderDel("authorization"),
+		azure.WithEndpoint(endpoint, apiVersion),
+	}
+
+	var apiKey string
+	if cfg.TokenKey != "" {
+		apiKey, _ = env.Get(ctx, cfg.TokenKey)
+	}
+	if apiKey != "" {
+		slog.Debug("Azure OpenAI using API key authentication", "endpoint", endpoint, "api_version", apiVersion)
+		return append(clientOptions, azure.WithAPIKey(apiKey)), nil
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("azure provider: no API key found in %s and Entra ID credentials are unavailable: %w", cmp.Or(cfg.TokenKey, "token_key"), err)
+	}
+	slog.Debug("Azure OpenAI using Entra ID authentication", "endpoint", endpoint, "api_version", apiVersion)
+	return append(clientOptions, azure.WithTokenCredential(cred)), nil
+}
+
+// splitAzureBaseURL splits a base URL into the resource endpoint and, for
+// deployment-shaped URLs (https://<resource>.openai.azure.com/openai/deployments/<name>),
+// the deployment name.
+func splitAzureBaseURL(baseURL string) (endpoint, deployment string) {
+	endpoint, rest, found := strings.Cut(strings.TrimSuffix(baseURL, "/"), "/openai/deployments/")
+	if !found {
+		return strings.TrimSuffix(endpoint, "/openai"), ""
+	}
+	deployment, _, _ = strings.Cut(rest, "/")
+	return endpoint, deployment
+}
+
+// requestModel returns the model name sent in API requests. Azure OpenAI
+// routes requests by deployment name, which can differ from the model name.
+func (c *Client) requestModel() string {
+	if c.ModelConfig.Provider != "azure" {
+		return c.ModelConfig.Model
+	}
+	if deployment, _ := c.ModelConfig.ProviderOpts["deployment"].(string); deployment != "" {
+		return deployment
+	}
+	if _, deployment := splitAzureBaseURL(c.ModelConfig.BaseURL); deployment != "" {
+		return deployment
+	}
+	return c.ModelConfig.Model
+}
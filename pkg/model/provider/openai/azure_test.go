@@ -0,0 +1,109 @@
+package openai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+func TestAzure_DeploymentRoutingAndAPIKey(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu  sync.Mutex
+		req *http.Request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		req = r.Clone(r.Context())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	cfg := &latest.ModelConfig{
+		Provider: "azure",
+		Model:    "gpt-4o",
+		BaseURL:  server.URL,
+		TokenKey: "AZURE_API_KEY",
+		ProviderOpts: map[string]any{
+			"deployment":  "prod-gpt4o",
+			"api_version": "2025-01-01-preview",
+		},
+	}
+	env := environment.NewMapEnvProvider(map[string]string{"AZURE_API_KEY": "azure-key"})
+
+	client, err := NewClient(t.Context(), cfg, env)
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(t.Context(), []chat.Message{{Role: chat.MessageRoleUser, Content: "hi"}}, nil)
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	stream.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotNil(t, req)
+	assert.Equal(t, "/openai/deployments/prod-gpt4o/chat/completions", req.URL.Path)
+	assert.Equal(t, "2025-01-01-preview", req.URL.Query().Get("api-version"))
+	assert.Equal(t, "azure-key", req.Header.Get("Api-Key"))
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
+
+func TestAzure_RequiresBaseURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.ModelConfig{Provider: "azure", Model: "gpt-4o", TokenKey: "AZURE_API_KEY"}
+	env := environment.NewMapEnvProvider(map[string]string{"AZURE_API_KEY": "azure-key"})
+
+	_, err := NewClient(t.Context(), cfg, env)
+	require.ErrorContains(t, err, "azure provider requires base_url")
+}
+
+func TestSplitAzureBaseURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		baseURL    string
+		endpoint   string
+		deployment string
+	}{
+		{"https://res.openai.azure.com", "https://res.openai.azure.com", ""},
+		{"https://res.openai.azure.com/", "https://res.openai.azure.com", ""},
+		{"https://res.openai.azure.com/openai", "https://res.openai.azure.com", ""},
+		{"https://res.openai.azure.com/openai/deployments/gpt-4o", "https://res.openai.azure.com", "gpt-4o"},
+		{"https://res.openai.azure.com/openai/deployments/gpt-4o/", "https://res.openai.azure.com", "gpt-4o"},
+	}
+	for _, tt := range tests {
+		endpoint, deployment := splitAzureBaseURL(tt.baseURL)
+		assert.Equal(t, tt.endpoint, endpoint, tt.baseURL)
+		assert.Equal(t, tt.deployment, deployment, tt.baseURL)
+	}
+}
+
+func TestAzure_RequestModel(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{}
+	client.ModelConfig = latest.ModelConfig{Provider: "azure", Model: "gpt-4o", BaseURL: "https://res.openai.azure.com/openai/deployments/legacy"}
+	assert.Equal(t, "legacy", client.requestModel())
+
+	client.ModelConfig.ProviderOpts = map[string]any{"deployment": "prod"}
+	assert.Equal(t, "prod", client.requestModel())
+
+	client.ModelConfig = latest.ModelConfig{Provider: "openai", Model: "gpt-4o", ProviderOpts: map[string]any{"deployment": "ignored"}}
+	assert.Equal(t, "gpt-4o", client.requestModel())
+}
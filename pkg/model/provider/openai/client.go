@@ -51,14 +51,21 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, env environment.Pro
 	if gateway := globalOptions.Gateway(); gateway == "" {
 		var clientOptions []option.RequestOption
 
-		if cfg.TokenKey != "" {
+		switch {
+		case cfg.Provider == "azure":
+			azureOptions, err := azureClientOptions(ctx, cfg, env)
+			if err != nil {
+				return nil, err
+			}
+			clientOptions = append(clientOptions, azureOptions...)
+		case cfg.TokenKey != "":
 			// Explicit token_key configured - use that env var
 			authToken, _ := env.Get(ctx, cfg.TokenKey)
 			if authToken == "" {
 				return nil, fmt.Errorf("%s environment variable is required", cfg.TokenKey)
 			}
 			clientOptions = append(clientOptions, option.WithAPIKey(authToken))
-		} else if isCustomProvider(cfg) {
+		case isCustomProvider(cfg):
 			// Custom provider (has api_type in ProviderOpts) without token_key - no auth
 			slog.Debug("Custom provider with no token_key, sending requests without authentication",
 				"provider", cfg.Provider, "base_url", cfg.BaseURL)
@@ -66,22 +73,7 @@ func NewClient(ctx context.Context, cfg *latest.ModelConfig, env environment.Pro
 		}
 		// Otherwise let the OpenAI SDK use its default behavior (OPENAI_API_KEY from env)
 
-		if cfg.Provider == "azure" {
-			// Azure configuration
-			if cfg.BaseURL != "" {
-				clientOptions = append(clientOptions, option.WithBaseURL(cfg.BaseURL))
-			}
-
-			// Azure API version from provider opts
-			if cfg.ProviderOpts != nil {
-				if apiVersion, exists := cfg.ProviderOpts["api_version"]; exists {
-					slog.Debug("Setting API version", "api_version", apiVersion)
-					if apiVersionStr, ok := apiVersion.(string); ok {
-						clientOptions = append(clientOptions, option.WithQueryAdd("api-version", apiVersionStr))
-					}
-				}
-			}
-		} else if cfg.BaseURL != "" {
+		if cfg.BaseURL != "" && cfg.Provider != "azure" {
 			clientOptions = append(clientOptions, option.WithBaseURL(cfg.BaseURL))
 		}
 
@@ -194,7 +186,7 @@ func (c *Client) CreateChatCompletionStream(
 	trackUsage := c.ModelConfig.TrackUsage == nil || *c.ModelConfig.TrackUsage
 
 	params := openai.ChatCompletionNewParams{
-		Model:    c.ModelConfig.Model,
+		Model:    c.requestModel(),
 		Messages: convertMessages(messages),
 		StreamOptions: openai.ChatCompletionStreamOptionsParam{
 			IncludeUsage: openai.Bool(trackUsage),
@@ -242,7 +234,7 @@ func (c *Client) CreateChatCompletionStream(
 
 			slog.Debug("Added tool to OpenAI request", "tool_name", tool.Name)
 		}
-		params.Tools = toolsParam
+		params.Tools = append(params.Tools, toolsParam...)
 
 		if c.ModelConfig.ParallelToolCalls != nil {
 			params.ParallelToolCalls = openai.Bool(*c.ModelConfig.ParallelToolCalls)
@@ -314,7 +306,7 @@ func (c *Client) CreateResponseStream(
 	input := convertMessagesToResponseInput(messages)
 
 	params := responses.ResponseNewParams{
-		Model: c.ModelConfig.Model,
+		Model: c.requestModel(),
 	}
 	params.Input.OfInputItemList = input
 
@@ -342,6 +334,16 @@ func (c *Client) CreateResponseStream(
 		slog.Debug("OpenAI responses request configured with max output tokens", "max_output_tokens", maxTokens)
 	}
 
+	builtinTools, err := getBuiltinResponseTools(&c.ModelConfig)
+	if err != nil {
+		slog.Error("OpenAI responses request using builtin_tools failed", "error", err)
+		return nil, err
+	}
+	if len(builtinTools) > 0 {
+		slog.Debug("Adding built-in tools to OpenAI responses request", "tool_count", len(builtinTools))
+		params.Tools = builtinTools
+	}
+
 	if len(requestTools) > 0 {
 		slog.Debug("Adding tools to OpenAI responses request", "tool_count", len(requestTools))
 		toolsParam := make([]responses.ToolUnionParam, len(requestTools))
@@ -363,7 +365,7 @@ func (c *Client) CreateResponseStream(
 
 			slog.Debug("Added tool to OpenAI responses request", "tool_name", tool.Name)
 		}
-		params.Tools = toolsParam
+		params.Tools = append(params.Tools, toolsParam...)
 
 		if c.ModelConfig.ParallelToolCalls != nil {
 			params.ParallelToolCalls = param.NewOpt(*c.ModelConfig.ParallelToolCalls)
@@ -389,6 +391,14 @@ func (c *Client) CreateResponseStream(
 			slog.Debug("OpenAI responses request using thinking_budget", "reasoning_effort", effort)
 		}
 		slog.Debug("OpenAI responses request configured with reasoning summary", "model", c.ModelConfig.Model, "summary", "detailed")
+
+		// Stateless mode: the response is not stored server-side, so reasoning
+		// has to come back encrypted and be replayed on the next request.
+		if useEncryptedReasoning(&c.ModelConfig) {
+			params.Store = param.NewOpt(false)
+			params.Include = append(params.Include, responses.ResponseIncludableReasoningEncryptedContent)
+			slog.Debug("OpenAI responses request configured with encrypted reasoning", "model", c.ModelConfig.Model)
+		}
 	}
 
 	// Apply structured output configuration
@@ -471,6 +481,21 @@ func convertMessagesToResponseInput(messages []chat.Message) []responses.Respons
 			}
 
 		case chat.MessageRoleAssistant:
+			// Replay encrypted reasoning ahead of the output it produced
+			for _, reasoning := range msg.ReasoningItems {
+				summary := make([]responses.ResponseReasoningItemSummaryParam, 0, len(reasoning.Summary))
+				for _, text := range reasoning.Summary {
+					summary = append(summary, responses.ResponseReasoningItemSummaryParam{Text: text})
+				}
+				input = append(input, responses.ResponseInputItemUnionParam{
+					OfReasoning: &responses.ResponseReasoningItemParam{
+						ID:               reasoning.ID,
+						Summary:          summary,
+						EncryptedContent: param.NewOpt(reasoning.EncryptedContent),
+					},
+				})
+			}
+
 			if len(msg.ToolCalls) == 0 {
 				// Simple assistant message
 				item.OfMessage = &responses.EasyInputMessageParam{
@@ -636,7 +661,7 @@ func (c *Client) CreateBatchEmbedding(ctx context.Context, texts []string) (*bas
 		Input: openai.EmbeddingNewParamsInputUnion{
 			OfArrayOfStrings: texts,
 		},
-		Model: c.ModelConfig.Model,
+		Model: c.requestModel(),
 	}
 
 	response, err := client.Embeddings.New(ctx, params)
@@ -709,7 +734,7 @@ func (c *Client) Rerank(ctx context.Context, query string, documents []types.Doc
 	systemPrompt := prompts.BuildRerankSystemPrompt(documents, criteria, c.ModelConfig.ProviderOpts, jsonFormatInstruction)
 
 	params := openai.ChatCompletionNewParams{
-		Model: c.ModelConfig.Model,
+		Model: c.requestModel(),
 		Messages: []openai.ChatCompletionMessageParamUnion{
 			openai.SystemMessage(systemPrompt),
 			openai.UserMessage(userPrompt),
@@ -882,6 +907,56 @@ func getAPIType(cfg *latest.ModelConfig) string {
 	return ""
 }
 
+// useEncryptedReasoning reports whether provider_opts.encrypted_reasoning is set.
+// When enabled, Responses API requests are not stored by OpenAI and the
+// encrypted reasoning items are carried in the conversation history instead.
+func useEncryptedReasoning(cfg *latest.ModelConfig) bool {
+	if cfg == nil || cfg.ProviderOpts == nil {
+		return false
+	}
+	enabled, _ := cfg.ProviderOpts["encrypted_reasoning"].(bool)
+	return enabled
+}
+
+// getBuiltinResponseTools returns the OpenAI-hosted tools listed in
+// provider_opts.builtin_tools. Only the Responses API supports them.
+func getBuiltinResponseTools(cfg *latest.ModelConfig) ([]responses.ToolUnionParam, error) {
+	if cfg == nil || cfg.ProviderOpts == nil {
+		return nil, nil
+	}
+
+	var names []string
+	switch v := cfg.ProviderOpts["builtin_tools"].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		names = v
+	case []any:
+		for _, name := range v {
+			s, ok := name.(string)
+			if !ok {
+				return nil, fmt.Errorf("builtin_tools must be a list of strings, got %T", name)
+			}
+			names = append(names, s)
+		}
+	default:
+		return nil, fmt.Errorf("builtin_tools must be a list of strings, got %T", v)
+	}
+
+	builtinTools := make([]responses.ToolUnionParam, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "web_search":
+			builtinTools = append(builtinTools, responses.ToolUnionParam{
+				OfWebSearch: &responses.WebSearchToolParam{Type: responses.WebSearchToolTypeWebSearch},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported OpenAI built-in tool: %q (supported: web_search)", name)
+		}
+	}
+	return builtinTools, nil
+}
+
 // isCustomProvider returns true if the config represents a custom provider
 // (defined in the providers: section). Custom providers have api_type set in ProviderOpts.
 func isCustomProvider(cfg *latest.ModelConfig) bool {
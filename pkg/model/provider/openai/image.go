@@ -0,0 +1,76 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/openai/openai-go/v3"
+
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+)
+
+// GenerateImage generates an image from a text prompt with the image model of
+// the client, such as gpt-image-1 or dall-e-3.
+func (c *Client) GenerateImage(ctx context.Context, prompt string, opts base.ImageGenerationOptions) (*base.ImageGenerationResult, error) {
+	slog.Debug("Creating OpenAI image", "model", c.ModelConfig.Model, "prompt_length", len(prompt), "aspect_ratio", opts.AspectRatio)
+
+	client, err := c.clientFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	model := c.requestModel()
+	params := openai.ImageGenerateParams{
+		Prompt: prompt,
+		Model:  model,
+		Size:   imageSize(model, opts.AspectRatio),
+	}
+	// gpt-image models always return base64 data and reject response_format.
+	if strings.HasPrefix(model, "dall-e") {
+		params.ResponseFormat = openai.ImageGenerateParamsResponseFormatB64JSON
+	}
+
+	response, err := client.Images.Generate(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate image: %w", err)
+	}
+	if len(response.Data) == 0 || response.Data[0].B64JSON == "" {
+		return nil, errors.New("no image returned from OpenAI")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(response.Data[0].B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("decoding generated image: %w", err)
+	}
+	return &base.ImageGenerationResult{
+		Data:          data,
+		MimeType:      http.DetectContentType(data),
+		RevisedPrompt: response.Data[0].RevisedPrompt,
+	}, nil
+}
+
+// imageSize returns the size matching the aspect ratio for the model.
+// DALL·E 2 only generates square images.
+func imageSize(model, aspectRatio string) openai.ImageGenerateParamsSize {
+	wide := "1536x1024"
+	if model == "dall-e-3" {
+		wide = "1792x1024"
+	}
+
+	switch {
+	case aspectRatio == "" || model == "dall-e-2":
+		return ""
+	case aspectRatio == base.AspectRatioLandscape:
+		return openai.ImageGenerateParamsSize(wide)
+	case aspectRatio == base.AspectRatioPortrait:
+		width, height, _ := strings.Cut(wide, "x")
+		return openai.ImageGenerateParamsSize(height + "x" + width)
+	default:
+		return openai.ImageGenerateParamsSize1024x1024
+	}
+}
@@ -0,0 +1,50 @@
+package openai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+)
+
+func TestGenerateImage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/images/generations", r.URL.Path)
+
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"prompt": "a cat",
+			"model":  "gpt-image-1",
+			"size":   "1536x1024",
+		}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		// base64 of the PNG signature
+		_, _ = w.Write([]byte(`{"created":1,"data":[{"b64_json":"iVBORw0KGgo="}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "gpt-image-1")
+	image, err := client.GenerateImage(t.Context(), "a cat", base.ImageGenerationOptions{AspectRatio: base.AspectRatioLandscape})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", image.MimeType)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n"), image.Data)
+}
+
+func TestImageSize(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, imageSize("gpt-image-1", ""))
+	assert.Equal(t, "1024x1024", string(imageSize("gpt-image-1", base.AspectRatioSquare)))
+	assert.Equal(t, "1024x1536", string(imageSize("gpt-image-1", base.AspectRatioPortrait)))
+	assert.Equal(t, "1792x1024", string(imageSize("dall-e-3", base.AspectRatioLandscape)))
+	assert.Empty(t, imageSize("dall-e-2", base.AspectRatioLandscape))
+}
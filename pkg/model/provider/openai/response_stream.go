@@ -197,6 +197,10 @@ func (a *ResponseStreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 	case "response.reasoning_summary_part.added", "response.reasoning_summary_part.done":
 		slog.Debug("Reasoning summary part event", "type", event.Type, "item_id", event.ItemID)
 
+	case "response.web_search_call.in_progress", "response.web_search_call.searching", "response.web_search_call.completed":
+		// Built-in web search runs server-side, results flow into the output text
+		slog.Debug("Web search call event", "type", event.Type, "item_id", event.ItemID)
+
 	case "response.output_item.done":
 		// Tool call or message item is complete
 		slog.Debug("Output item done", "item_id", event.ItemID, "type", event.Item.Type)
@@ -215,6 +219,22 @@ func (a *ResponseStreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 				}
 			}
 		}
+		// Keep encrypted reasoning so it can be replayed on the next request
+		if event.Item.Type == "reasoning" && event.Item.EncryptedContent != "" {
+			item := chat.ReasoningItem{
+				ID:               event.Item.ID,
+				EncryptedContent: event.Item.EncryptedContent,
+			}
+			for _, summary := range event.Item.Summary {
+				item.Summary = append(item.Summary, summary.Text)
+			}
+			response.Choices = append(response.Choices, chat.MessageStreamChoice{
+				Delta: chat.MessageDelta{
+					ReasoningItems: []chat.ReasoningItem{item},
+					Role:           "assistant",
+				},
+			})
+		}
 
 	case "response.done", "response.completed":
 		slog.Info("Response done received", "event_type", event.Type)
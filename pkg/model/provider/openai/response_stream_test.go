@@ -0,0 +1,175 @@
+package openai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// writeResponsesSSE writes a Responses API stream with an encrypted reasoning
+// item followed by a text answer.
+func writeResponsesSSE(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	flusher := w.(http.Flusher)
+
+	events := []map[string]any{
+		{
+			"type": "response.reasoning_summary_text.delta", "item_id": "rs_1", "delta": "Thinking about it",
+		},
+		{
+			"type": "response.output_item.done", "output_index": 0,
+			"item": map[string]any{
+				"type": "reasoning", "id": "rs_1", "encrypted_content": "gAAAA-secret",
+				"summary": []map[string]any{{"type": "summary_text", "text": "Thinking about it"}},
+			},
+		},
+		{
+			"type": "response.output_text.delta", "item_id": "msg_1", "delta": "Hello",
+		},
+		{
+			"type": "response.completed",
+			"response": map[string]any{
+				"id": "resp_1", "output": []map[string]any{},
+				"usage": map[string]any{"input_tokens": 5, "output_tokens": 3, "total_tokens": 8},
+			},
+		},
+	}
+	for _, event := range events {
+		data, _ := json.Marshal(event)
+		_, _ = w.Write([]byte("event: " + event["type"].(string) + "\ndata: " + string(data) + "\n\n"))
+	}
+	flusher.Flush()
+}
+
+func TestResponseStream_EncryptedReasoningAndBuiltinTools(t *testing.T) {
+	t.Parallel()
+
+	var (
+		body map[string]any
+		mu   sync.Mutex
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		_ = json.Unmarshal(data, &body)
+		mu.Unlock()
+		writeResponsesSSE(w)
+	}))
+	defer server.Close()
+
+	cfg := &latest.ModelConfig{
+		Provider: "openai",
+		Model:    "gpt-5-mini",
+		BaseURL:  server.URL,
+		TokenKey: "OPENAI_API_KEY",
+		ProviderOpts: map[string]any{
+			"encrypted_reasoning": true,
+			"builtin_tools":       []any{"web_search"},
+		},
+	}
+	env := environment.NewMapEnvProvider(map[string]string{"OPENAI_API_KEY": "sk-test"})
+
+	client, err := NewClient(t.Context(), cfg, env)
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(t.Context(), []chat.Message{{Role: chat.MessageRoleUser, Content: "hi"}}, nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var (
+		reasoning      string
+		content        string
+		reasoningItems []chat.ReasoningItem
+	)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		for _, choice := range resp.Choices {
+			reasoning += choice.Delta.ReasoningContent
+			content += choice.Delta.Content
+			reasoningItems = append(reasoningItems, choice.Delta.ReasoningItems...)
+		}
+	}
+
+	assert.Equal(t, "Thinking about it", reasoning)
+	assert.Equal(t, "Hello", content)
+	assert.Equal(t, []chat.ReasoningItem{{
+		ID:               "rs_1",
+		EncryptedContent: "gAAAA-secret",
+		Summary:          []string{"Thinking about it"},
+	}}, reasoningItems)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, false, body["store"])
+	assert.Equal(t, []any{"reasoning.encrypted_content"}, body["include"])
+	assert.Equal(t, []any{map[string]any{"type": "web_search"}}, body["tools"])
+}
+
+func TestConvertMessagesToResponseInput_ReplaysReasoningItems(t *testing.T) {
+	t.Parallel()
+
+	messages := []chat.Message{
+		{Role: chat.MessageRoleUser, Content: "hi"},
+		{
+			Role: chat.MessageRoleAssistant,
+			ReasoningItems: []chat.ReasoningItem{{
+				ID:               "rs_1",
+				EncryptedContent: "gAAAA-secret",
+				Summary:          []string{"Thinking about it"},
+			}},
+			ToolCalls: []tools.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: tools.FunctionCall{Name: "search", Arguments: "{}"},
+			}},
+		},
+		{Role: chat.MessageRoleTool, ToolCallID: "call_1", Content: "result"},
+	}
+
+	input := convertMessagesToResponseInput(messages)
+	require.Len(t, input, 4)
+
+	reasoning := input[1].OfReasoning
+	require.NotNil(t, reasoning, "reasoning must be replayed before the function call")
+	assert.Equal(t, "rs_1", reasoning.ID)
+	assert.Equal(t, "gAAAA-secret", reasoning.EncryptedContent.Value)
+	require.Len(t, reasoning.Summary, 1)
+	assert.Equal(t, "Thinking about it", reasoning.Summary[0].Text)
+
+	require.NotNil(t, input[2].OfFunctionCall)
+	require.NotNil(t, input[3].OfFunctionCallOutput)
+}
+
+func TestGetBuiltinResponseTools(t *testing.T) {
+	t.Parallel()
+
+	builtinTools, err := getBuiltinResponseTools(&latest.ModelConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, builtinTools)
+
+	builtinTools, err = getBuiltinResponseTools(&latest.ModelConfig{ProviderOpts: map[string]any{"builtin_tools": []string{"web_search"}}})
+	require.NoError(t, err)
+	require.Len(t, builtinTools, 1)
+	assert.NotNil(t, builtinTools[0].OfWebSearch)
+
+	_, err = getBuiltinResponseTools(&latest.ModelConfig{ProviderOpts: map[string]any{"builtin_tools": []any{"image_generation"}}})
+	require.ErrorContains(t, err, "unsupported OpenAI built-in tool")
+
+	_, err = getBuiltinResponseTools(&latest.ModelConfig{ProviderOpts: map[string]any{"builtin_tools": "web_search"}})
+	require.ErrorContains(t, err, "must be a list of strings")
+}
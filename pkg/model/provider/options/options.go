@@ -11,6 +11,7 @@ type ModelOptions struct {
 	maxTokens        int64
 	providers        map[string]latest.ProviderConfig
 	thinking         *bool
+	pullProgress     func(model, status string)
 }
 
 func (c *ModelOptions) Gateway() string {
@@ -37,6 +38,12 @@ func (c *ModelOptions) Thinking() *bool {
 	return c.thinking
 }
 
+// PullProgress returns the callback that receives progress updates while a
+// local model is being pulled, or nil to use the interactive terminal.
+func (c *ModelOptions) PullProgress() func(model, status string) {
+	return c.pullProgress
+}
+
 type Opt func(*ModelOptions)
 
 func WithGateway(gateway string) Opt {
@@ -75,6 +82,12 @@ func WithThinking(enabled bool) Opt {
 	}
 }
 
+func WithPullProgress(progress func(model, status string)) Opt {
+	return func(cfg *ModelOptions) {
+		cfg.pullProgress = progress
+	}
+}
+
 // FromModelOptions converts a concrete ModelOptions value into a slice of
 // Opt configuration functions. Later Opts override earlier ones when applied.
 func FromModelOptions(m ModelOptions) []Opt {
@@ -97,5 +110,8 @@ func FromModelOptions(m ModelOptions) []Opt {
 	if m.thinking != nil {
 		out = append(out, WithThinking(*m.thinking))
 	}
+	if m.pullProgress != nil {
+		out = append(out, WithPullProgress(m.pullProgress))
+	}
 	return out
 }
@@ -5,20 +5,24 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+	"math"
 	"slices"
 	"strings"
 
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/environment"
+	"github.com/docker/docker-agent/pkg/model/provider/alloy"
 	"github.com/docker/docker-agent/pkg/model/provider/anthropic"
 	"github.com/docker/docker-agent/pkg/model/provider/base"
 	"github.com/docker/docker-agent/pkg/model/provider/bedrock"
 	"github.com/docker/docker-agent/pkg/model/provider/dmr"
 	"github.com/docker/docker-agent/pkg/model/provider/gemini"
+	"github.com/docker/docker-agent/pkg/model/provider/mock"
 	"github.com/docker/docker-agent/pkg/model/provider/openai"
 	"github.com/docker/docker-agent/pkg/model/provider/options"
 	"github.com/docker/docker-agent/pkg/model/provider/rulebased"
+	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/rag/types"
 	"github.com/docker/docker-agent/pkg/tools"
 )
@@ -38,6 +42,7 @@ var CoreProviders = []string{
 	"google",
 	"dmr",
 	"amazon-bedrock",
+	"mock",
 }
 
 // AllProviders returns all known provider names (core providers + aliases),
@@ -57,6 +62,20 @@ func IsKnownProvider(name string) bool {
 	return exists
 }
 
+// APIKeyEnvVar returns the environment variable holding the API key of a
+// provider, or "" for providers that don't use one.
+func APIKeyEnvVar(name string) string {
+	switch strings.ToLower(name) {
+	case "openai":
+		return "OPENAI_API_KEY"
+	case "anthropic":
+		return "ANTHROPIC_API_KEY"
+	case "google":
+		return "GOOGLE_API_KEY"
+	}
+	return Aliases[strings.ToLower(name)].TokenEnvVar
+}
+
 // CatalogProviders returns the list of provider names that should be shown in the model catalog.
 // This includes core providers and aliases that have a defined BaseURL (self-contained endpoints).
 // Aliases without a BaseURL (like azure) require user configuration and are excluded.
@@ -168,6 +187,58 @@ type RerankingProvider interface {
 	Rerank(ctx context.Context, query string, documents []types.Document, criteria string) ([]float64, error)
 }
 
+// TokenCounter defines the interface for providers that can count the input
+// tokens of a request with their API.
+type TokenCounter interface {
+	Provider
+	// CountTokens returns the number of input tokens the request would use.
+	CountTokens(ctx context.Context, messages []chat.Message, tools []tools.Tool) (int64, error)
+}
+
+// ResponseResumer defines the interface for providers whose models can
+// continue a response from a trailing assistant message, so that a response
+// cut short by a failure is resumed instead of started over.
+type ResponseResumer interface {
+	Provider
+	// CanResumeResponse reports whether the model, as configured, continues
+	// a trailing assistant message.
+	CanResumeResponse() bool
+}
+
+// SpeechToTextProvider defines the interface for providers that can
+// transcribe recorded speech.
+type SpeechToTextProvider interface {
+	Provider
+	// Transcribe returns the text spoken in the given WAV audio.
+	Transcribe(ctx context.Context, wav []byte) (string, error)
+}
+
+// MediaTranscriptionProvider defines the interface for providers that can
+// transcribe audio and video files.
+type MediaTranscriptionProvider interface {
+	Provider
+	// TranscribeMedia returns the transcript of the given audio or video
+	// file.
+	TranscribeMedia(ctx context.Context, data []byte, mimeType string) (string, error)
+}
+
+// TextToSpeechProvider defines the interface for providers that can
+// synthesize speech.
+type TextToSpeechProvider interface {
+	Provider
+	// Synthesize returns the given text spoken with the given voice, as WAV
+	// audio. An empty voice selects the provider's default voice.
+	Synthesize(ctx context.Context, text, voice string) ([]byte, error)
+}
+
+// ImageGenerationProvider defines the interface for providers that can
+// generate images.
+type ImageGenerationProvider interface {
+	Provider
+	// GenerateImage generates an image from a text prompt.
+	GenerateImage(ctx context.Context, prompt string, opts base.ImageGenerationOptions) (*base.ImageGenerationResult, error)
+}
+
 // New creates a new provider from a model config.
 // This is a convenience wrapper for NewWithModels with no models map.
 func New(ctx context.Context, cfg *latest.ModelConfig, env environment.Provider, opts ...options.Opt) (Provider, error) {
@@ -184,9 +255,64 @@ func NewWithModels(ctx context.Context, cfg *latest.ModelConfig, models map[stri
 		return createRuleBasedRouter(ctx, cfg, models, env, opts...)
 	}
 
+	// Alloys with a selection strategy pick one of their models per request
+	if cfg.Alloy != nil {
+		return createAlloy(ctx, cfg, models, env, opts...)
+	}
+
 	return createDirectProvider(ctx, cfg, env, opts...)
 }
 
+// createAlloy creates a provider that picks one of the alloy's models for
+// each request according to its strategy.
+func createAlloy(ctx context.Context, cfg *latest.ModelConfig, models map[string]latest.ModelConfig, env environment.Provider, opts ...options.Opt) (Provider, error) {
+	factory := func(ctx context.Context, modelSpec string, models map[string]latest.ModelConfig, env environment.Provider, factoryOpts ...options.Opt) (alloy.Provider, error) {
+		modelCfg, exists := models[modelSpec]
+		if !exists {
+			// Inline model spec (e.g., "openai/gpt-4o")
+			parsed, err := latest.ParseModelRef(modelSpec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid model spec %q: expected 'provider/model' format or a model reference", modelSpec)
+			}
+			modelCfg = parsed
+		}
+		// Prevent infinite recursion - alloys cannot be nested
+		if modelCfg.Provider == "" && strings.Contains(modelCfg.Model, ",") {
+			return nil, fmt.Errorf("model %q is an alloy and cannot be used in another alloy", modelSpec)
+		}
+		return NewWithModels(ctx, &modelCfg, models, env, factoryOpts...)
+	}
+
+	return alloy.NewClient(ctx, cfg, models, env, factory, lookupAlloyModelInfo, opts...)
+}
+
+// lookupAlloyModelInfo returns the pricing and capabilities of a model from
+// models.dev, or nil if the model is unknown.
+func lookupAlloyModelInfo(ctx context.Context, id string) *alloy.ModelInfo {
+	store, err := modelsdev.NewStore()
+	if err != nil {
+		slog.Debug("Models store unavailable for alloy model", "error", err)
+		return nil
+	}
+
+	m, err := store.GetModel(ctx, id)
+	if err != nil || m == nil {
+		slog.Debug("Alloy model not found in models.dev", "model_id", id, "error", err)
+		return nil
+	}
+
+	info := &alloy.ModelInfo{
+		Vision: slices.Contains(m.Modalities.Input, "image"),
+	}
+	if m.Cost != nil {
+		info.Cost = m.Cost.Input + m.Cost.Output
+	} else {
+		// Unknown price: rank after the models with a known price
+		info.Cost = math.Inf(1)
+	}
+	return info
+}
+
 // createRuleBasedRouter creates a rule-based routing provider.
 func createRuleBasedRouter(ctx context.Context, cfg *latest.ModelConfig, models map[string]latest.ModelConfig, env environment.Provider, opts ...options.Opt) (Provider, error) {
 	// Create a provider factory that can resolve model references
@@ -266,6 +392,9 @@ func createDirectProvider(ctx context.Context, cfg *latest.ModelConfig, env envi
 	case "amazon-bedrock":
 		return bedrock.NewClient(ctx, enhancedCfg, env, opts...)
 
+	case "mock":
+		return mock.NewClient(ctx, enhancedCfg, opts...)
+
 	default:
 		slog.Error("Unknown provider type", "type", providerType)
 		return nil, fmt.Errorf("unknown provider type: %s", providerType)
@@ -570,7 +699,7 @@ func isGeminiFlashModel(model string) bool {
 }
 
 // applyBedrockDefaults applies default configuration for Amazon Bedrock models.
-// Only applies to Claude models (anthropic.claude-* or global.anthropic.claude-*).
+// Only applies to Claude models (anthropic.claude-*, including cross-region profiles such as us.anthropic.claude-*).
 func applyBedrockDefaults(cfg *latest.ModelConfig) {
 	// Only apply defaults for Claude models on Bedrock
 	if !isBedrockClaudeModel(cfg.Model) {
@@ -602,8 +731,9 @@ func applyBedrockDefaults(cfg *latest.ModelConfig) {
 }
 
 // isBedrockClaudeModel returns true if the model ID is a Claude model on Bedrock.
-// Claude model IDs on Bedrock start with "anthropic.claude-" or "global.anthropic.claude-".
+// Claude model IDs on Bedrock start with "anthropic.claude-", optionally behind a
+// cross-region inference profile prefix such as "global." or "eu.".
 func isBedrockClaudeModel(model string) bool {
-	m := strings.ToLower(model)
-	return strings.HasPrefix(m, "anthropic.claude-") || strings.HasPrefix(m, "global.anthropic.claude-")
+	m := bedrock.BaseModelID(strings.ToLower(model))
+	return strings.HasPrefix(m, "anthropic.claude-")
 }
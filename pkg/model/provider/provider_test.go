@@ -106,3 +106,12 @@ func TestIsKnownProvider(t *testing.T) {
 	assert.False(t, IsKnownProvider("unknown"))
 	assert.False(t, IsKnownProvider(""))
 }
+
+func TestIsBedrockClaudeModel(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isBedrockClaudeModel("anthropic.claude-sonnet-4-5-20250929-v1:0"))
+	assert.True(t, isBedrockClaudeModel("global.anthropic.claude-sonnet-4-5-20250929-v1:0"))
+	assert.True(t, isBedrockClaudeModel("eu.anthropic.claude-sonnet-4-5-20250929-v1:0"))
+	assert.False(t, isBedrockClaudeModel("us.amazon.nova-pro-v1:0"))
+}
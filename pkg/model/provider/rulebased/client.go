@@ -168,7 +168,7 @@ func (c *Client) CreateChatCompletionStream(
 		"message_count", len(messages),
 	)
 
-	return provider.CreateChatCompletionStream(ctx, messages, availableTools)
+	return provider.CreateChatCompletionStream(ctx, messages, tools.ForProvider(availableTools, provider.BaseConfig().ModelConfig.Provider))
 }
 
 // selectProvider finds the best matching provider for the messages.
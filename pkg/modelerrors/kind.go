@@ -0,0 +1,212 @@
+package modelerrors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Kind is the kind of a model error, in terms users can act on, whatever
+// the provider that returned it.
+type Kind string
+
+// Kinds of model errors.
+const (
+	// KindContextLength is a conversation that doesn't fit in the context
+	// window of the model.
+	KindContextLength Kind = "context_length"
+	// KindAuthentication is a missing or invalid API key, or one that isn't
+	// allowed to use the model.
+	KindAuthentication Kind = "authentication"
+	// KindContentFilter is a request or a response blocked by the content
+	// filter of the provider.
+	KindContentFilter Kind = "content_filter"
+	// KindOverloaded is a provider that's overloaded or temporarily
+	// unavailable.
+	KindOverloaded Kind = "overloaded"
+	// KindRateLimit is a provider limiting the rate of the requests.
+	KindRateLimit Kind = "rate_limit"
+	// KindQuota is an account out of quota or credits.
+	KindQuota Kind = "quota"
+	// KindModelNotFound is a model the provider doesn't know.
+	KindModelNotFound Kind = "model_not_found"
+)
+
+// ProviderError is a model error classified by kind, with a message and a
+// suggested remediation for users instead of the raw error of the provider.
+type ProviderError struct {
+	Kind Kind
+	// Message describes the error for users.
+	Message string
+	// Remediation is what users can do about the error.
+	Remediation string
+	// Err is the original error.
+	Err error
+}
+
+func (e *ProviderError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// descriptions are the message and remediation of each kind.
+var descriptions = map[Kind]struct{ message, remediation string }{
+	KindContextLength: {
+		"The conversation has exceeded the model's context window.",
+		"Run /compact to summarize the conversation, remove large attachments, or switch to a model with a larger context window.",
+	},
+	KindAuthentication: {
+		"The provider rejected the credentials.",
+		"Check the API key of the provider (e.g. ANTHROPIC_API_KEY or OPENAI_API_KEY) and that it's allowed to use the model.",
+	},
+	KindContentFilter: {
+		"The provider's content filter blocked the request or the response.",
+		"Rephrase the request, or remove the attachments or tool results that trigger the filter.",
+	},
+	KindOverloaded: {
+		"The provider is overloaded or temporarily unavailable.",
+		"Try again in a few moments, switch model, or configure fallback models.",
+	},
+	KindRateLimit: {
+		"The provider is rate limiting the requests.",
+		"Wait a moment before trying again, switch model, or configure fallback models.",
+	},
+	KindQuota: {
+		"The provider account has run out of quota or credits.",
+		"Check the billing and usage limits of the provider account, or switch model.",
+	},
+	KindModelNotFound: {
+		"The provider doesn't know the model, or the account can't use it.",
+		"Check the model name in the agent configuration, or switch model.",
+	},
+}
+
+// Patterns of the kinds whose errors don't always have a distinct status
+// code. They're checked case-insensitively against the error messages.
+var (
+	quotaPatterns = []string{
+		"insufficient_quota",
+		"exceeded your current quota",
+		"quota exceeded",
+		"billing",
+		"credit balance",
+		"insufficient credits",
+	}
+	contentFilterPatterns = []string{
+		"content_filter",
+		"content filter",
+		"content management policy",
+		"content_policy_violation",
+		"responsible ai",
+		"blocked due to safety",
+		"prohibited_content",
+	}
+	authenticationPatterns = []string{
+		"authentication_error",
+		"invalid api key",
+		"invalid x-api-key",
+		"incorrect api key",
+		"invalid_api_key",
+		"api key not valid",
+		"permission_denied",
+		"unrecognizedclientexception",
+		"security token included in the request is invalid",
+	}
+	modelNotFoundPatterns = []string{
+		"model_not_found",
+		"model not found",
+		"unknown model",
+		"does not exist or you do not have access",
+	}
+	overloadedPatterns = []string{
+		"overloaded",
+		"service unavailable",
+	}
+	rateLimitPatterns = []string{
+		"rate limit",
+		"rate_limit",
+		"too many requests",
+		"throttl",
+	}
+)
+
+// Classify returns the ProviderError of a model error, or nil when its kind
+// isn't known. Canceled requests aren't classified.
+func Classify(err error) *ProviderError {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	if pe, ok := errors.AsType[*ProviderError](err); ok {
+		return pe
+	}
+
+	kind, ok := classifyKind(err)
+	if !ok {
+		return nil
+	}
+	d := descriptions[kind]
+	return &ProviderError{
+		Kind:        kind,
+		Message:     d.message,
+		Remediation: d.remediation,
+		Err:         err,
+	}
+}
+
+func classifyKind(err error) (Kind, bool) {
+	if IsContextOverflowError(err) {
+		return KindContextLength, true
+	}
+
+	msg := strings.ToLower(err.Error())
+	// Some providers report content filtering and exhausted quotas with the
+	// status codes of bad requests and rate limits.
+	switch {
+	case containsAny(msg, contentFilterPatterns):
+		return KindContentFilter, true
+	case containsAny(msg, quotaPatterns):
+		return KindQuota, true
+	}
+
+	statusCode := ExtractHTTPStatusCode(err)
+	if statusErr, ok := errors.AsType[*StatusError](err); ok {
+		statusCode = statusErr.StatusCode
+	}
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return KindAuthentication, true
+	case http.StatusPaymentRequired:
+		return KindQuota, true
+	case http.StatusNotFound:
+		return KindModelNotFound, true
+	case http.StatusTooManyRequests:
+		return KindRateLimit, true
+	case http.StatusServiceUnavailable, 529:
+		return KindOverloaded, true
+	}
+
+	switch {
+	case containsAny(msg, authenticationPatterns):
+		return KindAuthentication, true
+	case containsAny(msg, modelNotFoundPatterns):
+		return KindModelNotFound, true
+	case containsAny(msg, rateLimitPatterns):
+		return KindRateLimit, true
+	case containsAny(msg, overloadedPatterns):
+		return KindOverloaded, true
+	}
+	return "", false
+}
+
+func containsAny(s string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(s, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package modelerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"context overflow", &ContextOverflowError{Underlying: errors.New("prompt is too long")}, KindContextLength},
+		{"openai context length", errors.New(`POST "/v1/chat/completions": 400 Bad Request {"code":"context_length_exceeded"}`), KindContextLength},
+		{"anthropic invalid key", errors.New(`POST "/v1/messages": 401 Unauthorized {"type":"authentication_error","message":"invalid x-api-key"}`), KindAuthentication},
+		{"forbidden", &StatusError{StatusCode: 403, Err: errors.New("forbidden")}, KindAuthentication},
+		{"gemini invalid key", errors.New("Error 400, Message: API key not valid. Please pass a valid API key., Status: INVALID_ARGUMENT"), KindAuthentication},
+		{"azure content filter", errors.New(`POST "/chat/completions": 400 Bad Request {"code":"content_filter"}`), KindContentFilter},
+		{"anthropic overloaded", errors.New(`POST "/v1/messages": 529 {"type":"overloaded_error"}`), KindOverloaded},
+		{"service unavailable", &StatusError{StatusCode: 503, Err: errors.New("unavailable")}, KindOverloaded},
+		{"rate limit", &StatusError{StatusCode: 429, Err: errors.New("too many requests")}, KindRateLimit},
+		{"bedrock throttling", errors.New("ThrottlingException: Rate exceeded"), KindRateLimit},
+		{"openai quota", errors.New(`POST "/v1/chat/completions": 429 Too Many Requests {"code":"insufficient_quota"}`), KindQuota},
+		{"anthropic credits", errors.New(`400 Bad Request: Your credit balance is too low`), KindQuota},
+		{"model not found", errors.New(`POST "/v1/chat/completions": 404 Not Found {"code":"model_not_found"}`), KindModelNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pe := Classify(fmt.Errorf("all models failed: %w", tt.err))
+			require.NotNil(t, pe)
+			assert.Equal(t, tt.want, pe.Kind)
+			assert.NotEmpty(t, pe.Message)
+			assert.NotEmpty(t, pe.Remediation)
+			assert.ErrorIs(t, pe, tt.err)
+		})
+	}
+}
+
+func TestClassify_Unknown(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, Classify(nil))
+	assert.Nil(t, Classify(context.Canceled))
+	assert.Nil(t, Classify(errors.New("unexpected end of JSON input")))
+}
@@ -415,19 +415,33 @@ func ClassifyModelError(err error) (retryable, rateLimited bool, retryAfter time
 // CalculateBackoff returns the backoff duration for a given attempt (0-indexed).
 // Uses exponential backoff with jitter.
 func CalculateBackoff(attempt int) time.Duration {
+	return CalculateBackoffWithin(attempt, backoffBaseDelay, backoffMaxDelay)
+}
+
+// CalculateBackoffWithin returns the backoff duration for a given attempt
+// (0-indexed), starting at initial and capped at maxDelay. Zero values fall
+// back to the defaults.
+func CalculateBackoffWithin(attempt int, initial, maxDelay time.Duration) time.Duration {
 	if attempt < 0 {
 		attempt = 0
 	}
+	if initial <= 0 {
+		initial = backoffBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = backoffMaxDelay
+	}
+	maxDelay = max(maxDelay, initial)
 
 	// Calculate exponential delay
-	delay := float64(backoffBaseDelay)
+	delay := float64(initial)
 	for range attempt {
 		delay *= backoffFactor
 	}
 
 	// Cap at max delay
-	if delay > float64(backoffMaxDelay) {
-		delay = float64(backoffMaxDelay)
+	if delay > float64(maxDelay) {
+		delay = float64(maxDelay)
 	}
 
 	// Add jitter (±10%)
@@ -452,17 +466,15 @@ func SleepWithContext(ctx context.Context, d time.Duration) bool {
 }
 
 // FormatError returns a user-friendly error message for model errors.
-// Context overflow gets a dedicated actionable message; all other errors
+// Errors of a known Kind get their message and remediation; all other errors
 // pass through their original message.
 func FormatError(err error) string {
 	if err == nil {
 		return ""
 	}
 
-	// Context overflow gets a dedicated, actionable message.
-	if _, ok := errors.AsType[*ContextOverflowError](err); ok {
-		return "The conversation has exceeded the model's context window and automatic compaction is not enabled. " +
-			"Try running /compact to reduce the conversation size, or start a new session."
+	if pe := Classify(err); pe != nil {
+		return pe.Message + " " + pe.Remediation
 	}
 
 	return err.Error()
@@ -103,6 +103,28 @@ func TestCalculateBackoff(t *testing.T) {
 	})
 }
 
+func TestCalculateBackoffWithin(t *testing.T) {
+	t.Parallel()
+
+	backoff := CalculateBackoffWithin(1, time.Second, 30*time.Second)
+	assert.GreaterOrEqual(t, backoff, 1800*time.Millisecond)
+	assert.LessOrEqual(t, backoff, 2200*time.Millisecond)
+
+	backoff = CalculateBackoffWithin(10, time.Second, 5*time.Second)
+	assert.GreaterOrEqual(t, backoff, 4500*time.Millisecond)
+	assert.LessOrEqual(t, backoff, 5500*time.Millisecond)
+
+	// A cap below the initial delay doesn't shorten it
+	backoff = CalculateBackoffWithin(3, time.Second, time.Millisecond)
+	assert.GreaterOrEqual(t, backoff, 900*time.Millisecond)
+	assert.LessOrEqual(t, backoff, 1100*time.Millisecond)
+
+	// Zero values use the defaults
+	backoff = CalculateBackoffWithin(0, 0, 0)
+	assert.GreaterOrEqual(t, backoff, 180*time.Millisecond)
+	assert.LessOrEqual(t, backoff, 220*time.Millisecond)
+}
+
 func TestSleepWithContext(t *testing.T) {
 	t.Parallel()
 
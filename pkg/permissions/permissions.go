@@ -3,6 +3,7 @@
 package permissions
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strconv"
@@ -249,3 +250,30 @@ func matchGlob(pattern, value string) bool {
 	matched, err := filepath.Match(pattern, value)
 	return err == nil && matched
 }
+
+// shellMetacharacters are the characters that can chain, substitute or
+// redirect shell commands.
+const shellMetacharacters = ";&|$`()<>\n"
+
+// SuggestPattern returns the pattern offered to the user to always allow a
+// tool call like this one. For shell commands, it's the exact command, e.g.
+// "shell:cmd=git status", so that approving it doesn't approve other
+// commands starting the same way. Commands with shell metacharacters or glob
+// characters, or with a colon that patterns can't hold, get no pattern. When there's no pattern, it's the tool name.
+func SuggestPattern(toolName, arguments string) string {
+	if toolName != "shell" {
+		return toolName
+	}
+
+	var args struct {
+		Cmd string `json:"cmd"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return toolName
+	}
+	cmd := args.Cmd
+	if strings.TrimSpace(cmd) == "" || strings.ContainsAny(cmd, shellMetacharacters+"*?[\\\r:") {
+		return toolName
+	}
+	return toolName + ":cmd=" + cmd
+}
@@ -0,0 +1,287 @@
+// Package project discovers the per-project settings of docker agent, stored
+// in .cagent/project.yaml at the root of a project. They let `docker agent run`
+// without arguments start the project's agent with the project's approval
+// policy, tool permissions, env files, task list and memory store.
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/permissions"
+)
+
+const (
+	// Dir is the directory holding the settings and the data of a project.
+	Dir = ".cagent"
+	// File is the name of the settings file in Dir.
+	File = "project.yaml"
+)
+
+// Approval policies.
+const (
+	// ApprovalAsk asks before running tools, even if the user settings enable YOLO mode.
+	ApprovalAsk = "ask"
+	// ApprovalYOLO approves all tool calls, like --yolo.
+	ApprovalYOLO = "yolo"
+)
+
+// Settings are the settings of a project. Paths are relative to the root of
+// the project.
+type Settings struct {
+	// Agent is the agent run when none is given: a file, an alias, a
+	// built-in agent or a registry reference.
+	Agent string `yaml:"agent,omitempty"`
+	// Approval is the tool approval policy: "ask" or "yolo".
+	Approval string `yaml:"approval,omitempty"`
+	// Permissions are the tool permissions of the project, checked after
+	// the ones of the session and of the agent. The tool calls the user
+	// always allows are added to them.
+	Permissions *latest.PermissionsConfig `yaml:"permissions,omitempty"`
+	// EnvFiles are env files loaded, after the --env-from-file ones.
+	// Missing files are skipped.
+	EnvFiles []string `yaml:"env_files,omitempty"`
+	// TaskList is the ID of the task list used by the tasks toolsets that
+	// don't set a path. It's stored in .cagent/tasks/<id>.json.
+	TaskList string `yaml:"task_list,omitempty"`
+	// Memory is the database used by the memory toolsets that don't set a
+	// path, instead of the user's one.
+	Memory string `yaml:"memory,omitempty"`
+}
+
+// Project is a project with settings.
+type Project struct {
+	// Root is the absolute path of the directory holding .cagent.
+	Root string
+	Settings
+
+	// mu guards Permissions and approved, updated by AllowTool while tools
+	// run.
+	mu sync.Mutex
+	// trusted is set once the user trusts the project: until then, the
+	// settings of the repository can't auto-approve tool calls.
+	trusted bool
+	// approved are the patterns the user always allowed since the project
+	// was loaded, honored even if the project isn't trusted.
+	approved []string
+}
+
+// Find returns the project of dir: the closest directory, from dir up to the
+// root of the filesystem, with a .cagent/project.yaml file. It returns nil if
+// there is none.
+func Find(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		// ~/.cagent is the data directory, not a project.
+		if filepath.Join(dir, Dir) != paths.GetDataDir() {
+			p, err := Load(dir)
+			if err != nil || p != nil {
+				return p, err
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads the settings of the project rooted in root. It returns nil if
+// root has no .cagent/project.yaml file.
+func Load(root string) (*Project, error) {
+	path := filepath.Join(root, Dir, File)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Project{Root: root}
+	if err := yaml.UnmarshalWithOptions(data, &p.Settings, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	switch p.Approval {
+	case "", ApprovalAsk, ApprovalYOLO:
+	default:
+		return nil, fmt.Errorf("parsing %s: invalid approval %q, must be %q or %q", path, p.Approval, ApprovalAsk, ApprovalYOLO)
+	}
+	return p, nil
+}
+
+// AgentRef returns the reference of the project's agent. Agent files are
+// resolved relative to the root of the project.
+func (p *Project) AgentRef() string {
+	if p.Agent == "" || filepath.IsAbs(p.Agent) {
+		return p.Agent
+	}
+	if path := filepath.Join(p.Root, p.Agent); fileExists(path) {
+		return path
+	}
+	return p.Agent
+}
+
+// EnvFilePaths returns the absolute paths of the project's env files that exist.
+func (p *Project) EnvFilePaths() []string {
+	var files []string
+	for _, file := range p.EnvFiles {
+		if path := p.path(file); fileExists(path) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// TasksPath returns the file of the project's task list, or "" if the project
+// has none.
+func (p *Project) TasksPath() string {
+	if p.TaskList == "" {
+		return ""
+	}
+	return filepath.Join(p.Root, Dir, "tasks", filepath.Base(p.TaskList)+".json")
+}
+
+// MemoryPath returns the project's memory database, or "" if the project has none.
+func (p *Project) MemoryPath() string {
+	if p.Memory == "" {
+		return ""
+	}
+	return p.path(p.Memory)
+}
+
+// UntrustedSettings describes the settings of the project that auto-approve
+// tool calls, approval: yolo and permissions.allow. They're ignored until
+// the user trusts the project, since anyone can commit them to a repository.
+func (p *Project) UntrustedSettings() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var settings []string
+	if p.Approval == ApprovalYOLO {
+		settings = append(settings, "approval: "+ApprovalYOLO)
+	}
+	if p.Permissions != nil && len(p.Permissions.Allow) > 0 {
+		settings = append(settings, "permissions.allow: "+strings.Join(p.Permissions.Allow, ", "))
+	}
+	return settings
+}
+
+// Trust marks the project as trusted by the user, so that all its settings
+// are honored.
+func (p *Project) Trust() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.trusted = true
+}
+
+// ApprovalPolicy returns the tool approval policy of the project. YOLO mode
+// is only returned for a trusted project.
+func (p *Project) ApprovalPolicy() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Approval == ApprovalYOLO && !p.trusted {
+		return ""
+	}
+	return p.Approval
+}
+
+// PermissionsChecker returns the checker of the tool permissions of the
+// project, or nil if it has none. Unless the project is trusted, only the
+// patterns the user always allowed since it was loaded are allowed.
+func (p *Project) PermissionsChecker() *permissions.Checker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Permissions == nil {
+		return nil
+	}
+	if p.trusted {
+		return permissions.NewChecker(p.Permissions)
+	}
+	return permissions.NewChecker(&latest.PermissionsConfig{
+		Allow: p.approved,
+		Ask:   p.Permissions.Ask,
+		Deny:  p.Permissions.Deny,
+	})
+}
+
+// AllowTool adds a permission pattern to the tool calls the project always
+// allows, and saves it to .cagent/project.yaml. The file is created if the
+// project has none yet; its other settings and comments are kept.
+func (p *Project) AllowTool(pattern string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	path := filepath.Join(p.Root, Dir, File)
+	var settings Settings
+	comments := yaml.CommentMap{}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := yaml.UnmarshalWithOptions(data, &settings, yaml.Strict(), yaml.CommentToMap(comments)); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+
+	// The checkers already returned keep the previous permissions.
+	if !slices.Contains(p.approved, pattern) {
+		p.approved = append(slices.Clone(p.approved), pattern)
+	}
+	allowed := &latest.PermissionsConfig{}
+	if settings.Permissions != nil {
+		*allowed = *settings.Permissions
+	}
+	if slices.Contains(allowed.Allow, pattern) {
+		p.Permissions = allowed
+		return nil
+	}
+	allowed.Allow = append(slices.Clone(allowed.Allow), pattern)
+	settings.Permissions = allowed
+
+	data, err = yaml.MarshalWithOptions(settings, yaml.WithComment(comments), yaml.IndentSequence(true))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+
+	p.Permissions = allowed
+	return nil
+}
+
+func (p *Project) path(file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(p.Root, file)
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,152 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, Dir, File), `
+agent: agents/dev.yaml
+approval: yolo
+env_files: [.env, missing.env]
+task_list: backend
+memory: .cagent/memory.db
+`)
+	writeFile(t, filepath.Join(root, "agents", "dev.yaml"), "agents: {}")
+	writeFile(t, filepath.Join(root, ".env"), "KEY=value")
+	subDir := filepath.Join(root, "src", "pkg")
+	require.NoError(t, os.MkdirAll(subDir, 0o755))
+
+	p, err := Find(subDir)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	assert.Equal(t, root, p.Root)
+	assert.Equal(t, filepath.Join(root, "agents", "dev.yaml"), p.AgentRef())
+	assert.Equal(t, ApprovalYOLO, p.Approval)
+	assert.Equal(t, []string{filepath.Join(root, ".env")}, p.EnvFilePaths())
+	assert.Equal(t, filepath.Join(root, Dir, "tasks", "backend.json"), p.TasksPath())
+	assert.Equal(t, filepath.Join(root, Dir, "memory.db"), p.MemoryPath())
+}
+
+func TestFindNoProject(t *testing.T) {
+	t.Parallel()
+
+	p, err := Find(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestAgentRefNotAFile(t *testing.T) {
+	t.Parallel()
+
+	p := &Project{Root: t.TempDir(), Settings: Settings{Agent: "coder"}}
+	assert.Equal(t, "coder", p.AgentRef())
+
+	p = &Project{Root: t.TempDir()}
+	assert.Empty(t, p.AgentRef())
+	assert.Empty(t, p.TasksPath())
+	assert.Empty(t, p.MemoryPath())
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, Dir, File), "approval: sometimes")
+	_, err := Load(root)
+	require.ErrorContains(t, err, `invalid approval "sometimes"`)
+
+	writeFile(t, filepath.Join(root, Dir, File), "unknown: true")
+	_, err = Load(root)
+	require.Error(t, err)
+}
+
+func TestAllowTool(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, Dir, File), `# The agent of the team.
+agent: agents/dev.yaml
+permissions:
+  deny:
+    - shell:cmd=rm*
+`)
+	p, err := Load(root)
+	require.NoError(t, err)
+	before := p.PermissionsChecker()
+
+	require.NoError(t, p.AllowTool("shell:cmd=git*"))
+	require.NoError(t, p.AllowTool("shell:cmd=git*"))
+	require.NoError(t, p.AllowTool("write_file"))
+
+	assert.Equal(t, []string{"shell:cmd=git*", "write_file"}, p.PermissionsChecker().AllowPatterns())
+	assert.Empty(t, before.AllowPatterns())
+
+	saved, err := Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, "agents/dev.yaml", saved.Agent)
+	assert.Equal(t, []string{"shell:cmd=rm*"}, saved.Permissions.Deny)
+	assert.Equal(t, []string{"shell:cmd=git*", "write_file"}, saved.Permissions.Allow)
+
+	data, err := os.ReadFile(filepath.Join(root, Dir, File))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# The agent of the team.")
+}
+
+func TestAllowToolNewProject(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	p := &Project{Root: root}
+	assert.Nil(t, p.PermissionsChecker())
+	require.NoError(t, p.AllowTool("fetch"))
+
+	saved, err := Load(root)
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, []string{"fetch"}, saved.Permissions.Allow)
+}
+
+func TestUntrustedProject(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, Dir, File), `
+approval: yolo
+permissions:
+  allow:
+    - shell
+  deny:
+    - shell:cmd=rm*
+`)
+	p, err := Load(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"approval: yolo", "permissions.allow: shell"}, p.UntrustedSettings())
+	assert.Empty(t, p.ApprovalPolicy(), "an untrusted project can't enable YOLO mode")
+	assert.Empty(t, p.PermissionsChecker().AllowPatterns(), "an untrusted project can't allow tools")
+	assert.Equal(t, []string{"shell:cmd=rm*"}, p.PermissionsChecker().DenyPatterns())
+
+	// The tools the user always allows are allowed anyway.
+	require.NoError(t, p.AllowTool("fetch"))
+	assert.Equal(t, []string{"fetch"}, p.PermissionsChecker().AllowPatterns())
+
+	p.Trust()
+	assert.Equal(t, ApprovalYOLO, p.ApprovalPolicy())
+	assert.Equal(t, []string{"shell", "fetch"}, p.PermissionsChecker().AllowPatterns())
+}
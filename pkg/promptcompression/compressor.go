@@ -0,0 +1,199 @@
+// Package promptcompression shortens old tool outputs with a small model
+// before a conversation is sent to the model of an agent, to save tokens on
+// expensive providers.
+//
+// Like sessiontitle, it makes one-shot calls to the provider and is
+// independent of pkg/runtime.
+package promptcompression
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/tokens"
+)
+
+const (
+	systemPrompt = "You compress the outputs of tools for another AI agent. Rewrite the output you are given as concisely as possible. Keep every fact, identifier, file path, number, error message and code snippet the agent may still need; drop boilerplate, repetition and formatting. Return only the compressed output."
+
+	// Prefix tells the agent that a tool output was compressed.
+	Prefix = "[compressed tool output]\n"
+
+	// DefaultMinLength is the length, in characters, from which a tool
+	// output is compressed.
+	DefaultMinLength = 4000
+	// DefaultKeepRecent is the number of most recent tool outputs that are
+	// never compressed: the agent is most likely still working with them.
+	DefaultKeepRecent = 3
+
+	compressionTimeout = 60 * time.Second
+)
+
+// Compressor compresses the old tool outputs of conversations. Compressed
+// outputs are cached, so each output is compressed once.
+type Compressor struct {
+	model      provider.Provider
+	minLength  int
+	keepRecent int
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]string
+}
+
+// New creates a Compressor that compresses the tool outputs longer than
+// minLength characters, except the keepRecent most recent ones, with model.
+// Non-positive values use the defaults.
+func New(model provider.Provider, minLength, keepRecent int) *Compressor {
+	if minLength <= 0 {
+		minLength = DefaultMinLength
+	}
+	if keepRecent <= 0 {
+		keepRecent = DefaultKeepRecent
+	}
+	return &Compressor{
+		model:      model,
+		minLength:  minLength,
+		keepRecent: keepRecent,
+		cache:      make(map[[sha256.Size]byte]string),
+	}
+}
+
+// Result is the outcome of compressing a conversation.
+type Result struct {
+	// Messages is the conversation with compressed tool outputs.
+	Messages []chat.Message
+	// Compressed is the number of compressed tool outputs.
+	Compressed int
+	// OriginalTokens and CompressedTokens measure the compressed tool
+	// outputs before and after compression.
+	OriginalTokens   int64
+	CompressedTokens int64
+}
+
+// Compress returns a copy of messages in which the old tool outputs are
+// compressed. Tokens are measured with counter. Outputs that can't be
+// compressed are left as they are.
+func (c *Compressor) Compress(ctx context.Context, messages []chat.Message, counter tokens.Counter) Result {
+	result := Result{Messages: messages}
+
+	var candidates []int
+	for i := range messages {
+		if messages[i].Role == chat.MessageRoleTool && len(messages[i].MultiContent) == 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) <= c.keepRecent {
+		return result
+	}
+	candidates = candidates[:len(candidates)-c.keepRecent]
+
+	var original, compressed []chat.Message
+	for _, i := range candidates {
+		msg := &messages[i]
+		if len(msg.Content) < c.minLength {
+			continue
+		}
+		content, ok := c.compress(ctx, msg.Content)
+		if !ok {
+			continue
+		}
+
+		if result.Compressed == 0 {
+			result.Messages = make([]chat.Message, len(messages))
+			copy(result.Messages, messages)
+		}
+		result.Messages[i].Content = content
+		result.Compressed++
+		original = append(original, *msg)
+		compressed = append(compressed, result.Messages[i])
+	}
+
+	if result.Compressed > 0 {
+		result.OriginalTokens = tokens.Count(ctx, counter, original, nil)
+		result.CompressedTokens = tokens.Count(ctx, counter, compressed, nil)
+	}
+	return result
+}
+
+// compress returns the compressed version of a tool output, from the cache
+// if it was already compressed. It returns false if the output couldn't be
+// made shorter.
+func (c *Compressor) compress(ctx context.Context, content string) (string, bool) {
+	key := sha256.Sum256([]byte(content))
+
+	c.mu.Lock()
+	cached, ok := c.cache[key]
+	c.mu.Unlock()
+	if !ok {
+		var err error
+		cached, err = c.generate(ctx, content)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", false
+			}
+			slog.Warn("Failed to compress tool output", "model", c.model.ID(), "error", err)
+			// Don't retry on every turn.
+			cached = ""
+		} else {
+			cached = Prefix + cached
+		}
+
+		c.mu.Lock()
+		c.cache[key] = cached
+		c.mu.Unlock()
+	}
+
+	if cached == "" || len(cached) >= len(content) {
+		return "", false
+	}
+	return cached, true
+}
+
+func (c *Compressor) generate(ctx context.Context, content string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, compressionTimeout)
+	defer cancel()
+
+	model := provider.CloneWithOptions(ctx, c.model,
+		options.WithStructuredOutput(nil),
+		options.WithThinking(false),
+	)
+
+	stream, err := model.CreateChatCompletionStream(ctx, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: systemPrompt},
+		{Role: chat.MessageRoleUser, Content: content},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var out strings.Builder
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(response.Choices) > 0 {
+			out.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+
+	compressed := strings.TrimSpace(out.String())
+	if compressed == "" {
+		return "", fmt.Errorf("empty output from model %q", c.model.ID())
+	}
+	return compressed, nil
+}
@@ -0,0 +1,118 @@
+package promptcompression
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tokens"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+type mockProvider struct {
+	calls  int
+	output string
+	err    error
+}
+
+func (p *mockProvider) ID() string { return "mock/small" }
+
+func (p *mockProvider) CreateChatCompletionStream(context.Context, []chat.Message, []tools.Tool) (chat.MessageStream, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &mockStream{content: p.output}, nil
+}
+
+func (p *mockProvider) BaseConfig() base.Config { return base.Config{} }
+
+type mockStream struct {
+	content string
+	done    bool
+}
+
+func (s *mockStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.done {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.done = true
+	return chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.content}}},
+	}, nil
+}
+
+func (s *mockStream) Close() {}
+
+func conversation(outputs ...string) []chat.Message {
+	messages := []chat.Message{{Role: chat.MessageRoleUser, Content: "Fix the build"}}
+	for _, output := range outputs {
+		messages = append(messages, chat.Message{Role: chat.MessageRoleTool, ToolCallID: "call", Content: output})
+	}
+	return messages
+}
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("log line\n", 100)
+	model := &mockProvider{output: "build failed: missing import"}
+	c := New(model, 100, 1)
+
+	messages := conversation(long, "short", long, long)
+	res := c.Compress(t.Context(), messages, tokens.Heuristic)
+
+	require.Equal(t, 2, res.Compressed)
+	assert.Equal(t, Prefix+"build failed: missing import", res.Messages[1].Content)
+	assert.Equal(t, "short", res.Messages[2].Content)
+	assert.Equal(t, Prefix+"build failed: missing import", res.Messages[3].Content)
+	// The most recent output is kept.
+	assert.Equal(t, long, res.Messages[4].Content)
+	assert.Greater(t, res.OriginalTokens, res.CompressedTokens)
+
+	// The conversation isn't modified.
+	assert.Equal(t, long, messages[1].Content)
+
+	// Outputs are compressed once.
+	assert.Equal(t, 1, model.calls)
+	res = c.Compress(t.Context(), messages, tokens.Heuristic)
+	assert.Equal(t, 2, res.Compressed)
+	assert.Equal(t, 1, model.calls)
+}
+
+func TestCompressNothingToCompress(t *testing.T) {
+	t.Parallel()
+
+	model := &mockProvider{output: "compressed"}
+	c := New(model, 0, 0)
+
+	messages := conversation(strings.Repeat("x", DefaultMinLength), "short")
+	res := c.Compress(t.Context(), messages, tokens.Heuristic)
+
+	assert.Zero(t, res.Compressed)
+	assert.Equal(t, messages, res.Messages)
+	assert.Zero(t, model.calls)
+}
+
+func TestCompressKeepsOutputsThatCantBeCompressed(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 200)
+
+	failing := &mockProvider{err: errors.New("model unavailable")}
+	res := New(failing, 100, 1).Compress(t.Context(), conversation(long, long), tokens.Heuristic)
+	assert.Zero(t, res.Compressed)
+	assert.Equal(t, long, res.Messages[1].Content)
+
+	verbose := &mockProvider{output: strings.Repeat("y", 300)}
+	res = New(verbose, 100, 1).Compress(t.Context(), conversation(long, long), tokens.Heuristic)
+	assert.Zero(t, res.Compressed)
+	assert.Equal(t, long, res.Messages[1].Content)
+}
@@ -0,0 +1,92 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// Artifact is an agent fetched from a registry without being stored locally.
+type Artifact struct {
+	Reference   string
+	Digest      string
+	Annotations map[string]string
+	// Config is the agent configuration file.
+	Config []byte
+}
+
+// Fetch fetches an agent from a registry without storing it in the content
+// store, e.g. to inspect it before pulling it.
+func Fetch(ctx context.Context, registryRef string, opts ...crane.Option) (*Artifact, error) {
+	opts = append(opts, crane.WithContext(ctx))
+
+	ref, err := name.ParseReference(registryRef)
+	if err != nil {
+		return nil, fmt.Errorf("parsing registry reference %s: %w", registryRef, err)
+	}
+
+	img, err := crane.Pull(ref.String(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("pulling image from registry %s: %w", registryRef, err)
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("getting manifest from pulled image: %w", err)
+	}
+	if !hasCagentAnnotation(manifest.Annotations) {
+		return nil, fmt.Errorf("%s is not an agent: it wasn't created by `docker agent share push`", registryRef)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("getting image digest: %w", err)
+	}
+
+	// Agents are stored in the first layer
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("getting image layers: %w", err)
+	}
+	if len(layers) == 0 {
+		return nil, errors.New("agent artifact has no layers")
+	}
+	rc, err := layers[0].Uncompressed()
+	if err != nil {
+		return nil, fmt.Errorf("reading agent layer: %w", err)
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rc); err != nil {
+		return nil, fmt.Errorf("reading agent layer: %w", err)
+	}
+
+	return &Artifact{
+		Reference:   ref.String(),
+		Digest:      digest.String(),
+		Annotations: manifest.Annotations,
+		Config:      buf.Bytes(),
+	}, nil
+}
+
+// IsAgent reports whether a registry reference points to an agent, looking
+// only at its manifest.
+func IsAgent(ctx context.Context, registryRef string, opts ...crane.Option) bool {
+	opts = append(opts, crane.WithContext(ctx))
+
+	img, err := crane.Pull(registryRef, opts...)
+	if err != nil {
+		return false
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return false
+	}
+	return hasCagentAnnotation(manifest.Annotations)
+}
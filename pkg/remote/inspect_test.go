@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	agentYAML := []byte("agents:\n  root:\n    model: openai/gpt-4o\n")
+	img, err := mutate.AppendLayers(empty.Image, static.NewLayer(agentYAML, types.OCIUncompressedLayer))
+	require.NoError(t, err)
+
+	agent := mutate.Annotations(img, map[string]string{"io.docker.agent.version": "v1.0.0"}).(v1.Image)
+	require.NoError(t, crane.Push(agent, host+"/org/agent:latest"))
+	require.NoError(t, crane.Push(img, host+"/org/image:latest"))
+
+	artifact, err := Fetch(t.Context(), host+"/org/agent:latest")
+	require.NoError(t, err)
+	assert.Equal(t, agentYAML, artifact.Config)
+	assert.Equal(t, "v1.0.0", artifact.Annotations["io.docker.agent.version"])
+	assert.True(t, strings.HasPrefix(artifact.Digest, "sha256:"))
+	assert.True(t, IsAgent(t.Context(), host+"/org/agent:latest"))
+
+	_, err = Fetch(t.Context(), host+"/org/image:latest")
+	require.ErrorContains(t, err, "is not an agent")
+	assert.False(t, IsAgent(t.Context(), host+"/org/image:latest"))
+	assert.False(t, IsAgent(t.Context(), host+"/org/missing:latest"))
+}
@@ -0,0 +1,205 @@
+// Package runlog writes a structured, local log of agent runs.
+//
+// Each session gets its own JSONL file. Every line is an [Entry]: the runtime
+// events sent to the UI, the requests sent to model providers with their
+// responses, and the tool calls with their duration. Run logs never leave
+// the machine and can be replayed with `docker agent replay`.
+package runlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Entry types.
+const (
+	TypeEvent         = "event"
+	TypeModelRequest  = "model_request"
+	TypeModelResponse = "model_response"
+	TypeToolCall      = "tool_call"
+)
+
+// maxLineSize is the largest entry Read accepts. Model requests carry the
+// whole conversation so lines can be big.
+const maxLineSize = 64 * 1024 * 1024
+
+// Entry is a line of a run log.
+type Entry struct {
+	Time      time.Time       `json:"time"`
+	Type      string          `json:"type"`
+	SessionID string          `json:"session_id"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ModelRequest is a request sent to a model provider.
+type ModelRequest struct {
+	Agent    string         `json:"agent"`
+	Model    string         `json:"model"`
+	Messages []chat.Message `json:"messages"`
+	Tools    []string       `json:"tools,omitempty"`
+}
+
+// ModelResponse is what a model provider streamed back for a request.
+type ModelResponse struct {
+	Agent      string           `json:"agent"`
+	Model      string           `json:"model"`
+	DurationMs int64            `json:"duration_ms"`
+	Content    string           `json:"content,omitempty"`
+	Reasoning  string           `json:"reasoning,omitempty"`
+	ToolCalls  []tools.ToolCall `json:"tool_calls,omitempty"`
+	Usage      *chat.Usage      `json:"usage,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// ToolCall is a tool call and its result.
+type ToolCall struct {
+	Agent      string `json:"agent"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Output     string `json:"output"`
+	IsError    bool   `json:"is_error,omitempty"`
+}
+
+// Log writes run logs to a directory, one file per session.
+// A nil *Log records nothing.
+type Log struct {
+	dir string
+	now func() time.Time
+
+	mu    sync.Mutex
+	files map[string]*os.File
+}
+
+// New returns a Log writing to dir. Files are only created once something
+// is recorded.
+func New(dir string) *Log {
+	return &Log{
+		dir:   dir,
+		now:   time.Now,
+		files: make(map[string]*os.File),
+	}
+}
+
+// Dir returns the directory the run logs are written to.
+func (l *Log) Dir() string {
+	return l.dir
+}
+
+// Path returns the run log file of a session.
+func (l *Log) Path(sessionID string) string {
+	return filepath.Join(l.dir, sessionID+".jsonl")
+}
+
+// Record appends an entry to the run log of a session. Failing to record
+// never fails the run, so errors are returned for logging only.
+func (l *Log) Record(sessionID, entryType string, data any) error {
+	if l == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding %s entry: %w", entryType, err)
+	}
+	line, err := json.Marshal(Entry{
+		Time:      l.now(),
+		Type:      entryType,
+		SessionID: sessionID,
+		Data:      raw,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding %s entry: %w", entryType, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := l.file(sessionID)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func (l *Log) file(sessionID string) (*os.File, error) {
+	if f, ok := l.files[sessionID]; ok {
+		return f, nil
+	}
+
+	if err := os.MkdirAll(l.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating run log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.Path(sessionID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening run log: %w", err)
+	}
+	l.files[sessionID] = f
+	return f, nil
+}
+
+// Close closes all the run log files.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	for id, f := range l.files {
+		errs = append(errs, f.Close())
+		delete(l.files, id)
+	}
+	return errors.Join(errs...)
+}
+
+// Read reads all the entries of a run log.
+func Read(r io.Reader) ([]Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var entries []Entry
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ReadFile reads all the entries of a run log file.
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading run log %s: %w", path, err)
+	}
+	return entries, nil
+}
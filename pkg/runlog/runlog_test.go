@@ -0,0 +1,58 @@
+package runlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_RecordAndRead(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "runs")
+	l := New(dir)
+
+	require.NoError(t, l.Record("s1", TypeModelRequest, ModelRequest{Agent: "root", Model: "openai/gpt-5", Tools: []string{"shell"}}))
+	require.NoError(t, l.Record("s1", TypeToolCall, ToolCall{Agent: "root", ID: "1", Name: "shell", DurationMs: 12, Output: "ok"}))
+	require.NoError(t, l.Record("s2", TypeEvent, map[string]string{"type": "stream_started"}))
+	require.NoError(t, l.Close())
+
+	entries, err := ReadFile(l.Path("s1"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, TypeModelRequest, entries[0].Type)
+	assert.Equal(t, "s1", entries[0].SessionID)
+	assert.False(t, entries[0].Time.IsZero())
+
+	var call ToolCall
+	require.NoError(t, json.Unmarshal(entries[1].Data, &call))
+	assert.Equal(t, "shell", call.Name)
+	assert.Equal(t, int64(12), call.DurationMs)
+
+	entries, err = ReadFile(filepath.Join(dir, "s2.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, TypeEvent, entries[0].Type)
+}
+
+func TestLog_Nil(t *testing.T) {
+	t.Parallel()
+
+	var l *Log
+	require.NoError(t, l.Record("s1", TypeEvent, nil))
+	require.NoError(t, l.Close())
+}
+
+func TestReadFile_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "run.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"type\":\"event\"}\nnot json\n"), 0o600))
+
+	_, err := ReadFile(path)
+	require.ErrorContains(t, err, "line 2")
+}
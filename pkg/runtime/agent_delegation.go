@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/tools"
 	"github.com/docker/docker-agent/pkg/tools/builtin"
@@ -75,6 +76,12 @@ func (r *LocalRuntime) RunAgent(ctx context.Context, params agenttool.RunParams)
 		return &agenttool.RunResult{ErrMsg: fmt.Sprintf("agent %q not found: %s", params.AgentName, err)}
 	}
 
+	ctx, guard := r.delegationGuard(ctx)
+	ctx, errResult := guard.transfer(ctx, r.CurrentAgentName(), params.AgentName)
+	if errResult != nil {
+		return &agenttool.RunResult{ErrMsg: errResult.Output}
+	}
+
 	sess := params.ParentSession
 
 	// Background tasks run with tools pre-approved because there is no user present
@@ -91,13 +98,20 @@ func (r *LocalRuntime) RunAgent(ctx context.Context, params agenttool.RunParams)
 		session.WithMaxIterations(child.MaxIterations()),
 		session.WithTitle("Background agent task"),
 		session.WithToolsApproved(true),
+		session.WithSimulate(sess.Simulate),
 		session.WithThinking(sess.Thinking),
 		session.WithSendUserMessage(false),
 		session.WithParentID(sess.ID),
+		session.WithBlackboardOf(sess),
 		session.WithAgentName(params.AgentName),
+		session.WithTaskBudget(r.taskBudget(params.AgentName)),
 	)
 
+	ctx, cancel := withTaskTimeBudget(ctx, s)
+	defer cancel()
+
 	var errMsg string
+	var exceeded *TaskBudgetExceededEvent
 	events := r.RunStream(ctx, s)
 	for event := range events {
 		if ctx.Err() != nil {
@@ -108,6 +122,9 @@ func (r *LocalRuntime) RunAgent(ctx context.Context, params agenttool.RunParams)
 				params.OnContent(choice.Content)
 			}
 		}
+		if e, ok := event.(*TaskBudgetExceededEvent); ok {
+			exceeded = e
+		}
 		if errEvt, ok := event.(*ErrorEvent); ok {
 			errMsg = errEvt.Error
 			break
@@ -118,6 +135,15 @@ func (r *LocalRuntime) RunAgent(ctx context.Context, params agenttool.RunParams)
 	for range events {
 	}
 
+	if exceeded == nil && taskTimeBudgetExceeded(ctx) {
+		reason := fmt.Sprintf("the task ran for more than %s", s.TaskBudget.MaxTime.Duration)
+		exceeded = TaskBudgetExceeded(s.ID, params.AgentName, taskBudgetMaxTime, reason).(*TaskBudgetExceededEvent)
+	}
+	if exceeded != nil {
+		sess.AddSubSession(s)
+		return &agenttool.RunResult{ErrMsg: taskBudgetFailureResult(s, exceeded).Output}
+	}
+
 	if errMsg != "" {
 		return &agenttool.RunResult{ErrMsg: errMsg}
 	}
@@ -145,6 +171,12 @@ func (r *LocalRuntime) handleTaskTransfer(ctx context.Context, sess *session.Ses
 		return errResult, nil
 	}
 
+	ctx, guard := r.delegationGuard(ctx)
+	ctx, errResult := guard.transfer(ctx, a.Name(), params.Agent)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	ctx, span := r.startSpan(ctx, "runtime.task_transfer", trace.WithAttributes(
 		attribute.String("from.agent", a.Name()),
 		attribute.String("to.agent", params.Agent),
@@ -184,43 +216,108 @@ func (r *LocalRuntime) handleTaskTransfer(ctx context.Context, sess *session.Ses
 		return nil, err
 	}
 
+	systemMessage := buildTaskSystemMessage(params.Task, params.ExpectedOutput)
+	if a.TaskOutput() != nil {
+		systemMessage += taskOutputInstructions(a.TaskOutput())
+	}
+
 	s := session.New(
-		session.WithSystemMessage(buildTaskSystemMessage(params.Task, params.ExpectedOutput)),
+		session.WithSystemMessage(systemMessage),
 		session.WithImplicitUserMessage("Please proceed."),
 		session.WithMaxIterations(child.MaxIterations()),
 		session.WithTitle("Transferred task"),
 		session.WithToolsApproved(sess.ToolsApproved),
+		session.WithSimulate(sess.Simulate),
 		session.WithThinking(sess.Thinking),
 		session.WithSendUserMessage(false),
 		session.WithParentID(sess.ID),
+		session.WithBlackboardOf(sess),
+		session.WithOutputSchema(a.TaskOutput()),
+		session.WithTaskBudget(r.taskBudget(params.Agent)),
 	)
 
-	return r.runSubSession(ctx, sess, s, span, evts, a.Name())
+	res, err := r.runSubSession(ctx, sess, s, span, evts, a.Name())
+	if err != nil {
+		return res, err
+	}
+
+	if res.IsError {
+		return res, nil
+	}
+
+	// Structured results are returned as is, so that they can be parsed.
+	if s.OutputSchema != nil {
+		result, reason := checkTaskOutput(ctx, s, res.Output)
+		if reason != "" {
+			return tools.ResultError(fmt.Sprintf("The result of %s doesn't follow the task output schema: %s", params.Agent, reason)), nil
+		}
+		return tools.ResultSuccess(result), nil
+	}
+
+	if hasSubAgentTraceTool(a) {
+		res.Output += fmt.Sprintf("\n\n[%s can show how this answer was reached, with session_id %q]", builtin.ToolNameGetSubAgentTrace, s.ID)
+	}
+	return res, nil
+}
+
+// taskBudget returns the limits of the tasks delegated to the given agent, or
+// nil.
+func (r *LocalRuntime) taskBudget(agentName string) *latest.TaskBudget {
+	budget, ok := r.team.TaskBudget(agentName)
+	if !ok {
+		return nil
+	}
+	return &budget
 }
 
 // runSubSession runs a child session within the parent, forwarding events and
 // propagating state (tool approvals, thinking) back to the parent when done.
+// A child stopped at a limit of its task budget returns a failure.
 func (r *LocalRuntime) runSubSession(ctx context.Context, parent, child *session.Session, span trace.Span, evts chan Event, agentName string) (*tools.ToolCallResult, error) {
+	ctx, cancel := withTaskTimeBudget(ctx, child)
+	defer cancel()
+
+	var exceeded *TaskBudgetExceededEvent
 	for event := range r.RunStream(ctx, child) {
+		// The errors of a task stopped at its max_time are reported as a
+		// budget failure below.
+		if _, ok := event.(*ErrorEvent); ok && taskTimeBudgetExceeded(ctx) {
+			continue
+		}
 		evts <- event
+		if e, ok := event.(*TaskBudgetExceededEvent); ok {
+			exceeded = e
+		}
 		if errEvent, ok := event.(*ErrorEvent); ok {
 			span.RecordError(fmt.Errorf("%s", errEvent.Error))
 			span.SetStatus(codes.Error, "sub-session error")
 			return nil, fmt.Errorf("%s", errEvent.Error)
 		}
 	}
+	if exceeded == nil && taskTimeBudgetExceeded(ctx) {
+		reason := fmt.Sprintf("the task ran for more than %s", child.TaskBudget.MaxTime.Duration)
+		exceeded = TaskBudgetExceeded(child.ID, r.CurrentAgentName(), taskBudgetMaxTime, reason).(*TaskBudgetExceededEvent)
+		evts <- exceeded
+	}
 
 	parent.ToolsApproved = child.ToolsApproved
 	parent.Thinking = child.Thinking
+	parent.Simulate = child.Simulate
 
 	parent.AddSubSession(child)
 	evts <- SubSessionCompleted(parent.ID, child, agentName)
+	propagateArtifacts(parent, child, evts, agentName)
+
+	if exceeded != nil {
+		span.SetStatus(codes.Error, "sub-session budget exceeded")
+		return taskBudgetFailureResult(child, exceeded), nil
+	}
 
 	span.SetStatus(codes.Ok, "sub-session completed")
 	return tools.ResultSuccess(child.GetLastAssistantMessageContent()), nil
 }
 
-func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+func (r *LocalRuntime) handleHandoff(ctx context.Context, _ *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
 	var params builtin.HandoffArgs
 	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
@@ -237,6 +334,11 @@ func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, tool
 		return errResult, nil
 	}
 
+	_, guard := r.delegationGuard(ctx)
+	if errResult := guard.handoff(ca, params.Agent); errResult != nil {
+		return errResult, nil
+	}
+
 	next, err := r.team.Agent(params.Agent)
 	if err != nil {
 		return nil, err
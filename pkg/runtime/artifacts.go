@@ -0,0 +1,74 @@
+package runtime
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// registerArtifacts records the files returned by a tool as artifacts of the
+// session. Files that don't exist are skipped: the tool may have reported a
+// file it failed to write.
+func (r *LocalRuntime) registerArtifacts(sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, artifacts []tools.Artifact, events chan Event) {
+	for _, artifact := range artifacts {
+		path := artifact.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.artifactsBaseDir(sess), path)
+		}
+		path = filepath.Clean(path)
+
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			slog.Warn("Skipping artifact that is not a file", "tool", toolCall.Function.Name, "path", path, "error", err)
+			continue
+		}
+
+		mimeType := artifact.MimeType
+		if mimeType == "" {
+			mimeType = chat.DetectMimeType(path)
+		}
+
+		registered := sess.AddArtifact(session.Artifact{
+			ID:          uuid.New().String(),
+			Name:        filepath.Base(path),
+			Path:        path,
+			MimeType:    mimeType,
+			Size:        info.Size(),
+			Description: artifact.Description,
+			ToolName:    toolCall.Function.Name,
+			AgentName:   a.Name(),
+			CreatedAt:   time.Now(),
+		})
+		events <- ArtifactAdded(sess.ID, registered, a.Name())
+	}
+}
+
+// artifactsBaseDir is the directory relative artifact paths are resolved
+// against.
+func (r *LocalRuntime) artifactsBaseDir(sess *session.Session) string {
+	if sess.WorkingDir != "" {
+		return sess.WorkingDir
+	}
+	if r.workingDir != "" {
+		return r.workingDir
+	}
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// propagateArtifacts registers the artifacts of a completed sub-session with
+// its parent, so that the files produced by sub-agents are outputs of the
+// session the user started.
+func propagateArtifacts(parent, child *session.Session, events chan Event, agentName string) {
+	for _, artifact := range child.GetArtifacts() {
+		events <- ArtifactAdded(parent.ID, parent.AddArtifact(artifact), agentName)
+	}
+}
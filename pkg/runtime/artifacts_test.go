@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestRegisterArtifacts(t *testing.T) {
+	rt, a := newToolOutputTestRuntime(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.md"), []byte("# Report"), 0o600))
+	sess := session.New(session.WithWorkingDir(dir))
+
+	events := make(chan Event, 10)
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "write_report"}}
+	rt.registerArtifacts(sess, a, toolCall, []tools.Artifact{
+		{Path: "report.md", Description: "The report"},
+		{Path: "missing.md"},
+		{Path: dir},
+	}, events)
+
+	artifacts := sess.GetArtifacts()
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, "report.md", artifacts[0].Name)
+	assert.Equal(t, filepath.Join(dir, "report.md"), artifacts[0].Path)
+	assert.Equal(t, "The report", artifacts[0].Description)
+	assert.Equal(t, "text/plain; charset=utf-8", artifacts[0].MimeType)
+	assert.Equal(t, int64(8), artifacts[0].Size)
+	assert.Equal(t, "write_report", artifacts[0].ToolName)
+	assert.Equal(t, "root", artifacts[0].AgentName)
+	assert.NotEmpty(t, artifacts[0].ID)
+
+	require.Len(t, events, 1)
+	event := (<-events).(*ArtifactAddedEvent)
+	assert.Equal(t, sess.ID, event.SessionID)
+	assert.Equal(t, artifacts[0], event.Artifact)
+
+	// Sub-session artifacts are registered with the parent.
+	parent := session.New()
+	propagateArtifacts(parent, sess, events, "root")
+	assert.Equal(t, artifacts, parent.GetArtifacts())
+	assert.Equal(t, parent.ID, (<-events).(*ArtifactAddedEvent).SessionID)
+}
@@ -0,0 +1,216 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+const (
+	// attachmentPreviewLines and attachmentPreviewLength bound the preview
+	// of large attached text files sent to models.
+	attachmentPreviewLines  = 50
+	attachmentPreviewLength = 8000
+
+	// defaultAttachmentChunkLines is the number of lines read_attachment
+	// returns when no limit is given.
+	defaultAttachmentChunkLines = 500
+	// maxAttachmentChunkLength bounds the length, in characters, of the
+	// chunks returned by read_attachment.
+	maxAttachmentChunkLength = 40_000
+)
+
+// attachmentPreviews caches the previews of large attached text files, so
+// that files aren't read again before every model call.
+type attachmentPreviews struct {
+	mu       sync.Mutex
+	previews map[string]string
+}
+
+func (p *attachmentPreviews) get(path string) string {
+	key := path
+	if fi, err := os.Stat(path); err == nil {
+		key = fmt.Sprintf("%s:%d:%d", path, fi.Size(), fi.ModTime().UnixNano())
+	}
+
+	p.mu.Lock()
+	preview, ok := p.previews[key]
+	p.mu.Unlock()
+	if ok {
+		return preview
+	}
+
+	preview = textAttachmentPreview(path)
+
+	p.mu.Lock()
+	if p.previews == nil {
+		p.previews = make(map[string]string)
+	}
+	p.previews[key] = preview
+	p.mu.Unlock()
+	return preview
+}
+
+// prepareAttachments replaces the files attached to messages that models
+// can't read as they are: audio and video files with their transcript, and
+// large text files with a preview that agents read further with
+// read_attachment. Files that can't be transcribed are reported with a
+// warning, once.
+func (r *LocalRuntime) prepareAttachments(ctx context.Context, messages []chat.Message, agentName string, events chan Event) []chat.Message {
+	if transcriber := r.team.Transcriber(); transcriber != nil {
+		res := transcriber.Transcribe(ctx, messages)
+		for _, err := range res.Errors {
+			events <- Warning(fmt.Sprintf("Could not transcribe %v", err), agentName)
+		}
+		messages = res.Messages
+	}
+
+	copied := false
+	for i := range messages {
+		if !slices.ContainsFunc(messages[i].MultiContent, isLargeTextAttachment) {
+			continue
+		}
+
+		parts := slices.Clone(messages[i].MultiContent)
+		for j, part := range parts {
+			if isLargeTextAttachment(part) {
+				parts[j] = chat.MessagePart{Type: chat.MessagePartTypeText, Text: r.attachmentPreviews.get(part.File.Path)}
+			}
+		}
+
+		if !copied {
+			messages = slices.Clone(messages)
+			copied = true
+		}
+		messages[i].MultiContent = parts
+	}
+	return messages
+}
+
+// withAttachmentTools adds read_attachment to the tools of the agent when
+// the conversation has large attached text files.
+func withAttachmentTools(ctx context.Context, messages []chat.Message, agentTools []tools.Tool) []tools.Tool {
+	hasLargeTextAttachments := slices.ContainsFunc(messages, func(msg chat.Message) bool {
+		return slices.ContainsFunc(msg.MultiContent, isLargeTextAttachment)
+	})
+	if !hasLargeTextAttachments || slices.ContainsFunc(agentTools, func(t tools.Tool) bool { return t.Name == builtin.ToolNameReadAttachment }) {
+		return agentTools
+	}
+
+	attachmentTools, _ := builtin.NewAttachmentTool().Tools(ctx)
+	return append(slices.Clone(agentTools), attachmentTools...)
+}
+
+// isLargeTextAttachment reports whether part is a text file too large to be
+// inlined, see chat.AttachFile.
+func isLargeTextAttachment(part chat.MessagePart) bool {
+	return part.Type == chat.MessagePartTypeFile && part.File != nil && part.File.Path != "" && part.File.MimeType == "text/plain"
+}
+
+// textAttachmentPreview returns the first lines of a large attached text
+// file, with its number of lines and how to read the rest.
+func textAttachmentPreview(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Sprintf("<attached_file path=%q>\n[This file can't be read: %v]\n</attached_file>", path, err)
+	}
+	defer f.Close()
+
+	var preview strings.Builder
+	lines, previewLines := 0, 0
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			lines++
+			if lines <= attachmentPreviewLines && preview.Len()+len(line) <= attachmentPreviewLength {
+				preview.WriteString(line)
+				previewLines++
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	return fmt.Sprintf("<attached_file path=%q lines=\"%d\">\n%s\n[... %d more lines. This file is too large to be inlined: call %s with this path and an offset to read it in chunks.]\n</attached_file>",
+		path, lines, strings.TrimSuffix(preview.String(), "\n"), lines-previewLines, builtin.ToolNameReadAttachment)
+}
+
+// handleReadAttachment returns a chunk of lines of a large text file
+// attached to the session.
+func (r *LocalRuntime) handleReadAttachment(_ context.Context, sess *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+	var args builtin.ReadAttachmentArgs
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	attached := slices.ContainsFunc(sess.GetAllMessages(), func(msg session.Message) bool {
+		return msg.Message.Role == chat.MessageRoleUser && slices.ContainsFunc(msg.Message.MultiContent, func(part chat.MessagePart) bool {
+			return isLargeTextAttachment(part) && part.File.Path == args.Path
+		})
+	})
+	if !attached {
+		return tools.ResultError(fmt.Sprintf("%q is not a large text file attached to this conversation.", args.Path)), nil
+	}
+
+	offset := max(args.Offset, 1)
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultAttachmentChunkLines
+	}
+
+	f, err := os.Open(args.Path)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to read %s: %v", args.Path, err)), nil
+	}
+	defer f.Close()
+
+	var chunk strings.Builder
+	lines, last := 0, 0
+	full := false
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			lines++
+			if lines >= offset && !full {
+				// The first line is always returned, however long it is.
+				full = lines >= offset+limit || (chunk.Len() > 0 && chunk.Len()+len(line) > maxAttachmentChunkLength)
+				if !full {
+					chunk.WriteString(line)
+					last = lines
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return tools.ResultError(fmt.Sprintf("Failed to read %s: %v", args.Path, err)), nil
+		}
+	}
+
+	if last == 0 {
+		return tools.ResultError(fmt.Sprintf("Offset %d is past the end of the file (%d lines).", offset, lines)), nil
+	}
+
+	content := strings.TrimSuffix(chunk.String(), "\n")
+	if last == lines {
+		return tools.ResultSuccess(fmt.Sprintf("%s\n\n[End of the file: lines %d to %d of %d.]", content, offset, last, lines)), nil
+	}
+	return tools.ResultSuccess(fmt.Sprintf("%s\n\n[Lines %d to %d of %d. Call %s with offset %d for the next chunk.]",
+		content, offset, last, lines, builtin.ToolNameReadAttachment, last+1)), nil
+}
@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+func newLargeAttachmentSession(t *testing.T, lines int) (*session.Session, string) {
+	t.Helper()
+
+	var content strings.Builder
+	for i := range lines {
+		fmt.Fprintf(&content, "line %d\n", i+1)
+	}
+	path := filepath.Join(t.TempDir(), "server.log")
+	require.NoError(t, os.WriteFile(path, []byte(content.String()), 0o644))
+
+	sess := session.New()
+	sess.AddMessage(session.UserMessage("Why did the server crash?",
+		chat.MessagePart{Type: chat.MessagePartTypeText, Text: "Why did the server crash?"},
+		chat.MessagePart{Type: chat.MessagePartTypeFile, File: &chat.MessageFile{Path: path, MimeType: "text/plain"}},
+	))
+	return sess, path
+}
+
+func readAttachment(t *testing.T, rt *LocalRuntime, sess *session.Session, args builtin.ReadAttachmentArgs) *tools.ToolCallResult {
+	t.Helper()
+
+	arguments, err := json.Marshal(args)
+	require.NoError(t, err)
+	res, err := rt.handleReadAttachment(t.Context(), sess, tools.ToolCall{
+		ID:       "call_read",
+		Function: tools.FunctionCall{Name: builtin.ToolNameReadAttachment, Arguments: string(arguments)},
+	}, nil)
+	require.NoError(t, err)
+	return res
+}
+
+func TestPrepareAttachments_LargeTextFiles(t *testing.T) {
+	rt, a := newToolOutputTestRuntime(t)
+	sess, path := newLargeAttachmentSession(t, 120)
+
+	messages := sess.GetMessages(a)
+	agentTools := withAttachmentTools(t.Context(), messages, nil)
+	require.Len(t, agentTools, 1)
+	assert.Equal(t, builtin.ToolNameReadAttachment, agentTools[0].Name)
+
+	prepared := rt.prepareAttachments(t.Context(), messages, a.Name(), make(chan Event, 10))
+	last := prepared[len(prepared)-1]
+	require.Len(t, last.MultiContent, 2)
+	preview := last.MultiContent[1]
+	assert.Equal(t, chat.MessagePartTypeText, preview.Type)
+	assert.True(t, strings.HasPrefix(preview.Text, fmt.Sprintf("<attached_file path=%q lines=\"120\">\nline 1\n", path)))
+	assert.Contains(t, preview.Text, "line 50\n[... 70 more lines.")
+	assert.NotContains(t, preview.Text, "line 51")
+
+	// The session keeps the file.
+	assert.Equal(t, chat.MessagePartTypeFile, sess.GetMessages(a)[len(prepared)-1].MultiContent[1].Type)
+
+	// No tool without large attachments.
+	assert.Empty(t, withAttachmentTools(t.Context(), []chat.Message{{Role: chat.MessageRoleUser, Content: "hi"}}, nil))
+}
+
+func TestHandleReadAttachment(t *testing.T) {
+	rt, _ := newToolOutputTestRuntime(t)
+	sess, path := newLargeAttachmentSession(t, 120)
+
+	res := readAttachment(t, rt, sess, builtin.ReadAttachmentArgs{Path: path, Limit: 100})
+	require.False(t, res.IsError, res.Output)
+	assert.True(t, strings.HasPrefix(res.Output, "line 1\nline 2\n"))
+	assert.True(t, strings.HasSuffix(res.Output, "line 100\n\n[Lines 1 to 100 of 120. Call read_attachment with offset 101 for the next chunk.]"))
+
+	res = readAttachment(t, rt, sess, builtin.ReadAttachmentArgs{Path: path, Offset: 101})
+	require.False(t, res.IsError, res.Output)
+	assert.True(t, strings.HasPrefix(res.Output, "line 101\n"))
+	assert.True(t, strings.HasSuffix(res.Output, "line 120\n\n[End of the file: lines 101 to 120 of 120.]"))
+
+	res = readAttachment(t, rt, sess, builtin.ReadAttachmentArgs{Path: path, Offset: 200})
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Output, "past the end of the file (120 lines)")
+
+	// Only attached files can be read.
+	res = readAttachment(t, rt, sess, builtin.ReadAttachmentArgs{Path: "/etc/passwd"})
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Output, "is not a large text file attached to this conversation")
+}
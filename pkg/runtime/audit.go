@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/audit"
+)
+
+// WithAuditLog records the tool calls the agents execute and the answers of
+// the user to tool call confirmations in a tamper-evident audit log.
+func WithAuditLog(l *audit.Log) Opt {
+	return func(r *LocalRuntime) {
+		if l == nil {
+			return
+		}
+		r.auditLog = l
+		r.events.Subscribe(EventSinkFunc(r.auditEvent))
+	}
+}
+
+// auditEvent is the event sink of the audit log. Tool call start times are
+// kept by tool call ID to record the duration of the calls.
+func (r *LocalRuntime) auditEvent(sessionID string, event Event) {
+	switch e := event.(type) {
+	case *ToolCallEvent:
+		r.auditToolStarts.Store(e.ToolCall.ID, e.Timestamp)
+	case *ToolCallResponseEvent:
+		var duration time.Duration
+		if start, ok := r.auditToolStarts.LoadAndDelete(e.ToolCall.ID); ok {
+			duration = e.Timestamp.Sub(start.(time.Time))
+		}
+		r.audit(sessionID, audit.TypeToolCall, audit.ToolCall{
+			Agent:      e.AgentName,
+			ID:         e.ToolCall.ID,
+			Name:       e.ToolCall.Function.Name,
+			Arguments:  e.ToolCall.Function.Arguments,
+			DurationMs: duration.Milliseconds(),
+			OutputHash: audit.HashOutput(e.Response),
+			IsError:    e.Result != nil && e.Result.IsError,
+		})
+	case *RecordAddedEvent:
+		if e.Record == nil || e.Record.ToolApproval == nil {
+			return
+		}
+		a := e.Record.ToolApproval
+		r.audit(sessionID, audit.TypeApproval, audit.Approval{
+			Agent:      e.Record.AgentName,
+			ToolCallID: a.ToolCallID,
+			ToolName:   a.ToolName,
+			Arguments:  a.Arguments,
+			Decision:   a.Decision,
+			Pattern:    a.Pattern,
+			Reason:     a.Reason,
+		})
+	}
+}
+
+func (r *LocalRuntime) audit(sessionID, entryType string, data any) {
+	if err := r.auditLog.Record(sessionID, entryType, data); err != nil {
+		slog.Error("Failed to write audit log", "type", entryType, "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestAuditLog_RecordsToolCallsAndApprovals(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.Open(path)
+	require.NoError(t, err)
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithAuditLog(log))
+	require.NoError(t, err)
+
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"make"}`}}
+	rt.events.Publish("s1", RecordAdded("s1", &session.Record{
+		AgentName:    "root",
+		ToolApproval: &session.ToolApprovalRecord{ToolCallID: "call_1", ToolName: "shell", Decision: "approve"},
+	}, "root"))
+	rt.events.Publish("s1", ToolCall(toolCall, tools.Tool{}, "root"))
+	rt.events.Publish("s1", ToolCallResponse(toolCall, tools.Tool{}, tools.ResultSuccess("ok"), "ok", "root"))
+	require.NoError(t, log.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry audit.Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, audit.TypeApproval, entries[0].Type)
+	var approval audit.Approval
+	require.NoError(t, json.Unmarshal(entries[0].Data, &approval))
+	assert.Equal(t, "approve", approval.Decision)
+
+	assert.Equal(t, audit.TypeToolCall, entries[1].Type)
+	assert.Equal(t, "s1", entries[1].SessionID)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+	var call audit.ToolCall
+	require.NoError(t, json.Unmarshal(entries[1].Data, &call))
+	assert.Equal(t, "shell", call.Name)
+	assert.Equal(t, `{"cmd":"make"}`, call.Arguments)
+	assert.Equal(t, audit.HashOutput("ok"), call.OutputHash)
+}
@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+// handlePostToBlackboard writes a finding on the blackboard of the team run.
+func (r *LocalRuntime) handlePostToBlackboard(_ context.Context, sess *session.Session, toolCall tools.ToolCall, events chan Event) (*tools.ToolCallResult, error) {
+	var params builtin.PostToBlackboardArgs
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if params.Key == "" {
+		return tools.ResultError("key is required"), nil
+	}
+
+	owner := sess.BlackboardSession()
+	agentName := r.CurrentAgentName()
+	entry := owner.PostToBlackboard(params.Key, params.Value, agentName)
+	events <- BlackboardUpdated(owner.ID, params.Key, &entry, agentName)
+
+	if entry.Version == 1 {
+		return tools.ResultSuccess(fmt.Sprintf("Posted %q to the blackboard", params.Key)), nil
+	}
+	return tools.ResultSuccess(fmt.Sprintf("Updated %q on the blackboard (version %d)", params.Key, entry.Version)), nil
+}
+
+// handleReadBlackboard returns the findings on the blackboard of the team run.
+func (r *LocalRuntime) handleReadBlackboard(_ context.Context, sess *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+	var params builtin.ReadBlackboardArgs
+	if toolCall.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	entries := sess.BlackboardSession().GetBlackboard(params.Prefix)
+	if len(entries) == 0 {
+		if params.Prefix != "" {
+			return tools.ResultSuccess(fmt.Sprintf("No entries on the blackboard start with %q", params.Prefix)), nil
+		}
+		return tools.ResultSuccess("The blackboard is empty"), nil
+	}
+
+	var out strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "## %s (version %d, by %s)\n%s\n", e.Key, e.Version, e.AgentName, e.Value)
+	}
+	return tools.ResultSuccess(out.String()), nil
+}
+
+// handleRemoveFromBlackboard removes an entry from the blackboard of the team
+// run.
+func (r *LocalRuntime) handleRemoveFromBlackboard(_ context.Context, sess *session.Session, toolCall tools.ToolCall, events chan Event) (*tools.ToolCallResult, error) {
+	var params builtin.RemoveFromBlackboardArgs
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	owner := sess.BlackboardSession()
+	if !owner.RemoveFromBlackboard(params.Key) {
+		return tools.ResultError(fmt.Sprintf("No entry %q on the blackboard", params.Key)), nil
+	}
+	events <- BlackboardUpdated(owner.ID, params.Key, nil, r.CurrentAgentName())
+
+	return tools.ResultSuccess(fmt.Sprintf("Removed %q from the blackboard", params.Key)), nil
+}
@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestBlackboardTools(t *testing.T) {
+	rt, _ := newToolOutputTestRuntime(t)
+	parent := session.New()
+	child := session.New(session.WithParentID(parent.ID), session.WithBlackboardOf(parent))
+	events := make(chan Event, 10)
+
+	call := func(handler ToolHandlerFunc, sess *session.Session, args string) *tools.ToolCallResult {
+		t.Helper()
+		res, err := handler(t.Context(), sess, tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Arguments: args}}, events)
+		require.NoError(t, err)
+		return res
+	}
+
+	res := call(rt.handlePostToBlackboard, child, `{"key":"api/auth","value":"OAuth with PKCE"}`)
+	assert.Equal(t, `Posted "api/auth" to the blackboard`, res.Output)
+	event := (<-events).(*BlackboardUpdatedEvent)
+	assert.Equal(t, parent.ID, event.SessionID, "sub-agents write on the blackboard of the team run")
+	assert.Equal(t, "OAuth with PKCE", event.Entry.Value)
+
+	res = call(rt.handlePostToBlackboard, parent, `{"key":"api/auth","value":"OAuth with PKCE, tokens in Redis"}`)
+	assert.Equal(t, `Updated "api/auth" on the blackboard (version 2)`, res.Output)
+	<-events
+
+	res = call(rt.handleReadBlackboard, child, `{"prefix":"api/"}`)
+	assert.Equal(t, "## api/auth (version 2, by root)\nOAuth with PKCE, tokens in Redis\n", res.Output)
+
+	res = call(rt.handleRemoveFromBlackboard, child, `{"key":"api/auth"}`)
+	assert.False(t, res.IsError)
+	assert.Nil(t, (<-events).(*BlackboardUpdatedEvent).Entry)
+
+	res = call(rt.handleReadBlackboard, parent, "")
+	assert.Equal(t, "The blackboard is empty", res.Output)
+
+	res = call(rt.handleRemoveFromBlackboard, parent, `{"key":"api/auth"}`)
+	assert.True(t, res.IsError)
+}
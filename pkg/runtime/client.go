@@ -58,37 +58,7 @@ func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		registry: map[string]func() Event{
-			"user_message":           func() Event { return &UserMessageEvent{} },
-			"tool_call":              func() Event { return &ToolCallEvent{} },
-			"tool_call_response":     func() Event { return &ToolCallResponseEvent{} },
-			"tool_call_confirmation": func() Event { return &ToolCallConfirmationEvent{} },
-			"token_usage":            func() Event { return &TokenUsageEvent{} },
-			"stream_stopped":         func() Event { return &StreamStoppedEvent{} },
-			"stream_started":         func() Event { return &StreamStartedEvent{} },
-			"shell":                  func() Event { return &ShellOutputEvent{} },
-			"session_title":          func() Event { return &SessionTitleEvent{} },
-			"session_summary":        func() Event { return &SessionSummaryEvent{} },
-			"session_compaction":     func() Event { return &SessionCompactionEvent{} },
-			"partial_tool_call":      func() Event { return &PartialToolCallEvent{} },
-			"max_iterations_reached": func() Event { return &MaxIterationsReachedEvent{} },
-			"error":                  func() Event { return &ErrorEvent{} },
-			"elicitation_request":    func() Event { return &ElicitationRequestEvent{} },
-			"authorization_event":    func() Event { return &AuthorizationEvent{} },
-			"agent_choice":           func() Event { return &AgentChoiceEvent{} },
-			"agent_choice_reasoning": func() Event { return &AgentChoiceReasoningEvent{} },
-			"mcp_init_started":       func() Event { return &MCPInitStartedEvent{} },
-			"mcp_init_finished":      func() Event { return &MCPInitFinishedEvent{} },
-			"agent_info":             func() Event { return &AgentInfoEvent{} },
-			"team_info":              func() Event { return &TeamInfoEvent{} },
-			"toolset_info":           func() Event { return &ToolsetInfoEvent{} },
-			"agent_switching":        func() Event { return &AgentSwitchingEvent{} },
-			"warning":                func() Event { return &WarningEvent{} },
-			"hook_blocked":           func() Event { return &HookBlockedEvent{} },
-			"rag_indexing_started":   func() Event { return &RAGIndexingStartedEvent{} },
-			"rag_indexing_progress":  func() Event { return &RAGIndexingProgressEvent{} },
-			"rag_indexing_completed": func() Event { return &RAGIndexingCompletedEvent{} },
-		},
+		registry: eventRegistry(),
 	}
 
 	for _, opt := range opts {
@@ -98,6 +68,82 @@ func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
 	return client, nil
 }
 
+// eventRegistry maps the type of the events that can be decoded from JSON
+// to a constructor of the event.
+func eventRegistry() map[string]func() Event {
+	return map[string]func() Event{
+		"user_message":             func() Event { return &UserMessageEvent{} },
+		"tool_call":                func() Event { return &ToolCallEvent{} },
+		"tool_call_response":       func() Event { return &ToolCallResponseEvent{} },
+		"tool_call_confirmation":   func() Event { return &ToolCallConfirmationEvent{} },
+		"token_usage":              func() Event { return &TokenUsageEvent{} },
+		"context_usage":            func() Event { return &ContextUsageEvent{} },
+		"stream_stopped":           func() Event { return &StreamStoppedEvent{} },
+		"stream_started":           func() Event { return &StreamStartedEvent{} },
+		"shell":                    func() Event { return &ShellOutputEvent{} },
+		"session_title":            func() Event { return &SessionTitleEvent{} },
+		"session_summary":          func() Event { return &SessionSummaryEvent{} },
+		"run_queued":               func() Event { return &RunQueuedEvent{} },
+		"artifact_added":           func() Event { return &ArtifactAddedEvent{} },
+		"blackboard_updated":       func() Event { return &BlackboardUpdatedEvent{} },
+		"task_budget_exceeded":     func() Event { return &TaskBudgetExceededEvent{} },
+		"session_compaction":       func() Event { return &SessionCompactionEvent{} },
+		"record_added":             func() Event { return &RecordAddedEvent{} },
+		"prompt_compression":       func() Event { return &PromptCompressionEvent{} },
+		"guardrail":                func() Event { return &GuardrailEvent{} },
+		"content_filtered":         func() Event { return &ContentFilteredEvent{} },
+		"tool_result_confirmation": func() Event { return &ToolResultConfirmationEvent{} },
+		"tool_call_simulated":      func() Event { return &ToolCallSimulatedEvent{} },
+		"partial_tool_call":        func() Event { return &PartialToolCallEvent{} },
+		"max_iterations_reached":   func() Event { return &MaxIterationsReachedEvent{} },
+		"run_paused":               func() Event { return &RunPausedEvent{} },
+		"error":                    func() Event { return &ErrorEvent{} },
+		"elicitation_request":      func() Event { return &ElicitationRequestEvent{} },
+		"authorization_event":      func() Event { return &AuthorizationEvent{} },
+		"agent_choice":             func() Event { return &AgentChoiceEvent{} },
+		"agent_choice_reasoning":   func() Event { return &AgentChoiceReasoningEvent{} },
+		"citations":                func() Event { return &CitationsEvent{} },
+		"model_fallback":           func() Event { return &ModelFallbackEvent{} },
+		"model_retry":              func() Event { return &ModelRetryEvent{} },
+		"mcp_init_started":         func() Event { return &MCPInitStartedEvent{} },
+		"mcp_init_finished":        func() Event { return &MCPInitFinishedEvent{} },
+		"agent_info":               func() Event { return &AgentInfoEvent{} },
+		"team_info":                func() Event { return &TeamInfoEvent{} },
+		"toolset_info":             func() Event { return &ToolsetInfoEvent{} },
+		"toolset_status":           func() Event { return &ToolsetStatusEvent{} },
+		"tool_list_changed":        func() Event { return &ToolListChangedEvent{} },
+		"agent_switching":          func() Event { return &AgentSwitchingEvent{} },
+		"warning":                  func() Event { return &WarningEvent{} },
+		"hook_blocked":             func() Event { return &HookBlockedEvent{} },
+		"rag_indexing_started":     func() Event { return &RAGIndexingStartedEvent{} },
+		"rag_indexing_progress":    func() Event { return &RAGIndexingProgressEvent{} },
+		"rag_indexing_completed":   func() Event { return &RAGIndexingCompletedEvent{} },
+		"model_pull_progress":      func() Event { return &ModelPullProgressEvent{} },
+	}
+}
+
+// DecodeEvent decodes an event encoded as JSON, e.g. by the API server or
+// in a run log.
+func DecodeEvent(data []byte) (Event, error) {
+	var baseEvent struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &baseEvent); err != nil {
+		return nil, err
+	}
+
+	createEvent, found := eventRegistry()[baseEvent.Type]
+	if !found {
+		return nil, fmt.Errorf("unknown event type %q", baseEvent.Type)
+	}
+
+	e := createEvent()
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
 // ErrorResponse represents an error response from the API
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -260,9 +306,10 @@ func (c *Client) CreateSession(ctx context.Context, sessTemplate *session.Sessio
 	return &sess, err
 }
 
-// ResumeSession resumes a session by ID with optional rejection reason or tool name
-func (c *Client) ResumeSession(ctx context.Context, id, confirmation, reason, toolName string) error {
-	req := api.ResumeSessionRequest{Confirmation: confirmation, Reason: reason, ToolName: toolName}
+// ResumeSession resumes a session by ID with optional rejection reason, tool
+// name or number of iterations to continue for
+func (c *Client) ResumeSession(ctx context.Context, id, confirmation, reason, toolName string, iterations int) error {
+	req := api.ResumeSessionRequest{Confirmation: confirmation, Reason: reason, ToolName: toolName, Iterations: iterations}
 	return c.doRequest(ctx, http.MethodPost, "/api/sessions/"+id+"/resume", req, nil)
 }
 
@@ -394,6 +441,15 @@ func (c *Client) UpdateSessionTitle(ctx context.Context, sessionID, title string
 	return c.doRequest(ctx, http.MethodPatch, "/api/sessions/"+sessionID+"/title", req, nil)
 }
 
+// RegenerateSessionTitle generates a new title for a session and returns it
+func (c *Client) RegenerateSessionTitle(ctx context.Context, sessionID string) (string, error) {
+	var resp api.UpdateSessionTitleResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/api/sessions/"+sessionID+"/title/regenerate", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Title, nil
+}
+
 // GetAgentToolCount returns the number of tools available for an agent.
 func (c *Client) GetAgentToolCount(ctx context.Context, agentFilename, agentName string) (int, error) {
 	var resp struct {
@@ -613,3 +613,20 @@ func TestResolveCommand_ArgsSlice(t *testing.T) {
 	result := ResolveCommand(t.Context(), rt, "/test first second third")
 	assert.Equal(t, "Rest: second third", result)
 }
+
+func TestAgentCommandsMergesUserCommands(t *testing.T) {
+	t.Parallel()
+
+	r := &LocalRuntime{userCommands: types.Commands{
+		"review": {Instruction: "Review the code"},
+		"fix":    {Instruction: "User fix"},
+	}}
+
+	commands := r.agentCommands(types.Commands{"fix": {Instruction: "Agent fix"}})
+
+	assert.Equal(t, types.Commands{
+		"review": {Instruction: "Review the code"},
+		"fix":    {Instruction: "Agent fix"},
+	}, commands)
+	assert.Len(t, r.userCommands, 2, "user commands must not be modified")
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/contentfilter"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// filterToolResult scans the result of a tool call with the content filters
+// of the agent, before it's added to the conversation. Flagged results are
+// wrapped in a warning, stripped of the flagged lines, or withheld unless the
+// user approves them. Runs that approve all tool calls have nobody to ask, so
+// their results are stripped instead.
+func (r *LocalRuntime) filterToolResult(ctx context.Context, sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, content string, searchResults []tools.SearchResult, events chan Event) (string, []tools.SearchResult, bool) {
+	filters := a.ContentFilters()
+	if len(filters) == 0 {
+		return content, searchResults, false
+	}
+
+	toolName := toolCall.Function.Name
+	matches := contentfilter.Scan(filters, toolName, content)
+	for _, sr := range searchResults {
+		for _, chunk := range sr.Content {
+			matches = append(matches, contentfilter.Scan(filters, toolName, chunk)...)
+		}
+	}
+	if len(matches) == 0 {
+		return content, searchResults, false
+	}
+
+	action := contentfilter.Action(matches)
+	if action == latest.ContentFilterActionApprove && sess.ToolsApproved {
+		action = latest.ContentFilterActionStrip
+	}
+
+	var names, flagged []string
+	for _, m := range matches {
+		names = append(names, m.Filter.Name)
+		flagged = append(flagged, m.Matches...)
+	}
+	slog.Warn("Tool result flagged by content filters", "agent", a.Name(), "session_id", sess.ID, "tool", toolName, "filters", strings.Join(names, ", "), "action", action)
+	events <- ContentFiltered(sess.ID, a.Name(), toolCall, names, action, flagged)
+
+	var transform func(string, []contentfilter.Match) string
+	switch action {
+	case latest.ContentFilterActionApprove:
+		if !r.approveToolResult(ctx, sess, toolCall, names, flagged, events, a) {
+			return contentfilter.Withheld, nil, true
+		}
+		return content, searchResults, false
+	case latest.ContentFilterActionStrip:
+		transform = contentfilter.Strip
+	default:
+		transform = contentfilter.Warn
+	}
+
+	filter := func(text string) string {
+		if m := contentfilter.Scan(filters, toolName, text); len(m) > 0 {
+			return transform(text, m)
+		}
+		return text
+	}
+	content = filter(content)
+	filtered := make([]tools.SearchResult, len(searchResults))
+	for i, sr := range searchResults {
+		sr.Content = slices.Clone(sr.Content)
+		for j, chunk := range sr.Content {
+			sr.Content[j] = filter(chunk)
+		}
+		filtered[i] = sr
+	}
+	return content, filtered, false
+}
+
+// approveToolResult asks the user whether a flagged tool result can be sent
+// to the model, showing them the flagged text. Approvals only apply to this
+// result: flagged results are never approved for the session or the tool.
+func (r *LocalRuntime) approveToolResult(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, filters, flagged []string, events chan Event, a *agent.Agent) bool {
+	slog.Debug("Tool result flagged, waiting for approval", "tool", toolCall.Function.Name, "session_id", sess.ID)
+	events <- ToolResultConfirmation(sess.ID, a.Name(), toolCall, filters, flagged)
+
+	r.executeOnUserInputHooks(ctx, sess.ID, "tool result approval")
+
+	select {
+	case req := <-r.resumeChan:
+		return req.Type != ResumeTypeReject
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// DefaultContextWarningThresholds are the percentages of the context window
+// at which a warning is sent, unless WithContextWarningThresholds is used.
+var DefaultContextWarningThresholds = []int{75, 90}
+
+// WithContextWarningThresholds sets the percentages of the context window at
+// which a warning is sent, so that users know the conversation should be
+// compacted or moved to a model with a larger context window. Each threshold
+// warns once, until the usage drops below it again. No thresholds disables
+// the warnings.
+func WithContextWarningThresholds(thresholds ...int) Opt {
+	return func(r *LocalRuntime) {
+		r.contextWarningThresholds = slices.Sorted(slices.Values(thresholds))
+	}
+}
+
+// reportContextUsage sends the context usage of the last request of the
+// session, along with a warning when it crossed a new threshold.
+func (r *LocalRuntime) reportContextUsage(sess *session.Session, a *agent.Agent, contextLimit int64, events chan Event) {
+	if contextLimit <= 0 || sess.InputTokens <= 0 {
+		return
+	}
+
+	percent := int(sess.InputTokens * 100 / contextLimit)
+	threshold := 0
+	for _, t := range r.contextWarningThresholds {
+		if percent >= t {
+			threshold = t
+		}
+	}
+
+	warned, _ := r.contextWarnings.Swap(sess.ID, threshold)
+	if threshold > 0 && (warned == nil || threshold > warned.(int)) {
+		events <- Warning(contextWarning(percent, sess.InputTokens, contextLimit, r.sessionCompaction), a.Name())
+	}
+	events <- ContextUsage(sess.ID, a.Name(), sess.InputTokens, contextLimit, threshold)
+}
+
+func contextWarning(percent int, promptTokens, contextLimit int64, compaction bool) string {
+	msg := fmt.Sprintf("The conversation uses %d%% of the model's context window (%d of %d tokens).", percent, promptTokens, contextLimit)
+	if compaction && percent < 100 {
+		return msg + " It will be compacted automatically before it fills up."
+	}
+	return msg + " Compact the conversation or switch to a model with a larger context window."
+}
@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestReportContextUsage(t *testing.T) {
+	t.Parallel()
+
+	rt := newBusRuntime(t, WithContextWarningThresholds(90, 75))
+	a := rt.CurrentAgent()
+	sess := session.New()
+
+	report := func(promptTokens int64) []Event {
+		sess.InputTokens = promptTokens
+		events := make(chan Event, 10)
+		rt.reportContextUsage(sess, a, 1000, events)
+		close(events)
+
+		var got []Event
+		for event := range events {
+			got = append(got, event)
+		}
+		return got
+	}
+
+	events := report(500)
+	require.Len(t, events, 1)
+	usage := events[0].(*ContextUsageEvent)
+	assert.Equal(t, sess.ID, usage.SessionID)
+	assert.Equal(t, "root", usage.AgentName)
+	assert.Equal(t, int64(500), usage.PromptTokens)
+	assert.Equal(t, int64(1000), usage.ContextLimit)
+	assert.Equal(t, 50, usage.Percent)
+	assert.Zero(t, usage.Threshold)
+
+	// Crossing a threshold warns once.
+	events = report(780)
+	require.Len(t, events, 2)
+	assert.IsType(t, &WarningEvent{}, events[0])
+	assert.Equal(t, 75, events[1].(*ContextUsageEvent).Threshold)
+	assert.Len(t, report(800), 1)
+
+	events = report(950)
+	require.Len(t, events, 2)
+	assert.Contains(t, events[0].(*WarningEvent).Message, "95% of the model's context window (950 of 1000 tokens)")
+	assert.Equal(t, 90, events[1].(*ContextUsageEvent).Threshold)
+
+	// Once the conversation shrinks, thresholds warn again.
+	assert.Len(t, report(100), 1)
+	assert.Len(t, report(800), 2)
+}
+
+func TestReportContextUsageWithoutLimit(t *testing.T) {
+	t.Parallel()
+
+	rt := newBusRuntime(t)
+	sess := session.New()
+	sess.InputTokens = 500
+
+	events := make(chan Event, 10)
+	rt.reportContextUsage(sess, rt.CurrentAgent(), 0, events)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/continuity"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+const continuityTimeout = 30 * time.Second
+
+// WithContinuityStore sets where the summaries of the sessions of agents with
+// continuity enabled are stored.
+func WithContinuityStore(store *continuity.Store) Opt {
+	return func(r *LocalRuntime) {
+		r.continuityStore = store
+	}
+}
+
+func (r *LocalRuntime) continuityDir(sess *session.Session) string {
+	if sess.WorkingDir != "" {
+		return sess.WorkingDir
+	}
+	if r.workingDir != "" {
+		return r.workingDir
+	}
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// loadContinuity loads, on the first run of a top-level session, the summary
+// of the previous sessions of the agent in the project. The agent is
+// remembered to update the same summary at the end of each run.
+func (r *LocalRuntime) loadContinuity(sess *session.Session, a *agent.Agent) {
+	if !a.Continuity() || sess.ParentID != "" {
+		return
+	}
+	if _, loaded := r.continuityAgents.LoadOrStore(sess.ID, a); loaded {
+		return
+	}
+
+	summary, err := r.continuityStore.Load(r.continuityDir(sess), a.Name())
+	if err != nil {
+		slog.Warn("Failed to load the summary of previous sessions", "agent", a.Name(), "error", err)
+		return
+	}
+	sess.PreviousSessionsSummary = summary
+}
+
+// saveContinuity updates the summary of the sessions of the agent in the
+// project with the conversation of the session. The summary is always
+// computed from the one loaded when the session started, so that each
+// session contributes once, however many runs it has.
+func (r *LocalRuntime) saveContinuity(ctx context.Context, sess *session.Session) {
+	value, ok := r.continuityAgents.Load(sess.ID)
+	if !ok {
+		return
+	}
+	a := value.(*agent.Agent)
+
+	var messages []chat.Message
+	for _, msg := range sess.GetAllMessages() {
+		messages = append(messages, msg.Message)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), continuityTimeout)
+	defer cancel()
+
+	summary, err := continuity.Summarize(ctx, a.Model(), sess.PreviousSessionsSummary, messages)
+	if err != nil {
+		slog.Warn("Failed to summarize the session", "agent", a.Name(), "session_id", sess.ID, "error", err)
+		return
+	}
+	if summary == "" {
+		return
+	}
+	if err := r.continuityStore.Save(r.continuityDir(sess), a.Name(), summary); err != nil {
+		slog.Warn("Failed to save the summary of the session", "agent", a.Name(), "error", err)
+	}
+}
@@ -0,0 +1,151 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// delegation is a task transfer or a handoff from one agent to another.
+type delegation struct {
+	from, to string
+}
+
+type (
+	delegationGuardKey struct{}
+	delegationChainKey struct{}
+)
+
+// delegationGuard enforces the team's delegation limits over a whole turn:
+// it is created by the outermost RunStream and shared, through the context,
+// by every sub-session it starts.
+//
+// Task transfers nest, so they are checked against the chain of transfers
+// leading to them. Handoffs don't nest, so they are checked against all the
+// handoffs of the turn.
+type delegationGuard struct {
+	maxDepth  int
+	loopLimit int
+
+	mu       sync.Mutex
+	handoffs []delegation
+	err      error
+	reported bool
+}
+
+// delegationGuard returns the guard of the current turn, creating it if ctx
+// has none yet.
+func (r *LocalRuntime) delegationGuard(ctx context.Context) (context.Context, *delegationGuard) {
+	if g, ok := ctx.Value(delegationGuardKey{}).(*delegationGuard); ok {
+		return ctx, g
+	}
+	g := &delegationGuard{
+		maxDepth:  r.team.MaxDelegationDepth(),
+		loopLimit: r.team.DelegationLoopLimit(),
+	}
+	return context.WithValue(ctx, delegationGuardKey{}, g), g
+}
+
+// transfer checks a task transfer from one agent to another. It returns the
+// context to run the transfer with, or a tool error result if the transfer
+// isn't allowed.
+func (g *delegationGuard) transfer(ctx context.Context, from, to string) (context.Context, *tools.ToolCallResult) {
+	chain, _ := ctx.Value(delegationChainKey{}).([]delegation)
+	next := delegation{from: from, to: to}
+
+	if errResult := g.check(chain, next); errResult != nil {
+		return ctx, errResult
+	}
+	if g.maxDepth > 0 && len(chain) >= g.maxDepth {
+		return ctx, tools.ResultError(fmt.Sprintf(
+			"Agent %s cannot transfer task to %s: the maximum delegation depth of %d is reached (%s). Complete the task yourself instead.",
+			from, to, g.maxDepth, delegationPath(chain, next),
+		))
+	}
+
+	return context.WithValue(ctx, delegationChainKey{}, append(slices.Clip(chain), next)), nil
+}
+
+// handoff checks a handoff from one agent to another and records it.
+func (g *delegationGuard) handoff(from, to string) *tools.ToolCallResult {
+	g.mu.Lock()
+	handoffs := g.handoffs
+	g.mu.Unlock()
+
+	next := delegation{from: from, to: to}
+	if errResult := g.check(handoffs, next); errResult != nil {
+		return errResult
+	}
+
+	g.mu.Lock()
+	g.handoffs = append(g.handoffs, next)
+	g.mu.Unlock()
+	return nil
+}
+
+// check stops the turn if next would repeat a delegation of previous more
+// than the loop limit allows.
+func (g *delegationGuard) check(previous []delegation, next delegation) *tools.ToolCallResult {
+	if err := g.stopped(); err != nil {
+		return tools.ResultError(err.Error())
+	}
+	if g.loopLimit <= 0 {
+		return nil
+	}
+
+	repeats := 0
+	for _, d := range previous {
+		if d == next {
+			repeats++
+		}
+	}
+	if repeats < g.loopLimit {
+		return nil
+	}
+
+	err := fmt.Errorf("delegation loop detected, %s → %s happened %d times: %s",
+		next.from, next.to, repeats+1, delegationPath(previous, next))
+
+	g.mu.Lock()
+	if g.err == nil {
+		g.err = err
+	}
+	g.mu.Unlock()
+
+	return tools.ResultError(err.Error())
+}
+
+// stopped returns the error that stopped the turn, if any.
+func (g *delegationGuard) stopped() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+// report tells whether the error that stopped the turn still has to be
+// reported, so that only one of the nested sessions reports it.
+func (g *delegationGuard) report() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err == nil || g.reported {
+		return false
+	}
+	g.reported = true
+	return true
+}
+
+// delegationPath formats a sequence of delegations as "a → b → c".
+func delegationPath(previous []delegation, next delegation) string {
+	var names []string
+	for _, d := range append(slices.Clip(previous), next) {
+		if len(names) == 0 || names[len(names)-1] != d.from {
+			names = append(names, d.from)
+		}
+		names = append(names, d.to)
+	}
+	return strings.Join(names, " → ")
+}
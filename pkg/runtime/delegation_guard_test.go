@@ -0,0 +1,140 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+// delegatingProvider always asks to delegate to the same agent, and never
+// stops on its own.
+type delegatingProvider struct {
+	tool, target string
+	calls        int
+}
+
+func (p *delegatingProvider) ID() string { return "test/delegating" }
+func (p *delegatingProvider) CreateChatCompletionStream(context.Context, []chat.Message, []tools.Tool) (chat.MessageStream, error) {
+	p.calls++
+	id := fmt.Sprintf("call_%s_%d", p.target, p.calls)
+	return newStreamBuilder().
+		AddToolCallName(id, p.tool).
+		AddToolCallArguments(id, fmt.Sprintf(`{"agent":%q,"task":"ping"}`, p.target)).
+		Build(), nil
+}
+func (p *delegatingProvider) BaseConfig() base.Config { return base.Config{} }
+
+func newPingPongRuntime(t *testing.T, tool string, opts ...team.Opt) (*LocalRuntime, *delegatingProvider) {
+	t.Helper()
+
+	toA := &delegatingProvider{tool: tool, target: "a"}
+	toB := &delegatingProvider{tool: tool, target: "b"}
+	var toolSet tools.ToolSet = builtin.NewTransferTaskTool()
+	if tool == "handoff" {
+		toolSet = builtin.NewHandoffTool()
+	}
+	a := agent.New("a", "test", agent.WithModel(toB), agent.WithToolSets(toolSet))
+	b := agent.New("b", "test", agent.WithModel(toA), agent.WithToolSets(toolSet))
+	if tool == "handoff" {
+		agent.WithHandoffs(b)(a)
+		agent.WithHandoffs(a)(b)
+	} else {
+		agent.WithSubAgents(b)(a)
+		agent.WithSubAgents(a)(b)
+	}
+
+	tm := team.New(append([]team.Opt{team.WithAgents(a, b)}, opts...)...)
+	rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+	return rt, toB
+}
+
+func runUntilError(t *testing.T, rt *LocalRuntime) []*ErrorEvent {
+	t.Helper()
+
+	sess := session.New(session.WithUserMessage("Go"), session.WithToolsApproved(true))
+	var errs []*ErrorEvent
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if errEvent, ok := ev.(*ErrorEvent); ok {
+			errs = append(errs, errEvent)
+		}
+	}
+	return errs
+}
+
+func TestDelegationLoopStopsHandoffs(t *testing.T) {
+	rt, toB := newPingPongRuntime(t, "handoff", team.WithDelegationLimits(0, 3))
+
+	errs := runUntilError(t, rt)
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "delegation loop detected, a → b happened 4 times: a → b → a → b → a → b → a → b", errs[0].Error)
+	assert.Equal(t, 4, toB.calls)
+}
+
+func TestDelegationLoopStopsNestedTransfers(t *testing.T) {
+	rt, toB := newPingPongRuntime(t, "transfer_task", team.WithDelegationLimits(0, 2))
+
+	errs := runUntilError(t, rt)
+
+	require.Len(t, errs, 1, "the loop is reported once, however deep it's detected")
+	assert.Contains(t, errs[0].Error, "delegation loop detected, a → b happened 3 times")
+	assert.Equal(t, 3, toB.calls)
+}
+
+func TestDelegationGuard_MaxDepth(t *testing.T) {
+	g := &delegationGuard{maxDepth: 2}
+
+	ctx, errResult := g.transfer(t.Context(), "root", "planner")
+	require.Nil(t, errResult)
+	ctx, errResult = g.transfer(ctx, "planner", "writer")
+	require.Nil(t, errResult)
+
+	_, errResult = g.transfer(ctx, "writer", "reviewer")
+	require.NotNil(t, errResult)
+	assert.True(t, errResult.IsError)
+	assert.Contains(t, errResult.Output, "maximum delegation depth of 2 is reached (root → planner → writer → reviewer)")
+	require.NoError(t, g.stopped(), "too deep a transfer doesn't stop the run")
+
+	// Sibling transfers don't add up.
+	_, errResult = g.transfer(t.Context(), "root", "writer")
+	assert.Nil(t, errResult)
+}
+
+func TestDelegationGuard_LoopLimit(t *testing.T) {
+	g := &delegationGuard{loopLimit: 1}
+
+	require.Nil(t, g.handoff("a", "b"))
+	require.Nil(t, g.handoff("b", "a"))
+	errResult := g.handoff("a", "b")
+	require.NotNil(t, errResult)
+	require.Error(t, g.stopped())
+
+	assert.True(t, g.report())
+	assert.False(t, g.report(), "the loop is reported once")
+
+	// Nothing can be delegated once the run is stopped.
+	_, errResult = g.transfer(t.Context(), "c", "d")
+	assert.NotNil(t, errResult)
+}
+
+func TestDelegationGuard_LoopDetectionDisabled(t *testing.T) {
+	g := &delegationGuard{}
+
+	for range 10 {
+		require.Nil(t, g.handoff("a", "b"))
+		require.Nil(t, g.handoff("b", "a"))
+	}
+	assert.NoError(t, g.stopped())
+}
@@ -0,0 +1,163 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/shellpath"
+)
+
+const (
+	// defaultContextMaxSize is the default maximum number of bytes injected
+	// by a dynamic context source.
+	defaultContextMaxSize = 16 * 1024
+
+	// defaultContextTimeout is the default timeout of context commands.
+	defaultContextTimeout = 10 * time.Second
+)
+
+// dynamicContext keeps the content of the dynamic context sources read
+// during a run. Sources are read once per run, unless their cache duration
+// expires, in which case they're read again before the next model call.
+type dynamicContext struct {
+	entries map[contextCacheKey]contextCacheEntry
+}
+
+type contextCacheKey struct {
+	workingDir string
+	command    string
+	file       string
+}
+
+type contextCacheEntry struct {
+	content string
+	read    time.Time
+}
+
+func newDynamicContext() *dynamicContext {
+	return &dynamicContext{entries: map[contextCacheKey]contextCacheEntry{}}
+}
+
+// refresh sets the dynamic context of the agent on the session, before a
+// model call. The commands run with ctx, so that they stop with the run.
+func (d *dynamicContext) refresh(ctx context.Context, sess *session.Session, a *agent.Agent) {
+	workingDir := sess.WorkingDir
+	if workingDir == "" {
+		workingDir, _ = os.Getwd()
+	}
+
+	var sections []string
+	for _, source := range a.Contexts() {
+		if section := d.section(ctx, workingDir, source); section != "" {
+			sections = append(sections, section)
+		}
+	}
+	sess.DynamicContext = sections
+}
+
+// section returns the system prompt section of a dynamic context source, or
+// an empty string if the source produced nothing.
+func (d *dynamicContext) section(ctx context.Context, workingDir string, source latest.ContextConfig) string {
+	content := d.content(ctx, workingDir, source)
+	if content == "" {
+		return ""
+	}
+
+	var what string
+	if source.Command != "" {
+		what = fmt.Sprintf("the output of `%s`", source.Command)
+	} else {
+		what = "the content of " + source.File
+	}
+
+	return fmt.Sprintf("Here is %s, refreshed automatically:\n<context>\n%s\n</context>", what, content)
+}
+
+func (d *dynamicContext) content(ctx context.Context, workingDir string, source latest.ContextConfig) string {
+	key := contextCacheKey{workingDir: workingDir, command: source.Command, file: source.File}
+
+	entry, ok := d.entries[key]
+	if ok && (source.Cache.Duration <= 0 || time.Since(entry.read) < source.Cache.Duration) {
+		return entry.content
+	}
+
+	content := readContextContent(ctx, workingDir, source)
+	d.entries[key] = contextCacheEntry{content: content, read: time.Now()}
+	return content
+}
+
+// readContextContent runs the command, or reads the file, of a dynamic
+// context source and truncates the result to its maximum size.
+func readContextContent(ctx context.Context, workingDir string, source latest.ContextConfig) string {
+	var (
+		content []byte
+		err     error
+	)
+	if source.Command != "" {
+		content, err = runContextCommand(ctx, workingDir, source)
+	} else {
+		path := source.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workingDir, path)
+		}
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		slog.Warn("Failed to read dynamic context", "command", source.Command, "file", source.File, "error", err)
+		return ""
+	}
+
+	maxSize := source.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultContextMaxSize
+	}
+
+	return truncateContext(strings.TrimSpace(string(content)), maxSize)
+}
+
+func runContextCommand(ctx context.Context, workingDir string, source latest.ContextConfig) ([]byte, error) {
+	timeout := defaultContextTimeout
+	if source.Timeout > 0 {
+		timeout = time.Duration(source.Timeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	shell, argsPrefix := shellpath.DetectShell()
+	cmd := shellpath.Command(ctx, shell, argsPrefix, source.Command)
+	cmd.Dir = workingDir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// truncateContext cuts content to at most maxSize bytes, without splitting
+// a multi-byte character, and marks it as truncated.
+func truncateContext(content string, maxSize int) string {
+	if len(content) <= maxSize {
+		return content
+	}
+
+	cut := maxSize
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + "\n[truncated]"
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestDynamicContext_File(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TODO.md"), []byte("- write tests\n"), 0o644))
+
+	d := newDynamicContext()
+	content := d.section(t.Context(), dir, latest.ContextConfig{File: "TODO.md"})
+	assert.Equal(t, "Here is the content of TODO.md, refreshed automatically:\n<context>\n- write tests\n</context>", content)
+
+	assert.Empty(t, d.section(t.Context(), dir, latest.ContextConfig{File: "missing.md"}))
+}
+
+func TestDynamicContext_Command(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), nil, 0o644))
+
+	d := newDynamicContext()
+	content := d.section(t.Context(), dir, latest.ContextConfig{Command: "ls"})
+	assert.Equal(t, "Here is the output of `ls`, refreshed automatically:\n<context>\na.txt\n</context>", content)
+
+	assert.Empty(t, d.section(t.Context(), dir, latest.ContextConfig{Command: "exit 1"}))
+}
+
+func TestDynamicContext_ReadOncePerRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "status.txt")
+	require.NoError(t, os.WriteFile(path, []byte("before"), 0o644))
+
+	a := agent.New("root", "", agent.WithContexts([]latest.ContextConfig{{File: "status.txt"}}))
+	sess := session.New(session.WithWorkingDir(dir))
+
+	run := newDynamicContext()
+	run.refresh(t.Context(), sess, a)
+	require.Len(t, sess.DynamicContext, 1)
+	assert.Contains(t, sess.DynamicContext[0], "before")
+
+	require.NoError(t, os.WriteFile(path, []byte("after"), 0o644))
+	run.refresh(t.Context(), sess, a)
+	assert.Contains(t, sess.DynamicContext[0], "before", "the content is kept for the rest of the run")
+
+	newDynamicContext().refresh(t.Context(), sess, a)
+	assert.Contains(t, sess.DynamicContext[0], "after", "the next run reads the content again")
+}
+
+func TestDynamicContext_CacheExpires(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "status.txt")
+	require.NoError(t, os.WriteFile(path, []byte("before"), 0o644))
+
+	source := latest.ContextConfig{File: "status.txt", Cache: latest.Duration{Duration: time.Hour}}
+	d := newDynamicContext()
+	assert.Contains(t, d.section(t.Context(), dir, source), "before")
+
+	require.NoError(t, os.WriteFile(path, []byte("after"), 0o644))
+	assert.Contains(t, d.section(t.Context(), dir, source), "before")
+
+	source.Cache.Duration = time.Nanosecond
+	assert.Contains(t, d.section(t.Context(), dir, source), "after")
+}
+
+func TestTruncateContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "short", truncateContext("short", 10))
+	assert.Equal(t, "abcd\n[truncated]", truncateContext("abcdefgh", 4))
+	// Multi-byte characters are never split
+	assert.Equal(t, "a\n[truncated]", truncateContext("aé", 2))
+	assert.True(t, strings.HasSuffix(truncateContext(strings.Repeat("x", 100), 10), "[truncated]"))
+}
@@ -2,10 +2,13 @@ package runtime
 
 import (
 	"cmp"
+	"strings"
 	"time"
 
+	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/modelerrors"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/tools"
 )
@@ -42,6 +45,9 @@ type UserMessageEvent struct {
 	MultiContent    []chat.MessagePart `json:"multi_content,omitempty"`
 	SessionID       string             `json:"session_id"`
 	SessionPosition int                `json:"session_position"` // Index in session.Messages, -1 if unknown
+	// User is the user who sent the message, in sessions shared by several
+	// users of the API server.
+	User string `json:"user,omitempty"`
 	AgentContext
 }
 
@@ -180,12 +186,49 @@ func AgentChoiceReasoning(agentName, sessionID, content string) Event {
 	}
 }
 
+// CitationsEvent is emitted once an assistant answer that cites sources
+// (e.g. search results returned by a tool) has been fully received.
+type CitationsEvent struct {
+	Type      string          `json:"type"`
+	Citations []chat.Citation `json:"citations"`
+	SessionID string          `json:"session_id,omitempty"`
+	AgentContext
+}
+
+func (e *CitationsEvent) GetSessionID() string { return e.SessionID }
+
+func Citations(agentName, sessionID string, citations []chat.Citation) Event {
+	return &CitationsEvent{
+		Type:         "citations",
+		Citations:    citations,
+		SessionID:    sessionID,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
 type ErrorEvent struct {
 	Type  string `json:"type"`
 	Error string `json:"error"`
+	// Code classifies the error, when known: ErrorCodeModel or ErrorCodeTools.
+	Code string `json:"code,omitempty"`
+	// Kind is the kind of model errors, when known: context_length,
+	// authentication, content_filter, overloaded, rate_limit, quota or
+	// model_not_found. Error is then a message for users, Remediation
+	// suggests what they can do, and Detail is the error of the provider.
+	Kind        string `json:"kind,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+	Detail      string `json:"detail,omitempty"`
 	AgentContext
 }
 
+// Codes of ErrorEvent.
+const (
+	// ErrorCodeModel is the code of errors returned by models and providers.
+	ErrorCodeModel = "model_error"
+	// ErrorCodeTools is the code of errors starting the tools of an agent.
+	ErrorCodeTools = "tool_failure"
+)
+
 func Error(msg string) Event {
 	return &ErrorEvent{
 		Type:  "error",
@@ -193,6 +236,41 @@ func Error(msg string) Event {
 	}
 }
 
+// ErrorWithCode creates an ErrorEvent classified with one of the ErrorCode
+// constants.
+func ErrorWithCode(code, msg string) Event {
+	return &ErrorEvent{
+		Type:  "error",
+		Error: msg,
+		Code:  code,
+	}
+}
+
+// Text returns the error followed by its remediation, if any.
+func (e *ErrorEvent) Text() string {
+	if e.Remediation == "" {
+		return e.Error
+	}
+	return e.Error + "\n" + e.Remediation
+}
+
+// ModelError creates the ErrorEvent of an error returned by models and
+// providers, classified by kind when it's known.
+func ModelError(err error) Event {
+	event := &ErrorEvent{
+		Type:  "error",
+		Error: err.Error(),
+		Code:  ErrorCodeModel,
+	}
+	if pe := modelerrors.Classify(err); pe != nil {
+		event.Error = pe.Message
+		event.Kind = string(pe.Kind)
+		event.Remediation = pe.Remediation
+		event.Detail = err.Error()
+	}
+	return event
+}
+
 type ShellOutputEvent struct {
 	Type   string `json:"type"`
 	Output string `json:"output"`
@@ -232,6 +310,9 @@ type ModelFallbackEvent struct {
 	Reason        string `json:"reason"`
 	Attempt       int    `json:"attempt"`      // Current attempt number (1-indexed)
 	MaxAttempts   int    `json:"max_attempts"` // Total attempts allowed for this model
+	// Discarded is set when the failed model had streamed part of a response
+	// that clients should drop.
+	Discarded bool `json:"discarded,omitempty"`
 	AgentContext
 }
 
@@ -248,6 +329,39 @@ func ModelFallback(agentName, failedModel, fallbackModel, reason string, attempt
 	}
 }
 
+// ModelRetryEvent is emitted before the runtime retries a model after a
+// retryable error (5xx, timeouts, streams dying mid-response).
+//
+// When the failed attempt had already streamed part of a response, that
+// partial output is either resumed by the retry (Resumed) or thrown away
+// (Discarded): clients should then drop what they displayed of it.
+type ModelRetryEvent struct {
+	Type        string `json:"type"`
+	Model       string `json:"model"`
+	Reason      string `json:"reason"`
+	Attempt     int    `json:"attempt"`      // Attempt about to be made (1-indexed)
+	MaxAttempts int    `json:"max_attempts"` // Total attempts allowed for this model
+	DelayMs     int64  `json:"delay_ms"`     // Backoff before the attempt
+	Resumed     bool   `json:"resumed,omitempty"`
+	Discarded   bool   `json:"discarded,omitempty"`
+	AgentContext
+}
+
+// ModelRetry creates a new ModelRetryEvent.
+func ModelRetry(agentName, model, reason string, attempt, maxAttempts int, delay time.Duration, resumed, discarded bool) Event {
+	return &ModelRetryEvent{
+		Type:         "model_retry",
+		Model:        model,
+		Reason:       reason,
+		Attempt:      attempt,
+		MaxAttempts:  maxAttempts,
+		DelayMs:      delay.Milliseconds(),
+		Resumed:      resumed,
+		Discarded:    discarded,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
 type TokenUsageEvent struct {
 	Type      string `json:"type"`
 	SessionID string `json:"session_id"`
@@ -261,16 +375,55 @@ type Usage struct {
 	ContextLength int64         `json:"context_length"`
 	ContextLimit  int64         `json:"context_limit"`
 	Cost          float64       `json:"cost"`
+	Cache         *CacheUsage   `json:"cache,omitempty"`
 	LastMessage   *MessageUsage `json:"last_message,omitempty"`
 }
 
+// CacheUsage reports how much of a session's input was served from the
+// provider's prompt cache. It is only set once caching kicked in.
+type CacheUsage struct {
+	// InputTokens is the total number of uncached input tokens.
+	InputTokens int64 `json:"input_tokens"`
+	// ReadTokens is the total number of input tokens read from the cache.
+	ReadTokens int64 `json:"read_tokens"`
+	// WriteTokens is the total number of input tokens written to the cache.
+	WriteTokens int64 `json:"write_tokens"`
+}
+
+// HitRate returns the fraction of input tokens that were read from the cache.
+func (c *CacheUsage) HitRate() float64 {
+	total := c.InputTokens + c.ReadTokens + c.WriteTokens
+	if total == 0 {
+		return 0
+	}
+	return float64(c.ReadTokens) / float64(total)
+}
+
+// NewCacheUsage builds a CacheUsage from cumulative token usage.
+// Returns nil if nothing was read from or written to the cache.
+func NewCacheUsage(usage chat.Usage) *CacheUsage {
+	if usage.CachedInputTokens == 0 && usage.CacheWriteTokens == 0 {
+		return nil
+	}
+	return &CacheUsage{
+		InputTokens: usage.InputTokens,
+		ReadTokens:  usage.CachedInputTokens,
+		WriteTokens: usage.CacheWriteTokens,
+	}
+}
+
 // MessageUsage contains per-message usage data to include in TokenUsageEvent.
-// It embeds chat.Usage and adds Cost and Model fields.
+// It embeds chat.Usage and adds the cost, model, latency and response ID of
+// the message.
 type MessageUsage struct {
 	chat.Usage
 	chat.RateLimit
 	Cost  float64
 	Model string
+	// LatencyMs is the wall-clock time of the model call, in milliseconds.
+	LatencyMs int64
+	// ResponseID is the ID the provider gave to the response.
+	ResponseID string
 }
 
 // NewTokenUsageEvent creates a TokenUsageEvent with the given usage data.
@@ -292,6 +445,33 @@ func SessionUsage(sess *session.Session, contextLimit int64) *Usage {
 		ContextLength: sess.InputTokens + sess.OutputTokens,
 		ContextLimit:  contextLimit,
 		Cost:          sess.OwnCost(),
+		Cache:         NewCacheUsage(sess.OwnUsage()),
+	}
+}
+
+// ContextUsageEvent reports how much of the model's context window the
+// prompt of the last request used. It is sent after every model call.
+type ContextUsageEvent struct {
+	Type         string `json:"type"`
+	SessionID    string `json:"session_id"`
+	PromptTokens int64  `json:"prompt_tokens"`
+	ContextLimit int64  `json:"context_limit"`
+	// Percent is the percentage of the context window in use.
+	Percent int `json:"percent"`
+	// Threshold is the highest warning threshold reached, or 0.
+	Threshold int `json:"threshold,omitempty"`
+	AgentContext
+}
+
+func ContextUsage(sessionID, agentName string, promptTokens, contextLimit int64, threshold int) Event {
+	return &ContextUsageEvent{
+		Type:         "context_usage",
+		SessionID:    sessionID,
+		PromptTokens: promptTokens,
+		ContextLimit: contextLimit,
+		Percent:      int(promptTokens * 100 / contextLimit),
+		Threshold:    threshold,
+		AgentContext: newAgentContext(agentName),
 	}
 }
 
@@ -310,6 +490,89 @@ func SessionTitle(sessionID, title string) Event {
 	}
 }
 
+// ArtifactAddedEvent is sent when a tool registers a file as an artifact of
+// the session.
+type ArtifactAddedEvent struct {
+	Type      string           `json:"type"`
+	SessionID string           `json:"session_id"`
+	Artifact  session.Artifact `json:"artifact"`
+	AgentContext
+}
+
+func (e *ArtifactAddedEvent) GetSessionID() string { return e.SessionID }
+
+func ArtifactAdded(sessionID string, artifact session.Artifact, agentName string) Event {
+	return &ArtifactAddedEvent{
+		Type:         "artifact_added",
+		SessionID:    sessionID,
+		Artifact:     artifact,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// BlackboardUpdatedEvent is sent when an agent writes or removes an entry of
+// the blackboard of a team run. Entry is nil when the entry was removed.
+type BlackboardUpdatedEvent struct {
+	Type      string                   `json:"type"`
+	SessionID string                   `json:"session_id"`
+	Key       string                   `json:"key"`
+	Entry     *session.BlackboardEntry `json:"entry,omitempty"`
+	AgentContext
+}
+
+func (e *BlackboardUpdatedEvent) GetSessionID() string { return e.SessionID }
+
+func BlackboardUpdated(sessionID, key string, entry *session.BlackboardEntry, agentName string) Event {
+	return &BlackboardUpdatedEvent{
+		Type:         "blackboard_updated",
+		SessionID:    sessionID,
+		Key:          key,
+		Entry:        entry,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// TaskBudgetExceededEvent is sent when a delegated task reaches one of the
+// limits of its budget: max_turns, max_cost or max_time. The task stops and
+// the agent that delegated it gets a failure.
+type TaskBudgetExceededEvent struct {
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	Limit     string `json:"limit"`
+	Reason    string `json:"reason"`
+	AgentContext
+}
+
+func (e *TaskBudgetExceededEvent) GetSessionID() string { return e.SessionID }
+
+func TaskBudgetExceeded(sessionID, agentName, limit, reason string) Event {
+	return &TaskBudgetExceededEvent{
+		Type:         "task_budget_exceeded",
+		SessionID:    sessionID,
+		Limit:        limit,
+		Reason:       reason,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// RunQueuedEvent is sent by the API server while a run waits for a free
+// slot. Position is the number of runs to start before it, plus one.
+type RunQueuedEvent struct {
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	Position  int    `json:"position"`
+	AgentContext
+}
+
+func RunQueued(sessionID string, position int) Event {
+	return &RunQueuedEvent{
+		Type:         "run_queued",
+		SessionID:    sessionID,
+		Position:     position,
+		AgentContext: newAgentContext(""),
+	}
+}
+
 type SessionSummaryEvent struct {
 	Type      string `json:"type"`
 	SessionID string `json:"session_id"`
@@ -342,6 +605,143 @@ func SessionCompaction(sessionID, status, agentName string) Event {
 	}
 }
 
+// RecordAddedEvent is sent when an interaction that isn't a message, like a
+// tool approval or a model switch, is recorded in the session.
+type RecordAddedEvent struct {
+	Type      string          `json:"type"`
+	SessionID string          `json:"session_id"`
+	Record    *session.Record `json:"record"`
+	AgentContext
+}
+
+func RecordAdded(sessionID string, record *session.Record, agentName string) Event {
+	return &RecordAddedEvent{
+		Type:         "record_added",
+		SessionID:    sessionID,
+		Record:       record,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// PromptCompressionEvent reports the tokens saved by compressing old tool
+// outputs before a model call.
+type PromptCompressionEvent struct {
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	// Compressed is the number of compressed tool outputs.
+	Compressed       int   `json:"compressed"`
+	OriginalTokens   int64 `json:"original_tokens"`
+	CompressedTokens int64 `json:"compressed_tokens"`
+	AgentContext
+}
+
+func PromptCompression(sessionID, agentName string, compressed int, originalTokens, compressedTokens int64) Event {
+	return &PromptCompressionEvent{
+		Type:             "prompt_compression",
+		SessionID:        sessionID,
+		Compressed:       compressed,
+		OriginalTokens:   originalTokens,
+		CompressedTokens: compressedTokens,
+		AgentContext:     newAgentContext(agentName),
+	}
+}
+
+// GuardrailEvent is emitted when the final answer of an agent doesn't follow
+// one of its guardrails. Action is what was done about it: block, retry or
+// annotate. Blocked and retried answers are withheld (Discarded): clients
+// should drop what they displayed of them.
+type GuardrailEvent struct {
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	Guardrail string `json:"guardrail"`
+	Action    string `json:"action"`
+	Reason    string `json:"reason"`
+	Discarded bool   `json:"discarded,omitempty"`
+	AgentContext
+}
+
+func Guardrail(sessionID, agentName, guardrail, action, reason string, discarded bool) Event {
+	return &GuardrailEvent{
+		Type:         "guardrail",
+		SessionID:    sessionID,
+		Guardrail:    guardrail,
+		Action:       action,
+		Reason:       reason,
+		Discarded:    discarded,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// ContentFilteredEvent is sent when content filters flag the result of a
+// tool call as a possible prompt injection, before it reaches the model.
+type ContentFilteredEvent struct {
+	Type      string         `json:"type"`
+	SessionID string         `json:"session_id"`
+	ToolCall  tools.ToolCall `json:"tool_call"`
+	Filters   []string       `json:"filters"`
+	// Action is what happens to the result: warn, strip or approve.
+	Action  string   `json:"action"`
+	Matches []string `json:"matches"`
+	AgentContext
+}
+
+func ContentFiltered(sessionID, agentName string, toolCall tools.ToolCall, filters []string, action string, matches []string) Event {
+	return &ContentFilteredEvent{
+		Type:         "content_filtered",
+		SessionID:    sessionID,
+		ToolCall:     toolCall,
+		Filters:      filters,
+		Action:       action,
+		Matches:      matches,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// ToolResultConfirmationEvent asks the user whether the result of a tool call
+// that content filters flagged can be sent to the model. It's answered with
+// an approval, for this result only, or a rejection, which withholds it.
+type ToolResultConfirmationEvent struct {
+	Type      string         `json:"type"`
+	SessionID string         `json:"session_id"`
+	ToolCall  tools.ToolCall `json:"tool_call"`
+	Filters   []string       `json:"filters"`
+	// Matches are the flagged parts of the result.
+	Matches []string `json:"matches"`
+	AgentContext
+}
+
+func ToolResultConfirmation(sessionID, agentName string, toolCall tools.ToolCall, filters, matches []string) Event {
+	return &ToolResultConfirmationEvent{
+		Type:         "tool_result_confirmation",
+		SessionID:    sessionID,
+		ToolCall:     toolCall,
+		Filters:      filters,
+		Matches:      matches,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// ToolCallSimulatedEvent is sent when a tool call is simulated instead of
+// run, in sessions that simulate their tools. The simulated calls of a run
+// are the plan of actions it would carry out.
+type ToolCallSimulatedEvent struct {
+	Type           string         `json:"type"`
+	SessionID      string         `json:"session_id"`
+	ToolCall       tools.ToolCall `json:"tool_call"`
+	ToolDefinition tools.Tool     `json:"tool_definition"`
+	AgentContext
+}
+
+func ToolCallSimulated(sessionID string, toolCall tools.ToolCall, toolDefinition tools.Tool, agentName string) Event {
+	return &ToolCallSimulatedEvent{
+		Type:           "tool_call_simulated",
+		SessionID:      sessionID,
+		ToolCall:       toolCall,
+		ToolDefinition: toolDefinition,
+		AgentContext:   newAgentContext(agentName),
+	}
+}
+
 type StreamStoppedEvent struct {
 	Type      string `json:"type"`
 	SessionID string `json:"session_id,omitempty"`
@@ -408,6 +808,31 @@ func MaxIterationsReached(maxIterations int) Event {
 	}
 }
 
+// RunPausedEvent is sent when a run stops at its max_iterations limit and the
+// user didn't continue it. The session keeps its state: the run can be
+// continued later, without a new message, for more iterations.
+type RunPausedEvent struct {
+	Type          string `json:"type"`
+	SessionID     string `json:"session_id"`
+	Reason        string `json:"reason"`
+	MaxIterations int    `json:"max_iterations"`
+	AgentContext
+}
+
+// RunPausedReasonMaxIterations is the reason of runs paused at their
+// max_iterations limit.
+const RunPausedReasonMaxIterations = "max_iterations"
+
+func RunPaused(sessionID, agentName string, maxIterations int) Event {
+	return &RunPausedEvent{
+		Type:          "run_paused",
+		SessionID:     sessionID,
+		Reason:        RunPausedReasonMaxIterations,
+		MaxIterations: maxIterations,
+		AgentContext:  newAgentContext(agentName),
+	}
+}
+
 // MCPInitStartedEvent is for MCP initialization lifecycle events
 type MCPInitStartedEvent struct {
 	Type string `json:"type"`
@@ -517,6 +942,66 @@ func ToolsetInfo(availableTools int, loading bool, agentName string) Event {
 	}
 }
 
+// ToolsetStatusEvent is sent as each toolset of an agent is starting, then
+// ready or failed to start, so that clients can show a startup checklist.
+type ToolsetStatusEvent struct {
+	Type    string              `json:"type"`
+	Toolset string              `json:"toolset"`
+	Status  agent.ToolSetStatus `json:"status"`
+	Error   string              `json:"error,omitempty"`
+	AgentContext
+}
+
+func ToolsetStatus(toolset string, status agent.ToolSetStatus, err error, agentName string) Event {
+	e := &ToolsetStatusEvent{
+		Type:         "toolset_status",
+		Toolset:      toolset,
+		Status:       status,
+		AgentContext: newAgentContext(agentName),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// ToolListChangedEvent is sent when a toolset, e.g. an MCP server, adds,
+// removes or changes tools in the middle of a session.
+type ToolListChangedEvent struct {
+	Type    string   `json:"type"`
+	Toolset string   `json:"toolset"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	AgentContext
+}
+
+func ToolListChanged(toolset string, change tools.ToolsChange, agentName string) Event {
+	return &ToolListChangedEvent{
+		Type:         "tool_list_changed",
+		Toolset:      toolset,
+		Added:        change.Added,
+		Removed:      change.Removed,
+		Changed:      change.Changed,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// Text returns a user-visible description of the change.
+func (e *ToolListChangedEvent) Text() string {
+	var changes []string
+	if len(e.Added) > 0 {
+		changes = append(changes, "added "+strings.Join(e.Added, ", "))
+	}
+	if len(e.Removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(e.Removed, ", "))
+	}
+	if len(e.Changed) > 0 {
+		changes = append(changes, "changed "+strings.Join(e.Changed, ", "))
+	}
+	return e.Toolset + " changed its tools: " + strings.Join(changes, "; ")
+}
+
 // RAGIndexingStartedEvent is for RAG lifecycle events
 type RAGIndexingStartedEvent struct {
 	Type         string `json:"type"`
@@ -554,6 +1039,25 @@ func RAGIndexingProgress(ragName, strategyName string, current, total int, agent
 	}
 }
 
+// ModelPullProgressEvent reports progress while a local model is pulled
+// through Docker Model Runner, e.g. after switching to a model that isn't
+// available locally yet.
+type ModelPullProgressEvent struct {
+	Type   string `json:"type"`
+	Model  string `json:"model"`
+	Status string `json:"status"`
+	AgentContext
+}
+
+func ModelPullProgress(model, status, agentName string) Event {
+	return &ModelPullProgressEvent{
+		Type:         "model_pull_progress",
+		Model:        model,
+		Status:       status,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
 type RAGIndexingCompletedEvent struct {
 	Type         string `json:"type"`
 	RAGName      string `json:"rag_name"`
@@ -0,0 +1,192 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// EventSink consumes the events of a runtime. Sinks are called
+// synchronously, in the order they subscribed, so they must not block:
+// slow consumers (e.g. webhooks) should queue events themselves.
+type EventSink interface {
+	HandleEvent(sessionID string, event Event)
+}
+
+// EventSinkFunc adapts a function to an EventSink.
+type EventSinkFunc func(sessionID string, event Event)
+
+func (f EventSinkFunc) HandleEvent(sessionID string, event Event) {
+	f(sessionID, event)
+}
+
+// EventBus publishes the events of the top-level sessions of a runtime to
+// every subscribed sink. Events of sub-sessions are forwarded to their
+// parent session, so each event is published once, with the ID of the
+// top-level session.
+type EventBus struct {
+	mu     sync.RWMutex
+	nextID int
+	sinks  []subscription
+}
+
+type subscription struct {
+	id   int
+	sink EventSink
+}
+
+// NewEventBus creates an event bus without sinks.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe adds a sink to the bus and returns a function that removes it.
+func (b *EventBus) Subscribe(sink EventSink) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	// Copy on write so that Publish can iterate over a snapshot without
+	// holding the lock while sinks run.
+	b.sinks = append(b.sinks[:len(b.sinks):len(b.sinks)], subscription{id: id, sink: sink})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.unsubscribe(id) })
+	}
+}
+
+func (b *EventBus) unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sinks := make([]subscription, 0, len(b.sinks))
+	for _, s := range b.sinks {
+		if s.id != id {
+			sinks = append(sinks, s)
+		}
+	}
+	b.sinks = sinks
+}
+
+// Publish sends an event to every sink.
+func (b *EventBus) Publish(sessionID string, event Event) {
+	b.mu.RLock()
+	sinks := b.sinks
+	b.mu.RUnlock()
+
+	for _, s := range sinks {
+		s.sink.HandleEvent(sessionID, event)
+	}
+}
+
+// NewJSONLSink returns a sink that writes every event to w as a line of
+// JSON, along with the ID of its session.
+func NewJSONLSink(w io.Writer) EventSink {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return EventSinkFunc(func(sessionID string, event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		err := enc.Encode(struct {
+			SessionID string `json:"session_id"`
+			Event     Event  `json:"event"`
+		}{sessionID, event})
+		if err != nil {
+			slog.Debug("Failed to write event", "session_id", sessionID, "error", err)
+		}
+	})
+}
+
+// eventStream is a top-level stream being consumed by the caller of
+// RunStream. Events that don't come from the loop of the session are sent
+// to it through injected, which is never closed: done tells senders that
+// the stream is over.
+type eventStream struct {
+	sess     *session.Session
+	injected chan Event
+	done     chan struct{}
+}
+
+// send delivers an event to the caller of the stream.
+func (s *eventStream) send(ctx context.Context, event Event) error {
+	select {
+	case s.injected <- event:
+		return nil
+	case <-s.done:
+		return errors.New("the event stream is closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// currentStream returns the most recent top-level stream, or nil.
+func (r *LocalRuntime) currentStream() *eventStream {
+	r.streamsMu.Lock()
+	defer r.streamsMu.Unlock()
+
+	if len(r.streams) == 0 {
+		return nil
+	}
+	return r.streams[len(r.streams)-1]
+}
+
+func (r *LocalRuntime) removeStream(stream *eventStream) {
+	r.streamsMu.Lock()
+	defer r.streamsMu.Unlock()
+
+	r.streams = slices.DeleteFunc(r.streams, func(s *eventStream) bool { return s == stream })
+}
+
+// publishEvents forwards the events of a top-level session to the caller,
+// along with the events sent to the stream from outside the loop, and
+// publishes them on the event bus. Events of sub-sessions are already
+// forwarded to their parent session, so they are only published once.
+func (r *LocalRuntime) publishEvents(sess *session.Session, in <-chan Event) <-chan Event {
+	if sess.ParentID != "" {
+		if r.runLog != nil {
+			r.runLogRoots.Store(sess.ID, r.runLogSessionID(sess))
+		}
+		return in
+	}
+
+	stream := &eventStream{
+		sess:     sess,
+		injected: make(chan Event),
+		done:     make(chan struct{}),
+	}
+	r.streamsMu.Lock()
+	r.streams = append(r.streams, stream)
+	r.streamsMu.Unlock()
+
+	out := make(chan Event, cap(in))
+	go func() {
+		defer close(out)
+		defer close(stream.done)
+		defer r.removeStream(stream)
+
+		for {
+			var event Event
+			select {
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				event = e
+			case event = <-stream.injected:
+			}
+
+			r.events.Publish(sess.ID, event)
+			out <- event
+		}
+	}()
+	return out
+}
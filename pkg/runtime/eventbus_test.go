@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestEventBus(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus()
+
+	var received []string
+	first := bus.Subscribe(EventSinkFunc(func(sessionID string, _ Event) {
+		received = append(received, "first:"+sessionID)
+	}))
+	bus.Subscribe(EventSinkFunc(func(sessionID string, _ Event) {
+		received = append(received, "second:"+sessionID)
+	}))
+
+	bus.Publish("a", StreamStarted("a", "root"))
+	first()
+	first()
+	bus.Publish("b", StreamStarted("b", "root"))
+
+	assert.Equal(t, []string{"first:a", "second:a", "second:b"}, received)
+}
+
+func TestJSONLSink(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	sink := NewJSONLSink(&buf)
+	sink.HandleEvent("sess", StreamStarted("sess", "root"))
+	sink.HandleEvent("sess", StreamStopped("sess", "root"))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var line struct {
+		SessionID string         `json:"session_id"`
+		Event     map[string]any `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal(lines[1], &line))
+	assert.Equal(t, "sess", line.SessionID)
+	assert.Equal(t, "stream_stopped", line.Event["type"])
+}
+
+func newBusRuntime(t *testing.T, opts ...Opt) *LocalRuntime {
+	t.Helper()
+
+	stream := newStreamBuilder().
+		AddContent("Hello").
+		AddStopWithUsage(3, 2).
+		Build()
+
+	prov := &mockProvider{id: "test/mock-model", stream: stream}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := NewLocalRuntime(tm, append([]Opt{WithSessionCompaction(false), WithModelStore(mockModelStore{})}, opts...)...)
+	require.NoError(t, err)
+	return rt
+}
+
+func TestRunStreamPublishesEvents(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var published []Event
+	rt := newBusRuntime(t, WithEventSink(EventSinkFunc(func(sessionID string, event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, event)
+	})))
+
+	sess := session.New(session.WithUserMessage("Hi"))
+
+	var events []Event
+	for ev := range rt.RunStream(t.Context(), sess) {
+		events = append(events, ev)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, events, published)
+}
+
+func TestElicitationReachesTopLevelStream(t *testing.T) {
+	t.Parallel()
+
+	rt := newBusRuntime(t)
+
+	_, err := rt.elicitationHandler(t.Context(), &mcp.ElicitParams{Message: "no stream"})
+	require.Error(t, err)
+
+	// Simulate a session whose loop is busy while a toolset asks the user
+	// for input.
+	in := make(chan Event)
+	out := rt.publishEvents(session.New(), in)
+
+	type elicitation struct {
+		result tools.ElicitationResult
+		err    error
+	}
+	done := make(chan elicitation, 1)
+	go func() {
+		result, err := rt.elicitationHandler(t.Context(), &mcp.ElicitParams{Message: "Your name?"})
+		done <- elicitation{result, err}
+	}()
+
+	event := <-out
+	request, ok := event.(*ElicitationRequestEvent)
+	require.True(t, ok)
+	assert.Equal(t, "Your name?", request.Message)
+
+	rt.elicitationRequestCh <- ElicitationResult{Action: tools.ElicitationActionAccept}
+	res := <-done
+	require.NoError(t, res.err)
+	assert.Equal(t, tools.ElicitationActionAccept, res.result.Action)
+
+	// The answer is recorded in the session of the stream
+	event = <-out
+	recorded, ok := event.(*RecordAddedEvent)
+	require.True(t, ok)
+	assert.Equal(t, "Your name?", recorded.Record.Elicitation.Message)
+	assert.Equal(t, "accept", recorded.Record.Elicitation.Action)
+
+	close(in)
+	_, open := <-out
+	assert.False(t, open)
+
+	_, err = rt.elicitationHandler(t.Context(), &mcp.ElicitParams{Message: "stream over"})
+	require.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/docker/docker-agent/pkg/agent"
@@ -165,6 +166,11 @@ func getEffectiveRetries(a *agent.Agent) int {
 // - Retryable errors (5xx, timeouts): retry the same model with exponential backoff
 // - Non-retryable errors (429, 4xx): skip to the next model in the chain immediately
 //
+// When a stream dies mid-response, the retry resumes from the partial content
+// if the model supports it (see canResume). Otherwise the partial output is
+// discarded and the response starts over; the retry and fallback events tell
+// clients which of the two happened.
+//
 // Cooldown behavior:
 //   - When the primary fails with a non-retryable error and a fallback succeeds, the runtime
 //     "sticks" with that fallback for a configurable cooldown period.
@@ -192,6 +198,7 @@ func (r *LocalRuntime) tryModelWithFallback(
 	}
 
 	fallbackRetries := getEffectiveRetries(a)
+	initialBackoff, maxBackoff := a.FallbackBackoff()
 
 	// Build the chain of models to try: primary (index 0) + fallbacks (index 1+)
 	modelChain := buildModelChain(primaryModel, fallbackModels)
@@ -214,9 +221,21 @@ func (r *LocalRuntime) tryModelWithFallback(
 	primaryFailedWithNonRetryable := false
 	hasFallbacks := len(fallbackModels) > 0
 
+	// resume holds the output streamed by failed attempts that the next
+	// attempt continues from. discarded is set when a failed attempt streamed
+	// output that is thrown away instead.
+	var resume streamResult
+	discarded := false
+
 	for chainIdx := startIndex; chainIdx < len(modelChain); chainIdx++ {
 		modelEntry := modelChain[chainIdx]
 
+		// A partial response is never resumed by another model
+		if resume.Content != "" {
+			resume = streamResult{}
+			discarded = true
+		}
+
 		// Each model in the chain gets (1 + retries) attempts for retryable errors.
 		// Non-retryable errors (429 with fallbacks, 4xx) skip immediately to the next model.
 		// 429 without fallbacks is retried directly on the same model.
@@ -230,8 +249,19 @@ func (r *LocalRuntime) tryModelWithFallback(
 
 			// Apply backoff before retry (not on first attempt of each model)
 			if attempt > 0 {
-				backoff := modelerrors.CalculateBackoff(attempt - 1)
+				backoff := modelerrors.CalculateBackoffWithin(attempt-1, initialBackoff, maxBackoff)
 				logRetryBackoff(a.Name(), modelEntry.provider.ID(), attempt, backoff)
+				events <- ModelRetry(
+					a.Name(),
+					modelEntry.provider.ID(),
+					errorReason(lastErr),
+					attempt+1,
+					maxAttempts,
+					backoff,
+					resume.Content != "",
+					discarded,
+				)
+				discarded = false
 				if !modelerrors.SleepWithContext(ctx, backoff) {
 					return streamResult{}, nil, ctx.Err()
 				}
@@ -242,18 +272,25 @@ func (r *LocalRuntime) tryModelWithFallback(
 				logFallbackAttempt(a.Name(), modelEntry, attempt, fallbackRetries, lastErr)
 				// Get the previous model's ID for the event
 				prevModelID := modelChain[chainIdx-1].provider.ID()
-				reason := ""
-				if lastErr != nil {
-					reason = lastErr.Error()
-				}
-				events <- ModelFallback(
+				fallback := ModelFallback(
 					a.Name(),
 					prevModelID,
 					modelEntry.provider.ID(),
-					reason,
+					errorReason(lastErr),
 					attempt+1,
 					maxAttempts,
-				)
+				).(*ModelFallbackEvent)
+				fallback.Discarded = discarded
+				events <- fallback
+				r.addRecord(sess, &session.Record{
+					AgentName: a.Name(),
+					ModelSwitch: &session.ModelSwitchRecord{
+						From:   prevModelID,
+						To:     modelEntry.provider.ID(),
+						Reason: errorReason(lastErr),
+					},
+				}, events)
+				discarded = false
 			}
 
 			slog.Debug("Creating chat completion stream",
@@ -263,8 +300,29 @@ func (r *LocalRuntime) tryModelWithFallback(
 				"in_cooldown", inCooldown,
 				"attempt", attempt+1)
 
-			stream, err := modelEntry.provider.CreateChatCompletionStream(ctx, messages, agentTools)
+			attemptMessages := messages
+			if resume.Content != "" {
+				attemptMessages = append(slices.Clip(messages), chat.Message{
+					Role:    chat.MessageRoleAssistant,
+					Content: resume.Content,
+				})
+			}
+
+			modelTools := toolsForModel(agentTools, modelEntry.provider)
+			r.recordModelRequest(sess, a.Name(), modelEntry.provider.ID(), attemptMessages, modelTools)
+			requestStart := time.Now()
+
+			// The watchdog cancels the request if the model stays silent for
+			// too long, turning a hung stream into a retryable timeout.
+			requestCtx, cancelRequest := context.WithCancel(ctx)
+			watchdog := newIdleWatchdog(a.StreamIdleTimeout(), cancelRequest)
+
+			stream, err := modelEntry.provider.CreateChatCompletionStream(requestCtx, attemptMessages, modelTools)
 			if err != nil {
+				watchdog.stop()
+				cancelRequest()
+				err = watchdog.wrap(err)
+				r.recordModelResponse(sess, a.Name(), modelEntry.provider.ID(), requestStart, streamResult{}, err)
 				lastErr = err
 
 				// Context cancellation is never retryable
@@ -283,7 +341,10 @@ func (r *LocalRuntime) tryModelWithFallback(
 
 			// Stream created successfully, now handle it
 			slog.Debug("Processing stream", "agent", a.Name(), "model", modelEntry.provider.ID())
-			res, err := r.handleStream(ctx, stream, a, agentTools, sess, m, events)
+			res, err := r.handleStream(ctx, watchdog.stream(stream), a, agentTools, sess, m, events)
+			watchdog.stop()
+			cancelRequest()
+			r.recordModelResponse(sess, a.Name(), modelEntry.provider.ID(), requestStart, res, err)
 			if err != nil {
 				lastErr = err
 
@@ -295,12 +356,33 @@ func (r *LocalRuntime) tryModelWithFallback(
 				decision := r.handleModelError(ctx, err, a, modelEntry, attempt, hasFallbacks, &primaryFailedWithNonRetryable)
 				if decision == retryDecisionReturn {
 					return streamResult{}, nil, ctx.Err()
-				} else if decision == retryDecisionBreak {
+				}
+
+				// Keep what the failed stream produced for the next attempt
+				// to continue from, when the model can.
+				if res.hasOutput() {
+					if decision == retryDecisionContinue && canResume(modelEntry.provider, res) {
+						resume = resume.resumedBy(res)
+						slog.Debug("Resuming partial response on retry",
+							"agent", a.Name(),
+							"model", modelEntry.provider.ID(),
+							"content_length", len(resume.Content))
+					} else {
+						resume = streamResult{}
+						discarded = true
+					}
+				}
+
+				if decision == retryDecisionBreak {
 					break
 				}
 				continue
 			}
 
+			if resume.Content != "" {
+				res = resume.resumedBy(res)
+			}
+
 			// Success!
 			// Handle cooldown state based on which model succeeded
 			switch {
@@ -331,6 +413,27 @@ func (r *LocalRuntime) tryModelWithFallback(
 	return streamResult{}, nil, errors.New("all models failed with unknown error")
 }
 
+// canResume reports whether a model can continue a response that a failure
+// cut short, i.e. whether it accepts a trailing assistant message to continue
+// from (see provider.ResponseResumer). Responses that started reasoning or
+// calling tools are never resumed.
+func canResume(model provider.Provider, partial streamResult) bool {
+	if partial.Content == "" || partial.ReasoningContent != "" || len(partial.Calls) > 0 {
+		return false
+	}
+
+	resumer, ok := model.(provider.ResponseResumer)
+	return ok && resumer.CanResumeResponse()
+}
+
+// errorReason returns the message of err, or an empty string if err is nil.
+func errorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
 // retryDecision is the outcome of handleModelError.
 type retryDecision int
 
@@ -421,3 +524,14 @@ func (r *LocalRuntime) handleModelError(
 		"error", err)
 	return retryDecisionContinue
 }
+
+// toolsForModel drops the provider-native tools that the given model can't
+// use. Rule-based routers and alloys are left alone: they filter per
+// selected model.
+func toolsForModel(agentTools []tools.Tool, model provider.Provider) []tools.Tool {
+	cfg := model.BaseConfig().ModelConfig
+	if len(cfg.Routing) > 0 || cfg.Alloy != nil {
+		return agentTools
+	}
+	return tools.ForProvider(agentTools, cfg.Provider)
+}
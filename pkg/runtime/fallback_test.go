@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"testing/synctest"
 	"time"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/model/provider/base"
 	"github.com/docker/docker-agent/pkg/modelerrors"
@@ -730,3 +732,130 @@ func TestRateLimitGate_EnabledWithFallbacks_SkipsToFallback(t *testing.T) {
 		assert.Equal(t, 1, primary.callCount, "primary should only be called once — fallbacks take priority over retry")
 	})
 }
+
+// brokenStream streams its responses, then fails instead of ending.
+type brokenStream struct {
+	mockStream
+	err error
+}
+
+func (s *brokenStream) Recv() (chat.MessageStreamResponse, error) {
+	resp, err := s.mockStream.Recv()
+	if errors.Is(err, io.EOF) {
+		return resp, s.err
+	}
+	return resp, err
+}
+
+// scriptedProvider returns its streams in order and records the messages of
+// each request.
+type scriptedProvider struct {
+	resumable bool
+	streams   []chat.MessageStream
+	requests  [][]chat.Message
+}
+
+func (p *scriptedProvider) ID() string { return "test/scripted" }
+func (p *scriptedProvider) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	p.requests = append(p.requests, messages)
+	stream := p.streams[0]
+	p.streams = p.streams[1:]
+	return stream, nil
+}
+
+func (p *scriptedProvider) BaseConfig() base.Config {
+	return base.Config{ModelConfig: latest.ModelConfig{Model: "scripted"}}
+}
+
+func (p *scriptedProvider) CanResumeResponse() bool { return p.resumable }
+
+func runMidStreamFailure(t *testing.T, resumable bool) (*scriptedProvider, *session.Session, []*ModelRetryEvent) {
+	t.Helper()
+
+	prov := &scriptedProvider{
+		resumable: resumable,
+		streams: []chat.MessageStream{
+			&brokenStream{
+				mockStream: *newStreamBuilder().AddContent("Hello ").Build(),
+				err:        errors.New("read: connection reset by peer"),
+			},
+			newStreamBuilder().AddContent(" world").AddStopWithUsage(10, 5).Build(),
+		},
+	}
+
+	root := agent.New("root", "test", agent.WithModel(prov), agent.WithFallbackBackoff(time.Second, 5*time.Second))
+	tm := team.New(team.WithAgents(root))
+	rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"))
+	sess.Title = "Mid-stream failure"
+
+	var retries []*ModelRetryEvent
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if retry, ok := ev.(*ModelRetryEvent); ok {
+			retries = append(retries, retry)
+		}
+	}
+	return prov, sess, retries
+}
+
+func TestRetryResumesPartialResponse(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		prov, sess, retries := runMidStreamFailure(t, true)
+
+		require.Len(t, retries, 1)
+		assert.True(t, retries[0].Resumed)
+		assert.False(t, retries[0].Discarded)
+		assert.Equal(t, 2, retries[0].Attempt)
+		assert.Contains(t, retries[0].Reason, "connection reset")
+		assert.GreaterOrEqual(t, retries[0].DelayMs, int64(900))
+
+		// The retry continues from the partial response
+		require.Len(t, prov.requests, 2)
+		last := prov.requests[1][len(prov.requests[1])-1]
+		assert.Equal(t, chat.MessageRoleAssistant, last.Role)
+		assert.Equal(t, "Hello ", last.Content)
+
+		assert.Equal(t, "Hello world", sess.GetLastAssistantMessageContent())
+	})
+}
+
+func TestRetryDiscardsPartialResponse(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		// Models that can't continue a trailing assistant message start over
+		prov, sess, retries := runMidStreamFailure(t, false)
+
+		require.Len(t, retries, 1)
+		assert.False(t, retries[0].Resumed)
+		assert.True(t, retries[0].Discarded)
+
+		// The retry starts the response over
+		require.Len(t, prov.requests, 2)
+		assert.Equal(t, prov.requests[0], prov.requests[1])
+
+		assert.Equal(t, "world", sess.GetLastAssistantMessageContent())
+	})
+}
+
+func TestStreamResultResumedBy(t *testing.T) {
+	t.Parallel()
+
+	partial := streamResult{
+		Content:   "See [1] ",
+		Citations: []chat.Citation{{Source: "https://a.example", ContentOffset: 4}},
+	}
+	next := streamResult{
+		Content:   " and [2].",
+		Citations: []chat.Citation{{Source: "https://b.example", ContentOffset: 5}},
+		Stopped:   true,
+	}
+
+	res := partial.resumedBy(next)
+	assert.Equal(t, "See [1] and [2].", res.Content)
+	assert.True(t, res.Stopped)
+	require.Len(t, res.Citations, 2)
+	assert.Equal(t, 4, res.Citations[0].ContentOffset)
+	assert.Equal(t, 12, res.Citations[1].ContentOffset)
+	assert.Equal(t, "[2]", res.Content[res.Citations[1].ContentOffset:res.Citations[1].ContentOffset+3])
+}
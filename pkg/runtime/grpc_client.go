@@ -0,0 +1,359 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/api/agentv1"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// GRPCClient is a gRPC client for the docker agent server API
+type GRPCClient struct {
+	conn   *grpc.ClientConn
+	client agentv1.AgentServiceClient
+}
+
+var _ RemoteClient = (*GRPCClient)(nil)
+
+// NewGRPCClient creates a gRPC client for the docker agent server, e.g. on
+// localhost:9090 or unix:///path/to/socket. Without options, the connection
+// isn't encrypted.
+func NewGRPCClient(target string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	if len(opts) == 0 {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating gRPC client: %w", err)
+	}
+	return &GRPCClient{conn: conn, client: agentv1.NewAgentServiceClient(conn)}, nil
+}
+
+// Close closes the connection to the server.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetAgents retrieves all available agents
+func (c *GRPCClient) GetAgents(ctx context.Context) ([]api.Agent, error) {
+	resp, err := c.client.ListAgents(ctx, &agentv1.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	agents := make([]api.Agent, 0, len(resp.GetAgents()))
+	for _, agent := range resp.GetAgents() {
+		agents = append(agents, api.Agent{Name: agent.GetName(), Description: agent.GetDescription(), Multi: agent.GetMulti()})
+	}
+	return agents, nil
+}
+
+// GetAgent retrieves an agent configuration by ID
+func (c *GRPCClient) GetAgent(ctx context.Context, id string) (*latest.Config, error) {
+	resp, err := c.client.GetAgent(ctx, &agentv1.AgentRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	var cfg latest.Config
+	if err := json.Unmarshal(resp.GetJson(), &cfg); err != nil {
+		return nil, fmt.Errorf("decoding agent config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// GetAgentToolCount returns the number of tools available for an agent.
+func (c *GRPCClient) GetAgentToolCount(ctx context.Context, agentFilename, agentName string) (int, error) {
+	resp, err := c.client.GetAgentToolCount(ctx, &agentv1.AgentRequest{Id: agentFilename, AgentName: agentName})
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.GetAvailableTools()), nil
+}
+
+// GetSessions retrieves all sessions
+func (c *GRPCClient) GetSessions(ctx context.Context) ([]api.SessionsResponse, error) {
+	resp, err := c.client.ListSessions(ctx, &agentv1.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]api.SessionsResponse, 0, len(resp.GetSessions()))
+	for _, sess := range resp.GetSessions() {
+		sessions = append(sessions, api.SessionsResponse{
+			ID:           sess.GetId(),
+			Title:        sess.GetTitle(),
+			CreatedAt:    sess.GetCreatedAt(),
+			NumMessages:  int(sess.GetNumMessages()),
+			InputTokens:  sess.GetInputTokens(),
+			OutputTokens: sess.GetOutputTokens(),
+			WorkingDir:   sess.GetWorkingDir(),
+		})
+	}
+	return sessions, nil
+}
+
+// GetSession retrieves a session by ID
+func (c *GRPCClient) GetSession(ctx context.Context, id string) (*api.SessionResponse, error) {
+	resp, err := c.client.GetSession(ctx, &agentv1.SessionRequest{SessionId: id})
+	if err != nil {
+		return nil, err
+	}
+	return decodeSession(resp)
+}
+
+// CreateSession creates a new session
+func (c *GRPCClient) CreateSession(ctx context.Context, sessTemplate *session.Session) (*session.Session, error) {
+	req := &agentv1.CreateSessionRequest{
+		WorkingDirs:   sessTemplate.Roots(),
+		MaxIterations: int64(sessTemplate.MaxIterations),
+		ToolsApproved: sessTemplate.ToolsApproved,
+	}
+	if sessTemplate.Permissions != nil {
+		permissions, err := json.Marshal(sessTemplate.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		req.PermissionsJson = permissions
+	}
+
+	resp, err := c.client.CreateSession(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := decodeSession(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &session.Session{
+		ID:            sess.ID,
+		Title:         sess.Title,
+		CreatedAt:     sess.CreatedAt,
+		ToolsApproved: sess.ToolsApproved,
+		Thinking:      sess.Thinking,
+		InputTokens:   sess.InputTokens,
+		OutputTokens:  sess.OutputTokens,
+		WorkingDir:    sess.WorkingDir,
+		WorkingDirs:   sess.WorkingDirs,
+		MaxIterations: sessTemplate.MaxIterations,
+		Permissions:   sess.Permissions,
+	}, nil
+}
+
+// DeleteSession deletes a session by ID
+func (c *GRPCClient) DeleteSession(ctx context.Context, id string) error {
+	_, err := c.client.DeleteSession(ctx, &agentv1.SessionRequest{SessionId: id})
+	return err
+}
+
+// UpdateSessionTitle updates the title of a session
+func (c *GRPCClient) UpdateSessionTitle(ctx context.Context, sessionID, title string) error {
+	_, err := c.client.UpdateSessionTitle(ctx, &agentv1.UpdateSessionTitleRequest{SessionId: sessionID, Title: title})
+	return err
+}
+
+// RegenerateSessionTitle generates a new title for a session and returns it
+func (c *GRPCClient) RegenerateSessionTitle(ctx context.Context, sessionID string) (string, error) {
+	resp, err := c.client.RegenerateSessionTitle(ctx, &agentv1.SessionRequest{SessionId: sessionID})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetTitle(), nil
+}
+
+// ResumeSession resumes a paused session with optional rejection reason, tool
+// name or number of iterations to continue for
+func (c *GRPCClient) ResumeSession(ctx context.Context, id, confirmation, reason, toolName string, iterations int) error {
+	req := agentv1.ResumeSessionRequest{SessionId: id, Confirmation: confirmation, Reason: reason, ToolName: toolName, Iterations: int64(iterations)}
+	_, err := c.client.ResumeSession(ctx, &req)
+	return err
+}
+
+// ResumeElicitation sends an elicitation response
+func (c *GRPCClient) ResumeElicitation(ctx context.Context, sessionID string, action tools.ElicitationAction, content map[string]any) error {
+	req, err := elicitationRequest(action, content)
+	if err != nil {
+		return err
+	}
+	req.SessionId = sessionID
+	_, err = c.client.ResumeElicitation(ctx, req)
+	return err
+}
+
+// RunAgent executes an agent and returns a channel of streaming events
+func (c *GRPCClient) RunAgent(ctx context.Context, sessionID, agent string, messages []api.Message) (<-chan Event, error) {
+	return c.RunAgentWithAgentName(ctx, sessionID, agent, "", messages)
+}
+
+// RunAgentWithAgentName executes an agent with a specific agent name and returns a channel of streaming events
+func (c *GRPCClient) RunAgentWithAgentName(ctx context.Context, sessionID, agent, agentName string, messages []api.Message) (<-chan Event, error) {
+	req, err := runAgentRequest(sessionID, agent, agentName, messages)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := c.client.RunAgent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return receiveEvents(stream), nil
+}
+
+// GRPCSessionStream is a run of an agent on a bidirectional stream, on which
+// its tool call confirmations and elicitations are answered.
+type GRPCSessionStream struct {
+	stream grpc.BidiStreamingClient[agentv1.SessionStreamRequest, agentv1.Event]
+	events <-chan Event
+}
+
+// OpenSession runs an agent on a bidirectional stream. The agent name can be
+// empty to run the root agent.
+func (c *GRPCClient) OpenSession(ctx context.Context, sessionID, agent, agentName string, messages []api.Message) (*GRPCSessionStream, error) {
+	run, err := runAgentRequest(sessionID, agent, agentName, messages)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := c.client.StreamSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&agentv1.SessionStreamRequest{Request: &agentv1.SessionStreamRequest_Run{Run: run}}); err != nil {
+		return nil, err
+	}
+
+	return &GRPCSessionStream{
+		stream: stream,
+		events: receiveEvents(stream),
+	}, nil
+}
+
+// Events returns the events of the run. The channel is closed when the run ends.
+func (s *GRPCSessionStream) Events() <-chan Event {
+	return s.events
+}
+
+// Resume answers a tool call confirmation, with an optional rejection reason or tool name.
+func (s *GRPCSessionStream) Resume(confirmation, reason, toolName string) error {
+	return s.stream.Send(&agentv1.SessionStreamRequest{Request: &agentv1.SessionStreamRequest_Resume{Resume: &agentv1.ResumeSessionRequest{
+		Confirmation: confirmation,
+		Reason:       reason,
+		ToolName:     toolName,
+	}}})
+}
+
+// ResumeElicitation answers an elicitation.
+func (s *GRPCSessionStream) ResumeElicitation(action tools.ElicitationAction, content map[string]any) error {
+	req, err := elicitationRequest(action, content)
+	if err != nil {
+		return err
+	}
+	return s.stream.Send(&agentv1.SessionStreamRequest{Request: &agentv1.SessionStreamRequest_Elicitation{Elicitation: req}})
+}
+
+func runAgentRequest(sessionID, agent, agentName string, messages []api.Message) (*agentv1.RunAgentRequest, error) {
+	req := &agentv1.RunAgentRequest{SessionId: sessionID, Agent: agent, AgentName: agentName}
+	for _, msg := range messages {
+		message := &agentv1.Message{Role: string(msg.Role), Content: msg.Content}
+		if len(msg.MultiContent) > 0 {
+			multiContent, err := json.Marshal(msg.MultiContent)
+			if err != nil {
+				return nil, err
+			}
+			message.MultiContentJson = multiContent
+		}
+		req.Messages = append(req.Messages, message)
+	}
+	return req, nil
+}
+
+func elicitationRequest(action tools.ElicitationAction, content map[string]any) (*agentv1.ResumeElicitationRequest, error) {
+	req := &agentv1.ResumeElicitationRequest{Action: string(action)}
+	if content != nil {
+		data, err := json.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		req.ContentJson = data
+	}
+	return req, nil
+}
+
+// decodeSession converts a session of the gRPC API to the HTTP API.
+func decodeSession(sess *agentv1.Session) (*api.SessionResponse, error) {
+	resp := &api.SessionResponse{
+		ID:            sess.GetId(),
+		Title:         sess.GetTitle(),
+		ToolsApproved: sess.GetToolsApproved(),
+		Thinking:      sess.GetThinking(),
+		InputTokens:   sess.GetInputTokens(),
+		OutputTokens:  sess.GetOutputTokens(),
+		WorkingDir:    sess.GetWorkingDir(),
+		WorkingDirs:   sess.GetWorkingDirs(),
+	}
+	if createdAt := sess.GetCreatedAt(); createdAt != "" {
+		t, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("decoding session: %w", err)
+		}
+		resp.CreatedAt = t
+	}
+	for data, field := range map[*[]byte]any{
+		&sess.MessagesJson:    &resp.Messages,
+		&sess.PermissionsJson: &resp.Permissions,
+		&sess.ArtifactsJson:   &resp.Artifacts,
+		&sess.BlackboardJson:  &resp.Blackboard,
+		&sess.RecordsJson:     &resp.Records,
+	} {
+		if len(*data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(*data, field); err != nil {
+			return nil, fmt.Errorf("decoding session: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// eventReceiver is a stream of events of the gRPC API.
+type eventReceiver interface {
+	Recv() (*agentv1.Event, error)
+}
+
+// receiveEvents decodes the events of a stream until it ends.
+func receiveEvents(stream eventReceiver) <-chan Event {
+	eventChan := make(chan Event, 128)
+
+	go func() {
+		defer close(eventChan)
+
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					slog.Debug("event", "error", err)
+					eventChan <- Error(err.Error())
+				}
+				return
+			}
+
+			e, err := DecodeGRPCEvent(ev)
+			if err != nil {
+				slog.Debug("event", "error", err)
+				continue
+			}
+			eventChan <- e
+		}
+	}()
+
+	return eventChan
+}
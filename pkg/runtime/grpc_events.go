@@ -0,0 +1,311 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/api/agentv1"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// EncodeGRPCEvent converts an event to the gRPC API. The events that have no
+// message of their own are sent as the JSON objects of the HTTP API.
+func EncodeGRPCEvent(event Event) (*agentv1.Event, error) {
+	pe := &agentv1.Event{AgentName: event.GetAgentName()}
+
+	var agentContext AgentContext
+	switch e := event.(type) {
+	case *StreamStartedEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_StreamStarted{StreamStarted: &agentv1.StreamStarted{SessionId: e.SessionID}}
+	case *StreamStoppedEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_StreamStopped{StreamStopped: &agentv1.StreamStopped{SessionId: e.SessionID}}
+	case *AgentChoiceEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_AgentChoice{AgentChoice: &agentv1.AgentChoice{SessionId: e.SessionID, Content: e.Content}}
+	case *AgentChoiceReasoningEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_AgentChoiceReasoning{AgentChoiceReasoning: &agentv1.AgentChoice{SessionId: e.SessionID, Content: e.Content}}
+	case *ToolCallEvent:
+		agentContext = e.AgentContext
+		definition, err := protoToolDefinition(e.ToolDefinition)
+		if err != nil {
+			return nil, err
+		}
+		pe.Event = &agentv1.Event_ToolCall{ToolCall: &agentv1.ToolCallEvent{ToolCall: protoToolCall(e.ToolCall), ToolDefinition: definition}}
+	case *PartialToolCallEvent:
+		agentContext = e.AgentContext
+		definition, err := protoToolDefinition(e.ToolDefinition)
+		if err != nil {
+			return nil, err
+		}
+		pe.Event = &agentv1.Event_PartialToolCall{PartialToolCall: &agentv1.ToolCallEvent{ToolCall: protoToolCall(e.ToolCall), ToolDefinition: definition}}
+	case *ToolCallConfirmationEvent:
+		agentContext = e.AgentContext
+		definition, err := protoToolDefinition(e.ToolDefinition)
+		if err != nil {
+			return nil, err
+		}
+		pe.Event = &agentv1.Event_ToolCallConfirmation{ToolCallConfirmation: &agentv1.ToolCallConfirmation{ToolCall: protoToolCall(e.ToolCall), ToolDefinition: definition}}
+	case *ToolCallResponseEvent:
+		agentContext = e.AgentContext
+		definition, err := protoToolDefinition(e.ToolDefinition)
+		if err != nil {
+			return nil, err
+		}
+		var result []byte
+		if e.Result != nil {
+			if result, err = marshalJSON(e.Result); err != nil {
+				return nil, err
+			}
+		}
+		pe.Event = &agentv1.Event_ToolCallResponse{ToolCallResponse: &agentv1.ToolCallResponse{
+			ToolCall:       protoToolCall(e.ToolCall),
+			ToolDefinition: definition,
+			Response:       e.Response,
+			ResultJson:     result,
+		}}
+	case *ErrorEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_Error{Error: &agentv1.Error{
+			Error:       e.Error,
+			Code:        e.Code,
+			Kind:        e.Kind,
+			Remediation: e.Remediation,
+			Detail:      e.Detail,
+		}}
+	case *ElicitationRequestEvent:
+		agentContext = e.AgentContext
+		schema, err := marshalJSON(e.Schema)
+		if err != nil {
+			return nil, err
+		}
+		var meta []byte
+		if len(e.Meta) > 0 {
+			if meta, err = marshalJSON(e.Meta); err != nil {
+				return nil, err
+			}
+		}
+		pe.Event = &agentv1.Event_ElicitationRequest{ElicitationRequest: &agentv1.ElicitationRequest{
+			Message:       e.Message,
+			Mode:          e.Mode,
+			SchemaJson:    schema,
+			Url:           e.URL,
+			ElicitationId: e.ElicitationID,
+			MetaJson:      meta,
+		}}
+	case *MaxIterationsReachedEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_MaxIterationsReached{MaxIterationsReached: &agentv1.MaxIterationsReached{MaxIterations: int64(e.MaxIterations)}}
+	case *SessionTitleEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_SessionTitle{SessionTitle: &agentv1.SessionTitle{SessionId: e.SessionID, Title: e.Title}}
+	case *WarningEvent:
+		agentContext = e.AgentContext
+		pe.Event = &agentv1.Event_Warning{Warning: &agentv1.Warning{Message: e.Message}}
+	default:
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		var base struct {
+			Type      string    `json:"type"`
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if err := json.Unmarshal(data, &base); err != nil {
+			return nil, err
+		}
+		agentContext.Timestamp = base.Timestamp
+		pe.Event = &agentv1.Event_Other{Other: &agentv1.OtherEvent{Type: base.Type, Json: data}}
+	}
+
+	if !agentContext.Timestamp.IsZero() {
+		pe.Timestamp = agentContext.Timestamp.Format(time.RFC3339Nano)
+	}
+	return pe, nil
+}
+
+// DecodeGRPCEvent converts an event of the gRPC API.
+func DecodeGRPCEvent(pe *agentv1.Event) (Event, error) {
+	agentContext := AgentContext{AgentName: pe.GetAgentName()}
+	if timestamp := pe.GetTimestamp(); timestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("decoding event: %w", err)
+		}
+		agentContext.Timestamp = t
+	}
+
+	switch e := pe.GetEvent().(type) {
+	case *agentv1.Event_StreamStarted:
+		return &StreamStartedEvent{Type: "stream_started", SessionID: e.StreamStarted.GetSessionId(), AgentContext: agentContext}, nil
+	case *agentv1.Event_StreamStopped:
+		return &StreamStoppedEvent{Type: "stream_stopped", SessionID: e.StreamStopped.GetSessionId(), AgentContext: agentContext}, nil
+	case *agentv1.Event_AgentChoice:
+		return &AgentChoiceEvent{Type: "agent_choice", SessionID: e.AgentChoice.GetSessionId(), Content: e.AgentChoice.GetContent(), AgentContext: agentContext}, nil
+	case *agentv1.Event_AgentChoiceReasoning:
+		return &AgentChoiceReasoningEvent{Type: "agent_choice_reasoning", SessionID: e.AgentChoiceReasoning.GetSessionId(), Content: e.AgentChoiceReasoning.GetContent(), AgentContext: agentContext}, nil
+	case *agentv1.Event_ToolCall:
+		definition, err := decodeToolDefinition(e.ToolCall.GetToolDefinition())
+		if err != nil {
+			return nil, err
+		}
+		return &ToolCallEvent{Type: "tool_call", ToolCall: decodeToolCall(e.ToolCall.GetToolCall()), ToolDefinition: definition, AgentContext: agentContext}, nil
+	case *agentv1.Event_PartialToolCall:
+		definition, err := decodeToolDefinition(e.PartialToolCall.GetToolDefinition())
+		if err != nil {
+			return nil, err
+		}
+		return &PartialToolCallEvent{Type: "partial_tool_call", ToolCall: decodeToolCall(e.PartialToolCall.GetToolCall()), ToolDefinition: definition, AgentContext: agentContext}, nil
+	case *agentv1.Event_ToolCallConfirmation:
+		definition, err := decodeToolDefinition(e.ToolCallConfirmation.GetToolDefinition())
+		if err != nil {
+			return nil, err
+		}
+		return &ToolCallConfirmationEvent{Type: "tool_call_confirmation", ToolCall: decodeToolCall(e.ToolCallConfirmation.GetToolCall()), ToolDefinition: definition, AgentContext: agentContext}, nil
+	case *agentv1.Event_ToolCallResponse:
+		definition, err := decodeToolDefinition(e.ToolCallResponse.GetToolDefinition())
+		if err != nil {
+			return nil, err
+		}
+		event := &ToolCallResponseEvent{
+			Type:           "tool_call_response",
+			ToolCall:       decodeToolCall(e.ToolCallResponse.GetToolCall()),
+			ToolDefinition: definition,
+			Response:       e.ToolCallResponse.GetResponse(),
+			AgentContext:   agentContext,
+		}
+		if err := unmarshalJSON(e.ToolCallResponse.GetResultJson(), &event.Result); err != nil {
+			return nil, err
+		}
+		return event, nil
+	case *agentv1.Event_Error:
+		return &ErrorEvent{
+			Type:         "error",
+			Error:        e.Error.GetError(),
+			Code:         e.Error.GetCode(),
+			Kind:         e.Error.GetKind(),
+			Remediation:  e.Error.GetRemediation(),
+			Detail:       e.Error.GetDetail(),
+			AgentContext: agentContext,
+		}, nil
+	case *agentv1.Event_ElicitationRequest:
+		event := &ElicitationRequestEvent{
+			Type:          "elicitation_request",
+			Message:       e.ElicitationRequest.GetMessage(),
+			Mode:          e.ElicitationRequest.GetMode(),
+			URL:           e.ElicitationRequest.GetUrl(),
+			ElicitationID: e.ElicitationRequest.GetElicitationId(),
+			AgentContext:  agentContext,
+		}
+		if err := unmarshalJSON(e.ElicitationRequest.GetSchemaJson(), &event.Schema); err != nil {
+			return nil, err
+		}
+		if err := unmarshalJSON(e.ElicitationRequest.GetMetaJson(), &event.Meta); err != nil {
+			return nil, err
+		}
+		return event, nil
+	case *agentv1.Event_MaxIterationsReached:
+		return &MaxIterationsReachedEvent{Type: "max_iterations_reached", MaxIterations: int(e.MaxIterationsReached.GetMaxIterations()), AgentContext: agentContext}, nil
+	case *agentv1.Event_SessionTitle:
+		return &SessionTitleEvent{Type: "session_title", SessionID: e.SessionTitle.GetSessionId(), Title: e.SessionTitle.GetTitle(), AgentContext: agentContext}, nil
+	case *agentv1.Event_Warning:
+		return &WarningEvent{Type: "warning", Message: e.Warning.GetMessage(), AgentContext: agentContext}, nil
+	case *agentv1.Event_Other:
+		return DecodeEvent(e.Other.GetJson())
+	default:
+		return nil, fmt.Errorf("unknown event %T", e)
+	}
+}
+
+func protoToolCall(toolCall tools.ToolCall) *agentv1.ToolCall {
+	return &agentv1.ToolCall{
+		Id:        toolCall.ID,
+		Type:      string(toolCall.Type),
+		Name:      toolCall.Function.Name,
+		Arguments: toolCall.Function.Arguments,
+	}
+}
+
+func decodeToolCall(toolCall *agentv1.ToolCall) tools.ToolCall {
+	return tools.ToolCall{
+		ID:   toolCall.GetId(),
+		Type: tools.ToolType(toolCall.GetType()),
+		Function: tools.FunctionCall{
+			Name:      toolCall.GetName(),
+			Arguments: toolCall.GetArguments(),
+		},
+	}
+}
+
+func protoToolDefinition(tool tools.Tool) (*agentv1.ToolDefinition, error) {
+	parameters, err := marshalJSON(tool.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	outputSchema, err := marshalJSON(tool.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &agentv1.ToolDefinition{
+		Name:        tool.Name,
+		Category:    tool.Category,
+		Description: tool.Description,
+		Annotations: &agentv1.ToolAnnotations{
+			Title:           tool.Annotations.Title,
+			ReadOnlyHint:    tool.Annotations.ReadOnlyHint,
+			DestructiveHint: tool.Annotations.DestructiveHint,
+			IdempotentHint:  tool.Annotations.IdempotentHint,
+			OpenWorldHint:   tool.Annotations.OpenWorldHint,
+		},
+		ParametersJson:   parameters,
+		OutputSchemaJson: outputSchema,
+	}, nil
+}
+
+func decodeToolDefinition(tool *agentv1.ToolDefinition) (tools.Tool, error) {
+	annotations := tool.GetAnnotations()
+	definition := tools.Tool{
+		Name:        tool.GetName(),
+		Category:    tool.GetCategory(),
+		Description: tool.GetDescription(),
+		Annotations: tools.ToolAnnotations{
+			Title:           annotations.GetTitle(),
+			ReadOnlyHint:    annotations.GetReadOnlyHint(),
+			DestructiveHint: annotations.DestructiveHint,
+			IdempotentHint:  annotations.GetIdempotentHint(),
+			OpenWorldHint:   annotations.OpenWorldHint,
+		},
+	}
+	if err := unmarshalJSON(tool.GetParametersJson(), &definition.Parameters); err != nil {
+		return tools.Tool{}, err
+	}
+	if err := unmarshalJSON(tool.GetOutputSchemaJson(), &definition.OutputSchema); err != nil {
+		return tools.Tool{}, err
+	}
+	return definition, nil
+}
+
+// marshalJSON encodes the values that have no fixed shape, nil as nothing.
+func marshalJSON(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding event: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalJSON(data []byte, v any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding event: %w", err)
+	}
+	return nil
+}
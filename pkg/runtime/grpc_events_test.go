@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/api/agentv1"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestGRPCEvents(t *testing.T) {
+	destructive := true
+	toolCall := tools.ToolCall{ID: "call_1", Type: "function", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"ls"}`}}
+	toolDefinition := tools.Tool{
+		Name:        "shell",
+		Category:    "shell",
+		Description: "Runs a command",
+		Parameters:  map[string]any{"type": "object"},
+		Annotations: tools.ToolAnnotations{Title: "Shell", DestructiveHint: &destructive},
+	}
+
+	for _, event := range []Event{
+		StreamStarted("session", "root"),
+		AgentChoice("root", "session", "Hello"),
+		ToolCallConfirmation(toolCall, toolDefinition, "root"),
+		ToolCallResponse(toolCall, toolDefinition, &tools.ToolCallResult{Output: "file.txt"}, "file.txt", "root"),
+		ElicitationRequest("Your name?", "form", map[string]any{"type": "object"}, "", "elicitation", map[string]any{"key": "value"}, "root"),
+		MaxIterationsReached(10),
+		SessionTitle("session", "Title"),
+		Warning("careful", "root"),
+		NewTokenUsageEvent("session", "root", &Usage{InputTokens: 1, OutputTokens: 2, Cost: 0.5}),
+	} {
+		pe, err := EncodeGRPCEvent(event)
+		require.NoError(t, err)
+		decoded, err := DecodeGRPCEvent(pe)
+		require.NoError(t, err)
+
+		expected, err := json.Marshal(event)
+		require.NoError(t, err)
+		actual, err := json.Marshal(decoded)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(actual))
+	}
+}
+
+func TestGRPCEvents_Typed(t *testing.T) {
+	pe, err := EncodeGRPCEvent(ToolCallConfirmation(tools.ToolCall{Function: tools.FunctionCall{Name: "shell"}}, tools.Tool{Name: "shell"}, "root"))
+	require.NoError(t, err)
+	assert.Equal(t, "root", pe.GetAgentName())
+	assert.Equal(t, "shell", pe.GetToolCallConfirmation().GetToolCall().GetName())
+
+	pe, err = EncodeGRPCEvent(NewTokenUsageEvent("session", "root", &Usage{InputTokens: 1, OutputTokens: 2, Cost: 0.5}))
+	require.NoError(t, err)
+	assert.Equal(t, "token_usage", pe.GetOther().GetType())
+	assert.IsType(t, &agentv1.Event_Other{}, pe.GetEvent())
+}
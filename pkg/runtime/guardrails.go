@@ -0,0 +1,74 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/guardrails"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// checkGuardrails checks the final answer of an agent against its
+// guardrails, and outputGuardrail when the session has an output schema. A
+// blocked answer is replaced, in res, by the message of the
+// guardrail. When the answer must be retried, it's withheld from the user and
+// the feedback asking the model for a new answer is returned. retries counts
+// the answers each guardrail already retried during the run.
+func (r *LocalRuntime) checkGuardrails(ctx context.Context, sess *session.Session, a *agent.Agent, outputGuardrail *guardrails.Guardrail, model provider.Provider, res *streamResult, retries map[*guardrails.Guardrail]int, events chan Event) string {
+	all := a.Guardrails()
+	if outputGuardrail != nil {
+		all = append(slices.Clone(all), outputGuardrail)
+	}
+	if len(all) == 0 || len(res.Calls) > 0 || strings.TrimSpace(res.Content) == "" {
+		return ""
+	}
+
+	violations := guardrails.Check(ctx, all, res.Content, model)
+	if len(violations) == 0 {
+		return ""
+	}
+
+	// The strictest action wins: a blocked answer isn't retried, and
+	// retried answers are withheld even if other guardrails only annotate.
+	var blocked *guardrails.Violation
+	var retried []guardrails.Violation
+	for i, v := range violations {
+		switch {
+		case v.Guardrail.Action == latest.GuardrailActionAnnotate:
+		case v.Guardrail.Action == latest.GuardrailActionRetry && retries[v.Guardrail] < v.Guardrail.MaxRetries:
+			retried = append(retried, v)
+		case blocked == nil:
+			blocked = &violations[i]
+		}
+	}
+	withheld := blocked != nil || len(retried) > 0
+
+	for _, v := range violations {
+		action := v.Guardrail.Action
+		switch {
+		case blocked != nil && action != latest.GuardrailActionAnnotate:
+			action = latest.GuardrailActionBlock
+		case blocked == nil && action == latest.GuardrailActionRetry:
+			retries[v.Guardrail]++
+		}
+		slog.Warn("Answer doesn't follow a guardrail", "agent", a.Name(), "session_id", sess.ID, "guardrail", v.Guardrail.Name, "action", action, "reason", v.Reason)
+		events <- Guardrail(sess.ID, a.Name(), v.Guardrail.Name, action, v.Reason, withheld)
+	}
+
+	switch {
+	case blocked != nil:
+		res.Content = blocked.Guardrail.Message
+		events <- AgentChoice(a.Name(), sess.ID, res.Content)
+		return ""
+	case len(retried) > 0:
+		res.Withheld = true
+		return guardrails.Feedback(retried)
+	default:
+		return ""
+	}
+}
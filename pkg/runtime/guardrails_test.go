@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/guardrails"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+)
+
+func runWithGuardrail(t *testing.T, cfg latest.GuardrailConfig, answers ...string) (*session.Session, []*GuardrailEvent) {
+	t.Helper()
+
+	g, err := guardrails.New(0, cfg, nil)
+	require.NoError(t, err)
+
+	var streams []chat.MessageStream
+	for _, answer := range answers {
+		streams = append(streams, newStreamBuilder().AddContent(answer).AddStopWithUsage(1, 1).Build())
+	}
+	prov := &queueProvider{id: "test/mock-model", streams: streams}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithGuardrails([]*guardrails.Guardrail{g}))
+
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"))
+	var violations []*GuardrailEvent
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if e, ok := ev.(*GuardrailEvent); ok {
+			violations = append(violations, e)
+		}
+	}
+	return sess, violations
+}
+
+func TestGuardrails_Retry(t *testing.T) {
+	sess, violations := runWithGuardrail(t,
+		latest.GuardrailConfig{Name: "json", JSONSchema: map[string]any{"type": "object"}, Action: latest.GuardrailActionRetry},
+		"Here you go", `{"ok": true}`)
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, "json", violations[0].Guardrail)
+	assert.Equal(t, latest.GuardrailActionRetry, violations[0].Action)
+	assert.True(t, violations[0].Discarded)
+
+	messages := sess.GetAllMessages()
+	require.Len(t, messages, 4)
+	assert.True(t, messages[1].Implicit, "the rejected answer is hidden")
+	assert.True(t, messages[2].Implicit)
+	assert.Contains(t, messages[2].Message.Content, "not valid JSON")
+	assert.False(t, messages[3].Implicit)
+	assert.JSONEq(t, `{"ok": true}`, messages[3].Message.Content)
+}
+
+func TestGuardrails_BlockAfterRetries(t *testing.T) {
+	sess, violations := runWithGuardrail(t,
+		latest.GuardrailConfig{Deny: []string{"refund"}, Action: latest.GuardrailActionRetry, MaxRetries: 1, Message: "Please contact support."},
+		"You'll get a refund", "A refund is on its way")
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, latest.GuardrailActionRetry, violations[0].Action)
+	assert.Equal(t, latest.GuardrailActionBlock, violations[1].Action)
+
+	messages := sess.GetAllMessages()
+	assert.Equal(t, "Please contact support.", messages[len(messages)-1].Message.Content)
+}
+
+func TestGuardrails_Annotate(t *testing.T) {
+	sess, violations := runWithGuardrail(t,
+		latest.GuardrailConfig{Deny: []string{"refund"}, Action: latest.GuardrailActionAnnotate},
+		"You'll get a refund")
+
+	require.Len(t, violations, 1)
+	assert.False(t, violations[0].Discarded)
+
+	messages := sess.GetAllMessages()
+	assert.Equal(t, "You'll get a refund", messages[len(messages)-1].Message.Content)
+}
@@ -17,23 +17,35 @@ import (
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/compaction"
+	"github.com/docker/docker-agent/pkg/guardrails"
+	"github.com/docker/docker-agent/pkg/httpclient"
 	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/model/provider/options"
 	"github.com/docker/docker-agent/pkg/modelerrors"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/telemetry"
+	"github.com/docker/docker-agent/pkg/tokens"
 	"github.com/docker/docker-agent/pkg/tools"
 	"github.com/docker/docker-agent/pkg/tools/builtin"
 )
 
 // registerDefaultTools wires up the built-in tool handlers (delegation,
-// background agents, model switching) into the runtime's tool dispatch map.
+// background agents, model switching, paging of long tool outputs,
+// attachments, the team blackboard and sub-agent traces) into the
+// runtime's tool dispatch map.
 func (r *LocalRuntime) registerDefaultTools() {
 	r.toolMap[builtin.ToolNameTransferTask] = r.handleTaskTransfer
 	r.toolMap[builtin.ToolNameHandoff] = r.handleHandoff
 	r.toolMap[builtin.ToolNameChangeModel] = r.handleChangeModel
 	r.toolMap[builtin.ToolNameRevertModel] = r.handleRevertModel
+	r.toolMap[builtin.ToolNameGetMoreOutput] = r.handleGetMoreOutput
+	r.toolMap[builtin.ToolNameSearchTools] = r.handleSearchTools
+	r.toolMap[builtin.ToolNameReadAttachment] = r.handleReadAttachment
+	r.toolMap[builtin.ToolNamePostToBlackboard] = r.handlePostToBlackboard
+	r.toolMap[builtin.ToolNameReadBlackboard] = r.handleReadBlackboard
+	r.toolMap[builtin.ToolNameRemoveFromBlackboard] = r.handleRemoveFromBlackboard
+	r.toolMap[builtin.ToolNameGetSubAgentTrace] = r.handleGetSubAgentTrace
 
 	r.bgAgents.RegisterHandlers(func(name string, fn func(context.Context, *session.Session, tools.ToolCall) (*tools.ToolCallResult, error)) {
 		r.toolMap[name] = func(ctx context.Context, sess *session.Session, tc tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
@@ -43,20 +55,17 @@ func (r *LocalRuntime) registerDefaultTools() {
 }
 
 // finalizeEventChannel performs cleanup at the end of a RunStream goroutine:
-// restores the previous elicitation channel, emits the StreamStopped event,
-// fires hooks, and closes the events channel.
-func (r *LocalRuntime) finalizeEventChannel(ctx context.Context, sess *session.Session, prevElicitationCh, events chan Event) {
-	// Swap back the parent's elicitation channel before closing this
-	// stream's channel. This prevents a send-on-closed-channel panic
-	// and restores elicitation for the parent session.
-	r.swapElicitationEventsChannel(prevElicitationCh)
-
+// emits the StreamStopped event, fires hooks, updates the summary of the
+// session for agents with continuity, and closes the events channel.
+func (r *LocalRuntime) finalizeEventChannel(ctx context.Context, sess *session.Session, events chan Event) {
 	defer close(events)
 
 	events <- StreamStopped(sess.ID, r.resolveSessionAgent(sess).Name())
 
 	r.executeOnUserInputHooks(ctx, sess.ID, "stream stopped")
 
+	r.saveContinuity(ctx, sess)
+
 	telemetry.RecordSessionEnd(ctx)
 }
 
@@ -68,6 +77,9 @@ func (r *LocalRuntime) finalizeEventChannel(ctx context.Context, sess *session.S
 func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-chan Event {
 	slog.Debug("Starting runtime stream", "agent", r.CurrentAgentName(), "session_id", sess.ID)
 	events := make(chan Event, 128)
+	// Register the stream before the loop starts, so that elicitation
+	// requests of the toolsets being started reach the caller.
+	out := r.publishEvents(sess, events)
 
 	go func() {
 		telemetry.RecordSessionStart(ctx, r.CurrentAgentName(), sess.ID)
@@ -78,13 +90,11 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 		))
 		defer sessionSpan.End()
 
-		// Swap in this stream's events channel for elicitation and save the
-		// previous one so it can be restored on teardown. This allows nested
-		// RunStream calls to temporarily own elicitation without losing the
-		// parent's channel.
-		prevElicitationCh := r.swapElicitationEventsChannel(events)
+		// Share the delegation limits of the turn with nested sub-sessions.
+		ctx, guard := r.delegationGuard(ctx)
 
 		a := r.resolveSessionAgent(sess)
+		r.loadContinuity(sess, a)
 
 		// Emit agent information for sidebar display
 		// Use getEffectiveModelID to account for active fallback cooldowns
@@ -101,33 +111,55 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 
 		agentTools, err := r.getTools(ctx, a, sessionSpan, events)
 		if err != nil {
-			events <- Error(fmt.Sprintf("failed to get tools: %v", err))
+			events <- ErrorWithCode(ErrorCodeTools, fmt.Sprintf("failed to get tools: %v", err))
 			return
 		}
 
 		events <- ToolsetInfo(len(agentTools), false, a.Name())
 
 		messages := sess.GetMessages(a)
-		if sess.SendUserMessage {
+		// A run continued without a new message doesn't end with a user message.
+		if sess.SendUserMessage && len(messages) > 0 && messages[len(messages)-1].Role == chat.MessageRoleUser {
 			lastMsg := messages[len(messages)-1]
-			events <- UserMessage(lastMsg.Content, sess.ID, lastMsg.MultiContent, len(sess.Messages)-1)
+			userMessage := UserMessage(lastMsg.Content, sess.ID, lastMsg.MultiContent, len(sess.Messages)-1).(*UserMessageEvent)
+			userMessage.User = sess.LastUserMessageAuthor()
+			events <- userMessage
 		}
 
 		events <- StreamStarted(sess.ID, a.Name())
 
-		defer r.finalizeEventChannel(ctx, sess, prevElicitationCh, events)
+		defer r.finalizeEventChannel(ctx, sess, events)
 
 		r.registerDefaultTools()
 
 		iteration := 0
 		// Use a runtime copy of maxIterations so we don't modify the session's persistent config
 		runtimeMaxIterations := sess.MaxIterations
+		if sess.IterationBudget > 0 {
+			runtimeMaxIterations = sess.IterationBudget
+			sess.IterationBudget = 0
+		}
 
 		// toolModelOverride holds the per-toolset model from the most recent
 		// tool calls. It applies for one LLM turn, then resets.
 		var toolModelOverride string
 		var prevAgentName string
 
+		// guardrailRetries counts the answers each guardrail retried.
+		guardrailRetries := map[*guardrails.Guardrail]int{}
+
+		// The dynamic context sources are read once per run, or again
+		// when their cache expires.
+		dynContext := newDynamicContext()
+
+		// The answers of sub-sessions with an output schema are checked
+		// like those of agents with a json_schema guardrail.
+		outputGuardrail, err := outputSchemaGuardrail(sess)
+		if err != nil {
+			events <- Error(fmt.Sprintf("invalid task output schema: %v", err))
+			return
+		}
+
 		for {
 			a = r.resolveSessionAgent(sess)
 
@@ -143,7 +175,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 
 			agentTools, err := r.getTools(ctx, a, sessionSpan, events)
 			if err != nil {
-				events <- Error(fmt.Sprintf("failed to get tools: %v", err))
+				events <- ErrorWithCode(ErrorCodeTools, fmt.Sprintf("failed to get tools: %v", err))
 				return
 			}
 
@@ -152,6 +184,9 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			// server and may return a different count.
 			events <- ToolsetInfo(len(agentTools), false, a.Name())
 
+			// Agents with many tools only get the ones they searched for.
+			agentTools = r.selectTools(sess, a, agentTools)
+
 			// Check iteration limit
 			if runtimeMaxIterations > 0 && iteration >= runtimeMaxIterations {
 				slog.Debug(
@@ -167,21 +202,14 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				select {
 				case req := <-r.resumeChan:
 					if req.Type == ResumeTypeApprove {
-						slog.Debug("User chose to continue after max iterations", "agent", a.Name())
-						runtimeMaxIterations = iteration + 10
+						more := cmp.Or(req.Iterations, DefaultContinueIterations)
+						slog.Debug("User chose to continue after max iterations", "agent", a.Name(), "iterations", more)
+						runtimeMaxIterations = iteration + more
 					} else {
-						slog.Debug("User rejected continuation", "agent", a.Name())
-
-						assistantMessage := chat.Message{
-							Role: chat.MessageRoleAssistant,
-							Content: fmt.Sprintf(
-								"Execution stopped after reaching the configured max_iterations limit (%d).",
-								runtimeMaxIterations,
-							),
-							CreatedAt: time.Now().Format(time.RFC3339),
-						}
-
-						addAgentMessage(sess, a, &assistantMessage, events)
+						// The run is paused rather than ended: the session is
+						// left as is, so it can be continued later.
+						slog.Debug("User rejected continuation, pausing the run", "agent", a.Name())
+						events <- RunPaused(sess.ID, a.Name(), runtimeMaxIterations)
 						return
 					}
 
@@ -195,6 +223,14 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				}
 			}
 
+			// Delegated tasks stop at the limits of their budget, without
+			// asking the user: the agent that delegated them decides.
+			if limit, reason := exceededTaskBudget(sess, iteration); limit != "" {
+				slog.Debug("Task budget exceeded", "agent", a.Name(), "session_id", sess.ID, "limit", limit)
+				events <- TaskBudgetExceeded(sess.ID, a.Name(), limit, reason)
+				return
+			}
+
 			iteration++
 
 			// Exit immediately if the stream context has been cancelled (e.g., Ctrl+C)
@@ -234,6 +270,12 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			model = provider.CloneWithOptions(ctx, model, options.WithThinking(sess.Thinking))
 			slog.Debug("Cloned provider with thinking setting", "agent", a.Name(), "model", model.ID(), "thinking", sess.Thinking)
 
+			// Ask the models that support structured outputs for answers
+			// following the output schema of the session.
+			if sess.OutputSchema != nil {
+				model = provider.CloneWithOptions(ctx, model, options.WithStructuredOutput(sess.OutputSchema))
+			}
+
 			modelID := model.ID()
 
 			// Notify sidebar when this turn uses a different model (per-tool override).
@@ -257,9 +299,13 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				r.Summarize(ctx, sess, "", events)
 			}
 
+			dynContext.refresh(ctx, sess, a)
 			messages := sess.GetMessages(a)
 			slog.Debug("Retrieved messages for processing", "agent", a.Name(), "message_count", len(messages))
 
+			agentTools = withAttachmentTools(ctx, messages, agentTools)
+			messages = r.prepareAttachments(ctx, messages, a.Name(), events)
+
 			// Strip image content from messages if the model doesn't support image input.
 			// This prevents API errors when conversation history contains images (e.g. from
 			// tool results or user attachments) but the current model is text-only.
@@ -267,8 +313,21 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				messages = stripImageContent(messages)
 			}
 
-			// Try primary model with fallback chain if configured
-			res, usedModel, err := r.tryModelWithFallback(streamCtx, a, model, messages, agentTools, sess, m, events)
+			if compressor := r.team.PromptCompressor(); compressor != nil {
+				res := compressor.Compress(ctx, messages, tokens.Local(model))
+				if res.Compressed > 0 {
+					messages = res.Messages
+					events <- PromptCompression(sess.ID, a.Name(), res.Compressed, res.OriginalTokens, res.CompressedTokens)
+				}
+			}
+
+			// Try primary model with fallback chain if configured. The
+			// requests are dumped to the session's files with --debug-llm.
+			modelStart := time.Now()
+			modelCtx, requestDump := httpclient.WithDebugRequest(httpclient.WithDebugSession(streamCtx, sess.ID))
+			res, usedModel, err := r.tryModelWithFallback(modelCtx, a, model, messages, agentTools, sess, m, events)
+			res.Latency = time.Since(modelStart)
+			res.RequestDump = requestDump()
 			if err != nil {
 				// Treat context cancellation as a graceful stop
 				if errors.Is(err, context.Canceled) {
@@ -306,7 +365,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				slog.Error("All models failed", "agent", a.Name(), "error", err)
 				// Track error in telemetry
 				telemetry.RecordError(ctx, err.Error())
-				events <- Error(modelerrors.FormatError(err))
+				events <- ModelError(err)
 				streamSpan.End()
 				return
 			}
@@ -330,31 +389,53 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			streamSpan.End()
 			slog.Debug("Stream processed", "agent", a.Name(), "tool_calls", len(res.Calls), "content_length", len(res.Content), "stopped", res.Stopped)
 
+			// Policy guardrails are checked by the model that answered,
+			// unless they have their own.
+			answerModel := model
+			if usedModel != nil {
+				answerModel = usedModel
+			}
+			guardrailFeedback := r.checkGuardrails(ctx, sess, a, outputGuardrail, answerModel, &res, guardrailRetries, events)
+
 			msgUsage := r.recordAssistantMessage(sess, a, res, agentTools, modelID, m, events)
 
 			usage := SessionUsage(sess, contextLimit)
 			usage.LastMessage = msgUsage
 			events <- NewTokenUsageEvent(sess.ID, a.Name(), usage)
-
-			// Record the message count before tool calls so we can
-			// measure how much content was added by tool results.
-			messageCountBeforeTools := len(sess.GetAllMessages())
+			r.reportContextUsage(sess, a, contextLimit, events)
 
 			r.processToolCalls(ctx, sess, res.Calls, agentTools, events)
 
+			// A delegation loop stops the whole turn, in this session and in
+			// all the sessions it's nested in.
+			if err := guard.stopped(); err != nil {
+				slog.Warn("Stopping run", "agent", a.Name(), "session_id", sess.ID, "error", err)
+				if guard.report() {
+					events <- Error(err.Error())
+				}
+				return
+			}
+
 			// Record per-toolset model override for the next LLM turn.
 			toolModelOverride = resolveToolCallModelOverride(res.Calls, agentTools)
 
+			if guardrailFeedback != "" {
+				feedback := session.ImplicitUserMessage(guardrailFeedback)
+				sess.AddMessage(feedback)
+				events <- MessageAdded(sess.ID, feedback, a.Name())
+				continue
+			}
+
 			if res.Stopped {
 				slog.Debug("Conversation stopped", "agent", a.Name())
 				break
 			}
 
-			r.compactIfNeeded(ctx, sess, a, m, contextLimit, messageCountBeforeTools, events)
+			r.compactIfNeeded(ctx, sess, a, model, m, contextLimit, agentTools, events)
 		}
 	}()
 
-	return events
+	return out
 }
 
 // Run executes the agent loop synchronously and returns the final session
@@ -424,9 +505,24 @@ func (r *LocalRuntime) recordAssistantMessage(
 		Usage:             res.Usage,
 		Model:             messageModel,
 		Cost:              messageCost,
+		LatencyMs:         res.Latency.Milliseconds(),
+		ResponseID:        res.ResponseID,
+		Citations:         res.Citations,
+		ReasoningItems:    res.ReasoningItems,
+		Ensemble:          res.Ensemble,
+		RequestDump:       res.RequestDump,
 	}
 
-	addAgentMessage(sess, a, &assistantMessage, events)
+	agentMsg := session.NewAgentMessage(a.Name(), &assistantMessage)
+	agentMsg.Implicit = res.Withheld
+	sess.AddMessage(agentMsg)
+	events <- MessageAdded(sess.ID, agentMsg, a.Name())
+	if len(res.Citations) > 0 {
+		events <- Citations(a.Name(), sess.ID, res.Citations)
+	}
+	if res.Ensemble != nil && res.Ensemble.Disagreement != "" {
+		events <- Warning("The models of the ensemble disagreed. "+res.Ensemble.Disagreement, a.Name())
+	}
 	slog.Debug("Added assistant message to session", "agent", a.Name(), "total_messages", len(sess.GetAllMessages()))
 
 	// Build per-message usage for the event.
@@ -434,9 +530,11 @@ func (r *LocalRuntime) recordAssistantMessage(
 		return nil
 	}
 	msgUsage := &MessageUsage{
-		Usage: *res.Usage,
-		Cost:  messageCost,
-		Model: messageModel,
+		Usage:      *res.Usage,
+		Cost:       messageCost,
+		Model:      messageModel,
+		LatencyMs:  res.Latency.Milliseconds(),
+		ResponseID: res.ResponseID,
 	}
 	if res.RateLimit != nil {
 		msgUsage.RateLimit = *res.RateLimit
@@ -444,39 +542,50 @@ func (r *LocalRuntime) recordAssistantMessage(
 	return msgUsage
 }
 
-// compactIfNeeded estimates the token impact of tool results added since
-// messageCountBefore and triggers proactive compaction when the estimated
-// total exceeds 90% of the context window. This prevents sending an
-// oversized request on the next iteration.
+// compactIfNeeded counts the tokens of the next request, now that tool
+// results were added to the session, reports them as the context usage of
+// the session and triggers proactive compaction when they exceed 90% of the
+// context window. This prevents sending an oversized request on the next
+// iteration.
+//
+// The count is estimated locally first; providers that count tokens with
+// their API are only asked to confirm that compaction is needed.
 func (r *LocalRuntime) compactIfNeeded(
 	ctx context.Context,
 	sess *session.Session,
 	a *agent.Agent,
+	model provider.Provider,
 	m *modelsdev.Model,
 	contextLimit int64,
-	messageCountBefore int,
+	agentTools []tools.Tool,
 	events chan Event,
 ) {
-	if m == nil || !r.sessionCompaction || contextLimit <= 0 {
+	if m == nil || contextLimit <= 0 {
 		return
 	}
 
-	newMessages := sess.GetAllMessages()[messageCountBefore:]
-	var addedTokens int64
-	for _, msg := range newMessages {
-		addedTokens += compaction.EstimateMessageTokens(&msg.Message)
-	}
+	messages := sess.GetMessages(a)
+	estimated := tokens.Count(ctx, tokens.Local(model), messages, agentTools)
 
-	if !compaction.ShouldCompact(sess.InputTokens, sess.OutputTokens, addedTokens, contextLimit) {
+	usage := SessionUsage(sess, contextLimit)
+	usage.ContextLength = estimated
+	events <- NewTokenUsageEvent(sess.ID, a.Name(), usage)
+
+	if !r.sessionCompaction || !compaction.ShouldCompact(estimated, 0, 0, contextLimit) {
 		return
 	}
+	if _, ok := model.(provider.TokenCounter); ok {
+		counted := tokens.Count(ctx, tokens.ForModel(model), messages, agentTools)
+		if !compaction.ShouldCompact(counted, 0, 0, contextLimit) {
+			slog.Debug("Token count is below the compaction threshold", "agent", a.Name(), "estimated", estimated, "counted", counted)
+			return
+		}
+		estimated = counted
+	}
 
-	slog.Info("Proactive compaction: tool results pushed estimated context past 90%% threshold",
+	slog.Info("Proactive compaction: tool results pushed context past 90%% threshold",
 		"agent", a.Name(),
-		"input_tokens", sess.InputTokens,
-		"output_tokens", sess.OutputTokens,
-		"added_estimated_tokens", addedTokens,
-		"estimated_total", sess.InputTokens+sess.OutputTokens+addedTokens,
+		"context_tokens", estimated,
 		"context_limit", contextLimit,
 	)
 	r.Summarize(ctx, sess, "", events)
@@ -494,6 +603,12 @@ func (r *LocalRuntime) getTools(ctx context.Context, a *agent.Agent, sessionSpan
 		}
 	}()
 
+	// Start the toolsets concurrently, reporting their readiness, before
+	// listing their tools. Failures are reported as warnings by the agent.
+	_ = a.StartToolSets(ctx, func(toolSet string, status agent.ToolSetStatus, err error) {
+		events <- ToolsetStatus(toolSet, status, err, a.Name())
+	})
+
 	agentTools, err := a.Tools(ctx)
 	if err != nil {
 		slog.Error("Failed to get agent tools", "agent", a.Name(), "error", err)
@@ -0,0 +1,57 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+)
+
+func TestMaxIterations_PauseAndContinue(t *testing.T) {
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().
+			AddToolCallName("call_1", "test_tool").
+			AddToolCallArguments("call_1", `{}`).
+			Build(),
+		newStreamBuilder().AddContent("Done").AddStopWithUsage(1, 1).Build(),
+	}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"), session.WithMaxIterations(1), session.WithToolsApproved(true))
+
+	var paused *RunPausedEvent
+	for ev := range rt.RunStream(t.Context(), sess) {
+		switch e := ev.(type) {
+		case *MaxIterationsReachedEvent:
+			// Resume drops requests the loop isn't waiting for yet.
+			rt.resumeChan <- ResumeReject("")
+		case *RunPausedEvent:
+			paused = e
+		}
+	}
+
+	require.NotNil(t, paused)
+	assert.Equal(t, RunPausedReasonMaxIterations, paused.Reason)
+	assert.Equal(t, 1, paused.MaxIterations)
+
+	// The run stops after the tool call, without a made-up answer.
+	messages := sess.GetAllMessages()
+	assert.Equal(t, chat.MessageRoleTool, messages[len(messages)-1].Message.Role)
+
+	sess.IterationBudget = 1
+	for ev := range rt.RunStream(t.Context(), sess) {
+		_, ok := ev.(*MaxIterationsReachedEvent)
+		assert.False(t, ok, "the continued run has its own budget")
+	}
+
+	messages = sess.GetAllMessages()
+	assert.Equal(t, "Done", messages[len(messages)-1].Message.Content)
+	assert.Zero(t, sess.IterationBudget, "the budget only applies to one run")
+}
@@ -86,8 +86,9 @@ func (r *LocalRuntime) SetAgentModel(ctx context.Context, agentName, modelRef st
 	// Check if modelRef is a named model from config
 	if modelConfig, exists := r.modelSwitcherCfg.Models[modelRef]; exists {
 		modelConfig.Name = modelRef
-		// Check if this is an alloy model (no provider, comma-separated models)
-		if isAlloyModelConfig(modelConfig) {
+		// Check if this is an alloy model (no provider, comma-separated models).
+		// Alloys with a strategy are a single provider that picks per request.
+		if isAlloyModelConfig(modelConfig) && modelConfig.Alloy == nil {
 			providers, err := r.resolveModelRefs(ctx, modelConfig.Model)
 			if err != nil {
 				return fmt.Errorf("failed to create alloy model from config: %w", err)
@@ -138,7 +139,7 @@ func (r *LocalRuntime) resolveModelRef(ctx context.Context, modelRef string) (pr
 
 	// Try named model from config first.
 	if modelCfg, exists := r.modelSwitcherCfg.Models[modelRef]; exists {
-		if isAlloyModelConfig(modelCfg) {
+		if isAlloyModelConfig(modelCfg) && modelCfg.Alloy == nil {
 			return nil, fmt.Errorf("model reference %q is an alloy (multi-model) config and cannot be used as a single model override", modelRef)
 		}
 		modelCfg.Name = modelRef
@@ -418,6 +419,15 @@ func (r *LocalRuntime) createProviderFromConfig(ctx context.Context, cfg *latest
 		options.WithProviders(r.modelSwitcherCfg.Providers),
 	}
 
+	// Report local model pulls as events: the TUI owns the terminal, so
+	// the provider can't prompt or print progress itself.
+	if r.onModelPullProgress != nil {
+		agentName := r.CurrentAgentName()
+		opts = append(opts, options.WithPullProgress(func(model, status string) {
+			r.onModelPullProgress(ModelPullProgress(model, status, agentName))
+		}))
+	}
+
 	// Use max_tokens from config if specified, otherwise look up from models.dev
 	if cfg.MaxTokens != nil {
 		opts = append(opts, options.WithMaxTokens(*cfg.MaxTokens))
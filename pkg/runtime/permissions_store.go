@@ -0,0 +1,37 @@
+package runtime
+
+import (
+	"log/slog"
+
+	"github.com/docker/docker-agent/pkg/permissions"
+)
+
+// PermissionsStore holds tool permissions kept across sessions, like the ones
+// of the project.
+type PermissionsStore interface {
+	// PermissionsChecker returns the checker of the stored permissions, or
+	// nil if there are none.
+	PermissionsChecker() *permissions.Checker
+	// AllowTool saves a permission pattern of tool calls to always approve.
+	AllowTool(pattern string) error
+}
+
+// WithPermissionsStore sets the permissions checked after the ones of the
+// session, where the tool calls the user always approves are saved.
+func WithPermissionsStore(store PermissionsStore) Opt {
+	return func(r *LocalRuntime) {
+		r.permissionsStore = store
+	}
+}
+
+// saveAllowedTool saves a permission pattern the user always approves. Without
+// a store, or if it can't be saved, it's only approved for the session.
+func (r *LocalRuntime) saveAllowedTool(pattern string) {
+	if r.permissionsStore == nil {
+		slog.Warn("No permissions store, the tool is only approved for this session", "pattern", pattern)
+		return
+	}
+	if err := r.permissionsStore.AllowTool(pattern); err != nil {
+		slog.Error("Failed to save the tool permission, the tool is only approved for this session", "pattern", pattern, "error", err)
+	}
+}
@@ -0,0 +1,114 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/permissions"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+type memoryPermissionsStore struct {
+	allow []string
+}
+
+func (s *memoryPermissionsStore) PermissionsChecker() *permissions.Checker {
+	if len(s.allow) == 0 {
+		return nil
+	}
+	return permissions.NewChecker(&latest.PermissionsConfig{Allow: s.allow})
+}
+
+func (s *memoryPermissionsStore) AllowTool(pattern string) error {
+	s.allow = append(s.allow, pattern)
+	return nil
+}
+
+func TestPermissionsStore_ApproveAlways(t *testing.T) {
+	var executed []string
+	agentTools := []tools.Tool{{
+		Name:       "shell",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, tc tools.ToolCall) (*tools.ToolCallResult, error) {
+			executed = append(executed, tc.Function.Arguments)
+			return tools.ResultSuccess("executed"), nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	store := &memoryPermissionsStore{}
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithPermissionsStore(store))
+	require.NoError(t, err)
+
+	shell := func(cmd string) []tools.ToolCall {
+		return []tools.ToolCall{{
+			ID:       "call_" + cmd,
+			Type:     "function",
+			Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"` + cmd + `"}`},
+		}}
+	}
+
+	// The first call is confirmed, and git commands are always approved.
+	events := make(chan Event, 10)
+	go func() {
+		for ev := range events {
+			if _, ok := ev.(*ToolCallConfirmationEvent); ok {
+				rt.resumeChan <- ResumeApproveAlways("shell:cmd=git*")
+			}
+		}
+	}()
+	sess := session.New(session.WithUserMessage("Test"))
+	rt.processToolCalls(t.Context(), sess, shell("git status"), agentTools, events)
+	close(events)
+
+	assert.Equal(t, []string{"shell:cmd=git*"}, store.allow)
+	assert.Equal(t, []string{"shell:cmd=git*"}, sess.Permissions.Allow)
+
+	// The next sessions run git commands without confirmation.
+	events = make(chan Event, 10)
+	rt.processToolCalls(t.Context(), session.New(session.WithUserMessage("Test")), shell("git log"), agentTools, events)
+	close(events)
+	for ev := range events {
+		_, ok := ev.(*ToolCallConfirmationEvent)
+		assert.False(t, ok, "git commands are approved by the project permissions")
+	}
+
+	assert.Equal(t, []string{`{"cmd":"git status"}`, `{"cmd":"git log"}`}, executed)
+}
+
+func TestPermissionsStore_DoesNotOverrideDeny(t *testing.T) {
+	executed := false
+	agentTools := []tools.Tool{{
+		Name:       "shell",
+		Parameters: map[string]any{},
+		Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			executed = true
+			return tools.ResultSuccess("executed"), nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	store := &memoryPermissionsStore{allow: []string{"shell"}}
+	denied := permissions.NewChecker(&latest.PermissionsConfig{Deny: []string{"shell:cmd=rm*"}})
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root), team.WithPermissions(denied)), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithPermissionsStore(store))
+	require.NoError(t, err)
+
+	events := make(chan Event, 10)
+	rt.processToolCalls(t.Context(), session.New(session.WithUserMessage("Test")), []tools.ToolCall{{
+		ID:       "call_rm",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"rm -rf /"}`},
+	}}, agentTools, events)
+	close(events)
+
+	assert.False(t, executed, "the agent's deny rules win over the project permissions")
+}
@@ -99,7 +99,9 @@ func (r *PersistentRuntime) handleEvent(ctx context.Context, sess *session.Sessi
 		streaming.agentName = ""
 		streaming.messageID = 0
 
-		if _, err := r.sessionStore.AddMessage(ctx, e.SessionID, session.UserMessage(e.Message, e.MultiContent...)); err != nil {
+		msg := session.UserMessage(e.Message, e.MultiContent...)
+		msg.User = e.User
+		if _, err := r.sessionStore.AddMessage(ctx, e.SessionID, msg); err != nil {
 			slog.Warn("Failed to persist user message", "session_id", e.SessionID, "error", err)
 		}
 
@@ -135,6 +137,11 @@ func (r *PersistentRuntime) handleEvent(ctx context.Context, sess *session.Sessi
 			slog.Warn("Failed to persist summary", "session_id", e.SessionID, "error", err)
 		}
 
+	case *RecordAddedEvent:
+		if err := r.sessionStore.AddRecord(ctx, e.SessionID, e.Record); err != nil {
+			slog.Warn("Failed to persist record", "session_id", e.SessionID, "error", err)
+		}
+
 	case *TokenUsageEvent:
 		// Only persist token usage for the current session.
 		// During task transfers, sub-session events flow through but should
@@ -145,6 +152,16 @@ func (r *PersistentRuntime) handleEvent(ctx context.Context, sess *session.Sessi
 			}
 		}
 
+	case *ArtifactAddedEvent:
+		if err := r.sessionStore.UpdateSession(ctx, sess); err != nil {
+			slog.Warn("Failed to persist artifact", "session_id", sess.ID, "error", err)
+		}
+
+	case *BlackboardUpdatedEvent:
+		if err := r.sessionStore.UpdateSession(ctx, sess); err != nil {
+			slog.Warn("Failed to persist blackboard", "session_id", sess.ID, "error", err)
+		}
+
 	case *SessionTitleEvent:
 		if err := r.sessionStore.UpdateSessionTitle(ctx, sess.ID, e.Title); err != nil {
 			slog.Warn("Failed to persist session title", "session_id", sess.ID, "error", err)
@@ -9,7 +9,7 @@ import (
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
-// RemoteClient is the interface that both HTTP and Connect-RPC clients implement
+// RemoteClient is the interface that both HTTP and gRPC clients implement
 // for communicating with a remote docker agent server.
 type RemoteClient interface {
 	// GetAgent retrieves an agent configuration by ID
@@ -18,8 +18,9 @@ type RemoteClient interface {
 	// CreateSession creates a new session
 	CreateSession(ctx context.Context, sessTemplate *session.Session) (*session.Session, error)
 
-	// ResumeSession resumes a paused session with optional rejection reason or tool name
-	ResumeSession(ctx context.Context, id, confirmation, reason, toolName string) error
+	// ResumeSession resumes a paused session with optional rejection reason,
+	// tool name or number of iterations to continue for
+	ResumeSession(ctx context.Context, id, confirmation, reason, toolName string, iterations int) error
 
 	// ResumeElicitation sends an elicitation response
 	ResumeElicitation(ctx context.Context, sessionID string, action tools.ElicitationAction, content map[string]any) error
@@ -33,6 +34,9 @@ type RemoteClient interface {
 	// UpdateSessionTitle updates the title of a session
 	UpdateSessionTitle(ctx context.Context, sessionID, title string) error
 
+	// RegenerateSessionTitle generates a new title for a session and returns it
+	RegenerateSessionTitle(ctx context.Context, sessionID string) (string, error)
+
 	// GetAgentToolCount returns the number of tools available for an agent
 	GetAgentToolCount(ctx context.Context, agentFilename, agentName string) (int, error)
 }
@@ -24,7 +24,7 @@ import (
 
 // RemoteRuntime implements the Runtime interface using a remote client.
 // It works with any client that implements the RemoteClient interface,
-// including both HTTP (Client) and Connect-RPC (ConnectRPCClient) clients.
+// including both HTTP (Client) and gRPC (GRPCClient) clients.
 type RemoteRuntime struct {
 	client                  RemoteClient
 	currentAgent            string
@@ -220,7 +220,7 @@ func (r *RemoteRuntime) Resume(ctx context.Context, req ResumeRequest) {
 		return
 	}
 
-	if err := r.client.ResumeSession(ctx, r.sessionID, string(req.Type), req.Reason, req.ToolName); err != nil {
+	if err := r.client.ResumeSession(ctx, r.sessionID, string(req.Type), req.Reason, req.ToolName, req.Iterations); err != nil {
 		slog.Error("Failed to resume remote session", "error", err, "session_id", r.sessionID)
 	}
 }
@@ -449,6 +449,20 @@ func (r *RemoteRuntime) UpdateSessionTitle(ctx context.Context, sess *session.Se
 	return r.client.UpdateSessionTitle(ctx, r.sessionID, title)
 }
 
+// RegenerateSessionTitle generates a new title for the current session on
+// the remote server.
+func (r *RemoteRuntime) RegenerateSessionTitle(ctx context.Context, sess *session.Session) (string, error) {
+	if r.sessionID == "" {
+		return "", errors.New("cannot regenerate session title: no session ID available")
+	}
+	title, err := r.client.RegenerateSessionTitle(ctx, r.sessionID)
+	if err != nil {
+		return "", err
+	}
+	sess.Title = title
+	return title, nil
+}
+
 // CurrentMCPPrompts is not supported on remote runtimes.
 func (r *RemoteRuntime) CurrentMCPPrompts(context.Context) map[string]mcp.PromptInfo {
 	return make(map[string]mcp.PromptInfo)
@@ -0,0 +1,207 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/runlog"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/sessiontitle"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+	"github.com/docker/docker-agent/pkg/tools/mcp"
+)
+
+// maxReplayPause caps the time spent waiting between two replayed events,
+// so that a run where the user stepped away doesn't stall the replay.
+const maxReplayPause = 2 * time.Second
+
+var errReplayReadOnly = errors.New("this session is a replay: messages are not sent to any agent")
+
+// replayedEvent is an event of a run log with the time it was recorded.
+type replayedEvent struct {
+	time  time.Time
+	event Event
+}
+
+// ReplayRuntime implements the Runtime interface by playing back the events
+// of a run log. It never calls a model provider nor runs a tool.
+type ReplayRuntime struct {
+	events       []replayedEvent
+	speed        float64
+	currentAgent CurrentAgentInfo
+}
+
+// ReplayRuntimeOption is a function for configuring the ReplayRuntime
+type ReplayRuntimeOption func(*ReplayRuntime)
+
+// WithReplaySpeed sets how fast the run is played back: 1 replays it at the
+// recorded pace, 2 twice as fast... 0 replays it without pauses.
+func WithReplaySpeed(speed float64) ReplayRuntimeOption {
+	return func(r *ReplayRuntime) {
+		r.speed = speed
+	}
+}
+
+// NewReplayRuntime creates a runtime that replays the events of a run log.
+func NewReplayRuntime(entries []runlog.Entry, opts ...ReplayRuntimeOption) (*ReplayRuntime, error) {
+	r := &ReplayRuntime{
+		speed:        1,
+		currentAgent: CurrentAgentInfo{Name: "root"},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	agentSet := false
+	for _, entry := range entries {
+		if entry.Type != runlog.TypeEvent {
+			continue
+		}
+
+		event, err := DecodeEvent(entry.Data)
+		if err != nil {
+			slog.Debug("Skipping run log event", "error", err)
+			continue
+		}
+
+		switch e := event.(type) {
+		case *AgentInfoEvent:
+			if !agentSet {
+				r.currentAgent = CurrentAgentInfo{Name: e.AgentName, Description: e.Description}
+				agentSet = true
+			}
+		case *ToolCallConfirmationEvent, *ElicitationRequestEvent, *MaxIterationsReachedEvent:
+			// The user already answered these during the run.
+			continue
+		}
+
+		r.events = append(r.events, replayedEvent{time: entry.Time, event: event})
+	}
+
+	if len(r.events) == 0 {
+		return nil, errors.New("the run log has no events to replay")
+	}
+	return r, nil
+}
+
+// CurrentAgentName returns the name of the first agent of the run.
+func (r *ReplayRuntime) CurrentAgentName() string {
+	return r.currentAgent.Name
+}
+
+// CurrentAgentInfo returns information about the first agent of the run.
+func (r *ReplayRuntime) CurrentAgentInfo(context.Context) CurrentAgentInfo {
+	return r.currentAgent
+}
+
+// SetCurrentAgent is not supported while replaying.
+func (r *ReplayRuntime) SetCurrentAgent(string) error {
+	return errReplayReadOnly
+}
+
+// CurrentAgentTools returns no tools: tools are never run while replaying.
+func (r *ReplayRuntime) CurrentAgentTools(context.Context) ([]tools.Tool, error) {
+	return nil, nil
+}
+
+// EmitStartupInfo plays back the recorded events, at the recorded pace
+// adjusted by the replay speed.
+func (r *ReplayRuntime) EmitStartupInfo(ctx context.Context, _ *session.Session, events chan Event) {
+	for i, e := range r.events {
+		if i > 0 && r.speed > 0 {
+			pause := time.Duration(float64(e.time.Sub(r.events[i-1].time)) / r.speed)
+			select {
+			case <-time.After(min(max(pause, 0), maxReplayPause)):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case events <- e.event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ResetStartupInfo is a no-op for replay runtimes.
+func (r *ReplayRuntime) ResetStartupInfo() {
+}
+
+// RunStream doesn't send the messages anywhere and reports that the session
+// is a replay.
+func (r *ReplayRuntime) RunStream(_ context.Context, sess *session.Session) <-chan Event {
+	events := make(chan Event, 3)
+	events <- StreamStarted(sess.ID, r.currentAgent.Name)
+	events <- Error(errReplayReadOnly.Error())
+	events <- StreamStopped(sess.ID, r.currentAgent.Name)
+	close(events)
+	return events
+}
+
+// Run is not supported while replaying.
+func (r *ReplayRuntime) Run(context.Context, *session.Session) ([]session.Message, error) {
+	return nil, errReplayReadOnly
+}
+
+// Resume is a no-op: recorded tool calls were already confirmed.
+func (r *ReplayRuntime) Resume(context.Context, ResumeRequest) {
+}
+
+// ResumeElicitation is a no-op: recorded elicitations were already answered.
+func (r *ReplayRuntime) ResumeElicitation(context.Context, tools.ElicitationAction, map[string]any) error {
+	return nil
+}
+
+// SessionStore returns nil: replays are not stored.
+func (r *ReplayRuntime) SessionStore() session.Store {
+	return nil
+}
+
+// Summarize is not supported while replaying.
+func (r *ReplayRuntime) Summarize(_ context.Context, _ *session.Session, _ string, events chan Event) {
+	events <- Warning(fmt.Sprintf("Cannot compact: %v", errReplayReadOnly), r.currentAgent.Name)
+}
+
+// PermissionsInfo returns nil: tools are never run while replaying.
+func (r *ReplayRuntime) PermissionsInfo() *PermissionsInfo {
+	return nil
+}
+
+// CurrentAgentSkillsToolset returns nil: skills are never run while replaying.
+func (r *ReplayRuntime) CurrentAgentSkillsToolset() *builtin.SkillsToolset {
+	return nil
+}
+
+// CurrentMCPPrompts returns no prompts: MCP servers are not started while replaying.
+func (r *ReplayRuntime) CurrentMCPPrompts(context.Context) map[string]mcp.PromptInfo {
+	return make(map[string]mcp.PromptInfo)
+}
+
+// ExecuteMCPPrompt is not supported while replaying.
+func (r *ReplayRuntime) ExecuteMCPPrompt(context.Context, string, map[string]string) (string, error) {
+	return "", errReplayReadOnly
+}
+
+// UpdateSessionTitle only updates the title shown in the UI.
+func (r *ReplayRuntime) UpdateSessionTitle(_ context.Context, sess *session.Session, title string) error {
+	sess.Title = title
+	return nil
+}
+
+// TitleGenerator returns nil: titles are part of the replayed events.
+func (r *ReplayRuntime) TitleGenerator() *sessiontitle.Generator {
+	return nil
+}
+
+// Close is a no-op for replay runtimes.
+func (r *ReplayRuntime) Close() error {
+	return nil
+}
+
+var _ Runtime = (*ReplayRuntime)(nil)
@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/runlog"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// WithRunLog records every run to a local run log: the events, the model
+// requests and responses, and the tool calls with their duration.
+func WithRunLog(l *runlog.Log) Opt {
+	return func(r *LocalRuntime) {
+		if l == nil {
+			return
+		}
+		r.runLog = l
+		r.events.Subscribe(EventSinkFunc(r.recordEvent))
+	}
+}
+
+// runLogSessionID returns the session whose run log an entry goes to.
+// Sub-sessions (transferred tasks, background agents) are recorded in the
+// run log of their top-level session.
+func (r *LocalRuntime) runLogSessionID(sess *session.Session) string {
+	if sess.ParentID == "" {
+		return sess.ID
+	}
+	if root, ok := r.runLogRoots.Load(sess.ParentID); ok {
+		return root.(string)
+	}
+	return sess.ParentID
+}
+
+func (r *LocalRuntime) record(sess *session.Session, entryType string, data any) {
+	r.recordTo(r.runLogSessionID(sess), entryType, data)
+}
+
+func (r *LocalRuntime) recordTo(sessionID, entryType string, data any) {
+	if err := r.runLog.Record(sessionID, entryType, data); err != nil {
+		slog.Debug("Failed to write run log", "type", entryType, "error", err)
+	}
+}
+
+// recordEvent is the event sink of the run log. Tool call start times are
+// kept by tool call ID to record the duration of the calls.
+func (r *LocalRuntime) recordEvent(sessionID string, event Event) {
+	r.recordTo(sessionID, runlog.TypeEvent, event)
+
+	switch e := event.(type) {
+	case *ToolCallEvent:
+		r.runLogToolStarts.Store(e.ToolCall.ID, e.Timestamp)
+	case *ToolCallResponseEvent:
+		var duration time.Duration
+		if start, ok := r.runLogToolStarts.LoadAndDelete(e.ToolCall.ID); ok {
+			duration = e.Timestamp.Sub(start.(time.Time))
+		}
+		r.recordTo(sessionID, runlog.TypeToolCall, runlog.ToolCall{
+			Agent:      e.AgentName,
+			ID:         e.ToolCall.ID,
+			Name:       e.ToolCall.Function.Name,
+			Arguments:  e.ToolCall.Function.Arguments,
+			DurationMs: duration.Milliseconds(),
+			Output:     e.Response,
+			IsError:    e.Result != nil && e.Result.IsError,
+		})
+	}
+}
+
+func (r *LocalRuntime) recordModelRequest(sess *session.Session, agentName, model string, messages []chat.Message, agentTools []tools.Tool) {
+	if r.runLog == nil {
+		return
+	}
+
+	toolNames := make([]string, len(agentTools))
+	for i, t := range agentTools {
+		toolNames[i] = t.Name
+	}
+	r.record(sess, runlog.TypeModelRequest, runlog.ModelRequest{
+		Agent:    agentName,
+		Model:    model,
+		Messages: messages,
+		Tools:    toolNames,
+	})
+}
+
+func (r *LocalRuntime) recordModelResponse(sess *session.Session, agentName, model string, start time.Time, res streamResult, err error) {
+	if r.runLog == nil {
+		return
+	}
+
+	response := runlog.ModelResponse{
+		Agent:      agentName,
+		Model:      model,
+		DurationMs: time.Since(start).Milliseconds(),
+		Content:    res.Content,
+		Reasoning:  res.ReasoningContent,
+		ToolCalls:  res.Calls,
+		Usage:      res.Usage,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	r.record(sess, runlog.TypeModelResponse, response)
+}
@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/runlog"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+)
+
+func recordRun(t *testing.T) (*session.Session, []runlog.Entry, []Event) {
+	t.Helper()
+
+	stream := newStreamBuilder().
+		AddContent("Hello").
+		AddStopWithUsage(3, 2).
+		Build()
+
+	prov := &mockProvider{id: "test/mock-model", stream: stream}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	tm := team.New(team.WithAgents(root))
+
+	log := runlog.New(filepath.Join(t.TempDir(), "runs"))
+	rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithRunLog(log))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"))
+
+	var events []Event
+	for ev := range rt.RunStream(t.Context(), sess) {
+		events = append(events, ev)
+	}
+	require.NoError(t, log.Close())
+
+	entries, err := runlog.ReadFile(log.Path(sess.ID))
+	require.NoError(t, err)
+	return sess, entries, events
+}
+
+func TestRunLog_RecordsEventsAndModelCalls(t *testing.T) {
+	t.Parallel()
+
+	sess, entries, events := recordRun(t)
+
+	var eventEntries int
+	var request runlog.ModelRequest
+	var response runlog.ModelResponse
+	for _, entry := range entries {
+		assert.Equal(t, sess.ID, entry.SessionID)
+		switch entry.Type {
+		case runlog.TypeEvent:
+			eventEntries++
+		case runlog.TypeModelRequest:
+			require.NoError(t, json.Unmarshal(entry.Data, &request))
+		case runlog.TypeModelResponse:
+			require.NoError(t, json.Unmarshal(entry.Data, &response))
+		}
+	}
+
+	assert.Equal(t, len(events), eventEntries)
+
+	assert.Equal(t, "root", request.Agent)
+	assert.Equal(t, "test/mock-model", request.Model)
+	require.NotEmpty(t, request.Messages)
+	assert.Equal(t, "Hi", request.Messages[len(request.Messages)-1].Content)
+
+	assert.Equal(t, "Hello", response.Content)
+	require.NotNil(t, response.Usage)
+	assert.Equal(t, int64(2), response.Usage.OutputTokens)
+	assert.Empty(t, response.Error)
+}
+
+func TestReplayRuntime(t *testing.T) {
+	t.Parallel()
+
+	_, entries, events := recordRun(t)
+
+	rt, err := NewReplayRuntime(entries, WithReplaySpeed(0))
+	require.NoError(t, err)
+	assert.Equal(t, "root", rt.CurrentAgentName())
+
+	replayed := make(chan Event, len(entries))
+	rt.EmitStartupInfo(t.Context(), nil, replayed)
+	close(replayed)
+
+	var types []string
+	for ev := range replayed {
+		types = append(types, eventType(ev))
+	}
+	// Events only used to persist sessions are not replayed
+	var want []string
+	for _, ev := range events {
+		if _, ok := eventRegistry()[eventType(ev)]; ok {
+			want = append(want, eventType(ev))
+		}
+	}
+	assert.Equal(t, want, types)
+
+	// Replays never reach a model
+	sess := session.New(session.WithUserMessage("Hi again"))
+	var errs []string
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if e, ok := ev.(*ErrorEvent); ok {
+			errs = append(errs, e.Error)
+		}
+	}
+	assert.Equal(t, []string{errReplayReadOnly.Error()}, errs)
+}
+
+func TestNewReplayRuntime_NoEvents(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewReplayRuntime([]runlog.Entry{{Type: runlog.TypeModelRequest, Data: json.RawMessage(`{}`)}})
+	require.Error(t, err)
+}
+
+func eventType(ev Event) string {
+	data, _ := json.Marshal(ev)
+	var base struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(data, &base)
+	return base.Type
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -15,11 +16,14 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/audit"
 	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/continuity"
 	"github.com/docker/docker-agent/pkg/hooks"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/rag"
 	ragtypes "github.com/docker/docker-agent/pkg/rag/types"
+	"github.com/docker/docker-agent/pkg/runlog"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/sessiontitle"
 	"github.com/docker/docker-agent/pkg/team"
@@ -51,6 +55,7 @@ const (
 	ResumeTypeApprove        ResumeType = "approve"
 	ResumeTypeApproveSession ResumeType = "approve-session"
 	ResumeTypeApproveTool    ResumeType = "approve-tool"
+	ResumeTypeApproveAlways  ResumeType = "approve-always"
 	ResumeTypeReject         ResumeType = "reject"
 )
 
@@ -59,9 +64,20 @@ const (
 type ResumeRequest struct {
 	Type     ResumeType
 	Reason   string // Optional; primarily used with ResumeTypeReject
-	ToolName string // Optional; used with ResumeTypeApproveTool to specify which tool to always allow
+	ToolName string // Optional; used with ResumeTypeApproveTool and ResumeTypeApproveAlways to specify which tool, or permission pattern, to always allow
+	// Iterations is how many more iterations a run that reached its
+	// max_iterations limit may take once approved. Zero means
+	// DefaultContinueIterations.
+	Iterations int
+	// User is the user answering, in sessions shared by several users of
+	// the API server. It's recorded with the decision.
+	User string
 }
 
+// DefaultContinueIterations is how many more iterations a run takes when
+// continued after reaching its max_iterations limit.
+const DefaultContinueIterations = 10
+
 // ResumeApprove creates a ResumeRequest to approve a single tool call.
 func ResumeApprove() ResumeRequest {
 	return ResumeRequest{Type: ResumeTypeApprove}
@@ -77,6 +93,19 @@ func ResumeApproveTool(toolName string) ResumeRequest {
 	return ResumeRequest{Type: ResumeTypeApproveTool, ToolName: toolName}
 }
 
+// ResumeApproveAlways creates a ResumeRequest to always approve a tool, or the
+// tool calls matching a permission pattern like "shell:cmd=git*". The pattern
+// is saved to the permissions store, to be approved in the next sessions too.
+func ResumeApproveAlways(pattern string) ResumeRequest {
+	return ResumeRequest{Type: ResumeTypeApproveAlways, ToolName: pattern}
+}
+
+// ResumeContinue creates a ResumeRequest to continue a run that reached its
+// max_iterations limit for the given number of iterations.
+func ResumeContinue(iterations int) ResumeRequest {
+	return ResumeRequest{Type: ResumeTypeApprove, Iterations: iterations}
+}
+
 // ResumeReject creates a ResumeRequest to reject a tool call with an optional reason.
 func ResumeReject(reason string) ResumeRequest {
 	return ResumeRequest{Type: ResumeTypeReject, Reason: reason}
@@ -178,25 +207,48 @@ type ToolsChangeSubscriber interface {
 	OnToolsChanged(handler func(Event))
 }
 
+// ModelPullSubscriber is implemented by runtimes that can report progress
+// while pulling a local model that a model switch depends on. The provided
+// callback is invoked outside of any RunStream.
+type ModelPullSubscriber interface {
+	OnModelPullProgress(handler func(Event))
+}
+
+// SessionEnder is implemented by runtimes that release the resources of a
+// session, such as the background processes started by its tools, when the
+// user moves on to another session.
+type SessionEnder interface {
+	EndSession(ctx context.Context, sess *session.Session)
+}
+
 // LocalRuntime manages the execution of agents
 type LocalRuntime struct {
-	toolMap                     map[string]ToolHandlerFunc
-	team                        *team.Team
-	currentAgent                string
-	resumeChan                  chan ResumeRequest
-	tracer                      trace.Tracer
-	modelsStore                 ModelStore
-	sessionCompaction           bool
-	managedOAuth                bool
-	startupInfoEmitted          bool                   // Track if startup info has been emitted to avoid unnecessary duplication
-	elicitationRequestCh        chan ElicitationResult // Channel for receiving elicitation responses
-	elicitationEventsChannel    chan Event             // Current events channel for sending elicitation requests
-	elicitationEventsChannelMux sync.RWMutex           // Protects elicitationEventsChannel
-	ragInitialized              atomic.Bool
-	sessionStore                session.Store
-	workingDir                  string   // Working directory for hooks execution
-	env                         []string // Environment variables for hooks execution
-	modelSwitcherCfg            *ModelSwitcherConfig
+	toolMap              map[string]ToolHandlerFunc
+	toolOutputs          toolOutputStore
+	toolSearch           toolSearchStore
+	attachmentPreviews   attachmentPreviews
+	team                 *team.Team
+	currentAgent         string
+	resumeChan           chan ResumeRequest
+	tracer               trace.Tracer
+	modelsStore          ModelStore
+	sessionCompaction    bool
+	managedOAuth         bool
+	startupInfoEmitted   bool                   // Track if startup info has been emitted to avoid unnecessary duplication
+	elicitationRequestCh chan ElicitationResult // Channel for receiving elicitation responses
+	ragInitialized       atomic.Bool
+	sessionStore         session.Store
+	workingDir           string   // Working directory for hooks execution
+	env                  []string // Environment variables for hooks execution
+	modelSwitcherCfg     *ModelSwitcherConfig
+
+	// events publishes the events of top-level sessions to the sinks
+	// (run log, traces, ...). streams are the top-level streams being
+	// consumed, most recent last; events that don't come from the loop of
+	// a session, like elicitation requests, are sent to the last one.
+	events    *EventBus
+	streams   []*eventStream
+	streamsMu sync.Mutex
 
 	// retryOnRateLimit enables retry-with-backoff for HTTP 429 (rate limit) errors
 	// when no fallback models are configured. When false (default), 429 errors are
@@ -213,7 +265,42 @@ type LocalRuntime struct {
 	// onToolsChanged is called when an MCP toolset reports a tool list change.
 	onToolsChanged func(Event)
 
+	// onModelPullProgress is called while a local model is being pulled.
+	onModelPullProgress func(Event)
+
 	bgAgents *agenttool.Handler
+
+	// runLog records runs locally when enabled. runLogRoots maps
+	// sub-sessions to the top-level session they are recorded in.
+	// runLogToolStarts holds the start time of running tool calls.
+	runLog           *runlog.Log
+	runLogRoots      sync.Map
+	runLogToolStarts sync.Map
+
+	// auditLog records the tool calls and the approvals of the user when
+	// enabled. auditToolStarts holds the start time of running tool calls.
+	auditLog        *audit.Log
+	auditToolStarts sync.Map
+
+	// userCommands are the slash commands defined by the user, available
+	// to every agent. Agent commands with the same name take precedence.
+	userCommands types.Commands
+
+	// contextWarningThresholds are the percentages of the context window
+	// at which a warning is sent. contextWarnings holds the last threshold
+	// each session was warned about.
+	contextWarningThresholds []int
+	contextWarnings          sync.Map
+
+	// continuityStore stores the summaries of the sessions of agents with
+	// continuity enabled. continuityAgents holds, for each session whose
+	// summary was loaded, the agent the summary belongs to.
+	continuityStore  *continuity.Store
+	continuityAgents sync.Map
+
+	// permissionsStore holds the permissions kept across sessions, where
+	// the tool calls the user always approves are saved.
+	permissionsStore PermissionsStore
 }
 
 type Opt func(*LocalRuntime)
@@ -269,6 +356,22 @@ func WithEnv(env []string) Opt {
 	}
 }
 
+// WithUserCommands makes user-defined slash commands available to every
+// agent, in addition to the commands of their configuration.
+func WithUserCommands(commands types.Commands) Opt {
+	return func(r *LocalRuntime) {
+		r.userCommands = commands
+	}
+}
+
+// WithEventSink subscribes a sink to the events of the runtime's top-level
+// sessions. See EventBus.
+func WithEventSink(sink EventSink) Opt {
+	return func(r *LocalRuntime) {
+		r.events.Subscribe(sink)
+	}
+}
+
 // WithRetryOnRateLimit enables automatic retry with backoff for HTTP 429 (rate limit)
 // errors when no fallback models are available. When enabled, the runtime will honor
 // the Retry-After header from the provider's response to determine wait time before
@@ -300,10 +403,14 @@ func NewLocalRuntime(agents *team.Team, opts ...Opt) (*LocalRuntime, error) {
 		currentAgent:         defaultAgent.Name(),
 		resumeChan:           make(chan ResumeRequest),
 		elicitationRequestCh: make(chan ElicitationResult),
+		events:               NewEventBus(),
 		sessionCompaction:    true,
 		managedOAuth:         true,
 		sessionStore:         session.NewInMemorySessionStore(),
 		fallbackCooldowns:    make(map[string]*fallbackCooldownState),
+
+		contextWarningThresholds: DefaultContextWarningThresholds,
+		continuityStore:          continuity.DefaultStore(),
 	}
 	r.bgAgents = agenttool.NewHandler(r)
 
@@ -459,7 +566,7 @@ func (r *LocalRuntime) CurrentAgentInfo(context.Context) CurrentAgentInfo {
 	return CurrentAgentInfo{
 		Name:        currentAgent.Name(),
 		Description: currentAgent.Description(),
-		Commands:    currentAgent.Commands(),
+		Commands:    r.agentCommands(currentAgent.Commands()),
 	}
 }
 
@@ -474,7 +581,17 @@ func (r *LocalRuntime) SetCurrentAgent(agentName string) error {
 }
 
 func (r *LocalRuntime) CurrentAgentCommands(context.Context) types.Commands {
-	return r.CurrentAgent().Commands()
+	return r.agentCommands(r.CurrentAgent().Commands())
+}
+
+// agentCommands returns the commands of an agent merged with the user commands.
+func (r *LocalRuntime) agentCommands(commands types.Commands) types.Commands {
+	if len(r.userCommands) == 0 {
+		return commands
+	}
+	merged := maps.Clone(r.userCommands)
+	maps.Copy(merged, commands)
+	return merged
 }
 
 // CurrentAgentTools returns the tools available to the current agent.
@@ -628,7 +745,16 @@ func (r *LocalRuntime) ExecuteMCPPrompt(ctx context.Context, promptName string,
 }
 
 // TitleGenerator returns a title generator for automatic session title generation.
+// It uses the titles model of the team if there's one, or the model of the
+// current agent. It returns nil when title generation is disabled.
 func (r *LocalRuntime) TitleGenerator() *sessiontitle.Generator {
+	if !r.team.TitlesEnabled() {
+		return nil
+	}
+	if model := r.team.TitleModel(); model != nil {
+		return sessiontitle.New(model)
+	}
+
 	a := r.CurrentAgent()
 	if a == nil {
 		return nil
@@ -744,6 +870,12 @@ func (r *LocalRuntime) SessionStore() session.Store {
 	return r.sessionStore
 }
 
+// EventBus returns the bus the events of top-level sessions are published
+// on, to subscribe new sinks.
+func (r *LocalRuntime) EventBus() *EventBus {
+	return r.events
+}
+
 // Close releases resources held by the runtime, including the session store.
 func (r *LocalRuntime) Close() error {
 	r.bgAgents.StopAll()
@@ -753,6 +885,28 @@ func (r *LocalRuntime) Close() error {
 	return nil
 }
 
+// EndSession releases the resources the toolsets of the team hold for a
+// session, such as background shell jobs.
+func (r *LocalRuntime) EndSession(ctx context.Context, sess *session.Session) {
+	slog.Debug("Ending session", "session_id", sess.ID)
+
+	r.toolSearch.remove(sess.ID)
+
+	for _, name := range r.team.AgentNames() {
+		a, err := r.team.Agent(name)
+		if err != nil {
+			continue
+		}
+		for _, ts := range a.ToolSets() {
+			if ender, ok := tools.As[tools.SessionEnder](ts); ok {
+				ender.EndSession(tools.WithSessionID(ctx, sess.ID))
+			}
+		}
+	}
+}
+
+var _ SessionEnder = (*LocalRuntime)(nil)
+
 // UpdateSessionTitle persists the session title via the session store.
 func (r *LocalRuntime) UpdateSessionTitle(ctx context.Context, sess *session.Session, title string) error {
 	sess.Title = title
@@ -796,18 +950,32 @@ func (r *LocalRuntime) OnToolsChanged(handler func(Event)) {
 		}
 		for _, ts := range a.ToolSets() {
 			if n, ok := tools.As[tools.ChangeNotifier](ts); ok {
-				n.SetToolsChangedHandler(r.emitToolsChanged)
+				desc := tools.DescribeToolSet(ts)
+				n.SetToolsChangedHandler(func(change tools.ToolsChange) {
+					r.emitToolsChanged(name, desc, change)
+				})
 			}
 		}
 	}
 }
 
-// emitToolsChanged is the callback registered on MCP toolsets. It re-reads
-// the current agent's full tool list and pushes a ToolsetInfo event.
-func (r *LocalRuntime) emitToolsChanged() {
+// OnModelPullProgress registers a handler that is called with progress
+// updates while a model selected at runtime is being pulled locally.
+func (r *LocalRuntime) OnModelPullProgress(handler func(Event)) {
+	r.onModelPullProgress = handler
+}
+
+// emitToolsChanged is the callback registered on MCP toolsets. It reports
+// the tools the toolset added, removed or changed, so that tools aren't
+// swapped silently, then re-reads the current agent's full tool list and
+// pushes a ToolsetInfo event.
+func (r *LocalRuntime) emitToolsChanged(agentName, toolset string, change tools.ToolsChange) {
 	if r.onToolsChanged == nil {
 		return
 	}
+	if !change.IsEmpty() {
+		r.onToolsChanged(ToolListChanged(toolset, change, agentName))
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	agentTools, err := r.CurrentAgentTools(ctx)
@@ -893,6 +1061,11 @@ func (r *LocalRuntime) emitToolsProgressively(ctx context.Context, a *agent.Agen
 		return
 	}
 
+	// Start the toolsets concurrently, reporting the readiness of each one
+	_ = a.StartToolSets(ctx, func(toolSet string, status agent.ToolSetStatus, err error) {
+		send(ToolsetStatus(toolSet, status, err, a.Name()))
+	})
+
 	// Load tools from each toolset and emit progress
 	var totalTools int
 	for i, toolset := range toolsets {
@@ -903,14 +1076,9 @@ func (r *LocalRuntime) emitToolsProgressively(ctx context.Context, a *agent.Agen
 
 		isLast := i == totalToolsets-1
 
-		// Start the toolset if needed
-		if startable, ok := toolset.(*tools.StartableToolSet); ok {
-			if !startable.IsStarted() {
-				if err := startable.Start(ctx); err != nil {
-					slog.Warn("Toolset start failed; skipping", "agent", a.Name(), "toolset", fmt.Sprintf("%T", startable.ToolSet), "error", err)
-					continue
-				}
-			}
+		// Skip the toolsets that failed to start
+		if startable, ok := toolset.(*tools.StartableToolSet); ok && !startable.IsStarted() {
+			continue
 		}
 
 		// Get tools from this toolset
@@ -1017,44 +1185,71 @@ func (r *LocalRuntime) Summarize(ctx context.Context, sess *session.Session, add
 	events <- NewTokenUsageEvent(sess.ID, a.Name(), SessionUsage(sess, contextLimit))
 }
 
-// swapElicitationEventsChannel atomically replaces the current elicitation
-// events channel and returns the previous one. Each RunStream call swaps in
-// its own channel on entry and swaps the previous one back on exit, so nested
-// streams (sub-sessions, background agents) don't lose the parent's channel.
-func (r *LocalRuntime) swapElicitationEventsChannel(ch chan Event) chan Event {
-	r.elicitationEventsChannelMux.Lock()
-	defer r.elicitationEventsChannelMux.Unlock()
-	prev := r.elicitationEventsChannel
-	r.elicitationEventsChannel = ch
-	return prev
-}
-
 // elicitationHandler creates an elicitation handler that can be used by MCP clients
 // This handler propagates elicitation requests to the runtime's client via events
+// addRecord adds a record to the session and tells the client about it, so
+// that it can be persisted.
+func (r *LocalRuntime) addRecord(sess *session.Session, record *session.Record, events chan Event) {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	sess.AddRecord(record)
+	events <- RecordAdded(sess.ID, record, record.AgentName)
+}
+
 func (r *LocalRuntime) elicitationHandler(ctx context.Context, req *mcp.ElicitParams) (tools.ElicitationResult, error) {
 	slog.Debug("Elicitation request received from MCP server", "message", req.Message)
 
-	// Hold the read lock while sending to the channel to prevent a race
-	// with swapElicitationEventsChannel / close(events).
-	r.elicitationEventsChannelMux.RLock()
-	eventsChannel := r.elicitationEventsChannel
-	if eventsChannel == nil {
-		r.elicitationEventsChannelMux.RUnlock()
+	stream := r.currentStream()
+	if stream == nil {
 		return tools.ElicitationResult{}, errors.New("no events channel available for elicitation")
 	}
 
 	r.executeOnUserInputHooks(ctx, "", "elicitation")
 
+	// Escalations are recorded as soon as they happen, since the human may
+	// answer long after, or never.
+	if reason, ok := req.Meta[builtin.EscalationMetaKey].(string); ok && stream.sess != nil {
+		record := &session.Record{
+			AgentName:  r.CurrentAgentName(),
+			CreatedAt:  time.Now(),
+			Escalation: &session.EscalationRecord{Reason: reason},
+		}
+		stream.sess.AddRecord(record)
+		if err := stream.send(ctx, RecordAdded(stream.sess.ID, record, record.AgentName)); err != nil {
+			slog.Debug("Failed to send escalation record", "error", err)
+		}
+	}
+
 	slog.Debug("Sending elicitation request event to client", "message", req.Message, "mode", req.Mode, "requested_schema", req.RequestedSchema, "url", req.URL)
 	slog.Debug("Elicitation request meta", "meta", req.Meta)
 
 	// Send elicitation request event to the runtime's client
-	eventsChannel <- ElicitationRequest(req.Message, req.Mode, req.RequestedSchema, req.URL, req.ElicitationID, req.Meta, r.CurrentAgentName())
-	r.elicitationEventsChannelMux.RUnlock()
+	event := ElicitationRequest(req.Message, req.Mode, req.RequestedSchema, req.URL, req.ElicitationID, req.Meta, r.CurrentAgentName())
+	if err := stream.send(ctx, event); err != nil {
+		return tools.ElicitationResult{}, err
+	}
 
 	// Wait for response from the client
 	select {
 	case result := <-r.elicitationRequestCh:
+		if stream.sess != nil {
+			fields := slices.Sorted(maps.Keys(result.Content))
+			record := &session.Record{
+				AgentName: r.CurrentAgentName(),
+				CreatedAt: time.Now(),
+				Elicitation: &session.ElicitationRecord{
+					Message: req.Message,
+					URL:     req.URL,
+					Action:  string(result.Action),
+					Fields:  fields,
+				},
+			}
+			stream.sess.AddRecord(record)
+			if err := stream.send(ctx, RecordAdded(stream.sess.ID, record, record.AgentName)); err != nil {
+				slog.Debug("Failed to send elicitation record", "error", err)
+			}
+		}
 		return tools.ElicitationResult{
 			Action:  result.Action,
 			Content: result.Content,
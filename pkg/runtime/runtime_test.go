@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net/http"
 	"reflect"
 	"sync"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/modelerrors"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/permissions"
 	"github.com/docker/docker-agent/pkg/rag"
@@ -128,6 +130,16 @@ func (b *streamBuilder) AddToolCallArguments(id, argsChunk string) *streamBuilde
 	return b
 }
 
+func (b *streamBuilder) AddCitation(citation chat.Citation) *streamBuilder {
+	b.responses = append(b.responses, chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{
+			Index: 0,
+			Delta: chat.MessageDelta{Citations: []chat.Citation{citation}},
+		}},
+	})
+	return b
+}
+
 func (b *streamBuilder) AddStopWithUsage(input, output int64) *streamBuilder {
 	b.responses = append(b.responses, chat.MessageStreamResponse{
 		Choices: []chat.MessageStreamChoice{{
@@ -231,12 +243,17 @@ func assertEventsEqual(t *testing.T, expected, actual []Event) {
 	}
 }
 
-// clearTimestamps sets Timestamp fields to zero value in events for comparison.
+// clearTimestamps sets Timestamp fields, and the latency of model calls, to
+// zero value in events for comparison.
 func clearTimestamps(event Event) {
 	if event == nil {
 		return
 	}
 
+	if e, ok := event.(*TokenUsageEvent); ok && e.Usage != nil && e.Usage.LastMessage != nil {
+		e.Usage.LastMessage.LatencyMs = 0
+	}
+
 	// Use reflection to find and clear Timestamp in embedded AgentContext
 	v := reflect.ValueOf(event)
 	if v.Kind() == reflect.Pointer {
@@ -294,6 +311,71 @@ func TestSimple(t *testing.T) {
 	assertEventsEqual(t, expectedEvents, events)
 }
 
+func TestCitations(t *testing.T) {
+	citation := chat.Citation{
+		Type:      "search_result_location",
+		CitedText: "Paris is the capital of France.",
+		Source:    "https://example.com/france",
+		Title:     "France",
+	}
+	stream := newStreamBuilder().
+		AddContent("The capital is ").
+		AddCitation(citation).
+		AddContent("Paris.").
+		AddStopWithUsage(3, 2).
+		Build()
+
+	sess := session.New(session.WithUserMessage("What is the capital of France?"))
+
+	events := runSession(t, sess, stream)
+
+	var msgAdded *MessageAddedEvent
+	var citationsEvent *CitationsEvent
+	for _, event := range events {
+		switch e := event.(type) {
+		case *MessageAddedEvent:
+			msgAdded = e
+		case *CitationsEvent:
+			citationsEvent = e
+		}
+	}
+	require.NotNil(t, msgAdded)
+	require.NotNil(t, citationsEvent)
+
+	expected := citation
+	expected.ContentOffset = len("The capital is ")
+	assert.Equal(t, "The capital is Paris.", msgAdded.Message.Message.Content)
+	assert.Equal(t, []chat.Citation{expected}, msgAdded.Message.Message.Citations)
+	assert.Equal(t, []chat.Citation{expected}, citationsEvent.Citations)
+	assert.Equal(t, sess.ID, citationsEvent.SessionID)
+}
+
+func TestTokenUsageReportsPromptCache(t *testing.T) {
+	stream := newStreamBuilder().
+		AddContent("Hello").
+		Build()
+	stream.responses = append(stream.responses, chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{FinishReason: chat.FinishReasonStop}},
+		Usage:   &chat.Usage{InputTokens: 10, OutputTokens: 2, CachedInputTokens: 30, CacheWriteTokens: 10},
+	})
+
+	sess := session.New(session.WithUserMessage("Hi"))
+
+	events := runSession(t, sess, stream)
+
+	var usage *Usage
+	for _, event := range events {
+		if e, ok := event.(*TokenUsageEvent); ok {
+			usage = e.Usage
+		}
+	}
+	require.NotNil(t, usage)
+	require.NotNil(t, usage.Cache)
+	assert.Equal(t, CacheUsage{InputTokens: 10, ReadTokens: 30, WriteTokens: 10}, *usage.Cache)
+	assert.InDelta(t, 0.6, usage.Cache.HitRate(), 1e-9)
+	assert.Equal(t, int64(30), usage.LastMessage.CachedInputTokens)
+}
+
 func TestMultipleContentChunks(t *testing.T) {
 	stream := newStreamBuilder().
 		AddContent("Hello ").
@@ -417,6 +499,34 @@ func TestMixedContentAndReasoning(t *testing.T) {
 	assertEventsEqual(t, expectedEvents, events)
 }
 
+func TestTurnUsage(t *testing.T) {
+	stream := newStreamBuilder().
+		AddContent("Hello").
+		AddStopWithUsage(3, 2).
+		Build()
+	stream.responses[0].ID = "msg_123"
+
+	sess := session.New(session.WithUserMessage("Hi"))
+	events := runSession(t, sess, stream)
+
+	var usage *MessageUsage
+	for _, ev := range events {
+		if e, ok := ev.(*TokenUsageEvent); ok {
+			usage = e.Usage.LastMessage
+		}
+	}
+	require.NotNil(t, usage)
+	assert.Equal(t, "msg_123", usage.ResponseID)
+	assert.GreaterOrEqual(t, usage.LatencyMs, int64(0))
+
+	// The usage of the turn is saved with its message.
+	messages := sess.GetAllMessages()
+	answer := messages[len(messages)-1].Message
+	assert.Equal(t, "msg_123", answer.ResponseID)
+	assert.Equal(t, usage.LatencyMs, answer.LatencyMs)
+	assert.Equal(t, int64(2), answer.Usage.OutputTokens)
+}
+
 func TestToolCallSequence(t *testing.T) {
 	stream := newStreamBuilder().
 		AddToolCallName("call_123", "test_tool").
@@ -467,6 +577,21 @@ func TestErrorEvent(t *testing.T) {
 	require.Contains(t, errorEvent.Error, "simulated error")
 }
 
+func TestModelError(t *testing.T) {
+	raw := &modelerrors.StatusError{StatusCode: http.StatusUnauthorized, Err: errors.New(`401 {"type":"authentication_error"}`)}
+
+	event := ModelError(raw).(*ErrorEvent)
+	assert.Equal(t, ErrorCodeModel, event.Code)
+	assert.Equal(t, string(modelerrors.KindAuthentication), event.Kind)
+	assert.Equal(t, raw.Error(), event.Detail)
+	assert.NotContains(t, event.Error, "authentication_error", "users get a message instead of the raw error")
+	assert.Contains(t, event.Text(), event.Remediation)
+
+	event = ModelError(errors.New("simulated error")).(*ErrorEvent)
+	assert.Equal(t, "simulated error", event.Text())
+	assert.Empty(t, event.Kind)
+}
+
 func TestContextCancellation(t *testing.T) {
 	stream := newStreamBuilder().
 		AddContent("This should not complete").
@@ -1439,6 +1564,17 @@ func TestToolRejectionWithReason(t *testing.T) {
 	require.True(t, toolResponse.Result.IsError, "expected tool result to be an error")
 	require.Contains(t, toolResponse.Response, "The user rejected the tool call.")
 	require.Contains(t, toolResponse.Response, "Reason: The arguments provided are incorrect.")
+
+	// The rejection is recorded in the session
+	records := sess.GetRecords()
+	require.Len(t, records, 1)
+	require.Equal(t, session.ToolApprovalRecord{
+		ToolCallID: "call_1",
+		ToolName:   "shell",
+		Arguments:  "{}",
+		Decision:   "reject",
+		Reason:     "The arguments provided are incorrect.",
+	}, *records[0].ToolApproval)
 }
 
 func TestToolRejectionWithoutReason(t *testing.T) {
@@ -1706,6 +1842,55 @@ func TestYoloMode_OverridesSessionDeny(t *testing.T) {
 	require.True(t, executed, "expected tool to be executed in --yolo mode despite session deny permission")
 }
 
+func TestAlwaysAsk_OverridesYoloMode(t *testing.T) {
+	// Test that tools that must always be confirmed ask even in --yolo mode
+	var executed bool
+	agentTools := []tools.Tool{{
+		Name:       "edit_agent_config",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+			executed = true
+			return tools.ResultSuccess("executed"), nil
+		},
+		AlwaysAsk: true,
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+	)
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Test"), session.WithToolsApproved(true))
+
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "edit_agent_config", Arguments: "{}"},
+	}}
+
+	events := make(chan Event, 10)
+	go func() {
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+		close(events)
+	}()
+
+	var confirmed bool
+	for ev := range events {
+		if _, ok := ev.(*ToolCallConfirmationEvent); ok {
+			confirmed = true
+			rt.resumeChan <- ResumeApprove()
+		}
+	}
+
+	require.True(t, confirmed, "expected a confirmation request despite --yolo")
+	require.True(t, executed, "expected tool to be executed once approved")
+}
+
 func TestStripImageContent(t *testing.T) {
 	t.Parallel()
 
@@ -1929,3 +2114,86 @@ func TestProcessToolCalls_UsesPinnedAgent(t *testing.T) {
 		}
 	}
 }
+
+func TestTitleGenerator(t *testing.T) {
+	t.Parallel()
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+	assert.NotNil(t, rt.TitleGenerator())
+
+	rt, err = NewLocalRuntime(team.New(team.WithAgents(root), team.WithTitles(nil, false)), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+	assert.Nil(t, rt.TitleGenerator(), "title generation is disabled")
+}
+
+func TestProcessToolCalls_StructuredContent(t *testing.T) {
+	type output struct {
+		Count int `json:"count"`
+	}
+
+	agentTools := []tools.Tool{{
+		Name:         "valid_tool",
+		Parameters:   map[string]any{},
+		OutputSchema: tools.MustSchemaFor[output](),
+		Handler: func(ctx context.Context, tc tools.ToolCall) (*tools.ToolCallResult, error) {
+			return tools.ResultJSON(output{Count: 42}), nil
+		},
+	}, {
+		Name:         "invalid_tool",
+		Parameters:   map[string]any{},
+		OutputSchema: tools.MustSchemaFor[output](),
+		Handler: func(ctx context.Context, tc tools.ToolCall) (*tools.ToolCallResult, error) {
+			return tools.ResultJSON(map[string]any{"count": "many"}), nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+	)
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Test"))
+	sess.ToolsApproved = true
+
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "valid_tool", Arguments: "{}"},
+	}, {
+		ID:       "call_2",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "invalid_tool", Arguments: "{}"},
+	}}
+
+	events := make(chan Event, 20)
+	rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+	close(events)
+
+	responses := map[string]*ToolCallResponseEvent{}
+	for ev := range events {
+		if tr, ok := ev.(*ToolCallResponseEvent); ok {
+			responses[tr.ToolCall.ID] = tr
+		}
+	}
+	require.Len(t, responses, 2)
+	assert.Equal(t, map[string]any{"count": float64(42)}, responses["call_1"].Result.StructuredContent)
+	assert.Nil(t, responses["call_2"].Result.StructuredContent)
+	assert.JSONEq(t, `{"count":"many"}`, responses["call_2"].Result.Output)
+
+	structured := map[string]any{}
+	for _, msg := range sess.GetAllMessages() {
+		if msg.Message.Role == chat.MessageRoleTool {
+			structured[msg.Message.ToolCallID] = msg.Message.StructuredContent
+		}
+	}
+	assert.Equal(t, map[string]any{"call_1": map[string]any{"count": float64(42)}, "call_2": nil}, structured)
+}
@@ -61,18 +61,20 @@ func (r *LocalRuntime) doCompact(ctx context.Context, sess *session.Session, a *
 		return
 	}
 
+	messages = r.prepareAttachments(ctx, messages, a.Name(), events)
 	prepared := compaction.BuildPrompt(messages, additionalPrompt)
 
 	result, err := runSummarization(ctx, a.Model(), prepared)
 	if err != nil {
 		slog.Error("Failed to generate session summary", "error", err)
-		events <- Error(err.Error())
+		events <- ModelError(err)
 		return
 	}
 	if result.Summary == "" {
 		return
 	}
 
+	inputTokensBefore := sess.InputTokens
 	sess.Messages = append(sess.Messages, session.Item{Summary: result.Summary, Cost: result.Cost})
 	sess.InputTokens = result.InputTokens
 	sess.OutputTokens = 0
@@ -81,4 +83,8 @@ func (r *LocalRuntime) doCompact(ctx context.Context, sess *session.Session, a *
 
 	slog.Debug("Generated session summary", "session_id", sess.ID, "summary_length", len(result.Summary), "compaction_cost", result.Cost)
 	events <- SessionSummary(sess.ID, result.Summary, a.Name())
+	r.addRecord(sess, &session.Record{
+		AgentName:  a.Name(),
+		Compaction: &session.CompactionRecord{InputTokensBefore: inputTokensBefore, InputTokensAfter: result.InputTokens},
+	}, events)
 }
@@ -0,0 +1,36 @@
+package runtime
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// SimulatedToolResult is the result of the tool calls simulated instead of
+// run, in sessions that simulate their tools.
+const SimulatedToolResult = "Simulated: this tool call was not run, this is a dry run reviewed by the user. " +
+	"Describe the outcome you expect from it in one or two sentences, then continue " +
+	"with the next steps of the task as if it had succeeded."
+
+// simulateTool answers a tool call that isn't read-only without running it,
+// asking the model to describe its expected outcome. The call is reported
+// with a ToolCallSimulated event, for the plan of actions of the run.
+func (r *LocalRuntime) simulateTool(sess *session.Session, toolCall tools.ToolCall, tool tools.Tool, events chan Event, a *agent.Agent) {
+	slog.Debug("Simulating tool call", "agent", a.Name(), "tool", toolCall.Function.Name, "session_id", sess.ID)
+
+	events <- ToolCall(toolCall, tool, a.Name())
+	events <- ToolCallSimulated(sess.ID, toolCall, tool, a.Name())
+	events <- ToolCallResponse(toolCall, tool, tools.ResultSuccess(SimulatedToolResult), SimulatedToolResult, a.Name())
+
+	toolResponseMsg := chat.Message{
+		Role:       chat.MessageRoleTool,
+		Content:    SimulatedToolResult,
+		ToolCallID: toolCall.ID,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	addAgentMessage(sess, a, &toolResponseMsg, events)
+}
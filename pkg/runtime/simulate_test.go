@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestSimulate(t *testing.T) {
+	var ran []string
+	handler := func(_ context.Context, tc tools.ToolCall) (*tools.ToolCallResult, error) {
+		ran = append(ran, tc.Function.Name)
+		return tools.ResultSuccess("done"), nil
+	}
+	readFile := tools.Tool{Name: "read_file", Annotations: tools.ToolAnnotations{ReadOnlyHint: true}, Handler: handler}
+	writeFile := tools.Tool{Name: "write_file", Handler: handler}
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().
+			AddToolCallName("call_1", "read_file").
+			AddToolCallArguments("call_1", `{"path":"main.go"}`).
+			AddToolCallName("call_2", "write_file").
+			AddToolCallArguments("call_2", `{"path":"main.go","content":"package main"}`).
+			Build(),
+		newStreamBuilder().AddContent("I would rewrite main.go.").AddStopWithUsage(1, 1).Build(),
+	}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithTools(readFile, writeFile))
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Fix main.go"), session.WithSimulate(true))
+	var simulated []string
+	for ev := range rt.RunStream(t.Context(), sess) {
+		switch e := ev.(type) {
+		case *ToolCallSimulatedEvent:
+			simulated = append(simulated, e.ToolCall.Function.Name)
+		case *ToolCallConfirmationEvent:
+			t.Fatal("simulated tool calls don't need approval")
+		}
+	}
+
+	assert.Equal(t, []string{"read_file"}, ran, "read-only tools still run")
+	assert.Equal(t, []string{"write_file"}, simulated)
+
+	results := map[string]string{}
+	for _, m := range sess.GetAllMessages() {
+		if m.Message.Role == chat.MessageRoleTool {
+			results[m.Message.ToolCallID] = m.Message.Content
+		}
+	}
+	assert.Equal(t, "done", results["call_1"])
+	assert.Equal(t, SimulatedToolResult, results["call_2"])
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/chat"
@@ -29,6 +32,45 @@ type streamResult struct {
 	ActualModel       string
 	Usage             *chat.Usage
 	RateLimit         *chat.RateLimit
+	Citations         []chat.Citation
+	ReasoningItems    []chat.ReasoningItem
+	// Ensemble is how an ensemble alloy chose the response.
+	Ensemble *chat.Ensemble
+	// ResponseID is the ID the provider gave to the response.
+	ResponseID string
+	// Latency is the wall-clock time of the model call, set by the caller.
+	Latency time.Duration
+	// RequestDump is the file --debug-llm dumped the model request to, set
+	// by the caller.
+	RequestDump string
+	// Withheld is set when a guardrail retries the answer: it's kept for the
+	// model but hidden from the user.
+	Withheld bool
+}
+
+// hasOutput reports whether anything of the response was streamed.
+func (s streamResult) hasOutput() bool {
+	return s.Content != "" || s.ReasoningContent != "" || len(s.Calls) > 0
+}
+
+// resumedBy returns the response made of s continued by next. Anthropic
+// drops the trailing whitespace of the message it continues from, so the
+// continuation already starts with it.
+func (s streamResult) resumedBy(next streamResult) streamResult {
+	prefix := strings.TrimRightFunc(s.Content, unicode.IsSpace)
+	if next.Content == "" && len(next.Calls) == 0 {
+		prefix = s.Content
+	}
+
+	citations := slices.Clone(s.Citations)
+	for _, c := range next.Citations {
+		c.ContentOffset += len(prefix)
+		citations = append(citations, c)
+	}
+
+	next.Content = prefix + next.Content
+	next.Citations = citations
+	return next
 }
 
 // handleStream reads a chat.MessageStream to completion, emitting streaming
@@ -46,6 +88,10 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 	var actualModel string
 	var messageUsage *chat.Usage
 	var messageRateLimit *chat.RateLimit
+	var citations []chat.Citation
+	var reasoningItems []chat.ReasoningItem
+	var ensemble *chat.Ensemble
+	var responseID string
 
 	toolCallIndex := make(map[string]int)   // toolCallID -> index in toolCalls slice
 	emittedPartial := make(map[string]bool) // toolCallID -> whether we've emitted a partial event
@@ -79,7 +125,22 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 			break
 		}
 		if err != nil {
-			return streamResult{Stopped: true}, fmt.Errorf("error receiving from stream: %w", err)
+			// Return what was streamed so far: the caller may resume from it
+			return streamResult{
+				Calls:            toolCalls,
+				Content:          fullContent.String(),
+				ReasoningContent: fullReasoningContent.String(),
+				Stopped:          true,
+				ActualModel:      actualModel,
+				Usage:            messageUsage,
+				Citations:        citations,
+				Ensemble:         ensemble,
+				ResponseID:       responseID,
+			}, fmt.Errorf("error receiving from stream: %w", err)
+		}
+
+		if responseID == "" {
+			responseID = response.ID
 		}
 
 		if response.Usage != nil {
@@ -102,6 +163,10 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 			thoughtSignature = choice.Delta.ThoughtSignature
 		}
 
+		if choice.Delta.Ensemble != nil {
+			ensemble = choice.Delta.Ensemble
+		}
+
 		// Capture the actual model from the stream response (useful for model routing)
 		if actualModel == "" && response.Model != "" {
 			actualModel = response.Model
@@ -119,6 +184,10 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 				ActualModel:       actualModel,
 				Usage:             messageUsage,
 				RateLimit:         messageRateLimit,
+				Citations:         citations,
+				ReasoningItems:    reasoningItems,
+				Ensemble:          ensemble,
+				ResponseID:        responseID,
 			}, nil
 		}
 
@@ -168,11 +237,20 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 			fullReasoningContent.WriteString(choice.Delta.ReasoningContent)
 		}
 
+		// Capture encrypted reasoning for the OpenAI Responses API
+		reasoningItems = append(reasoningItems, choice.Delta.ReasoningItems...)
+
 		// Capture thinking signature for Anthropic extended thinking
 		if choice.Delta.ThinkingSignature != "" {
 			thinkingSignature = choice.Delta.ThinkingSignature
 		}
 
+		// Citations refer to the content streamed so far
+		for _, c := range choice.Delta.Citations {
+			c.ContentOffset = fullContent.Len()
+			citations = append(citations, c)
+		}
+
 		if choice.Delta.Content != "" {
 			events <- AgentChoice(a.Name(), sess.ID, choice.Delta.Content)
 			fullContent.WriteString(choice.Delta.Content)
@@ -194,6 +272,10 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 		ActualModel:       actualModel,
 		Usage:             messageUsage,
 		RateLimit:         messageRateLimit,
+		Citations:         citations,
+		ReasoningItems:    reasoningItems,
+		Ensemble:          ensemble,
+		ResponseID:        responseID,
 	}, nil
 }
 
@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+const (
+	// defaultSubAgentTraceTokens is the size of the traces when the agent
+	// doesn't ask for one.
+	defaultSubAgentTraceTokens = 4000
+	// maxSubAgentTraceTokens caps the size of the traces, so that one trace
+	// doesn't fill the context of the agent.
+	maxSubAgentTraceTokens = 16000
+)
+
+// handleGetSubAgentTrace returns the transcript of a sub-session of the
+// session, capped to the number of tokens the agent asked for.
+func (r *LocalRuntime) handleGetSubAgentTrace(_ context.Context, sess *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+	var params builtin.GetSubAgentTraceArgs
+	if toolCall.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	var raw bool
+	switch params.Mode {
+	case "", builtin.SubAgentTraceModeSummary:
+	case builtin.SubAgentTraceModeRaw:
+		raw = true
+	default:
+		return tools.ResultError(fmt.Sprintf("invalid mode %q: must be %s or %s", params.Mode, builtin.SubAgentTraceModeSummary, builtin.SubAgentTraceModeRaw)), nil
+	}
+
+	maxTokens := params.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultSubAgentTraceTokens
+	}
+	maxTokens = min(maxTokens, maxSubAgentTraceTokens)
+
+	var sub *session.Session
+	if params.SessionID == "" {
+		subSessions := sess.SubSessions()
+		if len(subSessions) == 0 {
+			return tools.ResultError("No sub-agent has completed a task in this session"), nil
+		}
+		sub = subSessions[len(subSessions)-1]
+	} else {
+		found, ok := sess.FindSubSession(params.SessionID)
+		if !ok {
+			return tools.ResultError(fmt.Sprintf("No sub-session %q in this session", params.SessionID)), nil
+		}
+		sub = found
+	}
+
+	trace := capTrace(sub.Trace(raw), maxTokens*toolOutputCharsPerToken)
+	return tools.ResultSuccess(fmt.Sprintf("Trace of sub-session %s:\n\n%s", sub.ID, trace)), nil
+}
+
+// capTrace shortens a trace to maxChars, keeping its beginning, with the task,
+// and its end, with the conclusion.
+func capTrace(trace string, maxChars int) string {
+	if len(trace) <= maxChars {
+		return trace
+	}
+	headEnd, tailStart := headAndTail(trace, maxChars)
+	return fmt.Sprintf("%s\n\n[... %d characters omitted ...]\n\n%s", trace[:headEnd], tailStart-headEnd, trace[tailStart:])
+}
+
+// hasSubAgentTraceTool reports whether the agent can read the traces of its
+// sub-agents.
+func hasSubAgentTraceTool(a *agent.Agent) bool {
+	for _, ts := range a.ToolSets() {
+		if _, ok := tools.As[*builtin.SubAgentTraceTool](ts); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+func TestHandleGetSubAgentTrace(t *testing.T) {
+	rt, _ := newToolOutputTestRuntime(t)
+	sess := session.New()
+
+	getTrace := func(args string) *tools.ToolCallResult {
+		t.Helper()
+		res, err := rt.handleGetSubAgentTrace(t.Context(), sess, tools.ToolCall{
+			ID:       "call_trace",
+			Function: tools.FunctionCall{Name: builtin.ToolNameGetSubAgentTrace, Arguments: args},
+		}, nil)
+		require.NoError(t, err)
+		return res
+	}
+
+	assert.True(t, getTrace("").IsError, "no sub-agent completed a task yet")
+
+	first := session.New(session.WithSystemMessage("First task"))
+	first.AddMessage(session.NewAgentMessage("helper", &chat.Message{Role: chat.MessageRoleAssistant, Content: "First answer"}))
+	sess.AddSubSession(first)
+	last := session.New(session.WithSystemMessage("Last task"))
+	last.AddMessage(session.NewAgentMessage("helper", &chat.Message{Role: chat.MessageRoleAssistant, Content: strings.Repeat("long answer ", 2000)}))
+	sess.AddSubSession(last)
+
+	res := getTrace(`{"session_id":"` + first.ID + `"}`)
+	assert.False(t, res.IsError)
+	assert.Contains(t, res.Output, "First answer")
+
+	res = getTrace(`{"max_tokens":100}`)
+	assert.Contains(t, res.Output, "Trace of sub-session "+last.ID)
+	assert.Contains(t, res.Output, "Last task")
+	assert.Contains(t, res.Output, "characters omitted")
+	assert.Less(t, len(res.Output), 600)
+
+	assert.True(t, getTrace(`{"mode":"verbose"}`).IsError)
+	assert.True(t, getTrace(`{"session_id":"unknown"}`).IsError)
+}
@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Limits of the budgets of delegated tasks.
+const (
+	taskBudgetMaxTurns = "max_turns"
+	taskBudgetMaxCost  = "max_cost"
+	taskBudgetMaxTime  = "max_time"
+)
+
+// errTaskTimeBudget is the cause of the cancellation of the tasks that run
+// for longer than their max_time.
+var errTaskTimeBudget = errors.New("task time budget exceeded")
+
+// exceededTaskBudget returns the limit of the budget of the session that its
+// run reached after the given number of turns, and why, or an empty limit.
+// max_time is enforced by the context of the run, see withTaskTimeBudget.
+func exceededTaskBudget(sess *session.Session, turns int) (limit, reason string) {
+	budget := sess.TaskBudget
+	if budget == nil {
+		return "", ""
+	}
+	if budget.MaxTurns > 0 && turns >= budget.MaxTurns {
+		return taskBudgetMaxTurns, fmt.Sprintf("the task used its %d turns", budget.MaxTurns)
+	}
+	if cost := sess.TotalCost(); budget.MaxCost > 0 && cost >= budget.MaxCost {
+		return taskBudgetMaxCost, fmt.Sprintf("the task cost $%.4f, more than its budget of $%.4f", cost, budget.MaxCost)
+	}
+	return "", ""
+}
+
+// withTaskTimeBudget returns the context to run a sub-session with, cancelled
+// with errTaskTimeBudget when the session runs for longer than its max_time.
+func withTaskTimeBudget(ctx context.Context, sess *session.Session) (context.Context, context.CancelFunc) {
+	if sess.TaskBudget == nil || sess.TaskBudget.MaxTime.Duration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, sess.TaskBudget.MaxTime.Duration, errTaskTimeBudget)
+}
+
+// taskTimeBudgetExceeded reports whether ctx was cancelled because the task
+// ran for longer than its max_time.
+func taskTimeBudgetExceeded(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errTaskTimeBudget)
+}
+
+// taskBudgetFailure is the result of a task that stopped at a limit of its
+// budget, for the agent that delegated it to decide what to do next.
+type taskBudgetFailure struct {
+	Error         string `json:"error"`
+	Agent         string `json:"agent"`
+	Limit         string `json:"limit"`
+	Reason        string `json:"reason"`
+	SessionID     string `json:"session_id"`
+	PartialResult string `json:"partial_result,omitempty"`
+}
+
+// taskBudgetFailureResult returns the tool result of a task that stopped at
+// a limit of its budget, with what the sub-agent last said.
+func taskBudgetFailureResult(child *session.Session, e *TaskBudgetExceededEvent) *tools.ToolCallResult {
+	buf, err := json.MarshalIndent(taskBudgetFailure{
+		Error:         "budget_exceeded",
+		Agent:         e.AgentName,
+		Limit:         e.Limit,
+		Reason:        e.Reason,
+		SessionID:     child.ID,
+		PartialResult: child.GetLastAssistantMessageContent(),
+	}, "", "  ")
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("The task of %s stopped: %s", e.AgentName, e.Reason))
+	}
+	return tools.ResultError(string(buf))
+}
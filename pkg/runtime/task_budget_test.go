@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func transferWithBudget(t *testing.T, prov provider.Provider, budget latest.TaskBudget) (*tools.ToolCallResult, []Event) {
+	t.Helper()
+
+	worker := agent.New("worker", "Works", agent.WithModel(prov))
+	root := agent.New("root", "Root agent", agent.WithModel(&mockProvider{id: "test/mock-model", stream: &mockStream{}}))
+	agent.WithSubAgents(worker)(root)
+
+	tm := team.New(team.WithAgents(root, worker), team.WithTaskBudgets(map[string]latest.TaskBudget{"worker": budget}))
+	rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Do the work"), session.WithToolsApproved(true))
+	evts := make(chan Event)
+	var events []Event
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range evts {
+			events = append(events, ev)
+		}
+	}()
+
+	res, err := rt.handleTaskTransfer(t.Context(), sess, tools.ToolCall{
+		ID:       "call_1",
+		Function: tools.FunctionCall{Name: "transfer_task", Arguments: `{"agent":"worker","task":"Do the work"}`},
+	}, evts)
+	require.NoError(t, err)
+	close(evts)
+	<-done
+
+	assert.Len(t, sess.SubSessions(), 1, "the partial work is kept")
+	return res, events
+}
+
+func TestTransferTask_MaxTurns(t *testing.T) {
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("Let me look").AddToolCallName("call_2", "lookup").AddToolCallArguments("call_2", `{}`).Build(),
+		newStreamBuilder().AddContent("Never reached").AddStopWithUsage(1, 1).Build(),
+	}}
+
+	res, events := transferWithBudget(t, prov, latest.TaskBudget{MaxTurns: 1})
+
+	require.True(t, res.IsError)
+	var failure taskBudgetFailure
+	require.NoError(t, json.Unmarshal([]byte(res.Output), &failure))
+	assert.Equal(t, "budget_exceeded", failure.Error)
+	assert.Equal(t, "worker", failure.Agent)
+	assert.Equal(t, taskBudgetMaxTurns, failure.Limit)
+	assert.Equal(t, "Let me look", failure.PartialResult)
+	assert.True(t, hasEventType(t, events, &TaskBudgetExceededEvent{}))
+	assert.False(t, hasEventType(t, events, &ErrorEvent{}))
+}
+
+func TestTransferTask_MaxTime(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		prov := &hangingProvider{stream: newStreamBuilder().AddContent("Too late").AddStopWithUsage(1, 1).Build()}
+
+		res, events := transferWithBudget(t, prov, latest.TaskBudget{MaxTime: latest.Duration{Duration: time.Minute}})
+
+		require.True(t, res.IsError)
+		var failure taskBudgetFailure
+		require.NoError(t, json.Unmarshal([]byte(res.Output), &failure))
+		assert.Equal(t, taskBudgetMaxTime, failure.Limit)
+		assert.Equal(t, "the task ran for more than 1m0s", failure.Reason)
+		assert.False(t, hasEventType(t, events, &ErrorEvent{}), "the run isn't killed")
+	})
+}
+
+func TestExceededTaskBudget_MaxCost(t *testing.T) {
+	sess := session.New(session.WithTaskBudget(&latest.TaskBudget{MaxCost: 0.5}))
+	sess.AddMessage(session.NewAgentMessage("worker", &chat.Message{Role: chat.MessageRoleAssistant, Content: "Thinking", Cost: 0.2}))
+
+	limit, _ := exceededTaskBudget(sess, 1)
+	assert.Empty(t, limit)
+
+	sub := session.New()
+	sub.AddMessage(session.NewAgentMessage("helper", &chat.Message{Role: chat.MessageRoleAssistant, Content: "Done", Cost: 0.4}))
+	sess.AddSubSession(sub)
+
+	limit, reason := exceededTaskBudget(sess, 2)
+	assert.Equal(t, taskBudgetMaxCost, limit)
+	assert.Contains(t, reason, "$0.6000")
+
+	limit, _ = exceededTaskBudget(session.New(), 100)
+	assert.Empty(t, limit, "sessions without a budget are unlimited")
+}
@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/guardrails"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// taskOutputGuardrailName names the guardrail checking the results of
+// delegated tasks against the task output schema.
+const taskOutputGuardrailName = "task_output"
+
+// outputSchemaGuardrail returns the guardrail checking the final answer of a
+// session against its output schema, or nil when the session has none. The
+// answers that don't follow the schema are retried, then blocked.
+func outputSchemaGuardrail(sess *session.Session) (*guardrails.Guardrail, error) {
+	if sess.OutputSchema == nil {
+		return nil, nil
+	}
+	return guardrails.New(0, latest.GuardrailConfig{
+		Name:       taskOutputGuardrailName,
+		JSONSchema: sess.OutputSchema.Schema,
+		Action:     latest.GuardrailActionRetry,
+		Message:    "No result following the task output schema was produced.",
+	}, nil)
+}
+
+// taskOutputInstructions tells a sub-agent the schema its final answer must
+// follow, for the models that don't support structured outputs.
+func taskOutputInstructions(schema *latest.StructuredOutput) string {
+	buf, err := json.MarshalIndent(schema.Schema, "", "  ")
+	if err != nil {
+		return ""
+	}
+	msg := "\n\nYour final answer must be a JSON document, and nothing else, following this JSON Schema:"
+	if schema.Description != "" {
+		msg += "\n" + schema.Description
+	}
+	return msg + fmt.Sprintf("\n\n<output_schema>\n%s\n</output_schema>", buf)
+}
+
+// checkTaskOutput validates the result of a delegated task against the
+// schema of the session. It returns the JSON document, without the markdown
+// code block it might be wrapped in, or why the result doesn't follow the
+// schema.
+func checkTaskOutput(ctx context.Context, sess *session.Session, result string) (string, string) {
+	g, err := outputSchemaGuardrail(sess)
+	if err != nil {
+		return "", err.Error()
+	}
+	if reason := g.Check(ctx, result, nil); reason != "" {
+		return "", reason
+	}
+	return guardrails.StripCodeFence(result), ""
+}
@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+var reviewSchema = &latest.StructuredOutput{
+	Name: "review",
+	Schema: map[string]any{
+		"type":     "object",
+		"required": []any{"approved"},
+		"properties": map[string]any{
+			"approved": map[string]any{"type": "boolean"},
+		},
+	},
+}
+
+func transferWithTaskOutput(t *testing.T, answers ...string) (*tools.ToolCallResult, *session.Session) {
+	t.Helper()
+
+	var streams []chat.MessageStream
+	for _, answer := range answers {
+		streams = append(streams, newStreamBuilder().AddContent(answer).AddStopWithUsage(1, 1).Build())
+	}
+	reviewer := agent.New("reviewer", "Reviews code", agent.WithModel(&queueProvider{id: "test/mock-model", streams: streams}))
+	root := agent.New("root", "Root agent", agent.WithModel(&mockProvider{id: "test/mock-model", stream: &mockStream{}}), agent.WithTaskOutput(reviewSchema))
+	agent.WithSubAgents(reviewer)(root)
+
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root, reviewer)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Review my change"), session.WithToolsApproved(true))
+	evts := make(chan Event)
+	go func() {
+		for range evts {
+		}
+	}()
+	defer close(evts)
+
+	res, err := rt.handleTaskTransfer(t.Context(), sess, tools.ToolCall{
+		ID:       "call_1",
+		Function: tools.FunctionCall{Name: "transfer_task", Arguments: `{"agent":"reviewer","task":"Review the change"}`},
+	}, evts)
+	require.NoError(t, err)
+
+	subSessions := sess.SubSessions()
+	require.Len(t, subSessions, 1)
+	return res, subSessions[0]
+}
+
+func TestTransferTask_TaskOutput(t *testing.T) {
+	res, child := transferWithTaskOutput(t, "Looks good to me", "```json\n{\"approved\": true}\n```")
+
+	assert.False(t, res.IsError)
+	assert.JSONEq(t, `{"approved": true}`, res.Output)
+	assert.Same(t, reviewSchema, child.OutputSchema)
+	assert.Contains(t, child.Messages[0].Message.Message.Content, "<output_schema>")
+}
+
+func TestTransferTask_TaskOutputNotFollowed(t *testing.T) {
+	res, _ := transferWithTaskOutput(t, "Looks good", `{"approved": "yes"}`, "Approved")
+
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Output, "The result of reviewer doesn't follow the task output schema")
+}
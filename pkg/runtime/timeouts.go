@@ -0,0 +1,133 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// streamIdleError is returned when a model stream produced no data for longer
+// than the agent's stream idle timeout. It is a timeout net.Error, so the
+// request is retried like any other timeout.
+type streamIdleError struct {
+	timeout time.Duration
+}
+
+func (e *streamIdleError) Error() string {
+	return fmt.Sprintf("model stream idle timeout: no data received for %s", e.timeout)
+}
+
+func (e *streamIdleError) Timeout() bool   { return true }
+func (e *streamIdleError) Temporary() bool { return true }
+
+// idleWatchdog cancels a model request when its stream goes quiet for too long.
+type idleWatchdog struct {
+	timeout time.Duration
+	timer   *time.Timer
+	fired   atomic.Bool
+}
+
+// newIdleWatchdog starts a watchdog calling cancel after timeout, unless
+// reset in the meantime. A zero timeout disables it: nil is returned, and
+// all methods are no-ops on a nil watchdog.
+func newIdleWatchdog(timeout time.Duration, cancel context.CancelFunc) *idleWatchdog {
+	if timeout <= 0 {
+		return nil
+	}
+
+	w := &idleWatchdog{timeout: timeout}
+	w.timer = time.AfterFunc(timeout, func() {
+		w.fired.Store(true)
+		cancel()
+	})
+	return w
+}
+
+func (w *idleWatchdog) reset() {
+	if w != nil {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+func (w *idleWatchdog) stop() {
+	if w != nil {
+		w.timer.Stop()
+	}
+}
+
+// wrap replaces the error caused by the watchdog cancelling the request.
+func (w *idleWatchdog) wrap(err error) error {
+	if w == nil || err == nil || !w.fired.Load() {
+		return err
+	}
+	return &streamIdleError{timeout: w.timeout}
+}
+
+// stream returns s watched by the watchdog. Only the time spent waiting for
+// chunks counts, not the time spent handling them.
+func (w *idleWatchdog) stream(s chat.MessageStream) chat.MessageStream {
+	if w == nil {
+		return s
+	}
+	return &watchedStream{MessageStream: s, watchdog: w}
+}
+
+type watchedStream struct {
+	chat.MessageStream
+	watchdog *idleWatchdog
+}
+
+func (s *watchedStream) Recv() (chat.MessageStreamResponse, error) {
+	s.watchdog.reset()
+	resp, err := s.MessageStream.Recv()
+	s.watchdog.stop()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return resp, s.watchdog.wrap(err)
+	}
+	return resp, err
+}
+
+// runToolWithTimeout runs a tool handler for at most timeout. The handler's
+// context is cancelled on timeout, but the call returns right away even if
+// the handler ignores it, e.g. a hung MCP server. A zero timeout disables it.
+func runToolWithTimeout(ctx context.Context, timeout time.Duration, handler tools.ToolHandler, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+	if timeout <= 0 {
+		return handler(ctx, toolCall)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		res *tools.ToolCallResult
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := handler(ctx, toolCall)
+		done <- result{res, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return toolTimeoutResult(timeout), nil
+		}
+		return r.res, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return toolTimeoutResult(timeout), nil
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func toolTimeoutResult(timeout time.Duration) *tools.ToolCallResult {
+	return tools.ResultError(fmt.Sprintf("The tool call timed out after %s and was cancelled.", timeout))
+}
@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// hangingStream streams its responses, then blocks until its request is
+// cancelled.
+type hangingStream struct {
+	mockStream
+	ctx context.Context
+}
+
+func (s *hangingStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.idx < len(s.responses) {
+		return s.mockStream.Recv()
+	}
+	<-s.ctx.Done()
+	return chat.MessageStreamResponse{}, s.ctx.Err()
+}
+
+// hangingProvider hangs on its first request and answers the next ones.
+type hangingProvider struct {
+	calls  int
+	stream chat.MessageStream
+}
+
+func (p *hangingProvider) ID() string { return "test/hanging" }
+func (p *hangingProvider) CreateChatCompletionStream(ctx context.Context, _ []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	p.calls++
+	if p.calls == 1 {
+		return &hangingStream{mockStream: *newStreamBuilder().AddContent("Hel").Build(), ctx: ctx}, nil
+	}
+	return p.stream, nil
+}
+func (p *hangingProvider) BaseConfig() base.Config { return base.Config{} }
+
+func TestStreamIdleTimeoutRetries(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		prov := &hangingProvider{stream: newStreamBuilder().AddContent("Hello").AddStopWithUsage(3, 2).Build()}
+		root := agent.New("root", "test", agent.WithModel(prov), agent.WithStreamIdleTimeout(30*time.Second))
+		tm := team.New(team.WithAgents(root))
+		rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+		require.NoError(t, err)
+
+		sess := session.New(session.WithUserMessage("Hi"))
+		sess.Title = "Idle stream"
+
+		start := time.Now()
+		var retries []*ModelRetryEvent
+		for ev := range rt.RunStream(t.Context(), sess) {
+			if retry, ok := ev.(*ModelRetryEvent); ok {
+				retries = append(retries, retry)
+			}
+		}
+
+		require.Len(t, retries, 1)
+		assert.Contains(t, retries[0].Reason, "idle timeout")
+		assert.True(t, retries[0].Discarded)
+		assert.Equal(t, 2, prov.calls)
+		assert.Equal(t, "Hello", sess.GetLastAssistantMessageContent())
+		assert.Less(t, time.Since(start), time.Minute)
+	})
+}
+
+func TestRunToolWithTimeout(t *testing.T) {
+	call := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "slow"}}
+
+	t.Run("handler ignoring its context", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+
+			res, err := runToolWithTimeout(t.Context(), time.Minute, func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+				<-release
+				return tools.ResultSuccess("too late"), nil
+			}, call)
+			require.NoError(t, err)
+			assert.True(t, res.IsError)
+			assert.Contains(t, res.Output, "timed out after 1m0s")
+		})
+	})
+
+	t.Run("handler honouring its context", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			res, err := runToolWithTimeout(t.Context(), time.Minute, func(ctx context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}, call)
+			require.NoError(t, err)
+			assert.True(t, res.IsError)
+		})
+	})
+
+	t.Run("fast handler", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			res, err := runToolWithTimeout(t.Context(), time.Minute, func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+				return tools.ResultSuccess("done"), nil
+			}, call)
+			require.NoError(t, err)
+			assert.Equal(t, "done", res.Output)
+		})
+	})
+
+	t.Run("cancelled by the user", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(t.Context())
+			cancel()
+
+			_, err := runToolWithTimeout(ctx, time.Minute, func(ctx context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}, call)
+			require.ErrorIs(t, err, context.Canceled)
+		})
+	})
+}
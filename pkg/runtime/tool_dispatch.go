@@ -35,6 +35,8 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 		agentToolMap[t.Name] = t
 	}
 
+	_, guard := r.delegationGuard(ctx)
+
 	for _, toolCall := range calls {
 		callCtx, callSpan := r.startSpan(ctx, "runtime.tool.call", trace.WithAttributes(
 			attribute.String("tool.name", toolCall.Function.Name),
@@ -60,12 +62,29 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 			continue
 		}
 
+		// Once a delegation loop stopped the turn, the remaining calls are
+		// only answered so that the conversation stays well-formed.
+		if err := guard.stopped(); err != nil {
+			r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, err.Error())
+			callSpan.SetStatus(codes.Error, "run stopped")
+			callSpan.End()
+			continue
+		}
+
 		// Pick the handler: runtime-managed tools (transfer_task, handoff)
 		// have dedicated handlers; everything else goes through the toolset.
 		var runTool func()
-		if handler, exists := r.toolMap[toolCall.Function.Name]; exists {
+		handler, exists := r.toolMap[toolCall.Function.Name]
+		switch {
+		case exists:
 			runTool = func() { r.runAgentTool(callCtx, handler, sess, toolCall, tool, events, a) }
-		} else {
+		case sess.Simulate && !tool.Annotations.ReadOnlyHint:
+			// Nothing runs, so there is nothing to approve.
+			r.simulateTool(sess, toolCall, tool, events, a)
+			callSpan.SetStatus(codes.Ok, "tool call simulated")
+			callSpan.End()
+			continue
+		default:
 			runTool = func() { r.runTool(callCtx, tool, toolCall, events, sess, a) }
 		}
 
@@ -87,11 +106,14 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 //
 // The approval flow considers (in order):
 //
-//  1. sess.ToolsApproved (--yolo flag) - auto-approve everything, takes precedence
-//  2. Session-level permissions (if configured) - pattern-based Allow/Ask/Deny rules
-//  3. Team-level permissions config - checked second
-//  4. Read-only hint - auto-approve
-//  5. Default: ask for user confirmation
+//  1. tool.AlwaysAsk - ask for user confirmation, whatever the other settings
+//  2. sess.ToolsApproved (--yolo flag) - auto-approve everything else
+//  3. Session-level permissions (if configured) - pattern-based Allow/Ask/Deny rules
+//  4. Team-level permissions config - checked second
+//  5. Project permissions - the approvals remembered in .cagent/project.yaml,
+//     checked last so they never override the configuration's rules
+//  6. Read-only hint - auto-approve
+//  7. Default: ask for user confirmation
 func (r *LocalRuntime) executeWithApproval(
 	ctx context.Context,
 	sess *session.Session,
@@ -103,7 +125,14 @@ func (r *LocalRuntime) executeWithApproval(
 ) (canceled bool) {
 	toolName := toolCall.Function.Name
 
-	// --yolo flag takes absolute precedence: auto-approve everything.
+	// Tools that must always be confirmed, like the ones editing the agent's
+	// own configuration, are never auto-approved.
+	if tool.AlwaysAsk {
+		slog.Debug("Tool always requires confirmation", "tool", toolName, "session_id", sess.ID)
+		return r.askUserForConfirmation(ctx, sess, toolCall, tool, events, a, runTool)
+	}
+
+	// --yolo flag takes precedence over everything else: auto-approve.
 	if sess.ToolsApproved {
 		slog.Debug("Tool auto-approved by --yolo flag", "tool", toolName, "session_id", sess.ID)
 		runTool()
@@ -119,7 +148,7 @@ func (r *LocalRuntime) executeWithApproval(
 		}
 	}
 
-	// Collect permission checkers in priority order (session first, then team)
+	// Collect permission checkers in priority order (session, team, then project)
 	checkers := r.permissionCheckers(sess)
 
 	for _, pc := range checkers {
@@ -175,11 +204,20 @@ func (r *LocalRuntime) permissionCheckers(sess *session.Session) []permissionChe
 			source:  "permissions configuration",
 		})
 	}
+	if r.permissionsStore != nil {
+		if sc := r.permissionsStore.PermissionsChecker(); sc != nil {
+			checkers = append(checkers, permissionChecker{
+				checker: sc,
+				source:  "project permissions",
+			})
+		}
+	}
 	return checkers
 }
 
 // askUserForConfirmation sends a confirmation event and waits for user response.
-// This is only called when --yolo is not active and no permission rule auto-approved the tool.
+// This is only called when the tool must always be confirmed, or when --yolo is not active and
+// no permission rule auto-approved the tool.
 func (r *LocalRuntime) askUserForConfirmation(
 	ctx context.Context,
 	sess *session.Session,
@@ -197,15 +235,23 @@ func (r *LocalRuntime) askUserForConfirmation(
 
 	select {
 	case req := <-r.resumeChan:
+		approval := &session.ToolApprovalRecord{
+			ToolCallID: toolCall.ID,
+			ToolName:   toolName,
+			Arguments:  toolCall.Function.Arguments,
+			Decision:   string(req.Type),
+		}
 		switch req.Type {
 		case ResumeTypeApprove:
 			slog.Debug("Resume signal received, approving tool", "tool", toolName, "session_id", sess.ID)
+			r.addRecord(sess, &session.Record{AgentName: a.Name(), User: req.User, ToolApproval: approval}, events)
 			runTool()
 		case ResumeTypeApproveSession:
 			slog.Debug("Resume signal received, approving session", "tool", toolName, "session_id", sess.ID)
 			sess.ToolsApproved = true
+			r.addRecord(sess, &session.Record{AgentName: a.Name(), User: req.User, ToolApproval: approval}, events)
 			runTool()
-		case ResumeTypeApproveTool:
+		case ResumeTypeApproveTool, ResumeTypeApproveAlways:
 			// Add the tool to session's allow list for future auto-approval
 			approvedTool := req.ToolName
 			if approvedTool == "" {
@@ -218,6 +264,11 @@ func (r *LocalRuntime) askUserForConfirmation(
 				sess.Permissions.Allow = append(sess.Permissions.Allow, approvedTool)
 			}
 			slog.Debug("Resume signal received, approving tool permanently", "tool", approvedTool, "session_id", sess.ID)
+			if req.Type == ResumeTypeApproveAlways {
+				r.saveAllowedTool(approvedTool)
+			}
+			approval.Pattern = approvedTool
+			r.addRecord(sess, &session.Record{AgentName: a.Name(), User: req.User, ToolApproval: approval}, events)
 			runTool()
 		case ResumeTypeReject:
 			slog.Debug("Resume signal received, rejecting tool", "tool", toolName, "session_id", sess.ID, "reason", req.Reason)
@@ -225,6 +276,8 @@ func (r *LocalRuntime) askUserForConfirmation(
 			if strings.TrimSpace(req.Reason) != "" {
 				rejectMsg += " Reason: " + strings.TrimSpace(req.Reason)
 			}
+			approval.Reason = strings.TrimSpace(req.Reason)
+			r.addRecord(sess, &session.Record{AgentName: a.Name(), User: req.User, ToolApproval: approval}, events)
 			r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, rejectMsg)
 		}
 		return false
@@ -257,7 +310,7 @@ func (r *LocalRuntime) executeToolWithHandler(
 
 	events <- ToolCall(toolCall, tool, a.Name())
 
-	res, duration, err := execute(ctx)
+	res, duration, err := execute(tools.WithSessionID(ctx, sess.ID))
 
 	telemetry.RecordToolCall(ctx, toolCall.Function.Name, sess.ID, a.Name(), duration, err)
 
@@ -277,31 +330,57 @@ func (r *LocalRuntime) executeToolWithHandler(
 		slog.Debug("Tool call completed", "tool", toolCall.Function.Name, "output_length", len(res.Output))
 	}
 
+	if res.StructuredContent != nil && tool.OutputSchema != nil {
+		if err := tools.ValidateOutput(tool.OutputSchema, res.StructuredContent); err != nil {
+			slog.Debug("Dropping structured output not matching the output schema of the tool", "tool", toolCall.Function.Name, "error", err)
+			res.StructuredContent = nil
+		}
+	}
+
 	events <- ToolCallResponse(toolCall, tool, res, res.Output, a.Name())
 
+	r.registerArtifacts(sess, a, toolCall, res.Artifacts, events)
+
 	// Ensure tool response content is not empty for API compatibility
 	content := res.Output
 	if strings.TrimSpace(content) == "" {
 		content = "(no output)"
 	}
+	images, searchResults := res.Images, res.SearchResults
+	content, searchResults, withheld := r.filterToolResult(ctx, sess, a, toolCall, content, searchResults, events)
+	if withheld {
+		images = nil
+	}
+	content = r.processToolOutput(ctx, sess, a, toolCall, content)
 
 	toolResponseMsg := chat.Message{
-		Role:       chat.MessageRoleTool,
-		Content:    content,
-		ToolCallID: toolCall.ID,
-		IsError:    res.IsError,
-		CreatedAt:  time.Now().Format(time.RFC3339),
+		Role:              chat.MessageRoleTool,
+		Content:           content,
+		ToolCallID:        toolCall.ID,
+		IsError:           res.IsError,
+		CreatedAt:         time.Now().Format(time.RFC3339),
+		StructuredContent: res.StructuredContent,
 	}
 
-	// If the tool result contains images, attach them as MultiContent
-	if len(res.Images) > 0 {
+	// If the tool result contains images or search results, attach them as MultiContent
+	if len(images) > 0 || len(searchResults) > 0 {
 		multiContent := []chat.MessagePart{
 			{
 				Type: chat.MessagePartTypeText,
 				Text: content,
 			},
 		}
-		for _, img := range res.Images {
+		for _, sr := range searchResults {
+			multiContent = append(multiContent, chat.MessagePart{
+				Type: chat.MessagePartTypeSearchResult,
+				SearchResult: &chat.MessageSearchResult{
+					Source:  sr.Source,
+					Title:   sr.Title,
+					Content: sr.Content,
+				},
+			})
+		}
+		for _, img := range images {
 			multiContent = append(multiContent, chat.MessagePart{
 				Type: chat.MessagePartTypeImageURL,
 				ImageURL: &chat.MessageImageURL{
@@ -331,7 +410,7 @@ func (r *LocalRuntime) runTool(ctx context.Context, tool tools.Tool, toolCall to
 
 	r.executeToolWithHandler(ctx, toolCall, tool, events, sess, a, "runtime.tool.handler",
 		func(ctx context.Context) (*tools.ToolCallResult, time.Duration, error) {
-			res, err := tool.Handler(ctx, toolCall)
+			res, err := runToolWithTimeout(ctx, a.ToolTimeout(), tool.Handler, toolCall)
 			return res, 0, err
 		})
 
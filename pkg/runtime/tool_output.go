@@ -0,0 +1,227 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+const (
+	// toolOutputCharsPerToken estimates the size of tool outputs in tokens,
+	// like compaction.EstimateMessageTokens.
+	toolOutputCharsPerToken = 4
+
+	// maxStoredToolOutputs bounds the number of long outputs kept for
+	// get_more_output. The oldest ones are dropped first.
+	maxStoredToolOutputs = 32
+
+	// maxSummarizedLength bounds the length, in characters, of the tool
+	// outputs sent to the summary model. The middle of longer outputs is
+	// left out.
+	maxSummarizedLength = 400_000
+
+	toolOutputSummaryTimeout = 2 * time.Minute
+
+	toolOutputSummaryPrompt = `You summarize the output of a tool called by an AI agent, because it is too long to be given to the agent as-is.
+
+Keep everything the agent may need to act on the output: errors and warnings, file paths, line numbers, identifiers, numbers, URLs and the overall outcome. Drop repetitive lines and noise. Never invent anything. Return only the summary.`
+)
+
+// toolOutputStore keeps the long tool outputs that were truncated or
+// summarized, so that agents can page through them with get_more_output.
+type toolOutputStore struct {
+	mu      sync.Mutex
+	outputs map[string]string
+	order   []string
+}
+
+func (s *toolOutputStore) add(key, output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.outputs == nil {
+		s.outputs = make(map[string]string)
+	}
+	if _, exists := s.outputs[key]; !exists {
+		s.order = append(s.order, key)
+	}
+	s.outputs[key] = output
+
+	for len(s.order) > maxStoredToolOutputs {
+		delete(s.outputs, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (s *toolOutputStore) get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	output, ok := s.outputs[key]
+	return output, ok
+}
+
+func toolOutputKey(sessionID, toolCallID string) string {
+	return sessionID + "/" + toolCallID
+}
+
+// processToolOutput caps the output of a tool call added to the conversation
+// at the agent's maximum. Longer outputs are summarized if the agent has a
+// summary model, or truncated, keeping their beginning and their end. The
+// original output is kept so that the agent can page through it.
+func (r *LocalRuntime) processToolOutput(ctx context.Context, sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, output string) string {
+	maxChars := a.MaxToolOutputTokens() * toolOutputCharsPerToken
+	if maxChars <= 0 || len(output) <= maxChars || toolCall.Function.Name == builtin.ToolNameGetMoreOutput {
+		return output
+	}
+
+	r.toolOutputs.add(toolOutputKey(sess.ID, toolCall.ID), output)
+
+	if model := a.ToolOutputSummaryModel(); model != nil {
+		summary, err := summarizeToolOutput(ctx, model, toolCall.Function.Name, output)
+		switch {
+		case err != nil:
+			slog.Warn("Failed to summarize tool output, truncating it", "tool", toolCall.Function.Name, "model", model.ID(), "error", err)
+		case len(summary) > maxChars:
+			slog.Warn("Tool output summary is too long, truncating the output", "tool", toolCall.Function.Name, "model", model.ID(), "summary_chars", len(summary), "max_chars", maxChars)
+		default:
+			return fmt.Sprintf("%s\n\n[Summary of a %d-character output. Call %s with cursor %q to read the original output.]",
+				summary, len(output), builtin.ToolNameGetMoreOutput, formatToolOutputCursor(toolCall.ID, 0))
+		}
+	}
+
+	return truncateToolOutput(output, maxChars, toolCall.ID)
+}
+
+// truncateToolOutput keeps the beginning and the end of output, for a total
+// of at most maxChars characters.
+func truncateToolOutput(output string, maxChars int, toolCallID string) string {
+	headEnd, tailStart := headAndTail(output, maxChars)
+	return fmt.Sprintf("%s\n\n[... %d characters omitted. Call %s with cursor %q to read them ...]\n\n%s",
+		output[:headEnd], tailStart-headEnd, builtin.ToolNameGetMoreOutput, formatToolOutputCursor(toolCallID, headEnd), output[tailStart:])
+}
+
+// headAndTail returns the end of the beginning and the start of the end of
+// output that add up to maxChars characters.
+func headAndTail(output string, maxChars int) (headEnd, tailStart int) {
+	return runeStart(output, maxChars/2), runeStart(output, len(output)-maxChars/2)
+}
+
+// handleGetMoreOutput returns a page of a long tool output, starting at the
+// offset of the cursor.
+func (r *LocalRuntime) handleGetMoreOutput(_ context.Context, sess *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+	var args builtin.GetMoreOutputArgs
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	toolCallID, offset, err := parseToolOutputCursor(args.Cursor)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+	output, ok := r.toolOutputs.get(toolOutputKey(sess.ID, toolCallID))
+	if !ok {
+		return tools.ResultError(fmt.Sprintf("The output of cursor %q is no longer available. Call the tool again instead.", args.Cursor)), nil
+	}
+	if offset > len(output) {
+		return tools.ResultError(fmt.Sprintf("Cursor %q is past the end of the output (%d characters).", args.Cursor, len(output))), nil
+	}
+
+	end := len(output)
+	if maxChars := r.resolveSessionAgent(sess).MaxToolOutputTokens() * toolOutputCharsPerToken; maxChars > 0 && offset+maxChars < end {
+		end = runeStart(output, offset+maxChars)
+	}
+
+	if end == len(output) {
+		return tools.ResultSuccess(fmt.Sprintf("%s\n\n[End of the output: characters %d to %d of %d.]", output[offset:end], offset, end, len(output))), nil
+	}
+	return tools.ResultSuccess(fmt.Sprintf("%s\n\n[Characters %d to %d of %d. Call %s with cursor %q for the next page.]",
+		output[offset:end], offset, end, len(output), builtin.ToolNameGetMoreOutput, formatToolOutputCursor(toolCallID, end))), nil
+}
+
+func formatToolOutputCursor(toolCallID string, offset int) string {
+	return toolCallID + ":" + strconv.Itoa(offset)
+}
+
+func parseToolOutputCursor(cursor string) (toolCallID string, offset int, err error) {
+	i := strings.LastIndexByte(cursor, ':')
+	if i < 0 {
+		return "", 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	offset, err = strconv.Atoi(cursor[i+1:])
+	if err != nil || offset < 0 {
+		return "", 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return cursor[:i], offset, nil
+}
+
+// runeStart returns the start of the rune at byte offset i of s, so that s
+// is never cut in the middle of a character.
+func runeStart(s string, i int) int {
+	i = max(0, min(i, len(s)))
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+// summarizeToolOutput summarizes the output of a tool with a one-shot call
+// to model.
+func summarizeToolOutput(ctx context.Context, model provider.Provider, toolName, output string) (string, error) {
+	if len(output) > maxSummarizedLength {
+		headEnd, tailStart := headAndTail(output, maxSummarizedLength)
+		output = fmt.Sprintf("%s\n\n[... %d characters omitted ...]\n\n%s", output[:headEnd], tailStart-headEnd, output[tailStart:])
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, toolOutputSummaryTimeout)
+	defer cancel()
+
+	model = provider.CloneWithOptions(ctx, model,
+		options.WithStructuredOutput(nil),
+		options.WithThinking(false),
+	)
+	stream, err := model.CreateChatCompletionStream(ctx, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: toolOutputSummaryPrompt},
+		{Role: chat.MessageRoleUser, Content: fmt.Sprintf("Output of the %s tool:\n\n%s", toolName, output)},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var out strings.Builder
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(response.Choices) > 0 {
+			out.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+
+	summary := strings.TrimSpace(out.String())
+	if summary == "" {
+		return "", fmt.Errorf("empty summary from model %q", model.ID())
+	}
+	return summary, nil
+}
@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+func newToolOutputTestRuntime(t *testing.T, opts ...agent.Opt) (*LocalRuntime, *agent.Agent) {
+	t.Helper()
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent", append([]agent.Opt{agent.WithModel(prov)}, opts...)...)
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+	return rt, root
+}
+
+func getMoreOutput(t *testing.T, rt *LocalRuntime, sess *session.Session, cursor string) *tools.ToolCallResult {
+	t.Helper()
+
+	args, err := json.Marshal(builtin.GetMoreOutputArgs{Cursor: cursor})
+	require.NoError(t, err)
+	res, err := rt.handleGetMoreOutput(t.Context(), sess, tools.ToolCall{
+		ID:       "call_more",
+		Function: tools.FunctionCall{Name: builtin.ToolNameGetMoreOutput, Arguments: string(args)},
+	}, nil)
+	require.NoError(t, err)
+	return res
+}
+
+func TestProcessToolOutput_ShortOutputsAreUnchanged(t *testing.T) {
+	rt, a := newToolOutputTestRuntime(t, agent.WithToolOutputLimit(10, nil))
+	sess := session.New()
+
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell"}}
+	assert.Equal(t, "short output", rt.processToolOutput(t.Context(), sess, a, toolCall, "short output"))
+
+	// No limit by default
+	rt, a = newToolOutputTestRuntime(t)
+	long := strings.Repeat("x", 100_000)
+	assert.Equal(t, long, rt.processToolOutput(t.Context(), sess, a, toolCall, long))
+}
+
+func TestProcessToolOutput_TruncatesAndPages(t *testing.T) {
+	rt, a := newToolOutputTestRuntime(t, agent.WithToolOutputLimit(25, nil))
+	sess := session.New()
+
+	var lines []string
+	for i := range 100 {
+		lines = append(lines, strings.Repeat(string(rune('a'+i%26)), 9))
+	}
+	output := strings.Join(lines, "\n")
+
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell"}}
+	processed := rt.processToolOutput(t.Context(), sess, a, toolCall, output)
+
+	assert.True(t, strings.HasPrefix(processed, output[:50]))
+	assert.True(t, strings.HasSuffix(processed, output[len(output)-50:]))
+	assert.Contains(t, processed, `[... 899 characters omitted. Call get_more_output with cursor "call_1:50" to read them ...]`)
+
+	// Page through the whole output
+	var paged strings.Builder
+	paged.WriteString(output[:50])
+	cursor := "call_1:50"
+	for range 100 {
+		res := getMoreOutput(t, rt, sess, cursor)
+		require.False(t, res.IsError, res.Output)
+
+		page, note, _ := strings.Cut(res.Output, "\n\n[")
+		paged.WriteString(page)
+		if strings.HasPrefix(note, "End of the output") {
+			break
+		}
+		_, next, ok := strings.Cut(note, `cursor "`)
+		require.True(t, ok, note)
+		cursor, _, _ = strings.Cut(next, `"`)
+	}
+	assert.Equal(t, output, paged.String())
+}
+
+func TestProcessToolOutput_DoesNotCutCharacters(t *testing.T) {
+	rt, a := newToolOutputTestRuntime(t, agent.WithToolOutputLimit(3, nil))
+	sess := session.New()
+
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell"}}
+	processed := rt.processToolOutput(t.Context(), sess, a, toolCall, strings.Repeat("é", 50))
+
+	assert.True(t, strings.HasPrefix(processed, "ééé\n\n[..."), processed)
+	assert.True(t, strings.HasSuffix(processed, "...]\n\nééé"), processed)
+}
+
+func TestProcessToolOutput_Summarizes(t *testing.T) {
+	summaryModel := &mockProvider{id: "test/summary-model", stream: newStreamBuilder().AddContent("3 tests failed in pkg/foo").Build()}
+	rt, a := newToolOutputTestRuntime(t, agent.WithToolOutputLimit(100, summaryModel))
+	sess := session.New()
+
+	output := strings.Repeat("--- FAIL: TestFoo\n", 100)
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell"}}
+	processed := rt.processToolOutput(t.Context(), sess, a, toolCall, output)
+
+	assert.Equal(t, "3 tests failed in pkg/foo\n\n[Summary of a 1800-character output. Call get_more_output with cursor \"call_1:0\" to read the original output.]", processed)
+
+	res := getMoreOutput(t, rt, sess, "call_1:0")
+	require.False(t, res.IsError)
+	assert.True(t, strings.HasPrefix(res.Output, output[:400]))
+	assert.Contains(t, res.Output, `[Characters 0 to 400 of 1800. Call get_more_output with cursor "call_1:400" for the next page.]`)
+}
+
+func TestHandleGetMoreOutput_Errors(t *testing.T) {
+	rt, a := newToolOutputTestRuntime(t, agent.WithToolOutputLimit(10, nil))
+	sess := session.New()
+
+	assert.True(t, getMoreOutput(t, rt, sess, "invalid").IsError)
+	assert.True(t, getMoreOutput(t, rt, sess, "call_1:-1").IsError)
+	assert.Contains(t, getMoreOutput(t, rt, sess, "unknown:0").Output, "no longer available")
+
+	rt.processToolOutput(t.Context(), sess, a, tools.ToolCall{ID: "call_1"}, strings.Repeat("x", 100))
+	assert.Contains(t, getMoreOutput(t, rt, sess, "call_1:101").Output, "past the end")
+
+	// Outputs are only available in the session that produced them.
+	assert.True(t, getMoreOutput(t, rt, session.New(), "call_1:0").IsError)
+}
+
+func TestToolOutputStore_DropsOldestOutputs(t *testing.T) {
+	t.Parallel()
+
+	var store toolOutputStore
+	for i := range maxStoredToolOutputs + 1 {
+		store.add(formatToolOutputCursor("call", i), "output")
+	}
+
+	_, ok := store.get(formatToolOutputCursor("call", 0))
+	assert.False(t, ok)
+	_, ok = store.get(formatToolOutputCursor("call", maxStoredToolOutputs))
+	assert.True(t, ok)
+}
@@ -0,0 +1,164 @@
+package runtime
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+// defaultToolSearchResults is the number of tools a search makes available
+// when the agent doesn't set it.
+const defaultToolSearchResults = 5
+
+// toolSearchStore keeps the tools found with search_tools, per session, in
+// the order they were found. Found tools stay available for the rest of the
+// session, so that the tools sent to the model only grow, which keeps them
+// cacheable by providers.
+type toolSearchStore struct {
+	mu    sync.Mutex
+	found map[string][]string
+}
+
+func (s *toolSearchStore) add(sessionID string, names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.found == nil {
+		s.found = make(map[string][]string)
+	}
+	for _, name := range names {
+		if !slices.Contains(s.found[sessionID], name) {
+			s.found[sessionID] = append(s.found[sessionID], name)
+		}
+	}
+}
+
+func (s *toolSearchStore) get(sessionID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.found[sessionID])
+}
+
+func (s *toolSearchStore) remove(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.found, sessionID)
+}
+
+// selectTools returns the tools sent to the model. Agents with more tools
+// than their tool search threshold only get search_tools, the tools handled
+// by the runtime and the tools they found.
+func (r *LocalRuntime) selectTools(sess *session.Session, a *agent.Agent, agentTools []tools.Tool) []tools.Tool {
+	threshold := a.ToolSearchThreshold()
+	if threshold <= 0 {
+		return agentTools
+	}
+	if len(agentTools) <= threshold {
+		return slices.DeleteFunc(slices.Clone(agentTools), func(t tools.Tool) bool {
+			return t.Name == builtin.ToolNameSearchTools
+		})
+	}
+
+	byName := make(map[string]tools.Tool, len(agentTools))
+	var selected []tools.Tool
+	for _, t := range agentTools {
+		byName[t.Name] = t
+		if _, handled := r.toolMap[t.Name]; handled || t.Name == builtin.ToolNameSearchTools {
+			selected = append(selected, t)
+		}
+	}
+	for _, name := range r.toolSearch.get(sess.ID) {
+		if t, ok := byName[name]; ok {
+			selected = append(selected, t)
+		}
+	}
+	return selected
+}
+
+// handleSearchTools finds the hidden tools of the agent that best match the
+// query, and makes them available from the next request on.
+func (r *LocalRuntime) handleSearchTools(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+	var args builtin.SearchToolsArgs
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return tools.ResultError("The query is empty. Describe the tools to find with a few keywords."), nil
+	}
+
+	a := r.resolveSessionAgent(sess)
+	agentTools, err := a.Tools(ctx)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to list the tools: %s", err)), nil
+	}
+
+	available := make(map[string]bool)
+	for _, t := range r.selectTools(sess, a, agentTools) {
+		available[t.Name] = true
+	}
+	hidden := slices.DeleteFunc(agentTools, func(t tools.Tool) bool { return available[t.Name] })
+
+	found := searchTools(hidden, args.Query, cmp.Or(a.ToolSearchMaxResults(), defaultToolSearchResults))
+	if len(found) == 0 {
+		return tools.ResultSuccess(fmt.Sprintf("No tools match %q. Try other keywords.", args.Query)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString("These tools are now available:\n")
+	for _, t := range found {
+		r.toolSearch.add(sess.ID, t.Name)
+		description, _, _ := strings.Cut(t.Description, "\n")
+		fmt.Fprintf(&out, "\n- %s: %s", t.Name, description)
+	}
+	return tools.ResultSuccess(out.String()), nil
+}
+
+// searchTools ranks tools by how many keywords of the query their name, and
+// to a lesser extent their description, contain, and returns the best ones.
+func searchTools(candidates []tools.Tool, query string, maxResults int) []tools.Tool {
+	keywords := strings.Fields(strings.ToLower(query))
+
+	type scoredTool struct {
+		tool  tools.Tool
+		score int
+	}
+	var scored []scoredTool
+	for _, t := range candidates {
+		name := strings.NewReplacer("_", " ", "-", " ", ".", " ").Replace(strings.ToLower(t.Name))
+		description := strings.ToLower(t.Description)
+
+		score := 0
+		for _, keyword := range keywords {
+			if strings.Contains(name, keyword) {
+				score += 3
+			}
+			if strings.Contains(description, keyword) {
+				score++
+			}
+		}
+		if score > 0 {
+			scored = append(scored, scoredTool{tool: t, score: score})
+		}
+	}
+
+	slices.SortStableFunc(scored, func(a, b scoredTool) int {
+		return b.score - a.score
+	})
+
+	var found []tools.Tool
+	for _, s := range scored[:min(len(scored), maxResults)] {
+		found = append(found, s.tool)
+	}
+	return found
+}
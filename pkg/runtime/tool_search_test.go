@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+func toolSearchTestTools(t *testing.T) []tools.Tool {
+	t.Helper()
+
+	searchTools, err := builtin.NewToolSearchTool().Tools(t.Context())
+	require.NoError(t, err)
+	return append(searchTools,
+		tools.Tool{Name: "github_create_issue", Description: "Create an issue in a GitHub repository"},
+		tools.Tool{Name: "github_list_issues", Description: "List the issues of a GitHub repository"},
+		tools.Tool{Name: "slack_send_message", Description: "Send a message to a Slack channel"},
+		tools.Tool{Name: "read_file", Description: "Read a file\nReturns its content"},
+	)
+}
+
+func toolNames(toolsList []tools.Tool) []string {
+	var names []string
+	for _, t := range toolsList {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func searchToolsCall(t *testing.T, rt *LocalRuntime, sess *session.Session, query string) *tools.ToolCallResult {
+	t.Helper()
+
+	args, err := json.Marshal(builtin.SearchToolsArgs{Query: query})
+	require.NoError(t, err)
+	res, err := rt.handleSearchTools(t.Context(), sess, tools.ToolCall{
+		ID:       "call_search",
+		Function: tools.FunctionCall{Name: builtin.ToolNameSearchTools, Arguments: string(args)},
+	}, nil)
+	require.NoError(t, err)
+	return res
+}
+
+func TestSelectTools_BelowThreshold(t *testing.T) {
+	agentTools := toolSearchTestTools(t)
+	sess := session.New()
+
+	// Without tool search, tools are unchanged
+	rt, a := newToolOutputTestRuntime(t)
+	assert.Equal(t, agentTools, rt.selectTools(sess, a, agentTools))
+
+	// Below the threshold, all the tools but search_tools are sent
+	rt, a = newToolOutputTestRuntime(t, agent.WithToolSearch(10, 0))
+	assert.Equal(t, []string{"github_create_issue", "github_list_issues", "slack_send_message", "read_file"}, toolNames(rt.selectTools(sess, a, agentTools)))
+}
+
+func TestSearchTools_ExposesFoundTools(t *testing.T) {
+	agentTools := toolSearchTestTools(t)
+	rt, a := newToolOutputTestRuntime(t, agent.WithToolSearch(2, 0), agent.WithToolSets(newStubToolSet(nil, agentTools, nil)))
+	sess := session.New()
+
+	assert.Equal(t, []string{builtin.ToolNameSearchTools}, toolNames(rt.selectTools(sess, a, agentTools)))
+
+	res := searchToolsCall(t, rt, sess, "slack message")
+	require.False(t, res.IsError, res.Output)
+	assert.Equal(t, "These tools are now available:\n\n- slack_send_message: Send a message to a Slack channel", res.Output)
+	assert.Equal(t, []string{builtin.ToolNameSearchTools, "slack_send_message"}, toolNames(rt.selectTools(sess, a, agentTools)))
+
+	// Found tools are not found again, and are sent in the order they were found
+	res = searchToolsCall(t, rt, sess, "github issue message")
+	require.False(t, res.IsError, res.Output)
+	assert.NotContains(t, res.Output, "slack_send_message")
+	assert.Equal(t, []string{builtin.ToolNameSearchTools, "slack_send_message", "github_create_issue", "github_list_issues"}, toolNames(rt.selectTools(sess, a, agentTools)))
+
+	// Other sessions don't see them
+	assert.Equal(t, []string{builtin.ToolNameSearchTools}, toolNames(rt.selectTools(session.New(), a, agentTools)))
+}
+
+func TestSearchTools_MaxResultsAndErrors(t *testing.T) {
+	agentTools := toolSearchTestTools(t)
+	rt, a := newToolOutputTestRuntime(t, agent.WithToolSearch(2, 1), agent.WithToolSets(newStubToolSet(nil, agentTools, nil)))
+	sess := session.New()
+
+	res := searchToolsCall(t, rt, sess, "github issue")
+	require.False(t, res.IsError, res.Output)
+	assert.Equal(t, []string{builtin.ToolNameSearchTools, "github_create_issue"}, toolNames(rt.selectTools(sess, a, agentTools)))
+
+	res = searchToolsCall(t, rt, sess, "kubernetes")
+	assert.False(t, res.IsError)
+	assert.Equal(t, `No tools match "kubernetes". Try other keywords.`, res.Output)
+
+	res = searchToolsCall(t, rt, sess, " ")
+	assert.True(t, res.IsError)
+}
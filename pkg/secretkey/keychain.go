@@ -0,0 +1,197 @@
+package secretkey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when a secret isn't in the keychain.
+	ErrNotFound = errors.New("secret not found")
+	// ErrExists is returned when adding a secret that's in the keychain
+	// already.
+	ErrExists = errors.New("secret already exists")
+)
+
+// Exit statuses of security for errSecItemNotFound and errSecDuplicateItem.
+const (
+	securityNotFound  = 44
+	securityDuplicate = 45
+)
+
+// Keychain keeps generic passwords in the macOS keychain, through the
+// security command.
+type Keychain struct {
+	// binaryPath is the absolute path to the `security` binary, resolved at
+	// construction time to avoid PATH hijacking.
+	binaryPath string
+}
+
+// NewKeychain returns the macOS keychain, or false if there's none.
+func NewKeychain() (*Keychain, bool) {
+	if runtime.GOOS != "darwin" {
+		return nil, false
+	}
+	path, err := exec.LookPath("security")
+	if err != nil {
+		return nil, false
+	}
+	return &Keychain{binaryPath: path}, true
+}
+
+// Find returns the password of service, and of account unless it's empty,
+// or ErrNotFound. Any other error, like a locked keychain or a denied
+// prompt, means the password may exist but can't be read.
+func (k *Keychain) Find(ctx context.Context, service, account string) (string, error) {
+	args := []string{"find-generic-password", "-w", "-s", service}
+	if account != "" {
+		args = append(args, "-a", account)
+	}
+	out, err := run(ctx, k.binaryPath, nil, args...)
+	if exitCode(err) == securityNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s from the keychain: %w", service, err)
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+// Add adds a password for service and account. An existing password is
+// replaced if replace is set, or else Add returns ErrExists. The command is
+// given to security on stdin, so that the password isn't visible in the
+// arguments of the process.
+func (k *Keychain) Add(ctx context.Context, service, account, password string, replace bool) error {
+	command := "add-generic-password"
+	if replace {
+		command += " -U"
+	}
+	command += fmt.Sprintf(" -a %s -s %s -w %s\n", securityQuote(account), securityQuote(service), securityQuote(password))
+
+	_, err := run(ctx, k.binaryPath, strings.NewReader(command), "-i")
+	if exitCode(err) == securityDuplicate {
+		return ErrExists
+	}
+	if err != nil {
+		return fmt.Errorf("storing %s in the keychain: %w", service, err)
+	}
+	return nil
+}
+
+// Delete removes the password of service and account, if any.
+func (k *Keychain) Delete(ctx context.Context, service, account string) error {
+	_, err := run(ctx, k.binaryPath, nil, "delete-generic-password", "-s", service, "-a", account)
+	if exitCode(err) == securityNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("removing %s from the keychain: %w", service, err)
+	}
+	return nil
+}
+
+// securityQuote quotes s as an argument of a command of security -i.
+func securityQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// SecretService keeps secrets in the Secret Service, like GNOME Keyring or
+// KWallet, through secret-tool. Secrets are identified by attributes, given
+// as name/value pairs.
+type SecretService struct {
+	binaryPath string
+}
+
+// NewSecretService returns the Secret Service, or false if there's none.
+func NewSecretService() (*SecretService, bool) {
+	// The Secret Service is reached through the session bus.
+	if runtime.GOOS != "linux" || os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return nil, false
+	}
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return nil, false
+	}
+	return &SecretService{binaryPath: path}, true
+}
+
+// Lookup returns the secret with the given attributes, or ErrNotFound. Any
+// other error, like the Secret Service being unreachable, means the secret
+// may exist but can't be read.
+func (s *SecretService) Lookup(ctx context.Context, attributes ...string) (string, error) {
+	out, err := run(ctx, s.binaryPath, nil, append([]string{"lookup"}, attributes...)...)
+	var cmdErr *commandError
+	if exitCode(err) == 1 && out == "" && errors.As(err, &cmdErr) && cmdErr.stderr == "" {
+		// secret-tool fails without a message when there's no secret.
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading from the Secret Service: %w", err)
+	}
+	return out, nil
+}
+
+// Store stores a secret with the given attributes, replacing the previous
+// one.
+func (s *SecretService) Store(ctx context.Context, label, secret string, attributes ...string) error {
+	args := append([]string{"store", "--label=" + label}, attributes...)
+	if _, err := run(ctx, s.binaryPath, strings.NewReader(secret), args...); err != nil {
+		return fmt.Errorf("storing in the Secret Service: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the secrets with the given attributes, if any.
+func (s *SecretService) Clear(ctx context.Context, attributes ...string) error {
+	if _, err := run(ctx, s.binaryPath, nil, append([]string{"clear"}, attributes...)...); err != nil {
+		return fmt.Errorf("removing from the Secret Service: %w", err)
+	}
+	return nil
+}
+
+// commandError is the error of a command that failed, with its stderr.
+type commandError struct {
+	err    error
+	stderr string
+}
+
+func (e *commandError) Error() string {
+	if e.stderr == "" {
+		return e.err.Error()
+	}
+	return e.err.Error() + ": " + e.stderr
+}
+
+func (e *commandError) Unwrap() error {
+	return e.err
+}
+
+// run runs a command and returns its stdout.
+func run(ctx context.Context, binaryPath string, stdin *strings.Reader, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, binaryPath, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), &commandError{err: err, stderr: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.String(), nil
+}
+
+// exitCode returns the exit status of a command that failed, or -1.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,218 @@
+// Package secretkey keeps the keys encrypting data at rest, like OAuth tokens
+// and sessions, and encrypts data with them.
+//
+// Keys are kept in the macOS keychain or the Secret Service when available,
+// or else in a file only readable by the user. Keychain and SecretService
+// are also used to keep other secrets, like API keys.
+package secretkey
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Size is the size of the keys, for AES-256.
+const Size = 32
+
+var (
+	// ErrNoKey is returned by Keyring.Get when there's no key yet.
+	ErrNoKey = errors.New("no key")
+	// ErrKeyExists is returned by Keyring.Set when there's a key already.
+	ErrKeyExists = errors.New("key already exists")
+)
+
+// Keyring keeps a key.
+type Keyring interface {
+	// Get returns the key, or ErrNoKey if there's none yet. Any other error
+	// means the key may exist but can't be read.
+	Get() ([]byte, error)
+	// Set stores the key, or returns ErrKeyExists if there's one already:
+	// keys are never replaced, since the data they encrypt would be lost.
+	Set(key []byte) error
+}
+
+// Default returns the macOS keychain or the Secret Service when available,
+// or else the file. service names the key in the keychain and label
+// describes it in the Secret Service.
+func Default(service, label, file string) Keyring {
+	if keychain, ok := NewKeychain(); ok {
+		return &keychainKeyring{keychain: keychain, service: service}
+	}
+	if secretService, ok := NewSecretService(); ok {
+		return &secretServiceKeyring{secretService: secretService, service: service, label: label}
+	}
+	return NewFileKeyring(file)
+}
+
+// Generate generates a key and keeps it in the keyring.
+func Generate(keyring Keyring) ([]byte, error) {
+	key := make([]byte, Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := keyring.Set(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetOrGenerate returns the key of the keyring, generating it the first time.
+// If the key can't be read, the error is returned and no key is generated.
+func GetOrGenerate(keyring Keyring) ([]byte, error) {
+	key, err := keyring.Get()
+	if !errors.Is(err, ErrNoKey) {
+		return key, err
+	}
+
+	key, err = Generate(keyring)
+	if errors.Is(err, ErrKeyExists) {
+		// Another process generated the key first.
+		return keyring.Get()
+	}
+	return key, err
+}
+
+// Decode decodes a base64-encoded key.
+func Decode(encoded []byte) ([]byte, error) {
+	encoded = bytes.TrimSpace(encoded)
+	if len(encoded) == 0 {
+		return nil, ErrNoKey
+	}
+	return base64.StdEncoding.DecodeString(string(encoded))
+}
+
+// Encrypt encrypts plaintext with AES-GCM. The nonce is prepended to the
+// ciphertext.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt.
+func Decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted data")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keychainKeyring keeps the key in the macOS keychain.
+type keychainKeyring struct {
+	keychain *Keychain
+	service  string
+}
+
+func (k *keychainKeyring) Get() ([]byte, error) {
+	encoded, err := k.keychain.Find(context.Background(), k.service, "")
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrNoKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Decode([]byte(encoded))
+}
+
+func (k *keychainKeyring) Set(key []byte) error {
+	err := k.keychain.Add(context.Background(), k.service, "docker-agent", base64.StdEncoding.EncodeToString(key), false)
+	if errors.Is(err, ErrExists) {
+		return ErrKeyExists
+	}
+	return err
+}
+
+// secretServiceKeyring keeps the key in the Secret Service.
+type secretServiceKeyring struct {
+	secretService *SecretService
+	service       string
+	label         string
+}
+
+func (k *secretServiceKeyring) Get() ([]byte, error) {
+	encoded, err := k.secretService.Lookup(context.Background(), "service", k.service)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrNoKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Decode([]byte(encoded))
+}
+
+// Set stores the key in the Secret Service. secret-tool replaces existing
+// secrets, so Set checks first that there's none.
+func (k *secretServiceKeyring) Set(key []byte) error {
+	if _, err := k.Get(); !errors.Is(err, ErrNoKey) {
+		if err == nil {
+			return ErrKeyExists
+		}
+		return err
+	}
+	return k.secretService.Store(context.Background(), k.label, base64.StdEncoding.EncodeToString(key), "service", k.service)
+}
+
+// FileKeyring keeps the key in a file only readable by the user.
+type FileKeyring struct {
+	file string
+}
+
+// NewFileKeyring returns a keyring keeping the key in file.
+func NewFileKeyring(file string) *FileKeyring {
+	return &FileKeyring{file: file}
+}
+
+func (k *FileKeyring) Get() ([]byte, error) {
+	data, err := os.ReadFile(k.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+func (k *FileKeyring) Set(key []byte) error {
+	if err := os.MkdirAll(filepath.Dir(k.file), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(k.file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return ErrKeyExists
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
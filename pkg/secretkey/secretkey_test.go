@@ -0,0 +1,169 @@
+package secretkey
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileKeyring_GetOrGenerate(t *testing.T) {
+	t.Parallel()
+
+	keyring := NewFileKeyring(filepath.Join(t.TempDir(), "keys", "test.key"))
+	_, err := keyring.Get()
+	require.ErrorIs(t, err, ErrNoKey)
+
+	key, err := GetOrGenerate(keyring)
+	require.NoError(t, err)
+	assert.Len(t, key, Size)
+
+	again, err := GetOrGenerate(keyring)
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	key, err := Generate(NewFileKeyring(filepath.Join(t.TempDir(), "test.key")))
+	require.NoError(t, err)
+
+	data, err := Encrypt(key, []byte("secret"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	plaintext, err := Decrypt(key, data)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	data[len(data)-1] ^= 1
+	_, err = Decrypt(key, data)
+	require.Error(t, err)
+}
+
+func TestFileKeyring_NeverReplacesTheKey(t *testing.T) {
+	t.Parallel()
+
+	keyring := NewFileKeyring(filepath.Join(t.TempDir(), "test.key"))
+	key, err := Generate(keyring)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, keyring.Set(make([]byte, Size)), ErrKeyExists)
+
+	again, err := keyring.Get()
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+}
+
+type failingKeyring struct {
+	set bool
+}
+
+func (k *failingKeyring) Get() ([]byte, error) { return nil, errors.New("keychain locked") }
+
+func (k *failingKeyring) Set([]byte) error {
+	k.set = true
+	return nil
+}
+
+func TestGetOrGenerate_DoesntReplaceAKeyThatCantBeRead(t *testing.T) {
+	t.Parallel()
+
+	keyring := &failingKeyring{}
+	_, err := GetOrGenerate(keyring)
+	require.ErrorContains(t, err, "keychain locked")
+	assert.False(t, keyring.set)
+}
+
+// fakeCommand writes a shell script standing for security or secret-tool. It
+// saves its arguments and stdin in dir.
+func fakeCommand(t *testing.T, dir, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake commands are shell scripts")
+	}
+
+	path := filepath.Join(dir, "fake")
+	content := "#!/bin/sh\necho \"$@\" > " + filepath.Join(dir, "args") + "\ncat > " + filepath.Join(dir, "stdin") + "\n" + script + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o700))
+	return path
+}
+
+func TestKeychainKeyring_Get(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keyring := &keychainKeyring{keychain: &Keychain{binaryPath: fakeCommand(t, dir, "exit 44")}, service: "test"}
+	_, err := keyring.Get()
+	require.ErrorIs(t, err, ErrNoKey)
+
+	// User interaction is not allowed: the keychain is locked.
+	keyring.keychain.binaryPath = fakeCommand(t, dir, "echo 'User interaction is not allowed.' >&2; exit 36")
+	_, err = keyring.Get()
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNoKey)
+	assert.Contains(t, err.Error(), "User interaction is not allowed.")
+}
+
+func TestKeychainKeyring_Set(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keyring := &keychainKeyring{keychain: &Keychain{binaryPath: fakeCommand(t, dir, "")}, service: "test service"}
+	key := bytes.Repeat([]byte{1}, Size)
+	require.NoError(t, keyring.Set(key))
+
+	encoded := base64.StdEncoding.EncodeToString(key)
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(args), encoded)
+	assert.NotContains(t, string(args), "-U")
+
+	stdin, err := os.ReadFile(filepath.Join(dir, "stdin"))
+	require.NoError(t, err)
+	assert.Equal(t, "add-generic-password -a 'docker-agent' -s 'test service' -w '"+encoded+"'\n", string(stdin))
+
+	keyring.keychain.binaryPath = fakeCommand(t, dir, "exit 45")
+	require.ErrorIs(t, keyring.Set(key), ErrKeyExists)
+}
+
+func TestSecretServiceKeyring_Get(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keyring := &secretServiceKeyring{secretService: &SecretService{binaryPath: fakeCommand(t, dir, "exit 1")}, service: "test"}
+	_, err := keyring.Get()
+	require.ErrorIs(t, err, ErrNoKey)
+
+	keyring.secretService.binaryPath = fakeCommand(t, dir, "echo 'Cannot autolaunch D-Bus without X11 $DISPLAY' >&2; exit 1")
+	_, err = keyring.Get()
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNoKey)
+
+	// secret-tool store replaces items, so Set checks there's none.
+	keyring.secretService.binaryPath = fakeCommand(t, dir, "echo "+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, Size)))
+	require.ErrorIs(t, keyring.Set(make([]byte, Size)), ErrKeyExists)
+}
+
+func TestKeychain_AddReplacing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keychain := &Keychain{binaryPath: fakeCommand(t, dir, "")}
+	require.NoError(t, keychain.Add(t.Context(), "docker-agent", "OPENAI_API_KEY", "sk-secret", true))
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	assert.Equal(t, "-i\n", string(args))
+
+	stdin, err := os.ReadFile(filepath.Join(dir, "stdin"))
+	require.NoError(t, err)
+	assert.Equal(t, "add-generic-password -U -a 'OPENAI_API_KEY' -s 'docker-agent' -w 'sk-secret'\n", string(stdin))
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// ErrForbidden is returned when a user of a shared session doesn't have the
+// role an action requires.
+var ErrForbidden = errors.New("forbidden")
+
+// Role is what a member of a shared session can do.
+type Role string
+
+const (
+	// RoleOwner is the user who created the session: they can do anything,
+	// including managing the members.
+	RoleOwner Role = "owner"
+	// RoleEditor sends messages and answers the confirmations and
+	// elicitations of the runs.
+	RoleEditor Role = "editor"
+	// RoleReviewer answers the confirmations and elicitations of the runs.
+	RoleReviewer Role = "reviewer"
+	// RoleViewer only follows the events of the runs.
+	RoleViewer Role = "viewer"
+)
+
+// Valid reports whether the role can be given to a member.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleEditor, RoleReviewer, RoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// action is something a member of a shared session does.
+type action int
+
+const (
+	actionWatch action = iota
+	actionAnswer
+	actionSend
+	actionManage
+)
+
+// allows reports whether the role can do the action.
+func (r Role) allows(a action) bool {
+	switch a {
+	case actionWatch:
+		return r != ""
+	case actionAnswer:
+		return r == RoleOwner || r == RoleEditor || r == RoleReviewer
+	case actionSend:
+		return r == RoleOwner || r == RoleEditor
+	default:
+		return r == RoleOwner
+	}
+}
+
+// watcherBuffer is the number of events kept for a watcher that doesn't read
+// them fast enough. Later events are dropped.
+const watcherBuffer = 256
+
+// collaboration holds the members of a session shared by several users, as
+// saved in the session store, and the users following its events. Sessions
+// created without a user, when the server doesn't identify them, are open to
+// every request.
+type collaboration struct {
+	mu       sync.Mutex
+	owner    string
+	members  map[string]Role
+	watchers map[chan runtime.Event]struct{}
+}
+
+func newCollaboration(saved session.Members) *collaboration {
+	members := map[string]Role{}
+	for user, role := range saved.Roles {
+		members[user] = Role(role)
+	}
+	return &collaboration{
+		owner:    saved.Owner,
+		members:  members,
+		watchers: map[chan runtime.Event]struct{}{},
+	}
+}
+
+// role returns the role of a user in the session, or an empty role for
+// users who aren't members.
+func (c *collaboration) role(user string) Role {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.owner == "" || user == c.owner {
+		return RoleOwner
+	}
+	return c.members[user]
+}
+
+// check returns ErrForbidden if the user can't do the action.
+func (c *collaboration) check(user string, a action) error {
+	if !c.role(user).allows(a) {
+		return fmt.Errorf("%w: %q isn't allowed to do this in the session", ErrForbidden, user)
+	}
+	return nil
+}
+
+// setMember gives a role to a user, or removes them from the members with an
+// empty role. The members are changed only once save has saved them.
+func (c *collaboration) setMember(user string, role Role, save func(session.Members) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if user == c.owner {
+		return errors.New("the role of the owner of the session can't be changed")
+	}
+	if role != "" && !role.Valid() {
+		return fmt.Errorf("invalid role %q: must be editor, reviewer or viewer", role)
+	}
+
+	members := maps.Clone(c.members)
+	if role == "" {
+		delete(members, user)
+	} else {
+		members[user] = role
+	}
+
+	saved := session.Members{Owner: c.owner, Roles: map[string]string{}}
+	for member, memberRole := range members {
+		saved.Roles[member] = string(memberRole)
+	}
+	if err := save(saved); err != nil {
+		return err
+	}
+	c.members = members
+	return nil
+}
+
+// Member is a user of a shared session.
+type Member struct {
+	User string `json:"user"`
+	Role Role   `json:"role"`
+}
+
+// list returns the owner and the members of the session, sorted by user.
+func (c *collaboration) list() []Member {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var members []Member
+	if c.owner != "" {
+		members = append(members, Member{User: c.owner, Role: RoleOwner})
+	}
+	for _, user := range slices.Sorted(maps.Keys(c.members)) {
+		members = append(members, Member{User: user, Role: c.members[user]})
+	}
+	return members
+}
+
+// watch returns a channel receiving the events of the runs of the session,
+// and a function to stop watching them.
+func (c *collaboration) watch() (<-chan runtime.Event, func()) {
+	events := make(chan runtime.Event, watcherBuffer)
+
+	c.mu.Lock()
+	c.watchers[events] = struct{}{}
+	c.mu.Unlock()
+
+	return events, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.watchers[events]; ok {
+			delete(c.watchers, events)
+			close(events)
+		}
+	}
+}
+
+// broadcast sends an event to the watchers of the session.
+func (c *collaboration) broadcast(sessionID string, event runtime.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for events := range c.watchers {
+		select {
+		case events <- event:
+		default:
+			slog.Warn("Dropping an event for a slow watcher of the session", "session_id", sessionID)
+		}
+	}
+}
+
+// close stops all the watchers of the session, when it's deleted.
+func (c *collaboration) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for events := range c.watchers {
+		delete(c.watchers, events)
+		close(events)
+	}
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestSessionManager_SharedSession(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	sm := NewSessionManager(ctx, config.Sources{}, store, 0, &config.RuntimeConfig{})
+
+	sess, err := sm.CreateSession(ctx, &session.Session{}, "alice")
+	require.NoError(t, err)
+	rt := &sharedRuntime{}
+	sm.runtimeSessions.Store(sess.ID, &activeRuntimes{runtime: rt, session: sess, cancel: func() {}})
+
+	require.NoError(t, sm.SetSessionMember(ctx, sess.ID, "alice", "bob", RoleReviewer))
+	require.NoError(t, sm.SetSessionMember(ctx, sess.ID, "alice", "carol", RoleViewer))
+	require.ErrorIs(t, sm.SetSessionMember(ctx, sess.ID, "bob", "dave", RoleEditor), ErrForbidden)
+	require.Error(t, sm.SetSessionMember(ctx, sess.ID, "alice", "alice", RoleViewer), "the owner keeps their role")
+
+	members, err := sm.SessionMembers(ctx, sess.ID, "carol")
+	require.NoError(t, err)
+	assert.Equal(t, []Member{{User: "alice", Role: RoleOwner}, {User: "bob", Role: RoleReviewer}, {User: "carol", Role: RoleViewer}}, members)
+
+	_, _, err = sm.WatchSession(ctx, sess.ID, "dave")
+	require.ErrorIs(t, err, ErrForbidden)
+	watched, stop, err := sm.WatchSession(ctx, sess.ID, "bob")
+	require.NoError(t, err)
+	defer stop()
+
+	_, err = sm.RunSession(ctx, sess.ID, "agent.yaml", "root", "bob", []api.Message{{Content: "hello"}})
+	require.ErrorIs(t, err, ErrForbidden)
+
+	events, err := sm.RunSession(ctx, sess.ID, "agent.yaml", "root", "alice", []api.Message{{Content: "hello"}})
+	require.NoError(t, err)
+	for range events {
+	}
+	assert.IsType(t, &runtime.WarningEvent{}, <-watched, "watchers receive the events of the runs of other users")
+
+	saved, err := store.GetSession(ctx, sess.ID)
+	require.NoError(t, err)
+	require.Len(t, saved.Messages, 1)
+	assert.Equal(t, "alice", saved.Messages[0].Message.User)
+
+	require.ErrorIs(t, sm.ResumeSession(ctx, sess.ID, "carol", string(runtime.ResumeTypeApprove), "", "", 0), ErrForbidden)
+	require.NoError(t, sm.ResumeSession(ctx, sess.ID, "bob", string(runtime.ResumeTypeApprove), "", "", 0))
+	assert.Equal(t, "bob", rt.resumed.User)
+
+	require.ErrorIs(t, sm.DeleteSession(ctx, sess.ID, "bob"), ErrForbidden)
+	require.NoError(t, sm.DeleteSession(ctx, sess.ID, "alice"))
+	_, open := <-watched
+	assert.False(t, open, "deleting the session stops its watchers")
+}
+
+func TestSessionManager_ChecksTheRolesOnEveryEndpoint(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	sm := NewSessionManager(ctx, config.Sources{}, store, 0, &config.RuntimeConfig{})
+
+	sess, err := sm.CreateSession(ctx, &session.Session{}, "alice")
+	require.NoError(t, err)
+	require.NoError(t, sm.SetSessionMember(ctx, sess.ID, "alice", "bob", RoleViewer))
+
+	_, err = sm.GetSession(ctx, sess.ID, "dave")
+	require.ErrorIs(t, err, ErrForbidden)
+	_, err = sm.GetSessionItems(ctx, sess.ID, "dave", 0, 0)
+	require.ErrorIs(t, err, ErrForbidden)
+	_, err = sm.GetSession(ctx, sess.ID, "bob")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, sm.ToggleToolApproval(ctx, sess.ID, "bob"), ErrForbidden)
+	require.ErrorIs(t, sm.ToggleThinking(ctx, sess.ID, "bob"), ErrForbidden)
+	require.ErrorIs(t, sm.UpdateSessionPermissions(ctx, sess.ID, "bob", nil), ErrForbidden)
+	require.ErrorIs(t, sm.UpdateSessionTitle(ctx, sess.ID, "bob", "Mine"), ErrForbidden)
+	require.ErrorIs(t, sm.DeleteSession(ctx, sess.ID, "bob"), ErrForbidden)
+	require.NoError(t, sm.UpdateSessionTitle(ctx, sess.ID, "alice", "Ours"))
+
+	summaries, err := sm.ListSessionSummaries(ctx, "dave", session.Page{}, session.SummaryFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+	summaries, err = sm.ListSessionSummaries(ctx, "bob", session.Page{}, session.SummaryFilter{})
+	require.NoError(t, err)
+	assert.Len(t, summaries, 1)
+
+	// The members are kept by the store, for the next servers.
+	restarted := NewSessionManager(ctx, config.Sources{}, store, 0, &config.RuntimeConfig{})
+	_, err = restarted.GetSession(ctx, sess.ID, "dave")
+	require.ErrorIs(t, err, ErrForbidden)
+	members, err := restarted.SessionMembers(ctx, sess.ID, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, []Member{{User: "alice", Role: RoleOwner}, {User: "bob", Role: RoleViewer}}, members)
+
+	_, err = restarted.GetSession(ctx, "unknown", "alice")
+	require.ErrorIs(t, err, session.ErrNotFound)
+}
+
+func TestSessionManager_SessionWithoutOwner(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	sm := NewSessionManager(ctx, config.Sources{}, store, 0, &config.RuntimeConfig{})
+
+	sess, err := sm.CreateSession(ctx, &session.Session{}, "")
+	require.NoError(t, err)
+
+	_, stop, err := sm.WatchSession(ctx, sess.ID, "anyone")
+	require.NoError(t, err)
+	stop()
+	require.NoError(t, sm.SetSessionMember(ctx, sess.ID, "anyone", "bob", RoleViewer))
+}
+
+func TestCollaboration_SlowWatcher(t *testing.T) {
+	t.Parallel()
+
+	c := newCollaboration(session.Members{Owner: "alice"})
+	events, stop := c.watch()
+	defer stop()
+
+	for range watcherBuffer + 1 {
+		c.broadcast("session", runtime.Warning("warning", ""))
+	}
+	assert.Len(t, events, watcherBuffer, "events are dropped instead of blocking the run")
+}
+
+// sharedRuntime emits one event per run and remembers the last answer to a
+// confirmation.
+type sharedRuntime struct {
+	runtime.Runtime
+
+	resumed runtime.ResumeRequest
+}
+
+func (*sharedRuntime) RunStream(context.Context, *session.Session) <-chan runtime.Event {
+	events := make(chan runtime.Event, 1)
+	events <- runtime.Warning("running", "")
+	close(events)
+	return events
+}
+
+func (r *sharedRuntime) Resume(_ context.Context, req runtime.ResumeRequest) {
+	r.resumed = req
+}
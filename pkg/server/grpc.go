@@ -0,0 +1,354 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/api/agentv1"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// ServeGRPC serves the gRPC API, the same API as the HTTP one, with typed
+// messages and streams instead of SSE. It shuts down gracefully like Serve.
+func (s *Server) ServeGRPC(ctx context.Context, ln net.Listener) error {
+	srv := grpc.NewServer()
+	agentv1.RegisterAgentServiceServer(srv, &grpcService{s: s})
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		if ctx.Err() == nil {
+			slog.Error("Failed to start gRPC server", "error", err)
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	s.drain()
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(interruptGracePeriod):
+		slog.Warn("Failed to shut down the gRPC server gracefully")
+		srv.Stop()
+	}
+
+	return nil
+}
+
+// grpcService is the gRPC service of the API server.
+type grpcService struct {
+	agentv1.UnimplementedAgentServiceServer
+
+	s *Server
+}
+
+func (g *grpcService) ListAgents(ctx context.Context, _ *agentv1.Empty) (*agentv1.ListAgentsResponse, error) {
+	var resp agentv1.ListAgentsResponse
+	for _, agent := range g.s.listAgents(ctx) {
+		resp.Agents = append(resp.Agents, &agentv1.Agent{Name: agent.Name, Description: agent.Description, Multi: agent.Multi})
+	}
+	return &resp, nil
+}
+
+func (g *grpcService) GetAgent(ctx context.Context, req *agentv1.AgentRequest) (*agentv1.AgentConfig, error) {
+	cfg, found := g.s.agentConfig(ctx, req.GetId())
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "agent not found: %s", req.GetId())
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal agent config: %v", err)
+	}
+	return &agentv1.AgentConfig{Json: data}, nil
+}
+
+func (g *grpcService) GetAgentToolCount(ctx context.Context, req *agentv1.AgentRequest) (*agentv1.AgentToolCountResponse, error) {
+	count, err := g.s.sm.GetAgentToolCount(ctx, req.GetId(), cmp.Or(req.GetAgentName(), "root"))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent tool count: %v", err)
+	}
+	return &agentv1.AgentToolCountResponse{AvailableTools: int64(count)}, nil
+}
+
+func (g *grpcService) ListSessions(ctx context.Context, _ *agentv1.Empty) (*agentv1.ListSessionsResponse, error) {
+	sessions, err := g.s.sm.ListSessionSummaries(ctx, g.user(ctx), session.Page{}, session.SummaryFilter{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get sessions: %v", err)
+	}
+	var resp agentv1.ListSessionsResponse
+	for _, sess := range sessionsResponse(sessions) {
+		resp.Sessions = append(resp.Sessions, &agentv1.SessionSummary{
+			Id:           sess.ID,
+			Title:        sess.Title,
+			CreatedAt:    sess.CreatedAt,
+			NumMessages:  int64(sess.NumMessages),
+			InputTokens:  sess.InputTokens,
+			OutputTokens: sess.OutputTokens,
+			WorkingDir:   sess.WorkingDir,
+		})
+	}
+	return &resp, nil
+}
+
+func (g *grpcService) GetSession(ctx context.Context, req *agentv1.SessionRequest) (*agentv1.Session, error) {
+	sess, err := g.s.sm.GetSession(ctx, req.GetSessionId(), g.user(ctx))
+	if errors.Is(err, ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "session not found: %v", err)
+	}
+	return protoSession(sessionResponse(sess))
+}
+
+func (g *grpcService) CreateSession(ctx context.Context, req *agentv1.CreateSessionRequest) (*agentv1.Session, error) {
+	sessionTemplate := session.Session{
+		WorkingDirs:   req.GetWorkingDirs(),
+		MaxIterations: int(req.GetMaxIterations()),
+		ToolsApproved: req.GetToolsApproved(),
+	}
+	if len(req.GetPermissionsJson()) > 0 {
+		if err := json.Unmarshal(req.GetPermissionsJson(), &sessionTemplate.Permissions); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid permissions: %v", err)
+		}
+	}
+
+	sess, err := g.s.sm.CreateSession(ctx, &sessionTemplate, g.user(ctx))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create session: %v", err)
+	}
+	return protoSession(sessionResponse(sess))
+}
+
+func (g *grpcService) DeleteSession(ctx context.Context, req *agentv1.SessionRequest) (*agentv1.Empty, error) {
+	err := g.s.sm.DeleteSession(ctx, req.GetSessionId(), g.user(ctx))
+	if errors.Is(err, ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete session: %v", err)
+	}
+	return &agentv1.Empty{}, nil
+}
+
+func (g *grpcService) UpdateSessionTitle(ctx context.Context, req *agentv1.UpdateSessionTitleRequest) (*agentv1.UpdateSessionTitleResponse, error) {
+	err := g.s.sm.UpdateSessionTitle(ctx, req.GetSessionId(), g.user(ctx), req.GetTitle())
+	if errors.Is(err, ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update session title: %v", err)
+	}
+	return &agentv1.UpdateSessionTitleResponse{Id: req.GetSessionId(), Title: req.GetTitle()}, nil
+}
+
+func (g *grpcService) RegenerateSessionTitle(ctx context.Context, req *agentv1.SessionRequest) (*agentv1.UpdateSessionTitleResponse, error) {
+	title, err := g.s.sm.RegenerateSessionTitle(ctx, req.GetSessionId(), g.user(ctx))
+	if errors.Is(err, ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to regenerate session title: %v", err)
+	}
+	return &agentv1.UpdateSessionTitleResponse{Id: req.GetSessionId(), Title: title}, nil
+}
+
+func (g *grpcService) ResumeSession(ctx context.Context, req *agentv1.ResumeSessionRequest) (*agentv1.Empty, error) {
+	err := g.resume(ctx, req.GetSessionId(), req)
+	if errors.Is(err, ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to resume session: %v", err)
+	}
+	return &agentv1.Empty{}, nil
+}
+
+func (g *grpcService) ResumeElicitation(ctx context.Context, req *agentv1.ResumeElicitationRequest) (*agentv1.Empty, error) {
+	err := g.resumeElicitation(ctx, req.GetSessionId(), req)
+	if errors.Is(err, ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to resume elicitation: %v", err)
+	}
+	return &agentv1.Empty{}, nil
+}
+
+func (g *grpcService) resume(ctx context.Context, sessionID string, req *agentv1.ResumeSessionRequest) error {
+	return g.s.sm.ResumeSession(ctx, sessionID, g.user(ctx), req.GetConfirmation(), req.GetReason(), req.GetToolName(), int(req.GetIterations()))
+}
+
+func (g *grpcService) resumeElicitation(ctx context.Context, sessionID string, req *agentv1.ResumeElicitationRequest) error {
+	var content map[string]any
+	if len(req.GetContentJson()) > 0 {
+		if err := json.Unmarshal(req.GetContentJson(), &content); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid elicitation content: %v", err)
+		}
+	}
+	return g.s.sm.ResumeElicitation(ctx, sessionID, g.user(ctx), req.GetAction(), content)
+}
+
+// RunAgent runs an agent and streams its events.
+func (g *grpcService) RunAgent(req *agentv1.RunAgentRequest, stream grpc.ServerStreamingServer[agentv1.Event]) error {
+	events, err := g.run(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	return sendEvents(stream, events)
+}
+
+// StreamSession runs an agent with the first message of the stream and streams
+// its events, while the next messages answer its confirmations and elicitations.
+func (g *grpcService) StreamSession(stream grpc.BidiStreamingServer[agentv1.SessionStreamRequest, agentv1.Event]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if first.GetRun() == nil {
+		return status.Error(codes.InvalidArgument, "the first message of a session stream must start a run")
+	}
+
+	events, err := g.run(stream.Context(), first.GetRun())
+	if err != nil {
+		return err
+	}
+
+	sessionID := first.GetRun().GetSessionId()
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) && stream.Context().Err() == nil {
+					slog.Debug("Failed to receive from session stream", "session_id", sessionID, "error", err)
+				}
+				return
+			}
+
+			switch {
+			case req.GetResume() != nil:
+				err = g.resume(stream.Context(), sessionID, req.GetResume())
+			case req.GetElicitation() != nil:
+				err = g.resumeElicitation(stream.Context(), sessionID, req.GetElicitation())
+			default:
+				err = errors.New("only confirmations and elicitations can be sent during a run")
+			}
+			if err != nil {
+				slog.Warn("Invalid message on session stream", "session_id", sessionID, "error", err)
+			}
+		}
+	}()
+
+	return sendEvents(stream, events)
+}
+
+func (g *grpcService) run(ctx context.Context, req *agentv1.RunAgentRequest) (<-chan runtime.Event, error) {
+	agentName := cmp.Or(req.GetAgentName(), "root")
+	slog.Debug("Running agent", "agent_filename", req.GetAgent(), "session_id", req.GetSessionId(), "current_agent", agentName)
+
+	messages := make([]api.Message, 0, len(req.GetMessages()))
+	for _, msg := range req.GetMessages() {
+		message := api.Message{Role: chat.MessageRole(msg.GetRole()), Content: msg.GetContent()}
+		if len(msg.GetMultiContentJson()) > 0 {
+			if err := json.Unmarshal(msg.GetMultiContentJson(), &message.MultiContent); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid message content: %v", err)
+			}
+		}
+		messages = append(messages, message)
+	}
+
+	events, err := g.s.sm.RunSession(ctx, req.GetSessionId(), req.GetAgent(), agentName, g.user(ctx), messages)
+	if errors.Is(err, ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if errors.Is(err, ErrShuttingDown) {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to run session: %v", err)
+	}
+	return events, nil
+}
+
+// user returns the user of a call, from the metadata of the user header.
+func (g *grpcService) user(ctx context.Context) string {
+	if header := g.s.sm.userHeader(); header != "" {
+		if values := metadata.ValueFromIncomingContext(ctx, header); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// sendEvents sends the events of a run.
+func sendEvents(stream interface{ Send(*agentv1.Event) error }, events <-chan runtime.Event) error {
+	for event := range events {
+		pe, err := runtime.EncodeGRPCEvent(event)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to marshal event: %v", err)
+		}
+		if err := stream.Send(pe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protoSession converts a session of the HTTP API to the gRPC API.
+func protoSession(sess api.SessionResponse) (*agentv1.Session, error) {
+	resp := &agentv1.Session{
+		Id:            sess.ID,
+		Title:         sess.Title,
+		CreatedAt:     sess.CreatedAt.Format(time.RFC3339),
+		ToolsApproved: sess.ToolsApproved,
+		Thinking:      sess.Thinking,
+		InputTokens:   sess.InputTokens,
+		OutputTokens:  sess.OutputTokens,
+		WorkingDir:    sess.WorkingDir,
+		WorkingDirs:   sess.WorkingDirs,
+	}
+	for field, value := range map[*[]byte]any{
+		&resp.MessagesJson:    sess.Messages,
+		&resp.PermissionsJson: sess.Permissions,
+		&resp.ArtifactsJson:   sess.Artifacts,
+		&resp.BlackboardJson:  sess.Blackboard,
+		&resp.RecordsJson:     sess.Records,
+	} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal session: %v", err)
+		}
+		*field = data
+	}
+	return resp, nil
+}
+
+// Verify interface compliance
+var _ agentv1.AgentServiceServer = (*grpcService)(nil)
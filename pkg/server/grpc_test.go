@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestGRPCServer(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "dummy")
+	t.Setenv("ANTHROPIC_API_KEY", "dummy")
+
+	ctx := t.Context()
+	client := startGRPCServer(t, ctx, prepareAgentsDir(t, "multi_agents.yaml", "pirate.yaml"), session.NewInMemorySessionStore())
+
+	agents, err := client.GetAgents(ctx)
+	require.NoError(t, err)
+	require.Len(t, agents, 2)
+	assert.Contains(t, agents[0].Name, "multi_agents")
+	assert.True(t, agents[0].Multi)
+	assert.Contains(t, agents[1].Name, "pirate")
+	assert.Equal(t, "Talk like a pirate", agents[1].Description)
+
+	cfg, err := client.GetAgent(ctx, agents[1].Name)
+	require.NoError(t, err)
+	assert.Equal(t, "Talk like a pirate", cfg.Agents.First().Description)
+
+	_, err = client.GetAgent(ctx, "unknown")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	sess, err := client.CreateSession(ctx, &session.Session{})
+	require.NoError(t, err)
+	require.NotEmpty(t, sess.ID)
+
+	require.NoError(t, client.UpdateSessionTitle(ctx, sess.ID, "My Custom Title"))
+	got, err := client.GetSession(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "My Custom Title", got.Title)
+
+	sessions, err := client.GetSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, sess.ID, sessions[0].ID)
+
+	require.NoError(t, client.DeleteSession(ctx, sess.ID))
+	sessions, err = client.GetSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestGRPCServer_RunUnknownSession(t *testing.T) {
+	ctx := t.Context()
+	client := startGRPCServer(t, ctx, prepareAgentsDir(t), session.NewInMemorySessionStore())
+
+	// The stream ends with an error event.
+	stream, err := client.OpenSession(ctx, "", "", "", nil)
+	require.NoError(t, err)
+
+	var events []runtime.Event
+	for event := range stream.Events() {
+		events = append(events, event)
+	}
+	require.Len(t, events, 1)
+	assert.IsType(t, &runtime.ErrorEvent{}, events[0])
+}
+
+func startGRPCServer(t *testing.T, ctx context.Context, agentsDir string, store session.Store) *runtime.GRPCClient {
+	t.Helper()
+
+	runConfig := config.RuntimeConfig{}
+
+	sources, err := config.ResolveSources(agentsDir, nil)
+	require.NoError(t, err)
+	srv, err := New(ctx, store, &runConfig, 0, sources)
+	require.NoError(t, err)
+
+	socketPath := "unix://" + filepath.Join(t.TempDir(), "grpc.sock")
+	ln, err := Listen(ctx, socketPath)
+	require.NoError(t, err)
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	go func() {
+		_ = srv.ServeGRPC(ctx, ln)
+	}()
+
+	client, err := runtime.NewGRPCClient(socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
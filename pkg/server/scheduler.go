@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a user has used their daily tokens.
+var ErrQuotaExceeded = errors.New("daily token quota exceeded")
+
+// SchedulerConfig limits the runs of the API server. Zero values mean no limit.
+type SchedulerConfig struct {
+	// MaxConcurrentRuns is the number of runs executed at the same time.
+	// Other runs wait in a queue.
+	MaxConcurrentRuns int
+	// MaxRunsPerUser is the number of runs of a user executed at the same
+	// time. Other runs of the user wait in the queue, without blocking
+	// the runs of other users.
+	MaxRunsPerUser int
+	// DailyTokensPerUser is the number of input and output tokens a user
+	// can use per day, in UTC. Runs of users over their quota are rejected.
+	DailyTokensPerUser int64
+	// UserHeader is the HTTP header, or the gRPC metadata, identifying the
+	// user of a request. Requests without it are all from the same user.
+	UserHeader string
+}
+
+func (c SchedulerConfig) enabled() bool {
+	return c.MaxConcurrentRuns > 0 || c.MaxRunsPerUser > 0 || c.DailyTokensPerUser > 0
+}
+
+// scheduler starts the runs in the order they are requested, within the
+// limits of its config.
+type scheduler struct {
+	config SchedulerConfig
+	now    func() time.Time
+
+	mu            sync.Mutex
+	running       int
+	runningByUser map[string]int
+	queue         []*queuedRun
+	usage         map[string]dailyUsage
+}
+
+type dailyUsage struct {
+	day    string
+	tokens int64
+}
+
+type queuedRun struct {
+	user         string
+	started      chan struct{}
+	position     chan int
+	lastPosition int
+}
+
+// scheduledRun is a run holding a slot of the scheduler.
+type scheduledRun struct {
+	s    *scheduler
+	user string
+	once sync.Once
+}
+
+func newScheduler(config SchedulerConfig) *scheduler {
+	return &scheduler{
+		config:        config,
+		now:           time.Now,
+		runningByUser: map[string]int{},
+		usage:         map[string]dailyUsage{},
+	}
+}
+
+// checkQuota returns ErrQuotaExceeded if the user can't start runs today.
+func (s *scheduler) checkQuota(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.checkQuotaLocked(user)
+}
+
+func (s *scheduler) checkQuotaLocked(user string) error {
+	if s.config.DailyTokensPerUser > 0 && s.usedTokensLocked(user) >= s.config.DailyTokensPerUser {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (s *scheduler) usedTokensLocked(user string) int64 {
+	usage := s.usage[user]
+	if usage.day != s.today() {
+		return 0
+	}
+	return usage.tokens
+}
+
+func (s *scheduler) today() string {
+	return s.now().UTC().Format(time.DateOnly)
+}
+
+// acquire waits for a slot to run. While the run is queued, onQueued is
+// called with its position every time it changes.
+func (s *scheduler) acquire(ctx context.Context, user string, onQueued func(position int)) (*scheduledRun, error) {
+	s.mu.Lock()
+	if err := s.checkQuotaLocked(user); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	q := &queuedRun{
+		user:     user,
+		started:  make(chan struct{}),
+		position: make(chan int, 1),
+	}
+	s.queue = append(s.queue, q)
+	s.dispatchLocked()
+	s.mu.Unlock()
+
+	for {
+		select {
+		case <-q.started:
+			return &scheduledRun{s: s, user: user}, nil
+		case position := <-q.position:
+			onQueued(position)
+		case <-ctx.Done():
+			s.mu.Lock()
+			select {
+			case <-q.started:
+				// Started in the meantime: give the slot back.
+				s.mu.Unlock()
+				(&scheduledRun{s: s, user: user}).release(0)
+			default:
+				s.queue = slices.DeleteFunc(s.queue, func(other *queuedRun) bool { return other == q })
+				s.dispatchLocked()
+				s.mu.Unlock()
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *scheduler) canStartLocked(user string) bool {
+	if s.config.MaxConcurrentRuns > 0 && s.running >= s.config.MaxConcurrentRuns {
+		return false
+	}
+	if s.config.MaxRunsPerUser > 0 && s.runningByUser[user] >= s.config.MaxRunsPerUser {
+		return false
+	}
+	return true
+}
+
+func (s *scheduler) startLocked(user string) {
+	s.running++
+	s.runningByUser[user]++
+}
+
+// dispatchLocked starts the queued runs that can start, in order, and tells
+// the others their new position.
+func (s *scheduler) dispatchLocked() {
+	var waiting []*queuedRun
+	for _, q := range s.queue {
+		if s.canStartLocked(q.user) {
+			s.startLocked(q.user)
+			close(q.started)
+			continue
+		}
+
+		waiting = append(waiting, q)
+		if q.lastPosition == len(waiting) {
+			continue
+		}
+		q.lastPosition = len(waiting)
+		// Only keep the latest position.
+		select {
+		case <-q.position:
+		default:
+		}
+		q.position <- q.lastPosition
+	}
+	s.queue = waiting
+}
+
+// release frees the slot of the run and counts the tokens it used.
+func (r *scheduledRun) release(tokens int64) {
+	r.once.Do(func() {
+		s := r.s
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.running--
+		s.runningByUser[r.user]--
+		if s.runningByUser[r.user] == 0 {
+			delete(s.runningByUser, r.user)
+		}
+
+		today := s.today()
+		usage := s.usage[r.user]
+		if usage.day != today {
+			usage = dailyUsage{day: today}
+		}
+		usage.tokens += tokens
+		s.usage[r.user] = usage
+
+		s.dispatchLocked()
+	})
+}
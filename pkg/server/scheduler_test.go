@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acquireAsync acquires a slot in the background and reports the queue
+// positions of the run.
+func acquireAsync(t *testing.T, ctx context.Context, s *scheduler, user string) (<-chan *scheduledRun, <-chan int) {
+	t.Helper()
+
+	runs := make(chan *scheduledRun, 1)
+	positions := make(chan int, 10)
+	go func() {
+		run, err := s.acquire(ctx, user, func(position int) { positions <- position })
+		if err == nil {
+			runs <- run
+		}
+		close(runs)
+	}()
+	return runs, positions
+}
+
+func TestScheduler_MaxConcurrentRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	s := newScheduler(SchedulerConfig{MaxConcurrentRuns: 1})
+
+	first, err := s.acquire(ctx, "alice", nil)
+	require.NoError(t, err)
+
+	secondRuns, secondPositions := acquireAsync(t, ctx, s, "bob")
+	assert.Equal(t, 1, <-secondPositions)
+	thirdRuns, thirdPositions := acquireAsync(t, ctx, s, "carol")
+	assert.Equal(t, 2, <-thirdPositions)
+
+	first.release(0)
+	second := <-secondRuns
+	require.NotNil(t, second)
+	assert.Equal(t, 1, <-thirdPositions)
+
+	second.release(0)
+	third := <-thirdRuns
+	require.NotNil(t, third)
+	third.release(0)
+}
+
+func TestScheduler_MaxRunsPerUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	s := newScheduler(SchedulerConfig{MaxRunsPerUser: 1})
+
+	first, err := s.acquire(ctx, "alice", nil)
+	require.NoError(t, err)
+
+	aliceRuns, alicePositions := acquireAsync(t, ctx, s, "alice")
+	assert.Equal(t, 1, <-alicePositions)
+
+	// Other users don't wait for alice's runs
+	bob, err := s.acquire(ctx, "bob", nil)
+	require.NoError(t, err)
+	bob.release(0)
+
+	first.release(0)
+	second := <-aliceRuns
+	require.NotNil(t, second)
+	second.release(0)
+}
+
+func TestScheduler_CanceledWhileQueued(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduler(SchedulerConfig{MaxConcurrentRuns: 1})
+
+	first, err := s.acquire(t.Context(), "alice", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	canceledRuns, canceledPositions := acquireAsync(t, ctx, s, "bob")
+	assert.Equal(t, 1, <-canceledPositions)
+	nextRuns, nextPositions := acquireAsync(t, t.Context(), s, "carol")
+	assert.Equal(t, 2, <-nextPositions)
+
+	cancel()
+	assert.Nil(t, <-canceledRuns)
+	assert.Equal(t, 1, <-nextPositions)
+
+	first.release(0)
+	next := <-nextRuns
+	require.NotNil(t, next)
+	next.release(0)
+}
+
+func TestScheduler_DailyTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := newScheduler(SchedulerConfig{DailyTokensPerUser: 1000})
+	s.now = func() time.Time { return now }
+
+	run, err := s.acquire(t.Context(), "alice", nil)
+	require.NoError(t, err)
+	run.release(600)
+	run.release(600) // released once
+
+	run, err = s.acquire(t.Context(), "alice", nil)
+	require.NoError(t, err)
+	run.release(600)
+
+	require.ErrorIs(t, s.checkQuota("alice"), ErrQuotaExceeded)
+	_, err = s.acquire(t.Context(), "alice", nil)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	require.NoError(t, s.checkQuota("bob"))
+
+	// Quotas are reset every day
+	now = now.Add(24 * time.Hour)
+	require.NoError(t, s.checkQuota("alice"))
+}
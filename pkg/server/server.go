@@ -4,35 +4,73 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
 	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/app/export"
 	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/upstream"
 )
 
+// DefaultShutdownTimeout is how long the runs going on have to finish when the
+// server shuts down, before being interrupted.
+const DefaultShutdownTimeout = 30 * time.Second
+
 type Server struct {
 	e  *echo.Echo
 	sm *SessionManager
+
+	shutdownTimeout time.Duration
+	drainOnce       sync.Once
+}
+
+type Opt func(*Server)
+
+// WithShutdownTimeout sets how long the runs going on have to finish when the
+// server shuts down, before being interrupted.
+func WithShutdownTimeout(timeout time.Duration) Opt {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithScheduler limits the concurrent runs and the daily tokens of the users.
+// Its user header also identifies the members of shared sessions.
+func WithScheduler(config SchedulerConfig) Opt {
+	return func(s *Server) {
+		s.sm.userHeaderName = config.UserHeader
+		if config.enabled() {
+			s.sm.scheduler = newScheduler(config)
+		}
+	}
 }
 
-func New(ctx context.Context, sessionStore session.Store, runConfig *config.RuntimeConfig, refreshInterval time.Duration, agentSources config.Sources) (*Server, error) {
+func New(ctx context.Context, sessionStore session.Store, runConfig *config.RuntimeConfig, refreshInterval time.Duration, agentSources config.Sources, opts ...Opt) (*Server, error) {
 	e := echo.New()
 	e.Use(middleware.RequestLogger())
 	e.Use(echo.WrapMiddleware(upstream.Handler))
 
 	s := &Server{
-		e:  e,
-		sm: NewSessionManager(ctx, agentSources, sessionStore, refreshInterval, runConfig),
+		e:               e,
+		sm:              NewSessionManager(ctx, agentSources, sessionStore, refreshInterval, runConfig),
+		shutdownTimeout: DefaultShutdownTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	group := e.Group("/api")
@@ -46,6 +84,26 @@ func New(ctx context.Context, sessionStore session.Store, runConfig *config.Runt
 	group.GET("/sessions", s.getSessions)
 	// Get a session by id
 	group.GET("/sessions/:id", s.getSession)
+	// List the items of a session, page by page
+	group.GET("/sessions/:id/items", s.getSessionItems)
+	// Follow the events of the runs of a session
+	group.GET("/sessions/:id/events", s.watchSession)
+	// List the members of a session
+	group.GET("/sessions/:id/members", s.getSessionMembers)
+	// Give a role in a session to a user
+	group.PUT("/sessions/:id/members/:user", s.setSessionMember)
+	// Remove a user from the members of a session
+	group.DELETE("/sessions/:id/members/:user", s.deleteSessionMember)
+	// Share a read-only transcript of a session
+	group.POST("/sessions/:id/share", s.shareSession)
+	// Revoke the read-only transcript of a session
+	group.DELETE("/sessions/:id/share", s.unshareSession)
+	// Get the read-only transcript of a shared session
+	group.GET("/shared/:token", s.getSharedSession)
+	// List the artifacts of a session
+	group.GET("/sessions/:id/artifacts", s.getSessionArtifacts)
+	// Download an artifact of a session
+	group.GET("/sessions/:id/artifacts/:artifact_id", s.getSessionArtifact)
 	// Resume a session by id
 	group.POST("/sessions/:id/resume", s.resumeSession)
 	// Toggle YOLO mode for a session
@@ -56,6 +114,8 @@ func New(ctx context.Context, sessionStore session.Store, runConfig *config.Runt
 	group.PATCH("/sessions/:id/permissions", s.updateSessionPermissions)
 	// Update session title
 	group.PATCH("/sessions/:id/title", s.updateSessionTitle)
+	// Regenerate session title
+	group.POST("/sessions/:id/title/regenerate", s.regenerateSessionTitle)
 	// Create a new session
 	group.POST("/sessions", s.createSession)
 	// Delete a session
@@ -76,25 +136,59 @@ func New(ctx context.Context, sessionStore session.Store, runConfig *config.Runt
 	return s, nil
 }
 
+// Serve serves the HTTP API until ctx is done. The server then shuts down
+// gracefully: new runs are rejected while the runs going on finish, and
+// confirmations can still be sent for them.
 func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
 	srv := http.Server{
 		Handler: s.e,
 	}
 
-	if err := srv.Serve(ln); err != nil && ctx.Err() == nil {
-		slog.Error("Failed to start server", "error", err)
-		return err
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		if ctx.Err() == nil {
+			slog.Error("Failed to start server", "error", err)
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	s.drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptGracePeriod)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Failed to shut down the server gracefully", "error", err)
+		_ = srv.Close()
 	}
 
 	return nil
 }
 
+// drain waits for the runs going on, once for all the APIs being served.
+func (s *Server) drain() {
+	s.drainOnce.Do(func() {
+		slog.Info("Shutting down, waiting for the runs going on", "timeout", s.shutdownTimeout)
+		s.sm.Drain(context.Background(), s.shutdownTimeout)
+	})
+}
+
 func (s *Server) getAgents(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.listAgents(c.Request().Context()))
+}
+
+// listAgents returns the agents of all the sources, sorted by name.
+func (s *Server) listAgents(ctx context.Context) []api.Agent {
 	agents := []api.Agent{}
 	for k, agentSource := range s.sm.Sources {
 		slog.Debug("API source", "source", agentSource.Name())
 
-		c, err := config.Load(c.Request().Context(), agentSource)
+		c, err := config.Load(ctx, agentSource)
 		if err != nil {
 			slog.Error("Failed to load config from API source", "key", k, "error", err)
 			continue
@@ -125,49 +219,73 @@ func (s *Server) getAgents(c echo.Context) error {
 		return cmp.Compare(a.Name, b.Name)
 	})
 
-	return c.JSON(http.StatusOK, agents)
+	return agents
 }
 
 func (s *Server) getAgentConfig(c echo.Context) error {
-	agentID := c.Param("id")
+	cfg, found := s.agentConfig(c.Request().Context(), c.Param("id"))
+	if !found {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	return c.JSON(http.StatusOK, cfg)
+}
 
+// agentConfig loads the config of an agent.
+func (s *Server) agentConfig(ctx context.Context, agentID string) (*latest.Config, bool) {
 	for k, agentSource := range s.sm.Sources {
 		if k != agentID {
 			continue
 		}
 
 		slog.Debug("API source", "source", agentSource.Name())
-		cfg, err := config.Load(c.Request().Context(), agentSource)
+		cfg, err := config.Load(ctx, agentSource)
 		if err != nil {
 			slog.Error("Failed to load config from API source", "key", k, "error", err)
 			continue
 		}
 
-		return c.JSON(http.StatusOK, cfg)
+		return cfg, true
 	}
 
-	return echo.NewHTTPError(http.StatusNotFound)
+	return nil, false
 }
 
 func (s *Server) getSessions(c echo.Context) error {
-	sessions, err := s.sm.GetSessions(c.Request().Context())
+	var page session.Page
+	var filter session.SummaryFilter
+	if err := echo.QueryParamsBinder(c).
+		Int("offset", &page.Offset).
+		Int("limit", &page.Limit).
+		String("q", &filter.Query).
+		String("working_dir", &filter.WorkingDir).
+		Bool("starred", &filter.Starred).
+		BindError(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid query: %v", err))
+	}
+
+	sessions, err := s.sm.ListSessionSummaries(c.Request().Context(), s.user(c), page, filter)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to get sessions: %v", err))
 	}
 
+	return c.JSON(http.StatusOK, sessionsResponse(sessions))
+}
+
+func sessionsResponse(sessions []session.Summary) []api.SessionsResponse {
 	responses := make([]api.SessionsResponse, len(sessions))
 	for i, sess := range sessions {
 		responses[i] = api.SessionsResponse{
 			ID:           sess.ID,
 			Title:        sess.Title,
 			CreatedAt:    sess.CreatedAt.Format(time.RFC3339),
-			NumMessages:  len(sess.GetAllMessages()),
+			NumMessages:  sess.NumMessages,
 			InputTokens:  sess.InputTokens,
 			OutputTokens: sess.OutputTokens,
 			WorkingDir:   sess.WorkingDir,
 		}
 	}
-	return c.JSON(http.StatusOK, responses)
+	return responses
 }
 
 func (s *Server) createSession(c echo.Context) error {
@@ -176,7 +294,7 @@ func (s *Server) createSession(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
-	sess, err := s.sm.CreateSession(c.Request().Context(), &sessionTemplate)
+	sess, err := s.sm.CreateSession(c.Request().Context(), &sessionTemplate, s.user(c))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to create session: %v", err))
 	}
@@ -185,12 +303,42 @@ func (s *Server) createSession(c echo.Context) error {
 }
 
 func (s *Server) getSession(c echo.Context) error {
-	sess, err := s.sm.GetSession(c.Request().Context(), c.Param("id"))
+	sess, err := s.sm.GetSession(c.Request().Context(), c.Param("id"), s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
 	}
 
-	return c.JSON(http.StatusOK, api.SessionResponse{
+	return c.JSON(http.StatusOK, sessionResponse(sess))
+}
+
+func (s *Server) getSessionItems(c echo.Context) error {
+	var offset, limit int
+	if err := echo.QueryParamsBinder(c).
+		Int("offset", &offset).
+		Int("limit", &limit).
+		BindError(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid query: %v", err))
+	}
+
+	items, err := s.sm.GetSessionItems(c.Request().Context(), c.Param("id"), s.user(c), offset, limit)
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+	if items == nil {
+		items = []session.Item{} // We don't want null, but an empty array
+	}
+
+	return c.JSON(http.StatusOK, api.SessionItemsResponse{Items: items})
+}
+
+func sessionResponse(sess *session.Session) api.SessionResponse {
+	return api.SessionResponse{
 		ID:            sess.ID,
 		Title:         sess.Title,
 		CreatedAt:     sess.CreatedAt,
@@ -200,8 +348,51 @@ func (s *Server) getSession(c echo.Context) error {
 		InputTokens:   sess.InputTokens,
 		OutputTokens:  sess.OutputTokens,
 		WorkingDir:    sess.WorkingDir,
+		WorkingDirs:   sess.WorkingDirs,
 		Permissions:   sess.Permissions,
-	})
+		Artifacts:     sess.GetArtifacts(),
+		Blackboard:    sess.GetBlackboard(""),
+		Records:       sess.GetRecords(),
+	}
+}
+
+func (s *Server) getSessionArtifacts(c echo.Context) error {
+	sess, err := s.sm.GetSessionMetadata(c.Request().Context(), c.Param("id"), s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+
+	artifacts := sess.GetArtifacts()
+	if artifacts == nil {
+		artifacts = []session.Artifact{}
+	}
+	return c.JSON(http.StatusOK, artifacts)
+}
+
+func (s *Server) getSessionArtifact(c echo.Context) error {
+	sess, err := s.sm.GetSessionMetadata(c.Request().Context(), c.Param("id"), s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+
+	artifact, ok := sess.GetArtifact(c.Param("artifact_id"))
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "artifact not found")
+	}
+	if _, err := os.Stat(artifact.Path); err != nil {
+		return echo.NewHTTPError(http.StatusGone, fmt.Sprintf("artifact file is not available: %v", err))
+	}
+
+	if artifact.MimeType != "" {
+		c.Response().Header().Set(echo.HeaderContentType, artifact.MimeType)
+	}
+	return c.Attachment(artifact.Path, artifact.Name)
 }
 
 func (s *Server) resumeSession(c echo.Context) error {
@@ -210,7 +401,11 @@ func (s *Server) resumeSession(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
-	if err := s.sm.ResumeSession(c.Request().Context(), c.Param("id"), req.Confirmation, req.Reason, req.ToolName); err != nil {
+	err := s.sm.ResumeSession(c.Request().Context(), c.Param("id"), s.user(c), req.Confirmation, req.Reason, req.ToolName, req.Iterations)
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to resume session: %v", err))
 	}
 
@@ -218,7 +413,11 @@ func (s *Server) resumeSession(c echo.Context) error {
 }
 
 func (s *Server) toggleSessionYolo(c echo.Context) error {
-	if err := s.sm.ToggleToolApproval(c.Request().Context(), c.Param("id")); err != nil {
+	err := s.sm.ToggleToolApproval(c.Request().Context(), c.Param("id"), s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to toggle session tool approval mode: %v", err))
 	}
 	return c.JSON(http.StatusOK, nil)
@@ -234,7 +433,11 @@ func (s *Server) getAgentToolCount(c echo.Context) error {
 }
 
 func (s *Server) toggleSessionThinking(c echo.Context) error {
-	if err := s.sm.ToggleThinking(c.Request().Context(), c.Param("id")); err != nil {
+	err := s.sm.ToggleThinking(c.Request().Context(), c.Param("id"), s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to toggle session thinking mode: %v", err))
 	}
 	return c.JSON(http.StatusOK, nil)
@@ -247,7 +450,11 @@ func (s *Server) updateSessionPermissions(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
-	if err := s.sm.UpdateSessionPermissions(c.Request().Context(), sessionID, req.Permissions); err != nil {
+	err := s.sm.UpdateSessionPermissions(c.Request().Context(), sessionID, s.user(c), req.Permissions)
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to update session permissions: %v", err))
 	}
 
@@ -261,7 +468,11 @@ func (s *Server) updateSessionTitle(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
-	if err := s.sm.UpdateSessionTitle(c.Request().Context(), sessionID, req.Title); err != nil {
+	err := s.sm.UpdateSessionTitle(c.Request().Context(), sessionID, s.user(c), req.Title)
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to update session title: %v", err))
 	}
 
@@ -271,10 +482,31 @@ func (s *Server) updateSessionTitle(c echo.Context) error {
 	})
 }
 
+func (s *Server) regenerateSessionTitle(c echo.Context) error {
+	sessionID := c.Param("id")
+
+	title, err := s.sm.RegenerateSessionTitle(c.Request().Context(), sessionID, s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to regenerate session title: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, api.UpdateSessionTitleResponse{
+		ID:    sessionID,
+		Title: title,
+	})
+}
+
 func (s *Server) deleteSession(c echo.Context) error {
 	sessionID := c.Param("id")
 
-	if err := s.sm.DeleteSession(c.Request().Context(), sessionID); err != nil {
+	err := s.sm.DeleteSession(c.Request().Context(), sessionID, s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to delete session: %v", err))
 	}
 
@@ -293,16 +525,30 @@ func (s *Server) runAgent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
-	streamChan, err := s.sm.RunSession(c.Request().Context(), sessionID, agentFilename, currentAgent, messages)
+	streamChan, err := s.sm.RunSession(c.Request().Context(), sessionID, agentFilename, currentAgent, s.user(c), messages)
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	}
+	if errors.Is(err, ErrShuttingDown) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to run session: %v", err))
 	}
 
+	return streamEvents(c, streamChan)
+}
+
+// streamEvents streams the events as server-sent events.
+func streamEvents(c echo.Context, events <-chan runtime.Event) error {
 	c.Response().Header().Set("Content-Type", "text/event-stream")
 	c.Response().Header().Set("Cache-Control", "no-cache")
 	c.Response().Header().Set("Connection", "keep-alive")
 	c.Response().WriteHeader(http.StatusOK)
-	for event := range streamChan {
+	for event := range events {
 		data, err := json.Marshal(event)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to marshal event: %v", err))
@@ -321,9 +567,138 @@ func (s *Server) elicitation(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
-	if err := s.sm.ResumeElicitation(c.Request().Context(), sessionID, req.Action, req.Content); err != nil {
+	err := s.sm.ResumeElicitation(c.Request().Context(), sessionID, s.user(c), req.Action, req.Content)
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to resume elicitation: %v", err))
 	}
 
 	return c.JSON(http.StatusOK, nil)
 }
+
+// user returns the user of a request, from the user header.
+func (s *Server) user(c echo.Context) string {
+	if header := s.sm.userHeader(); header != "" {
+		return c.Request().Header.Get(header)
+	}
+	return ""
+}
+
+func (s *Server) watchSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	events, stop, err := s.sm.WatchSession(ctx, c.Param("id"), s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+	defer stop()
+
+	// Stop watching when the client goes away, even between runs.
+	stopOnDone := context.AfterFunc(ctx, stop)
+	defer stopOnDone()
+
+	return streamEvents(c, events)
+}
+
+func (s *Server) getSessionMembers(c echo.Context) error {
+	members, err := s.sm.SessionMembers(c.Request().Context(), c.Param("id"), s.user(c))
+	if errors.Is(err, ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+	if members == nil {
+		members = []Member{}
+	}
+
+	return c.JSON(http.StatusOK, members)
+}
+
+func (s *Server) setSessionMember(c echo.Context) error {
+	var req api.SetSessionMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+	}
+	if !Role(req.Role).Valid() {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid role %q: must be editor, reviewer or viewer", req.Role))
+	}
+
+	if err := s.updateSessionMember(c, Role(req.Role)); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, Member{User: c.Param("user"), Role: Role(req.Role)})
+}
+
+func (s *Server) deleteSessionMember(c echo.Context) error {
+	if err := s.updateSessionMember(c, ""); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "session member removed"})
+}
+
+func (s *Server) updateSessionMember(c echo.Context, role Role) error {
+	err := s.sm.SetSessionMember(c.Request().Context(), c.Param("id"), s.user(c), c.Param("user"), role)
+	switch {
+	case errors.Is(err, ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, session.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	case err != nil:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("failed to update session member: %v", err))
+	}
+	return nil
+}
+
+func (s *Server) shareSession(c echo.Context) error {
+	token, err := s.sm.ShareSession(c.Request().Context(), c.Param("id"), s.user(c))
+	switch {
+	case errors.Is(err, ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, session.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to share session: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, api.ShareSessionResponse{
+		Token: token,
+		URL:   c.Scheme() + "://" + c.Request().Host + "/api/shared/" + token,
+	})
+}
+
+func (s *Server) unshareSession(c echo.Context) error {
+	err := s.sm.UnshareSession(c.Request().Context(), c.Param("id"), s.user(c))
+	switch {
+	case errors.Is(err, ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, session.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to unshare session: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "session unshared"})
+}
+
+func (s *Server) getSharedSession(c echo.Context) error {
+	sess, err := s.sm.GetSharedSession(c.Request().Context(), c.Param("token"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "shared session not found")
+	}
+
+	page, err := export.SessionHTML(sess, "")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to export session: %v", err))
+	}
+
+	// The transcript renders the Markdown of the models: don't let it load
+	// or send anything, nor run any script but the page's own.
+	c.Response().Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; script-src "+export.ScriptHash()+"; img-src data:")
+	c.Response().Header().Set("X-Robots-Tag", "noindex")
+	return c.HTML(http.StatusOK, page)
+}
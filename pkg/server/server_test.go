@@ -11,12 +11,15 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/app/export"
 	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
 )
 
@@ -120,6 +123,12 @@ func httpGET(t *testing.T, ctx context.Context, socketPath, path string) []byte
 
 func httpDo(t *testing.T, ctx context.Context, method, socketPath, path string, payload any) []byte {
 	t.Helper()
+	_, buf := httpResponse(t, ctx, method, socketPath, path, payload)
+	return buf
+}
+
+func httpResponse(t *testing.T, ctx context.Context, method, socketPath, path string, payload any) (http.Header, []byte) {
+	t.Helper()
 
 	var (
 		body        io.Reader
@@ -160,7 +169,7 @@ func httpDo(t *testing.T, ctx context.Context, method, socketPath, path string,
 	require.NoError(t, err)
 
 	require.Less(t, resp.StatusCode, 400, string(buf))
-	return buf
+	return resp.Header, buf
 }
 
 func unmarshal(t *testing.T, buf []byte, v any) {
@@ -199,6 +208,58 @@ func TestServer_UpdateSessionTitle(t *testing.T) {
 	assert.Equal(t, newTitle, sessionResp.Title)
 }
 
+func TestServer_SessionArtifacts(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	lnPath := startServerWithStore(t, ctx, prepareAgentsDir(t), store)
+
+	report := filepath.Join(t.TempDir(), "report.md")
+	require.NoError(t, os.WriteFile(report, []byte("# Report"), 0o600))
+
+	sess := session.New()
+	sess.AddArtifact(session.Artifact{ID: "report", Name: "report.md", Path: report, MimeType: "text/markdown", Size: 8})
+	require.NoError(t, store.AddSession(ctx, sess))
+
+	var artifacts []session.Artifact
+	unmarshal(t, httpGET(t, ctx, lnPath, "/api/sessions/"+sess.ID+"/artifacts"), &artifacts)
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, "report.md", artifacts[0].Name)
+
+	assert.Equal(t, "# Report", string(httpGET(t, ctx, lnPath, "/api/sessions/"+sess.ID+"/artifacts/report")))
+
+	var sessionResp api.SessionResponse
+	unmarshal(t, httpGET(t, ctx, lnPath, "/api/sessions/"+sess.ID), &sessionResp)
+	assert.Equal(t, artifacts, sessionResp.Artifacts)
+}
+
+func TestServer_ShareSession(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	lnPath := startServerWithStore(t, ctx, prepareAgentsDir(t), store)
+
+	sess := session.New(session.WithTitle("Incident"))
+	sess.AddMessage(session.UserMessage("Why is the API down?"))
+	require.NoError(t, store.AddSession(ctx, sess))
+
+	var shared api.ShareSessionResponse
+	unmarshal(t, httpDo(t, ctx, http.MethodPost, lnPath, "/api/sessions/"+sess.ID+"/share", nil), &shared)
+	require.NotEmpty(t, shared.Token)
+	assert.Equal(t, "http://_/api/shared/"+shared.Token, shared.URL)
+
+	header, page := httpResponse(t, ctx, http.MethodGet, lnPath, "/api/shared/"+shared.Token, nil)
+	assert.Contains(t, string(page), "<title>Incident</title>")
+	assert.Contains(t, string(page), "Why is the API down?")
+	assert.Equal(t, "default-src 'none'; style-src 'unsafe-inline'; script-src "+export.ScriptHash()+"; img-src data:", header.Get("Content-Security-Policy"))
+
+	httpDo(t, ctx, http.MethodDelete, lnPath, "/api/sessions/"+sess.ID+"/share", nil)
+	_, err := store.GetSharedSession(ctx, shared.Token)
+	require.ErrorIs(t, err, session.ErrNotFound)
+}
+
 func startServerWithStore(t *testing.T, ctx context.Context, agentsDir string, store session.Store) string {
 	t.Helper()
 
@@ -235,3 +296,64 @@ func (s mockStore) GetSessions(context.Context) ([]*session.Session, error) {
 func (s mockStore) GetSessionSummaries(context.Context) ([]session.Summary, error) {
 	return nil, nil
 }
+
+func (s mockStore) ListSessionSummaries(context.Context, session.Page, session.SummaryFilter) ([]session.Summary, error) {
+	return nil, nil
+}
+
+func TestSessionManager_DrainRejectsRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	sess := session.New()
+	require.NoError(t, store.AddSession(ctx, sess))
+
+	sm := NewSessionManager(ctx, config.Sources{}, store, 0, &config.RuntimeConfig{})
+	sm.Drain(ctx, time.Second)
+
+	_, err := sm.RunSession(ctx, sess.ID, "agent.yaml", "root", "", nil)
+	require.ErrorIs(t, err, ErrShuttingDown)
+}
+
+func TestSessionManager_DrainInterruptsRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	sess := session.New()
+	require.NoError(t, store.AddSession(ctx, sess))
+
+	sm := NewSessionManager(ctx, config.Sources{}, store, 0, &config.RuntimeConfig{})
+	sm.runtimeSessions.Store(sess.ID, &activeRuntimes{runtime: blockingRuntime{}, session: sess})
+
+	events, err := sm.RunSession(ctx, sess.ID, "agent.yaml", "root", "", []api.Message{{Content: "hello"}})
+	require.NoError(t, err)
+
+	sm.Drain(ctx, 10*time.Millisecond)
+
+	var received []runtime.Event
+	for event := range events {
+		received = append(received, event)
+	}
+	require.Len(t, received, 1)
+	assert.IsType(t, &runtime.WarningEvent{}, received[0])
+
+	saved, err := store.GetSession(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Len(t, saved.GetAllMessages(), 1)
+}
+
+// blockingRuntime runs until its context is cancelled.
+type blockingRuntime struct {
+	runtime.Runtime
+}
+
+func (blockingRuntime) RunStream(ctx context.Context, _ *session.Session) <-chan runtime.Event {
+	events := make(chan runtime.Event)
+	go func() {
+		<-ctx.Done()
+		close(events)
+	}()
+	return events
+}
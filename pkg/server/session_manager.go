@@ -9,9 +9,11 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/concurrent"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/runtime"
@@ -22,14 +24,22 @@ import (
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
+// ErrShuttingDown is returned for the runs requested while the server shuts down.
+var ErrShuttingDown = errors.New("the server is shutting down")
+
+// interruptGracePeriod is how long the interrupted runs have to stop and
+// save their session.
+const interruptGracePeriod = 10 * time.Second
+
 type activeRuntimes struct {
 	runtime  runtime.Runtime
+	team     *team.Team
 	cancel   context.CancelFunc
 	session  *session.Session        // The actual session object used by the runtime
 	titleGen *sessiontitle.Generator // Title generator (includes fallback models)
 }
 
-// SessionManager manages sessions for HTTP and Connect-RPC servers.
+// SessionManager manages sessions for the HTTP and gRPC servers.
 type SessionManager struct {
 	runtimeSessions *concurrent.Map[string, *activeRuntimes]
 	sessionStore    session.Store
@@ -41,6 +51,24 @@ type SessionManager struct {
 
 	refreshInterval time.Duration
 
+	// scheduler limits the runs. Runs start right away without one.
+	scheduler *scheduler
+
+	// userHeaderName is the header identifying the users of the requests.
+	userHeaderName string
+	// collaborations holds the members and the watchers of the sessions
+	// loaded from the store.
+	collaborations map[string]*collaboration
+	collabMux      sync.Mutex
+
+	// draining is set when the server shuts down: new runs are rejected
+	// while runs waits for the ones going on.
+	draining atomic.Bool
+	runs     sync.WaitGroup
+	// interrupted is cancelled to interrupt the runs going on.
+	interrupted context.Context
+	interrupt   context.CancelFunc
+
 	mux sync.Mutex
 }
 
@@ -57,29 +85,81 @@ func NewSessionManager(ctx context.Context, sources config.Sources, sessionStore
 		Sources:         loaders,
 		refreshInterval: refreshInterval,
 		runConfig:       runConfig,
+		collaborations:  map[string]*collaboration{},
 	}
+	sm.interrupted, sm.interrupt = context.WithCancel(context.Background())
 
 	return sm
 }
 
-// GetSession retrieves a session by ID.
-func (sm *SessionManager) GetSession(ctx context.Context, id string) (*session.Session, error) {
-	sess, err := sm.sessionStore.GetSession(ctx, id)
+// userHeader returns the header identifying the users of the requests.
+func (sm *SessionManager) userHeader() string {
+	return sm.userHeaderName
+}
+
+// collaboration returns the members and the watchers of a session, loading
+// its members from the store the first time.
+func (sm *SessionManager) collaboration(ctx context.Context, sessionID string) (*collaboration, error) {
+	sm.collabMux.Lock()
+	defer sm.collabMux.Unlock()
+
+	if c, ok := sm.collaborations[sessionID]; ok {
+		return c, nil
+	}
+	members, err := sm.sessionStore.GetSessionMembers(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
-	return sess, nil
+	c := newCollaboration(members)
+	sm.collaborations[sessionID] = c
+	return c, nil
+}
+
+// authorize returns the members and the watchers of a session, or
+// ErrForbidden if the user can't do the action in it.
+func (sm *SessionManager) authorize(ctx context.Context, sessionID, user string, a action) (*collaboration, error) {
+	c, err := sm.collaboration(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.check(user, a); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetSession retrieves a session by ID, for a user who can watch it.
+func (sm *SessionManager) GetSession(ctx context.Context, id, user string) (*session.Session, error) {
+	if _, err := sm.authorize(ctx, id, user, actionWatch); err != nil {
+		return nil, err
+	}
+	return sm.sessionStore.GetSession(ctx, id)
+}
+
+// GetSessionMetadata retrieves a session by ID without its items, for a user
+// who can watch it.
+func (sm *SessionManager) GetSessionMetadata(ctx context.Context, id, user string) (*session.Session, error) {
+	if _, err := sm.authorize(ctx, id, user, actionWatch); err != nil {
+		return nil, err
+	}
+	return sm.sessionStore.GetSessionMetadata(ctx, id)
 }
 
-// CreateSession creates a new session from a template.
-func (sm *SessionManager) CreateSession(ctx context.Context, sessionTemplate *session.Session) (*session.Session, error) {
+// CreateSession creates a new session from a template. The user creating it,
+// if any, owns it: other users need a role to take part in it.
+func (sm *SessionManager) CreateSession(ctx context.Context, sessionTemplate *session.Session, user string) (*session.Session, error) {
 	var opts []session.Opt
 	opts = append(opts,
 		session.WithMaxIterations(sessionTemplate.MaxIterations),
 		session.WithToolsApproved(sessionTemplate.ToolsApproved),
 	)
 
-	if wd := strings.TrimSpace(sessionTemplate.WorkingDir); wd != "" {
+	var workingDirs []string
+	for _, wd := range sessionTemplate.Roots() {
+		wd = strings.TrimSpace(wd)
+		if wd == "" {
+			continue
+		}
 		absWd, err := filepath.Abs(wd)
 		if err != nil {
 			return nil, err
@@ -91,31 +171,59 @@ func (sm *SessionManager) CreateSession(ctx context.Context, sessionTemplate *se
 		if !info.IsDir() {
 			return nil, errors.New("working directory must be a directory")
 		}
-		opts = append(opts, session.WithWorkingDir(absWd))
+		workingDirs = append(workingDirs, absWd)
 	}
+	opts = append(opts, session.WithWorkingDirs(workingDirs...))
 
 	if sessionTemplate.Permissions != nil {
 		opts = append(opts, session.WithPermissions(sessionTemplate.Permissions))
 	}
 
 	sess := session.New(opts...)
-	return sess, sm.sessionStore.AddSession(ctx, sess)
+	if err := sm.sessionStore.AddSession(ctx, sess); err != nil {
+		return nil, err
+	}
+	members := session.Members{Owner: user}
+	if user != "" {
+		if err := sm.sessionStore.SetSessionMembers(ctx, sess.ID, members); err != nil {
+			return nil, err
+		}
+	}
+
+	sm.collabMux.Lock()
+	sm.collaborations[sess.ID] = newCollaboration(members)
+	sm.collabMux.Unlock()
+
+	return sess, nil
 }
 
-// GetSessions retrieves all sessions.
-func (sm *SessionManager) GetSessions(ctx context.Context) ([]*session.Session, error) {
-	sessions, err := sm.sessionStore.GetSessions(ctx)
-	if err != nil {
+// ListSessionSummaries retrieves a page of the metadata of the sessions
+// selected by the filter that the user can watch, without loading their
+// messages.
+func (sm *SessionManager) ListSessionSummaries(ctx context.Context, user string, page session.Page, filter session.SummaryFilter) ([]session.Summary, error) {
+	filter.Restricted = true
+	filter.Member = user
+	return sm.sessionStore.ListSessionSummaries(ctx, page, filter)
+}
+
+// GetSessionItems retrieves a page of the items of a session, for a user who
+// can watch it.
+func (sm *SessionManager) GetSessionItems(ctx context.Context, sessionID, user string, offset, limit int) ([]session.Item, error) {
+	if _, err := sm.authorize(ctx, sessionID, user, actionWatch); err != nil {
 		return nil, err
 	}
-	return sessions, nil
+	return sm.sessionStore.GetSessionItems(ctx, sessionID, offset, limit)
 }
 
-// DeleteSession deletes a session by ID.
-func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
+// DeleteSession deletes a session by ID. Only the owner of the session
+// deletes it.
+func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID, user string) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	if _, err := sm.authorize(ctx, sessionID, user, actionManage); err != nil {
+		return err
+	}
+	sess, err := sm.sessionStore.GetSessionMetadata(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -126,16 +234,95 @@ func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID string) e
 
 	if sessionRuntime, ok := sm.runtimeSessions.Load(sess.ID); ok {
 		sessionRuntime.cancel()
+		if ender, ok := sessionRuntime.runtime.(runtime.SessionEnder); ok {
+			ender.EndSession(ctx, sess)
+		}
 		sm.runtimeSessions.Delete(sess.ID)
 	}
 
+	sm.collabMux.Lock()
+	if c, ok := sm.collaborations[sess.ID]; ok {
+		c.close()
+		delete(sm.collaborations, sess.ID)
+	}
+	sm.collabMux.Unlock()
+
 	return nil
 }
 
-// RunSession runs a session with the given messages.
-func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilename, currentAgent string, messages []api.Message) (<-chan runtime.Event, error) {
+// WatchSession returns the events of the runs of a session, whoever starts
+// them, and a function to stop watching them.
+func (sm *SessionManager) WatchSession(ctx context.Context, sessionID, user string) (<-chan runtime.Event, func(), error) {
+	c, err := sm.authorize(ctx, sessionID, user, actionWatch)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, stop := c.watch()
+	return events, stop, nil
+}
+
+// SessionMembers returns the owner and the members of a session.
+func (sm *SessionManager) SessionMembers(ctx context.Context, sessionID, user string) ([]Member, error) {
+	c, err := sm.authorize(ctx, sessionID, user, actionWatch)
+	if err != nil {
+		return nil, err
+	}
+	return c.list(), nil
+}
+
+// SetSessionMember gives a role in a session to a member, or removes them
+// with an empty role. Only the owner of the session manages its members.
+func (sm *SessionManager) SetSessionMember(ctx context.Context, sessionID, user, member string, role Role) error {
+	c, err := sm.authorize(ctx, sessionID, user, actionManage)
+	if err != nil {
+		return err
+	}
+	return c.setMember(member, role, func(members session.Members) error {
+		return sm.sessionStore.SetSessionMembers(ctx, sessionID, members)
+	})
+}
+
+// ShareSession returns the token of the read-only link of a session. Only
+// the owner of the session shares it.
+func (sm *SessionManager) ShareSession(ctx context.Context, sessionID, user string) (string, error) {
+	if _, err := sm.authorize(ctx, sessionID, user, actionManage); err != nil {
+		return "", err
+	}
+	return sm.sessionStore.ShareSession(ctx, sessionID)
+}
+
+// UnshareSession revokes the read-only link of a session.
+func (sm *SessionManager) UnshareSession(ctx context.Context, sessionID, user string) error {
+	if _, err := sm.authorize(ctx, sessionID, user, actionManage); err != nil {
+		return err
+	}
+	return sm.sessionStore.UnshareSession(ctx, sessionID)
+}
+
+// GetSharedSession returns the session shared with a token.
+func (sm *SessionManager) GetSharedSession(ctx context.Context, token string) (*session.Session, error) {
+	return sm.sessionStore.GetSharedSession(ctx, token)
+}
+
+// RunSession runs a session with the given messages, on behalf of user.
+// With a scheduler, the run waits for a slot and RunQueued events tell its
+// position in the queue.
+func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilename, currentAgent, user string, messages []api.Message) (<-chan runtime.Event, error) {
+	if sm.scheduler != nil {
+		if err := sm.scheduler.checkQuota(user); err != nil {
+			return nil, err
+		}
+	}
+
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
+	if sm.draining.Load() {
+		return nil, ErrShuttingDown
+	}
+	collab, err := sm.authorize(ctx, sessionID, user, actionSend)
+	if err != nil {
+		return nil, err
+	}
 	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
 	if err != nil {
 		return nil, err
@@ -143,11 +330,16 @@ func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilena
 
 	rc := sm.runConfig.Clone()
 	rc.WorkingDir = sess.WorkingDir
+	if roots := sess.Roots(); len(roots) > 1 {
+		rc.AdditionalDirs = roots[1:]
+	}
 
 	// Collect user messages for potential title generation
 	var userMessages []string
 	for _, msg := range messages {
-		sess.AddMessage(session.UserMessage(msg.Content, msg.MultiContent...))
+		userMessage := session.UserMessage(msg.Content, msg.MultiContent...)
+		userMessage.User = user
+		sess.AddMessage(userMessage)
 		if msg.Content != "" {
 			userMessages = append(userMessages, msg.Content)
 		}
@@ -161,19 +353,13 @@ func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilena
 	streamCtx, cancel := context.WithCancel(ctx)
 	var titleGen *sessiontitle.Generator
 	if !exists {
-		var rt runtime.Runtime
-		rt, titleGen, err = sm.runtimeForSession(ctx, sess, agentFilename, currentAgent, rc)
+		runtimeSession, err = sm.runtimeForSession(ctx, sess, agentFilename, currentAgent, rc)
 		if err != nil {
 			cancel()
 			return nil, err
 		}
-		runtimeSession = &activeRuntimes{
-			runtime:  rt,
-			cancel:   cancel,
-			session:  sess,
-			titleGen: titleGen,
-		}
-		sm.runtimeSessions.Store(sessionID, runtimeSession)
+		runtimeSession.cancel = cancel
+		titleGen = runtimeSession.titleGen
 	} else {
 		// Update the session pointer in case it was reloaded
 		runtimeSession.session = sess
@@ -185,32 +371,65 @@ func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilena
 	// Check if we need to generate a title
 	needsTitle := sess.Title == "" && len(userMessages) > 0 && titleGen != nil
 
+	sm.runs.Add(1)
+	stopInterrupt := context.AfterFunc(sm.interrupted, cancel)
 	go func() {
 		// Start title generation in parallel if needed
 		if needsTitle {
 			go sm.generateTitle(ctx, sess, titleGen, userMessages, streamChan)
 		}
 
-		stream := runtimeSession.runtime.RunStream(streamCtx, sess)
+		defer sm.runs.Done()
+		defer stopInterrupt()
 		defer cancel()
 		defer close(streamChan)
+
+		if sm.scheduler != nil {
+			run, err := sm.scheduler.acquire(streamCtx, user, func(position int) {
+				select {
+				case streamChan <- runtime.RunQueued(sessionID, position):
+				case <-streamCtx.Done():
+				}
+			})
+			if err != nil {
+				if streamCtx.Err() == nil {
+					streamChan <- runtime.Error(err.Error())
+				}
+				return
+			}
+			tokensBefore := sess.InputTokens + sess.OutputTokens
+			defer func() {
+				run.release(sess.InputTokens + sess.OutputTokens - tokensBefore)
+			}()
+		}
+
+		stream := runtimeSession.runtime.RunStream(streamCtx, sess)
 		for event := range stream {
+			// Keep reading until the runtime stops, so that the session
+			// is saved with the outcome of its last tool calls.
 			if streamCtx.Err() != nil {
-				return
+				continue
 			}
+			collab.broadcast(sessionID, event)
 			streamChan <- event
 		}
 
-		if err := sm.sessionStore.UpdateSession(ctx, sess); err != nil {
-			return
+		if sm.interrupted.Err() != nil && ctx.Err() == nil {
+			streamChan <- runtime.Warning("The server is shutting down: the run was interrupted and can be continued by sending a new message", "")
+		}
+
+		// Save the session even if the run was interrupted.
+		if err := sm.sessionStore.UpdateSession(context.WithoutCancel(ctx), sess); err != nil {
+			slog.Error("Failed to save session", "session_id", sessionID, "error", err)
 		}
 	}()
 
 	return streamChan, nil
 }
 
-// ResumeSession resumes a paused session with an optional rejection reason or tool name.
-func (sm *SessionManager) ResumeSession(ctx context.Context, sessionID, confirmation, reason, toolName string) error {
+// ResumeSession resumes a paused session, on behalf of user, with an optional
+// rejection reason, tool name or number of iterations to continue for.
+func (sm *SessionManager) ResumeSession(ctx context.Context, sessionID, user, confirmation, reason, toolName string, iterations int) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
 
@@ -219,32 +438,45 @@ func (sm *SessionManager) ResumeSession(ctx context.Context, sessionID, confirma
 	if !exists {
 		return errors.New("session not found")
 	}
+	if _, err := sm.authorize(ctx, sessionID, user, actionAnswer); err != nil {
+		return err
+	}
 
 	rt.runtime.Resume(ctx, runtime.ResumeRequest{
-		Type:     runtime.ResumeType(confirmation),
-		Reason:   reason,
-		ToolName: toolName,
+		Type:       runtime.ResumeType(confirmation),
+		Reason:     reason,
+		ToolName:   toolName,
+		Iterations: iterations,
+		User:       user,
 	})
 	return nil
 }
 
-// ResumeElicitation resumes an elicitation request.
-func (sm *SessionManager) ResumeElicitation(ctx context.Context, sessionID, action string, content map[string]any) error {
+// ResumeElicitation resumes an elicitation request on behalf of user.
+func (sm *SessionManager) ResumeElicitation(ctx context.Context, sessionID, user, action string, content map[string]any) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
 	rt, exists := sm.runtimeSessions.Load(sessionID)
 	if !exists {
 		return errors.New("session not found")
 	}
+	if _, err := sm.authorize(ctx, sessionID, user, actionAnswer); err != nil {
+		return err
+	}
 
 	return rt.runtime.ResumeElicitation(ctx, tools.ElicitationAction(action), content)
 }
 
-// ToggleToolApproval toggles the tool approval mode for a session.
-func (sm *SessionManager) ToggleToolApproval(ctx context.Context, sessionID string) error {
+// ToggleToolApproval toggles the tool approval mode for a session. Only the
+// owner of the session toggles it, since it approves the tool calls of every
+// run.
+func (sm *SessionManager) ToggleToolApproval(ctx context.Context, sessionID, user string) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	if _, err := sm.authorize(ctx, sessionID, user, actionManage); err != nil {
+		return err
+	}
+	sess, err := sm.sessionStore.GetSessionMetadata(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -254,11 +486,15 @@ func (sm *SessionManager) ToggleToolApproval(ctx context.Context, sessionID stri
 	return sm.sessionStore.UpdateSession(ctx, sess)
 }
 
-// ToggleThinking toggles the thinking mode for a session.
-func (sm *SessionManager) ToggleThinking(ctx context.Context, sessionID string) error {
+// ToggleThinking toggles the thinking mode for a session, for a user who can
+// send messages to it.
+func (sm *SessionManager) ToggleThinking(ctx context.Context, sessionID, user string) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	if _, err := sm.authorize(ctx, sessionID, user, actionSend); err != nil {
+		return err
+	}
+	sess, err := sm.sessionStore.GetSessionMetadata(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -268,11 +504,15 @@ func (sm *SessionManager) ToggleThinking(ctx context.Context, sessionID string)
 	return sm.sessionStore.UpdateSession(ctx, sess)
 }
 
-// UpdateSessionPermissions updates the permissions for a session.
-func (sm *SessionManager) UpdateSessionPermissions(ctx context.Context, sessionID string, perms *session.PermissionsConfig) error {
+// UpdateSessionPermissions updates the permissions for a session. Only the
+// owner of the session updates them.
+func (sm *SessionManager) UpdateSessionPermissions(ctx context.Context, sessionID, user string, perms *session.PermissionsConfig) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	if _, err := sm.authorize(ctx, sessionID, user, actionManage); err != nil {
+		return err
+	}
+	sess, err := sm.sessionStore.GetSessionMetadata(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -282,12 +522,16 @@ func (sm *SessionManager) UpdateSessionPermissions(ctx context.Context, sessionI
 	return sm.sessionStore.UpdateSession(ctx, sess)
 }
 
-// UpdateSessionTitle updates the title for a session.
+// UpdateSessionTitle updates the title for a session, for a user who can send
+// messages to it.
 // If the session is actively running, it also updates the in-memory session
 // object to prevent subsequent runtime saves from overwriting the title.
-func (sm *SessionManager) UpdateSessionTitle(ctx context.Context, sessionID, title string) error {
+func (sm *SessionManager) UpdateSessionTitle(ctx context.Context, sessionID, user, title string) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
+	if _, err := sm.authorize(ctx, sessionID, user, actionSend); err != nil {
+		return err
+	}
 
 	// If session is actively running, update the in-memory session object directly.
 	// This ensures the runtime's saveSession won't overwrite our manual edit.
@@ -298,7 +542,7 @@ func (sm *SessionManager) UpdateSessionTitle(ctx context.Context, sessionID, tit
 	}
 
 	// Session is not actively running, load from store and update
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	sess, err := sm.sessionStore.GetSessionMetadata(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -307,6 +551,42 @@ func (sm *SessionManager) UpdateSessionTitle(ctx context.Context, sessionID, tit
 	return sm.sessionStore.UpdateSession(ctx, sess)
 }
 
+// RegenerateSessionTitle generates a new title for a session from its user
+// messages, and persists it. The session must have been run, so that its
+// agent is known.
+func (sm *SessionManager) RegenerateSessionTitle(ctx context.Context, sessionID, user string) (string, error) {
+	if _, err := sm.authorize(ctx, sessionID, user, actionSend); err != nil {
+		return "", err
+	}
+	rt, ok := sm.runtimeSessions.Load(sessionID)
+	if !ok || rt.session == nil {
+		return "", errors.New("session is not running: titles can only be regenerated once the session has run")
+	}
+	if rt.titleGen == nil {
+		return "", errors.New("title generation is disabled")
+	}
+
+	var userMessages []string
+	for _, msg := range rt.session.GetAllMessages() {
+		if msg.Message.Role == chat.MessageRoleUser && msg.Message.Content != "" {
+			userMessages = append(userMessages, msg.Message.Content)
+		}
+	}
+
+	title, err := rt.titleGen.Generate(ctx, sessionID, userMessages)
+	if err != nil {
+		return "", err
+	}
+	if title == "" {
+		return "", errors.New("no title was generated")
+	}
+
+	if err := sm.UpdateSessionTitle(ctx, sessionID, user, title); err != nil {
+		return "", err
+	}
+	return title, nil
+}
+
 // generateTitle generates a title for a session using the sessiontitle package.
 // The generated title is stored in the session and persisted to the store.
 // A SessionTitleEvent is emitted to notify clients.
@@ -343,20 +623,20 @@ func (sm *SessionManager) generateTitle(ctx context.Context, sess *session.Sessi
 	}
 }
 
-func (sm *SessionManager) runtimeForSession(ctx context.Context, sess *session.Session, agentFilename, currentAgent string, rc *config.RuntimeConfig) (runtime.Runtime, *sessiontitle.Generator, error) {
+func (sm *SessionManager) runtimeForSession(ctx context.Context, sess *session.Session, agentFilename, currentAgent string, rc *config.RuntimeConfig) (*activeRuntimes, error) {
 	rt, exists := sm.runtimeSessions.Load(sess.ID)
 	if exists && rt.runtime != nil {
-		return rt.runtime, rt.titleGen, nil
+		return rt, nil
 	}
 
 	t, err := sm.loadTeam(ctx, agentFilename, rc)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	agent, err := t.Agent(currentAgent)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	sess.MaxIterations = agent.MaxIterations()
 	// Initialize thinking state based on whether thinking_budget was explicitly configured
@@ -370,20 +650,73 @@ func (sm *SessionManager) runtimeForSession(ctx context.Context, sess *session.S
 	}
 	run, err := runtime.New(t, opts...)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	titleGen := sessiontitle.New(agent.Model(), agent.FallbackModels()...)
-
-	sm.runtimeSessions.Store(sess.ID, &activeRuntimes{
+	rt = &activeRuntimes{
 		runtime:  run,
+		team:     t,
 		session:  sess,
-		titleGen: titleGen,
-	})
+		titleGen: run.TitleGenerator(),
+	}
+	sm.runtimeSessions.Store(sess.ID, rt)
 
 	slog.Debug("Runtime created for session", "session_id", sess.ID)
 
-	return run, titleGen, nil
+	return rt, nil
+}
+
+// Drain rejects new runs and waits, for at most timeout, for the runs going
+// on to finish. The runs still going on after that are interrupted and their
+// sessions saved, so that they can be continued later. The toolsets of all
+// the sessions are then stopped, which stops the MCP servers they started.
+func (sm *SessionManager) Drain(ctx context.Context, timeout time.Duration) {
+	sm.mux.Lock()
+	sm.draining.Store(true)
+	sm.mux.Unlock()
+
+	if !waitTimeout(&sm.runs, timeout) {
+		slog.Warn("Interrupting the runs still going on", "timeout", timeout)
+		sm.interrupt()
+		if !waitTimeout(&sm.runs, interruptGracePeriod) {
+			slog.Error("Some runs didn't stop after being interrupted")
+		}
+	}
+
+	sm.runtimeSessions.Range(func(sessionID string, rt *activeRuntimes) bool {
+		if ender, ok := rt.runtime.(runtime.SessionEnder); ok && rt.session != nil {
+			ender.EndSession(ctx, rt.session)
+		}
+		if rt.team != nil {
+			if err := rt.team.StopToolSets(ctx); err != nil {
+				slog.Error("Failed to stop tool sets", "session_id", sessionID, "error", err)
+			}
+		}
+		return true
+	})
+
+	// The runs are over: stop the watchers of the sessions.
+	sm.collabMux.Lock()
+	for _, c := range sm.collaborations {
+		c.close()
+	}
+	sm.collabMux.Unlock()
+}
+
+// waitTimeout waits for wg, for at most timeout. It returns false on timeout.
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
 }
 
 func (sm *SessionManager) loadTeam(ctx context.Context, agentFilename string, runConfig *config.RuntimeConfig) (*team.Team, error) {
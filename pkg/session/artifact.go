@@ -0,0 +1,67 @@
+package session
+
+import (
+	"slices"
+	"time"
+)
+
+// Artifact is a file produced during a session, such as a report, an image
+// or a patch, that a tool registered so that it can be listed and retrieved
+// once the session is over.
+type Artifact struct {
+	// ID identifies the artifact within its session.
+	ID string `json:"id"`
+	// Name is the base name of the file.
+	Name string `json:"name"`
+	// Path is the absolute path of the file.
+	Path string `json:"path"`
+	// MimeType is the media type of the file, when known.
+	MimeType string `json:"mime_type,omitempty"`
+	// Size is the size of the file in bytes, when it was registered.
+	Size int64 `json:"size"`
+	// Description tells what the file contains.
+	Description string `json:"description,omitempty"`
+	// ToolName is the name of the tool that registered the artifact.
+	ToolName string `json:"tool_name,omitempty"`
+	// AgentName is the name of the agent that called the tool.
+	AgentName string `json:"agent_name,omitempty"`
+	// CreatedAt is the time the artifact was registered.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddArtifact registers an artifact with the session and returns it. An
+// artifact with the same path replaces the one registered before and keeps
+// its ID, so that a file written several times is only listed once.
+func (s *Session) AddArtifact(artifact Artifact) Artifact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := slices.IndexFunc(s.Artifacts, func(a Artifact) bool { return a.Path == artifact.Path }); i >= 0 {
+		artifact.ID = s.Artifacts[i].ID
+		s.Artifacts[i] = artifact
+		return artifact
+	}
+	s.Artifacts = append(s.Artifacts, artifact)
+	return artifact
+}
+
+// GetArtifacts returns a copy of the artifacts of the session.
+func (s *Session) GetArtifacts() []Artifact {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.Artifacts)
+}
+
+// GetArtifact returns the artifact with the given ID.
+func (s *Session) GetArtifact(id string) (Artifact, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.Artifacts {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return Artifact{}, false
+}
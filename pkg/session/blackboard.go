@@ -0,0 +1,96 @@
+package session
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// BlackboardEntry is a finding an agent of a team posted on the blackboard
+// of the session, for the other agents of the run to read.
+type BlackboardEntry struct {
+	// Key identifies the entry on the blackboard.
+	Key string `json:"key"`
+	// Value is the content of the finding.
+	Value string `json:"value"`
+	// AgentName is the name of the agent that last wrote the entry.
+	AgentName string `json:"agent_name,omitempty"`
+	// Version is incremented each time the entry is written, starting at 1.
+	Version int `json:"version"`
+	// CreatedAt is the time the entry was first posted.
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the time the entry was last written.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BlackboardSession returns the session holding the blackboard of the team
+// run the session is part of: the session the user started, for the
+// sub-sessions of the agents it delegated to.
+func (s *Session) BlackboardSession() *Session {
+	if s.blackboardOwner != nil {
+		return s.blackboardOwner
+	}
+	return s
+}
+
+// WithBlackboardOf makes the sub-session share the blackboard of the team run
+// of its parent.
+func WithBlackboardOf(parent *Session) Opt {
+	return func(s *Session) {
+		s.blackboardOwner = parent.BlackboardSession()
+	}
+}
+
+// PostToBlackboard writes an entry of the blackboard of the session, adding
+// it or replacing the value of the entry with the same key, and returns it.
+func (s *Session) PostToBlackboard(key, value, agentName string) BlackboardEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if i := slices.IndexFunc(s.Blackboard, func(e BlackboardEntry) bool { return e.Key == key }); i >= 0 {
+		entry := &s.Blackboard[i]
+		entry.Value = value
+		entry.AgentName = agentName
+		entry.Version++
+		entry.UpdatedAt = now
+		return *entry
+	}
+
+	entry := BlackboardEntry{
+		Key:       key,
+		Value:     value,
+		AgentName: agentName,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.Blackboard = append(s.Blackboard, entry)
+	return entry
+}
+
+// RemoveFromBlackboard removes an entry of the blackboard of the session. It
+// returns false if there was no entry with the key.
+func (s *Session) RemoveFromBlackboard(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.Blackboard)
+	s.Blackboard = slices.DeleteFunc(s.Blackboard, func(e BlackboardEntry) bool { return e.Key == key })
+	return len(s.Blackboard) != n
+}
+
+// GetBlackboard returns a copy of the entries of the blackboard of the
+// session whose key starts with prefix, in the order they were posted.
+func (s *Session) GetBlackboard(prefix string) []BlackboardEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []BlackboardEntry
+	for _, e := range s.Blackboard {
+		if strings.HasPrefix(e.Key, prefix) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
@@ -20,17 +20,56 @@ func BranchSession(parent *Session, branchAtPosition int) (*Session, error) {
 		return nil, fmt.Errorf("branch position %d out of range", branchAtPosition)
 	}
 
+	return branchSession(parent, branchAtPosition, nil)
+}
+
+// BranchSessionWithToolResult creates a new session branched from the parent
+// to re-run it from the tool result at the given position, with the content
+// of that result replaced. The results of the other tool calls of the same
+// assistant message are kept, so that all its tool calls have a result.
+func BranchSessionWithToolResult(parent *Session, position int, content string) (*Session, error) {
+	if parent == nil {
+		return nil, errors.New("parent session is nil")
+	}
+	if position < 0 || position >= len(parent.Messages) {
+		return nil, fmt.Errorf("branch position %d out of range", position)
+	}
+	if item := parent.Messages[position]; item.Message == nil || item.Message.Message.Role != chat.MessageRoleTool {
+		return nil, fmt.Errorf("item at position %d is not a tool result", position)
+	}
+
+	end := position + 1
+	for end < len(parent.Messages) {
+		item := parent.Messages[end]
+		if item.Message == nil || item.Message.Message.Role != chat.MessageRoleTool {
+			break
+		}
+		end++
+	}
+
+	return branchSession(parent, end, func(branched *Session) {
+		branched.Messages[position].Message.Message.Content = content
+		branched.Messages[position].Message.Message.MultiContent = nil
+	})
+}
+
+// branchSession clones the first n items of the parent into a new session,
+// and lets edit change them before the totals are computed.
+func branchSession(parent *Session, n int, edit func(*Session)) (*Session, error) {
 	branched := New()
 	copySessionMetadata(branched, parent, generateBranchTitle(parent.Title))
 
-	branched.Messages = make([]Item, 0, branchAtPosition)
-	for i := range branchAtPosition {
+	branched.Messages = make([]Item, 0, n)
+	for i := range n {
 		cloned, err := cloneSessionItem(parent.Messages[i])
 		if err != nil {
 			return nil, err
 		}
 		branched.Messages = append(branched.Messages, cloned)
 	}
+	if edit != nil {
+		edit(branched)
+	}
 
 	setParentIDs(branched)
 	recalculateSessionTotals(branched)
@@ -49,6 +88,9 @@ func cloneSessionItem(item Item) (Item, error) {
 		return Item{SubSession: clonedSub}, nil
 	case item.Summary != "":
 		return Item{Summary: item.Summary, Cost: item.Cost}, nil
+	case item.Record != nil:
+		record := *item.Record
+		return Item{Record: &record}, nil
 	default:
 		return Item{}, errors.New("cannot clone empty session item")
 	}
@@ -83,14 +125,19 @@ func copySessionMetadata(dst, src *Session, title string) {
 	dst.Title = title
 	dst.ToolsApproved = src.ToolsApproved
 	dst.Thinking = src.Thinking
+	dst.Simulate = src.Simulate
 	dst.HideToolResults = src.HideToolResults
 	dst.WorkingDir = src.WorkingDir
+	dst.WorkingDirs = slices.Clone(src.WorkingDirs)
 	dst.SendUserMessage = src.SendUserMessage
 	dst.MaxIterations = src.MaxIterations
 	dst.Starred = src.Starred
 	dst.Permissions = clonePermissionsConfig(src.Permissions)
 	dst.AgentModelOverrides = cloneStringMap(src.AgentModelOverrides)
 	dst.CustomModelsUsed = cloneStringSlice(src.CustomModelsUsed)
+	dst.Artifacts = slices.Clone(src.Artifacts)
+	dst.Blackboard = slices.Clone(src.Blackboard)
+	dst.ConfigHash = src.ConfigHash
 }
 
 // generateBranchTitle creates a title for a branched session based on the parent title.
@@ -5,6 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
 )
 
 func TestGenerateBranchTitle(t *testing.T) {
@@ -140,3 +143,53 @@ func TestBranchSession(t *testing.T) {
 		assert.Equal(t, "msg2", branched.Messages[1].Message.Message.Content)
 	})
 }
+
+func TestBranchSessionWithToolResult(t *testing.T) {
+	toolResult := func(id, content string) Item {
+		return NewMessageItem(&Message{Message: chat.Message{Role: chat.MessageRoleTool, ToolCallID: id, Content: content}})
+	}
+	parent := &Session{
+		Title: "Parent Title",
+		Messages: []Item{
+			NewMessageItem(UserMessage("list the files")),
+			NewMessageItem(NewAgentMessage("root", &chat.Message{
+				Role:      chat.MessageRoleAssistant,
+				ToolCalls: []tools.ToolCall{{ID: "call_1"}, {ID: "call_2"}},
+			})),
+			toolResult("call_1", "a.txt"),
+			toolResult("call_2", "b.txt"),
+			NewMessageItem(NewAgentMessage("root", &chat.Message{Role: chat.MessageRoleAssistant, Content: "a.txt and b.txt"})),
+		},
+	}
+
+	t.Run("replaces the result and keeps the other results", func(t *testing.T) {
+		branched, err := BranchSessionWithToolResult(parent, 2, "c.txt")
+		require.NoError(t, err)
+
+		assert.Equal(t, "Parent Title (branched)", branched.Title)
+		require.Len(t, branched.Messages, 4)
+		assert.Equal(t, "c.txt", branched.Messages[2].Message.Message.Content)
+		assert.Equal(t, "b.txt", branched.Messages[3].Message.Message.Content)
+		assert.Equal(t, "a.txt", parent.Messages[2].Message.Message.Content, "the parent is unchanged")
+	})
+
+	t.Run("last tool result", func(t *testing.T) {
+		branched, err := BranchSessionWithToolResult(parent, 3, "d.txt")
+		require.NoError(t, err)
+
+		require.Len(t, branched.Messages, 4)
+		assert.Equal(t, "d.txt", branched.Messages[3].Message.Message.Content)
+	})
+
+	t.Run("not a tool result", func(t *testing.T) {
+		_, err := BranchSessionWithToolResult(parent, 1, "c.txt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a tool result")
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := BranchSessionWithToolResult(parent, 5, "c.txt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "out of range")
+	})
+}
@@ -0,0 +1,121 @@
+package session
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/secretkey"
+)
+
+// EncryptionKeyEnv is the environment variable holding the base64-encoded
+// key encrypting the sessions at rest.
+const EncryptionKeyEnv = "DOCKER_AGENT_SESSION_KEY"
+
+// encryptionKeyService is the name of the encryption key of the sessions in
+// the system keychain.
+const encryptionKeyService = "docker-agent-sessions"
+
+// encryptedPrefix marks the values encrypted in the database. Values without
+// it were written before the encryption was enabled and are read as is.
+const encryptedPrefix = "enc:v1:"
+
+// ErrEncrypted is returned when reading an encrypted session without the key
+// it was encrypted with.
+var ErrEncrypted = errors.New("the session is encrypted: set " + EncryptionKeyEnv + " or enable encrypt_sessions to read it")
+
+// SQLiteOpt configures a SQLite session store.
+type SQLiteOpt func(*SQLiteSessionStore) error
+
+// WithEncryptionKey encrypts the messages, summaries and records of the
+// sessions with a 32 bytes AES key. A nil key leaves them unencrypted.
+func WithEncryptionKey(key []byte) SQLiteOpt {
+	return func(s *SQLiteSessionStore) error {
+		if key == nil {
+			return nil
+		}
+		if len(key) != secretkey.Size {
+			return fmt.Errorf("the session encryption key must be %d bytes, got %d", secretkey.Size, len(key))
+		}
+		s.key = key
+		return nil
+	}
+}
+
+// EncryptionKey returns the key encrypting the sessions: the one of the
+// DOCKER_AGENT_SESSION_KEY environment variable, or else, when useKeychain is
+// set, a key kept in the system keychain, or in a file of dir when there's no
+// keychain. It returns nil when the sessions aren't encrypted.
+func EncryptionKey(useKeychain bool, dir string) ([]byte, error) {
+	if encoded := os.Getenv(EncryptionKeyEnv); encoded != "" {
+		key, err := secretkey.Decode([]byte(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", EncryptionKeyEnv, err)
+		}
+		return key, nil
+	}
+	if !useKeychain {
+		return nil, nil
+	}
+
+	keyring := secretkey.Default(encryptionKeyService, "docker-agent session encryption key", filepath.Join(dir, "session.key"))
+	key, err := secretkey.GetOrGenerate(keyring)
+	if err != nil {
+		return nil, fmt.Errorf("reading the session encryption key: %w", err)
+	}
+	return key, nil
+}
+
+// seal encrypts a value written to the database, if the store is encrypted.
+func (s *SQLiteSessionStore) seal(value string) (string, error) {
+	if s.key == nil {
+		return value, nil
+	}
+	data, err := secretkey.Encrypt(s.key, []byte(value))
+	if err != nil {
+		return "", fmt.Errorf("encrypting: %w", err)
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// sealJSON encodes a value to JSON and encrypts it, if the store is encrypted.
+func (s *SQLiteSessionStore) sealJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return s.seal(string(data))
+}
+
+// sealBlackboard encodes the blackboard of a session, and encrypts it if the
+// store is encrypted.
+func (s *SQLiteSessionStore) sealBlackboard(entries []BlackboardEntry) (string, error) {
+	if len(entries) == 0 {
+		return "[]", nil
+	}
+	return s.sealJSON(entries)
+}
+
+// open decrypts a value read from the database, if it's encrypted.
+func (s *SQLiteSessionStore) open(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return value, nil
+	}
+	if s.key == nil {
+		return "", ErrEncrypted
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decoding: %w", err)
+	}
+	plaintext, err := secretkey.Decrypt(s.key, data)
+	if err != nil {
+		return "", fmt.Errorf("decrypting with the session encryption key: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestSQLiteSessionStore_Encryption(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_encryption.db")
+	key := newKey(t)
+
+	// A session written before the encryption was enabled
+	plain, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	require.NoError(t, plain.AddSession(t.Context(), &Session{ID: "plain", CreatedAt: time.Now()}))
+	_, err = plain.AddMessage(t.Context(), "plain", UserMessage("written in clear"))
+	require.NoError(t, err)
+	require.NoError(t, plain.(*SQLiteSessionStore).Close())
+
+	store, err := NewSQLiteSessionStore(tempDB, WithEncryptionKey(key))
+	require.NoError(t, err)
+	require.NoError(t, store.AddSession(t.Context(), &Session{
+		ID:         "secret",
+		CreatedAt:  time.Now(),
+		Blackboard: []BlackboardEntry{{Key: "codes", Value: "the launch codes", Version: 1}},
+	}))
+	_, err = store.AddMessage(t.Context(), "secret", UserMessage("the launch codes"))
+	require.NoError(t, err)
+	require.NoError(t, store.AddSummary(t.Context(), "secret", "a summary of the launch codes"))
+	require.NoError(t, store.AddRecord(t.Context(), "secret", &Record{
+		AgentName:    "root",
+		CreatedAt:    time.Now(),
+		ToolApproval: &ToolApprovalRecord{ToolName: "shell", Arguments: `{"cmd":"launch codes"}`, Decision: "approve"},
+	}))
+
+	loaded, err := store.GetSession(t.Context(), "secret")
+	require.NoError(t, err)
+	require.Len(t, loaded.Messages, 3)
+	assert.Equal(t, "the launch codes", loaded.Messages[0].Message.Message.Content)
+	assert.Equal(t, "a summary of the launch codes", loaded.Messages[1].Summary)
+	assert.Equal(t, "approve", loaded.Messages[2].Record.ToolApproval.Decision)
+	require.Len(t, loaded.Blackboard, 1)
+	assert.Equal(t, "the launch codes", loaded.Blackboard[0].Value)
+
+	loaded, err = store.GetSession(t.Context(), "plain")
+	require.NoError(t, err)
+	require.Len(t, loaded.Messages, 1)
+	assert.Equal(t, "written in clear", loaded.Messages[0].Message.Message.Content)
+
+	// Nothing is readable in the database
+	db := store.(*SQLiteSessionStore).db
+	var count int
+	require.NoError(t, db.QueryRowContext(t.Context(),
+		`SELECT COUNT(*) FROM session_items
+		 WHERE message_json LIKE '%launch codes%' OR summary_text LIKE '%launch codes%' OR record_json LIKE '%launch codes%'`).Scan(&count))
+	assert.Zero(t, count)
+	require.NoError(t, db.QueryRowContext(t.Context(),
+		`SELECT COUNT(*) FROM sessions WHERE messages LIKE '%launch codes%' OR blackboard LIKE '%launch codes%'`).Scan(&count))
+	assert.Zero(t, count)
+	require.NoError(t, store.(*SQLiteSessionStore).Close())
+
+	// Without the key, or with another one, the session can't be read
+	noKey, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer noKey.(*SQLiteSessionStore).Close()
+	_, err = noKey.GetSession(t.Context(), "secret")
+	require.ErrorIs(t, err, ErrEncrypted)
+
+	otherKey, err := NewSQLiteSessionStore(tempDB, WithEncryptionKey(newKey(t)))
+	require.NoError(t, err)
+	defer otherKey.(*SQLiteSessionStore).Close()
+	_, err = otherKey.GetSession(t.Context(), "secret")
+	require.ErrorContains(t, err, "decrypting")
+}
+
+func TestWithEncryptionKey_InvalidSize(t *testing.T) {
+	_, err := NewSQLiteSessionStore(filepath.Join(t.TempDir(), "test.db"), WithEncryptionKey([]byte("short")))
+	require.ErrorContains(t, err, "must be 32 bytes")
+}
+
+func TestEncryptionKey(t *testing.T) {
+	key, err := EncryptionKey(false, t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	t.Setenv(EncryptionKeyEnv, "not a key")
+	_, err = EncryptionKey(false, t.TempDir())
+	require.ErrorContains(t, err, EncryptionKeyEnv)
+}
@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"maps"
+)
+
+// Members are the users of a session shared by several users: its owner,
+// who created it, and the roles given to the other users. Sessions without
+// owner, created when the server doesn't identify its users, are open to
+// everyone.
+type Members struct {
+	Owner string            `json:"owner,omitempty"`
+	Roles map[string]string `json:"roles,omitempty"`
+}
+
+// Includes reports whether the user owns the session or has a role in it.
+func (m Members) Includes(user string) bool {
+	if m.Owner == "" || user == m.Owner {
+		return true
+	}
+	_, ok := m.Roles[user]
+	return ok
+}
+
+// GetSessionMembers returns the owner and the members of a session.
+func (s *InMemorySessionStore) GetSessionMembers(_ context.Context, id string) (Members, error) {
+	if id == "" {
+		return Members{}, ErrEmptyID
+	}
+	if _, exists := s.sessions.Load(id); !exists {
+		return Members{}, ErrNotFound
+	}
+	members, _ := s.members.Load(id)
+	members.Roles = maps.Clone(members.Roles)
+	return members, nil
+}
+
+// SetSessionMembers replaces the owner and the members of a session.
+func (s *InMemorySessionStore) SetSessionMembers(_ context.Context, id string, members Members) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	if _, exists := s.sessions.Load(id); !exists {
+		return ErrNotFound
+	}
+	members.Roles = maps.Clone(members.Roles)
+	s.members.Store(id, members)
+	return nil
+}
+
+// GetSessionMembers returns the owner and the members of a session.
+func (s *SQLiteSessionStore) GetSessionMembers(ctx context.Context, id string) (Members, error) {
+	if id == "" {
+		return Members{}, ErrEmptyID
+	}
+
+	var membersJSON sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT members FROM sessions WHERE id = ?", id).Scan(&membersJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Members{}, ErrNotFound
+	}
+	if err != nil {
+		return Members{}, err
+	}
+
+	var members Members
+	if membersJSON.Valid && membersJSON.String != "" {
+		if err := json.Unmarshal([]byte(membersJSON.String), &members); err != nil {
+			return Members{}, err
+		}
+	}
+	return members, nil
+}
+
+// SetSessionMembers replaces the owner and the members of a session.
+func (s *SQLiteSessionStore) SetSessionMembers(ctx context.Context, id string, members Members) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	membersJSON, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.ExecContext(ctx, "UPDATE sessions SET members = ? WHERE id = ?", string(membersJSON), id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
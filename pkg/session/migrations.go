@@ -344,6 +344,64 @@ func getAllMigrations() []Migration {
 				ALTER TABLE sessions DROP COLUMN split_diff_view;
 			`,
 		},
+		{
+			ID:          20,
+			Name:        "020_add_working_dirs_column",
+			Description: "Add working_dirs column to sessions table for multi-root workspaces",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN working_dirs TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN working_dirs`,
+		},
+		{
+			ID:          21,
+			Name:        "021_add_artifacts_column",
+			Description: "Add artifacts column to sessions table for the files registered by tools",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN artifacts TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN artifacts`,
+		},
+		{
+			ID:          22,
+			Name:        "022_add_config_hash_column",
+			Description: "Add config_hash column to sessions table for the version of the agent configuration",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN config_hash TEXT DEFAULT ''`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN config_hash`,
+		},
+		{
+			ID:          23,
+			Name:        "023_add_session_items_record_column",
+			Description: "Add record_json column to session_items table for approvals, elicitations, model switches and compactions",
+			UpSQL:       `ALTER TABLE session_items ADD COLUMN record_json TEXT`,
+			DownSQL:     `ALTER TABLE session_items DROP COLUMN record_json`,
+		},
+		{
+			ID:          25,
+			Name:        "025_add_session_items_user_column",
+			Description: "Add user_name column to session_items table for the users who sent the messages of shared sessions",
+			UpSQL:       `ALTER TABLE session_items ADD COLUMN user_name TEXT`,
+			DownSQL:     `ALTER TABLE session_items DROP COLUMN user_name`,
+		},
+		{
+			ID:          26,
+			Name:        "026_add_share_token_column",
+			Description: "Add share_token column to sessions table for the read-only links of the shared sessions",
+			UpSQL: `
+				ALTER TABLE sessions ADD COLUMN share_token TEXT;
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_share_token ON sessions(share_token);
+			`,
+		},
+		{
+			ID:          27,
+			Name:        "027_add_blackboard_column",
+			Description: "Add blackboard column to sessions table for the findings the agents of a team share",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN blackboard TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN blackboard`,
+		},
+		{
+			ID:          28,
+			Name:        "028_add_members_column",
+			Description: "Add members column to sessions table for the owners and the members of the shared sessions",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN members TEXT`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN members`,
+		},
 	}
 }
 
@@ -0,0 +1,151 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Record is an interaction of the session that isn't a message of the
+// conversation: a decision of the user on a tool call, an answer to an
+// elicitation, an escalation to a human, a model switch or a compaction. Records keep the history of a
+// session complete, for exports and audits, and are never sent to the model.
+// Exactly one of the kinds of records is set.
+type Record struct {
+	// AgentName is the name of the agent the record is about.
+	AgentName string `json:"agent_name,omitempty"`
+	// CreatedAt is the time of the interaction.
+	CreatedAt time.Time `json:"created_at"`
+	// User is the user who made the decision, in sessions shared by several
+	// users of the API server.
+	User string `json:"user,omitempty"`
+
+	ToolApproval *ToolApprovalRecord `json:"tool_approval,omitempty"`
+	Elicitation  *ElicitationRecord  `json:"elicitation,omitempty"`
+	Escalation   *EscalationRecord   `json:"escalation,omitempty"`
+	ModelSwitch  *ModelSwitchRecord  `json:"model_switch,omitempty"`
+	Compaction   *CompactionRecord   `json:"compaction,omitempty"`
+}
+
+// ToolApprovalRecord is the answer of the user to a tool call that needed
+// their confirmation.
+type ToolApprovalRecord struct {
+	ToolCallID string `json:"tool_call_id"`
+	ToolName   string `json:"tool_name"`
+	Arguments  string `json:"arguments,omitempty"`
+	// Decision is the answer of the user, as a resume type of the runtime:
+	// approve, approve-session, approve-tool, approve-always or reject.
+	Decision string `json:"decision"`
+	// Pattern is the tool or pattern the user allowed from then on, for the
+	// approve-tool and approve-always decisions.
+	Pattern string `json:"pattern,omitempty"`
+	// Reason is the reason the user gave for rejecting the tool call.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ElicitationRecord is the answer of the user to a request for input of a
+// tool, e.g. an MCP server. Only the names of the fields the user filled in
+// are kept, their values can be secrets.
+type ElicitationRecord struct {
+	Message string `json:"message"`
+	URL     string `json:"url,omitempty"`
+	// Action is accept, decline or cancel.
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// EscalationRecord is a handover of the agent to a human, when it couldn't go
+// on by itself. The answer of the human follows as an elicitation record.
+type EscalationRecord struct {
+	Reason string `json:"reason"`
+}
+
+// ModelSwitchRecord is a change of the model of an agent.
+type ModelSwitchRecord struct {
+	// From and To are the models before and after the switch. An empty
+	// model is the default model of the agent.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Reason is why the model changed: "user" when the user picked it,
+	// "agent" when the agent did, or the error the previous model failed with.
+	Reason string `json:"reason"`
+}
+
+// CompactionRecord is a compaction of the conversation into a summary.
+type CompactionRecord struct {
+	InputTokensBefore int64 `json:"input_tokens_before"`
+	InputTokensAfter  int64 `json:"input_tokens_after"`
+}
+
+// NewRecordItem creates an item holding a record.
+func NewRecordItem(record *Record) Item {
+	return Item{Record: record}
+}
+
+// AddRecord adds a record to the session.
+func (s *Session) AddRecord(record *Record) {
+	s.mu.Lock()
+	s.Messages = append(s.Messages, NewRecordItem(record))
+	s.mu.Unlock()
+}
+
+// GetRecords returns the records of the session, not including the ones of
+// its sub-sessions.
+func (s *Session) GetRecords() []*Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var records []*Record
+	for _, item := range s.Messages {
+		if item.IsRecord() {
+			records = append(records, item.Record)
+		}
+	}
+	return records
+}
+
+// String describes the record in a line for users.
+func (r *Record) String() string {
+	switch {
+	case r.ToolApproval != nil:
+		a := r.ToolApproval
+		switch a.Decision {
+		case "reject":
+			if a.Reason != "" {
+				return fmt.Sprintf("Rejected %s: %s", a.ToolName, a.Reason)
+			}
+			return "Rejected " + a.ToolName
+		case "approve-session":
+			return fmt.Sprintf("Approved %s and all the tool calls of the session", a.ToolName)
+		case "approve-tool":
+			return fmt.Sprintf("Approved %s for the session (%s)", a.ToolName, a.Pattern)
+		case "approve-always":
+			return fmt.Sprintf("Approved %s for the project (%s)", a.ToolName, a.Pattern)
+		default:
+			return "Approved " + a.ToolName
+		}
+	case r.Elicitation != nil:
+		e := r.Elicitation
+		s := fmt.Sprintf("Answered %q: %s", e.Message, e.Action)
+		if len(e.Fields) > 0 {
+			s += " (" + strings.Join(e.Fields, ", ") + ")"
+		}
+		return s
+	case r.Escalation != nil:
+		return "Escalated to a human: " + r.Escalation.Reason
+	case r.ModelSwitch != nil:
+		m := r.ModelSwitch
+		return fmt.Sprintf("Switched the model of %s from %s to %s (%s)", r.AgentName, modelOrDefault(m.From), modelOrDefault(m.To), m.Reason)
+	case r.Compaction != nil:
+		return fmt.Sprintf("Compacted the conversation from %d to %d tokens", r.Compaction.InputTokensBefore, r.Compaction.InputTokensAfter)
+	default:
+		return ""
+	}
+}
+
+func modelOrDefault(model string) string {
+	if model == "" {
+		return "the default model"
+	}
+	return model
+}
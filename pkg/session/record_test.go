@@ -0,0 +1,60 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		record   Record
+		expected string
+	}{
+		{
+			name:     "approval",
+			record:   Record{ToolApproval: &ToolApprovalRecord{ToolName: "shell", Decision: "approve"}},
+			expected: "Approved shell",
+		},
+		{
+			name:     "approval for the project",
+			record:   Record{ToolApproval: &ToolApprovalRecord{ToolName: "shell", Decision: "approve-always", Pattern: "shell:cmd=ls*"}},
+			expected: "Approved shell for the project (shell:cmd=ls*)",
+		},
+		{
+			name:     "rejection",
+			record:   Record{ToolApproval: &ToolApprovalRecord{ToolName: "shell", Decision: "reject", Reason: "too risky"}},
+			expected: "Rejected shell: too risky",
+		},
+		{
+			name:     "elicitation",
+			record:   Record{Elicitation: &ElicitationRecord{Message: "Sign in", Action: "accept", Fields: []string{"user", "token"}}},
+			expected: `Answered "Sign in": accept (user, token)`,
+		},
+		{
+			name:     "escalation",
+			record:   Record{Escalation: &EscalationRecord{Reason: "tests keep failing"}},
+			expected: "Escalated to a human: tests keep failing",
+		},
+		{
+			name:     "model switch",
+			record:   Record{AgentName: "root", ModelSwitch: &ModelSwitchRecord{To: "openai/gpt-4o", Reason: "user"}},
+			expected: "Switched the model of root from the default model to openai/gpt-4o (user)",
+		},
+		{
+			name:     "compaction",
+			record:   Record{Compaction: &CompactionRecord{InputTokensBefore: 120000, InputTokensAfter: 3000}},
+			expected: "Compacted the conversation from 120000 to 3000 tokens",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, tt.record.String())
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+)
+
+// RetentionPolicy limits the sessions kept in the store. Sessions are pruned
+// oldest first and starred sessions are never pruned. Zero fields don't
+// limit anything.
+type RetentionPolicy struct {
+	// MaxAge prunes the sessions created longer ago than this.
+	MaxAge time.Duration
+	// MaxCount prunes the oldest sessions beyond this number of sessions.
+	MaxCount int
+	// MaxSize prunes the oldest sessions until the data of the database
+	// takes at most this number of bytes.
+	MaxSize int64
+}
+
+// IsZero reports whether the policy doesn't limit anything.
+func (p RetentionPolicy) IsZero() bool {
+	return p.MaxAge <= 0 && p.MaxCount <= 0 && p.MaxSize <= 0
+}
+
+// WithRetention prunes the sessions that the policy doesn't retain when the
+// store is opened. The space of the pruned sessions is reused by the new
+// sessions; Vacuum gives it back to the file system.
+func WithRetention(policy RetentionPolicy) SQLiteOpt {
+	return func(s *SQLiteSessionStore) error {
+		if policy.IsZero() {
+			return nil
+		}
+		pruned, err := s.Prune(context.Background(), policy, false)
+		if err != nil {
+			// Keeping too many sessions shouldn't prevent running agents.
+			slog.Warn("Failed to prune sessions", "error", err)
+			return nil
+		}
+		if len(pruned) > 0 {
+			slog.Debug("Pruned sessions", "count", len(pruned))
+		}
+		return nil
+	}
+}
+
+// Prune deletes the top-level sessions, with their sub-sessions, that the
+// policy doesn't retain and returns them, oldest first. With dryRun, the
+// sessions are only returned.
+func (s *SQLiteSessionStore) Prune(ctx context.Context, policy RetentionPolicy, dryRun bool) ([]Summary, error) {
+	if policy.IsZero() {
+		return nil, nil
+	}
+
+	// Deleting in a transaction that's rolled back for dry runs lets the size
+	// limit be measured the same way in both cases.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sessions, err := retentionCandidates(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	remaining := len(sessions)
+	var pruned []Summary
+	for _, sess := range sessions {
+		if sess.Starred {
+			continue
+		}
+		tooOld := policy.MaxAge > 0 && now.Sub(sess.CreatedAt) > policy.MaxAge
+		tooMany := policy.MaxCount > 0 && remaining > policy.MaxCount
+		tooLarge := false
+		if !tooOld && !tooMany && policy.MaxSize > 0 {
+			size, err := usedSize(ctx, tx)
+			if err != nil {
+				return nil, err
+			}
+			tooLarge = size > policy.MaxSize
+		}
+		if !tooOld && !tooMany && !tooLarge {
+			// Sessions are sorted oldest first: the next ones are retained too.
+			break
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", sess.ID); err != nil {
+			return nil, fmt.Errorf("deleting session %s: %w", sess.ID, err)
+		}
+		remaining--
+		pruned = append(pruned, sess)
+	}
+
+	if dryRun {
+		return pruned, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// Vacuum rebuilds the database to give the space of the deleted sessions back
+// to the file system.
+func (s *SQLiteSessionStore) Vacuum(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, "VACUUM")
+	return err
+}
+
+// retentionCandidates returns the top-level sessions, oldest first.
+func retentionCandidates(ctx context.Context, q querier) ([]Summary, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, COALESCE(title, ''), created_at, starred
+		 FROM sessions
+		 WHERE parent_id IS NULL OR parent_id = ''`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []Summary
+	for rows.Next() {
+		var id, title, createdAtStr, starredStr string
+		if err := rows.Scan(&id, &title, &createdAtStr, &starredStr); err != nil {
+			return nil, err
+		}
+		createdAt, err := time.Parse(time.RFC3339, createdAtStr)
+		if err != nil {
+			return nil, err
+		}
+		starred, err := strconv.ParseBool(starredStr)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, Summary{
+			ID:        id,
+			Title:     title,
+			CreatedAt: createdAt,
+			Starred:   starred,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(sessions, func(a, b Summary) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return sessions, nil
+}
+
+// usedSize returns the number of bytes of the pages of the database that
+// aren't free.
+func usedSize(ctx context.Context, q querier) (int64, error) {
+	var pageCount, freelistCount, pageSize int64
+	if err := q.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, err
+	}
+	if err := q.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freelistCount); err != nil {
+		return 0, err
+	}
+	if err := q.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return (pageCount - freelistCount) * pageSize, nil
+}
+
+// ParseRetentionAge parses a maximum age of the sessions. It accepts the
+// units of time.ParseDuration plus days ("30d") and weeks ("2w").
+func ParseRetentionAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(v * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// ParseRetentionSize parses a maximum size of the database, e.g. "500MB".
+func ParseRetentionSize(s string) (int64, error) {
+	size, err := units.RAMInBytes(strings.TrimSpace(s))
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return size, nil
+}
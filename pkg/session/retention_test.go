@@ -0,0 +1,140 @@
+package session
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetentionStore(t *testing.T) *SQLiteSessionStore {
+	t.Helper()
+
+	store, err := NewSQLiteSessionStore(filepath.Join(t.TempDir(), "test_retention.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	now := time.Now()
+	for i, age := range []time.Duration{40 * 24 * time.Hour, 35 * 24 * time.Hour, 10 * 24 * time.Hour, time.Hour} {
+		id := string(rune('a' + i))
+		require.NoError(t, store.AddSession(t.Context(), &Session{ID: id, Title: "session " + id, CreatedAt: now.Add(-age)}))
+		_, err := store.AddMessage(t.Context(), id, UserMessage(strings.Repeat("x", 64*1024)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.AddSubSession(t.Context(), "a", &Session{ID: "a-sub", CreatedAt: now, Messages: []Item{NewMessageItem(UserMessage("sub-session"))}}))
+	require.NoError(t, store.SetSessionStarred(t.Context(), "b", true))
+
+	return store.(*SQLiteSessionStore)
+}
+
+func prunedIDs(pruned []Summary) []string {
+	var ids []string
+	for _, s := range pruned {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func TestSQLiteSessionStore_PruneMaxAge(t *testing.T) {
+	store := newRetentionStore(t)
+
+	pruned, err := store.Prune(t.Context(), RetentionPolicy{MaxAge: 30 * 24 * time.Hour}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, prunedIDs(pruned), "starred sessions are kept")
+
+	_, err = store.GetSession(t.Context(), "a")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.GetSession(t.Context(), "a-sub")
+	require.ErrorIs(t, err, ErrNotFound, "sub-sessions are deleted with their parent")
+	_, err = store.GetSession(t.Context(), "b")
+	require.NoError(t, err)
+}
+
+func TestSQLiteSessionStore_PruneMaxCount(t *testing.T) {
+	store := newRetentionStore(t)
+
+	pruned, err := store.Prune(t.Context(), RetentionPolicy{MaxCount: 2}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, prunedIDs(pruned))
+
+	summaries, err := store.GetSessionSummaries(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, summaries, 2)
+}
+
+func TestSQLiteSessionStore_PruneMaxSize(t *testing.T) {
+	store := newRetentionStore(t)
+
+	size, err := usedSize(t.Context(), store.db)
+	require.NoError(t, err)
+
+	// Pruning the oldest session is enough to get below the current size.
+	pruned, err := store.Prune(t.Context(), RetentionPolicy{MaxSize: size - 1}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, prunedIDs(pruned))
+
+	pruned, err = store.Prune(t.Context(), RetentionPolicy{MaxSize: 1}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, prunedIDs(pruned), "starred sessions are kept")
+}
+
+func TestSQLiteSessionStore_PruneDryRun(t *testing.T) {
+	store := newRetentionStore(t)
+
+	pruned, err := store.Prune(t.Context(), RetentionPolicy{MaxCount: 1}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c", "d"}, prunedIDs(pruned))
+
+	summaries, err := store.GetSessionSummaries(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, summaries, 4, "a dry run doesn't delete anything")
+}
+
+func TestNewSQLiteSessionStore_WithRetention(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_retention.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	require.NoError(t, store.AddSession(t.Context(), &Session{ID: "old", CreatedAt: time.Now().Add(-48 * time.Hour)}))
+	require.NoError(t, store.AddSession(t.Context(), &Session{ID: "new", CreatedAt: time.Now()}))
+	require.NoError(t, store.Close())
+
+	store, err = NewSQLiteSessionStore(tempDB, WithRetention(RetentionPolicy{MaxAge: 24 * time.Hour}))
+	require.NoError(t, err)
+	defer store.Close()
+
+	summaries, err := store.GetSessionSummaries(t.Context())
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "new", summaries[0].ID)
+}
+
+func TestParseRetentionAge(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"30d":  30 * 24 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"1.5d": 36 * time.Hour,
+	} {
+		age, err := ParseRetentionAge(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, age, input)
+	}
+
+	for _, input := range []string{"", "d", "-1d", "thirty days"} {
+		_, err := ParseRetentionAge(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestParseRetentionSize(t *testing.T) {
+	size, err := ParseRetentionSize("500MB")
+	require.NoError(t, err)
+	assert.Equal(t, int64(500*1024*1024), size)
+
+	_, err = ParseRetentionSize("large")
+	require.Error(t, err)
+}
@@ -12,7 +12,9 @@ import (
 
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/workspace"
 )
 
 const (
@@ -25,7 +27,7 @@ const (
 	toolContentPlaceholder = "[content truncated]"
 )
 
-// Item represents either a message or a sub-session
+// Item represents either a message, a sub-session, a summary or a record
 type Item struct {
 	// Message holds a regular conversation message
 	Message *Message `json:"message,omitempty"`
@@ -39,6 +41,9 @@ type Item struct {
 	// Cost tracks the cost of operations associated with this item that
 	// don't produce a regular message (e.g., compaction/summarization).
 	Cost float64 `json:"cost,omitempty"`
+
+	// Record holds an interaction that isn't a message, like an approval
+	Record *Record `json:"record,omitempty"`
 }
 
 // IsMessage returns true if this item contains a message
@@ -51,6 +56,11 @@ func (si *Item) IsSubSession() bool {
 	return si.SubSession != nil
 }
 
+// IsRecord returns true if this item contains a record
+func (si *Item) IsRecord() bool {
+	return si.Record != nil
+}
+
 // Session represents the agent's state including conversation history and variables
 type Session struct {
 	// mu protects Messages from concurrent read/write access.
@@ -83,9 +93,20 @@ type Session struct {
 	// HideToolResults is a flag to indicate if tool results should be hidden
 	HideToolResults bool `json:"hide_tool_results"`
 
+	// Simulate makes the runtime simulate the tool calls that aren't
+	// read-only instead of running them: the model is asked to describe
+	// their expected outcome, so that the run narrates a plan of actions.
+	// It is not persisted: resuming a session runs its tools for real.
+	Simulate bool `json:"-"`
+
 	// WorkingDir is the base directory used for filesystem-aware tools
 	WorkingDir string `json:"working_dir,omitempty"`
 
+	// WorkingDirs are the roots of a multi-root workspace. The first one is
+	// WorkingDir, which is kept for sessions with a single root and for
+	// compatibility. Use Roots to get the roots of any session.
+	WorkingDirs []string `json:"working_dirs,omitempty"`
+
 	// SendUserMessage is a flag to indicate if the user message should be sent
 	SendUserMessage bool
 
@@ -93,6 +114,10 @@ type Session struct {
 	// If 0, there is no limit
 	MaxIterations int `json:"max_iterations"`
 
+	// IterationBudget, when set, replaces MaxIterations for the next run only.
+	// It's used to continue a run that stopped at its max_iterations limit.
+	IterationBudget int `json:"-"`
+
 	// Starred indicates if this session has been starred by the user
 	Starred bool `json:"starred"`
 
@@ -113,6 +138,17 @@ type Session struct {
 	// These are shown in the model picker for easy re-selection.
 	CustomModelsUsed []string `json:"custom_models_used,omitempty"`
 
+	// Artifacts are the files that tools registered as outputs of the session.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+
+	// Blackboard holds the findings the agents of the team posted for each
+	// other during the session.
+	Blackboard []BlackboardEntry `json:"blackboard,omitempty"`
+
+	// ConfigHash identifies the version of the agent configuration the session
+	// last ran with, in the configuration history.
+	ConfigHash string `json:"config_hash,omitempty"`
+
 	// AgentName, when set, tells RunStream which agent to use for this session
 	// instead of reading from the shared runtime currentAgent field. This is
 	// required for background agent tasks where multiple sessions may run
@@ -124,6 +160,30 @@ type Session struct {
 	// within the parent session's Messages array.
 	ParentID string `json:"-"`
 
+	// OutputSchema is the JSON Schema the final answer of a sub-session must
+	// follow, set when the agent that delegated the task asks for structured
+	// results.
+	OutputSchema *latest.StructuredOutput `json:"-"`
+
+	// TaskBudget limits the turns and the cost of a sub-session, set when
+	// the team limits the tasks delegated to its agent.
+	TaskBudget *latest.TaskBudget `json:"-"`
+
+	// blackboardOwner is the session holding the blackboard a sub-session
+	// shares with the rest of its team run.
+	blackboardOwner *Session
+
+	// PreviousSessionsSummary is the summary of the previous sessions of the
+	// agent in the working directory, loaded by the runtime when the agent
+	// has continuity enabled. It is not persisted: it's reloaded when the
+	// session is resumed.
+	PreviousSessionsSummary string `json:"-"`
+
+	// DynamicContext holds the sections of the dynamic context sources of
+	// the current agent, read by the runtime before each model call. It is
+	// not persisted.
+	DynamicContext []string `json:"-"`
+
 	// MessageUsageHistory stores per-message usage data for remote mode.
 	// In remote mode, messages are managed server-side, so we track usage separately.
 	// This is not persisted (json:"-") as it's only needed for the current session display.
@@ -157,6 +217,9 @@ type Message struct {
 	ID        int64        `json:"-"`
 	AgentName string       `json:"agentName"` // TODO: rename to agent_name
 	Message   chat.Message `json:"message"`
+	// User is the user who sent a user message, in sessions shared by
+	// several users of the API server.
+	User string `json:"user,omitempty"`
 	// Implicit is an optional field to indicate if the message shouldn't be shown to the user. It's needed for special  situations
 	// like when an agent transfers a task to another agent - new session is created with a default user message, but this shouldn't be shown to the user.
 	// Such messages should be marked as true
@@ -300,14 +363,22 @@ func (s *Session) Duration() time.Duration {
 	return last.Sub(first)
 }
 
-// AllowedDirectories returns the directories that should be considered safe for tools
-func (s *Session) AllowedDirectories() []string {
+// Roots returns the roots of the session's workspace, WorkingDir first.
+func (s *Session) Roots() []string {
+	if len(s.WorkingDirs) > 0 {
+		return s.WorkingDirs
+	}
 	if s.WorkingDir == "" {
 		return nil
 	}
 	return []string{s.WorkingDir}
 }
 
+// AllowedDirectories returns the directories that should be considered safe for tools
+func (s *Session) AllowedDirectories() []string {
+	return s.Roots()
+}
+
 // GetAllMessages extracts all messages from the session, including from sub-sessions
 func (s *Session) GetAllMessages() []Message {
 	s.mu.RLock()
@@ -334,6 +405,53 @@ func (s *Session) GetAllMessages() []Message {
 	return messages
 }
 
+// GetHistory returns the messages and the records of the session in order,
+// including the ones of its sub-sessions, as items. System messages are left
+// out, like in GetAllMessages.
+func (s *Session) GetHistory() []Item {
+	s.mu.RLock()
+	items := make([]Item, len(s.Messages))
+	for i, item := range s.Messages {
+		if item.Message != nil {
+			items[i] = Item{Message: deepCopyMessage(item.Message)}
+		} else {
+			items[i] = item
+		}
+	}
+	s.mu.RUnlock()
+
+	var history []Item
+	for _, item := range items {
+		switch {
+		case item.IsMessage() && item.Message.Message.Role != chat.MessageRoleSystem:
+			history = append(history, item)
+		case item.IsSubSession():
+			history = append(history, item.SubSession.GetHistory()...)
+		case item.IsRecord():
+			history = append(history, item)
+		}
+	}
+	return history
+}
+
+// Items returns the items of the session in order, at the positions
+// BranchSession takes. Unlike GetHistory, the sub-sessions aren't flattened
+// and the system messages are kept.
+func (s *Session) Items() []Item {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]Item, len(s.Messages))
+	for i, item := range s.Messages {
+		if item.Message != nil {
+			items[i] = Item{Message: deepCopyMessage(item.Message), Summary: item.Summary, Cost: item.Cost}
+		} else {
+			items[i] = item
+		}
+	}
+	return items
+}
+
 func (s *Session) GetLastAssistantMessageContent() string {
 	return s.getLastMessageContentByRole(chat.MessageRoleAssistant)
 }
@@ -420,6 +538,21 @@ func WithWorkingDir(workingDir string) Opt {
 	}
 }
 
+// WithWorkingDirs sets the roots of a multi-root workspace, the working
+// directory first.
+func WithWorkingDirs(workingDirs ...string) Opt {
+	return func(s *Session) {
+		if len(workingDirs) == 0 {
+			return
+		}
+		s.WorkingDir = workingDirs[0]
+		s.WorkingDirs = nil
+		if len(workingDirs) > 1 {
+			s.WorkingDirs = workingDirs
+		}
+	}
+}
+
 func WithTitle(title string) Opt {
 	return func(s *Session) {
 		s.Title = title
@@ -438,6 +571,12 @@ func WithThinking(thinking bool) Opt {
 	}
 }
 
+func WithSimulate(simulate bool) Opt {
+	return func(s *Session) {
+		s.Simulate = simulate
+	}
+}
+
 func WithHideToolResults(hideToolResults bool) Opt {
 	return func(s *Session) {
 		s.HideToolResults = hideToolResults
@@ -473,6 +612,21 @@ func WithParentID(parentID string) Opt {
 	}
 }
 
+// WithOutputSchema sets the JSON Schema the final answer of the session must
+// follow.
+func WithOutputSchema(schema *latest.StructuredOutput) Opt {
+	return func(s *Session) {
+		s.OutputSchema = schema
+	}
+}
+
+// WithTaskBudget sets the limits of the task the session runs.
+func WithTaskBudget(budget *latest.TaskBudget) Opt {
+	return func(s *Session) {
+		s.TaskBudget = budget
+	}
+}
+
 // IsSubSession returns true if this session is a sub-session (has a parent).
 func (s *Session) IsSubSession() bool {
 	return s.ParentID != ""
@@ -492,6 +646,34 @@ func (s *Session) MessageCount() int {
 	return n
 }
 
+// LastUserMessageAuthor returns the user who sent the last user message of
+// the session, if known.
+func (s *Session) LastUserMessageAuthor() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, item := range slices.Backward(s.Messages) {
+		if item.IsMessage() && item.Message.Message.Role == chat.MessageRoleUser {
+			return item.Message.User
+		}
+	}
+	return ""
+}
+
+// lastAgentName returns the name of the last agent that added a message to
+// the session.
+func (s *Session) lastAgentName() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, item := range slices.Backward(s.Messages) {
+		if item.IsMessage() && item.Message.AgentName != "" {
+			return item.Message.AgentName
+		}
+	}
+	return ""
+}
+
 // TotalCost computes the total cost of a session by walking all messages,
 // sub-sessions, and summary items. It does not use the session-level Cost
 // field, which exists only for backward-compatible persistence.
@@ -530,6 +712,27 @@ func (s *Session) OwnCost() float64 {
 	return cost
 }
 
+// OwnUsage returns the token usage summed over this session's own messages.
+// It excludes sub-sessions, like OwnCost.
+func (s *Session) OwnUsage() chat.Usage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var usage chat.Usage
+	for _, item := range s.Messages {
+		if !item.IsMessage() || item.Message.Message.Usage == nil {
+			continue
+		}
+		u := item.Message.Message.Usage
+		usage.InputTokens += u.InputTokens
+		usage.OutputTokens += u.OutputTokens
+		usage.CachedInputTokens += u.CachedInputTokens
+		usage.CacheWriteTokens += u.CacheWriteTokens
+		usage.ReasoningTokens += u.ReasoningTokens
+	}
+	return usage
+}
+
 // New creates a new agent session
 func New(opts ...Opt) *Session {
 	sessionID := uuid.New().String()
@@ -651,6 +854,13 @@ func buildContextSpecificSystemMessages(a *agent.Agent, s *Session) []chat.Messa
 		})
 	}
 
+	if a.Continuity() && s.PreviousSessionsSummary != "" {
+		messages = append(messages, chat.Message{
+			Role:    chat.MessageRoleSystem,
+			Content: "Summary of your previous sessions in this project:\n\n" + s.PreviousSessionsSummary,
+		})
+	}
+
 	wd := s.WorkingDir
 	if wd == "" {
 		var err error
@@ -667,6 +877,13 @@ func buildContextSpecificSystemMessages(a *agent.Agent, s *Session) []chat.Messa
 			})
 		}
 
+		if roots := s.Roots(); len(roots) > 1 {
+			messages = append(messages, chat.Message{
+				Role:    chat.MessageRoleSystem,
+				Content: workspace.New(roots[0], roots[1:]...).Instructions(),
+			})
+		}
+
 		for _, prompt := range a.AddPromptFiles() {
 			additionalPrompts, err := readPromptFiles(wd, prompt)
 			if err != nil {
@@ -683,6 +900,13 @@ func buildContextSpecificSystemMessages(a *agent.Agent, s *Session) []chat.Messa
 		}
 	}
 
+	for _, section := range s.DynamicContext {
+		messages = append(messages, chat.Message{
+			Role:    chat.MessageRoleSystem,
+			Content: section,
+		})
+	}
+
 	return messages
 }
 
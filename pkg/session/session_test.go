@@ -218,6 +218,22 @@ func TestGetMessages_CacheControlWithSummary(t *testing.T) {
 	assert.Contains(t, messages[checkpointIndices[1]].Content, "Today's date", "checkpoint #2 should be on date message")
 }
 
+func TestGetMessages_PreviousSessionsSummary(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	s.PreviousSessionsSummary = "## Decisions\n- Use sqlite"
+
+	messages := s.GetMessages(agent.New("root", "instructions", agent.WithContinuity(true)))
+	require.Len(t, messages, 2)
+	assert.Equal(t, chat.MessageRoleSystem, messages[1].Role)
+	assert.Contains(t, messages[1].Content, "- Use sqlite")
+
+	// The summary is only given to agents with continuity enabled.
+	messages = s.GetMessages(agent.New("root", "instructions"))
+	assert.Len(t, messages, 1)
+}
+
 func TestGetLastUserMessages(t *testing.T) {
 	t.Parallel()
 
@@ -330,3 +346,30 @@ func TestTransferTaskPromptExcludesParents(t *testing.T) {
 	assert.Contains(t, subAgentMsg, "librarian", "should list librarian as a valid sub-agent")
 	assert.NotContains(t, subAgentMsg, "planner", "should NOT list parent agent planner as a valid transfer target")
 }
+
+func TestOwnUsage(t *testing.T) {
+	sess := New(WithUserMessage("hello"))
+	sess.AddMessage(&Message{Message: chat.Message{
+		Role:  chat.MessageRoleAssistant,
+		Usage: &chat.Usage{InputTokens: 100, OutputTokens: 10, CacheWriteTokens: 900},
+	}})
+	sess.AddMessage(&Message{Message: chat.Message{
+		Role:  chat.MessageRoleAssistant,
+		Usage: &chat.Usage{InputTokens: 50, OutputTokens: 20, CachedInputTokens: 900, ReasoningTokens: 5},
+	}})
+
+	sub := New()
+	sub.AddMessage(&Message{Message: chat.Message{
+		Role:  chat.MessageRoleAssistant,
+		Usage: &chat.Usage{InputTokens: 1000, CachedInputTokens: 1000},
+	}})
+	sess.AddSubSession(sub)
+
+	assert.Equal(t, chat.Usage{
+		InputTokens:       150,
+		OutputTokens:      30,
+		CachedInputTokens: 900,
+		CacheWriteTokens:  900,
+		ReasoningTokens:   5,
+	}, sess.OwnUsage())
+}
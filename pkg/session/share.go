@@ -0,0 +1,117 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+)
+
+// ShareSession returns the token of the read-only link of a session,
+// creating it if the session isn't shared yet.
+func (s *InMemorySessionStore) ShareSession(_ context.Context, id string) (string, error) {
+	if id == "" {
+		return "", ErrEmptyID
+	}
+	if _, exists := s.sessions.Load(id); !exists {
+		return "", ErrNotFound
+	}
+	if token, ok := s.shares.Load(id); ok {
+		return token, nil
+	}
+	token := rand.Text()
+	s.shares.Store(id, token)
+	return token, nil
+}
+
+// UnshareSession revokes the token of the read-only link of a session.
+func (s *InMemorySessionStore) UnshareSession(_ context.Context, id string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	if _, exists := s.sessions.Load(id); !exists {
+		return ErrNotFound
+	}
+	s.shares.Delete(id)
+	return nil
+}
+
+// GetSharedSession returns the session shared with a token.
+func (s *InMemorySessionStore) GetSharedSession(ctx context.Context, token string) (*Session, error) {
+	if token == "" {
+		return nil, ErrNotFound
+	}
+	var id string
+	s.shares.Range(func(sessionID, sessionToken string) bool {
+		if sessionToken == token {
+			id = sessionID
+			return false
+		}
+		return true
+	})
+	if id == "" {
+		return nil, ErrNotFound
+	}
+	return s.GetSession(ctx, id)
+}
+
+// ShareSession returns the token of the read-only link of a session,
+// creating it if the session isn't shared yet.
+func (s *SQLiteSessionStore) ShareSession(ctx context.Context, id string) (string, error) {
+	if id == "" {
+		return "", ErrEmptyID
+	}
+
+	// Keep the token of a session already shared, so that its link still works.
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE sessions SET share_token = ? WHERE id = ? AND share_token IS NULL", rand.Text(), id); err != nil {
+		return "", err
+	}
+
+	var token sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT share_token FROM sessions WHERE id = ?", id).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return token.String, nil
+}
+
+// UnshareSession revokes the token of the read-only link of a session.
+func (s *SQLiteSessionStore) UnshareSession(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	result, err := s.db.ExecContext(ctx, "UPDATE sessions SET share_token = NULL WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetSharedSession returns the session shared with a token.
+func (s *SQLiteSessionStore) GetSharedSession(ctx context.Context, token string) (*Session, error) {
+	if token == "" {
+		return nil, ErrNotFound
+	}
+
+	var id string
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM sessions WHERE share_token = ?", token).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSession(ctx, id)
+}
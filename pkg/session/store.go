@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -63,14 +64,80 @@ func ResolveSessionID(ctx context.Context, store Store, ref string) (string, err
 	return summaries[index].ID, nil
 }
 
+// LatestSessionInDir returns the ID of the most recent session that was
+// started in the given working directory.
+// It returns ErrNotFound if there is no such session.
+func LatestSessionInDir(ctx context.Context, store Store, dir string) (string, error) {
+	summaries, err := store.GetSessionSummaries(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting session summaries: %w", err)
+	}
+
+	dir = filepath.Clean(dir)
+	for _, summary := range summaries {
+		if summary.WorkingDir != "" && filepath.Clean(summary.WorkingDir) == dir {
+			return summary.ID, nil
+		}
+	}
+
+	return "", ErrNotFound
+}
+
 // Summary contains lightweight session metadata for listing purposes.
 // This is used instead of loading full Session objects with all messages.
 type Summary struct {
-	ID          string
-	Title       string
-	CreatedAt   time.Time
-	Starred     bool
-	NumMessages int
+	ID           string
+	Title        string
+	CreatedAt    time.Time
+	Starred      bool
+	NumMessages  int
+	Agent        string // name of the last agent that answered
+	WorkingDir   string
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
+}
+
+// Page selects a page of results: at most Limit results after skipping the
+// first Offset ones. A zero Limit doesn't limit the number of results.
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// apply returns the page of n results as the bounds of a slice.
+func (p Page) apply(n int) (start, end int) {
+	start = min(max(p.Offset, 0), n)
+	end = n
+	if p.Limit > 0 {
+		end = min(start+p.Limit, n)
+	}
+	return start, end
+}
+
+// SummaryFilter selects the sessions to list. Zero fields select every session.
+type SummaryFilter struct {
+	// Query selects the sessions whose title contains it, ignoring case.
+	Query string
+	// WorkingDir selects the sessions run in this directory.
+	WorkingDir string
+	// Starred selects only the starred sessions.
+	Starred bool
+	// Restricted selects only the sessions Member owns or has a role in,
+	// and the sessions without owner.
+	Restricted bool
+	Member     string
+}
+
+// matches reports whether the filter selects the session summary.
+func (f SummaryFilter) matches(summary Summary) bool {
+	if f.Query != "" && !strings.Contains(strings.ToLower(summary.Title), strings.ToLower(f.Query)) {
+		return false
+	}
+	if f.WorkingDir != "" && summary.WorkingDir != f.WorkingDir {
+		return false
+	}
+	return !f.Starred || summary.Starred
 }
 
 // Store defines the interface for session storage
@@ -78,12 +145,24 @@ type Store interface {
 	// === Core session operations ===
 	AddSession(ctx context.Context, session *Session) error
 	GetSession(ctx context.Context, id string) (*Session, error)
+	GetSessionMetadata(ctx context.Context, id string) (*Session, error) // Loads metadata only (not messages/items)
 	GetSessions(ctx context.Context) ([]*Session, error)
 	GetSessionSummaries(ctx context.Context) ([]Summary, error)
 	DeleteSession(ctx context.Context, id string) error
 	UpdateSession(ctx context.Context, session *Session) error // Updates metadata only (not messages/items)
 	SetSessionStarred(ctx context.Context, id string, starred bool) error
 
+	// === Paginated reads ===
+
+	// ListSessionSummaries returns a page of the metadata of the root
+	// sessions selected by the filter, most recent first, without loading
+	// their messages.
+	ListSessionSummaries(ctx context.Context, page Page, filter SummaryFilter) ([]Summary, error)
+
+	// GetSessionItems returns limit items of a session, starting at offset.
+	// A zero limit returns all the items after offset.
+	GetSessionItems(ctx context.Context, id string, offset, limit int) ([]Item, error)
+
 	// === Granular item operations ===
 
 	// AddMessage adds a message to a session at the next position.
@@ -101,6 +180,9 @@ type Store interface {
 	// AddSummary adds a summary item to a session at the next position
 	AddSummary(ctx context.Context, sessionID, summary string) error
 
+	// AddRecord adds a record item to a session at the next position
+	AddRecord(ctx context.Context, sessionID string, record *Record) error
+
 	// === Granular metadata updates ===
 
 	// UpdateSessionTokens updates only token/cost fields
@@ -109,18 +191,42 @@ type Store interface {
 	// UpdateSessionTitle updates only the title
 	UpdateSessionTitle(ctx context.Context, sessionID, title string) error
 
+	// === Sharing ===
+
+	// ShareSession returns the token of the read-only link of a session,
+	// creating it if the session isn't shared yet.
+	ShareSession(ctx context.Context, id string) (string, error)
+
+	// UnshareSession revokes the token of the read-only link of a session.
+	UnshareSession(ctx context.Context, id string) error
+
+	// GetSharedSession returns the session shared with a token.
+	GetSharedSession(ctx context.Context, token string) (*Session, error)
+
+	// === Members ===
+
+	// GetSessionMembers returns the owner and the members of a session.
+	GetSessionMembers(ctx context.Context, id string) (Members, error)
+
+	// SetSessionMembers replaces the owner and the members of a session.
+	SetSessionMembers(ctx context.Context, id string, members Members) error
+
 	// Close releases any resources held by the store (e.g., database connections).
 	Close() error
 }
 
 type InMemorySessionStore struct {
 	sessions  *concurrent.Map[string, *Session]
-	messageID int64 // simple counter for message IDs
+	shares    *concurrent.Map[string, string]  // session ID -> share token
+	members   *concurrent.Map[string, Members] // session ID -> members
+	messageID int64                            // simple counter for message IDs
 }
 
 func NewInMemorySessionStore() Store {
 	return &InMemorySessionStore{
 		sessions: concurrent.NewMap[string, *Session](),
+		shares:   concurrent.NewMap[string, string](),
+		members:  concurrent.NewMap[string, Members](),
 	}
 }
 
@@ -143,6 +249,12 @@ func (s *InMemorySessionStore) GetSession(_ context.Context, id string) (*Sessio
 	return session, nil
 }
 
+// GetSessionMetadata retrieves a session by ID. The in-memory store has
+// nothing to load lazily: the session comes with its items.
+func (s *InMemorySessionStore) GetSessionMetadata(ctx context.Context, id string) (*Session, error) {
+	return s.GetSession(ctx, id)
+}
+
 func (s *InMemorySessionStore) GetSessions(_ context.Context) ([]*Session, error) {
 	sessions := make([]*Session, 0, s.sessions.Length())
 	s.sessions.Range(func(key string, value *Session) bool {
@@ -159,11 +271,16 @@ func (s *InMemorySessionStore) GetSessionSummaries(_ context.Context) ([]Summary
 			return true
 		}
 		summaries = append(summaries, Summary{
-			ID:          value.ID,
-			Title:       value.Title,
-			CreatedAt:   value.CreatedAt,
-			Starred:     value.Starred,
-			NumMessages: value.MessageCount(),
+			ID:           value.ID,
+			Title:        value.Title,
+			CreatedAt:    value.CreatedAt,
+			Starred:      value.Starred,
+			NumMessages:  value.MessageCount(),
+			Agent:        value.lastAgentName(),
+			WorkingDir:   value.WorkingDir,
+			InputTokens:  value.InputTokens,
+			OutputTokens: value.OutputTokens,
+			Cost:         value.Cost,
 		})
 		return true
 	})
@@ -173,6 +290,37 @@ func (s *InMemorySessionStore) GetSessionSummaries(_ context.Context) ([]Summary
 	return summaries, nil
 }
 
+func (s *InMemorySessionStore) ListSessionSummaries(ctx context.Context, page Page, filter SummaryFilter) ([]Summary, error) {
+	summaries, err := s.GetSessionSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries = slices.DeleteFunc(summaries, func(summary Summary) bool {
+		if filter.Restricted {
+			if members, _ := s.members.Load(summary.ID); !members.Includes(filter.Member) {
+				return true
+			}
+		}
+		return !filter.matches(summary)
+	})
+	start, end := page.apply(len(summaries))
+	return summaries[start:end], nil
+}
+
+func (s *InMemorySessionStore) GetSessionItems(_ context.Context, id string, offset, limit int) ([]Item, error) {
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+	session, exists := s.sessions.Load(id)
+	if !exists {
+		return nil, ErrNotFound
+	}
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	start, end := Page{Offset: offset, Limit: limit}.apply(len(session.Messages))
+	return slices.Clone(session.Messages[start:end]), nil
+}
+
 func (s *InMemorySessionStore) DeleteSession(_ context.Context, id string) error {
 	if id == "" {
 		return ErrEmptyID
@@ -182,6 +330,8 @@ func (s *InMemorySessionStore) DeleteSession(_ context.Context, id string) error
 		return ErrNotFound
 	}
 	s.sessions.Delete(id)
+	s.shares.Delete(id)
+	s.members.Delete(id)
 	return nil
 }
 
@@ -205,6 +355,7 @@ func (s *InMemorySessionStore) UpdateSession(_ context.Context, session *Session
 		Thinking:            session.Thinking,
 		HideToolResults:     session.HideToolResults,
 		WorkingDir:          session.WorkingDir,
+		WorkingDirs:         session.WorkingDirs,
 		SendUserMessage:     session.SendUserMessage,
 		MaxIterations:       session.MaxIterations,
 		Starred:             session.Starred,
@@ -214,6 +365,9 @@ func (s *InMemorySessionStore) UpdateSession(_ context.Context, session *Session
 		Permissions:         session.Permissions,
 		AgentModelOverrides: session.AgentModelOverrides,
 		CustomModelsUsed:    session.CustomModelsUsed,
+		Artifacts:           session.Artifacts,
+		Blackboard:          session.GetBlackboard(""),
+		ConfigHash:          session.ConfigHash,
 		ParentID:            session.ParentID,
 	}
 
@@ -318,6 +472,26 @@ func (s *InMemorySessionStore) AddSummary(_ context.Context, sessionID, summary
 	return nil
 }
 
+// AddRecord adds a record item to a session at the next position. The
+// stored session is often the one the runtime already added the record to,
+// so a record is only added once.
+func (s *InMemorySessionStore) AddRecord(_ context.Context, sessionID string, record *Record) error {
+	if sessionID == "" {
+		return ErrEmptyID
+	}
+	session, exists := s.sessions.Load(sessionID)
+	if !exists {
+		return ErrNotFound
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if slices.ContainsFunc(session.Messages, func(item Item) bool { return item.Record == record }) {
+		return nil
+	}
+	session.Messages = append(session.Messages, Item{Record: record})
+	return nil
+}
+
 // querier is an interface that abstracts *sql.DB and *sql.Tx for query operations.
 type querier interface {
 	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
@@ -328,33 +502,8 @@ type querier interface {
 // SQLiteSessionStore implements Store using SQLite
 type SQLiteSessionStore struct {
 	db *sql.DB
-}
-
-// syncMessagesColumn rebuilds the messages JSON column from session_items for backward compatibility.
-// This allows older versions of docker agent to read sessions created by newer versions.
-func (s *SQLiteSessionStore) syncMessagesColumn(ctx context.Context, sessionID string) error {
-	return s.syncMessagesColumnWith(ctx, s.db, sessionID)
-}
-
-// syncMessagesColumnTx is like syncMessagesColumn but uses an existing transaction.
-func (s *SQLiteSessionStore) syncMessagesColumnTx(ctx context.Context, tx *sql.Tx, sessionID string) error {
-	return s.syncMessagesColumnWith(ctx, tx, sessionID)
-}
-
-// syncMessagesColumnWith rebuilds the messages JSON column using the provided querier.
-func (s *SQLiteSessionStore) syncMessagesColumnWith(ctx context.Context, q querier, sessionID string) error {
-	items, err := s.loadSessionItemsWith(ctx, q, sessionID)
-	if err != nil {
-		return fmt.Errorf("loading session items: %w", err)
-	}
-
-	messagesJSON, err := json.Marshal(items)
-	if err != nil {
-		return fmt.Errorf("marshaling messages: %w", err)
-	}
-
-	_, err = q.ExecContext(ctx, "UPDATE sessions SET messages = ? WHERE id = ?", string(messagesJSON), sessionID)
-	return err
+	// key encrypts the messages, summaries and records when set.
+	key []byte
 }
 
 // UpdateSessionTokens updates only token/cost fields.
@@ -391,7 +540,7 @@ func (s *InMemorySessionStore) Close() error {
 }
 
 // NewSQLiteSessionStore creates a new SQLite session store
-func NewSQLiteSessionStore(path string) (Store, error) {
+func NewSQLiteSessionStore(path string, opts ...SQLiteOpt) (Store, error) {
 	store, err := openAndMigrateSQLiteStore(path)
 	if err != nil {
 		// If migrations failed, try to recover by backing up the database and starting fresh
@@ -413,6 +562,13 @@ func NewSQLiteSessionStore(path string) (Store, error) {
 		slog.Info("Successfully recovered session store with fresh database")
 	}
 
+	for _, opt := range opts {
+		if err := opt(store); err != nil {
+			store.Close()
+			return nil, err
+		}
+	}
+
 	return store, nil
 }
 
@@ -517,6 +673,29 @@ func (s *SQLiteSessionStore) AddSession(ctx context.Context, session *Session) e
 		customModelsUsedJSON = string(customBytes)
 	}
 
+	workingDirsJSON := "[]"
+	if len(session.WorkingDirs) > 0 {
+		dirsBytes, err := json.Marshal(session.WorkingDirs)
+		if err != nil {
+			return err
+		}
+		workingDirsJSON = string(dirsBytes)
+	}
+
+	artifactsJSON := "[]"
+	if len(session.Artifacts) > 0 {
+		artifactsBytes, err := json.Marshal(session.Artifacts)
+		if err != nil {
+			return err
+		}
+		artifactsJSON = string(artifactsBytes)
+	}
+
+	blackboardJSON, err := s.sealBlackboard(session.Blackboard)
+	if err != nil {
+		return err
+	}
+
 	// Use NULL for empty parent_id to avoid foreign key constraint issues
 	var parentID any
 	if session.ParentID != "" {
@@ -533,12 +712,12 @@ func (s *SQLiteSessionStore) AddSession(ctx context.Context, session *Session) e
 		`INSERT INTO sessions (
 			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
 			max_iterations, working_dir, created_at, permissions, agent_model_overrides,
-			custom_models_used, thinking, parent_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			custom_models_used, thinking, parent_id, working_dirs, artifacts, blackboard, config_hash
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens, session.Title,
 		session.Cost, session.SendUserMessage, session.MaxIterations, session.WorkingDir,
 		session.CreatedAt.Format(time.RFC3339), permissionsJSON, agentModelOverridesJSON,
-		customModelsUsedJSON, session.Thinking, parentID)
+		customModelsUsedJSON, session.Thinking, parentID, workingDirsJSON, artifactsJSON, blackboardJSON, session.ConfigHash)
 	if err != nil {
 		return err
 	}
@@ -555,7 +734,7 @@ func (s *SQLiteSessionStore) AddSession(ctx context.Context, session *Session) e
 
 // scanSession scans a single row into a Session struct
 // Note: Messages are loaded separately from session_items table
-func scanSession(scanner interface {
+func (s *SQLiteSessionStore) scanSession(scanner interface {
 	Scan(dest ...any) error
 },
 ) (*Session, error) {
@@ -564,7 +743,11 @@ func scanSession(scanner interface {
 	var workingDir sql.NullString
 	var permissionsJSON sql.NullString
 	var parentID sql.NullString
-	err := scanner.Scan(&sessionID, &toolsApprovedStr, &inputTokensStr, &outputTokensStr, &titleStr, &costStr, &sendUserMessageStr, &maxIterationsStr, &workingDir, &createdAtStr, &starredStr, &permissionsJSON, &agentModelOverridesJSON, &customModelsUsedJSON, &thinkingStr, &parentID)
+	var workingDirsJSON sql.NullString
+	var artifactsJSON sql.NullString
+	var blackboardJSON sql.NullString
+	var configHash sql.NullString
+	err := scanner.Scan(&sessionID, &toolsApprovedStr, &inputTokensStr, &outputTokensStr, &titleStr, &costStr, &sendUserMessageStr, &maxIterationsStr, &workingDir, &createdAtStr, &starredStr, &permissionsJSON, &agentModelOverridesJSON, &customModelsUsedJSON, &thinkingStr, &parentID, &workingDirsJSON, &artifactsJSON, &blackboardJSON, &configHash)
 	if err != nil {
 		return nil, err
 	}
@@ -639,6 +822,34 @@ func scanSession(scanner interface {
 		}
 	}
 
+	// Parse working directories (may be NULL, empty or "[]")
+	var workingDirs []string
+	if workingDirsJSON.Valid && workingDirsJSON.String != "" && workingDirsJSON.String != "[]" {
+		if err := json.Unmarshal([]byte(workingDirsJSON.String), &workingDirs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse artifacts (may be NULL, empty or "[]")
+	var artifacts []Artifact
+	if artifactsJSON.Valid && artifactsJSON.String != "" && artifactsJSON.String != "[]" {
+		if err := json.Unmarshal([]byte(artifactsJSON.String), &artifacts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse the blackboard (may be NULL, empty or "[]")
+	var blackboard []BlackboardEntry
+	if blackboardJSON.Valid && blackboardJSON.String != "" && blackboardJSON.String != "[]" {
+		data, err := s.open(blackboardJSON.String)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(data), &blackboard); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Session{
 		ID:                  sessionID,
 		Title:               titleStr,
@@ -652,38 +863,53 @@ func scanSession(scanner interface {
 		MaxIterations:       maxIterations,
 		CreatedAt:           createdAt,
 		WorkingDir:          workingDir.String,
+		WorkingDirs:         workingDirs,
 		Starred:             starred,
 		Permissions:         permissions,
 		AgentModelOverrides: agentModelOverrides,
 		CustomModelsUsed:    customModelsUsed,
+		Artifacts:           artifacts,
+		Blackboard:          blackboard,
+		ConfigHash:          configHash.String,
 		ParentID:            parentID.String,
 	}, nil
 }
 
 // GetSession retrieves a session by ID
 func (s *SQLiteSessionStore) GetSession(ctx context.Context, id string) (*Session, error) {
+	sess, err := s.GetSessionMetadata(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load messages from session_items table
+	items, err := s.loadSessionItems(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("loading session items: %w", err)
+	}
+	sess.Messages = items
+
+	return sess, nil
+}
+
+// GetSessionMetadata retrieves a session by ID without loading its items,
+// for the callers that only read or update its metadata. The items of long
+// sessions are read with GetSessionItems, a page at a time.
+func (s *SQLiteSessionStore) GetSessionMetadata(ctx context.Context, id string) (*Session, error) {
 	if id == "" {
 		return nil, ErrEmptyID
 	}
 
 	row := s.db.QueryRowContext(ctx,
-		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id FROM sessions WHERE id = ?", id)
+		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, working_dirs, artifacts, blackboard, config_hash FROM sessions WHERE id = ?", id)
 
-	sess, err := scanSession(row)
+	sess, err := s.scanSession(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
-
-	// Load messages from session_items table
-	items, err := s.loadSessionItems(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("loading session items: %w", err)
-	}
-	sess.Messages = items
-
 	return sess, nil
 }
 
@@ -692,10 +918,12 @@ type sessionItemRow struct {
 	position     int
 	itemType     string
 	agentName    sql.NullString
+	userName     sql.NullString
 	messageJSON  sql.NullString
 	implicit     bool
 	subsessionID sql.NullString
 	summaryText  sql.NullString
+	recordJSON   sql.NullString
 }
 
 // loadSessionItems loads all items for a session from the session_items table.
@@ -707,9 +935,20 @@ func (s *SQLiteSessionStore) loadSessionItems(ctx context.Context, sessionID str
 
 // loadSessionItemsWith loads items using the provided querier (db or tx).
 func (s *SQLiteSessionStore) loadSessionItemsWith(ctx context.Context, q querier, sessionID string) ([]Item, error) {
+	return s.loadSessionItemsPage(ctx, q, sessionID, Page{})
+}
+
+// loadSessionItemsPage loads a page of the items of a session using the
+// provided querier (db or tx).
+func (s *SQLiteSessionStore) loadSessionItemsPage(ctx context.Context, q querier, sessionID string, page Page) ([]Item, error) {
+	// SQLite requires a LIMIT with an OFFSET: -1 doesn't limit the rows.
+	limit := -1
+	if page.Limit > 0 {
+		limit = page.Limit
+	}
 	rows, err := q.QueryContext(ctx,
-		`SELECT position, item_type, agent_name, message_json, implicit, subsession_id, summary_text
-		 FROM session_items WHERE session_id = ? ORDER BY position`, sessionID)
+		`SELECT position, item_type, agent_name, user_name, message_json, implicit, subsession_id, summary_text, record_json
+		 FROM session_items WHERE session_id = ? ORDER BY position LIMIT ? OFFSET ?`, sessionID, limit, max(page.Offset, 0))
 	if err != nil {
 		return nil, err
 	}
@@ -719,7 +958,7 @@ func (s *SQLiteSessionStore) loadSessionItemsWith(ctx context.Context, q querier
 	var rawRows []sessionItemRow
 	for rows.Next() {
 		var row sessionItemRow
-		if err := rows.Scan(&row.position, &row.itemType, &row.agentName, &row.messageJSON, &row.implicit, &row.subsessionID, &row.summaryText); err != nil {
+		if err := rows.Scan(&row.position, &row.itemType, &row.agentName, &row.userName, &row.messageJSON, &row.implicit, &row.subsessionID, &row.summaryText, &row.recordJSON); err != nil {
 			rows.Close()
 			return nil, err
 		}
@@ -733,7 +972,12 @@ func (s *SQLiteSessionStore) loadSessionItemsWith(ctx context.Context, q querier
 
 	// If no session_items found, fall back to legacy messages column
 	if len(rawRows) == 0 {
-		return s.loadMessagesFromLegacyColumn(ctx, sessionID)
+		items, err := s.loadMessagesFromLegacyColumn(ctx, q, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		start, end := page.apply(len(items))
+		return items[start:end], nil
 	}
 
 	// Now process the collected rows, making recursive calls as needed
@@ -741,13 +985,18 @@ func (s *SQLiteSessionStore) loadSessionItemsWith(ctx context.Context, q querier
 	for _, row := range rawRows {
 		switch row.itemType {
 		case "message":
+			msgJSON, err := s.open(row.messageJSON.String)
+			if err != nil {
+				return nil, fmt.Errorf("reading message at position %d: %w", row.position, err)
+			}
 			var chatMsg chat.Message
-			if err := json.Unmarshal([]byte(row.messageJSON.String), &chatMsg); err != nil {
+			if err := json.Unmarshal([]byte(msgJSON), &chatMsg); err != nil {
 				return nil, fmt.Errorf("unmarshaling message at position %d: %w", row.position, err)
 			}
 			items = append(items, Item{
 				Message: &Message{
 					AgentName: row.agentName.String,
+					User:      row.userName.String,
 					Message:   chatMsg,
 					Implicit:  row.implicit,
 				},
@@ -773,7 +1022,22 @@ func (s *SQLiteSessionStore) loadSessionItemsWith(ctx context.Context, q querier
 			items = append(items, Item{SubSession: subSession})
 
 		case "summary":
-			items = append(items, Item{Summary: row.summaryText.String})
+			summary, err := s.open(row.summaryText.String)
+			if err != nil {
+				return nil, fmt.Errorf("reading summary at position %d: %w", row.position, err)
+			}
+			items = append(items, Item{Summary: summary})
+
+		case "record":
+			recordJSON, err := s.open(row.recordJSON.String)
+			if err != nil {
+				return nil, fmt.Errorf("reading record at position %d: %w", row.position, err)
+			}
+			var record Record
+			if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+				return nil, fmt.Errorf("unmarshaling record at position %d: %w", row.position, err)
+			}
+			items = append(items, Item{Record: &record})
 		}
 	}
 
@@ -783,9 +1047,9 @@ func (s *SQLiteSessionStore) loadSessionItemsWith(ctx context.Context, q querier
 // loadSessionWith loads a session using the provided querier.
 func (s *SQLiteSessionStore) loadSessionWith(ctx context.Context, q querier, id string) (*Session, error) {
 	row := q.QueryRowContext(ctx,
-		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id FROM sessions WHERE id = ?", id)
+		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, working_dirs, artifacts, blackboard, config_hash FROM sessions WHERE id = ?", id)
 
-	sess, err := scanSession(row)
+	sess, err := s.scanSession(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -806,9 +1070,9 @@ func (s *SQLiteSessionStore) loadSessionWith(ctx context.Context, q querier, id
 // loadMessagesFromLegacyColumn loads messages from the legacy messages JSON column.
 // This is used for backward compatibility with sessions created by older docker agent versions
 // that haven't been migrated to the session_items table yet.
-func (s *SQLiteSessionStore) loadMessagesFromLegacyColumn(ctx context.Context, sessionID string) ([]Item, error) {
+func (s *SQLiteSessionStore) loadMessagesFromLegacyColumn(ctx context.Context, q querier, sessionID string) ([]Item, error) {
 	var messagesJSON sql.NullString
-	err := s.db.QueryRowContext(ctx, "SELECT messages FROM sessions WHERE id = ?", sessionID).Scan(&messagesJSON)
+	err := q.QueryRowContext(ctx, "SELECT messages FROM sessions WHERE id = ?", sessionID).Scan(&messagesJSON)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -827,8 +1091,13 @@ func (s *SQLiteSessionStore) loadMessagesFromLegacyColumn(ctx context.Context, s
 		return nil, nil
 	}
 
+	messages, err := s.open(messagesJSON.String)
+	if err != nil {
+		return nil, fmt.Errorf("reading legacy messages: %w", err)
+	}
+
 	var items []Item
-	if err := json.Unmarshal([]byte(messagesJSON.String), &items); err != nil {
+	if err := json.Unmarshal([]byte(messages), &items); err != nil {
 		return nil, fmt.Errorf("unmarshaling legacy messages: %w", err)
 	}
 
@@ -838,7 +1107,7 @@ func (s *SQLiteSessionStore) loadMessagesFromLegacyColumn(ctx context.Context, s
 // GetSessions retrieves all root sessions (excludes sub-sessions)
 func (s *SQLiteSessionStore) GetSessions(ctx context.Context) ([]*Session, error) {
 	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id FROM sessions WHERE parent_id IS NULL OR parent_id = '' ORDER BY created_at DESC")
+		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, working_dirs, artifacts, blackboard, config_hash FROM sessions WHERE parent_id IS NULL OR parent_id = '' ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -847,7 +1116,7 @@ func (s *SQLiteSessionStore) GetSessions(ctx context.Context) ([]*Session, error
 	// Collect sessions first to close the rows before loading items
 	var sessions []*Session
 	for rows.Next() {
-		session, err := scanSession(rows)
+		session, err := s.scanSession(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -872,12 +1141,47 @@ func (s *SQLiteSessionStore) GetSessions(ctx context.Context) ([]*Session, error
 // GetSessionSummaries retrieves lightweight session metadata for listing (excludes sub-sessions).
 // This is much faster than GetSessions as it doesn't load message content.
 func (s *SQLiteSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary, error) {
+	return s.ListSessionSummaries(ctx, Page{}, SummaryFilter{})
+}
+
+// ListSessionSummaries retrieves a page of the metadata of the root sessions
+// selected by the filter, most recent first.
+func (s *SQLiteSessionStore) ListSessionSummaries(ctx context.Context, page Page, filter SummaryFilter) ([]Summary, error) {
+	where := "(s.parent_id IS NULL OR s.parent_id = '')"
+	var args []any
+	if filter.Query != "" {
+		where += ` AND s.title LIKE ? ESCAPE '\'`
+		args = append(args, "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+	if filter.WorkingDir != "" {
+		where += " AND s.working_dir = ?"
+		args = append(args, filter.WorkingDir)
+	}
+	if filter.Starred {
+		where += " AND s.starred = 1"
+	}
+	if filter.Restricted {
+		where += ` AND (COALESCE(json_extract(s.members, '$.owner'), '') IN ('', ?)
+		            OR EXISTS (SELECT 1 FROM json_each(s.members, '$.roles') WHERE key = ?))`
+		args = append(args, filter.Member, filter.Member)
+	}
+	limit := -1
+	if page.Limit > 0 {
+		limit = page.Limit
+	}
+	args = append(args, limit, max(page.Offset, 0))
+
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT s.id, s.title, s.created_at, s.starred,
-		        (SELECT COUNT(*) FROM session_items si WHERE si.session_id = s.id AND si.item_type = 'message')
+		        (SELECT COUNT(*) FROM session_items si WHERE si.session_id = s.id AND si.item_type = 'message'),
+		        COALESCE((SELECT si.agent_name FROM session_items si
+		                  WHERE si.session_id = s.id AND si.item_type = 'message' AND si.agent_name != ''
+		                  ORDER BY si.position DESC LIMIT 1), ''),
+		        COALESCE(s.working_dir, ''), COALESCE(s.input_tokens, 0), COALESCE(s.output_tokens, 0), COALESCE(s.cost, 0)
 		 FROM sessions s
-		 WHERE s.parent_id IS NULL OR s.parent_id = ''
-		 ORDER BY s.created_at DESC`)
+		 WHERE `+where+`
+		 ORDER BY s.created_at DESC
+		 LIMIT ? OFFSET ?`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -885,9 +1189,11 @@ func (s *SQLiteSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary
 
 	var summaries []Summary
 	for rows.Next() {
-		var id, title, createdAtStr, starredStr string
+		var id, title, createdAtStr, starredStr, agentName, workingDir string
 		var numMessages int
-		if err := rows.Scan(&id, &title, &createdAtStr, &starredStr, &numMessages); err != nil {
+		var inputTokens, outputTokens int64
+		var cost float64
+		if err := rows.Scan(&id, &title, &createdAtStr, &starredStr, &numMessages, &agentName, &workingDir, &inputTokens, &outputTokens, &cost); err != nil {
 			return nil, err
 		}
 		createdAt, err := time.Parse(time.RFC3339, createdAtStr)
@@ -899,11 +1205,16 @@ func (s *SQLiteSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary
 			return nil, err
 		}
 		summaries = append(summaries, Summary{
-			ID:          id,
-			Title:       title,
-			CreatedAt:   createdAt,
-			Starred:     starred,
-			NumMessages: numMessages,
+			ID:           id,
+			Title:        title,
+			CreatedAt:    createdAt,
+			Starred:      starred,
+			NumMessages:  numMessages,
+			Agent:        agentName,
+			WorkingDir:   workingDir,
+			InputTokens:  inputTokens,
+			OutputTokens: outputTokens,
+			Cost:         cost,
 		})
 	}
 
@@ -914,6 +1225,30 @@ func (s *SQLiteSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary
 	return summaries, nil
 }
 
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetSessionItems retrieves a page of the items of a session.
+func (s *SQLiteSessionStore) GetSessionItems(ctx context.Context, id string, offset, limit int) ([]Item, error) {
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+
+	var exists int
+	if err := s.db.QueryRowContext(ctx, "SELECT 1 FROM sessions WHERE id = ?", id).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	items, err := s.loadSessionItemsPage(ctx, s.db, id, Page{Offset: offset, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("loading session items: %w", err)
+	}
+	return items, nil
+}
+
 // DeleteSession deletes a session by ID
 func (s *SQLiteSessionStore) DeleteSession(ctx context.Context, id string) error {
 	if id == "" {
@@ -974,6 +1309,29 @@ func (s *SQLiteSessionStore) UpdateSession(ctx context.Context, session *Session
 		customModelsUsedJSON = string(customBytes)
 	}
 
+	workingDirsJSON := "[]"
+	if len(session.WorkingDirs) > 0 {
+		dirsBytes, err := json.Marshal(session.WorkingDirs)
+		if err != nil {
+			return err
+		}
+		workingDirsJSON = string(dirsBytes)
+	}
+
+	artifactsJSON := "[]"
+	if len(session.Artifacts) > 0 {
+		artifactsBytes, err := json.Marshal(session.Artifacts)
+		if err != nil {
+			return err
+		}
+		artifactsJSON = string(artifactsBytes)
+	}
+
+	blackboardJSON, err := s.sealBlackboard(session.Blackboard)
+	if err != nil {
+		return err
+	}
+
 	// Use NULL for empty parent_id to avoid foreign key constraint issues
 	var parentID any
 	if session.ParentID != "" {
@@ -991,9 +1349,9 @@ func (s *SQLiteSessionStore) UpdateSession(ctx context.Context, session *Session
 		`INSERT INTO sessions (
 			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
 			max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides,
-			custom_models_used, thinking, parent_id
+			custom_models_used, thinking, parent_id, working_dirs, artifacts, blackboard, config_hash
 		)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(id) DO UPDATE SET
 		   title = excluded.title,
 		   tools_approved = excluded.tools_approved,
@@ -1008,11 +1366,15 @@ func (s *SQLiteSessionStore) UpdateSession(ctx context.Context, session *Session
 		   agent_model_overrides = excluded.agent_model_overrides,
 		   custom_models_used = excluded.custom_models_used,
 		   thinking = excluded.thinking,
-		   parent_id = excluded.parent_id`,
+		   parent_id = excluded.parent_id,
+		   working_dirs = excluded.working_dirs,
+		   artifacts = excluded.artifacts,
+		   blackboard = excluded.blackboard,
+		   config_hash = excluded.config_hash`,
 		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens,
 		session.Title, session.Cost, session.SendUserMessage, session.MaxIterations, session.WorkingDir,
 		session.CreatedAt.Format(time.RFC3339), session.Starred, permissionsJSON, agentModelOverridesJSON,
-		customModelsUsedJSON, session.Thinking, parentID)
+		customModelsUsedJSON, session.Thinking, parentID, workingDirsJSON, artifactsJSON, blackboardJSON, session.ConfigHash)
 	if err != nil {
 		return err
 	}
@@ -1058,16 +1420,16 @@ func (s *SQLiteSessionStore) AddMessage(ctx context.Context, sessionID string, m
 		return 0, ErrEmptyID
 	}
 
-	msgJSON, err := json.Marshal(msg.Message)
+	msgJSON, err := s.sealJSON(msg.Message)
 	if err != nil {
 		return 0, fmt.Errorf("marshaling message: %w", err)
 	}
 
 	// Insert a new message at the next position
 	result, err := s.db.ExecContext(ctx,
-		`INSERT INTO session_items (session_id, position, item_type, agent_name, message_json, implicit)
-		 VALUES (?, (SELECT COALESCE(MAX(position), -1) + 1 FROM session_items WHERE session_id = ?), 'message', ?, ?, ?)`,
-		sessionID, sessionID, msg.AgentName, string(msgJSON), msg.Implicit)
+		`INSERT INTO session_items (session_id, position, item_type, agent_name, user_name, message_json, implicit)
+		 VALUES (?, (SELECT COALESCE(MAX(position), -1) + 1 FROM session_items WHERE session_id = ?), 'message', ?, ?, ?, ?)`,
+		sessionID, sessionID, msg.AgentName, msg.User, msgJSON, msg.Implicit)
 	if err != nil {
 		return 0, fmt.Errorf("inserting message: %w", err)
 	}
@@ -1077,25 +1439,20 @@ func (s *SQLiteSessionStore) AddMessage(ctx context.Context, sessionID string, m
 		return 0, fmt.Errorf("getting last insert id: %w", err)
 	}
 
-	// Update messages column for backward compatibility with older docker agent versions
-	if err := s.syncMessagesColumn(ctx, sessionID); err != nil {
-		slog.Warn("[STORE] Failed to sync messages column", "session_id", sessionID, "error", err)
-	}
-
 	slog.Debug("[STORE] AddMessage", "session_id", sessionID, "message_id", id, "role", msg.Message.Role, "agent", msg.AgentName)
 	return id, nil
 }
 
 // UpdateMessage updates an existing message by its ID.
 func (s *SQLiteSessionStore) UpdateMessage(ctx context.Context, messageID int64, msg *Message) error {
-	msgJSON, err := json.Marshal(msg.Message)
+	msgJSON, err := s.sealJSON(msg.Message)
 	if err != nil {
 		return fmt.Errorf("marshaling message: %w", err)
 	}
 
 	result, err := s.db.ExecContext(ctx,
 		`UPDATE session_items SET message_json = ?, implicit = ? WHERE id = ?`,
-		string(msgJSON), msg.Implicit, messageID)
+		msgJSON, msg.Implicit, messageID)
 	if err != nil {
 		return fmt.Errorf("updating message: %w", err)
 	}
@@ -1109,15 +1466,6 @@ func (s *SQLiteSessionStore) UpdateMessage(ctx context.Context, messageID int64,
 		return ErrNotFound
 	}
 
-	// Get session ID for this message to sync the messages column
-	var sessionID string
-	err = s.db.QueryRowContext(ctx, "SELECT session_id FROM session_items WHERE id = ?", messageID).Scan(&sessionID)
-	if err == nil {
-		if syncErr := s.syncMessagesColumn(ctx, sessionID); syncErr != nil {
-			slog.Warn("[STORE] Failed to sync messages column", "session_id", sessionID, "error", syncErr)
-		}
-	}
-
 	return nil
 }
 
@@ -1159,14 +1507,6 @@ func (s *SQLiteSessionStore) AddSubSession(ctx context.Context, parentSessionID
 		return fmt.Errorf("inserting subsession reference: %w", err)
 	}
 
-	// 5. Update messages column for both parent and sub-session for backward compatibility
-	if err := s.syncMessagesColumnTx(ctx, tx, parentSessionID); err != nil {
-		slog.Warn("[STORE] Failed to sync parent messages column", "session_id", parentSessionID, "error", err)
-	}
-	if err := s.syncMessagesColumnTx(ctx, tx, subSession.ID); err != nil {
-		slog.Warn("[STORE] Failed to sync sub-session messages column", "session_id", subSession.ID, "error", err)
-	}
-
 	return tx.Commit()
 }
 
@@ -1199,23 +1539,46 @@ func (s *SQLiteSessionStore) addSessionTx(ctx context.Context, tx *sql.Tx, sessi
 		customModelsUsedJSON = string(customBytes)
 	}
 
+	workingDirsJSON := "[]"
+	if len(session.WorkingDirs) > 0 {
+		dirsBytes, err := json.Marshal(session.WorkingDirs)
+		if err != nil {
+			return err
+		}
+		workingDirsJSON = string(dirsBytes)
+	}
+
+	artifactsJSON := "[]"
+	if len(session.Artifacts) > 0 {
+		artifactsBytes, err := json.Marshal(session.Artifacts)
+		if err != nil {
+			return err
+		}
+		artifactsJSON = string(artifactsBytes)
+	}
+
+	blackboardJSON, err := s.sealBlackboard(session.Blackboard)
+	if err != nil {
+		return err
+	}
+
 	// Use NULL for empty parent_id to avoid foreign key constraint issues
 	var parentID any
 	if session.ParentID != "" {
 		parentID = session.ParentID
 	}
-	_, err := tx.ExecContext(ctx,
+	_, err = tx.ExecContext(ctx,
 		`INSERT INTO sessions (
 			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
 			max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides,
-			custom_models_used, thinking, parent_id
+			custom_models_used, thinking, parent_id, working_dirs, artifacts, blackboard, config_hash
 		)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens,
 		session.Title, session.Cost, session.SendUserMessage, session.MaxIterations,
 		session.WorkingDir, session.CreatedAt.Format(time.RFC3339), session.Starred,
 		permissionsJSON, agentModelOverridesJSON, customModelsUsedJSON, session.Thinking,
-		parentID)
+		parentID, workingDirsJSON, artifactsJSON, blackboardJSON, session.ConfigHash)
 	return err
 }
 
@@ -1223,14 +1586,14 @@ func (s *SQLiteSessionStore) addSessionTx(ctx context.Context, tx *sql.Tx, sessi
 func (s *SQLiteSessionStore) addItemTx(ctx context.Context, tx *sql.Tx, sessionID string, position int, item Item) error {
 	switch {
 	case item.Message != nil:
-		msgJSON, err := json.Marshal(item.Message.Message)
+		msgJSON, err := s.sealJSON(item.Message.Message)
 		if err != nil {
 			return fmt.Errorf("marshaling message: %w", err)
 		}
 		_, err = tx.ExecContext(ctx,
-			`INSERT INTO session_items (session_id, position, item_type, agent_name, message_json, implicit)
-			 VALUES (?, ?, 'message', ?, ?, ?)`,
-			sessionID, position, item.Message.AgentName, string(msgJSON), item.Message.Implicit)
+			`INSERT INTO session_items (session_id, position, item_type, agent_name, user_name, message_json, implicit)
+			 VALUES (?, ?, 'message', ?, ?, ?, ?)`,
+			sessionID, position, item.Message.AgentName, item.Message.User, msgJSON, item.Message.Implicit)
 		return err
 
 	case item.SubSession != nil:
@@ -1255,10 +1618,25 @@ func (s *SQLiteSessionStore) addItemTx(ctx context.Context, tx *sql.Tx, sessionI
 		return err
 
 	case item.Summary != "":
-		_, err := tx.ExecContext(ctx,
+		summary, err := s.seal(item.Summary)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
 			`INSERT INTO session_items (session_id, position, item_type, summary_text)
 			 VALUES (?, ?, 'summary', ?)`,
-			sessionID, position, item.Summary)
+			sessionID, position, summary)
+		return err
+
+	case item.Record != nil:
+		recordJSON, err := s.sealJSON(item.Record)
+		if err != nil {
+			return fmt.Errorf("marshaling record: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO session_items (session_id, position, item_type, agent_name, record_json)
+			 VALUES (?, ?, 'record', ?, ?)`,
+			sessionID, position, item.Record.AgentName, recordJSON)
 		return err
 
 	default:
@@ -1272,7 +1650,12 @@ func (s *SQLiteSessionStore) AddSummary(ctx context.Context, sessionID, summary
 		return ErrEmptyID
 	}
 
-	_, err := s.db.ExecContext(ctx,
+	summary, err := s.seal(summary)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
 		`INSERT INTO session_items (session_id, position, item_type, summary_text)
 		 VALUES (?, (SELECT COALESCE(MAX(position), -1) + 1 FROM session_items WHERE session_id = ?), 'summary', ?)`,
 		sessionID, sessionID, summary)
@@ -1280,9 +1663,26 @@ func (s *SQLiteSessionStore) AddSummary(ctx context.Context, sessionID, summary
 		return err
 	}
 
-	// Update messages column for backward compatibility with older docker agent versions
-	if syncErr := s.syncMessagesColumn(ctx, sessionID); syncErr != nil {
-		slog.Warn("[STORE] Failed to sync messages column", "session_id", sessionID, "error", syncErr)
+	return nil
+}
+
+// AddRecord adds a record item to a session at the next position.
+func (s *SQLiteSessionStore) AddRecord(ctx context.Context, sessionID string, record *Record) error {
+	if sessionID == "" {
+		return ErrEmptyID
+	}
+
+	recordJSON, err := s.sealJSON(record)
+	if err != nil {
+		return fmt.Errorf("marshaling record: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO session_items (session_id, position, item_type, agent_name, record_json)
+		 VALUES (?, (SELECT COALESCE(MAX(position), -1) + 1 FROM session_items WHERE session_id = ?), 'record', ?, ?)`,
+		sessionID, sessionID, record.AgentName, recordJSON)
+	if err != nil {
+		return err
 	}
 
 	return nil
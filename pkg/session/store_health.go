@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/sqliteutil"
+)
+
+// StoreHealth describes the state of a SQLite session database.
+type StoreHealth struct {
+	// Exists is false when the database hasn't been created yet.
+	Exists bool
+	// Size is the size of the database file, in bytes.
+	Size int64
+	// Sessions is the number of sessions in the database.
+	Sessions int
+	// PendingMigrations lists the migrations that will be applied the next
+	// time the database is opened.
+	PendingMigrations []string
+	// Integrity lists the problems found by SQLite's integrity check.
+	Integrity []string
+}
+
+// CheckSQLiteSessionStore inspects the session database at the given path.
+// Unlike NewSQLiteSessionStore, it opens the database read-only: it neither
+// creates nor migrates it, and doesn't reset a database it can't read.
+func CheckSQLiteSessionStore(ctx context.Context, path string) (*StoreHealth, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &StoreHealth{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	health := &StoreHealth{Exists: true, Size: info.Size()}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		if sqliteutil.IsCantOpenError(err) {
+			return nil, sqliteutil.DiagnoseDBOpenError(path, err)
+		}
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "PRAGMA quick_check")
+	if err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, err
+		}
+		if result != "ok" {
+			health.Integrity = append(health.Integrity, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sessions").Scan(&health.Sessions); err != nil && !isNoSuchTable(err) {
+		return nil, fmt.Errorf("counting sessions: %w", err)
+	}
+
+	applied := map[string]bool{}
+	migrations, err := NewMigrationManager(db).GetAppliedMigrations(ctx)
+	if err != nil && !isNoSuchTable(err) {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+	for _, migration := range migrations {
+		applied[migration.Name] = true
+	}
+	for _, migration := range getAllMigrations() {
+		if !applied[migration.Name] {
+			health.PendingMigrations = append(health.PendingMigrations, migration.Name)
+		}
+	}
+
+	return health, nil
+}
+
+func isNoSuchTable(err error) bool {
+	return strings.Contains(err.Error(), "no such table")
+}
@@ -3,8 +3,10 @@ package session
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -197,7 +199,11 @@ func TestGetSessionSummaries(t *testing.T) {
 				Content: "Another long message that should not be loaded when getting summaries",
 			})),
 		},
-		CreatedAt: session2Time,
+		CreatedAt:    session2Time,
+		WorkingDir:   "/work/project",
+		InputTokens:  1200,
+		OutputTokens: 300,
+		Cost:         0.25,
 	}
 
 	// Store the sessions
@@ -216,6 +222,11 @@ func TestGetSessionSummaries(t *testing.T) {
 	assert.Equal(t, "Second Session", summaries[0].Title)
 	assert.Equal(t, session2Time, summaries[0].CreatedAt)
 	assert.Equal(t, 1, summaries[0].NumMessages)
+	assert.Equal(t, "test-agent", summaries[0].Agent)
+	assert.Equal(t, "/work/project", summaries[0].WorkingDir)
+	assert.Equal(t, int64(1200), summaries[0].InputTokens)
+	assert.Equal(t, int64(300), summaries[0].OutputTokens)
+	assert.InDelta(t, 0.25, summaries[0].Cost, 1e-9)
 
 	assert.Equal(t, "session-1", summaries[1].ID)
 	assert.Equal(t, "First Session", summaries[1].Title)
@@ -569,6 +580,101 @@ func TestAgentModelOverrides_SQLite(t *testing.T) {
 	assert.Equal(t, "anthropic/claude-sonnet-4-0", retrieved.AgentModelOverrides["researcher"])
 }
 
+func TestWorkingDirs_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_working_dirs.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := New(WithWorkingDirs("/src/frontend", "/src/backend"))
+	require.NoError(t, store.AddSession(t.Context(), session))
+
+	retrieved, err := store.GetSession(t.Context(), session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "/src/frontend", retrieved.WorkingDir)
+	assert.Equal(t, []string{"/src/frontend", "/src/backend"}, retrieved.Roots())
+
+	// Sessions with a single root keep using WorkingDir only.
+	session = New(WithWorkingDirs("/src/app"))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err = store.GetSession(t.Context(), session.ID)
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.WorkingDirs)
+	assert.Equal(t, []string{"/src/app"}, retrieved.Roots())
+}
+
+func TestBlackboard(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_blackboard.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			session := New()
+			require.NoError(t, store.AddSession(t.Context(), session))
+
+			// Sub-sessions write on the blackboard of the session the user started.
+			sub := New(WithParentID(session.ID), WithBlackboardOf(session))
+			sub.BlackboardSession().PostToBlackboard("api/auth", "OAuth with PKCE", "researcher")
+			session.PostToBlackboard("api/auth", "OAuth with PKCE, tokens in Redis", "root")
+			session.PostToBlackboard("tests/failures", "none", "tester")
+			require.NoError(t, store.UpdateSession(t.Context(), session))
+
+			retrieved, err := store.GetSession(t.Context(), session.ID)
+			require.NoError(t, err)
+			entries := retrieved.GetBlackboard("api/")
+			require.Len(t, entries, 1)
+			assert.Equal(t, "OAuth with PKCE, tokens in Redis", entries[0].Value)
+			assert.Equal(t, "root", entries[0].AgentName)
+			assert.Equal(t, 2, entries[0].Version)
+			assert.Len(t, retrieved.GetBlackboard(""), 2)
+			assert.Empty(t, sub.Blackboard)
+
+			assert.True(t, retrieved.RemoveFromBlackboard("tests/failures"))
+			assert.False(t, retrieved.RemoveFromBlackboard("tests/failures"))
+			assert.Len(t, retrieved.GetBlackboard(""), 1)
+		})
+	}
+}
+
+func TestArtifacts_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_artifacts.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := New()
+	require.NoError(t, store.AddSession(t.Context(), session))
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	session.AddArtifact(Artifact{ID: "1", Name: "report.md", Path: "/work/report.md", Size: 12, CreatedAt: createdAt})
+	session.AddArtifact(Artifact{ID: "2", Name: "chart.png", Path: "/work/chart.png", MimeType: "image/png", ToolName: "plot", CreatedAt: createdAt})
+	// Registering the same file again replaces it.
+	session.AddArtifact(Artifact{ID: "3", Name: "report.md", Path: "/work/report.md", Size: 42, Description: "Final report", CreatedAt: createdAt})
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err := store.GetSession(t.Context(), session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []Artifact{
+		{ID: "1", Name: "report.md", Path: "/work/report.md", Size: 42, Description: "Final report", CreatedAt: createdAt},
+		{ID: "2", Name: "chart.png", Path: "/work/chart.png", MimeType: "image/png", ToolName: "plot", CreatedAt: createdAt},
+	}, retrieved.Artifacts)
+
+	artifact, ok := retrieved.GetArtifact("2")
+	require.True(t, ok)
+	assert.Equal(t, "chart.png", artifact.Name)
+	_, ok = retrieved.GetArtifact("3")
+	assert.False(t, ok)
+}
+
 func TestAgentModelOverrides_Update(t *testing.T) {
 	tempDB := filepath.Join(t.TempDir(), "test_model_overrides_update.db")
 
@@ -838,10 +944,11 @@ func TestBackwardCompatibility_ReadLegacyMessages(t *testing.T) {
 	assert.Equal(t, "Hi from legacy agent!", retrieved.Messages[1].Message.Message.Content)
 }
 
-// TestForwardCompatibility_MessagesColumnPopulated verifies that new code populates
-// the messages column so older docker agent versions can read sessions.
-func TestForwardCompatibility_MessagesColumnPopulated(t *testing.T) {
-	tempDB := filepath.Join(t.TempDir(), "test_forward.db")
+// TestMessagesColumnNotRewritten verifies that adding items to a session
+// appends rows to session_items without serializing the whole session into
+// the legacy messages column.
+func TestMessagesColumnNotRewritten(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_append_only.db")
 
 	store, err := NewSQLiteSessionStore(tempDB)
 	require.NoError(t, err)
@@ -849,143 +956,66 @@ func TestForwardCompatibility_MessagesColumnPopulated(t *testing.T) {
 
 	sqliteStore := store.(*SQLiteSessionStore)
 
-	// Create a session using the new API
-	session := &Session{
-		ID:        "new-session",
-		CreatedAt: time.Now(),
-	}
-	err = store.AddSession(t.Context(), session)
+	err = store.AddSession(t.Context(), &Session{ID: "parent-session", CreatedAt: time.Now()})
 	require.NoError(t, err)
 
-	// Add messages using the new granular API
-	_, err = store.AddMessage(t.Context(), "new-session", UserMessage("Hello from new code"))
+	id, err := store.AddMessage(t.Context(), "parent-session", UserMessage("Start task"))
 	require.NoError(t, err)
-
-	_, err = store.AddMessage(t.Context(), "new-session", &Message{
-		AgentName: "new-agent",
-		Message: chat.Message{
-			Role:    chat.MessageRoleAssistant,
-			Content: "Response from new agent",
-		},
-	})
-	require.NoError(t, err)
-
-	// Verify messages column is populated (how old docker agent would read it)
-	var messagesJSON string
-	err = sqliteStore.db.QueryRowContext(t.Context(),
-		"SELECT messages FROM sessions WHERE id = ?", "new-session").Scan(&messagesJSON)
-	require.NoError(t, err)
-	assert.NotEmpty(t, messagesJSON)
-	assert.NotEqual(t, "[]", messagesJSON)
-
-	// Parse and verify the messages column content
-	var items []Item
-	err = json.Unmarshal([]byte(messagesJSON), &items)
-	require.NoError(t, err)
-
-	assert.Len(t, items, 2)
-	assert.Equal(t, "Hello from new code", items[0].Message.Message.Content)
-	assert.Equal(t, "new-agent", items[1].Message.AgentName)
-	assert.Equal(t, "Response from new agent", items[1].Message.Message.Content)
-}
-
-// TestForwardCompatibility_SubSessionPopulated verifies that sub-sessions
-// are properly serialized to the messages column for backward compatibility.
-func TestForwardCompatibility_SubSessionPopulated(t *testing.T) {
-	tempDB := filepath.Join(t.TempDir(), "test_subsession.db")
-
-	store, err := NewSQLiteSessionStore(tempDB)
-	require.NoError(t, err)
-	defer store.(*SQLiteSessionStore).Close()
-
-	sqliteStore := store.(*SQLiteSessionStore)
-
-	// Create parent session
-	parentSession := &Session{
-		ID:        "parent-session",
-		CreatedAt: time.Now(),
-	}
-	err = store.AddSession(t.Context(), parentSession)
-	require.NoError(t, err)
-
-	// Add a message to parent
-	_, err = store.AddMessage(t.Context(), "parent-session", UserMessage("Start task"))
-	require.NoError(t, err)
-
-	// Create and add a sub-session
-	subSession := &Session{
+	require.NoError(t, store.UpdateMessage(t.Context(), id, UserMessage("Start the task")))
+	require.NoError(t, store.AddSummary(t.Context(), "parent-session", "This is a summary of the conversation."))
+	require.NoError(t, store.AddSubSession(t.Context(), "parent-session", &Session{
 		ID:        "sub-session",
 		CreatedAt: time.Now(),
-		Messages: []Item{
-			NewMessageItem(UserMessage("Sub task")),
-			NewMessageItem(&Message{
-				AgentName: "sub-agent",
-				Message: chat.Message{
-					Role:    chat.MessageRoleAssistant,
-					Content: "Sub response",
-				},
-			}),
-		},
-	}
-	err = store.AddSubSession(t.Context(), "parent-session", subSession)
-	require.NoError(t, err)
+		Messages:  []Item{NewMessageItem(UserMessage("Sub task"))},
+	}))
 
-	// Verify parent's messages column contains the sub-session
-	var messagesJSON string
-	err = sqliteStore.db.QueryRowContext(t.Context(),
-		"SELECT messages FROM sessions WHERE id = ?", "parent-session").Scan(&messagesJSON)
-	require.NoError(t, err)
+	for _, id := range []string{"parent-session", "sub-session"} {
+		var messagesJSON sql.NullString
+		err = sqliteStore.db.QueryRowContext(t.Context(),
+			"SELECT messages FROM sessions WHERE id = ?", id).Scan(&messagesJSON)
+		require.NoError(t, err)
+		assert.False(t, messagesJSON.Valid, "the messages column of %s is left empty", id)
+	}
 
-	var items []Item
-	err = json.Unmarshal([]byte(messagesJSON), &items)
+	retrieved, err := store.GetSession(t.Context(), "parent-session")
 	require.NoError(t, err)
-
-	assert.Len(t, items, 2) // user message + subsession
-	assert.Equal(t, "Start task", items[0].Message.Message.Content)
-	assert.NotNil(t, items[1].SubSession)
-	assert.Equal(t, "sub-session", items[1].SubSession.ID)
-	assert.Len(t, items[1].SubSession.Messages, 2)
+	require.Len(t, retrieved.Messages, 3)
+	assert.Equal(t, "Start the task", retrieved.Messages[0].Message.Message.Content)
+	assert.Equal(t, "This is a summary of the conversation.", retrieved.Messages[1].Summary)
+	require.NotNil(t, retrieved.Messages[2].SubSession)
+	assert.Len(t, retrieved.Messages[2].SubSession.Messages, 1)
 }
 
-// TestForwardCompatibility_SummaryPopulated verifies that summaries
-// are properly serialized to the messages column for backward compatibility.
-func TestForwardCompatibility_SummaryPopulated(t *testing.T) {
-	tempDB := filepath.Join(t.TempDir(), "test_summary.db")
+// TestGetSessionMetadata verifies that the metadata of a session is read
+// without its items, and that updating it leaves the items alone.
+func TestGetSessionMetadata(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_metadata.db")
 
 	store, err := NewSQLiteSessionStore(tempDB)
 	require.NoError(t, err)
 	defer store.(*SQLiteSessionStore).Close()
 
-	sqliteStore := store.(*SQLiteSessionStore)
-
-	// Create session
-	session := &Session{
-		ID:        "summary-session",
-		CreatedAt: time.Now(),
+	require.NoError(t, store.AddSession(t.Context(), &Session{ID: "session", Title: "Long session", CreatedAt: time.Now()}))
+	for range 3 {
+		_, err = store.AddMessage(t.Context(), "session", UserMessage("Hello"))
+		require.NoError(t, err)
 	}
-	err = store.AddSession(t.Context(), session)
-	require.NoError(t, err)
 
-	// Add messages and a summary
-	_, err = store.AddMessage(t.Context(), "summary-session", UserMessage("Hello"))
+	sess, err := store.GetSessionMetadata(t.Context(), "session")
 	require.NoError(t, err)
+	assert.Equal(t, "Long session", sess.Title)
+	assert.Empty(t, sess.Messages)
 
-	err = store.AddSummary(t.Context(), "summary-session", "This is a summary of the conversation.")
-	require.NoError(t, err)
+	sess.Title = "Renamed"
+	require.NoError(t, store.UpdateSession(t.Context(), sess))
 
-	// Verify messages column contains the summary
-	var messagesJSON string
-	err = sqliteStore.db.QueryRowContext(t.Context(),
-		"SELECT messages FROM sessions WHERE id = ?", "summary-session").Scan(&messagesJSON)
+	retrieved, err := store.GetSession(t.Context(), "session")
 	require.NoError(t, err)
+	assert.Equal(t, "Renamed", retrieved.Title)
+	assert.Len(t, retrieved.Messages, 3)
 
-	var items []Item
-	err = json.Unmarshal([]byte(messagesJSON), &items)
-	require.NoError(t, err)
-
-	assert.Len(t, items, 2)
-	assert.Equal(t, "Hello", items[0].Message.Message.Content)
-	assert.Equal(t, "This is a summary of the conversation.", items[1].Summary)
+	_, err = store.GetSessionMetadata(t.Context(), "unknown")
+	require.ErrorIs(t, err, ErrNotFound)
 }
 
 // TestOrphanedSubsessionReference verifies that loading sessions gracefully
@@ -1099,6 +1129,13 @@ func TestMigration_ExistingMessagesToSessionItems(t *testing.T) {
 	assert.Len(t, retrieved.Messages, 2)
 	assert.Equal(t, "Legacy message 1", retrieved.Messages[0].Message.Message.Content)
 	assert.Equal(t, "legacy-agent", retrieved.Messages[1].Message.AgentName)
+
+	// The legacy column is kept, for older docker agent versions to read
+	var messagesJSON sql.NullString
+	err = store.(*SQLiteSessionStore).db.QueryRowContext(t.Context(),
+		"SELECT messages FROM sessions WHERE id = ?", "migration-test-session").Scan(&messagesJSON)
+	require.NoError(t, err)
+	assert.Contains(t, messagesJSON.String, "Legacy message 1")
 }
 
 func TestParseRelativeSessionRef(t *testing.T) {
@@ -1278,3 +1315,333 @@ func TestResolveSessionID_InMemory(t *testing.T) {
 		assert.Equal(t, "some-uuid", id)
 	})
 }
+
+func TestLatestSessionInDir(t *testing.T) {
+	store := NewInMemorySessionStore()
+
+	baseTime := time.Now()
+	for _, s := range []*Session{
+		{ID: "old-project", WorkingDir: "/work/project", CreatedAt: baseTime.Add(-3 * time.Hour)},
+		{ID: "project", WorkingDir: "/work/project", CreatedAt: baseTime.Add(-2 * time.Hour)},
+		{ID: "other", WorkingDir: "/work/other", CreatedAt: baseTime.Add(-1 * time.Hour)},
+	} {
+		require.NoError(t, store.AddSession(t.Context(), s))
+	}
+
+	id, err := LatestSessionInDir(t.Context(), store, "/work/project/")
+	require.NoError(t, err)
+	assert.Equal(t, "project", id)
+
+	id, err = LatestSessionInDir(t.Context(), store, "/work/other")
+	require.NoError(t, err)
+	assert.Equal(t, "other", id)
+
+	_, err = LatestSessionInDir(t.Context(), store, "/work/new")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAddRecord(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_record.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			session := &Session{
+				ID:        "record-session-" + name,
+				CreatedAt: time.Now(),
+			}
+			require.NoError(t, store.AddSession(t.Context(), session))
+
+			_, err := store.AddMessage(t.Context(), session.ID, UserMessage("Delete the build directory"))
+			require.NoError(t, err)
+
+			record := &Record{
+				AgentName: "root",
+				CreatedAt: time.Now(),
+				ToolApproval: &ToolApprovalRecord{
+					ToolCallID: "call_1",
+					ToolName:   "shell",
+					Arguments:  `{"cmd":"rm -rf build"}`,
+					Decision:   "reject",
+					Reason:     "not this one",
+				},
+			}
+			require.NoError(t, store.AddRecord(t.Context(), session.ID, record))
+
+			loaded, err := store.GetSession(t.Context(), session.ID)
+			require.NoError(t, err)
+			require.Len(t, loaded.Messages, 2)
+			require.True(t, loaded.Messages[1].IsRecord())
+			assert.Equal(t, "root", loaded.Messages[1].Record.AgentName)
+			assert.Equal(t, *record.ToolApproval, *loaded.Messages[1].Record.ToolApproval)
+
+			// Records are part of the history, but are never sent to the model
+			assert.Len(t, loaded.GetHistory(), 2)
+			assert.Len(t, loaded.GetAllMessages(), 1)
+		})
+	}
+}
+
+func TestMessageUser(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_message_user.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			session := &Session{
+				ID:        "shared-session-" + name,
+				CreatedAt: time.Now(),
+			}
+			require.NoError(t, store.AddSession(t.Context(), session))
+
+			for _, user := range []string{"alice", "bob"} {
+				msg := UserMessage("Check the logs of the API")
+				msg.User = user
+				_, err := store.AddMessage(t.Context(), session.ID, msg)
+				require.NoError(t, err)
+			}
+
+			loaded, err := store.GetSession(t.Context(), session.ID)
+			require.NoError(t, err)
+			require.Len(t, loaded.Messages, 2)
+			assert.Equal(t, "alice", loaded.Messages[0].Message.User)
+			assert.Equal(t, "bob", loaded.Messages[1].Message.User)
+			assert.Equal(t, "bob", loaded.LastUserMessageAuthor())
+		})
+	}
+}
+
+func TestShareSession(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_share.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "shared", Title: "Incident", CreatedAt: time.Now()}))
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "private", CreatedAt: time.Now()}))
+
+			token, err := store.ShareSession(t.Context(), "shared")
+			require.NoError(t, err)
+			assert.Len(t, token, 26)
+			again, err := store.ShareSession(t.Context(), "shared")
+			require.NoError(t, err)
+			assert.Equal(t, token, again, "sharing a session again keeps its link")
+
+			shared, err := store.GetSharedSession(t.Context(), token)
+			require.NoError(t, err)
+			assert.Equal(t, "Incident", shared.Title)
+
+			_, err = store.GetSharedSession(t.Context(), "")
+			require.ErrorIs(t, err, ErrNotFound)
+			_, err = store.ShareSession(t.Context(), "unknown")
+			require.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, store.UnshareSession(t.Context(), "shared"))
+			_, err = store.GetSharedSession(t.Context(), token)
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestSessionMembers(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_members.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "shared", CreatedAt: now}))
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "open", CreatedAt: now.Add(-time.Minute)}))
+
+			members, err := store.GetSessionMembers(t.Context(), "open")
+			require.NoError(t, err)
+			assert.Equal(t, Members{}, members)
+
+			require.NoError(t, store.SetSessionMembers(t.Context(), "shared", Members{Owner: "alice", Roles: map[string]string{"bob": "viewer"}}))
+			members, err = store.GetSessionMembers(t.Context(), "shared")
+			require.NoError(t, err)
+			assert.Equal(t, Members{Owner: "alice", Roles: map[string]string{"bob": "viewer"}}, members)
+
+			_, err = store.GetSessionMembers(t.Context(), "unknown")
+			require.ErrorIs(t, err, ErrNotFound)
+			require.ErrorIs(t, store.SetSessionMembers(t.Context(), "unknown", Members{}), ErrNotFound)
+
+			for user, want := range map[string][]string{
+				"alice": {"shared", "open"},
+				"bob":   {"shared", "open"},
+				"carol": {"open"},
+				"":      {"open"},
+			} {
+				summaries, err := store.ListSessionSummaries(t.Context(), Page{}, SummaryFilter{Restricted: true, Member: user})
+				require.NoError(t, err)
+				var ids []string
+				for _, summary := range summaries {
+					ids = append(ids, summary.ID)
+				}
+				assert.Equal(t, want, ids, "sessions of %q", user)
+			}
+		})
+	}
+}
+
+func TestListSessionSummaries(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_list_summaries.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			for i, title := range []string{"Fix the build", "Write the release notes", "Fix 100% of the tests", "Review the PR"} {
+				require.NoError(t, store.AddSession(t.Context(), &Session{
+					ID:         fmt.Sprintf("session-%d", i),
+					Title:      title,
+					WorkingDir: "/src/" + strings.Fields(title)[0],
+					CreatedAt:  now.Add(time.Duration(i) * time.Minute),
+				}))
+			}
+			require.NoError(t, store.SetSessionStarred(t.Context(), "session-1", true))
+
+			ids := func(summaries []Summary) []string {
+				var ids []string
+				for _, s := range summaries {
+					ids = append(ids, s.ID)
+				}
+				return ids
+			}
+
+			summaries, err := store.ListSessionSummaries(t.Context(), Page{Offset: 1, Limit: 2}, SummaryFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"session-2", "session-1"}, ids(summaries), "most recent first")
+
+			summaries, err = store.ListSessionSummaries(t.Context(), Page{}, SummaryFilter{Query: "fix"})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"session-2", "session-0"}, ids(summaries))
+
+			summaries, err = store.ListSessionSummaries(t.Context(), Page{}, SummaryFilter{Query: "100%"})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"session-2"}, ids(summaries), "wildcards are matched literally")
+
+			summaries, err = store.ListSessionSummaries(t.Context(), Page{}, SummaryFilter{WorkingDir: "/src/Review"})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"session-3"}, ids(summaries))
+
+			summaries, err = store.ListSessionSummaries(t.Context(), Page{}, SummaryFilter{Starred: true})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"session-1"}, ids(summaries))
+
+			summaries, err = store.ListSessionSummaries(t.Context(), Page{Offset: 10}, SummaryFilter{})
+			require.NoError(t, err)
+			assert.Empty(t, summaries)
+		})
+	}
+}
+
+func TestGetSessionItems(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_session_items.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "long-session", CreatedAt: time.Now()}))
+			for i := range 5 {
+				_, err := store.AddMessage(t.Context(), "long-session", UserMessage(fmt.Sprintf("message %d", i)))
+				require.NoError(t, err)
+			}
+			require.NoError(t, store.AddSummary(t.Context(), "long-session", "a summary"))
+
+			items, err := store.GetSessionItems(t.Context(), "long-session", 3, 2)
+			require.NoError(t, err)
+			require.Len(t, items, 2)
+			assert.Equal(t, "message 3", items[0].Message.Message.Content)
+			assert.Equal(t, "message 4", items[1].Message.Message.Content)
+
+			items, err = store.GetSessionItems(t.Context(), "long-session", 4, 0)
+			require.NoError(t, err)
+			require.Len(t, items, 2)
+			assert.Equal(t, "a summary", items[1].Summary)
+
+			items, err = store.GetSessionItems(t.Context(), "long-session", 10, 5)
+			require.NoError(t, err)
+			assert.Empty(t, items)
+
+			_, err = store.GetSessionItems(t.Context(), "unknown", 0, 5)
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestCheckSQLiteSessionStore(t *testing.T) {
+	t.Run("missing database", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "missing.db")
+
+		health, err := CheckSQLiteSessionStore(t.Context(), dbPath)
+		require.NoError(t, err)
+		assert.False(t, health.Exists)
+
+		_, err = os.Stat(dbPath)
+		assert.True(t, os.IsNotExist(err), "the check must not create the database")
+	})
+
+	t.Run("healthy database", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "session.db")
+		store, err := NewSQLiteSessionStore(dbPath)
+		require.NoError(t, err)
+		require.NoError(t, store.AddSession(t.Context(), &Session{ID: "s1", CreatedAt: time.Now()}))
+		require.NoError(t, store.(*SQLiteSessionStore).Close())
+
+		health, err := CheckSQLiteSessionStore(t.Context(), dbPath)
+		require.NoError(t, err)
+		assert.True(t, health.Exists)
+		assert.Positive(t, health.Size)
+		assert.Equal(t, 1, health.Sessions)
+		assert.Empty(t, health.PendingMigrations)
+		assert.Empty(t, health.Integrity)
+	})
+
+	t.Run("corrupt database is left untouched", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "session.db")
+		require.NoError(t, os.WriteFile(dbPath, []byte("not a valid sqlite database"), 0o644))
+
+		_, err := CheckSQLiteSessionStore(t.Context(), dbPath)
+		require.Error(t, err)
+
+		_, err = os.Stat(dbPath + ".bak")
+		assert.True(t, os.IsNotExist(err), "the check must not reset the database")
+	})
+}
@@ -0,0 +1,104 @@
+package session
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/docker/docker-agent/pkg/chat"
+)
+
+// traceSummaryChars is the length tool results and arguments are shortened
+// to in the summaries of the traces.
+const traceSummaryChars = 300
+
+// SubSessions returns the sub-sessions of the tasks the session delegated, in
+// the order they completed.
+func (s *Session) SubSessions() []*Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var subSessions []*Session
+	for _, item := range s.Messages {
+		if item.IsSubSession() {
+			subSessions = append(subSessions, item.SubSession)
+		}
+	}
+	return subSessions
+}
+
+// FindSubSession returns the sub-session with the given ID, among the
+// sub-sessions of the session and theirs.
+func (s *Session) FindSubSession(id string) (*Session, bool) {
+	for _, sub := range s.SubSessions() {
+		if sub.ID == id {
+			return sub, true
+		}
+		if found, ok := sub.FindSubSession(id); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// Trace returns the transcript of the session, for an agent to audit how a
+// sub-agent reached its answer. The summary only keeps the beginning of long
+// tool calls and results, and the final answer of the nested sub-sessions,
+// while the raw transcript keeps everything.
+func (s *Session) Trace(raw bool) string {
+	s.mu.RLock()
+	items := slices.Clone(s.Messages)
+	s.mu.RUnlock()
+
+	shorten := func(text string) string {
+		if raw || len(text) <= traceSummaryChars {
+			return text
+		}
+		n := traceSummaryChars
+		for n > 0 && !utf8.RuneStart(text[n]) {
+			n--
+		}
+		return text[:n] + fmt.Sprintf("... [%d more bytes]", len(text)-n)
+	}
+
+	var out strings.Builder
+	for _, item := range items {
+		switch {
+		case item.IsMessage():
+			msg := &item.Message.Message
+			switch msg.Role {
+			case chat.MessageRoleSystem:
+				fmt.Fprintf(&out, "[system]\n%s\n\n", msg.Content)
+			case chat.MessageRoleUser:
+				fmt.Fprintf(&out, "[user]\n%s\n\n", msg.Content)
+			case chat.MessageRoleAssistant:
+				if raw && msg.ReasoningContent != "" {
+					fmt.Fprintf(&out, "[%s reasoning]\n%s\n\n", item.Message.AgentName, msg.ReasoningContent)
+				}
+				if msg.Content != "" {
+					fmt.Fprintf(&out, "[%s]\n%s\n\n", item.Message.AgentName, msg.Content)
+				}
+				for _, tc := range msg.ToolCalls {
+					fmt.Fprintf(&out, "[%s called %s]\n%s\n\n", item.Message.AgentName, tc.Function.Name, shorten(tc.Function.Arguments))
+				}
+			case chat.MessageRoleTool:
+				fmt.Fprintf(&out, "[tool result]\n%s\n\n", shorten(msg.Content))
+			}
+		case item.IsSubSession():
+			sub := item.SubSession
+			if raw {
+				fmt.Fprintf(&out, "[sub-session %s]\n%s[end of sub-session %s]\n\n", sub.ID, sub.Trace(true), sub.ID)
+			} else {
+				fmt.Fprintf(&out, "[sub-session %s answered]\n%s\n\n", sub.ID, shorten(sub.GetLastAssistantMessageContent()))
+			}
+		case item.IsRecord():
+			if line := item.Record.String(); line != "" {
+				fmt.Fprintf(&out, "[%s]\n\n", line)
+			}
+		case item.Summary != "":
+			fmt.Fprintf(&out, "[summary of the earlier messages]\n%s\n\n", item.Summary)
+		}
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
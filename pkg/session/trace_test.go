@@ -0,0 +1,51 @@
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+
+	nested := New(WithSystemMessage("Read the logs"))
+	nested.AddMessage(NewAgentMessage("logs", &chat.Message{Role: chat.MessageRoleAssistant, Content: "The database timed out"}))
+
+	sub := New(WithSystemMessage("Find the cause of the outage"), WithParentID("parent"))
+	sub.AddMessage(NewAgentMessage("researcher", &chat.Message{
+		Role:      chat.MessageRoleAssistant,
+		Content:   "Let me look at the logs.",
+		ToolCalls: []tools.ToolCall{{ID: "call_1", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"journalctl"}`}}},
+	}))
+	sub.AddMessage(NewAgentMessage("researcher", &chat.Message{Role: chat.MessageRoleTool, ToolCallID: "call_1", Content: strings.Repeat("x", 1000)}))
+	sub.AddSubSession(nested)
+	sub.AddMessage(NewAgentMessage("researcher", &chat.Message{Role: chat.MessageRoleAssistant, Content: "The database is the cause."}))
+
+	parent := New()
+	parent.AddSubSession(sub)
+
+	assert.Equal(t, []*Session{sub}, parent.SubSessions())
+	found, ok := parent.FindSubSession(nested.ID)
+	require.True(t, ok)
+	assert.Same(t, nested, found)
+	_, ok = parent.FindSubSession("unknown")
+	assert.False(t, ok)
+
+	summary := sub.Trace(false)
+	assert.Contains(t, summary, "[system]\nFind the cause of the outage")
+	assert.Contains(t, summary, "[researcher called shell]\n{\"cmd\":\"journalctl\"}")
+	assert.Contains(t, summary, "... [700 more bytes]")
+	assert.Contains(t, summary, "[sub-session "+nested.ID+" answered]\nThe database timed out")
+	assert.NotContains(t, summary, "Read the logs")
+	assert.True(t, strings.HasSuffix(summary, "[researcher]\nThe database is the cause.\n"))
+
+	raw := sub.Trace(true)
+	assert.Contains(t, raw, strings.Repeat("x", 1000))
+	assert.Contains(t, raw, "[system]\nRead the logs")
+}
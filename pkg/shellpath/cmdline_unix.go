@@ -0,0 +1,7 @@
+//go:build !windows
+
+package shellpath
+
+import "os/exec"
+
+func setCmdLine(*exec.Cmd, string, []string, string) {}
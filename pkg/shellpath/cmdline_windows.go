@@ -0,0 +1,20 @@
+package shellpath
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// setCmdLine passes cmd.exe commands verbatim: with /S, cmd.exe strips the
+// outer quotes and runs the rest of the command line as-is.
+func setCmdLine(cmd *exec.Cmd, shell string, argsPrefix []string, command string) {
+	if !IsCmdExe(shell) {
+		return
+	}
+
+	args := append([]string{syscall.EscapeArg(shell), "/S"}, argsPrefix...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: strings.Join(args, " ") + ` "` + command + `"`,
+	}
+}
@@ -0,0 +1,24 @@
+package shellpath
+
+import (
+	"testing"
+)
+
+func TestCommand_WindowsCmdExePassesCommandVerbatim(t *testing.T) {
+	cmd := Command(t.Context(), `C:\Windows\System32\cmd.exe`, []string{"/C"}, `echo "a b" & dir "C:\Program Files"`)
+
+	want := `C:\Windows\System32\cmd.exe /S /C "echo "a b" & dir "C:\Program Files""`
+	if cmd.SysProcAttr == nil || cmd.SysProcAttr.CmdLine != want {
+		t.Errorf("Command() CmdLine = %+v, want %q", cmd.SysProcAttr, want)
+	}
+}
+
+func TestCommand_WindowsCmdExeRuns(t *testing.T) {
+	out, err := Command(t.Context(), WindowsCmdExe(), []string{"/C"}, `echo "quoted"`).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); got != "\"quoted\"\r\n" {
+		t.Errorf("Command() output = %q, want %q", got, "\"quoted\"\r\n")
+	}
+}
@@ -3,10 +3,12 @@
 package shellpath
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 )
 
 // WindowsCmdExe returns the absolute path to cmd.exe on Windows using the
@@ -71,3 +73,49 @@ func defaultUnixShell() string {
 	}
 	return "/bin/sh"
 }
+
+// Command returns the command running command with a shell returned by
+// [DetectShell].
+//
+// PowerShell commands report the exit code of the last native command, like
+// other shells do, output UTF-8 and don't print progress records. cmd.exe
+// commands are passed verbatim, since cmd.exe doesn't parse its command line
+// with the quoting rules Go uses.
+func Command(ctx context.Context, shell string, argsPrefix []string, command string) *exec.Cmd {
+	if IsPowerShell(shell) {
+		command = powerShellCommand(command)
+	}
+	cmd := exec.CommandContext(ctx, shell, append(argsPrefix, command)...)
+	setCmdLine(cmd, shell, argsPrefix, command)
+	return cmd
+}
+
+// IsPowerShell returns whether shell is Windows PowerShell or PowerShell Core.
+func IsPowerShell(shell string) bool {
+	switch strings.ToLower(baseName(shell)) {
+	case "pwsh", "pwsh.exe", "powershell", "powershell.exe":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsCmdExe returns whether shell is cmd.exe.
+func IsCmdExe(shell string) bool {
+	return strings.EqualFold(baseName(shell), "cmd.exe") || strings.EqualFold(baseName(shell), "cmd")
+}
+
+// powerShellCommand wraps a PowerShell command, the way GitHub Actions does,
+// so that the exit code of native commands is reported.
+func powerShellCommand(command string) string {
+	return "$ProgressPreference = 'SilentlyContinue'\n" +
+		"[Console]::OutputEncoding = [System.Text.Encoding]::UTF8\n" +
+		command + "\n" +
+		"if ((Test-Path -LiteralPath variable:\\LASTEXITCODE)) { exit $LASTEXITCODE }"
+}
+
+// baseName returns the last element of a path using either separator, so
+// that Windows paths are handled on all platforms.
+func baseName(path string) string {
+	return path[strings.LastIndexAny(path, `/\`)+1:]
+}
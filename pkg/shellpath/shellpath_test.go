@@ -3,6 +3,7 @@ package shellpath
 import (
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -101,3 +102,55 @@ func TestDetectWindowsShell_FallbackUsesAbsolutePath(t *testing.T) {
 		t.Errorf("DetectWindowsShell() args = %v, want [/C]", args)
 	}
 }
+
+func TestIsPowerShell(t *testing.T) {
+	for shell, want := range map[string]bool{
+		`C:\Program Files\PowerShell\7\pwsh.exe`:                    true,
+		`C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`: true,
+		"/usr/bin/pwsh":               true,
+		`C:\Windows\System32\cmd.exe`: false,
+		"/bin/bash":                   false,
+	} {
+		if got := IsPowerShell(shell); got != want {
+			t.Errorf("IsPowerShell(%q) = %v, want %v", shell, got, want)
+		}
+	}
+}
+
+func TestIsCmdExe(t *testing.T) {
+	if !IsCmdExe(`C:\Windows\System32\CMD.EXE`) {
+		t.Error("IsCmdExe should match cmd.exe case-insensitively")
+	}
+	if IsCmdExe(`C:\Program Files\PowerShell\7\pwsh.exe`) {
+		t.Error("IsCmdExe should not match pwsh.exe")
+	}
+}
+
+func TestCommand_PowerShell(t *testing.T) {
+	cmd := Command(t.Context(), "/usr/bin/pwsh", []string{"-NoProfile", "-NonInteractive", "-Command"}, "git status")
+
+	if len(cmd.Args) != 5 {
+		t.Fatalf("Command() args = %v, want 5 args", cmd.Args)
+	}
+	script := cmd.Args[4]
+	if !strings.Contains(script, "\ngit status\n") {
+		t.Errorf("PowerShell script should contain the command on its own line, got %q", script)
+	}
+	if !strings.HasSuffix(script, "exit $LASTEXITCODE }") {
+		t.Errorf("PowerShell script should propagate the exit code of native commands, got %q", script)
+	}
+}
+
+func TestCommand_Unix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix-only test")
+	}
+
+	out, err := Command(t.Context(), "/bin/sh", []string{"-c"}, `echo "hello world"`).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "hello world\n" {
+		t.Errorf("Command() output = %q, want %q", out, "hello world\n")
+	}
+}
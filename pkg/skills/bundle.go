@@ -0,0 +1,111 @@
+package skills
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+// maxBundleFiles caps the number of supporting files listed for a skill
+// bundle, so that a large directory doesn't bloat the system prompt.
+const maxBundleFiles = 100
+
+// IsPath reports whether a skills source is a path to a skill bundle
+// directory rather than "local", a URL or an OCI reference.
+func IsPath(source string) bool {
+	return strings.HasPrefix(source, "./") ||
+		strings.HasPrefix(source, "../") ||
+		strings.HasPrefix(source, "~/") ||
+		filepath.IsAbs(source)
+}
+
+// ResolvePaths makes the relative skill bundle paths of sources relative to
+// baseDir, typically the directory of the agent file, and expands "~/".
+// Other sources are returned unchanged.
+func ResolvePaths(sources []string, baseDir string) []string {
+	resolved := make([]string, 0, len(sources))
+	for _, source := range sources {
+		switch {
+		case strings.HasPrefix(source, "~/"):
+			source = filepath.Join(paths.GetHomeDir(), source[2:])
+		case IsPath(source) && !filepath.IsAbs(source) && baseDir != "":
+			source = filepath.Join(baseDir, source)
+		}
+		resolved = append(resolved, source)
+	}
+	return resolved
+}
+
+// loadBundle loads the skills of a bundle directory. The directory is either
+// a single skill, with a SKILL.md at its root, or a set of skills, one per
+// subdirectory. Every file of a skill's directory, such as scripts and
+// reference documents, is listed so that the agent can read it on demand.
+func loadBundle(dir string) []Skill {
+	if skill, ok := loadBundleSkill(dir); ok {
+		return []Skill{skill}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var skills []Skill
+	for _, entry := range entries {
+		if !entry.IsDir() || isHidden(entry) || isSymlink(entry) {
+			continue
+		}
+		if skill, ok := loadBundleSkill(filepath.Join(dir, entry.Name())); ok {
+			skills = append(skills, skill)
+		}
+	}
+	return skills
+}
+
+func loadBundleSkill(dir string) (Skill, bool) {
+	skill, ok := loadSkillFile(filepath.Join(dir, skillFile), filepath.Base(dir))
+	if !ok {
+		return Skill{}, false
+	}
+
+	skill.Files = listBundleFiles(dir)
+	return skill, true
+}
+
+// listBundleFiles returns the slash-separated paths of the regular files of
+// a skill directory, SKILL.md first, skipping hidden files.
+func listBundleFiles(dir string) []string {
+	var files []string
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path != dir && isHidden(d) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == skillFile {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		if len(files) >= maxBundleFiles {
+			return fs.SkipAll
+		}
+		return nil
+	})
+
+	slices.Sort(files)
+	return append([]string{skillFile}, files...)
+}
@@ -0,0 +1,83 @@
+package skills
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSkill(t *testing.T, dir, name string, files ...string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	content := "---\nname: " + name + "\ndescription: The " + name + " skill\n---\n\n# " + name + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, skillFile), []byte(content), 0o644))
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(f), 0o644))
+	}
+}
+
+func TestLoadBundle_SingleSkill(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "pdf")
+	writeSkill(t, dir, "pdf", "scripts/extract.py", "reference.md", ".hidden")
+
+	skills := Load([]string{dir})
+	require.Len(t, skills, 1)
+	assert.Equal(t, "pdf", skills[0].Name)
+	assert.Equal(t, dir, skills[0].BaseDir)
+	assert.Equal(t, []string{"SKILL.md", "reference.md", "scripts/extract.py"}, skills[0].Files)
+}
+
+func TestLoadBundle_SkillSet(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeSkill(t, filepath.Join(dir, "pdf"), "pdf")
+	writeSkill(t, filepath.Join(dir, "xlsx"), "xlsx")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "not-a-skill"), 0o755))
+
+	skills := Load([]string{dir})
+	var names []string
+	for _, s := range skills {
+		names = append(names, s.Name)
+	}
+	assert.ElementsMatch(t, []string{"pdf", "xlsx"}, names)
+}
+
+func TestLoadBundle_Missing(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, Load([]string{filepath.Join(t.TempDir(), "missing")}))
+}
+
+func TestIsPath(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsPath("./skills/pdf"))
+	assert.True(t, IsPath("../shared/skills"))
+	assert.True(t, IsPath("~/skills"))
+	assert.True(t, IsPath(t.TempDir()))
+	assert.False(t, IsPath("local"))
+	assert.False(t, IsPath("https://skills.example.com"))
+	assert.False(t, IsPath("docker.io/org/skill:tag"))
+}
+
+func TestResolvePaths(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	resolved := ResolvePaths([]string{"local", "./skills/pdf", "https://skills.example.com", "docker.io/org/skill:tag"}, base)
+	assert.Equal(t, []string{
+		"local",
+		filepath.Join(base, "skills", "pdf"),
+		"https://skills.example.com",
+		"docker.io/org/skill:tag",
+	}, resolved)
+}
@@ -0,0 +1,172 @@
+package skills
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+// ociPullTimeout bounds the time spent pulling a skill bundle.
+const ociPullTimeout = 2 * time.Minute
+
+// maxOCIBundleSize caps the size of an extracted skill bundle.
+const maxOCIBundleSize = 100 << 20
+
+func defaultOCICacheDir() string {
+	return filepath.Join(paths.GetCacheDir(), "skills", "oci")
+}
+
+// loadOCISkills loads the skills of a bundle distributed as an OCI image,
+// e.g. docker.io/org/skill:tag. The image filesystem is the bundle directory:
+// a SKILL.md at its root, or one skill per top-level directory.
+//
+// Bundles are extracted once per digest. When the registry can't be reached,
+// the last bundle pulled for the reference is used.
+func loadOCISkills(ref string) []Skill {
+	dir, err := pullOCIBundle(ref, defaultOCICacheDir())
+	if err != nil {
+		slog.Warn("Failed to pull skill bundle", "ref", ref, "error", err)
+		return nil
+	}
+
+	skills := loadBundle(dir)
+	slog.Debug("Loaded skill bundle", "ref", ref, "count", len(skills))
+	return skills
+}
+
+// pullOCIBundle returns the directory of the extracted bundle for ref,
+// pulling and extracting it if needed.
+func pullOCIBundle(ref, cacheDir string, opts ...crane.Option) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ociPullTimeout)
+	defer cancel()
+	opts = append(opts, crane.WithContext(ctx))
+
+	refHash := sha256.Sum256([]byte(ref))
+	refFile := filepath.Join(cacheDir, "refs", hex.EncodeToString(refHash[:]))
+
+	digest, err := crane.Digest(ref, opts...)
+	if err != nil {
+		// Offline: fall back on the last pulled bundle
+		if last, readErr := os.ReadFile(refFile); readErr == nil {
+			dir := bundleDir(cacheDir, string(last))
+			if _, statErr := os.Stat(dir); statErr == nil {
+				slog.Debug("Using cached skill bundle", "ref", ref, "error", err)
+				return dir, nil
+			}
+		}
+		return "", fmt.Errorf("resolving digest: %w", err)
+	}
+
+	dir := bundleDir(cacheDir, digest)
+	if _, err := os.Stat(dir); err != nil {
+		img, err := crane.Pull(ref, opts...)
+		if err != nil {
+			return "", fmt.Errorf("pulling: %w", err)
+		}
+
+		// Extract to a temporary directory first so that a failed pull never
+		// leaves a partial bundle behind.
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			return "", err
+		}
+		tmp, err := os.MkdirTemp(filepath.Dir(dir), ".pull-*")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(tmp)
+
+		rc := mutate.Extract(img)
+		err = extractTar(rc, tmp)
+		rc.Close()
+		if err != nil {
+			return "", fmt.Errorf("extracting: %w", err)
+		}
+		if err := os.Rename(tmp, dir); err != nil {
+			// Another process may have extracted the same bundle meanwhile
+			if _, statErr := os.Stat(dir); statErr != nil {
+				return "", err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(refFile), 0o755); err == nil {
+		_ = os.WriteFile(refFile, []byte(digest), 0o644)
+	}
+	return dir, nil
+}
+
+func bundleDir(cacheDir, digest string) string {
+	return filepath.Join(cacheDir, strings.ReplaceAll(digest, ":", "-"))
+}
+
+// extractTar extracts the directories and regular files of a tar stream into
+// destDir. Entries that would escape destDir are rejected.
+func extractTar(r io.Reader, destDir string) error {
+	tr := tar.NewReader(r)
+
+	var total int64
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.FromSlash(strings.TrimPrefix(header.Name, "/"))
+		path := filepath.Join(destDir, name)
+		if !strings.HasPrefix(path, filepath.Clean(destDir)+string(filepath.Separator)) {
+			if filepath.Clean(path) == filepath.Clean(destDir) {
+				continue
+			}
+			return fmt.Errorf("invalid path %q in bundle", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += header.Size
+			if total > maxOCIBundleSize {
+				return fmt.Errorf("bundle is larger than %d bytes", maxOCIBundleSize)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := writeBundleFile(path, tr, header.Size, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeBundleFile(path string, r io.Reader, size int64, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm|0o600)
+	if err != nil {
+		return err
+	}
+
+	_, copyErr := io.CopyN(f, r, size)
+	closeErr := f.Close()
+	if copyErr != nil {
+		return copyErr
+	}
+	return closeErr
+}
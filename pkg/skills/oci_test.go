@@ -0,0 +1,84 @@
+package skills
+
+import (
+	"archive/tar"
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pushBundle pushes an image made of a single layer with the given files.
+func pushBundle(t *testing.T, ref string, files map[string]string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	layer, err := tarball.LayerFromReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	require.NoError(t, err)
+	require.NoError(t, crane.Push(img, ref))
+}
+
+func TestPullOCIBundle(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New())
+	ref := strings.TrimPrefix(srv.URL, "http://") + "/org/pdf:latest"
+
+	pushBundle(t, ref, map[string]string{
+		"SKILL.md":           "---\nname: pdf\ndescription: Work with PDF files\n---\n\n# PDF\n",
+		"scripts/extract.py": "print('hello')",
+	})
+
+	cacheDir := t.TempDir()
+	dir, err := pullOCIBundle(ref, cacheDir)
+	require.NoError(t, err)
+
+	skills := loadBundle(dir)
+	require.Len(t, skills, 1)
+	assert.Equal(t, "pdf", skills[0].Name)
+	assert.Equal(t, []string{"SKILL.md", "scripts/extract.py"}, skills[0].Files)
+
+	script, err := os.ReadFile(filepath.Join(dir, "scripts", "extract.py"))
+	require.NoError(t, err)
+	assert.Equal(t, "print('hello')", string(script))
+
+	// The last pulled bundle is used when the registry is unreachable
+	srv.Close()
+	offlineDir, err := pullOCIBundle(ref, cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, offlineDir)
+}
+
+func TestExtractTar_RejectsEscapingPaths(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	err = extractTar(&buf, t.TempDir())
+	require.ErrorContains(t, err, "invalid path")
+}
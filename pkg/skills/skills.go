@@ -56,8 +56,11 @@ func (s *stringOrList) UnmarshalYAML(unmarshal func(any) error) error {
 }
 
 // Load discovers and loads skills from the given sources.
-// Each source is either "local" (for filesystem-based skills) or an HTTP/HTTPS URL
-// (for remote skills per the well-known skills discovery spec).
+// Each source is one of:
+//   - "local", for skills found in the standard filesystem locations
+//   - an HTTP/HTTPS URL, for remote skills per the well-known skills discovery spec
+//   - a path to a skill bundle directory (see [IsPath] and [ResolvePaths])
+//   - an OCI reference to a skill bundle, e.g. docker.io/org/skill:tag
 //
 // Local skills are loaded from (in order, later overrides earlier):
 //
@@ -82,6 +85,14 @@ func Load(sources []string) []Skill {
 			for _, skill := range loadRemoteSkills(source) {
 				skillMap[source+"/"+skill.Name] = skill
 			}
+		case IsPath(source):
+			for _, skill := range loadBundle(source) {
+				skillMap[skill.Name] = skill
+			}
+		default:
+			for _, skill := range loadOCISkills(source) {
+				skillMap[skill.Name] = skill
+			}
 		}
 	}
 
@@ -8,15 +8,29 @@ import (
 	"strings"
 
 	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/permissions"
+	"github.com/docker/docker-agent/pkg/promptcompression"
 	"github.com/docker/docker-agent/pkg/rag"
+	"github.com/docker/docker-agent/pkg/transcription"
 )
 
 type Team struct {
 	agents      []*agent.Agent
 	ragManagers map[string]*rag.Manager
 	permissions *permissions.Checker
+
+	maxDelegationDepth  int
+	delegationLoopLimit int
+	taskBudgets         map[string]latest.TaskBudget
+
+	titleModel    provider.Provider
+	titlesEnabled bool
+
+	promptCompressor *promptcompression.Compressor
+	transcriber      *transcription.Transcriber
 }
 
 type Opt func(*Team)
@@ -39,9 +53,54 @@ func WithPermissions(checker *permissions.Checker) Opt {
 	}
 }
 
+// WithDelegationLimits sets the maximum depth of nested task transfers and
+// the number of times the same delegation can repeat before the run is
+// stopped. Zero or negative values disable the corresponding limit.
+func WithDelegationLimits(maxDepth, loopLimit int) Opt {
+	return func(t *Team) {
+		t.maxDelegationDepth = maxDepth
+		t.delegationLoopLimit = loopLimit
+	}
+}
+
+// WithTaskBudgets sets the limits of the tasks delegated to each agent.
+func WithTaskBudgets(budgets map[string]latest.TaskBudget) Opt {
+	return func(t *Team) {
+		t.taskBudgets = budgets
+	}
+}
+
+// WithTitles configures session title generation: model generates the
+// titles, or the model of the current agent when nil. When enabled is
+// false, titles are never generated.
+func WithTitles(model provider.Provider, enabled bool) Opt {
+	return func(t *Team) {
+		t.titleModel = model
+		t.titlesEnabled = enabled
+	}
+}
+
+// WithPromptCompressor compresses the old tool outputs of the conversations
+// of every agent before they are sent to their models.
+func WithPromptCompressor(compressor *promptcompression.Compressor) Opt {
+	return func(t *Team) {
+		t.promptCompressor = compressor
+	}
+}
+
+// WithTranscriber transcribes the audio and video files attached to the
+// messages of the users before they are sent to the models.
+func WithTranscriber(transcriber *transcription.Transcriber) Opt {
+	return func(t *Team) {
+		t.transcriber = transcriber
+	}
+}
+
 func New(opts ...Opt) *Team {
 	t := &Team{
-		ragManagers: make(map[string]*rag.Manager),
+		ragManagers:   make(map[string]*rag.Manager),
+		titlesEnabled: true,
+		transcriber:   transcription.New(nil, nil, 0),
 	}
 	for _, opt := range opts {
 		opt(t)
@@ -174,3 +233,45 @@ func (t *Team) StartRAGFileWatchers(ctx context.Context) {
 func (t *Team) Permissions() *permissions.Checker {
 	return t.permissions
 }
+
+// MaxDelegationDepth returns the maximum depth of nested task transfers.
+// Returns 0 if unlimited.
+func (t *Team) MaxDelegationDepth() int {
+	return t.maxDelegationDepth
+}
+
+// DelegationLoopLimit returns the number of times the same delegation can
+// repeat before the run is stopped. Returns 0 if loops aren't detected.
+func (t *Team) DelegationLoopLimit() int {
+	return t.delegationLoopLimit
+}
+
+// TaskBudget returns the limits of the tasks delegated to the given agent,
+// and whether it has any.
+func (t *Team) TaskBudget(agentName string) (latest.TaskBudget, bool) {
+	budget, ok := t.taskBudgets[agentName]
+	return budget, ok
+}
+
+// TitleModel returns the model that generates session titles, or nil to use
+// the model of the current agent.
+func (t *Team) TitleModel() provider.Provider {
+	return t.titleModel
+}
+
+// TitlesEnabled returns whether session titles are generated.
+func (t *Team) TitlesEnabled() bool {
+	return t.titlesEnabled
+}
+
+// PromptCompressor returns the compressor of old tool outputs, or nil when
+// prompt compression is disabled.
+func (t *Team) PromptCompressor() *promptcompression.Compressor {
+	return t.promptCompressor
+}
+
+// Transcriber returns the transcriber of attached audio and video files.
+// Without a transcription model, it replaces the files with a note.
+func (t *Team) Transcriber() *transcription.Transcriber {
+	return t.transcriber
+}
@@ -0,0 +1,123 @@
+package teamloader
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// WithToolsAliases creates a toolset whose tools are renamed, from their
+// original names to their aliases. Tools without an alias keep their name.
+func WithToolsAliases(inner tools.ToolSet, aliases map[string]string) tools.ToolSet {
+	if len(aliases) == 0 {
+		return inner
+	}
+
+	return &aliasTools{
+		ToolSet: inner,
+		aliases: aliases,
+	}
+}
+
+type aliasTools struct {
+	tools.ToolSet
+	aliases map[string]string
+}
+
+// Verify interface compliance
+var (
+	_ tools.Instructable = (*aliasTools)(nil)
+	_ tools.Unwrapper    = (*aliasTools)(nil)
+)
+
+// Unwrap implements tools.Unwrapper.
+func (a *aliasTools) Unwrap() tools.ToolSet {
+	return a.ToolSet
+}
+
+// Instructions implements tools.Instructable by delegating to the inner toolset.
+func (a *aliasTools) Instructions() string {
+	return tools.GetInstructions(a.ToolSet)
+}
+
+func (a *aliasTools) Tools(ctx context.Context) ([]tools.Tool, error) {
+	allTools, err := a.ToolSet.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := make([]tools.Tool, len(allTools))
+	for i, tool := range allTools {
+		if alias, ok := a.aliases[tool.Name]; ok {
+			tool = tools.Rename(tool, alias)
+		}
+		renamed[i] = tool
+	}
+	return renamed, nil
+}
+
+// WithNamespace creates a toolset whose tools are prefixed with namespace
+// when their names conflict with the tools of the agent's other toolsets.
+func WithNamespace(inner tools.ToolSet, namespace string) tools.ToolSet {
+	if namespace == "" {
+		return inner
+	}
+
+	return &namespacedTools{
+		ToolSet:   inner,
+		namespace: namespace,
+	}
+}
+
+type namespacedTools struct {
+	tools.ToolSet
+	namespace string
+}
+
+// Verify interface compliance
+var (
+	_ tools.Instructable = (*namespacedTools)(nil)
+	_ tools.Namespacer   = (*namespacedTools)(nil)
+	_ tools.Unwrapper    = (*namespacedTools)(nil)
+)
+
+// Unwrap implements tools.Unwrapper.
+func (n *namespacedTools) Unwrap() tools.ToolSet {
+	return n.ToolSet
+}
+
+// Instructions implements tools.Instructable by delegating to the inner toolset.
+func (n *namespacedTools) Instructions() string {
+	return tools.GetInstructions(n.ToolSet)
+}
+
+// Namespace implements tools.Namespacer.
+func (n *namespacedTools) Namespace() string {
+	return n.namespace
+}
+
+var nonNamespaceChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// toolsetNamespace returns the prefix of the conflicting tools of a toolset:
+// its name, or else what its server is started from, or else its type.
+func toolsetNamespace(toolset latest.Toolset) string {
+	namespace := toolset.Type
+	switch {
+	case toolset.Name != "":
+		namespace = toolset.Name
+	case toolset.Ref != "":
+		namespace = strings.TrimPrefix(toolset.Ref, "docker:")
+	case toolset.Remote.URL != "":
+		if u, err := url.Parse(toolset.Remote.URL); err == nil && u.Hostname() != "" {
+			namespace = u.Hostname()
+		}
+	case toolset.Command != "":
+		namespace = filepath.Base(toolset.Command)
+	}
+	return strings.Trim(nonNamespaceChars.ReplaceAllString(strings.ToLower(namespace), "_"), "_")
+}
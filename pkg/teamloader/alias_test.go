@@ -0,0 +1,74 @@
+package teamloader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestWithToolsAliases_NoAliases(t *testing.T) {
+	inner := &mockToolSet{}
+
+	assert.Same(t, inner, WithToolsAliases(inner, nil))
+}
+
+func TestWithToolsAliases_RenamesTools(t *testing.T) {
+	var calledWith string
+	inner := &mockToolSet{
+		toolsFunc: func(context.Context) ([]tools.Tool, error) {
+			return []tools.Tool{
+				{Name: "search_code", Handler: func(_ context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+					calledWith = toolCall.Function.Name
+					return tools.ResultSuccess("found"), nil
+				}},
+				{Name: "get_file_contents"},
+			}, nil
+		},
+	}
+
+	wrapped := WithToolsAliases(inner, map[string]string{"search_code": "github_search", "unknown": "other"})
+
+	result, err := wrapped.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "github_search", result[0].Name)
+	assert.Equal(t, "get_file_contents", result[1].Name)
+
+	res, err := result[0].Handler(t.Context(), tools.ToolCall{Function: tools.FunctionCall{Name: "github_search"}})
+	require.NoError(t, err)
+	assert.Equal(t, "found", res.Output)
+	assert.Equal(t, "search_code", calledWith)
+}
+
+func TestWithNamespace(t *testing.T) {
+	inner := &mockToolSet{}
+	assert.Same(t, inner, WithNamespace(inner, ""))
+
+	// The namespace is found through other wrappers
+	wrapped := WithToolsFilter(WithNamespace(inner, "github"), "search")
+	ns, ok := tools.As[tools.Namespacer](wrapped)
+	require.True(t, ok)
+	assert.Equal(t, "github", ns.Namespace())
+}
+
+func TestToolsetNamespace(t *testing.T) {
+	tests := []struct {
+		toolset latest.Toolset
+		want    string
+	}{
+		{toolset: latest.Toolset{Type: "mcp", Name: "My Server", Ref: "docker:github-official"}, want: "my_server"},
+		{toolset: latest.Toolset{Type: "mcp", Ref: "docker:github-official"}, want: "github_official"},
+		{toolset: latest.Toolset{Type: "mcp", Remote: latest.Remote{URL: "https://mcp.linear.app/sse"}}, want: "mcp_linear_app"},
+		{toolset: latest.Toolset{Type: "mcp", Command: "/usr/local/bin/mcp-server-git"}, want: "mcp_server_git"},
+		{toolset: latest.Toolset{Type: "filesystem"}, want: "filesystem"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, toolsetNamespace(tt.toolset))
+	}
+}
@@ -0,0 +1,234 @@
+package teamloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// WithLazyStart wraps a toolset so that it's only started when one of its
+// tools is first called. Until then, the tools are described from the last
+// list cached in cacheFile. Without a cached list, the toolset is started
+// to list its tools, and the list is cached for the next time.
+func WithLazyStart(inner tools.ToolSet, cacheFile string) tools.ToolSet {
+	return &lazyToolset{
+		ToolSet:   inner,
+		cacheFile: cacheFile,
+	}
+}
+
+// lazyToolsCacheFile returns the file caching the tools of a lazy toolset.
+// It depends on what identifies the server, never on secrets.
+func lazyToolsCacheFile(toolset latest.Toolset) string {
+	key, _ := json.Marshal([]any{toolset.Type, toolset.Name, toolset.Ref, toolset.Command, toolset.Args, toolset.Remote.URL, toolset.Remote.TransportType, toolset.Config})
+	sum := sha256.Sum256(key)
+	return filepath.Join(paths.GetCacheDir(), "lazy-tools", hex.EncodeToString(sum[:])+".json")
+}
+
+type lazyToolset struct {
+	tools.ToolSet
+	cacheFile string
+
+	mu      sync.Mutex
+	started bool
+	cached  *cachedTools
+	loaded  bool
+	saved   []byte
+}
+
+// cachedTools is the list of tools of a lazy toolset, as cached on disk.
+type cachedTools struct {
+	Instructions string       `json:"instructions,omitempty"`
+	Tools        []cachedTool `json:"tools"`
+}
+
+type cachedTool struct {
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	Parameters   any                   `json:"parameters,omitempty"`
+	OutputSchema any                   `json:"output_schema,omitempty"`
+	Annotations  tools.ToolAnnotations `json:"annotations"`
+}
+
+// Verify interface compliance
+var (
+	_ tools.Startable    = (*lazyToolset)(nil)
+	_ tools.Instructable = (*lazyToolset)(nil)
+	_ tools.Unwrapper    = (*lazyToolset)(nil)
+)
+
+// Unwrap implements tools.Unwrapper.
+func (l *lazyToolset) Unwrap() tools.ToolSet {
+	return l.ToolSet
+}
+
+// Start doesn't start the inner toolset: it's started by the first call to
+// one of its tools, or by Tools if no list of tools is cached.
+func (l *lazyToolset) Start(context.Context) error {
+	return nil
+}
+
+func (l *lazyToolset) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.started {
+		return nil
+	}
+	l.started = false
+	if startable, ok := tools.As[tools.Startable](l.ToolSet); ok {
+		return startable.Stop(ctx)
+	}
+	return nil
+}
+
+func (l *lazyToolset) Instructions() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.started {
+		if cached := l.loadCacheLocked(); cached != nil {
+			return cached.Instructions
+		}
+	}
+	return tools.GetInstructions(l.ToolSet)
+}
+
+func (l *lazyToolset) Tools(ctx context.Context) ([]tools.Tool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.started {
+		if cached := l.loadCacheLocked(); cached != nil {
+			return l.cachedToolsLocked(cached), nil
+		}
+	}
+	return l.startAndListToolsLocked(ctx)
+}
+
+// cachedToolsLocked describes the cached tools, with handlers starting the
+// inner toolset and calling its live tools.
+func (l *lazyToolset) cachedToolsLocked(cached *cachedTools) []tools.Tool {
+	toolsList := make([]tools.Tool, len(cached.Tools))
+	for i, t := range cached.Tools {
+		toolsList[i] = tools.Tool{
+			Name:         t.Name,
+			Description:  t.Description,
+			Parameters:   t.Parameters,
+			OutputSchema: t.OutputSchema,
+			Annotations:  t.Annotations,
+			Handler:      l.callTool,
+		}
+	}
+	return toolsList
+}
+
+func (l *lazyToolset) callTool(ctx context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+	l.mu.Lock()
+	liveTools, err := l.startAndListToolsLocked(ctx)
+	l.mu.Unlock()
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to start the server of tool %s: %s", toolCall.Function.Name, err)), nil
+	}
+
+	for _, t := range liveTools {
+		if t.Name == toolCall.Function.Name && t.Handler != nil {
+			return t.Handler(ctx, toolCall)
+		}
+	}
+	return tools.ResultError(fmt.Sprintf("Tool %s is no longer available.", toolCall.Function.Name)), nil
+}
+
+// startAndListToolsLocked starts the inner toolset if needed, and lists and
+// caches its live tools.
+func (l *lazyToolset) startAndListToolsLocked(ctx context.Context) ([]tools.Tool, error) {
+	if !l.started {
+		slog.Debug("Starting lazy toolset", "toolset", tools.DescribeToolSet(l.ToolSet))
+		if startable, ok := tools.As[tools.Startable](l.ToolSet); ok {
+			if err := startable.Start(ctx); err != nil {
+				return nil, err
+			}
+		}
+		l.started = true
+	}
+
+	toolsList, err := l.ToolSet.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l.saveCacheLocked(toolsList)
+	return toolsList, nil
+}
+
+// loadCacheLocked returns the cached list of tools, if any. The cache is
+// only read once.
+func (l *lazyToolset) loadCacheLocked() *cachedTools {
+	if l.loaded {
+		return l.cached
+	}
+	l.loaded = true
+
+	data, err := os.ReadFile(l.cacheFile)
+	if err != nil {
+		return nil
+	}
+	var cached cachedTools
+	if err := json.Unmarshal(data, &cached); err != nil {
+		slog.Debug("Ignoring invalid cache of lazy toolset", "file", l.cacheFile, "error", err)
+		return nil
+	}
+	l.cached = &cached
+	l.saved = data
+	return l.cached
+}
+
+// saveCacheLocked caches the list of tools of the started toolset, if it
+// changed. Empty lists, as listed by unavailable servers, are not cached.
+func (l *lazyToolset) saveCacheLocked(toolsList []tools.Tool) {
+	if len(toolsList) == 0 {
+		return
+	}
+
+	cached := &cachedTools{
+		Instructions: tools.GetInstructions(l.ToolSet),
+		Tools:        make([]cachedTool, len(toolsList)),
+	}
+	for i, t := range toolsList {
+		cached.Tools[i] = cachedTool{
+			Name:         t.Name,
+			Description:  t.Description,
+			Parameters:   t.Parameters,
+			OutputSchema: t.OutputSchema,
+			Annotations:  t.Annotations,
+		}
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		slog.Debug("Failed to encode the tools of lazy toolset", "error", err)
+		return
+	}
+	if bytes.Equal(l.saved, data) {
+		return
+	}
+	l.saved = data
+
+	if err := os.MkdirAll(filepath.Dir(l.cacheFile), 0o700); err != nil {
+		slog.Debug("Failed to cache the tools of lazy toolset", "file", l.cacheFile, "error", err)
+		return
+	}
+	if err := os.WriteFile(l.cacheFile, data, 0o600); err != nil {
+		slog.Debug("Failed to cache the tools of lazy toolset", "file", l.cacheFile, "error", err)
+	}
+}
@@ -0,0 +1,165 @@
+package teamloader
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// startableToolSet is a toolset whose tools are only listed once started.
+type startableToolSet struct {
+	toolNames []string
+	starts    int
+	stops     int
+	started   bool
+	startErr  error
+}
+
+func (s *startableToolSet) Start(context.Context) error {
+	if s.startErr != nil {
+		return s.startErr
+	}
+	s.starts++
+	s.started = true
+	return nil
+}
+
+func (s *startableToolSet) Stop(context.Context) error {
+	s.stops++
+	s.started = false
+	return nil
+}
+
+func (s *startableToolSet) Instructions() string {
+	return "Use the server wisely"
+}
+
+func (s *startableToolSet) Tools(context.Context) ([]tools.Tool, error) {
+	if !s.started {
+		return nil, errors.New("toolset not started")
+	}
+
+	var result []tools.Tool
+	for _, name := range s.toolNames {
+		result = append(result, tools.Tool{
+			Name:        name,
+			Description: "The " + name + " tool",
+			Parameters:  map[string]any{"type": "object"},
+			Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+				return tools.ResultSuccess("called " + name), nil
+			},
+		})
+	}
+	return result, nil
+}
+
+func callLazyTool(t *testing.T, tool tools.Tool) *tools.ToolCallResult {
+	t.Helper()
+
+	result, err := tool.Handler(t.Context(), tools.ToolCall{Function: tools.FunctionCall{Name: tool.Name}})
+	require.NoError(t, err)
+	return result
+}
+
+func TestWithLazyStart_StartsToListToolsWithoutCache(t *testing.T) {
+	t.Parallel()
+
+	inner := &startableToolSet{toolNames: []string{"search", "fetch"}}
+	lazy := WithLazyStart(inner, filepath.Join(t.TempDir(), "tools.json"))
+
+	require.NoError(t, lazy.(tools.Startable).Start(t.Context()))
+	assert.Equal(t, 0, inner.starts)
+
+	result, err := lazy.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, 1, inner.starts)
+	assert.Equal(t, "called search", callLazyTool(t, result[0]).Output)
+
+	require.NoError(t, lazy.(tools.Startable).Stop(t.Context()))
+	assert.Equal(t, 1, inner.stops)
+}
+
+func TestWithLazyStart_StartsOnFirstCall(t *testing.T) {
+	t.Parallel()
+
+	cacheFile := filepath.Join(t.TempDir(), "lazy", "tools.json")
+
+	// A first run caches the tools.
+	first := WithLazyStart(&startableToolSet{toolNames: []string{"search", "fetch"}}, cacheFile)
+	_, err := first.Tools(t.Context())
+	require.NoError(t, err)
+
+	inner := &startableToolSet{toolNames: []string{"search", "fetch"}}
+	lazy := WithLazyStart(inner, cacheFile)
+
+	result, err := lazy.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "search", result[0].Name)
+	assert.Equal(t, "The search tool", result[0].Description)
+	assert.Equal(t, map[string]any{"type": "object"}, result[0].Parameters)
+	assert.Equal(t, "Use the server wisely", tools.GetInstructions(lazy))
+	assert.Equal(t, 0, inner.starts)
+
+	// Stopping a toolset that never started doesn't stop the server.
+	require.NoError(t, lazy.(tools.Startable).Stop(t.Context()))
+	assert.Equal(t, 0, inner.stops)
+
+	assert.Equal(t, "called fetch", callLazyTool(t, result[1]).Output)
+	assert.Equal(t, "called search", callLazyTool(t, result[0]).Output)
+	assert.Equal(t, 1, inner.starts)
+
+	require.NoError(t, lazy.(tools.Startable).Stop(t.Context()))
+	assert.Equal(t, 1, inner.stops)
+}
+
+func TestWithLazyStart_ToolNoLongerAvailable(t *testing.T) {
+	t.Parallel()
+
+	cacheFile := filepath.Join(t.TempDir(), "tools.json")
+	first := WithLazyStart(&startableToolSet{toolNames: []string{"search", "fetch"}}, cacheFile)
+	_, err := first.Tools(t.Context())
+	require.NoError(t, err)
+
+	// The server was updated and doesn't have the fetch tool anymore.
+	inner := &startableToolSet{toolNames: []string{"search"}}
+	lazy := WithLazyStart(inner, cacheFile)
+	result, err := lazy.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	res := callLazyTool(t, result[1])
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Output, "no longer available")
+
+	// Once started, the live tools are listed.
+	result, err = lazy.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "search", result[0].Name)
+}
+
+func TestWithLazyStart_StartFailure(t *testing.T) {
+	t.Parallel()
+
+	cacheFile := filepath.Join(t.TempDir(), "tools.json")
+	first := WithLazyStart(&startableToolSet{toolNames: []string{"search"}}, cacheFile)
+	_, err := first.Tools(t.Context())
+	require.NoError(t, err)
+
+	lazy := WithLazyStart(&startableToolSet{toolNames: []string{"search"}, startErr: errors.New("boom")}, cacheFile)
+	result, err := lazy.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	res := callLazyTool(t, result[0])
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Output, "boom")
+}
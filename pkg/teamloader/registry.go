@@ -1,9 +1,11 @@
 package teamloader
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"time"
@@ -14,6 +16,8 @@ import (
 	"github.com/docker/docker-agent/pkg/gateway"
 	"github.com/docker/docker-agent/pkg/js"
 	"github.com/docker/docker-agent/pkg/memory/database/sqlite"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
 	"github.com/docker/docker-agent/pkg/path"
 	"github.com/docker/docker-agent/pkg/paths"
 	"github.com/docker/docker-agent/pkg/toolinstall"
@@ -22,6 +26,8 @@ import (
 	"github.com/docker/docker-agent/pkg/tools/builtin"
 	agenttool "github.com/docker/docker-agent/pkg/tools/builtin/agent"
 	"github.com/docker/docker-agent/pkg/tools/mcp"
+	"github.com/docker/docker-agent/pkg/tools/wasm"
+	"github.com/docker/docker-agent/pkg/workspace"
 )
 
 // ToolsetCreator is a function that creates a toolset based on the provided configuration.
@@ -45,6 +51,13 @@ func (r *ToolsetRegistry) Register(toolsetType string, creator ToolsetCreator) {
 	r.creators[toolsetType] = creator
 }
 
+// With returns a copy of the registry with an additional toolset creator.
+func (r *ToolsetRegistry) With(toolsetType string, creator ToolsetCreator) *ToolsetRegistry {
+	clone := &ToolsetRegistry{creators: maps.Clone(r.creators)}
+	clone.Register(toolsetType, creator)
+	return clone
+}
+
 // Get retrieves a toolset creator for the given type
 func (r *ToolsetRegistry) Get(toolsetType string) (ToolsetCreator, bool) {
 	creator, ok := r.creators[toolsetType]
@@ -67,6 +80,9 @@ func NewDefaultToolsetRegistry() *ToolsetRegistry {
 	r.Register("tasks", createTasksTool)
 	r.Register("memory", createMemoryTool)
 	r.Register("think", createThinkTool)
+	r.Register("artifacts", createArtifactsTool)
+	r.Register("blackboard", createBlackboardTool)
+	r.Register("image_generation", createImageGenerationTool)
 	r.Register("shell", createShellTool)
 	r.Register("script", createScriptTool)
 	r.Register("filesystem", createFilesystemTool)
@@ -76,9 +92,14 @@ func NewDefaultToolsetRegistry() *ToolsetRegistry {
 	r.Register("a2a", createA2ATool)
 	r.Register("lsp", createLSPTool)
 	r.Register("user_prompt", createUserPromptTool)
+	r.Register("escalate_to_human", createEscalateTool)
 	r.Register("openapi", createOpenAPITool)
 	r.Register("model_picker", createModelPickerTool)
 	r.Register("background_agents", createBackgroundAgentsTool)
+	r.Register("google_search", createGoogleSearchTool)
+	r.Register("code_execution", createCodeExecutionTool)
+	r.Register("github", createGitHubTool)
+	r.Register("wasm", createWASMTool)
 	return r
 }
 
@@ -90,10 +111,7 @@ func createTodoTool(_ context.Context, toolset latest.Toolset, _ string, _ *conf
 }
 
 func createTasksTool(_ context.Context, toolset latest.Toolset, parentDir string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
-	toolsetPath := toolset.Path
-	if toolsetPath == "" {
-		toolsetPath = "tasks.json"
-	}
+	toolsetPath := cmp.Or(toolset.Path, runConfig.TasksPath, "tasks.json")
 
 	var basePath string
 	if filepath.IsAbs(toolsetPath) {
@@ -134,6 +152,9 @@ func createMemoryTool(_ context.Context, toolset latest.Toolset, parentDir strin
 		if err != nil {
 			return nil, fmt.Errorf("invalid memory database path: %w", err)
 		}
+	} else if runConfig.MemoryPath != "" {
+		// Set by the project
+		validatedMemoryPath = runConfig.MemoryPath
 	} else {
 		// Default: ~/.cagent/memory/<configName>/memory.db
 		if configName == "" {
@@ -154,18 +175,89 @@ func createMemoryTool(_ context.Context, toolset latest.Toolset, parentDir strin
 	return builtin.NewMemoryToolWithPath(db, validatedMemoryPath), nil
 }
 
+// toolsetEnv returns the environment of the processes of a toolset: the
+// whole environment, or only the variables processes need to run if the
+// toolset doesn't inherit it, followed by the toolset's own variables, which
+// take precedence. Their values are expanded with envProvider, so that they
+// can reference secrets, e.g. GITHUB_TOKEN: ${GITHUB_TOKEN}.
+func toolsetEnv(ctx context.Context, toolset latest.Toolset, envProvider environment.Provider) ([]string, error) {
+	declared, err := environment.ExpandAll(ctx, environment.ToValues(toolset.Env), envProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand the tool's environment variables: %w", err)
+	}
+
+	base := os.Environ()
+	if toolset.InheritEnv != nil && !*toolset.InheritEnv {
+		base = environment.SystemEnv()
+	}
+	return append(base, declared...), nil
+}
+
+// agentConfigToolCreator returns the creator of agent_config toolsets, which
+// edit the agent file being loaded.
+func agentConfigToolCreator(agentSource config.Source) ToolsetCreator {
+	return func(context.Context, latest.Toolset, string, *config.RuntimeConfig, string) (tools.ToolSet, error) {
+		path := config.FilePath(agentSource)
+		if path == "" {
+			return nil, fmt.Errorf("agent_config toolset needs an agent loaded from a local file, not %s", agentSource.Name())
+		}
+		return builtin.NewAgentConfigTool(path, func(ctx context.Context, data []byte) error {
+			_, err := config.Load(ctx, config.NewBytesSource(path, data))
+			return err
+		}), nil
+	}
+}
+
 func createThinkTool(_ context.Context, _ latest.Toolset, _ string, _ *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
 	return builtin.NewThinkTool(), nil
 }
 
+func createBlackboardTool(_ context.Context, _ latest.Toolset, _ string, _ *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	return builtin.NewBlackboardTool(), nil
+}
+
+func createArtifactsTool(_ context.Context, _ latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	return builtin.NewArtifactsTool(runConfig.WorkingDir), nil
+}
+
+// defaultImageModel is the model of image_generation toolsets that don't set one.
+const defaultImageModel = "openai/gpt-image-1"
+
+func createImageGenerationTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	modelCfg, err := latest.ParseModelRef(cmp.Or(toolset.ImageModel, defaultImageModel))
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := provider.New(ctx, &modelCfg, runConfig.EnvProvider(), options.WithGateway(runConfig.ModelsGateway))
+	if err != nil {
+		return nil, fmt.Errorf("creating image model: %w", err)
+	}
+	generator, ok := model.(provider.ImageGenerationProvider)
+	if !ok {
+		return nil, fmt.Errorf("model %s does not support image generation", model.ID())
+	}
+	return builtin.NewImageGenerationTool(generator, runConfig.WorkingDir), nil
+}
+
 func createShellTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
-	env, err := environment.ExpandAll(ctx, environment.ToValues(toolset.Env), runConfig.EnvProvider())
+	env, err := toolsetEnv(ctx, toolset, runConfig.EnvProvider())
 	if err != nil {
-		return nil, fmt.Errorf("failed to expand the tool's environment variables: %w", err)
+		return nil, err
 	}
-	env = append(env, os.Environ()...)
 
-	return builtin.NewShellTool(env, runConfig), nil
+	tool := builtin.NewShellTool(env, runConfig)
+	if toolset.Root != "" {
+		ws, err := toolsetWorkspace(toolset, runConfig.WorkingDir, runConfig)
+		if err != nil {
+			return nil, err
+		}
+		tool.SetWorkspace(ws)
+	}
+	if toolset.PTY {
+		tool.EnablePTY()
+	}
+	return tool, nil
 }
 
 func createScriptTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
@@ -173,11 +265,10 @@ func createScriptTool(ctx context.Context, toolset latest.Toolset, _ string, run
 		return nil, errors.New("shell is required for script toolset")
 	}
 
-	env, err := environment.ExpandAll(ctx, environment.ToValues(toolset.Env), runConfig.EnvProvider())
+	env, err := toolsetEnv(ctx, toolset, runConfig.EnvProvider())
 	if err != nil {
-		return nil, fmt.Errorf("failed to expand the tool's environment variables: %w", err)
+		return nil, err
 	}
-	env = append(env, os.Environ()...)
 	return builtin.NewScriptShellTool(toolset.Shell, env)
 }
 
@@ -212,9 +303,25 @@ func createFilesystemTool(_ context.Context, toolset latest.Toolset, _ string, r
 		opts = append(opts, builtin.WithPostEditCommands(postEditConfigs))
 	}
 
+	ws, err := toolsetWorkspace(toolset, wd, runConfig)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, builtin.WithWorkspace(ws))
+
 	return builtin.NewFilesystemTool(wd, opts...), nil
 }
 
+// toolsetWorkspace returns the workspace of a toolset, whose primary root is
+// the root set on the toolset, if any.
+func toolsetWorkspace(toolset latest.Toolset, workingDir string, runConfig *config.RuntimeConfig) (workspace.Workspace, error) {
+	ws := workspace.New(workingDir, runConfig.AdditionalDirs...)
+	if toolset.Root == "" {
+		return ws, nil
+	}
+	return ws.WithPrimary(toolset.Root)
+}
+
 func createAPITool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
 	if toolset.APIConfig.Endpoint == "" {
 		return nil, errors.New("api tool requires an endpoint in api_config")
@@ -236,7 +343,52 @@ func createFetchTool(_ context.Context, toolset latest.Toolset, _ string, _ *con
 	return builtin.NewFetchTool(opts...), nil
 }
 
-func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+func createGitHubTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	envProvider := runConfig.EnvProvider()
+
+	token, _ := envProvider.Get(ctx, "GITHUB_TOKEN")
+	if token == "" {
+		token, _ = envProvider.Get(ctx, "GH_TOKEN")
+	}
+	apiURL := toolset.URL
+	if apiURL == "" {
+		apiURL, _ = envProvider.Get(ctx, "GITHUB_API_URL")
+	}
+
+	return builtin.NewGitHubTool(token, apiURL, runConfig.WorkingDir), nil
+}
+
+func createWASMTool(_ context.Context, toolset latest.Toolset, parentDir string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	// Modules are shipped with the agent: resolve them relative to its config.
+	var basePath string
+	if filepath.IsAbs(toolset.Path) {
+		basePath = ""
+	} else if parentDir != "" {
+		basePath = parentDir
+	} else {
+		basePath = runConfig.WorkingDir
+	}
+
+	modulePath, err := path.ValidatePathInDirectory(toolset.Path, basePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wasm module path: %w", err)
+	}
+
+	return wasm.NewToolset(modulePath, toolset.WASMConfig, runConfig.WorkingDir), nil
+}
+
+func createMCPTool(ctx context.Context, toolset latest.Toolset, parentDir string, runConfig *config.RuntimeConfig, configName string) (tools.ToolSet, error) {
+	ts, err := newMCPToolset(ctx, toolset, parentDir, runConfig, configName)
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := ts.(interface{ SetApproveToolChanges(bool) }); ok {
+		a.SetApproveToolChanges(toolset.ApproveToolChanges)
+	}
+	return ts, nil
+}
+
+func newMCPToolset(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
 	envProvider := runConfig.EnvProvider()
 
 	switch {
@@ -250,7 +402,9 @@ func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 
 		// TODO(dga): until the MCP Gateway supports oauth with docker agent, we fetch the remote url and directly connect to it.
 		if serverSpec.Type == "remote" {
-			return mcp.NewRemoteToolset(toolset.Name, serverSpec.Remote.URL, serverSpec.Remote.TransportType, nil), nil
+			ts := mcp.NewRemoteToolset(toolset.Name, serverSpec.Remote.URL, serverSpec.Remote.TransportType, nil)
+			ts.SetOAuthAccount(toolset.Remote.Account)
+			return ts, nil
 		}
 
 		env, err := environment.ExpandAll(ctx, environment.ToValues(toolset.Env), envProvider)
@@ -273,11 +427,10 @@ func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 			return nil, fmt.Errorf("resolving command %q: %w", toolset.Command, err)
 		}
 
-		env, err := environment.ExpandAll(ctx, environment.ToValues(toolset.Env), envProvider)
+		env, err := toolsetEnv(ctx, toolset, envProvider)
 		if err != nil {
-			return nil, fmt.Errorf("failed to expand the tool's environment variables: %w", err)
+			return nil, err
 		}
-		env = append(env, os.Environ()...)
 
 		// Prepend tools bin dir to PATH so child processes can find installed tools
 		env = toolinstall.PrependBinDirToEnv(env)
@@ -291,7 +444,9 @@ func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 		headers := expander.ExpandMap(ctx, toolset.Remote.Headers)
 		url := expander.Expand(ctx, toolset.Remote.URL, nil)
 
-		return mcp.NewRemoteToolset(toolset.Name, url, toolset.Remote.TransportType, headers), nil
+		ts := mcp.NewRemoteToolset(toolset.Name, url, toolset.Remote.TransportType, headers)
+		ts.SetOAuthAccount(toolset.Remote.Account)
+		return ts, nil
 
 	default:
 		return nil, errors.New("mcp toolset requires either ref, command, or remote configuration")
@@ -313,16 +468,20 @@ func createLSPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 		return nil, fmt.Errorf("resolving command %q: %w", toolset.Command, err)
 	}
 
-	env, err := environment.ExpandAll(ctx, environment.ToValues(toolset.Env), runConfig.EnvProvider())
+	env, err := toolsetEnv(ctx, toolset, runConfig.EnvProvider())
 	if err != nil {
-		return nil, fmt.Errorf("failed to expand the tool's environment variables: %w", err)
+		return nil, err
 	}
-	env = append(env, os.Environ()...)
 
 	// Prepend tools bin dir to PATH so child processes can find installed tools
 	env = toolinstall.PrependBinDirToEnv(env)
 
-	tool := builtin.NewLSPTool(resolvedCommand, toolset.Args, env, runConfig.WorkingDir)
+	ws, err := toolsetWorkspace(toolset, runConfig.WorkingDir, runConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	tool := builtin.NewLSPTool(resolvedCommand, toolset.Args, env, ws.Primary())
 	if len(toolset.FileTypes) > 0 {
 		tool.SetFileTypes(toolset.FileTypes)
 	}
@@ -334,6 +493,15 @@ func createUserPromptTool(_ context.Context, _ latest.Toolset, _ string, _ *conf
 	return builtin.NewUserPromptTool(), nil
 }
 
+func createEscalateTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	expander := js.NewJsExpander(runConfig.EnvProvider())
+
+	webhook := expander.Expand(ctx, toolset.Webhook, nil)
+	headers := expander.ExpandMap(ctx, toolset.Headers)
+
+	return builtin.NewEscalateTool(webhook, headers, toolset.Desktop), nil
+}
+
 func createOpenAPITool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
 	expander := js.NewJsExpander(runConfig.EnvProvider())
 
@@ -353,3 +521,11 @@ func createModelPickerTool(_ context.Context, toolset latest.Toolset, _ string,
 func createBackgroundAgentsTool(_ context.Context, _ latest.Toolset, _ string, _ *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
 	return agenttool.NewToolSet(), nil
 }
+
+func createGoogleSearchTool(_ context.Context, _ latest.Toolset, _ string, _ *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	return builtin.NewGoogleSearchTool(), nil
+}
+
+func createCodeExecutionTool(_ context.Context, _ latest.Toolset, _ string, _ *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	return builtin.NewCodeExecutionTool(), nil
+}
@@ -1,6 +1,8 @@
 package teamloader
 
 import (
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,3 +28,87 @@ func TestCreateShellTool(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, tool)
 }
+
+func TestCreateImageGenerationTool(t *testing.T) {
+	registry := NewDefaultToolsetRegistry()
+	runConfig := &config.RuntimeConfig{
+		Config: config.Config{WorkingDir: t.TempDir()},
+		EnvProviderForTests: environment.NewMapEnvProvider(map[string]string{
+			"OPENAI_API_KEY":    "test-key",
+			"ANTHROPIC_API_KEY": "test-key",
+		}),
+	}
+
+	tool, err := registry.CreateTool(t.Context(), latest.Toolset{Type: "image_generation"}, ".", runConfig, "test-agent")
+	require.NoError(t, err)
+	require.NotNil(t, tool)
+
+	_, err = registry.CreateTool(t.Context(), latest.Toolset{Type: "image_generation", ImageModel: "anthropic/claude-sonnet-4-5"}, ".", runConfig, "test-agent")
+	require.ErrorContains(t, err, "does not support image generation")
+}
+
+func TestCreateAgentConfigTool(t *testing.T) {
+	runConfig := &config.RuntimeConfig{
+		Config:              config.Config{WorkingDir: t.TempDir()},
+		EnvProviderForTests: environment.NewOsEnvProvider(),
+	}
+	toolset := latest.Toolset{Type: "agent_config"}
+
+	registry := NewDefaultToolsetRegistry().With("agent_config", agentConfigToolCreator(config.NewFileSource("agent.yaml")))
+	tool, err := registry.CreateTool(t.Context(), toolset, ".", runConfig, "test-agent")
+	require.NoError(t, err)
+	require.NotNil(t, tool)
+
+	registry = NewDefaultToolsetRegistry().With("agent_config", agentConfigToolCreator(config.NewBytesSource("agent", nil)))
+	_, err = registry.CreateTool(t.Context(), toolset, ".", runConfig, "test-agent")
+	require.ErrorContains(t, err, "needs an agent loaded from a local file")
+}
+
+func TestToolsetEnv(t *testing.T) {
+	t.Setenv("TOOLSET_ENV_SECRET", "s3cr3t")
+	t.Setenv("TOOLSET_ENV_OTHER", "other")
+	t.Setenv("TOOLSET_ENV_OVERRIDDEN", "parent")
+
+	toolset := latest.Toolset{
+		Type: "shell",
+		Env: map[string]string{
+			"API_KEY":                "${TOOLSET_ENV_SECRET}",
+			"TOOLSET_ENV_OVERRIDDEN": "declared",
+		},
+	}
+
+	// The whole environment is inherited by default, and declared variables
+	// take precedence.
+	env, err := toolsetEnv(t.Context(), toolset, environment.NewOsEnvProvider())
+	require.NoError(t, err)
+	require.Contains(t, env, "TOOLSET_ENV_OTHER=other")
+	require.Contains(t, env, "API_KEY=s3cr3t")
+	require.Equal(t, "declared", lookupEnv(env, "TOOLSET_ENV_OVERRIDDEN"))
+
+	// Only the declared variables and the system ones otherwise.
+	toolset.InheritEnv = new(false)
+	env, err = toolsetEnv(t.Context(), toolset, environment.NewOsEnvProvider())
+	require.NoError(t, err)
+	require.NotContains(t, env, "TOOLSET_ENV_OTHER=other")
+	require.NotContains(t, env, "TOOLSET_ENV_SECRET=s3cr3t")
+	require.Contains(t, env, "API_KEY=s3cr3t")
+	require.Equal(t, "declared", lookupEnv(env, "TOOLSET_ENV_OVERRIDDEN"))
+	require.Equal(t, os.Getenv("PATH"), lookupEnv(env, "PATH"))
+
+	// Missing variables are errors.
+	toolset.Env = map[string]string{"API_KEY": "${TOOLSET_ENV_MISSING}"}
+	_, err = toolsetEnv(t.Context(), toolset, environment.NewOsEnvProvider())
+	require.Error(t, err)
+}
+
+// lookupEnv returns the value of a variable like exec.Cmd does: the last one
+// wins.
+func lookupEnv(env []string, name string) string {
+	var value string
+	for _, e := range env {
+		if v, ok := strings.CutPrefix(e, name+"="); ok {
+			value = v
+		}
+	}
+	return value
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -16,26 +17,32 @@ import (
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/contentfilter"
+	"github.com/docker/docker-agent/pkg/guardrails"
 	"github.com/docker/docker-agent/pkg/js"
 	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/model/provider/dmr"
 	"github.com/docker/docker-agent/pkg/model/provider/options"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/permissions"
+	"github.com/docker/docker-agent/pkg/promptcompression"
 	"github.com/docker/docker-agent/pkg/rag"
 	"github.com/docker/docker-agent/pkg/skills"
 	"github.com/docker/docker-agent/pkg/team"
 	"github.com/docker/docker-agent/pkg/tools"
 	"github.com/docker/docker-agent/pkg/tools/builtin"
 	"github.com/docker/docker-agent/pkg/tools/codemode"
+	"github.com/docker/docker-agent/pkg/transcription"
 )
 
 var defaultMaxTokens int64 = 32000
 
 type loadOptions struct {
-	modelOverrides  []string
-	promptFiles     []string
-	toolsetRegistry *ToolsetRegistry
+	modelOverrides         []string
+	temperatureOverrides   []string
+	maxIterationsOverrides []string
+	promptFiles            []string
+	toolsetRegistry        *ToolsetRegistry
 }
 
 type Opt func(*loadOptions) error
@@ -47,6 +54,24 @@ func WithModelOverrides(overrides []string) Opt {
 	}
 }
 
+// WithTemperatureOverrides overrides the temperature of the models of
+// agents: [agent=]temperature.
+func WithTemperatureOverrides(overrides []string) Opt {
+	return func(opts *loadOptions) error {
+		opts.temperatureOverrides = overrides
+		return nil
+	}
+}
+
+// WithMaxIterationsOverrides overrides the maximum number of tool-calling
+// loops of agents: [agent=]iterations.
+func WithMaxIterationsOverrides(overrides []string) Opt {
+	return func(opts *loadOptions) error {
+		opts.maxIterationsOverrides = overrides
+		return nil
+	}
+}
+
 // WithPromptFiles adds additional prompt files to all agents.
 // These are merged with any prompt files defined in the agent config.
 func WithPromptFiles(files []string) Opt {
@@ -115,6 +140,12 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 	if err := config.ApplyModelOverrides(cfg, loadOpts.modelOverrides); err != nil {
 		return nil, err
 	}
+	if err := config.ApplyTemperatureOverrides(cfg, loadOpts.temperatureOverrides); err != nil {
+		return nil, err
+	}
+	if err := config.ApplyMaxIterationsOverrides(cfg, loadOpts.maxIterationsOverrides); err != nil {
+		return nil, err
+	}
 
 	// Early check for required env vars before loading models and tools.
 	env := runConfig.EnvProvider()
@@ -125,6 +156,9 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 	// Create RAG managers
 	parentDir := cmp.Or(agentSource.ParentDir(), runConfig.WorkingDir)
 	configName := configNameFromSource(agentSource.Name())
+	// agent_config toolsets edit the file of this configuration.
+	toolsetRegistry := loadOpts.toolsetRegistry.With("agent_config", agentConfigToolCreator(agentSource))
+	resolveMockFixtures(cfg, parentDir)
 	ragManagers, err := rag.NewManagers(ctx, cfg, rag.ManagersBuildConfig{
 		ParentDir:     parentDir,
 		ModelsGateway: runConfig.ModelsGateway,
@@ -144,6 +178,7 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 	})
 
 	expander := js.NewJsExpander(env)
+	templates := newTemplateContext(ctx, cfg, runConfig.WorkingDir, runConfig.Vars, env)
 
 	for _, agentConfig := range cfg.Agents {
 		// Merge CLI prompt files with agent config prompt files, deduplicating
@@ -168,9 +203,14 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			agent.WithAddDescriptionParameter(agentConfig.AddDescriptionParameter),
 			agent.WithAddPromptFiles(promptFiles),
 			agent.WithMaxIterations(agentConfig.MaxIterations),
+			agent.WithStreamIdleTimeout(agentConfig.GetStreamIdleTimeout()),
+			agent.WithToolTimeout(agentConfig.GetToolTimeout()),
 			agent.WithNumHistoryItems(agentConfig.NumHistoryItems),
 			agent.WithCommands(expander.ExpandCommands(ctx, agentConfig.Commands)),
 			agent.WithHooks(agentConfig.Hooks),
+			agent.WithContexts(agentConfig.Context),
+			agent.WithContinuity(agentConfig.Continuity),
+			agent.WithTaskOutput(agentConfig.TaskOutput),
 		}
 
 		models, thinkingConfigured, err := getModelsForAgent(ctx, cfg, &agentConfig, autoModel, runConfig)
@@ -197,13 +237,62 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			for _, model := range fallbackModels {
 				opts = append(opts, agent.WithFallbackModel(model))
 			}
+		}
+		// Retries also apply to the primary model when no fallback models are set
+		if agentConfig.Fallback != nil {
 			opts = append(opts,
 				agent.WithFallbackRetries(agentConfig.GetFallbackRetries()),
 				agent.WithFallbackCooldown(agentConfig.GetFallbackCooldown()),
+				agent.WithFallbackBackoff(agentConfig.GetFallbackBackoff()),
 			)
 		}
 
-		agentTools, warnings := getToolsForAgent(ctx, &agentConfig, parentDir, runConfig, loadOpts.toolsetRegistry, configName)
+		if agentConfig.ToolOutput != nil {
+			var summaryModel provider.Provider
+			if agentConfig.ToolOutput.SummaryModel != "" {
+				summaryModel, err = newTeamModel(ctx, cfg, runConfig, agentConfig.ToolOutput.SummaryModel, "tool_output")
+				if err != nil {
+					return nil, err
+				}
+			}
+			opts = append(opts, agent.WithToolOutputLimit(agentConfig.ToolOutput.MaxTokens, summaryModel))
+		}
+
+		if agentConfig.ToolSearch != nil {
+			opts = append(opts, agent.WithToolSearch(agentConfig.ToolSearch.Threshold, agentConfig.ToolSearch.MaxResults))
+		}
+
+		if len(agentConfig.Guardrails) > 0 {
+			agentGuardrails, err := getGuardrailsForAgent(ctx, cfg, &agentConfig, runConfig)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, agent.WithGuardrails(agentGuardrails))
+		}
+
+		if len(agentConfig.ContentFilters) > 0 {
+			var filters []*contentfilter.Filter
+			for i, fc := range agentConfig.ContentFilters {
+				f, err := contentfilter.New(i, fc)
+				if err != nil {
+					return nil, fmt.Errorf("agent '%s': %w", agentConfig.Name, err)
+				}
+				filters = append(filters, f)
+			}
+			opts = append(opts, agent.WithContentFilters(filters))
+		}
+
+		agentTools, warnings := getToolsForAgent(ctx, &agentConfig, parentDir, runConfig, toolsetRegistry, configName)
+
+		// A broken template shouldn't prevent the agent from starting:
+		// keep the raw instruction and warn about it.
+		instruction, err := templates.renderInstruction(ctx, &agentConfig)
+		if err != nil {
+			slog.Warn("Failed to render instruction template", "agent", agentConfig.Name, "error", err)
+			warnings = append(warnings, fmt.Sprintf("instruction template: %v", err))
+			instruction = agentConfig.Instruction
+		}
+
 		if len(warnings) > 0 {
 			opts = append(opts, agent.WithLoadTimeWarnings(warnings))
 		}
@@ -216,7 +305,7 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 
 		// Add skills toolset if skills are enabled
 		if agentConfig.Skills.Enabled() {
-			loadedSkills := skills.Load(agentConfig.Skills.Sources)
+			loadedSkills := skills.Load(skills.ResolvePaths(agentConfig.Skills.Sources, parentDir))
 			if len(loadedSkills) > 0 {
 				agentTools = append(agentTools, builtin.NewSkillsToolset(loadedSkills))
 			}
@@ -224,7 +313,7 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 
 		opts = append(opts, agent.WithToolSets(agentTools...))
 
-		ag := agent.New(agentConfig.Name, agentConfig.Instruction, opts...)
+		ag := agent.New(agentConfig.Name, instruction, opts...)
 		agents = append(agents, ag)
 		agentsByName[agentConfig.Name] = ag
 	}
@@ -241,7 +330,7 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			continue
 		}
 
-		subAgents, err := resolveAgentRefs(ctx, agentConfig.SubAgents, agentsByName, externalAgents, &agents, runConfig, &loadOpts)
+		subAgents, err := resolveAgentRefs(ctx, allowedDelegations(cfg.Delegation, agentConfig.Name, agentConfig.SubAgents), agentsByName, externalAgents, &agents, runConfig, &loadOpts)
 		if err != nil {
 			return nil, fmt.Errorf("agent '%s': resolving sub-agents: %w", agentConfig.Name, err)
 		}
@@ -249,7 +338,7 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			agent.WithSubAgents(subAgents...)(a)
 		}
 
-		handoffs, err := resolveAgentRefs(ctx, agentConfig.Handoffs, agentsByName, externalAgents, &agents, runConfig, &loadOpts)
+		handoffs, err := resolveAgentRefs(ctx, allowedDelegations(cfg.Delegation, agentConfig.Name, agentConfig.Handoffs), agentsByName, externalAgents, &agents, runConfig, &loadOpts)
 		if err != nil {
 			return nil, fmt.Errorf("agent '%s': resolving handoffs: %w", agentConfig.Name, err)
 		}
@@ -269,11 +358,31 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 		}
 	}
 
+	titleModel, err := getTitleModel(ctx, cfg, runConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	promptCompressor, err := getPromptCompressor(ctx, cfg, runConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriber, err := getTranscriber(ctx, cfg, runConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &LoadResult{
 		Team: team.New(
 			team.WithAgents(agents...),
 			team.WithRAGManagers(ragManagers),
 			team.WithPermissions(permChecker),
+			team.WithDelegationLimits(cfg.Delegation.GetMaxDepth(), cfg.Delegation.GetLoopLimit()),
+			team.WithTaskBudgets(cfg.Delegation.GetBudgets()),
+			team.WithTitles(titleModel, cfg.Titles == nil || !cfg.Titles.Disabled),
+			team.WithPromptCompressor(promptCompressor),
+			team.WithTranscriber(transcriber),
 		),
 		Models:             cfg.Models,
 		Providers:          cfg.Providers,
@@ -281,6 +390,21 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 	}, nil
 }
 
+// resolveMockFixtures makes the fixtures of mock models relative to the
+// directory of the agent configuration.
+func resolveMockFixtures(cfg *latest.Config, parentDir string) {
+	for name, model := range cfg.Models {
+		fixture, ok := model.ProviderOpts["fixture"].(string)
+		if model.Provider != "mock" || !ok || fixture == "" || filepath.IsAbs(fixture) || parentDir == "" {
+			continue
+		}
+
+		model.ProviderOpts = maps.Clone(model.ProviderOpts)
+		model.ProviderOpts["fixture"] = filepath.Join(parentDir, fixture)
+		cfg.Models[name] = model
+	}
+}
+
 func getModelsForAgent(ctx context.Context, cfg *latest.Config, a *latest.AgentConfig, autoModelFn func() latest.ModelConfig, runConfig *config.RuntimeConfig) ([]provider.Provider, bool, error) {
 	var models []provider.Provider
 	thinkingConfigured := false
@@ -406,6 +530,100 @@ func getFallbackModelsForAgent(ctx context.Context, cfg *latest.Config, a *lates
 	return fallbackModels, nil
 }
 
+// getGuardrailsForAgent creates the guardrails of an agent, with the models
+// checking their policies.
+func getGuardrailsForAgent(ctx context.Context, cfg *latest.Config, a *latest.AgentConfig, runConfig *config.RuntimeConfig) ([]*guardrails.Guardrail, error) {
+	var result []*guardrails.Guardrail
+	for i, gc := range a.Guardrails {
+		var model provider.Provider
+		if gc.Model != "" {
+			var err error
+			if model, err = newTeamModel(ctx, cfg, runConfig, gc.Model, "guardrail"); err != nil {
+				return nil, err
+			}
+		}
+		g, err := guardrails.New(i, gc, model)
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s': %w", a.Name, err)
+		}
+		result = append(result, g)
+	}
+	return result, nil
+}
+
+// getTitleModel returns the model configured to generate session titles,
+// or nil if titles are generated with the model of the current agent.
+func getTitleModel(ctx context.Context, cfg *latest.Config, runConfig *config.RuntimeConfig) (provider.Provider, error) {
+	if cfg.Titles == nil || cfg.Titles.Model == "" || cfg.Titles.Disabled {
+		return nil, nil
+	}
+	return newTeamModel(ctx, cfg, runConfig, cfg.Titles.Model, "titles")
+}
+
+// getPromptCompressor returns the compressor of old tool outputs, or nil if
+// prompt compression isn't configured.
+func getPromptCompressor(ctx context.Context, cfg *latest.Config, runConfig *config.RuntimeConfig) (*promptcompression.Compressor, error) {
+	pc := cfg.PromptCompression
+	if pc == nil {
+		return nil, nil
+	}
+	model, err := newTeamModel(ctx, cfg, runConfig, pc.Model, "prompt_compression")
+	if err != nil {
+		return nil, err
+	}
+	return promptcompression.New(model, pc.MinLength, pc.KeepRecent), nil
+}
+
+// getTranscriber returns the transcriber of attached audio and video files.
+// Without a transcription section, files are replaced with a note saying
+// that they can't be transcribed.
+func getTranscriber(ctx context.Context, cfg *latest.Config, runConfig *config.RuntimeConfig) (*transcription.Transcriber, error) {
+	tc := cfg.Transcription
+	if tc == nil {
+		return transcription.New(nil, nil, 0), nil
+	}
+
+	model, err := newTeamModel(ctx, cfg, runConfig, tc.Model, "transcription")
+	if err != nil {
+		return nil, err
+	}
+	transcriptionModel, ok := model.(provider.MediaTranscriptionProvider)
+	if !ok {
+		return nil, fmt.Errorf("transcription model '%s' can't transcribe audio and video files: use an OpenAI transcription model or a Gemini model", tc.Model)
+	}
+
+	var summaryModel provider.Provider
+	if tc.SummaryModel != "" {
+		if summaryModel, err = newTeamModel(ctx, cfg, runConfig, tc.SummaryModel, "transcription summary"); err != nil {
+			return nil, err
+		}
+	}
+	return transcription.New(transcriptionModel, summaryModel, tc.MaxLength), nil
+}
+
+// newTeamModel creates a helper model, e.g. one generating titles, rather
+// than the model of an agent. section names the configuration section
+// referencing it.
+func newTeamModel(ctx context.Context, cfg *latest.Config, runConfig *config.RuntimeConfig, name, section string) (provider.Provider, error) {
+	modelCfg, exists := cfg.Models[name]
+	if !exists {
+		return nil, fmt.Errorf("%s model '%s' not found in configuration", section, name)
+	}
+	modelCfg.Name = name
+
+	model, err := provider.NewWithModels(ctx,
+		&modelCfg,
+		cfg.Models,
+		runConfig.EnvProvider(),
+		options.WithGateway(runConfig.ModelsGateway),
+		options.WithProviders(cfg.Providers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s model '%s': %w", section, name, err)
+	}
+	return model, nil
+}
+
 // getToolsForAgent returns the tool definitions for an agent based on its configuration
 func getToolsForAgent(ctx context.Context, a *latest.AgentConfig, parentDir string, runConfig *config.RuntimeConfig, registry *ToolsetRegistry, configName string) ([]tools.ToolSet, []string) {
 	var (
@@ -427,7 +645,14 @@ func getToolsForAgent(ctx context.Context, a *latest.AgentConfig, parentDir stri
 			continue
 		}
 
+		if toolset.Lazy {
+			tool = WithLazyStart(tool, lazyToolsCacheFile(toolset))
+		}
+
 		wrapped := WithToolsFilter(tool, toolset.Tools...)
+		wrapped = WithToolsExcludeFilter(wrapped, toolset.Exclude...)
+		wrapped = WithToolsAliases(wrapped, toolset.Alias)
+		wrapped = WithNamespace(wrapped, toolsetNamespace(toolset))
 		wrapped = WithInstructions(wrapped, toolset.Instruction)
 		wrapped = WithToon(wrapped, toolset.Toon)
 		wrapped = WithModelOverride(wrapped, toolset.Model)
@@ -473,10 +698,19 @@ func getToolsForAgent(ctx context.Context, a *latest.AgentConfig, parentDir stri
 
 	if len(a.SubAgents) > 0 {
 		toolSets = append(toolSets, builtin.NewTransferTaskTool())
+		if a.SubAgentTraces {
+			toolSets = append(toolSets, builtin.NewSubAgentTraceTool())
+		}
 	}
 	if len(a.Handoffs) > 0 {
 		toolSets = append(toolSets, builtin.NewHandoffTool())
 	}
+	if a.GetMaxToolOutputTokens() > 0 {
+		toolSets = append(toolSets, builtin.NewToolOutputTool())
+	}
+	if a.ToolSearch != nil && a.ToolSearch.Threshold > 0 {
+		toolSets = append(toolSets, builtin.NewToolSearchTool())
+	}
 
 	// Wrap all tools in a single Code Mode toolset.
 	// This allows the agent to call multiple tools in a single response.
@@ -506,6 +740,18 @@ func configNameFromSource(sourceName string) string {
 	return base + "-" + hex.EncodeToString(h[:4])
 }
 
+// allowedDelegations filters an agent's sub-agent or handoff references
+// down to those the delegation graph allows it to delegate to.
+func allowedDelegations(delegation *latest.DelegationConfig, agentName string, refs []string) []string {
+	allowed, restricted := delegation.AllowedTargets(agentName)
+	if !restricted {
+		return refs
+	}
+	return slices.DeleteFunc(slices.Clone(refs), func(ref string) bool {
+		return !slices.Contains(allowed, ref)
+	})
+}
+
 // resolveAgentRefs resolves a list of agent references to agent instances.
 // References that match a locally-defined agent name are looked up directly.
 // References that are external (OCI or URL) are loaded on-demand and cached
@@ -483,3 +483,19 @@ func TestExternalDepthContext(t *testing.T) {
 	ctx = contextWithExternalDepth(ctx, 7)
 	assert.Equal(t, 7, externalDepthFromContext(ctx))
 }
+
+func TestAllowedDelegations(t *testing.T) {
+	t.Parallel()
+
+	delegation := &latest.DelegationConfig{Allow: map[string][]string{
+		"root":   {"writer"},
+		"writer": {},
+	}}
+	refs := []string{"researcher", "writer"}
+
+	assert.Equal(t, []string{"writer"}, allowedDelegations(delegation, "root", refs))
+	assert.Empty(t, allowedDelegations(delegation, "writer", refs))
+	assert.Equal(t, refs, allowedDelegations(delegation, "researcher", refs))
+	assert.Equal(t, refs, allowedDelegations(nil, "root", refs))
+	assert.Equal(t, []string{"researcher", "writer"}, refs, "references are left untouched")
+}
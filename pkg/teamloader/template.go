@@ -0,0 +1,120 @@
+package teamloader
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+// gitBranchTimeout bounds the time spent looking up the current git branch.
+const gitBranchTimeout = 2 * time.Second
+
+// templateContext holds what's shared by the instruction templates of all
+// the agents of a config.
+type templateContext struct {
+	env      environment.Provider
+	builtins func() map[string]string
+	vars     map[string]string
+	cliVars  map[string]string
+	partials map[string]string
+}
+
+// newTemplateContext collects the config vars and partials, and the vars set
+// on the command line. Built-in variables are only computed once an
+// instruction actually uses templating.
+func newTemplateContext(ctx context.Context, cfg *latest.Config, workingDir string, cliVars map[string]string, env environment.Provider) *templateContext {
+	return &templateContext{
+		env: env,
+		builtins: sync.OnceValue(func() map[string]string {
+			workingDir := cmp.Or(workingDir, currentDir())
+			return map[string]string{
+				"Date":       time.Now().Format("2006-01-02"),
+				"WorkingDir": workingDir,
+				"GitBranch":  gitBranch(ctx, workingDir),
+				"OS":         runtime.GOOS,
+			}
+		}),
+		vars:     cfg.Vars,
+		cliVars:  cliVars,
+		partials: cfg.Partials,
+	}
+}
+
+// renderInstruction renders an agent's instruction as a Go template.
+// Instructions that don't use template actions are returned unchanged.
+//
+// Variables are resolved, by increasing precedence, from the built-ins
+// (Date, WorkingDir, GitBranch, OS and AgentName), the top-level vars, the
+// agent's vars and the --var flags.
+func (tc *templateContext) renderInstruction(ctx context.Context, agentConfig *latest.AgentConfig) (string, error) {
+	instruction := agentConfig.Instruction
+	if !strings.Contains(instruction, "{{") {
+		return instruction, nil
+	}
+
+	data := maps.Clone(tc.builtins())
+	data["AgentName"] = agentConfig.Name
+	maps.Copy(data, tc.vars)
+	maps.Copy(data, agentConfig.Vars)
+	maps.Copy(data, tc.cliVars)
+
+	funcs := template.FuncMap{
+		"env": func(name string) string {
+			v, _ := tc.env.Get(ctx, name)
+			return v
+		},
+	}
+
+	tmpl := template.New(agentConfig.Name).Funcs(funcs).Option("missingkey=error")
+	for name, partial := range tc.partials {
+		if _, err := tmpl.New(name).Parse(partial); err != nil {
+			return "", fmt.Errorf("parsing partial %q: %w", name, err)
+		}
+	}
+	if _, err := tmpl.Parse(instruction); err != nil {
+		return "", fmt.Errorf("parsing instruction: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering instruction: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func currentDir() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return wd
+}
+
+// gitBranch returns the current branch of the git repository containing dir,
+// or an empty string if there's none.
+func gitBranch(ctx context.Context, dir string) string {
+	if dir == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gitBranchTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,83 @@
+package teamloader
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/environment"
+)
+
+func TestRenderInstruction_NoTemplate(t *testing.T) {
+	t.Parallel()
+
+	tc := newTemplateContext(t.Context(), &latest.Config{}, t.TempDir(), nil, environment.NewNoEnvProvider())
+
+	instruction, err := tc.renderInstruction(t.Context(), &latest.AgentConfig{Instruction: "Hello ${env.USER}"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello ${env.USER}", instruction)
+}
+
+func TestRenderInstruction_VarsPrecedence(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Vars: map[string]string{"team": "core", "repo": "cagent", "lang": "go"},
+	}
+	tc := newTemplateContext(t.Context(), cfg, t.TempDir(), map[string]string{"team": "payments"}, environment.NewNoEnvProvider())
+
+	instruction, err := tc.renderInstruction(t.Context(), &latest.AgentConfig{
+		Name:        "root",
+		Instruction: "{{ .AgentName }} works for {{ .team }} on {{ .repo }} in {{ .lang }} ({{ .OS }})",
+		Vars:        map[string]string{"team": "billing", "repo": "ledger"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "root works for payments on ledger in go ("+runtime.GOOS+")", instruction)
+}
+
+func TestRenderInstruction_Builtins(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tc := newTemplateContext(t.Context(), &latest.Config{}, dir, nil, environment.NewNoEnvProvider())
+
+	instruction, err := tc.renderInstruction(t.Context(), &latest.AgentConfig{
+		Instruction: "{{ .WorkingDir }} [{{ .GitBranch }}] {{ if .Date }}dated{{ end }}",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dir+" [] dated", instruction)
+}
+
+func TestRenderInstruction_PartialsAndEnv(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{
+		Vars: map[string]string{"team": "payments"},
+		Partials: map[string]string{
+			"rules": "Always follow the {{ .team }} coding rules.",
+		},
+	}
+	env := environment.NewMapEnvProvider(map[string]string{"REGION": "eu-west-1"})
+	tc := newTemplateContext(t.Context(), cfg, t.TempDir(), nil, env)
+
+	instruction, err := tc.renderInstruction(t.Context(), &latest.AgentConfig{
+		Instruction: `You deploy to {{ env "REGION" }}. {{ template "rules" . }}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "You deploy to eu-west-1. Always follow the payments coding rules.", instruction)
+}
+
+func TestRenderInstruction_Errors(t *testing.T) {
+	t.Parallel()
+
+	tc := newTemplateContext(t.Context(), &latest.Config{}, t.TempDir(), nil, environment.NewNoEnvProvider())
+
+	_, err := tc.renderInstruction(t.Context(), &latest.AgentConfig{Instruction: "{{ .missing }}"})
+	require.ErrorContains(t, err, "rendering instruction")
+
+	_, err = tc.renderInstruction(t.Context(), &latest.AgentConfig{Instruction: "{{ .unclosed"})
+	require.ErrorContains(t, err, "parsing instruction")
+}
@@ -0,0 +1,65 @@
+package termimage
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Protocol is a terminal graphics protocol.
+type Protocol int
+
+const (
+	// None means images are rendered with colored half blocks.
+	None Protocol = iota
+	Kitty
+	ITerm2
+	Sixel
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case Kitty:
+		return "kitty"
+	case ITerm2:
+		return "iterm2"
+	case Sixel:
+		return "sixel"
+	default:
+		return "none"
+	}
+}
+
+// Detect returns the graphics protocol supported by the terminal, guessed
+// from its environment variables. The result is computed once.
+var Detect = sync.OnceValue(func() Protocol {
+	return detect(os.Getenv)
+})
+
+func detect(getenv func(string) string) Protocol {
+	// Multiplexers need their own passthrough escape sequences.
+	if getenv("TMUX") != "" || strings.HasPrefix(getenv("TERM"), "screen") {
+		return None
+	}
+
+	term := getenv("TERM")
+	termProgram := getenv("TERM_PROGRAM")
+
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "",
+		strings.Contains(term, "kitty"),
+		strings.Contains(term, "ghostty"),
+		termProgram == "ghostty":
+		return Kitty
+	case termProgram == "iTerm.app",
+		termProgram == "WezTerm",
+		getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm2
+	case strings.HasPrefix(term, "foot"),
+		strings.HasPrefix(term, "mlterm"),
+		strings.Contains(term, "sixel"),
+		termProgram == "contour":
+		return Sixel
+	}
+	return None
+}
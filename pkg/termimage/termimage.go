@@ -0,0 +1,253 @@
+// Package termimage displays images in terminals, using the kitty, iTerm2 or
+// sixel graphics protocols when the terminal supports one of them, and
+// colored half blocks otherwise.
+package termimage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"math/rand/v2"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/iterm2"
+	"github.com/charmbracelet/x/ansi/kitty"
+	"github.com/charmbracelet/x/ansi/sixel"
+	"golang.org/x/image/draw"
+
+	// Register the decoders of the image formats agents work with.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxCols is the maximum width of an image, in cells.
+	MaxCols = 48
+	// MaxRows is the maximum height of an image, in cells.
+	MaxRows = 16
+	// cellWidthPx and cellHeightPx approximate the size of a terminal cell,
+	// in pixels.
+	cellWidthPx  = 10
+	cellHeightPx = 20
+)
+
+// Image is a decoded image, sized to fit in the terminal.
+type Image struct {
+	// Cols and Rows are the size of the image, in cells.
+	Cols, Rows int
+
+	id          uint32
+	data        []byte
+	img         image.Image
+	transmitted bool
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[[sha256.Size]byte]*Image{}
+	nextID  = rand.Uint32N(1<<24-1<<16) + 1
+)
+
+// Decode decodes a base64 encoded image. Images are cached so that decoding
+// the same data twice returns the same image.
+func Decode(data string) (*Image, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64 image: %w", err)
+	}
+
+	key := sha256.Sum256(raw)
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if img, ok := cache[key]; ok {
+		return img, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, errors.New("empty image")
+	}
+
+	cols, rows := fit(bounds.Dx(), bounds.Dy())
+	i := &Image{
+		Cols: cols,
+		Rows: rows,
+		id:   nextID,
+		data: raw,
+		img:  img,
+	}
+	// Kitty image IDs are encoded in 24-bit colors.
+	nextID = nextID%(1<<24-1) + 1
+	cache[key] = i
+	return i, nil
+}
+
+// DecodeDataURL decodes an image given as a base64 data URL.
+func DecodeDataURL(url string) (*Image, error) {
+	header, data, ok := strings.Cut(url, ",")
+	if !ok || !strings.HasPrefix(header, "data:image/") || !strings.HasSuffix(header, ";base64") {
+		return nil, errors.New("not a base64 image data URL")
+	}
+	return Decode(data)
+}
+
+// fit returns the size in cells of an image of the given size in pixels,
+// keeping its aspect ratio and staying within MaxCols and MaxRows.
+func fit(width, height int) (cols, rows int) {
+	cols = min(MaxCols, max(1, (width+cellWidthPx-1)/cellWidthPx))
+	rows = max(1, (cols*height*cellWidthPx+width*cellHeightPx/2)/(width*cellHeightPx))
+	if rows > MaxRows {
+		rows = MaxRows
+		cols = max(1, (rows*width*cellHeightPx+height*cellWidthPx/2)/(height*cellWidthPx))
+	}
+	return cols, rows
+}
+
+// Inline returns the escape sequences that display the image at the cursor,
+// using the given protocol. This is meant for plain terminal output, not for
+// full-screen applications that redraw the screen.
+func (i *Image) Inline(p Protocol) string {
+	var b strings.Builder
+
+	switch p {
+	case Kitty:
+		err := kitty.EncodeGraphics(&b, i.img, &kitty.Options{
+			Action:       kitty.TransmitAndPut,
+			Transmission: kitty.Direct,
+			Format:       kitty.PNG,
+			Quite:        2,
+			Columns:      i.Cols,
+			Rows:         i.Rows,
+			Chunk:        true,
+		})
+		if err != nil {
+			return i.HalfBlocks(i.Cols)
+		}
+
+	case ITerm2:
+		b.WriteString(ansi.ITerm2(iterm2.File{
+			Inline:  true,
+			Size:    int64(len(i.data)),
+			Width:   iterm2.Cells(i.Cols),
+			Height:  iterm2.Cells(i.Rows),
+			Content: []byte(base64.StdEncoding.EncodeToString(i.data)),
+		}))
+
+	case Sixel:
+		scaled := image.NewRGBA(image.Rect(0, 0, i.Cols*cellWidthPx, i.Rows*cellHeightPx))
+		draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), i.img, i.img.Bounds(), draw.Src, nil)
+		var payload bytes.Buffer
+		if err := (&sixel.Encoder{}).Encode(&payload, scaled); err != nil {
+			return i.HalfBlocks(i.Cols)
+		}
+		b.WriteString(ansi.SixelGraphics(0, 1, 0, payload.Bytes()))
+
+	default:
+		return i.HalfBlocks(i.Cols)
+	}
+
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// Transmit returns the kitty escape sequences that send the image to the
+// terminal, to be displayed with Placeholders. It returns an empty string
+// once the image was transmitted.
+func (i *Image) Transmit() string {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if i.transmitted {
+		return ""
+	}
+
+	var b strings.Builder
+	err := kitty.EncodeGraphics(&b, i.img, &kitty.Options{
+		Action:           kitty.TransmitAndPut,
+		Transmission:     kitty.Direct,
+		Format:           kitty.PNG,
+		Quite:            2,
+		ID:               int(i.id),
+		Columns:          i.Cols,
+		Rows:             i.Rows,
+		VirtualPlacement: true,
+		Chunk:            true,
+	})
+	if err != nil {
+		return ""
+	}
+	i.transmitted = true
+	return b.String()
+}
+
+// Transmitted returns whether the image was sent to a kitty terminal.
+func (i *Image) Transmitted() bool {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return i.transmitted
+}
+
+// Placeholders returns the kitty Unicode placeholders that display a
+// transmitted image. Unlike other graphics protocols, placeholders are plain
+// text cells, so they survive the redraws of full-screen applications.
+func (i *Image) Placeholders() string {
+	var b strings.Builder
+	color := fmt.Sprintf("\x1b[38;2;%d;%d;%dm", byte(i.id>>16), byte(i.id>>8), byte(i.id))
+	for row := range i.Rows {
+		if row > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(color)
+		for col := range i.Cols {
+			b.WriteRune(kitty.Placeholder)
+			b.WriteRune(kitty.Diacritic(row))
+			b.WriteRune(kitty.Diacritic(col))
+		}
+		b.WriteString("\x1b[39m")
+	}
+	return b.String()
+}
+
+// HalfBlocks renders the image with colored half blocks, two pixels per cell,
+// at most the given number of columns wide. This works in every terminal that
+// supports true colors.
+func (i *Image) HalfBlocks(cols int) string {
+	cols = max(1, min(cols, i.Cols))
+	bounds := i.img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	rows := max(1, (cols*h*cellWidthPx+w*cellHeightPx/2)/(w*cellHeightPx))
+
+	pixel := func(x, y int) (r, g, b uint8) {
+		c := i.img.At(bounds.Min.X+x*w/cols, bounds.Min.Y+y*h/(2*rows))
+		cr, cg, cb, _ := c.RGBA()
+		return uint8(cr >> 8), uint8(cg >> 8), uint8(cb >> 8)
+	}
+
+	var b strings.Builder
+	for y := range rows {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := range cols {
+			tr, tg, tb := pixel(x, 2*y)
+			br, bg, bb := pixel(x, 2*y+1)
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", tr, tg, tb, br, bg, bb)
+		}
+		b.WriteString("\x1b[m")
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package termimage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{env: map[string]string{"TERM": "xterm-256color"}, want: None},
+		{env: map[string]string{"TERM": "xterm-kitty"}, want: Kitty},
+		{env: map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, want: Kitty},
+		{env: map[string]string{"TERM": "xterm-ghostty"}, want: Kitty},
+		{env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, want: ITerm2},
+		{env: map[string]string{"TERM_PROGRAM": "WezTerm"}, want: ITerm2},
+		{env: map[string]string{"TERM": "foot"}, want: Sixel},
+		{env: map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, want: None},
+	}
+	for _, tt := range tests {
+		got := detect(func(key string) string { return tt.env[key] })
+		assert.Equal(t, tt.want, got, "%v", tt.env)
+	}
+}
+
+func TestFit(t *testing.T) {
+	t.Parallel()
+
+	cols, rows := fit(100, 50)
+	assert.Equal(t, 10, cols)
+	assert.Equal(t, 3, rows)
+
+	cols, rows = fit(2000, 1000)
+	assert.Equal(t, MaxCols, cols)
+	assert.Equal(t, 12, rows)
+
+	cols, rows = fit(100, 2000)
+	assert.LessOrEqual(t, rows, MaxRows)
+	assert.GreaterOrEqual(t, cols, 1)
+}
+
+func TestDecodeIsCached(t *testing.T) {
+	t.Parallel()
+
+	data := encodePNG(t, 40, 20)
+	img, err := Decode(data)
+	require.NoError(t, err)
+	again, err := Decode(data)
+	require.NoError(t, err)
+	assert.Same(t, img, again)
+
+	fromURL, err := DecodeDataURL("data:image/png;base64," + data)
+	require.NoError(t, err)
+	assert.Same(t, img, fromURL)
+
+	_, err = DecodeDataURL("https://example.com/image.png")
+	require.Error(t, err)
+	_, err = Decode(base64.StdEncoding.EncodeToString([]byte("not an image")))
+	require.Error(t, err)
+}
+
+func TestHalfBlocks(t *testing.T) {
+	t.Parallel()
+
+	img, err := Decode(encodePNG(t, 200, 100))
+	require.NoError(t, err)
+
+	view := img.HalfBlocks(8)
+	lines := strings.Split(view, "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Equal(t, 8, ansi.StringWidth(line))
+	}
+}
+
+func TestTransmitOnce(t *testing.T) {
+	t.Parallel()
+
+	img, err := Decode(encodePNG(t, 30, 30))
+	require.NoError(t, err)
+
+	seq := img.Transmit()
+	assert.True(t, strings.HasPrefix(seq, "\x1b_G"))
+	assert.Contains(t, seq, "U=1")
+	assert.True(t, img.Transmitted())
+	assert.Empty(t, img.Transmit())
+
+	placeholders := strings.Split(img.Placeholders(), "\n")
+	assert.Len(t, placeholders, img.Rows)
+}
+
+func TestInline(t *testing.T) {
+	t.Parallel()
+
+	img, err := Decode(encodePNG(t, 50, 50))
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(img.Inline(Kitty), "\x1b_G"))
+	assert.True(t, strings.HasPrefix(img.Inline(ITerm2), "\x1b]1337;File="))
+	assert.True(t, strings.HasPrefix(img.Inline(Sixel), "\x1bP"))
+	assert.Contains(t, img.Inline(None), "▀")
+}
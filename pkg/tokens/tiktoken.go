@@ -0,0 +1,138 @@
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Overheads of the chat format of OpenAI models, in tokens.
+const (
+	tokensPerMessage = 3
+	tokensPerTool    = 8
+	// replyPriming is added to every request: each reply starts with
+	// <|start|>assistant<|message|>.
+	replyPriming = 3
+)
+
+// encodingForModel returns the tiktoken encoding of an OpenAI model, or ""
+// for models of other vendors. Models served by other providers (e.g.
+// "openai/gpt-4o" on a gateway) are recognized by their name.
+func encodingForModel(model string) string {
+	model = strings.ToLower(model)
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+
+	switch {
+	case strings.HasPrefix(model, "gpt-4o"),
+		strings.HasPrefix(model, "gpt-4.1"),
+		strings.HasPrefix(model, "gpt-4.5"),
+		strings.HasPrefix(model, "gpt-5"),
+		strings.HasPrefix(model, "gpt-oss"),
+		strings.HasPrefix(model, "chatgpt-"),
+		strings.HasPrefix(model, "o1"),
+		strings.HasPrefix(model, "o3"),
+		strings.HasPrefix(model, "o4"):
+		return tiktoken.MODEL_O200K_BASE
+	case strings.HasPrefix(model, "gpt-4"),
+		strings.HasPrefix(model, "gpt-3.5"):
+		return tiktoken.MODEL_CL100K_BASE
+	default:
+		return ""
+	}
+}
+
+// The BPE ranks of the encodings are embedded in the binary, so tokens are
+// counted without downloading them, even offline.
+func init() {
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+// encoding is a tiktoken encoding, loaded in the background on first use:
+// parsing its BPE ranks takes a while.
+type encoding struct {
+	once  sync.Once
+	ready chan struct{}
+	tk    *tiktoken.Tiktoken
+}
+
+var encodings sync.Map // encoding name -> *encoding
+
+// get returns the encoding if it's loaded. It starts loading it otherwise,
+// so that counting never blocks on it.
+func (e *encoding) get(name string) *tiktoken.Tiktoken {
+	e.once.Do(func() {
+		go func() {
+			defer close(e.ready)
+			tk, err := tiktoken.GetEncoding(name)
+			if err != nil {
+				slog.Warn("Failed to load tiktoken encoding, token counts will be estimated", "encoding", name, "error", err)
+				return
+			}
+			e.tk = tk
+		}()
+	})
+
+	select {
+	case <-e.ready:
+		return e.tk
+	default:
+		return nil
+	}
+}
+
+func loadEncoding(name string) *tiktoken.Tiktoken {
+	e, _ := encodings.LoadOrStore(name, &encoding{ready: make(chan struct{})})
+	return e.(*encoding).get(name)
+}
+
+// tiktokenCounter counts tokens like OpenAI models do. Until the encoding is
+// loaded, or if it can't be, tokens are estimated with the heuristic.
+type tiktokenCounter struct {
+	encoding string
+}
+
+func (c tiktokenCounter) CountTokens(ctx context.Context, messages []chat.Message, tools []tools.Tool) (int64, error) {
+	tk := loadEncoding(c.encoding)
+	if tk == nil {
+		return Heuristic.CountTokens(ctx, messages, tools)
+	}
+
+	count := func(text string) int64 {
+		if text == "" {
+			return 0
+		}
+		return int64(len(tk.Encode(text, nil, nil)))
+	}
+
+	n := int64(replyPriming)
+	for i := range messages {
+		msg := &messages[i]
+		n += tokensPerMessage + count(string(msg.Role)) + count(msg.Content) + count(msg.ReasoningContent)
+		for _, part := range msg.MultiContent {
+			n += count(part.Text)
+		}
+		for _, tc := range msg.ToolCalls {
+			n += tokensPerMessage + count(tc.Function.Name) + count(tc.Function.Arguments)
+		}
+	}
+	for i := range tools {
+		tool := &tools[i]
+		n += tokensPerTool + count(tool.Name) + count(tool.Description)
+		if tool.Parameters != nil {
+			if params, err := json.Marshal(tool.Parameters); err == nil {
+				n += count(string(params))
+			}
+		}
+	}
+	return n, nil
+}
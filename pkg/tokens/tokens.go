@@ -0,0 +1,88 @@
+// Package tokens counts the tokens of model requests.
+//
+// Counting is as accurate as the model allows:
+//
+//   - providers that can count tokens with their API (e.g. Anthropic)
+//     implement [provider.TokenCounter],
+//   - OpenAI models are counted locally with their tiktoken encoding,
+//   - other models fall back to a conservative character-based heuristic.
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/compaction"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Counter counts the input tokens of a request.
+type Counter interface {
+	CountTokens(ctx context.Context, messages []chat.Message, tools []tools.Tool) (int64, error)
+}
+
+// Heuristic estimates token counts from the length of the text. It
+// overestimates rather than underestimates, and never fails.
+var Heuristic Counter = heuristic{}
+
+// ForModel returns the most accurate counter for a model. It may call the
+// API of the provider, so prefer [Local] for frequent estimates.
+func ForModel(p provider.Provider) Counter {
+	if tc, ok := p.(provider.TokenCounter); ok {
+		return tc
+	}
+	return Local(p)
+}
+
+// Local returns the most accurate counter for a model that doesn't make
+// network calls to count.
+func Local(p provider.Provider) Counter {
+	if encoding := encodingForModel(p.BaseConfig().ModelConfig.Model); encoding != "" {
+		return tiktokenCounter{encoding: encoding}
+	}
+	return Heuristic
+}
+
+// Count counts the tokens of a request with c, falling back to the heuristic
+// when c fails.
+func Count(ctx context.Context, c Counter, messages []chat.Message, tools []tools.Tool) int64 {
+	n, err := c.CountTokens(ctx, messages, tools)
+	if err != nil {
+		slog.Debug("Failed to count tokens, estimating them", "error", err)
+		n, _ = Heuristic.CountTokens(ctx, messages, tools)
+	}
+	return n
+}
+
+type heuristic struct{}
+
+func (heuristic) CountTokens(_ context.Context, messages []chat.Message, tools []tools.Tool) (int64, error) {
+	var n int64
+	for i := range messages {
+		n += compaction.EstimateMessageTokens(&messages[i])
+	}
+	for i := range tools {
+		n += estimateToolTokens(&tools[i])
+	}
+	return n, nil
+}
+
+// estimateToolTokens estimates the tokens of a tool definition with the same
+// heuristic as compaction.EstimateMessageTokens.
+func estimateToolTokens(tool *tools.Tool) int64 {
+	const (
+		charsPerToken   = 4
+		perToolOverhead = 5
+	)
+
+	chars := len(tool.Name) + len(tool.Description)
+	if tool.Parameters != nil {
+		if params, err := json.Marshal(tool.Parameters); err == nil {
+			chars += len(params)
+		}
+	}
+	return int64(chars/charsPerToken) + perToolOverhead
+}
@@ -0,0 +1,126 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pkoukk/tiktoken-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+type fakeProvider struct {
+	model string
+}
+
+func (p *fakeProvider) ID() string { return "fake/" + p.model }
+
+func (p *fakeProvider) CreateChatCompletionStream(context.Context, []chat.Message, []tools.Tool) (chat.MessageStream, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProvider) BaseConfig() base.Config {
+	return base.Config{ModelConfig: latest.ModelConfig{Provider: "fake", Model: p.model}}
+}
+
+type countingProvider struct {
+	fakeProvider
+	count int64
+	err   error
+}
+
+func (p *countingProvider) CountTokens(context.Context, []chat.Message, []tools.Tool) (int64, error) {
+	return p.count, p.err
+}
+
+func TestEncodingForModel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"gpt-4o", "o200k_base"},
+		{"gpt-4o-mini", "o200k_base"},
+		{"gpt-4.1", "o200k_base"},
+		{"gpt-5-mini", "o200k_base"},
+		{"o3-mini", "o200k_base"},
+		{"openai/gpt-4o", "o200k_base"},
+		{"GPT-4o", "o200k_base"},
+		{"gpt-4", "cl100k_base"},
+		{"gpt-4-turbo", "cl100k_base"},
+		{"gpt-3.5-turbo", "cl100k_base"},
+		{"claude-sonnet-4-5", ""},
+		{"gemini-2.5-pro", ""},
+		{"ai/qwen3", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, encodingForModel(tt.model))
+		})
+	}
+}
+
+func TestForModel(t *testing.T) {
+	t.Parallel()
+
+	counting := &countingProvider{fakeProvider: fakeProvider{model: "claude-sonnet-4-5"}}
+	assert.Equal(t, counting, ForModel(counting))
+	assert.Equal(t, Heuristic, Local(counting))
+
+	assert.Equal(t, Heuristic, ForModel(&fakeProvider{model: "gemini-2.5-pro"}))
+	assert.Equal(t, tiktokenCounter{encoding: "o200k_base"}, ForModel(&fakeProvider{model: "gpt-4o"}))
+}
+
+func TestHeuristic(t *testing.T) {
+	t.Parallel()
+
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "You are a helpful assistant."}, // 28 chars
+		{Role: chat.MessageRoleUser, Content: "Hello"},                          // 5 chars
+	}
+	n, err := Heuristic.CountTokens(t.Context(), messages, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(28/4+5+5/4+5), n)
+
+	toolList := []tools.Tool{{
+		Name:        "read_file",
+		Description: "Read a file",
+		Parameters:  map[string]any{"type": "object"},
+	}}
+	withTools, err := Heuristic.CountTokens(t.Context(), messages, toolList)
+	require.NoError(t, err)
+	// 9 + 11 chars of name and description, 17 of JSON parameters.
+	assert.Equal(t, n+int64((9+11+17)/4+5), withTools)
+}
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+
+	messages := []chat.Message{{Role: chat.MessageRoleUser, Content: "Hello"}}
+	estimated, err := Heuristic.CountTokens(t.Context(), messages, nil)
+	require.NoError(t, err)
+
+	counting := &countingProvider{count: 42}
+	assert.Equal(t, int64(42), Count(t.Context(), counting, messages, nil))
+
+	counting.err = errors.New("rate limited")
+	assert.Equal(t, estimated, Count(t.Context(), counting, messages, nil))
+}
+
+func TestTiktokenEncodingsAreEmbedded(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{tiktoken.MODEL_O200K_BASE, tiktoken.MODEL_CL100K_BASE} {
+		tk, err := tiktoken.GetEncoding(name)
+		require.NoError(t, err, "the %s encoding is loaded without network", name)
+		assert.NotEmpty(t, tk.Encode("Hello, world!", nil, nil))
+	}
+}
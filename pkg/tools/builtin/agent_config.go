@@ -0,0 +1,126 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const (
+	ToolNameReadAgentConfig = "read_agent_config"
+	ToolNameEditAgentConfig = "edit_agent_config"
+)
+
+// AgentConfigTool lets agents read and edit their own configuration file, to
+// refine their instructions based on the user's feedback. Every edit must be
+// approved by the user and is validated before being written.
+type AgentConfigTool struct {
+	path     string
+	validate func(context.Context, []byte) error
+	mu       sync.Mutex
+}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet      = (*AgentConfigTool)(nil)
+	_ tools.Instructable = (*AgentConfigTool)(nil)
+)
+
+type EditAgentConfigArgs struct {
+	OldText string `json:"old_text" jsonschema:"The exact text to replace. It must appear exactly once in the configuration"`
+	NewText string `json:"new_text" jsonschema:"The text to replace it with"`
+	Reason  string `json:"reason" jsonschema:"Why the change improves the agent, shown to the user who approves it"`
+}
+
+// NewAgentConfigTool creates a tool editing the agent configuration file at
+// path. validate checks that an edited configuration is still valid.
+func NewAgentConfigTool(path string, validate func(context.Context, []byte) error) *AgentConfigTool {
+	return &AgentConfigTool{
+		path:     path,
+		validate: validate,
+	}
+}
+
+func (t *AgentConfigTool) readConfig(_ context.Context, _ map[string]any) (*tools.ToolCallResult, error) {
+	buf, err := os.ReadFile(t.path)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to read the agent configuration: %v", err)), nil
+	}
+	return tools.ResultSuccess(fmt.Sprintf("Agent configuration %s:\n\n%s", t.path, buf)), nil
+}
+
+func (t *AgentConfigTool) editConfig(ctx context.Context, params EditAgentConfigArgs) (*tools.ToolCallResult, error) {
+	if params.OldText == "" {
+		return tools.ResultError("old_text is required"), nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buf, err := os.ReadFile(t.path)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to read the agent configuration: %v", err)), nil
+	}
+	content := string(buf)
+
+	switch strings.Count(content, params.OldText) {
+	case 0:
+		return tools.ResultError("old_text was not found in the agent configuration. Read it again with read_agent_config."), nil
+	case 1:
+	default:
+		return tools.ResultError("old_text appears several times in the agent configuration. Include more context to make it unique."), nil
+	}
+
+	edited := []byte(strings.Replace(content, params.OldText, params.NewText, 1))
+	if err := t.validate(ctx, edited); err != nil {
+		return tools.ResultError(fmt.Sprintf("The edited configuration is invalid, nothing was written: %v", err)), nil
+	}
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to write the agent configuration: %v", err)), nil
+	}
+	if err := os.WriteFile(t.path, edited, info.Mode().Perm()); err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to write the agent configuration: %v", err)), nil
+	}
+
+	return tools.ResultSuccess(fmt.Sprintf("Updated %s. The change applies to new sessions of the agent.", t.path)), nil
+}
+
+func (t *AgentConfigTool) Instructions() string {
+	return `## Agent Configuration
+
+You can read your own configuration with read_agent_config and propose changes with edit_agent_config, for instance to refine your instructions when the user gives feedback on how you work. Keep the changes small and explain them in the reason: the user approves every change, which applies to new sessions.`
+}
+
+func (t *AgentConfigTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:         ToolNameReadAgentConfig,
+			Category:     "agent_config",
+			Description:  "Read the YAML configuration file of the agent.",
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.readConfig),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Read Agent Configuration",
+			},
+		},
+		{
+			Name:         ToolNameEditAgentConfig,
+			Category:     "agent_config",
+			Description:  "Replace a piece of text of the agent's configuration file. The edited configuration is validated before being written.",
+			Parameters:   tools.MustSchemaFor[EditAgentConfigArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.editConfig),
+			Annotations: tools.ToolAnnotations{
+				Title: "Edit Agent Configuration",
+			},
+			AlwaysAsk: true,
+		},
+	}, nil
+}
@@ -0,0 +1,98 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAgentConfig = `agents:
+  root:
+    model: openai/gpt-4o
+    instruction: Be helpful.
+`
+
+func newTestAgentConfigTool(t *testing.T) (*AgentConfigTool, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testAgentConfig), 0o600))
+
+	return NewAgentConfigTool(path, func(_ context.Context, data []byte) error {
+		if !strings.Contains(string(data), "instruction:") {
+			return errors.New("missing instruction")
+		}
+		return nil
+	}), path
+}
+
+func TestAgentConfigTool_Read(t *testing.T) {
+	tool, path := newTestAgentConfigTool(t)
+
+	result, err := tool.readConfig(t.Context(), nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Output, path)
+	assert.Contains(t, result.Output, "instruction: Be helpful.")
+}
+
+func TestAgentConfigTool_Edit(t *testing.T) {
+	tool, path := newTestAgentConfigTool(t)
+
+	result, err := tool.editConfig(t.Context(), EditAgentConfigArgs{
+		OldText: "Be helpful.",
+		NewText: "Be helpful. Always run the tests before answering.",
+		Reason:  "The user asked to always run the tests",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Output)
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "instruction: Be helpful. Always run the tests before answering.")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestAgentConfigTool_EditErrors(t *testing.T) {
+	tool, path := newTestAgentConfigTool(t)
+
+	result, err := tool.editConfig(t.Context(), EditAgentConfigArgs{OldText: "Be concise."})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "not found")
+
+	result, err = tool.editConfig(t.Context(), EditAgentConfigArgs{OldText: "o"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "several times")
+
+	result, err = tool.editConfig(t.Context(), EditAgentConfigArgs{OldText: "instruction: Be helpful.", NewText: "description: Helpful"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "missing instruction")
+
+	// Nothing was written.
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, testAgentConfig, string(buf))
+}
+
+func TestAgentConfigTool_EditAlwaysAsks(t *testing.T) {
+	tool, _ := newTestAgentConfigTool(t)
+
+	allTools, err := tool.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, allTools, 2)
+	assert.True(t, allTools[0].Annotations.ReadOnlyHint)
+	assert.False(t, allTools[0].AlwaysAsk)
+	assert.True(t, allTools[1].AlwaysAsk)
+}
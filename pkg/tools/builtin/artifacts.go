@@ -0,0 +1,77 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const ToolNameRegisterArtifact = "register_artifact"
+
+// ArtifactsTool lets agents register the files they produced as artifacts of
+// the session.
+type ArtifactsTool struct {
+	workingDir string
+}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet      = (*ArtifactsTool)(nil)
+	_ tools.Instructable = (*ArtifactsTool)(nil)
+)
+
+type RegisterArtifactArgs struct {
+	Path        string `json:"path" jsonschema:"The path of the file to register, absolute or relative to the working directory"`
+	Description string `json:"description,omitempty" jsonschema:"A short description of what the file contains"`
+}
+
+func NewArtifactsTool(workingDir string) *ArtifactsTool {
+	return &ArtifactsTool{
+		workingDir: workingDir,
+	}
+}
+
+func (t *ArtifactsTool) callTool(_ context.Context, params RegisterArtifactArgs) (*tools.ToolCallResult, error) {
+	path := params.Path
+	if !filepath.IsAbs(path) && t.workingDir != "" {
+		path = filepath.Join(t.workingDir, path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Cannot register %s: %v", params.Path, err)), nil
+	}
+	if info.IsDir() {
+		return tools.ResultError(fmt.Sprintf("Cannot register %s: it is a directory", params.Path)), nil
+	}
+
+	result := tools.ResultSuccess(fmt.Sprintf("Registered %s as an artifact (%d bytes)", params.Path, info.Size()))
+	result.Artifacts = []tools.Artifact{{Path: path, Description: params.Description}}
+	return result, nil
+}
+
+func (t *ArtifactsTool) Instructions() string {
+	return `## Artifacts
+
+When you produce a file that is a deliverable of your task (a report, an image, a patch, ...), register it with the register_artifact tool so that the user can retrieve it. Don't register temporary or intermediate files.`
+}
+
+func (t *ArtifactsTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:         ToolNameRegisterArtifact,
+			Category:     "artifacts",
+			Description:  "Register a file you produced as an artifact of the session, so that the user can list and retrieve it.",
+			Parameters:   tools.MustSchemaFor[RegisterArtifactArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.callTool),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Register Artifact",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,33 @@
+package builtin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestArtifactsTool_Register(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "chart.png"), []byte("png"), 0o600))
+	tool := NewArtifactsTool(dir)
+
+	result, err := tool.callTool(t.Context(), RegisterArtifactArgs{Path: "chart.png", Description: "Sales chart"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, []tools.Artifact{{Path: filepath.Join(dir, "chart.png"), Description: "Sales chart"}}, result.Artifacts)
+
+	result, err = tool.callTool(t.Context(), RegisterArtifactArgs{Path: "missing.png"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Empty(t, result.Artifacts)
+
+	result, err = tool.callTool(t.Context(), RegisterArtifactArgs{Path: dir})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "is a directory")
+}
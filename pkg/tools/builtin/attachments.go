@@ -0,0 +1,42 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const ToolNameReadAttachment = "read_attachment"
+
+// AttachmentTool lets agents read, in chunks, the text files attached to the
+// messages of the user that are too large to be inlined. The runtime adds it
+// to the sessions with such files and handles the calls, since it knows
+// which files were attached.
+type AttachmentTool struct{}
+
+var _ tools.ToolSet = (*AttachmentTool)(nil)
+
+type ReadAttachmentArgs struct {
+	Path   string `json:"path" jsonschema:"The path of the attached file, as given in the attached_file tag."`
+	Offset int    `json:"offset,omitempty" jsonschema:"The line number to start reading from, starting at 1. Defaults to 1."`
+	Limit  int    `json:"limit,omitempty" jsonschema:"The maximum number of lines to read. Defaults to 500."`
+}
+
+func NewAttachmentTool() *AttachmentTool {
+	return &AttachmentTool{}
+}
+
+func (t *AttachmentTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:        ToolNameReadAttachment,
+			Category:    "attachments",
+			Description: "Read a chunk of lines of a text file attached to a message of the user that was too large to be inlined.",
+			Parameters:  tools.MustSchemaFor[ReadAttachmentArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Read Attachment",
+			},
+		},
+	}, nil
+}
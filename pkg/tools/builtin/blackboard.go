@@ -0,0 +1,91 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const (
+	ToolNamePostToBlackboard     = "post_to_blackboard"
+	ToolNameReadBlackboard       = "read_blackboard"
+	ToolNameRemoveFromBlackboard = "remove_from_blackboard"
+)
+
+// BlackboardTool provides the tools of the blackboard the agents of a team
+// share during a run. The tools are handled by the runtime, which keeps the
+// blackboard in the session the user started.
+type BlackboardTool struct{}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet      = (*BlackboardTool)(nil)
+	_ tools.Instructable = (*BlackboardTool)(nil)
+)
+
+// PostToBlackboardArgs are the arguments for the post_to_blackboard tool.
+type PostToBlackboardArgs struct {
+	Key   string `json:"key" jsonschema:"The key of the finding, e.g. 'api/auth-flow'. Posting to an existing key updates it."`
+	Value string `json:"value" jsonschema:"The finding: facts, decisions, file paths or results the other agents need"`
+}
+
+// ReadBlackboardArgs are the arguments for the read_blackboard tool.
+type ReadBlackboardArgs struct {
+	Prefix string `json:"prefix,omitempty" jsonschema:"Only return the entries whose key starts with this prefix. Returns all the entries when empty."`
+}
+
+// RemoveFromBlackboardArgs are the arguments for the remove_from_blackboard tool.
+type RemoveFromBlackboardArgs struct {
+	Key string `json:"key" jsonschema:"The key of the entry to remove"`
+}
+
+// NewBlackboardTool creates a new BlackboardTool.
+func NewBlackboardTool() *BlackboardTool {
+	return &BlackboardTool{}
+}
+
+func (t *BlackboardTool) Instructions() string {
+	return `## Blackboard
+
+The blackboard is shared by all the agents of the team during the run. Post
+your findings to it with post_to_blackboard, under short hierarchical keys
+(e.g. "api/auth-flow", "tests/failures"), and read what the other agents found
+with read_blackboard before starting a task. When you delegate a task, point
+the other agent to the keys to read instead of repeating their content.
+Posting to an existing key replaces its value: read it first to build on it.`
+}
+
+func (t *BlackboardTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:        ToolNamePostToBlackboard,
+			Category:    "blackboard",
+			Description: "Post a finding to the blackboard shared by all the agents of the team, or update the one with the same key.",
+			Parameters:  tools.MustSchemaFor[PostToBlackboardArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Post to Blackboard",
+			},
+		},
+		{
+			Name:        ToolNameReadBlackboard,
+			Category:    "blackboard",
+			Description: "Read the findings the agents of the team posted to the blackboard.",
+			Parameters:  tools.MustSchemaFor[ReadBlackboardArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Read Blackboard",
+			},
+		},
+		{
+			Name:        ToolNameRemoveFromBlackboard,
+			Category:    "blackboard",
+			Description: "Remove an entry that is no longer relevant from the blackboard.",
+			Parameters:  tools.MustSchemaFor[RemoveFromBlackboardArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Remove from Blackboard",
+			},
+		},
+	}, nil
+}
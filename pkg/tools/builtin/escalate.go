@@ -0,0 +1,220 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/desktopnotify"
+	"github.com/docker/docker-agent/pkg/httpclient"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const ToolNameEscalateToHuman = "escalate_to_human"
+
+// EscalationMetaKey is the key of the meta of the elicitations of the
+// escalate_to_human tool. Its value is the reason of the escalation.
+const EscalationMetaKey = "cagent/escalation"
+
+// escalationWebhookTimeout bounds the notification of the webhook, so that an
+// unreachable endpoint doesn't delay the escalation.
+const escalationWebhookTimeout = 10 * time.Second
+
+// EscalateTool lets an agent running unattended hand over to a human when it
+// can't go on by itself. The run pauses until a human answers, from the TUI
+// or the API.
+type EscalateTool struct {
+	elicitationHandler tools.ElicitationHandler
+	webhook            string
+	headers            map[string]string
+	desktop            bool
+	client             *http.Client
+}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet      = (*EscalateTool)(nil)
+	_ tools.Elicitable   = (*EscalateTool)(nil)
+	_ tools.Instructable = (*EscalateTool)(nil)
+)
+
+type EscalateArgs struct {
+	Reason   string `json:"reason" jsonschema:"Why you can't go on without a human: what is beyond your knowledge, permissions or confidence"`
+	Summary  string `json:"summary,omitempty" jsonschema:"What you did and tried so far, so that the human can pick up from there"`
+	Question string `json:"question,omitempty" jsonschema:"The decision or information you need from the human"`
+}
+
+type EscalateResponse struct {
+	Action   string `json:"action" jsonschema:"The human action: accept, decline, or cancel"`
+	Response string `json:"response,omitempty" jsonschema:"The answer or instructions of the human (only present when action is accept)"`
+}
+
+// escalationNotification is the body POSTed to the webhook.
+type escalationNotification struct {
+	Reason    string    `json:"reason"`
+	Summary   string    `json:"summary,omitempty"`
+	Question  string    `json:"question,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewEscalateTool creates the tool. webhook, if set, is notified of every
+// escalation with an HTTP POST carrying the given headers, and desktop shows a
+// desktop notification.
+func NewEscalateTool(webhook string, headers map[string]string, desktop bool) *EscalateTool {
+	return &EscalateTool{
+		webhook: webhook,
+		headers: headers,
+		desktop: desktop,
+		client:  httpclient.NewHTTPClient(),
+	}
+}
+
+func (t *EscalateTool) SetElicitationHandler(handler tools.ElicitationHandler) {
+	t.elicitationHandler = handler
+}
+
+func (t *EscalateTool) escalate(ctx context.Context, params EscalateArgs) (*tools.ToolCallResult, error) {
+	if params.Reason == "" {
+		return tools.ResultError("reason is required"), nil
+	}
+	if t.elicitationHandler == nil {
+		return tools.ResultError("escalate_to_human tool is not available in this context (no elicitation handler configured)"), nil
+	}
+
+	t.notify(ctx, params)
+
+	message := "The agent needs a human: " + params.Reason
+	if params.Summary != "" {
+		message += "\n\n" + params.Summary
+	}
+	if params.Question != "" {
+		message += "\n\n" + params.Question
+	}
+
+	req := &mcp.ElicitParams{
+		Message: message,
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"response": map[string]any{
+					"type":        "string",
+					"title":       "Response",
+					"description": "Your answer or instructions for the agent",
+				},
+			},
+			"required": []string{"response"},
+		},
+		Meta: mcp.Meta{
+			"cagent/title":    "Escalation",
+			EscalationMetaKey: params.Reason,
+		},
+	}
+
+	result, err := t.elicitationHandler(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("elicitation request failed: %w", err)
+	}
+
+	response := EscalateResponse{Action: string(result.Action)}
+	if s, ok := result.Content["response"].(string); ok {
+		response.Response = s
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	if result.Action != tools.ElicitationActionAccept {
+		return tools.ResultError(string(responseJSON)), nil
+	}
+
+	return tools.ResultSuccess(string(responseJSON)), nil
+}
+
+// notify sends the escalation to the configured channels. Failures are
+// logged: the human can still answer from the TUI or the API.
+func (t *EscalateTool) notify(ctx context.Context, params EscalateArgs) {
+	if t.desktop {
+		desktopnotify.Send("docker agent needs a human", params.Reason)
+	}
+	if t.webhook == "" {
+		return
+	}
+	if err := t.postWebhook(ctx, params); err != nil {
+		slog.Warn("Failed to notify the escalation webhook", "error", err)
+	}
+}
+
+func (t *EscalateTool) postWebhook(ctx context.Context, params EscalateArgs) error {
+	body, err := json.Marshal(escalationNotification{
+		Reason:    params.Reason,
+		Summary:   params.Summary,
+		Question:  params.Question,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, escalationWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (t *EscalateTool) Instructions() string {
+	return `## Escalate to Human Tool
+
+When you reach the limit of what you can do safely or competently on your own,
+call escalate_to_human instead of guessing: missing permissions or credentials,
+ambiguous requirements with costly consequences, repeated failures you can't
+explain, or decisions that a human must own.
+
+Give the reason, a summary of what you did and tried, and the question you need
+answered. The run pauses until a human responds. Follow their response; when
+it's declined, stop and report what's left to do.`
+}
+
+func (t *EscalateTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:         ToolNameEscalateToHuman,
+			Category:     "escalate_to_human",
+			Description:  "Hand over to a human when you can't go on by yourself. Notifies the humans in charge, pauses the run and returns their response. Use it when the task is beyond your competence, permissions or confidence, not for routine questions.",
+			Parameters:   tools.MustSchemaFor[EscalateArgs](),
+			OutputSchema: tools.MustSchemaFor[EscalateResponse](),
+			Handler:      tools.NewHandler(t.escalate),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Escalate to Human",
+			},
+		},
+	}, nil
+}
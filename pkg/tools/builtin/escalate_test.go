@@ -0,0 +1,81 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestEscalateTool_NotifiesAndWaitsForHuman(t *testing.T) {
+	var notification escalationNotification
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	tool := NewEscalateTool(webhook.URL, map[string]string{"Authorization": "Bearer secret"}, false)
+	tool.SetElicitationHandler(func(_ context.Context, req *mcp.ElicitParams) (tools.ElicitationResult, error) {
+		assert.Equal(t, "The agent needs a human: the migration deletes data\n\nShould I run it?", req.Message)
+		assert.Equal(t, "the migration deletes data", req.Meta[EscalationMetaKey])
+		return tools.ElicitationResult{
+			Action:  tools.ElicitationActionAccept,
+			Content: map[string]any{"response": "Back up the table first"},
+		}, nil
+	})
+
+	result, err := tool.escalate(t.Context(), EscalateArgs{Reason: "the migration deletes data", Question: "Should I run it?"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	var response EscalateResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &response))
+	assert.Equal(t, EscalateResponse{Action: "accept", Response: "Back up the table first"}, response)
+	assert.Equal(t, "the migration deletes data", notification.Reason)
+	assert.Equal(t, "Should I run it?", notification.Question)
+}
+
+func TestEscalateTool_Declined(t *testing.T) {
+	tool := NewEscalateTool("", nil, false)
+	tool.SetElicitationHandler(func(context.Context, *mcp.ElicitParams) (tools.ElicitationResult, error) {
+		return tools.ElicitationResult{Action: tools.ElicitationActionDecline}, nil
+	})
+
+	result, err := tool.escalate(t.Context(), EscalateArgs{Reason: "stuck"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.JSONEq(t, `{"action":"decline"}`, result.Output)
+}
+
+func TestEscalateTool_WebhookFailureDoesNotBlock(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer webhook.Close()
+
+	tool := NewEscalateTool(webhook.URL, nil, false)
+	tool.SetElicitationHandler(func(context.Context, *mcp.ElicitParams) (tools.ElicitationResult, error) {
+		return tools.ElicitationResult{Action: tools.ElicitationActionAccept, Content: map[string]any{"response": "ok"}}, nil
+	})
+
+	result, err := tool.escalate(t.Context(), EscalateArgs{Reason: "stuck"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+}
+
+func TestEscalateTool_NoHandler(t *testing.T) {
+	tool := NewEscalateTool("", nil, false)
+
+	result, err := tool.escalate(t.Context(), EscalateArgs{Reason: "stuck"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
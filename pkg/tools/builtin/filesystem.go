@@ -17,6 +17,7 @@ import (
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/fsx"
 	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/workspace"
 )
 
 const (
@@ -39,6 +40,7 @@ type PostEditConfig struct {
 
 type FilesystemTool struct {
 	workingDir       string
+	workspace        workspace.Workspace
 	postEditCommands []PostEditConfig
 	ignoreVCS        bool
 	repoMatcher      *fsx.VCSMatcher
@@ -65,9 +67,19 @@ func WithIgnoreVCS(ignoreVCS bool) FileSystemOpt {
 	}
 }
 
+// WithWorkspace sets the roots relative paths can be resolved from. Its
+// primary root replaces the working directory.
+func WithWorkspace(ws workspace.Workspace) FileSystemOpt {
+	return func(t *FilesystemTool) {
+		t.workspace = ws
+		t.workingDir = ws.Primary()
+	}
+}
+
 func NewFilesystemTool(workingDir string, opts ...FileSystemOpt) *FilesystemTool {
 	t := &FilesystemTool{
 		workingDir: workingDir,
+		workspace:  workspace.New(workingDir),
 	}
 
 	for _, opt := range opts {
@@ -326,14 +338,11 @@ func (t *FilesystemTool) executePostEditCommands(ctx context.Context, filePath s
 }
 
 // resolvePath resolves a path relative to the working directory.
-// Relative paths (including ".") are joined with the working directory.
+// Relative paths (including ".") are joined with the working directory,
+// or with another root of the workspace when prefixed with "<root>:".
 // Absolute paths and paths starting with ".." are used as-is.
 func (t *FilesystemTool) resolvePath(path string) string {
-	if filepath.IsAbs(path) {
-		return filepath.Clean(path)
-	}
-
-	return filepath.Clean(filepath.Join(t.workingDir, path))
+	return t.workspace.Resolve(path)
 }
 
 // initGitignoreMatcher initializes the gitignore matcher for the working directory.
@@ -379,6 +388,15 @@ func (t *FilesystemTool) shouldIgnorePath(path string) bool {
 		return true
 	}
 
+	// Paths in other roots of the workspace follow the rules of their own repository.
+	if root := t.workspace.RootOf(path); root != "" && root != t.workingDir {
+		if absRoot, err := filepath.Abs(root); err == nil {
+			if matcher, _ := fsx.NewVCSMatcher(absRoot); matcher != nil && matcher.ShouldIgnore(path) {
+				return true
+			}
+		}
+	}
+
 	return false
 }
 
@@ -443,13 +461,20 @@ func (t *FilesystemTool) handleEditFile(ctx context.Context, args EditFileArgs)
 	originalContent := string(content)
 	modifiedContent := originalContent
 
+	// Models write \n line endings, even for files with Windows line endings.
+	crlf := strings.Contains(originalContent, "\r\n")
+
 	var changes []string
 	for i, edit := range args.Edits {
-		if !strings.Contains(modifiedContent, edit.OldText) {
+		oldText, newText := edit.OldText, edit.NewText
+		if crlf && !strings.Contains(modifiedContent, oldText) {
+			oldText, newText = toCRLF(oldText), toCRLF(newText)
+		}
+		if !strings.Contains(modifiedContent, oldText) {
 			return tools.ResultError(fmt.Sprintf("Edit %d failed: old text not found", i+1)), nil
 		}
-		modifiedContent = strings.Replace(modifiedContent, edit.OldText, edit.NewText, 1)
-		changes = append(changes, fmt.Sprintf("Edit %d: Replaced %d characters", i+1, len(edit.OldText)))
+		modifiedContent = strings.Replace(modifiedContent, oldText, newText, 1)
+		changes = append(changes, fmt.Sprintf("Edit %d: Replaced %d characters", i+1, len(oldText)))
 	}
 
 	if err := os.WriteFile(resolvedPath, []byte(modifiedContent), 0o644); err != nil {
@@ -467,6 +492,11 @@ func (t *FilesystemTool) handleEditFile(ctx context.Context, args EditFileArgs)
 	return tools.ResultSuccess("File edited successfully. Changes:\n" + strings.Join(changes, "\n")), nil
 }
 
+// toCRLF converts the line endings of text to \r\n.
+func toCRLF(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+}
+
 func (t *FilesystemTool) handleListDirectory(_ context.Context, args ListDirectoryArgs) (*tools.ToolCallResult, error) {
 	resolvedPath := t.resolvePath(args.Path)
 
@@ -13,6 +13,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/workspace"
 )
 
 // initGitRepo initializes a git repository in the given directory
@@ -67,6 +69,20 @@ func TestFilesystemTool_ResolvePath(t *testing.T) {
 	assert.Equal(t, "/etc/hosts", resolvedPath)
 }
 
+func TestFilesystemTool_ResolvePathWithWorkspace(t *testing.T) {
+	t.Parallel()
+	frontend := filepath.Join(t.TempDir(), "frontend")
+	backend := filepath.Join(t.TempDir(), "backend")
+	tool := NewFilesystemTool(frontend, WithWorkspace(workspace.New(frontend, backend)))
+
+	assert.Equal(t, filepath.Join(frontend, "src", "app.ts"), tool.resolvePath("src/app.ts"))
+	assert.Equal(t, filepath.Join(backend, "cmd", "main.go"), tool.resolvePath("backend:cmd/main.go"))
+	assert.Equal(t, frontend, tool.resolvePath("frontend:"))
+
+	// Unknown roots aren't selectors.
+	assert.Equal(t, filepath.Join(frontend, "other:file"), tool.resolvePath("other:file"))
+}
+
 func TestFilesystemTool_WriteFile(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -260,6 +276,27 @@ func TestFilesystemTool_EditFile(t *testing.T) {
 	assert.Contains(t, result.Output, "old text not found")
 }
 
+func TestFilesystemTool_EditFileCRLF(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tool := NewFilesystemTool(tmpDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("first\r\nsecond\r\nthird\r\n"), 0o644))
+
+	result, err := tool.handleEditFile(t.Context(), EditFileArgs{
+		Path: "test.txt",
+		Edits: []Edit{
+			{OldText: "first\nsecond", NewText: "1\n2"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "File edited successfully")
+
+	editedContent, err := os.ReadFile(filepath.Join(tmpDir, "test.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "1\r\n2\r\nthird\r\n", string(editedContent))
+}
+
 func TestFilesystemTool_SearchFilesContent(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -0,0 +1,30 @@
+package builtin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemTool_WindowsPathSeparators(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tool := NewFilesystemTool(tmpDir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "src", "app"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "app", "main.go"), []byte("package main\r\n"), 0o644))
+
+	assert.Equal(t, filepath.Join(tmpDir, "src", "app", "main.go"), tool.resolvePath("src/app/main.go"))
+	assert.Equal(t, filepath.Join(tmpDir, "src", "app", "main.go"), tool.resolvePath(`src\app\main.go`))
+	assert.Equal(t, filepath.Join(tmpDir, "src", "app", "main.go"), tool.resolvePath(filepath.ToSlash(filepath.Join(tmpDir, "src", "app", "main.go"))))
+
+	result, err := tool.handleEditFile(t.Context(), EditFileArgs{
+		Path:  "src/app/main.go",
+		Edits: []Edit{{OldText: "package main\n", NewText: "package app\n"}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "File edited successfully")
+}
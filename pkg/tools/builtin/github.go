@@ -0,0 +1,537 @@
+package builtin
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/useragent"
+)
+
+const (
+	ToolNameGitHubCreatePullRequest = "github_create_pull_request"
+	ToolNameGitHubListIssues        = "github_list_issues"
+	ToolNameGitHubComment           = "github_comment"
+	ToolNameGitHubAddLabels         = "github_add_labels"
+	ToolNameGitHubReview            = "github_review_pull_request"
+	ToolNameGitHubCIStatus          = "github_ci_status"
+
+	// DefaultGitHubAPIURL is the URL of the REST API of github.com.
+	DefaultGitHubAPIURL = "https://api.github.com"
+)
+
+// GitHubTool gives agents structured access to the GitHub REST API: pull
+// requests, issues, reviews and CI status.
+type GitHubTool struct {
+	handler *githubHandler
+}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet      = (*GitHubTool)(nil)
+	_ tools.Instructable = (*GitHubTool)(nil)
+)
+
+type githubHandler struct {
+	token      string
+	apiURL     string
+	workingDir string
+	client     *http.Client
+}
+
+// NewGitHubTool creates a GitHub toolset authenticated with token. apiURL is
+// the URL of the REST API, DefaultGitHubAPIURL for github.com. Repositories
+// default to the GitHub repository of the origin remote of workingDir.
+func NewGitHubTool(token, apiURL, workingDir string) *GitHubTool {
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+	return &GitHubTool{
+		handler: &githubHandler{
+			token:      token,
+			apiURL:     strings.TrimSuffix(apiURL, "/"),
+			workingDir: workingDir,
+			client:     &http.Client{Timeout: 30 * time.Second},
+		},
+	}
+}
+
+type GitHubRepoArgs struct {
+	Repo string `json:"repo,omitempty" jsonschema:"The repository, as owner/name (default: the repository of the working directory)"`
+}
+
+type GitHubCreatePullRequestArgs struct {
+	GitHubRepoArgs
+	Title string `json:"title" jsonschema:"The title of the pull request"`
+	Head  string `json:"head" jsonschema:"The branch with the changes (owner:branch for branches of forks)"`
+	Base  string `json:"base" jsonschema:"The branch the changes are pulled into"`
+	Body  string `json:"body,omitempty" jsonschema:"The description of the pull request, in markdown"`
+	Draft bool   `json:"draft,omitempty" jsonschema:"Whether to create a draft pull request"`
+}
+
+type GitHubListIssuesArgs struct {
+	GitHubRepoArgs
+	State  string   `json:"state,omitempty" jsonschema:"open, closed or all (default: open)"`
+	Labels []string `json:"labels,omitempty" jsonschema:"Only list the issues with all these labels"`
+	Limit  int      `json:"limit,omitempty" jsonschema:"Maximum number of issues (default: 30, max: 100)"`
+}
+
+type GitHubCommentArgs struct {
+	GitHubRepoArgs
+	Number int    `json:"number" jsonschema:"The number of the issue or pull request"`
+	Body   string `json:"body" jsonschema:"The comment, in markdown"`
+}
+
+type GitHubAddLabelsArgs struct {
+	GitHubRepoArgs
+	Number int      `json:"number" jsonschema:"The number of the issue or pull request"`
+	Labels []string `json:"labels" jsonschema:"The labels to add"`
+}
+
+type GitHubReviewArgs struct {
+	GitHubRepoArgs
+	Number int    `json:"number" jsonschema:"The number of the pull request"`
+	Event  string `json:"event" jsonschema:"APPROVE, REQUEST_CHANGES or COMMENT"`
+	Body   string `json:"body,omitempty" jsonschema:"The review comment, in markdown (required for REQUEST_CHANGES and COMMENT)"`
+}
+
+type GitHubCIStatusArgs struct {
+	GitHubRepoArgs
+	Ref string `json:"ref" jsonschema:"A branch, tag or commit SHA"`
+}
+
+// GitHubIssue is an issue, or a pull request, as listed by github_list_issues.
+type GitHubIssue struct {
+	Number      int      `json:"number"`
+	Title       string   `json:"title"`
+	State       string   `json:"state"`
+	Author      string   `json:"author"`
+	Labels      []string `json:"labels,omitempty"`
+	URL         string   `json:"url"`
+	PullRequest bool     `json:"pull_request,omitempty"`
+}
+
+// GitHubCheck is the status of a check run or a commit status.
+type GitHubCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// GitHubCIStatus is the CI status of a ref.
+type GitHubCIStatus struct {
+	Ref    string        `json:"ref"`
+	State  string        `json:"state"`
+	Checks []GitHubCheck `json:"checks"`
+}
+
+func (t *GitHubTool) Instructions() string {
+	return `## GitHub Tools
+
+- Repositories default to the GitHub repository of the working directory; set repo (owner/name) for others
+- Pull requests are issues: github_comment and github_add_labels work on both
+- Use github_ci_status to check the CI of a branch or commit before merging or after pushing`
+}
+
+func (t *GitHubTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:         ToolNameGitHubCreatePullRequest,
+			Category:     "github",
+			Description:  "Create a pull request. Returns the number and URL of the pull request.",
+			Parameters:   tools.MustSchemaFor[GitHubCreatePullRequestArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handler.createPullRequest),
+			Annotations: tools.ToolAnnotations{
+				Title: "Create Pull Request",
+			},
+		},
+		{
+			Name:         ToolNameGitHubListIssues,
+			Category:     "github",
+			Description:  "List the issues and pull requests of a repository, most recently updated first.",
+			Parameters:   tools.MustSchemaFor[GitHubListIssuesArgs](),
+			OutputSchema: tools.MustSchemaFor[[]GitHubIssue](),
+			Handler:      tools.NewHandler(t.handler.listIssues),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "List Issues",
+			},
+		},
+		{
+			Name:         ToolNameGitHubComment,
+			Category:     "github",
+			Description:  "Comment on an issue or a pull request.",
+			Parameters:   tools.MustSchemaFor[GitHubCommentArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handler.comment),
+			Annotations: tools.ToolAnnotations{
+				Title: "Comment",
+			},
+		},
+		{
+			Name:         ToolNameGitHubAddLabels,
+			Category:     "github",
+			Description:  "Add labels to an issue or a pull request.",
+			Parameters:   tools.MustSchemaFor[GitHubAddLabelsArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handler.addLabels),
+			Annotations: tools.ToolAnnotations{
+				Title: "Add Labels",
+			},
+		},
+		{
+			Name:         ToolNameGitHubReview,
+			Category:     "github",
+			Description:  "Review a pull request: approve it, request changes or comment.",
+			Parameters:   tools.MustSchemaFor[GitHubReviewArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handler.review),
+			Annotations: tools.ToolAnnotations{
+				Title: "Review Pull Request",
+			},
+		},
+		{
+			Name:         ToolNameGitHubCIStatus,
+			Category:     "github",
+			Description:  "Get the CI status of a branch, tag or commit: the overall state and the state of each check.",
+			Parameters:   tools.MustSchemaFor[GitHubCIStatusArgs](),
+			OutputSchema: tools.MustSchemaFor[GitHubCIStatus](),
+			Handler:      tools.NewHandler(t.handler.ciStatus),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "CI Status",
+			},
+		},
+	}, nil
+}
+
+func (h *githubHandler) createPullRequest(ctx context.Context, args GitHubCreatePullRequestArgs) (*tools.ToolCallResult, error) {
+	repo, err := h.repo(args.Repo)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	var pr struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err = h.do(ctx, http.MethodPost, "/repos/"+repo+"/pulls", map[string]any{
+		"title": args.Title,
+		"head":  args.Head,
+		"base":  args.Base,
+		"body":  args.Body,
+		"draft": args.Draft,
+	}, &pr)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	return tools.ResultSuccess(fmt.Sprintf("Created pull request #%d: %s", pr.Number, pr.HTMLURL)), nil
+}
+
+func (h *githubHandler) listIssues(ctx context.Context, args GitHubListIssuesArgs) (*tools.ToolCallResult, error) {
+	repo, err := h.repo(args.Repo)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 30
+	}
+	query := url.Values{
+		"state":    {cmp.Or(args.State, "open")},
+		"sort":     {"updated"},
+		"per_page": {fmt.Sprint(min(limit, 100))},
+	}
+	if len(args.Labels) > 0 {
+		query.Set("labels", strings.Join(args.Labels, ","))
+	}
+
+	var issues []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		HTMLURL     string          `json:"html_url"`
+		PullRequest json.RawMessage `json:"pull_request"`
+	}
+	if err := h.do(ctx, http.MethodGet, "/repos/"+repo+"/issues?"+query.Encode(), nil, &issues); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	result := make([]GitHubIssue, 0, len(issues))
+	for _, issue := range issues {
+		item := GitHubIssue{
+			Number:      issue.Number,
+			Title:       issue.Title,
+			State:       issue.State,
+			Author:      issue.User.Login,
+			URL:         issue.HTMLURL,
+			PullRequest: len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null",
+		}
+		for _, label := range issue.Labels {
+			item.Labels = append(item.Labels, label.Name)
+		}
+		result = append(result, item)
+	}
+	return tools.ResultJSON(result), nil
+}
+
+func (h *githubHandler) comment(ctx context.Context, args GitHubCommentArgs) (*tools.ToolCallResult, error) {
+	repo, err := h.repo(args.Repo)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	var comment struct {
+		HTMLURL string `json:"html_url"`
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, args.Number)
+	if err := h.do(ctx, http.MethodPost, path, map[string]any{"body": args.Body}, &comment); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	return tools.ResultSuccess("Commented: " + comment.HTMLURL), nil
+}
+
+func (h *githubHandler) addLabels(ctx context.Context, args GitHubAddLabelsArgs) (*tools.ToolCallResult, error) {
+	if len(args.Labels) == 0 {
+		return tools.ResultError("at least one label is required"), nil
+	}
+	repo, err := h.repo(args.Repo)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/labels", repo, args.Number)
+	if err := h.do(ctx, http.MethodPost, path, map[string]any{"labels": args.Labels}, nil); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	return tools.ResultSuccess(fmt.Sprintf("Added labels %s to #%d", strings.Join(args.Labels, ", "), args.Number)), nil
+}
+
+func (h *githubHandler) review(ctx context.Context, args GitHubReviewArgs) (*tools.ToolCallResult, error) {
+	event := strings.ToUpper(args.Event)
+	switch event {
+	case "APPROVE":
+	case "REQUEST_CHANGES", "COMMENT":
+		if args.Body == "" {
+			return tools.ResultError("body is required to " + strings.ToLower(strings.ReplaceAll(event, "_", " "))), nil
+		}
+	default:
+		return tools.ResultError(fmt.Sprintf("invalid event %q, must be APPROVE, REQUEST_CHANGES or COMMENT", args.Event)), nil
+	}
+	repo, err := h.repo(args.Repo)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	var review struct {
+		HTMLURL string `json:"html_url"`
+	}
+	path := fmt.Sprintf("/repos/%s/pulls/%d/reviews", repo, args.Number)
+	if err := h.do(ctx, http.MethodPost, path, map[string]any{"event": event, "body": args.Body}, &review); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	return tools.ResultSuccess(fmt.Sprintf("Reviewed #%d (%s): %s", args.Number, event, review.HTMLURL)), nil
+}
+
+func (h *githubHandler) ciStatus(ctx context.Context, args GitHubCIStatusArgs) (*tools.ToolCallResult, error) {
+	if args.Ref == "" {
+		return tools.ResultError("ref is required"), nil
+	}
+	repo, err := h.repo(args.Repo)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+	ref := url.PathEscape(args.Ref)
+
+	// Commit statuses, set by external CI services.
+	var combined struct {
+		State    string `json:"state"`
+		Statuses []struct {
+			Context   string `json:"context"`
+			State     string `json:"state"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := h.do(ctx, http.MethodGet, "/repos/"+repo+"/commits/"+ref+"/status", nil, &combined); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	// Check runs, set by GitHub Actions and GitHub Apps.
+	var checkRuns struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := h.do(ctx, http.MethodGet, "/repos/"+repo+"/commits/"+ref+"/check-runs?per_page=100", nil, &checkRuns); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
+	status := GitHubCIStatus{Ref: args.Ref, Checks: []GitHubCheck{}}
+	for _, s := range combined.Statuses {
+		status.Checks = append(status.Checks, GitHubCheck{Name: s.Context, Status: s.State, URL: s.TargetURL})
+	}
+	for _, run := range checkRuns.CheckRuns {
+		status.Checks = append(status.Checks, GitHubCheck{Name: run.Name, Status: run.Status, Conclusion: run.Conclusion, URL: run.HTMLURL})
+	}
+	status.State = overallCIState(status.Checks)
+
+	return tools.ResultJSON(status), nil
+}
+
+// overallCIState summarizes the checks of a ref: failure if any check failed,
+// pending if any check isn't done, success otherwise.
+func overallCIState(checks []GitHubCheck) string {
+	if len(checks) == 0 {
+		return "no checks"
+	}
+	state := "success"
+	for _, check := range checks {
+		switch {
+		case check.Status == "failure" || check.Status == "error",
+			check.Conclusion == "failure" || check.Conclusion == "timed_out" || check.Conclusion == "cancelled" || check.Conclusion == "action_required":
+			return "failure"
+		case check.Status == "pending" || check.Status == "queued" || check.Status == "in_progress":
+			state = "pending"
+		}
+	}
+	return state
+}
+
+// repo returns the repository to use: repo if set, otherwise the GitHub
+// repository of the origin remote of the working directory.
+func (h *githubHandler) repo(repo string) (string, error) {
+	if repo != "" {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return "", fmt.Errorf("invalid repo %q, must be owner/name", repo)
+		}
+		return repo, nil
+	}
+
+	r, err := git.PlainOpenWithOptions(cmp.Or(h.workingDir, "."), &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", errors.New("repo is required: the working directory isn't a git repository")
+	}
+	remote, err := r.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return "", errors.New("repo is required: the working directory has no origin remote")
+	}
+	repo, ok := parseGitHubRemote(remote.Config().URLs[0])
+	if !ok {
+		return "", fmt.Errorf("repo is required: the origin remote %q isn't a GitHub repository", remote.Config().URLs[0])
+	}
+	return repo, nil
+}
+
+// parseGitHubRemote returns the owner/name of the repository of a remote URL,
+// e.g. https://github.com/owner/name.git or git@github.com:owner/name.git.
+func parseGitHubRemote(remoteURL string) (string, bool) {
+	var path string
+	if rest, ok := strings.CutPrefix(remoteURL, "git@"); ok {
+		_, path, ok = strings.Cut(rest, ":")
+		if !ok {
+			return "", false
+		}
+	} else {
+		u, err := url.Parse(remoteURL)
+		if err != nil || u.Host == "" {
+			return "", false
+		}
+		path = u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	owner, name, ok := strings.Cut(path, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return owner + "/" + name, true
+}
+
+// do sends a request to the GitHub API and decodes the response into out.
+func (h *githubHandler) do(ctx context.Context, method, path string, body, out any) error {
+	if h.token == "" {
+		return errors.New("GITHUB_TOKEN is not set")
+	}
+
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, h.apiURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+h.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", useragent.Header)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			message = apiErr.Message
+			for _, e := range apiErr.Errors {
+				if e.Message != "" {
+					message += ": " + e.Message
+				}
+			}
+		}
+		return fmt.Errorf("GitHub API error (HTTP %d): %s", resp.StatusCode, message)
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,219 @@
+package builtin
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubTool_Tools(t *testing.T) {
+	tool := NewGitHubTool("token", "", t.TempDir())
+
+	allTools, err := tool.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, allTools, 6)
+	for _, tool := range allTools {
+		assert.NotNil(t, tool.Handler)
+		assert.Equal(t, "github", tool.Category)
+	}
+
+	schema, err := json.Marshal(allTools[0].Parameters)
+	require.NoError(t, err)
+	assert.Contains(t, string(schema), `"repo"`)
+	assert.Contains(t, string(schema), `"head"`)
+}
+
+func TestGitHubTool_CreatePullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/repos/docker/cagent/pulls", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Fix bug", body["title"])
+		assert.Equal(t, "fix", body["head"])
+		assert.Equal(t, "main", body["base"])
+		assert.Equal(t, true, body["draft"])
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"number": 42, "html_url": "https://github.com/docker/cagent/pull/42"}`)
+	}))
+	defer server.Close()
+
+	tool := NewGitHubTool("secret", server.URL, t.TempDir())
+	result, err := tool.handler.createPullRequest(t.Context(), GitHubCreatePullRequestArgs{
+		GitHubRepoArgs: GitHubRepoArgs{Repo: "docker/cagent"},
+		Title:          "Fix bug",
+		Head:           "fix",
+		Base:           "main",
+		Draft:          true,
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Created pull request #42: https://github.com/docker/cagent/pull/42", result.Output)
+}
+
+func TestGitHubTool_ListIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/docker/cagent/issues", r.URL.Path)
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		assert.Equal(t, "bug,help wanted", r.URL.Query().Get("labels"))
+		assert.Equal(t, "5", r.URL.Query().Get("per_page"))
+
+		_, _ = io.WriteString(w, `[
+			{"number": 1, "title": "Crash", "state": "open", "user": {"login": "alice"}, "labels": [{"name": "bug"}], "html_url": "https://github.com/docker/cagent/issues/1"},
+			{"number": 2, "title": "Fix crash", "state": "open", "user": {"login": "bob"}, "labels": [], "html_url": "https://github.com/docker/cagent/pull/2", "pull_request": {}}
+		]`)
+	}))
+	defer server.Close()
+
+	tool := NewGitHubTool("secret", server.URL, t.TempDir())
+	result, err := tool.handler.listIssues(t.Context(), GitHubListIssuesArgs{
+		GitHubRepoArgs: GitHubRepoArgs{Repo: "docker/cagent"},
+		Labels:         []string{"bug", "help wanted"},
+		Limit:          5,
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Output)
+
+	var issues []GitHubIssue
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &issues))
+	assert.Equal(t, []GitHubIssue{
+		{Number: 1, Title: "Crash", State: "open", Author: "alice", Labels: []string{"bug"}, URL: "https://github.com/docker/cagent/issues/1"},
+		{Number: 2, Title: "Fix crash", State: "open", Author: "bob", URL: "https://github.com/docker/cagent/pull/2", PullRequest: true},
+	}, issues)
+}
+
+func TestGitHubTool_Review(t *testing.T) {
+	tool := NewGitHubTool("secret", "http://unused", t.TempDir())
+
+	result, err := tool.handler.review(t.Context(), GitHubReviewArgs{
+		GitHubRepoArgs: GitHubRepoArgs{Repo: "docker/cagent"},
+		Number:         1,
+		Event:          "merge",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "invalid event")
+
+	result, err = tool.handler.review(t.Context(), GitHubReviewArgs{
+		GitHubRepoArgs: GitHubRepoArgs{Repo: "docker/cagent"},
+		Number:         1,
+		Event:          "request_changes",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "body is required")
+}
+
+func TestGitHubTool_CIStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/docker/cagent/commits/main/status":
+			_, _ = io.WriteString(w, `{"state": "success", "statuses": [{"context": "ci/lint", "state": "success"}]}`)
+		case "/repos/docker/cagent/commits/main/check-runs":
+			_, _ = io.WriteString(w, `{"check_runs": [
+				{"name": "test", "status": "completed", "conclusion": "failure"},
+				{"name": "build", "status": "in_progress"}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tool := NewGitHubTool("secret", server.URL, t.TempDir())
+	result, err := tool.handler.ciStatus(t.Context(), GitHubCIStatusArgs{
+		GitHubRepoArgs: GitHubRepoArgs{Repo: "docker/cagent"},
+		Ref:            "main",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Output)
+
+	var status GitHubCIStatus
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &status))
+	assert.Equal(t, "failure", status.State)
+	assert.Len(t, status.Checks, 3)
+}
+
+func TestGitHubTool_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = io.WriteString(w, `{"message": "Validation Failed", "errors": [{"message": "A pull request already exists"}]}`)
+	}))
+	defer server.Close()
+
+	tool := NewGitHubTool("secret", server.URL, t.TempDir())
+	result, err := tool.handler.createPullRequest(t.Context(), GitHubCreatePullRequestArgs{
+		GitHubRepoArgs: GitHubRepoArgs{Repo: "docker/cagent"},
+		Title:          "Fix bug",
+		Head:           "fix",
+		Base:           "main",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "GitHub API error (HTTP 422): Validation Failed: A pull request already exists", result.Output)
+}
+
+func TestGitHubTool_NoToken(t *testing.T) {
+	tool := NewGitHubTool("", "http://unused", t.TempDir())
+
+	result, err := tool.handler.comment(t.Context(), GitHubCommentArgs{
+		GitHubRepoArgs: GitHubRepoArgs{Repo: "docker/cagent"},
+		Number:         1,
+		Body:           "LGTM",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "GITHUB_TOKEN is not set")
+}
+
+func TestGitHubTool_RepoFromOrigin(t *testing.T) {
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	_, err = r.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{"git@github.com:docker/cagent.git"}})
+	require.NoError(t, err)
+
+	tool := NewGitHubTool("secret", "http://unused", dir)
+	repo, err := tool.handler.repo("")
+	require.NoError(t, err)
+	assert.Equal(t, "docker/cagent", repo)
+
+	_, err = NewGitHubTool("secret", "http://unused", t.TempDir()).handler.repo("")
+	require.Error(t, err)
+
+	_, err = tool.handler.repo("docker")
+	require.Error(t, err)
+}
+
+func TestParseGitHubRemote(t *testing.T) {
+	tests := []struct {
+		url  string
+		repo string
+		ok   bool
+	}{
+		{url: "https://github.com/docker/cagent.git", repo: "docker/cagent", ok: true},
+		{url: "https://github.com/docker/cagent", repo: "docker/cagent", ok: true},
+		{url: "git@github.com:docker/cagent.git", repo: "docker/cagent", ok: true},
+		{url: "ssh://git@github.com/docker/cagent.git", repo: "docker/cagent", ok: true},
+		{url: "/local/path", ok: false},
+		{url: "https://github.com/docker", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			repo, ok := parseGitHubRemote(tt.url)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.repo, repo)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package builtin
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const ToolNameGenerateImage = "generate_image"
+
+// ImageGenerator generates images. It is implemented by the providers that
+// support image generation.
+type ImageGenerator interface {
+	GenerateImage(ctx context.Context, prompt string, opts base.ImageGenerationOptions) (*base.ImageGenerationResult, error)
+}
+
+// ImageGenerationTool lets agents generate images, such as diagrams or
+// mockups, with an image model. Generated images are saved in the working
+// directory and registered as artifacts of the session.
+type ImageGenerationTool struct {
+	generator  ImageGenerator
+	workingDir string
+}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet      = (*ImageGenerationTool)(nil)
+	_ tools.Instructable = (*ImageGenerationTool)(nil)
+)
+
+type GenerateImageArgs struct {
+	Prompt      string `json:"prompt" jsonschema:"A detailed description of the image to generate"`
+	Path        string `json:"path,omitempty" jsonschema:"Where to save the image, absolute or relative to the working directory. Defaults to a file in the images directory named after the prompt"`
+	AspectRatio string `json:"aspect_ratio,omitempty" jsonschema:"The aspect ratio of the image: 1:1 (default), 16:9 or 9:16"`
+}
+
+func NewImageGenerationTool(generator ImageGenerator, workingDir string) *ImageGenerationTool {
+	return &ImageGenerationTool{
+		generator:  generator,
+		workingDir: workingDir,
+	}
+}
+
+func (t *ImageGenerationTool) callTool(ctx context.Context, params GenerateImageArgs) (*tools.ToolCallResult, error) {
+	if strings.TrimSpace(params.Prompt) == "" {
+		return tools.ResultError("The prompt is required"), nil
+	}
+	switch params.AspectRatio {
+	case "", base.AspectRatioSquare, base.AspectRatioLandscape, base.AspectRatioPortrait:
+	default:
+		return tools.ResultError(fmt.Sprintf("Unsupported aspect ratio %q: use 1:1, 16:9 or 9:16", params.AspectRatio)), nil
+	}
+
+	image, err := t.generator.GenerateImage(ctx, params.Prompt, base.ImageGenerationOptions{AspectRatio: params.AspectRatio})
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to generate image: %v", err)), nil
+	}
+
+	path := t.imagePath(params, image.MimeType)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to save image: %v", err)), nil
+	}
+	if err := os.WriteFile(path, image.Data, 0o644); err != nil {
+		return tools.ResultError(fmt.Sprintf("Failed to save image: %v", err)), nil
+	}
+
+	output := fmt.Sprintf("Generated image saved to %s (%d bytes)", path, len(image.Data))
+	if image.RevisedPrompt != "" {
+		output += "\nRevised prompt: " + image.RevisedPrompt
+	}
+	result := tools.ResultSuccess(output)
+	result.Images = []tools.ImageContent{{
+		Data:     base64.StdEncoding.EncodeToString(image.Data),
+		MimeType: image.MimeType,
+	}}
+	result.Artifacts = []tools.Artifact{{
+		Path:        path,
+		Description: params.Prompt,
+		MimeType:    image.MimeType,
+	}}
+	return result, nil
+}
+
+// imagePath returns where to save the image: the requested path, or a new
+// file of the images directory named after the prompt.
+func (t *ImageGenerationTool) imagePath(params GenerateImageArgs, mimeType string) string {
+	if params.Path != "" {
+		if filepath.IsAbs(params.Path) {
+			return params.Path
+		}
+		return filepath.Join(t.workingDir, params.Path)
+	}
+
+	dir := filepath.Join(t.workingDir, "images")
+	name := promptSlug(params.Prompt)
+	ext := imageExtension(mimeType)
+	path := filepath.Join(dir, name+ext)
+	for i := 2; fileExists(path); i++ {
+		path = filepath.Join(dir, name+"-"+strconv.Itoa(i)+ext)
+	}
+	return path
+}
+
+// promptSlug turns the first words of a prompt into a file name.
+func promptSlug(prompt string) string {
+	words := strings.FieldsFunc(strings.ToLower(prompt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words = words[:min(len(words), 6)]
+	if len(words) == 0 {
+		return "image"
+	}
+	return strings.Join(words, "-")
+}
+
+func imageExtension(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".png"
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (t *ImageGenerationTool) Instructions() string {
+	return `## Image Generation
+
+Use the generate_image tool to create images such as diagrams, mockups or illustrations. Describe the content, style and any text of the image precisely in the prompt. Generated images are saved to the working directory and registered as artifacts of the session.`
+}
+
+func (t *ImageGenerationTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:         ToolNameGenerateImage,
+			Category:     "image_generation",
+			Description:  "Generate an image from a text description and save it to a file.",
+			Parameters:   tools.MustSchemaFor[GenerateImageArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.callTool),
+			Annotations: tools.ToolAnnotations{
+				Title: "Generate Image",
+			},
+		},
+	}, nil
+}
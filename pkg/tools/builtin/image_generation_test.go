@@ -0,0 +1,69 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+type fakeImageGenerator struct {
+	opts base.ImageGenerationOptions
+	err  error
+}
+
+func (g *fakeImageGenerator) GenerateImage(_ context.Context, _ string, opts base.ImageGenerationOptions) (*base.ImageGenerationResult, error) {
+	g.opts = opts
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &base.ImageGenerationResult{Data: []byte("png"), MimeType: "image/png"}, nil
+}
+
+func TestImageGenerationTool_Generate(t *testing.T) {
+	dir := t.TempDir()
+	generator := &fakeImageGenerator{}
+	tool := NewImageGenerationTool(generator, dir)
+
+	result, err := tool.callTool(t.Context(), GenerateImageArgs{Prompt: "An architecture diagram, in blue!", AspectRatio: "16:9"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Output)
+	assert.Equal(t, "16:9", generator.opts.AspectRatio)
+
+	path := filepath.Join(dir, "images", "an-architecture-diagram-in-blue.png")
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(buf))
+	assert.Equal(t, []tools.Artifact{{Path: path, Description: "An architecture diagram, in blue!", MimeType: "image/png"}}, result.Artifacts)
+	assert.Equal(t, []tools.ImageContent{{Data: "cG5n", MimeType: "image/png"}}, result.Images)
+
+	// Generating the same image again doesn't overwrite the first one.
+	result, err = tool.callTool(t.Context(), GenerateImageArgs{Prompt: "An architecture diagram, in blue!"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "images", "an-architecture-diagram-in-blue-2.png"), result.Artifacts[0].Path)
+
+	result, err = tool.callTool(t.Context(), GenerateImageArgs{Prompt: "Logo", Path: "logo.png"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "logo.png"), result.Artifacts[0].Path)
+}
+
+func TestImageGenerationTool_Errors(t *testing.T) {
+	tool := NewImageGenerationTool(&fakeImageGenerator{err: errors.New("quota exceeded")}, t.TempDir())
+
+	result, err := tool.callTool(t.Context(), GenerateImageArgs{Prompt: "A cat"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "quota exceeded")
+
+	result, err = tool.callTool(t.Context(), GenerateImageArgs{Prompt: "A cat", AspectRatio: "4:3"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "Unsupported aspect ratio")
+}
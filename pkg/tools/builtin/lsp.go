@@ -20,6 +20,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/docker/docker-agent/pkg/childprocess"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
@@ -58,6 +59,7 @@ var (
 type lspHandler struct {
 	mu          sync.Mutex
 	cmd         *exec.Cmd
+	proc        *childprocess.Handle
 	cancel      context.CancelFunc // cancels the process-lifetime context
 	stdin       io.WriteCloser
 	stdout      *bufio.Reader
@@ -517,7 +519,8 @@ func (h *lspHandler) startLocked() error {
 	processCtx, processCancel := context.WithCancel(context.Background())
 
 	cmd := exec.CommandContext(processCtx, h.command, h.args...)
-	cmd.Env = append(os.Environ(), h.env...)
+	// A nil env inherits the environment.
+	cmd.Env = h.env
 	cmd.Dir = h.workingDir
 
 	stdin, err := cmd.StdinPipe()
@@ -536,13 +539,22 @@ func (h *lspHandler) startLocked() error {
 	var stderrBuf bytes.Buffer
 	cmd.Stderr = &stderrBuf
 
+	childprocess.Prepare(cmd)
 	if err := cmd.Start(); err != nil {
 		stdin.Close()
 		processCancel()
 		return fmt.Errorf("failed to start LSP server: %w", err)
 	}
+	proc, err := childprocess.Track(cmd, childprocess.OwnerLSP)
+	if err != nil {
+		stdin.Close()
+		processCancel()
+		_ = cmd.Wait()
+		return fmt.Errorf("failed to start LSP server: %w", err)
+	}
 
 	h.cmd = cmd
+	h.proc = proc
 	h.cancel = processCancel
 	h.stdin = stdin
 	h.stdout = bufio.NewReader(stdout)
@@ -576,7 +588,10 @@ func (h *lspHandler) stopLocked() error {
 	}
 
 	err := h.cmd.Wait()
+	// Kill what the server may have left behind.
+	_ = h.proc.Kill()
 	h.cmd = nil
+	h.proc = nil
 	h.stdin = nil
 	h.stdout = nil
 	h.initialized.Store(false)
@@ -616,7 +631,7 @@ func (h *lspHandler) ensureInitialized() error {
 	}
 
 	if !h.initialized.Load() {
-		rootURI := "file://" + h.workingDir
+		rootURI := pathToURI(h.workingDir)
 		initParams := map[string]any{
 			"processId": os.Getpid(),
 			"rootUri":   rootURI,
@@ -1038,7 +1053,7 @@ func (h *lspHandler) format(ctx context.Context, args FileArgs) (*tools.ToolCall
 		return tools.ResultError(fmt.Sprintf("Failed to apply formatting: %s", err)), nil
 	}
 
-	if err := h.NotifyFileChange(ctx, uri); err != nil {
+	if err := h.notifyFileChangeLocked(uri); err != nil {
 		slog.Debug("Failed to notify LSP of format changes", "error", err)
 	}
 
@@ -1249,7 +1264,8 @@ func (h *lspHandler) inlayHints(ctx context.Context, args InlayHintsArgs) (*tool
 	return tools.ResultSuccess(formatInlayHints(args.File, startLine, endLine, hints)), nil
 }
 
-// applyWorkspaceEdit applies a workspace edit and returns a summary
+// applyWorkspaceEdit applies a workspace edit and returns a summary. The
+// caller must hold h.mu.
 func (h *lspHandler) applyWorkspaceEdit(edit *lspWorkspaceEdit, newName string) *tools.ToolCallResult {
 	var totalChanges int
 	var modifiedFiles []string
@@ -1257,10 +1273,11 @@ func (h *lspHandler) applyWorkspaceEdit(edit *lspWorkspaceEdit, newName string)
 
 	if len(edit.DocumentChanges) > 0 {
 		for _, docEdit := range edit.DocumentChanges {
-			filePath := strings.TrimPrefix(docEdit.TextDocument.URI, "file://")
+			filePath := uriToPath(docEdit.TextDocument.URI)
 			if err := applyTextEditsToFile(filePath, docEdit.Edits); err != nil {
 				return tools.ResultError(fmt.Sprintf("Failed to apply changes to %s: %s", filePath, err))
 			}
+			h.syncEditedFileLocked(filePath)
 			fileChangeCounts[filePath] = len(docEdit.Edits)
 			totalChanges += len(docEdit.Edits)
 			modifiedFiles = append(modifiedFiles, filePath)
@@ -1269,10 +1286,11 @@ func (h *lspHandler) applyWorkspaceEdit(edit *lspWorkspaceEdit, newName string)
 
 	if len(edit.Changes) > 0 {
 		for uri, edits := range edit.Changes {
-			filePath := strings.TrimPrefix(uri, "file://")
+			filePath := uriToPath(uri)
 			if err := applyTextEditsToFile(filePath, edits); err != nil {
 				return tools.ResultError(fmt.Sprintf("Failed to apply changes to %s: %s", filePath, err))
 			}
+			h.syncEditedFileLocked(filePath)
 			fileChangeCounts[filePath] = len(edits)
 			totalChanges += len(edits)
 			modifiedFiles = append(modifiedFiles, filePath)
@@ -1293,6 +1311,19 @@ func (h *lspHandler) applyWorkspaceEdit(edit *lspWorkspaceEdit, newName string)
 	return tools.ResultSuccess(result.String())
 }
 
+// syncEditedFileLocked sends the new content of a file edited on disk to the
+// server if the document is open, so that later requests see the edits. The
+// caller must hold h.mu.
+func (h *lspHandler) syncEditedFileLocked(filePath string) {
+	uri := pathToURI(filePath)
+	if !h.isFileOpen(uri) {
+		return
+	}
+	if err := h.notifyFileChangeLocked(uri); err != nil {
+		slog.Debug("Failed to notify LSP of workspace edit", "file", filePath, "error", err)
+	}
+}
+
 // applyTextEditsToFile applies LSP text edits to a file on disk
 func applyTextEditsToFile(filePath string, edits []lspTextEdit) error {
 	content, err := os.ReadFile(filePath)
@@ -1507,7 +1538,7 @@ func (h *lspHandler) processNotification(msg []byte) {
 			return
 		}
 		h.diagnosticsMu.Lock()
-		h.diagnostics[params.URI] = params.Diagnostics
+		h.diagnostics[normalizeURI(params.URI)] = params.Diagnostics
 		h.diagnosticsVersion.Add(1)
 		h.diagnosticsMu.Unlock()
 		slog.Debug("Received diagnostics", "uri", params.URI, "count", len(params.Diagnostics))
@@ -1532,19 +1563,22 @@ func (h *lspHandler) handlesFile(path string) bool {
 	return false
 }
 
+// isFileOpen returns whether the document is open. Open documents are
+// tracked by normalized URI, so any encoding of a URI matches.
 func (h *lspHandler) isFileOpen(uri string) bool {
 	h.openFilesMu.RLock()
 	defer h.openFilesMu.RUnlock()
-	_, ok := h.openFiles[uri]
+	_, ok := h.openFiles[normalizeURI(uri)]
 	return ok
 }
 
 func (h *lspHandler) openFileOnDemand(_ context.Context, uri string) error {
+	uri = normalizeURI(uri)
 	if h.isFileOpen(uri) {
 		return nil
 	}
 
-	filePath := strings.TrimPrefix(uri, "file://")
+	filePath := uriToPath(uri)
 
 	if !h.handlesFile(filePath) {
 		return fmt.Errorf("LSP does not handle file type: %s", filepath.Ext(filePath))
@@ -1582,11 +1616,21 @@ func (h *lspHandler) openFileOnDemand(_ context.Context, uri string) error {
 }
 
 func (h *lspHandler) NotifyFileChange(_ context.Context, uri string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.notifyFileChangeLocked(uri)
+}
+
+// notifyFileChangeLocked sends the content of an open document on disk to the
+// server. The caller must hold h.mu.
+func (h *lspHandler) notifyFileChangeLocked(uri string) error {
+	uri = normalizeURI(uri)
 	if !h.isFileOpen(uri) {
 		return fmt.Errorf("file not open: %s", uri)
 	}
 
-	filePath := strings.TrimPrefix(uri, "file://")
+	filePath := uriToPath(uri)
 
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -1598,9 +1642,6 @@ func (h *lspHandler) NotifyFileChange(_ context.Context, uri string) error {
 	version := h.openFiles[uri]
 	h.openFilesMu.Unlock()
 
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	changeParams := map[string]any{
 		"textDocument":   map[string]any{"uri": uri, "version": version},
 		"contentChanges": []map[string]any{{"text": string(content)}},
@@ -1629,14 +1670,6 @@ func (h *lspHandler) waitForDiagnostics(ctx context.Context, timeout time.Durati
 	}
 }
 
-func pathToURI(path string) string {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return "file://" + path
-	}
-	return "file://" + absPath
-}
-
 func detectLanguageID(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
 	languageMap := map[string]string{
@@ -1778,7 +1811,7 @@ func formatLocations(data json.RawMessage) string {
 
 func formatLocation(loc lspLocation) string {
 	return fmt.Sprintf("- %s:%d:%d",
-		strings.TrimPrefix(loc.URI, "file://"),
+		uriToPath(loc.URI),
 		loc.Range.Start.Line+1,
 		loc.Range.Start.Character+1)
 }
@@ -1798,7 +1831,7 @@ func formatSymbols(data json.RawMessage) string {
 		var lines []string
 		for _, s := range symbols {
 			kind := symbolKindName(s.Kind)
-			loc := strings.TrimPrefix(s.Location.URI, "file://")
+			loc := uriToPath(s.Location.URI)
 			line := fmt.Sprintf("- %s %s (%s:%d)", kind, s.Name, loc, s.Location.Range.Start.Line+1)
 			if s.ContainerName != "" {
 				line += fmt.Sprintf(" [in %s]", s.ContainerName)
@@ -1879,7 +1912,7 @@ func formatIncomingCalls(targetName string, data json.RawMessage) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("Incoming calls to '%s':", targetName))
 	for _, call := range calls {
-		filePath := strings.TrimPrefix(call.From.URI, "file://")
+		filePath := uriToPath(call.From.URI)
 		line := call.From.Range.Start.Line + 1
 		detail := ""
 		if call.From.Detail != "" {
@@ -1910,7 +1943,7 @@ func formatOutgoingCalls(sourceName string, data json.RawMessage) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("Outgoing calls from '%s':", sourceName))
 	for _, call := range calls {
-		filePath := strings.TrimPrefix(call.To.URI, "file://")
+		filePath := uriToPath(call.To.URI)
 		line := call.To.Range.Start.Line + 1
 		detail := ""
 		if call.To.Detail != "" {
@@ -1935,7 +1968,7 @@ func formatTypeHierarchy(typeName, direction string, data json.RawMessage) strin
 	var lines []string
 	lines = append(lines, fmt.Sprintf("%s of '%s':", direction, typeName))
 	for _, item := range items {
-		filePath := strings.TrimPrefix(item.URI, "file://")
+		filePath := uriToPath(item.URI)
 		line := item.Range.Start.Line + 1
 		detail := ""
 		if item.Detail != "" {
@@ -431,14 +431,6 @@ func TestLSPTool_HandlesFile(t *testing.T) {
 	assert.False(t, toolNoDot.HandlesFile("index.js"))
 }
 
-func TestPathToURI(t *testing.T) {
-	t.Parallel()
-
-	// Absolute path
-	uri := pathToURI("/home/user/project/main.go")
-	assert.Equal(t, "file:///home/user/project/main.go", uri)
-}
-
 func TestLSPHandler_IsFileOpen(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,59 @@
+package builtin
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// File URIs are the identifiers of documents in LSP. Paths must go through
+// pathToURI and uriToPath, never through string concatenation or trimming of
+// "file://", so that Windows paths and paths with spaces or non-ASCII
+// characters are escaped and unescaped correctly.
+
+// pathToURI returns the file URI of a path: file:///home/user/main.go, or
+// file:///C:/Users/user/main.go for Windows paths.
+func pathToURI(path string) string {
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Drive letter
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// uriToPath returns the path of a file URI. Escaped characters are decoded,
+// and drive letters, which servers may send lower-cased and escaped (e.g.
+// file:///c%3A/Users), are normalized. Other URIs are returned as-is.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+
+	path := u.Path
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' && isASCIILetter(path[1]) {
+		path = strings.ToUpper(path[1:2]) + path[2:]
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		// UNC path: file://server/share/file
+		path = "//" + u.Host + path
+	}
+	return filepath.FromSlash(path)
+}
+
+// normalizeURI returns the canonical form of a file URI, the one pathToURI
+// returns, so that URIs sent by servers can be compared to ours.
+func normalizeURI(uri string) string {
+	if !strings.HasPrefix(uri, "file:") {
+		return uri
+	}
+	return pathToURI(uriToPath(uri))
+}
+
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
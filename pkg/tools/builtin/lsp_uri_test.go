@@ -0,0 +1,98 @@
+package builtin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathToURI(t *testing.T) {
+	t.Parallel()
+
+	// Absolute path
+	uri := pathToURI("/home/user/project/main.go")
+	assert.Equal(t, "file:///home/user/project/main.go", uri)
+
+	// Spaces and non-ASCII characters are escaped
+	assert.Equal(t, "file:///home/user/my%20project/caf%C3%A9.go", pathToURI("/home/user/my project/café.go"))
+}
+
+func TestURIToPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.FromSlash("/home/user/my project/main.go"), uriToPath("file:///home/user/my%20project/main.go"))
+	assert.Equal(t, filepath.FromSlash("/home/user/café.go"), uriToPath("file:///home/user/caf%C3%A9.go"))
+	assert.Equal(t, filepath.FromSlash("C:/Users/user/main.go"), uriToPath("file:///C:/Users/user/main.go"))
+	assert.Equal(t, filepath.FromSlash("C:/Users/user/main.go"), uriToPath("file:///c%3A/Users/user/main.go"))
+	assert.Equal(t, filepath.FromSlash("//server/share/main.go"), uriToPath("file://server/share/main.go"))
+	assert.Equal(t, "untitled:Untitled-1", uriToPath("untitled:Untitled-1"))
+}
+
+func TestURIRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"main.go", "my file.go", "café.go", "日本語.go", "100%.go", "a#b?c.go"} {
+		path := filepath.Join(t.TempDir(), "dir with spaces", name)
+		assert.Equal(t, path, uriToPath(pathToURI(path)), name)
+	}
+}
+
+func TestNormalizeURI(t *testing.T) {
+	t.Parallel()
+
+	// Servers may escape characters we don't, or not escape those we do.
+	assert.Equal(t, "file:///home/user/my%20project/main.go", normalizeURI("file:///home/user/my%20project/main.go"))
+	assert.Equal(t, "file:///home/user/caf%C3%A9.go", normalizeURI("file:///home/user/caf%c3%a9.go"))
+	assert.Equal(t, "file:///home/user/main.go", normalizeURI("file:///home/user/%6Dain.go"))
+	assert.Equal(t, "untitled:Untitled-1", normalizeURI("untitled:Untitled-1"))
+}
+
+func TestLSPHandler_IsFileOpenNormalizesURIs(t *testing.T) {
+	t.Parallel()
+
+	tool := NewLSPTool("gopls", nil, nil, "/tmp")
+
+	tool.handler.openFilesMu.Lock()
+	tool.handler.openFiles[pathToURI("/home/user/café.go")] = 1
+	tool.handler.openFilesMu.Unlock()
+
+	assert.True(t, tool.handler.isFileOpen("file:///home/user/caf%c3%a9.go"))
+}
+
+func TestLSPHandler_ApplyWorkspaceEditWithEscapedURIs(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "my project")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	first := filepath.Join(dir, "café.go")
+	second := filepath.Join(dir, "main file.go")
+	require.NoError(t, os.WriteFile(first, []byte("var oldName = 1\n"), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte("print(oldName)\n"), 0o644))
+
+	edit := func(line, start, end int) []lspTextEdit {
+		return []lspTextEdit{{
+			Range:   lspRange{Start: lspPosition{Line: line, Character: start}, End: lspPosition{Line: line, Character: end}},
+			NewText: "newName",
+		}}
+	}
+
+	tool := NewLSPTool("gopls", nil, nil, dir)
+	result := tool.handler.applyWorkspaceEdit(&lspWorkspaceEdit{
+		Changes: map[string][]lspTextEdit{
+			pathToURI(first):  edit(0, 4, 11),
+			pathToURI(second): edit(0, 6, 13),
+		},
+	}, "newName")
+	require.False(t, result.IsError, result.Output)
+
+	content, err := os.ReadFile(first)
+	require.NoError(t, err)
+	assert.Equal(t, "var newName = 1\n", string(content))
+
+	content, err = os.ReadFile(second)
+	require.NoError(t, err)
+	assert.Equal(t, "print(newName)\n", string(content))
+}
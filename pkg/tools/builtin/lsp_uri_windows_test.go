@@ -0,0 +1,21 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathToURI_Windows(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "file:///C:/Users/user/my%20project/main.go", pathToURI(`C:\Users\user\my project\main.go`))
+	assert.Equal(t, `C:\Users\user\my project\main.go`, uriToPath(pathToURI(`C:\Users\user\my project\main.go`)))
+}
+
+func TestNormalizeURI_Windows(t *testing.T) {
+	t.Parallel()
+
+	// Servers like gopls send lower-cased, escaped drive letters.
+	assert.Equal(t, "file:///C:/Users/user/main.go", normalizeURI("file:///c%3A/Users/user/main.go"))
+}
@@ -0,0 +1,55 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const (
+	ToolNameGoogleSearch  = "google_search"
+	ToolNameCodeExecution = "code_execution"
+)
+
+// NativeTool exposes a tool that the model provider runs server-side, such as
+// Gemini's Google Search grounding or code execution. The runtime never
+// executes it: the provider receives it as a built-in tool declaration.
+type NativeTool struct {
+	tool tools.Tool
+}
+
+// Verify interface compliance
+var _ tools.ToolSet = (*NativeTool)(nil)
+
+// NewGoogleSearchTool grounds Gemini answers with Google Search results.
+func NewGoogleSearchTool() *NativeTool {
+	return newNativeTool("google", ToolNameGoogleSearch, "Search", "Ground answers with Google Search results.")
+}
+
+// NewCodeExecutionTool lets Gemini write and run Python code in a sandbox.
+func NewCodeExecutionTool() *NativeTool {
+	return newNativeTool("google", ToolNameCodeExecution, "Code Execution", "Write and run Python code in a sandbox hosted by the model provider.")
+}
+
+func newNativeTool(provider, name, title, description string) *NativeTool {
+	return &NativeTool{
+		tool: tools.Tool{
+			Name:        name,
+			Category:    name,
+			Description: description,
+			Provider:    provider,
+			Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+				return tools.ResultError(fmt.Sprintf("%s is run by the %s model provider and can't be called directly", name, provider)), nil
+			},
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        title,
+			},
+		},
+	}
+}
+
+func (t *NativeTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{t.tool}, nil
+}
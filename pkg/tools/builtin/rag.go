@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"slices"
 
 	"github.com/docker/docker-agent/pkg/rag"
@@ -138,7 +139,25 @@ func (t *RAGTool) handleQueryRAG(ctx context.Context, args QueryRAGArgs) (*tools
 		return nil, fmt.Errorf("failed to marshal results: %w", err)
 	}
 
-	return tools.ResultSuccess(string(resultJSON)), nil
+	result := tools.ResultSuccess(string(resultJSON))
+	result.SearchResults = searchResults(allResults)
+	return result, nil
+}
+
+// searchResults converts query results into citable search results
+func searchResults(results []QueryResult) []tools.SearchResult {
+	searchResults := make([]tools.SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Content == "" {
+			continue
+		}
+		searchResults = append(searchResults, tools.SearchResult{
+			Source:  result.SourcePath,
+			Title:   fmt.Sprintf("%s (chunk %d)", filepath.Base(result.SourcePath), result.ChunkIndex),
+			Content: []string{result.Content},
+		})
+	}
+	return searchResults
 }
 
 // sortResults sorts query results by similarity in descending order
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/tools"
 )
 
 func TestRAGTool_ToolName(t *testing.T) {
@@ -77,3 +79,22 @@ func TestRAGTool_SortResults(t *testing.T) {
 	assert.Equal(t, "a.txt", results[2].SourcePath)
 	assert.Equal(t, "c.txt", results[3].SourcePath)
 }
+
+func TestRAGTool_SearchResults(t *testing.T) {
+	results := []QueryResult{
+		{SourcePath: "/docs/guide.md", Content: "Install the CLI first.", Similarity: 0.9, ChunkIndex: 2},
+		{SourcePath: "/docs/empty.md", Content: "", Similarity: 0.5},
+		{SourcePath: "/docs/faq.md", Content: "Use --debug for logs.", Similarity: 0.4},
+	}
+
+	searchResults := searchResults(results)
+
+	require.Len(t, searchResults, 2)
+	assert.Equal(t, tools.SearchResult{
+		Source:  "/docs/guide.md",
+		Title:   "guide.md (chunk 2)",
+		Content: []string{"Install the CLI first."},
+	}, searchResults[0])
+	assert.Equal(t, "/docs/faq.md", searchResults[1].Source)
+	assert.Equal(t, []string{"Use --debug for logs."}, searchResults[1].Content)
+}
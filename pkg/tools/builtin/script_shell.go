@@ -1,6 +1,7 @@
 package builtin
 
 import (
+	"bytes"
 	"cmp"
 	"context"
 	"encoding/json"
@@ -11,6 +12,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/docker/docker-agent/pkg/childprocess"
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/tools"
 )
@@ -48,16 +50,23 @@ func validateConfig(toolName string, tool latest.ScriptShellToolConfig) error {
 		}
 	}
 
-	// Check for typos in args
-	var missingArgs []string
-	os.Expand(tool.Cmd, func(varName string) string {
-		if _, ok := tool.Args[varName]; !ok {
-			missingArgs = append(missingArgs, varName)
+	if tool.Interpreter != nil && (len(tool.Interpreter) == 0 || tool.Interpreter[0] == "") {
+		return fmt.Errorf("tool '%s' has an empty interpreter", toolName)
+	}
+
+	// Check for typos in args. Only shell commands reference the args as
+	// variables: other interpreters read them from their environment.
+	if tool.Interpreter == nil {
+		var missingArgs []string
+		os.Expand(tool.Cmd, func(varName string) string {
+			if _, ok := tool.Args[varName]; !ok {
+				missingArgs = append(missingArgs, varName)
+			}
+			return ""
+		})
+		if len(missingArgs) > 0 {
+			return fmt.Errorf("tool '%s' uses undefined args: %v", toolName, missingArgs)
 		}
-		return ""
-	})
-	if len(missingArgs) > 0 {
-		return fmt.Errorf("tool '%s' uses undefined args: %v", toolName, missingArgs)
 	}
 
 	// Check that all required args are defined
@@ -83,7 +92,10 @@ func (t *ScriptShellTool) Instructions() string {
 		}
 
 		for argName, argDef := range tool.Args {
-			description := argDef.(map[string]any)["description"].(string)
+			var description string
+			if def, ok := argDef.(map[string]any); ok {
+				description, _ = def["description"].(string)
+			}
 			required := ""
 			if slices.Contains(tool.Required, argName) {
 				required = " (required)"
@@ -136,24 +148,71 @@ func (t *ScriptShellTool) execute(ctx context.Context, toolConfig *latest.Script
 		}
 	}
 
-	// Use default shell
-	shell := cmp.Or(os.Getenv("SHELL"), "/bin/sh")
+	// Use default shell, unless the tool has its own interpreter
+	interpreter := toolConfig.Interpreter
+	if interpreter == nil {
+		interpreter = []string{cmp.Or(os.Getenv("SHELL"), "/bin/sh"), "-c"}
+	}
 
-	cmd := exec.CommandContext(ctx, shell, "-c", toolConfig.Cmd)
+	cmd := exec.CommandContext(ctx, interpreter[0], append(slices.Clone(interpreter[1:]), toolConfig.Cmd)...)
 	cmd.Dir = toolConfig.WorkingDir
-	cmd.Env = t.env
+	cmd.Env = slices.Clone(t.env)
+	for _, key := range slices.Sorted(maps.Keys(toolConfig.Env)) {
+		cmd.Env = append(cmd.Env, key+"="+toolConfig.Env[key])
+	}
 	for key, value := range params {
 		if value != nil {
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+			cmd.Env = append(cmd.Env, key+"="+envValue(value))
 		}
 	}
+	if toolConfig.ArgsStdin {
+		if params == nil {
+			params = map[string]any{}
+		}
+		stdin, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	childprocess.Prepare(cmd)
 
-	output, err := cmd.CombinedOutput()
+	if err := cmd.Start(); err != nil {
+		return tools.ResultError(fmt.Sprintf("Error executing command '%s': %s", toolConfig.Cmd, err)), nil
+	}
+	proc, err := childprocess.Track(cmd, childprocess.OwnerScript)
 	if err != nil {
-		return tools.ResultError(fmt.Sprintf("Error executing command '%s': %s\nOutput: %s", toolConfig.Cmd, err, limitOutput(string(output)))), nil
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return tools.ResultError(fmt.Sprintf("Error executing command '%s': %s", toolConfig.Cmd, err)), nil
+	}
+	defer proc.Release()
+	// Also kill the children of the command when the call is cancelled.
+	stop := context.AfterFunc(ctx, func() { _ = proc.Kill() })
+	defer stop()
+
+	if err := cmd.Wait(); err != nil {
+		return tools.ResultError(fmt.Sprintf("Error executing command '%s': %s\nOutput: %s", toolConfig.Cmd, err, limitOutput(output.String()))), nil
 	}
 
-	return tools.ResultSuccess(limitOutput(string(output))), nil
+	return tools.ResultSuccess(limitOutput(output.String())), nil
+}
+
+// envValue formats an argument as an environment variable: strings as is,
+// other values as JSON.
+func envValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(buf)
 }
 
 // defaultPropertyTypes returns a copy of properties where any property
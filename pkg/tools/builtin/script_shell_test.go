@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/tools"
 )
 
 func TestNewScriptShellTool_Empty(t *testing.T) {
@@ -150,3 +151,73 @@ func TestNewScriptShellTool_ArgWithoutType(t *testing.T) {
 	"required": ["name"]
 }`, string(schema))
 }
+
+func TestScriptShellTool_Interpreter(t *testing.T) {
+	shellTools := map[string]latest.ScriptShellToolConfig{
+		"greet": {
+			Description: "Greet someone",
+			Interpreter: []string{"sh", "-c"},
+			Cmd:         `printf '%s %s x%s' "$GREETING" "$name" "$count"`,
+			Args: map[string]any{
+				"name":  map[string]any{"type": "string"},
+				"count": map[string]any{"type": "integer"},
+			},
+			Env: map[string]string{"GREETING": "Hello"},
+		},
+	}
+
+	tool, err := NewScriptShellTool(shellTools, nil)
+	require.NoError(t, err)
+
+	allTools, err := tool.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, allTools, 1)
+
+	result, err := allTools[0].Handler(t.Context(), tools.ToolCall{
+		Function: tools.FunctionCall{Name: "greet", Arguments: `{"name":"world","count":3}`},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError, result.Output)
+	assert.Equal(t, "Hello world x3", result.Output)
+}
+
+func TestScriptShellTool_ArgsStdin(t *testing.T) {
+	shellTools := map[string]latest.ScriptShellToolConfig{
+		"echo_args": {
+			Description: "Print the arguments",
+			Interpreter: []string{"sh", "-c"},
+			Cmd:         "cat",
+			Args: map[string]any{
+				"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+			ArgsStdin: true,
+		},
+	}
+
+	tool, err := NewScriptShellTool(shellTools, nil)
+	require.NoError(t, err)
+
+	allTools, err := tool.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, allTools, 1)
+
+	result, err := allTools[0].Handler(t.Context(), tools.ToolCall{
+		Function: tools.FunctionCall{Name: "echo_args", Arguments: `{"tags":["a","b"]}`},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError, result.Output)
+	assert.JSONEq(t, `{"tags":["a","b"]}`, result.Output)
+}
+
+func TestNewScriptShellTool_EmptyInterpreter(t *testing.T) {
+	shellTools := map[string]latest.ScriptShellToolConfig{
+		"broken": {
+			Cmd:         "print('hello')",
+			Interpreter: []string{},
+		},
+	}
+
+	tool, err := NewScriptShellTool(shellTools, nil)
+	require.Nil(t, tool)
+	require.ErrorContains(t, err, "tool 'broken' has an empty interpreter")
+}
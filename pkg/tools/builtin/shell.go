@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -15,18 +14,21 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/docker/docker-agent/pkg/childprocess"
 	"github.com/docker/docker-agent/pkg/concurrent"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/shellpath"
 	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/workspace"
 )
 
 const (
-	ToolNameShell              = "shell"
-	ToolNameRunShellBackground = "run_background_job"
-	ToolNameListBackgroundJobs = "list_background_jobs"
-	ToolNameViewBackgroundJob  = "view_background_job"
-	ToolNameStopBackgroundJob  = "stop_background_job"
+	ToolNameShell               = "shell"
+	ToolNameRunShellBackground  = "run_background_job"
+	ToolNameListBackgroundJobs  = "list_background_jobs"
+	ToolNameViewBackgroundJob   = "view_background_job"
+	ToolNameStopBackgroundJob   = "stop_background_job"
+	ToolNameViewShellTranscript = "view_shell_transcript"
 )
 
 // ShellTool provides shell command execution capabilities.
@@ -39,6 +41,7 @@ var (
 	_ tools.ToolSet      = (*ShellTool)(nil)
 	_ tools.Startable    = (*ShellTool)(nil)
 	_ tools.Instructable = (*ShellTool)(nil)
+	_ tools.SessionEnder = (*ShellTool)(nil)
 )
 
 type shellHandler struct {
@@ -47,8 +50,15 @@ type shellHandler struct {
 	env             []string
 	timeout         time.Duration
 	workingDir      string
+	workspace       workspace.Workspace
 	jobs            *concurrent.Map[string, *backgroundJob]
 	jobCounter      atomic.Int64
+
+	// pty runs commands in a persistent shell instead of a fresh one per call.
+	// Each session has its own shell, by session ID.
+	pty       bool
+	ptyMu     sync.Mutex
+	ptyShells map[string]*ptyShell
 }
 
 // Job status constants
@@ -61,17 +71,17 @@ const (
 
 // backgroundJob tracks a background shell command
 type backgroundJob struct {
-	id           string
-	cmd          string
-	cwd          string
-	process      *os.Process
-	processGroup *processGroup
-	outputMu     sync.RWMutex
-	output       *bytes.Buffer
-	startTime    time.Time
-	status       atomic.Int32
-	exitCode     int
-	err          error
+	id        string
+	cmd       string
+	cwd       string
+	process   *childprocess.Handle
+	outputMu  sync.RWMutex
+	output    *limitedWriter
+	viewed    int // length of the output returned by the last view
+	startTime time.Time
+	status    atomic.Int32
+	exitCode  int
+	err       error
 }
 
 // limitedWriter wraps a buffer and stops writing after maxSize bytes
@@ -102,6 +112,14 @@ func (lw *limitedWriter) Write(p []byte) (n int, err error) {
 	return n, err
 }
 
+// String returns the output written so far.
+func (lw *limitedWriter) String() string {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	return lw.buf.String()
+}
+
 type RunShellArgs struct {
 	Cmd     string `json:"cmd" jsonschema:"The shell command to execute"`
 	Cwd     string `json:"cwd,omitempty" jsonschema:"The working directory to execute the command in (default: \".\")"`
@@ -114,7 +132,8 @@ type RunShellBackgroundArgs struct {
 }
 
 type ViewBackgroundJobArgs struct {
-	JobID string `json:"job_id" jsonschema:"The ID of the background job to view"`
+	JobID         string `json:"job_id" jsonschema:"The ID of the background job to view"`
+	NewOutputOnly bool   `json:"new_output_only,omitempty" jsonschema:"Only return the output produced since the previous view of the job, to poll its progress"`
 }
 
 type StopBackgroundJobArgs struct {
@@ -146,6 +165,10 @@ func (h *shellHandler) RunShell(ctx context.Context, params RunShellArgs) (*tool
 		timeout = time.Duration(params.Timeout) * time.Second
 	}
 
+	if h.pty {
+		return h.runPTY(ctx, params, timeout), nil
+	}
+
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -157,10 +180,10 @@ func (h *shellHandler) RunShell(ctx context.Context, params RunShellArgs) (*tool
 }
 
 func (h *shellHandler) runNativeCommand(timeoutCtx, ctx context.Context, command, cwd string, timeout time.Duration) *tools.ToolCallResult {
-	cmd := exec.Command(h.shell, append(h.shellArgsPrefix, command)...)
+	cmd := shellpath.Command(context.Background(), h.shell, h.shellArgsPrefix, command)
 	cmd.Env = h.env
 	cmd.Dir = cwd
-	cmd.SysProcAttr = platformSpecificSysProcAttr()
+	childprocess.Prepare(cmd)
 
 	var outBuf bytes.Buffer
 	cmd.Stdout = &outBuf
@@ -170,10 +193,12 @@ func (h *shellHandler) runNativeCommand(timeoutCtx, ctx context.Context, command
 		return tools.ResultError(fmt.Sprintf("Error starting command: %s", err))
 	}
 
-	pg, err := createProcessGroup(cmd.Process)
+	proc, err := childprocess.Track(cmd, childprocess.OwnerShell)
 	if err != nil {
+		_ = cmd.Process.Kill()
 		return tools.ResultError(fmt.Sprintf("Error creating process group: %s", err))
 	}
+	defer proc.Release()
 
 	done := make(chan error, 1)
 	go func() {
@@ -183,7 +208,7 @@ func (h *shellHandler) runNativeCommand(timeoutCtx, ctx context.Context, command
 	var cmdErr error
 	select {
 	case <-timeoutCtx.Done():
-		_ = kill(cmd.Process, pg)
+		_ = proc.Kill()
 	case cmdErr = <-done:
 	}
 
@@ -195,34 +220,32 @@ func (h *shellHandler) RunShellBackground(_ context.Context, params RunShellBack
 	counter := h.jobCounter.Add(1)
 	jobID := fmt.Sprintf("job_%d_%d", time.Now().Unix(), counter)
 
-	cmd := exec.Command(h.shell, append(h.shellArgsPrefix, params.Cmd)...)
+	cmd := shellpath.Command(context.Background(), h.shell, h.shellArgsPrefix, params.Cmd)
 	cmd.Env = h.env
 	cmd.Dir = h.resolveWorkDir(params.Cwd)
-	cmd.SysProcAttr = platformSpecificSysProcAttr()
+	childprocess.Prepare(cmd)
 
-	outputBuf := &bytes.Buffer{}
-	limitedWriter := &limitedWriter{buf: outputBuf, maxSize: 10 * 1024 * 1024}
-	cmd.Stdout = limitedWriter
-	cmd.Stderr = limitedWriter
+	output := &limitedWriter{buf: &bytes.Buffer{}, maxSize: 10 * 1024 * 1024}
+	cmd.Stdout = output
+	cmd.Stderr = output
 
 	if err := cmd.Start(); err != nil {
 		return tools.ResultError(fmt.Sprintf("Error starting background command: %s", err)), nil
 	}
 
-	pg, err := createProcessGroup(cmd.Process)
+	proc, err := childprocess.Track(cmd, childprocess.OwnerShell)
 	if err != nil {
-		_ = kill(cmd.Process, pg)
+		_ = cmd.Process.Kill()
 		return tools.ResultError(fmt.Sprintf("Error creating process group: %s", err)), nil
 	}
 
 	job := &backgroundJob{
-		id:           jobID,
-		cmd:          params.Cmd,
-		cwd:          params.Cwd,
-		process:      cmd.Process,
-		processGroup: pg,
-		output:       outputBuf,
-		startTime:    time.Now(),
+		id:        jobID,
+		cmd:       params.Cmd,
+		cwd:       params.Cwd,
+		process:   proc,
+		output:    output,
+		startTime: time.Now(),
 	}
 	job.status.Store(statusRunning)
 	h.jobs.Store(jobID, job)
@@ -235,6 +258,7 @@ func (h *shellHandler) RunShellBackground(_ context.Context, params RunShellBack
 
 func (h *shellHandler) monitorJob(job *backgroundJob, cmd *exec.Cmd) {
 	err := cmd.Wait()
+	job.process.Release()
 
 	job.outputMu.Lock()
 	defer job.outputMu.Unlock()
@@ -295,10 +319,17 @@ func (h *shellHandler) ViewBackgroundJob(_ context.Context, params ViewBackgroun
 
 	status := job.status.Load()
 
-	job.outputMu.RLock()
+	job.outputMu.Lock()
 	output := job.output.String()
 	exitCode := job.exitCode
-	job.outputMu.RUnlock()
+	viewed := job.viewed
+	job.viewed = len(output)
+	job.outputMu.Unlock()
+
+	truncated := len(output) >= 10*1024*1024
+	if params.NewOutputOnly {
+		output = output[min(viewed, len(output)):]
+	}
 
 	var result strings.Builder
 	fmt.Fprintf(&result, "Job ID: %s\n", job.id)
@@ -308,12 +339,16 @@ func (h *shellHandler) ViewBackgroundJob(_ context.Context, params ViewBackgroun
 	if status != statusRunning {
 		fmt.Fprintf(&result, "Exit Code: %d\n", exitCode)
 	}
-	result.WriteString("\n--- Output ---\n")
+	if params.NewOutputOnly {
+		result.WriteString("\n--- New Output ---\n")
+	} else {
+		result.WriteString("\n--- Output ---\n")
+	}
 	if output == "" {
 		result.WriteString("<no output>\n")
 	} else {
 		result.WriteString(output)
-		if len(output) >= 10*1024*1024 {
+		if truncated {
 			result.WriteString("\n\n[Output truncated at 10MB limit]")
 		}
 	}
@@ -332,7 +367,7 @@ func (h *shellHandler) StopBackgroundJob(_ context.Context, params StopBackgroun
 		return tools.ResultError(fmt.Sprintf("Job %s is not running (current status: %s)", params.JobID, statusToString(currentStatus))), nil
 	}
 
-	if err := kill(job.process, job.processGroup); err != nil {
+	if err := job.process.Kill(); err != nil {
 		return tools.ResultError(fmt.Sprintf("Job %s marked as stopped, but error killing process: %s", params.JobID, err)), nil
 	}
 
@@ -350,11 +385,27 @@ func NewShellTool(env []string, runConfig *config.RuntimeConfig) *ShellTool {
 		timeout:         30 * time.Second,
 		jobs:            concurrent.NewMap[string, *backgroundJob](),
 		workingDir:      runConfig.WorkingDir,
+		workspace:       runConfig.Workspace(),
 	}
 
 	return &ShellTool{handler: handler}
 }
 
+// SetWorkspace sets the roots the cwd of commands can be resolved from. Its
+// primary root replaces the working directory.
+func (t *ShellTool) SetWorkspace(ws workspace.Workspace) {
+	t.handler.workingDir = ws.Primary()
+	t.handler.workspace = ws
+}
+
+// EnablePTY makes commands run in a persistent interactive shell, in a
+// pseudo-terminal, instead of a fresh shell per command. The working
+// directory, variables and activated environments persist between commands
+// until the session ends.
+func (t *ShellTool) EnablePTY() {
+	t.handler.pty = true
+}
+
 // detectShell returns the appropriate shell and arguments based on the platform.
 // It delegates to shellpath.DetectShell which uses absolute paths to prevent
 // PATH hijacking (CWE-426).
@@ -367,6 +418,9 @@ func (h *shellHandler) resolveWorkDir(cwd string) string {
 	if cwd == "" || cwd == "." {
 		return h.workingDir
 	}
+	if h.workspace.IsMultiRoot() {
+		return h.workspace.Resolve(cwd)
+	}
 	if !filepath.IsAbs(cwd) {
 		return filepath.Clean(filepath.Join(h.workingDir, cwd))
 	}
@@ -392,6 +446,20 @@ func formatCommandOutput(timeoutCtx, ctx context.Context, err error, rawOutput s
 }
 
 func (t *ShellTool) Instructions() string {
+	if t.handler.pty {
+		return `## Shell Tools
+
+- All calls run in the same persistent shell: cd, exported variables and activated environments (e.g. Python venvs) carry over to the next calls
+- Default timeout: 30s. Set "timeout" for longer operations (builds, tests). Timed-out commands are interrupted
+- Don't start interactive programs (editors, pagers, REPLs); pass non-interactive flags instead
+- For git commits, add trailer: git commit -m "message" -m "" -m "Assisted-By: docker-agent"
+- Use view_shell_transcript to review the commands run so far and their outputs
+
+### Background Jobs
+
+Use run_background_job for long-running processes (servers, watchers). Background jobs run in a fresh shell, not in the persistent one. Poll their progress with view_background_job and new_output_only. Output capped at 10MB per job. All jobs auto-terminate when the session ends or the agent stops.`
+	}
+
 	return `## Shell Tools
 
 - Each call runs in a fresh shell session — no state persists between calls
@@ -403,15 +471,20 @@ func (t *ShellTool) Instructions() string {
 
 ### Background Jobs
 
-Use run_background_job for long-running processes (servers, watchers). Output capped at 10MB per job. All jobs auto-terminate when the agent stops.`
+Use run_background_job for long-running processes (servers, watchers). Poll their progress with view_background_job and new_output_only. Output capped at 10MB per job. All jobs auto-terminate when the session ends or the agent stops.`
 }
 
 func (t *ShellTool) Tools(context.Context) ([]tools.Tool, error) {
-	return []tools.Tool{
+	description := `Executes the given shell command in the user's default shell.`
+	if t.handler.pty {
+		description = `Executes the given shell command in a persistent interactive shell. The working directory, variables and activated environments are preserved across calls.`
+	}
+
+	shellTools := []tools.Tool{
 		{
 			Name:                    ToolNameShell,
 			Category:                "shell",
-			Description:             `Executes the given shell command in the user's default shell.`,
+			Description:             description,
 			Parameters:              tools.MustSchemaFor[RunShellArgs](),
 			OutputSchema:            tools.MustSchemaFor[string](),
 			Handler:                 tools.NewHandler(t.handler.RunShell),
@@ -457,7 +530,20 @@ func (t *ShellTool) Tools(context.Context) ([]tools.Tool, error) {
 			Annotations:             tools.ToolAnnotations{Title: "Stop Background Job"},
 			AddDescriptionParameter: true,
 		},
-	}, nil
+	}
+
+	if t.handler.pty {
+		shellTools = append(shellTools, tools.Tool{
+			Name:         ToolNameViewShellTranscript,
+			Category:     "shell",
+			Description:  `Views the transcript of the persistent shell: the commands run and their outputs.`,
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handler.ViewShellTranscript),
+			Annotations:  tools.ToolAnnotations{Title: "View Shell Transcript", ReadOnlyHint: true},
+		})
+	}
+
+	return shellTools, nil
 }
 
 func (t *ShellTool) Start(context.Context) error {
@@ -465,13 +551,25 @@ func (t *ShellTool) Start(context.Context) error {
 }
 
 func (t *ShellTool) Stop(context.Context) error {
-	// Terminate all running background jobs
-	t.handler.jobs.Range(func(_ string, job *backgroundJob) bool {
+	t.handler.stopAllJobs()
+	t.handler.closePTYShells()
+	return nil
+}
+
+// EndSession terminates the background jobs left running by the session and
+// its persistent shell, and forgets about all its jobs.
+func (t *ShellTool) EndSession(ctx context.Context) {
+	t.handler.stopAllJobs()
+	t.handler.jobs.Clear()
+	t.handler.closePTYShell(tools.SessionID(ctx))
+}
+
+// stopAllJobs terminates all running background jobs.
+func (h *shellHandler) stopAllJobs() {
+	h.jobs.Range(func(_ string, job *backgroundJob) bool {
 		if job.status.CompareAndSwap(statusRunning, statusStopped) {
-			_ = kill(job.process, job.processGroup)
+			_ = job.process.Kill()
 		}
 		return true
 	})
-
-	return nil
 }
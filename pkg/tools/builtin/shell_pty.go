@@ -0,0 +1,387 @@
+package builtin
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/creack/pty"
+
+	"github.com/docker/docker-agent/pkg/childprocess"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const (
+	// maxTranscriptSize bounds the transcript of a PTY shell. The beginning
+	// of long transcripts is dropped.
+	maxTranscriptSize = 1024 * 1024
+
+	// ptyResyncTimeout is how long to wait for the shell to be responsive
+	// again after interrupting a command.
+	ptyResyncTimeout = 2 * time.Second
+
+	// ptyResyncInterval is how long to wait for the shell to answer a
+	// resync before asking again, in case the interrupted command read it.
+	ptyResyncInterval = 250 * time.Millisecond
+)
+
+var (
+	errPTYShellExited = errors.New("the shell exited")
+	// errPTYShellKilled is returned when the shell doesn't respond after a
+	// command is interrupted, and is killed.
+	errPTYShellKilled = errors.New("the shell didn't respond and was killed")
+)
+
+// ptyShell is a persistent interactive shell running in a pseudo-terminal.
+// Commands run one at a time, in the same shell process, so the working
+// directory, variables and activated environments persist between them.
+type ptyShell struct {
+	cmd  *exec.Cmd
+	proc *childprocess.Handle
+	pty  *os.File
+
+	// runMu serializes commands.
+	runMu sync.Mutex
+
+	mu         sync.Mutex
+	output     bytes.Buffer // output not consumed by a command yet
+	changed    chan struct{}
+	exited     bool
+	transcript bytes.Buffer
+}
+
+// startPTYShell starts shell in a pseudo-terminal, as an interactive shell.
+func startPTYShell(shell string, env []string, dir string) (*ptyShell, error) {
+	cmd := exec.Command(shell)
+	cmd.Env = append(env, "TERM=dumb", "PS1=", "PS2=", "PROMPT=", "RPROMPT=")
+	cmd.Dir = dir
+
+	f, err := pty.Start(cmd)
+	if errors.Is(err, pty.ErrUnsupported) {
+		return nil, errors.New("PTY shells are not supported on this platform")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The shell leads its own session, and so its own process group.
+	proc, err := childprocess.Track(cmd, childprocess.OwnerShell)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = f.Close()
+		return nil, err
+	}
+
+	s := &ptyShell{
+		cmd:     cmd,
+		proc:    proc,
+		pty:     f,
+		changed: make(chan struct{}),
+	}
+	go s.read()
+
+	// Don't echo commands, and clear the prompts the rc files may have set.
+	if _, err := s.exec(context.Background(), "stty -echo 2>/dev/null; PS1=''; PS2=''; PROMPT=''; RPROMPT=''; unset PROMPT_COMMAND", 10*time.Second); err != nil {
+		s.close()
+		return nil, fmt.Errorf("initializing the shell: %w", err)
+	}
+
+	return s, nil
+}
+
+// read copies the output of the shell until it exits.
+func (s *ptyShell) read() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := s.pty.Read(buf)
+
+		s.mu.Lock()
+		s.output.Write(buf[:n])
+		if err != nil {
+			s.exited = true
+		}
+		close(s.changed)
+		s.changed = make(chan struct{})
+		s.mu.Unlock()
+
+		if err != nil {
+			_ = s.cmd.Wait()
+			return
+		}
+	}
+}
+
+// ptyCommandResult is the result of a command run in a PTY shell.
+type ptyCommandResult struct {
+	output   string
+	exitCode int
+}
+
+// run runs a command, in dir if set, and returns its output once it's done.
+// Commands still running after timeout are interrupted.
+func (s *ptyShell) run(ctx context.Context, command, dir string, timeout time.Duration) (ptyCommandResult, error) {
+	if dir != "" {
+		command = "cd " + shellQuote(dir) + " && " + command
+	}
+
+	result, err := s.exec(ctx, command, timeout)
+
+	s.mu.Lock()
+	fmt.Fprintf(&s.transcript, "$ %s\n%s\n", command, result.output)
+	if err != nil {
+		fmt.Fprintf(&s.transcript, "[%s]\n", err)
+	}
+	if s.transcript.Len() > maxTranscriptSize {
+		s.transcript.Next(s.transcript.Len() - maxTranscriptSize)
+	}
+	s.mu.Unlock()
+
+	return result, err
+}
+
+// exec writes a command to the shell, followed by a command printing a
+// unique marker with the exit code, and waits for the marker.
+func (s *ptyShell) exec(ctx context.Context, command string, timeout time.Duration) (ptyCommandResult, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	s.mu.Lock()
+	s.output.Reset()
+	s.mu.Unlock()
+
+	marker, pattern := newPTYMarker()
+	if _, err := fmt.Fprintf(s.pty, "%s\nprintf '\\n%s_%%s\\n' \"$?\"\n", command, marker); err != nil {
+		return ptyCommandResult{}, errPTYShellExited
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := s.waitFor(timeoutCtx, pattern)
+	if err == nil || errors.Is(err, errPTYShellExited) {
+		return result, err
+	}
+
+	// Interrupt the command, which also discards the printf, and check that
+	// the shell is responsive again.
+	if !s.resync(context.WithoutCancel(ctx)) {
+		s.close()
+		return result, errPTYShellKilled
+	}
+
+	return result, err
+}
+
+// resync interrupts the running command and waits for the shell to run
+// commands again. The shell is asked to print a marker once the interrupt
+// produced some output, or after ptyResyncInterval, and again at each
+// interval, since the command may read the request before it stops.
+func (s *ptyShell) resync(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, ptyResyncTimeout)
+	defer cancel()
+
+	s.mu.Lock()
+	changed := s.changed
+	s.mu.Unlock()
+
+	_, _ = s.pty.Write([]byte{0x03})
+
+	select {
+	case <-changed:
+	case <-time.After(ptyResyncInterval):
+	case <-ctx.Done():
+		return false
+	}
+
+	marker, pattern := newPTYMarker()
+	for ctx.Err() == nil {
+		if _, err := fmt.Fprintf(s.pty, "\nprintf '\\n%s_%%s\\n' \"$?\"\n", marker); err != nil {
+			return false
+		}
+		attemptCtx, attemptCancel := context.WithTimeout(ctx, ptyResyncInterval)
+		_, err := s.waitFor(attemptCtx, pattern)
+		attemptCancel()
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, errPTYShellExited) {
+			return false
+		}
+	}
+	return false
+}
+
+// waitFor waits for the output to contain the marker matched by pattern and
+// returns the output before it. On error, it returns the output so far.
+func (s *ptyShell) waitFor(ctx context.Context, pattern *regexp.Regexp) (ptyCommandResult, error) {
+	for {
+		s.mu.Lock()
+		output := s.output.String()
+		exited := s.exited
+		changed := s.changed
+		s.mu.Unlock()
+
+		if loc := pattern.FindStringSubmatchIndex(output); loc != nil {
+			exitCode, _ := strconv.Atoi(output[loc[2]:loc[3]])
+			s.mu.Lock()
+			s.output.Next(loc[1])
+			s.mu.Unlock()
+			return ptyCommandResult{output: cleanPTYOutput(output[:loc[0]]), exitCode: exitCode}, nil
+		}
+		if exited {
+			return ptyCommandResult{output: cleanPTYOutput(output), exitCode: -1}, errPTYShellExited
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ptyCommandResult{output: cleanPTYOutput(output), exitCode: -1}, ctx.Err()
+		}
+	}
+}
+
+// Transcript returns the commands run in the shell and their outputs.
+func (s *ptyShell) Transcript() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transcript.String()
+}
+
+func (s *ptyShell) isExited() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+func (s *ptyShell) close() {
+	_ = s.proc.Kill()
+	_ = s.cmd.Process.Kill()
+	_ = s.pty.Close()
+}
+
+// newPTYMarker returns a unique marker and the pattern matching it, followed
+// by an exit code, as printed by printf. In the commands written to the
+// shell, the marker is followed by a format verb instead of an exit code, so
+// that commands echoed by the terminal never match.
+func newPTYMarker() (string, *regexp.Regexp) {
+	nonce := make([]byte, 8)
+	_, _ = rand.Read(nonce)
+	id := hex.EncodeToString(nonce)
+
+	marker := "__DOCKER_AGENT_" + id
+	return marker, regexp.MustCompile(`\r?\n?` + regexp.QuoteMeta(marker) + `_(-?\d+)\r?\n`)
+}
+
+// stalePTYMarker matches the markers of the resyncs the shell answered more
+// than once, after the first answer was read.
+var stalePTYMarker = regexp.MustCompile(`\r?\n?__DOCKER_AGENT_[0-9a-f]{16}_-?\d+\r?\n`)
+
+// cleanPTYOutput removes the carriage returns and escape sequences terminals
+// add, and the markers of earlier resyncs.
+func cleanPTYOutput(output string) string {
+	output = stalePTYMarker.ReplaceAllString(output, "")
+	output = ansi.Strip(output)
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	return strings.TrimSpace(output)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runPTY runs a command in the persistent shell of the session, starting it
+// if needed.
+func (h *shellHandler) runPTY(ctx context.Context, params RunShellArgs, timeout time.Duration) *tools.ToolCallResult {
+	sessionID := tools.SessionID(ctx)
+
+	h.ptyMu.Lock()
+	s := h.ptyShells[sessionID]
+	if s == nil || s.isExited() {
+		var err error
+		s, err = startPTYShell(h.shell, h.env, h.workingDir)
+		if err != nil {
+			h.ptyMu.Unlock()
+			return tools.ResultError(fmt.Sprintf("Error starting shell: %s", err))
+		}
+		if h.ptyShells == nil {
+			h.ptyShells = map[string]*ptyShell{}
+		}
+		h.ptyShells[sessionID] = s
+	}
+	h.ptyMu.Unlock()
+
+	var dir string
+	if params.Cwd != "" && params.Cwd != "." {
+		dir = h.resolveWorkDir(params.Cwd)
+	}
+
+	slog.Debug("Executing command in PTY shell", "command", params.Cmd, "cwd", dir, "session_id", sessionID)
+
+	result, err := s.run(ctx, params.Cmd, dir, timeout)
+	output := cmp.Or(result.output, "<no output>")
+	switch {
+	case errors.Is(err, errPTYShellExited):
+		return tools.ResultSuccess(limitOutput(output + "\n\n[The shell exited. The next command starts a new shell.]"))
+	case errors.Is(err, errPTYShellKilled):
+		return tools.ResultSuccess(limitOutput(fmt.Sprintf("Command timed out after %v\nOutput: %s\n\n[The shell didn't respond to the interrupt and was killed. The next command starts a new shell.]", timeout, output)))
+	case ctx.Err() != nil:
+		return tools.ResultSuccess("Command cancelled")
+	case err != nil:
+		return tools.ResultSuccess(limitOutput(fmt.Sprintf("Command timed out after %v and was interrupted\nOutput: %s", timeout, output)))
+	case result.exitCode != 0:
+		return tools.ResultSuccess(limitOutput(fmt.Sprintf("Exit code %d\nOutput: %s", result.exitCode, output)))
+	default:
+		return tools.ResultSuccess(limitOutput(output))
+	}
+}
+
+// ViewShellTranscript returns the commands run in the persistent shell of
+// the session and their outputs.
+func (h *shellHandler) ViewShellTranscript(ctx context.Context, _ map[string]any) (*tools.ToolCallResult, error) {
+	h.ptyMu.Lock()
+	s := h.ptyShells[tools.SessionID(ctx)]
+	h.ptyMu.Unlock()
+
+	if s == nil {
+		return tools.ResultSuccess("<no commands run yet>"), nil
+	}
+	transcript := s.Transcript()
+	if len(transcript) > maxOutputSize {
+		transcript = "[Transcript truncated to its last 30,000 characters]\n\n" + transcript[len(transcript)-maxOutputSize:]
+	}
+	return tools.ResultSuccess(cmp.Or(transcript, "<no commands run yet>")), nil
+}
+
+// closePTYShell terminates the persistent shell of a session, if any.
+func (h *shellHandler) closePTYShell(sessionID string) {
+	h.ptyMu.Lock()
+	defer h.ptyMu.Unlock()
+
+	if s, ok := h.ptyShells[sessionID]; ok {
+		s.close()
+		delete(h.ptyShells, sessionID)
+	}
+}
+
+// closePTYShells terminates the persistent shells of all the sessions.
+func (h *shellHandler) closePTYShells() {
+	h.ptyMu.Lock()
+	defer h.ptyMu.Unlock()
+
+	for sessionID, s := range h.ptyShells {
+		s.close()
+		delete(h.ptyShells, sessionID)
+	}
+}
@@ -0,0 +1,132 @@
+//go:build !windows
+
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func newPTYShellTool(t *testing.T) *ShellTool {
+	t.Helper()
+
+	t.Setenv("SHELL", "/bin/sh")
+	tool := NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: t.TempDir()}})
+	tool.EnablePTY()
+	t.Cleanup(func() {
+		_ = tool.Stop(t.Context())
+	})
+	return tool
+}
+
+func TestShellTool_PTYPersistsState(t *testing.T) {
+	tool := newPTYShellTool(t)
+	subdir := t.TempDir()
+
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "export GREETING=hello && cd " + subdir})
+	require.NoError(t, err)
+	assert.Equal(t, "<no output>", result.Output)
+
+	result, err = tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo $GREETING; pwd"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n"+subdir, result.Output)
+}
+
+func TestShellTool_PTYExitCode(t *testing.T) {
+	tool := newPTYShellTool(t)
+
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo oops; false"})
+	require.NoError(t, err)
+	assert.Equal(t, "Exit code 1\nOutput: oops", result.Output)
+}
+
+func TestShellTool_PTYTimeout(t *testing.T) {
+	tool := newPTYShellTool(t)
+
+	start := time.Now()
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "export KEPT=yes; sleep 30", Timeout: 1})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "Command timed out after 1s")
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	// The shell survives the interrupt.
+	result, err = tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo $KEPT"})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", result.Output)
+}
+
+func TestShellTool_PTYTimeoutReadingInput(t *testing.T) {
+	tool := newPTYShellTool(t)
+
+	// The interrupted command may read the first resync request.
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "export KEPT=yes; cat", Timeout: 1})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "Command timed out after 1s")
+
+	result, err = tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo $KEPT"})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", result.Output)
+}
+
+func TestShellTool_PTYSessions(t *testing.T) {
+	tool := newPTYShellTool(t)
+	alice := tools.WithSessionID(t.Context(), "alice")
+	bob := tools.WithSessionID(t.Context(), "bob")
+
+	_, err := tool.handler.RunShell(alice, RunShellArgs{Cmd: "export NAME=alice"})
+	require.NoError(t, err)
+
+	result, err := tool.handler.RunShell(bob, RunShellArgs{Cmd: "echo ${NAME:-none}"})
+	require.NoError(t, err)
+	assert.Equal(t, "none", result.Output)
+
+	// Ending a session only closes its own shell.
+	tool.EndSession(bob)
+	result, err = tool.handler.RunShell(alice, RunShellArgs{Cmd: "echo $NAME"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", result.Output)
+}
+
+func TestShellTool_PTYExit(t *testing.T) {
+	tool := newPTYShellTool(t)
+
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "export GONE=yes; exit"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "The shell exited")
+
+	result, err = tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo ${GONE:-fresh}"})
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", result.Output)
+}
+
+func TestShellTool_PTYTranscriptAndEndSession(t *testing.T) {
+	tool := newPTYShellTool(t)
+
+	_, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "export NAME=agent; echo hi $NAME"})
+	require.NoError(t, err)
+
+	transcript, err := tool.handler.ViewShellTranscript(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "$ export NAME=agent; echo hi $NAME\nhi agent\n", transcript.Output)
+
+	tool.EndSession(t.Context())
+
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo ${NAME:-none}"})
+	require.NoError(t, err)
+	assert.Equal(t, "none", result.Output)
+}
+
+func TestShellTool_PTYTools(t *testing.T) {
+	tool := newPTYShellTool(t)
+
+	allTools, err := tool.Tools(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ToolNameViewShellTranscript, allTools[len(allTools)-1].Name)
+	assert.Contains(t, tool.Instructions(), "persistent shell")
+}
@@ -2,7 +2,9 @@ package builtin
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -123,6 +125,65 @@ func TestShellTool_ListBackgroundJobs(t *testing.T) {
 	assert.Contains(t, listResult.Output, "ID: job_")
 }
 
+func TestShellTool_ViewBackgroundJobNewOutputOnly(t *testing.T) {
+	tool := NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: t.TempDir()}})
+	t.Cleanup(func() {
+		_ = tool.Stop(t.Context())
+	})
+
+	_, err := tool.handler.RunShellBackground(t.Context(), RunShellBackgroundArgs{Cmd: "echo first; sleep 0.5; echo second; sleep 30"})
+	require.NoError(t, err)
+	jobID := onlyJobID(t, tool)
+
+	view := func() string {
+		result, err := tool.handler.ViewBackgroundJob(t.Context(), ViewBackgroundJobArgs{JobID: jobID, NewOutputOnly: true})
+		require.NoError(t, err)
+		_, output, _ := strings.Cut(result.Output, "--- New Output ---")
+		return output
+	}
+
+	var output string
+	require.Eventually(t, func() bool {
+		output = view()
+		return strings.Contains(output, "first")
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NotContains(t, output, "second")
+
+	require.Eventually(t, func() bool {
+		output = view()
+		return strings.Contains(output, "second")
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NotContains(t, output, "first")
+}
+
+func TestShellTool_EndSession(t *testing.T) {
+	tool := NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: t.TempDir()}})
+	t.Cleanup(func() {
+		_ = tool.Stop(t.Context())
+	})
+
+	_, err := tool.handler.RunShellBackground(t.Context(), RunShellBackgroundArgs{Cmd: "sleep 30"})
+	require.NoError(t, err)
+	job, _ := tool.handler.jobs.Load(onlyJobID(t, tool))
+
+	tool.EndSession(t.Context())
+
+	assert.Equal(t, statusStopped, job.status.Load())
+	assert.Equal(t, 0, tool.handler.jobs.Length())
+}
+
+func onlyJobID(t *testing.T, tool *ShellTool) string {
+	t.Helper()
+
+	var ids []string
+	tool.handler.jobs.Range(func(id string, _ *backgroundJob) bool {
+		ids = append(ids, id)
+		return true
+	})
+	require.Len(t, ids, 1)
+	return ids[0]
+}
+
 func TestShellTool_Instructions(t *testing.T) {
 	t.Parallel()
 
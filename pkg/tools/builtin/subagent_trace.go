@@ -0,0 +1,63 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const ToolNameGetSubAgentTrace = "get_subagent_trace"
+
+// Modes of the get_subagent_trace tool.
+const (
+	SubAgentTraceModeSummary = "summary"
+	SubAgentTraceModeRaw     = "raw"
+)
+
+// SubAgentTraceTool lets an agent read the transcript of the sub-sessions of
+// the agents it delegated to, to audit how they reached their answers. The
+// tool is handled by the runtime.
+type SubAgentTraceTool struct{}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet      = (*SubAgentTraceTool)(nil)
+	_ tools.Instructable = (*SubAgentTraceTool)(nil)
+)
+
+// GetSubAgentTraceArgs are the arguments for the get_subagent_trace tool.
+type GetSubAgentTraceArgs struct {
+	SessionID string `json:"session_id,omitempty" jsonschema:"The ID of the sub-session, given in the result of transfer_task. Defaults to the last sub-agent that finished."`
+	Mode      string `json:"mode,omitempty" jsonschema:"summary (default) for the messages and tool calls with shortened tool results, or raw for the complete transcript"`
+	MaxTokens int    `json:"max_tokens,omitempty" jsonschema:"Maximum size of the trace in tokens. Defaults to 4000, at most 16000."`
+}
+
+// NewSubAgentTraceTool creates a new SubAgentTraceTool.
+func NewSubAgentTraceTool() *SubAgentTraceTool {
+	return &SubAgentTraceTool{}
+}
+
+func (t *SubAgentTraceTool) Instructions() string {
+	return `## Sub-agent Traces
+
+The results of transfer_task only hold the final answer of the sub-agent. When
+you need to check how it reached its conclusion (which files it read, which
+commands it ran, what it assumed), call get_subagent_trace with the session_id
+given in the result. Start with the summary mode, and only ask for the raw
+transcript when you need the complete tool results.`
+}
+
+func (t *SubAgentTraceTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:        ToolNameGetSubAgentTrace,
+			Category:    "transfer",
+			Description: "Get the transcript of a task a sub-agent completed: its messages, tool calls and tool results, summarized or raw, capped to a number of tokens.",
+			Parameters:  tools.MustSchemaFor[GetSubAgentTraceArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Get Sub-agent Trace",
+			},
+		},
+	}, nil
+}
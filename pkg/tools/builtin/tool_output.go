@@ -0,0 +1,47 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const ToolNameGetMoreOutput = "get_more_output"
+
+// ToolOutputTool lets agents page through tool outputs that were truncated,
+// or summarized, because they were too long. The runtime handles the calls,
+// since it keeps the original outputs.
+type ToolOutputTool struct{}
+
+var (
+	_ tools.ToolSet      = (*ToolOutputTool)(nil)
+	_ tools.Instructable = (*ToolOutputTool)(nil)
+)
+
+type GetMoreOutputArgs struct {
+	Cursor string `json:"cursor" jsonschema:"The cursor given in the truncated or summarized tool output, or at the end of the previous page."`
+}
+
+func NewToolOutputTool() *ToolOutputTool {
+	return &ToolOutputTool{}
+}
+
+func (t *ToolOutputTool) Instructions() string {
+	return "## Long Tool Outputs\n\n" +
+		"Long tool outputs are truncated, or summarized, to save context. Only call `" + ToolNameGetMoreOutput + "` with the cursor they mention when the missing part is needed to complete the task."
+}
+
+func (t *ToolOutputTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:        ToolNameGetMoreOutput,
+			Category:    "tool_output",
+			Description: "Read the next page of a tool output that was truncated or summarized because it was too long.",
+			Parameters:  tools.MustSchemaFor[GetMoreOutputArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Get More Output",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,40 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const ToolNameSearchTools = "search_tools"
+
+// ToolSearchTool lets agents with many tools find the tools they need. Their
+// tools are hidden until found, so that hundreds of tool definitions aren't
+// sent with every request. The runtime handles the calls, since it decides
+// which tools are sent.
+type ToolSearchTool struct{}
+
+var _ tools.ToolSet = (*ToolSearchTool)(nil)
+
+type SearchToolsArgs struct {
+	Query string `json:"query" jsonschema:"Keywords describing the tools to find, e.g. \"create github issue\" or \"query database\"."`
+}
+
+func NewToolSearchTool() *ToolSearchTool {
+	return &ToolSearchTool{}
+}
+
+func (t *ToolSearchTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:        ToolNameSearchTools,
+			Category:    "tool_search",
+			Description: "Search for tools by keywords. Only some of the tools are available at first: when none of them fits the task, search for the right one. The tools found are available from the next step on.",
+			Parameters:  tools.MustSchemaFor[SearchToolsArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Search Tools",
+			},
+		},
+	}, nil
+}
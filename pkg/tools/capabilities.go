@@ -1,6 +1,9 @@
 package tools
 
-import "context"
+import (
+	"context"
+	"reflect"
+)
 
 // Startable is implemented by toolsets that require initialization before use.
 // Toolsets that don't implement this interface are assumed to be ready immediately.
@@ -19,6 +22,29 @@ type Elicitable interface {
 	SetElicitationHandler(handler ElicitationHandler)
 }
 
+// SessionEnder is implemented by toolsets that hold resources belonging to
+// a session, such as the processes started by its tool calls, and release
+// them when the session ends.
+// The ID of the session is in the context, see SessionID.
+type SessionEnder interface {
+	EndSession(ctx context.Context)
+}
+
+type sessionIDKey struct{}
+
+// WithSessionID returns a context carrying the ID of the session a tool call,
+// or the end of a session, belongs to.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionID returns the ID of the session a tool call belongs to, or an empty
+// string if it's unknown.
+func SessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDKey{}).(string)
+	return sessionID
+}
+
 // OAuthCapable is implemented by toolsets that support OAuth flows.
 type OAuthCapable interface {
 	SetOAuthSuccessHandler(handler func())
@@ -37,7 +63,46 @@ func GetInstructions(ts ToolSet) string {
 // ChangeNotifier is implemented by toolsets that can notify when their
 // tool list changes (e.g. after an MCP ToolListChanged notification).
 type ChangeNotifier interface {
-	SetToolsChangedHandler(handler func())
+	SetToolsChangedHandler(handler func(change ToolsChange))
+}
+
+// ToolsChange lists the names of the tools added, removed or changed (in
+// their description, parameters or annotations) by a change of the tool list
+// of a toolset.
+type ToolsChange struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// IsEmpty returns whether no tool was added, removed or changed.
+func (c ToolsChange) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// DiffTools returns the change from the tools before to the tools after.
+func DiffTools(before, after []Tool) ToolsChange {
+	var change ToolsChange
+	previous := make(map[string]Tool, len(before))
+	for _, t := range before {
+		previous[t.Name] = t
+	}
+	for _, t := range after {
+		p, ok := previous[t.Name]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, t.Name)
+		case p.Description != t.Description || !reflect.DeepEqual(p.Parameters, t.Parameters) || !reflect.DeepEqual(p.Annotations, t.Annotations):
+			change.Changed = append(change.Changed, t.Name)
+		}
+		delete(previous, t.Name)
+	}
+	for _, t := range before {
+		if _, ok := previous[t.Name]; ok {
+			change.Removed = append(change.Removed, t.Name)
+		}
+	}
+	return change
 }
 
 // ConfigureHandlers sets all applicable handlers on a toolset.
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+// NewFunc turns a Go function into a tool. The JSON schema of the parameters
+// is generated from Args, whose fields are described with json and
+// jsonschema struct tags. A result of type string is returned as is, a
+// *ToolCallResult is returned unchanged and any other result is returned as
+// JSON, with an output schema generated from Result. Errors returned by fn
+// are reported to the model as failed tool calls.
+//
+// The returned tool can be customized, to set its annotations for example,
+// before it's added to a FuncToolSet.
+func NewFunc[Args, Result any](name, description string, fn func(context.Context, Args) (Result, error)) Tool {
+	tool := Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  MustSchemaFor[Args](),
+		Handler: NewHandler(func(ctx context.Context, args Args) (*ToolCallResult, error) {
+			result, err := fn(ctx, args)
+			if err != nil {
+				return nil, err
+			}
+			return funcResult(result), nil
+		}),
+	}
+
+	var zero Result
+	switch any(zero).(type) {
+	case string, *ToolCallResult:
+	default:
+		tool.OutputSchema = MustSchemaFor[Result]()
+	}
+	return tool
+}
+
+func funcResult(result any) *ToolCallResult {
+	switch r := result.(type) {
+	case string:
+		return ResultSuccess(r)
+	case *ToolCallResult:
+		if r == nil {
+			return ResultSuccess("")
+		}
+		return r
+	default:
+		return ResultJSON(r)
+	}
+}
+
+// FuncToolSet is a ToolSet made of tools created with NewFunc, for the
+// programs that use docker agent as a library.
+type FuncToolSet struct {
+	name         string
+	tools        []Tool
+	instructions string
+	start        func(context.Context) error
+	stop         func(context.Context) error
+}
+
+// Verify interface compliance
+var (
+	_ ToolSet      = (*FuncToolSet)(nil)
+	_ Startable    = (*FuncToolSet)(nil)
+	_ Instructable = (*FuncToolSet)(nil)
+	_ Describer    = (*FuncToolSet)(nil)
+)
+
+// NewFuncToolSet creates a toolset with the given tools. The name is used as
+// the category of the tools that don't have one.
+func NewFuncToolSet(name string, fns ...Tool) *FuncToolSet {
+	return &FuncToolSet{
+		name:  name,
+		tools: fns,
+	}
+}
+
+// WithInstructions sets the instructions added to the system prompt of the
+// agents using the toolset.
+func (s *FuncToolSet) WithInstructions(instructions string) *FuncToolSet {
+	s.instructions = instructions
+	return s
+}
+
+// OnStart sets a function called before the tools are first used, to open a
+// connection to a database for example. If it fails, the toolset is started
+// again before its tools are used next.
+func (s *FuncToolSet) OnStart(fn func(context.Context) error) *FuncToolSet {
+	s.start = fn
+	return s
+}
+
+// OnStop sets a function called when the toolset is stopped, to release what
+// was acquired by the OnStart function.
+func (s *FuncToolSet) OnStop(fn func(context.Context) error) *FuncToolSet {
+	s.stop = fn
+	return s
+}
+
+func (s *FuncToolSet) Tools(context.Context) ([]Tool, error) {
+	seen := map[string]bool{}
+	toolList := make([]Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		if tool.Name == "" {
+			return nil, fmt.Errorf("toolset %s: a tool has no name", s.name)
+		}
+		if tool.Handler == nil {
+			return nil, fmt.Errorf("toolset %s: tool %s has no handler", s.name, tool.Name)
+		}
+		if seen[tool.Name] {
+			return nil, fmt.Errorf("toolset %s: duplicate tool %s", s.name, tool.Name)
+		}
+		seen[tool.Name] = true
+
+		if tool.Category == "" {
+			tool.Category = s.name
+		}
+		toolList = append(toolList, tool)
+	}
+	return toolList, nil
+}
+
+func (s *FuncToolSet) Start(ctx context.Context) error {
+	if s.start == nil {
+		return nil
+	}
+	return s.start(ctx)
+}
+
+func (s *FuncToolSet) Stop(ctx context.Context) error {
+	if s.stop == nil {
+		return nil
+	}
+	return s.stop(ctx)
+}
+
+func (s *FuncToolSet) Instructions() string {
+	return s.instructions
+}
+
+func (s *FuncToolSet) Describe() string {
+	return "func(" + s.name + ")"
+}
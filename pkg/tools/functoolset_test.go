@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type addArgs struct {
+	A int `json:"a" jsonschema:"The first number"`
+	B int `json:"b" jsonschema:"The second number"`
+}
+
+type sum struct {
+	Sum int `json:"sum"`
+}
+
+func call(t *testing.T, tool Tool, arguments string) (*ToolCallResult, error) {
+	t.Helper()
+
+	return tool.Handler(t.Context(), ToolCall{
+		Function: FunctionCall{Name: tool.Name, Arguments: arguments},
+	})
+}
+
+func TestNewFunc_JSONResult(t *testing.T) {
+	tool := NewFunc("add", "Add two numbers", func(_ context.Context, args addArgs) (sum, error) {
+		return sum{Sum: args.A + args.B}, nil
+	})
+
+	assert.Equal(t, "add", tool.Name)
+	assert.Equal(t, "Add two numbers", tool.Description)
+	params, err := SchemaToMap(tool.Parameters)
+	require.NoError(t, err)
+	assert.Contains(t, params["properties"], "a")
+	assert.Contains(t, params["properties"], "b")
+	assert.NotNil(t, tool.OutputSchema)
+
+	result, err := call(t, tool, `{"a":1,"b":2}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sum":3}`, result.Output)
+	assert.Equal(t, map[string]any{"sum": float64(3)}, result.StructuredContent)
+	require.NoError(t, ValidateOutput(tool.OutputSchema, result.StructuredContent))
+}
+
+func TestNewFunc_StringResult(t *testing.T) {
+	tool := NewFunc("greet", "Greet someone", func(_ context.Context, args struct {
+		Name string `json:"name"`
+	},
+	) (string, error) {
+		return "hello " + args.Name, nil
+	})
+	assert.Nil(t, tool.OutputSchema)
+
+	result, err := call(t, tool, `{"name":"world"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", result.Output)
+	assert.False(t, result.IsError)
+}
+
+func TestNewFunc_ToolCallResult(t *testing.T) {
+	tool := NewFunc("fail", "Always fail", func(context.Context, struct{}) (*ToolCallResult, error) {
+		return ResultError("nope"), nil
+	})
+	assert.Nil(t, tool.OutputSchema)
+
+	result, err := call(t, tool, "")
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "nope", result.Output)
+}
+
+func TestNewFunc_Error(t *testing.T) {
+	tool := NewFunc("broken", "Always error", func(context.Context, struct{}) (string, error) {
+		return "", errors.New("boom")
+	})
+
+	_, err := call(t, tool, "{}")
+	require.EqualError(t, err, "boom")
+}
+
+func TestFuncToolSet_Tools(t *testing.T) {
+	add := NewFunc("add", "Add two numbers", func(_ context.Context, args addArgs) (sum, error) {
+		return sum{Sum: args.A + args.B}, nil
+	})
+	custom := NewFunc("custom", "Custom category", func(context.Context, struct{}) (string, error) {
+		return "", nil
+	})
+	custom.Category = "other"
+	custom.Annotations.ReadOnlyHint = true
+
+	ts := NewFuncToolSet("math", add, custom).WithInstructions("Use the math tools to compute.")
+
+	toolList, err := ts.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, toolList, 2)
+	assert.Equal(t, "math", toolList[0].Category)
+	assert.Equal(t, "other", toolList[1].Category)
+	assert.True(t, toolList[1].Annotations.ReadOnlyHint)
+
+	assert.Equal(t, "Use the math tools to compute.", GetInstructions(ts))
+	assert.Equal(t, "func(math)", DescribeToolSet(ts))
+}
+
+func TestFuncToolSet_InvalidTools(t *testing.T) {
+	tool := NewFunc("dup", "Duplicate", func(context.Context, struct{}) (string, error) {
+		return "", nil
+	})
+
+	_, err := NewFuncToolSet("dups", tool, tool).Tools(t.Context())
+	require.ErrorContains(t, err, "duplicate tool dup")
+
+	_, err = NewFuncToolSet("nohandler", Tool{Name: "empty"}).Tools(t.Context())
+	require.ErrorContains(t, err, "tool empty has no handler")
+}
+
+func TestFuncToolSet_Lifecycle(t *testing.T) {
+	var events []string
+	ts := NewFuncToolSet("db").
+		OnStart(func(context.Context) error {
+			events = append(events, "start")
+			return nil
+		}).
+		OnStop(func(context.Context) error {
+			events = append(events, "stop")
+			return nil
+		})
+
+	startable := NewStartable(ts)
+	require.NoError(t, startable.Start(t.Context()))
+	require.NoError(t, startable.Start(t.Context()))
+	require.NoError(t, startable.Stop(t.Context()))
+	assert.Equal(t, []string{"start", "stop"}, events)
+
+	// Without hooks, starting and stopping are no-ops.
+	require.NoError(t, NewFuncToolSet("none").Start(t.Context()))
+	require.NoError(t, NewFuncToolSet("none").Stop(t.Context()))
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Diagnosis reports the state of an MCP server, as checked by Diagnose.
+type Diagnosis struct {
+	ServerName      string
+	ServerVersion   string
+	ProtocolVersion string
+	Capabilities    []string
+	Tools           int
+	// Auth describes how the client authenticates with remote servers. It's
+	// empty for servers started with a command.
+	Auth          string
+	OAuthRequired bool
+	// Err is the error that prevented the server from starting or listing
+	// its tools, if any.
+	Err error
+	// Hints suggest how to fix common misconfigurations.
+	Hints []string
+}
+
+// Diagnose starts the MCP server, lists its tools and reports what it found.
+// Unlike Start, it reports servers that are unavailable instead of silently
+// skipping them, and it never starts an OAuth flow: servers that need one are
+// reported as such. The caller should stop the toolset afterwards.
+func (ts *Toolset) Diagnose(ctx context.Context) *Diagnosis {
+	d := &Diagnosis{}
+
+	var oauthRequired atomic.Bool
+	ts.mcpClient.SetElicitationHandler(func(_ context.Context, req *mcp.ElicitParams) (tools.ElicitationResult, error) {
+		if req.Meta["cagent/type"] == "oauth_flow" {
+			oauthRequired.Store(true)
+		}
+		return tools.ElicitationResult{Action: tools.ElicitationActionDecline}, nil
+	})
+	if c, ok := ts.mcpClient.(*remoteMCPClient); ok {
+		c.useStoredTokens()
+	}
+
+	ts.mu.Lock()
+	err := ts.doStart(ctx)
+	if err == nil {
+		ts.started = true
+	}
+	result := ts.initResult
+	ts.mu.Unlock()
+
+	if err == nil {
+		if result.ServerInfo != nil {
+			d.ServerName = result.ServerInfo.Name
+			d.ServerVersion = result.ServerInfo.Version
+		}
+		d.ProtocolVersion = result.ProtocolVersion
+		d.Capabilities = serverCapabilities(result.Capabilities)
+
+		var toolsList []tools.Tool
+		toolsList, err = ts.Tools(ctx)
+		d.Tools = len(toolsList)
+	}
+
+	d.Err = err
+	d.OAuthRequired = oauthRequired.Load()
+	if remote, ok := ts.mcpClient.(*remoteMCPClient); ok {
+		d.Auth = remoteAuth(remote, d.OAuthRequired)
+	}
+	d.Hints = ts.diagnosisHints(d)
+
+	return d
+}
+
+// serverCapabilities lists the capabilities advertised by a server.
+func serverCapabilities(capabilities *mcp.ServerCapabilities) []string {
+	if capabilities == nil {
+		return nil
+	}
+
+	var names []string
+	if capabilities.Tools != nil {
+		names = append(names, "tools")
+	}
+	if capabilities.Prompts != nil {
+		names = append(names, "prompts")
+	}
+	if capabilities.Resources != nil {
+		names = append(names, "resources")
+	}
+	if capabilities.Logging != nil {
+		names = append(names, "logging")
+	}
+	if capabilities.Completions != nil {
+		names = append(names, "completions")
+	}
+	return names
+}
+
+// remoteAuth describes how the client authenticates with a remote server.
+func remoteAuth(remote *remoteMCPClient, oauthRequired bool) string {
+	if oauthRequired {
+		return "OAuth authorization required"
+	}
+	for name := range remote.headers {
+		if strings.EqualFold(name, "Authorization") {
+			return "Authorization header"
+		}
+	}
+	return "none"
+}
+
+// diagnosisHints suggests how to fix the misconfigurations that show up in a
+// diagnosis.
+func (ts *Toolset) diagnosisHints(d *Diagnosis) []string {
+	var hints []string
+
+	if remote, ok := ts.mcpClient.(*remoteMCPClient); ok {
+		switch remote.transportType {
+		case "sse", "streamable", "streamable-http":
+		default:
+			hints = append(hints, fmt.Sprintf("Transport type %q is not supported. Set remote.transport_type to \"streamable\" or \"sse\".", remote.transportType))
+		}
+		for _, name := range slices.Sorted(maps.Keys(remote.headers)) {
+			if isEmptyHeader(remote.headers[name]) {
+				hints = append(hints, fmt.Sprintf("Header %q is empty. Check the environment variables it uses.", name))
+			}
+		}
+	}
+
+	err := d.Err
+	switch {
+	case err == nil:
+		if d.Tools == 0 {
+			hints = append(hints, "The server exposes no tools.")
+		}
+	case d.OAuthRequired:
+		hints = append(hints, "The server requires an OAuth authorization. Run the agent interactively to authorize it.")
+	case errors.Is(err, exec.ErrNotFound):
+		hints = append(hints, fmt.Sprintf("Command %q was not found. Install it, or fix the command of the toolset.", ts.logID))
+	case isStdio(ts.mcpClient):
+		hints = append(hints, "The server failed to start. Run its command in a terminal to see its errors.")
+	case errors.Is(err, errServerUnavailable):
+		hints = append(hints, "The server closed the connection during initialization.")
+	case strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "403") || strings.Contains(strings.ToLower(err.Error()), "unauthorized"):
+		hints = append(hints, "The server rejected the credentials. Check the headers of the toolset.")
+	}
+
+	return hints
+}
+
+func isStdio(client mcpClient) bool {
+	_, ok := client.(*stdioMCPClient)
+	return ok
+}
+
+// isEmptyHeader reports whether a header value is empty, or is only an
+// authentication scheme, as happens when the variable holding its token is
+// not set.
+func isEmptyHeader(value string) bool {
+	fields := strings.Fields(value)
+	return len(fields) == 0 || (len(fields) == 1 && (strings.EqualFold(fields[0], "Bearer") || strings.EqualFold(fields[0], "Basic")))
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	server := gomcp.NewServer(&gomcp.Implementation{Name: "test-server", Version: "1.2.3"}, nil)
+	gomcp.AddTool(server, &gomcp.Tool{Name: "echo"}, func(context.Context, *gomcp.CallToolRequest, struct{}) (*gomcp.CallToolResult, any, error) {
+		return &gomcp.CallToolResult{}, nil, nil
+	})
+	httpServer := httptest.NewServer(gomcp.NewStreamableHTTPHandler(func(*http.Request) *gomcp.Server { return server }, nil))
+	t.Cleanup(httpServer.Close)
+
+	ts := NewRemoteToolset("test", httpServer.URL, "streamable", nil)
+	t.Cleanup(func() { _ = ts.Stop(t.Context()) })
+
+	d := ts.Diagnose(t.Context())
+	require.NoError(t, d.Err)
+	assert.Equal(t, "test-server", d.ServerName)
+	assert.Equal(t, "1.2.3", d.ServerVersion)
+	assert.NotEmpty(t, d.ProtocolVersion)
+	assert.Contains(t, d.Capabilities, "tools")
+	assert.Equal(t, 1, d.Tools)
+	assert.Equal(t, "none", d.Auth)
+	assert.False(t, d.OAuthRequired)
+	assert.Empty(t, d.Hints)
+}
+
+func TestDiagnose_OAuthRequired(t *testing.T) {
+	t.Parallel()
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mcp" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="test"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(httpServer.Close)
+
+	ts := NewRemoteToolset("test", httpServer.URL+"/mcp", "streamable", nil)
+	t.Cleanup(func() { _ = ts.Stop(t.Context()) })
+
+	d := ts.Diagnose(t.Context())
+	require.Error(t, d.Err)
+	assert.True(t, d.OAuthRequired)
+	assert.Equal(t, "OAuth authorization required", d.Auth)
+	assert.Contains(t, d.Hints[0], "OAuth")
+}
+
+func TestDiagnose_RemoteMisconfigurations(t *testing.T) {
+	t.Parallel()
+
+	ts := NewRemoteToolset("test", "http://127.0.0.1:1/mcp", "", map[string]string{"Authorization": "Bearer "})
+	t.Cleanup(func() { _ = ts.Stop(t.Context()) })
+
+	d := ts.Diagnose(t.Context())
+	require.Error(t, d.Err)
+	assert.Equal(t, "Authorization header", d.Auth)
+	assert.Equal(t, []string{
+		`Transport type "" is not supported. Set remote.transport_type to "streamable" or "sse".`,
+		`Header "Authorization" is empty. Check the environment variables it uses.`,
+	}, d.Hints)
+}
+
+func TestIsEmptyHeader(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isEmptyHeader(""))
+	assert.True(t, isEmptyHeader("  "))
+	assert.True(t, isEmptyHeader("Bearer "))
+	assert.True(t, isEmptyHeader("basic"))
+	assert.False(t, isEmptyHeader("Bearer token"))
+	assert.False(t, isEmptyHeader("token"))
+}
@@ -11,6 +11,7 @@ import (
 	"iter"
 	"log/slog"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -44,6 +45,7 @@ type Toolset struct {
 	logID        string
 	description  string // user-visible description, set by constructors
 	instructions string
+	initResult   *mcp.InitializeResult
 	mu           sync.Mutex
 	started      bool
 	stopping     bool // true when Stop() has been called
@@ -57,7 +59,17 @@ type Toolset struct {
 
 	// toolsChangedHandler is called after the tool cache is refreshed
 	// following a ToolListChanged notification from the server.
-	toolsChangedHandler func()
+	toolsChangedHandler func(tools.ToolsChange)
+
+	// listedTools is the last tool list fetched from the server, and
+	// pendingChange the changes of the list not reported yet.
+	listedTools   []tools.Tool
+	pendingChange tools.ToolsChange
+
+	// approveToolChanges makes the tools added or changed since the toolset
+	// first listed its tools always ask for the user's confirmation.
+	approveToolChanges bool
+	changedTools       map[string]bool
 }
 
 // invalidateCache clears the cached tools and prompts and bumps the
@@ -101,7 +113,7 @@ func NewRemoteToolset(name, urlString, transport string, headers map[string]stri
 	desc := buildRemoteDescription(urlString, transport)
 	return &Toolset{
 		name:        name,
-		mcpClient:   newRemoteClient(urlString, transport, headers, NewInMemoryTokenStore()),
+		mcpClient:   newRemoteClient(urlString, transport, headers, defaultTokenStore()),
 		logID:       urlString,
 		description: desc,
 	}
@@ -229,8 +241,8 @@ func (ts *Toolset) doStart(ctx context.Context) error {
 		// Only retry when initialization fails due to sending the initialized notification.
 		if !isInitNotificationSendError(err) {
 			if errors.Is(err, io.EOF) {
-				slog.Debug(
-					"MCP client unavailable (EOF), skipping MCP toolset",
+				slog.Warn(
+					"MCP server unavailable (EOF), its tools are disabled. Run `docker-agent mcp doctor` for details",
 					"server", ts.logID,
 				)
 				return errServerUnavailable
@@ -254,6 +266,7 @@ func (ts *Toolset) doStart(ctx context.Context) error {
 
 	slog.Debug("Started MCP toolset successfully", "server", ts.logID)
 	ts.instructions = result.Instructions
+	ts.initResult = result
 
 	return nil
 }
@@ -376,6 +389,7 @@ func (ts *Toolset) Tools(ctx context.Context) ([]tools.Tool, error) {
 	slog.Debug("Listed MCP tools", "count", len(toolsList), "server", ts.logID)
 
 	ts.mu.Lock()
+	ts.recordToolList(toolsList)
 	// Only populate the cache if no invalidation happened while we were
 	// fetching from the server. Otherwise drop the result so the next
 	// caller re-fetches with the latest data.
@@ -387,6 +401,35 @@ func (ts *Toolset) Tools(ctx context.Context) ([]tools.Tool, error) {
 	return toolsList, nil
 }
 
+// recordToolList records the changes from the previous tool list, to be
+// reported by refreshToolCache, and marks the added and changed tools as
+// always asking for confirmation when tool changes must be approved. The
+// caller must hold ts.mu.
+func (ts *Toolset) recordToolList(toolsList []tools.Tool) {
+	if ts.listedTools != nil {
+		change := tools.DiffTools(ts.listedTools, toolsList)
+		ts.pendingChange.Added = append(ts.pendingChange.Added, change.Added...)
+		ts.pendingChange.Removed = append(ts.pendingChange.Removed, change.Removed...)
+		ts.pendingChange.Changed = append(ts.pendingChange.Changed, change.Changed...)
+
+		if ts.approveToolChanges {
+			if ts.changedTools == nil {
+				ts.changedTools = make(map[string]bool)
+			}
+			for _, name := range slices.Concat(change.Added, change.Changed) {
+				ts.changedTools[name] = true
+			}
+		}
+	}
+	ts.listedTools = toolsList
+
+	for i := range toolsList {
+		if ts.changedTools[toolsList[i].Name] {
+			toolsList[i].AlwaysAsk = true
+		}
+	}
+}
+
 // refreshToolCache fetches the tool list from the server and populates the
 // cache. It is called by the ToolListChanged notification handler so that
 // the cache is already warm by the time the runtime loop calls Tools().
@@ -398,10 +441,15 @@ func (ts *Toolset) refreshToolCache(ctx context.Context) {
 
 	ts.mu.Lock()
 	handler := ts.toolsChangedHandler
+	change := ts.pendingChange
+	ts.pendingChange = tools.ToolsChange{}
 	ts.mu.Unlock()
 
+	if !change.IsEmpty() {
+		slog.Info("MCP server changed its tools", "server", ts.logID, "added", change.Added, "removed", change.Removed, "changed", change.Changed)
+	}
 	if handler != nil {
-		handler()
+		handler(change)
 	}
 }
 
@@ -541,12 +589,29 @@ func (ts *Toolset) SetManagedOAuth(managed bool) {
 	ts.mcpClient.SetManagedOAuth(managed)
 }
 
-func (ts *Toolset) SetToolsChangedHandler(handler func()) {
+// SetOAuthAccount selects the account whose OAuth token is used on a remote
+// MCP server. It has no effect on other servers.
+func (ts *Toolset) SetOAuthAccount(account string) {
+	if c, ok := ts.mcpClient.(*remoteMCPClient); ok {
+		c.SetOAuthAccount(account)
+	}
+}
+
+func (ts *Toolset) SetToolsChangedHandler(handler func(tools.ToolsChange)) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 	ts.toolsChangedHandler = handler
 }
 
+// SetApproveToolChanges makes the tools the server adds or changes after it
+// first listed its tools always ask for the user's confirmation, even with
+// --yolo or permissions that allow them.
+func (ts *Toolset) SetApproveToolChanges(approve bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.approveToolChanges = approve
+}
+
 // ListPrompts retrieves available prompts from the MCP server.
 // Returns a slice of PromptInfo containing metadata about each available prompt
 // including name, description, and argument specifications.
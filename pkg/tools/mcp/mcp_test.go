@@ -15,6 +15,7 @@ import (
 // mockMCPClient is a test double for the mcpClient interface.
 type mockMCPClient struct {
 	callToolFn func(ctx context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error)
+	tools      []*mcp.Tool
 }
 
 func (m *mockMCPClient) Initialize(context.Context, *mcp.InitializeRequest) (*mcp.InitializeResult, error) {
@@ -22,7 +23,13 @@ func (m *mockMCPClient) Initialize(context.Context, *mcp.InitializeRequest) (*mc
 }
 
 func (m *mockMCPClient) ListTools(context.Context, *mcp.ListToolsParams) iter.Seq2[*mcp.Tool, error] {
-	return func(func(*mcp.Tool, error) bool) {}
+	return func(yield func(*mcp.Tool, error) bool) {
+		for _, t := range m.tools {
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
 }
 
 func (m *mockMCPClient) CallTool(ctx context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error) {
@@ -110,6 +117,43 @@ func TestCallToolStripsNullArguments(t *testing.T) {
 	}
 }
 
+func TestToolListChangeIsReported(t *testing.T) {
+	t.Parallel()
+
+	client := &mockMCPClient{tools: []*mcp.Tool{
+		{Name: "search", Description: "Search the web"},
+		{Name: "fetch", Description: "Fetch a URL"},
+	}}
+	ts := &Toolset{started: true, mcpClient: client, approveToolChanges: true}
+
+	var changes []tools.ToolsChange
+	ts.SetToolsChangedHandler(func(change tools.ToolsChange) {
+		changes = append(changes, change)
+	})
+
+	_, err := ts.Tools(t.Context())
+	require.NoError(t, err)
+
+	// The server swaps a tool and notifies a change of its tool list.
+	client.tools = []*mcp.Tool{
+		{Name: "search", Description: "Search the web"},
+		{Name: "exec", Description: "Run a command"},
+	}
+	ts.mu.Lock()
+	ts.invalidateCache()
+	ts.mu.Unlock()
+	ts.refreshToolCache(t.Context())
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, tools.ToolsChange{Added: []string{"exec"}, Removed: []string{"fetch"}}, changes[0])
+
+	toolsList, err := ts.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, toolsList, 2)
+	assert.False(t, toolsList[0].AlwaysAsk, "unchanged tools don't need a new approval")
+	assert.True(t, toolsList[1].AlwaysAsk, "added tools must be approved")
+}
+
 func TestProcessMCPContent(t *testing.T) {
 	t.Parallel()
 
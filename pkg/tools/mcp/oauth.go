@@ -164,9 +164,42 @@ type oauthTransport struct {
 	client     *remoteMCPClient
 	tokenStore OAuthTokenStore
 	baseURL    string
+	account    string
 	managed    bool
 }
 
+// tokenKey is the key of the token of the server in the token store.
+func (t *oauthTransport) tokenKey() string {
+	return TokenKey(t.baseURL, t.account)
+}
+
+// validToken returns the stored token of the server, refreshed if it
+// expired, or nil.
+func (t *oauthTransport) validToken(ctx context.Context) *OAuthToken {
+	token, err := t.tokenStore.GetToken(t.tokenKey())
+	if err != nil {
+		return nil
+	}
+	if !token.IsExpired() {
+		return token
+	}
+	if token.RefreshToken == "" || token.TokenEndpoint == "" {
+		return nil
+	}
+
+	slog.Debug("Refreshing OAuth token", "url", t.baseURL)
+	refreshed, err := RefreshAccessToken(ctx, token)
+	if err != nil {
+		slog.Debug("Failed to refresh OAuth token", "url", t.baseURL, "error", err)
+		_ = t.tokenStore.RemoveToken(t.tokenKey())
+		return nil
+	}
+	if err := t.tokenStore.StoreToken(t.tokenKey(), refreshed); err != nil {
+		slog.Debug("Failed to store refreshed OAuth token", "url", t.baseURL, "error", err)
+	}
+	return refreshed
+}
+
 func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	var bodyBytes []byte
 	if req.Body != nil && req.Body != http.NoBody {
@@ -180,7 +213,7 @@ func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	reqClone := req.Clone(req.Context())
 
-	if token, err := t.tokenStore.GetToken(t.baseURL); err == nil && !token.IsExpired() {
+	if token := t.validToken(req.Context()); token != nil {
 		reqClone.Header.Set("Authorization", "Bearer "+token.AccessToken)
 	}
 
@@ -347,8 +380,11 @@ func (t *oauthTransport) handleManagedOAuthFlow(ctx context.Context, authServer,
 	if err != nil {
 		return fmt.Errorf("failed to exchange code for token: %w", err)
 	}
+	token.TokenEndpoint = authServerMetadata.TokenEndpoint
+	token.ClientID = clientID
+	token.ClientSecret = clientSecret
 
-	if err := t.tokenStore.StoreToken(t.baseURL, token); err != nil {
+	if err := t.tokenStore.StoreToken(t.tokenKey(), token); err != nil {
 		return fmt.Errorf("failed to store token: %w", err)
 	}
 
@@ -443,7 +479,7 @@ func (t *oauthTransport) handleUnmanagedOAuthFlow(ctx context.Context, authServe
 	if refreshToken, ok := tokenData["refresh_token"].(string); ok {
 		token.RefreshToken = refreshToken
 	}
-	if err := t.tokenStore.StoreToken(t.baseURL, token); err != nil {
+	if err := t.tokenStore.StoreToken(t.tokenKey(), token); err != nil {
 		return fmt.Errorf("failed to store token: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
@@ -82,6 +83,55 @@ func ExchangeCodeForToken(ctx context.Context, tokenEndpoint, code, codeVerifier
 	return &token, nil
 }
 
+// RefreshAccessToken gets a new access token with the refresh token of an
+// expired token. The new token keeps the refresh token if the server
+// doesn't rotate it.
+func RefreshAccessToken(ctx context.Context, token *OAuthToken) (*OAuthToken, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", token.RefreshToken)
+	data.Set("client_id", token.ClientID)
+	if token.ClientSecret != "" {
+		data.Set("client_secret", token.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, token.TokenEndpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var refreshed OAuthToken
+	if err := json.NewDecoder(resp.Body).Decode(&refreshed); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if refreshed.AccessToken == "" {
+		return nil, errors.New("token response missing access_token")
+	}
+
+	if refreshed.ExpiresIn > 0 {
+		refreshed.ExpiresAt = time.Now().Add(time.Duration(refreshed.ExpiresIn) * time.Second)
+	}
+	refreshed.RefreshToken = cmp.Or(refreshed.RefreshToken, token.RefreshToken)
+	refreshed.TokenEndpoint = token.TokenEndpoint
+	refreshed.ClientID = token.ClientID
+	refreshed.ClientSecret = token.ClientSecret
+
+	return &refreshed, nil
+}
+
 // RequestAuthorizationCode requests the user to open the authorization URL and waits for the callback
 func RequestAuthorizationCode(ctx context.Context, authURL string, callbackServer *CallbackServer, expectedState string) (string, string, error) {
 	if err := browser.Open(ctx, authURL); err != nil {
@@ -109,7 +159,7 @@ func RegisterClient(ctx context.Context, authMetadata *AuthorizationServerMetada
 	reqBody := map[string]any{
 		"redirect_uris": []string{redirectURI},
 		"client_name":   "cagent",
-		"grant_types":   []string{"authorization_code"},
+		"grant_types":   []string{"authorization_code", "refresh_token"},
 		"response_types": []string{
 			"code",
 		},
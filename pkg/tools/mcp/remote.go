@@ -17,7 +17,11 @@ type remoteMCPClient struct {
 	transportType string
 	headers       map[string]string
 	tokenStore    OAuthTokenStore
+	account       string
 	managed       bool
+	// clientTokens keeps the tokens obtained by the clients of the runtime
+	// when OAuth isn't managed by docker agent. They aren't persisted.
+	clientTokens OAuthTokenStore
 }
 
 func newRemoteClient(url, transportType string, headers map[string]string, tokenStore OAuthTokenStore) *remoteMCPClient {
@@ -32,6 +36,7 @@ func newRemoteClient(url, transportType string, headers map[string]string, token
 		transportType: transportType,
 		headers:       headers,
 		tokenStore:    tokenStore,
+		clientTokens:  NewInMemoryTokenStore(),
 	}
 }
 
@@ -93,18 +98,40 @@ func (c *remoteMCPClient) SetManagedOAuth(managed bool) {
 	c.mu.Unlock()
 }
 
+// useStoredTokens makes the client use the stored tokens without managing
+// OAuth, to check a server with the authorizations of the user.
+func (c *remoteMCPClient) useStoredTokens() {
+	c.mu.Lock()
+	c.clientTokens = c.tokenStore
+	c.mu.Unlock()
+}
+
+// SetOAuthAccount selects the account whose OAuth token is used, for users
+// with several accounts on the server.
+func (c *remoteMCPClient) SetOAuthAccount(account string) {
+	c.mu.Lock()
+	c.account = account
+	c.mu.Unlock()
+}
+
 // createHTTPClient creates an HTTP client with custom headers and OAuth support.
 // Header values may contain ${headers.NAME} placeholders that are resolved
 // at request time from upstream headers stored in the request context.
 func (c *remoteMCPClient) createHTTPClient() *http.Client {
 	transport := c.headerTransport()
 
+	tokenStore := c.tokenStore
+	if !c.managed {
+		tokenStore = c.clientTokens
+	}
+
 	// Then wrap with OAuth support
 	transport = &oauthTransport{
 		base:       transport,
 		client:     c,
-		tokenStore: c.tokenStore,
+		tokenStore: tokenStore,
 		baseURL:    c.url,
+		account:    c.account,
 		managed:    c.managed,
 	}
 
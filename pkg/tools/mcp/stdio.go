@@ -3,11 +3,14 @@ package mcp
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"os/exec"
 	"runtime"
+	"sync"
 
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/docker/docker-agent/pkg/childprocess"
 	"github.com/docker/docker-agent/pkg/desktop"
 )
 
@@ -17,6 +20,9 @@ type stdioMCPClient struct {
 	args    []string
 	env     []string
 	cwd     string
+
+	procMu sync.Mutex
+	proc   *childprocess.Handle
 }
 
 func newStdioCmdClient(command string, args, env []string, cwd string) *stdioMCPClient {
@@ -52,10 +58,15 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *gomcp.InitializeRequ
 	cmd := exec.CommandContext(ctx, c.command, c.args...)
 	cmd.Env = c.env
 	cmd.Dir = c.cwd
+	childprocess.Prepare(cmd)
 	session, err := client.Connect(ctx, &gomcp.CommandTransport{
 		Command: cmd,
 	}, nil)
+	if cmd.Process != nil {
+		c.track(cmd)
+	}
 	if err != nil {
+		c.killProcess()
 		return nil, err
 	}
 
@@ -63,3 +74,39 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *gomcp.InitializeRequ
 
 	return session.InitializeResult(), nil
 }
+
+// Close stops the server, and the processes it left behind.
+func (c *stdioMCPClient) Close(ctx context.Context) error {
+	err := c.sessionClient.Close(ctx)
+	c.killProcess()
+	return err
+}
+
+func (c *stdioMCPClient) track(cmd *exec.Cmd) {
+	proc, err := childprocess.Track(cmd, childprocess.OwnerMCP)
+	if err != nil {
+		slog.Debug("Failed to track MCP server", "command", c.command, "error", err)
+		return
+	}
+
+	c.procMu.Lock()
+	previous := c.proc
+	c.proc = proc
+	c.procMu.Unlock()
+
+	// The server was restarted.
+	if previous != nil {
+		_ = previous.Kill()
+	}
+}
+
+func (c *stdioMCPClient) killProcess() {
+	c.procMu.Lock()
+	proc := c.proc
+	c.proc = nil
+	c.procMu.Unlock()
+
+	if proc != nil {
+		_ = proc.Kill()
+	}
+}
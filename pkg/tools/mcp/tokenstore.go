@@ -1,12 +1,25 @@
 package mcp
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker-agent/pkg/concurrent"
+	"github.com/docker/docker-agent/pkg/paths"
+	"github.com/docker/docker-agent/pkg/secretkey"
 )
 
+// tokenKeyService is the name of the encryption key of the OAuth tokens in
+// the system keychain.
+const tokenKeyService = "docker-agent-mcp-oauth"
+
 // OAuthTokenStore manages OAuth tokens
 type OAuthTokenStore interface {
 	// GetToken retrieves a token for the given resource URL
@@ -24,6 +37,11 @@ type OAuthToken struct {
 	RefreshToken string    `json:"refresh_token,omitempty"`
 	Scope        string    `json:"scope,omitempty"`
 	ExpiresAt    time.Time `json:"expires_at"`
+
+	// TokenEndpoint, ClientID and ClientSecret are used to refresh the token.
+	TokenEndpoint string `json:"token_endpoint,omitempty"`
+	ClientID      string `json:"client_id,omitempty"`
+	ClientSecret  string `json:"client_secret,omitempty"`
 }
 
 // IsExpired checks if the token is expired
@@ -64,3 +82,162 @@ func (s *InMemoryTokenStore) RemoveToken(resourceURL string) error {
 	s.tokens.Delete(resourceURL)
 	return nil
 }
+
+// TokenKey returns the key of the token of an account on an MCP server, in
+// a token store. The default account uses the server URL.
+func TokenKey(serverURL, account string) string {
+	if account == "" {
+		return serverURL
+	}
+	// URLs can't contain spaces.
+	return serverURL + " " + account
+}
+
+// SplitTokenKey returns the server URL and the account of a token key.
+func SplitTokenKey(key string) (serverURL, account string) {
+	serverURL, account, _ = strings.Cut(key, " ")
+	return serverURL, account
+}
+
+// FileTokenStore implements OAuthTokenStore in an encrypted file, so that
+// OAuth authorizations survive restarts. The encryption key is kept in the
+// system keychain when available, or else in a file only readable by the user.
+type FileTokenStore struct {
+	mu      sync.Mutex
+	file    string
+	keyring secretkey.Keyring
+}
+
+// NewFileTokenStore creates a token store in the given directory.
+func NewFileTokenStore(dir string) *FileTokenStore {
+	return &FileTokenStore{
+		file:    filepath.Join(dir, "tokens.enc"),
+		keyring: secretkey.Default(tokenKeyService, "docker-agent MCP OAuth tokens", filepath.Join(dir, "tokens.key")),
+	}
+}
+
+// defaultTokenStore is the token store shared by the remote MCP servers.
+var defaultTokenStore = sync.OnceValue(func() OAuthTokenStore {
+	return NewFileTokenStore(filepath.Join(paths.GetDataDir(), "mcp-oauth"))
+})
+
+// DefaultTokenStore returns the store of the OAuth tokens of remote MCP
+// servers.
+func DefaultTokenStore() *FileTokenStore {
+	return defaultTokenStore().(*FileTokenStore)
+}
+
+func (s *FileTokenStore) GetToken(resourceURL string) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	token, ok := tokens[resourceURL]
+	if !ok {
+		return nil, fmt.Errorf("no token found for resource: %s", resourceURL)
+	}
+	return token, nil
+}
+
+func (s *FileTokenStore) StoreToken(resourceURL string, token *OAuthToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if errors.Is(err, errUnreadableTokens) {
+		// Tokens that can't be decrypted anymore are replaced. Tokens whose
+		// key can't be read, from a locked keychain for example, are kept.
+		slog.Debug("Resetting the OAuth token store", "file", s.file, "error", err)
+		tokens = map[string]*OAuthToken{}
+	} else if err != nil {
+		return err
+	}
+	tokens[resourceURL] = token
+	return s.save(tokens)
+}
+
+func (s *FileTokenStore) RemoveToken(resourceURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[resourceURL]; !ok {
+		return nil
+	}
+	delete(tokens, resourceURL)
+	return s.save(tokens)
+}
+
+// Tokens returns all the stored tokens, by key.
+func (s *FileTokenStore) Tokens() (map[string]*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// errUnreadableTokens is returned when the tokens file can't be decrypted or
+// decoded with the key of the keyring.
+var errUnreadableTokens = errors.New("unreadable OAuth tokens")
+
+func (s *FileTokenStore) load() (map[string]*OAuthToken, error) {
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*OAuthToken{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyring.Get()
+	if errors.Is(err, secretkey.ErrNoKey) {
+		return nil, fmt.Errorf("%w: the key is lost", errUnreadableTokens)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading the key of the OAuth tokens: %w", err)
+	}
+	plaintext, err := secretkey.Decrypt(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decrypting: %w", errUnreadableTokens, err)
+	}
+
+	var tokens map[string]*OAuthToken
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, fmt.Errorf("%w: decoding: %w", errUnreadableTokens, err)
+	}
+	if tokens == nil {
+		tokens = map[string]*OAuthToken{}
+	}
+	return tokens, nil
+}
+
+func (s *FileTokenStore) save(tokens map[string]*OAuthToken) error {
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	key, err := secretkey.GetOrGenerate(s.keyring)
+	if err != nil {
+		return fmt.Errorf("reading the key of the OAuth tokens: %w", err)
+	}
+	data, err := secretkey.Encrypt(key, plaintext)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.file), 0o700); err != nil {
+		return err
+	}
+	tmp := s.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.file)
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/secretkey"
+)
+
+func newTestFileTokenStore(dir string) *FileTokenStore {
+	return &FileTokenStore{
+		file:    filepath.Join(dir, "tokens.enc"),
+		keyring: secretkey.NewFileKeyring(filepath.Join(dir, "tokens.key")),
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := newTestFileTokenStore(dir)
+
+	_, err := store.GetToken("https://mcp.example.com")
+	require.Error(t, err)
+
+	token := &OAuthToken{AccessToken: "secret-token", RefreshToken: "refresh", ExpiresAt: time.Now().Add(time.Hour).Round(0)}
+	require.NoError(t, store.StoreToken(TokenKey("https://mcp.example.com", "work"), token))
+
+	// Tokens are encrypted, and survive restarts
+	data, err := os.ReadFile(filepath.Join(dir, "tokens.enc"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-token")
+
+	info, err := os.Stat(filepath.Join(dir, "tokens.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	got, err := newTestFileTokenStore(dir).GetToken(TokenKey("https://mcp.example.com", "work"))
+	require.NoError(t, err)
+	assert.Equal(t, token.AccessToken, got.AccessToken)
+	assert.True(t, token.ExpiresAt.Equal(got.ExpiresAt))
+
+	_, err = store.GetToken("https://mcp.example.com")
+	require.Error(t, err)
+
+	tokens, err := store.Tokens()
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	require.NoError(t, store.RemoveToken(TokenKey("https://mcp.example.com", "work")))
+	tokens, err = store.Tokens()
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
+func TestFileTokenStore_LostKey(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := newTestFileTokenStore(dir)
+	require.NoError(t, store.StoreToken("https://mcp.example.com", &OAuthToken{AccessToken: "old"}))
+	require.NoError(t, os.Remove(filepath.Join(dir, "tokens.key")))
+
+	_, err := store.GetToken("https://mcp.example.com")
+	require.Error(t, err)
+
+	// Tokens that can't be decrypted are replaced
+	require.NoError(t, store.StoreToken("https://other.example.com", &OAuthToken{AccessToken: "new"}))
+	tokens, err := store.Tokens()
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	assert.Equal(t, "new", tokens["https://other.example.com"].AccessToken)
+}
+
+func TestTokenKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "https://mcp.example.com", TokenKey("https://mcp.example.com", ""))
+
+	serverURL, account := SplitTokenKey(TokenKey("https://mcp.example.com", "work"))
+	assert.Equal(t, "https://mcp.example.com", serverURL)
+	assert.Equal(t, "work", account)
+
+	serverURL, account = SplitTokenKey("https://mcp.example.com")
+	assert.Equal(t, "https://mcp.example.com", serverURL)
+	assert.Empty(t, account)
+}
+
+func TestOAuthTransport_RefreshesExpiredTokens(t *testing.T) {
+	t.Parallel()
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.Form.Get("grant_type"))
+		assert.Equal(t, "refresh", r.Form.Get("refresh_token"))
+		assert.Equal(t, "client", r.Form.Get("client_id"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"fresh","token_type":"Bearer","expires_in":3600}`))
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	store := NewInMemoryTokenStore()
+	transport := &oauthTransport{tokenStore: store, baseURL: "https://mcp.example.com", account: "work"}
+	require.NoError(t, store.StoreToken(transport.tokenKey(), &OAuthToken{
+		AccessToken:   "stale",
+		RefreshToken:  "refresh",
+		ExpiresAt:     time.Now().Add(-time.Minute),
+		TokenEndpoint: tokenServer.URL,
+		ClientID:      "client",
+	}))
+
+	token := transport.validToken(t.Context())
+	require.NotNil(t, token)
+	assert.Equal(t, "fresh", token.AccessToken)
+	assert.Equal(t, "refresh", token.RefreshToken)
+	assert.False(t, token.IsExpired())
+
+	stored, err := store.GetToken(transport.tokenKey())
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", stored.AccessToken)
+}
+
+func TestOAuthTransport_DropsTokensThatCantBeRefreshed(t *testing.T) {
+	t.Parallel()
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	store := NewInMemoryTokenStore()
+	transport := &oauthTransport{tokenStore: store, baseURL: "https://mcp.example.com"}
+	require.NoError(t, store.StoreToken(transport.tokenKey(), &OAuthToken{
+		AccessToken:   "stale",
+		RefreshToken:  "revoked",
+		ExpiresAt:     time.Now().Add(-time.Minute),
+		TokenEndpoint: tokenServer.URL,
+	}))
+
+	assert.Nil(t, transport.validToken(t.Context()))
+	_, err := store.GetToken(transport.tokenKey())
+	require.Error(t, err)
+}
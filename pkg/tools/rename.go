@@ -0,0 +1,26 @@
+package tools
+
+import "context"
+
+// Namespacer can be implemented by a ToolSet to provide the prefix given to
+// its tools when their names conflict with the tools of another toolset.
+type Namespacer interface {
+	Namespace() string
+}
+
+// Rename returns a copy of tool with another name. The handler still gets
+// the calls with the original name, since toolsets like MCP servers look
+// their tools up by name.
+func Rename(tool Tool, name string) Tool {
+	originalName := tool.Name
+	handler := tool.Handler
+
+	tool.Name = name
+	if handler != nil {
+		tool.Handler = func(ctx context.Context, toolCall ToolCall) (*ToolCallResult, error) {
+			toolCall.Function.Name = originalName
+			return handler(ctx, toolCall)
+		}
+	}
+	return tool
+}
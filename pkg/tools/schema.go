@@ -2,6 +2,7 @@ package tools
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/google/jsonschema-go/jsonschema"
 )
@@ -97,3 +98,22 @@ func ConvertSchema(params, v any) error {
 
 	return json.Unmarshal(buf, v)
 }
+
+// ValidateOutput validates the structured content of a tool result against
+// the OutputSchema of the tool. content must be a JSON-decoded value.
+func ValidateOutput(outputSchema, content any) error {
+	buf, err := json.Marshal(outputSchema)
+	if err != nil {
+		return err
+	}
+
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(buf, &schema); err != nil {
+		return fmt.Errorf("invalid output schema: %w", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("invalid output schema: %w", err)
+	}
+	return resolved.Validate(content)
+}
@@ -186,3 +186,25 @@ func TestSchemaToMap_DeeplyNestedPropertyWithoutType(t *testing.T) {
 		},
 	}, m)
 }
+
+func TestValidateOutput(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		Name  string   `json:"name"`
+		Items []string `json:"items,omitempty"`
+	}
+
+	schema := MustSchemaFor[output]()
+	require.NoError(t, ValidateOutput(schema, map[string]any{"name": "test", "items": []any{"a"}}))
+	require.Error(t, ValidateOutput(schema, map[string]any{"items": []any{"a"}}))
+	require.Error(t, ValidateOutput(schema, map[string]any{"name": 42}))
+
+	// Schemas given as maps, like the ones of MCP tools
+	schemaMap := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"count": map[string]any{"type": "integer"}},
+	}
+	require.NoError(t, ValidateOutput(schemaMap, map[string]any{"count": float64(3)}))
+	require.Error(t, ValidateOutput(schemaMap, map[string]any{"count": "three"}))
+}
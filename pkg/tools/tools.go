@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"slices"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -56,6 +57,29 @@ type ImageContent = MediaContent
 // AudioContent is an alias kept for readability at call sites.
 type AudioContent = MediaContent
 
+// SearchResult is a citable search result returned by a tool.
+type SearchResult struct {
+	// Source identifies the result (URL, file path, document ID, ...).
+	Source string `json:"source"`
+	// Title is an optional human-readable title.
+	Title string `json:"title,omitempty"`
+	// Content holds the text passages of the result.
+	Content []string `json:"content"`
+}
+
+// Artifact is a file produced by a tool that should be kept as an output of
+// the session, such as a report, an image or a patch.
+type Artifact struct {
+	// Path is the path of the file, relative to the working directory of the
+	// session if it's not absolute.
+	Path string `json:"path"`
+	// Description optionally tells what the file contains.
+	Description string `json:"description,omitempty"`
+	// MimeType is the media type of the file. It's detected from the content
+	// of the file when empty.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
 type ToolCallResult struct {
 	Output  string `json:"output"`
 	IsError bool   `json:"isError,omitempty"`
@@ -64,10 +88,18 @@ type ToolCallResult struct {
 	Images []MediaContent `json:"images,omitempty"`
 	// Audios contains optional audio attachments returned by the tool.
 	Audios []MediaContent `json:"audios,omitempty"`
-	// StructuredContent holds optional structured output returned by an MCP
-	// tool whose definition includes an OutputSchema. When non-nil it is the
-	// JSON-decoded structured result from the server.
+	// StructuredContent holds optional structured output, alongside the text
+	// of Output: the structuredContent of MCP tools, or the value of built-in
+	// tools returning ResultJSON. When non-nil it is a JSON-decoded value and,
+	// if the tool has an OutputSchema, it's valid against it.
 	StructuredContent any `json:"structuredContent,omitempty"`
+	// SearchResults contains optional citable search results. Providers that
+	// support citations receive them as search result blocks instead of
+	// Output; other providers only see Output.
+	SearchResults []SearchResult `json:"searchResults,omitempty"`
+	// Artifacts contains the files the tool produced and registers as
+	// artifacts of the session.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
 }
 
 func ResultError(output string) *ToolCallResult {
@@ -84,14 +116,20 @@ func ResultSuccess(output string) *ToolCallResult {
 	}
 }
 
-// ResultJSON marshals v as JSON and returns it as a successful tool result.
-// If marshaling fails, it returns an error result.
+// ResultJSON marshals v as JSON and returns it as a successful tool result,
+// with v as its structured content. If marshaling fails, it returns an error
+// result.
 func ResultJSON(v any) *ToolCallResult {
 	data, err := json.Marshal(v)
 	if err != nil {
 		return ResultError(err.Error())
 	}
-	return &ToolCallResult{Output: string(data)}
+
+	var structured any
+	if err := json.Unmarshal(data, &structured); err != nil {
+		return ResultError(err.Error())
+	}
+	return &ToolCallResult{Output: string(data), StructuredContent: structured}
 }
 
 type ToolType string
@@ -108,6 +146,21 @@ type Tool struct {
 	// ModelOverride is the per-toolset model for the LLM turn that processes
 	// this tool's results. Set automatically from the toolset "model" field.
 	ModelOverride string `json:"-"`
+	// Provider is set on provider-native tools (e.g. Gemini's google_search)
+	// that the model provider runs server-side. They are only sent to models
+	// of that provider, which translate them into their own tool declarations.
+	Provider string `json:"-"`
+	// AlwaysAsk makes every call of the tool ask for the user's confirmation,
+	// even with --yolo or permissions that allow the tool.
+	AlwaysAsk bool `json:"-"`
+}
+
+// ForProvider returns the tools that can be sent to a model of the given
+// provider, dropping the provider-native tools of other providers.
+func ForProvider(ts []Tool, provider string) []Tool {
+	return slices.DeleteFunc(slices.Clone(ts), func(t Tool) bool {
+		return t.Provider != "" && t.Provider != provider
+	})
 }
 
 type ToolAnnotations mcp.ToolAnnotations
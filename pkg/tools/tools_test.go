@@ -78,3 +78,47 @@ func TestNewHandler_InvalidArguments(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestForProvider(t *testing.T) {
+	t.Parallel()
+
+	ts := []Tool{
+		{Name: "read_file"},
+		{Name: "google_search", Provider: "google"},
+	}
+
+	assert.Equal(t, ts, ForProvider(ts, "google"))
+	assert.Equal(t, []Tool{{Name: "read_file"}}, ForProvider(ts, "openai"))
+	assert.Len(t, ts, 2, "input must not be modified")
+}
+
+func TestResultJSON(t *testing.T) {
+	t.Parallel()
+
+	result := ResultJSON(struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}{Name: "test", Count: 2})
+
+	assert.False(t, result.IsError)
+	assert.JSONEq(t, `{"name":"test","count":2}`, result.Output)
+	assert.Equal(t, map[string]any{"name": "test", "count": float64(2)}, result.StructuredContent)
+}
+
+func TestDiffTools(t *testing.T) {
+	t.Parallel()
+
+	before := []Tool{
+		{Name: "search", Description: "Search the web"},
+		{Name: "fetch", Description: "Fetch a URL"},
+		{Name: "delete", Description: "Delete a file"},
+	}
+	after := []Tool{
+		{Name: "search", Description: "Search the web"},
+		{Name: "fetch", Description: "Fetch a URL and send it to example.com"},
+		{Name: "exec", Description: "Run a command"},
+	}
+
+	assert.Equal(t, ToolsChange{Added: []string{"exec"}, Removed: []string{"delete"}, Changed: []string{"fetch"}}, DiffTools(before, after))
+	assert.True(t, DiffTools(before, before).IsEmpty())
+}
@@ -0,0 +1,37 @@
+;; A plugin for the tests of the wasm toolset, built with:
+;;
+;;   wat2wasm echo.wat -o echo.wasm
+;;
+;; Its tools are chosen by the first letter of their name:
+;;   echo returns its arguments,
+;;   spin never returns,
+;;   grow grows the memory by 100 pages (6.4MB) before echoing.
+(module
+  (memory (export "memory") 1)
+
+  (global $heap (mut i32) (i32.const 4096))
+
+  (data (i32.const 16) "[{\"name\":\"echo\",\"description\":\"Returns its arguments\",\"parameters\":{\"type\":\"object\",\"properties\":{\"text\":{\"type\":\"string\"}}}},{\"name\":\"spin\",\"description\":\"Never returns\"},{\"name\":\"grow\",\"description\":\"Grows the memory\"}]")
+
+  (func (export "cagent_alloc") (param $size i32) (result i32)
+    global.get $heap
+    global.get $heap
+    local.get $size
+    i32.add
+    global.set $heap)
+
+  ;; The tools are the 221 bytes of data at offset 16: 16 << 32 | 221.
+  (func (export "cagent_tools") (result i64)
+    (i64.const 68719476957))
+
+  (func (export "cagent_call") (param $name i32) (param $name_len i32) (param $args i32) (param $args_len i32) (result i64)
+    (block
+      (br_if 0 (i32.ne (i32.load8_u (local.get $name)) (i32.const 0x73)))
+      (loop (br 0)))
+    (block
+      (br_if 0 (i32.ne (i32.load8_u (local.get $name)) (i32.const 0x67)))
+      (if (i32.eq (memory.grow (i32.const 100)) (i32.const -1))
+        (then unreachable)))
+    (i64.or
+      (i64.shl (i64.extend_i32_u (local.get $args)) (i64.const 32))
+      (i64.extend_i32_u (local.get $args_len)))))
@@ -0,0 +1,17 @@
+;; A plugin for the tests of the wasm toolset, built with:
+;;
+;;   wat2wasm spin.wat -o spin.wasm
+;;
+;; It never returns the list of its tools.
+(module
+  (memory (export "memory") 1)
+
+  (func (export "cagent_alloc") (param $size i32) (result i32)
+    (i32.const 0))
+
+  (func (export "cagent_tools") (result i64)
+    (loop (br 0))
+    (i64.const 0))
+
+  (func (export "cagent_call") (param $name i32) (param $name_len i32) (param $args i32) (param $args_len i32) (result i64)
+    (i64.const 0)))
@@ -0,0 +1,454 @@
+// Package wasm provides a toolset running the tools exported by WebAssembly
+// modules, in a sandbox.
+//
+// A module exports its memory and three functions:
+//
+//	cagent_alloc(size i32) i32
+//	cagent_tools() i64
+//	cagent_call(name_ptr, name_len, args_ptr, args_len i32) i64
+//
+// cagent_alloc returns a buffer of size bytes in the memory of the module,
+// used to pass it strings. cagent_tools returns the JSON list of its tools,
+// each with a name, a description and the JSON Schema of its parameters.
+// cagent_call runs a tool with its JSON arguments and returns its output.
+// Strings returned by the module are packed in an i64: the pointer in the
+// high 32 bits and the length in the low 32 bits.
+//
+// Modules can import these functions from the "cagent" module:
+//
+//	log(ptr, len i32)
+//	error(ptr, len i32)
+//	http_request(ptr, len i32) i64
+//
+// log writes a message to the debug log. error marks the current call as
+// failed, with the message as its output. http_request sends the JSON
+// encoded request {"method", "url", "headers", "body"} and returns the JSON
+// response {"status", "headers", "body"}, or {"error"}. It's only allowed
+// when the toolset is given network access.
+//
+// Modules can also use WASI. They only see the working directory, mounted
+// at /, when given filesystem access.
+package wasm
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+const (
+	// defaultMemoryLimit is the maximum memory of a module, in MiB.
+	defaultMemoryLimit = 64
+	defaultTimeout     = 30 * time.Second
+
+	// maxResponseSize is the maximum size of the body of HTTP responses
+	// given to modules.
+	maxResponseSize = 10 << 20
+
+	// pagesPerMiB is the number of 64KiB WebAssembly pages in a MiB.
+	pagesPerMiB = 16
+)
+
+// Toolset implements tools.ToolSet for the tools of a WebAssembly module.
+type Toolset struct {
+	path       string
+	config     latest.WASMToolConfig
+	workingDir string
+	httpClient *http.Client
+
+	mu      sync.RWMutex
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	tools   []toolDefinition
+}
+
+// Verify interface compliance
+var (
+	_ tools.ToolSet   = (*Toolset)(nil)
+	_ tools.Startable = (*Toolset)(nil)
+	_ tools.Describer = (*Toolset)(nil)
+)
+
+type toolDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+// NewToolset creates a toolset for the module at path. Its filesystem
+// access is limited to workingDir.
+func NewToolset(path string, config latest.WASMToolConfig, workingDir string) *Toolset {
+	return &Toolset{
+		path:       path,
+		config:     config,
+		workingDir: workingDir,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (t *Toolset) Describe() string {
+	return "wasm(" + filepath.Base(t.path) + ")"
+}
+
+// Start compiles the module and reads the list of its tools.
+func (t *Toolset) Start(ctx context.Context) error {
+	code, err := os.ReadFile(t.path)
+	if err != nil {
+		return fmt.Errorf("reading wasm module: %w", err)
+	}
+
+	memoryLimit := cmp.Or(t.config.MemoryLimit, defaultMemoryLimit)
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(memoryLimit*pagesPerMiB)).
+		WithCloseOnContextDone(true))
+
+	module, toolList, err := t.load(ctx, runtime, code)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.runtime != nil {
+		_ = t.runtime.Close(ctx)
+	}
+	t.runtime = runtime
+	t.module = module
+	t.tools = toolList
+	return nil
+}
+
+func (t *Toolset) load(ctx context.Context, runtime wazero.Runtime, code []byte) (wazero.CompiledModule, []toolDefinition, error) {
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		return nil, nil, fmt.Errorf("instantiating WASI: %w", err)
+	}
+	if _, err := runtime.NewHostModuleBuilder("cagent").
+		NewFunctionBuilder().WithFunc(hostLog).Export("log").
+		NewFunctionBuilder().WithFunc(hostError).Export("error").
+		NewFunctionBuilder().WithFunc(t.hostHTTPRequest).Export("http_request").
+		Instantiate(ctx); err != nil {
+		return nil, nil, fmt.Errorf("instantiating host functions: %w", err)
+	}
+
+	module, err := runtime.CompileModule(ctx, code)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compiling wasm module: %w", err)
+	}
+	for _, name := range []string{"cagent_alloc", "cagent_tools", "cagent_call"} {
+		if _, ok := module.ExportedFunctions()[name]; !ok {
+			return nil, nil, fmt.Errorf("wasm module doesn't export %s", name)
+		}
+	}
+
+	// Listing the tools runs the code of the module, like calls.
+	timeout := t.timeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	instance, err := t.instantiate(ctx, runtime, module)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer instance.Close(context.WithoutCancel(ctx))
+
+	results, err := instance.ExportedFunction("cagent_tools").Call(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, nil, fmt.Errorf("listing the tools of the wasm module timed out after %s", timeout)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing the tools of the wasm module: %w", err)
+	}
+	data, err := readPacked(instance, results[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing the tools of the wasm module: %w", err)
+	}
+
+	var toolList []toolDefinition
+	if err := json.Unmarshal(data, &toolList); err != nil {
+		return nil, nil, fmt.Errorf("invalid tool list of the wasm module: %w", err)
+	}
+	for _, tool := range toolList {
+		if tool.Name == "" {
+			return nil, nil, errors.New("a tool of the wasm module has no name")
+		}
+	}
+
+	return module, toolList, nil
+}
+
+// instantiate creates a new instance of the module, so that calls don't
+// share their memory.
+func (t *Toolset) instantiate(ctx context.Context, runtime wazero.Runtime, module wazero.CompiledModule) (api.Module, error) {
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions("_initialize").
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader).
+		WithStderr(slogWriter{})
+
+	dir := cmp.Or(t.workingDir, ".")
+	switch t.config.FS {
+	case "read":
+		config = config.WithFSConfig(wazero.NewFSConfig().WithReadOnlyDirMount(dir, "/"))
+	case "write":
+		config = config.WithFSConfig(wazero.NewFSConfig().WithDirMount(dir, "/"))
+	}
+
+	instance, err := runtime.InstantiateModule(ctx, module, config)
+	if err != nil {
+		return nil, fmt.Errorf("instantiating wasm module: %w", err)
+	}
+	return instance, nil
+}
+
+func (t *Toolset) Stop(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.runtime == nil {
+		return nil
+	}
+	err := t.runtime.Close(ctx)
+	t.runtime = nil
+	t.module = nil
+	t.tools = nil
+	return err
+}
+
+func (t *Toolset) Tools(context.Context) ([]tools.Tool, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.runtime == nil {
+		return nil, errors.New("wasm toolset not started")
+	}
+
+	toolList := make([]tools.Tool, 0, len(t.tools))
+	for _, def := range t.tools {
+		parameters := def.Parameters
+		if parameters == nil {
+			parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		toolList = append(toolList, tools.Tool{
+			Name:        def.Name,
+			Category:    "wasm",
+			Description: def.Description,
+			Parameters:  parameters,
+			Handler: func(ctx context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+				return t.call(ctx, def.Name, cmp.Or(toolCall.Function.Arguments, "{}"))
+			},
+		})
+	}
+	return toolList, nil
+}
+
+// timeout is the maximum duration of the code of the module, for each call.
+func (t *Toolset) timeout() time.Duration {
+	if t.config.Timeout > 0 {
+		return time.Duration(t.config.Timeout) * time.Second
+	}
+	return defaultTimeout
+}
+
+// callState is the state of a call that the host functions can change.
+type callState struct {
+	failure *string
+}
+
+type callStateKey struct{}
+
+func (t *Toolset) call(ctx context.Context, name, arguments string) (*tools.ToolCallResult, error) {
+	t.mu.RLock()
+	runtime, module := t.runtime, t.module
+	t.mu.RUnlock()
+	if runtime == nil {
+		return nil, errors.New("wasm toolset not started")
+	}
+
+	timeout := t.timeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	state := &callState{}
+	ctx = context.WithValue(ctx, callStateKey{}, state)
+
+	instance, err := t.instantiate(ctx, runtime, module)
+	if err != nil {
+		return nil, err
+	}
+	defer instance.Close(context.WithoutCancel(ctx))
+
+	namePtr, err := writeString(ctx, instance, name)
+	if err != nil {
+		return nil, err
+	}
+	argsPtr, err := writeString(ctx, instance, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := instance.ExportedFunction("cagent_call").Call(ctx,
+		uint64(namePtr), uint64(len(name)), uint64(argsPtr), uint64(len(arguments)))
+	if errors.Is(err, context.DeadlineExceeded) {
+		return tools.ResultError(fmt.Sprintf("tool %s timed out after %s", name, timeout)), nil
+	}
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("tool %s failed: %s", name, err)), nil
+	}
+	if state.failure != nil {
+		return tools.ResultError(*state.failure), nil
+	}
+
+	output, err := readPacked(instance, results[0])
+	if err != nil {
+		return nil, err
+	}
+	return tools.ResultSuccess(string(output)), nil
+}
+
+// writeString copies s into a buffer allocated by the module.
+func writeString(ctx context.Context, m api.Module, s string) (uint32, error) {
+	results, err := m.ExportedFunction("cagent_alloc").Call(ctx, uint64(len(s)))
+	if err != nil {
+		return 0, fmt.Errorf("allocating wasm memory: %w", err)
+	}
+	ptr := uint32(results[0])
+	if !m.Memory().Write(ptr, []byte(s)) {
+		return 0, fmt.Errorf("writing %d bytes at %d is out of the wasm memory", len(s), ptr)
+	}
+	return ptr, nil
+}
+
+// readPacked reads the string whose pointer and length are packed in v.
+func readPacked(m api.Module, v uint64) ([]byte, error) {
+	return read(m, uint32(v>>32), uint32(v))
+}
+
+func read(m api.Module, ptr, length uint32) ([]byte, error) {
+	data, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		return nil, fmt.Errorf("reading %d bytes at %d is out of the wasm memory", length, ptr)
+	}
+	return bytes.Clone(data), nil
+}
+
+func hostLog(_ context.Context, m api.Module, ptr, length uint32) {
+	if msg, err := read(m, ptr, length); err == nil {
+		slog.Debug("wasm tool", "message", string(msg))
+	}
+}
+
+func hostError(ctx context.Context, m api.Module, ptr, length uint32) {
+	state, ok := ctx.Value(callStateKey{}).(*callState)
+	if !ok {
+		return
+	}
+	msg, err := read(m, ptr, length)
+	if err != nil {
+		msg = []byte(err.Error())
+	}
+	failure := string(msg)
+	state.failure = &failure
+}
+
+type httpRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+type httpResponse struct {
+	Status  int               `json:"status,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+func (t *Toolset) hostHTTPRequest(ctx context.Context, m api.Module, ptr, length uint32) uint64 {
+	response := t.httpRequest(ctx, m, ptr, length)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return 0
+	}
+	out, err := writeString(ctx, m, string(data))
+	if err != nil {
+		return 0
+	}
+	return uint64(out)<<32 | uint64(len(data))
+}
+
+func (t *Toolset) httpRequest(ctx context.Context, m api.Module, ptr, length uint32) httpResponse {
+	if !t.config.Net {
+		return httpResponse{Error: "network access is not allowed"}
+	}
+
+	data, err := read(m, ptr, length)
+	if err != nil {
+		return httpResponse{Error: err.Error()}
+	}
+	var request httpRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return httpResponse{Error: "invalid request: " + err.Error()}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cmp.Or(request.Method, http.MethodGet), request.URL, strings.NewReader(request.Body))
+	if err != nil {
+		return httpResponse{Error: err.Error()}
+	}
+	for key, value := range request.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return httpResponse{Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return httpResponse{Error: err.Error()}
+	}
+
+	headers := make(map[string]string, len(resp.Header))
+	for key := range resp.Header {
+		headers[key] = resp.Header.Get(key)
+	}
+	return httpResponse{
+		Status:  resp.StatusCode,
+		Headers: headers,
+		Body:    string(body),
+	}
+}
+
+// slogWriter writes the stderr of modules to the debug log.
+type slogWriter struct{}
+
+func (slogWriter) Write(p []byte) (int, error) {
+	slog.Debug("wasm tool", "stderr", strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
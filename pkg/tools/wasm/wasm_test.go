@@ -0,0 +1,95 @@
+package wasm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func startToolset(t *testing.T, config latest.WASMToolConfig) map[string]tools.Tool {
+	t.Helper()
+
+	ts := NewToolset("testdata/echo.wasm", config, t.TempDir())
+	require.NoError(t, ts.Start(t.Context()))
+	t.Cleanup(func() { _ = ts.Stop(t.Context()) })
+
+	toolList, err := ts.Tools(t.Context())
+	require.NoError(t, err)
+
+	byName := map[string]tools.Tool{}
+	for _, tool := range toolList {
+		byName[tool.Name] = tool
+	}
+	return byName
+}
+
+func callTool(t *testing.T, tool tools.Tool, arguments string) *tools.ToolCallResult {
+	t.Helper()
+
+	result, err := tool.Handler(t.Context(), tools.ToolCall{
+		Function: tools.FunctionCall{Name: tool.Name, Arguments: arguments},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestToolsetListsTheToolsOfTheModule(t *testing.T) {
+	toolList := startToolset(t, latest.WASMToolConfig{})
+
+	require.Len(t, toolList, 3)
+	assert.Equal(t, "Returns its arguments", toolList["echo"].Description)
+	assert.Equal(t, "wasm", toolList["echo"].Category)
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"text": map[string]any{"type": "string"}},
+	}, toolList["echo"].Parameters)
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{}}, toolList["spin"].Parameters)
+}
+
+func TestToolsetCallsTheModule(t *testing.T) {
+	toolList := startToolset(t, latest.WASMToolConfig{})
+
+	result := callTool(t, toolList["echo"], `{"text":"hello"}`)
+	assert.False(t, result.IsError)
+	assert.JSONEq(t, `{"text":"hello"}`, result.Output)
+
+	result = callTool(t, toolList["echo"], "")
+	assert.Equal(t, "{}", result.Output)
+}
+
+func TestToolsetStopsCallsAfterTheTimeout(t *testing.T) {
+	toolList := startToolset(t, latest.WASMToolConfig{Timeout: 1})
+
+	result := callTool(t, toolList["spin"], "{}")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "timed out")
+}
+
+func TestToolsetStopsListingTheToolsAfterTheTimeout(t *testing.T) {
+	ts := NewToolset("testdata/spin.wasm", latest.WASMToolConfig{Timeout: 1}, t.TempDir())
+	err := ts.Start(t.Context())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestToolsetLimitsMemory(t *testing.T) {
+	toolList := startToolset(t, latest.WASMToolConfig{})
+	result := callTool(t, toolList["grow"], "{}")
+	assert.False(t, result.IsError)
+
+	toolList = startToolset(t, latest.WASMToolConfig{MemoryLimit: 1})
+	result = callTool(t, toolList["grow"], "{}")
+	assert.True(t, result.IsError)
+}
+
+func TestToolsetRequiresTheABI(t *testing.T) {
+	ts := NewToolset("testdata/missing.wasm", latest.WASMToolConfig{}, t.TempDir())
+	require.Error(t, ts.Start(t.Context()))
+
+	_, err := ts.Tools(t.Context())
+	require.Error(t, err)
+}
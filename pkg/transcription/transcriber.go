@@ -0,0 +1,298 @@
+// Package transcription turns the audio and video files attached to user
+// messages into text that every model can read. Files are transcribed by a
+// transcription-capable model, such as OpenAI's gpt-4o-mini-transcribe or a
+// Gemini model, and transcripts that are too long are summarized chunk by
+// chunk.
+//
+// Like promptcompression, it makes one-shot calls to the providers and is
+// independent of pkg/runtime.
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+)
+
+const (
+	// DefaultMaxLength is the length, in characters, from which a
+	// transcript is summarized rather than inlined.
+	DefaultMaxLength = 20_000
+
+	summaryPrompt = `You summarize a part of the transcript of an audio or video file attached to a message for an AI agent, because the whole transcript is too long to be given to the agent as-is.
+
+Keep who said what, decisions, action items, questions, names, numbers, dates and anything shown on screen. Keep the order of the discussion. Never invent anything. Return only the summary.`
+
+	transcriptionTimeout = 5 * time.Minute
+	summaryTimeout       = 2 * time.Minute
+)
+
+// ErrNoModel is returned for the files attached while no transcription model
+// is configured.
+var ErrNoModel = errors.New("no transcription model is configured")
+
+// Transcriber replaces the audio and video files attached to messages with
+// their transcript. Transcripts are cached, so each file is transcribed once.
+type Transcriber struct {
+	model        provider.MediaTranscriptionProvider
+	summaryModel provider.Provider
+	maxLength    int
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// New creates a Transcriber that transcribes files with model and summarizes
+// the transcripts longer than maxLength characters with summaryModel. A
+// non-positive maxLength uses the default. Without summaryModel, long
+// transcripts are truncated instead. Without model, files are replaced by a
+// note saying that they couldn't be transcribed.
+func New(model provider.MediaTranscriptionProvider, summaryModel provider.Provider, maxLength int) *Transcriber {
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLength
+	}
+	return &Transcriber{
+		model:        model,
+		summaryModel: summaryModel,
+		maxLength:    maxLength,
+		cache:        make(map[string]string),
+	}
+}
+
+// Result is the outcome of transcribing the files of a conversation.
+type Result struct {
+	// Messages is the conversation in which audio and video files are
+	// replaced with their transcript.
+	Messages []chat.Message
+	// Transcribed is the number of files transcribed by this call, as
+	// opposed to the ones found in the cache.
+	Transcribed int
+	// Errors are the reasons why files couldn't be transcribed by this
+	// call. Those files are replaced by a note.
+	Errors []error
+}
+
+// Transcribe returns a copy of messages in which the audio and video files
+// are replaced with text parts: their transcript, or a summary of it.
+func (t *Transcriber) Transcribe(ctx context.Context, messages []chat.Message) Result {
+	result := Result{Messages: messages}
+
+	copied := false
+	for i := range messages {
+		if !slices.ContainsFunc(messages[i].MultiContent, isMedia) {
+			continue
+		}
+
+		parts := slices.Clone(messages[i].MultiContent)
+		for j, part := range parts {
+			if !isMedia(part) {
+				continue
+			}
+			text, transcribed, err := t.text(ctx, part.File)
+			if ctx.Err() != nil {
+				return result
+			}
+			if transcribed {
+				result.Transcribed++
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("%s: %w", part.File.Path, err))
+			}
+			parts[j] = chat.MessagePart{Type: chat.MessagePartTypeText, Text: text}
+		}
+
+		if !copied {
+			result.Messages = slices.Clone(messages)
+			copied = true
+		}
+		result.Messages[i].MultiContent = parts
+	}
+	return result
+}
+
+func isMedia(part chat.MessagePart) bool {
+	return part.Type == chat.MessagePartTypeFile && part.File != nil && chat.IsMediaMimeType(part.File.MimeType)
+}
+
+// text returns the text replacing a file, from the cache if the file was
+// already transcribed. transcribed reports whether the file was transcribed
+// by this call.
+func (t *Transcriber) text(ctx context.Context, file *chat.MessageFile) (text string, transcribed bool, err error) {
+	key := file.Path
+	if fi, statErr := os.Stat(file.Path); statErr == nil {
+		key = fmt.Sprintf("%s:%d:%d", file.Path, fi.Size(), fi.ModTime().UnixNano())
+	}
+
+	t.mu.Lock()
+	cached, ok := t.cache[key]
+	t.mu.Unlock()
+	if ok {
+		return cached, false, nil
+	}
+
+	text, err = t.transcribe(ctx, file)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", false, err
+		}
+		// Don't retry on every turn.
+		text = fmt.Sprintf("<attached_file path=%q>\n[This %s file could not be transcribed: %v]\n</attached_file>", file.Path, mediaKind(file.MimeType), err)
+	}
+
+	t.mu.Lock()
+	t.cache[key] = text
+	t.mu.Unlock()
+	return text, err == nil, err
+}
+
+func (t *Transcriber) transcribe(ctx context.Context, file *chat.MessageFile) (string, error) {
+	if t.model == nil {
+		return "", ErrNoModel
+	}
+
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return "", fmt.Errorf("reading file: %w", err)
+	}
+
+	transcribeCtx, cancel := context.WithTimeout(ctx, transcriptionTimeout)
+	defer cancel()
+
+	slog.Debug("Transcribing attached file", "path", file.Path, "mime_type", file.MimeType, "model", t.model.ID())
+	transcript, err := t.model.TranscribeMedia(transcribeCtx, data, file.MimeType)
+	if err != nil {
+		return "", err
+	}
+	transcript = strings.TrimSpace(transcript)
+
+	if len(transcript) <= t.maxLength {
+		return fmt.Sprintf("<attached_file path=%q type=\"transcript\">\n%s\n</attached_file>", file.Path, transcript), nil
+	}
+
+	if t.summaryModel != nil {
+		summary, err := t.summarize(ctx, transcript)
+		if err == nil {
+			return fmt.Sprintf("<attached_file path=%q type=\"transcript summary\">\n[Summary of a %d-character transcript]\n\n%s\n</attached_file>", file.Path, len(transcript), summary), nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		slog.Warn("Failed to summarize transcript, truncating it", "path", file.Path, "model", t.summaryModel.ID(), "error", err)
+	}
+
+	headEnd := runeStart(transcript, t.maxLength/2)
+	tailStart := runeStart(transcript, len(transcript)-t.maxLength/2)
+	return fmt.Sprintf("<attached_file path=%q type=\"transcript\">\n%s\n\n[... %d characters omitted ...]\n\n%s\n</attached_file>",
+		file.Path, transcript[:headEnd], tailStart-headEnd, transcript[tailStart:]), nil
+}
+
+// summarize summarizes a long transcript in chunks of at most maxLength
+// characters, so that the summary of each chunk fits the context of small
+// models.
+func (t *Transcriber) summarize(ctx context.Context, transcript string) (string, error) {
+	chunks := splitChunks(transcript, t.maxLength)
+
+	var summary strings.Builder
+	for i, chunk := range chunks {
+		part, err := t.summarizeChunk(ctx, chunk, i+1, len(chunks))
+		if err != nil {
+			return "", err
+		}
+		if len(chunks) > 1 {
+			fmt.Fprintf(&summary, "Part %d of %d:\n", i+1, len(chunks))
+		}
+		summary.WriteString(part)
+		if i < len(chunks)-1 {
+			summary.WriteString("\n\n")
+		}
+	}
+	return summary.String(), nil
+}
+
+func (t *Transcriber) summarizeChunk(ctx context.Context, chunk string, n, total int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, summaryTimeout)
+	defer cancel()
+
+	model := provider.CloneWithOptions(ctx, t.summaryModel,
+		options.WithStructuredOutput(nil),
+		options.WithThinking(false),
+	)
+	stream, err := model.CreateChatCompletionStream(ctx, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: summaryPrompt},
+		{Role: chat.MessageRoleUser, Content: fmt.Sprintf("Part %d of %d of the transcript:\n\n%s", n, total, chunk)},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var out strings.Builder
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(response.Choices) > 0 {
+			out.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+
+	summary := strings.TrimSpace(out.String())
+	if summary == "" {
+		return "", fmt.Errorf("empty summary from model %q", t.summaryModel.ID())
+	}
+	return summary, nil
+}
+
+// splitChunks splits s into chunks of at most size bytes, preferably at the
+// end of a paragraph, a line or a word.
+func splitChunks(s string, size int) []string {
+	var chunks []string
+	for len(s) > size {
+		end := runeStart(s, size)
+		for _, sep := range []string{"\n\n", "\n", " "} {
+			if i := strings.LastIndex(s[:end], sep); i > size/2 {
+				end = i + len(sep)
+				break
+			}
+		}
+		chunks = append(chunks, strings.TrimSpace(s[:end]))
+		s = s[end:]
+	}
+	if s = strings.TrimSpace(s); s != "" {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// runeStart returns the start of the rune at byte offset i of s, so that s
+// is never cut in the middle of a character.
+func runeStart(s string, i int) int {
+	i = max(0, min(i, len(s)))
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+func mediaKind(mimeType string) string {
+	if strings.HasPrefix(mimeType, "video/") {
+		return "video"
+	}
+	return "audio"
+}
@@ -0,0 +1,158 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+type mockProvider struct {
+	transcript string
+	summary    string
+	err        error
+
+	transcriptions int
+	summaries      []string
+}
+
+func (p *mockProvider) ID() string { return "mock/model" }
+
+func (p *mockProvider) BaseConfig() base.Config { return base.Config{} }
+
+func (p *mockProvider) TranscribeMedia(_ context.Context, _ []byte, _ string) (string, error) {
+	p.transcriptions++
+	return p.transcript, p.err
+}
+
+func (p *mockProvider) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	p.summaries = append(p.summaries, messages[len(messages)-1].Content)
+	return &mockStream{content: p.summary}, nil
+}
+
+type mockStream struct {
+	content string
+	done    bool
+}
+
+func (s *mockStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.done {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.done = true
+	return chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.content}}},
+	}, nil
+}
+
+func (s *mockStream) Close() {}
+
+func conversation(t *testing.T) ([]chat.Message, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "meeting.mp3")
+	require.NoError(t, os.WriteFile(path, []byte("ID3"), 0o644))
+
+	return []chat.Message{{
+		Role:    chat.MessageRoleUser,
+		Content: "What was decided?",
+		MultiContent: []chat.MessagePart{
+			{Type: chat.MessagePartTypeText, Text: "What was decided?"},
+			{Type: chat.MessagePartTypeFile, File: &chat.MessageFile{Path: path, MimeType: "audio/mpeg"}},
+		},
+	}}, path
+}
+
+func TestTranscribe(t *testing.T) {
+	t.Parallel()
+
+	messages, path := conversation(t)
+	model := &mockProvider{transcript: "We ship on Friday."}
+	transcriber := New(model, nil, 0)
+
+	res := transcriber.Transcribe(t.Context(), messages)
+	require.Empty(t, res.Errors)
+	assert.Equal(t, 1, res.Transcribed)
+	assert.Equal(t, chat.MessagePart{
+		Type: chat.MessagePartTypeText,
+		Text: "<attached_file path=\"" + path + "\" type=\"transcript\">\nWe ship on Friday.\n</attached_file>",
+	}, res.Messages[0].MultiContent[1])
+
+	// The conversation isn't modified.
+	assert.Equal(t, chat.MessagePartTypeFile, messages[0].MultiContent[1].Type)
+
+	// Transcripts are cached.
+	res = transcriber.Transcribe(t.Context(), messages)
+	assert.Equal(t, 0, res.Transcribed)
+	assert.Equal(t, 1, model.transcriptions)
+	assert.Contains(t, res.Messages[0].MultiContent[1].Text, "We ship on Friday.")
+}
+
+func TestTranscribe_Summary(t *testing.T) {
+	t.Parallel()
+
+	messages, _ := conversation(t)
+	paragraph := strings.Repeat("word ", 19) + "end.\n\n"
+	model := &mockProvider{transcript: strings.Repeat(paragraph, 10), summary: "They agreed to ship."}
+	transcriber := New(model, model, 250)
+
+	res := transcriber.Transcribe(t.Context(), messages)
+	require.Empty(t, res.Errors)
+
+	text := res.Messages[0].MultiContent[1].Text
+	assert.Contains(t, text, `type="transcript summary"`)
+	assert.Contains(t, text, "Part 1 of 5:\nThey agreed to ship.")
+	assert.Contains(t, text, "Part 5 of 5:\nThey agreed to ship.")
+	require.Len(t, model.summaries, 5)
+	assert.True(t, strings.HasSuffix(model.summaries[0], "end."))
+}
+
+func TestTranscribe_Truncated(t *testing.T) {
+	t.Parallel()
+
+	messages, _ := conversation(t)
+	transcriber := New(&mockProvider{transcript: strings.Repeat("a", 300)}, nil, 100)
+
+	res := transcriber.Transcribe(t.Context(), messages)
+	require.Empty(t, res.Errors)
+	assert.Contains(t, res.Messages[0].MultiContent[1].Text, "[... 200 characters omitted ...]")
+}
+
+func TestTranscribe_Errors(t *testing.T) {
+	t.Parallel()
+
+	messages, path := conversation(t)
+
+	res := New(nil, nil, 0).Transcribe(t.Context(), messages)
+	require.Len(t, res.Errors, 1)
+	require.ErrorIs(t, res.Errors[0], ErrNoModel)
+	assert.Equal(t, "<attached_file path=\""+path+"\">\n[This audio file could not be transcribed: no transcription model is configured]\n</attached_file>", res.Messages[0].MultiContent[1].Text)
+
+	model := &mockProvider{err: errors.New("quota exceeded")}
+	transcriber := New(model, nil, 0)
+	res = transcriber.Transcribe(t.Context(), messages)
+	require.Len(t, res.Errors, 1)
+	assert.Contains(t, res.Messages[0].MultiContent[1].Text, "quota exceeded")
+
+	// Failures aren't retried on every turn.
+	res = transcriber.Transcribe(t.Context(), messages)
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, 1, model.transcriptions)
+}
+
+func TestSplitChunks(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"one two", "three four", "five"}, splitChunks("one two three four five", 11))
+	assert.Equal(t, []string{"short"}, splitChunks("short", 100))
+}
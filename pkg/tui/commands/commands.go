@@ -4,13 +4,17 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/docker/docker-agent/pkg/app"
 	"github.com/docker/docker-agent/pkg/feedback"
+	"github.com/docker/docker-agent/pkg/httpclient"
 	"github.com/docker/docker-agent/pkg/modelsdev"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tui/components/notification"
 	"github.com/docker/docker-agent/pkg/tui/components/toolcommon"
 	"github.com/docker/docker-agent/pkg/tui/core"
 	"github.com/docker/docker-agent/pkg/tui/messages"
@@ -32,11 +36,23 @@ type Item struct {
 	Description  string
 	Category     string
 	SlashCommand string
-	Execute      ExecuteFunc
+	// Shortcut is the key bound to the command, shown in the palette.
+	Shortcut string
+	Execute  ExecuteFunc
 }
 
 func builtInSessionCommands() []Item {
 	cmds := []Item{
+		{
+			ID:           "session.artifacts",
+			Label:        "Artifacts",
+			SlashCommand: "/artifacts",
+			Description:  "List the files registered as artifacts of this session",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ShowArtifactsDialogMsg{})
+			},
+		},
 		{
 			ID:           "session.attach",
 			Label:        "Attach",
@@ -57,6 +73,24 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.CompactSessionMsg{AdditionalPrompt: arg})
 			},
 		},
+		{
+			ID:           "session.continue",
+			Label:        "Continue",
+			SlashCommand: "/continue",
+			Description:  "Continue a run paused at its iteration limit (usage: /continue [iterations])",
+			Category:     "Session",
+			Execute: func(arg string) tea.Cmd {
+				iterations := runtime.DefaultContinueIterations
+				if arg = strings.TrimSpace(arg); arg != "" {
+					n, err := strconv.Atoi(arg)
+					if err != nil || n <= 0 {
+						return notification.ErrorCmd(fmt.Sprintf("Invalid number of iterations: %q", arg))
+					}
+					iterations = n
+				}
+				return core.CmdHandler(messages.ContinueRunMsg{Iterations: iterations})
+			},
+		},
 		{
 			ID:           "session.clipboard",
 			Label:        "Copy",
@@ -87,6 +121,16 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.ShowCostDialogMsg{})
 			},
 		},
+		{
+			ID:           "session.dictate",
+			Label:        "Dictate",
+			SlashCommand: "/dictate",
+			Description:  "Record a voice message and transcribe it into the editor (press Enter to transcribe or Escape to cancel)",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleDictationMsg{})
+			},
+		},
 		{
 			ID:           "session.eval",
 			Label:        "Eval",
@@ -97,6 +141,26 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.EvalSessionMsg{Filename: arg})
 			},
 		},
+		{
+			ID:           "session.debug_llm",
+			Label:        "Debug LLM",
+			SlashCommand: "/debug-llm",
+			Description:  "Open the last model request and response dumped by --debug-llm",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.OpenDebugDumpMsg{})
+			},
+		},
+		{
+			ID:           "session.editor",
+			Label:        "Editor",
+			SlashCommand: "/editor",
+			Description:  "Compose your message in $VISUAL or $EDITOR",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.OpenExternalEditorMsg{})
+			},
+		},
 		{
 			ID:           "session.exit",
 			Label:        "Exit",
@@ -187,6 +251,16 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.ToggleThinkingMsg{})
 			},
 		},
+		{
+			ID:           "session.timetravel",
+			Label:        "Time Travel",
+			SlashCommand: "/timetravel",
+			Description:  "Step through the session, inspect each model and tool call, and re-run from any step",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ShowTimeTravelDialogMsg{})
+			},
+		},
 		{
 			ID:           "session.title",
 			Label:        "Title",
@@ -213,6 +287,16 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.ToggleYoloMsg{})
 			},
 		},
+		{
+			ID:           "session.simulate",
+			Label:        "Simulate",
+			SlashCommand: "/simulate",
+			Description:  "Toggle the simulation of tool calls that change things",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleSimulateMsg{})
+			},
+		},
 	}
 
 	// Add speak command on supported platforms (macOS only)
@@ -225,6 +309,16 @@ func builtInSessionCommands() []Item {
 
 func builtInSettingsCommands() []Item {
 	return []Item{
+		{
+			ID:           "settings.split-agents",
+			Label:        "Split Agents",
+			SlashCommand: "/split-agents",
+			Description:  "Toggle one pane per agent in team runs",
+			Category:     "Settings",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleAgentPanesMsg{})
+			},
+		},
 		{
 			ID:           "settings.split-diff",
 			Label:        "Split Diff",
@@ -245,6 +339,25 @@ func builtInSettingsCommands() []Item {
 				return core.CmdHandler(messages.OpenThemePickerMsg{})
 			},
 		},
+		{
+			ID:          "settings.tool-results",
+			Label:       "Tool Results",
+			Description: "Show or hide tool call results",
+			Category:    "Settings",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleHideToolResultsMsg{})
+			},
+		},
+		{
+			ID:           "settings.voice",
+			Label:        "Voice",
+			SlashCommand: "/voice",
+			Description:  "Toggle reading assistant replies aloud",
+			Category:     "Settings",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleReadRepliesMsg{})
+			},
+		},
 	}
 }
 
@@ -307,6 +420,17 @@ func BuildCommandCategories(ctx context.Context, application *app.App) []Categor
 		sessionCommands = filtered
 	}
 
+	// Hide /debug-llm unless the model requests are dumped
+	if !httpclient.DebugDumping() {
+		filtered := make([]Item, 0, len(sessionCommands))
+		for _, cmd := range sessionCommands {
+			if cmd.ID != "session.debug_llm" {
+				filtered = append(filtered, cmd)
+			}
+		}
+		sessionCommands = filtered
+	}
+
 	categories := []Category{
 		{
 			Name:     "Session",
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/tui/messages"
 )
 
@@ -122,3 +123,33 @@ func TestParseSlashCommand_Compact(t *testing.T) {
 		assert.Equal(t, "focus on the API design", compactMsg.AdditionalPrompt)
 	})
 }
+
+func TestParseSlashCommand_Continue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("continue without argument", func(t *testing.T) {
+		t.Parallel()
+		cmd := ParseSlashCommand("/continue")
+		require.NotNil(t, cmd)
+		continueMsg, ok := cmd().(messages.ContinueRunMsg)
+		require.True(t, ok)
+		assert.Equal(t, runtime.DefaultContinueIterations, continueMsg.Iterations)
+	})
+
+	t.Run("continue with iterations", func(t *testing.T) {
+		t.Parallel()
+		cmd := ParseSlashCommand("/continue 25")
+		require.NotNil(t, cmd)
+		continueMsg, ok := cmd().(messages.ContinueRunMsg)
+		require.True(t, ok)
+		assert.Equal(t, 25, continueMsg.Iterations)
+	})
+
+	t.Run("continue with invalid iterations", func(t *testing.T) {
+		t.Parallel()
+		cmd := ParseSlashCommand("/continue many")
+		require.NotNil(t, cmd)
+		_, ok := cmd().(messages.ContinueRunMsg)
+		assert.False(t, ok)
+	})
+}
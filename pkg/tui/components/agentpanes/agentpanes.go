@@ -0,0 +1,243 @@
+// Package agentpanes renders the live activity of each agent of a team run in
+// its own pane, so that work fanned out to sub-agents is not interleaved into
+// a single scrollback.
+package agentpanes
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tui/styles"
+)
+
+const (
+	// minPaneWidth is the width below which panes are shown as tabs.
+	minPaneWidth = 32
+	// minHeight is the smallest height given to the panes.
+	minHeight = 6
+	// maxBufferSize bounds the streamed text kept per agent.
+	maxBufferSize = 8 * 1024
+)
+
+// Status is what an agent is currently doing.
+type Status int
+
+const (
+	StatusIdle Status = iota
+	StatusStreaming
+	StatusTool
+	StatusDone
+)
+
+type pane struct {
+	agent  string
+	status Status
+	tool   string
+	buffer string
+}
+
+func (p *pane) append(text string) {
+	p.buffer += text
+	if len(p.buffer) > maxBufferSize {
+		p.buffer = p.buffer[len(p.buffer)-maxBufferSize:]
+		// Don't start in the middle of a line
+		if i := strings.IndexByte(p.buffer, '\n'); i >= 0 {
+			p.buffer = p.buffer[i+1:]
+		}
+	}
+}
+
+// Model tracks one pane per agent, in the order the agents first appeared.
+type Model struct {
+	enabled bool
+	panes   []*pane
+	// active is the index of the pane that was updated last; it is the one
+	// shown when the panes are displayed as tabs.
+	active int
+
+	width, height int
+}
+
+// New creates an empty, disabled set of agent panes.
+func New() *Model {
+	return &Model{}
+}
+
+// Toggle enables or disables the panes and returns the new state.
+func (m *Model) Toggle() bool {
+	m.enabled = !m.enabled
+	return m.enabled
+}
+
+// Enabled returns whether the panes were turned on by the user.
+func (m *Model) Enabled() bool {
+	return m.enabled
+}
+
+// Visible returns whether the panes should be displayed: they are only
+// useful once more than one agent is active.
+func (m *Model) Visible() bool {
+	return m.enabled && len(m.panes) > 1
+}
+
+// Agents returns the names of the agents that have a pane.
+func (m *Model) Agents() []string {
+	names := make([]string, len(m.panes))
+	for i, p := range m.panes {
+		names[i] = p.agent
+	}
+	return names
+}
+
+// Reset removes all the panes, at the start of a new turn.
+func (m *Model) Reset() {
+	m.panes = nil
+	m.active = 0
+}
+
+// Handle updates the panes from a runtime event. It returns true when the
+// event was relevant to the panes.
+func (m *Model) Handle(msg any) bool {
+	switch e := msg.(type) {
+	case *runtime.StreamStartedEvent:
+		m.pane(e.AgentName).status = StatusStreaming
+	case *runtime.AgentChoiceEvent:
+		p := m.pane(e.AgentName)
+		p.status = StatusStreaming
+		p.append(e.Content)
+	case *runtime.ToolCallEvent:
+		p := m.pane(e.AgentName)
+		p.status = StatusTool
+		p.tool = e.ToolCall.Function.Name
+		p.append("\n→ " + p.tool + "\n")
+	case *runtime.ToolCallResponseEvent:
+		p := m.pane(e.AgentName)
+		p.status = StatusStreaming
+		p.tool = ""
+	case *runtime.StreamStoppedEvent:
+		p := m.pane(e.AgentName)
+		p.status = StatusDone
+		p.tool = ""
+	default:
+		return false
+	}
+	return true
+}
+
+// pane returns the pane of the given agent, creating it if needed, and makes
+// it the active one.
+func (m *Model) pane(agent string) *pane {
+	for i, p := range m.panes {
+		if p.agent == agent {
+			m.active = i
+			return p
+		}
+	}
+	m.panes = append(m.panes, &pane{agent: agent})
+	m.active = len(m.panes) - 1
+	return m.panes[m.active]
+}
+
+// Height returns the height the panes take out of the given chat height.
+func (m *Model) Height(chatHeight int) int {
+	if !m.Visible() {
+		return 0
+	}
+	return min(chatHeight/2, max(minHeight, chatHeight*2/5))
+}
+
+// SetSize sets the dimensions of the area the panes are rendered in.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// View renders the panes side by side, or as tabs when there isn't enough
+// room for all of them.
+func (m *Model) View() string {
+	if !m.Visible() || m.width <= 0 || m.height <= 0 {
+		return ""
+	}
+
+	if m.width/len(m.panes) < minPaneWidth {
+		return m.renderTabs()
+	}
+
+	paneWidth := m.width / len(m.panes)
+	views := make([]string, len(m.panes))
+	for i, p := range m.panes {
+		w := paneWidth
+		if i == len(m.panes)-1 {
+			w = m.width - paneWidth*(len(m.panes)-1)
+		}
+		views[i] = m.renderPane(p, w, i == m.active)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, views...)
+}
+
+func (m *Model) renderPane(p *pane, width int, active bool) string {
+	header := renderHeader(p, active)
+	body := renderBody(p.buffer, width-2, m.height-3)
+
+	borderStyle := styles.FadingStyle
+	if active {
+		borderStyle = styles.MutedStyle
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderStyle.GetForeground()).
+		Width(width).
+		Height(m.height).
+		Render(header + "\n" + body)
+}
+
+func (m *Model) renderTabs() string {
+	tabs := make([]string, len(m.panes))
+	for i, p := range m.panes {
+		tabs[i] = renderHeader(p, i == m.active)
+	}
+	tabBar := lipgloss.NewStyle().MaxWidth(m.width).Render(strings.Join(tabs, styles.FadingStyle.Render(" │ ")))
+	divider := styles.FadingStyle.Render(strings.Repeat("─", m.width))
+	body := renderBody(m.panes[m.active].buffer, m.width, m.height-2)
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Render(tabBar + "\n" + divider + "\n" + body)
+}
+
+func renderHeader(p *pane, active bool) string {
+	nameStyle := styles.MutedStyle
+	if active {
+		nameStyle = styles.BoldStyle
+	}
+
+	var status string
+	switch p.status {
+	case StatusStreaming:
+		status = styles.InfoStyle.Render("●")
+	case StatusTool:
+		status = styles.WarningStyle.Render("⚙ " + p.tool)
+	case StatusDone:
+		status = styles.SuccessStyle.Render("✓")
+	default:
+		status = styles.MutedStyle.Render("○")
+	}
+	return nameStyle.Render(p.agent) + " " + status
+}
+
+// renderBody wraps the streamed text to the given width and keeps the last
+// lines that fit in the given height.
+func renderBody(text string, width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	wrapped := lipgloss.NewStyle().Width(width).Render(strings.TrimSpace(text))
+	lines := strings.Split(wrapped, "\n")
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+package agentpanes
+
+import (
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func choice(agent, content string) *runtime.AgentChoiceEvent {
+	return &runtime.AgentChoiceEvent{Content: content, AgentContext: runtime.AgentContext{AgentName: agent}}
+}
+
+func TestVisibleOnlyWithSeveralAgents(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.Handle(choice("root", "hello"))
+	m.Handle(choice("researcher", "searching"))
+	assert.False(t, m.Visible(), "panes are disabled by default")
+
+	require.True(t, m.Toggle())
+	assert.True(t, m.Visible())
+	assert.Equal(t, []string{"root", "researcher"}, m.Agents())
+
+	m.Reset()
+	m.Handle(choice("root", "hello"))
+	assert.False(t, m.Visible(), "a single agent doesn't need panes")
+	assert.Zero(t, m.Height(40))
+}
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	assert.False(t, m.Handle(&runtime.ErrorEvent{}))
+
+	m.Handle(&runtime.StreamStartedEvent{AgentContext: runtime.AgentContext{AgentName: "writer"}})
+	m.Handle(choice("writer", "drafting"))
+	m.Handle(&runtime.ToolCallEvent{
+		ToolCall:     tools.ToolCall{Function: tools.FunctionCall{Name: "read_file"}},
+		AgentContext: runtime.AgentContext{AgentName: "writer"},
+	})
+
+	p := m.panes[0]
+	assert.Equal(t, StatusTool, p.status)
+	assert.Equal(t, "read_file", p.tool)
+	assert.Contains(t, p.buffer, "drafting")
+	assert.Contains(t, p.buffer, "→ read_file")
+
+	m.Handle(&runtime.StreamStoppedEvent{AgentContext: runtime.AgentContext{AgentName: "writer"}})
+	assert.Equal(t, StatusDone, p.status)
+	assert.Empty(t, p.tool)
+}
+
+func TestBufferIsBounded(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	line := strings.Repeat("x", 99) + "\n"
+	for range 200 {
+		m.Handle(choice("root", line))
+	}
+	assert.LessOrEqual(t, len(m.panes[0].buffer), maxBufferSize)
+	assert.True(t, strings.HasPrefix(m.panes[0].buffer, "x"))
+}
+
+func TestView(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		width int
+		tabs  bool
+	}{
+		{name: "side by side", width: 120},
+		{name: "tabs", width: 50, tabs: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := New()
+			m.Toggle()
+			m.Handle(choice("root", "planning the work"))
+			m.Handle(choice("researcher", "looking things up"))
+			m.SetSize(tt.width, 10)
+
+			view := m.View()
+			assert.Equal(t, tt.width, lipgloss.Width(view))
+			assert.Equal(t, 10, lipgloss.Height(view))
+			assert.Contains(t, view, "root")
+			assert.Contains(t, view, "researcher")
+			assert.Contains(t, view, "looking things up")
+			if tt.tabs {
+				assert.NotContains(t, view, "planning the work", "only the active agent is shown as a tab")
+			} else {
+				assert.Contains(t, view, "planning the work")
+			}
+		})
+	}
+}
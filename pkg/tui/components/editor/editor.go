@@ -21,6 +21,7 @@ import (
 	"github.com/rivo/uniseg"
 
 	"github.com/docker/docker-agent/pkg/app"
+	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/history"
 	"github.com/docker/docker-agent/pkg/paths"
 	"github.com/docker/docker-agent/pkg/tui/components/completion"
@@ -70,6 +71,9 @@ type Editor interface {
 	Value() string
 	// SetValue updates the editor content
 	SetValue(content string)
+	// ImportDraft replaces the content with a draft edited outside the TUI,
+	// attaching the files it references
+	ImportDraft(content string)
 	// InsertText inserts text at the current cursor position
 	InsertText(text string)
 	// AttachFile adds a file as an attachment and inserts @filepath into the editor
@@ -1270,6 +1274,17 @@ func (e *editor) SetValue(content string) {
 	e.refreshSuggestion()
 }
 
+// ImportDraft replaces the content with a draft edited outside the TUI.
+// Pending attachments are kept as long as their placeholder is still in the
+// draft, and @filepath references added in the external editor are attached.
+func (e *editor) ImportDraft(content string) {
+	e.SetValue(content)
+	for _, word := range strings.Fields(content) {
+		e.tryAddFileRef(word)
+	}
+	e.updateAttachmentBanner()
+}
+
 // InsertText inserts text at the current cursor position
 func (e *editor) InsertText(text string) {
 	e.textarea.InsertString(text)
@@ -1335,8 +1350,7 @@ func (e *editor) addFileAttachment(placeholder string) error {
 		return fmt.Errorf("path is a directory: %s", absPath)
 	}
 
-	const maxFileSize = 5 * 1024 * 1024
-	if info.Size() >= maxFileSize {
+	if info.Size() > chat.MaxAttachmentSize {
 		return fmt.Errorf("file too large: %s (%s)", absPath, units.HumanSize(float64(info.Size())))
 	}
 
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+
+	"github.com/docker/docker-agent/pkg/chat"
 )
 
 // validateFilePath checks that a path is safe: no path traversal, no symlinks.
@@ -25,14 +27,16 @@ func validateFilePath(path string) (os.FileInfo, error) {
 	return info, nil
 }
 
-// Supported file extensions for drag-and-drop attachments
+// Supported file extensions for drag-and-drop attachments of files that
+// can't be read yet. The type of other files is sniffed from their content.
 var supportedFileExtensions = []string{
 	// Images
-	".png", ".jpg", ".jpeg", ".gif", ".webp", ".bmp", ".svg",
+	".png", ".jpg", ".jpeg", ".gif", ".webp",
 	// PDFs
 	".pdf",
-	// Text files (future)
-	// ".txt", ".md", ".json", ".yaml", ".yml", ".toml",
+	// Audio and video, transcribed before they are sent to the model
+	".mp3", ".wav", ".m4a", ".aac", ".flac", ".ogg", ".opus",
+	".mp4", ".mov", ".webm", ".mkv",
 }
 
 // ParsePastedFiles attempts to parse pasted content as file paths.
@@ -188,8 +192,16 @@ func unixParsePastedFiles(s string) []string {
 	return paths
 }
 
-// IsSupportedFileType checks if a file has a supported extension.
+// IsSupportedFileType checks if a file can be attached: text and source
+// files, images, PDFs, audio and video. The type is sniffed from the content
+// of the file, and from its extension when it can't be read.
 func IsSupportedFileType(path string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	return slices.Contains(supportedFileExtensions, ext)
+	if chat.IsTextFile(path) {
+		return true
+	}
+	if _, err := os.Stat(path); err != nil {
+		return slices.Contains(supportedFileExtensions, strings.ToLower(filepath.Ext(path)))
+	}
+	mimeType := chat.DetectMimeType(path)
+	return chat.IsImageMimeType(mimeType) || chat.IsSupportedMimeType(mimeType) || chat.IsMediaMimeType(mimeType)
 }
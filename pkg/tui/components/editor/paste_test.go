@@ -11,6 +11,8 @@ import (
 	"github.com/docker/go-units"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
 )
 
 func TestHandlePaste_SmallContent(t *testing.T) {
@@ -349,8 +351,12 @@ func TestIsSupportedFileType(t *testing.T) {
 		{"/path/to/image.jpg", true},
 		{"/path/to/image.JPEG", true}, // Case insensitive
 		{"/path/to/doc.pdf", true},
-		{"/path/to/file.txt", false}, // Not supported yet
-		{"/path/to/script.sh", false},
+		{"/path/to/meeting.mp3", true},
+		{"/path/to/demo.mov", true},
+		{"/path/to/file.txt", true},
+		{"/path/to/script.sh", true},
+		{"/path/to/main.go", true},
+		{"/path/to/archive.tar.gz", false},
 		{"/path/to/noext", false},
 	}
 
@@ -450,28 +456,34 @@ func TestAddFileAttachment_SizeLimit(t *testing.T) {
 
 	tmpDir := t.TempDir()
 
-	// Exactly 5MB — should be rejected (>= 5MB)
-	exactly5MB := filepath.Join(tmpDir, "exact5mb.png")
-	if err := os.WriteFile(exactly5MB, make([]byte, 5*1024*1024), 0o644); err != nil {
+	// Just over 100MB — should be rejected
+	tooLarge := filepath.Join(tmpDir, "too-large.log")
+	if err := os.WriteFile(tooLarge, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(tooLarge, chat.MaxAttachmentSize+1); err != nil {
 		t.Fatal(err)
 	}
 
-	// Just under 5MB — should be accepted
-	justUnder := filepath.Join(tmpDir, "under5mb.png")
-	if err := os.WriteFile(justUnder, make([]byte, 5*1024*1024-1), 0o644); err != nil {
+	// Exactly 100MB — should be accepted, large text files are read in chunks
+	exactly := filepath.Join(tmpDir, "exact.log")
+	if err := os.WriteFile(exactly, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(exactly, chat.MaxAttachmentSize); err != nil {
 		t.Fatal(err)
 	}
 
 	e := &editor{}
 
-	err := e.addFileAttachment("@" + exactly5MB)
+	err := e.addFileAttachment("@" + tooLarge)
 	if err == nil {
-		t.Error("expected addFileAttachment to reject file exactly 5MB, but it succeeded")
+		t.Error("expected addFileAttachment to reject file over 100MB, but it succeeded")
 	}
 
-	err = e.addFileAttachment("@" + justUnder)
+	err = e.addFileAttachment("@" + exactly)
 	if err != nil {
-		t.Errorf("expected addFileAttachment to accept file just under 5MB, got error: %v", err)
+		t.Errorf("expected addFileAttachment to accept file of 100MB, got error: %v", err)
 	}
 }
 
@@ -524,9 +536,10 @@ func TestHandlePaste_RollbackOnPartialFailure(t *testing.T) {
 	goodFile := filepath.Join(tmpDir, "valid.png")
 	require.NoError(t, os.WriteFile(goodFile, []byte("PNG"), 0o644))
 
-	// Second file is too large (>= 5MB)
+	// Second file is too large (> 100MB)
 	bigFile := filepath.Join(tmpDir, "huge.png")
-	require.NoError(t, os.WriteFile(bigFile, make([]byte, 5*1024*1024), 0o644))
+	require.NoError(t, os.WriteFile(bigFile, nil, 0o644))
+	require.NoError(t, os.Truncate(bigFile, chat.MaxAttachmentSize+1))
 
 	e := newPasteTestEditor()
 	handled := e.handlePaste(goodFile + " " + bigFile)
@@ -542,12 +555,12 @@ func TestHandlePaste_UnsupportedTypeRollback(t *testing.T) {
 
 	tmpDir := t.TempDir()
 	png := filepath.Join(tmpDir, "ok.png")
-	sh := filepath.Join(tmpDir, "script.sh")
+	archive := filepath.Join(tmpDir, "archive.bin")
 	require.NoError(t, os.WriteFile(png, []byte("PNG"), 0o644))
-	require.NoError(t, os.WriteFile(sh, []byte("#!/bin/sh"), 0o644))
+	require.NoError(t, os.WriteFile(archive, []byte{0x1f, 0x8b, 0x08, 0x00, 0x00}, 0o644))
 
 	e := newPasteTestEditor()
-	handled := e.handlePaste(png + " " + sh)
+	handled := e.handlePaste(png + " " + archive)
 
 	assert.False(t, handled, "unsupported file type should cause fallback to text")
 	assert.Empty(t, e.attachments, "no attachments when file type is unsupported")
@@ -667,3 +680,35 @@ func TestAttachFile_SetsCorrectLabel(t *testing.T) {
 	expectedLabel := fmt.Sprintf("labeled.png (%s)", units.HumanSize(float64(len(data))))
 	assert.Equal(t, expectedLabel, e.attachments[0].label)
 }
+
+func TestImportDraft_AttachesFileRefs(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	kept := filepath.Join(tmpDir, "kept.txt")
+	added := filepath.Join(tmpDir, "added.txt")
+	removed := filepath.Join(tmpDir, "removed.txt")
+	for _, f := range []string{kept, added, removed} {
+		require.NoError(t, os.WriteFile(f, []byte("content"), 0o644))
+	}
+
+	e := newPasteTestEditor()
+	require.NoError(t, e.AttachFile(kept))
+	require.NoError(t, e.AttachFile(removed))
+
+	e.ImportDraft("first line\nlook at @" + kept + "\nand @" + added + "\n@username")
+
+	assert.Equal(t, "first line\nlook at @"+kept+"\nand @"+added+"\n@username", e.Value())
+	assert.Len(t, e.attachments, 3)
+
+	var banner []string
+	for _, item := range e.banner.items {
+		banner = append(banner, item.placeholder)
+	}
+	assert.Equal(t, []string{"@" + kept, "@" + added}, banner)
+
+	attachments := e.collectAttachments(e.Value())
+	require.Len(t, attachments, 2)
+	assert.Equal(t, kept, attachments[0].FilePath)
+	assert.Equal(t, added, attachments[1].FilePath)
+}
@@ -0,0 +1,82 @@
+// Package imageview renders the images attached to user messages and returned
+// by tools.
+//
+// In kitty terminals, images are sent once to the terminal and displayed with
+// Unicode placeholders, which behave like regular text in the scrollback.
+// Other terminals get a half-block rendering: the iTerm2 and sixel protocols
+// draw over the screen and don't survive the redraws of the TUI.
+package imageview
+
+import (
+	"log/slog"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/termimage"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// FromMedia decodes the images returned by a tool, skipping the ones that
+// can't be decoded.
+func FromMedia(media []tools.MediaContent) []*termimage.Image {
+	var images []*termimage.Image
+	for _, m := range media {
+		img, err := termimage.Decode(m.Data)
+		if err != nil {
+			slog.Debug("Failed to decode tool image", "mime_type", m.MimeType, "error", err)
+			continue
+		}
+		images = append(images, img)
+	}
+	return images
+}
+
+// FromParts decodes the images attached to a user message, skipping the
+// ones that can't be decoded.
+func FromParts(parts []chat.MessagePart) []*termimage.Image {
+	var images []*termimage.Image
+	for _, part := range parts {
+		if part.Type != chat.MessagePartTypeImageURL || part.ImageURL == nil {
+			continue
+		}
+		img, err := termimage.DecodeDataURL(part.ImageURL.URL)
+		if err != nil {
+			slog.Debug("Failed to decode attached image", "error", err)
+			continue
+		}
+		images = append(images, img)
+	}
+	return images
+}
+
+// TransmitCmd sends the images to the terminal when it supports the kitty
+// graphics protocol. It returns nil otherwise.
+func TransmitCmd(images []*termimage.Image) tea.Cmd {
+	if termimage.Detect() != termimage.Kitty {
+		return nil
+	}
+
+	var seq strings.Builder
+	for _, img := range images {
+		seq.WriteString(img.Transmit())
+	}
+	if seq.Len() == 0 {
+		return nil
+	}
+	return tea.Raw(seq.String())
+}
+
+// View renders the images one below the other, within the given width.
+func View(images []*termimage.Image, width int) string {
+	views := make([]string, 0, len(images))
+	for _, img := range images {
+		if img.Transmitted() && img.Cols <= width {
+			views = append(views, img.Placeholders())
+		} else {
+			views = append(views, img.HalfBlocks(width))
+		}
+	}
+	return strings.Join(views, "\n")
+}
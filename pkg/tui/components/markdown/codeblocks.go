@@ -0,0 +1,29 @@
+package markdown
+
+import "strings"
+
+// CodeBlocks returns the contents of the fenced code blocks of a markdown
+// document, in order, without their fences.
+func CodeBlocks(input string) []string {
+	var blocks []string
+
+	lines := strings.Split(input, "\n")
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+			continue
+		}
+
+		fence := trimmed[:3]
+		var code []string
+		for i++; i < len(lines); i++ {
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				break
+			}
+			code = append(code, lines[i])
+		}
+		blocks = append(blocks, strings.Join(code, "\n"))
+	}
+
+	return blocks
+}
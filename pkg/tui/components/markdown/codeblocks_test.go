@@ -0,0 +1,16 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	input := "Install it:\n\n```bash\nnpm install\nnpm test\n```\n\nThen:\n\n~~~\nrun\n~~~\n\n```go\nunterminated"
+
+	assert.Equal(t, []string{"npm install\nnpm test", "run", "unterminated"}, CodeBlocks(input))
+	assert.Empty(t, CodeBlocks("no code here"))
+}
@@ -606,6 +606,86 @@ type tableCell struct {
 	longestWord int    // width of longest single word (for minimum column width)
 }
 
+// tableAlign is the alignment of a table column, from the separator line
+type tableAlign int
+
+const (
+	alignLeft tableAlign = iota
+	alignCenter
+	alignRight
+)
+
+// parseTableAlignments reads the column alignments from a table separator
+// line such as "| :--- | :---: | ---: |"
+func parseTableAlignments(separator string) []tableAlign {
+	cells := splitTableRow(separator)
+	aligns := make([]tableAlign, len(cells))
+	for i, cell := range cells {
+		left := strings.HasPrefix(cell, ":")
+		right := strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns[i] = alignCenter
+		case right:
+			aligns[i] = alignRight
+		}
+	}
+	return aligns
+}
+
+// writeAlignedCell writes a rendered cell padded to the column width
+// according to the column alignment
+func writeAlignedCell(b *strings.Builder, rendered string, cellWidth, colWidth int, align tableAlign, bold ansiStyle, isHeader bool) {
+	padding := max(0, colWidth-cellWidth)
+	var before int
+	switch align {
+	case alignRight:
+		before = padding
+	case alignCenter:
+		before = padding / 2
+	}
+	writeSpaces(b, before)
+	if isHeader {
+		bold.renderTo(b, rendered)
+	} else {
+		b.WriteString(rendered)
+	}
+	writeSpaces(b, padding-before)
+}
+
+// splitTableRow splits a table row into trimmed cells. Pipes escaped with a
+// backslash or inside code spans don't separate cells.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	if line != "" && line[0] == '|' {
+		line = line[1:]
+	}
+	if line != "" && line[len(line)-1] == '|' && (len(line) < 2 || line[len(line)-2] != '\\') {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	inCode := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+			continue
+		case c == '`':
+			inCode = !inCode
+		case c == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(c)
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
 // tableLayout holds the computed table layout parameters
 type tableLayout struct {
 	colWidths  []int  // width for each column
@@ -825,6 +905,7 @@ func (p *parser) tryTable(line string) bool {
 			return false
 		}
 	}
+	aligns := parseTableAlignments(separator)
 
 	// Parse and render cells in one pass
 	// Pre-allocate rows slice (numLines - 1 because we skip the separator)
@@ -877,12 +958,17 @@ func (p *parser) tryTable(line string) bool {
 	styledSepLine := textStyle.render(sepLine)
 	styledSep := textStyle.render(layout.sep)
 
+	// Columns without an explicit alignment are left-aligned
+	for len(aligns) < numCols {
+		aligns = append(aligns, alignLeft)
+	}
+
 	if !layout.needsWrap {
 		// Fast path: no wrapping needed, render single-line rows
-		p.renderTableRowsFast(rows, colWidths, styledSep, styledSepLine)
+		p.renderTableRowsFast(rows, colWidths, aligns, styledSep, styledSepLine)
 	} else {
 		// Slow path: wrap cells and render multi-line rows
-		p.renderTableRowsWrapped(rows, colWidths, styledSep, styledSepLine)
+		p.renderTableRowsWrapped(rows, colWidths, aligns, styledSep, styledSepLine)
 	}
 
 	p.out.WriteByte('\n')
@@ -919,7 +1005,7 @@ func buildTableBlankRow(colWidths []int, styledSep string) string {
 }
 
 // renderTableRowsFast renders table rows without wrapping (fast path)
-func (p *parser) renderTableRowsFast(rows [][]tableCell, colWidths []int, styledSep, styledSepLine string) {
+func (p *parser) renderTableRowsFast(rows [][]tableCell, colWidths []int, aligns []tableAlign, styledSep, styledSepLine string) {
 	numCols := len(colWidths)
 	blankRow := buildTableBlankRow(colWidths, styledSep)
 
@@ -936,18 +1022,8 @@ func (p *parser) renderTableRowsFast(rows [][]tableCell, colWidths []int, styled
 				cell = row[i]
 			}
 
-			if rowIdx == 0 {
-				// Header row - bold
-				p.styles.ansiBold.renderTo(&p.out, cell.rendered)
-			} else {
-				p.out.WriteString(cell.rendered)
-			}
-
-			// Add padding
-			padding := colWidths[i] - cell.width
-			if padding > 0 {
-				p.out.WriteString(spaces(padding))
-			}
+			// Header row is bold
+			writeAlignedCell(&p.out, cell.rendered, cell.width, colWidths[i], aligns[i], p.styles.ansiBold, rowIdx == 0)
 
 			if i < numCols-1 {
 				p.out.WriteString(styledSep)
@@ -964,7 +1040,7 @@ func (p *parser) renderTableRowsFast(rows [][]tableCell, colWidths []int, styled
 }
 
 // renderTableRowsWrapped renders table rows with cell wrapping (slow path)
-func (p *parser) renderTableRowsWrapped(rows [][]tableCell, colWidths []int, styledSep, styledSepLine string) {
+func (p *parser) renderTableRowsWrapped(rows [][]tableCell, colWidths []int, aligns []tableAlign, styledSep, styledSepLine string) {
 	numCols := len(colWidths)
 	blankRow := buildTableBlankRow(colWidths, styledSep)
 
@@ -1008,19 +1084,9 @@ func (p *parser) renderTableRowsWrapped(rows [][]tableCell, colWidths []int, sty
 					lineContent = wrappedCells[colIdx][lineIdx]
 				}
 
-				// Apply bold to header row
-				if rowIdx == 0 {
-					p.styles.ansiBold.renderTo(&p.out, lineContent)
-				} else {
-					p.out.WriteString(lineContent)
-				}
-
-				// Pad to column width
+				// Apply bold to header row and pad to column width
 				lineWidth := ansiStringWidth(lineContent)
-				padding := colWidths[colIdx] - lineWidth
-				if padding > 0 {
-					p.out.WriteString(spaces(padding))
-				}
+				writeAlignedCell(&p.out, lineContent, lineWidth, colWidths[colIdx], aligns[colIdx], p.styles.ansiBold, rowIdx == 0)
 
 				if colIdx < numCols-1 {
 					p.out.WriteString(styledSep)
@@ -1039,32 +1105,10 @@ func (p *parser) renderTableRowsWrapped(rows [][]tableCell, colWidths []int, sty
 
 // parseAndRenderTableRow parses a table row and renders cells in one pass
 func (p *parser) parseAndRenderTableRow(line string) []tableCell {
-	// Trim leading/trailing whitespace and pipes
-	line = strings.TrimSpace(line)
-	if line != "" && line[0] == '|' {
-		line = line[1:]
-	}
-	if line != "" && line[len(line)-1] == '|' {
-		line = line[:len(line)-1]
-	}
+	texts := splitTableRow(line)
+	cells := make([]tableCell, 0, len(texts))
 
-	// Count cells first to pre-allocate
-	numCells := 1
-	for i := range len(line) {
-		if line[i] == '|' {
-			numCells++
-		}
-	}
-
-	cells := make([]tableCell, 0, numCells)
-	start := 0
-
-	for i := 0; i <= len(line); i++ {
-		if i < len(line) && line[i] != '|' {
-			continue
-		}
-		// Extract and trim the cell
-		cellText := strings.TrimSpace(line[start:i])
+	for _, cellText := range texts {
 		var rendered string
 		var width int
 		// Fast path: if cell has no markdown, skip full inline rendering
@@ -1081,7 +1125,6 @@ func (p *parser) parseAndRenderTableRow(line string) []tableCell {
 			width:       width,
 			longestWord: longestWordWidth(cellText),
 		})
-		start = i + 1
 	}
 
 	return cells
@@ -562,6 +562,55 @@ func TestFastRendererTablesColumnAlignment(t *testing.T) {
 	}
 }
 
+func TestFastRendererTableCellAlignment(t *testing.T) {
+	t.Parallel()
+
+	input := `| Left | Center | Right |
+|:-----|:------:|------:|
+| a | b | c |
+| longer | longer | longer |`
+
+	r := NewFastRenderer(80)
+	result, err := r.Render(input)
+	require.NoError(t, err)
+
+	plain := stripANSI(result)
+	assert.Contains(t, plain, "a      │   b    │      c")
+	assert.Contains(t, plain, "longer │ longer │ longer")
+	assertTableColumnsAligned(t, result)
+}
+
+func TestFastRendererTableEscapedPipes(t *testing.T) {
+	t.Parallel()
+
+	input := "| Operator | Example |\n|---|---|\n| or | `a | b` |\n| pipe | x \\| y |"
+
+	r := NewFastRenderer(80)
+	result, err := r.Render(input)
+	require.NoError(t, err)
+
+	plain := stripANSI(result)
+	assert.Contains(t, plain, "a | b")
+	assert.Contains(t, plain, "x | y")
+	assert.NotContains(t, plain, "\\|")
+	assertTableColumnsAligned(t, result)
+}
+
+func TestParseTableAlignments(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []tableAlign{alignLeft, alignLeft, alignCenter, alignRight}, parseTableAlignments("|---|:--|:-:|--:|"))
+}
+
+func TestSplitTableRow(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"a", "b", "c"}, splitTableRow("| a | b | c |"))
+	assert.Equal(t, []string{"a", "b"}, splitTableRow("a | b"))
+	assert.Equal(t, []string{"`x | y`", "z"}, splitTableRow("| `x | y` | z |"))
+	assert.Equal(t, []string{"x | y", "z"}, splitTableRow("| x \\| y | z |"))
+}
+
 func TestFastRendererTableViewportWidth(t *testing.T) {
 	t.Parallel()
 
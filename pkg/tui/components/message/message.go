@@ -8,6 +8,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/charmbracelet/x/ansi"
 
+	"github.com/docker/docker-agent/pkg/tui/components/imageview"
 	"github.com/docker/docker-agent/pkg/tui/components/markdown"
 	"github.com/docker/docker-agent/pkg/tui/components/spinner"
 	"github.com/docker/docker-agent/pkg/tui/core/layout"
@@ -93,15 +94,19 @@ func (mv *messageModel) Render(width int) string {
 			messageStyle = styles.SelectedUserMessageStyle
 		}
 
+		innerWidth := width - messageStyle.GetHorizontalFrameSize()
+		content := msg.Content
+		if len(msg.Images) > 0 {
+			content = strings.TrimRight(content, "\n\r\t ") + "\n\n" + imageview.View(msg.Images, innerWidth)
+		}
+
 		if msg.SessionPosition == nil {
-			return messageStyle.Width(width).Render(msg.Content)
+			return messageStyle.Width(width).Render(content)
 		}
 
 		// For editable messages, place the pencil icon in the top padding row
-		innerWidth := width - messageStyle.GetHorizontalFrameSize()
-		content := strings.TrimRight(msg.Content, "\n\r\t ")
-		if content == "" {
-			content = msg.Content
+		if trimmed := strings.TrimRight(content, "\n\r\t "); trimmed != "" {
+			content = trimmed
 		}
 
 		// Create the edit icon for the top row
@@ -146,6 +151,8 @@ func (mv *messageModel) Render(width int) string {
 		return msg.Content
 	case types.MessageTypeCancelled:
 		return styles.WarningStyle.Render("⚠ stream cancelled ⚠")
+	case types.MessageTypeRecord:
+		return styles.MutedStyle.Render("• " + msg.Content)
 	case types.MessageTypeWelcome:
 		messageStyle := styles.WelcomeMessageStyle
 		// Convert explicit newlines to markdown hard line breaks (two trailing spaces)
@@ -1,6 +1,7 @@
 package messages
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/charmbracelet/x/ansi"
 	"github.com/mattn/go-runewidth"
 
+	"github.com/docker/docker-agent/pkg/tui/components/markdown"
 	"github.com/docker/docker-agent/pkg/tui/components/notification"
 )
 
@@ -183,15 +185,47 @@ func (m *model) copySelectedMessageToClipboard() tea.Cmd {
 	return copyTextToClipboard(content)
 }
 
+// copySelectedCodeBlockToClipboard copies a code block of the selected message
+// to clipboard. Copying again from the same message copies its next code block.
+func (m *model) copySelectedCodeBlockToClipboard() tea.Cmd {
+	if m.selectedMessageIndex < 0 || m.selectedMessageIndex >= len(m.messages) {
+		return nil
+	}
+
+	blocks := markdown.CodeBlocks(m.messages[m.selectedMessageIndex].Content)
+	if len(blocks) == 0 {
+		return notification.InfoCmd("No code block in this message.")
+	}
+
+	block := 0
+	if m.lastCopiedCodeMsg == m.selectedMessageIndex {
+		block = (m.lastCopiedCodeBlock + 1) % len(blocks)
+	}
+	m.lastCopiedCodeMsg = m.selectedMessageIndex
+	m.lastCopiedCodeBlock = block
+
+	text := blocks[block]
+	if len(blocks) > 1 {
+		return copyTextToClipboardWithMessage(text, fmt.Sprintf("Code block %d/%d copied to clipboard.", block+1, len(blocks)))
+	}
+	return copyTextToClipboardWithMessage(text, "Code block copied to clipboard.")
+}
+
 // copyTextToClipboard copies text to the system clipboard
 func copyTextToClipboard(text string) tea.Cmd {
+	return copyTextToClipboardWithMessage(text, "Text copied to clipboard.")
+}
+
+// copyTextToClipboardWithMessage copies text to the system clipboard and
+// shows the given notification
+func copyTextToClipboardWithMessage(text, message string) tea.Cmd {
 	return tea.Sequence(
 		func() tea.Msg {
 			_ = clipboard.WriteAll(text)
 			return nil
 		},
 		tea.SetClipboard(text),
-		notification.SuccessCmd("Text copied to clipboard."),
+		notification.SuccessCmd(message),
 	)
 }
 
@@ -16,9 +16,12 @@ import (
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/termimage"
 	"github.com/docker/docker-agent/pkg/tools"
 	"github.com/docker/docker-agent/pkg/tools/builtin"
 	"github.com/docker/docker-agent/pkg/tui/animation"
+	"github.com/docker/docker-agent/pkg/tui/components/imageview"
+	"github.com/docker/docker-agent/pkg/tui/components/markdown"
 	"github.com/docker/docker-agent/pkg/tui/components/message"
 	"github.com/docker/docker-agent/pkg/tui/components/reasoningblock"
 	"github.com/docker/docker-agent/pkg/tui/components/scrollview"
@@ -45,7 +48,7 @@ type Model interface {
 
 	AddUserMessage(content string) tea.Cmd
 	AddLoadingMessage(description string) tea.Cmd
-	ReplaceLoadingWithUser(content string, sessionPos int) tea.Cmd
+	ReplaceLoadingWithUser(content string, images []*termimage.Image, sessionPos int) tea.Cmd
 	AddErrorMessage(content string) tea.Cmd
 	AddAssistantMessage() tea.Cmd
 	AddCancelledMessage() tea.Cmd
@@ -58,6 +61,9 @@ type Model interface {
 	LoadFromSession(sess *session.Session) tea.Cmd
 
 	RemoveSpinner()
+	// DiscardPartialResponse removes what an agent streamed of a response
+	// the runtime threw away, e.g. before retrying a model.
+	DiscardPartialResponse(agentName string)
 	ScrollToBottom() tea.Cmd
 	AdjustBottomSlack(delta int)
 
@@ -120,6 +126,10 @@ type model struct {
 	selectedMessageIndex int  // Index of selected message (-1 = no selection)
 	focused              bool // Whether the messages component is focused
 
+	// Code block copy state: pressing y again on the same message copies the next block
+	lastCopiedCodeMsg   int
+	lastCopiedCodeBlock int
+
 	// Inline editing state
 	inlineEditMsgIndex      int            // Index of message being edited (-1 = not editing)
 	inlineEditSessionPos    int            // Session position for branching
@@ -151,6 +161,7 @@ func newModel(width, height int, sessionState *service.SessionState) *model {
 		sessionState:         sessionState,
 		scrollview:           sv,
 		selectedMessageIndex: -1,
+		lastCopiedCodeMsg:    -1,
 		inlineEditMsgIndex:   -1,
 		renderDirty:          true,
 	}
@@ -433,6 +444,12 @@ func (m *model) handleKeyPress(msg tea.KeyPressMsg) (layout.Model, tea.Cmd) {
 			return m, cmd
 		}
 		return m, nil
+	case "y":
+		if m.focused && m.selectedMessageIndex >= 0 {
+			cmd := m.copySelectedCodeBlockToClipboard()
+			return m, cmd
+		}
+		return m, nil
 	case "e":
 		if m.focused && m.selectedMessageIndex >= 0 {
 			msg := m.messages[m.selectedMessageIndex]
@@ -628,6 +645,9 @@ func (m *model) Bindings() []key.Binding {
 	// Only show edit binding when a user message with session position is selected
 	if m.selectedMessageIndex >= 0 && m.selectedMessageIndex < len(m.messages) {
 		msg := m.messages[m.selectedMessageIndex]
+		if len(markdown.CodeBlocks(msg.Content)) > 0 {
+			bindings = append(bindings, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy code block")))
+		}
 		if msg.Type == types.MessageTypeUser && msg.SessionPosition != nil {
 			bindings = append(bindings, key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit message")))
 		}
@@ -1066,7 +1086,7 @@ func (m *model) AddLoadingMessage(description string) tea.Cmd {
 	return m.addMessage(types.Loading(description))
 }
 
-func (m *model) ReplaceLoadingWithUser(content string, sessionPos int) tea.Cmd {
+func (m *model) ReplaceLoadingWithUser(content string, images []*termimage.Image, sessionPos int) tea.Cmd {
 	for i := len(m.messages) - 1; i >= 0; i-- {
 		if m.messages[i].Type == types.MessageTypeLoading {
 			m.messages = append(m.messages[:i], m.messages[i+1:]...)
@@ -1078,11 +1098,12 @@ func (m *model) ReplaceLoadingWithUser(content string, sessionPos int) tea.Cmd {
 		}
 	}
 	msg := types.User(content)
+	msg.Images = images
 	if sessionPos >= 0 {
 		pos := sessionPos
 		msg.SessionPosition = &pos
 	}
-	return m.addMessage(msg)
+	return tea.Batch(m.addMessage(msg), imageview.TransmitCmd(images))
 }
 
 func (m *model) AddErrorMessage(content string) tea.Cmd {
@@ -1211,6 +1232,11 @@ func (m *model) LoadFromSession(sess *session.Session) tea.Cmd {
 	}
 
 	for pos, item := range sess.Messages {
+		if item.IsRecord() {
+			msg := types.Record(item.Record.AgentName, item.Record.String())
+			appendSessionMessage(msg, m.createMessageView(msg))
+			continue
+		}
 		if !item.IsMessage() {
 			continue
 		}
@@ -1246,7 +1272,7 @@ func (m *model) LoadFromSession(sess *session.Session) tea.Cmd {
 
 			// Step 2: Handle assistant content - this breaks the reasoning block chain
 			if hasContent {
-				msg := types.Agent(types.MessageTypeAssistant, smsg.AgentName, smsg.Message.Content)
+				msg := types.Agent(types.MessageTypeAssistant, smsg.AgentName, smsg.Message.Content+types.Sources(smsg.Message.Citations))
 				appendSessionMessage(msg, m.createMessageView(msg))
 			}
 
@@ -1329,6 +1355,11 @@ func (m *model) AddOrUpdateToolCall(agentName string, toolCall tools.ToolCall, t
 }
 
 func (m *model) AddToolResult(msg *runtime.ToolCallResponseEvent, status types.ToolStatus) tea.Cmd {
+	var imagesCmd tea.Cmd
+	if msg.Result != nil {
+		imagesCmd = imageview.TransmitCmd(imageview.FromMedia(msg.Result.Images))
+	}
+
 	// First check reasoning blocks for the tool call
 	for i := len(m.messages) - 1; i >= 0; i-- {
 		if m.messages[i].Type == types.MessageTypeAssistantReasoningBlock {
@@ -1336,7 +1367,7 @@ func (m *model) AddToolResult(msg *runtime.ToolCallResponseEvent, status types.T
 				if block.HasToolCall(msg.ToolCall.ID) {
 					cmd := block.UpdateToolResult(msg.ToolCall.ID, msg.Response, status, msg.Result)
 					m.invalidateItem(i)
-					return cmd
+					return tea.Batch(cmd, imagesCmd)
 				}
 			}
 		}
@@ -1353,7 +1384,7 @@ func (m *model) AddToolResult(msg *runtime.ToolCallResponseEvent, status types.T
 
 			view := m.createToolCallView(toolMessage)
 			m.views[i] = view
-			return view.Init()
+			return tea.Batch(view.Init(), imagesCmd)
 		}
 	}
 	return nil
@@ -1533,6 +1564,40 @@ func (m *model) removeSpinner() {
 	}
 }
 
+func (m *model) DiscardPartialResponse(agentName string) {
+	end := len(m.messages)
+	for end > 0 {
+		msg := m.messages[end-1]
+		if msg.Sender != agentName || !isPartialResponse(msg) {
+			break
+		}
+		end--
+	}
+	if end == len(m.messages) {
+		return
+	}
+
+	for i := end; i < len(m.views); i++ {
+		stopViewAnimation(m.views[i])
+	}
+	m.messages = m.messages[:end]
+	m.views = m.views[:min(end, len(m.views))]
+	m.invalidateAllItems()
+}
+
+// isPartialResponse reports whether a message can be part of a response that
+// is still streaming.
+func isPartialResponse(msg *types.Message) bool {
+	switch msg.Type {
+	case types.MessageTypeAssistant, types.MessageTypeAssistantReasoningBlock:
+		return true
+	case types.MessageTypeToolCall:
+		return msg.ToolStatus == types.ToolStatusPending
+	default:
+		return false
+	}
+}
+
 func (m *model) removePendingToolCallMessages() {
 	toolCallMessages := make([]*types.Message, 0, len(m.messages))
 	views := make([]layout.Model, 0, len(m.views))
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/assert"
@@ -1140,3 +1141,49 @@ func TestBindingsExcludesEditKeyWhenAssistantMessageSelected(t *testing.T) {
 	}
 	assert.False(t, foundE, "Bindings should NOT include 'e' key when assistant message is selected")
 }
+
+func TestKeyYCyclesThroughCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	sessionState := &service.SessionState{}
+	m := NewScrollableView(80, 24, sessionState).(*model)
+	m.SetSize(80, 24)
+
+	assistantMsg := types.Agent(types.MessageTypeAssistant, "root", "```sh\nmake\n```\nand\n```sh\nmake test\n```")
+	m.messages = append(m.messages, assistantMsg)
+	m.views = append(m.views, m.createMessageView(assistantMsg))
+	m.Focus()
+
+	assert.True(t, slices.ContainsFunc(m.Bindings(), func(b key.Binding) bool {
+		return slices.Contains(b.Keys(), "y")
+	}), "Bindings should include 'y' key when the selected message has code blocks")
+
+	for _, want := range []int{0, 1, 0} {
+		_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+		assert.NotNil(t, cmd)
+		assert.Equal(t, want, m.lastCopiedCodeBlock)
+	}
+}
+
+func TestDiscardPartialResponse(t *testing.T) {
+	t.Parallel()
+
+	sessionState := &service.SessionState{}
+	m := NewScrollableView(80, 24, sessionState).(*model)
+	m.SetSize(80, 24)
+
+	m.AddUserMessage("Hello")
+	m.AppendToLastMessage("other", "Done.")
+	m.AppendReasoning("root", "Thinking")
+	m.AppendToLastMessage("root", "Partial answ")
+
+	m.DiscardPartialResponse("root")
+
+	require.Len(t, m.messages, 2)
+	require.Len(t, m.views, 2)
+	assert.Equal(t, "Done.", m.messages[1].Content)
+
+	// Nothing left to discard
+	m.DiscardPartialResponse("root")
+	assert.Len(t, m.messages, 2)
+}
@@ -15,6 +15,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/paths"
 	"github.com/docker/docker-agent/pkg/runtime"
@@ -105,6 +106,12 @@ type ragIndexingState struct {
 	spinner spinner.Spinner
 }
 
+// toolsetStartupState is an entry of the toolset startup checklist
+type toolsetStartupState struct {
+	name   string
+	status agent.ToolSetStatus
+}
+
 // model implements Model
 type model struct {
 	width              int
@@ -116,6 +123,7 @@ type model struct {
 	sessionAgent       map[string]string         // sessionID -> agent name
 	todoComp           *todotool.SidebarComponent
 	mcpInit            bool
+	toolsetStartup     []toolsetStartupState        // startup checklist, while toolsets are starting
 	ragIndexing        map[string]*ragIndexingState // strategy name -> indexing state
 	spinner            spinner.Spinner
 	spinnerActive      bool // true when spinner is registered with animation coordinator
@@ -203,7 +211,7 @@ func (m *model) Init() tea.Cmd {
 
 // needsSpinner returns true if any spinner-driving state is active.
 func (m *model) needsSpinner() bool {
-	return m.workingAgent != "" || m.toolsLoading || m.mcpInit || m.titleRegenerating
+	return m.workingAgent != "" || m.toolsLoading || m.mcpInit || m.titleRegenerating || len(m.toolsetStartup) > 0
 }
 
 // startSpinner registers the spinner with the animation coordinator if not already active.
@@ -468,6 +476,7 @@ func (m *model) LoadFromSession(sess *session.Session) {
 			OutputTokens:  sess.OutputTokens,
 			ContextLength: sess.InputTokens + sess.OutputTokens,
 			Cost:          totalCost,
+			Cache:         runtime.NewCacheUsage(sess.OwnUsage()),
 		}
 	}
 
@@ -562,6 +571,14 @@ func (m *model) contextPercent() string {
 	return ""
 }
 
+// cacheHitPercent returns the prompt cache hit rate for the current agent's session.
+func (m *model) cacheHitPercent() string {
+	if usage, ok := m.currentSessionUsage(); ok && usage.Cache != nil {
+		return fmt.Sprintf("%.0f%%", usage.Cache.HitRate()*100)
+	}
+	return ""
+}
+
 // getCurrentWorkingDirectory returns the current working directory with home directory replaced by ~/
 func getCurrentWorkingDirectory() string {
 	pwd, err := os.Getwd()
@@ -611,6 +628,8 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 			m.stopSpinner() // Will only stop if no other state needs it
 		}
 		return m, nil
+	case *runtime.ToolsetStatusEvent:
+		return m, m.setToolsetStatus(msg.Toolset, msg.Status)
 	case *runtime.RAGIndexingStartedEvent:
 		// Ignore if stream was cancelled (stale event from before cancellation)
 		if m.streamCancelled {
@@ -722,6 +741,7 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		m.workingAgent = ""
 		m.toolsLoading = false
 		m.mcpInit = false
+		m.toolsetStartup = nil
 		m.titleRegenerating = false
 		// Force-stop main spinner if it was active (state is now cleared)
 		if m.spinnerActive {
@@ -769,7 +789,7 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		needsInvalidate := false
 
 		// Update main spinner when MCP is initializing, tools are loading, agent is working, or title is regenerating
-		if m.mcpInit || m.toolsLoading || m.workingAgent != "" || m.titleRegenerating {
+		if m.needsSpinner() {
 			model, cmd := m.spinner.Update(msg)
 			m.spinner = model.(spinner.Spinner)
 			cmds = append(cmds, cmd)
@@ -973,7 +993,22 @@ func (m *model) groupedRAGIndexing() (ragNames []string, ragGroups map[string][]
 func (m *model) workingIndicator() string {
 	var indicators []string
 
-	if m.mcpInit {
+	switch {
+	case len(m.toolsetStartup) > 0:
+		indicators = append(indicators, styles.ActiveStyle.Render(m.spinner.View()+" Starting toolsets "+m.toolsetStartupProgress()))
+		for _, ts := range m.toolsetStartup {
+			var icon string
+			switch ts.status {
+			case agent.ToolSetReady:
+				icon = styles.SuccessStyle.Render("✓")
+			case agent.ToolSetFailed:
+				icon = styles.ErrorStyle.Render("✗")
+			default:
+				icon = m.spinner.View()
+			}
+			indicators = append(indicators, "  "+icon+" "+ts.name)
+		}
+	case m.mcpInit:
 		indicators = append(indicators, styles.ActiveStyle.Render(m.spinner.View()+" Initializing MCP servers…"))
 	}
 
@@ -1006,7 +1041,10 @@ func (m *model) workingIndicator() string {
 func (m *model) workingIndicatorCollapsed() string {
 	var labels []string
 
-	if m.mcpInit {
+	switch {
+	case len(m.toolsetStartup) > 0:
+		labels = append(labels, "Starting toolsets "+m.toolsetStartupProgress())
+	case m.mcpInit:
 		labels = append(labels, "Initializing MCP servers…")
 	}
 
@@ -1044,6 +1082,7 @@ func (m *model) formatProgress(state *ragIndexingState) string {
 type usageStats struct {
 	tokens       int64
 	contextPct   string
+	cacheHitPct  string
 	totalCost    float64
 	sessionCount int
 }
@@ -1056,6 +1095,7 @@ func (m *model) computeUsageStats() usageStats {
 	}
 	s.tokens, _ = m.currentSessionTokens()
 	s.contextPct = m.contextPercent()
+	s.cacheHitPct = m.cacheHitPercent()
 	return s
 }
 
@@ -1067,6 +1107,9 @@ func (m *model) tokenUsage(contentWidth int) string {
 		line += " (" + s.contextPct + ")"
 	}
 	line += " " + styles.TabAccentStyle.Render("$"+formatCost(s.totalCost))
+	if s.cacheHitPct != "" {
+		line += " " + styles.MutedStyle.Render(s.cacheHitPct+" cached")
+	}
 	if s.sessionCount > 1 {
 		line += " " + styles.MutedStyle.Render(fmt.Sprintf("(%d sub-sessions)", s.sessionCount-1))
 	}
@@ -1094,6 +1137,9 @@ func (m *model) tokenUsageSummary() string {
 			parts = append(parts, "Context: "+s.contextPct)
 		}
 	}
+	if s.cacheHitPct != "" {
+		parts = append(parts, "Cached: "+s.cacheHitPct)
+	}
 
 	return strings.Join(parts, " | ")
 }
@@ -1248,6 +1294,7 @@ func (m *model) toolsetInfo(contentWidth int) string {
 		shortcut string
 	}{
 		{m.sessionState.YoloMode(), "YOLO mode enabled", "^y"},
+		{m.sessionState.Simulate(), "Simulating tool calls", "/simulate"},
 		{m.sessionState.Thinking() && m.reasoningSupported, "Thinking enabled", "/think"},
 		{m.sessionState.HideToolResults(), "Tool output hidden", "^o"},
 		{m.sessionState.SplitDiffView(), "Split Diff View", "/split-diff"},
@@ -1266,6 +1313,46 @@ func (m *model) toolsetInfo(contentWidth int) string {
 	return m.renderTab("Tools", lipgloss.JoinVertical(lipgloss.Top, lines...), contentWidth)
 }
 
+// setToolsetStatus updates the toolset startup checklist. The checklist is
+// cleared once no toolset is starting anymore; failures are reported as
+// warnings by the runtime.
+func (m *model) setToolsetStatus(name string, status agent.ToolSetStatus) tea.Cmd {
+	// Ignore if stream was cancelled (stale event from before cancellation)
+	if m.streamCancelled && status == agent.ToolSetStarting {
+		return nil
+	}
+
+	i := slices.IndexFunc(m.toolsetStartup, func(ts toolsetStartupState) bool { return ts.name == name })
+	switch {
+	case i >= 0:
+		m.toolsetStartup[i].status = status
+	case status == agent.ToolSetStarting:
+		m.toolsetStartup = append(m.toolsetStartup, toolsetStartupState{name: name, status: status})
+	default:
+		return nil
+	}
+	m.invalidateCache()
+
+	if slices.ContainsFunc(m.toolsetStartup, func(ts toolsetStartupState) bool { return ts.status == agent.ToolSetStarting }) {
+		return m.startSpinner()
+	}
+	m.toolsetStartup = nil
+	m.stopSpinner() // Will only stop if no other state needs it
+	return nil
+}
+
+// toolsetStartupProgress returns the number of toolsets done starting out of
+// the toolsets of the checklist, e.g. "(2/5)".
+func (m *model) toolsetStartupProgress() string {
+	var done int
+	for _, ts := range m.toolsetStartup {
+		if ts.status != agent.ToolSetStarting {
+			done++
+		}
+	}
+	return fmt.Sprintf("(%d/%d)", done, len(m.toolsetStartup))
+}
+
 // renderToolsStatus renders the tools available/loading status line
 func (m *model) renderToolsStatus() string {
 	if m.toolsLoading {
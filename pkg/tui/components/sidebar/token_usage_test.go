@@ -276,3 +276,30 @@ func TestContextPercent_SessionIDTakesPrecedence(t *testing.T) {
 		assert.Equal(t, "50%", m.contextPercent(), "contextPercent() returned inconsistent value — the flickering bug is back")
 	}
 }
+
+func TestCacheHitPercent(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New()
+	sessionState := service.NewSessionState(sess)
+	m := New(sessionState).(*model)
+	m.currentAgent = "root"
+
+	m.SetTokenUsage(&runtime.TokenUsageEvent{
+		SessionID:    "session-1",
+		AgentContext: runtime.AgentContext{AgentName: "root"},
+		Usage:        &runtime.Usage{InputTokens: 5000, OutputTokens: 3000},
+	})
+	assert.Empty(t, m.cacheHitPercent(), "no cache usage reported yet")
+
+	m.SetTokenUsage(&runtime.TokenUsageEvent{
+		SessionID:    "session-1",
+		AgentContext: runtime.AgentContext{AgentName: "root"},
+		Usage: &runtime.Usage{
+			InputTokens:  5000,
+			OutputTokens: 3000,
+			Cache:        &runtime.CacheUsage{InputTokens: 100, ReadTokens: 300, WriteTokens: 100},
+		},
+	})
+	assert.Equal(t, "60%", m.cacheHitPercent())
+}
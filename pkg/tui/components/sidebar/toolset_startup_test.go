@@ -0,0 +1,38 @@
+package sidebar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tui/service"
+)
+
+func TestSidebar_ToolsetStartupChecklist(t *testing.T) {
+	t.Parallel()
+
+	sb := New(&service.SessionState{})
+	m := sb.(*model)
+
+	sb.Update(runtime.ToolsetStatus("mcp(github)", agent.ToolSetStarting, nil, "root"))
+	sb.Update(runtime.ToolsetStatus("lsp(gopls)", agent.ToolSetStarting, nil, "root"))
+	assert.True(t, m.needsSpinner(), "should need spinner while toolsets are starting")
+	assert.Contains(t, m.workingIndicator(), "Starting toolsets (0/2)")
+
+	sb.Update(runtime.ToolsetStatus("mcp(github)", agent.ToolSetReady, nil, "root"))
+	indicator := ansi.Strip(m.workingIndicator())
+	assert.Contains(t, indicator, "Starting toolsets (1/2)")
+	assert.Contains(t, indicator, "✓ mcp(github)")
+	assert.Contains(t, indicator, "lsp(gopls)")
+	assert.Contains(t, m.workingIndicatorCollapsed(), "Starting toolsets (1/2)")
+
+	// The checklist goes away once every toolset is done starting
+	sb.Update(runtime.ToolsetStatus("lsp(gopls)", agent.ToolSetFailed, errors.New("not found"), "root"))
+	assert.Empty(t, m.toolsetStartup)
+	assert.False(t, m.needsSpinner(), "should not need spinner once toolsets are started")
+	assert.Empty(t, m.workingIndicator())
+}
@@ -1,11 +1,13 @@
 package statusbar
 
 import (
+	"fmt"
 	"strings"
 
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
 
+	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/tui/core"
 	"github.com/docker/docker-agent/pkg/tui/styles"
 	"github.com/docker/docker-agent/pkg/version"
@@ -13,10 +15,14 @@ import (
 
 // StatusBar displays key-binding help on the left and version info on the right.
 // When the tab bar is hidden (single tab), it also shows a clickable "+ new tab" button.
+// Once the active session made a model call, it also shows how much of the
+// context window is in use.
 type StatusBar struct {
 	width int
 	help  core.KeyMapHelp
 
+	contextUsage *runtime.ContextUsageEvent
+
 	showNewTab   bool
 	newTabStartX int
 	newTabEndX   int
@@ -55,6 +61,15 @@ func (s *StatusBar) SetShowNewTab(show bool) {
 	}
 }
 
+// SetContextUsage sets the context usage of the active session, or nil to
+// hide it.
+func (s *StatusBar) SetContextUsage(usage *runtime.ContextUsageEvent) {
+	if s.contextUsage != usage {
+		s.contextUsage = usage
+		s.cacheDirty = true
+	}
+}
+
 // ClickedNewTab returns true if the given X coordinate hits the "+" button.
 func (s *StatusBar) ClickedNewTab(x int) bool {
 	return s.showNewTab && x >= s.newTabStartX && x < s.newTabEndX
@@ -76,7 +91,8 @@ func (s *StatusBar) rebuild() {
 	s.newTabStartX = 0
 	s.newTabEndX = 0
 
-	// Build the styled right side: optional new-tab button + version.
+	// Build the styled right side: optional context usage + optional
+	// new-tab button + version.
 	var right string
 	var rightW, newTabW int
 	ver := styles.MutedStyle.Render("docker agent " + version.Version)
@@ -86,11 +102,20 @@ func (s *StatusBar) rebuild() {
 			styles.SecondaryStyle.Render(" new tab")
 		newTabW = lipgloss.Width(newTab)
 		right = newTab + "  " + ver
-		rightW = lipgloss.Width(right)
 	} else {
 		right = ver
-		rightW = lipgloss.Width(right)
 	}
+	var contextW int
+	if s.contextUsage != nil {
+		contextStyle := styles.MutedStyle
+		if s.contextUsage.Threshold > 0 {
+			contextStyle = styles.WarningStyle
+		}
+		context := contextStyle.Render(fmt.Sprintf("context %d%%", s.contextUsage.Percent)) + "  "
+		contextW = lipgloss.Width(context)
+		right = context + right
+	}
+	rightW = lipgloss.Width(right)
 
 	// Build the styled left side: help bindings (possibly truncated).
 	const pad = 1
@@ -125,7 +150,7 @@ func (s *StatusBar) rebuild() {
 	gap := max(1, s.width-leftW-rightW-pad)
 
 	if s.showNewTab {
-		s.newTabStartX = leftW + gap
+		s.newTabStartX = leftW + gap + contextW
 		s.newTabEndX = s.newTabStartX + newTabW
 	}
 
@@ -134,7 +159,7 @@ func (s *StatusBar) rebuild() {
 
 // View renders the status bar.
 //
-// Layout: [ help text ...           (+ new tab)  docker agent VERSION ]
+// Layout: [ help text ...   (context N%)  (+ new tab)  docker agent VERSION ]
 func (s *StatusBar) View() string {
 	if s.cacheDirty {
 		s.rebuild()
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/docker/go-units"
 
@@ -31,7 +32,13 @@ func render(msg *types.Message, s spinner.Spinner, sessionState service.SessionS
 	}
 
 	// Add inline result/progress after the tool name
+	var result string
 	switch msg.ToolStatus {
+	case types.ToolStatusConfirmation:
+		// Show every URL the call requests, for the user to confirm them all
+		if urls := extractURLs(args); len(urls) > 1 {
+			result = strings.Join(urls, "\n")
+		}
 	case types.ToolStatusRunning:
 		// While running, show what we're calling
 		if endpoint := extractEndpoint(args); endpoint != "" {
@@ -42,7 +49,7 @@ func render(msg *types.Message, s spinner.Spinner, sessionState service.SessionS
 		params += styles.MutedStyle.Render(": Received " + units.HumanSize(float64(len(msg.Content))))
 	}
 
-	return toolcommon.RenderTool(msg, s, params, "", width, sessionState.HideToolResults())
+	return toolcommon.RenderTool(msg, s, params, result, width, sessionState.HideToolResults())
 }
 
 // extractEndpoint tries to find the endpoint/URL being called.
@@ -56,6 +63,18 @@ func extractEndpoint(args map[string]any) string {
 	return ""
 }
 
+// extractURLs returns the URLs of the urls field, used by fetch tools.
+func extractURLs(args map[string]any) []string {
+	urlsVal, _ := args["urls"].([]any)
+	var urls []string
+	for _, u := range urlsVal {
+		if urlStr, ok := u.(string); ok {
+			urls = append(urls, urlStr)
+		}
+	}
+	return urls
+}
+
 // formatArgs creates a concise string representation of the arguments.
 func formatArgs(args map[string]any) string {
 	if len(args) == 0 {
@@ -66,19 +85,10 @@ func formatArgs(args map[string]any) string {
 	if urlVal, ok := args["url"].(string); ok && urlVal != "" {
 		return urlVal
 	}
-	if urlsVal, ok := args["urls"].([]any); ok && len(urlsVal) > 0 {
-		// Extract just the URLs from the array
-		var urls []string
-		for _, u := range urlsVal {
-			if urlStr, ok := u.(string); ok {
-				urls = append(urls, urlStr)
-			}
-		}
-		if len(urls) == 1 {
-			return urls[0]
-		} else if len(urls) > 1 {
-			return fmt.Sprintf("%s (+%d more)", urls[0], len(urls)-1)
-		}
+	if urls := extractURLs(args); len(urls) == 1 {
+		return urls[0]
+	} else if len(urls) > 1 {
+		return fmt.Sprintf("%s (+%d more)", urls[0], len(urls)-1)
 	}
 
 	// Try to find common parameter names that might indicate what's being queried
@@ -91,6 +91,19 @@ func getOrCreateCache(toolCallID string) *toolRenderCache {
 }
 
 func renderEditFile(toolCall tools.ToolCall, width int, splitView bool, toolStatus types.ToolStatus) string {
+	return renderCached(toolCall, width, splitView, toolStatus, renderEditFileUncached)
+}
+
+// RenderWriteFile renders the diff between a file and the content a
+// write_file tool call writes to it, for the user to confirm the call. New
+// files are diffed against an empty file.
+func RenderWriteFile(toolCall tools.ToolCall, width int, splitView bool) string {
+	return renderCached(toolCall, width, splitView, types.ToolStatusConfirmation, renderWriteFileUncached)
+}
+
+// renderCached renders a tool call, caching the result per tool call until
+// the width, the diff view or the status changes.
+func renderCached(toolCall tools.ToolCall, width int, splitView bool, toolStatus types.ToolStatus, render func(tools.ToolCall, int, bool, types.ToolStatus) string) string {
 	c := getOrCreateCache(toolCall.ID)
 
 	cacheMu.RLock()
@@ -104,7 +117,7 @@ func renderEditFile(toolCall tools.ToolCall, width int, splitView bool, toolStat
 	}
 	cacheMu.RUnlock()
 
-	result := renderEditFileUncached(toolCall, width, splitView, toolStatus)
+	result := render(toolCall, width, splitView, toolStatus)
 
 	cacheMu.Lock()
 	c.rendered = result
@@ -144,6 +157,28 @@ func renderEditFileUncached(toolCall tools.ToolCall, width int, splitView bool,
 	return output.String()
 }
 
+func renderWriteFileUncached(toolCall tools.ToolCall, width int, splitView bool, _ types.ToolStatus) string {
+	var args builtin.WriteFileArgs
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+		return ""
+	}
+
+	// A missing file is created: everything is added.
+	currentContent, _ := os.ReadFile(args.Path)
+	oldContent := string(currentContent)
+	edits := udiff.Strings(oldContent, args.Content)
+	diff, err := udiff.ToUnifiedDiff("old", "new", oldContent, edits, 3)
+	if err != nil || len(diff.Hunks) == 0 {
+		return styles.MutedStyle.Render("No changes")
+	}
+
+	hunks := normalizeDiff(diff.Hunks)
+	if splitView {
+		return renderSplitDiffWithSyntaxHighlight(hunks, args.Path, width)
+	}
+	return renderDiffWithSyntaxHighlight(hunks, args.Path, width)
+}
+
 // countDiffLines returns the number of added and removed lines for the edit.
 // Results are cached per tool call since arguments are immutable.
 func countDiffLines(toolCall tools.ToolCall, _ types.ToolStatus) (added, removed int) {
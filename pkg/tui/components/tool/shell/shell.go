@@ -10,6 +10,15 @@ import (
 
 func New(msg *types.Message, sessionState service.SessionStateReader) layout.Model {
 	return toolcommon.NewBase(msg, sessionState, toolcommon.SimpleRenderer(
-		toolcommon.ExtractField(func(a builtin.RunShellArgs) string { return a.Cmd }),
+		toolcommon.ExtractField(command),
 	))
 }
+
+// command returns the full command run by the shell, with its working
+// directory when it's not the default one.
+func command(a builtin.RunShellArgs) string {
+	if a.Cwd == "" || a.Cwd == "." {
+		return a.Cmd
+	}
+	return a.Cmd + " (in " + a.Cwd + ")"
+}
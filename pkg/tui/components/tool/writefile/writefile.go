@@ -2,14 +2,33 @@ package writefile
 
 import (
 	"github.com/docker/docker-agent/pkg/tools/builtin"
+	"github.com/docker/docker-agent/pkg/tui/components/spinner"
+	"github.com/docker/docker-agent/pkg/tui/components/tool/editfile"
 	"github.com/docker/docker-agent/pkg/tui/components/toolcommon"
 	"github.com/docker/docker-agent/pkg/tui/core/layout"
 	"github.com/docker/docker-agent/pkg/tui/service"
+	"github.com/docker/docker-agent/pkg/tui/styles"
 	"github.com/docker/docker-agent/pkg/tui/types"
 )
 
+var renderPath = toolcommon.SimpleRenderer(
+	toolcommon.ExtractField(func(a builtin.WriteFileArgs) string { return a.Path }),
+)
+
 func New(msg *types.Message, sessionState service.SessionStateReader) layout.Model {
-	return toolcommon.NewBase(msg, sessionState, toolcommon.SimpleRenderer(
-		toolcommon.ExtractField(func(a builtin.WriteFileArgs) string { return a.Path }),
-	))
+	return toolcommon.NewBase(msg, sessionState, render)
+}
+
+// render shows the path of the file and, while the call waits for
+// confirmation, the diff of the file with the content to write.
+func render(msg *types.Message, s spinner.Spinner, sessionState service.SessionStateReader, width, height int) string {
+	content := renderPath(msg, s, sessionState, width, height)
+	if msg.ToolStatus != types.ToolStatusConfirmation || sessionState.HideToolResults() || msg.ToolCall.Function.Arguments == "" {
+		return content
+	}
+
+	contentWidth := width - styles.ToolCallResult.GetHorizontalFrameSize()
+	return content + "\n" + styles.ToolCallResult.Render(
+		editfile.RenderWriteFile(msg.ToolCall, contentWidth, sessionState.SplitDiffView()),
+	)
 }
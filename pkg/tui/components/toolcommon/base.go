@@ -2,7 +2,9 @@ package toolcommon
 
 import (
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
 
+	"github.com/docker/docker-agent/pkg/tui/components/imageview"
 	"github.com/docker/docker-agent/pkg/tui/components/spinner"
 	"github.com/docker/docker-agent/pkg/tui/core/layout"
 	"github.com/docker/docker-agent/pkg/tui/service"
@@ -124,7 +126,15 @@ func (b *Base) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 }
 
 func (b *Base) View() string {
-	return b.render(b.message, b.spinner, b.sessionState, b.width, b.height)
+	view := b.render(b.message, b.spinner, b.sessionState, b.width, b.height)
+
+	// Show the images returned by the tool, e.g. read_file on a picture
+	if result := b.message.ToolResult; result != nil && len(result.Images) > 0 && !b.sessionState.HideToolResults() {
+		margin := styles.ToolCompletedIcon.GetMarginLeft()
+		images := imageview.View(imageview.FromMedia(result.Images), b.width-margin)
+		view += "\n" + lipgloss.NewStyle().MarginLeft(margin).Render(images)
+	}
+	return view
 }
 
 // CollapsedView returns a simplified view for use in collapsed reasoning blocks.
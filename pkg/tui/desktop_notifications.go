@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"cmp"
+
+	"github.com/docker/docker-agent/pkg/desktopnotify"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+// desktopNotification returns the title and message of the desktop
+// notification for an event of a session, if the event needs the user's
+// attention. depth is the number of streams of the session that are still
+// running after the event, so that only the end of a whole run is notified.
+func desktopNotification(sessionTitle string, event runtime.Event, depth int) (title, message string, ok bool) {
+	title = "docker agent"
+	if sessionTitle != "" {
+		title = sessionTitle + " - docker agent"
+	}
+	agentName := cmp.Or(event.GetAgentName(), "The agent")
+
+	switch ev := event.(type) {
+	case *runtime.ToolCallConfirmationEvent:
+		return title, agentName + " needs approval to run " + ev.ToolCall.Function.Name, true
+	case *runtime.ToolResultConfirmationEvent:
+		return title, agentName + " needs approval to read the flagged result of " + ev.ToolCall.Function.Name, true
+	case *runtime.ElicitationRequestEvent:
+		if reason, ok := ev.Meta[builtin.EscalationMetaKey].(string); ok {
+			return title, agentName + " needs a human: " + reason, true
+		}
+		return title, agentName + " is waiting for your input", true
+	case *runtime.StreamStoppedEvent:
+		if depth > 0 {
+			return "", "", false
+		}
+		return title, agentName + " finished", true
+	}
+	return "", "", false
+}
+
+// notifyDesktop sends a desktop notification for a runtime event of a
+// session when the terminal is in the background.
+func (m *appModel) notifyDesktop(sessionID string, event runtime.Event) {
+	if !m.desktopNotifications {
+		return
+	}
+
+	switch event.(type) {
+	case *runtime.StreamStartedEvent:
+		m.streamDepths[sessionID]++
+	case *runtime.StreamStoppedEvent:
+		m.streamDepths[sessionID] = max(m.streamDepths[sessionID]-1, 0)
+	}
+
+	if !m.backgrounded {
+		return
+	}
+
+	var sessionTitle string
+	if sessionState, ok := m.sessionStates[sessionID]; ok {
+		sessionTitle = sessionState.SessionTitle()
+	}
+	if title, message, ok := desktopNotification(sessionTitle, event, m.streamDepths[sessionID]); ok {
+		desktopnotify.Send(title, message)
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+func TestDesktopNotification(t *testing.T) {
+	t.Parallel()
+
+	confirmation := runtime.ToolCallConfirmation(tools.ToolCall{Function: tools.FunctionCall{Name: "shell"}}, tools.Tool{}, "root")
+	title, message, ok := desktopNotification("Fix the build", confirmation, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "Fix the build - docker agent", title)
+	assert.Equal(t, "root needs approval to run shell", message)
+
+	_, message, ok = desktopNotification("", runtime.ElicitationRequest("Name?", "form", nil, "", "", nil, "root"), 1)
+	assert.True(t, ok)
+	assert.Equal(t, "root is waiting for your input", message)
+
+	escalation := runtime.ElicitationRequest("Help", "form", nil, "", "", map[string]any{builtin.EscalationMetaKey: "tests keep failing"}, "root")
+	_, message, ok = desktopNotification("", escalation, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "root needs a human: tests keep failing", message)
+
+	// Only the end of the outermost stream finishes the run.
+	_, _, ok = desktopNotification("", runtime.StreamStopped("sub", "helper"), 1)
+	assert.False(t, ok)
+	title, message, ok = desktopNotification("", runtime.StreamStopped("sess", "root"), 0)
+	assert.True(t, ok)
+	assert.Equal(t, "docker agent", title)
+	assert.Equal(t, "root finished", message)
+
+	_, _, ok = desktopNotification("", runtime.StreamStarted("sess", "root"), 1)
+	assert.False(t, ok)
+}
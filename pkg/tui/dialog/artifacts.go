@@ -0,0 +1,155 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/atotto/clipboard"
+
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tui/components/notification"
+	"github.com/docker/docker-agent/pkg/tui/components/scrollview"
+	"github.com/docker/docker-agent/pkg/tui/core"
+	"github.com/docker/docker-agent/pkg/tui/core/layout"
+	"github.com/docker/docker-agent/pkg/tui/styles"
+)
+
+// artifactsDialog lists the files that tools registered as artifacts of the
+// session.
+type artifactsDialog struct {
+	BaseDialog
+	artifacts  []session.Artifact
+	closeKey   key.Binding
+	copyKey    key.Binding
+	scrollview *scrollview.Model
+}
+
+// NewArtifactsDialog creates a new dialog listing the artifacts of a session.
+func NewArtifactsDialog(artifacts []session.Artifact) Dialog {
+	return &artifactsDialog{
+		artifacts: artifacts,
+		scrollview: scrollview.New(
+			scrollview.WithKeyMap(scrollview.ReadOnlyScrollKeyMap()),
+			scrollview.WithReserveScrollbarSpace(true),
+		),
+		closeKey: key.NewBinding(key.WithKeys("esc", "enter", "q"), key.WithHelp("Esc", "close")),
+		copyKey:  key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy paths")),
+	}
+}
+
+func (d *artifactsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *artifactsDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	if handled, cmd := d.scrollview.Update(msg); handled {
+		return d, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.closeKey):
+			return d, core.CmdHandler(CloseDialogMsg{})
+		case key.Matches(msg, d.copyKey) && len(d.artifacts) > 0:
+			paths := make([]string, len(d.artifacts))
+			for i, artifact := range d.artifacts {
+				paths[i] = artifact.Path
+			}
+			_ = clipboard.WriteAll(strings.Join(paths, "\n"))
+			return d, notification.SuccessCmd("Artifact paths copied to clipboard.")
+		}
+	}
+	return d, nil
+}
+
+func (d *artifactsDialog) dialogSize() (dialogWidth, maxHeight, contentWidth int) {
+	dialogWidth = d.ComputeDialogWidth(70, 50, 90)
+	maxHeight = min(d.Height()*70/100, 40)
+	contentWidth = d.ContentWidth(dialogWidth, 2) - d.scrollview.ReservedCols()
+	return dialogWidth, maxHeight, contentWidth
+}
+
+func (d *artifactsDialog) Position() (row, col int) {
+	dialogWidth, maxHeight, _ := d.dialogSize()
+	return CenterPosition(d.Width(), d.Height(), dialogWidth, maxHeight)
+}
+
+func (d *artifactsDialog) View() string {
+	dialogWidth, maxHeight, contentWidth := d.dialogSize()
+	content := d.renderContent(contentWidth, maxHeight)
+	return styles.DialogStyle.Padding(1, 2).Width(dialogWidth).Render(content)
+}
+
+func (d *artifactsDialog) renderContent(contentWidth, maxHeight int) string {
+	lines := []string{
+		RenderTitle("Session Artifacts", contentWidth, styles.DialogTitleStyle),
+		RenderSeparator(contentWidth),
+		"",
+	}
+
+	if len(d.artifacts) == 0 {
+		lines = append(lines, styles.MutedStyle.Render("No artifacts registered in this session."), "")
+	}
+	for _, artifact := range d.artifacts {
+		lines = append(lines, d.renderArtifact(artifact, contentWidth)...)
+		lines = append(lines, "")
+	}
+
+	return d.applyScrolling(lines, contentWidth, maxHeight)
+}
+
+func (d *artifactsDialog) renderArtifact(artifact session.Artifact, contentWidth int) []string {
+	name := lipgloss.NewStyle().Bold(true).Foreground(styles.Highlight).Render(artifact.Name)
+	meta := []string{formatFileSize(artifact.Size)}
+	if artifact.MimeType != "" {
+		meta = append(meta, artifact.MimeType)
+	}
+	if artifact.ToolName != "" {
+		meta = append(meta, "by "+artifact.ToolName)
+	}
+
+	lines := []string{name + "  " + styles.MutedStyle.Render(strings.Join(meta, "  •  "))}
+	if artifact.Description != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.TextSecondary).Width(contentWidth).Render(artifact.Description))
+	}
+	lines = append(lines, styles.MutedStyle.Render(artifact.Path))
+	return lines
+}
+
+func (d *artifactsDialog) applyScrolling(allLines []string, contentWidth, maxHeight int) string {
+	const headerLines = 3 // title + separator + space
+	const footerLines = 2 // space + help
+
+	visibleLines := max(1, maxHeight-headerLines-footerLines-4)
+	contentLines := allLines[headerLines:]
+
+	regionWidth := contentWidth + d.scrollview.ReservedCols()
+	d.scrollview.SetSize(regionWidth, visibleLines)
+
+	dialogRow, dialogCol := d.Position()
+	d.scrollview.SetPosition(dialogCol+3, dialogRow+2+headerLines)
+	d.scrollview.SetContent(contentLines, len(contentLines))
+
+	parts := append(allLines[:headerLines:headerLines], d.scrollview.View())
+	parts = append(parts, "", RenderHelpKeys(regionWidth, "↑↓", "scroll", "c", "copy paths", "Esc", "close"))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func formatFileSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -356,17 +356,29 @@ func (d *commandPaletteDialog) renderCommand(cmd commands.Item, selected bool, c
 	label := " " + cmd.Label
 	labelWidth := lipgloss.Width(actionStyle.Render(label))
 
+	// Keyboard shortcuts are right-aligned
+	var shortcut string
+	if cmd.Shortcut != "" {
+		shortcut = descStyle.Render(cmd.Shortcut + " ")
+	}
+	shortcutWidth := lipgloss.Width(shortcut)
+
 	var content string
 	content += actionStyle.Render(label)
 	if cmd.Description != "" {
 		separator := " • "
 		separatorWidth := lipgloss.Width(separator)
-		availableWidth := contentWidth - labelWidth - separatorWidth
+		availableWidth := contentWidth - labelWidth - separatorWidth - shortcutWidth - 1
 		if availableWidth > 0 {
 			truncatedDesc := toolcommon.TruncateText(cmd.Description, availableWidth)
 			content += descStyle.Render(separator + truncatedDesc)
 		}
 	}
+	if shortcut != "" {
+		if gap := contentWidth - lipgloss.Width(content) - shortcutWidth; gap > 0 {
+			content += descStyle.Render(strings.Repeat(" ", gap)) + shortcut
+		}
+	}
 	return content
 }
 
@@ -88,7 +88,7 @@ func (d *maxIterationsDialog) View() string {
 
 	infoText := fmt.Sprintf("Max Iterations: %d", d.maxIterations)
 	messageText := "The agent may be stuck in a loop. This can happen with smaller or less capable models."
-	questionText := "Do you want to continue for 10 more iterations?"
+	questionText := fmt.Sprintf("Do you want to continue for %d more iterations? Otherwise, the run is paused and can be continued later with /continue.", runtime.DefaultContinueIterations)
 
 	content := NewContent(contentWidth).
 		AddTitle("Maximum Iterations Reached").
@@ -99,7 +99,7 @@ func (d *maxIterationsDialog) View() string {
 		AddSpace().
 		AddContent(styles.DialogQuestionStyle.Width(contentWidth).Render(wrapDisplayText(questionText, contentWidth))).
 		AddSpace().
-		AddHelpKeys("Y", "yes", "N", "no").
+		AddHelpKeys("Y", "yes", "N", "pause").
 		Build()
 
 	// DialogWarningStyle already includes Padding(1, 2)
@@ -1,7 +1,9 @@
 package dialog
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -10,10 +12,13 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/atotto/clipboard"
+	"github.com/junegunn/fzf/src/algo"
+	"github.com/junegunn/fzf/src/util"
 
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/tui/components/notification"
 	"github.com/docker/docker-agent/pkg/tui/components/scrollview"
+	"github.com/docker/docker-agent/pkg/tui/components/toolcommon"
 	"github.com/docker/docker-agent/pkg/tui/core"
 	"github.com/docker/docker-agent/pkg/tui/core/layout"
 	"github.com/docker/docker-agent/pkg/tui/messages"
@@ -29,6 +34,8 @@ type sessionBrowserKeyMap struct {
 	Star       key.Binding
 	FilterStar key.Binding
 	CopyID     key.Binding
+	Rename     key.Binding
+	Delete     key.Binding
 }
 
 // Session browser dialog dimension constants
@@ -39,28 +46,39 @@ const (
 
 type sessionBrowserDialog struct {
 	BaseDialog
-	textInput  textinput.Model
-	sessions   []session.Summary
-	filtered   []session.Summary
-	selected   int
-	scrollview *scrollview.Model
-	keyMap     sessionBrowserKeyMap
-	openedAt   time.Time // when dialog was opened, for stable time display
-	starFilter int       // 0 = all, 1 = starred only, 2 = unstarred only
+	textInput   textinput.Model
+	renameInput textinput.Model
+	sessions    []session.Summary
+	filtered    []session.Summary
+	selected    int
+	scrollview  *scrollview.Model
+	keyMap      sessionBrowserKeyMap
+	openedAt    time.Time // when dialog was opened, for stable time display
+	starFilter  int       // 0 = all, 1 = starred only, 2 = unstarred only
+	currentID   string    // the session open in the current tab, which can't be deleted
+
+	renamingID      string // session being renamed, if any
+	confirmDeleteID string // session waiting for a second ctrl+d to be deleted, if any
 
 	// Double-click detection
 	lastClickTime  time.Time
 	lastClickIndex int
 }
 
-// NewSessionBrowserDialog creates a new session browser dialog
-func NewSessionBrowserDialog(sessions []session.Summary) Dialog {
+// NewSessionBrowserDialog creates a new session browser dialog.
+// currentSessionID is the session open in the current tab, if any.
+func NewSessionBrowserDialog(sessions []session.Summary, currentSessionID string) Dialog {
 	ti := textinput.New()
 	ti.Placeholder = "Type to search sessions…"
 	ti.Focus()
 	ti.CharLimit = 100
 	ti.SetWidth(50)
 
+	ri := textinput.New()
+	ri.Prompt = "Rename: "
+	ri.CharLimit = 100
+	ri.SetWidth(50)
+
 	// Filter out empty sessions (sessions without a title)
 	nonEmptySessions := make([]session.Summary, 0, len(sessions))
 	for _, s := range sessions {
@@ -70,9 +88,11 @@ func NewSessionBrowserDialog(sessions []session.Summary) Dialog {
 	}
 
 	d := &sessionBrowserDialog{
-		textInput:  ti,
-		sessions:   nonEmptySessions,
-		scrollview: scrollview.New(scrollview.WithReserveScrollbarSpace(true)),
+		textInput:   ti,
+		renameInput: ri,
+		sessions:    nonEmptySessions,
+		currentID:   currentSessionID,
+		scrollview:  scrollview.New(scrollview.WithReserveScrollbarSpace(true)),
 		keyMap: sessionBrowserKeyMap{
 			Up:         key.NewBinding(key.WithKeys("up", "ctrl+k")),
 			Down:       key.NewBinding(key.WithKeys("down", "ctrl+j")),
@@ -81,6 +101,8 @@ func NewSessionBrowserDialog(sessions []session.Summary) Dialog {
 			Star:       key.NewBinding(key.WithKeys("ctrl+s")),
 			FilterStar: key.NewBinding(key.WithKeys("ctrl+f")),
 			CopyID:     key.NewBinding(key.WithKeys("ctrl+y")),
+			Rename:     key.NewBinding(key.WithKeys("ctrl+r")),
+			Delete:     key.NewBinding(key.WithKeys("ctrl+d")),
 		},
 		openedAt: time.Now(),
 	}
@@ -106,6 +128,10 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 
 	case tea.PasteMsg:
 		var cmd tea.Cmd
+		if d.renamingID != "" {
+			d.renameInput, cmd = d.renameInput.Update(msg)
+			return d, cmd
+		}
 		d.textInput, cmd = d.textInput.Update(msg)
 		d.filterSessions()
 		return d, cmd
@@ -135,8 +161,19 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 			return d, cmd
 		}
 
+		if d.renamingID != "" {
+			return d.updateRename(msg)
+		}
+
+		// Any key other than a second ctrl+d cancels a pending deletion.
+		confirmDeleteID := d.confirmDeleteID
+		d.confirmDeleteID = ""
+
 		switch {
 		case key.Matches(msg, d.keyMap.Escape):
+			if confirmDeleteID != "" {
+				return d, nil
+			}
 			return d, core.CmdHandler(CloseDialogMsg{})
 
 		case key.Matches(msg, d.keyMap.Up):
@@ -171,16 +208,38 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 						break
 					}
 				}
-				for i := range d.filtered {
-					if d.filtered[i].ID == sessionID {
-						d.filtered[i].Starred = !d.filtered[i].Starred
-						break
-					}
-				}
+				d.filterSessions()
+				d.selectSession(sessionID)
 				return d, core.CmdHandler(messages.ToggleSessionStarMsg{SessionID: sessionID})
 			}
 			return d, nil
 
+		case key.Matches(msg, d.keyMap.Rename):
+			if d.selected >= 0 && d.selected < len(d.filtered) {
+				d.renamingID = d.filtered[d.selected].ID
+				d.renameInput.SetValue(d.filtered[d.selected].Title)
+				d.renameInput.CursorEnd()
+				d.textInput.Blur()
+				return d, d.renameInput.Focus()
+			}
+			return d, nil
+
+		case key.Matches(msg, d.keyMap.Delete):
+			if d.selected < 0 || d.selected >= len(d.filtered) {
+				return d, nil
+			}
+			sessionID := d.filtered[d.selected].ID
+			if sessionID == d.currentID {
+				return d, notification.WarningCmd("The current session can't be deleted.")
+			}
+			if confirmDeleteID != sessionID {
+				d.confirmDeleteID = sessionID
+				return d, nil
+			}
+			d.sessions = slices.DeleteFunc(d.sessions, func(s session.Summary) bool { return s.ID == sessionID })
+			d.filterSessions()
+			return d, core.CmdHandler(messages.DeleteSessionMsg{SessionID: sessionID})
+
 		case key.Matches(msg, d.keyMap.FilterStar):
 			d.starFilter = (d.starFilter + 1) % 3
 			d.filterSessions()
@@ -205,10 +264,65 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 	return d, nil
 }
 
+// updateRename handles key presses while a session is being renamed.
+func (d *sessionBrowserDialog) updateRename(msg tea.KeyPressMsg) (layout.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, d.keyMap.Escape):
+		d.stopRename()
+		return d, nil
+
+	case key.Matches(msg, d.keyMap.Enter):
+		sessionID := d.renamingID
+		title := strings.TrimSpace(d.renameInput.Value())
+		d.stopRename()
+		if title == "" {
+			return d, nil
+		}
+		for i := range d.sessions {
+			if d.sessions[i].ID == sessionID {
+				d.sessions[i].Title = title
+				break
+			}
+		}
+		d.filterSessions()
+		d.selectSession(sessionID)
+		return d, core.CmdHandler(messages.RenameSessionMsg{SessionID: sessionID, Title: title})
+
+	default:
+		var cmd tea.Cmd
+		d.renameInput, cmd = d.renameInput.Update(msg)
+		return d, cmd
+	}
+}
+
+func (d *sessionBrowserDialog) stopRename() {
+	d.renamingID = ""
+	d.renameInput.Blur()
+	d.textInput.Focus()
+}
+
+// selectSession moves the selection to the given session, if it's listed.
+func (d *sessionBrowserDialog) selectSession(sessionID string) {
+	for i := range d.filtered {
+		if d.filtered[i].ID == sessionID {
+			d.selected = i
+			d.scrollview.EnsureLineVisible(i)
+			return
+		}
+	}
+}
+
+// filterSessions fuzzy-matches the query against the title, agent and
+// working directory of the sessions. Starred sessions are pinned to the top,
+// then sessions are ordered by match score and by recency.
 func (d *sessionBrowserDialog) filterSessions() {
-	query := strings.ToLower(strings.TrimSpace(d.textInput.Value()))
+	query := []rune(strings.ToLower(strings.TrimSpace(d.textInput.Value())))
 
-	d.filtered = nil
+	type match struct {
+		sess  session.Summary
+		score int
+	}
+	var matches []match
 	for _, sess := range d.sessions {
 		switch d.starFilter {
 		case 1:
@@ -221,17 +335,32 @@ func (d *sessionBrowserDialog) filterSessions() {
 			}
 		}
 
-		if query != "" {
-			title := sess.Title
-			if title == "" {
-				title = "Untitled"
-			}
-			if !strings.Contains(strings.ToLower(title), query) {
+		score := 0
+		if len(query) > 0 {
+			chars := util.ToChars([]byte(strings.Join([]string{sess.Title, sess.Agent, sess.WorkingDir}, " ")))
+			result, _ := algo.FuzzyMatchV1(false, false, true, &chars, query, false, nil)
+			if result.Start < 0 {
 				continue
 			}
+			score = result.Score
 		}
 
-		d.filtered = append(d.filtered, sess)
+		matches = append(matches, match{sess: sess, score: score})
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if a.sess.Starred != b.sess.Starred {
+			if a.sess.Starred {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.score, a.score)
+	})
+
+	d.filtered = nil
+	for _, m := range matches {
+		d.filtered = append(d.filtered, m.sess)
 	}
 
 	if d.selected >= len(d.filtered) {
@@ -259,8 +388,9 @@ func (d *sessionBrowserDialog) mouseYToSessionIndex(y int) int {
 }
 
 func (d *sessionBrowserDialog) dialogSize() (dialogWidth, maxHeight, contentWidth int) {
-	dialogWidth = max(min(d.Width()*85/100, 96), 60)
-	maxHeight = min(d.Height()*70/100, 30)
+	// The session browser takes the whole screen, leaving a small margin.
+	dialogWidth = max(d.Width()-4, 60)
+	maxHeight = max(d.Height()-2, sessionBrowserListOverhead+1)
 	contentWidth = dialogWidth - 6 - d.scrollview.ReservedCols()
 	return dialogWidth, maxHeight, contentWidth
 }
@@ -318,21 +448,40 @@ func (d *sessionBrowserDialog) View() string {
 		filterDesc = "☆ only"
 	}
 
-	var idFooter string
+	var footer string
 	if d.selected >= 0 && d.selected < len(d.filtered) {
-		idFooter = styles.MutedStyle.Render("ID: ") + styles.SecondaryStyle.Render(d.filtered[d.selected].ID)
+		sess := d.filtered[d.selected]
+		if d.confirmDeleteID == sess.ID {
+			footer = styles.ErrorStyle.Render(fmt.Sprintf("Delete %q? Press ctrl+d again to confirm.", sess.Title))
+		} else {
+			footer = styles.MutedStyle.Render("ID: ") + styles.SecondaryStyle.Render(sess.ID)
+			if sess.WorkingDir != "" {
+				footer += styles.MutedStyle.Render("  Dir: ") + styles.SecondaryStyle.Render(toolcommon.ShortenPath(sess.WorkingDir))
+			}
+			footer = toolcommon.TruncateText(footer, contentWidth)
+		}
+	}
+
+	input := d.textInput.View()
+	if d.renamingID != "" {
+		input = d.renameInput.View()
+	}
+
+	helpKeys := []string{"↑/↓", "navigate", "ctrl+s", "pin", "ctrl+f", filterDesc, "ctrl+r", "rename", "ctrl+d", "delete", "ctrl+y", "copy id", "enter", "load", "esc", "close"}
+	if d.renamingID != "" {
+		helpKeys = []string{"enter", "save", "esc", "cancel"}
 	}
 
 	content := NewContent(regionWidth).
 		AddTitle(title).
 		AddSpace().
-		AddContent(d.textInput.View()).
+		AddContent(input).
 		AddSeparator().
 		AddContent(scrollableContent).
 		AddSeparator().
-		AddContent(idFooter).
+		AddContent(footer).
 		AddSpace().
-		AddHelpKeys("↑/↓", "navigate", "ctrl+s", "star", "ctrl+f", filterDesc, "ctrl+y", "copy id", "enter", "load", "esc", "close").
+		AddHelpKeys(helpKeys...).
 		Build()
 
 	return styles.DialogStyle.Width(dialogWidth).Render(content)
@@ -359,10 +508,20 @@ func (d *sessionBrowserDialog) renderSession(sess session.Summary, selected bool
 		title = "Untitled"
 	}
 
-	suffix := fmt.Sprintf(" • (%d msg) • %s", sess.NumMessages, d.timeAgo(sess.CreatedAt))
+	suffix := fmt.Sprintf(" • %d msg", sess.NumMessages)
+	if sess.Agent != "" {
+		suffix = " • " + sess.Agent + suffix
+	}
+	if tokens := sess.InputTokens + sess.OutputTokens; tokens > 0 {
+		suffix += " • " + formatTokenCount(tokens) + " tok"
+	}
+	if sess.Cost > 0 {
+		suffix += " • " + formatCost(sess.Cost)
+	}
+	suffix += " • " + d.timeAgo(sess.CreatedAt)
 
 	starWidth := 3
-	maxTitleLen := max(1, maxWidth-len(suffix)-starWidth)
+	maxTitleLen := max(1, maxWidth-lipgloss.Width(suffix)-starWidth)
 	if len(title) > maxTitleLen {
 		title = title[:maxTitleLen-1] + "…"
 	}
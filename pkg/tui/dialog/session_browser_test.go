@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tui/messages"
 )
 
 func TestSessionBrowserNavigation(t *testing.T) {
@@ -20,7 +21,7 @@ func TestSessionBrowserNavigation(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 
 	// Initialize and set window size like the TUI does
@@ -68,7 +69,7 @@ func TestSessionBrowserNavigationWithCtrl(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -103,7 +104,7 @@ func TestSessionBrowserViewShowsSelection(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -133,7 +134,7 @@ func TestSessionBrowserFiltersEmptySessions(t *testing.T) {
 		{ID: "5", Title: "Session 5", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 
 	// Should only have non-empty sessions
@@ -152,7 +153,7 @@ func TestSessionBrowserAllEmptySessions(t *testing.T) {
 		{ID: "2", Title: "", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 
 	// Should have no sessions
@@ -171,7 +172,7 @@ func TestSessionBrowserScrolling(t *testing.T) {
 		}
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	// Set a small window size to force scrolling
@@ -211,7 +212,7 @@ func TestSessionBrowserMouseClickSelectsSession(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -257,7 +258,7 @@ func TestSessionBrowserDoubleClickOpensSession(t *testing.T) {
 		{ID: "sess-3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -288,7 +289,7 @@ func TestSessionBrowserClickOutsideListIgnored(t *testing.T) {
 		{ID: "2", Title: "Session 2", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -306,3 +307,86 @@ func TestSessionBrowserClickOutsideListIgnored(t *testing.T) {
 	require.Equal(t, 0, d.selected, "click outside list should not change selection")
 	require.Nil(t, cmd, "click outside list should not produce a command")
 }
+
+func TestSessionBrowserFuzzyFilterAndPinning(t *testing.T) {
+	sessions := []session.Summary{
+		{ID: "1", Title: "Refactor parser", Agent: "coder", CreatedAt: time.Now()},
+		{ID: "2", Title: "Write release notes", Agent: "writer", CreatedAt: time.Now()},
+		{ID: "3", Title: "Fix parser crash", Agent: "coder", Starred: true, CreatedAt: time.Now()},
+	}
+
+	d := NewSessionBrowserDialog(sessions, "").(*sessionBrowserDialog)
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	// Pinned sessions come first.
+	require.Equal(t, []string{"3", "1", "2"}, sessionIDs(d.filtered))
+
+	// Fuzzy match on title and agent.
+	d.textInput.SetValue("prsr")
+	d.filterSessions()
+	require.Equal(t, []string{"3", "1"}, sessionIDs(d.filtered))
+
+	d.textInput.SetValue("writer")
+	d.filterSessions()
+	require.Equal(t, []string{"2"}, sessionIDs(d.filtered))
+}
+
+func TestSessionBrowserRename(t *testing.T) {
+	sessions := []session.Summary{
+		{ID: "1", Title: "Session 1", CreatedAt: time.Now()},
+	}
+
+	d := NewSessionBrowserDialog(sessions, "").(*sessionBrowserDialog)
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	d.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	require.Equal(t, "1", d.renamingID)
+	require.Equal(t, "Session 1", d.renameInput.Value())
+
+	d.renameInput.SetValue("Better title")
+	_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	require.Equal(t, messages.RenameSessionMsg{SessionID: "1", Title: "Better title"}, cmd())
+	require.Empty(t, d.renamingID)
+	require.Equal(t, "Better title", d.filtered[0].Title)
+}
+
+func TestSessionBrowserDeleteNeedsConfirmation(t *testing.T) {
+	sessions := []session.Summary{
+		{ID: "current", Title: "Current", CreatedAt: time.Now()},
+		{ID: "old", Title: "Old", CreatedAt: time.Now()},
+	}
+
+	d := NewSessionBrowserDialog(sessions, "current").(*sessionBrowserDialog)
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+	ctrlD := tea.KeyPressMsg{Code: 'd', Mod: tea.ModCtrl}
+
+	// The current session can't be deleted.
+	d.Update(ctrlD)
+	require.Empty(t, d.confirmDeleteID)
+	require.Len(t, d.sessions, 2)
+
+	d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	_, cmd := d.Update(ctrlD)
+	require.Nil(t, cmd)
+	require.Equal(t, "old", d.confirmDeleteID)
+
+	// Escape cancels the deletion without closing the browser.
+	_, cmd = d.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	require.Nil(t, cmd)
+	require.Empty(t, d.confirmDeleteID)
+
+	d.Update(ctrlD)
+	_, cmd = d.Update(ctrlD)
+	require.NotNil(t, cmd)
+	require.Equal(t, messages.DeleteSessionMsg{SessionID: "old"}, cmd())
+	require.Equal(t, []string{"current"}, sessionIDs(d.sessions))
+}
+
+func sessionIDs(sessions []session.Summary) []string {
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
@@ -0,0 +1,434 @@
+package dialog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tui/components/notification"
+	"github.com/docker/docker-agent/pkg/tui/components/scrollview"
+	"github.com/docker/docker-agent/pkg/tui/components/toolcommon"
+	"github.com/docker/docker-agent/pkg/tui/core"
+	"github.com/docker/docker-agent/pkg/tui/core/layout"
+	"github.com/docker/docker-agent/pkg/tui/messages"
+	"github.com/docker/docker-agent/pkg/tui/styles"
+)
+
+// timeTravelDialog steps through the items of a session one by one: the
+// messages with the model call or the tool call behind them, the delegated
+// tasks and the records. The session can be re-run in a branch from any
+// message.
+type timeTravelDialog struct {
+	BaseDialog
+	sessionID  string
+	items      []session.Item
+	toolCalls  map[string]tools.ToolCall
+	step       int
+	request    bool
+	dumps      map[string]string
+	keyMap     timeTravelKeyMap
+	scrollview *scrollview.Model
+
+	// lines caches the rendered step, dumps can be large.
+	lines    []string
+	linesKey timeTravelLinesKey
+}
+
+type timeTravelKeyMap struct {
+	Close, Prev, Next, First, Last, Request, Rerun key.Binding
+}
+
+type timeTravelLinesKey struct {
+	step, width int
+	request     bool
+}
+
+// NewTimeTravelDialog creates a new dialog stepping through a session.
+func NewTimeTravelDialog(sess *session.Session) Dialog {
+	items := sess.Items()
+	toolCalls := map[string]tools.ToolCall{}
+	for _, item := range items {
+		if item.Message == nil {
+			continue
+		}
+		for _, tc := range item.Message.Message.ToolCalls {
+			toolCalls[tc.ID] = tc
+		}
+	}
+
+	return &timeTravelDialog{
+		sessionID: sess.ID,
+		items:     items,
+		toolCalls: toolCalls,
+		dumps:     map[string]string{},
+		scrollview: scrollview.New(
+			scrollview.WithKeyMap(scrollview.ReadOnlyScrollKeyMap()),
+			scrollview.WithReserveScrollbarSpace(true),
+		),
+		keyMap: timeTravelKeyMap{
+			Close:   key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("Esc", "close")),
+			Prev:    key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←", "previous step")),
+			Next:    key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→", "next step")),
+			First:   key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "first step")),
+			Last:    key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "last step")),
+			Request: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "model request")),
+			Rerun:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "re-run from here")),
+		},
+	}
+}
+
+func (d *timeTravelDialog) Init() tea.Cmd { return nil }
+
+func (d *timeTravelDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	if handled, cmd := d.scrollview.Update(msg); handled {
+		return d, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keyMap.Close):
+			return d, core.CmdHandler(CloseDialogMsg{})
+		case key.Matches(msg, d.keyMap.Prev):
+			d.goTo(d.step - 1)
+		case key.Matches(msg, d.keyMap.Next):
+			d.goTo(d.step + 1)
+		case key.Matches(msg, d.keyMap.First):
+			d.goTo(0)
+		case key.Matches(msg, d.keyMap.Last):
+			d.goTo(len(d.items) - 1)
+		case key.Matches(msg, d.keyMap.Request):
+			d.request = !d.request
+			d.scrollview.ScrollToTop()
+		case key.Matches(msg, d.keyMap.Rerun):
+			return d, d.rerun()
+		}
+	}
+	return d, nil
+}
+
+func (d *timeTravelDialog) goTo(step int) {
+	step = max(0, min(step, len(d.items)-1))
+	if step != d.step {
+		d.step = step
+		d.scrollview.ScrollToTop()
+	}
+}
+
+// rerun closes the dialog and asks to re-run the session from the current
+// step, when it's a message the session can be re-run from.
+func (d *timeTravelDialog) rerun() tea.Cmd {
+	if len(d.items) == 0 {
+		return nil
+	}
+	rerun := messages.RerunFromStepMsg{SessionID: d.sessionID, Position: d.step}
+	item := d.items[d.step]
+	switch {
+	case item.Message == nil:
+		return notification.InfoCmd("Only user messages, model calls and tool results can be re-run.")
+	case item.Message.Message.Role == chat.MessageRoleAssistant:
+		rerun.Regenerate = true
+	case item.Message.Message.Role == chat.MessageRoleTool:
+		rerun.ToolResult = true
+		rerun.Content = item.Message.Message.Content
+	case item.Message.Message.Role == chat.MessageRoleUser:
+		rerun.Content = item.Message.Message.Content
+	default:
+		return notification.InfoCmd("Only user messages, model calls and tool results can be re-run.")
+	}
+	return tea.Sequence(
+		core.CmdHandler(CloseDialogMsg{}),
+		core.CmdHandler(rerun),
+	)
+}
+
+func (d *timeTravelDialog) dialogSize() (dialogWidth, maxHeight, contentWidth int) {
+	dialogWidth = d.ComputeDialogWidth(85, 60, 140)
+	maxHeight = max(10, d.Height()*85/100)
+	contentWidth = d.ContentWidth(dialogWidth, 2) - d.scrollview.ReservedCols()
+	return dialogWidth, maxHeight, contentWidth
+}
+
+func (d *timeTravelDialog) Position() (row, col int) {
+	dialogWidth, maxHeight, _ := d.dialogSize()
+	return CenterPosition(d.Width(), d.Height(), dialogWidth, maxHeight)
+}
+
+func (d *timeTravelDialog) View() string {
+	dialogWidth, maxHeight, contentWidth := d.dialogSize()
+	content := d.renderContent(contentWidth, maxHeight)
+	return styles.DialogStyle.Padding(1, 2).Width(dialogWidth).Render(content)
+}
+
+func (d *timeTravelDialog) renderContent(contentWidth, maxHeight int) string {
+	if len(d.items) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			RenderTitle("Time Travel", contentWidth, styles.DialogTitleStyle),
+			RenderSeparator(contentWidth),
+			"",
+			styles.MutedStyle.Render("The session is empty."),
+			"",
+			RenderHelpKeys(contentWidth, "Esc", "close"),
+		)
+	}
+
+	header := []string{
+		RenderTitle("Time Travel", contentWidth, styles.DialogTitleStyle),
+		RenderSeparator(contentWidth),
+		d.stepHeader(contentWidth),
+		"",
+	}
+
+	linesKey := timeTravelLinesKey{step: d.step, width: contentWidth, request: d.request}
+	if d.lines == nil || d.linesKey != linesKey {
+		d.lines = d.stepLines(contentWidth)
+		d.linesKey = linesKey
+	}
+
+	visibleLines := max(1, maxHeight-len(header)-2-4)
+	regionWidth := contentWidth + d.scrollview.ReservedCols()
+	d.scrollview.SetSize(regionWidth, visibleLines)
+
+	dialogRow, dialogCol := d.Position()
+	d.scrollview.SetPosition(dialogCol+3, dialogRow+2+len(header))
+	d.scrollview.SetContent(d.lines, len(d.lines))
+
+	parts := append(header, d.scrollview.View(), "",
+		RenderHelpKeys(regionWidth, "←→", "step", "↑↓", "scroll", "p", "model request", "r", "re-run from here", "Esc", "close"))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// stepHeader describes the current step in a line: its number, its kind and
+// who it's from.
+func (d *timeTravelDialog) stepHeader(contentWidth int) string {
+	item := d.items[d.step]
+	meta := []string{fmt.Sprintf("Step %d of %d", d.step+1, len(d.items)), stepKind(item)}
+	switch {
+	case item.Message != nil:
+		msg := item.Message.Message
+		if item.Message.AgentName != "" {
+			meta = append(meta, item.Message.AgentName)
+		}
+		if msg.Model != "" {
+			meta = append(meta, msg.Model)
+		}
+		if msg.CreatedAt != "" {
+			meta = append(meta, msg.CreatedAt)
+		}
+	case item.Record != nil:
+		if item.Record.AgentName != "" {
+			meta = append(meta, item.Record.AgentName)
+		}
+		meta = append(meta, item.Record.CreatedAt.Format(time.RFC3339))
+	}
+	return styles.MutedStyle.Render(toolcommon.TruncateText(strings.Join(meta, "  •  "), contentWidth))
+}
+
+func stepKind(item session.Item) string {
+	switch {
+	case item.SubSession != nil:
+		return "Delegated task"
+	case item.Summary != "":
+		return "Summary"
+	case item.Record != nil:
+		return "Record"
+	case item.Message == nil:
+		return "Unknown"
+	}
+	switch item.Message.Message.Role {
+	case chat.MessageRoleUser:
+		return "User message"
+	case chat.MessageRoleAssistant:
+		return "Model call"
+	case chat.MessageRoleTool:
+		return "Tool result"
+	case chat.MessageRoleSystem:
+		return "System message"
+	default:
+		return string(item.Message.Message.Role)
+	}
+}
+
+// stepLines renders the details of the current step, wrapped to width.
+func (d *timeTravelDialog) stepLines(width int) []string {
+	item := d.items[d.step]
+	var s stepSections
+
+	switch {
+	case item.SubSession != nil:
+		s.text(width, "Task", item.SubSession.Title)
+		s.code(width, "Transcript", item.SubSession.Trace(false))
+	case item.Summary != "":
+		s.text(width, "Summary", item.Summary)
+	case item.Record != nil:
+		s.text(width, "Record", item.Record.String())
+		if approval := item.Record.ToolApproval; approval != nil && approval.Arguments != "" {
+			s.code(width, "Arguments", prettyJSON(approval.Arguments))
+		}
+	case item.Message != nil:
+		msg := item.Message.Message
+		switch msg.Role {
+		case chat.MessageRoleAssistant:
+			if d.request {
+				d.requestLines(&s, width, msg)
+			} else {
+				modelCallLines(&s, width, msg)
+			}
+		case chat.MessageRoleTool:
+			tc, ok := d.toolCalls[msg.ToolCallID]
+			if ok {
+				s.text(width, "Tool", tc.Function.Name)
+				s.code(width, "Arguments", prettyJSON(tc.Function.Arguments))
+			}
+			title := "Result"
+			if msg.IsError {
+				title = "Error"
+			}
+			s.code(width, title, msg.Content)
+		default:
+			s.text(width, "Content", messageText(msg))
+		}
+	}
+
+	if len(s.lines) == 0 {
+		return []string{styles.MutedStyle.Render("Nothing to show.")}
+	}
+	return s.lines
+}
+
+// modelCallLines renders what a model answered, and what it cost.
+func modelCallLines(s *stepSections, width int, msg chat.Message) {
+	if msg.ReasoningContent != "" {
+		s.text(width, "Reasoning", msg.ReasoningContent)
+	}
+	if msg.Content != "" {
+		s.text(width, "Answer", msg.Content)
+	}
+	for _, tc := range msg.ToolCalls {
+		s.code(width, "Tool call: "+tc.Function.Name, prettyJSON(tc.Function.Arguments))
+	}
+
+	var usage []string
+	if msg.Usage != nil {
+		usage = append(usage,
+			"input "+formatTokenCount(msg.Usage.InputTokens+msg.Usage.CachedInputTokens+msg.Usage.CacheWriteTokens),
+			"output "+formatTokenCount(msg.Usage.OutputTokens))
+	}
+	if msg.Cost > 0 {
+		usage = append(usage, formatCost(msg.Cost))
+	}
+	if msg.LatencyMs > 0 {
+		usage = append(usage, fmt.Sprintf("%d ms", msg.LatencyMs))
+	}
+	if len(usage) > 0 {
+		s.text(width, "Usage", strings.Join(usage, "  •  "))
+	}
+
+	if msg.Ensemble != nil {
+		note := fmt.Sprintf("Chosen by %s, %d of %d models agreed.", msg.Ensemble.Method, msg.Ensemble.Agreeing, len(msg.Ensemble.Variants))
+		if msg.Ensemble.Disagreement != "" {
+			note += "\n" + msg.Ensemble.Disagreement
+		}
+		s.text(width, "Ensemble", note)
+	}
+
+	if len(msg.ToolDefinitions) > 0 {
+		names := make([]string, len(msg.ToolDefinitions))
+		for i, t := range msg.ToolDefinitions {
+			names[i] = t.Name
+		}
+		s.text(width, "Tools used", strings.Join(names, ", "))
+	}
+}
+
+// requestLines renders the model request and response of a model call, as
+// dumped by --debug-llm.
+func (d *timeTravelDialog) requestLines(s *stepSections, width int, msg chat.Message) {
+	if msg.RequestDump == "" {
+		s.text(width, "Model request", "No dump of the model request of this step is available. Requests are only dumped when running with --debug-llm.")
+		return
+	}
+
+	dump, ok := d.dumps[msg.RequestDump]
+	if !ok {
+		buf, err := os.ReadFile(msg.RequestDump)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			s.text(width, "Model request", fmt.Sprintf("The dump of the model request of this step, %s, no longer exists.", msg.RequestDump))
+			return
+		case err != nil:
+			dump = fmt.Sprintf("Failed to read %s: %v", msg.RequestDump, err)
+		default:
+			dump = string(buf)
+		}
+		d.dumps[msg.RequestDump] = dump
+	}
+	s.code(width, "Model request: "+msg.RequestDump, dump)
+}
+
+// messageText returns the text of a message, with the names of the files
+// attached to it.
+func messageText(msg chat.Message) string {
+	if len(msg.MultiContent) == 0 {
+		return msg.Content
+	}
+	var parts []string
+	for _, part := range msg.MultiContent {
+		switch {
+		case part.Type == chat.MessagePartTypeText:
+			parts = append(parts, part.Text)
+		case part.File != nil:
+			parts = append(parts, "[file: "+part.File.Path+"]")
+		case part.ImageURL != nil:
+			parts = append(parts, "[image]")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// prettyJSON indents JSON arguments, and returns anything else as is.
+func prettyJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// stepSections accumulates the titled sections of a step.
+type stepSections struct {
+	lines []string
+}
+
+// text adds a section of prose, wrapped at word boundaries.
+func (s *stepSections) text(width int, title, body string) {
+	s.add(title, toolcommon.WrapLinesWords(strings.TrimSpace(body), width))
+}
+
+// code adds a section of code or data, wrapped without losing indentation.
+func (s *stepSections) code(width int, title, body string) {
+	s.add(title, toolcommon.WrapLines(strings.TrimRight(body, "\n"), width))
+}
+
+func (s *stepSections) add(title string, body []string) {
+	if len(s.lines) > 0 {
+		s.lines = append(s.lines, "")
+	}
+	s.lines = append(s.lines, lipgloss.NewStyle().Bold(true).Foreground(styles.Highlight).Render(title))
+	s.lines = append(s.lines, body...)
+}
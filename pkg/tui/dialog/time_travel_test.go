@@ -0,0 +1,147 @@
+package dialog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tui/messages"
+)
+
+func newTimeTravelSession(t *testing.T) *session.Session {
+	t.Helper()
+
+	dump := filepath.Join(t.TempDir(), "0001.http")
+	require.NoError(t, os.WriteFile(dump, []byte("POST https://api.example.com/v1/messages\n\n{\"model\": \"m\"}\n"), 0o600))
+
+	sess := session.New()
+	sess.AddMessage(session.UserMessage("List the files"))
+	sess.AddMessage(session.NewAgentMessage("root", &chat.Message{
+		Role:        chat.MessageRoleAssistant,
+		Content:     "Let me look",
+		Model:       "openai/gpt-4o",
+		ToolCalls:   []tools.ToolCall{{ID: "call_1", Function: tools.FunctionCall{Name: "list_directory", Arguments: `{"path":"."}`}}},
+		Usage:       &chat.Usage{InputTokens: 1200, OutputTokens: 30},
+		Cost:        0.0042,
+		RequestDump: dump,
+	}))
+	sess.AddMessage(&session.Message{Message: chat.Message{Role: chat.MessageRoleTool, ToolCallID: "call_1", Content: "a.txt\nb.txt"}})
+	sess.AddRecord(&session.Record{Escalation: &session.EscalationRecord{Reason: "Not sure which file"}})
+	return sess
+}
+
+func timeTravelView(t *testing.T, d Dialog) string {
+	t.Helper()
+	return ansi.Strip(d.View())
+}
+
+func TestTimeTravelDialog_Steps(t *testing.T) {
+	t.Parallel()
+
+	d := NewTimeTravelDialog(newTimeTravelSession(t))
+	d.SetSize(120, 50)
+
+	view := timeTravelView(t, d)
+	assert.Contains(t, view, "Step 1 of 4")
+	assert.Contains(t, view, "List the files")
+
+	d.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	view = timeTravelView(t, d)
+	assert.Contains(t, view, "Step 2 of 4")
+	assert.Contains(t, view, "Model call")
+	assert.Contains(t, view, "Tool call: list_directory")
+	assert.Contains(t, view, `"path": "."`)
+	assert.Contains(t, view, "output 30")
+
+	d.Update(tea.KeyPressMsg{Text: "p"})
+	view = timeTravelView(t, d)
+	assert.Contains(t, view, "POST https://api.example.com/v1/messages")
+	d.Update(tea.KeyPressMsg{Text: "p"})
+
+	d.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	view = timeTravelView(t, d)
+	assert.Contains(t, view, "Tool result")
+	assert.Contains(t, view, "list_directory")
+	assert.Contains(t, view, "b.txt")
+
+	d.Update(tea.KeyPressMsg{Text: "G"})
+	view = timeTravelView(t, d)
+	assert.Contains(t, view, "Step 4 of 4")
+	assert.Contains(t, view, "Escalated to a human: Not sure which file")
+
+	d.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	assert.Contains(t, timeTravelView(t, d), "Step 4 of 4", "stays on the last step")
+
+	d.Update(tea.KeyPressMsg{Text: "g"})
+	assert.Contains(t, timeTravelView(t, d), "Step 1 of 4")
+}
+
+func TestTimeTravelDialog_RequestNotRecorded(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New()
+	sess.AddMessage(session.NewAgentMessage("root", &chat.Message{Role: chat.MessageRoleAssistant, Content: "Hello"}))
+
+	d := NewTimeTravelDialog(sess)
+	d.SetSize(120, 50)
+	d.Update(tea.KeyPressMsg{Text: "p"})
+
+	assert.Contains(t, timeTravelView(t, d), "--debug-llm")
+}
+
+func TestTimeTravelDialog_RequestDumpDeleted(t *testing.T) {
+	t.Parallel()
+
+	dump := filepath.Join(t.TempDir(), "0001.http")
+	sess := session.New()
+	sess.AddMessage(session.NewAgentMessage("root", &chat.Message{Role: chat.MessageRoleAssistant, Content: "Hello", RequestDump: dump}))
+
+	d := NewTimeTravelDialog(sess)
+	d.SetSize(120, 50)
+	d.Update(tea.KeyPressMsg{Text: "p"})
+
+	assert.Contains(t, timeTravelView(t, d), "no longer exists")
+}
+
+func TestTimeTravelDialog_Rerun(t *testing.T) {
+	t.Parallel()
+
+	sess := newTimeTravelSession(t)
+	tests := []struct {
+		step int
+		want messages.RerunFromStepMsg
+	}{
+		{step: 0, want: messages.RerunFromStepMsg{SessionID: sess.ID, Position: 0, Content: "List the files"}},
+		{step: 1, want: messages.RerunFromStepMsg{SessionID: sess.ID, Position: 1, Regenerate: true}},
+		{step: 2, want: messages.RerunFromStepMsg{SessionID: sess.ID, Position: 2, ToolResult: true, Content: "a.txt\nb.txt"}},
+	}
+	for _, tt := range tests {
+		d := NewTimeTravelDialog(sess)
+		for range tt.step {
+			d.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+		}
+
+		_, cmd := d.Update(tea.KeyPressMsg{Text: "r"})
+		msgs := collectMsgs(cmd)
+
+		_, closed := findMsg[CloseDialogMsg](msgs)
+		assert.True(t, closed)
+		rerun, ok := findMsg[messages.RerunFromStepMsg](msgs)
+		require.True(t, ok)
+		assert.Equal(t, tt.want, rerun)
+	}
+
+	d := NewTimeTravelDialog(sess)
+	d.Update(tea.KeyPressMsg{Text: "G"})
+	_, cmd := d.Update(tea.KeyPressMsg{Text: "r"})
+	_, ok := findMsg[messages.RerunFromStepMsg](collectMsgs(cmd))
+	assert.False(t, ok, "records can't be re-run")
+}
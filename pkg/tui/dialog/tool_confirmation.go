@@ -1,7 +1,6 @@
 package dialog
 
 import (
-	"encoding/json"
 	"strings"
 
 	"charm.land/bubbles/v2/key"
@@ -9,8 +8,8 @@ import (
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
 
+	"github.com/docker/docker-agent/pkg/permissions"
 	"github.com/docker/docker-agent/pkg/runtime"
-	"github.com/docker/docker-agent/pkg/tools"
 	"github.com/docker/docker-agent/pkg/tui/components/messages"
 	"github.com/docker/docker-agent/pkg/tui/core"
 	"github.com/docker/docker-agent/pkg/tui/core/layout"
@@ -75,7 +74,7 @@ func (d *toolConfirmationDialog) SetSize(width, height int) tea.Cmd {
 	question := styles.DialogQuestionStyle.Width(contentWidth).Render("Do you want to allow this tool call?")
 	questionHeight := lipgloss.Height(question)
 
-	options := RenderHelpKeys(contentWidth, "Y", "yes", "N", "no", "T", d.alwaysAllowHelpText(), "A", "all tools")
+	options := RenderHelpKeys(contentWidth, d.helpKeys()...)
 	optionsHeight := lipgloss.Height(options)
 
 	// Calculate available height for scroll view
@@ -92,21 +91,21 @@ func (d *toolConfirmationDialog) renderSeparator(contentWidth int) string {
 	return RenderSeparator(contentWidth)
 }
 
-// alwaysAllowHelpText returns a descriptive help text for the "always allow" option.
-// For shell commands, it shows the command pattern (e.g., "always allow ls*").
-// For other tools, it shows "always allow <toolname>".
-func (d *toolConfirmationDialog) alwaysAllowHelpText() string {
-	pattern := d.permissionPattern
+// helpKeys returns the keys and labels of the approval scopes: just this
+// once, this tool for the session, always this tool, always this pattern
+// (for shell commands, e.g. "always git status") and all tools for the session.
+// "Always" choices are saved to the project settings.
+func (d *toolConfirmationDialog) helpKeys() []string {
 	toolName := d.msg.ToolCall.Function.Name
-
-	// For shell with a command pattern, show a more descriptive label
-	if toolName == "shell" {
-		if _, cmdPattern, ok := strings.Cut(pattern, ":cmd="); ok {
-			return "always allow " + cmdPattern
+	keys := []string{"Y", "once", "S", "session", "T", "always " + toolName}
+	if d.permissionPattern != toolName {
+		label := d.permissionPattern
+		if _, cmdPattern, ok := strings.Cut(label, ":cmd="); ok {
+			label = cmdPattern
 		}
+		keys = append(keys, "P", "always "+label)
 	}
-
-	return "always allow " + toolName
+	return append(keys, "A", "all tools", "N", "no")
 }
 
 // toolConfirmationKeyMap defines key bindings for tool confirmation dialog
@@ -114,7 +113,9 @@ type toolConfirmationKeyMap struct {
 	Yes      key.Binding
 	No       key.Binding
 	All      key.Binding
+	Session  key.Binding
 	ThisTool key.Binding
+	Pattern  key.Binding
 }
 
 // defaultToolConfirmationKeyMap returns default key bindings
@@ -132,41 +133,21 @@ func defaultToolConfirmationKeyMap() toolConfirmationKeyMap {
 			key.WithKeys("a", "A"),
 			key.WithHelp("A", "approve all"),
 		),
+		Session: key.NewBinding(
+			key.WithKeys("s", "S"),
+			key.WithHelp("S", "allow this tool for the session"),
+		),
 		ThisTool: key.NewBinding(
 			key.WithKeys("t", "T"),
 			key.WithHelp("T", "always allow this tool"),
 		),
+		Pattern: key.NewBinding(
+			key.WithKeys("p", "P"),
+			key.WithHelp("P", "always allow this pattern"),
+		),
 	}
 }
 
-// buildPermissionPattern creates a permission pattern for the tool call.
-// For shell commands, it extracts the first word of the command and creates
-// a pattern like "shell:cmd=ls*" to match all invocations of that command.
-// For other tools, it returns just the tool name.
-func buildPermissionPattern(toolCall tools.ToolCall) string {
-	toolName := toolCall.Function.Name
-
-	// For shell tool, extract the command and create a pattern
-	if toolName == "shell" {
-		var args struct {
-			Cmd string `json:"cmd"`
-		}
-		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err == nil {
-			// Extract the first word (the command) from the full command string
-			// e.g., "ls -la /tmp" -> "ls"
-			// strings.Fields handles all whitespace (space, tab, newline)
-			if fields := strings.Fields(args.Cmd); len(fields) > 0 {
-				// Create pattern: shell:cmd=<command>*
-				// The trailing * allows matching with any arguments
-				return toolName + ":cmd=" + fields[0] + "*"
-			}
-		}
-	}
-
-	// For other tools, just return the tool name
-	return toolName
-}
-
 // NewToolConfirmationDialog creates a new tool confirmation dialog
 func NewToolConfirmationDialog(msg *runtime.ToolCallConfirmationEvent, sessionState *service.SessionState) Dialog {
 	// Create scrollable view with minimal initial size (will be updated in SetSize)
@@ -181,7 +162,7 @@ func NewToolConfirmationDialog(msg *runtime.ToolCallConfirmationEvent, sessionSt
 	)
 
 	// Build and cache the permission pattern for display and use
-	pattern := buildPermissionPattern(msg.ToolCall)
+	pattern := permissions.SuggestPattern(msg.ToolCall.Function.Name, msg.ToolCall.Function.Arguments)
 
 	return &toolConfirmationDialog{
 		msg:               msg,
@@ -197,7 +178,7 @@ func (d *toolConfirmationDialog) Init() tea.Cmd {
 	return d.scrollView.Init()
 }
 
-// executeAction dispatches a confirmation action by key ("Y", "N", "T", "A").
+// executeAction dispatches a confirmation action by key ("Y", "N", "S", "T", "P", "A").
 func (d *toolConfirmationDialog) executeAction(action string) (layout.Model, tea.Cmd) {
 	switch action {
 	case "Y":
@@ -209,10 +190,23 @@ func (d *toolConfirmationDialog) executeAction(action string) (layout.Model, tea
 		return d, core.CmdHandler(OpenDialogMsg{
 			Model: NewToolRejectionReasonDialog(),
 		})
+	case "S":
+		return d, tea.Sequence(
+			core.CmdHandler(CloseDialogMsg{}),
+			core.CmdHandler(RuntimeResumeMsg{Request: runtime.ResumeApproveTool(d.msg.ToolCall.Function.Name)}),
+		)
 	case "T":
 		return d, tea.Sequence(
 			core.CmdHandler(CloseDialogMsg{}),
-			core.CmdHandler(RuntimeResumeMsg{Request: runtime.ResumeApproveTool(d.permissionPattern)}),
+			core.CmdHandler(RuntimeResumeMsg{Request: runtime.ResumeApproveAlways(d.msg.ToolCall.Function.Name)}),
+		)
+	case "P":
+		if d.permissionPattern == d.msg.ToolCall.Function.Name {
+			return d, nil
+		}
+		return d, tea.Sequence(
+			core.CmdHandler(CloseDialogMsg{}),
+			core.CmdHandler(RuntimeResumeMsg{Request: runtime.ResumeApproveAlways(d.permissionPattern)}),
 		)
 	case "A":
 		d.sessionState.SetYoloMode(true)
@@ -249,8 +243,12 @@ func (d *toolConfirmationDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 			return d.executeAction("N")
 		case key.Matches(msg, d.keyMap.All):
 			return d.executeAction("A")
+		case key.Matches(msg, d.keyMap.Session):
+			return d.executeAction("S")
 		case key.Matches(msg, d.keyMap.ThisTool):
 			return d.executeAction("T")
+		case key.Matches(msg, d.keyMap.Pattern):
+			return d.executeAction("P")
 		}
 
 		// Forward scrolling keys to the scroll view
@@ -269,7 +267,7 @@ func (d *toolConfirmationDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 	return d, nil
 }
 
-// handleMouseClick handles mouse clicks on the action buttons (Y/N/T/A).
+// handleMouseClick handles mouse clicks on the action buttons (Y/S/T/P/A/N).
 func (d *toolConfirmationDialog) handleMouseClick(msg tea.MouseClickMsg) (layout.Model, tea.Cmd) {
 	dialogRow, dialogCol := d.Position()
 	renderedDialog := d.View()
@@ -282,7 +280,7 @@ func (d *toolConfirmationDialog) handleMouseClick(msg tea.MouseClickMsg) (layout
 
 	// Render the help keys and strip ANSI to get plain text for hit-testing.
 	_, contentWidth := d.dialogDimensions()
-	options := RenderHelpKeys(contentWidth, "Y", "yes", "N", "no", "T", d.alwaysAllowHelpText(), "A", "all tools")
+	options := RenderHelpKeys(contentWidth, d.helpKeys()...)
 	optionsPlain := ansi.Strip(options)
 
 	// Content starts after left border + padding.
@@ -297,9 +295,9 @@ func (d *toolConfirmationDialog) handleMouseClick(msg tea.MouseClickMsg) (layout
 	}
 
 	// Walk backward from the click position to find the nearest action key.
-	// The plain text looks like: "Y yes  N no  T always allow...  A all tools"
+	// The plain text looks like: "Y once  S session  T always shell  P always ls*  A all tools  N no"
 	// Each region starts with its uppercase action key.
-	actionKeys := "YNTA"
+	actionKeys := "YSTPAN"
 	for i := relX; i >= 0; i-- {
 		if strings.ContainsRune(actionKeys, rune(optionsPlain[i])) {
 			return d.executeAction(string(optionsPlain[i]))
@@ -333,7 +331,7 @@ func (d *toolConfirmationDialog) View() string {
 
 	// Confirmation prompt
 	question := styles.DialogQuestionStyle.Width(contentWidth).Render("Do you want to allow this tool call?")
-	options := RenderHelpKeys(contentWidth, "Y", "yes", "N", "no", "T", d.alwaysAllowHelpText(), "A", "all tools")
+	options := RenderHelpKeys(contentWidth, d.helpKeys()...)
 
 	parts = append(parts, "", question, "", options)
 
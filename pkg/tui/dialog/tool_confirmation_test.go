@@ -0,0 +1,43 @@
+package dialog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tui/service"
+)
+
+func newTestToolConfirmationDialog(name, arguments string) *toolConfirmationDialog {
+	event := runtime.ToolCallConfirmation(tools.ToolCall{
+		ID:       "call_1",
+		Function: tools.FunctionCall{Name: name, Arguments: arguments},
+	}, tools.Tool{Name: name}, "root")
+	return NewToolConfirmationDialog(event.(*runtime.ToolCallConfirmationEvent), service.NewSessionState(session.New())).(*toolConfirmationDialog)
+}
+
+func TestToolConfirmationDialog_Scopes(t *testing.T) {
+	t.Parallel()
+
+	d := newTestToolConfirmationDialog("shell", `{"cmd":"git status --short"}`)
+	assert.Equal(t, "shell:cmd=git status --short", d.permissionPattern)
+	assert.Equal(t, []string{"Y", "once", "S", "session", "T", "always shell", "P", "always git status --short", "A", "all tools", "N", "no"}, d.helpKeys())
+
+	d.SetSize(120, 50)
+	view := d.View()
+	assert.Contains(t, view, "git status --short")
+	assert.Contains(t, view, "always git status --short")
+}
+
+func TestToolConfirmationDialog_NoPatternForOtherTools(t *testing.T) {
+	t.Parallel()
+
+	d := newTestToolConfirmationDialog("write_file", `{"path":"main.go","content":"package main"}`)
+	assert.Equal(t, []string{"Y", "once", "S", "session", "T", "always write_file", "A", "all tools", "N", "no"}, d.helpKeys())
+
+	_, cmd := d.executeAction("P")
+	assert.Nil(t, cmd)
+}
@@ -0,0 +1,109 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/tui/core"
+	"github.com/docker/docker-agent/pkg/tui/core/layout"
+	"github.com/docker/docker-agent/pkg/tui/styles"
+)
+
+// maxFlaggedLines is the number of flagged parts shown in the dialog.
+const maxFlaggedLines = 8
+
+type toolResultConfirmationDialog struct {
+	BaseDialog
+	msg    *runtime.ToolResultConfirmationEvent
+	keyMap ConfirmKeyMap
+}
+
+// NewToolResultConfirmationDialog creates a dialog asking whether the result
+// of a tool call, flagged by content filters, can be sent to the model.
+func NewToolResultConfirmationDialog(msg *runtime.ToolResultConfirmationEvent) Dialog {
+	return &toolResultConfirmationDialog{
+		msg:    msg,
+		keyMap: DefaultConfirmKeyMap(),
+	}
+}
+
+// Init initializes the tool result confirmation dialog
+func (d *toolResultConfirmationDialog) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the tool result confirmation dialog
+func (d *toolResultConfirmationDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		if cmd := HandleQuit(msg); cmd != nil {
+			return d, cmd
+		}
+
+		model, cmd, handled := HandleConfirmKeys(msg, d.keyMap,
+			func() (layout.Model, tea.Cmd) {
+				return d, tea.Sequence(
+					core.CmdHandler(CloseDialogMsg{}),
+					core.CmdHandler(RuntimeResumeMsg{Request: runtime.ResumeApprove()}),
+				)
+			},
+			func() (layout.Model, tea.Cmd) {
+				return d, tea.Sequence(
+					core.CmdHandler(CloseDialogMsg{}),
+					core.CmdHandler(RuntimeResumeMsg{Request: runtime.ResumeReject("")}),
+				)
+			},
+		)
+		if handled {
+			return model, cmd
+		}
+	}
+
+	return d, nil
+}
+
+// Position returns the dialog position (centered)
+func (d *toolResultConfirmationDialog) Position() (row, col int) {
+	return d.CenterDialog(d.View())
+}
+
+// View renders the tool result confirmation dialog
+func (d *toolResultConfirmationDialog) View() string {
+	dialogWidth := d.ComputeDialogWidth(maxIterDialogWidthPercent, maxIterDialogMinWidth, maxIterDialogMaxWidth)
+	contentWidth := dialogWidth - styles.DialogWarningStyle.GetHorizontalFrameSize()
+
+	messageText := fmt.Sprintf("The result of %s looks like a prompt injection (%s):", d.msg.ToolCall.Function.Name, strings.Join(d.msg.Filters, ", "))
+	questionText := "Send it to the model anyway? Otherwise, it's withheld."
+
+	content := NewContent(contentWidth).
+		AddTitle("Tool Result Flagged").
+		AddSeparator().
+		AddContent(styles.DialogContentStyle.Render(wrapDisplayText(messageText, contentWidth))).
+		AddSpace()
+	for i, match := range d.msg.Matches {
+		if i == maxFlaggedLines {
+			content.AddContent(styles.MutedStyle.Render(fmt.Sprintf("… and %d more", len(d.msg.Matches)-i)))
+			break
+		}
+		content.AddContent(styles.DialogContentStyle.Render(wrapDisplayText(fmt.Sprintf("%q", match), contentWidth)))
+	}
+
+	view := content.
+		AddSpace().
+		AddContent(styles.DialogQuestionStyle.Width(contentWidth).Render(wrapDisplayText(questionText, contentWidth))).
+		AddSpace().
+		AddHelpKeys("Y", "send", "N", "withhold").
+		Build()
+
+	// DialogWarningStyle already includes Padding(1, 2)
+	return styles.DialogWarningStyle.
+		Width(dialogWidth).
+		Render(view)
+}
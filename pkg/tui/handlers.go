@@ -8,12 +8,15 @@ import (
 	"os/exec"
 	goruntime "runtime"
 	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/atotto/clipboard"
 
 	"github.com/docker/docker-agent/pkg/app"
+	"github.com/docker/docker-agent/pkg/audio/speech"
 	"github.com/docker/docker-agent/pkg/browser"
+	"github.com/docker/docker-agent/pkg/environment"
 	"github.com/docker/docker-agent/pkg/evaluation"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/session"
@@ -54,10 +57,25 @@ func (m *appModel) handleBranchFromEdit(msg messages.BranchFromEditMsg) (tea.Mod
 		return m, notification.ErrorCmd(fmt.Sprintf("Failed to branch session: %v", err))
 	}
 
-	if err := store.AddSession(ctx, newSess); err != nil {
+	cmds, err := m.switchToBranch(ctx, store, newSess)
+	if err != nil {
 		return m, notification.ErrorCmd(fmt.Sprintf("Failed to save branched session: %v", err))
 	}
 
+	return m, tea.Sequence(append(cmds, core.CmdHandler(messages.SendMsg{
+		Content:     msg.Content,
+		Attachments: msg.Attachments,
+	}))...)
+}
+
+// switchToBranch saves a session branched from the current one and makes it
+// the session of the active tab. It returns the commands that initialize the
+// components of the branch.
+func (m *appModel) switchToBranch(ctx context.Context, store session.Store, newSess *session.Session) ([]tea.Cmd, error) {
+	if err := store.AddSession(ctx, newSess); err != nil {
+		return nil, err
+	}
+
 	if current := m.application.Session(); current != nil {
 		newSess.HideToolResults = current.HideToolResults
 		newSess.ToolsApproved = current.ToolsApproved
@@ -87,15 +105,95 @@ func (m *appModel) handleBranchFromEdit(msg messages.BranchFromEditMsg) (tea.Mod
 
 	m.reapplyKeyboardEnhancements()
 
-	return m, tea.Sequence(
+	return []tea.Cmd{
 		m.chatPage.Init(),
 		m.resizeAll(),
 		m.editor.Focus(),
-		core.CmdHandler(messages.SendMsg{
-			Content:     msg.Content,
-			Attachments: msg.Attachments,
-		}),
-	)
+	}, nil
+}
+
+// handleRerunFromStep re-runs the session from a step of the time travel
+// dialog. Model calls are regenerated right away; user messages and tool
+// results are edited in the external editor first.
+func (m *appModel) handleRerunFromStep(msg messages.RerunFromStepMsg) (tea.Model, tea.Cmd) {
+	if msg.Regenerate {
+		return m.forkAndContinue(msg.SessionID, func(parent *session.Session) (*session.Session, error) {
+			return session.BranchSession(parent, msg.Position)
+		})
+	}
+
+	tmpFile, err := os.CreateTemp("", "cagent-step-*.md")
+	if err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to create temp file: %v", err))
+	}
+	tmpPath := tmpFile.Name()
+	if _, err := tmpFile.WriteString(msg.Content); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to write temp file: %v", err))
+	}
+	tmpFile.Close()
+
+	return m, tea.ExecProcess(editorCommand(tmpPath), func(err error) tea.Msg {
+		defer os.Remove(tmpPath)
+		if err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Editor error: %v", err), Type: notification.TypeError}
+		}
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Failed to read edited file: %v", err), Type: notification.TypeError}
+		}
+
+		// Trim trailing newline that editors often add
+		content := strings.TrimSuffix(string(edited), "\n")
+		if strings.TrimSpace(content) == "" {
+			return notification.ShowMsg{Text: "The edited content is empty, nothing was re-run.", Type: notification.TypeInfo}
+		}
+		return messages.ForkFromStepMsg{SessionID: msg.SessionID, Position: msg.Position, ToolResult: msg.ToolResult, Content: content}
+	})
+}
+
+// handleForkFromStep re-runs the session in a branch from an edited user
+// message or tool result.
+func (m *appModel) handleForkFromStep(msg messages.ForkFromStepMsg) (tea.Model, tea.Cmd) {
+	if !msg.ToolResult {
+		return m.handleBranchFromEdit(messages.BranchFromEditMsg{
+			ParentSessionID:  msg.SessionID,
+			BranchAtPosition: msg.Position,
+			Content:          msg.Content,
+		})
+	}
+	return m.forkAndContinue(msg.SessionID, func(parent *session.Session) (*session.Session, error) {
+		return session.BranchSessionWithToolResult(parent, msg.Position, msg.Content)
+	})
+}
+
+// forkAndContinue branches the stored session with branch, switches to the
+// branch, and continues its run without a new message.
+func (m *appModel) forkAndContinue(sessionID string, branch func(*session.Session) (*session.Session, error)) (tea.Model, tea.Cmd) {
+	store := m.application.SessionStore()
+	if store == nil {
+		return m, notification.ErrorCmd("No session store configured")
+	}
+
+	ctx := context.Background()
+
+	parent, err := store.GetSession(ctx, sessionID)
+	if err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to load parent session: %v", err))
+	}
+
+	newSess, err := branch(parent)
+	if err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to branch session: %v", err))
+	}
+
+	cmds, err := m.switchToBranch(ctx, store, newSess)
+	if err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to save branched session: %v", err))
+	}
+
+	return m, tea.Sequence(append(cmds, core.CmdHandler(messages.ContinueRunMsg{}))...)
 }
 
 func (m *appModel) handleToggleSessionStar(sessionID string) (tea.Model, tea.Cmd) {
@@ -123,6 +221,32 @@ func (m *appModel) handleToggleSessionStar(sessionID string) (tea.Model, tea.Cmd
 	return m, nil
 }
 
+func (m *appModel) handleRenameSession(sessionID, title string) (tea.Model, tea.Cmd) {
+	if currentSess := m.application.Session(); currentSess != nil && currentSess.ID == sessionID {
+		return m.handleSetSessionTitle(title)
+	}
+
+	store := m.application.SessionStore()
+	if store == nil {
+		return m, notification.ErrorCmd("No session store configured")
+	}
+	if err := store.UpdateSessionTitle(context.Background(), sessionID, title); err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to rename session: %v", err))
+	}
+	return m, notification.SuccessCmd("Session renamed to: " + title)
+}
+
+func (m *appModel) handleDeleteSession(sessionID string) (tea.Model, tea.Cmd) {
+	store := m.application.SessionStore()
+	if store == nil {
+		return m, notification.ErrorCmd("No session store configured")
+	}
+	if err := store.DeleteSession(context.Background(), sessionID); err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to delete session: %v", err))
+	}
+	return m, notification.SuccessCmd("Session deleted.")
+}
+
 func (m *appModel) handleSetSessionTitle(title string) (tea.Model, tea.Cmd) {
 	if err := m.application.UpdateSessionTitle(context.Background(), title); err != nil {
 		if isErrTitleGenerating(err) {
@@ -174,6 +298,10 @@ func (m *appModel) handleCompactSession(additionalPrompt string) (tea.Model, tea
 	return m, m.chatPage.CompactSession(additionalPrompt)
 }
 
+func (m *appModel) handleContinueRun(iterations int) (tea.Model, tea.Cmd) {
+	return m, m.chatPage.ContinueRun(iterations)
+}
+
 func (m *appModel) handleCopySessionToClipboard() (tea.Model, tea.Cmd) {
 	transcript := m.application.PlainTextTranscript()
 	if transcript == "" {
@@ -258,6 +386,22 @@ func (m *appModel) handleToggleYolo() (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m *appModel) handleToggleSimulate() (tea.Model, tea.Cmd) {
+	sess := m.application.Session()
+	sess.Simulate = !sess.Simulate
+	m.sessionState.SetSimulate(sess.Simulate)
+
+	var infoMsg string
+	if sess.Simulate {
+		infoMsg = "Simulation enabled: tool calls that change things are described instead of run"
+	} else {
+		infoMsg = "Simulation disabled: ask the agent to carry out its plan for real"
+	}
+	updated, cmd := m.chatPage.Update(messages.SessionToggleChangedMsg{})
+	m.chatPage = updated.(chat.Page)
+	return m, tea.Batch(cmd, notification.InfoCmd(infoMsg))
+}
+
 func (m *appModel) handleToggleThinking() (tea.Model, tea.Cmd) {
 	if m.cancelThinkingCheck != nil {
 		m.cancelThinkingCheck()
@@ -301,6 +445,12 @@ func (m *appModel) handleToggleHideToolResults() (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m *appModel) handleToggleAgentPanes() (tea.Model, tea.Cmd) {
+	updated, cmd := m.chatPage.Update(messages.ToggleAgentPanesMsg{})
+	m.chatPage = updated.(chat.Page)
+	return m, cmd
+}
+
 func (m *appModel) handleToggleSplitDiff() (tea.Model, tea.Cmd) {
 	m.sessionState.ToggleSplitDiffView()
 	enabled := m.sessionState.SplitDiffView()
@@ -340,6 +490,26 @@ func (m *appModel) handleShowCostDialog() (tea.Model, tea.Cmd) {
 	})
 }
 
+func (m *appModel) handleShowArtifactsDialog() (tea.Model, tea.Cmd) {
+	var artifacts []session.Artifact
+	if sess := m.application.Session(); sess != nil {
+		artifacts = sess.GetArtifacts()
+	}
+	return m, core.CmdHandler(dialog.OpenDialogMsg{
+		Model: dialog.NewArtifactsDialog(artifacts),
+	})
+}
+
+func (m *appModel) handleShowTimeTravelDialog() (tea.Model, tea.Cmd) {
+	sess := m.application.Session()
+	if sess == nil {
+		return m, notification.InfoCmd("No session to travel through")
+	}
+	return m, core.CmdHandler(dialog.OpenDialogMsg{
+		Model: dialog.NewTimeTravelDialog(sess),
+	})
+}
+
 func (m *appModel) handleShowPermissionsDialog() (tea.Model, tea.Cmd) {
 	perms := m.application.PermissionsInfo()
 	sess := m.application.Session()
@@ -601,6 +771,147 @@ func (m *appModel) closeTranscriptCh() {
 	}
 }
 
+// --- Voice mode ---
+
+func (m *appModel) handleStartDictation() (tea.Model, tea.Cmd) {
+	if m.transcriber.IsRunning() {
+		return m, nil
+	}
+	m.stopReadingAloud()
+
+	if err := m.recorder.Start(); err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to start recording: %v", err))
+	}
+
+	return m, tea.Batch(
+		notification.InfoCmd("🎤 Recording... (ENTER to transcribe or ESC to cancel)"),
+		m.editor.SetRecording(true),
+	)
+}
+
+// handleStopDictation stops push-to-talk recording and, if transcribe is
+// true, transcribes the recording with the configured transcription model.
+func (m *appModel) handleStopDictation(transcribe bool) (tea.Model, tea.Cmd) {
+	if !m.recorder.IsRecording() {
+		return m, nil
+	}
+
+	wav, err := m.recorder.Stop()
+	recordingCmd := m.editor.SetRecording(false)
+	if err != nil {
+		return m, tea.Batch(recordingCmd, notification.ErrorCmd(fmt.Sprintf("Failed to stop recording: %v", err)))
+	}
+	if !transcribe {
+		return m, tea.Batch(recordingCmd, notification.InfoCmd("Recording discarded"))
+	}
+
+	model := userconfig.Get().GetTranscriptionModel()
+	return m, tea.Batch(recordingCmd, notification.InfoCmd("Transcribing..."), func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		transcriber, err := speech.NewTranscriber(ctx, speechConfig(model), environment.NewDefaultProvider())
+		if err != nil {
+			return messages.DictationTranscribedMsg{Err: err}
+		}
+		text, err := transcriber.Transcribe(ctx, wav)
+		return messages.DictationTranscribedMsg{Text: text, Err: err}
+	})
+}
+
+func (m *appModel) handleDictationTranscribed(msg messages.DictationTranscribedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to transcribe recording: %v", msg.Err))
+	}
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		return m, notification.InfoCmd("No speech detected")
+	}
+
+	m.editor.InsertText(text)
+	return m, notification.SuccessCmd("Transcribed")
+}
+
+func (m *appModel) handleToggleReadReplies() (tea.Model, tea.Cmd) {
+	m.sessionState.ToggleReadReplies()
+	enabled := m.sessionState.ReadReplies()
+
+	// Persist to global userconfig
+	go func() {
+		cfg, err := userconfig.Load()
+		if err != nil {
+			slog.Warn("Failed to load userconfig for voice toggle", "error", err)
+			return
+		}
+		if cfg.Settings == nil {
+			cfg.Settings = &userconfig.Settings{}
+		}
+		if cfg.Settings.Voice == nil {
+			cfg.Settings.Voice = &userconfig.VoiceSettings{}
+		}
+		cfg.Settings.Voice.ReadReplies = enabled
+		if err := cfg.Save(); err != nil {
+			slog.Warn("Failed to persist voice setting to userconfig", "error", err)
+		}
+	}()
+
+	if !enabled {
+		m.stopReadingAloud()
+		return m, notification.InfoCmd("Replies are no longer read aloud")
+	}
+	return m, notification.InfoCmd("🔊 Replies are read aloud")
+}
+
+// handleReadAloud synthesizes the text with the configured speech model and
+// plays it, interrupting any reply still being read.
+func (m *appModel) handleReadAloud(text string) (tea.Model, tea.Cmd) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return m, nil
+	}
+
+	m.stopReadingAloud()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopReading = cancel
+
+	model := userconfig.Get().GetSpeechModel()
+	return m, func() tea.Msg {
+		err := readAloud(ctx, model, text)
+		if err != nil && ctx.Err() == nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Failed to read reply aloud: %v", err), Type: notification.TypeError}
+		}
+		return nil
+	}
+}
+
+func readAloud(ctx context.Context, model userconfig.VoiceModel, text string) error {
+	synthesizer, err := speech.NewSynthesizer(ctx, speechConfig(model), environment.NewDefaultProvider())
+	if err != nil {
+		return err
+	}
+	wav, err := synthesizer.Synthesize(ctx, text, model.Voice)
+	if err != nil {
+		return err
+	}
+	return speech.Play(ctx, wav)
+}
+
+// stopReadingAloud interrupts the reply being read aloud, if any.
+func (m *appModel) stopReadingAloud() {
+	if m.stopReading != nil {
+		m.stopReading()
+		m.stopReading = nil
+	}
+}
+
+func speechConfig(model userconfig.VoiceModel) speech.Config {
+	return speech.Config{
+		Provider: model.Provider,
+		Model:    model.Model,
+		BaseURL:  model.BaseURL,
+	}
+}
+
 func (m *appModel) handleElicitationResponse(action tools.ElicitationAction, content map[string]any) (tea.Model, tea.Cmd) {
 	if err := m.application.ResumeElicitation(context.Background(), action, content); err != nil {
 		slog.Error("Failed to resume elicitation", "action", action, "error", err)
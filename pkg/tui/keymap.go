@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/docker-agent/pkg/tui/commands"
+)
+
+// Names of the global actions that can be rebound in the keybindings section
+// of the user config. Any other name is taken as the ID of a command palette
+// item, e.g. "session.compact".
+const (
+	actionCommandPalette    = "command_palette"
+	actionSuspend           = "suspend"
+	actionToggleYolo        = "toggle_yolo"
+	actionToggleToolResults = "toggle_tool_results"
+	actionCycleAgent        = "cycle_agent"
+	actionSwitchModel       = "switch_model"
+	actionClearQueue        = "clear_queue"
+	actionExternalEditor    = "external_editor"
+	actionHistorySearch     = "history_search"
+	actionToggleSidebar     = "toggle_sidebar"
+	actionPushToTalk        = "push_to_talk"
+)
+
+// keyMap holds the global key bindings of the TUI.
+type keyMap struct {
+	CommandPalette    key.Binding
+	Suspend           key.Binding
+	ToggleYolo        key.Binding
+	ToggleToolResults key.Binding
+	CycleAgent        key.Binding
+	SwitchModel       key.Binding
+	ClearQueue        key.Binding
+	ExternalEditor    key.Binding
+	HistorySearch     key.Binding
+	ToggleSidebar     key.Binding
+	PushToTalk        key.Binding
+
+	// Commands binds keys to command palette items, by ID.
+	Commands map[string]key.Binding
+}
+
+// defaultKeyMap returns the default global key bindings.
+func defaultKeyMap() keyMap {
+	return keyMap{
+		CommandPalette:    newBinding("commands", "ctrl+k"),
+		Suspend:           newBinding("suspend", "ctrl+z"),
+		ToggleYolo:        newBinding("toggle yolo", "ctrl+y"),
+		ToggleToolResults: newBinding("toggle tool results", "ctrl+o"),
+		CycleAgent:        newBinding("cycle agent", "ctrl+s"),
+		SwitchModel:       newBinding("switch model", "ctrl+m"),
+		ClearQueue:        newBinding("clear queue", "ctrl+x"),
+		ExternalEditor:    newBinding("edit in editor", "ctrl+g"),
+		HistorySearch:     newBinding("history search", "ctrl+r"),
+		ToggleSidebar:     newBinding("toggle sidebar", "ctrl+b"),
+		PushToTalk:        newBinding("push to talk", "ctrl+l"),
+		Commands:          map[string]key.Binding{},
+	}
+}
+
+// newKeyMap returns the default key bindings with the given overrides
+// applied. Overrides map action names or command IDs to keys; an empty list
+// of keys unbinds the action.
+func newKeyMap(overrides map[string][]string) keyMap {
+	km := defaultKeyMap()
+	actions := km.actions()
+	for name, keys := range overrides {
+		if binding, ok := actions[name]; ok {
+			*binding = newBinding(binding.Help().Desc, keys...)
+			continue
+		}
+		km.Commands[name] = newBinding(name, keys...)
+	}
+	return km
+}
+
+// actions returns the global actions, by name.
+func (km *keyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		actionCommandPalette:    &km.CommandPalette,
+		actionSuspend:           &km.Suspend,
+		actionToggleYolo:        &km.ToggleYolo,
+		actionToggleToolResults: &km.ToggleToolResults,
+		actionCycleAgent:        &km.CycleAgent,
+		actionSwitchModel:       &km.SwitchModel,
+		actionClearQueue:        &km.ClearQueue,
+		actionExternalEditor:    &km.ExternalEditor,
+		actionHistorySearch:     &km.HistorySearch,
+		actionToggleSidebar:     &km.ToggleSidebar,
+		actionPushToTalk:        &km.PushToTalk,
+	}
+}
+
+// matchCommand returns the ID of the command palette item bound to the
+// pressed key, if any.
+func (km *keyMap) matchCommand(msg tea.KeyPressMsg) (string, bool) {
+	// Sort for a deterministic match when a key is bound twice.
+	for _, id := range slices.Sorted(maps.Keys(km.Commands)) {
+		if key.Matches(msg, km.Commands[id]) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// shortcut returns the key bound to a command palette item, for display.
+// Commands that have a global action equivalent show the key of that action.
+func (km *keyMap) shortcut(id string) string {
+	if binding, ok := km.Commands[id]; ok {
+		return binding.Help().Key
+	}
+
+	var binding key.Binding
+	switch id {
+	case "session.dictate":
+		binding = km.PushToTalk
+	case "session.editor":
+		binding = km.ExternalEditor
+	case "session.model":
+		binding = km.SwitchModel
+	case "session.yolo":
+		binding = km.ToggleYolo
+	case "settings.tool-results":
+		binding = km.ToggleToolResults
+	default:
+		return ""
+	}
+	if !binding.Enabled() {
+		return ""
+	}
+	return binding.Help().Key
+}
+
+// annotate sets the shortcut of every command of the palette.
+func (km *keyMap) annotate(categories []commands.Category) {
+	for i := range categories {
+		for j := range categories[i].Commands {
+			categories[i].Commands[j].Shortcut = km.shortcut(categories[i].Commands[j].ID)
+		}
+	}
+}
+
+// newBinding creates a binding for the given keys, with help text in the
+// format of the status bar. A binding with no keys is disabled.
+func newBinding(desc string, keys ...string) key.Binding {
+	if len(keys) == 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(keyHelp(keys[0]), desc),
+	)
+}
+
+// keyHelp formats a key for display, e.g. "ctrl+k" becomes "Ctrl+k" and
+// "shift+enter" becomes "Shift+Enter".
+func keyHelp(k string) string {
+	parts := strings.Split(k, "+")
+	for i, part := range parts {
+		if part != "" && (i < len(parts)-1 || len(part) > 1) {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"testing"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/docker-agent/pkg/tui/commands"
+)
+
+func TestNewKeyMap_Defaults(t *testing.T) {
+	t.Parallel()
+
+	km := newKeyMap(nil)
+
+	assert.True(t, key.Matches(tea.KeyPressMsg{Code: 'k', Mod: tea.ModCtrl}, km.CommandPalette))
+	assert.True(t, key.Matches(tea.KeyPressMsg{Code: 'b', Mod: tea.ModCtrl}, km.ToggleSidebar))
+	assert.Equal(t, "Ctrl+k", km.CommandPalette.Help().Key)
+	assert.Empty(t, km.Commands)
+}
+
+func TestNewKeyMap_Overrides(t *testing.T) {
+	t.Parallel()
+
+	km := newKeyMap(map[string][]string{
+		"toggle_sidebar":  {"alt+b"},
+		"toggle_yolo":     {},
+		"session.compact": {"alt+c"},
+	})
+
+	assert.False(t, key.Matches(tea.KeyPressMsg{Code: 'b', Mod: tea.ModCtrl}, km.ToggleSidebar))
+	assert.True(t, key.Matches(tea.KeyPressMsg{Code: 'b', Mod: tea.ModAlt}, km.ToggleSidebar))
+	assert.Equal(t, "toggle sidebar", km.ToggleSidebar.Help().Desc)
+
+	assert.False(t, km.ToggleYolo.Enabled())
+	assert.False(t, key.Matches(tea.KeyPressMsg{Code: 'y', Mod: tea.ModCtrl}, km.ToggleYolo))
+
+	id, ok := km.matchCommand(tea.KeyPressMsg{Code: 'c', Mod: tea.ModAlt})
+	assert.True(t, ok)
+	assert.Equal(t, "session.compact", id)
+
+	_, ok = km.matchCommand(tea.KeyPressMsg{Code: 'x', Mod: tea.ModAlt})
+	assert.False(t, ok)
+}
+
+func TestKeyMap_Annotate(t *testing.T) {
+	t.Parallel()
+
+	km := newKeyMap(map[string][]string{
+		"switch_model":    {"alt+m"},
+		"toggle_yolo":     {},
+		"session.compact": {"alt+c"},
+	})
+	categories := []commands.Category{{
+		Name: "Session",
+		Commands: []commands.Item{
+			{ID: "session.compact"},
+			{ID: "session.model"},
+			{ID: "session.yolo"},
+			{ID: "settings.tool-results"},
+			{ID: "session.dictate"},
+			{ID: "session.new"},
+		},
+	}}
+
+	km.annotate(categories)
+
+	var shortcuts []string
+	for _, cmd := range categories[0].Commands {
+		shortcuts = append(shortcuts, cmd.Shortcut)
+	}
+	assert.Equal(t, []string{"Alt+c", "Alt+m", "", "Ctrl+o", "Ctrl+l", ""}, shortcuts)
+}
+
+func TestKeyHelp(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Ctrl+k", keyHelp("ctrl+k"))
+	assert.Equal(t, "Shift+Enter", keyHelp("shift+enter"))
+	assert.Equal(t, "Ctrl+Alt+x", keyHelp("ctrl+alt+x"))
+	assert.Equal(t, "F2", keyHelp("f2"))
+}
@@ -15,6 +15,26 @@ type BranchFromEditMsg struct {
 	Attachments      []Attachment
 }
 
+// RerunFromStepMsg requests re-running the session from one of its items,
+// from the time travel dialog. Model calls are regenerated as they are; user
+// messages and tool results are edited first, starting from Content.
+type RerunFromStepMsg struct {
+	SessionID  string
+	Position   int
+	Regenerate bool
+	ToolResult bool
+	Content    string
+}
+
+// ForkFromStepMsg re-runs the session in a branch, from the user message or
+// tool result at Position with its content replaced by Content.
+type ForkFromStepMsg struct {
+	SessionID  string
+	Position   int
+	ToolResult bool
+	Content    string
+}
+
 // InvalidateStatusBarMsg signals that the statusbar cache should be invalidated.
 // This is emitted when bindings change (e.g., entering/exiting inline edit mode).
 type InvalidateStatusBarMsg struct{}
@@ -17,6 +17,29 @@ type (
 	// SpeakTranscriptMsg contains transcription delta from speech-to-text.
 	SpeakTranscriptMsg struct{ Delta string }
 
+	// ToggleDictationMsg starts push-to-talk recording, or stops it and
+	// transcribes the recording into the editor.
+	ToggleDictationMsg struct{}
+
+	// CancelDictationMsg stops push-to-talk recording without transcribing.
+	CancelDictationMsg struct{}
+
+	// DictationTranscribedMsg contains the transcription of a push-to-talk
+	// recording.
+	DictationTranscribedMsg struct {
+		Text string
+		Err  error
+	}
+
+	// ReadAloudMsg reads an assistant reply aloud.
+	ReadAloudMsg struct{ Text string }
+
+	// OpenExternalEditorMsg opens the draft in $VISUAL or $EDITOR.
+	OpenExternalEditorMsg struct{}
+
+	// ExternalEditorDoneMsg contains the draft saved in the external editor.
+	ExternalEditorDoneMsg struct{ Content string }
+
 	// StartShellMsg starts an interactive shell.
 	StartShellMsg struct{}
 
@@ -35,9 +35,16 @@ type (
 	// EvalSessionMsg saves evaluation data to the specified file.
 	EvalSessionMsg struct{ Filename string }
 
+	// OpenDebugDumpMsg opens the last model request dumped by --debug-llm.
+	OpenDebugDumpMsg struct{}
+
 	// CompactSessionMsg generates a summary and compacts session history.
 	CompactSessionMsg struct{ AdditionalPrompt string }
 
+	// ContinueRunMsg continues the run of the session without a new message,
+	// for a number of iterations, e.g. after it paused at its iteration limit.
+	ContinueRunMsg struct{ Iterations int }
+
 	// CopySessionToClipboardMsg copies the entire conversation to clipboard.
 	CopySessionToClipboardMsg struct{}
 
@@ -56,6 +63,12 @@ type (
 	// ToggleSessionStarMsg toggles star on a session; empty ID means current session.
 	ToggleSessionStarMsg struct{ SessionID string }
 
+	// RenameSessionMsg sets the title of a stored session, from the session browser.
+	RenameSessionMsg struct{ SessionID, Title string }
+
+	// DeleteSessionMsg deletes a stored session, from the session browser.
+	DeleteSessionMsg struct{ SessionID string }
+
 	// SetSessionTitleMsg sets the session title to specified value.
 	SetSessionTitleMsg struct{ Title string }
 
@@ -5,6 +5,10 @@ type (
 	// ToggleYoloMsg toggles YOLO mode (auto-approve tools).
 	ToggleYoloMsg struct{}
 
+	// ToggleSimulateMsg toggles the simulation of the tool calls that
+	// change things.
+	ToggleSimulateMsg struct{}
+
 	// ToggleThinkingMsg toggles extended thinking mode.
 	ToggleThinkingMsg struct{}
 
@@ -17,6 +21,12 @@ type (
 	// ToggleHideToolResultsMsg toggles hiding of tool results.
 	ToggleHideToolResultsMsg struct{}
 
+	// ToggleReadRepliesMsg toggles reading assistant replies aloud.
+	ToggleReadRepliesMsg struct{}
+
+	// ToggleAgentPanesMsg toggles the per-agent panes shown in team runs.
+	ToggleAgentPanesMsg struct{}
+
 	// ToggleSidebarMsg toggles sidebar visibility.
 	// The top-level model also handles this to persist the collapsed state.
 	ToggleSidebarMsg struct{}
@@ -28,6 +38,13 @@ type (
 	// ShowCostDialogMsg shows the cost/usage dialog.
 	ShowCostDialogMsg struct{}
 
+	// ShowArtifactsDialogMsg shows the artifacts of the session.
+	ShowArtifactsDialogMsg struct{}
+
+	// ShowTimeTravelDialogMsg shows the time travel dialog, to step through
+	// the session.
+	ShowTimeTravelDialogMsg struct{}
+
 	// ShowPermissionsDialogMsg shows the permissions dialog.
 	ShowPermissionsDialogMsg struct{}
 )
@@ -18,6 +18,7 @@ import (
 	"github.com/docker/docker-agent/pkg/app"
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/tui/commands"
+	"github.com/docker/docker-agent/pkg/tui/components/agentpanes"
 	"github.com/docker/docker-agent/pkg/tui/components/messages"
 	"github.com/docker/docker-agent/pkg/tui/components/notification"
 	"github.com/docker/docker-agent/pkg/tui/components/sidebar"
@@ -62,6 +63,12 @@ type sidebarLayout struct {
 	handleX       int // X coordinate of resize handle column (only valid in vertical mode)
 	chatHeight    int // height available for chat area
 	sidebarHeight int // height of sidebar
+	panesHeight   int // height of the agent panes, at the bottom of the chat area
+}
+
+// messagesHeight returns the height of the messages, above the agent panes.
+func (l sidebarLayout) messagesHeight() int {
+	return max(1, l.chatHeight-l.panesHeight)
 }
 
 // isOnHandle returns true if adjustedX (already adjusted for app padding) is on the resize handle.
@@ -95,6 +102,8 @@ type Page interface {
 	layout.Sizeable
 	layout.Help
 	CompactSession(additionalPrompt string) tea.Cmd
+	// ContinueRun continues the run of the session for a number of iterations.
+	ContinueRun(iterations int) tea.Cmd
 	Cleanup()
 	// SetSessionStarred updates the sidebar star indicator
 	SetSessionStarred(starred bool)
@@ -118,6 +127,8 @@ type Page interface {
 	GetSidebarSettings() SidebarSettings
 	// SetSidebarSettings applies sidebar display settings
 	SetSidebarSettings(settings SidebarSettings)
+	// ToggleSidebar collapses or expands the sidebar
+	ToggleSidebar() tea.Cmd
 }
 
 // queuedMessage represents a message waiting to be sent to the agent
@@ -134,8 +145,9 @@ type chatPage struct {
 	width, height int
 
 	// Components
-	sidebar  sidebar.Model
-	messages messages.Model
+	sidebar    sidebar.Model
+	messages   messages.Model
+	agentPanes *agentpanes.Model
 
 	sessionState *service.SessionState
 
@@ -212,6 +224,8 @@ func (p *chatPage) computeSidebarLayout() sidebarLayout {
 		l.chatHeight = max(1, p.height-l.sidebarHeight)
 	}
 
+	l.panesHeight = p.agentPanes.Height(l.chatHeight)
+
 	return l
 }
 
@@ -219,7 +233,6 @@ func (p *chatPage) computeSidebarLayout() sidebarLayout {
 type KeyMap struct {
 	Cancel          key.Binding
 	ToggleSplitDiff key.Binding
-	ToggleSidebar   key.Binding
 }
 
 // defaultKeyMap returns the default key bindings.
@@ -238,10 +251,6 @@ func defaultKeyMap() KeyMap {
 			key.WithHelp("Esc", "interrupt"),
 		),
 		ToggleSplitDiff: splitDiff,
-		ToggleSidebar: key.NewBinding(
-			key.WithKeys("ctrl+b"),
-			key.WithHelp("Ctrl+b", "toggle sidebar"),
-		),
 	}
 }
 
@@ -305,6 +314,7 @@ func New(a *app.App, sessionState *service.SessionState) Page {
 	p := &chatPage{
 		sidebar:      sidebar.New(sessionState),
 		messages:     messages.New(sessionState),
+		agentPanes:   agentpanes.New(),
 		app:          a,
 		keyMap:       defaultKeyMap(),
 		sessionState: sessionState,
@@ -400,6 +410,9 @@ func (p *chatPage) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 	case msgtypes.ClearQueueMsg:
 		return p.handleClearQueue()
 
+	case msgtypes.ToggleAgentPanesMsg:
+		return p.handleToggleAgentPanes()
+
 	case msgtypes.ThemeChangedMsg:
 		// Theme changed - forward to all child components to invalidate caches
 		var cmds []tea.Cmd
@@ -417,8 +430,9 @@ func (p *chatPage) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 
 	default:
 		// Try to handle as a runtime event
+		panesCmd := p.updateAgentPanes(msg)
 		if handled, cmd := p.handleRuntimeEvent(msg); handled {
-			return p, cmd
+			return p, tea.Batch(cmd, panesCmd)
 		}
 	}
 
@@ -445,6 +459,15 @@ func (p *chatPage) setWorking(working bool) tea.Cmd {
 	return nil
 }
 
+// handleToggleAgentPanes shows or hides one pane per agent below the messages.
+func (p *chatPage) handleToggleAgentPanes() (layout.Model, tea.Cmd) {
+	text := "Agent panes disabled"
+	if p.agentPanes.Toggle() {
+		text = "Agent panes enabled, shown when more than one agent is active"
+	}
+	return p, tea.Batch(p.SetSize(p.width, p.height), notification.InfoCmd(text))
+}
+
 // setPendingResponse adds or removes the pending-response spinner message
 // inside the messages component. When starting, it adds a spinner message to
 // the scrollable list; when stopping, it explicitly removes any lingering spinner.
@@ -503,10 +526,7 @@ func (p *chatPage) View() string {
 
 	switch sl.mode {
 	case sidebarVertical:
-		chatView := styles.ChatStyle.
-			Height(sl.chatHeight).
-			Width(sl.chatWidth).
-			Render(messagesView)
+		chatView := p.renderChat(sl, sl.chatWidth, messagesView)
 
 		toggleCol := p.renderSidebarHandle(sl.chatHeight)
 
@@ -521,10 +541,7 @@ func (p *chatPage) View() string {
 	case sidebarCollapsed, sidebarCollapsedNarrow:
 		sidebarRendered := p.renderCollapsedSidebar(sl)
 
-		chatView := styles.ChatStyle.
-			Height(sl.chatHeight).
-			Width(sl.innerWidth).
-			Render(messagesView)
+		chatView := p.renderChat(sl, sl.innerWidth, messagesView)
 
 		bodyContent = lipgloss.JoinVertical(lipgloss.Top, sidebarRendered, chatView)
 	}
@@ -534,6 +551,20 @@ func (p *chatPage) View() string {
 		Render(bodyContent)
 }
 
+// renderChat renders the messages and, below them, the agent panes when
+// they are visible.
+func (p *chatPage) renderChat(sl sidebarLayout, width int, messagesView string) string {
+	chatView := styles.ChatStyle.
+		Height(sl.messagesHeight()).
+		Width(width).
+		Render(messagesView)
+
+	if sl.panesHeight == 0 {
+		return chatView
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, chatView, p.agentPanes.View())
+}
+
 // renderSidebarHandle renders the sidebar toggle/resize handle.
 // When collapsed: shows just « at top.
 // When expanded: shows » at top, rest is empty space (draggable for resize).
@@ -583,7 +614,8 @@ func (p *chatPage) SetSize(width, height int) tea.Cmd {
 		)
 	}
 
-	cmds = append(cmds, p.messages.SetSize(sl.chatWidth, sl.chatHeight))
+	p.agentPanes.SetSize(sl.chatWidth, sl.panesHeight)
+	cmds = append(cmds, p.messages.SetSize(sl.chatWidth, sl.messagesHeight()))
 
 	return tea.Batch(cmds...)
 }
@@ -593,6 +625,12 @@ func (p *chatPage) GetSize() (width, height int) {
 	return p.width, p.height
 }
 
+// ToggleSidebar collapses or expands the sidebar
+func (p *chatPage) ToggleSidebar() tea.Cmd {
+	p.sidebar.ToggleCollapsed()
+	return tea.Batch(p.SetSize(p.width, p.height), core.CmdHandler(msgtypes.ToggleSidebarMsg{}))
+}
+
 // Bindings returns key bindings for the chat page
 func (p *chatPage) Bindings() []key.Binding {
 	return p.messages.Bindings()
@@ -892,6 +930,24 @@ func (p *chatPage) CompactSession(additionalPrompt string) tea.Cmd {
 	)
 }
 
+// ContinueRun continues the run of the session without a new message, e.g.
+// after it paused at its max_iterations limit.
+func (p *chatPage) ContinueRun(iterations int) tea.Cmd {
+	if p.working {
+		return notification.InfoCmd("The agent is already running")
+	}
+
+	var ctx context.Context
+	ctx, p.msgCancel = context.WithCancel(context.Background())
+	p.streamDepth = 0
+	p.app.Continue(ctx, p.msgCancel, iterations)
+
+	return tea.Batch(
+		p.setWorking(true),
+		p.messages.ScrollToBottom(),
+	)
+}
+
 func (p *chatPage) Cleanup() {
 	p.sidebar.Cleanup()
 }
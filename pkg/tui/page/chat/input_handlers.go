@@ -62,11 +62,6 @@ func (p *chatPage) handleKeyPress(msg tea.KeyPressMsg) (layout.Model, tea.Cmd) {
 		model, cmd := p.messages.Update(editfile.ToggleDiffViewMsg{})
 		p.messages = model.(messages.Model)
 		return p, cmd
-
-	case key.Matches(msg, p.keyMap.ToggleSidebar):
-		p.sidebar.ToggleCollapsed()
-		cmd := p.SetSize(p.width, p.height)
-		return p, tea.Batch(cmd, core.CmdHandler(msgtypes.ToggleSidebarMsg{}))
 	}
 
 	// Route keys to messages (for scrolling, etc.)
@@ -3,12 +3,14 @@ package chat
 import (
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/sound"
+	"github.com/docker/docker-agent/pkg/tui/components/imageview"
 	"github.com/docker/docker-agent/pkg/tui/components/notification"
 	"github.com/docker/docker-agent/pkg/tui/components/sidebar"
 	"github.com/docker/docker-agent/pkg/tui/core"
@@ -30,6 +32,7 @@ import (
 // Content Events:
 //   - AgentChoiceEvent         → Append text to message
 //   - AgentChoiceReasoningEvent → Append reasoning block
+//   - CitationsEvent           → Append cited sources to message
 //   - UserMessageEvent         → Replace loading with user message
 //
 // Tool Events:
@@ -42,8 +45,9 @@ import (
 //   - TokenUsageEvent, AgentInfoEvent, TeamInfoEvent, etc.
 //
 // Dialogs:
-//   - MaxIterationsReachedEvent → Show max iterations dialog
-//   - ElicitationRequestEvent   → Show elicitation/OAuth dialog
+//   - MaxIterationsReachedEvent   → Show max iterations dialog
+//   - ToolResultConfirmationEvent → Show flagged tool result dialog
+//   - ElicitationRequestEvent     → Show elicitation/OAuth dialog
 
 // handleRuntimeEvent processes runtime events and returns the appropriate command.
 // Returns (handled, cmd) where handled indicates if the event was processed.
@@ -56,18 +60,49 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 		if userconfig.Get().GetSound() {
 			sound.Play(sound.Failure)
 		}
-		return true, p.messages.AddErrorMessage(msg.Error)
+		return true, p.messages.AddErrorMessage(msg.Text())
 
 	case *runtime.WarningEvent:
 		return true, notification.WarningCmd(msg.Message)
 
+	case *runtime.ToolListChangedEvent:
+		return true, notification.WarningCmd(msg.Text())
+
 	case *runtime.ModelFallbackEvent:
 		// Update sidebar with the fallback model immediately so it reflects the switch
 		sidebarCmd := p.sidebar.SetAgentInfo(msg.AgentName, msg.FallbackModel, "")
 		// Notify user when switching to a fallback model, include the reason
 		fallbackMsg := fmt.Sprintf("Model %s failed (%s), switching to %s", msg.FailedModel, msg.Reason, msg.FallbackModel)
+		if msg.Discarded {
+			p.messages.DiscardPartialResponse(msg.AgentName)
+		}
 		return true, tea.Batch(sidebarCmd, notification.WarningCmd(fallbackMsg))
 
+	case *runtime.ModelRetryEvent:
+		retryMsg := fmt.Sprintf("Model %s failed (%s), retrying (attempt %d/%d)", msg.Model, msg.Reason, msg.Attempt, msg.MaxAttempts)
+		if msg.Resumed {
+			retryMsg += ", resuming the response"
+		}
+		if msg.Discarded {
+			p.messages.DiscardPartialResponse(msg.AgentName)
+		}
+		return true, notification.WarningCmd(retryMsg)
+
+	case *runtime.GuardrailEvent:
+		if msg.Discarded {
+			p.messages.DiscardPartialResponse(msg.AgentName)
+		}
+		return true, notification.WarningCmd(fmt.Sprintf("Guardrail %s (%s): %s", msg.Guardrail, msg.Action, msg.Reason))
+
+	case *runtime.ContentFilteredEvent:
+		return true, notification.WarningCmd(fmt.Sprintf("Content filter %s (%s): the result of %s looks like a prompt injection", strings.Join(msg.Filters, ", "), msg.Action, msg.ToolCall.Function.Name))
+
+	case *runtime.RunPausedEvent:
+		return true, notification.InfoCmd(fmt.Sprintf("Run paused after %d iterations. Continue it with /continue [iterations].", msg.MaxIterations))
+
+	case *runtime.ModelPullProgressEvent:
+		return true, notification.InfoCmd(fmt.Sprintf("Pulling %s: %s", msg.Model, msg.Status))
+
 	// ===== Stream Lifecycle Events =====
 	case *runtime.StreamStartedEvent:
 		return true, p.handleStreamStarted(msg)
@@ -77,7 +112,7 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 
 	// ===== Content Events =====
 	case *runtime.UserMessageEvent:
-		return true, p.messages.ReplaceLoadingWithUser(msg.Message, msg.SessionPosition)
+		return true, p.messages.ReplaceLoadingWithUser(msg.Message, imageview.FromParts(msg.MultiContent), msg.SessionPosition)
 
 	case *runtime.AgentChoiceEvent:
 		return true, p.handleAgentChoice(msg)
@@ -85,6 +120,9 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 	case *runtime.AgentChoiceReasoningEvent:
 		return true, p.handleAgentChoiceReasoning(msg)
 
+	case *runtime.CitationsEvent:
+		return true, p.handleCitations(msg)
+
 	case *runtime.ShellOutputEvent:
 		return true, p.messages.AddShellOutputMessage(msg.Output)
 
@@ -124,6 +162,9 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 		p.sidebar.SetSkillsInfo(len(p.app.CurrentAgentSkills()))
 		return true, nil
 
+	case *runtime.ToolsetStatusEvent:
+		return true, p.forwardToSidebar(msg)
+
 	case *runtime.SessionTitleEvent:
 		return true, p.forwardToSidebar(msg)
 
@@ -148,6 +189,9 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 	case *runtime.MaxIterationsReachedEvent:
 		return true, p.handleMaxIterationsReached(msg)
 
+	case *runtime.ToolResultConfirmationEvent:
+		return true, p.handleToolResultConfirmation(msg)
+
 	case *runtime.ElicitationRequestEvent:
 		return true, p.handleElicitationRequest(msg)
 	}
@@ -155,6 +199,21 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 	return false, nil
 }
 
+// updateAgentPanes feeds runtime events to the agent panes. The panes are
+// cleared at the start of each turn, and the page is resized when they show
+// up or go away.
+func (p *chatPage) updateAgentPanes(msg tea.Msg) tea.Cmd {
+	if _, ok := msg.(*runtime.StreamStartedEvent); ok && p.streamDepth == 0 {
+		p.agentPanes.Reset()
+	}
+
+	wasVisible := p.agentPanes.Visible()
+	if !p.agentPanes.Handle(msg) || p.agentPanes.Visible() == wasVisible {
+		return nil
+	}
+	return p.SetSize(p.width, p.height)
+}
+
 // forwardToSidebar forwards a message to the sidebar and returns the resulting command.
 func (p *chatPage) forwardToSidebar(msg tea.Msg) tea.Cmd {
 	slog.Debug("Forwarding event to sidebar", "event_type", fmt.Sprintf("%T", msg))
@@ -214,6 +273,17 @@ func (p *chatPage) handleAgentChoice(msg *runtime.AgentChoiceEvent) tea.Cmd {
 	return p.messages.AppendToLastMessage(msg.AgentName, msg.Content)
 }
 
+func (p *chatPage) handleCitations(msg *runtime.CitationsEvent) tea.Cmd {
+	if p.streamCancelled {
+		return nil
+	}
+	sources := types.Sources(msg.Citations)
+	if sources == "" {
+		return nil
+	}
+	return p.messages.AppendToLastMessage(msg.AgentName, sources)
+}
+
 func (p *chatPage) handleAgentChoiceReasoning(msg *runtime.AgentChoiceReasoningEvent) tea.Cmd {
 	if p.streamCancelled {
 		return nil
@@ -252,6 +322,12 @@ func (p *chatPage) handleStreamStopped(msg *runtime.StreamStoppedEvent) tea.Cmd
 			sound.Play(sound.Success)
 		}
 	}
+	var readAloudCmd tea.Cmd
+	if p.sessionState.ReadReplies() && !p.streamCancelled {
+		if sess := p.app.Session(); sess != nil {
+			readAloudCmd = core.CmdHandler(msgtypes.ReadAloudMsg{Text: sess.GetLastAssistantMessageContent()})
+		}
+	}
 	p.msgCancel = nil
 	p.streamCancelled = false
 	spinnerCmd := p.setWorking(false)
@@ -266,7 +342,7 @@ func (p *chatPage) handleStreamStopped(msg *runtime.StreamStoppedEvent) tea.Cmd
 		})
 	}
 
-	return tea.Batch(p.messages.ScrollToBottom(), spinnerCmd, sidebarCmd, queueCmd, exitCmd)
+	return tea.Batch(p.messages.ScrollToBottom(), spinnerCmd, sidebarCmd, queueCmd, exitCmd, readAloudCmd)
 }
 
 // handlePartialToolCall processes partial tool call events by rendering each
@@ -321,6 +397,14 @@ func (p *chatPage) handleMaxIterationsReached(msg *runtime.MaxIterationsReachedE
 	return tea.Batch(spinnerCmd, dialogCmd)
 }
 
+func (p *chatPage) handleToolResultConfirmation(msg *runtime.ToolResultConfirmationEvent) tea.Cmd {
+	spinnerCmd := p.setWorking(false)
+	dialogCmd := core.CmdHandler(dialog.OpenDialogMsg{
+		Model: dialog.NewToolResultConfirmationDialog(msg),
+	})
+	return tea.Batch(spinnerCmd, dialogCmd)
+}
+
 func (p *chatPage) handleElicitationRequest(msg *runtime.ElicitationRequestEvent) tea.Cmd {
 	spinnerCmd := p.setWorking(false)
 
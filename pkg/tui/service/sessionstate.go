@@ -14,8 +14,10 @@ import (
 type SessionStateReader interface {
 	SplitDiffView() bool
 	YoloMode() bool
+	Simulate() bool
 	Thinking() bool
 	HideToolResults() bool
+	ReadReplies() bool
 	CurrentAgentName() string
 	PreviousMessage() *types.Message
 	SessionTitle() string
@@ -32,8 +34,10 @@ var _ SessionStateReader = (*SessionState)(nil)
 type SessionState struct {
 	splitDiffView   bool
 	yoloMode        bool
+	simulate        bool
 	thinking        bool
 	hideToolResults bool
+	readReplies     bool
 	sessionTitle    string
 
 	previousMessage  *types.Message
@@ -45,8 +49,10 @@ func NewSessionState(s *session.Session) *SessionState {
 	return &SessionState{
 		splitDiffView:   userconfig.Get().GetSplitDiffView(),
 		yoloMode:        s.ToolsApproved,
+		simulate:        s.Simulate,
 		thinking:        s.Thinking,
 		hideToolResults: s.HideToolResults,
+		readReplies:     userconfig.Get().GetReadReplies(),
 		sessionTitle:    s.Title,
 	}
 }
@@ -67,6 +73,14 @@ func (s *SessionState) SetYoloMode(yoloMode bool) {
 	s.yoloMode = yoloMode
 }
 
+func (s *SessionState) Simulate() bool {
+	return s.simulate
+}
+
+func (s *SessionState) SetSimulate(simulate bool) {
+	s.simulate = simulate
+}
+
 func (s *SessionState) Thinking() bool {
 	return s.thinking
 }
@@ -87,6 +101,14 @@ func (s *SessionState) SetHideToolResults(hideToolResults bool) {
 	s.hideToolResults = hideToolResults
 }
 
+func (s *SessionState) ReadReplies() bool {
+	return s.readReplies
+}
+
+func (s *SessionState) ToggleReadReplies() {
+	s.readReplies = !s.readReplies
+}
+
 func (s *SessionState) CurrentAgentName() string {
 	return s.currentAgentName
 }
@@ -19,8 +19,10 @@ import (
 	"charm.land/lipgloss/v2"
 
 	"github.com/docker/docker-agent/pkg/app"
+	"github.com/docker/docker-agent/pkg/audio/speech"
 	"github.com/docker/docker-agent/pkg/audio/transcribe"
 	"github.com/docker/docker-agent/pkg/history"
+	"github.com/docker/docker-agent/pkg/httpclient"
 	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/tui/animation"
@@ -92,6 +94,10 @@ type appModel struct {
 	transcriber  *transcribe.Transcriber
 	transcriptCh chan string // bridges transcriber goroutine → Bubble Tea event loop
 
+	// Voice mode: push-to-talk recording and replies read aloud
+	recorder    *speech.Recorder
+	stopReading context.CancelFunc
+
 	// Working state indicator (resize handle spinner)
 	workingSpinner spinner.Spinner
 
@@ -122,6 +128,9 @@ type appModel struct {
 	// keyboardEnhancementsSupported tracks whether the terminal supports keyboard enhancements
 	keyboardEnhancementsSupported bool
 
+	// keyMap holds the global key bindings, customizable in the user config
+	keyMap keyMap
+
 	// program holds a reference to the tea.Program so that we can
 	// perform a full terminal release/restore cycle on focus events.
 	program *tea.Program
@@ -137,6 +146,18 @@ type appModel struct {
 	// events emitted by RestoreTerminal re-enabling focus reporting.
 	focused bool
 
+	// desktopNotifications sends desktop notifications for events that
+	// need attention while backgrounded, i.e. while the terminal reports
+	// that it lost focus. streamDepths counts the running streams of each
+	// session, to notify only when a whole run finishes.
+	desktopNotifications bool
+	backgrounded         bool
+	streamDepths         map[string]int
+
+	// contextUsage is the last context usage of each session, shown in the
+	// status bar for the active one.
+	contextUsage map[string]*runtime.ContextUsageEvent
+
 	// pendingRestores maps runtime tab IDs (supervisor routing keys) to
 	// persisted session-store IDs. When a tab with a pending restore is first
 	// switched to, the persisted session is loaded via replaceActiveSession —
@@ -166,8 +187,8 @@ func New(ctx context.Context, spawner SessionSpawner, initialApp *app.App, initi
 	sv := supervisor.New(spawner)
 
 	// Initialize tab bar with configurable title length from user settings
-	tabTitleMaxLen := userconfig.Get().GetTabTitleMaxLength()
-	tb := tabbar.New(tabTitleMaxLen)
+	settings := userconfig.Get()
+	tb := tabbar.New(settings.GetTabTitleMaxLength())
 
 	// Initialize tab store
 	var ts *tuistate.Store
@@ -206,10 +227,15 @@ func New(ctx context.Context, spawner SessionSpawner, initialApp *app.App, initi
 		dialogMgr:               dialog.New(),
 		completions:             completion.New(),
 		transcriber:             transcribe.New(os.Getenv("OPENAI_API_KEY")),
+		recorder:                speech.NewRecorder(),
 		workingSpinner:          spinner.New(spinner.ModeSpinnerOnly, styles.SpinnerDotsHighlightStyle),
 		focusedPanel:            PanelEditor,
 		editorLines:             3,
+		keyMap:                  newKeyMap(settings.Keybindings),
 		dockerDesktop:           os.Getenv("TERM_PROGRAM") == "docker_desktop",
+		desktopNotifications:    settings.GetDesktopNotifications(),
+		streamDepths:            make(map[string]int),
+		contextUsage:            make(map[string]*runtime.ContextUsageEvent),
 	}
 
 	// Initialize status bar (pass m as help provider)
@@ -560,9 +586,11 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.BlurMsg:
 		m.focused = false
+		m.backgrounded = true
 		return m, nil
 
 	case tea.FocusMsg:
+		m.backgrounded = false
 		// Only act on a real blur→focus transition. RestoreTerminal
 		// re-enables focus reporting which delivers a spurious FocusMsg;
 		// since m.focused is already true at that point, we skip it.
@@ -725,6 +753,15 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.chatPage = updated.(chat.Page)
 		return m, cmd
 
+	// --- External editor ---
+
+	case messages.OpenExternalEditorMsg:
+		return m.openExternalEditor()
+
+	case messages.ExternalEditorDoneMsg:
+		m.editor.ImportDraft(msg.Content)
+		return m, m.resizeAll()
+
 	// --- File attachments (routed to editor) ---
 
 	case messages.InsertFileRefMsg:
@@ -750,11 +787,20 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.BranchFromEditMsg:
 		return m.handleBranchFromEdit(msg)
 
+	case messages.RerunFromStepMsg:
+		return m.handleRerunFromStep(msg)
+
+	case messages.ForkFromStepMsg:
+		return m.handleForkFromStep(msg)
+
 	// --- Session commands (slash commands, command palette) ---
 
 	case messages.ToggleYoloMsg:
 		return m.handleToggleYolo()
 
+	case messages.ToggleSimulateMsg:
+		return m.handleToggleSimulate()
+
 	case messages.ToggleThinkingMsg:
 		return m.handleToggleThinking()
 
@@ -767,6 +813,12 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.ToggleSplitDiffMsg:
 		return m.handleToggleSplitDiff()
 
+	case messages.ToggleReadRepliesMsg:
+		return m.handleToggleReadReplies()
+
+	case messages.ToggleAgentPanesMsg:
+		return m.handleToggleAgentPanes()
+
 	case messages.ClearQueueMsg:
 		updated, cmd := m.chatPage.Update(msg)
 		m.chatPage = updated.(chat.Page)
@@ -775,6 +827,9 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.CompactSessionMsg:
 		return m.handleCompactSession(msg.AdditionalPrompt)
 
+	case messages.ContinueRunMsg:
+		return m.handleContinueRun(msg.Iterations)
+
 	case messages.CopySessionToClipboardMsg:
 		return m.handleCopySessionToClipboard()
 
@@ -784,6 +839,9 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.EvalSessionMsg:
 		return m.handleEvalSession(msg.Filename)
 
+	case messages.OpenDebugDumpMsg:
+		return m.openDebugDump()
+
 	case messages.ExportSessionMsg:
 		return m.handleExportSession(msg.Filename)
 
@@ -798,6 +856,12 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m.handleToggleSessionStar(sessionID)
 
+	case messages.RenameSessionMsg:
+		return m.handleRenameSession(msg.SessionID, msg.Title)
+
+	case messages.DeleteSessionMsg:
+		return m.handleDeleteSession(msg.SessionID)
+
 	case messages.SetSessionTitleMsg:
 		return m.handleSetSessionTitle(msg.Title)
 
@@ -807,6 +871,12 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.ShowCostDialogMsg:
 		return m.handleShowCostDialog()
 
+	case messages.ShowArtifactsDialogMsg:
+		return m.handleShowArtifactsDialog()
+
+	case messages.ShowTimeTravelDialogMsg:
+		return m.handleShowTimeTravelDialog()
+
 	case messages.ShowPermissionsDialogMsg:
 		return m.handleShowPermissionsDialog()
 
@@ -860,6 +930,23 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd := m.waitForTranscript()
 		return m, cmd
 
+	// --- Voice mode ---
+
+	case messages.ToggleDictationMsg:
+		if m.recorder.IsRecording() {
+			return m.handleStopDictation(true)
+		}
+		return m.handleStartDictation()
+
+	case messages.CancelDictationMsg:
+		return m.handleStopDictation(false)
+
+	case messages.DictationTranscribedMsg:
+		return m.handleDictationTranscribed(msg)
+
+	case messages.ReadAloudMsg:
+		return m.handleReadAloud(msg.Text)
+
 	// --- MCP prompts ---
 
 	case messages.ShowMCPPromptInputMsg:
@@ -931,6 +1018,13 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleRoutedMsg processes messages routed to specific sessions.
 func (m *appModel) handleRoutedMsg(msg messages.RoutedMsg) (tea.Model, tea.Cmd) {
+	if event, isRuntimeEvent := msg.Inner.(runtime.Event); isRuntimeEvent {
+		m.notifyDesktop(msg.SessionID, event)
+	}
+	if usage, ok := msg.Inner.(*runtime.ContextUsageEvent); ok {
+		m.contextUsage[msg.SessionID] = usage
+	}
+
 	activeID := m.supervisor.ActiveID()
 
 	if msg.SessionID == activeID {
@@ -992,8 +1086,13 @@ func (m *appModel) handleOpenSessionBrowser() (tea.Model, tea.Cmd) {
 		return m, notification.InfoCmd("No previous sessions found")
 	}
 
+	var currentSessionID string
+	if sess := m.application.Session(); sess != nil {
+		currentSessionID = sess.ID
+	}
+
 	return m, core.CmdHandler(dialog.OpenDialogMsg{
-		Model: dialog.NewSessionBrowserDialog(sessions),
+		Model: dialog.NewSessionBrowserDialog(sessions, currentSessionID),
 	})
 }
 
@@ -1293,6 +1392,11 @@ func (m *appModel) replayPendingEvent(sessionID string) tea.Cmd {
 			Model: dialog.NewMaxIterationsDialog(ev.MaxIterations, m.application),
 		})
 
+	case *runtime.ToolResultConfirmationEvent:
+		return core.CmdHandler(dialog.OpenDialogMsg{
+			Model: dialog.NewToolResultConfirmationDialog(ev),
+		})
+
 	case *runtime.ElicitationRequestEvent:
 		return m.replayElicitationEvent(ev)
 	}
@@ -1370,6 +1474,8 @@ func (m *appModel) handleCloseTab(sessionID string) (tea.Model, tea.Cmd) {
 		delete(m.editors, sessionID)
 	}
 	delete(m.sessionStates, sessionID)
+	delete(m.streamDepths, sessionID)
+	delete(m.contextUsage, sessionID)
 	delete(m.pendingRestores, sessionID)
 	delete(m.pendingSidebarCollapsed, sessionID)
 
@@ -1488,10 +1594,9 @@ func (m *appModel) Bindings() []key.Binding {
 	bindings := []key.Binding{quitBinding, tabBinding}
 	bindings = append(bindings, m.tabBar.Bindings()...)
 
-	bindings = append(bindings, key.NewBinding(
-		key.WithKeys("ctrl+k"),
-		key.WithHelp("Ctrl+k", "commands"),
-	))
+	if m.keyMap.CommandPalette.Enabled() {
+		bindings = append(bindings, m.keyMap.CommandPalette)
+	}
 
 	// Show newline help based on keyboard enhancement support
 	if m.keyboardEnhancementsSupported {
@@ -1510,22 +1615,29 @@ func (m *appModel) Bindings() []key.Binding {
 		bindings = append(bindings, m.chatPage.Bindings()...)
 	} else {
 		editorName := getEditorDisplayNameFromEnv(os.Getenv("VISUAL"), os.Getenv("EDITOR"))
-		bindings = append(bindings,
-			key.NewBinding(
-				key.WithKeys("ctrl+g"),
-				key.WithHelp("Ctrl+g", "edit in "+editorName),
-			),
-			key.NewBinding(
-				key.WithKeys("ctrl+r"),
-				key.WithHelp("Ctrl+r", "history search"),
-			),
-		)
+		externalEditor := m.keyMap.ExternalEditor
+		externalEditor.SetHelp(externalEditor.Help().Key, "edit in "+editorName)
+		for _, binding := range []key.Binding{externalEditor, m.keyMap.HistorySearch} {
+			if binding.Enabled() {
+				bindings = append(bindings, binding)
+			}
+		}
 	}
 	return bindings
 }
 
 // handleKeyPress handles all keyboard input with proper priority routing.
 func (m *appModel) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	// Enter transcribes a push-to-talk recording, Escape discards it
+	if m.recorder.IsRecording() {
+		switch msg.String() {
+		case "enter":
+			return m.handleStopDictation(true)
+		case "esc":
+			return m.handleStopDictation(false)
+		}
+	}
+
 	// Check if we should stop transcription on Enter or Escape
 	if m.transcriber.IsRunning() {
 		switch msg.String() {
@@ -1580,29 +1692,38 @@ func (m *appModel) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			Model: dialog.NewExitConfirmationDialog(),
 		})
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+z"))):
+	case key.Matches(msg, m.keyMap.Suspend):
 		return m, tea.Suspend
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+k"))):
+	case key.Matches(msg, m.keyMap.CommandPalette):
 		categories := commands.BuildCommandCategories(context.Background(), m.application)
+		m.keyMap.annotate(categories)
 		return m, core.CmdHandler(dialog.OpenDialogMsg{
 			Model: dialog.NewCommandPaletteDialog(categories),
 		})
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+y"))):
+	case key.Matches(msg, m.keyMap.ToggleYolo):
 		return m, core.CmdHandler(messages.ToggleYoloMsg{})
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+o"))):
+	case key.Matches(msg, m.keyMap.ToggleToolResults):
 		return m, core.CmdHandler(messages.ToggleHideToolResultsMsg{})
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+s"))):
+	case key.Matches(msg, m.keyMap.CycleAgent):
 		return m.handleCycleAgent()
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+m"))):
+	case key.Matches(msg, m.keyMap.SwitchModel):
 		return m.handleOpenModelPicker()
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+x"))):
+	case key.Matches(msg, m.keyMap.ClearQueue):
 		return m, core.CmdHandler(messages.ClearQueueMsg{})
+
+	case key.Matches(msg, m.keyMap.PushToTalk):
+		return m, core.CmdHandler(messages.ToggleDictationMsg{})
+	}
+
+	// Keys bound to command palette items in the user config
+	if id, ok := m.keyMap.matchCommand(msg); ok {
+		return m, m.runCommand(id)
 	}
 
 	// History search is a modal state — capture all remaining keys before normal routing
@@ -1613,21 +1734,19 @@ func (m *appModel) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	}
 
 	switch {
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+g"))):
+	case key.Matches(msg, m.keyMap.ExternalEditor):
 		return m.openExternalEditor()
 
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+r"))):
+	case key.Matches(msg, m.keyMap.HistorySearch):
 		if m.focusedPanel == PanelEditor && !m.editor.IsRecording() {
 			model, cmd := m.editor.EnterHistorySearch()
 			m.editor = model.(editor.Editor)
 			return m, cmd
 		}
 
-	// Toggle sidebar (propagates to content view regardless of focus)
-	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+b"))):
-		updated, cmd := m.chatPage.Update(msg)
-		m.chatPage = updated.(chat.Page)
-		return m, cmd
+	// Toggle sidebar (applies to content view regardless of focus)
+	case key.Matches(msg, m.keyMap.ToggleSidebar):
+		return m, m.chatPage.ToggleSidebar()
 
 	// Focus switching: Tab key toggles between content and editor
 	case key.Matches(msg, key.NewBinding(key.WithKeys("tab"))):
@@ -1662,6 +1781,18 @@ func (m *appModel) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// runCommand executes the command palette item with the given ID.
+func (m *appModel) runCommand(id string) tea.Cmd {
+	for _, category := range commands.BuildCommandCategories(context.Background(), m.application) {
+		for _, cmd := range category.Commands {
+			if cmd.ID == id {
+				return cmd.Execute("")
+			}
+		}
+	}
+	return notification.WarningCmd(fmt.Sprintf("Unknown command %q in keybindings", id))
+}
+
 // parseCtrlNumberKey checks if msg is ctrl+1 through ctrl+9 and returns the index (0-8), or -1 if not matched
 func parseCtrlNumberKey(msg tea.KeyPressMsg) int {
 	s := msg.String()
@@ -1966,6 +2097,13 @@ func (m *appModel) renderResizeHandle(width int) string {
 
 // View renders the model.
 func (m *appModel) View() tea.View {
+	view := m.render()
+	// Focus reporting tells when the terminal is in the background.
+	view.ReportFocus = m.desktopNotifications
+	return view
+}
+
+func (m *appModel) render() tea.View {
 	windowTitle := m.windowTitle()
 
 	if m.err != nil {
@@ -1996,6 +2134,7 @@ func (m *appModel) View() tea.View {
 	editorView := m.editor.View()
 
 	// Status bar
+	m.statusBar.SetContextUsage(m.contextUsage[m.supervisor.ActiveID()])
 	statusBarView := m.statusBar.View()
 
 	// Combine: content | resize handle | [tab bar] | editor | status bar
@@ -2064,6 +2203,10 @@ func (m *appModel) cleanupAll() {
 	}
 	m.transcriber.Stop()
 	m.closeTranscriptCh()
+	if m.recorder.IsRecording() {
+		_, _ = m.recorder.Stop()
+	}
+	m.stopReadingAloud()
 	for _, cp := range m.chatPages {
 		cp.Cleanup()
 	}
@@ -2128,23 +2271,7 @@ func (m *appModel) openExternalEditor() (tea.Model, tea.Cmd) {
 	}
 	tmpFile.Close()
 
-	// Get the editor command (VISUAL, EDITOR, or platform default)
-	editorCmd := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"))
-	if editorCmd == "" {
-		if goruntime.GOOS == "windows" {
-			editorCmd = "notepad"
-		} else {
-			editorCmd = "vi"
-		}
-	}
-
-	// Parse editor command (may include arguments like "code --wait")
-	parts := strings.Fields(editorCmd)
-	args := append(parts[1:], tmpPath)
-	cmd := exec.Command(parts[0], args...)
-
-	ed := m.editor
-	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+	return m, tea.ExecProcess(editorCommand(tmpPath), func(err error) tea.Msg {
 		if err != nil {
 			os.Remove(tmpPath)
 			return notification.ShowMsg{Text: fmt.Sprintf("Editor error: %v", err), Type: notification.TypeError}
@@ -2159,17 +2286,48 @@ func (m *appModel) openExternalEditor() (tea.Model, tea.Cmd) {
 
 		// Trim trailing newline that editors often add
 		c := strings.TrimSuffix(string(updatedContent), "\n")
-
 		if strings.TrimSpace(c) == "" {
-			ed.SetValue("")
-		} else {
-			ed.SetValue(c)
+			c = ""
 		}
 
+		return messages.ExternalEditorDoneMsg{Content: c}
+	})
+}
+
+// openDebugDump opens the last model request dumped by --debug-llm in an
+// external editor.
+func (m *appModel) openDebugDump() (tea.Model, tea.Cmd) {
+	path := httpclient.LastDebugDump()
+	if path == "" {
+		return m, notification.InfoCmd("No model request was dumped yet. Run with --debug-llm to dump them.")
+	}
+
+	return m, tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		if err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Editor error: %v", err), Type: notification.TypeError}
+		}
 		return nil
 	})
 }
 
+// editorCommand returns the command opening a file in the editor of the
+// user: $VISUAL, $EDITOR, or the platform default.
+func editorCommand(path string) *exec.Cmd {
+	editorCmd := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"))
+	if editorCmd == "" {
+		if goruntime.GOOS == "windows" {
+			editorCmd = "notepad"
+		} else {
+			editorCmd = "vi"
+		}
+	}
+
+	// Parse editor command (may include arguments like "code --wait")
+	parts := strings.Fields(editorCmd)
+	args := append(parts[1:], path)
+	return exec.Command(parts[0], args...)
+}
+
 // getEditorDisplayNameFromEnv returns a friendly display name for the configured editor.
 func getEditorDisplayNameFromEnv(visual, editorEnv string) string {
 	editorCmd := cmp.Or(visual, editorEnv)
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/docker/docker-agent/pkg/audio/speech"
 	"github.com/docker/docker-agent/pkg/audio/transcribe"
 	"github.com/docker/docker-agent/pkg/tui/components/completion"
 	"github.com/docker/docker-agent/pkg/tui/components/editor"
@@ -31,6 +32,7 @@ func (m *mockChatPage) Update(tea.Msg) (layout.Model, tea.Cmd)   { return m, nil
 func (m *mockChatPage) View() string                             { return "" }
 func (m *mockChatPage) SetSize(int, int) tea.Cmd                 { return nil }
 func (m *mockChatPage) CompactSession(string) tea.Cmd            { return nil }
+func (m *mockChatPage) ContinueRun(int) tea.Cmd                  { return nil }
 func (m *mockChatPage) Cleanup()                                 { m.cleanupCalled = true }
 func (m *mockChatPage) SetSessionStarred(bool)                   {}
 func (m *mockChatPage) SetTitleRegenerating(bool) tea.Cmd        { return nil }
@@ -43,6 +45,7 @@ func (m *mockChatPage) FocusMessageAt(int, int) tea.Cmd          { return nil }
 func (m *mockChatPage) BlurMessages()                            {}
 func (m *mockChatPage) GetSidebarSettings() chat.SidebarSettings { return chat.SidebarSettings{} }
 func (m *mockChatPage) SetSidebarSettings(chat.SidebarSettings)  {}
+func (m *mockChatPage) ToggleSidebar() tea.Cmd                   { return nil }
 func (m *mockChatPage) Bindings() []key.Binding                  { return nil }
 func (m *mockChatPage) Help() help.KeyMap                        { return nil }
 
@@ -62,6 +65,7 @@ func (m *mockEditor) AcceptSuggestion() tea.Cmd              { return nil }
 func (m *mockEditor) ScrollByWheel(int)                      {}
 func (m *mockEditor) Value() string                          { return "" }
 func (m *mockEditor) SetValue(string)                        {}
+func (m *mockEditor) ImportDraft(string)                     {}
 func (m *mockEditor) InsertText(string)                      {}
 func (m *mockEditor) AttachFile(string) error                { return nil }
 func (m *mockEditor) Cleanup()                               { m.cleanupCalled = true }
@@ -139,6 +143,7 @@ func newTestModel() (*appModel, *mockChatPage, *mockEditor) {
 		chatPage:                page,
 		editor:                  ed,
 		transcriber:             transcribe.New(""),
+		recorder:                speech.NewRecorder(),
 		notification:            notification.New(),
 		dialogMgr:               dialog.New(),
 		completions:             completion.New(),
@@ -1,8 +1,11 @@
 package types
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/termimage"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
@@ -21,6 +24,7 @@ const (
 	MessageTypeToolResult
 	MessageTypeWelcome
 	MessageTypeLoading
+	MessageTypeRecord // Approval, elicitation, model switch or compaction of a restored session
 )
 
 const UserMessageEditLabel = "✎"
@@ -45,6 +49,7 @@ type Message struct {
 	ToolDefinition tools.Tool            // Definition of the tool being called
 	ToolStatus     ToolStatus            // Status for tool calls
 	ToolResult     *tools.ToolCallResult // Result of tool call (when completed)
+	Images         []*termimage.Image    // Images attached to a user message
 	// SessionPosition is the index of this message in session.Messages (when known).
 	// Used for operations like branching on edits.
 	SessionPosition *int
@@ -108,9 +113,40 @@ func ToolCallMessage(agentName string, toolCall tools.ToolCall, toolDef tools.To
 	}
 }
 
+func Record(agentName, content string) *Message {
+	return &Message{
+		Type:    MessageTypeRecord,
+		Sender:  agentName,
+		Content: content,
+	}
+}
+
 func Loading(description string) *Message {
 	return &Message{
 		Type:    MessageTypeLoading,
 		Content: strings.ReplaceAll(description, "\t", "    "),
 	}
 }
+
+// Sources renders the distinct sources cited in an assistant answer as a
+// markdown footer. It returns an empty string if there are no citations.
+func Sources(citations []chat.Citation) string {
+	var sb strings.Builder
+	seen := make(map[string]bool)
+	for _, c := range citations {
+		if c.Source == "" || seen[c.Source] {
+			continue
+		}
+		seen[c.Source] = true
+
+		if sb.Len() == 0 {
+			sb.WriteString("\n\n**Sources**\n")
+		}
+		if c.Title != "" {
+			fmt.Fprintf(&sb, "\n%d. [%s](%s)", len(seen), c.Title, c.Source)
+		} else {
+			fmt.Fprintf(&sb, "\n%d. %s", len(seen), c.Source)
+		}
+	}
+	return sb.String()
+}
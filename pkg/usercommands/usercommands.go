@@ -0,0 +1,119 @@
+// Package usercommands loads the slash commands defined by users as Markdown
+// files, in addition to the commands of the agent configuration.
+//
+// Each file defines one command, named after the file: review.md defines
+// /review. The file content is the instruction sent to the agent, with the
+// same syntax as agent commands (${args[0]}, ${args}, !tool(...)). An
+// optional YAML frontmatter sets the description shown in the TUI:
+//
+//	---
+//	description: Review the changes of a branch
+//	---
+//	Review the changes between ${args[0]} and main.
+package usercommands
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+// validName matches the command names that can be typed after a slash.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Dirs returns the directories user commands are loaded from, in order of
+// precedence (later overrides earlier):
+//   - ~/.cagent/commands/
+//   - .agents/commands/ in the working directory
+func Dirs(workingDir string) []string {
+	dirs := []string{filepath.Join(paths.GetDataDir(), "commands")}
+
+	if workingDir == "" {
+		workingDir, _ = os.Getwd()
+	}
+	if workingDir != "" {
+		dirs = append(dirs, filepath.Join(workingDir, ".agents", "commands"))
+	}
+	return dirs
+}
+
+// Load loads the user commands for the given working directory. Files that
+// can't be read or parsed are skipped.
+func Load(workingDir string) types.Commands {
+	commands := types.Commands{}
+	for _, dir := range Dirs(workingDir) {
+		for name, cmd := range loadDir(dir) {
+			commands[name] = cmd
+		}
+	}
+	return commands
+}
+
+func loadDir(dir string) types.Commands {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	commands := types.Commands{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(fileName, ".") || filepath.Ext(fileName) != ".md" {
+			continue
+		}
+
+		name := strings.TrimSuffix(fileName, ".md")
+		if !validName.MatchString(name) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			continue
+		}
+
+		cmd, ok := parse(string(content))
+		if !ok {
+			continue
+		}
+		commands[name] = cmd
+	}
+	return commands
+}
+
+// parse reads a command file: an optional YAML frontmatter followed by the
+// instruction.
+func parse(content string) (types.Command, bool) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var frontmatter struct {
+		Description string `yaml:"description"`
+	}
+	body := content
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		block, after, found := strings.Cut("\n"+rest, "\n---")
+		if !found {
+			return types.Command{}, false
+		}
+		if err := yaml.Unmarshal([]byte(block), &frontmatter); err != nil {
+			return types.Command{}, false
+		}
+		body = strings.TrimPrefix(after, "\n")
+	}
+
+	instruction := strings.TrimSpace(body)
+	if instruction == "" {
+		return types.Command{}, false
+	}
+
+	return types.Command{
+		Description: strings.TrimSpace(frontmatter.Description),
+		Instruction: instruction,
+	}, true
+}
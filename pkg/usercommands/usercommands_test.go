@@ -0,0 +1,91 @@
+package usercommands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/paths"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    types.Command
+		ok      bool
+	}{
+		{
+			name:    "plain instruction",
+			content: "Fix the lint issues\n",
+			want:    types.Command{Instruction: "Fix the lint issues"},
+			ok:      true,
+		},
+		{
+			name:    "with frontmatter",
+			content: "---\ndescription: Review a branch\n---\nReview ${args[0]}\n",
+			want:    types.Command{Description: "Review a branch", Instruction: "Review ${args[0]}"},
+			ok:      true,
+		},
+		{
+			name:    "empty frontmatter",
+			content: "---\n---\nHello",
+			want:    types.Command{Instruction: "Hello"},
+			ok:      true,
+		},
+		{
+			name:    "windows line endings",
+			content: "---\r\ndescription: Test\r\n---\r\nLine 1\r\nLine 2\r\n",
+			want:    types.Command{Description: "Test", Instruction: "Line 1\nLine 2"},
+			ok:      true,
+		},
+		{name: "empty", content: "  \n"},
+		{name: "unterminated frontmatter", content: "---\ndescription: Test\nHello"},
+		{name: "no instruction", content: "---\ndescription: Test\n---\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := parse(tt.content)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dataDir := t.TempDir()
+	paths.SetDataDir(dataDir)
+	t.Cleanup(func() { paths.SetDataDir("") })
+
+	workingDir := t.TempDir()
+	projectDir := filepath.Join(workingDir, ".agents", "commands")
+
+	writeFile(t, filepath.Join(dataDir, "commands", "review.md"), "Review the code")
+	writeFile(t, filepath.Join(dataDir, "commands", "deploy.md"), "Deploy to production")
+	writeFile(t, filepath.Join(projectDir, "deploy.md"), "---\ndescription: Deploy\n---\nDeploy to staging")
+	writeFile(t, filepath.Join(projectDir, "notes.txt"), "Not a command")
+	writeFile(t, filepath.Join(projectDir, ".hidden.md"), "Hidden")
+	writeFile(t, filepath.Join(projectDir, "with space.md"), "Invalid name")
+	writeFile(t, filepath.Join(projectDir, "nested", "sub.md"), "Nested")
+
+	commands := Load(workingDir)
+
+	assert.Equal(t, types.Commands{
+		"review": {Instruction: "Review the code"},
+		"deploy": {Description: "Deploy", Instruction: "Deploy to staging"},
+	}, commands)
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sync"
 
 	"github.com/goccy/go-yaml"
@@ -61,6 +62,75 @@ type Settings struct {
 	// SoundThreshold is the minimum duration in seconds a task must run
 	// before a success sound is played. Defaults to 5 seconds.
 	SoundThreshold int `yaml:"sound_threshold,omitempty"`
+	// DesktopNotifications sends desktop notifications when a tool call needs
+	// approval, an agent asks a question, or a run finishes while the
+	// terminal is in the background. Defaults to false (user must explicitly opt-in).
+	DesktopNotifications bool `yaml:"desktop_notifications,omitempty"`
+	// ContextWarningThresholds are the percentages of the model's context
+	// window at which a warning is shown. Defaults to 75 and 90; an empty
+	// list disables the warnings.
+	ContextWarningThresholds []int `yaml:"context_warning_thresholds,omitempty"`
+	// RunLog records a local run log of every session under ~/.cagent/runs.
+	// Defaults to false (user must explicitly opt-in).
+	RunLog bool `yaml:"run_log,omitempty"`
+	// AuditLog appends a tamper-evident log of the tool calls and approvals
+	// of every run to a file, syslog or an HTTP endpoint, like --audit-log.
+	AuditLog string `yaml:"audit_log,omitempty"`
+	// EncryptSessions encrypts the messages of the sessions at rest with a key
+	// kept in the system keychain. DOCKER_AGENT_SESSION_KEY takes precedence.
+	EncryptSessions bool `yaml:"encrypt_sessions,omitempty"`
+	// SessionRetention prunes the old sessions of the session database when
+	// docker agent starts. Defaults to keeping every session.
+	SessionRetention *SessionRetention `yaml:"session_retention,omitempty"`
+	// ShareUploadURL is where `share` uploads the transcripts of the
+	// sessions, with an HTTP PUT, like --upload-url.
+	ShareUploadURL string `yaml:"share_upload_url,omitempty"`
+	// Keybindings overrides the TUI keyboard shortcuts. Keys are action names
+	// (e.g. "toggle_yolo") or command palette IDs (e.g. "session.compact"),
+	// values are the keys bound to them. An empty list unbinds the action.
+	Keybindings map[string][]string `yaml:"keybindings,omitempty"`
+	// Voice configures the voice mode of the TUI: push-to-talk transcription
+	// and reading replies aloud.
+	Voice *VoiceSettings `yaml:"voice,omitempty"`
+}
+
+// SessionRetention limits the sessions kept in the session database. Starred
+// sessions are always kept.
+type SessionRetention struct {
+	// MaxAge prunes the sessions older than this, e.g. "30d" or "12h".
+	MaxAge string `yaml:"max_age,omitempty"`
+	// MaxCount keeps at most this number of sessions.
+	MaxCount int `yaml:"max_count,omitempty"`
+	// MaxSize prunes the oldest sessions when the database grows larger
+	// than this, e.g. "500MB".
+	MaxSize string `yaml:"max_size,omitempty"`
+}
+
+// VoiceSettings configures the voice mode of the TUI.
+type VoiceSettings struct {
+	// ReadReplies reads the assistant replies aloud. Defaults to false; can
+	// be toggled in the TUI with /voice.
+	ReadReplies bool `yaml:"read_replies,omitempty"`
+	// Transcription is the model that transcribes push-to-talk recordings.
+	// Defaults to OpenAI's gpt-4o-mini-transcribe.
+	Transcription *VoiceModel `yaml:"transcription,omitempty"`
+	// Speech is the model that reads replies aloud.
+	// Defaults to OpenAI's gpt-4o-mini-tts.
+	Speech *VoiceModel `yaml:"speech,omitempty"`
+}
+
+// VoiceModel selects the model used for transcription or speech synthesis.
+type VoiceModel struct {
+	// Provider is the model provider, e.g. "openai", or "whisper_cpp" for
+	// transcription with a local whisper.cpp server.
+	Provider string `yaml:"provider,omitempty"`
+	// Model is the model name, e.g. "whisper-1".
+	Model string `yaml:"model,omitempty"`
+	// BaseURL overrides the URL of the provider's API, or of the
+	// whisper.cpp server.
+	BaseURL string `yaml:"base_url,omitempty"`
+	// Voice is the voice used to read replies, e.g. "alloy".
+	Voice string `yaml:"voice,omitempty"`
 }
 
 // DefaultTabTitleMaxLength is the default maximum tab title length when not configured.
@@ -85,6 +155,14 @@ func (s *Settings) GetSound() bool {
 	return s.Sound
 }
 
+// GetDesktopNotifications returns whether desktop notifications are enabled, defaulting to false.
+func (s *Settings) GetDesktopNotifications() bool {
+	if s == nil {
+		return false
+	}
+	return s.DesktopNotifications
+}
+
 // GetSoundThreshold returns the minimum duration for sound notifications, defaulting to 10s.
 func (s *Settings) GetSoundThreshold() int {
 	if s == nil || s.SoundThreshold <= 0 {
@@ -101,6 +179,32 @@ func (s *Settings) GetSplitDiffView() bool {
 	return *s.SplitDiffView
 }
 
+// GetReadReplies returns whether assistant replies are read aloud, defaulting to false.
+func (s *Settings) GetReadReplies() bool {
+	if s == nil || s.Voice == nil {
+		return false
+	}
+	return s.Voice.ReadReplies
+}
+
+// GetTranscriptionModel returns the configured transcription model, or an
+// empty model selecting the defaults.
+func (s *Settings) GetTranscriptionModel() VoiceModel {
+	if s == nil || s.Voice == nil || s.Voice.Transcription == nil {
+		return VoiceModel{}
+	}
+	return *s.Voice.Transcription
+}
+
+// GetSpeechModel returns the configured speech model, or an empty model
+// selecting the defaults.
+func (s *Settings) GetSpeechModel() VoiceModel {
+	if s == nil || s.Voice == nil || s.Voice.Speech == nil {
+		return VoiceModel{}
+	}
+	return *s.Voice.Speech
+}
+
 // CredentialHelper contains configuration for a credential helper command
 // that retrieves Docker credentials (DOCKER_TOKEN) from an external source.
 type CredentialHelper struct {
@@ -132,6 +236,9 @@ type Config struct {
 	Settings *Settings `yaml:"settings,omitempty"`
 	// CredentialHelper configures an external command to retrieve Docker credentials
 	CredentialHelper *CredentialHelper `yaml:"credential_helper,omitempty"`
+	// TrustedProjects are the roots of the projects whose settings can
+	// auto-approve tool calls, with approval: yolo or permissions.allow.
+	TrustedProjects []string `yaml:"trusted_projects,omitempty"`
 }
 
 // Path returns the path to the config file
@@ -324,6 +431,24 @@ func (c *Config) DeleteAlias(name string) bool {
 	return false
 }
 
+// IsProjectTrusted returns whether the user trusts the project rooted in root.
+func (c *Config) IsProjectTrusted(root string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Contains(c.TrustedProjects, root)
+}
+
+// TrustProject adds the project rooted in root to the trusted projects.
+func (c *Config) TrustProject(root string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !slices.Contains(c.TrustedProjects, root) {
+		c.TrustedProjects = append(c.TrustedProjects, root)
+	}
+}
+
 // GetSettings returns the global settings with defaults applied.
 func (c *Config) GetSettings() *Settings {
 	if c.Settings == nil {
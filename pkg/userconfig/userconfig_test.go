@@ -171,6 +171,25 @@ func TestConfig_SaveAndLoad(t *testing.T) {
 	assert.Equal(t, config.Aliases["myagent"].Path, loaded.Aliases["myagent"].Path)
 }
 
+func TestConfig_TrustedProjects(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+
+	config := &Config{}
+	assert.False(t, config.IsProjectTrusted("/src/app"))
+
+	config.TrustProject("/src/app")
+	config.TrustProject("/src/app")
+	assert.Equal(t, []string{"/src/app"}, config.TrustedProjects)
+	require.NoError(t, config.saveTo(configFile))
+
+	loaded, err := loadFrom(configFile, "")
+	require.NoError(t, err)
+	assert.True(t, loaded.IsProjectTrusted("/src/app"))
+	assert.False(t, loaded.IsProjectTrusted("/src"))
+}
+
 func TestConfig_MigrateFromLegacy(t *testing.T) {
 	t.Parallel()
 
@@ -833,6 +852,27 @@ func TestSettings_GetSound(t *testing.T) {
 	}
 }
 
+func TestSettings_GetDesktopNotifications(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings *Settings
+		expected bool
+	}{
+		{"nil settings", nil, false},
+		{"empty settings", &Settings{}, false},
+		{"explicitly enabled", &Settings{DesktopNotifications: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, tt.settings.GetDesktopNotifications())
+		})
+	}
+}
+
 func TestSettings_RestoreTabs(t *testing.T) {
 	t.Parallel()
 
@@ -860,3 +900,49 @@ func TestSettings_RestoreTabs(t *testing.T) {
 		})
 	}
 }
+
+func TestGet_WithKeybindings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	cfg.Settings = &Settings{
+		Keybindings: map[string][]string{
+			"toggle_sidebar": {"alt+b"},
+			"toggle_yolo":    {},
+		},
+	}
+	require.NoError(t, cfg.Save())
+
+	settings := Get()
+	require.NotNil(t, settings)
+	assert.Equal(t, []string{"alt+b"}, settings.Keybindings["toggle_sidebar"])
+	keys, ok := settings.Keybindings["toggle_yolo"]
+	assert.True(t, ok)
+	assert.Empty(t, keys)
+}
+
+func TestGet_WithVoice(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	assert.False(t, Get().GetReadReplies())
+	assert.Equal(t, VoiceModel{}, Get().GetTranscriptionModel())
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	cfg.Settings = &Settings{
+		Voice: &VoiceSettings{
+			ReadReplies:   true,
+			Transcription: &VoiceModel{Provider: "whisper_cpp", BaseURL: "http://localhost:9000"},
+			Speech:        &VoiceModel{Voice: "nova"},
+		},
+	}
+	require.NoError(t, cfg.Save())
+
+	settings := Get()
+	assert.True(t, settings.GetReadReplies())
+	assert.Equal(t, VoiceModel{Provider: "whisper_cpp", BaseURL: "http://localhost:9000"}, settings.GetTranscriptionModel())
+	assert.Equal(t, VoiceModel{Voice: "nova"}, settings.GetSpeechModel())
+}
@@ -0,0 +1,168 @@
+// Package workspace describes the root directories a session works in: the
+// working directory and, optionally, additional roots such as the frontend
+// and backend repositories of an application.
+//
+// Roots are named after their directory. Relative paths resolve from the
+// primary root, unless they are prefixed with the name of another root and a
+// colon, e.g. "backend:cmd/main.go".
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Root is a root directory of a workspace.
+type Root struct {
+	Name string
+	Path string
+}
+
+// Workspace is an ordered set of roots. The first one is the primary root.
+type Workspace struct {
+	roots []Root
+}
+
+// New creates a workspace with primary as its primary root. An empty primary
+// root stands for the current directory of the process. Duplicate roots are
+// ignored, and roots with the same directory name are given a numeric suffix.
+func New(primary string, additional ...string) Workspace {
+	var w Workspace
+	seen := map[string]bool{}
+	names := map[string]bool{}
+	for i, dir := range append([]string{primary}, additional...) {
+		if i > 0 && dir == "" {
+			continue
+		}
+		key := dir
+		if abs, err := filepath.Abs(dir); err == nil {
+			key = abs
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		base := filepath.Base(key)
+		name := base
+		for n := 2; names[name]; n++ {
+			name = base + "-" + strconv.Itoa(n)
+		}
+		names[name] = true
+
+		w.roots = append(w.roots, Root{Name: name, Path: dir})
+	}
+	return w
+}
+
+// Roots returns the roots of the workspace, the primary one first.
+func (w Workspace) Roots() []Root {
+	return w.roots
+}
+
+// Primary returns the directory of the primary root.
+func (w Workspace) Primary() string {
+	if len(w.roots) == 0 {
+		return ""
+	}
+	return w.roots[0].Path
+}
+
+// Paths returns the directories of the roots, the primary one first.
+func (w Workspace) Paths() []string {
+	paths := make([]string, len(w.roots))
+	for i, root := range w.roots {
+		paths[i] = root.Path
+	}
+	return paths
+}
+
+// IsMultiRoot returns whether the workspace has more than one root.
+func (w Workspace) IsMultiRoot() bool {
+	return len(w.roots) > 1
+}
+
+// Root returns the directory of the root selected by name, or by directory.
+func (w Workspace) Root(selector string) (string, error) {
+	for _, root := range w.roots {
+		if root.Name == selector || root.Path == selector {
+			return root.Path, nil
+		}
+	}
+	return "", fmt.Errorf("unknown workspace root %q", selector)
+}
+
+// WithPrimary returns a copy of the workspace whose primary root is the one
+// selected by name, or by directory.
+func (w Workspace) WithPrimary(selector string) (Workspace, error) {
+	for i, root := range w.roots {
+		if root.Name == selector || root.Path == selector {
+			roots := make([]Root, 0, len(w.roots))
+			roots = append(roots, root)
+			roots = append(roots, w.roots[:i]...)
+			roots = append(roots, w.roots[i+1:]...)
+			return Workspace{roots: roots}, nil
+		}
+	}
+	return w, fmt.Errorf("unknown workspace root %q", selector)
+}
+
+// Resolve resolves a path: absolute paths are used as-is, paths prefixed
+// with "<root>:" are relative to that root and other paths are relative to
+// the primary root.
+func (w Workspace) Resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	if name, rel, ok := strings.Cut(path, ":"); ok {
+		for _, root := range w.roots {
+			if root.Name == name {
+				return filepath.Clean(filepath.Join(root.Path, rel))
+			}
+		}
+	}
+	return filepath.Clean(filepath.Join(w.Primary(), path))
+}
+
+// RootOf returns the directory of the innermost root containing path, or ""
+// if path is outside of the workspace.
+func (w Workspace) RootOf(path string) string {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+
+	var found, foundAbs string
+	for _, root := range w.roots {
+		abs, err := filepath.Abs(root.Path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(abs, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(abs) > len(foundAbs) {
+			found, foundAbs = root.Path, abs
+		}
+	}
+	return found
+}
+
+// Instructions describes the roots of a multi-root workspace to an agent.
+func (w Workspace) Instructions() string {
+	if !w.IsMultiRoot() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("The workspace has several roots. Relative paths resolve from the first one, the working directory. To use another root, prefix relative paths, and the cwd of shell commands, with the name of the root and a colon, e.g. \"")
+	b.WriteString(w.roots[1].Name)
+	b.WriteString(":README.md\".\n\nRoots:")
+	for _, root := range w.roots {
+		fmt.Fprintf(&b, "\n- %s: %s", root.Name, root.Path)
+	}
+	return b.String()
+}
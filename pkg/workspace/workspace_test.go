@@ -0,0 +1,77 @@
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	ws := New("/src/app", "/src/api", "/src/app", "", "/other/api")
+
+	assert.Equal(t, []Root{
+		{Name: "app", Path: "/src/app"},
+		{Name: "api", Path: "/src/api"},
+		{Name: "api-2", Path: "/other/api"},
+	}, ws.Roots())
+	assert.Equal(t, "/src/app", ws.Primary())
+	assert.Equal(t, []string{"/src/app", "/src/api", "/other/api"}, ws.Paths())
+	assert.True(t, ws.IsMultiRoot())
+	assert.False(t, New("/src/app").IsMultiRoot())
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	ws := New("/src/app", "/src/api")
+
+	assert.Equal(t, filepath.Clean("/src/app/main.go"), ws.Resolve("main.go"))
+	assert.Equal(t, filepath.Clean("/src/api/cmd/main.go"), ws.Resolve("api:cmd/main.go"))
+	assert.Equal(t, filepath.Clean("/src/api"), ws.Resolve("api:."))
+	assert.Equal(t, filepath.Clean("/etc/hosts"), ws.Resolve("/etc/hosts"))
+	assert.Equal(t, filepath.Clean("/src/app/unknown:file"), ws.Resolve("unknown:file"))
+}
+
+func TestRootAndWithPrimary(t *testing.T) {
+	t.Parallel()
+
+	ws := New("/src/app", "/src/api")
+
+	root, err := ws.Root("api")
+	require.NoError(t, err)
+	assert.Equal(t, "/src/api", root)
+
+	_, err = ws.Root("web")
+	require.ErrorContains(t, err, `unknown workspace root "web"`)
+
+	api, err := ws.WithPrimary("/src/api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/src/api", "/src/app"}, api.Paths())
+	// The original workspace isn't modified.
+	assert.Equal(t, "/src/app", ws.Primary())
+}
+
+func TestRootOf(t *testing.T) {
+	t.Parallel()
+
+	ws := New("/src", "/src/api", "/other")
+
+	assert.Equal(t, "/src/api", ws.RootOf("/src/api/main.go"))
+	assert.Equal(t, "/src", ws.RootOf("/src/app/main.go"))
+	assert.Equal(t, "/other", ws.RootOf("/other"))
+	assert.Empty(t, ws.RootOf("/elsewhere/file"))
+}
+
+func TestInstructions(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, New("/src/app").Instructions())
+
+	instructions := New("/src/app", "/src/api").Instructions()
+	assert.Contains(t, instructions, `"api:README.md"`)
+	assert.Contains(t, instructions, "- app: /src/app\n- api: /src/api")
+}
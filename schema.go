@@ -0,0 +1,14 @@
+package main
+
+import (
+	_ "embed"
+
+	"github.com/docker/docker-agent/cmd/root"
+)
+
+//go:embed agent-schema.json
+var agentSchema []byte
+
+func init() {
+	root.AgentSchema = agentSchema
+}